@@ -0,0 +1,510 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/archive"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/clickhouse"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/database"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/notify"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/pricing"
+	"github.com/bimakw/chain-indexer/internal/presentation/handlers"
+	"github.com/bimakw/chain-indexer/internal/presentation/middleware"
+)
+
+// newAPICmd returns the `chain-indexer api` command
+func newAPICmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api",
+		Short: "Serve the REST API",
+		Args:  cobra.NoArgs,
+	}
+	allowDegraded := cmd.Flags().Bool("allow-degraded-schema", false, "start on a schema behind this build, answering 503 on the endpoints whose tables are missing")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runAPI(cfg, logger, *allowDegraded)
+	})
+	return cmd
+}
+
+// runAPI serves the REST API until SIGINT or SIGTERM
+func runAPI(cfg *config.Config, logger *zap.Logger, allowDegraded bool) error {
+	logger.Info("Starting chain-indexer API",
+		zap.Int("port", cfg.API.Port),
+	)
+
+	ctx := context.Background()
+
+	// Connect to database
+	store, err := bootstrap.OpenStore(ctx, cfg, cfg.Database.ForAPI(), logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer store.Close()
+	schema := checkSchemaCompatibility(store, allowDegraded, logger)
+
+	// Connect to Redis cache (optional)
+	var redisCache *cache.RedisCache
+	redisCache, err = bootstrap.ConnectRedis(ctx, cfg, cfg.API.CacheTTL, logger)
+	if err != nil {
+		logger.Warn("Failed to connect to Redis, running without cache", zap.Error(err))
+		redisCache = nil
+	} else {
+		defer redisCache.Close()
+	}
+
+	// Active address sketches are written by the indexer to the same Redis
+	var sketches *cache.ActiveAddressSketches
+	if redisCache != nil {
+		sketches = cache.NewActiveAddressSketches(redisCache)
+	}
+
+	router, closeRouter := newAPIRouter(cfg, store, schema, redisCache, sketches, logger)
+	defer closeRouter()
+
+	// Start server
+	server := newAPIServer(cfg, router)
+
+	bootstrap.Serve(server, logger)
+
+	// Wait for shutdown signal
+	bootstrap.WaitForSignal()
+
+	logger.Info("Received shutdown signal, shutting down server...")
+
+	// Graceful shutdown
+	bootstrap.Shutdown(server, cfg.API.ShutdownTimeout, logger)
+
+	logger.Info("Server stopped")
+	return nil
+}
+
+// newAPIRouter wires the services and handlers of the REST API on an open store. Endpoints of
+// features whose tables schema lacks answer 503. redisCache and sketches may be nil. The returned
+// func releases the clients the router holds.
+func newAPIRouter(cfg *config.Config, store *database.Store, schema *database.SchemaStatus, redisCache *cache.RedisCache, sketches *cache.ActiveAddressSketches, logger *zap.Logger) (http.Handler, func()) {
+	var closers []func()
+
+	// Repositories
+	tokenRepo := store.Tokens
+	transferRepo := store.Transfers
+	portfolioRepo := store.Portfolio
+	swapRepo := store.Swaps
+	dailyStatsRepo := store.DailyStats
+	watchlistRepo := store.Watchlists
+	alertRuleRepo := store.AlertRules
+	denyListRepo := store.DenyList
+
+	// Create services
+	screeningService := services.NewScreeningService(denyListRepo, redisCache, logger)
+	transferService := services.NewTransferService(transferRepo, tokenRepo, redisCache, logger)
+	tokenService := services.NewTokenService(tokenRepo, redisCache, logger)
+	// Validated here as well as by doctor
+	tokenExposure, err := services.NewTokenExposure(cfg.API.ExposedTokens, cfg.API.HiddenTokens)
+	if err != nil {
+		logger.Fatal("Invalid token exposure", zap.Error(err))
+	}
+	if tokenExposure != nil {
+		tokenService.WithExposure(tokenExposure)
+		transferService.WithExposure(tokenExposure)
+	}
+	statsService := services.NewStatsService(transferRepo, tokenRepo, redisCache, logger).
+		WithAdvancedStats(store.AdvancedStats).
+		WithCacheTTL(cfg.API.StatsCacheTTL)
+	holdersService := services.NewHoldersService(transferRepo, tokenRepo, redisCache, logger).
+		WithCacheTTL(cfg.API.HoldersCacheTTL)
+	portfolioService := services.NewPortfolioService(portfolioRepo, redisCache, logger).
+		WithCacheTTL(cfg.API.PortfolioCacheTTL).
+		WithExposure(tokenExposure)
+	swapService := services.NewSwapService(swapRepo, redisCache, logger)
+	ethTransferService := services.NewEthTransferService(store.EthTransfers, redisCache, logger)
+	watchlistService := services.NewWatchlistService(watchlistRepo, transferService, logger)
+	entityService := services.NewEntityService(store.Entities, portfolioRepo, transferService, logger).WithExposure(tokenExposure)
+	methodSignatureService := services.NewMethodSignatureService(store.Signatures, logger)
+	alertService := services.NewAlertService(alertRuleRepo, notify.NewRegistryFromConfig(cfg.Alert), logger).WithTenants(store.Tenants)
+	tenantService := services.NewTenantService(store.Tenants, redisCache, logger)
+	addressNoteService := services.NewAddressNoteService(store.AddressNotes, logger)
+	contractService := services.NewContractService(store.Contracts, logger)
+
+	// Optional lookups are left out while their tables do not exist yet
+	if schema.Enabled(database.FeatureScreening) {
+		transferService.WithScreening(screeningService)
+	}
+	if schema.Enabled(database.FeatureTransferFlags) {
+		transferService.WithTransferFlags(store.TransferFlags)
+		statsService.WithTransferFlags(store.TransferFlags)
+	}
+	if schema.Enabled(database.FeatureMethodSignatures) {
+		transferService.WithMethodSignatures(store.Signatures)
+	}
+	if schema.Enabled(database.FeatureTenants) {
+		tokenService.WithTenants(store.Tenants)
+	}
+	if schema.Enabled(database.FeatureDailyStats) {
+		statsService.WithDailyStats(dailyStatsRepo)
+	}
+	if schema.Enabled(database.FeatureEntities) {
+		statsService.WithEntities(store.Entities)
+	}
+	if schema.Enabled(database.FeatureWalletStats) {
+		portfolioService.WithWalletStats(store.WalletStats)
+	}
+	if schema.Enabled(database.FeatureAddressNotes) {
+		transferService.WithAddressNotes(addressNoteService)
+		portfolioService.WithAddressNotes(addressNoteService)
+	}
+	if schema.Enabled(database.FeatureHolderSnapshots) {
+		holdersService.WithSnapshots(store.HolderSnapshots, cfg.Indexer.HolderSnapshotSize)
+	}
+
+	// Burn addresses and token contracts hold supply nobody controls
+	holderExclusions := services.HolderExclusions{ExcludeToken: cfg.API.HolderExcludeToken}
+	for _, addr := range cfg.API.HolderExclusions {
+		holderExclusions.Addresses = append(holderExclusions.Addresses, strings.ToLower(strings.TrimSpace(addr)))
+	}
+	statsService.WithHolderExclusions(holderExclusions)
+	holdersService.WithHolderExclusions(holderExclusions)
+
+	// Page sizes are validated here as well as by doctor: a bad cap would otherwise reject every request
+	pages := pageLimits(cfg)
+	if err := pages.Validate(); err != nil {
+		logger.Fatal("Invalid API page sizes", zap.Error(err))
+	}
+	holdersService.WithPageSizes(pages.Default, pages.MaxFor("holders"))
+
+	// Serve large tokens the holder count the indexer records with each snapshot
+	if cfg.API.HolderCountApproxMin > 0 && schema.Enabled(database.FeatureHolderCounts) {
+		statsService.WithHolderCounts(store.HolderCounts, cfg.API.HolderCountApproxMin)
+	}
+
+	// Ride out brief database outages: retry, then serve stale cached responses or 503
+	if cfg.API.DBBreakerThreshold > 0 {
+		breaker := services.NewBreaker(services.BreakerConfig{
+			Retries:   cfg.API.DBRetries,
+			Backoff:   cfg.API.DBRetryBackoff,
+			Threshold: cfg.API.DBBreakerThreshold,
+			Cooldown:  cfg.API.DBBreakerCooldown,
+			StaleTTL:  cfg.API.StaleCacheTTL,
+		}, database.IsTransient, logger)
+		transferService.WithBreaker(breaker)
+		tokenService.WithBreaker(breaker)
+		statsService.WithBreaker(breaker)
+		holdersService.WithBreaker(breaker)
+		portfolioService.WithBreaker(breaker)
+		swapService.WithBreaker(breaker)
+		watchlistService.WithBreaker(breaker)
+		entityService.WithBreaker(breaker)
+	}
+
+	if sketches != nil {
+		statsService.WithActiveAddresses(sketches)
+	}
+
+	// Heavy aggregate queries go to the ClickHouse analytics store when configured
+	if cfg.ClickHouse.URL != "" {
+		analytics := clickhouse.NewAnalyticsRepo(clickhouse.NewClient(cfg.ClickHouse))
+		statsService.WithAnalytics(analytics)
+		holdersService.WithAnalytics(analytics)
+		logger.Info("Reading analytics from ClickHouse")
+	}
+
+	// Price oracle for ?include_usd=true (optional)
+	if cfg.Price.Enabled {
+		priceProvider, closePrices, err := newPriceProvider(cfg, redisCache, logger)
+		if err != nil {
+			logger.Fatal("Failed to create price provider", zap.Error(err))
+		}
+		closers = append(closers, closePrices)
+
+		transferService.WithPriceProvider(priceProvider)
+		statsService.WithPriceProvider(priceProvider)
+		portfolioService.WithPriceProvider(priceProvider)
+	}
+
+	// Native ETH balances for ?include_native=true (optional)
+	if cfg.API.NativeBalanceEnabled {
+		ethClient, err := bootstrap.ConnectRPC(context.Background(), cfg, logger)
+		if err != nil {
+			logger.Fatal("Failed to connect to Ethereum node", zap.Error(err))
+		}
+		closers = append(closers, ethClient.Close)
+
+		portfolioService.WithNativeBalances(ethClient, cfg.API.NativeBalanceCacheTTL)
+		logger.Info("Native balance lookups enabled")
+	}
+
+	// Wallet exports share object storage across API instances, so any of them can serve a finished file
+	var exportHandler *handlers.ExportHandler
+	if cfg.API.ExportURL != "" {
+		exportCfg := cfg.Archive
+		exportCfg.URL = cfg.API.ExportURL
+		exportStore, err := archive.Open(exportCfg)
+		if err != nil {
+			logger.Fatal("Failed to open export storage", zap.Error(err))
+		}
+		exportService := services.NewExportService(store.Transfers, exportStore, cfg.API.ExportWorkers, logger).WithExposure(tokenExposure)
+		closers = append(closers, exportService.Close)
+		exportHandler = handlers.NewExportHandler(exportService, logger)
+	}
+
+	// Announcements of tokens with new transfers, from the indexer or the database
+	indexedTokens, err := newIndexedTokenSource(cfg, redisCache, logger)
+	if err != nil {
+		logger.Fatal("Invalid API_INDEXED_EVENTS", zap.Error(err))
+	}
+	eventsCtx, stopEvents := context.WithCancel(context.Background())
+	closers = append(closers, stopEvents)
+
+	// Keep the summaries of the most requested wallets cached (optional)
+	if cfg.API.HotWallets > 0 && redisCache != nil {
+		if err := services.CheckHotWalletsInterval(cfg.API.HotWalletsInterval); err != nil {
+			logger.Fatal("Invalid API_HOT_WALLETS_INTERVAL", zap.Error(err))
+		}
+		hotWallets := services.NewHotWallets(redisCache, portfolioService, cfg.API.HotWallets, cfg.API.HotWalletsInterval, logger)
+		portfolioService.WithHotWallets(hotWallets)
+		go hotWallets.Run(eventsCtx)
+	}
+
+	// Reload hot cached responses when the indexer announces new transfers (optional)
+	if len(cfg.API.CacheWarmTargets) > 0 && redisCache != nil && indexedTokens != nil {
+		if err := services.CheckWarmTargets(cfg.API.CacheWarmTargets); err != nil {
+			logger.Fatal("Invalid API_CACHE_WARM_TARGETS", zap.Error(err))
+		}
+		warmer := services.NewCacheWarmer(redisCache, cfg.API.CacheWarmTargets, cfg.API.CacheWarmInterval, logger).
+			WithStats(statsService).
+			WithHolders(holdersService).
+			WithTransfers(transferService, pages.DefaultFor("transfers"))
+		if tokens := indexedTokens(eventsCtx); tokens != nil {
+			go warmer.Run(eventsCtx, tokens)
+		}
+	}
+
+	// Create handlers
+	transferHandler := handlers.NewTransferHandler(transferService, logger).WithPageLimits(pages)
+	tokenHandler := handlers.NewTokenHandler(tokenService, logger).WithPageLimits(pages).
+		WithMetadataHistoryGate(requireFeature(schema, database.FeatureMetadataHistory))
+	statsHandler := handlers.NewStatsHandler(statsService, logger)
+	holdersHandler := handlers.NewHoldersHandler(holdersService, logger).WithPageLimits(pages)
+	portfolioHandler := handlers.NewPortfolioHandler(portfolioService, logger)
+	swapHandler := handlers.NewSwapHandler(swapService, logger).WithPageLimits(pages)
+	ethTransferHandler := handlers.NewEthTransferHandler(ethTransferService, logger).WithPageLimits(pages)
+	watchlistHandler := handlers.NewWatchlistHandler(watchlistService, transferService, logger).WithPageLimits(pages)
+	entityHandler := handlers.NewEntityHandler(entityService, transferService, logger).WithPageLimits(pages)
+	alertHandler := handlers.NewAlertHandler(alertService, logger)
+	tenantHandler := handlers.NewTenantHandler(tenantService, logger)
+	addressNoteHandler := handlers.NewAddressNoteHandler(addressNoteService, logger)
+	contractHandler := handlers.NewContractHandler(contractService, logger).WithPageLimits(pages)
+	screeningHandler := handlers.NewScreeningHandler(screeningService, logger)
+	methodSignatureHandler := handlers.NewMethodSignatureHandler(methodSignatureService, logger)
+	streamHandler := handlers.NewStreamHandler(transferService, cfg.API.StreamPollInterval, cfg.API.StreamHeartbeatInterval, logger)
+	if indexedTokens != nil {
+		if tokens := indexedTokens(eventsCtx); tokens != nil {
+			streamHandler.WithIndexedTokens(tokens)
+		}
+	}
+
+	var cacheChecker handlers.HealthChecker
+	if redisCache != nil {
+		cacheChecker = redisCache
+	}
+	healthHandler := handlers.NewHealthHandler(store, cacheChecker, logger).
+		WithFreshnessCheck(store.IndexerState, cfg.API.MaxDataAge)
+
+	// Forwarding headers are believed only from these proxies; validated here as well as by doctor
+	trustedProxies, err := cfg.API.TrustedProxyPrefixes()
+	if err != nil {
+		logger.Fatal("Invalid trusted proxies", zap.Error(err))
+	}
+
+	// Setup router
+	r := chi.NewRouter()
+
+	// Middleware stack
+	r.Use(chimiddleware.RequestID)
+	r.Use(middleware.RealIP(trustedProxies))
+	r.Use(middleware.Logger(logger))
+	r.Use(middleware.Metrics())
+	r.Use(chimiddleware.Recoverer)
+	r.Use(middleware.MaxBodySize(cfg.API.MaxBodyBytes))
+	// Requests with a tenant API key are scoped to the tenant and rate limited per tenant, others by IP
+	r.Use(middleware.Tenants(tenantService, cfg.API.RateLimitRPS))
+	// Retried write requests with the same Idempotency-Key get the first response, shared across instances
+	if redisCache != nil && cfg.API.IdempotencyTTL > 0 {
+		r.Use(middleware.Idempotency(redisCache, cfg.API.IdempotencyTTL))
+	}
+	// Raw amounts as hex or decimal parts with ?number_format=
+	r.Use(middleware.NumberFormat())
+	// Routes about tokens left out of API_EXPOSED_TOKENS, or listed in API_HIDDEN_TOKENS, answer 404
+	// except to the admin token
+	if tokenExposure != nil {
+		r.Use(middleware.TokenExposure(tokenExposure, cfg.API.AdminToken))
+	}
+
+	// Health endpoints (no rate limiting)
+	r.Get("/health", healthHandler.Health)
+	r.Get("/ready", healthHandler.Ready)
+	r.Get("/live", healthHandler.Live)
+	r.Handle("/metrics", promhttp.Handler())
+
+	// API routes
+	r.Route("/api/v1", func(r chi.Router) {
+		// Offset-paginated transfer feeds are superseded by the cursor-paginated v2 feed
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Deprecated("/api/v2/transfers", cfg.API.V1Sunset))
+			transferHandler.RegisterRoutes(r)
+		})
+		tokenHandler.RegisterRoutes(r)
+		portfolioHandler.RegisterRoutes(r)
+		streamHandler.RegisterRoutes(r)
+		// Features whose tables do not exist yet answer 503 until the database is migrated
+		r.With(requireFeature(schema, database.FeatureSwaps)).Group(swapHandler.RegisterRoutes)
+		r.With(requireFeature(schema, database.FeatureWatchlists)).Group(watchlistHandler.RegisterRoutes)
+		r.With(requireFeature(schema, database.FeatureEntities)).Group(entityHandler.RegisterRoutes)
+		r.With(requireFeature(schema, database.FeatureAlerts)).Group(alertHandler.RegisterRoutes)
+		r.With(requireFeature(schema, database.FeatureAddressNotes)).Group(addressNoteHandler.RegisterRoutes)
+		r.With(requireFeature(schema, database.FeatureScreening)).Group(screeningHandler.RegisterRoutes)
+		r.With(requireFeature(schema, database.FeatureMethodSignatures)).Group(methodSignatureHandler.RegisterRoutes)
+		r.With(requireFeature(schema, database.FeatureContractEvents)).Group(contractHandler.RegisterRoutes)
+		// Native ETH transfers are only captured when the indexer traces blocks
+		if cfg.Indexer.TraceMethod != "" {
+			r.With(requireFeature(schema, database.FeatureEthTransfers)).Group(ethTransferHandler.RegisterRoutes)
+		}
+
+		// Aggregations and exports may outlast API_WRITE_TIMEOUT, up to API_HEAVY_ROUTE_TIMEOUT
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(cfg.API.HeavyRouteTimeout))
+			if exportHandler != nil {
+				exportHandler.RegisterRoutes(r)
+			}
+			r.Get("/tokens/{address}/stats", statsHandler.GetTokenStats)
+			r.With(requireFeature(schema, database.FeatureAdvancedStats)).Get("/tokens/{address}/stats/advanced", statsHandler.GetAdvancedStats)
+			r.With(requireFeature(schema, database.FeatureEntities)).Get("/tokens/{address}/flows", statsHandler.GetTokenFlows)
+			r.Get("/tokens/{address}/holder-count", statsHandler.GetHolderCount)
+			r.Get("/tokens/{address}/activity/heatmap", statsHandler.GetActivityHeatmap)
+			if sketches != nil {
+				r.Get("/tokens/{address}/active-addresses", statsHandler.GetActiveAddresses)
+			}
+			r.Get("/tokens/{address}/holders", holdersHandler.GetTopHolders)
+			r.With(requireFeature(schema, database.FeatureHolderSnapshots)).Get("/tokens/{address}/holders/changes", holdersHandler.GetHolderChanges)
+			r.With(requireFeature(schema, database.FeatureHolderSnapshots)).Get("/tokens/{address}/holders/compare", holdersHandler.CompareHolderSnapshots)
+			r.With(requireFeature(schema, database.FeatureNewHolders)).Get("/tokens/{address}/holders/new", statsHandler.GetNewHolders)
+			r.Get("/tokens/{address}/holders/{holder_address}", holdersHandler.GetHolderBalance)
+			r.Get("/tokens/{address}/holders/{holder_address}/history", holdersHandler.GetHolderHistory)
+			r.Get("/wallets/{address}/graph", portfolioHandler.GetWalletGraph)
+		})
+
+		// Admin routes are only served when an admin token is configured
+		if cfg.API.AdminToken != "" {
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AdminAuth(cfg.API.AdminToken))
+				tokenHandler.RegisterAdminRoutes(r)
+				r.With(requireFeature(schema, database.FeatureTenants)).Group(tenantHandler.RegisterAdminRoutes)
+				r.With(requireFeature(schema, database.FeatureContractEvents)).Group(contractHandler.RegisterAdminRoutes)
+				holdersHandler.RegisterAdminRoutes(r)
+				if redisCache != nil {
+					handlers.NewCacheHandler(redisCache, logger).RegisterAdminRoutes(r)
+				}
+			})
+		}
+	})
+
+	// v2 routes: cursor pagination, {"data", "pagination"} envelopes and coded errors
+	r.Route("/api/v2", func(r chi.Router) {
+		transferHandler.RegisterV2Routes(r)
+	})
+
+	return r, func() {
+		for _, closeFn := range closers {
+			closeFn()
+		}
+	}
+}
+
+// newAPIServer creates the HTTP server for the API router on API_HOST:API_PORT
+func newAPIServer(cfg *config.Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", cfg.API.Host, cfg.API.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.API.ReadTimeout,
+		ReadHeaderTimeout: cfg.API.ReadHeaderTimeout,
+		WriteTimeout:      cfg.API.WriteTimeout,
+		MaxHeaderBytes:    cfg.API.MaxHeaderBytes,
+	}
+}
+
+// newIndexedTokenSource returns a function subscribing to the addresses of tokens with new
+// transfers: from the indexer's Redis pub/sub, or from the transfers insert trigger, which works
+// without the indexer publishing anything. It returns nil without Redis in redis mode; a
+// subscription that fails returns a nil channel.
+func newIndexedTokenSource(cfg *config.Config, redisCache *cache.RedisCache, logger *zap.Logger) (func(ctx context.Context) <-chan string, error) {
+	switch cfg.API.IndexedEvents {
+	case "redis":
+		if redisCache == nil {
+			return nil, nil
+		}
+		return func(ctx context.Context) <-chan string {
+			return redisCache.Subscribe(ctx, cache.IndexedTokensChannel)
+		}, nil
+	case "postgres":
+		if cfg.Database.Driver != "postgres" {
+			return nil, fmt.Errorf("postgres events require DB_DRIVER=postgres, not %s", cfg.Database.Driver)
+		}
+		logger.Info("Listening for new transfers in the database")
+		return func(ctx context.Context) <-chan string {
+			tokens, err := database.ListenIndexedTokens(ctx, cfg.Database, logger.Named("notify"))
+			if err != nil {
+				logger.Warn("Failed to listen for new transfers", zap.Error(err))
+				return nil
+			}
+			return tokens
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown event source %q (available: redis, postgres)", cfg.API.IndexedEvents)
+	}
+}
+
+// newPriceProvider builds the configured price provider wrapped in the Redis price cache.
+// The returned func releases any resources the provider holds.
+func newPriceProvider(cfg *config.Config, redisCache *cache.RedisCache, logger *zap.Logger) (pricing.Provider, func(), error) {
+	var provider pricing.Provider
+	closeFn := func() {}
+
+	switch cfg.Price.Provider {
+	case "coingecko":
+		provider = pricing.NewCoinGeckoProvider(cfg.Price.CoinGeckoURL, cfg.Price.CoinGeckoAPIKey)
+	case "chainlink":
+		ethClient, err := bootstrap.ConnectRPC(context.Background(), cfg, logger)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to Ethereum node: %w", err)
+		}
+		provider = ethereum.NewChainlinkPriceProvider(ethClient, cfg.Price.ChainlinkFeeds)
+		closeFn = ethClient.Close
+	default:
+		return nil, nil, fmt.Errorf("unknown price provider %q", cfg.Price.Provider)
+	}
+
+	logger.Info("USD price enrichment enabled", zap.String("provider", cfg.Price.Provider))
+	return pricing.NewCachedProvider(provider, redisCache, cfg.Price.CacheTTL, logger), closeFn, nil
+}
+
+// pageLimits returns the configured page sizes of list endpoints
+func pageLimits(cfg *config.Config) handlers.PageLimits {
+	return handlers.PageLimits{
+		Default:     cfg.API.DefaultPageSize,
+		Max:         cfg.API.MaxPageSize,
+		EndpointMax: cfg.API.EndpointMaxPageSize,
+	}
+}
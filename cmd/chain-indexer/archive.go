@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/archive"
+)
+
+// newArchiveCmd returns the `chain-indexer archive` command
+func newArchiveCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Export closed block ranges to the archive",
+		Args:  cobra.NoArgs,
+	}
+	token := cmd.Flags().String("token", "", "token contract address (default: all configured tokens)")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runArchive(cfg, logger, *token)
+	})
+	return cmd
+}
+
+// runArchive implements `chain-indexer archive [--token X]`.
+// It exports every closed block range not yet in the archive manifest once.
+func runArchive(cfg *config.Config, logger *zap.Logger, token string) error {
+	tokens := cfg.Indexer.TokenAddresses
+	if token != "" {
+		if !common.IsHexAddress(token) {
+			return usageErrorf("--token must be a valid contract address")
+		}
+		tokens = []string{token}
+	}
+
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	archiveService, closeStore, err := newArchiveService(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	if err := archiveService.ArchiveAll(ctx, tokens); err != nil {
+		return errFailed
+	}
+
+	fmt.Fprintf(os.Stderr, "archive: done for %d token(s)\n", len(tokens))
+	return nil
+}
+
+// newRestoreCmd returns the `chain-indexer restore` command
+func newRestoreCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore transfers from the archive",
+		Args:  cobra.NoArgs,
+	}
+	token := cmd.Flags().String("token", "", "token contract address to restore")
+	fromBlock := cmd.Flags().Int64("from", -1, "first block of the range (inclusive)")
+	toBlock := cmd.Flags().Int64("to", -1, "last block of the range (inclusive)")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runRestore(cfg, logger, *token, *fromBlock, *toBlock)
+	})
+	return cmd
+}
+
+// runRestore implements `chain-indexer restore --token X --from A --to B`.
+// It replaces stored transfers with the archived files overlapping the range.
+func runRestore(cfg *config.Config, logger *zap.Logger, token string, fromBlock, toBlock int64) error {
+	if !common.IsHexAddress(token) {
+		return usageErrorf("--token must be a valid contract address")
+	}
+	if fromBlock < 0 || toBlock < fromBlock {
+		return usageErrorf("--from and --to must form a valid block range")
+	}
+	tokenAddress := strings.ToLower(token)
+
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	archiveService, closeStore, err := newArchiveService(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	result, err := archiveService.Restore(ctx, tokenAddress, fromBlock, toBlock)
+	if err != nil {
+		logger.Error("Restore failed", zap.Error(err))
+		return errFailed
+	}
+	if result.Files == 0 {
+		fmt.Fprintf(os.Stderr, "restore: no archived files for %s in blocks %d-%d\n", tokenAddress, fromBlock, toBlock)
+		return errFailed
+	}
+
+	fmt.Fprintf(os.Stderr, "restore: done, %d file(s), blocks %d-%d, %d deleted, %d restored\n",
+		result.Files, result.FromBlock, result.ToBlock, result.Deleted, result.Restored)
+	fmt.Fprintln(os.Stderr, "restore: run `chain-indexer rollup` for the restored days to refresh daily stats")
+	return nil
+}
+
+// newArchiveService opens the database and archive store. On failure it returns the command's error.
+func newArchiveService(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*services.ArchiveService, func(), error) {
+	if cfg.Archive.URL == "" {
+		return nil, nil, usageErrorf("ARCHIVE_URL is not set")
+	}
+
+	objectStore, err := openArchiveStore(cfg.Archive)
+	if err != nil {
+		return nil, nil, usageErrorf("%v", err)
+	}
+
+	store, err := bootstrap.OpenStore(ctx, cfg, cfg.Database.ForIndexer(), logger)
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return nil, nil, errFailed
+	}
+
+	archiveService := services.NewArchiveService(
+		store.Transfers,
+		store.Tokens,
+		store.IndexerState,
+		objectStore,
+		cfg.Archive.RangeSize,
+		logger,
+	)
+	return archiveService, func() { _ = store.Close() }, nil
+}
+
+// openArchiveStore validates the archive configuration and opens its object store
+func openArchiveStore(cfg config.ArchiveConfig) (archive.ObjectStore, error) {
+	if cfg.RangeSize <= 0 {
+		return nil, fmt.Errorf("ARCHIVE_RANGE_SIZE must be positive")
+	}
+	return archive.Open(cfg)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// newBackfillCmd returns the `chain-indexer backfill` command
+func newBackfillCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Index a block range of one token",
+		Args:  cobra.NoArgs,
+	}
+	token := cmd.Flags().String("token", "", "token contract address to backfill")
+	fromBlock := cmd.Flags().Int64("from", -1, "first block of the range (inclusive)")
+	toBlock := cmd.Flags().Int64("to", -1, "last block of the range (inclusive, default: the confirmed head)")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runBackfill(cfg, logger, *token, *fromBlock, *toBlock)
+	})
+	return cmd
+}
+
+// runBackfill implements `chain-indexer backfill --token X --from A [--to B]`.
+// It indexes the range once, alongside or instead of a running indexer.
+func runBackfill(cfg *config.Config, logger *zap.Logger, token string, fromBlock, toBlock int64) error {
+	if !common.IsHexAddress(token) {
+		return usageErrorf("--token must be a valid contract address")
+	}
+	if fromBlock < 0 || (toBlock >= 0 && toBlock < fromBlock) {
+		return usageErrorf("--from and --to must form a valid block range")
+	}
+	tokenAddress := strings.ToLower(token)
+	if err := services.CheckBackfillPriority(cfg.Indexer.BackfillPriority); err != nil {
+		logger.Error("Invalid INDEXER_BACKFILL_PRIORITY", zap.Error(err))
+		return errFailed
+	}
+
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	store, err := bootstrap.OpenStore(ctx, cfg, cfg.Database.ForIndexer(), logger)
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return errFailed
+	}
+	defer store.Close()
+
+	ethClient, err := bootstrap.ConnectRPC(ctx, cfg, logger)
+	if err != nil {
+		logger.Error("Failed to connect to Ethereum node", zap.Error(err))
+		return errFailed
+	}
+	defer ethClient.Close()
+
+	// The backfill may keep fewer requests in flight than the provider allows, leaving the rest to live indexing
+	if cfg.Indexer.BackfillRPCMaxConcurrent > 0 {
+		rps, maxConcurrent, _ := cfg.Ethereum.RateLimitsFor(cfg.Ethereum.RPCURL)
+		if maxConcurrent == 0 || cfg.Indexer.BackfillRPCMaxConcurrent < maxConcurrent {
+			maxConcurrent = cfg.Indexer.BackfillRPCMaxConcurrent
+		}
+		ethClient.WithLimits(rps, maxConcurrent)
+	}
+
+	// Without --to, an interrupted backfill from the same block resumes with its original end
+	if toBlock < 0 {
+		state, err := store.IndexerState.Get(ctx, tokenAddress)
+		if err != nil {
+			logger.Error("Failed to get indexer state", zap.Error(err))
+			return errFailed
+		}
+		if state != nil && state.BackfillCheckpoint != nil && state.BackfillFromBlock != nil &&
+			*state.BackfillFromBlock == fromBlock && state.BackfillToBlock != nil {
+			toBlock = *state.BackfillToBlock
+			fmt.Fprintf(os.Stderr, "backfill: resuming the interrupted backfill of blocks %d-%d\n", fromBlock, toBlock)
+		}
+	}
+	if toBlock < 0 {
+		head, err := ethClient.GetLatestBlockNumber(ctx)
+		if err != nil {
+			logger.Error("Failed to get latest block", zap.Error(err))
+			return errFailed
+		}
+		toBlock = ethereum.ConfirmedBlock(int64(head), cfg.Indexer.ConfirmationsFor(tokenAddress))
+		if toBlock < fromBlock {
+			return usageErrorf("--from is past the confirmed head %d", toBlock)
+		}
+	}
+
+	// Only the backfilled token needs a tokens row
+	indexerCfg := cfg.Indexer
+	indexerCfg.TokenAddresses = []string{tokenAddress}
+
+	indexerService := services.NewIndexerService(
+		ethereum.NewFetcher(ethClient, indexerCfg, logger.Named("ethereum")),
+		ethereum.NewMetadataFetcher(ethClient, logger.Named("ethereum")),
+		store.Tokens,
+		store.Transfers,
+		store.IndexerState,
+		indexerCfg,
+		logger.Named("indexer"),
+	).WithDailyStats(store.DailyStats).
+		WithWalletStats(store.WalletStats).
+		WithBackfillThrottle(cfg.Indexer.TokenAddresses)
+
+	activeAddrs, closeActiveAddrs := connectActiveAddresses(ctx, cfg, logger)
+	defer closeActiveAddrs()
+	if activeAddrs != nil {
+		indexerService.WithActiveAddresses(activeAddrs)
+	}
+
+	analytics, err := connectAnalytics(ctx, cfg)
+	if err != nil {
+		logger.Error("Failed to connect to ClickHouse", zap.Error(err))
+		return errFailed
+	}
+	if analytics != nil {
+		indexerService.WithAnalytics(analytics)
+	}
+	if cfg.Indexer.StoreRawLogs {
+		indexerService.WithRawLogs(store.RawLogs)
+	}
+
+	if err := indexerService.InitializeTokens(ctx); err != nil {
+		logger.Error("Failed to initialize token", zap.Error(err))
+		return errFailed
+	}
+	if err := indexerService.Backfill(ctx, tokenAddress, fromBlock, toBlock); err != nil {
+		logger.Error("Backfill failed", zap.Error(err))
+		return errFailed
+	}
+
+	fmt.Fprintf(os.Stderr, "backfill: done, indexed blocks %d-%d of %s\n", fromBlock, toBlock, tokenAddress)
+	return nil
+}
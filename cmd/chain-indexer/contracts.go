@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// registerContractModule registers decoders for the events of every contract registered by ABI,
+// reporting whether there were any
+func registerContractModule(
+	ctx context.Context,
+	fetcher *ethereum.Fetcher,
+	contractRepo repositories.ContractRepository,
+	indexerService *services.IndexerService,
+	logger *zap.Logger,
+) (bool, error) {
+	contracts, err := contractRepo.ListContracts(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, contract := range contracts {
+		parsed, err := ethereum.ParseContractABI(contract.ABI, contract.Events)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse the ABI of contract %s: %w", contract.Address, err)
+		}
+
+		for _, et := range parsed.EventTypes(contract.Address) {
+			if err := fetcher.Registry().Register(et); err != nil {
+				return false, err
+			}
+			indexerService.RegisterEventRepository(et.Name, contractRepo)
+		}
+
+		logger.Info("Indexing contract events",
+			zap.String("contract", contract.Address),
+			zap.String("name", contract.Name),
+			zap.Strings("events", parsed.EventNames()),
+		)
+	}
+
+	return len(contracts) > 0, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+// newDenyListCmd returns the `chain-indexer denylist` command
+func newDenyListCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "denylist",
+		Short: "Refresh the screening deny lists",
+		Args:  cobra.NoArgs,
+	}
+	listName := cmd.Flags().String("list", "", "name of the list to import (e.g. ofac)")
+	source := cmd.Flags().String("source", "", "http(s) URL or file with one address per line, optionally followed by ,label")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runDenyList(cfg, logger, *listName, *source)
+	})
+	return cmd
+}
+
+// runDenyList implements `chain-indexer denylist [--list NAME --source URL|FILE]`.
+// Without flags it refreshes every list in SCREENING_LISTS once; with them it imports a single list.
+func runDenyList(cfg *config.Config, logger *zap.Logger, listName, source string) error {
+	var sources map[string]string
+	switch {
+	case listName != "" && source != "":
+		sources = map[string]string{listName: source}
+	case listName != "" || source != "":
+		return usageErrorf("--list and --source must be given together")
+	default:
+		var err error
+		sources, err = cfg.Screening.Sources()
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		if len(sources) == 0 {
+			return usageErrorf("no lists configured; set SCREENING_LISTS or pass --list and --source")
+		}
+	}
+
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	store, err := bootstrap.OpenStore(ctx, cfg, cfg.Database.ForIndexer(), logger)
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return errFailed
+	}
+	defer store.Close()
+
+	screeningService := services.NewScreeningService(store.DenyList, nil, logger)
+	if err := screeningService.RefreshAll(ctx, sources); err != nil {
+		logger.Error("Deny list import failed", zap.Error(err))
+		return errFailed
+	}
+
+	fmt.Fprintf(os.Stderr, "denylist: done, refreshed %d list(s)\n", len(sources))
+	return nil
+}
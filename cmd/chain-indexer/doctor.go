@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/clickhouse"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/database"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// newDoctorCmd returns the `chain-indexer doctor` command
+func newDoctorCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check configuration, dependencies, schema and lag",
+		Args:  cobra.NoArgs,
+	}
+	timeout := cmd.Flags().Duration("timeout", 30*time.Second, "time limit for each connection check")
+	maxLag := cmd.Flags().Int64("max-lag", 100, "blocks behind the confirmed head reported as a warning")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runDoctor(cfg, logger, *timeout, *maxLag)
+	})
+	return cmd
+}
+
+// runDoctor implements `chain-indexer doctor [--timeout 30s] [--max-lag 100]`.
+// It validates the configuration, connects to every dependency, checks the schema version and the
+// configured tokens, and estimates how far behind the chain head indexing is. The report goes to
+// stdout; the exit code is 1 if any check failed, so it can gate a deployment.
+func runDoctor(cfg *config.Config, logger *zap.Logger, timeout time.Duration, maxLag int64) error {
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	// Connection logs would interleave with the report; failures are reported by the checks
+	quiet := zap.NewNop()
+	report := &doctorReport{w: os.Stdout}
+
+	checkConfig(cfg, report)
+
+	var store *database.Store
+	withTimeout(ctx, timeout, func(ctx context.Context) {
+		var err error
+		store, err = database.Open(cfg.Database.ForIndexer(), quiet)
+		if cfg.Database.Driver == "sqlite" {
+			if err != nil {
+				report.fail("sqlite", err.Error(), "check DB_PATH is writable, and that the binary was built with cgo")
+				return
+			}
+			report.ok("sqlite", "opened %s", cfg.Database.Path)
+		} else {
+			if err != nil {
+				report.fail("postgres", err.Error(), "check DB_HOST, DB_PORT, DB_USER, DB_PASSWORD and DB_NAME, and that PostgreSQL is running")
+				return
+			}
+			report.ok("postgres", "connected to %s at %s:%d", cfg.Database.Name, cfg.Database.Host, cfg.Database.Port)
+		}
+		checkSchema(ctx, store, report)
+	})
+	if store != nil {
+		defer store.Close()
+	}
+
+	withTimeout(ctx, timeout, func(ctx context.Context) {
+		redisCache, err := cache.NewRedisCache(cfg.Redis, 0, quiet)
+		if err != nil {
+			report.warn("redis", err.Error(), "the API runs without a cache and the indexer without active address tracking; check REDIS_HOST and REDIS_PORT")
+			return
+		}
+		defer redisCache.Close()
+		report.ok("redis", "connected to %s:%d", cfg.Redis.Host, cfg.Redis.Port)
+	})
+
+	if cfg.ClickHouse.URL != "" {
+		withTimeout(ctx, timeout, func(ctx context.Context) {
+			if err := clickhouse.NewClient(cfg.ClickHouse).Ping(ctx); err != nil {
+				report.warn("clickhouse", err.Error(), "the API falls back to PostgreSQL for analytics; check CLICKHOUSE_URL and credentials")
+				return
+			}
+			report.ok("clickhouse", "connected to %s", cfg.ClickHouse.URL)
+		})
+	}
+
+	withTimeout(ctx, timeout, func(ctx context.Context) {
+		ethClient, err := ethereum.NewClient(cfg.Ethereum, quiet)
+		if err != nil {
+			report.fail("rpc", err.Error(), "check ETH_RPC_URL and that ETH_CHAIN_ID matches the node's chain")
+			return
+		}
+		defer ethClient.Close()
+
+		head, err := ethClient.GetLatestBlockNumber(ctx)
+		if err != nil {
+			report.fail("rpc", err.Error(), "the node accepted the connection but cannot serve eth_blockNumber")
+			return
+		}
+		report.ok("rpc", "chain %d, head block %d", cfg.Ethereum.ChainID, head)
+
+		checkTokens(ctx, cfg, ethereum.NewMetadataFetcher(ethClient, quiet), report)
+		if store != nil {
+			checkLag(ctx, cfg, store, ethClient, head, maxLag, report)
+		}
+	})
+
+	return report.summary()
+}
+
+// withTimeout runs fn with ctx limited to timeout
+func withTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context)) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	fn(ctx)
+}
+
+// checkConfig validates settings envconfig cannot: addresses, enumerations and pool sizes
+func checkConfig(cfg *config.Config, report *doctorReport) {
+	failed := report.failed
+
+	if len(cfg.Indexer.TokenAddresses) == 0 {
+		report.fail("config", "INDEXER_TOKEN_ADDRESSES is empty", "list the token contracts to index, comma-separated")
+	}
+	for _, addr := range cfg.Indexer.TokenAddresses {
+		if !common.IsHexAddress(addr) {
+			report.fail("config", fmt.Sprintf("INDEXER_TOKEN_ADDRESSES entry %q is not an address", addr), "use 0x-prefixed 40 hex digit addresses")
+		}
+	}
+	for _, addr := range cfg.Indexer.DexPools {
+		if !common.IsHexAddress(addr) {
+			report.fail("config", fmt.Sprintf("INDEXER_DEX_POOLS entry %q is not an address", addr), "use 0x-prefixed 40 hex digit addresses")
+		}
+	}
+
+	switch cfg.Indexer.TraceMethod {
+	case "", ethereum.TraceMethodDebug, ethereum.TraceMethodTrace:
+	default:
+		report.fail("config", fmt.Sprintf("INDEXER_TRACE_METHOD %q is not supported", cfg.Indexer.TraceMethod), "use debug, trace or leave it empty")
+	}
+
+	if cfg.Price.Enabled && cfg.Price.Provider != "coingecko" && cfg.Price.Provider != "chainlink" {
+		report.fail("config", fmt.Sprintf("PRICE_PROVIDER %q is not supported", cfg.Price.Provider), "use coingecko or chainlink")
+	}
+
+	if _, _, err := cfg.Ethereum.RateLimitsFor(cfg.Ethereum.RPCURL); err != nil {
+		report.fail("config", err.Error(), "")
+	}
+	if _, err := cfg.Screening.Sources(); err != nil {
+		report.fail("config", err.Error(), "")
+	}
+
+	for addr, confirmations := range cfg.Indexer.TokenConfirmations {
+		switch {
+		case !common.IsHexAddress(addr):
+			report.fail("config", fmt.Sprintf("INDEXER_TOKEN_CONFIRMATIONS entry %q is not an address", addr), "use address:confirmations pairs")
+		case confirmations < 0:
+			report.fail("config", fmt.Sprintf("INDEXER_TOKEN_CONFIRMATIONS entry for %s is negative", addr), "")
+		case !slices.ContainsFunc(cfg.Indexer.TokenAddresses, func(token string) bool { return strings.EqualFold(token, addr) }):
+			report.warn("config", fmt.Sprintf("INDEXER_TOKEN_CONFIRMATIONS lists %s, which is not in INDEXER_TOKEN_ADDRESSES", addr), "")
+		}
+	}
+
+	if cfg.Indexer.BatchSize <= 0 || cfg.Indexer.BackfillBatchSize <= 0 || cfg.Indexer.WorkerCount <= 0 || cfg.Indexer.PipelineDepth <= 0 {
+		report.fail("config", "INDEXER_BATCH_SIZE, INDEXER_BACKFILL_BATCH_SIZE, INDEXER_WORKER_COUNT and INDEXER_PIPELINE_DEPTH must be positive", "")
+	}
+	if cfg.Indexer.BackfillBlocksPerMinute < 0 || cfg.Indexer.BackfillRPCMaxConcurrent < 0 {
+		report.fail("config", "INDEXER_BACKFILL_BLOCKS_PER_MINUTE and INDEXER_BACKFILL_RPC_MAX_CONCURRENT must not be negative", "")
+	}
+	if cfg.Indexer.AdvancedStatsInterval > 0 && (cfg.Indexer.VelocityWindow <= 0 || cfg.Indexer.DormantAfter <= 0) {
+		report.fail("config", "INDEXER_VELOCITY_WINDOW and INDEXER_DORMANT_AFTER must be positive", "")
+	}
+	if err := services.CheckBackfillPriority(cfg.Indexer.BackfillPriority); err != nil {
+		report.fail("config", "invalid INDEXER_BACKFILL_PRIORITY: "+err.Error(), "")
+	}
+	if err := services.CheckFailedRangePolicy(cfg.Indexer.FailedRangePolicy); err != nil {
+		report.fail("config", "invalid INDEXER_FAILED_RANGE_POLICY: "+err.Error(), "")
+	}
+	if pool, workers := cfg.Database.ForIndexer().MaxOpenConns, max(cfg.Indexer.WorkerCount, cfg.Indexer.WorkerCountMax); pool > 0 && pool < workers {
+		report.warn("config", fmt.Sprintf("indexer database pool (%d) is smaller than the indexer's %d workers (INDEXER_WORKER_COUNT, INDEXER_WORKER_COUNT_MAX)", pool, workers),
+			"raise DB_INDEXER_MAX_OPEN_CONNS so workers do not wait for connections")
+	}
+
+	if _, err := cfg.API.TrustedProxyPrefixes(); err != nil {
+		report.fail("config", err.Error(), "list proxies as IPs or CIDRs, e.g. 10.0.0.0/8")
+	}
+	if _, err := services.NewTokenExposure(cfg.API.ExposedTokens, cfg.API.HiddenTokens); err != nil {
+		report.fail("config", "invalid token exposure: "+err.Error(), "set one of API_EXPOSED_TOKENS and API_HIDDEN_TOKENS to token addresses")
+	}
+	if err := services.CheckWarmTargets(cfg.API.CacheWarmTargets); err != nil {
+		report.fail("config", "invalid API_CACHE_WARM_TARGETS: "+err.Error(), "")
+	}
+	if cfg.API.HotWallets > 0 {
+		if err := services.CheckHotWalletsInterval(cfg.API.HotWalletsInterval); err != nil {
+			report.fail("config", "invalid API_HOT_WALLETS_INTERVAL: "+err.Error(), "")
+		}
+	}
+	if err := pageLimits(cfg).Validate(); err != nil {
+		report.fail("config", "invalid API page sizes: "+err.Error(), "check API_DEFAULT_PAGE_SIZE, API_MAX_PAGE_SIZE and API_ENDPOINT_MAX_PAGE_SIZE")
+	}
+
+	if report.failed == failed {
+		report.ok("config", "%d token(s), %d DEX pool(s)", len(cfg.Indexer.TokenAddresses), len(cfg.Indexer.DexPools))
+	}
+}
+
+// checkSchema compares the applied migration version with the one this build expects, and
+// checks that the tables of every feature exist
+func checkSchema(ctx context.Context, store *database.Store, report *doctorReport) {
+	version, dirty, err := store.SchemaVersion(ctx)
+	switch {
+	case errors.Is(err, database.ErrNoSchemaVersion):
+		report.warn("schema", "migrations were not applied with `chain-indexer migrate` or golang-migrate, so the version is unknown",
+			fmt.Sprintf("make sure every migration up to %06d has been applied, then run `chain-indexer migrate force %d`", database.SchemaVersion, database.SchemaVersion))
+	case err != nil:
+		report.fail("schema", err.Error(), "")
+	case dirty:
+		report.fail("schema", fmt.Sprintf("migration %d failed halfway (dirty)", version),
+			"fix the schema by hand, then run `chain-indexer migrate force` with the last clean version")
+	case version < database.SchemaVersion:
+		report.fail("schema", fmt.Sprintf("version %d, this build expects %d", version, database.SchemaVersion), "run `chain-indexer migrate up`")
+	case version > database.SchemaVersion:
+		report.warn("schema", fmt.Sprintf("version %d is newer than this build (%d)", version, database.SchemaVersion), "deploy the matching build")
+	default:
+		report.ok("schema", "version %d", version)
+	}
+
+	// Tables are checked as well, since migrations may have been applied by other means
+	schema, err := database.CheckSchema(ctx, store)
+	switch {
+	case err != nil:
+		report.fail("schema", err.Error(), "")
+	case len(schema.Disabled) > 0:
+		report.fail("schema", "tables missing for "+strings.Join(schema.DisabledFeatures(), ", "),
+			"run `chain-indexer migrate up`; until then the api and indexer only start with --allow-degraded-schema")
+	}
+}
+
+// checkTokens verifies each configured token answers ERC-20 calls
+func checkTokens(ctx context.Context, cfg *config.Config, metadata *ethereum.MetadataFetcher, report *doctorReport) {
+	for _, addr := range cfg.Indexer.TokenAddresses {
+		if !common.IsHexAddress(addr) {
+			continue // Reported by checkConfig
+		}
+		token, err := metadata.VerifyERC20(ctx, addr)
+		if err != nil {
+			report.fail("token "+addr, err.Error(), fmt.Sprintf("make sure it is an ERC-20 contract on chain %d", cfg.Ethereum.ChainID))
+			continue
+		}
+		report.ok("token "+addr, "%s, %d decimals", token.Symbol, token.Decimals)
+	}
+}
+
+// checkLag estimates how far each token's indexing is behind the confirmed chain head
+func checkLag(ctx context.Context, cfg *config.Config, store *database.Store, ethClient *ethereum.Client, head uint64, maxLag int64, report *doctorReport) {
+	for _, addr := range cfg.Indexer.TokenAddresses {
+		if !common.IsHexAddress(addr) {
+			continue
+		}
+		addr = strings.ToLower(addr)
+		check := "lag " + addr
+		confirmed := ethereum.ConfirmedBlock(int64(head), cfg.Indexer.ConfirmationsFor(addr))
+
+		state, err := store.IndexerState.Get(ctx, addr)
+		if err != nil {
+			report.fail(check, err.Error(), "")
+			continue
+		}
+		if state == nil || state.LastIndexedBlock == 0 {
+			report.warn(check, "not indexed yet", "the indexer backfills it on start")
+			continue
+		}
+
+		behind := confirmed - state.LastIndexedBlock
+		if behind <= maxLag {
+			report.ok(check, "%d block(s) behind the confirmed head", max(behind, 0))
+			continue
+		}
+
+		detail := fmt.Sprintf("%d blocks behind the confirmed head", behind)
+		headTime, headErr := ethClient.GetBlockTimestamp(ctx, uint64(confirmed))
+		lastTime, lastErr := ethClient.GetBlockTimestamp(ctx, uint64(state.LastIndexedBlock))
+		if headErr == nil && lastErr == nil {
+			detail += fmt.Sprintf(" (%s of chain time)", headTime.Sub(lastTime).Round(time.Minute))
+		}
+		report.warn(check, detail, fmt.Sprintf("about %d backfill batches; raise INDEXER_BACKFILL_CONCURRENCY to catch up faster",
+			(behind+int64(cfg.Indexer.BackfillBatchSize)-1)/int64(cfg.Indexer.BackfillBatchSize)))
+	}
+}
+
+// doctorReport prints one line per check, with a hint under warnings and failures
+type doctorReport struct {
+	w      io.Writer
+	failed int
+	warned int
+}
+
+func (r *doctorReport) ok(check, format string, args ...any) {
+	fmt.Fprintf(r.w, "[ OK ] %-20s %s\n", check, fmt.Sprintf(format, args...))
+}
+
+func (r *doctorReport) warn(check, detail, hint string) {
+	r.warned++
+	r.line("WARN", check, detail, hint)
+}
+
+func (r *doctorReport) fail(check, detail, hint string) {
+	r.failed++
+	r.line("FAIL", check, detail, hint)
+}
+
+func (r *doctorReport) line(status, check, detail, hint string) {
+	fmt.Fprintf(r.w, "[%s] %-20s %s\n", status, check, detail)
+	if hint != "" {
+		fmt.Fprintf(r.w, "       %-20s -> %s\n", "", hint)
+	}
+}
+
+// summary prints the totals and returns errFailed if any check failed
+func (r *doctorReport) summary() error {
+	fmt.Fprintf(r.w, "\n%d failed, %d warning(s)\n", r.failed, r.warned)
+	if r.failed > 0 {
+		return errFailed
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+// demoRPCURL is the public mainnet endpoint embedded mode uses when ETH_RPC_URL is not set
+const demoRPCURL = "https://ethereum-rpc.publicnode.com"
+
+// newEmbeddedCmd returns the `chain-indexer embedded` command
+func newEmbeddedCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "embedded",
+		Short: "Run the indexer and API in one process on SQLite, for demos",
+		Args:  cobra.NoArgs,
+	}
+	dir := cmd.Flags().String("dir", "", "directory for the SQLite file (default: a temporary directory removed on exit)")
+	rpcURL := cmd.Flags().String("rpc", "", "RPC endpoint (default: ETH_RPC_URL, or "+demoRPCURL+" when it is not set)")
+	recent := cmd.Flags().Int64("recent", 1000, "blocks of history indexed for tokens not yet in the database")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runEmbedded(cfg, logger, *dir, *rpcURL, *recent)
+	})
+	return cmd
+}
+
+// runEmbedded runs the indexer and the API in one process on a SQLite file and an in-memory
+// cache, so the project can be tried without PostgreSQL, Redis or a node of one's own
+func runEmbedded(cfg *config.Config, logger *zap.Logger, dir, rpcURL string, recent int64) error {
+	if dir == "" {
+		tmp, err := os.MkdirTemp("", "chain-indexer-")
+		if err != nil {
+			logger.Error("Failed to create data directory", zap.Error(err))
+			return errFailed
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+	}
+	cfg.Database.Driver = "sqlite"
+	cfg.Database.Path = filepath.Join(dir, "chain-indexer.db")
+
+	switch {
+	case rpcURL != "":
+		cfg.Ethereum.RPCURL = rpcURL
+	case os.Getenv("ETH_RPC_URL") == "":
+		cfg.Ethereum.RPCURL = demoRPCURL
+		// Stay well inside the public endpoint's limits unless throttling was configured
+		if cfg.Ethereum.RPCRateLimit == 0 {
+			cfg.Ethereum.RPCRateLimit = 5
+		}
+	}
+
+	logger.Info("Starting chain-indexer in embedded mode",
+		zap.String("database", cfg.Database.Path),
+		zap.String("rpc_url", cfg.Ethereum.RPCURL),
+		zap.Strings("tokens", cfg.Indexer.TokenAddresses),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store, err := bootstrap.OpenStore(ctx, cfg, cfg.Database, logger)
+	if err != nil {
+		logger.Error("Failed to open database", zap.Error(err))
+		return errFailed
+	}
+	defer store.Close()
+	// The SQLite schema is created by this build, so it is never degraded
+	schema := checkSchemaCompatibility(store, false, logger)
+
+	// Index recent history only: a new token would otherwise start at genesis
+	if cfg.Indexer.StartBlock == 0 && recent > 0 {
+		head, err := latestBlock(ctx, cfg, logger)
+		if err != nil {
+			logger.Error("Failed to connect to Ethereum node", zap.Error(err))
+			return errFailed
+		}
+		cfg.Indexer.StartBlock = max(head-recent, 1)
+	}
+
+	// The indexer announces new transfers on the API's cache, so cache warming works in one process
+	memCache := cache.NewMemoryCache(cfg.API.CacheTTL, logger.Named("cache"))
+
+	indexerService, closeIndexer := startIndexer(ctx, cfg, store, schema, nil, memCache, logger)
+	defer closeIndexer()
+
+	router, closeRouter := newAPIRouter(cfg, store, schema, memCache, nil, logger)
+	defer closeRouter()
+
+	server := newAPIServer(cfg, router)
+	bootstrap.Serve(server, logger)
+
+	// Wait for shutdown signal
+	bootstrap.WaitForSignal()
+
+	logger.Info("Received shutdown signal, stopping...")
+
+	bootstrap.Shutdown(server, cfg.API.ShutdownTimeout, logger)
+	indexerService.Stop()
+
+	logger.Info("Embedded mode stopped")
+	return nil
+}
+
+// latestBlock returns the node's head block number
+func latestBlock(ctx context.Context, cfg *config.Config, logger *zap.Logger) (int64, error) {
+	client, err := bootstrap.ConnectRPC(ctx, cfg, logger)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	head, err := client.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int64(head), nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+// newFailedRangesCmd returns the `chain-indexer failed-ranges` command
+func newFailedRangesCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "failed-ranges",
+		Short: "List, repair or resolve block ranges that kept failing",
+		Args:  cobra.NoArgs,
+	}
+	all := cmd.Flags().Bool("all", false, "list resolved ranges too")
+	repair := cmd.Flags().Bool("repair", false, "reindex the skipped ranges and mark them resolved")
+	resolve := cmd.Flags().Int64("resolve", 0, "mark the range with this ID resolved")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runFailedRanges(cfg, logger, *all, *repair, *resolve)
+	})
+	return cmd
+}
+
+// runFailedRanges implements `chain-indexer failed-ranges [--all | --repair | --resolve ID]`.
+// Without flags it lists the unresolved block ranges the indexer recorded after they kept failing.
+// --repair reindexes the unresolved ranges the indexer skipped and marks each resolved once it
+// succeeds; --resolve marks one range resolved without reindexing it.
+func runFailedRanges(cfg *config.Config, logger *zap.Logger, all, repair bool, resolve int64) error {
+	if repair && resolve != 0 {
+		return usageErrorf("--repair and --resolve cannot be combined")
+	}
+
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	store, err := bootstrap.OpenStore(ctx, cfg, cfg.Database.ForIndexer(), logger)
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return errFailed
+	}
+	defer store.Close()
+
+	if resolve != 0 {
+		found, err := store.FailedRanges.Resolve(ctx, resolve)
+		if err != nil {
+			logger.Error("Failed to resolve range", zap.Error(err))
+			return errFailed
+		}
+		if !found {
+			fmt.Fprintf(os.Stderr, "failed-ranges: no unresolved range with ID %d\n", resolve)
+			return errFailed
+		}
+		fmt.Fprintf(os.Stderr, "failed-ranges: resolved range %d\n", resolve)
+		return nil
+	}
+
+	ranges, err := store.FailedRanges.List(ctx, !all || repair)
+	if err != nil {
+		logger.Error("Failed to list failed ranges", zap.Error(err))
+		return errFailed
+	}
+
+	if !repair {
+		for _, r := range ranges {
+			state := "retrying"
+			switch {
+			case r.ResolvedAt != nil:
+				state = "resolved " + r.ResolvedAt.UTC().Format(time.RFC3339)
+			case r.Skipped:
+				state = "skipped"
+			}
+			fmt.Printf("%d\t%s\t%d-%d\t%d attempts\t%s\tlast failed %s\t%s\n",
+				r.ID, r.TokenAddress, r.FromBlock, r.ToBlock, r.Attempts, state,
+				r.LastFailedAt.UTC().Format(time.RFC3339), r.LastError)
+		}
+		fmt.Fprintf(os.Stderr, "failed-ranges: %d range(s)\n", len(ranges))
+		return nil
+	}
+
+	indexerService, closeService, err := newReindexService(ctx, cfg, store, logger)
+	if err != nil {
+		logger.Error("Failed to set up reindexing", zap.Error(err))
+		return errFailed
+	}
+	defer closeService()
+
+	// Ranges still retried by the indexer are left to it; only skipped ones have a gap to fill
+	repaired, failed := 0, 0
+	for _, r := range ranges {
+		if !r.Skipped {
+			continue
+		}
+		result, err := indexerService.Reindex(ctx, r.TokenAddress, r.FromBlock, r.ToBlock)
+		if err != nil {
+			logger.Error("Failed to repair range", zap.Int64("id", r.ID), zap.String("token", r.TokenAddress),
+				zap.Int64("from", r.FromBlock), zap.Int64("to", r.ToBlock), zap.Error(err))
+			failed++
+			continue
+		}
+		if _, err := store.FailedRanges.Resolve(ctx, r.ID); err != nil {
+			logger.Error("Failed to resolve repaired range", zap.Int64("id", r.ID), zap.Error(err))
+			failed++
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "failed-ranges: repaired range %d (%s %d-%d), inserted %d transfers\n",
+			r.ID, r.TokenAddress, r.FromBlock, r.ToBlock, result.Inserted)
+		repaired++
+	}
+
+	fmt.Fprintf(os.Stderr, "failed-ranges: repaired %d, failed %d\n", repaired, failed)
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
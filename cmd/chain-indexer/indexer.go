@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/clickhouse"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/database"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/notify"
+	"github.com/bimakw/chain-indexer/internal/presentation/handlers"
+)
+
+// newIndexerCmd returns the `chain-indexer indexer` command
+func newIndexerCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "indexer",
+		Short: "Index transfers of the configured tokens",
+		Args:  cobra.NoArgs,
+	}
+	allowDegraded := cmd.Flags().Bool("allow-degraded-schema", false, "start on a schema behind this build, without the modules whose tables are missing")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runIndexer(cfg, logger, *allowDegraded)
+	})
+	return cmd
+}
+
+// runIndexer runs the indexer until SIGINT or SIGTERM
+func runIndexer(cfg *config.Config, logger *zap.Logger, allowDegraded bool) error {
+	logger.Info("Starting chain-indexer",
+		zap.Strings("tokens", cfg.Indexer.TokenAddresses),
+		zap.String("rpc_url", cfg.Ethereum.RPCURL),
+	)
+
+	// Setup context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Connect to database
+	dbConfig := cfg.Database.ForIndexer()
+	store, err := bootstrap.OpenStore(ctx, cfg, dbConfig, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer store.Close()
+	schema := checkSchemaCompatibility(store, allowDegraded, logger)
+
+	if workers := max(cfg.Indexer.WorkerCount, cfg.Indexer.WorkerCountMax); dbConfig.MaxOpenConns > 0 && dbConfig.MaxOpenConns < workers {
+		logger.Warn("Database pool is smaller than the worker count, workers will wait for connections",
+			zap.Int("max_open_conns", dbConfig.MaxOpenConns),
+			zap.Int("workers", workers),
+		)
+	}
+
+	// Active address sketches and indexed token announcements go through Redis (optional)
+	var activeAddrs *cache.ActiveAddressSketches
+	redisCache, err := bootstrap.ConnectRedis(ctx, cfg, 0, logger)
+	if err != nil {
+		logger.Warn("Failed to connect to Redis, active address tracking and cache warming disabled", zap.Error(err))
+		redisCache = nil
+	} else {
+		defer redisCache.Close()
+		activeAddrs = cache.NewActiveAddressSketches(redisCache)
+	}
+
+	indexerService, closeIndexer := startIndexer(ctx, cfg, store, schema, activeAddrs, redisCache, logger)
+	defer closeIndexer()
+
+	// Start metrics server
+	statusHandler := handlers.NewStatusHandler(indexerService, logger)
+	healthHandler := handlers.NewIndexerHealthHandler(indexerService, store, logger)
+	go startMetricsServer(cfg.Indexer.MetricsPort, statusHandler, healthHandler, logger)
+
+	// Wait for shutdown signal
+	bootstrap.WaitForSignal()
+
+	logger.Info("Received shutdown signal, stopping indexer...")
+
+	// Graceful shutdown
+	indexerService.Stop()
+
+	logger.Info("Indexer stopped")
+	return nil
+}
+
+// startIndexer wires the indexer and its optional modules on an open store and starts it,
+// leaving out the modules whose tables schema lacks. activeAddrs and indexedEvents may be nil. The returned func closes the RPC client once the
+// indexer has stopped.
+func startIndexer(ctx context.Context, cfg *config.Config, store *database.Store, schema *database.SchemaStatus, activeAddrs *cache.ActiveAddressSketches, indexedEvents *cache.RedisCache, logger *zap.Logger) (*services.IndexerService, func()) {
+	// Connect to Ethereum node
+	ethClient, err := bootstrap.ConnectRPC(ctx, cfg, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to Ethereum node", zap.Error(err))
+	}
+
+	// Create fetcher
+	fetcher := ethereum.NewFetcher(ethClient, cfg.Indexer, logger.Named("ethereum"))
+
+	// Create metadata fetcher
+	metadataFetcher := ethereum.NewMetadataFetcher(ethClient, logger.Named("ethereum"))
+
+	// Create indexer service
+	indexerService := services.NewIndexerService(
+		fetcher,
+		metadataFetcher,
+		store.Tokens,
+		store.Transfers,
+		store.IndexerState,
+		cfg.Indexer,
+		logger.Named("indexer"),
+	)
+	if schema.Enabled(database.FeatureDailyStats) {
+		indexerService.WithDailyStats(store.DailyStats)
+	}
+	if schema.Enabled(database.FeatureWalletStats) {
+		indexerService.WithWalletStats(store.WalletStats)
+	}
+	if cfg.Indexer.RangeMaxRetries > 0 && featureEnabled(schema, database.FeatureFailedRanges, logger) {
+		indexerService.WithFailedRanges(store.FailedRanges)
+	}
+	if err := services.CheckFailedRangePolicy(cfg.Indexer.FailedRangePolicy); err != nil {
+		logger.Fatal("Invalid INDEXER_FAILED_RANGE_POLICY", zap.Error(err))
+	}
+	if activeAddrs != nil {
+		indexerService.WithActiveAddresses(activeAddrs)
+	}
+	if indexedEvents != nil {
+		indexerService.WithIndexedEvents(indexedEvents)
+	}
+
+	// Mirror transfers to the ClickHouse analytics store (optional)
+	analytics, err := connectAnalytics(ctx, cfg)
+	if err != nil {
+		logger.Fatal("Failed to connect to ClickHouse", zap.Error(err))
+	}
+	if analytics != nil {
+		indexerService.WithAnalytics(analytics)
+		logger.Info("Mirroring transfers to ClickHouse analytics store")
+	}
+
+	// Archive raw logs so parser fixes can be replayed without RPC (optional)
+	if cfg.Indexer.StoreRawLogs && featureEnabled(schema, database.FeatureRawLogs, logger) {
+		indexerService.WithRawLogs(store.RawLogs)
+		logger.Info("Archiving raw logs for replay")
+	}
+
+	// Evaluate alert rules against newly indexed transfers
+	if featureEnabled(schema, database.FeatureAlerts, logger) {
+		alertDrivers := notify.NewRegistryFromConfig(cfg.Alert)
+		indexerService.WithAlerts(services.NewAlertService(store.AlertRules, alertDrivers, logger).WithTenants(store.Tenants))
+		logger.Info("Alert notifications enabled", zap.Strings("channels", alertDrivers.Names()))
+	}
+
+	// Keep deny lists current for transfer screening
+	screeningSources, err := cfg.Screening.Sources()
+	if err != nil {
+		logger.Fatal("Invalid screening configuration", zap.Error(err))
+	}
+	if len(screeningSources) > 0 && featureEnabled(schema, database.FeatureScreening, logger) {
+		screeningService := services.NewScreeningService(store.DenyList, nil, logger)
+		go screeningService.RunRefreshLoop(ctx, screeningSources, cfg.Screening.RefreshInterval)
+	}
+
+	// Export closed block ranges to the Parquet archive
+	if cfg.Archive.URL != "" {
+		objectStore, err := openArchiveStore(cfg.Archive)
+		if err != nil {
+			logger.Fatal("Invalid archive configuration", zap.Error(err))
+		}
+		archiveService := services.NewArchiveService(store.Transfers, store.Tokens, store.IndexerState, objectStore, cfg.Archive.RangeSize, logger)
+		go archiveService.RunArchiveLoop(ctx, cfg.Indexer.TokenAddresses, cfg.Archive.Interval)
+	}
+
+	// Snapshot top holders for the holder changes endpoint, recording holder counts for approximate counts
+	if cfg.Indexer.HolderSnapshotInterval > 0 && featureEnabled(schema, database.FeatureHolderSnapshots, logger) &&
+		featureEnabled(schema, database.FeatureHolderCounts, logger) {
+		holdersService := services.NewHoldersService(store.Transfers, store.Tokens, nil, logger).
+			WithSnapshots(store.HolderSnapshots, cfg.Indexer.HolderSnapshotSize).
+			WithHolderCounts(store.HolderCounts)
+		if analytics != nil {
+			holdersService.WithAnalytics(analytics)
+		}
+		go holdersService.RunSnapshotLoop(ctx, cfg.Indexer.TokenAddresses, cfg.Indexer.HolderSnapshotInterval, cfg.Indexer.HolderSnapshotRetention)
+	}
+
+	// Aggregate velocity, dormancy and transfer patterns for the advanced stats endpoint
+	if cfg.Indexer.AdvancedStatsInterval > 0 && featureEnabled(schema, database.FeatureAdvancedStats, logger) &&
+		featureEnabled(schema, database.FeatureDailyStats, logger) {
+		statsService := services.NewStatsService(store.Transfers, store.Tokens, nil, logger).
+			WithDailyStats(store.DailyStats).
+			WithAdvancedStats(store.AdvancedStats)
+		go statsService.RunAdvancedStatsLoop(ctx, cfg.Indexer.TokenAddresses,
+			cfg.Indexer.AdvancedStatsInterval, cfg.Indexer.VelocityWindow, cfg.Indexer.DormantAfter, cfg.Indexer.AirdropFanOut)
+	}
+
+	// Flag transfers ping-ponging between two addresses as wash trades
+	if cfg.Indexer.WashTradeInterval > 0 && featureEnabled(schema, database.FeatureTransferFlags, logger) {
+		flagService := services.NewTransferFlagService(store.Transfers, store.TransferFlags, services.WashTradeHeuristics{
+			Window:         cfg.Indexer.WashTradeWindow,
+			ValueTolerance: cfg.Indexer.WashTradeTolerance,
+			MinTransfers:   cfg.Indexer.WashTradeMinTransfers,
+		}, logger)
+		go flagService.RunWashTradeLoop(ctx, cfg.Indexer.TokenAddresses, cfg.Indexer.WashTradeInterval, cfg.Indexer.WashTradeLookback)
+	}
+
+	// Pick up name, symbol and decimals changes of upgradeable tokens
+	if cfg.Indexer.MetadataRefreshInterval > 0 && featureEnabled(schema, database.FeatureMetadataHistory, logger) {
+		tokenService := services.NewTokenService(store.Tokens, indexedEvents, logger).WithMetadataReader(metadataFetcher)
+		go tokenService.RunMetadataRefreshLoop(ctx, cfg.Indexer.TokenAddresses, cfg.Indexer.MetadataRefreshInterval)
+	}
+
+	// Register the DEX swap module
+	if len(cfg.Indexer.DexPools) > 0 && featureEnabled(schema, database.FeatureSwaps, logger) {
+		if err := registerSwapModule(ctx, cfg.Indexer.DexPools, ethClient, fetcher, store.Swaps, indexerService, logger); err != nil {
+			logger.Fatal("Failed to register swap module", zap.Error(err))
+		}
+		indexerService.WithScopedEvents(store.ScopedEvents)
+	}
+
+	// Register the events selected from uploaded contract ABIs
+	if featureEnabled(schema, database.FeatureContractEvents, logger) {
+		registered, err := registerContractModule(ctx, fetcher, store.Contracts, indexerService, logger)
+		if err != nil {
+			logger.Fatal("Failed to register contract module", zap.Error(err))
+		}
+		if registered {
+			indexerService.WithScopedEvents(store.ScopedEvents)
+		}
+	}
+
+	// Capture native ETH transfers from block traces (optional)
+	switch cfg.Indexer.TraceMethod {
+	case "":
+	case ethereum.TraceMethodDebug, ethereum.TraceMethodTrace:
+		if !featureEnabled(schema, database.FeatureEthTransfers, logger) {
+			break
+		}
+		indexerService.WithEthTransfers(store.EthTransfers)
+		logger.Info("Indexing native ETH transfers from block traces", zap.String("method", cfg.Indexer.TraceMethod))
+	default:
+		logger.Fatal("Invalid INDEXER_TRACE_METHOD (available: debug, trace)", zap.String("method", cfg.Indexer.TraceMethod))
+	}
+
+	// Start indexer
+	if err := indexerService.Start(ctx); err != nil {
+		logger.Fatal("Failed to start indexer", zap.Error(err))
+	}
+
+	return indexerService, ethClient.Close
+}
+
+// connectAnalytics returns the ClickHouse analytics repository, or nil when CLICKHOUSE_URL is not set
+func connectAnalytics(ctx context.Context, cfg *config.Config) (*clickhouse.AnalyticsRepo, error) {
+	if cfg.ClickHouse.URL == "" {
+		return nil, nil
+	}
+
+	client := clickhouse.NewClient(cfg.ClickHouse)
+	if err := client.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return clickhouse.NewAnalyticsRepo(client), nil
+}
+
+func startMetricsServer(port int, statusHandler *handlers.StatusHandler, healthHandler *handlers.IndexerHealthHandler, logger *zap.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/status", statusHandler.Status)
+	mux.HandleFunc("/health", healthHandler.Health)
+
+	addr := fmt.Sprintf(":%d", port)
+	logger.Info("Starting metrics server", zap.String("addr", addr))
+
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Error("Metrics server error", zap.Error(err))
+	}
+}
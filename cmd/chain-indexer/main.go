@@ -0,0 +1,124 @@
+// Command chain-indexer runs the indexer, the API and the admin tasks from one binary, so they
+// share configuration, logging and database wiring.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+// Exit codes shared by every command
+const (
+	exitOK      = 0 // Success, or help was requested
+	exitFailure = 1 // The command ran and failed
+	exitUsage   = 2 // Invalid command line; nothing was done
+)
+
+// errFailed is returned by commands that ran and failed once they have reported why
+var errFailed = errors.New("command failed")
+
+// usageErrorf reports an invalid command line found after parsing, such as a malformed address.
+// Like cobra's own errors for unknown commands and flags, it exits with exitUsage.
+func usageErrorf(format string, args ...any) error {
+	return fmt.Errorf(format, args...)
+}
+
+// cli holds what the flags before and after the command name share
+type cli struct {
+	configFiles []string
+	settings    []string
+}
+
+func main() {
+	os.Exit(execute(os.Args[1:]))
+}
+
+// execute runs the command line and returns the exit code: exitOK on success or after help,
+// exitFailure when a command returned errFailed, and exitUsage for any other error
+func execute(args []string) int {
+	root := newRootCmd(&cli{})
+	root.SetArgs(args)
+
+	cmd, err := root.ExecuteC()
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errFailed):
+		return exitFailure
+	default:
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.CommandPath(), err)
+		fmt.Fprintf(os.Stderr, "Run `%s -h` for usage.\n", cmd.CommandPath())
+		return exitUsage
+	}
+}
+
+// newRootCmd returns the chain-indexer command with every subcommand added
+func newRootCmd(c *cli) *cobra.Command {
+	root := &cobra.Command{
+		Use:   "chain-indexer",
+		Short: "Index ERC-20 transfers and serve them over a REST API",
+		// Errors are printed by execute, with the exit code they map to
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	root.CompletionOptions.DisableDefaultCmd = true
+	root.PersistentFlags().StringArrayVar(&c.configFiles, "config", nil, "read settings from a KEY=VALUE file, over CONFIG_FILE (repeatable)")
+	root.PersistentFlags().StringArrayVar(&c.settings, "set", nil, "override a setting from the files and the environment, as KEY=VALUE (repeatable)")
+
+	embedded := newEmbeddedCmd(c)
+	root.AddCommand(
+		newAPICmd(c),
+		newIndexerCmd(c),
+		embedded,
+		newBackfillCmd(c),
+		newMigrateCmd(c),
+		newReindexCmd(c),
+		newFailedRangesCmd(c),
+		newReplayCmd(c),
+		newRollupCmd(c),
+		newDenyListCmd(c),
+		newArchiveCmd(c),
+		newRestoreCmd(c),
+		newDoctorCmd(c),
+	)
+
+	// --embedded is accepted as a flag-style alias of the embedded command
+	alias := root.Flags().Bool("embedded", false, "run the embedded command")
+	_ = root.Flags().MarkHidden("embedded")
+	root.RunE = func(cmd *cobra.Command, args []string) error {
+		if *alias {
+			return embedded.RunE(embedded, args)
+		}
+		return usageErrorf("a command is required")
+	}
+	return root
+}
+
+// run adapts a command's run function to cobra. The configuration is loaded only once the command
+// line has been parsed, so help works whatever the configuration holds.
+func (c *cli) run(fn func(cfg *config.Config, logger *zap.Logger) error) func(*cobra.Command, []string) error {
+	return func(*cobra.Command, []string) error {
+		// Load configuration: defaults < config files < environment < --set
+		sources := config.Sources{Files: c.configFiles}
+		for _, setting := range c.settings {
+			if err := sources.Set(setting); err != nil {
+				return err
+			}
+		}
+		cfg, logger, err := bootstrap.Load(sources)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "chain-indexer: %v\n", err)
+			return errFailed
+		}
+		defer func() { _ = logger.Sync() }()
+
+		return fn(cfg, logger)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExecute_ExitCodes(t *testing.T) {
+	for args, want := range map[string]int{
+		"-h":                         exitOK,
+		"help backfill":              exitOK,
+		"migrate up -h":              exitOK,
+		"":                           exitUsage,
+		"nope":                       exitUsage,
+		"backfill --bogus":           exitUsage,
+		"backfill extra":             exitUsage,
+		"migrate up 1 2":             exitUsage,
+		"migrate up -1":              exitUsage,
+		"migrate force":              exitUsage,
+		"api --set NOT_A_SETTING":    exitUsage,
+		"api --config /nonexistent":  exitFailure,
+		"--config /nonexistent api":  exitFailure,
+		"doctor --timeout not-a-dur": exitUsage,
+	} {
+		if got := execute(strings.Fields(args)); got != want {
+			t.Errorf("%q: expected exit code %d, got %d", args, want, got)
+		}
+	}
+}
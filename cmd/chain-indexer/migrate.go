@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/database"
+	"github.com/bimakw/chain-indexer/migrations"
+)
+
+// newMigrateCmd returns the `chain-indexer migrate` command. Without an action it applies every
+// pending migration, as `migrate up` does.
+func newMigrateCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply or revert database migrations",
+		Args:  cobra.NoArgs,
+	}
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runMigrate(cfg, logger, "up", 0)
+	})
+	cmd.AddCommand(
+		newMigrateActionCmd(c, "up [N]", "Apply the next N migrations, or every pending one", cobra.MaximumNArgs(1)),
+		newMigrateActionCmd(c, "down [N]", "Revert the latest N migrations, or the latest one", cobra.MaximumNArgs(1)),
+		newMigrateActionCmd(c, "version", "Print the applied and latest versions", cobra.NoArgs),
+		newMigrateActionCmd(c, "force V", "Record version V as applied without running anything", cobra.ExactArgs(1)),
+	)
+	return cmd
+}
+
+// newMigrateActionCmd returns a migrate subcommand, whose argument is a count or, for force, a version
+func newMigrateActionCmd(c *cli, use, short string, args cobra.PositionalArgs) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  args,
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var n int64
+		if len(args) == 1 {
+			var err error
+			if n, err = strconv.ParseInt(args[0], 10, 64); err != nil || n < 0 {
+				return usageErrorf("the count or version must be a non-negative number")
+			}
+		}
+		return c.run(func(cfg *config.Config, logger *zap.Logger) error {
+			return runMigrate(cfg, logger, cmd.Name(), n)
+		})(cmd, args)
+	}
+	return cmd
+}
+
+// runMigrate implements `chain-indexer migrate [up [N] | down [N] | version | force V]`.
+// It applies the migrations embedded in the binary.
+func runMigrate(cfg *config.Config, logger *zap.Logger, action string, n int64) error {
+	if action == "down" && n == 0 {
+		n = 1
+	}
+
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	if cfg.Database.Driver == "sqlite" {
+		return migrateSQLite(ctx, cfg, logger, action)
+	}
+
+	all, err := database.LoadMigrations(migrations.Files)
+	if err != nil {
+		logger.Error("Failed to load migrations", zap.Error(err))
+		return errFailed
+	}
+
+	var db *database.PostgresDB
+	err = bootstrap.Retry(ctx, cfg.Startup, "database", logger, func() (err error) {
+		db, err = database.NewPostgresDB(cfg.Database, logger)
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return errFailed
+	}
+	defer db.Close()
+
+	migrator := database.NewMigrator(db, all, logger)
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up(ctx, int(n))
+		if err != nil {
+			logger.Error("Migration failed", zap.Error(err))
+			return errFailed
+		}
+		fmt.Fprintf(os.Stderr, "migrate: applied %d migrations\n", applied)
+	case "down":
+		reverted, err := migrator.Down(ctx, int(n))
+		if err != nil {
+			logger.Error("Migration failed", zap.Error(err))
+			return errFailed
+		}
+		fmt.Fprintf(os.Stderr, "migrate: reverted %d migrations\n", reverted)
+	case "force":
+		if err := migrator.Force(ctx, n); err != nil {
+			logger.Error("Failed to force schema version", zap.Error(err))
+			return errFailed
+		}
+		fmt.Fprintf(os.Stderr, "migrate: recorded version %d as clean\n", n)
+	case "version":
+		version, dirty, err := db.SchemaVersion(ctx)
+		if errors.Is(err, database.ErrNoSchemaVersion) {
+			fmt.Fprintln(os.Stderr, "migrate: no migrations applied")
+			return nil
+		}
+		if err != nil {
+			logger.Error("Failed to get schema version", zap.Error(err))
+			return errFailed
+		}
+		state := ""
+		if dirty {
+			state = " (dirty)"
+		}
+		fmt.Fprintf(os.Stderr, "migrate: version %d%s, latest %d\n", version, state, migrator.Latest())
+	}
+
+	return nil
+}
+
+// migrateSQLite handles migrate for the SQLite backend, which has no migration history:
+// opening the file creates the current schema, so only up and version apply
+func migrateSQLite(ctx context.Context, cfg *config.Config, logger *zap.Logger, action string) error {
+	if action != "up" && action != "version" {
+		return usageErrorf("%s is not supported by the sqlite backend; delete %s to recreate it", action, cfg.Database.Path)
+	}
+
+	db, err := database.NewSQLiteDB(cfg.Database, logger)
+	if err != nil {
+		logger.Error("Failed to open database", zap.Error(err))
+		return errFailed
+	}
+	defer db.Close()
+
+	version, _, err := db.SchemaVersion(ctx)
+	if err != nil {
+		logger.Error("Failed to get schema version", zap.Error(err))
+		return errFailed
+	}
+	fmt.Fprintf(os.Stderr, "migrate: version %d, latest %d\n", version, database.SchemaVersion)
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/database"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// newReindexCmd returns the `chain-indexer reindex` command
+func newReindexCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reindex",
+		Short: "Delete and re-fetch a block range of one token",
+		Args:  cobra.NoArgs,
+	}
+	token := cmd.Flags().String("token", "", "token contract address to reindex")
+	fromBlock := cmd.Flags().Int64("from", -1, "first block of the range (inclusive)")
+	toBlock := cmd.Flags().Int64("to", -1, "last block of the range (inclusive)")
+	yes := cmd.Flags().Bool("yes", false, "skip the confirmation prompt")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runReindex(cfg, logger, *token, *fromBlock, *toBlock, *yes)
+	})
+	return cmd
+}
+
+// runReindex implements `chain-indexer reindex --token X --from A --to B [--yes]`.
+func runReindex(cfg *config.Config, logger *zap.Logger, token string, fromBlock, toBlock int64, yes bool) error {
+	if !common.IsHexAddress(token) {
+		return usageErrorf("--token must be a valid contract address")
+	}
+	if fromBlock < 0 || toBlock < fromBlock {
+		return usageErrorf("--from and --to must form a valid block range")
+	}
+	tokenAddress := strings.ToLower(token)
+
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	store, err := bootstrap.OpenStore(ctx, cfg, cfg.Database.ForIndexer(), logger)
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return errFailed
+	}
+	defer store.Close()
+
+	transferRepo := store.Transfers
+
+	existing, err := transferRepo.GetCount(ctx, entities.TransferFilter{
+		TokenAddress: &tokenAddress,
+		FromBlock:    &fromBlock,
+		ToBlock:      &toBlock,
+	})
+	if err != nil {
+		logger.Error("Failed to count existing transfers", zap.Error(err))
+		return errFailed
+	}
+
+	if !yes {
+		prompt := fmt.Sprintf("This will delete %d transfers of %s in blocks %d-%d and re-fetch them.",
+			existing, tokenAddress, fromBlock, toBlock)
+		if !confirm(os.Stdin, os.Stderr, prompt) {
+			fmt.Fprintln(os.Stderr, "reindex: aborted")
+			return errFailed
+		}
+	}
+
+	indexerService, closeService, err := newReindexService(ctx, cfg, store, logger)
+	if err != nil {
+		logger.Error("Failed to set up reindexing", zap.Error(err))
+		return errFailed
+	}
+	defer closeService()
+
+	result, err := indexerService.Reindex(ctx, tokenAddress, fromBlock, toBlock)
+	if err != nil {
+		logger.Error("Reindex failed", zap.Error(err))
+		return errFailed
+	}
+
+	fmt.Fprintf(os.Stderr, "reindex: done, deleted %d and inserted %d transfers\n", result.Deleted, result.Inserted)
+	return nil
+}
+
+// newReindexService creates an indexer service for rewriting block ranges, with the same
+// stores as the indexer so derived data is rewritten too. The returned func releases its
+// connections.
+func newReindexService(ctx context.Context, cfg *config.Config, store *database.Store, logger *zap.Logger) (*services.IndexerService, func(), error) {
+	ethClient, err := bootstrap.ConnectRPC(ctx, cfg, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to Ethereum node: %w", err)
+	}
+
+	indexerService := services.NewIndexerService(
+		ethereum.NewFetcher(ethClient, cfg.Indexer, logger.Named("ethereum")),
+		ethereum.NewMetadataFetcher(ethClient, logger.Named("ethereum")),
+		store.Tokens,
+		store.Transfers,
+		store.IndexerState,
+		cfg.Indexer,
+		logger.Named("indexer"),
+	).WithDailyStats(store.DailyStats).WithWalletStats(store.WalletStats)
+
+	activeAddrs, closeActiveAddrs := connectActiveAddresses(ctx, cfg, logger)
+	if activeAddrs != nil {
+		indexerService.WithActiveAddresses(activeAddrs)
+	}
+	closeAll := func() {
+		closeActiveAddrs()
+		ethClient.Close()
+	}
+
+	// Reindexed ranges are rewritten in the analytics store too
+	analytics, err := connectAnalytics(ctx, cfg)
+	if err != nil {
+		closeAll()
+		return nil, nil, fmt.Errorf("failed to connect to ClickHouse: %w", err)
+	}
+	if analytics != nil {
+		indexerService.WithAnalytics(analytics)
+	}
+	if cfg.Indexer.StoreRawLogs {
+		indexerService.WithRawLogs(store.RawLogs)
+	}
+	return indexerService, closeAll, nil
+}
+
+// confirm asks the user to type "yes" before a destructive operation
+func confirm(in io.Reader, out io.Writer, prompt string) bool {
+	fmt.Fprintf(out, "%s\nType 'yes' to continue: ", prompt)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	return strings.TrimSpace(answer) == "yes"
+}
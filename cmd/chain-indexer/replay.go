@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// newReplayCmd returns the `chain-indexer replay` command
+func newReplayCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Regenerate transfers from archived raw logs",
+		Args:  cobra.NoArgs,
+	}
+	token := cmd.Flags().String("token", "", "token contract address to replay")
+	fromBlock := cmd.Flags().Int64("from", -1, "first block of the range (inclusive)")
+	toBlock := cmd.Flags().Int64("to", -1, "last block of the range (inclusive)")
+	yes := cmd.Flags().Bool("yes", false, "skip the confirmation prompt")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runReplay(cfg, logger, *token, *fromBlock, *toBlock, *yes)
+	})
+	return cmd
+}
+
+// runReplay implements `chain-indexer replay --token X --from A --to B [--yes]`.
+// Transfers are regenerated from the raw log archive, so no Ethereum node is needed.
+func runReplay(cfg *config.Config, logger *zap.Logger, token string, fromBlock, toBlock int64, yes bool) error {
+	if !common.IsHexAddress(token) {
+		return usageErrorf("--token must be a valid contract address")
+	}
+	if fromBlock < 0 || toBlock < fromBlock {
+		return usageErrorf("--from and --to must form a valid block range")
+	}
+	tokenAddress := strings.ToLower(token)
+
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	store, err := bootstrap.OpenStore(ctx, cfg, cfg.Database.ForIndexer(), logger)
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return errFailed
+	}
+	defer store.Close()
+
+	existing, err := store.Transfers.GetCount(ctx, entities.TransferFilter{
+		TokenAddress: &tokenAddress,
+		FromBlock:    &fromBlock,
+		ToBlock:      &toBlock,
+	})
+	if err != nil {
+		logger.Error("Failed to count existing transfers", zap.Error(err))
+		return errFailed
+	}
+
+	if !yes {
+		prompt := fmt.Sprintf("This will delete %d transfers of %s in blocks %d-%d and regenerate them from archived raw logs.",
+			existing, tokenAddress, fromBlock, toBlock)
+		if !confirm(os.Stdin, os.Stderr, prompt) {
+			fmt.Fprintln(os.Stderr, "replay: aborted")
+			return errFailed
+		}
+	}
+
+	// Decoding stored logs needs the event registry but never touches the node
+	indexerService := services.NewIndexerService(
+		ethereum.NewFetcher(nil, cfg.Indexer, logger.Named("ethereum")),
+		nil,
+		store.Tokens,
+		store.Transfers,
+		store.IndexerState,
+		cfg.Indexer,
+		logger.Named("indexer"),
+	).WithDailyStats(store.DailyStats).WithWalletStats(store.WalletStats).WithRawLogs(store.RawLogs)
+
+	activeAddrs, closeActiveAddrs := connectActiveAddresses(ctx, cfg, logger)
+	defer closeActiveAddrs()
+	if activeAddrs != nil {
+		indexerService.WithActiveAddresses(activeAddrs)
+	}
+
+	analytics, err := connectAnalytics(ctx, cfg)
+	if err != nil {
+		logger.Error("Failed to connect to ClickHouse", zap.Error(err))
+		return errFailed
+	}
+	if analytics != nil {
+		indexerService.WithAnalytics(analytics)
+	}
+
+	result, err := indexerService.Replay(ctx, tokenAddress, fromBlock, toBlock)
+	if err != nil {
+		logger.Error("Replay failed", zap.Error(err))
+		return errFailed
+	}
+
+	fmt.Fprintf(os.Stderr, "replay: done, deleted %d and inserted %d transfers\n", result.Deleted, result.Inserted)
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+// newRollupCmd returns the `chain-indexer rollup` command
+func newRollupCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollup",
+		Short: "Rebuild daily stats and active address sketches",
+		Args:  cobra.NoArgs,
+	}
+	token := cmd.Flags().String("token", "", "token contract address (default: all configured tokens)")
+	fromDay := cmd.Flags().String("from", "", "first UTC day to rebuild (YYYY-MM-DD)")
+	toDay := cmd.Flags().String("to", time.Now().UTC().Format("2006-01-02"), "last UTC day to rebuild (YYYY-MM-DD)")
+	cmd.RunE = c.run(func(cfg *config.Config, logger *zap.Logger) error {
+		return runRollup(cfg, logger, *token, *fromDay, *toDay)
+	})
+	return cmd
+}
+
+// runRollup implements `chain-indexer rollup --from YYYY-MM-DD [--to YYYY-MM-DD] [--token X]`.
+// It rebuilds token_daily_stats, the active address sketches, each token's transfer count and
+// wallet stats from stored transfers. Transfer counts and wallet stats cover all of a token's
+// transfers, whatever the days given.
+func runRollup(cfg *config.Config, logger *zap.Logger, token, fromDay, toDay string) error {
+	from, err := time.Parse("2006-01-02", fromDay)
+	if err != nil {
+		return usageErrorf("--from must be a date in YYYY-MM-DD format")
+	}
+	to, err := time.Parse("2006-01-02", toDay)
+	if err != nil || to.Before(from) {
+		return usageErrorf("--to must be a date in YYYY-MM-DD format, not before --from")
+	}
+
+	tokens := cfg.Indexer.TokenAddresses
+	if token != "" {
+		if !common.IsHexAddress(token) {
+			return usageErrorf("--token must be a valid contract address")
+		}
+		tokens = []string{token}
+	}
+
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	store, err := bootstrap.OpenStore(ctx, cfg, cfg.Database.ForIndexer(), logger)
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return errFailed
+	}
+	defer store.Close()
+
+	// Rebuilding only reads stored transfers, so no Ethereum connection is needed
+	indexerService := services.NewIndexerService(
+		nil, nil,
+		store.Tokens,
+		store.Transfers,
+		store.IndexerState,
+		cfg.Indexer,
+		logger,
+	).WithDailyStats(store.DailyStats)
+
+	activeAddrs, closeActiveAddrs := connectActiveAddresses(ctx, cfg, logger)
+	defer closeActiveAddrs()
+	if activeAddrs != nil {
+		indexerService.WithActiveAddresses(activeAddrs)
+	}
+
+	for _, addr := range tokens {
+		addr = strings.ToLower(addr)
+		logger.Info("Rebuilding daily stats",
+			zap.String("token", addr),
+			zap.String("from", from.Format("2006-01-02")),
+			zap.String("to", to.Format("2006-01-02")),
+		)
+
+		if err := indexerService.RebuildDailyStats(ctx, addr, from, to); err != nil {
+			logger.Error("Rollup failed", zap.String("token", addr), zap.Error(err))
+			return errFailed
+		}
+
+		if _, err := store.Tokens.RecountTransfers(ctx, addr); err != nil {
+			logger.Error("Rollup failed", zap.String("token", addr), zap.Error(err))
+			return errFailed
+		}
+
+		if err := store.WalletStats.Rebuild(ctx, addr); err != nil {
+			logger.Error("Rollup failed", zap.String("token", addr), zap.Error(err))
+			return errFailed
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "rollup: done, rebuilt %s to %s for %d token(s)\n",
+		from.Format("2006-01-02"), to.Format("2006-01-02"), len(tokens))
+	return nil
+}
+
+// connectActiveAddresses returns Redis-backed active address sketches, or nil when Redis is unreachable.
+// The returned func closes the connection.
+func connectActiveAddresses(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*cache.ActiveAddressSketches, func()) {
+	redisCache, err := bootstrap.ConnectRedis(ctx, cfg, 0, logger)
+	if err != nil {
+		logger.Warn("Failed to connect to Redis, active address tracking disabled", zap.Error(err))
+		return nil, func() {}
+	}
+	return cache.NewActiveAddressSketches(redisCache), func() { _ = redisCache.Close() }
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/database"
+	"github.com/bimakw/chain-indexer/internal/presentation/middleware"
+)
+
+// checkSchemaCompatibility compares the database schema with this build before a long-running
+// command starts serving. A schema behind the build is fatal unless allowDegraded is set, when the
+// command starts with the features whose tables are missing disabled, so a rolling deployment can
+// run ahead of its migrations. A schema ahead of the build is expected while the old build drains.
+func checkSchemaCompatibility(store *database.Store, allowDegraded bool, logger *zap.Logger) *database.SchemaStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	schema, err := database.CheckSchema(ctx, store)
+	if err != nil {
+		if errors.Is(err, database.ErrSchemaIncompatible) {
+			logger.Fatal("Database schema is incompatible with this build", zap.Error(err))
+		}
+		logger.Fatal("Failed to check database schema", zap.Error(err))
+	}
+
+	if err := schema.Compatible(); err != nil {
+		if !allowDegraded {
+			logger.Fatal("Database schema is incompatible with this build; run `chain-indexer migrate up`, "+
+				"or pass --allow-degraded-schema to start without the features it lacks", zap.Error(err))
+		}
+		logger.Warn("Starting on a degraded schema, features without their tables are disabled",
+			zap.Error(err),
+			zap.Strings("disabled", schema.DisabledFeatures()),
+		)
+		return schema
+	}
+
+	if schema.Known && schema.Version > database.SchemaVersion {
+		logger.Info("Database schema is newer than this build",
+			zap.Int64("version", schema.Version),
+			zap.Int64("expected", database.SchemaVersion),
+		)
+	}
+	return schema
+}
+
+// featureEnabled reports whether the schema has a feature's tables, logging when a configured
+// module is left out for lack of them
+func featureEnabled(schema *database.SchemaStatus, feature database.Feature, logger *zap.Logger) bool {
+	if schema.Enabled(feature) {
+		return true
+	}
+	logger.Warn("Feature disabled until the database is migrated",
+		zap.String("feature", string(feature)),
+		zap.Strings("missing_tables", schema.Disabled[feature]),
+	)
+	return false
+}
+
+// requireFeature gates routes of a feature on the schema having its tables
+func requireFeature(schema *database.SchemaStatus, feature database.Feature) func(next http.Handler) http.Handler {
+	return middleware.RequireFeature(string(feature), schema.Enabled(feature))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// registerSwapModule resolves the configured pools and registers their Swap decoders and repository
+func registerSwapModule(
+	ctx context.Context,
+	poolAddresses []string,
+	ethClient *ethereum.Client,
+	fetcher *ethereum.Fetcher,
+	swapRepo repositories.SwapRepository,
+	indexerService *services.IndexerService,
+	logger *zap.Logger,
+) error {
+	pools := make([]entities.DexPool, 0, len(poolAddresses))
+	for _, addr := range poolAddresses {
+		pool, err := ethClient.FetchPool(ctx, addr)
+		if err != nil {
+			return fmt.Errorf("failed to resolve pool %s: %w", addr, err)
+		}
+
+		if err := swapRepo.UpsertPool(ctx, pool); err != nil {
+			return err
+		}
+
+		logger.Info("Indexing DEX pool",
+			zap.String("pool", pool.Address),
+			zap.String("token0", pool.Token0Address),
+			zap.String("token1", pool.Token1Address),
+		)
+		pools = append(pools, *pool)
+	}
+
+	for _, et := range ethereum.SwapEventTypes(pools) {
+		if err := fetcher.Registry().Register(et); err != nil {
+			return err
+		}
+		indexerService.RegisterEventRepository(et.Name, swapRepo)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+// Command seed fills the database with synthetic transfers so the holders and stats queries can be
+// measured at production-like volumes before production. Output is deterministic for a given
+// --seed and --end, which makes it suitable as a fixed dataset for benchmarks.
+//
+// Never point it at a production database: it inserts tokens, transfers and indexer state.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/bimakw/chain-indexer/internal/bootstrap"
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/database"
+	"github.com/bimakw/chain-indexer/internal/testutil/seed"
+)
+
+// tokenProgress tracks what was generated for one token
+type tokenProgress struct {
+	transfers int64
+	lastBlock int64
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses flags, seeds the database and returns the process exit code
+func run(args []string) int {
+	gen := seed.DefaultConfig()
+
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.IntVar(&gen.Tokens, "tokens", gen.Tokens, "number of tokens")
+	fs.IntVar(&gen.Holders, "holders", gen.Holders, "number of distinct holder addresses")
+	fs.Int64Var(&gen.Transfers, "transfers", gen.Transfers, "number of transfers to generate")
+	fs.Float64Var(&gen.Skew, "skew", gen.Skew, "Zipf exponent of holder activity (> 1; higher is more concentrated)")
+	fs.Int64Var(&gen.Seed, "seed", gen.Seed, "random seed")
+	fs.Int64Var(&gen.StartBlock, "start-block", gen.StartBlock, "block number of the first transfer")
+	fs.IntVar(&gen.TransfersPerBlock, "per-block", gen.TransfersPerBlock, "transfers per block")
+	end := fs.String("end", gen.EndTime.Format(time.RFC3339), "timestamp of the last block (RFC 3339)")
+	batchSize := fs.Int("batch", 5000, "transfers per insert transaction")
+	workers := fs.Int("workers", 4, "concurrent insert workers")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	endTime, err := time.Parse(time.RFC3339, *end)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "seed: --end must be an RFC 3339 timestamp")
+		return 2
+	}
+	gen.EndTime = endTime.UTC()
+	if *batchSize <= 0 || *workers <= 0 {
+		fmt.Fprintln(os.Stderr, "seed: --batch and --workers must be positive")
+		return 2
+	}
+
+	generator, err := seed.NewGenerator(gen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "seed: %v\n", err)
+		return 2
+	}
+
+	cfg, logger, err := bootstrap.Load(config.Sources{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "seed: %v\n", err)
+		return 1
+	}
+	defer logger.Sync()
+
+	ctx, stop := bootstrap.SignalContext()
+	defer stop()
+
+	store, err := bootstrap.OpenStore(ctx, cfg, cfg.Database.ForIndexer(), logger)
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return 1
+	}
+	defer store.Close()
+
+	if err := seedDatabase(ctx, store, generator, *batchSize, *workers, logger); err != nil {
+		logger.Error("Seeding failed", zap.Error(err))
+		return 1
+	}
+
+	addresses := make([]string, 0, gen.Tokens)
+	for _, token := range generator.Tokens() {
+		addresses = append(addresses, token.Address)
+	}
+
+	fmt.Fprintf(os.Stderr, "seed: inserted %d transfers for %d token(s)\n", generator.Generated(), gen.Tokens)
+	fmt.Fprintf(os.Stderr, "seed: INDEXER_TOKEN_ADDRESSES=%s\n", strings.Join(addresses, ","))
+	fmt.Fprintf(os.Stderr, "seed: run `chain-indexer rollup --from %s --to %s` to build daily stats\n",
+		gen.EndTime.Add(-time.Duration(gen.Blocks())*gen.BlockTime).Format("2006-01-02"),
+		gen.EndTime.Format("2006-01-02"))
+	return 0
+}
+
+// seedDatabase stores the tokens, inserts the generated transfers with concurrent workers,
+// and records per-token stats and indexer state so the API treats the tokens as indexed
+func seedDatabase(ctx context.Context, store *database.Store, generator *seed.Generator, batchSize, workers int, logger *zap.Logger) error {
+	for _, token := range generator.Tokens() {
+		if err := store.Tokens.Upsert(ctx, token); err != nil {
+			return err
+		}
+	}
+
+	progress := make(map[string]*tokenProgress)
+	for _, token := range generator.Tokens() {
+		progress[token.Address] = &tokenProgress{}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	batches := make(chan []entities.Transfer, workers)
+
+	// The generator is sequential, so batches are produced here and inserted by the workers
+	g.Go(func() error {
+		defer close(batches)
+		start := time.Now()
+		for batch := generator.Next(batchSize); batch != nil; batch = generator.Next(batchSize) {
+			for _, t := range batch {
+				p := progress[t.TokenAddress]
+				p.transfers++
+				p.lastBlock = t.BlockNumber
+			}
+
+			select {
+			case batches <- batch:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+
+			if done := generator.Generated(); done%int64(batchSize*100) < int64(len(batch)) {
+				logger.Info("Seeding progress",
+					zap.Int64("transfers", done),
+					zap.Float64("rows_per_second", float64(done)/time.Since(start).Seconds()),
+				)
+			}
+		}
+		return nil
+	})
+
+	for i := 0; i < workers; i++ {
+		g.Go(func() error {
+			for batch := range batches {
+				if _, err := store.Transfers.BatchInsert(gctx, batch); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for address, p := range progress {
+		if p.transfers == 0 {
+			continue
+		}
+		if err := store.Tokens.UpdateStats(ctx, address, p.transfers, p.lastBlock); err != nil {
+			return err
+		}
+		if err := store.IndexerState.Upsert(ctx, &entities.IndexerState{
+			TokenAddress:     address,
+			LastIndexedBlock: p.lastBlock,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// MaxAddressNoteLength caps a note, in characters
+const MaxAddressNoteLength = 2000
+
+// ErrAPIKeyRequired is returned for requests about a tenant's own data made without an API key
+var ErrAPIKeyRequired = errors.New("API key required")
+
+// ErrInvalidAddressNote is returned for empty or overlong notes
+var ErrInvalidAddressNote = errors.New("invalid address note")
+
+// AddressNoteService manages the private notes tenants attach to addresses, and looks them up
+// for the transfer and portfolio responses of the tenant's requests
+type AddressNoteService struct {
+	noteRepo repositories.AddressNoteRepository
+	logger   *zap.Logger
+}
+
+// NewAddressNoteService creates a new address note service
+func NewAddressNoteService(noteRepo repositories.AddressNoteRepository, logger *zap.Logger) *AddressNoteService {
+	return &AddressNoteService{
+		noteRepo: noteRepo,
+		logger:   logger,
+	}
+}
+
+// AddressNoteDTO is the API representation of an address note
+type AddressNoteDTO struct {
+	Address   string `json:"address"`
+	Note      string `json:"note"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// AddressNoteResponse is the API response for address note queries
+type AddressNoteResponse struct {
+	Data AddressNoteDTO `json:"data"`
+}
+
+// PutNote sets the request tenant's note on an address, replacing any earlier one
+func (s *AddressNoteService) PutNote(ctx context.Context, address, note string) (*AddressNoteResponse, error) {
+	tenant := TenantFromContext(ctx)
+	if tenant == nil {
+		return nil, ErrAPIKeyRequired
+	}
+
+	addr, err := entities.ParseEVMAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	note = strings.TrimSpace(note)
+	if note == "" || utf8.RuneCountInString(note) > MaxAddressNoteLength {
+		return nil, fmt.Errorf("%w: note is required and must be at most %d characters", ErrInvalidAddressNote, MaxAddressNoteLength)
+	}
+
+	stored := &entities.AddressNote{
+		TenantID: tenant.ID,
+		Address:  addr,
+		Note:     note,
+	}
+	if err := s.noteRepo.Put(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return toAddressNoteResponse(stored), nil
+}
+
+// GetNote returns the request tenant's note on an address, or nil if it has none
+func (s *AddressNoteService) GetNote(ctx context.Context, address string) (*AddressNoteResponse, error) {
+	tenant := TenantFromContext(ctx)
+	if tenant == nil {
+		return nil, ErrAPIKeyRequired
+	}
+
+	addr, err := entities.ParseEVMAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	note, err := s.noteRepo.Get(ctx, tenant.ID, addr)
+	if err != nil || note == nil {
+		return nil, err
+	}
+
+	return toAddressNoteResponse(note), nil
+}
+
+// DeleteNote removes the request tenant's note on an address, reporting whether it existed
+func (s *AddressNoteService) DeleteNote(ctx context.Context, address string) (bool, error) {
+	tenant := TenantFromContext(ctx)
+	if tenant == nil {
+		return false, ErrAPIKeyRequired
+	}
+
+	addr, err := entities.ParseEVMAddress(address)
+	if err != nil {
+		return false, err
+	}
+	return s.noteRepo.Delete(ctx, tenant.ID, addr)
+}
+
+// notesFor returns the request tenant's notes on the given lowercase addresses, by address. It
+// returns nil for requests without a tenant; a failed lookup is logged and leaves the notes out.
+func (s *AddressNoteService) notesFor(ctx context.Context, addresses []string) map[string]string {
+	tenant := TenantFromContext(ctx)
+	if tenant == nil || len(addresses) == 0 {
+		return nil
+	}
+
+	notes, err := s.noteRepo.GetByAddresses(ctx, tenant.ID, addresses)
+	if err != nil {
+		s.logger.Warn("Failed to get address notes", zap.Int64("tenant_id", tenant.ID), zap.Error(err))
+		return nil
+	}
+
+	byAddress := make(map[string]string, len(notes))
+	for _, n := range notes {
+		byAddress[n.Address.String()] = n.Note
+	}
+	return byAddress
+}
+
+func toAddressNoteResponse(note *entities.AddressNote) *AddressNoteResponse {
+	return &AddressNoteResponse{
+		Data: AddressNoteDTO{
+			Address:   note.Address.String(),
+			Note:      note.Note,
+			CreatedAt: note.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			UpdatedAt: note.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		},
+	}
+}
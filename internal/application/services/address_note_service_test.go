@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestAddressNoteService_Notes(t *testing.T) {
+	service := NewAddressNoteService(testutil.NewMockAddressNoteRepository(), zap.NewNop())
+	acme := ContextWithTenant(context.Background(), &entities.Tenant{ID: 1})
+	other := ContextWithTenant(context.Background(), &entities.Tenant{ID: 2})
+
+	if _, err := service.PutNote(context.Background(), testutil.AliceAddress, "exchange"); !errors.Is(err, ErrAPIKeyRequired) {
+		t.Errorf("expected ErrAPIKeyRequired without a tenant, got %v", err)
+	}
+	for _, note := range []string{"  ", strings.Repeat("x", MaxAddressNoteLength+1)} {
+		if _, err := service.PutNote(acme, testutil.AliceAddress, note); !errors.Is(err, ErrInvalidAddressNote) {
+			t.Errorf("expected ErrInvalidAddressNote for a %d character note, got %v", len(note), err)
+		}
+	}
+
+	put, err := service.PutNote(acme, "0x"+strings.ToUpper(testutil.AliceAddress[2:]), " hot wallet ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if put.Data.Note != "hot wallet" || put.Data.Address != testutil.AliceAddress || put.Data.CreatedAt == "" {
+		t.Errorf("expected the trimmed note on the lowercased address, got %+v", put.Data)
+	}
+	if _, err := service.PutNote(acme, "0xinvalid", "exchange"); !errors.Is(err, entities.ErrInvalidAddress) {
+		t.Errorf("expected ErrInvalidAddress, got %v", err)
+	}
+
+	put, err = service.PutNote(acme, testutil.AliceAddress, "cold wallet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := service.GetNote(acme, "0x"+strings.ToUpper(testutil.AliceAddress[2:]))
+	if err != nil || got == nil || got.Data.Note != "cold wallet" || got.Data.Address != testutil.AliceAddress {
+		t.Errorf("expected the replaced note, got %+v (%v)", got, err)
+	}
+
+	// Notes are private to their tenant
+	if got, err := service.GetNote(other, testutil.AliceAddress); err != nil || got != nil {
+		t.Errorf("expected no note for another tenant, got %+v (%v)", got, err)
+	}
+	if deleted, err := service.DeleteNote(other, testutil.AliceAddress); err != nil || deleted {
+		t.Errorf("expected nothing to delete for another tenant, got %v (%v)", deleted, err)
+	}
+
+	if deleted, err := service.DeleteNote(acme, testutil.AliceAddress); err != nil || !deleted {
+		t.Errorf("expected the note to be deleted, got %v (%v)", deleted, err)
+	}
+	if got, err := service.GetNote(acme, testutil.AliceAddress); err != nil || got != nil {
+		t.Errorf("expected no note after deleting it, got %+v (%v)", got, err)
+	}
+}
+
+func TestAddressNoteService_InlineNotes(t *testing.T) {
+	logger := zap.NewNop()
+	notes := NewAddressNoteService(testutil.NewMockAddressNoteRepository(), logger)
+	acme := ContextWithTenant(context.Background(), &entities.Tenant{ID: 1})
+	other := ContextWithTenant(context.Background(), &entities.Tenant{ID: 2})
+
+	if _, err := notes.PutNote(acme, testutil.AliceAddress, "treasury"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := notes.PutNote(other, testutil.BobAddress, "someone else's"); err != nil {
+		t.Fatal(err)
+	}
+
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(testutil.CreateTestTransfer())
+	// Cached responses are shared, so a tenant never sees another's notes through the cache
+	transfers := NewTransferService(transferRepo, testutil.NewMockTokenRepository(), cache.NewMemoryCache(time.Minute, logger), logger).
+		WithAddressNotes(notes)
+
+	for _, ctx := range []context.Context{acme, acme, other, context.Background()} {
+		response, err := transfers.GetTransfers(ctx, entities.TransferFilter{Limit: 10})
+		if err != nil || len(response.Transfers) != 1 {
+			t.Fatalf("unexpected transfers: %+v (%v)", response, err)
+		}
+		dto := response.Transfers[0]
+		wantFrom, wantTo := "", ""
+		switch ctx {
+		case acme:
+			wantFrom = "treasury"
+		case other:
+			wantTo = "someone else's"
+		}
+		if dto.FromNote != wantFrom || dto.ToNote != wantTo {
+			t.Errorf("expected notes %q/%q, got %q/%q", wantFrom, wantTo, dto.FromNote, dto.ToNote)
+		}
+	}
+
+	portfolio := NewPortfolioService(testutil.NewMockPortfolioRepository(), cache.NewMemoryCache(time.Minute, logger), logger).
+		WithAddressNotes(notes)
+	for _, ctx := range []context.Context{acme, other} {
+		summary, err := portfolio.GetWalletSummary(ctx, testutil.AliceAddress)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := ""
+		if ctx == acme {
+			want = "treasury"
+		}
+		if summary.Data.Note != want {
+			t.Errorf("expected wallet note %q, got %q", want, summary.Data.Note)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// ErrNoAdvancedStats is returned when advanced stats are requested before the indexer has computed them
+var ErrNoAdvancedStats = errors.New("no advanced stats computed yet")
+
+// WithAdvancedStats stores the velocity and dormancy stats computed by ComputeAdvancedStats and
+// serves them from GetAdvancedStats
+func (s *StatsService) WithAdvancedStats(repo repositories.AdvancedStatsRepository) *StatsService {
+	s.advancedStats = repo
+	return s
+}
+
+// AdvancedStatsDTO is a token's velocity, dormancy and transfer patterns as of the last aggregation run
+type AdvancedStatsDTO struct {
+	TokenAddress      string `json:"token_address"`
+	WindowFrom        string `json:"window_from"`
+	WindowTo          string `json:"window_to"`
+	Volume            string `json:"volume"`
+	AverageSupply     string `json:"average_supply"`
+	Velocity          string `json:"velocity,omitempty"` // Volume / AverageSupply, omitted when the supply is not positive
+	CirculatingSupply string `json:"circulating_supply"`
+	InactiveSince     string `json:"inactive_since"`
+	DormantSupply     string `json:"dormant_supply"`
+	DormantHolders    int64  `json:"dormant_holders"`
+	DormantPercentage string `json:"dormant_percentage,omitempty"` // Share of circulating supply
+
+	// Transfers within the window that move no value between holders: self-transfers, and
+	// airdrop-like transactions with more than FanOutThreshold transfers from one sender
+	SelfTransfers      int64  `json:"self_transfers"`
+	SelfTransferVolume string `json:"self_transfer_volume"`
+	FanOutThreshold    int    `json:"fan_out_threshold"`
+	FanOutTransactions int64  `json:"fan_out_transactions"`
+	FanOutTransfers    int64  `json:"fan_out_transfers"`
+	FanOutVolume       string `json:"fan_out_volume"`
+
+	ComputedAt string `json:"computed_at"`
+}
+
+// AdvancedStatsResponse is the API response for advanced stats queries
+type AdvancedStatsResponse struct {
+	Data AdvancedStatsDTO `json:"data"`
+}
+
+// GetAdvancedStats returns the velocity and dormancy stats last computed for a token. It returns
+// nil when the token is not indexed and ErrNoAdvancedStats before the first aggregation run.
+func (s *StatsService) GetAdvancedStats(ctx context.Context, tokenAddress string) (*AdvancedStatsResponse, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	cacheKey := "advanced_stats:" + tokenAddress
+	var cached AdvancedStatsResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token: %w", err)
+	}
+	if token == nil {
+		return nil, nil // Token not found
+	}
+
+	if s.advancedStats == nil {
+		return nil, ErrNoAdvancedStats
+	}
+	stats, err := s.advancedStats.Get(ctx, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		return nil, ErrNoAdvancedStats
+	}
+
+	response := &AdvancedStatsResponse{
+		Data: AdvancedStatsDTO{
+			TokenAddress:      tokenAddress,
+			WindowFrom:        stats.WindowFrom.UTC().Format(time.RFC3339),
+			WindowTo:          stats.WindowTo.UTC().Format(time.RFC3339),
+			Volume:            stats.Volume,
+			AverageSupply:     stats.AverageSupply,
+			Velocity:          ratio(stats.Volume, stats.AverageSupply, 1),
+			CirculatingSupply: stats.CirculatingSupply,
+			InactiveSince:     stats.InactiveSince.UTC().Format(time.RFC3339),
+			DormantSupply:     stats.DormantSupply,
+			DormantHolders:    stats.DormantHolders,
+			DormantPercentage: ratio(stats.DormantSupply, stats.CirculatingSupply, 100),
+
+			SelfTransfers:      stats.SelfTransfers,
+			SelfTransferVolume: stats.SelfTransferVolume,
+			FanOutThreshold:    stats.FanOutThreshold,
+			FanOutTransactions: stats.FanOutTransactions,
+			FanOutTransfers:    stats.FanOutTransfers,
+			FanOutVolume:       stats.FanOutVolume,
+
+			ComputedAt: stats.ComputedAt.UTC().Format(time.RFC3339),
+		},
+	}
+
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, s.cacheTTL); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// ratio returns scale * a / b with 4 decimal places, or "" when either is not a valid integer
+// or b is not positive
+func ratio(a, b string, scale int64) string {
+	x, ok := new(big.Int).SetString(a, 10)
+	if !ok {
+		return ""
+	}
+	y, ok := new(big.Int).SetString(b, 10)
+	if !ok || y.Sign() <= 0 {
+		return ""
+	}
+	return new(big.Rat).SetFrac(x.Mul(x, big.NewInt(scale)), y).FloatString(4)
+}
+
+// ComputeAdvancedStats computes and stores each token's velocity over the trailing window, the
+// supply held by addresses without transfers for dormantAfter, and the self-transfers and
+// transactions with more than fanOut transfers from one sender within the window. Failures are
+// logged and do not stop the remaining tokens.
+func (s *StatsService) ComputeAdvancedStats(ctx context.Context, tokenAddresses []string, window, dormantAfter time.Duration, fanOut int) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, tokenAddress := range tokenAddresses {
+		tokenAddress = strings.ToLower(tokenAddress)
+
+		stats, err := s.computeAdvancedStats(ctx, tokenAddress, now, window, dormantAfter, fanOut)
+		if err != nil {
+			s.logger.Warn("Failed to compute advanced stats", zap.String("token", tokenAddress), zap.Error(err))
+			continue
+		}
+		if err := s.advancedStats.Save(ctx, *stats); err != nil {
+			s.logger.Warn("Failed to save advanced stats", zap.String("token", tokenAddress), zap.Error(err))
+			continue
+		}
+		s.logger.Debug("Saved advanced stats",
+			zap.String("token", tokenAddress),
+			zap.String("volume", stats.Volume),
+			zap.String("dormant_supply", stats.DormantSupply),
+		)
+	}
+}
+
+// computeAdvancedStats aggregates a token's advanced stats as of now. The average supply is the
+// mean of the circulating supply at the start and the end of the window.
+func (s *StatsService) computeAdvancedStats(ctx context.Context, tokenAddress string, now time.Time, window, dormantAfter time.Duration, fanOut int) (*repositories.AdvancedStats, error) {
+	from := now.Add(-window)
+	inactiveSince := now.Add(-dormantAfter)
+
+	volume, err := s.rangeWindowStats(ctx, tokenAddress, from, now)
+	if err != nil {
+		return nil, err
+	}
+	startSupply, err := s.transferRepo.GetCirculatingSupplyAt(ctx, tokenAddress, from)
+	if err != nil {
+		return nil, err
+	}
+	endSupply, err := s.transferRepo.GetCirculatingSupply(ctx, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	dormant, err := s.transferRepo.GetDormantSupply(ctx, tokenAddress, inactiveSince)
+	if err != nil {
+		return nil, err
+	}
+	patterns, err := s.transferRepo.GetTransferPatterns(ctx, tokenAddress, from, now, fanOut)
+	if err != nil {
+		return nil, err
+	}
+
+	average := new(big.Int)
+	addVolume(average, startSupply)
+	addVolume(average, endSupply)
+	average.Quo(average, big.NewInt(2))
+
+	return &repositories.AdvancedStats{
+		TokenAddress:      tokenAddress,
+		WindowFrom:        from,
+		WindowTo:          now,
+		Volume:            volume.Volume,
+		AverageSupply:     average.String(),
+		CirculatingSupply: endSupply,
+		InactiveSince:     inactiveSince,
+		DormantSupply:     dormant.Balance,
+		DormantHolders:    dormant.Holders,
+
+		SelfTransfers:      patterns.SelfTransfers,
+		SelfTransferVolume: patterns.SelfTransferVolume,
+		FanOutThreshold:    fanOut,
+		FanOutTransactions: patterns.FanOutTransactions,
+		FanOutTransfers:    patterns.FanOutTransfers,
+		FanOutVolume:       patterns.FanOutVolume,
+
+		ComputedAt: now,
+	}, nil
+}
+
+// RunAdvancedStatsLoop computes advanced stats immediately and then every interval until ctx is cancelled
+func (s *StatsService) RunAdvancedStatsLoop(ctx context.Context, tokenAddresses []string, interval, window, dormantAfter time.Duration, fanOut int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.ComputeAdvancedStats(ctx, tokenAddresses, window, dormantAfter, fanOut)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/notify"
+)
+
+// MaxAlertCounterparties caps the counterparty list of one rule
+const MaxAlertCounterparties = 1000
+
+// ErrInvalidAlertRule is wrapped by validation errors from CreateRule
+var ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+// AlertService manages alert rules and evaluates them against newly indexed transfers
+type AlertService struct {
+	ruleRepo   repositories.AlertRuleRepository
+	tenantRepo repositories.TenantRepository
+	drivers    *notify.Registry
+	logger     *zap.Logger
+}
+
+// NewAlertService creates a new alert service delivering through the given drivers
+func NewAlertService(
+	ruleRepo repositories.AlertRuleRepository,
+	drivers *notify.Registry,
+	logger *zap.Logger,
+) *AlertService {
+	return &AlertService{
+		ruleRepo: ruleRepo,
+		drivers:  drivers,
+		logger:   logger,
+	}
+}
+
+// WithTenants scopes rules to the tenant of the request that created them. Rules of a tenant
+// are only listed to that tenant and only match transfers of the tenant's tokens.
+func (s *AlertService) WithTenants(tenantRepo repositories.TenantRepository) *AlertService {
+	s.tenantRepo = tenantRepo
+	return s
+}
+
+// AlertRuleDTO is the API representation of an alert rule
+type AlertRuleDTO struct {
+	ID             int64    `json:"id"`
+	Name           string   `json:"name"`
+	TokenAddress   *string  `json:"token_address,omitempty"`
+	Address        *string  `json:"address,omitempty"`
+	Direction      string   `json:"direction"`
+	MinValue       string   `json:"min_value,omitempty"`
+	Counterparties []string `json:"counterparties"`
+	Channel        string   `json:"channel"`
+	Target         string   `json:"target"`
+	CreatedAt      string   `json:"created_at"`
+}
+
+// AlertRuleResponse is the API response for a single alert rule
+type AlertRuleResponse struct {
+	Data AlertRuleDTO `json:"data"`
+}
+
+// AlertRuleListResponse is the API response for listing alert rules
+type AlertRuleListResponse struct {
+	Data []AlertRuleDTO `json:"data"`
+}
+
+// CreateRule validates and stores a rule. Validation failures wrap ErrInvalidAlertRule.
+func (s *AlertService) CreateRule(ctx context.Context, rule *entities.AlertRule) (*AlertRuleResponse, error) {
+	if rule.TokenAddress != nil {
+		addr := strings.ToLower(*rule.TokenAddress)
+		rule.TokenAddress = &addr
+	}
+	if rule.Address != nil {
+		addr := strings.ToLower(*rule.Address)
+		rule.Address = &addr
+	}
+	rule.Counterparties = normalizeAddresses(rule.Counterparties)
+	if rule.Direction == "" {
+		rule.Direction = entities.AlertDirectionAny
+	}
+
+	if err := s.validateRule(rule); err != nil {
+		return nil, err
+	}
+
+	if tenant := s.scopedTenant(ctx); tenant != nil {
+		if rule.TokenAddress != nil && !tenantHasToken(tenant, *rule.TokenAddress) {
+			return nil, fmt.Errorf("%w: token_address is not one of the tenant's tokens", ErrInvalidAlertRule)
+		}
+		rule.TenantID = &tenant.ID
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	return &AlertRuleResponse{Data: toAlertRuleDTO(rule)}, nil
+}
+
+func (s *AlertService) validateRule(rule *entities.AlertRule) error {
+	switch rule.Direction {
+	case entities.AlertDirectionIn, entities.AlertDirectionOut, entities.AlertDirectionAny:
+	default:
+		return fmt.Errorf("%w: direction must be in, out or any", ErrInvalidAlertRule)
+	}
+
+	if rule.Address == nil && len(rule.Counterparties) == 0 && rule.MinValue == nil {
+		return fmt.Errorf("%w: at least one of address, counterparties or min_value is required", ErrInvalidAlertRule)
+	}
+	if len(rule.Counterparties) > MaxAlertCounterparties {
+		return fmt.Errorf("%w: at most %d counterparties are allowed", ErrInvalidAlertRule, MaxAlertCounterparties)
+	}
+	if rule.MinValue != nil && rule.MinValue.Sign() < 0 {
+		return fmt.Errorf("%w: min_value must not be negative", ErrInvalidAlertRule)
+	}
+
+	driver, err := s.drivers.Get(rule.Channel)
+	if err != nil {
+		return fmt.Errorf("%w: channel must be one of %s", ErrInvalidAlertRule, strings.Join(s.drivers.Names(), ", "))
+	}
+	if err := driver.Validate(rule.Target); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAlertRule, err)
+	}
+
+	return nil
+}
+
+// GetRule retrieves a rule, or nil if it does not exist
+func (s *AlertService) GetRule(ctx context.Context, id int64) (*AlertRuleResponse, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+	if rule == nil || !s.visible(ctx, rule) {
+		return nil, nil
+	}
+
+	return &AlertRuleResponse{Data: toAlertRuleDTO(rule)}, nil
+}
+
+// ListRules retrieves all rules, oldest first
+func (s *AlertService) ListRules(ctx context.Context) (*AlertRuleListResponse, error) {
+	rules, err := s.ruleRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	dtos := make([]AlertRuleDTO, 0, len(rules))
+	for i := range rules {
+		if s.visible(ctx, &rules[i]) {
+			dtos = append(dtos, toAlertRuleDTO(&rules[i]))
+		}
+	}
+
+	return &AlertRuleListResponse{Data: dtos}, nil
+}
+
+// DeleteRule removes a rule, reporting whether it existed
+func (s *AlertService) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	if s.tenantRepo != nil {
+		rule, err := s.ruleRepo.GetByID(ctx, id)
+		if err != nil {
+			return false, fmt.Errorf("failed to get alert rule: %w", err)
+		}
+		if rule == nil || !s.visible(ctx, rule) {
+			return false, nil
+		}
+	}
+
+	deleted, err := s.ruleRepo.Delete(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+	return deleted, nil
+}
+
+// Evaluate matches every rule against a batch of new transfers and delivers one notification
+// per matched rule. Failures are logged rather than returned so alerting never blocks indexing.
+func (s *AlertService) Evaluate(ctx context.Context, transfers []entities.Transfer) {
+	if len(transfers) == 0 {
+		return
+	}
+
+	rules, err := s.ruleRepo.List(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load alert rules", zap.Error(err))
+		return
+	}
+
+	tenants := make(map[int64]*entities.Tenant)
+	for i := range rules {
+		rule := &rules[i]
+
+		var tenant *entities.Tenant
+		if rule.TenantID != nil {
+			if tenant = s.ruleTenant(ctx, tenants, *rule.TenantID); tenant == nil {
+				continue
+			}
+		}
+
+		var alerts []notify.Alert
+		for j := range transfers {
+			if tenant != nil && !tenantHasToken(tenant, transfers[j].TokenAddress) {
+				continue
+			}
+			if rule.Matches(&transfers[j]) {
+				alerts = append(alerts, toAlert(rule, &transfers[j]))
+			}
+		}
+		if len(alerts) == 0 {
+			continue
+		}
+
+		driver, err := s.drivers.Get(rule.Channel)
+		if err != nil {
+			s.logger.Warn("Alert rule uses an unavailable channel",
+				zap.Int64("rule_id", rule.ID),
+				zap.String("channel", rule.Channel),
+			)
+			continue
+		}
+
+		if err := driver.Send(ctx, rule.Target, alerts); err != nil {
+			s.logger.Warn("Failed to deliver alert",
+				zap.Int64("rule_id", rule.ID),
+				zap.String("channel", rule.Channel),
+				zap.Int("transfers", len(alerts)),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		s.logger.Info("Alert delivered",
+			zap.Int64("rule_id", rule.ID),
+			zap.String("channel", rule.Channel),
+			zap.Int("transfers", len(alerts)),
+		)
+	}
+}
+
+// ruleTenant returns the tenant owning a rule, loading it once per batch into tenants. It returns
+// nil, skipping the rule, when tenants are not configured or the tenant cannot be loaded.
+func (s *AlertService) ruleTenant(ctx context.Context, tenants map[int64]*entities.Tenant, id int64) *entities.Tenant {
+	if s.tenantRepo == nil {
+		return nil
+	}
+	if tenant, ok := tenants[id]; ok {
+		return tenant
+	}
+
+	tenant, err := s.tenantRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Warn("Failed to load alert rule tenant", zap.Int64("tenant_id", id), zap.Error(err))
+	}
+	tenants[id] = tenant
+	return tenant
+}
+
+// scopedTenant returns the tenant the request is scoped to, or nil when rules are not scoped
+func (s *AlertService) scopedTenant(ctx context.Context) *entities.Tenant {
+	if s.tenantRepo == nil {
+		return nil
+	}
+	return TenantFromContext(ctx)
+}
+
+// visible reports whether a rule belongs to the request's tenant, or has no tenant when the
+// request has none
+func (s *AlertService) visible(ctx context.Context, rule *entities.AlertRule) bool {
+	if s.tenantRepo == nil {
+		return true
+	}
+	tenant := TenantFromContext(ctx)
+	if tenant == nil {
+		return rule.TenantID == nil
+	}
+	return rule.TenantID != nil && *rule.TenantID == tenant.ID
+}
+
+func toAlert(rule *entities.AlertRule, t *entities.Transfer) notify.Alert {
+	return notify.Alert{
+		RuleID:         rule.ID,
+		RuleName:       rule.Name,
+		TxHash:         t.TxHash,
+		LogIndex:       t.LogIndex,
+		BlockNumber:    t.BlockNumber,
+		BlockTimestamp: t.BlockTimestamp.UTC().Format("2006-01-02T15:04:05Z"),
+		TokenAddress:   t.TokenAddress,
+		FromAddress:    t.FromAddress,
+		ToAddress:      t.ToAddress,
+		Value:          t.ValueString,
+	}
+}
+
+func toAlertRuleDTO(rule *entities.AlertRule) AlertRuleDTO {
+	dto := AlertRuleDTO{
+		ID:             rule.ID,
+		Name:           rule.Name,
+		TokenAddress:   rule.TokenAddress,
+		Address:        rule.Address,
+		Direction:      rule.Direction,
+		Counterparties: rule.Counterparties,
+		Channel:        rule.Channel,
+		Target:         rule.Target,
+		CreatedAt:      rule.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if rule.MinValue != nil {
+		dto.MinValue = rule.MinValue.String()
+	}
+	if dto.Counterparties == nil {
+		dto.Counterparties = []string{}
+	}
+	return dto
+}
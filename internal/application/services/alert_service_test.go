@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/notify"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestAlertRule_Matches(t *testing.T) {
+	alice := testutil.AliceAddress
+	usdt := testutil.USDTAddress
+	usdc := testutil.USDCAddress
+
+	aliceToBob := testutil.CreateTestTransfer(
+		testutil.WithFromAddress(testutil.AliceAddress),
+		testutil.WithToAddress(testutil.BobAddress),
+		testutil.WithValue(big.NewInt(2_000_000)),
+	)
+
+	tests := []struct {
+		name string
+		rule entities.AlertRule
+		want bool
+	}{
+		{"address out", entities.AlertRule{Address: &alice, Direction: entities.AlertDirectionOut}, true},
+		{"address in", entities.AlertRule{Address: &alice, Direction: entities.AlertDirectionIn}, false},
+		{"address any", entities.AlertRule{Address: &alice, Direction: entities.AlertDirectionAny}, true},
+		{"threshold met", entities.AlertRule{Address: &alice, Direction: entities.AlertDirectionOut, MinValue: big.NewInt(2_000_000)}, true},
+		{"threshold not met", entities.AlertRule{Address: &alice, Direction: entities.AlertDirectionOut, MinValue: big.NewInt(2_000_001)}, false},
+		{"token matches", entities.AlertRule{TokenAddress: &usdt, MinValue: big.NewInt(1)}, true},
+		{"other token", entities.AlertRule{TokenAddress: &usdc, MinValue: big.NewInt(1)}, false},
+		{"counterparty of address", entities.AlertRule{Address: &alice, Direction: entities.AlertDirectionAny, Counterparties: []string{testutil.BobAddress}}, true},
+		{"other counterparty", entities.AlertRule{Address: &alice, Direction: entities.AlertDirectionAny, Counterparties: []string{testutil.CharlieAddr}}, false},
+		{"any transfer to list", entities.AlertRule{Direction: entities.AlertDirectionIn, Counterparties: []string{testutil.BobAddress}}, true},
+		{"any transfer from list", entities.AlertRule{Direction: entities.AlertDirectionOut, Counterparties: []string{testutil.BobAddress}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(&aliceToBob); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAlertService_CreateRule(t *testing.T) {
+	ctx := context.Background()
+	service := NewAlertService(testutil.NewMockAlertRuleRepository(), notify.NewRegistry(testutil.NewMockNotifyDriver("webhook")), zap.NewNop())
+
+	t.Run("stores a valid rule with defaults", func(t *testing.T) {
+		addr := "0x1111111111111111111111111111111111111111"
+		result, err := service.CreateRule(ctx, &entities.AlertRule{
+			Name:    "alice sends",
+			Address: &addr,
+			Channel: "webhook",
+			Target:  "https://example.com/hook",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Data.ID == 0 || result.Data.Direction != entities.AlertDirectionAny {
+			t.Errorf("unexpected rule: %+v", result.Data)
+		}
+	})
+
+	tests := []struct {
+		name string
+		rule entities.AlertRule
+	}{
+		{"no conditions", entities.AlertRule{Channel: "webhook", Target: "x"}},
+		{"bad direction", entities.AlertRule{Direction: "sideways", MinValue: big.NewInt(1), Channel: "webhook", Target: "x"}},
+		{"unknown channel", entities.AlertRule{MinValue: big.NewInt(1), Channel: "sms", Target: "x"}},
+		{"invalid target", entities.AlertRule{MinValue: big.NewInt(1), Channel: "webhook"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			if _, err := service.CreateRule(ctx, &rule); !errors.Is(err, ErrInvalidAlertRule) {
+				t.Errorf("expected ErrInvalidAlertRule, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAlertService_Evaluate(t *testing.T) {
+	ctx := context.Background()
+	alice := testutil.AliceAddress
+
+	repo := testutil.NewMockAlertRuleRepository()
+	_ = repo.Create(ctx, &entities.AlertRule{
+		Name:      "alice sends over 1 USDT",
+		Address:   &alice,
+		Direction: entities.AlertDirectionOut,
+		MinValue:  big.NewInt(1_000_000),
+		Channel:   "webhook",
+		Target:    "https://example.com/hook",
+	})
+	_ = repo.Create(ctx, &entities.AlertRule{
+		Name:           "deny list",
+		Direction:      entities.AlertDirectionAny,
+		Counterparties: []string{testutil.CharlieAddr},
+		Channel:        "email",
+		Target:         "ops@example.com",
+	})
+
+	driver := testutil.NewMockNotifyDriver("webhook")
+	service := NewAlertService(repo, notify.NewRegistry(driver), zap.NewNop())
+
+	service.Evaluate(ctx, []entities.Transfer{
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithFromAddress(alice), testutil.WithValue(big.NewInt(5_000_000))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithFromAddress(alice), testutil.WithValue(big.NewInt(10))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x03"), testutil.WithFromAddress(alice), testutil.WithToAddress(testutil.CharlieAddr)),
+	})
+
+	batches := driver.Sent["https://example.com/hook"]
+	if len(batches) != 1 {
+		t.Fatalf("expected one webhook delivery, got %d", len(batches))
+	}
+	if len(batches[0]) != 2 || batches[0][0].TxHash != "0x01" || batches[0][0].RuleName != "alice sends over 1 USDT" {
+		t.Errorf("unexpected alerts: %+v", batches[0])
+	}
+
+	// The email rule matched but has no registered driver, which must not stop evaluation
+	if len(driver.Sent) != 1 {
+		t.Errorf("expected deliveries to one target, got %d", len(driver.Sent))
+	}
+
+	t.Run("rule load failure is not fatal", func(t *testing.T) {
+		repo.ListFunc = func(ctx context.Context) ([]entities.AlertRule, error) {
+			return nil, errors.New("database error")
+		}
+		service.Evaluate(ctx, []entities.Transfer{testutil.CreateTestTransfer()})
+	})
+}
+
+func TestAlertService_Tenants(t *testing.T) {
+	ctx := context.Background()
+	usdc := testutil.USDCAddress
+
+	tenantRepo := testutil.NewMockTenantRepository(testutil.NewMockTokenRepository())
+	acme := &entities.Tenant{Name: "acme", Tokens: []string{testutil.USDCAddress}}
+	if err := tenantRepo.Create(ctx, acme); err != nil {
+		t.Fatal(err)
+	}
+	acmeCtx := ContextWithTenant(ctx, acme)
+
+	driver := testutil.NewMockNotifyDriver("webhook")
+	service := NewAlertService(testutil.NewMockAlertRuleRepository(), notify.NewRegistry(driver), zap.NewNop()).WithTenants(tenantRepo)
+
+	shared, err := service.CreateRule(ctx, &entities.AlertRule{Name: "shared", MinValue: big.NewInt(1), Channel: "webhook", Target: "https://example.com/shared"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateRule(acmeCtx, &entities.AlertRule{Name: "acme", MinValue: big.NewInt(1), Channel: "webhook", Target: "https://example.com/acme"}); err != nil {
+		t.Fatal(err)
+	}
+	usdt := testutil.USDTAddress
+	if _, err := service.CreateRule(acmeCtx, &entities.AlertRule{Name: "other token", TokenAddress: &usdt, Channel: "webhook", Target: "https://example.com/acme"}); !errors.Is(err, ErrInvalidAlertRule) {
+		t.Errorf("expected a rule on a token outside the tenant's set to be rejected, got %v", err)
+	}
+
+	// Each side only sees its own rules
+	if list, _ := service.ListRules(acmeCtx); len(list.Data) != 1 || list.Data[0].Name != "acme" {
+		t.Errorf("expected the tenant to list only its rule, got %+v", list.Data)
+	}
+	if list, _ := service.ListRules(ctx); len(list.Data) != 1 || list.Data[0].Name != "shared" {
+		t.Errorf("expected requests without a tenant to list only shared rules, got %+v", list.Data)
+	}
+	if rule, _ := service.GetRule(acmeCtx, shared.Data.ID); rule != nil {
+		t.Error("expected the shared rule to be hidden from the tenant")
+	}
+	if deleted, _ := service.DeleteRule(acmeCtx, shared.Data.ID); deleted {
+		t.Error("expected the tenant not to delete the shared rule")
+	}
+
+	// The tenant's webhook only fires for its tokens
+	service.Evaluate(ctx, []entities.Transfer{
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithTokenAddress(testutil.USDTAddress), testutil.WithValue(big.NewInt(5))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithTokenAddress(usdc), testutil.WithValue(big.NewInt(5))),
+	})
+	if batches := driver.Sent["https://example.com/acme"]; len(batches) != 1 || len(batches[0]) != 1 || batches[0][0].TxHash != "0x02" {
+		t.Errorf("expected the tenant's webhook to get only its token's transfer, got %+v", batches)
+	}
+	if batches := driver.Sent["https://example.com/shared"]; len(batches) != 1 || len(batches[0]) != 2 {
+		t.Errorf("expected the shared webhook to get both transfers, got %+v", batches)
+	}
+}
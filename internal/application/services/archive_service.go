@@ -0,0 +1,260 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/archive"
+)
+
+// archivePageSize is the number of transfers read per query when exporting a range
+const archivePageSize = 10000
+
+// ArchiveService exports closed block ranges of transfers as Parquet files to object storage
+// and restores them into the database
+type ArchiveService struct {
+	transferRepo repositories.TransferRepository
+	tokenRepo    repositories.TokenRepository
+	stateRepo    repositories.IndexerStateRepository
+	store        archive.ObjectStore
+	rangeSize    int64
+	logger       *zap.Logger
+}
+
+// NewArchiveService creates a new archive service writing files of rangeSize blocks
+func NewArchiveService(
+	transferRepo repositories.TransferRepository,
+	tokenRepo repositories.TokenRepository,
+	stateRepo repositories.IndexerStateRepository,
+	store archive.ObjectStore,
+	rangeSize int64,
+	logger *zap.Logger,
+) *ArchiveService {
+	return &ArchiveService{
+		transferRepo: transferRepo,
+		tokenRepo:    tokenRepo,
+		stateRepo:    stateRepo,
+		store:        store,
+		rangeSize:    rangeSize,
+		logger:       logger,
+	}
+}
+
+// RestoreResult summarizes a completed restore
+type RestoreResult struct {
+	Files     int
+	FromBlock int64
+	ToBlock   int64
+	Deleted   int64
+	Restored  int64
+}
+
+// ArchiveToken exports every closed range of a token that is not in the manifest yet and returns
+// the number of files written. A range is closed once the indexer has indexed past its last block.
+// Tokens that are being backfilled are skipped, since the backfill may still add to closed ranges.
+func (s *ArchiveService) ArchiveToken(ctx context.Context, tokenAddress string) (int, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	state, err := s.stateRepo.Get(ctx, tokenAddress)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get indexer state: %w", err)
+	}
+	if state == nil {
+		return 0, nil
+	}
+	if state.IsBackfilling {
+		s.logger.Info("Skipping archival while backfilling", zap.String("token", tokenAddress))
+		return 0, nil
+	}
+
+	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get token: %w", err)
+	}
+	if token == nil || token.FirstSeenBlock == nil {
+		return 0, nil // nothing indexed yet
+	}
+
+	manifest, err := archive.LoadManifest(ctx, s.store)
+	if err != nil {
+		return 0, err
+	}
+
+	from := *token.FirstSeenBlock / s.rangeSize * s.rangeSize
+	if last := manifest.LastArchivedBlock(tokenAddress); last >= from {
+		from = last + 1
+	}
+
+	archived := 0
+	for ; from+s.rangeSize-1 <= state.LastIndexedBlock; from += s.rangeSize {
+		if err := ctx.Err(); err != nil {
+			return archived, err
+		}
+
+		to := from + s.rangeSize - 1
+		file, err := s.exportRange(ctx, tokenAddress, from, to)
+		if err != nil {
+			return archived, err
+		}
+
+		// Saved after every file so an interrupted run resumes where it stopped
+		manifest.Add(*file)
+		if err := archive.SaveManifest(ctx, s.store, manifest); err != nil {
+			return archived, err
+		}
+		archived++
+
+		s.logger.Info("Archived block range",
+			zap.String("token", tokenAddress),
+			zap.Int64("from", from),
+			zap.Int64("to", to),
+			zap.Int("rows", file.Rows),
+			zap.String("key", file.Key),
+		)
+	}
+
+	return archived, nil
+}
+
+// ArchiveAll runs ArchiveToken for each token, continuing past failures, and returns the first error
+func (s *ArchiveService) ArchiveAll(ctx context.Context, tokenAddresses []string) error {
+	var firstErr error
+	for _, addr := range tokenAddresses {
+		if _, err := s.ArchiveToken(ctx, addr); err != nil {
+			s.logger.Error("Archival failed", zap.String("token", addr), zap.Error(err))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// RunArchiveLoop archives closed ranges immediately and then every interval until ctx is cancelled
+func (s *ArchiveService) RunArchiveLoop(ctx context.Context, tokenAddresses []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = s.ArchiveAll(ctx, tokenAddresses)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// exportRange writes a token's transfers in [fromBlock, toBlock] to a Parquet file, ordered by block and log index
+func (s *ArchiveService) exportRange(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) (*archive.ManifestFile, error) {
+	var transfers []entities.Transfer
+	for offset := 0; ; offset += archivePageSize {
+		page, err := s.transferRepo.GetByFilter(ctx, entities.TransferFilter{
+			TokenAddress: &tokenAddress,
+			FromBlock:    &fromBlock,
+			ToBlock:      &toBlock,
+			Limit:        archivePageSize,
+			Offset:       offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transfers for blocks %d-%d: %w", fromBlock, toBlock, err)
+		}
+		transfers = append(transfers, page...)
+		if len(page) < archivePageSize {
+			break
+		}
+	}
+
+	sort.Slice(transfers, func(i, j int) bool {
+		if transfers[i].BlockNumber != transfers[j].BlockNumber {
+			return transfers[i].BlockNumber < transfers[j].BlockNumber
+		}
+		return transfers[i].LogIndex < transfers[j].LogIndex
+	})
+
+	var buf bytes.Buffer
+	if err := archive.WriteTransfers(&buf, transfers); err != nil {
+		return nil, fmt.Errorf("failed to encode blocks %d-%d: %w", fromBlock, toBlock, err)
+	}
+
+	key := archive.FileKey(tokenAddress, fromBlock, toBlock)
+	if err := s.store.Put(ctx, key, buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return &archive.ManifestFile{
+		TokenAddress: tokenAddress,
+		FromBlock:    fromBlock,
+		ToBlock:      toBlock,
+		Rows:         len(transfers),
+		Key:          key,
+		SHA256:       archive.Checksum(buf.Bytes()),
+		CreatedAt:    time.Now().UTC(),
+	}, nil
+}
+
+// Restore replaces a token's stored transfers with the archived ones for every file overlapping
+// [fromBlock, toBlock]. Whole files are restored, so the restored range may extend past the requested one.
+func (s *ArchiveService) Restore(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) (*RestoreResult, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	manifest, err := archive.LoadManifest(ctx, s.store)
+	if err != nil {
+		return nil, err
+	}
+
+	files := manifest.Overlapping(tokenAddress, fromBlock, toBlock)
+	result := &RestoreResult{FromBlock: fromBlock, ToBlock: toBlock}
+
+	for _, f := range files {
+		data, err := s.store.Get(ctx, f.Key)
+		if err != nil {
+			return result, fmt.Errorf("failed to download %s: %w", f.Key, err)
+		}
+		if archive.Checksum(data) != f.SHA256 {
+			return result, fmt.Errorf("checksum mismatch for %s", f.Key)
+		}
+
+		transfers, err := archive.ReadTransfers(data)
+		if err != nil {
+			return result, fmt.Errorf("failed to decode %s: %w", f.Key, err)
+		}
+		if len(transfers) != f.Rows {
+			return result, fmt.Errorf("%s has %d rows, manifest lists %d", f.Key, len(transfers), f.Rows)
+		}
+
+		deleted, inserted, err := s.transferRepo.ReplaceRange(ctx, tokenAddress, f.FromBlock, f.ToBlock, transfers)
+		if err != nil {
+			return result, fmt.Errorf("failed to restore blocks %d-%d: %w", f.FromBlock, f.ToBlock, err)
+		}
+
+		result.Files++
+		result.FromBlock = min(result.FromBlock, f.FromBlock)
+		result.ToBlock = max(result.ToBlock, f.ToBlock)
+		result.Deleted += deleted
+		result.Restored += inserted
+
+		s.logger.Info("Restored archive file",
+			zap.String("token", tokenAddress),
+			zap.String("key", f.Key),
+			zap.Int("rows", len(transfers)),
+		)
+	}
+
+	if result.Files > 0 {
+		if _, err := s.tokenRepo.RecountTransfers(ctx, tokenAddress); err != nil {
+			s.logger.Warn("Failed to recount token transfers", zap.String("token", tokenAddress), zap.Error(err))
+		}
+	}
+
+	return result, nil
+}
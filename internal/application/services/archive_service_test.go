@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/archive"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func setupArchiveServiceTest(t *testing.T) (*ArchiveService, *testutil.MockTransferRepository, *testutil.MockIndexerStateRepository, *archive.FileStore) {
+	transferRepo := testutil.NewMockTransferRepository()
+	tokenRepo := testutil.NewMockTokenRepository()
+	stateRepo := testutil.NewMockIndexerStateRepository()
+	store := archive.NewFileStore(t.TempDir())
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithFirstSeenBlock(150)))
+	stateRepo.AddState(&entities.IndexerState{TokenAddress: testutil.USDTAddress, LastIndexedBlock: 420})
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithBlockNumber(150)),
+		testutil.CreateTestTransfer(testutil.WithBlockNumber(250), testutil.WithLogIndex(1)),
+		testutil.CreateTestTransfer(testutil.WithBlockNumber(250), testutil.WithLogIndex(0)),
+		testutil.CreateTestTransfer(testutil.WithBlockNumber(410)),
+	)
+
+	service := NewArchiveService(transferRepo, tokenRepo, stateRepo, store, 100, zap.NewNop())
+	return service, transferRepo, stateRepo, store
+}
+
+func TestArchiveService_ArchiveToken(t *testing.T) {
+	service, _, _, store := setupArchiveServiceTest(t)
+	ctx := context.Background()
+
+	archived, err := service.ArchiveToken(ctx, testutil.USDTAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 100-199, 200-299 and 300-399 are closed; 400-499 is still being indexed
+	if archived != 3 {
+		t.Fatalf("expected 3 files, got %d", archived)
+	}
+
+	manifest, err := archive.LoadManifest(ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Files) != 3 || manifest.Files[1].FromBlock != 200 || manifest.Files[1].Rows != 2 || manifest.Files[2].Rows != 0 {
+		t.Errorf("unexpected manifest: %+v", manifest.Files)
+	}
+
+	data, err := store.Get(ctx, manifest.Files[1].Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfers, err := archive.ReadTransfers(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 2 || transfers[0].LogIndex != 0 || transfers[1].LogIndex != 1 {
+		t.Errorf("expected transfers ordered by log index, got %+v", transfers)
+	}
+
+	archived, err = service.ArchiveToken(ctx, testutil.USDTAddress)
+	if err != nil || archived != 0 {
+		t.Errorf("expected nothing left to archive, got %d (%v)", archived, err)
+	}
+}
+
+func TestArchiveService_ArchiveToken_SkipsBackfill(t *testing.T) {
+	service, _, stateRepo, _ := setupArchiveServiceTest(t)
+	stateRepo.AddState(&entities.IndexerState{TokenAddress: testutil.USDTAddress, LastIndexedBlock: 420, IsBackfilling: true})
+
+	archived, err := service.ArchiveToken(context.Background(), testutil.USDTAddress)
+	if err != nil || archived != 0 {
+		t.Errorf("expected backfilling token to be skipped, got %d (%v)", archived, err)
+	}
+}
+
+func TestArchiveService_Restore(t *testing.T) {
+	service, transferRepo, _, store := setupArchiveServiceTest(t)
+	ctx := context.Background()
+
+	if _, err := service.ArchiveToken(ctx, testutil.USDTAddress); err != nil {
+		t.Fatal(err)
+	}
+	transferRepo.Reset()
+
+	result, err := service.Restore(ctx, testutil.USDTAddress, 220, 260)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Files != 1 || result.Restored != 2 || result.FromBlock != 200 || result.ToBlock != 299 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	restored, _ := transferRepo.GetByFilter(ctx, entities.TransferFilter{Limit: 10})
+	if len(restored) != 2 || restored[0].BlockNumber != 250 || restored[0].ValueString != "1000000" {
+		t.Errorf("unexpected restored transfers: %+v", restored)
+	}
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		if err := store.Put(ctx, archive.FileKey(testutil.USDTAddress, 200, 299), []byte("corrupt")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.Restore(ctx, testutil.USDTAddress, 200, 299); err == nil {
+			t.Error("expected error for corrupted file")
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// Backfill priorities, see config.IndexerConfig.BackfillPriority
+const (
+	BackfillPriorityNormal = "normal" // Backfill as fast as its limits allow
+	BackfillPriorityLow    = "low"    // Pause while live indexing is behind
+)
+
+// CheckBackfillPriority rejects unknown backfill priorities
+func CheckBackfillPriority(priority string) error {
+	switch priority {
+	case BackfillPriorityNormal, BackfillPriorityLow:
+		return nil
+	}
+	return fmt.Errorf("unknown backfill priority %q, expected %s or %s", priority, BackfillPriorityNormal, BackfillPriorityLow)
+}
+
+// WithBackfillThrottle paces Backfill to the configured BackfillBlocksPerMinute and, for a
+// low priority backfill, pauses it before each range while live indexing of any of liveTokens
+// is more than a batch behind its confirmed head. Live indexing runs in another process, so
+// its progress is read from the stored checkpoints.
+func (s *IndexerService) WithBackfillThrottle(liveTokens []string) *IndexerService {
+	if s.config.BackfillBlocksPerMinute > 0 {
+		batch := max(s.config.BackfillBatchSize, 1)
+		s.backfillInterval = time.Duration(int64(batch) * int64(time.Minute) / s.config.BackfillBlocksPerMinute)
+	}
+	if s.config.BackfillPriority == BackfillPriorityLow {
+		s.backfillYieldTo = make([]string, len(liveTokens))
+		for i, addr := range liveTokens {
+			s.backfillYieldTo[i] = strings.ToLower(addr)
+		}
+	}
+	return s
+}
+
+// backfillThrottle spaces the ranges of one backfill run
+type backfillThrottle struct {
+	s    *IndexerService
+	next time.Time // when the next range may be dispatched
+}
+
+// wait returns once the next range of tokenAddress's backfill may be dispatched
+func (t *backfillThrottle) wait(ctx context.Context, tokenAddress string) error {
+	if len(t.s.backfillYieldTo) > 0 {
+		if err := t.s.yieldToLiveIndexing(ctx, tokenAddress); err != nil {
+			return err
+		}
+	}
+	if t.s.backfillInterval <= 0 {
+		return nil
+	}
+
+	if d := time.Until(t.next); d > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+	t.next = time.Now().Add(t.s.backfillInterval)
+	return nil
+}
+
+// yieldToLiveIndexing waits while live indexing lags, checking again every poll interval.
+// A failed check is logged and lets the backfill go on rather than stalling it.
+func (s *IndexerService) yieldToLiveIndexing(ctx context.Context, tokenAddress string) error {
+	paused := false
+	for {
+		lagging, err := s.laggingLiveToken(ctx)
+		if err != nil {
+			s.logger.Warn("Failed to check live indexing progress", zap.Error(err))
+			return nil
+		}
+		if lagging == "" {
+			if paused {
+				s.logger.Info("Backfill resumed, live indexing caught up", zap.String("token", tokenAddress))
+			}
+			return nil
+		}
+		if !paused {
+			s.logger.Info("Backfill paused while live indexing catches up",
+				zap.String("token", tokenAddress),
+				zap.String("lagging_token", lagging),
+			)
+			paused = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.config.PollInterval):
+		}
+	}
+}
+
+// laggingLiveToken returns an active live token more than a batch behind its confirmed head, or ""
+func (s *IndexerService) laggingLiveToken(ctx context.Context) (string, error) {
+	head, err := s.chain.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return "", err
+	}
+	tokens, err := s.tokenRepo.GetByAddresses(ctx, s.backfillYieldTo)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tokens: %w", err)
+	}
+	inactive := make(map[string]bool)
+	for _, t := range tokens {
+		if !t.Active {
+			inactive[t.Address] = true
+		}
+	}
+
+	for _, addr := range s.backfillYieldTo {
+		if inactive[addr] {
+			continue
+		}
+		state, err := s.stateRepo.Get(ctx, addr)
+		if err != nil {
+			return "", fmt.Errorf("failed to get indexer state: %w", err)
+		}
+		if state == nil {
+			continue // not indexed yet
+		}
+		confirmed := ethereum.ConfirmedBlock(head, s.config.ConfirmationsFor(addr))
+		if confirmed-state.LastIndexedBlock > int64(s.config.BatchSize) {
+			return addr, nil
+		}
+	}
+	return "", nil
+}
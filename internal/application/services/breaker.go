@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+var (
+	degradedResponses = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "api_degraded_responses_total",
+			Help: "Reads that could not reach the database, by operation and outcome (stale or unavailable)",
+		},
+		[]string{"operation", "outcome"},
+	)
+
+	dbRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "api_db_retries_total",
+			Help: "Reads retried after a transient database failure, by operation",
+		},
+		[]string{"operation"},
+	)
+
+	dbCircuitOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "api_db_circuit_open",
+		Help: "1 while the database circuit breaker is open, 0 otherwise",
+	})
+)
+
+// ErrUnavailable matches the *UnavailableError returned while the database cannot be reached
+var ErrUnavailable = errors.New("database unavailable")
+
+// UnavailableError is returned when a read failed because the database could not be reached,
+// either after exhausting its retries or because the circuit was open
+type UnavailableError struct {
+	RetryAfter time.Duration // Suggested wait before retrying the request
+	Err        error         // Last database error, nil when the circuit was open
+}
+
+func (e *UnavailableError) Error() string {
+	if e.Err == nil {
+		return "database unavailable: circuit open"
+	}
+	return fmt.Sprintf("database unavailable: %v", e.Err)
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrUnavailable) match any *UnavailableError
+func (e *UnavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+// BreakerConfig configures retries and circuit breaking around database reads
+type BreakerConfig struct {
+	Retries   int           // Retries after a transient failure
+	Backoff   time.Duration // Delay before the first retry, doubled for each one after
+	Threshold int           // Consecutive transient failures that open the circuit
+	Cooldown  time.Duration // How long the circuit stays open before a trial read is let through
+	StaleTTL  time.Duration // How long stale copies of cached responses are kept (0 disables)
+}
+
+// Breaker retries database reads that fail transiently and stops sending reads to the database
+// once Threshold of them fail in a row. After Cooldown a single trial read is let through: if it
+// succeeds the circuit closes, otherwise it stays open for another Cooldown.
+type Breaker struct {
+	cfg         BreakerConfig
+	isTransient func(error) bool
+	logger      *zap.Logger
+	now         func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool // A trial read is in flight
+}
+
+// NewBreaker creates a breaker; isTransient reports whether an error means the database could
+// not be reached, as opposed to a failing query
+func NewBreaker(cfg BreakerConfig, isTransient func(error) bool, logger *zap.Logger) *Breaker {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 1
+	}
+	return &Breaker{
+		cfg:         cfg,
+		isTransient: isTransient,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Do runs fn, retrying it with exponential backoff while it fails transiently. It returns an
+// *UnavailableError when the retries run out or the circuit is open, and fn's error otherwise.
+func (b *Breaker) Do(ctx context.Context, operation string, fn func(context.Context) error) error {
+	backoff := b.cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		if wait, ok := b.admit(); !ok {
+			return &UnavailableError{RetryAfter: wait}
+		}
+
+		err := fn(ctx)
+		transient := err != nil && ctx.Err() == nil && b.isTransient(err)
+		b.record(transient)
+		if !transient {
+			return err
+		}
+
+		if attempt >= b.cfg.Retries {
+			return &UnavailableError{RetryAfter: b.retryAfter(), Err: err}
+		}
+		dbRetries.WithLabelValues(operation).Inc()
+
+		select {
+		case <-ctx.Done():
+			return &UnavailableError{RetryAfter: b.retryAfter(), Err: err}
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// admit reports whether a read may go to the database, and if not, how long until one may
+func (b *Breaker) admit() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.cfg.Threshold {
+		return 0, true
+	}
+	if now := b.now(); now.Before(b.openUntil) {
+		return b.openUntil.Sub(now), false
+	}
+	if b.trial {
+		return 0, false
+	}
+	b.trial = true
+	return 0, true
+}
+
+// record updates the circuit with the outcome of a read
+func (b *Breaker) record(transient bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if !transient {
+		if b.failures >= b.cfg.Threshold {
+			b.logger.Info("Database reachable again, closing circuit")
+			dbCircuitOpen.Set(0)
+		}
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.cfg.Threshold {
+		if b.failures == b.cfg.Threshold {
+			b.logger.Warn("Database unavailable, opening circuit",
+				zap.Int("failures", b.failures),
+				zap.Duration("cooldown", b.cfg.Cooldown),
+			)
+			dbCircuitOpen.Set(1)
+		}
+		b.openUntil = b.now().Add(b.cfg.Cooldown)
+	}
+}
+
+// retryAfter is how long clients should wait before retrying a read that failed
+func (b *Breaker) retryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if wait := b.openUntil.Sub(b.now()); b.failures >= b.cfg.Threshold && wait > 0 {
+		return wait
+	}
+	return b.cfg.Cooldown
+}
+
+// staleKey is where a copy of a cached response is kept past its TTL, to be served while the
+// database is unavailable
+func staleKey(cacheKey string) string {
+	return "stale:" + cacheKey
+}
+
+// guardedLoad runs load through the breaker, or directly when b is nil. Responses load returns
+// are kept under staleKey(cacheKey) for StaleTTL; while the database is unavailable the kept copy
+// is served instead, and without one the *UnavailableError is returned.
+func guardedLoad[T any](
+	ctx context.Context,
+	b *Breaker,
+	c *cache.RedisCache,
+	logger *zap.Logger,
+	operation, cacheKey string,
+	load func(context.Context) (*T, error),
+) (*T, error) {
+	if b == nil {
+		return load(ctx)
+	}
+
+	var result *T
+	err := b.Do(ctx, operation, func(ctx context.Context) error {
+		var err error
+		result, err = load(ctx)
+		return err
+	})
+	if err == nil {
+		if result != nil && c != nil && b.cfg.StaleTTL > 0 {
+			if err := c.SetWithTTL(ctx, staleKey(cacheKey), result, b.cfg.StaleTTL); err != nil {
+				logger.Warn("Failed to cache stale copy", zap.Error(err))
+			}
+		}
+		return result, nil
+	}
+	if !errors.Is(err, ErrUnavailable) {
+		return nil, err
+	}
+
+	if c != nil && b.cfg.StaleTTL > 0 {
+		var stale T
+		if cacheErr := c.Get(ctx, staleKey(cacheKey), &stale); cacheErr == nil {
+			logger.Warn("Database unavailable, serving stale response",
+				zap.String("operation", operation),
+				zap.String("key", cacheKey),
+			)
+			degradedResponses.WithLabelValues(operation, "stale").Inc()
+			return &stale, nil
+		}
+	}
+
+	degradedResponses.WithLabelValues(operation, "unavailable").Inc()
+	return nil, err
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+var errConnRefused = errors.New("connection refused")
+
+func isTestTransient(err error) bool {
+	return errors.Is(err, errConnRefused)
+}
+
+func newTestBreaker(retries, threshold int) *Breaker {
+	return NewBreaker(BreakerConfig{
+		Retries:   retries,
+		Backoff:   time.Millisecond,
+		Threshold: threshold,
+		Cooldown:  10 * time.Second,
+	}, isTestTransient, zap.NewNop())
+}
+
+func TestBreaker_RetriesTransientFailures(t *testing.T) {
+	b := newTestBreaker(2, 5)
+
+	calls := 0
+	err := b.Do(context.Background(), "test", func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errConnRefused
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestBreaker_DoesNotRetryQueryErrors(t *testing.T) {
+	b := newTestBreaker(2, 5)
+	queryErr := errors.New("syntax error")
+
+	calls := 0
+	err := b.Do(context.Background(), "test", func(ctx context.Context) error {
+		calls++
+		return queryErr
+	})
+	if !errors.Is(err, queryErr) || errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected the query error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestBreaker_RetriesExhausted(t *testing.T) {
+	b := newTestBreaker(1, 5)
+
+	err := b.Do(context.Background(), "test", func(ctx context.Context) error {
+		return errConnRefused
+	})
+
+	var unavailable *UnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("expected *UnavailableError, got %v", err)
+	}
+	if !errors.Is(err, errConnRefused) {
+		t.Errorf("expected the database error to be wrapped, got %v", err)
+	}
+}
+
+func TestBreaker_OpensAndRecovers(t *testing.T) {
+	b := newTestBreaker(0, 2)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	failing := func(ctx context.Context) error { return errConnRefused }
+	for i := 0; i < 2; i++ {
+		_ = b.Do(ctx, "test", failing)
+	}
+
+	// Open: reads are refused without reaching the database
+	calls := 0
+	err := b.Do(ctx, "test", func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	var unavailable *UnavailableError
+	if !errors.As(err, &unavailable) || calls != 0 {
+		t.Fatalf("expected the open circuit to refuse the read, got %v after %d calls", err, calls)
+	}
+	if unavailable.RetryAfter != 10*time.Second {
+		t.Errorf("expected retry after 10s, got %v", unavailable.RetryAfter)
+	}
+
+	// After the cooldown a failing trial read reopens the circuit
+	now = now.Add(11 * time.Second)
+	if err := b.Do(ctx, "test", failing); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected the trial read to fail, got %v", err)
+	}
+	if err := b.Do(ctx, "test", func(ctx context.Context) error { calls++; return nil }); !errors.Is(err, ErrUnavailable) || calls != 0 {
+		t.Fatalf("expected the circuit to reopen, got %v after %d calls", err, calls)
+	}
+
+	// A succeeding trial read closes it
+	now = now.Add(11 * time.Second)
+	for i := 0; i < 2; i++ {
+		if err := b.Do(ctx, "test", func(ctx context.Context) error { calls++; return nil }); err != nil {
+			t.Fatalf("expected the circuit to close, got %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls once closed, got %d", calls)
+	}
+}
+
+func TestTokenService_GetByAddress_Unavailable(t *testing.T) {
+	service, tokenRepo := setupTokenServiceTest()
+	service.WithBreaker(newTestBreaker(1, 5))
+
+	calls := 0
+	tokenRepo.GetByAddressFunc = func(ctx context.Context, address string) (*entities.Token, error) {
+		calls++
+		return nil, errConnRefused
+	}
+
+	_, err := service.GetByAddress(context.Background(), testutil.USDTAddress)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the read to be retried once, got %d calls", calls)
+	}
+}
+
+func TestGuardedReads_Unavailable(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+
+	portfolioRepo := testutil.NewMockPortfolioRepository()
+	portfolioRepo.GetWalletHoldingsFunc = func(ctx context.Context, walletAddress string) ([]entities.TokenHolding, error) {
+		return nil, errConnRefused
+	}
+	swapRepo := testutil.NewMockSwapRepository()
+	swapRepo.GetPoolFunc = func(ctx context.Context, address string) (*entities.DexPool, error) {
+		return nil, errConnRefused
+	}
+	watchlistRepo := testutil.NewMockWatchlistRepository()
+	watchlistRepo.GetByIDFunc = func(ctx context.Context, id int64) (*entities.Watchlist, error) {
+		return nil, errConnRefused
+	}
+	entityRepo := testutil.NewMockEntityRepository()
+	entityRepo.GetByIDFunc = func(ctx context.Context, id int64) (*entities.Entity, error) {
+		return nil, errConnRefused
+	}
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.GetByFilterFunc = func(ctx context.Context, filter entities.TransferFilter) ([]entities.Transfer, error) {
+		return nil, errConnRefused
+	}
+
+	breaker := newTestBreaker(0, 100)
+	transferService := NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, logger).WithBreaker(breaker)
+	portfolioService := NewPortfolioService(portfolioRepo, nil, logger).WithBreaker(breaker)
+	swapService := NewSwapService(swapRepo, nil, logger).WithBreaker(breaker)
+	watchlistService := NewWatchlistService(watchlistRepo, transferService, logger).WithBreaker(breaker)
+	entityService := NewEntityService(entityRepo, portfolioRepo, transferService, logger).WithBreaker(breaker)
+
+	reads := map[string]func() error{
+		"portfolio": func() error {
+			_, err := portfolioService.GetPortfolio(ctx, testutil.AliceAddress)
+			return err
+		},
+		"pool swaps": func() error {
+			_, err := swapService.GetPoolSwaps(ctx, testutil.AliceAddress, 10, 0)
+			return err
+		},
+		"watchlist transfers": func() error {
+			_, err := watchlistService.GetWatchlistTransfers(ctx, 1, 10, 0)
+			return err
+		},
+		"entity summary": func() error {
+			_, err := entityService.GetEntitySummary(ctx, 1)
+			return err
+		},
+		"transfer page": func() error {
+			_, err := transferService.GetTransferPage(ctx, entities.DefaultTransferFilter())
+			return err
+		},
+	}
+
+	for name, read := range reads {
+		if err := read(); !errors.Is(err, ErrUnavailable) {
+			t.Errorf("%s: expected ErrUnavailable, got %v", name, err)
+		}
+	}
+}
+
+func TestTransferService_GetTransferPage_ServesStaleCopy(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(testutil.CreateTestTransfer())
+	breaker := NewBreaker(BreakerConfig{
+		Threshold: 100,
+		Cooldown:  10 * time.Second,
+		StaleTTL:  time.Minute,
+	}, isTestTransient, logger)
+	service := NewTransferService(transferRepo, testutil.NewMockTokenRepository(), cache.NewMemoryCache(time.Minute, logger), logger).
+		WithBreaker(breaker)
+
+	filter := entities.DefaultTransferFilter()
+	if _, err := service.GetTransferPage(ctx, filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	transferRepo.GetByFilterFunc = func(ctx context.Context, filter entities.TransferFilter) ([]entities.Transfer, error) {
+		return nil, errConnRefused
+	}
+
+	page, err := service.GetTransferPage(ctx, filter)
+	if err != nil {
+		t.Fatalf("expected the stale page, got %v", err)
+	}
+	if len(page.Data) != 1 {
+		t.Errorf("expected 1 transfer, got %d", len(page.Data))
+	}
+}
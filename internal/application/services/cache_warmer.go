@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+// Cache warm targets, the cached responses CacheWarmer can reload after indexing
+const (
+	WarmTargetStats     = "stats"     // GET /tokens/{address}/stats
+	WarmTargetHolders   = "holders"   // First page of GET /tokens/{address}/holders
+	WarmTargetTransfers = "transfers" // First page of GET /tokens/{address}/transfers
+)
+
+// WarmTargets lists the supported cache warm targets
+var WarmTargets = []string{WarmTargetStats, WarmTargetHolders, WarmTargetTransfers}
+
+var cacheWarms = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "api_cache_warms_total",
+		Help: "Cached responses reloaded after indexing, by target and result (ok or error)",
+	},
+	[]string{"target", "result"},
+)
+
+// CheckWarmTargets rejects unknown cache warm targets
+func CheckWarmTargets(targets []string) error {
+	for _, target := range targets {
+		if !slices.Contains(WarmTargets, target) {
+			return fmt.Errorf("unknown cache warm target %q, expected one of %s", target, strings.Join(WarmTargets, ", "))
+		}
+	}
+	return nil
+}
+
+// CacheWarmer reloads the cached responses of tokens the indexer has just written to, so the
+// first request after a cache entry expires does not pay for the query. Each token is warmed
+// at most once per interval across all processes sharing the cache.
+type CacheWarmer struct {
+	cache          *cache.RedisCache
+	targets        []string
+	interval       time.Duration
+	stats          *StatsService
+	holders        *HoldersService
+	transfers      *TransferService
+	transfersLimit int
+	logger         *zap.Logger
+}
+
+// NewCacheWarmer creates a cache warmer for the given targets. The services of the targets are
+// set with WithStats, WithHolders and WithTransfers; targets without one are skipped.
+func NewCacheWarmer(cache *cache.RedisCache, targets []string, interval time.Duration, logger *zap.Logger) *CacheWarmer {
+	return &CacheWarmer{
+		cache:    cache,
+		targets:  targets,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// WithStats warms token stats through s
+func (w *CacheWarmer) WithStats(s *StatsService) *CacheWarmer {
+	w.stats = s
+	return w
+}
+
+// WithHolders warms top holder pages through s
+func (w *CacheWarmer) WithHolders(s *HoldersService) *CacheWarmer {
+	w.holders = s
+	return w
+}
+
+// WithTransfers warms token transfer pages of limit transfers through s. limit must be the
+// default page size of the transfers endpoints for the warmed page to be the one served.
+func (w *CacheWarmer) WithTransfers(s *TransferService, limit int) *CacheWarmer {
+	w.transfers = s
+	w.transfersLimit = limit
+	return w
+}
+
+// Run warms the caches of each token address received on tokens until the channel is closed
+func (w *CacheWarmer) Run(ctx context.Context, tokens <-chan string) {
+	w.logger.Info("Warming caches after indexing",
+		zap.Strings("targets", w.targets),
+		zap.Duration("interval", w.interval),
+	)
+	for token := range tokens {
+		w.Warm(ctx, token)
+	}
+}
+
+// Warm reloads a token's cached responses unless they were warmed within the interval
+func (w *CacheWarmer) Warm(ctx context.Context, tokenAddress string) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	locked, err := w.cache.TryLock(ctx, "warm_lock:"+tokenAddress, w.interval)
+	if err != nil {
+		w.logger.Warn("Failed to claim cache warming", zap.String("token", tokenAddress), zap.Error(err))
+		return
+	}
+	if !locked {
+		return
+	}
+
+	for _, target := range w.targets {
+		err := w.warm(ctx, target, tokenAddress)
+		if errors.Is(err, errNoWarmService) {
+			continue
+		}
+
+		result := "ok"
+		if err != nil {
+			result = "error"
+			w.logger.Warn("Failed to warm cache",
+				zap.String("target", target),
+				zap.String("token", tokenAddress),
+				zap.Error(err),
+			)
+		}
+		cacheWarms.WithLabelValues(target, result).Inc()
+	}
+}
+
+// errNoWarmService reports a target whose service was not set
+var errNoWarmService = errors.New("no service to warm through")
+
+func (w *CacheWarmer) warm(ctx context.Context, target, tokenAddress string) error {
+	switch target {
+	case WarmTargetStats:
+		if w.stats == nil {
+			return errNoWarmService
+		}
+		return w.stats.warmTokenStats(ctx, tokenAddress)
+	case WarmTargetHolders:
+		if w.holders == nil {
+			return errNoWarmService
+		}
+		return w.holders.warmTopHolders(ctx, tokenAddress)
+	case WarmTargetTransfers:
+		if w.transfers == nil {
+			return errNoWarmService
+		}
+		return w.transfers.warmTokenTransfers(ctx, tokenAddress, w.transfersLimit)
+	}
+	return errNoWarmService
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestCacheWarmer_Warm(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	c := cache.NewMemoryCache(time.Minute, logger)
+
+	transferRepo := testutil.NewMockTransferRepository()
+	tokenRepo := testutil.NewMockTokenRepository()
+	tokenRepo.AddToken(testutil.CreateTestToken())
+	transferRepo.AddTransfers(testutil.CreateTestTransfer())
+
+	stats := NewStatsService(transferRepo, tokenRepo, c, logger)
+	holders := NewHoldersService(transferRepo, tokenRepo, c, logger).WithPageSizes(50, 20)
+	transfers := NewTransferService(transferRepo, tokenRepo, c, logger)
+	warmer := NewCacheWarmer(c, WarmTargets, time.Minute, logger).
+		WithStats(stats).
+		WithHolders(holders).
+		WithTransfers(transfers, 25)
+
+	// A stale holder count is replaced rather than reused
+	if err := c.Set(ctx, "holders_count:"+testutil.USDTAddress, 999); err != nil {
+		t.Fatal(err)
+	}
+
+	warmer.Warm(ctx, testutil.USDTAddress)
+
+	var statsResp TokenStatsResponse
+	if err := c.Get(ctx, "stats:"+testutil.USDTAddress, &statsResp); err != nil || statsResp.Data.TokenAddress != testutil.USDTAddress {
+		t.Errorf("expected warmed stats, got %+v (%v)", statsResp, err)
+	}
+
+	// The holders page is the one GetTopHolders serves without a limit: the default capped at the maximum
+	var holdersResp TopHoldersResponse
+	if err := c.Get(ctx, "holders:"+testutil.USDTAddress+":20:0", &holdersResp); err != nil || holdersResp.Pagination.Total == 999 {
+		t.Errorf("expected a warmed holders page with a fresh count, got %+v (%v)", holdersResp, err)
+	}
+
+	token := testutil.USDTAddress
+	var transfersResp TransferResponse
+	key := transfers.generateCacheKey(entities.TransferFilter{TokenAddress: &token, Limit: 25})
+	if err := c.Get(ctx, key, &transfersResp); err != nil || len(transfersResp.Transfers) != 1 {
+		t.Errorf("expected a warmed transfers page, got %+v (%v)", transfersResp, err)
+	}
+
+	// Within the interval the token is not warmed again
+	if err := c.Delete(ctx, "stats:"+testutil.USDTAddress); err != nil {
+		t.Fatal(err)
+	}
+	warmer.Warm(ctx, testutil.USDTAddress)
+	if err := c.Get(ctx, "stats:"+testutil.USDTAddress, &statsResp); !errors.Is(err, cache.ErrCacheMiss) {
+		t.Errorf("expected no warming within the interval, got %v", err)
+	}
+}
+
+func TestCheckWarmTargets(t *testing.T) {
+	if err := CheckWarmTargets([]string{"stats", "transfers"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := CheckWarmTargets([]string{"stats", "portfolio"}); err == nil {
+		t.Error("expected an unknown target to be rejected")
+	}
+}
+
+func TestIndexerService_AnnouncesIndexedTokens(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := cache.NewMemoryCache(time.Minute, zap.NewNop())
+	announced := c.Subscribe(ctx, cache.IndexedTokensChannel)
+
+	transferRepo := testutil.NewMockTransferRepository()
+	service := NewIndexerService(nil, nil, testutil.NewMockTokenRepository(), transferRepo, nil, config.IndexerConfig{}, zap.NewNop()).
+		WithIndexedEvents(c)
+
+	transfers := []entities.Transfer{testutil.CreateTestTransfer()}
+	if _, err := service.storeTokenTransfers(ctx, testutil.USDTAddress, 100, transfers); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case token := <-announced:
+		if token != testutil.USDTAddress {
+			t.Errorf("expected %s announced, got %s", testutil.USDTAddress, token)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the token to be announced")
+	}
+
+	// Transfers already stored are not news
+	transferRepo.BatchInsertFunc = func(ctx context.Context, transfers []entities.Transfer) (int64, error) {
+		return 0, nil
+	}
+	if _, err := service.storeTokenTransfers(ctx, testutil.USDTAddress, 100, transfers); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case token := <-announced:
+		t.Errorf("expected no announcement for duplicates, got %s", token)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
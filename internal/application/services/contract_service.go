@@ -0,0 +1,252 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// ErrInvalidContractEventFilter is returned for event or argument filters a contract's ABI does not support
+var ErrInvalidContractEventFilter = errors.New("invalid contract event filter")
+
+// MaxContractArgClauses bounds the argument comparisons of one contract event query
+const MaxContractArgClauses = 10
+
+// contractArgClausePrefix starts the argument of a filter clause, as in args.value>1000
+const contractArgClausePrefix = "args."
+
+// ContractService registers contracts by ABI and queries the events the indexer decoded for them
+type ContractService struct {
+	contractRepo repositories.ContractRepository
+	logger       *zap.Logger
+}
+
+// NewContractService creates a new contract service
+func NewContractService(contractRepo repositories.ContractRepository, logger *zap.Logger) *ContractService {
+	return &ContractService{
+		contractRepo: contractRepo,
+		logger:       logger,
+	}
+}
+
+// ContractDTO is the API representation of a registered contract
+type ContractDTO struct {
+	Address   string   `json:"address"`
+	Name      string   `json:"name"`
+	Events    []string `json:"events"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// ContractResponse is the API response for contract registration
+type ContractResponse struct {
+	Data ContractDTO `json:"data"`
+}
+
+// ContractEventDTO is the API representation of a decoded contract event
+type ContractEventDTO struct {
+	Event          string          `json:"event"`
+	TxHash         string          `json:"tx_hash"`
+	LogIndex       int             `json:"log_index"`
+	BlockNumber    int64           `json:"block_number"`
+	BlockTimestamp string          `json:"block_timestamp"`
+	Topics         []string        `json:"topics"`
+	Args           json.RawMessage `json:"args"`
+}
+
+// ContractEventsResponse is the API response for contract event queries
+type ContractEventsResponse struct {
+	Contract ContractDTO        `json:"contract"`
+	Events   []ContractEventDTO `json:"events"`
+	Total    int64              `json:"total"`
+	Limit    int                `json:"limit"`
+	Offset   int                `json:"offset"`
+	HasMore  bool               `json:"has_more"`
+}
+
+// RegisterContract registers the contract at address with its JSON ABI and the events to index,
+// every event of the ABI when none are named. Registering it again replaces the ABI and events.
+// The indexer picks registrations up when it starts.
+func (s *ContractService) RegisterContract(ctx context.Context, address, name, abiJSON string, events []string) (*ContractResponse, error) {
+	parsed, err := ethereum.ParseContractABI(abiJSON, events)
+	if err != nil {
+		return nil, err
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(abiJSON)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ethereum.ErrInvalidContractABI, err)
+	}
+
+	contract := &entities.Contract{
+		Address: strings.ToLower(address),
+		Name:    strings.TrimSpace(name),
+		ABI:     compact.String(),
+		Events:  parsed.EventNames(),
+	}
+	if err := s.contractRepo.UpsertContract(ctx, contract); err != nil {
+		return nil, err
+	}
+
+	return &ContractResponse{Data: toContractDTO(contract)}, nil
+}
+
+// GetContractEvents retrieves a page of a contract's decoded events, newest first, optionally of
+// one event and with arguments matching every clause of where. A clause compares an argument with
+// a value using one of entities.ContractArgOperators, as in args.to=0xabc or args.value>1000;
+// values are parsed by the argument's ABI type, so filtering on arguments requires the event. It
+// returns nil if the contract is not registered.
+func (s *ContractService) GetContractEvents(ctx context.Context, address, event string, where []string, limit, offset int) (*ContractEventsResponse, error) {
+	contract, err := s.contractRepo.GetContract(ctx, strings.ToLower(address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, nil
+	}
+
+	filter := entities.ContractEventFilter{
+		ContractAddress: contract.Address,
+		Limit:           limit,
+		Offset:          offset,
+	}
+	if event != "" || len(where) > 0 {
+		if filter.Args, filter.Conditions, err = s.argFilter(contract, event, where); err != nil {
+			return nil, err
+		}
+		filter.EventName = &event
+	}
+
+	events, err := s.contractRepo.GetEvents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract events: %w", err)
+	}
+
+	total, err := s.contractRepo.GetEventCount(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract event count: %w", err)
+	}
+
+	dtos := make([]ContractEventDTO, len(events))
+	for i, e := range events {
+		dtos[i] = ContractEventDTO{
+			Event:          e.EventName,
+			TxHash:         e.TxHash,
+			LogIndex:       e.LogIndex,
+			BlockNumber:    e.BlockNumber,
+			BlockTimestamp: e.BlockTimestamp.UTC().Format("2006-01-02T15:04:05Z"),
+			Topics:         e.Topics,
+			Args:           e.Args,
+		}
+	}
+
+	return &ContractEventsResponse{
+		Contract: toContractDTO(contract),
+		Events:   dtos,
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+		HasMore:  int64(offset+len(dtos)) < total,
+	}, nil
+}
+
+// argFilter checks the event is indexed for the contract and translates the where clauses into
+// equalities, matched by containment, and the other comparisons
+func (s *ContractService) argFilter(contract *entities.Contract, event string, where []string) (map[string]json.RawMessage, []entities.ContractArgCondition, error) {
+	if event == "" {
+		return nil, nil, fmt.Errorf("%w: filtering on arguments requires an event", ErrInvalidContractEventFilter)
+	}
+	if len(where) > MaxContractArgClauses {
+		return nil, nil, fmt.Errorf("%w: at most %d argument clauses are allowed, got %d", ErrInvalidContractEventFilter, MaxContractArgClauses, len(where))
+	}
+
+	parsed, err := ethereum.ParseContractABI(contract.ABI, contract.Events)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the ABI of contract %s: %w", contract.Address, err)
+	}
+	if !parsed.HasEvent(event) {
+		return nil, nil, fmt.Errorf("%w: event %q is not indexed for this contract", ErrInvalidContractEventFilter, event)
+	}
+
+	var args map[string]json.RawMessage
+	var conditions []entities.ContractArgCondition
+	for _, clause := range where {
+		name, op, raw, err := parseContractArgClause(clause)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidContractEventFilter, err)
+		}
+		if op.IsRange() && !parsed.IsIntegerArg(event, name) {
+			return nil, nil, fmt.Errorf("%w: %s is not an integer argument and cannot be compared with %s", ErrInvalidContractEventFilter, name, op)
+		}
+		value, err := parsed.ArgValue(event, name, raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidContractEventFilter, err)
+		}
+
+		if op != entities.ContractArgEq {
+			conditions = append(conditions, entities.ContractArgCondition{Arg: name, Operator: op, Value: value})
+			continue
+		}
+		if _, dup := args[name]; dup {
+			return nil, nil, fmt.Errorf("%w: %s is compared for equality more than once", ErrInvalidContractEventFilter, name)
+		}
+		if args == nil {
+			args = make(map[string]json.RawMessage)
+		}
+		args[name] = value
+	}
+	return args, conditions, nil
+}
+
+// parseContractArgClause splits a clause such as args.value>=1000 into the argument name, the
+// longest allowed operator following it and the value
+func parseContractArgClause(clause string) (string, entities.ContractArgOperator, string, error) {
+	rest, ok := strings.CutPrefix(clause, contractArgClausePrefix)
+	if !ok {
+		return "", "", "", fmt.Errorf("clause %q must start with %s", clause, contractArgClausePrefix)
+	}
+
+	// Argument names are Solidity identifiers
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return !(r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'))
+	})
+	if end == 0 {
+		return "", "", "", fmt.Errorf("clause %q names no argument", clause)
+	}
+	if end > 0 {
+		name := rest[:end]
+		for _, op := range entities.ContractArgOperators {
+			if value, ok := strings.CutPrefix(rest[end:], string(op)); ok {
+				if value == "" {
+					return "", "", "", fmt.Errorf("clause %q has no value", clause)
+				}
+				return name, op, value, nil
+			}
+		}
+	}
+
+	operators := make([]string, len(entities.ContractArgOperators))
+	for i, op := range entities.ContractArgOperators {
+		operators[i] = string(op)
+	}
+	return "", "", "", fmt.Errorf("clause %q has no operator, expected one of %s", clause, strings.Join(operators, " "))
+}
+
+func toContractDTO(contract *entities.Contract) ContractDTO {
+	return ContractDTO{
+		Address:   contract.Address,
+		Name:      contract.Name,
+		Events:    contract.Events,
+		CreatedAt: contract.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		UpdatedAt: contract.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
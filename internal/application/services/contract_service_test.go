@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestContractService_RegisterContract(t *testing.T) {
+	repo := testutil.NewMockContractRepository()
+	service := NewContractService(repo, zap.NewNop())
+	ctx := context.Background()
+
+	response, err := service.RegisterContract(ctx, testutil.USDTAddress, " Tether ", testutil.ERC20EventsABI, []string{"Transfer"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Data.Name != "Tether" || len(response.Data.Events) != 1 || response.Data.Events[0] != "Transfer" || response.Data.CreatedAt == "" {
+		t.Errorf("unexpected registration: %+v", response.Data)
+	}
+	if stored, _ := repo.GetContract(ctx, testutil.USDTAddress); stored == nil || strings.ContainsAny(stored.ABI, "\n\t") {
+		t.Errorf("expected the compacted ABI to be stored, got %+v", stored)
+	}
+
+	// Registering again without events selects all of them
+	response, err = service.RegisterContract(ctx, testutil.USDTAddress, "Tether", testutil.ERC20EventsABI, nil)
+	if err != nil || len(response.Data.Events) != 2 {
+		t.Errorf("expected both events, got %+v (%v)", response, err)
+	}
+
+	if _, err := service.RegisterContract(ctx, testutil.USDTAddress, "Tether", testutil.ERC20EventsABI, []string{"Swap"}); !errors.Is(err, ethereum.ErrInvalidContractABI) {
+		t.Errorf("expected ErrInvalidContractABI for a missing event, got %v", err)
+	}
+	if _, err := service.RegisterContract(ctx, testutil.USDTAddress, "Tether", `{"not":"an abi"}`, nil); !errors.Is(err, ethereum.ErrInvalidContractABI) {
+		t.Errorf("expected ErrInvalidContractABI for a malformed ABI, got %v", err)
+	}
+}
+
+func TestContractService_GetContractEvents(t *testing.T) {
+	repo := testutil.NewMockContractRepository()
+	service := NewContractService(repo, zap.NewNop())
+	ctx := context.Background()
+
+	if response, err := service.GetContractEvents(ctx, testutil.USDTAddress, "", nil, 10, 0); err != nil || response != nil {
+		t.Errorf("expected nil for an unregistered contract, got %+v (%v)", response, err)
+	}
+
+	if _, err := service.RegisterContract(ctx, testutil.USDTAddress, "Tether", testutil.ERC20EventsABI, []string{"Transfer"}); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	_ = repo.StoreEvents(ctx, []any{
+		&entities.ContractEvent{ContractAddress: testutil.USDTAddress, EventName: "Transfer", TxHash: "0xa1", BlockNumber: 1, BlockTimestamp: ts,
+			Args: []byte(`{"from":"` + testutil.AliceAddress + `","to":"` + testutil.BobAddress + `","value":1180591620717411303424}`)},
+		&entities.ContractEvent{ContractAddress: testutil.USDTAddress, EventName: "Transfer", TxHash: "0xa2", BlockNumber: 2, BlockTimestamp: ts.Add(time.Minute),
+			Args: []byte(`{"from":"` + testutil.AliceAddress + `","to":"` + testutil.CharlieAddr + `","value":5}`)},
+	})
+
+	all, err := service.GetContractEvents(ctx, strings.ToUpper(testutil.USDTAddress), "", nil, 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if all.Total != 2 || len(all.Events) != 1 || !all.HasMore || all.Events[0].TxHash != "0xa2" || all.Contract.Name != "Tether" {
+		t.Errorf("expected the newest of 2 events, got %+v", all)
+	}
+
+	// Values are matched as stored, whatever case the address is given in
+	filtered, err := service.GetContractEvents(ctx, testutil.USDTAddress, "Transfer",
+		[]string{"args.to=0x" + strings.ToUpper(testutil.BobAddress[2:]), "args.value=1180591620717411303424"}, 10, 0)
+	if err != nil || filtered.Total != 1 || filtered.Events[0].TxHash != "0xa1" || filtered.Events[0].Event != "Transfer" {
+		t.Errorf("expected the transfer to Bob, got %+v (%v)", filtered, err)
+	}
+
+	// Ranges compare integers past 64 bits exactly
+	for clauses, want := range map[string]string{
+		"args.value>1180591620717411303423":             "0xa1",
+		"args.value<=5":                                 "0xa2",
+		"args.value>=5&args.to!=" + testutil.BobAddress: "0xa2",
+	} {
+		filtered, err := service.GetContractEvents(ctx, testutil.USDTAddress, "Transfer", strings.Split(clauses, "&"), 10, 0)
+		if err != nil || filtered.Total != 1 || filtered.Events[0].TxHash != want {
+			t.Errorf("%s: expected %s, got %+v (%v)", clauses, want, filtered, err)
+		}
+	}
+
+	tooMany := make([]string, MaxContractArgClauses+1)
+	for i := range tooMany {
+		tooMany[i] = "args.value>1"
+	}
+	for name, tc := range map[string]struct {
+		event string
+		where []string
+	}{
+		"args without event":   {"", []string{"args.to=" + testutil.BobAddress}},
+		"unindexed event":      {"Approval", nil},
+		"unknown argument":     {"Transfer", []string{"args.amount=5"}},
+		"malformed value":      {"Transfer", []string{"args.value=five"}},
+		"address without 0x":   {"Transfer", []string{"args.to=" + testutil.BobAddress[2:]}},
+		"range on an address":  {"Transfer", []string{"args.to>" + testutil.BobAddress}},
+		"repeated equality":    {"Transfer", []string{"args.value=5", "args.value=6"}},
+		"too many clauses":     {"Transfer", tooMany},
+		"not an argument":      {"Transfer", []string{"event=Transfer"}},
+		"no argument name":     {"Transfer", []string{"args.=5"}},
+		"operator not allowed": {"Transfer", []string{"args.value~5"}},
+		"no value":             {"Transfer", []string{"args.value>="}},
+		"SQL in the name":      {"Transfer", []string{"args.value' OR 1=1 --"}},
+	} {
+		if _, err := service.GetContractEvents(ctx, testutil.USDTAddress, tc.event, tc.where, 10, 0); !errors.Is(err, ErrInvalidContractEventFilter) {
+			t.Errorf("%s: expected ErrInvalidContractEventFilter, got %v", name, err)
+		}
+	}
+}
+
+func TestParseContractArgClause(t *testing.T) {
+	tests := []struct {
+		clause string
+		name   string
+		op     entities.ContractArgOperator
+		value  string
+	}{
+		{"args.to=0xabc", "to", entities.ContractArgEq, "0xabc"},
+		{"args.value>=1000", "value", entities.ContractArgGte, "1000"},
+		{"args.value<=1000", "value", entities.ContractArgLte, "1000"},
+		{"args.value>1000", "value", entities.ContractArgGt, "1000"},
+		{"args.value<1000", "value", entities.ContractArgLt, "1000"},
+		{"args.memo!=a=b", "memo", entities.ContractArgNe, "a=b"},
+		{"args.arg0=1", "arg0", entities.ContractArgEq, "1"},
+	}
+
+	for _, tt := range tests {
+		name, op, value, err := parseContractArgClause(tt.clause)
+		if err != nil || name != tt.name || op != tt.op || value != tt.value {
+			t.Errorf("%s: expected %s %s %s, got %s %s %s (%v)", tt.clause, tt.name, tt.op, tt.value, name, op, value, err)
+		}
+	}
+}
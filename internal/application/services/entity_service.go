@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/pkg/units"
+)
+
+// MaxEntityAddresses caps how many addresses one entity can hold.
+// Aggregates match members with = ANY($1), so the cap is higher than for watchlists.
+const MaxEntityAddresses = 1000
+
+// ErrEntityFull is returned when adding addresses would exceed MaxEntityAddresses
+var ErrEntityFull = errors.New("entity address limit exceeded")
+
+// EntityService provides business logic for entities: groups of addresses reported as one
+type EntityService struct {
+	entityRepo      repositories.EntityRepository
+	portfolioRepo   repositories.PortfolioRepository
+	transferService *TransferService
+	breaker         *Breaker
+	exposure        *TokenExposure
+	logger          *zap.Logger
+}
+
+// NewEntityService creates a new entity service.
+// Aggregates are not cached because membership can change at any time.
+func NewEntityService(
+	entityRepo repositories.EntityRepository,
+	portfolioRepo repositories.PortfolioRepository,
+	transferService *TransferService,
+	logger *zap.Logger,
+) *EntityService {
+	return &EntityService{
+		entityRepo:      entityRepo,
+		portfolioRepo:   portfolioRepo,
+		transferService: transferService,
+		logger:          logger,
+	}
+}
+
+// WithBreaker retries entity reads through b, answering with an *UnavailableError while the
+// database is unavailable. Entity aggregates are not cached, so there are no stale copies to serve.
+func (s *EntityService) WithBreaker(b *Breaker) *EntityService {
+	s.breaker = b
+	return s
+}
+
+// WithExposure leaves the tokens e does not expose to the request out of entity holdings and
+// volumes; entity transfers are narrowed by the transfer service's own exposure
+func (s *EntityService) WithExposure(e *TokenExposure) *EntityService {
+	s.exposure = e
+	return s
+}
+
+// EntityDTO is the API representation of an entity
+type EntityDTO struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Category  string   `json:"category,omitempty"`
+	Addresses []string `json:"addresses"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// EntityResponse is the API response for entity queries
+type EntityResponse struct {
+	Data EntityDTO `json:"data"`
+}
+
+// EntityPortfolioDTO is the combined holdings of an entity's addresses
+type EntityPortfolioDTO struct {
+	EntityID     int64             `json:"entity_id"`
+	Name         string            `json:"name"`
+	AddressCount int               `json:"address_count"`
+	Holdings     []TokenHoldingDTO `json:"holdings"`
+}
+
+// EntityPortfolioResponse wraps entity holdings for API response
+type EntityPortfolioResponse struct {
+	Data EntityPortfolioDTO `json:"data"`
+}
+
+// EntityTokenVolumeDTO is an entity's flows in one token.
+// Internal transfers moved funds between two members and are not counted as in or out.
+type EntityTokenVolumeDTO struct {
+	TokenAddress            string `json:"token_address"`
+	TokenSymbol             string `json:"token_symbol"`
+	Decimals                int    `json:"decimals"`
+	TransfersIn             int64  `json:"transfers_in"`
+	TransfersOut            int64  `json:"transfers_out"`
+	InternalTransfers       int64  `json:"internal_transfers"`
+	VolumeIn                string `json:"volume_in"`
+	VolumeInFormatted       string `json:"volume_in_formatted"`
+	VolumeOut               string `json:"volume_out"`
+	VolumeOutFormatted      string `json:"volume_out_formatted"`
+	InternalVolume          string `json:"internal_volume"`
+	InternalVolumeFormatted string `json:"internal_volume_formatted"`
+	FirstActivityAt         string `json:"first_activity_at"`
+	LastActivityAt          string `json:"last_activity_at"`
+}
+
+// EntitySummaryDTO is an entity's activity across all its addresses
+type EntitySummaryDTO struct {
+	EntityID          int64                  `json:"entity_id"`
+	Name              string                 `json:"name"`
+	AddressCount      int                    `json:"address_count"`
+	TransfersIn       int64                  `json:"transfers_in"`
+	TransfersOut      int64                  `json:"transfers_out"`
+	InternalTransfers int64                  `json:"internal_transfers"`
+	FirstActivityAt   *string                `json:"first_activity_at,omitempty"`
+	LastActivityAt    *string                `json:"last_activity_at,omitempty"`
+	Tokens            []EntityTokenVolumeDTO `json:"tokens"`
+}
+
+// EntitySummaryResponse wraps an entity summary for API response
+type EntitySummaryResponse struct {
+	Data EntitySummaryDTO `json:"data"`
+}
+
+// CreateEntity creates an entity of the given category, empty for none, holding the given addresses
+func (s *EntityService) CreateEntity(ctx context.Context, name, category string, addresses []string) (*EntityResponse, error) {
+	addresses = normalizeAddresses(addresses)
+	if len(addresses) > MaxEntityAddresses {
+		return nil, ErrEntityFull
+	}
+
+	entity := &entities.Entity{
+		Name:      name,
+		Category:  category,
+		Addresses: addresses,
+	}
+	if err := s.entityRepo.Create(ctx, entity); err != nil {
+		return nil, fmt.Errorf("failed to create entity: %w", err)
+	}
+
+	return toEntityResponse(entity), nil
+}
+
+// GetEntity retrieves an entity, or nil if it does not exist
+func (s *EntityService) GetEntity(ctx context.Context, id int64) (*EntityResponse, error) {
+	entity, err := s.getEntity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return nil, nil
+	}
+
+	return toEntityResponse(entity), nil
+}
+
+// DeleteEntity removes an entity, reporting whether it existed
+func (s *EntityService) DeleteEntity(ctx context.Context, id int64) (bool, error) {
+	deleted, err := s.entityRepo.Delete(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete entity: %w", err)
+	}
+	return deleted, nil
+}
+
+// AddAddresses adds addresses to an entity and returns the updated entity,
+// or nil if it does not exist
+func (s *EntityService) AddAddresses(ctx context.Context, id int64, addresses []string) (*EntityResponse, error) {
+	entity, err := s.entityRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity: %w", err)
+	}
+	if entity == nil {
+		return nil, nil
+	}
+
+	var added []string
+	for _, addr := range normalizeAddresses(addresses) {
+		if !containsString(entity.Addresses, addr) {
+			added = append(added, addr)
+		}
+	}
+	if len(entity.Addresses)+len(added) > MaxEntityAddresses {
+		return nil, ErrEntityFull
+	}
+
+	if len(added) > 0 {
+		if err := s.entityRepo.AddAddresses(ctx, id, added); err != nil {
+			return nil, fmt.Errorf("failed to add entity addresses: %w", err)
+		}
+	}
+
+	return s.GetEntity(ctx, id)
+}
+
+// RemoveAddress removes an address from an entity and returns the updated entity,
+// or nil if it does not exist. Removing an address that is not a member is a no-op.
+func (s *EntityService) RemoveAddress(ctx context.Context, id int64, address string) (*EntityResponse, error) {
+	if _, err := s.entityRepo.RemoveAddress(ctx, id, strings.ToLower(address)); err != nil {
+		return nil, fmt.Errorf("failed to remove entity address: %w", err)
+	}
+
+	return s.GetEntity(ctx, id)
+}
+
+// GetEntityPortfolio retrieves the combined holdings of an entity's addresses,
+// or nil if the entity does not exist
+func (s *EntityService) GetEntityPortfolio(ctx context.Context, id int64) (*EntityPortfolioResponse, error) {
+	return guardedLoad(ctx, s.breaker, nil, s.logger, "entities.GetEntityPortfolio", "", func(ctx context.Context) (*EntityPortfolioResponse, error) {
+		return s.loadEntityPortfolio(ctx, id)
+	})
+}
+
+// loadEntityPortfolio reads the combined holdings of an entity's addresses from the database
+func (s *EntityService) loadEntityPortfolio(ctx context.Context, id int64) (*EntityPortfolioResponse, error) {
+	entity, err := s.entityRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity: %w", err)
+	}
+	if entity == nil {
+		return nil, nil
+	}
+
+	holdings := []entities.TokenHolding{}
+	if len(entity.Addresses) > 0 {
+		holdings, err = s.portfolioRepo.GetEntityHoldings(ctx, entity.Addresses)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get entity holdings: %w", err)
+		}
+		holdings = exposedOnly(ctx, s.exposure, holdings, func(h entities.TokenHolding) string { return h.TokenAddress })
+	}
+
+	dtos := make([]TokenHoldingDTO, len(holdings))
+	for i, h := range holdings {
+		dtos[i] = TokenHoldingDTO{
+			TokenAddress:     h.TokenAddress,
+			TokenName:        h.TokenName,
+			TokenSymbol:      h.TokenSymbol,
+			Decimals:         h.Decimals,
+			Balance:          h.BalanceStr,
+			BalanceFormatted: h.BalanceHuman,
+		}
+	}
+
+	return &EntityPortfolioResponse{
+		Data: EntityPortfolioDTO{
+			EntityID:     entity.ID,
+			Name:         entity.Name,
+			AddressCount: len(entity.Addresses),
+			Holdings:     dtos,
+		},
+	}, nil
+}
+
+// GetEntitySummary retrieves an entity's transfer counts and per-token volumes with transfers
+// between its own addresses counted separately, or nil if the entity does not exist
+func (s *EntityService) GetEntitySummary(ctx context.Context, id int64) (*EntitySummaryResponse, error) {
+	return guardedLoad(ctx, s.breaker, nil, s.logger, "entities.GetEntitySummary", "", func(ctx context.Context) (*EntitySummaryResponse, error) {
+		return s.loadEntitySummary(ctx, id)
+	})
+}
+
+// loadEntitySummary reads an entity's transfer counts and volumes from the database
+func (s *EntityService) loadEntitySummary(ctx context.Context, id int64) (*EntitySummaryResponse, error) {
+	entity, err := s.entityRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity: %w", err)
+	}
+	if entity == nil {
+		return nil, nil
+	}
+
+	volumes := []repositories.EntityTokenVolume{}
+	if len(entity.Addresses) > 0 {
+		volumes, err = s.portfolioRepo.GetEntityTokenVolumes(ctx, entity.Addresses)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get entity volumes: %w", err)
+		}
+		volumes = exposedOnly(ctx, s.exposure, volumes, func(v repositories.EntityTokenVolume) string { return v.TokenAddress })
+	}
+
+	summary := EntitySummaryDTO{
+		EntityID:     entity.ID,
+		Name:         entity.Name,
+		AddressCount: len(entity.Addresses),
+		Tokens:       make([]EntityTokenVolumeDTO, len(volumes)),
+	}
+
+	var first, last time.Time
+	for i, v := range volumes {
+		summary.TransfersIn += v.TransfersIn
+		summary.TransfersOut += v.TransfersOut
+		summary.InternalTransfers += v.InternalTransfers
+		if first.IsZero() || v.FirstActivityAt.Before(first) {
+			first = v.FirstActivityAt
+		}
+		if v.LastActivityAt.After(last) {
+			last = v.LastActivityAt
+		}
+
+		summary.Tokens[i] = EntityTokenVolumeDTO{
+			TokenAddress:            v.TokenAddress,
+			TokenSymbol:             v.TokenSymbol,
+			Decimals:                v.Decimals,
+			TransfersIn:             v.TransfersIn,
+			TransfersOut:            v.TransfersOut,
+			InternalTransfers:       v.InternalTransfers,
+			VolumeIn:                v.VolumeIn,
+			VolumeInFormatted:       units.Format(v.VolumeIn, v.Decimals),
+			VolumeOut:               v.VolumeOut,
+			VolumeOutFormatted:      units.Format(v.VolumeOut, v.Decimals),
+			InternalVolume:          v.InternalVolume,
+			InternalVolumeFormatted: units.Format(v.InternalVolume, v.Decimals),
+			FirstActivityAt:         v.FirstActivityAt.Format(time.RFC3339),
+			LastActivityAt:          v.LastActivityAt.Format(time.RFC3339),
+		}
+	}
+	if len(volumes) > 0 {
+		firstAt, lastAt := first.Format(time.RFC3339), last.Format(time.RFC3339)
+		summary.FirstActivityAt = &firstAt
+		summary.LastActivityAt = &lastAt
+	}
+
+	return &EntitySummaryResponse{Data: summary}, nil
+}
+
+// GetEntityTransfers retrieves transfers sent or received by any of an entity's addresses, newest first,
+// or nil if the entity does not exist. A transfer between two members appears once.
+func (s *EntityService) GetEntityTransfers(ctx context.Context, id int64, limit, offset int) (*TransferResponse, error) {
+	entity, err := s.getEntity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return nil, nil
+	}
+
+	// An empty Addresses filter would match every transfer
+	if len(entity.Addresses) == 0 {
+		return &TransferResponse{
+			Transfers: []TransferDTO{},
+			Limit:     limit,
+			Offset:    offset,
+		}, nil
+	}
+
+	filter := entities.DefaultTransferFilter()
+	filter.Addresses = entity.Addresses
+	filter.Limit = limit
+	filter.Offset = offset
+
+	return s.transferService.GetTransfers(ctx, filter)
+}
+
+// getEntity reads an entity through the breaker, nil if it does not exist
+func (s *EntityService) getEntity(ctx context.Context, id int64) (*entities.Entity, error) {
+	return guardedLoad(ctx, s.breaker, nil, s.logger, "entities.GetEntity", "", func(ctx context.Context) (*entities.Entity, error) {
+		entity, err := s.entityRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get entity: %w", err)
+		}
+		return entity, nil
+	})
+}
+
+func toEntityResponse(entity *entities.Entity) *EntityResponse {
+	addresses := entity.Addresses
+	if addresses == nil {
+		addresses = []string{}
+	}
+
+	return &EntityResponse{
+		Data: EntityDTO{
+			ID:        entity.ID,
+			Name:      entity.Name,
+			Category:  entity.Category,
+			Addresses: addresses,
+			CreatedAt: entity.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			UpdatedAt: entity.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		},
+	}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func newTestEntityService(transferRepo *testutil.MockTransferRepository, portfolioRepo *testutil.MockPortfolioRepository) (*EntityService, *testutil.MockEntityRepository) {
+	logger := zap.NewNop()
+	entityRepo := testutil.NewMockEntityRepository()
+	transferService := NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, logger)
+	return NewEntityService(entityRepo, portfolioRepo, transferService, logger), entityRepo
+}
+
+func TestEntityService_AddAddresses(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestEntityService(testutil.NewMockTransferRepository(), testutil.NewMockPortfolioRepository())
+
+	created, err := service.CreateEntity(ctx, "exchange", entities.EntityCategoryExchange, []string{testutil.AliceAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated, err := service.AddAddresses(ctx, created.Data.ID, []string{testutil.AliceAddress, testutil.BobAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{testutil.AliceAddress, testutil.BobAddress}
+	if fmt.Sprint(updated.Data.Addresses) != fmt.Sprint(want) {
+		t.Errorf("expected addresses %v, got %v", want, updated.Data.Addresses)
+	}
+
+	addresses := make([]string, MaxEntityAddresses)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("0x%040x", i+1)
+	}
+	if _, err := service.AddAddresses(ctx, created.Data.ID, addresses); !errors.Is(err, ErrEntityFull) {
+		t.Errorf("expected ErrEntityFull, got %v", err)
+	}
+
+	if missing, err := service.AddAddresses(ctx, 999, []string{testutil.BobAddress}); err != nil || missing != nil {
+		t.Errorf("expected nil for unknown entity, got %+v (%v)", missing, err)
+	}
+}
+
+func TestEntityService_GetEntityPortfolio(t *testing.T) {
+	ctx := context.Background()
+	portfolioRepo := testutil.NewMockPortfolioRepository()
+	portfolioRepo.GetEntityHoldingsFunc = func(ctx context.Context, addresses []string) ([]entities.TokenHolding, error) {
+		if len(addresses) != 2 {
+			t.Errorf("expected both members, got %v", addresses)
+		}
+		return []entities.TokenHolding{
+			{TokenAddress: testutil.USDTAddress, TokenSymbol: "USDT", Decimals: 6, BalanceStr: "2500000", BalanceHuman: "2.5"},
+		}, nil
+	}
+	service, _ := newTestEntityService(testutil.NewMockTransferRepository(), portfolioRepo)
+
+	created, _ := service.CreateEntity(ctx, "fund", "", []string{testutil.AliceAddress, testutil.BobAddress})
+
+	result, err := service.GetEntityPortfolio(ctx, created.Data.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Data.AddressCount != 2 || len(result.Data.Holdings) != 1 || result.Data.Holdings[0].BalanceFormatted != "2.5" {
+		t.Errorf("unexpected portfolio: %+v", result.Data)
+	}
+
+	// An entity without addresses holds nothing and does not query the repository
+	empty, _ := service.CreateEntity(ctx, "empty", "", nil)
+	portfolioRepo.Reset()
+	result, err = service.GetEntityPortfolio(ctx, empty.Data.ID)
+	if err != nil || len(result.Data.Holdings) != 0 || len(portfolioRepo.Calls) != 0 {
+		t.Errorf("expected empty portfolio without queries, got %+v (%v, %d calls)", result, err, len(portfolioRepo.Calls))
+	}
+}
+
+func TestEntityService_GetEntitySummary(t *testing.T) {
+	ctx := context.Background()
+	first := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2024, 2, 20, 12, 0, 0, 0, time.UTC)
+
+	portfolioRepo := testutil.NewMockPortfolioRepository()
+	portfolioRepo.GetEntityTokenVolumesFunc = func(ctx context.Context, addresses []string) ([]repositories.EntityTokenVolume, error) {
+		return []repositories.EntityTokenVolume{
+			{TokenAddress: testutil.USDTAddress, Decimals: 6, TransfersIn: 3, TransfersOut: 2, InternalTransfers: 4,
+				VolumeIn: "3000000", VolumeOut: "1000000", InternalVolume: "500000",
+				FirstActivityAt: first.AddDate(0, 0, 5), LastActivityAt: last},
+			{TokenAddress: testutil.USDCAddress, Decimals: 6, TransfersIn: 1,
+				VolumeIn: "1000000", VolumeOut: "0", InternalVolume: "0",
+				FirstActivityAt: first, LastActivityAt: first},
+		}, nil
+	}
+	service, _ := newTestEntityService(testutil.NewMockTransferRepository(), portfolioRepo)
+
+	created, _ := service.CreateEntity(ctx, "fund", "", []string{testutil.AliceAddress})
+
+	result, err := service.GetEntitySummary(ctx, created.Data.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := result.Data
+	if data.TransfersIn != 4 || data.TransfersOut != 2 || data.InternalTransfers != 4 {
+		t.Errorf("unexpected totals: %+v", data)
+	}
+	if *data.FirstActivityAt != "2024-01-10T00:00:00Z" || *data.LastActivityAt != "2024-02-20T12:00:00Z" {
+		t.Errorf("unexpected activity range: %s - %s", *data.FirstActivityAt, *data.LastActivityAt)
+	}
+	if data.Tokens[0].InternalVolumeFormatted != "0.5" || data.Tokens[0].VolumeInFormatted != "3" {
+		t.Errorf("unexpected token volumes: %+v", data.Tokens[0])
+	}
+
+	if missing, err := service.GetEntitySummary(ctx, 999); err != nil || missing != nil {
+		t.Errorf("expected nil for unknown entity, got %+v (%v)", missing, err)
+	}
+}
+
+func TestEntityService_GetEntityTransfers(t *testing.T) {
+	ctx := context.Background()
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.BobAddress)),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithFromAddress(testutil.CharlieAddr), testutil.WithToAddress(testutil.BobAddress)),
+	)
+	service, _ := newTestEntityService(transferRepo, testutil.NewMockPortfolioRepository())
+
+	created, _ := service.CreateEntity(ctx, "exchange", entities.EntityCategoryExchange, []string{testutil.AliceAddress, testutil.BobAddress})
+
+	// The internal Alice -> Bob transfer is listed once
+	result, err := service.GetEntityTransfers(ctx, created.Data.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Transfers) != 2 {
+		t.Errorf("expected 2 transfers, got %d", len(result.Transfers))
+	}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/pkg/units"
+)
+
+// ethDecimals is the number of decimals of native ETH amounts (wei)
+const ethDecimals = 18
+
+// EthTransferService provides business logic for native ETH transfer queries
+type EthTransferService struct {
+	ethTransferRepo repositories.EthTransferRepository
+	cache           *cache.RedisCache
+	logger          *zap.Logger
+}
+
+// NewEthTransferService creates a new ETH transfer service
+func NewEthTransferService(
+	ethTransferRepo repositories.EthTransferRepository,
+	cache *cache.RedisCache,
+	logger *zap.Logger,
+) *EthTransferService {
+	return &EthTransferService{
+		ethTransferRepo: ethTransferRepo,
+		cache:           cache,
+		logger:          logger,
+	}
+}
+
+// EthTransferResponse is the API response for ETH transfer queries
+type EthTransferResponse struct {
+	Transfers []EthTransferDTO `json:"transfers"`
+	Total     int64            `json:"total"`
+	Limit     int              `json:"limit"`
+	Offset    int              `json:"offset"`
+	HasMore   bool             `json:"has_more"`
+}
+
+// EthTransferDTO is the API representation of a native ETH transfer.
+// Internal transfers were made by a contract call rather than by the transaction itself.
+type EthTransferDTO struct {
+	TxHash         string `json:"tx_hash"`
+	TraceAddress   string `json:"trace_address"`
+	BlockNumber    int64  `json:"block_number"`
+	BlockTimestamp string `json:"block_timestamp"`
+	FromAddress    string `json:"from_address"`
+	ToAddress      string `json:"to_address"`
+	Value          string `json:"value"`
+	ValueFormatted string `json:"value_formatted"` // Value in ETH
+	CallType       string `json:"call_type"`
+	Internal       bool   `json:"internal"`
+}
+
+// GetEthTransfers retrieves ETH transfers based on filter, newest first
+func (s *EthTransferService) GetEthTransfers(ctx context.Context, filter entities.EthTransferFilter) (*EthTransferResponse, error) {
+	// Generate cache key
+	cacheKey := s.generateCacheKey(filter)
+
+	// Try cache first
+	var cached EthTransferResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	transfers, err := s.ethTransferRepo.GetByFilter(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eth transfers: %w", err)
+	}
+
+	total, err := s.ethTransferRepo.GetCount(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eth transfer count: %w", err)
+	}
+
+	dtos := make([]EthTransferDTO, len(transfers))
+	for i, t := range transfers {
+		dtos[i] = EthTransferDTO{
+			TxHash:         t.TxHash,
+			TraceAddress:   t.TraceAddress,
+			BlockNumber:    t.BlockNumber,
+			BlockTimestamp: t.BlockTimestamp.Format("2006-01-02T15:04:05Z"),
+			FromAddress:    t.FromAddress,
+			ToAddress:      t.ToAddress,
+			Value:          t.ValueString,
+			ValueFormatted: units.Format(t.ValueString, ethDecimals),
+			CallType:       t.CallType,
+			Internal:       t.IsInternal(),
+		}
+	}
+
+	response := &EthTransferResponse{
+		Transfers: dtos,
+		Total:     total,
+		Limit:     filter.Limit,
+		Offset:    filter.Offset,
+		HasMore:   int64(filter.Offset+len(transfers)) < total,
+	}
+
+	// Cache the response
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, cacheKey, response); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// generateCacheKey generates a unique cache key for the filter
+func (s *EthTransferService) generateCacheKey(filter entities.EthTransferFilter) string {
+	parts := []string{"eth_transfers"}
+
+	if filter.Address != nil {
+		parts = append(parts, "addr:"+*filter.Address)
+	}
+	if filter.FromAddress != nil {
+		parts = append(parts, "from:"+*filter.FromAddress)
+	}
+	if filter.ToAddress != nil {
+		parts = append(parts, "to:"+*filter.ToAddress)
+	}
+	parts = append(parts, fmt.Sprintf("l:%d", filter.Limit), fmt.Sprintf("o:%d", filter.Offset))
+
+	return strings.Join(parts, ":")
+}
+
+// GetEthTransfersByAddress retrieves ETH transfers sent or received by an address
+func (s *EthTransferService) GetEthTransfersByAddress(ctx context.Context, address string, limit, offset int) (*EthTransferResponse, error) {
+	address = strings.ToLower(address)
+	return s.GetEthTransfers(ctx, entities.EthTransferFilter{
+		Address: &address,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestEthTransferService_GetEthTransfersByAddress(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("returns transfers touching the address", func(t *testing.T) {
+		repo := testutil.NewMockEthTransferRepository()
+		repo.AddEthTransfers(
+			entities.EthTransfer{
+				TxHash:         "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				BlockNumber:    19000000,
+				BlockTimestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+				FromAddress:    testutil.AliceAddress,
+				ToAddress:      testutil.BobAddress,
+				ValueString:    "1500000000000000000",
+				CallType:       entities.EthCallTypeCall,
+			},
+			entities.EthTransfer{
+				TxHash:       "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				TraceAddress: "0-1",
+				FromAddress:  testutil.BobAddress,
+				ToAddress:    testutil.CharlieAddr,
+				ValueString:  "1",
+				CallType:     entities.EthCallTypeCall,
+			},
+		)
+		service := NewEthTransferService(repo, nil, logger)
+
+		result, err := service.GetEthTransfersByAddress(ctx, testutil.AliceAddress, 10, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Transfers) != 1 || result.Total != 1 || result.HasMore {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+
+		got := result.Transfers[0]
+		if got.ValueFormatted != "1.5" {
+			t.Errorf("expected value_formatted 1.5, got %s", got.ValueFormatted)
+		}
+		if got.Internal {
+			t.Error("expected top-level transfer not to be internal")
+		}
+		if got.BlockTimestamp != "2024-01-15T10:30:00Z" {
+			t.Errorf("unexpected block timestamp %s", got.BlockTimestamp)
+		}
+
+		result, err = service.GetEthTransfersByAddress(ctx, testutil.CharlieAddr, 10, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Transfers) != 1 || !result.Transfers[0].Internal || result.Transfers[0].TraceAddress != "0-1" {
+			t.Errorf("expected the internal transfer, got %+v", result.Transfers)
+		}
+	})
+
+	t.Run("propagates repository errors", func(t *testing.T) {
+		repo := testutil.NewMockEthTransferRepository()
+		repo.GetByFilterFunc = func(ctx context.Context, filter entities.EthTransferFilter) ([]entities.EthTransfer, error) {
+			return nil, errors.New("db down")
+		}
+		service := NewEthTransferService(repo, nil, logger)
+
+		if _, err := service.GetEthTransfersByAddress(ctx, testutil.AliceAddress, 10, 0); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
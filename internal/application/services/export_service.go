@@ -0,0 +1,368 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/archive"
+)
+
+// exportPageSize is the number of transfers read per query while exporting a wallet
+const exportPageSize = 10000
+
+// MaxQueuedExports is the number of exports that may wait for or hold a worker at once
+const MaxQueuedExports = 100
+
+// Export job states
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportDone    = "done"
+	ExportFailed  = "failed"
+)
+
+// Export file formats
+const (
+	ExportCSV  = "csv"
+	ExportJSON = "json"
+)
+
+// ErrExportQueueFull is returned by StartExport while MaxQueuedExports exports are queued or running
+var ErrExportQueueFull = errors.New("too many exports in progress")
+
+// ExportJob is the API representation of a wallet transfer export. It is stored as JSON next to
+// the ZIP file it produces, so any API instance sharing the object store can report on it.
+type ExportJob struct {
+	ID          string     `json:"id"`
+	Address     string     `json:"address"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	Rows        int64      `json:"rows"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// exportRow is a transfer as written to JSON exports
+type exportRow struct {
+	TxHash         string    `json:"tx_hash"`
+	LogIndex       int       `json:"log_index"`
+	BlockNumber    int64     `json:"block_number"`
+	BlockTimestamp time.Time `json:"block_timestamp"`
+	TokenAddress   string    `json:"token_address"`
+	FromAddress    string    `json:"from_address"`
+	ToAddress      string    `json:"to_address"`
+	Value          string    `json:"value"`
+}
+
+// exportColumns are the CSV header, in the order of exportRow
+var exportColumns = []string{"tx_hash", "log_index", "block_number", "block_timestamp", "token_address", "from_address", "to_address", "value"}
+
+// ExportService writes a wallet's full transfer history to object storage as a ZIP file in the
+// background. Exports run in the API process on a fixed number of workers; one interrupted by a
+// restart is left failed rather than resumed.
+type ExportService struct {
+	transferRepo repositories.TransferRepository
+	store        archive.ObjectStore
+	workers      chan struct{}
+	queued       chan struct{}
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	exposure     *TokenExposure
+	logger       *zap.Logger
+}
+
+// NewExportService creates an export service running at most workers exports at once
+func NewExportService(transferRepo repositories.TransferRepository, store archive.ObjectStore, workers int, logger *zap.Logger) *ExportService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ExportService{
+		transferRepo: transferRepo,
+		store:        store,
+		workers:      make(chan struct{}, max(workers, 1)),
+		queued:       make(chan struct{}, MaxQueuedExports),
+		ctx:          ctx,
+		cancel:       cancel,
+		logger:       logger,
+	}
+}
+
+// WithExposure leaves the tokens e does not expose out of exports. Exports run apart from the
+// request that started them, so they never include hidden tokens, even for the admin token.
+func (s *ExportService) WithExposure(e *TokenExposure) *ExportService {
+	s.exposure = e
+	return s
+}
+
+// Close stops running exports, marking them failed, and waits for them to return
+func (s *ExportService) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// StartExport queues an export of every transfer to or from address and returns the pending job
+func (s *ExportService) StartExport(ctx context.Context, address, format string) (*ExportJob, error) {
+	if format != ExportCSV && format != ExportJSON {
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+
+	select {
+	case s.queued <- struct{}{}:
+	default:
+		return nil, ErrExportQueueFull
+	}
+
+	id, err := newExportID()
+	if err != nil {
+		<-s.queued
+		return nil, err
+	}
+	job := &ExportJob{
+		ID:        id,
+		Address:   strings.ToLower(address),
+		Format:    format,
+		Status:    ExportPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.saveJob(ctx, job); err != nil {
+		<-s.queued
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go func(job ExportJob) {
+		defer s.wg.Done()
+		defer func() { <-s.queued }()
+		s.run(&job)
+	}(*job)
+
+	return job, nil
+}
+
+// GetExport returns an address's export job, or nil if there is none with that ID
+func (s *ExportService) GetExport(ctx context.Context, address, id string) (*ExportJob, error) {
+	data, err := s.store.Get(ctx, exportJobKey(address, id))
+	if errors.Is(err, archive.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export job: %w", err)
+	}
+
+	var job ExportJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("failed to decode export job: %w", err)
+	}
+	return &job, nil
+}
+
+// GetExportFile returns the ZIP file of a finished export
+func (s *ExportService) GetExportFile(ctx context.Context, job *ExportJob) ([]byte, error) {
+	data, err := s.store.Get(ctx, exportFileKey(job.Address, job.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export file: %w", err)
+	}
+	return data, nil
+}
+
+// ExportFileName is the name a finished export is downloaded as
+func ExportFileName(job *ExportJob) string {
+	return fmt.Sprintf("%s-transfers-%s.zip", job.Address, job.ID)
+}
+
+// run waits for a worker, writes the export and records its outcome
+func (s *ExportService) run(job *ExportJob) {
+	select {
+	case s.workers <- struct{}{}:
+		defer func() { <-s.workers }()
+	case <-s.ctx.Done():
+		s.finish(job, 0, s.ctx.Err())
+		return
+	}
+
+	job.Status = ExportRunning
+	if err := s.saveJob(s.ctx, job); err != nil {
+		s.logger.Warn("Failed to save export job", zap.String("id", job.ID), zap.Error(err))
+	}
+
+	rows, err := s.export(s.ctx, job)
+	s.finish(job, rows, err)
+}
+
+// finish records a job's outcome. The status is saved even while the service is closing.
+func (s *ExportService) finish(job *ExportJob, rows int64, err error) {
+	now := time.Now().UTC()
+	job.CompletedAt = &now
+	job.Rows = rows
+	job.Status = ExportDone
+	if err != nil {
+		job.Status = ExportFailed
+		job.Error = err.Error()
+		s.logger.Warn("Export failed", zap.String("id", job.ID), zap.String("address", job.Address), zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.saveJob(ctx, job); err != nil {
+		s.logger.Error("Failed to save export job", zap.String("id", job.ID), zap.Error(err))
+	}
+}
+
+// export writes the address's transfers, newest first, to the job's ZIP file and returns the row count
+func (s *ExportService) export(ctx context.Context, job *ExportJob) (int64, error) {
+	var buf bytes.Buffer
+	archiveWriter := zip.NewWriter(&buf)
+	entry, err := archiveWriter.Create(fmt.Sprintf("%s-transfers.%s", job.Address, job.Format))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	rows, err := s.writeTransfers(ctx, entry, job.Address, job.Format)
+	if err != nil {
+		return 0, err
+	}
+	if err := archiveWriter.Close(); err != nil {
+		return 0, fmt.Errorf("failed to finish export file: %w", err)
+	}
+
+	if err := s.store.Put(ctx, exportFileKey(job.Address, job.ID), buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
+
+// writeTransfers pages through the address's transfers by cursor and encodes them to w
+func (s *ExportService) writeTransfers(ctx context.Context, w io.Writer, address, format string) (int64, error) {
+	csvWriter := csv.NewWriter(w)
+	if format == ExportCSV {
+		if err := csvWriter.Write(exportColumns); err != nil {
+			return 0, err
+		}
+	} else if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	filter := entities.TransferFilter{Address: &address, Limit: exportPageSize}
+	s.exposure.scopeTransfers(ctx, &filter)
+	for {
+		page, err := s.transferRepo.GetByFilter(ctx, filter)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read transfers: %w", err)
+		}
+
+		for _, t := range page {
+			if format == ExportCSV {
+				err = csvWriter.Write([]string{
+					t.TxHash,
+					strconv.Itoa(t.LogIndex),
+					strconv.FormatInt(t.BlockNumber, 10),
+					t.BlockTimestamp.UTC().Format(time.RFC3339),
+					t.TokenAddress,
+					t.FromAddress,
+					t.ToAddress,
+					t.ValueString,
+				})
+			} else {
+				err = writeJSONRow(w, rows, t)
+			}
+			if err != nil {
+				return 0, fmt.Errorf("failed to write transfer: %w", err)
+			}
+			rows++
+		}
+
+		if len(page) < exportPageSize {
+			break
+		}
+		cursor := entities.CursorAt(page[len(page)-1])
+		filter.After = &cursor
+	}
+
+	if format == ExportCSV {
+		csvWriter.Flush()
+		return rows, csvWriter.Error()
+	}
+	_, err := io.WriteString(w, "]\n")
+	return rows, err
+}
+
+// writeJSONRow writes one element of the JSON array, preceded by a separator unless it is the first
+func writeJSONRow(w io.Writer, index int64, t entities.Transfer) error {
+	data, err := json.Marshal(exportRow{
+		TxHash:         t.TxHash,
+		LogIndex:       t.LogIndex,
+		BlockNumber:    t.BlockNumber,
+		BlockTimestamp: t.BlockTimestamp.UTC(),
+		TokenAddress:   t.TokenAddress,
+		FromAddress:    t.FromAddress,
+		ToAddress:      t.ToAddress,
+		Value:          t.ValueString,
+	})
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if index == 0 {
+		separator = "\n"
+	}
+	if _, err := io.WriteString(w, separator); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// saveJob writes a job's state to the object store
+func (s *ExportService) saveJob(ctx context.Context, job *ExportJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode export job: %w", err)
+	}
+	if err := s.store.Put(ctx, exportJobKey(job.Address, job.ID), data); err != nil {
+		return fmt.Errorf("failed to save export job: %w", err)
+	}
+	return nil
+}
+
+// newExportID returns a random 32 hex digit job ID
+func newExportID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate export ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// IsExportID reports whether id has the form of an export job ID
+func IsExportID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil && strings.ToLower(id) == id
+}
+
+func exportJobKey(address, id string) string {
+	return "exports/" + strings.ToLower(address) + "/" + id + ".json"
+}
+
+func exportFileKey(address, id string) string {
+	return "exports/" + strings.ToLower(address) + "/" + id + ".zip"
+}
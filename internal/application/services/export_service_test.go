@@ -0,0 +1,131 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/archive"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+// waitForExport polls an export job until it leaves the pending and running states
+func waitForExport(t *testing.T, service *ExportService, address, id string) *ExportJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := service.GetExport(context.Background(), address, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job != nil && job.Status != ExportPending && job.Status != ExportRunning {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("export %s did not finish", id)
+	return nil
+}
+
+// readExport returns the single file inside an export's ZIP
+func readExport(t *testing.T, service *ExportService, job *ExportJob) string {
+	t.Helper()
+	data, err := service.GetExportFile(context.Background(), job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil || len(zr.File) != 1 {
+		t.Fatalf("expected a ZIP with one file, got %v (%v)", zr, err)
+	}
+	f, err := zr.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func setupExportServiceTest(t *testing.T) *ExportService {
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithLogIndex(1), testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.BobAddress)),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(0), testutil.WithFromAddress(testutil.BobAddress), testutil.WithToAddress(testutil.CharlieAddr)),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(2), testutil.WithFromAddress(testutil.CharlieAddr), testutil.WithToAddress(testutil.AliceAddress)),
+	)
+
+	service := NewExportService(transferRepo, archive.NewFileStore(t.TempDir()), 1, zap.NewNop())
+	t.Cleanup(service.Close)
+	return service
+}
+
+func TestExportService_CSV(t *testing.T) {
+	service := setupExportServiceTest(t)
+
+	job, err := service.StartExport(context.Background(), strings.ToUpper(testutil.BobAddress[:2])+testutil.BobAddress[2:], ExportCSV)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Status != ExportPending || job.Address != testutil.BobAddress || !IsExportID(job.ID) {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+
+	job = waitForExport(t, service, testutil.BobAddress, job.ID)
+	if job.Status != ExportDone || job.Rows != 2 || job.CompletedAt == nil {
+		t.Fatalf("expected a finished export of 2 rows, got %+v", job)
+	}
+
+	lines := strings.Split(strings.TrimSpace(readExport(t, service, job)), "\n")
+	if len(lines) != 3 || lines[0] != strings.Join(exportColumns, ",") {
+		t.Fatalf("expected a header and 2 rows, got %q", lines)
+	}
+	if !strings.Contains(lines[1], testutil.BobAddress) || !strings.Contains(lines[2], testutil.BobAddress) {
+		t.Errorf("expected only Bob's transfers, got %q", lines[1:])
+	}
+}
+
+func TestExportService_JSON(t *testing.T) {
+	service := setupExportServiceTest(t)
+
+	job, err := service.StartExport(context.Background(), testutil.AliceAddress, ExportJSON)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	job = waitForExport(t, service, testutil.AliceAddress, job.ID)
+
+	var rows []exportRow
+	if err := json.Unmarshal([]byte(readExport(t, service, job)), &rows); err != nil {
+		t.Fatalf("export is not a JSON array: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Value == "" || rows[0].TxHash == "" {
+		t.Errorf("expected Alice's 2 transfers, got %+v", rows)
+	}
+}
+
+func TestExportService_RejectsUnknownFormat(t *testing.T) {
+	service := setupExportServiceTest(t)
+
+	if _, err := service.StartExport(context.Background(), testutil.AliceAddress, "xlsx"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestExportService_GetExportMissing(t *testing.T) {
+	service := setupExportServiceTest(t)
+
+	job, err := service.GetExport(context.Background(), testutil.AliceAddress, strings.Repeat("ab", 16))
+	if err != nil || job != nil {
+		t.Errorf("expected no job, got %+v (%v)", job, err)
+	}
+}
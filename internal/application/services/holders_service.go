@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 
@@ -12,11 +14,48 @@ import (
 	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
 )
 
+// ErrNoHolderSnapshot is returned when holder changes are requested before the indexer has taken a snapshot
+var ErrNoHolderSnapshot = errors.New("no holder snapshot available")
+
+// MaxHolderChangesWindow is the furthest back holder changes can be requested
+const MaxHolderChangesWindow = 30 * 24 * time.Hour
+
+// MaxHolderHistoryLimit is the largest holder history page; pages are streamed, so it can be large
+const MaxHolderHistoryLimit = 10000
+
+// DefaultHoldersCacheTTL is how long holder rankings are cached unless configured otherwise
+const DefaultHoldersCacheTTL = 5 * time.Minute
+
+// HolderExclusions lists addresses left out of holder rankings and counts, such as burn addresses
+// whose balance is not held by anyone
+type HolderExclusions struct {
+	Addresses    []string // Lowercase
+	ExcludeToken bool     // Also leave out each token's own contract
+}
+
+// forToken returns the addresses excluded from tokenAddress's holders
+func (e HolderExclusions) forToken(tokenAddress string) []string {
+	exclude := append([]string(nil), e.Addresses...)
+	if e.ExcludeToken {
+		exclude = append(exclude, tokenAddress)
+	}
+	return exclude
+}
+
 // HoldersService provides business logic for token holders
 type HoldersService struct {
 	transferRepo repositories.TransferRepository
 	tokenRepo    repositories.TokenRepository
+	analytics    repositories.AnalyticsRepository
+	snapshots    repositories.HolderSnapshotRepository
+	snapshotSize int
+	holderCounts repositories.HolderCountRepository
+	exclusions   HolderExclusions
+	defaultLimit int
+	maxLimit     int
+	breaker      *Breaker
 	cache        *cache.RedisCache
+	cacheTTL     time.Duration
 	logger       *zap.Logger
 }
 
@@ -30,16 +69,66 @@ func NewHoldersService(
 	return &HoldersService{
 		transferRepo: transferRepo,
 		tokenRepo:    tokenRepo,
+		defaultLimit: 100,
+		maxLimit:     1000,
 		cache:        cache,
+		cacheTTL:     DefaultHoldersCacheTTL,
 		logger:       logger,
 	}
 }
 
+// WithCacheTTL sets how long holder rankings and their totals are cached
+func (s *HoldersService) WithCacheTTL(ttl time.Duration) *HoldersService {
+	s.cacheTTL = ttl
+	return s
+}
+
+// CacheTTL returns how long holder rankings and their totals are cached
+func (s *HoldersService) CacheTTL() time.Duration {
+	return s.cacheTTL
+}
+
+// WithAnalytics reads holder rankings and counts from the analytics store,
+// falling back to PostgreSQL when it fails
+func (s *HoldersService) WithAnalytics(repo repositories.AnalyticsRepository) *HoldersService {
+	s.analytics = repo
+	return s
+}
+
+// WithSnapshots enables holder snapshots of each token's top size holders, used to report holder changes
+func (s *HoldersService) WithSnapshots(repo repositories.HolderSnapshotRepository, size int) *HoldersService {
+	s.snapshots = repo
+	s.snapshotSize = size
+	return s
+}
+
+// WithHolderExclusions leaves the given addresses out of holder rankings, counts and changes
+// unless a request asks to include them
+func (s *HoldersService) WithHolderExclusions(exclusions HolderExclusions) *HoldersService {
+	s.exclusions = exclusions
+	return s
+}
+
+// WithPageSizes sets the number of top holders returned when no limit is given and the most returned at once
+func (s *HoldersService) WithPageSizes(defaultLimit, maxLimit int) *HoldersService {
+	s.defaultLimit = defaultLimit
+	s.maxLimit = maxLimit
+	return s
+}
+
+// WithBreaker retries top holder and holder balance reads through b and serves stale cached
+// responses while the database is unavailable
+func (s *HoldersService) WithBreaker(b *Breaker) *HoldersService {
+	s.breaker = b
+	return s
+}
+
 // HolderDTO is the API representation of a holder's balance
 type HolderDTO struct {
-	Address string `json:"address"`
-	Balance string `json:"balance"`
-	Rank    int    `json:"rank"`
+	Address    string `json:"address"`
+	Balance    string `json:"balance"`
+	Rank       int    `json:"rank"`
+	Percentage string `json:"percentage,omitempty"` // Share of circulating supply, see AddPercentages
 }
 
 // PaginationMetadata contains pagination information
@@ -61,16 +150,94 @@ type HolderBalanceResponse struct {
 	Data HolderDTO `json:"data"`
 }
 
-// GetTopHolders retrieves top token holders sorted by balance with pagination
-func (s *HoldersService) GetTopHolders(ctx context.Context, tokenAddress string, limit, offset int) (*TopHoldersResponse, error) {
+// BalanceChangeDTO is the API representation of an address's net balance change
+type BalanceChangeDTO struct {
+	Address string `json:"address"`
+	Change  string `json:"change"`
+}
+
+// HolderChangesDTO describes how a token's holders changed since a snapshot
+type HolderChangesDTO struct {
+	TokenAddress   string             `json:"token_address"`
+	SnapshotAt     time.Time          `json:"snapshot_at"`
+	TopN           int                `json:"top_n"`
+	Entered        []HolderDTO        `json:"entered"`         // in the current top N but not the snapshot's, with current balance
+	Exited         []HolderDTO        `json:"exited"`          // in the snapshot's top N but not the current one, with snapshot balance
+	LargestChanges []BalanceChangeDTO `json:"largest_changes"` // net change since the snapshot, by absolute size
+}
+
+// HolderChangesResponse is the API response for holder changes queries
+type HolderChangesResponse struct {
+	Data HolderChangesDTO `json:"data"`
+}
+
+// HolderRankChangeDTO compares a holder's rank and balance in two snapshots
+type HolderRankChangeDTO struct {
+	Address      string `json:"address"`
+	FromRank     int    `json:"from_rank"`
+	ToRank       int    `json:"to_rank"`
+	RankChange   int    `json:"rank_change"` // positive when the holder moved up
+	FromBalance  string `json:"from_balance"`
+	ToBalance    string `json:"to_balance"`
+	BalanceDelta string `json:"balance_delta"`
+}
+
+// HolderComparisonDTO compares a token's top holders in two snapshots
+type HolderComparisonDTO struct {
+	TokenAddress   string                `json:"token_address"`
+	FromSnapshotAt time.Time             `json:"from_snapshot_at"`
+	ToSnapshotAt   time.Time             `json:"to_snapshot_at"`
+	Changes        []HolderRankChangeDTO `json:"changes"` // in both snapshots' top N, by rank in the later one
+	Entered        []HolderDTO           `json:"entered"` // in the later top N but not the earlier one, with its later balance
+	Exited         []HolderDTO           `json:"exited"`  // in the earlier top N but not the later one, with its earlier balance
+}
+
+// HolderComparisonResponse is the API response for holder snapshot comparisons
+type HolderComparisonResponse struct {
+	Data HolderComparisonDTO `json:"data"`
+}
+
+// NegativeBalancesDTO reports a token's negative balances, each a holder with transfers missing
+type NegativeBalancesDTO struct {
+	TokenAddress string      `json:"token_address"`
+	Count        int64       `json:"count"`
+	Worst        []HolderDTO `json:"worst"` // most negative first
+}
+
+// NegativeBalancesResponse is the API response for negative balance diagnostics
+type NegativeBalancesResponse struct {
+	Data []NegativeBalancesDTO `json:"data"`
+}
+
+// HolderHistoryEntryDTO is the API representation of one transfer in a holder's ledger
+type HolderHistoryEntryDTO struct {
+	BlockNumber    int64  `json:"block_number"`
+	BlockTimestamp string `json:"block_timestamp"`
+	TxHash         string `json:"tx_hash"`
+	LogIndex       int    `json:"log_index"`
+	Counterparty   string `json:"counterparty"`
+	Change         string `json:"change"`  // signed raw amount
+	Balance        string `json:"balance"` // raw balance after this transfer
+}
+
+// HolderHistoryPagination describes a streamed holder history page
+type HolderHistoryPagination struct {
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"has_more"`
+}
+
+// GetTopHolders retrieves top token holders sorted by balance with pagination.
+// includeExcluded ranks the excluded addresses like any other holder.
+func (s *HoldersService) GetTopHolders(ctx context.Context, tokenAddress string, limit, offset int, includeExcluded bool) (*TopHoldersResponse, error) {
 	tokenAddress = strings.ToLower(tokenAddress)
 
 	// Validate limit
 	if limit <= 0 {
-		limit = 100
+		limit = s.defaultLimit
 	}
-	if limit > 1000 {
-		limit = 1000
+	if limit > s.maxLimit {
+		limit = s.maxLimit
 	}
 
 	// Validate offset
@@ -78,8 +245,13 @@ func (s *HoldersService) GetTopHolders(ctx context.Context, tokenAddress string,
 		offset = 0
 	}
 
+	var exclude []string
+	if !includeExcluded {
+		exclude = s.exclusions.forToken(tokenAddress)
+	}
+
 	// Generate cache key with offset
-	cacheKey := fmt.Sprintf("holders:%s:%d:%d", tokenAddress, limit, offset)
+	cacheKey := fmt.Sprintf("holders:%s:%d:%d%s", tokenAddress, limit, offset, exclusionCacheSuffix(includeExcluded))
 
 	// Try cache first
 	var cached TopHoldersResponse
@@ -90,6 +262,31 @@ func (s *HoldersService) GetTopHolders(ctx context.Context, tokenAddress string,
 		}
 	}
 
+	return guardedLoad(ctx, s.breaker, s.cache, s.logger, "holders.GetTopHolders", cacheKey, func(ctx context.Context) (*TopHoldersResponse, error) {
+		return s.loadTopHolders(ctx, tokenAddress, limit, offset, includeExcluded, exclude, cacheKey)
+	})
+}
+
+// warmTopHolders reloads the first page of a token's top holders, at the default page size and
+// without excluded holders, into the cache along with a fresh holder count
+func (s *HoldersService) warmTopHolders(ctx context.Context, tokenAddress string) error {
+	limit := min(s.defaultLimit, s.maxLimit)
+	cacheKey := fmt.Sprintf("holders:%s:%d:%d%s", tokenAddress, limit, 0, exclusionCacheSuffix(false))
+
+	// loadTopHolders reuses a cached holder count
+	if err := s.cache.Delete(ctx, fmt.Sprintf("holders_count:%s%s", tokenAddress, exclusionCacheSuffix(false))); err != nil {
+		return err
+	}
+
+	exclude := s.exclusions.forToken(tokenAddress)
+	_, err := guardedLoad(ctx, s.breaker, s.cache, s.logger, "holders.GetTopHolders", cacheKey, func(ctx context.Context) (*TopHoldersResponse, error) {
+		return s.loadTopHolders(ctx, tokenAddress, limit, 0, false, exclude, cacheKey)
+	})
+	return err
+}
+
+// loadTopHolders reads a top holders page from the database and caches it under cacheKey
+func (s *HoldersService) loadTopHolders(ctx context.Context, tokenAddress string, limit, offset int, includeExcluded bool, exclude []string, cacheKey string) (*TopHoldersResponse, error) {
 	// Check if token exists
 	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
 	if err != nil {
@@ -101,31 +298,40 @@ func (s *HoldersService) GetTopHolders(ctx context.Context, tokenAddress string,
 
 	// Get total holder count (with separate cache key)
 	var total int64
-	countCacheKey := fmt.Sprintf("holders_count:%s", tokenAddress)
+	countCacheKey := fmt.Sprintf("holders_count:%s%s", tokenAddress, exclusionCacheSuffix(includeExcluded))
 	if s.cache != nil {
 		if cacheErr := s.cache.Get(ctx, countCacheKey, &total); cacheErr != nil {
 			// Cache miss, fetch from database
 			var countErr error
-			total, countErr = s.transferRepo.GetHolderCount(ctx, tokenAddress)
+			total, countErr = holderCount(ctx, s.analytics, s.transferRepo, tokenAddress, exclude, s.logger)
 			if countErr != nil {
-				return nil, fmt.Errorf("failed to get holder count: %w", countErr)
+				return nil, countErr
 			}
-			// Cache the count with 5 min TTL
-			if setErr := s.cache.SetWithTTL(ctx, countCacheKey, total, 5*time.Minute); setErr != nil {
+			// Cache the count for the holders TTL
+			if setErr := s.cache.SetWithTTL(ctx, countCacheKey, total, s.cacheTTL); setErr != nil {
 				s.logger.Warn("Failed to cache holder count", zap.Error(setErr))
 			}
 		}
 	} else {
-		total, err = s.transferRepo.GetHolderCount(ctx, tokenAddress)
+		total, err = holderCount(ctx, s.analytics, s.transferRepo, tokenAddress, exclude, s.logger)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get holder count: %w", err)
+			return nil, err
 		}
 	}
 
-	// Get top holders with offset from database
-	holders, err := s.transferRepo.GetTopHoldersWithOffset(ctx, tokenAddress, limit, offset)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get top holders: %w", err)
+	// Get top holders with offset from the analytics store, or the database
+	var holders []repositories.HolderBalance
+	if s.analytics != nil {
+		holders, err = s.analytics.GetTopHolders(ctx, tokenAddress, limit, offset, exclude)
+		if err != nil {
+			s.logger.Warn("Analytics store query failed, falling back to database", zap.Error(err))
+		}
+	}
+	if s.analytics == nil || err != nil {
+		holders, err = s.transferRepo.GetTopHoldersWithOffset(ctx, tokenAddress, limit, offset, exclude)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get top holders: %w", err)
+		}
 	}
 
 	// Build response
@@ -151,9 +357,9 @@ func (s *HoldersService) GetTopHolders(ctx context.Context, tokenAddress string,
 		},
 	}
 
-	// Cache the response (5 minutes TTL for holders)
+	// Cache the response for the holders TTL
 	if s.cache != nil {
-		if err := s.cache.SetWithTTL(ctx, cacheKey, response, 5*time.Minute); err != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, s.cacheTTL); err != nil {
 			s.logger.Warn("Failed to cache response", zap.Error(err))
 		}
 	}
@@ -161,6 +367,39 @@ func (s *HoldersService) GetTopHolders(ctx context.Context, tokenAddress string,
 	return response, nil
 }
 
+// exclusionCacheSuffix keeps responses including excluded holders apart from the default ones
+func exclusionCacheSuffix(includeExcluded bool) string {
+	if includeExcluded {
+		return ":all"
+	}
+	return ""
+}
+
+// holderCount returns a token's holder count, leaving out exclude, from the analytics store when
+// configured, falling back to the transfers table when it is not or the query fails
+func holderCount(
+	ctx context.Context,
+	analytics repositories.AnalyticsRepository,
+	transferRepo repositories.TransferRepository,
+	tokenAddress string,
+	exclude []string,
+	logger *zap.Logger,
+) (int64, error) {
+	if analytics != nil {
+		count, err := analytics.GetHolderCount(ctx, tokenAddress, exclude)
+		if err == nil {
+			return count, nil
+		}
+		logger.Warn("Analytics store query failed, falling back to database", zap.Error(err))
+	}
+
+	count, err := transferRepo.GetHolderCount(ctx, tokenAddress, exclude)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get holder count: %w", err)
+	}
+	return count, nil
+}
+
 // GetHolderBalance retrieves balance for a specific holder
 func (s *HoldersService) GetHolderBalance(ctx context.Context, tokenAddress, holderAddress string) (*HolderBalanceResponse, error) {
 	tokenAddress = strings.ToLower(tokenAddress)
@@ -178,6 +417,13 @@ func (s *HoldersService) GetHolderBalance(ctx context.Context, tokenAddress, hol
 		}
 	}
 
+	return guardedLoad(ctx, s.breaker, s.cache, s.logger, "holders.GetHolderBalance", cacheKey, func(ctx context.Context) (*HolderBalanceResponse, error) {
+		return s.loadHolderBalance(ctx, tokenAddress, holderAddress, cacheKey)
+	})
+}
+
+// loadHolderBalance reads a holder's balance from the database and caches it under cacheKey
+func (s *HoldersService) loadHolderBalance(ctx context.Context, tokenAddress, holderAddress, cacheKey string) (*HolderBalanceResponse, error) {
 	// Check if token exists
 	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
 	if err != nil {
@@ -210,3 +456,465 @@ func (s *HoldersService) GetHolderBalance(ctx context.Context, tokenAddress, hol
 
 	return response, nil
 }
+
+// AddPercentages fills each holder's percentage of the token's circulating supply, tracked as indexed
+// mints minus burns. Percentages are left out when the supply is unavailable or not positive.
+func (s *HoldersService) AddPercentages(ctx context.Context, tokenAddress string, response *TopHoldersResponse) {
+	if response == nil {
+		return
+	}
+	supply, ok := s.circulatingSupply(ctx, strings.ToLower(tokenAddress))
+	if !ok {
+		return
+	}
+	for i := range response.Data {
+		response.Data[i].Percentage = supplyPercentage(response.Data[i].Balance, supply)
+	}
+}
+
+// AddBalancePercentage fills a single holder's percentage of the token's circulating supply
+func (s *HoldersService) AddBalancePercentage(ctx context.Context, tokenAddress string, response *HolderBalanceResponse) {
+	if response == nil {
+		return
+	}
+	supply, ok := s.circulatingSupply(ctx, strings.ToLower(tokenAddress))
+	if !ok {
+		return
+	}
+	response.Data.Percentage = supplyPercentage(response.Data.Balance, supply)
+}
+
+// circulatingSupply returns a token's circulating supply, cached for 5 minutes
+func (s *HoldersService) circulatingSupply(ctx context.Context, tokenAddress string) (*big.Int, bool) {
+	cacheKey := fmt.Sprintf("supply:%s", tokenAddress)
+
+	var raw string
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &raw); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+		}
+	}
+
+	if raw == "" {
+		var err error
+		raw, err = s.transferRepo.GetCirculatingSupply(ctx, tokenAddress)
+		if err != nil {
+			s.logger.Warn("Failed to get circulating supply", zap.Error(err), zap.String("token", tokenAddress))
+			return nil, false
+		}
+		if s.cache != nil {
+			if err := s.cache.SetWithTTL(ctx, cacheKey, raw, 5*time.Minute); err != nil {
+				s.logger.Warn("Failed to cache response", zap.Error(err))
+			}
+		}
+	}
+
+	supply, ok := new(big.Int).SetString(raw, 10)
+	if !ok || supply.Sign() <= 0 {
+		return nil, false
+	}
+	return supply, true
+}
+
+// supplyPercentage returns balance as a percentage of supply with 4 decimal places,
+// or "" when balance is not a valid integer
+func supplyPercentage(balance string, supply *big.Int) string {
+	b, ok := new(big.Int).SetString(balance, 10)
+	if !ok {
+		return ""
+	}
+	return new(big.Rat).SetFrac(b.Mul(b, big.NewInt(100)), supply).FloatString(4)
+}
+
+// topHolders returns a token's top holders from the analytics store when configured,
+// falling back to the transfers table when it is not or the query fails
+func (s *HoldersService) topHolders(ctx context.Context, tokenAddress string, limit int) ([]repositories.HolderBalance, error) {
+	if s.analytics != nil {
+		holders, err := s.analytics.GetTopHolders(ctx, tokenAddress, limit, 0, nil)
+		if err == nil {
+			return holders, nil
+		}
+		s.logger.Warn("Analytics store query failed, falling back to database", zap.Error(err))
+	}
+
+	holders, err := s.transferRepo.GetTopHolders(ctx, tokenAddress, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top holders: %w", err)
+	}
+	return holders, nil
+}
+
+// GetHolderChanges compares a token's current top holders with the latest snapshot taken at least
+// since ago (or the earliest one, if none is that old) and returns who entered and exited the top N,
+// along with the limit addresses whose balance changed the most since the snapshot. Excluded addresses
+// are left out of all three unless includeExcluded is set.
+func (s *HoldersService) GetHolderChanges(ctx context.Context, tokenAddress string, since time.Duration, limit int, includeExcluded bool) (*HolderChangesResponse, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	if s.snapshots == nil {
+		return nil, ErrNoHolderSnapshot
+	}
+
+	cacheKey := fmt.Sprintf("holder_changes:%s:%s:%d%s", tokenAddress, since, limit, exclusionCacheSuffix(includeExcluded))
+	var cached HolderChangesResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token: %w", err)
+	}
+	if token == nil {
+		return nil, nil // Token not found
+	}
+
+	snapshot, err := s.snapshots.GetAt(ctx, tokenAddress, time.Now().Add(-since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holder snapshot: %w", err)
+	}
+	if snapshot == nil {
+		return nil, ErrNoHolderSnapshot
+	}
+
+	current, err := s.topHolders(ctx, tokenAddress, s.snapshotSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Over-fetch so excluded addresses do not shorten the list
+	var excluded map[string]bool
+	fetchLimit := limit
+	if !includeExcluded {
+		excluded = make(map[string]bool)
+		for _, addr := range s.exclusions.forToken(tokenAddress) {
+			excluded[addr] = true
+		}
+		fetchLimit += len(excluded)
+	}
+
+	changes, err := s.transferRepo.GetBalanceChanges(ctx, tokenAddress, snapshot.TakenAt, fetchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance changes: %w", err)
+	}
+
+	data := HolderChangesDTO{
+		TokenAddress:   tokenAddress,
+		SnapshotAt:     snapshot.TakenAt,
+		TopN:           s.snapshotSize,
+		Entered:        holderDiff(current, snapshot.Holders, excluded),
+		Exited:         holderDiff(snapshot.Holders, current, excluded),
+		LargestChanges: make([]BalanceChangeDTO, 0, limit),
+	}
+	for _, c := range changes {
+		if excluded[c.Address] || len(data.LargestChanges) == limit {
+			continue
+		}
+		data.LargestChanges = append(data.LargestChanges, BalanceChangeDTO{Address: c.Address, Change: c.Change})
+	}
+
+	response := &HolderChangesResponse{Data: data}
+
+	// Cache the response (1 minute TTL, like individual holder balances)
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, time.Minute); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// CompareHolderSnapshots compares a token's top holders in the snapshots of two days: the latest
+// snapshot taken by the end of each day, or the earliest one when none is that old. It reports the
+// rank and balance changes of holders in both, and who entered and exited the top N. Excluded
+// addresses are left out unless includeExcluded is set. Returns nil if the token does not exist.
+func (s *HoldersService) CompareHolderSnapshots(ctx context.Context, tokenAddress string, from, to time.Time, includeExcluded bool) (*HolderComparisonResponse, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	if s.snapshots == nil {
+		return nil, ErrNoHolderSnapshot
+	}
+
+	cacheKey := fmt.Sprintf("holder_compare:%s:%s:%s%s", tokenAddress, from.Format("2006-01-02"), to.Format("2006-01-02"), exclusionCacheSuffix(includeExcluded))
+	var cached HolderComparisonResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token: %w", err)
+	}
+	if token == nil {
+		return nil, nil // Token not found
+	}
+
+	// Snapshots are taken throughout a day, so a day's is the last one before the next day begins
+	endOfDay := func(day time.Time) time.Time { return day.AddDate(0, 0, 1).Add(-time.Microsecond) }
+	earlier, err := s.snapshots.GetAt(ctx, tokenAddress, endOfDay(from))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holder snapshot: %w", err)
+	}
+	later, err := s.snapshots.GetAt(ctx, tokenAddress, endOfDay(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holder snapshot: %w", err)
+	}
+	if earlier == nil || later == nil {
+		return nil, ErrNoHolderSnapshot
+	}
+
+	var excluded map[string]bool
+	if !includeExcluded {
+		excluded = make(map[string]bool)
+		for _, addr := range s.exclusions.forToken(tokenAddress) {
+			excluded[addr] = true
+		}
+	}
+
+	data := HolderComparisonDTO{
+		TokenAddress:   tokenAddress,
+		FromSnapshotAt: earlier.TakenAt,
+		ToSnapshotAt:   later.TakenAt,
+		Changes:        make([]HolderRankChangeDTO, 0),
+		Entered:        holderDiff(later.Holders, earlier.Holders, excluded),
+		Exited:         holderDiff(earlier.Holders, later.Holders, excluded),
+	}
+	before := make(map[string]repositories.HolderBalance, len(earlier.Holders))
+	for _, h := range earlier.Holders {
+		before[h.Address] = h
+	}
+	for _, h := range later.Holders {
+		prev, ok := before[h.Address]
+		if !ok || excluded[h.Address] {
+			continue
+		}
+		data.Changes = append(data.Changes, HolderRankChangeDTO{
+			Address:      h.Address,
+			FromRank:     prev.Rank,
+			ToRank:       h.Rank,
+			RankChange:   prev.Rank - h.Rank,
+			FromBalance:  prev.Balance,
+			ToBalance:    h.Balance,
+			BalanceDelta: balanceDelta(prev.Balance, h.Balance),
+		})
+	}
+
+	response := &HolderComparisonResponse{Data: data}
+
+	// Cache the response (1 minute TTL, like holder changes); a day still under way gets new snapshots
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, time.Minute); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// balanceDelta returns to minus from for two raw balances, or "" if either is not an integer
+func balanceDelta(from, to string) string {
+	a, okA := new(big.Int).SetString(from, 10)
+	b, okB := new(big.Int).SetString(to, 10)
+	if !okA || !okB {
+		return ""
+	}
+	return b.Sub(b, a).String()
+}
+
+// holderDiff returns the holders in a that are not in b or excluded, in a's order
+func holderDiff(a, b []repositories.HolderBalance, excluded map[string]bool) []HolderDTO {
+	inB := make(map[string]bool, len(b))
+	for _, h := range b {
+		inB[h.Address] = true
+	}
+
+	result := make([]HolderDTO, 0)
+	for _, h := range a {
+		if !inB[h.Address] && !excluded[h.Address] {
+			result = append(result, HolderDTO{Address: h.Address, Balance: h.Balance, Rank: h.Rank})
+		}
+	}
+	return result
+}
+
+// GetNegativeBalances reports the negative balances of a token, or of every registered token when
+// tokenAddress is empty, with the limit most negative of each. Balances are derived from the
+// transfers in the database, not the analytics store, and are not cached, so a repair shows at
+// once. Returns nil if a given token does not exist.
+func (s *HoldersService) GetNegativeBalances(ctx context.Context, tokenAddress string, limit int) (*NegativeBalancesResponse, error) {
+	var addresses []string
+	if tokenAddress != "" {
+		tokenAddress = strings.ToLower(tokenAddress)
+		token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token: %w", err)
+		}
+		if token == nil {
+			return nil, nil
+		}
+		addresses = []string{tokenAddress}
+	} else {
+		tokens, err := s.tokenRepo.GetAll(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tokens: %w", err)
+		}
+		for _, token := range tokens {
+			addresses = append(addresses, token.Address)
+		}
+	}
+
+	response := &NegativeBalancesResponse{Data: make([]NegativeBalancesDTO, 0, len(addresses))}
+	for _, address := range addresses {
+		negative, err := s.transferRepo.GetNegativeBalances(ctx, address, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		worst := make([]HolderDTO, len(negative.Worst))
+		for i, h := range negative.Worst {
+			worst[i] = HolderDTO{Address: h.Address, Balance: h.Balance, Rank: h.Rank}
+		}
+		response.Data = append(response.Data, NegativeBalancesDTO{TokenAddress: address, Count: negative.Count, Worst: worst})
+
+		if negative.Count > 0 {
+			s.logger.Warn("Token has negative balances, transfers may be missing",
+				zap.String("token", address), zap.Int64("count", negative.Count))
+		}
+	}
+
+	return response, nil
+}
+
+// WithHolderCounts also records each token's holder count with its snapshot, served as an approximate
+// count for large tokens
+func (s *HoldersService) WithHolderCounts(repo repositories.HolderCountRepository) *HoldersService {
+	s.holderCounts = repo
+	return s
+}
+
+// TakeSnapshots stores the current top holders of each token, and its holder count when enabled, then
+// deletes snapshots older than retention. Failures are logged and do not stop the remaining tokens.
+func (s *HoldersService) TakeSnapshots(ctx context.Context, tokenAddresses []string, retention time.Duration) {
+	takenAt := time.Now().UTC().Truncate(time.Second)
+
+	for _, tokenAddress := range tokenAddresses {
+		tokenAddress = strings.ToLower(tokenAddress)
+
+		holders, err := s.topHolders(ctx, tokenAddress, s.snapshotSize)
+		if err != nil {
+			s.logger.Warn("Failed to read holders for snapshot", zap.String("token", tokenAddress), zap.Error(err))
+			continue
+		}
+		if err := s.snapshots.Save(ctx, tokenAddress, takenAt, holders); err != nil {
+			s.logger.Warn("Failed to save holder snapshot", zap.String("token", tokenAddress), zap.Error(err))
+			continue
+		}
+		s.logger.Debug("Saved holder snapshot", zap.String("token", tokenAddress), zap.Int("holders", len(holders)))
+
+		if s.holderCounts != nil {
+			s.recordHolderCount(ctx, tokenAddress, takenAt)
+		}
+	}
+
+	deleted, err := s.snapshots.DeleteBefore(ctx, takenAt.Add(-retention))
+	if err != nil {
+		s.logger.Warn("Failed to prune holder snapshots", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info("Pruned holder snapshots", zap.Int64("rows", deleted))
+	}
+}
+
+// recordHolderCount counts all of a token's holders, including excluded addresses, and records the count
+func (s *HoldersService) recordHolderCount(ctx context.Context, tokenAddress string, countedAt time.Time) {
+	count, err := holderCount(ctx, s.analytics, s.transferRepo, tokenAddress, nil, s.logger)
+	if err != nil {
+		s.logger.Warn("Failed to count holders", zap.String("token", tokenAddress), zap.Error(err))
+		return
+	}
+	if err := s.holderCounts.Save(ctx, tokenAddress, count, countedAt); err != nil {
+		s.logger.Warn("Failed to save holder count", zap.String("token", tokenAddress), zap.Error(err))
+	}
+}
+
+// RunSnapshotLoop takes holder snapshots immediately and then every interval until ctx is cancelled
+func (s *HoldersService) RunSnapshotLoop(ctx context.Context, tokenAddresses []string, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.TakeSnapshots(ctx, tokenAddresses, retention)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// StreamHolderHistory calls fn for each entry of a holder's ledger page, oldest first, as rows are read.
+// It returns nil without calling fn when the token is not indexed.
+func (s *HoldersService) StreamHolderHistory(
+	ctx context.Context,
+	tokenAddress, holderAddress string,
+	filter repositories.HolderHistoryFilter,
+	fn func(HolderHistoryEntryDTO) error,
+) (*HolderHistoryPagination, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+	holderAddress = strings.ToLower(holderAddress)
+
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+	if filter.Limit > MaxHolderHistoryLimit {
+		filter.Limit = MaxHolderHistoryLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token: %w", err)
+	}
+	if token == nil {
+		return nil, nil // Token not found
+	}
+
+	pagination := &HolderHistoryPagination{Limit: filter.Limit, Offset: filter.Offset}
+
+	// Read one extra row to learn whether another page follows
+	limit := filter.Limit
+	filter.Limit++
+	emitted := 0
+	err = s.transferRepo.StreamHolderHistory(ctx, tokenAddress, holderAddress, filter, func(e repositories.HolderBalanceEvent) error {
+		if emitted == limit {
+			pagination.HasMore = true
+			return nil
+		}
+		emitted++
+		return fn(HolderHistoryEntryDTO{
+			BlockNumber:    e.BlockNumber,
+			BlockTimestamp: e.BlockTimestamp.Format("2006-01-02T15:04:05Z"),
+			TxHash:         e.TxHash,
+			LogIndex:       e.LogIndex,
+			Counterparty:   e.Counterparty,
+			Change:         e.Change,
+			Balance:        e.Balance,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream holder history: %w", err)
+	}
+
+	return pagination, nil
+}
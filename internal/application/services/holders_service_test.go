@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"errors"
+	"math/big"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -39,12 +41,12 @@ func TestHoldersService_GetTopHolders_Success(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 100, nil
 	}
 
 	// Setup mock holders response
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		return []repositories.HolderBalance{
 			{Address: "0x47ac0fb4f2d84898e4d9e7b4dab3c24507a6d503", Balance: "999999999999999999999", Rank: 1},
 			{Address: "0x1111111111111111111111111111111111111111", Balance: "500000000000000000000", Rank: 2},
@@ -52,7 +54,7 @@ func TestHoldersService_GetTopHolders_Success(t *testing.T) {
 		}, nil
 	}
 
-	response, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0)
+	response, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -97,7 +99,7 @@ func TestHoldersService_GetTopHolders_TokenNotFound(t *testing.T) {
 	service, _, _ := setupHoldersServiceTest()
 	ctx := context.Background()
 
-	response, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0)
+	response, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -117,16 +119,16 @@ func TestHoldersService_GetTopHolders_EmptyResult(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 0, nil
 	}
 
 	// Setup mock empty holders response
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		return []repositories.HolderBalance{}, nil
 	}
 
-	response, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0)
+	response, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -154,25 +156,25 @@ func TestHoldersService_GetTopHolders_LimitValidation(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 0, nil
 	}
 
 	// Track the limit passed to repo
 	var capturedLimit int
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		capturedLimit = limit
 		return []repositories.HolderBalance{}, nil
 	}
 
 	// Test default limit (when 0 is passed)
-	_, _ = service.GetTopHolders(ctx, testutil.USDTAddress, 0, 0)
+	_, _ = service.GetTopHolders(ctx, testutil.USDTAddress, 0, 0, false)
 	if capturedLimit != 100 {
 		t.Errorf("expected default limit 100, got %d", capturedLimit)
 	}
 
 	// Test max limit (when > 1000 is passed)
-	_, _ = service.GetTopHolders(ctx, testutil.USDTAddress, 5000, 0)
+	_, _ = service.GetTopHolders(ctx, testutil.USDTAddress, 5000, 0, false)
 	if capturedLimit != 1000 {
 		t.Errorf("expected max limit 1000, got %d", capturedLimit)
 	}
@@ -188,20 +190,20 @@ func TestHoldersService_GetTopHolders_Lowercase(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 0, nil
 	}
 
 	// Track which address was queried
 	var queriedAddress string
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		queriedAddress = tokenAddress
 		return []repositories.HolderBalance{}, nil
 	}
 
 	// Use uppercase address
 	upperAddr := "0xDAC17F958D2EE523A2206206994597C13D831EC7"
-	_, err := service.GetTopHolders(ctx, upperAddr, 100, 0)
+	_, err := service.GetTopHolders(ctx, upperAddr, 100, 0, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -219,7 +221,7 @@ func TestHoldersService_GetTopHolders_TokenRepoError(t *testing.T) {
 		return nil, errors.New("database connection failed")
 	}
 
-	_, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0)
+	_, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0, false)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -238,15 +240,15 @@ func TestHoldersService_GetTopHolders_TransferRepoError(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 100, nil
 	}
 
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		return nil, errors.New("query timeout")
 	}
 
-	_, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0)
+	_, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0, false)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -265,13 +267,13 @@ func TestHoldersService_GetTopHolders_Pagination(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 150, nil
 	}
 
 	// Track offset passed to repo
 	var capturedOffset int
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		capturedOffset = offset
 		return []repositories.HolderBalance{
 			{Address: "0x1111111111111111111111111111111111111111", Balance: "1000", Rank: offset + 1},
@@ -279,7 +281,7 @@ func TestHoldersService_GetTopHolders_Pagination(t *testing.T) {
 	}
 
 	// Test with offset 100
-	response, err := service.GetTopHolders(ctx, testutil.USDTAddress, 50, 100)
+	response, err := service.GetTopHolders(ctx, testutil.USDTAddress, 50, 100, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -297,7 +299,7 @@ func TestHoldersService_GetTopHolders_Pagination(t *testing.T) {
 	}
 
 	// Test with offset 0
-	response, err = service.GetTopHolders(ctx, testutil.USDTAddress, 50, 0)
+	response, err = service.GetTopHolders(ctx, testutil.USDTAddress, 50, 0, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -317,11 +319,11 @@ func TestHoldersService_GetTopHolders_HolderCountError(t *testing.T) {
 	))
 
 	// Setup mock holder count error
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 0, errors.New("count query failed")
 	}
 
-	_, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0)
+	_, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0, false)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -330,6 +332,148 @@ func TestHoldersService_GetTopHolders_HolderCountError(t *testing.T) {
 	}
 }
 
+func TestHoldersService_GetTopHolders_FromAnalytics(t *testing.T) {
+	service, transferRepo, tokenRepo := setupHoldersServiceTest()
+	analytics := testutil.NewMockAnalyticsRepository()
+	analytics.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
+		return 50, nil
+	}
+	analytics.GetTopHoldersFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+		return []repositories.HolderBalance{{Address: testutil.AliceAddress, Balance: "900", Rank: offset + 1}}, nil
+	}
+	service.WithAnalytics(analytics)
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	response, err := service.GetTopHolders(context.Background(), testutil.USDTAddress, 10, 20, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data[0].Rank != 21 || response.Pagination.Total != 50 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if len(transferRepo.Calls) != 0 {
+		t.Errorf("expected database not to be queried, got %d calls", len(transferRepo.Calls))
+	}
+}
+
+func TestHoldersService_GetTopHolders_AnalyticsFallback(t *testing.T) {
+	service, transferRepo, tokenRepo := setupHoldersServiceTest()
+	analytics := testutil.NewMockAnalyticsRepository()
+	analytics.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
+		return 0, errors.New("connection refused")
+	}
+	analytics.GetTopHoldersFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+		return nil, errors.New("connection refused")
+	}
+	service.WithAnalytics(analytics)
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
+		return 3, nil
+	}
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+		return []repositories.HolderBalance{{Address: testutil.BobAddress, Balance: "1", Rank: 1}}, nil
+	}
+
+	response, err := service.GetTopHolders(context.Background(), testutil.USDTAddress, 10, 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data[0].Address != testutil.BobAddress || response.Pagination.Total != 3 {
+		t.Errorf("expected database results after analytics failure, got %+v", response)
+	}
+}
+
+func TestHoldersService_GetTopHolders_Exclusions(t *testing.T) {
+	service, transferRepo, tokenRepo := setupHoldersServiceTest()
+	ctx := context.Background()
+	service.WithHolderExclusions(HolderExclusions{Addresses: []string{testutil.CharlieAddr}, ExcludeToken: true})
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	// Alice, Charlie (a burn address here) and the token contract each hold a balance
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithFromAddress(entities.ZeroAddress), testutil.WithToAddress(testutil.AliceAddress)),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithFromAddress(entities.ZeroAddress), testutil.WithToAddress(testutil.CharlieAddr)),
+		testutil.CreateTestTransfer(testutil.WithID(3), testutil.WithFromAddress(entities.ZeroAddress), testutil.WithToAddress(testutil.USDTAddress)),
+	)
+
+	response, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data[0].Address != testutil.AliceAddress || response.Pagination.Total != 1 {
+		t.Errorf("expected only alice, got %+v", response)
+	}
+
+	response, err = service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Data) != 3 || response.Pagination.Total != 3 {
+		t.Errorf("expected all three holders with include_excluded, got %+v", response)
+	}
+}
+
+func TestHoldersService_AddPercentages(t *testing.T) {
+	service, transferRepo, tokenRepo := setupHoldersServiceTest()
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	// 3000 minted, 1000 of Bob's burned: Alice holds 1500 of 2000, Bob 500
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithFromAddress(entities.ZeroAddress), testutil.WithToAddress(testutil.AliceAddress), testutil.WithValue(big.NewInt(1500))),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithFromAddress(entities.ZeroAddress), testutil.WithToAddress(testutil.BobAddress), testutil.WithValue(big.NewInt(1500))),
+		testutil.CreateTestTransfer(testutil.WithID(3), testutil.WithFromAddress(testutil.BobAddress), testutil.WithToAddress(entities.ZeroAddress), testutil.WithValue(big.NewInt(1000))),
+	)
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+		return []repositories.HolderBalance{
+			{Address: testutil.AliceAddress, Balance: "1500", Rank: 1},
+			{Address: testutil.BobAddress, Balance: "500", Rank: 2},
+		}, nil
+	}
+
+	response, err := service.GetTopHolders(ctx, testutil.USDTAddress, 100, 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Data[0].Percentage != "" {
+		t.Errorf("expected no percentage before AddPercentages, got %q", response.Data[0].Percentage)
+	}
+
+	service.AddPercentages(ctx, testutil.USDTAddress, response)
+	if response.Data[0].Percentage != "75.0000" || response.Data[1].Percentage != "25.0000" {
+		t.Errorf("expected 75.0000 and 25.0000, got %q and %q", response.Data[0].Percentage, response.Data[1].Percentage)
+	}
+
+	balance := &HolderBalanceResponse{Data: HolderDTO{Address: testutil.BobAddress, Balance: "1", Rank: 2}}
+	service.AddBalancePercentage(ctx, testutil.USDTAddress, balance)
+	if balance.Data.Percentage != "0.0500" {
+		t.Errorf("expected 0.0500, got %q", balance.Data.Percentage)
+	}
+}
+
+func TestHoldersService_AddPercentages_NoSupply(t *testing.T) {
+	service, transferRepo, _ := setupHoldersServiceTest()
+	ctx := context.Background()
+
+	response := &TopHoldersResponse{Data: []HolderDTO{{Address: testutil.AliceAddress, Balance: "1500", Rank: 1}}}
+
+	// No mints indexed
+	service.AddPercentages(ctx, testutil.USDTAddress, response)
+	if response.Data[0].Percentage != "" {
+		t.Errorf("expected no percentage without a tracked supply, got %q", response.Data[0].Percentage)
+	}
+
+	transferRepo.GetCirculatingSupplyFunc = func(ctx context.Context, tokenAddress string) (string, error) {
+		return "", errors.New("database error")
+	}
+	service.AddPercentages(ctx, testutil.USDTAddress, response)
+	if response.Data[0].Percentage != "" {
+		t.Errorf("expected no percentage when the supply lookup fails, got %q", response.Data[0].Percentage)
+	}
+}
+
 func TestHoldersService_GetHolderBalance_Success(t *testing.T) {
 	service, transferRepo, tokenRepo := setupHoldersServiceTest()
 	ctx := context.Background()
@@ -501,3 +645,265 @@ func TestHoldersService_GetHolderBalance_TransferRepoError(t *testing.T) {
 		t.Errorf("unexpected error message: %v", err)
 	}
 }
+
+func TestHoldersService_GetHolderChanges(t *testing.T) {
+	service, transferRepo, tokenRepo := setupHoldersServiceTest()
+	snapshots := testutil.NewMockHolderSnapshotRepository()
+	service.WithSnapshots(snapshots, 2)
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	// A day ago Alice and Charlie were the top two; now Bob has overtaken Charlie
+	takenAt := time.Now().Add(-25 * time.Hour)
+	_ = snapshots.Save(ctx, testutil.USDTAddress, takenAt, []repositories.HolderBalance{
+		{Address: testutil.AliceAddress, Balance: "500", Rank: 1},
+		{Address: testutil.CharlieAddr, Balance: "300", Rank: 2},
+	})
+	transferRepo.GetTopHoldersFunc = func(ctx context.Context, tokenAddress string, limit int) ([]repositories.HolderBalance, error) {
+		if limit != 2 {
+			t.Errorf("expected current top 2, got limit %d", limit)
+		}
+		return []repositories.HolderBalance{
+			{Address: testutil.BobAddress, Balance: "450", Rank: 1},
+			{Address: testutil.AliceAddress, Balance: "400", Rank: 2},
+		}, nil
+	}
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithFromAddress(testutil.CharlieAddr), testutil.WithToAddress(testutil.BobAddress),
+			testutil.WithValue(big.NewInt(250)), testutil.WithBlockTimestamp(time.Now().Add(-time.Hour))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(1), testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.BobAddress),
+			testutil.WithValue(big.NewInt(100)), testutil.WithBlockTimestamp(time.Now().Add(-time.Hour))),
+	)
+
+	response, err := service.GetHolderChanges(ctx, testutil.USDTAddress, 24*time.Hour, 2, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := response.Data
+	if !data.SnapshotAt.Equal(takenAt) || data.TopN != 2 {
+		t.Errorf("unexpected snapshot metadata: %v, top %d", data.SnapshotAt, data.TopN)
+	}
+	if len(data.Entered) != 1 || data.Entered[0].Address != testutil.BobAddress || data.Entered[0].Balance != "450" {
+		t.Errorf("expected Bob to enter, got %+v", data.Entered)
+	}
+	if len(data.Exited) != 1 || data.Exited[0].Address != testutil.CharlieAddr || data.Exited[0].Rank != 2 {
+		t.Errorf("expected Charlie to exit, got %+v", data.Exited)
+	}
+	if len(data.LargestChanges) != 2 ||
+		data.LargestChanges[0] != (BalanceChangeDTO{Address: testutil.BobAddress, Change: "350"}) ||
+		data.LargestChanges[1] != (BalanceChangeDTO{Address: testutil.CharlieAddr, Change: "-250"}) {
+		t.Errorf("unexpected largest changes: %+v", data.LargestChanges)
+	}
+}
+
+func TestHoldersService_CompareHolderSnapshots(t *testing.T) {
+	service, _, tokenRepo := setupHoldersServiceTest()
+	snapshots := testutil.NewMockHolderSnapshotRepository()
+	service.WithSnapshots(snapshots, 3)
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	_ = snapshots.Save(ctx, testutil.USDTAddress, day(1).Add(6*time.Hour), []repositories.HolderBalance{
+		{Address: testutil.AliceAddress, Balance: "900", Rank: 1},
+	})
+	// The last snapshot of January 1st
+	_ = snapshots.Save(ctx, testutil.USDTAddress, day(1).Add(18*time.Hour), []repositories.HolderBalance{
+		{Address: testutil.AliceAddress, Balance: "500", Rank: 1},
+		{Address: testutil.CharlieAddr, Balance: "300", Rank: 2},
+		{Address: testutil.BobAddress, Balance: "200", Rank: 3},
+	})
+	_ = snapshots.Save(ctx, testutil.USDTAddress, day(8).Add(12*time.Hour), []repositories.HolderBalance{
+		{Address: testutil.BobAddress, Balance: "700", Rank: 1},
+		{Address: testutil.AliceAddress, Balance: "400", Rank: 2},
+		{Address: "0x4444444444444444444444444444444444444444", Balance: "100", Rank: 3},
+	})
+
+	response, err := service.CompareHolderSnapshots(ctx, testutil.USDTAddress, day(1), day(10), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := response.Data
+	if !data.FromSnapshotAt.Equal(day(1).Add(18*time.Hour)) || !data.ToSnapshotAt.Equal(day(8).Add(12*time.Hour)) {
+		t.Errorf("unexpected snapshots: %v and %v", data.FromSnapshotAt, data.ToSnapshotAt)
+	}
+	want := []HolderRankChangeDTO{
+		{Address: testutil.BobAddress, FromRank: 3, ToRank: 1, RankChange: 2, FromBalance: "200", ToBalance: "700", BalanceDelta: "500"},
+		{Address: testutil.AliceAddress, FromRank: 1, ToRank: 2, RankChange: -1, FromBalance: "500", ToBalance: "400", BalanceDelta: "-100"},
+	}
+	if len(data.Changes) != len(want) || data.Changes[0] != want[0] || data.Changes[1] != want[1] {
+		t.Errorf("unexpected changes: %+v", data.Changes)
+	}
+	if len(data.Entered) != 1 || data.Entered[0].Address != "0x4444444444444444444444444444444444444444" {
+		t.Errorf("expected one holder to enter, got %+v", data.Entered)
+	}
+	if len(data.Exited) != 1 || data.Exited[0].Address != testutil.CharlieAddr || data.Exited[0].Balance != "300" {
+		t.Errorf("expected Charlie to exit, got %+v", data.Exited)
+	}
+
+	// Unknown tokens are not found
+	if response, err := service.CompareHolderSnapshots(ctx, testutil.USDCAddress, day(1), day(10), false); err != nil || response != nil {
+		t.Errorf("expected nil response for unknown token, got %+v (%v)", response, err)
+	}
+}
+
+func TestHoldersService_GetHolderChanges_NoSnapshot(t *testing.T) {
+	service, _, tokenRepo := setupHoldersServiceTest()
+	ctx := context.Background()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	// Snapshots not configured
+	if _, err := service.GetHolderChanges(ctx, testutil.USDTAddress, time.Hour, 10, false); !errors.Is(err, ErrNoHolderSnapshot) {
+		t.Errorf("expected ErrNoHolderSnapshot, got %v", err)
+	}
+
+	// Configured, but none taken yet
+	service.WithSnapshots(testutil.NewMockHolderSnapshotRepository(), 100)
+	if _, err := service.GetHolderChanges(ctx, testutil.USDTAddress, time.Hour, 10, false); !errors.Is(err, ErrNoHolderSnapshot) {
+		t.Errorf("expected ErrNoHolderSnapshot, got %v", err)
+	}
+}
+
+func TestHoldersService_GetHolderChanges_TokenNotFound(t *testing.T) {
+	service, _, _ := setupHoldersServiceTest()
+	service.WithSnapshots(testutil.NewMockHolderSnapshotRepository(), 100)
+
+	response, err := service.GetHolderChanges(context.Background(), testutil.USDTAddress, time.Hour, 10, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response != nil {
+		t.Error("expected nil response for unknown token")
+	}
+}
+
+func TestHoldersService_TakeSnapshots(t *testing.T) {
+	service, transferRepo, _ := setupHoldersServiceTest()
+	snapshots := testutil.NewMockHolderSnapshotRepository()
+	service.WithSnapshots(snapshots, 10)
+	ctx := context.Background()
+
+	_ = snapshots.Save(ctx, testutil.USDTAddress, time.Now().Add(-48*time.Hour), []repositories.HolderBalance{
+		{Address: testutil.AliceAddress, Balance: "1", Rank: 1},
+	})
+	transferRepo.GetTopHoldersFunc = func(ctx context.Context, tokenAddress string, limit int) ([]repositories.HolderBalance, error) {
+		if tokenAddress == testutil.USDCAddress {
+			return nil, errors.New("database error")
+		}
+		return []repositories.HolderBalance{{Address: testutil.BobAddress, Balance: "5", Rank: 1}}, nil
+	}
+
+	// A failing token does not stop the others, and the two-day-old snapshot is pruned
+	service.TakeSnapshots(ctx, []string{testutil.USDCAddress, testutil.USDTAddress}, 24*time.Hour)
+
+	snapshot, err := snapshots.GetAt(ctx, testutil.USDTAddress, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot == nil || len(snapshot.Holders) != 1 || snapshot.Holders[0].Address != testutil.BobAddress {
+		t.Errorf("expected Bob's snapshot to be the only one left, got %+v", snapshot)
+	}
+	if s, _ := snapshots.GetAt(ctx, testutil.USDCAddress, time.Now()); s != nil {
+		t.Errorf("expected no USDC snapshot, got %+v", s)
+	}
+}
+
+func TestHoldersService_TakeSnapshots_RecordsHolderCounts(t *testing.T) {
+	service, transferRepo, _ := setupHoldersServiceTest()
+	counts := testutil.NewMockHolderCountRepository()
+	service.WithSnapshots(testutil.NewMockHolderSnapshotRepository(), 10).WithHolderCounts(counts)
+	ctx := context.Background()
+
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
+		if len(exclude) != 0 {
+			t.Errorf("expected recorded counts to include every holder, got exclusions %v", exclude)
+		}
+		return 42, nil
+	}
+
+	service.TakeSnapshots(ctx, []string{testutil.USDTAddress}, 24*time.Hour)
+
+	recorded, err := counts.Get(ctx, testutil.USDTAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded == nil || recorded.Count != 42 || recorded.CountedAt.IsZero() {
+		t.Errorf("expected a recorded count of 42, got %+v", recorded)
+	}
+}
+
+func TestHoldersService_StreamHolderHistory(t *testing.T) {
+	service, transferRepo, tokenRepo := setupHoldersServiceTest()
+	ctx := context.Background()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithLogIndex(0), testutil.WithBlockNumber(100),
+			testutil.WithFromAddress(testutil.BobAddress), testutil.WithToAddress(testutil.AliceAddress), testutil.WithValue(big.NewInt(500))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(0), testutil.WithBlockNumber(101),
+			testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.CharlieAddr), testutil.WithValue(big.NewInt(200))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(0), testutil.WithBlockNumber(102),
+			testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.AliceAddress), testutil.WithValue(big.NewInt(50))),
+	)
+
+	var entries []HolderHistoryEntryDTO
+	collect := func(e HolderHistoryEntryDTO) error {
+		entries = append(entries, e)
+		return nil
+	}
+
+	pagination, err := service.StreamHolderHistory(ctx, testutil.USDTAddress, testutil.AliceAddress,
+		repositories.HolderHistoryFilter{Limit: 2}, collect)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pagination == nil || !pagination.HasMore || pagination.Limit != 2 {
+		t.Errorf("expected a full first page with more to follow, got %+v", pagination)
+	}
+	if len(entries) != 2 ||
+		entries[0].Change != "500" || entries[0].Balance != "500" || entries[0].Counterparty != testutil.BobAddress ||
+		entries[1].Change != "-200" || entries[1].Balance != "300" || entries[1].Counterparty != testutil.CharlieAddr {
+		t.Errorf("unexpected first page: %+v", entries)
+	}
+
+	// The running balance carries over into later pages; self-transfers change nothing
+	entries = nil
+	pagination, err = service.StreamHolderHistory(ctx, testutil.USDTAddress, testutil.AliceAddress,
+		repositories.HolderHistoryFilter{Limit: 2, Offset: 2}, collect)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pagination.HasMore || len(entries) != 1 || entries[0].Change != "0" || entries[0].Balance != "300" {
+		t.Errorf("unexpected last page: %+v %+v", pagination, entries)
+	}
+}
+
+func TestHoldersService_StreamHolderHistory_TokenNotFound(t *testing.T) {
+	service, _, _ := setupHoldersServiceTest()
+
+	pagination, err := service.StreamHolderHistory(context.Background(), testutil.USDTAddress, testutil.AliceAddress,
+		repositories.HolderHistoryFilter{}, func(HolderHistoryEntryDTO) error {
+			t.Error("unexpected entry for unknown token")
+			return nil
+		})
+	if err != nil || pagination != nil {
+		t.Errorf("expected nil pagination and error, got %+v (%v)", pagination, err)
+	}
+}
+
+func TestHoldersService_StreamHolderHistory_CallbackError(t *testing.T) {
+	service, transferRepo, tokenRepo := setupHoldersServiceTest()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	transferRepo.AddTransfers(testutil.CreateTestTransfer(testutil.WithToAddress(testutil.AliceAddress)))
+
+	writeErr := errors.New("client went away")
+	_, err := service.StreamHolderHistory(context.Background(), testutil.USDTAddress, testutil.AliceAddress,
+		repositories.HolderHistoryFilter{}, func(HolderHistoryEntryDTO) error { return writeErr })
+	if !errors.Is(err, writeErr) {
+		t.Errorf("expected callback error, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+// Hot wallets are ranked by their summary requests, halved every interval so the ranking follows
+// the last few intervals. Only wallets scoring at least hotWalletMinScore are refreshed, so one
+// asked for once is not, and counters decayed below half of it are dropped.
+const (
+	hotWalletsKey     = "hot_wallets"
+	hotWalletsLockKey = "hot_wallets_lock"
+	hotWalletDecay    = 0.5
+	hotWalletMinScore = 2
+)
+
+var (
+	hotWalletsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "api_hot_wallets",
+		Help: "Wallets whose summaries were refreshed in the last hot wallet refresh",
+	})
+	hotWalletRefreshes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "api_hot_wallet_refreshes_total",
+			Help: "Wallet summaries reloaded ahead of requests, by result (ok or error)",
+		},
+		[]string{"result"},
+	)
+)
+
+// CheckHotWalletsInterval rejects refresh intervals that would let hot wallet summaries expire
+// between refreshes
+func CheckHotWalletsInterval(interval time.Duration) error {
+	if interval <= 0 || interval >= WalletSummaryCacheTTL {
+		return fmt.Errorf("hot wallet refresh interval %s must be positive and below the %s summary cache TTL", interval, WalletSummaryCacheTTL)
+	}
+	return nil
+}
+
+// HotWallets keeps the summaries of the most requested wallets cached, so dashboards polling
+// popular addresses such as exchanges do not run the wallet aggregation whenever the cached
+// summary expires. Requests are counted in process and added to counters shared through the cache
+// every interval; one process per interval then reloads the top wallets' summaries.
+type HotWallets struct {
+	cache     *cache.RedisCache
+	portfolio *PortfolioService
+	size      int
+	interval  time.Duration
+	logger    *zap.Logger
+
+	mu     sync.Mutex
+	counts map[string]float64 // Requests since the last flush, by wallet
+}
+
+// NewHotWallets creates a tracker refreshing the summaries of the size most requested wallets
+// through portfolio every interval
+func NewHotWallets(cache *cache.RedisCache, portfolio *PortfolioService, size int, interval time.Duration, logger *zap.Logger) *HotWallets {
+	return &HotWallets{
+		cache:     cache,
+		portfolio: portfolio,
+		size:      size,
+		interval:  interval,
+		logger:    logger,
+		counts:    make(map[string]float64),
+	}
+}
+
+// record counts a summary request for a lowercased wallet address
+func (h *HotWallets) record(walletAddress string) {
+	h.mu.Lock()
+	h.counts[walletAddress]++
+	h.mu.Unlock()
+}
+
+// Run flushes request counts and refreshes hot wallet summaries every interval until ctx is done
+func (h *HotWallets) Run(ctx context.Context) {
+	h.logger.Info("Refreshing hot wallet summaries",
+		zap.Int("wallets", h.size),
+		zap.Duration("interval", h.interval),
+	)
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.Refresh(ctx); err != nil {
+				h.logger.Warn("Failed to refresh hot wallets", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Refresh adds this process's request counts to the shared counters and, unless another process
+// did within the interval, reloads the summaries of the top wallets and decays the counters
+func (h *HotWallets) Refresh(ctx context.Context) error {
+	h.mu.Lock()
+	counts := h.counts
+	h.counts = make(map[string]float64)
+	h.mu.Unlock()
+
+	if err := h.cache.IncrementScores(ctx, hotWalletsKey, counts); err != nil {
+		return err
+	}
+
+	// A little under the interval, so the next tick of whichever process gets there first refreshes
+	locked, err := h.cache.TryLock(ctx, hotWalletsLockKey, h.interval*9/10)
+	if err != nil || !locked {
+		return err
+	}
+
+	wallets, err := h.cache.TopScores(ctx, hotWalletsKey, h.size, hotWalletMinScore)
+	if err != nil {
+		return err
+	}
+	hotWalletsGauge.Set(float64(len(wallets)))
+
+	for _, wallet := range wallets {
+		if err := h.portfolio.warmWalletSummary(ctx, wallet); err != nil {
+			hotWalletRefreshes.WithLabelValues("error").Inc()
+			h.logger.Warn("Failed to refresh wallet summary", zap.String("wallet", wallet), zap.Error(err))
+			continue
+		}
+		hotWalletRefreshes.WithLabelValues("ok").Inc()
+	}
+
+	if err := h.cache.DecayScores(ctx, hotWalletsKey, hotWalletDecay, hotWalletMinScore*hotWalletDecay); err != nil {
+		return fmt.Errorf("failed to decay hot wallet counters: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestHotWallets_Refresh(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	c := cache.NewMemoryCache(time.Minute, logger)
+	repo := testutil.NewMockPortfolioRepository()
+	portfolio := NewPortfolioService(repo, c, logger)
+	hot := NewHotWallets(c, portfolio, 1, time.Minute, logger)
+	portfolio.WithHotWallets(hot)
+
+	// Alice is asked for three times, Bob twice and Charlie once; only the top one is kept warm
+	for _, wallet := range []string{testutil.AliceAddress, testutil.AliceAddress, testutil.BobAddress, testutil.AliceAddress, testutil.BobAddress, testutil.CharlieAddr} {
+		if _, err := portfolio.GetWalletSummary(ctx, wallet); err != nil {
+			t.Fatal(err)
+		}
+		_ = c.Delete(ctx, "wallet_summary:"+wallet)
+	}
+	before := len(repo.Calls)
+
+	if err := hot.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if refreshed := repo.Calls[before:]; len(refreshed) != 1 || refreshed[0].Args[0] != testutil.AliceAddress {
+		t.Fatalf("expected only Alice's summary reloaded, got %+v", refreshed)
+	}
+	var cached WalletSummaryResponse
+	if err := c.Get(ctx, "wallet_summary:"+testutil.AliceAddress, &cached); err != nil || cached.Data.WalletAddress != testutil.AliceAddress {
+		t.Errorf("expected Alice's summary cached, got %+v (%v)", cached, err)
+	}
+
+	// Other processes refreshing within the interval are held off
+	before = len(repo.Calls)
+	if err := hot.Refresh(ctx); err != nil || len(repo.Calls) != before {
+		t.Errorf("expected no refresh within the interval, got %d loads (%v)", len(repo.Calls)-before, err)
+	}
+
+	// Without new requests the counters decay until nothing is hot
+	if top, _ := c.TopScores(ctx, hotWalletsKey, 10, hotWalletMinScore); len(top) != 0 {
+		t.Errorf("expected the halved counters below the minimum, got %v", top)
+	}
+
+	if err := CheckHotWalletsInterval(WalletSummaryCacheTTL); err == nil {
+		t.Error("expected an interval as long as the summary TTL to be rejected")
+	}
+}
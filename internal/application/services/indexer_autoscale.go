@@ -0,0 +1,58 @@
+package services
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var indexerWorkers = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "indexer_workers",
+	Help: "Concurrent live indexing fetches: tokens indexed in parallel times block ranges each fetches ahead",
+})
+
+// autoscaling reports whether the worker count adapts to the lag
+func (s *IndexerService) autoscaling() bool {
+	return s.config.WorkerCountMax > s.config.WorkerCount
+}
+
+// workerLevel returns the workers for a lag in blocks: WorkerCount at the head, rising linearly
+// to WorkerCountMax at AutoscaleLag blocks behind
+func (s *IndexerService) workerLevel(lag int64) int {
+	lo, hi := max(s.config.WorkerCount, 1), s.config.WorkerCountMax
+	if !s.autoscaling() {
+		return lo
+	}
+	if s.config.AutoscaleLag <= 0 || lag >= s.config.AutoscaleLag {
+		return hi
+	}
+	return lo + int(int64(hi-lo)*max(lag, 0)/s.config.AutoscaleLag)
+}
+
+// adjustWorkers sets the worker level from how far the lowest token checkpoint is behind the
+// chain head and returns it
+func (s *IndexerService) adjustWorkers() int {
+	level := s.workerLevel(0)
+	head := s.catchUp.head.Load()
+	if checkpoint, ok := s.lowestCheckpoint(); ok && head > 0 {
+		level = s.workerLevel(head - checkpoint)
+	}
+
+	if previous := s.workers.Swap(int64(level)); previous != 0 && previous != int64(level) {
+		s.logger.Info("Adjusted indexer workers",
+			zap.Int64("previous", previous),
+			zap.Int("workers", level),
+		)
+	}
+	indexerWorkers.Set(float64(level))
+	return level
+}
+
+// rangeWindow returns how many block ranges a token fetches ahead: its share of the workers
+// among the tokens indexed in parallel, or 1 without autoscaling
+func (s *IndexerService) rangeWindow(parallel int) int {
+	if !s.autoscaling() {
+		return 1
+	}
+	return max(1, int(s.workers.Load())/max(parallel, 1))
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	catchUpActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "indexer_catching_up",
+		Help: "1 while the lowest token checkpoint is more than INDEXER_CATCHUP_THRESHOLD blocks behind the chain head",
+	})
+	catchUpRemaining = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "indexer_catchup_blocks_remaining",
+		Help: "Blocks between the lowest token checkpoint and the chain head while catching up",
+	})
+	catchUpRate = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "indexer_catchup_blocks_per_second",
+		Help: "Blocks per second the lowest token checkpoint advanced since catch-up started",
+	})
+	catchUpETA = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "indexer_catchup_eta_seconds",
+		Help: "Estimated seconds until catch-up ends, -1 while the lag is not shrinking",
+	})
+)
+
+// CatchUpStatus reports progress while the indexer is far behind the chain head
+type CatchUpStatus struct {
+	StartedAt       string  `json:"started_at"`
+	BlocksRemaining int64   `json:"blocks_remaining"`
+	BlocksPerSecond float64 `json:"blocks_per_second"`
+	ETASeconds      *int64  `json:"eta_seconds,omitempty"` // Absent while the lag is not shrinking
+}
+
+// catchUp tracks catch-up mode: entered when the lowest checkpoint of the active tokens is
+// more than the threshold behind the chain head, left once the lag drops below it
+type catchUp struct {
+	head            atomic.Int64 // Latest chain head seen by the indexing loop
+	mu              sync.Mutex
+	active          bool
+	startedAt       time.Time
+	startCheckpoint int64
+	startLag        int64
+	status          CatchUpStatus
+}
+
+// recordCheckpoint remembers a token's checkpoint for catch-up tracking, worker autoscaling and
+// health checks
+func (s *IndexerService) recordCheckpoint(tokenAddress string, block int64) {
+	s.progressMu.Lock()
+	p := s.tokenProgressLocked(tokenAddress)
+	advanced := p.hasCheckpoint && block > p.checkpoint
+	p.checkpoint, p.hasCheckpoint = block, true
+	s.progressMu.Unlock()
+
+	if advanced {
+		s.metrics.mu.Lock()
+		s.metrics.LastCheckpointAt = time.Now()
+		s.metrics.mu.Unlock()
+	}
+
+	s.adjustWorkers()
+}
+
+// lowestCheckpoint returns the lowest known checkpoint of the active tokens
+func (s *IndexerService) lowestCheckpoint() (int64, bool) {
+	s.activeMu.Lock()
+	tokens := s.activeTokens
+	s.activeMu.Unlock()
+	if tokens == nil {
+		tokens = s.tokenAddresses()
+	}
+
+	s.progressMu.RLock()
+	defer s.progressMu.RUnlock()
+	lowest, found := int64(math.MaxInt64), false
+	for _, addr := range tokens {
+		if p, ok := s.progress[addr]; ok && p.hasCheckpoint {
+			lowest, found = min(lowest, p.checkpoint), true
+		}
+	}
+	return lowest, found
+}
+
+// observeCatchUp enters or leaves catch-up mode from the latest head and checkpoints and
+// updates the catch-up metrics. Entering and leaving are logged; logProgress also logs the
+// progress of a catch-up under way.
+func (s *IndexerService) observeCatchUp(now time.Time, logProgress bool) {
+	threshold := s.config.CatchUpThreshold
+	head := s.catchUp.head.Load()
+	checkpoint, ok := s.lowestCheckpoint()
+	if threshold <= 0 || head == 0 || !ok {
+		return
+	}
+	lag := max(head-checkpoint, 0)
+
+	c := &s.catchUp
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.active {
+		if lag <= threshold {
+			return
+		}
+		c.active = true
+		c.startedAt = now
+		c.startCheckpoint = checkpoint
+		c.startLag = lag
+		c.status = CatchUpStatus{StartedAt: now.UTC().Format(time.RFC3339), BlocksRemaining: lag}
+		catchUpActive.Set(1)
+		catchUpRemaining.Set(float64(lag))
+		catchUpRate.Set(0)
+		catchUpETA.Set(-1)
+		s.logger.Info("Indexer is catching up",
+			zap.Int64("blocks_remaining", lag),
+			zap.Int64("chain_head", head),
+			zap.Int64("lowest_checkpoint", checkpoint),
+		)
+		return
+	}
+
+	if lag < threshold {
+		c.active = false
+		catchUpActive.Set(0)
+		catchUpRemaining.Set(0)
+		catchUpRate.Set(0)
+		catchUpETA.Set(0)
+		s.logger.Info("Indexer caught up",
+			zap.Int64("blocks_remaining", lag),
+			zap.Duration("took", now.Sub(c.startedAt)),
+		)
+		return
+	}
+
+	elapsed := now.Sub(c.startedAt).Seconds()
+	c.status.BlocksRemaining = lag
+	c.status.ETASeconds = nil
+	if elapsed > 0 {
+		c.status.BlocksPerSecond = math.Round(float64(checkpoint-c.startCheckpoint)/elapsed*100) / 100
+		// The lag shrinks by the indexing rate less the rate new blocks arrive
+		if closing := float64(c.startLag-lag) / elapsed; closing > 0 {
+			eta := int64(float64(lag) / closing)
+			c.status.ETASeconds = &eta
+		}
+	}
+
+	catchUpRemaining.Set(float64(lag))
+	catchUpRate.Set(c.status.BlocksPerSecond)
+	if c.status.ETASeconds != nil {
+		catchUpETA.Set(float64(*c.status.ETASeconds))
+	} else {
+		catchUpETA.Set(-1)
+	}
+
+	if logProgress {
+		fields := []zap.Field{
+			zap.Int64("blocks_remaining", lag),
+			zap.Float64("blocks_per_second", c.status.BlocksPerSecond),
+		}
+		if c.status.ETASeconds != nil {
+			fields = append(fields, zap.Duration("eta", time.Duration(*c.status.ETASeconds)*time.Second))
+		}
+		s.logger.Info("Catch-up progress", fields...)
+	}
+}
+
+// catchUpStatus returns the progress of a catch-up under way, or nil when not catching up
+func (s *IndexerService) catchUpStatus() *CatchUpStatus {
+	s.catchUp.mu.Lock()
+	defer s.catchUp.mu.Unlock()
+	if !s.catchUp.active {
+		return nil
+	}
+	status := s.catchUp.status
+	return &status
+}
+
+// runCatchUpReporter logs catch-up progress every CatchUpLogInterval while catching up
+func (s *IndexerService) runCatchUpReporter(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.CatchUpLogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case now := <-ticker.C:
+			s.observeCatchUp(now, true)
+		}
+	}
+}
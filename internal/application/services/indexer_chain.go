@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/chain"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+var _ ChainAdapter = (*ethereum.Fetcher)(nil)
+
+// ChainAdapter is what the indexer needs from a chain: its head, the transfer-like events of a
+// set of tokens in a block range, and decoding of the raw records it archived. *ethereum.Fetcher
+// implements it for EVM chains; adapters for other chain families plug in with WithChainAdapter.
+type ChainAdapter interface {
+	// Family returns the address family of the chain's tokens and holders
+	Family() entities.ChainFamily
+	// GetLatestBlockNumber returns the chain head
+	GetLatestBlockNumber(ctx context.Context) (int64, error)
+	// GetBlockTimestamp returns the time a block was produced
+	GetBlockTimestamp(ctx context.Context, blockNumber int64) (time.Time, error)
+	// FetchTransfers fetches the transfers of the tokens, and any other records the adapter
+	// decodes alongside them, for a range of blocks
+	FetchTransfers(ctx context.Context, tokenAddresses []string, fromBlock, toBlock int64) (*chain.FetchResult, error)
+	// DecodeRawLogs decodes archived raw records as FetchTransfers would have when they were
+	// fetched, returning the transfers of the tokens, other records by event type and the
+	// number of records that failed to decode
+	DecodeRawLogs(raws []entities.RawLog, tokenAddresses []string) ([]entities.Transfer, map[string][]any, int, error)
+}
+
+// WithChainAdapter indexes a chain through adapter instead of the EVM fetcher. The passes that
+// need an EVM node, scoped events and native ETH transfers, are skipped for other chain families.
+func (s *IndexerService) WithChainAdapter(adapter ChainAdapter) *IndexerService {
+	s.chain = adapter
+	if adapter.Family() != entities.ChainFamilyEVM {
+		s.fetcher = nil
+		s.metadataFetcher = nil
+	}
+	return s
+}
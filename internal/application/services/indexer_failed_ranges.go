@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// Failed range policies, see config.IndexerConfig.FailedRangePolicy
+const (
+	FailedRangeHalt = "halt" // Record the range and keep retrying it
+	FailedRangeSkip = "skip" // Record the range and move the checkpoint past it
+)
+
+var failedRangesTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "indexer_failed_ranges_total",
+		Help: "Block ranges that failed INDEXER_RANGE_MAX_RETRIES times in a row, by token and whether they were skipped",
+	},
+	[]string{"token", "action"},
+)
+
+// CheckFailedRangePolicy rejects unknown failed range policies
+func CheckFailedRangePolicy(policy string) error {
+	switch policy {
+	case FailedRangeHalt, FailedRangeSkip:
+		return nil
+	}
+	return fmt.Errorf("unknown failed range policy %q, expected %s or %s", policy, FailedRangeHalt, FailedRangeSkip)
+}
+
+// rangeError is a failure to fetch or store one block range
+type rangeError struct {
+	r   ethereum.BlockRange
+	err error
+}
+
+func (e *rangeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rangeError) Unwrap() error {
+	return e.err
+}
+
+// recordRangeFailure counts a failure of a token's next block range and reports whether the
+// range was skipped. After RangeMaxRetries failures in a row the range is recorded in
+// failed_ranges; under the skip policy the checkpoint then moves past it, otherwise it is retried
+// at every poll as before. Cancellations and panics, which quarantine the token, are not counted.
+func (s *IndexerService) recordRangeFailure(ctx context.Context, tokenAddress string, r ethereum.BlockRange, err error) bool {
+	var p *panicError
+	if s.failedRanges == nil || s.config.RangeMaxRetries <= 0 || r.From > r.To ||
+		errors.Is(err, context.Canceled) || errors.As(err, &p) {
+		return false
+	}
+
+	s.progressMu.Lock()
+	progress := s.tokenProgressLocked(tokenAddress)
+	if progress.failingFrom != r.From {
+		progress.failingFrom, progress.rangeFailures = r.From, 0
+	}
+	progress.rangeFailures++
+	attempts := progress.rangeFailures
+	s.progressMu.Unlock()
+	if attempts < s.config.RangeMaxRetries {
+		return false
+	}
+
+	// A failing range fetched ahead of this one may have cancelled ctx, which must not stop the
+	// range being recorded
+	ctx = context.WithoutCancel(ctx)
+	skip := s.config.FailedRangePolicy == FailedRangeSkip
+	fields := []zap.Field{
+		zap.String("token", tokenAddress),
+		zap.Int64("from", r.From),
+		zap.Int64("to", r.To),
+		zap.Int("attempts", attempts),
+		zap.Error(err),
+	}
+	if recordErr := s.failedRanges.Record(ctx, entities.FailedRange{
+		TokenAddress: tokenAddress,
+		FromBlock:    r.From,
+		ToBlock:      r.To,
+		Attempts:     attempts,
+		LastError:    err.Error(),
+		Skipped:      skip,
+	}); recordErr != nil {
+		s.logger.Error("Failed to record failing block range", append(fields, zap.NamedError("record_error", recordErr))...)
+		return false
+	}
+
+	if !skip {
+		if attempts == s.config.RangeMaxRetries {
+			failedRangesTotal.WithLabelValues(tokenAddress, "recorded").Inc()
+			s.logger.Error("Block range keeps failing, recorded in failed_ranges", fields...)
+		}
+		return false
+	}
+
+	if updateErr := s.stateRepo.UpdateLastBlock(ctx, tokenAddress, r.To); updateErr != nil {
+		s.logger.Error("Failed to skip failing block range", append(fields, zap.NamedError("update_error", updateErr))...)
+		return false
+	}
+	s.recordCheckpoint(tokenAddress, r.To)
+
+	s.progressMu.Lock()
+	progress.rangeFailures = 0
+	s.progressMu.Unlock()
+
+	failedRangesTotal.WithLabelValues(tokenAddress, "skipped").Inc()
+	s.logger.Warn("Skipped block range that keeps failing, recorded in failed_ranges", fields...)
+	return true
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IndexerHealth reports whether the indexer is working, for orchestrators deciding whether to
+// restart it
+type IndexerHealth struct {
+	Status      string            `json:"status"` // healthy or unhealthy
+	Timestamp   string            `json:"timestamp"`
+	Checks      map[string]string `json:"checks"`
+	LagBlocks   *int64            `json:"lag_blocks,omitempty"` // Chain head minus the lowest token checkpoint
+	LastPassAt  string            `json:"last_pass_at,omitempty"`
+	LastError   string            `json:"last_error,omitempty"`
+	LastErrorAt string            `json:"last_error_at,omitempty"`
+}
+
+// Fail marks a check as failed and the indexer as unhealthy
+func (h *IndexerHealth) Fail(check, reason string) {
+	h.Status = "unhealthy"
+	h.Checks[check] = "unhealthy: " + reason
+}
+
+// recordPass records the outcome of an indexing loop pass, nil when it succeeded
+func (s *IndexerService) recordPass(err error) {
+	s.metrics.mu.Lock()
+	defer s.metrics.mu.Unlock()
+
+	if err == nil {
+		s.metrics.ConsecutiveFailures = 0
+		return
+	}
+	s.metrics.ConsecutiveFailures++
+	s.metrics.LastPassError = err.Error()
+	s.metrics.LastPassErrorAt = time.Now()
+}
+
+// CheckHealth checks that the RPC node answers and that the indexing loop is not stuck. The loop
+// is unhealthy after HealthMaxFailures failed passes in a row, when no pass finished within
+// HealthStallAfter, or while more than HealthMaxLag blocks behind the chain head without a
+// checkpoint advancing within HealthStallAfter; a catch-up making progress stays healthy.
+func (s *IndexerService) CheckHealth(ctx context.Context) *IndexerHealth {
+	now := time.Now()
+	health := &IndexerHealth{
+		Status:    "healthy",
+		Timestamp: now.UTC().Format(time.RFC3339),
+		Checks:    make(map[string]string),
+	}
+
+	if _, err := s.chain.GetLatestBlockNumber(ctx); err != nil {
+		health.Fail("rpc", err.Error())
+	} else {
+		health.Checks["rpc"] = "healthy"
+	}
+
+	metrics := s.GetMetrics()
+	if !metrics.LastIndexedTime.IsZero() {
+		health.LastPassAt = metrics.LastIndexedTime.UTC().Format(time.RFC3339)
+	}
+	if metrics.LastPassError != "" {
+		health.LastError = metrics.LastPassError
+		health.LastErrorAt = metrics.LastPassErrorAt.UTC().Format(time.RFC3339)
+	}
+
+	// Before the first pass or checkpoint advance, time is counted from startup
+	lastPass := metrics.LastIndexedTime
+	if lastPass.IsZero() {
+		lastPass = s.startedAt
+	}
+	lastAdvance := metrics.LastCheckpointAt
+	if lastAdvance.IsZero() {
+		lastAdvance = s.startedAt
+	}
+	stall := s.config.HealthStallAfter
+
+	switch {
+	case s.config.HealthMaxFailures > 0 && metrics.ConsecutiveFailures >= s.config.HealthMaxFailures:
+		health.Fail("indexing", fmt.Sprintf("last %d passes failed", metrics.ConsecutiveFailures))
+	case stall > 0 && now.Sub(lastPass) > stall:
+		health.Fail("indexing", "no pass finished for "+now.Sub(lastPass).Truncate(time.Second).String())
+	default:
+		health.Checks["indexing"] = "healthy"
+	}
+
+	head := s.catchUp.head.Load()
+	if checkpoint, ok := s.lowestCheckpoint(); ok && head > 0 {
+		lag := max(head-checkpoint, 0)
+		health.LagBlocks = &lag
+
+		switch {
+		case s.config.HealthMaxLag <= 0 || lag <= s.config.HealthMaxLag:
+			health.Checks["lag"] = "healthy"
+		case stall > 0 && now.Sub(lastAdvance) > stall:
+			health.Fail("lag", fmt.Sprintf("%d blocks behind, no checkpoint advanced for %s",
+				lag, now.Sub(lastAdvance).Truncate(time.Second)))
+		default:
+			health.Checks["lag"] = fmt.Sprintf("catching up: %d blocks behind", lag)
+		}
+	}
+
+	return health
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+var idleTokens = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "indexer_idle_tokens",
+	Help: "Tokens without transfers for INDEXER_IDLE_AFTER whose polls are backed off",
+})
+
+// scheduleTokens splits the tokens into those polled as usual and the idle tokens whose backoff
+// has elapsed. A token is idle once it is caught up and has had no transfers for IdleAfter; idle
+// tokens skip polls, waiting twice as long after every empty poll up to IdlePollMax.
+func (s *IndexerService) scheduleTokens(tokenAddresses []string, head int64, now time.Time) (busy, idleDue []string) {
+	if s.config.IdleAfter <= 0 {
+		return tokenAddresses, nil
+	}
+
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+
+	idle := 0
+	for _, tokenAddress := range tokenAddresses {
+		p := s.tokenProgressLocked(tokenAddress)
+		if p.lastActivity.IsZero() {
+			// Activity before startup is unknown, so every token starts out active
+			p.lastActivity = now
+		}
+
+		// A token still catching up keeps polling, or the backoff would slow the catch-up
+		caughtUp := p.hasCheckpoint &&
+			ethereum.ConfirmedBlock(head, s.config.ConfirmationsFor(tokenAddress))-p.checkpoint <= int64(s.config.BatchSize)
+		if !caughtUp || now.Sub(p.lastActivity) < s.config.IdleAfter {
+			busy = append(busy, tokenAddress)
+			continue
+		}
+
+		idle++
+		if !now.Before(p.nextPoll) {
+			idleDue = append(idleDue, tokenAddress)
+		}
+	}
+	idleTokens.Set(float64(idle))
+
+	return busy, idleDue
+}
+
+// scheduleIdlePolls backs off the next poll of the idle tokens just polled; a token that had
+// transfers in the poll was already reset by recordActivity
+func (s *IndexerService) scheduleIdlePolls(tokenAddresses []string, now time.Time) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+
+	for _, tokenAddress := range tokenAddresses {
+		p := s.tokenProgressLocked(tokenAddress)
+		if now.Sub(p.lastActivity) < s.config.IdleAfter {
+			continue
+		}
+
+		if !p.idle {
+			p.idle = true
+			s.logger.Info("Token is idle, backing off polls",
+				zap.String("token", tokenAddress),
+				zap.Time("last_activity", p.lastActivity),
+			)
+		}
+		p.idleBackoff = min(max(2*p.idleBackoff, 2*s.config.PollInterval), max(s.config.IdlePollMax, s.config.PollInterval))
+		p.nextPoll = now.Add(p.idleBackoff)
+	}
+}
+
+// recordActivity marks a token as having transfers, returning an idle token to every poll
+func (s *IndexerService) recordActivity(tokenAddress string, now time.Time) {
+	if s.config.IdleAfter <= 0 {
+		return
+	}
+
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+
+	p := s.tokenProgressLocked(tokenAddress)
+	if p.idle {
+		s.logger.Info("Idle token has transfers again",
+			zap.String("token", tokenAddress),
+			zap.Duration("idle_for", now.Sub(p.lastActivity)),
+		)
+	}
+	p.lastActivity = now
+	p.idle = false
+	p.idleBackoff = 0
+	p.nextPoll = time.Time{}
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// Pipelines, the label of their stage metrics
+const (
+	pipelineLive     = "live"     // One token's live indexing
+	pipelineCombined = "combined" // Live indexing of all tokens with one getLogs call per range
+	pipelineBackfill = "backfill"
+)
+
+var (
+	pipelineStageSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "indexer_pipeline_stage_seconds",
+			Help:    "Time to fetch or to store one block range, by pipeline (live, combined or backfill) and stage (fetch or store)",
+			Buckets: []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"pipeline", "stage"},
+	)
+	pipelineStageBlocked = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "indexer_pipeline_stage_blocked_seconds_total",
+			Help: "Time a stage waited on the other: fetch for room in the queue, store for the next fetched range",
+		},
+		[]string{"pipeline", "stage"},
+	)
+	pipelineQueued = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "indexer_pipeline_queued",
+			Help: "Fetched block ranges waiting for the store stage, by pipeline",
+		},
+		[]string{"pipeline"},
+	)
+)
+
+// pipeline runs block ranges through a fetch stage and a store stage connected by a bounded
+// queue, so fetching the next ranges from RPC overlaps storing the current one in the database
+// instead of waiting for it. The fetch stage passes results on in range order; with a single
+// storer they are stored, and checkpoints advance, in that order too.
+type pipeline[T any] struct {
+	name string // Label of the stage metrics
+
+	// fetchers is how many ranges are fetched at once, re-read before each range starts so it
+	// follows the worker level as the lag shrinks
+	fetchers func() int
+	storers  int // Ranges stored at once
+	depth    int // Fetched ranges queued for the store stage, bounding the results held in memory
+
+	// before, when set, is called before each fetch starts, one range at a time, to throttle the
+	// fetch stage
+	before func(ctx context.Context) error
+	fetch  func(ctx context.Context, r ethereum.BlockRange) (T, error)
+	store  func(ctx context.Context, r ethereum.BlockRange, result T) error
+}
+
+// fetched is a range's fetch result on its way to the store stage
+type fetched[T any] struct {
+	r      ethereum.BlockRange
+	result T
+	err    error
+}
+
+// run fetches and stores the ranges. The first failure stops both stages and is returned as a
+// *rangeError naming the range, a panic in either stage as a *panicError within it; with a
+// single storer every range before it has been stored.
+// A failed fetch only stops the pipeline once the ranges before it are stored.
+func (p *pipeline[T]) run(ctx context.Context, ranges []ethereum.BlockRange) error {
+	g, ctx := errgroup.WithContext(ctx)
+	queue := make(chan fetched[T], max(p.depth, 1))
+	queued := pipelineQueued.WithLabelValues(p.name)
+
+	g.Go(func() error {
+		defer close(queue)
+		return p.produce(ctx, ranges, queue, queued)
+	})
+
+	for range max(p.storers, 1) {
+		g.Go(func() error {
+			for {
+				waited := time.Now()
+				item, ok := <-queue
+				if !ok {
+					return nil
+				}
+				pipelineStageBlocked.WithLabelValues(p.name, "store").Add(time.Since(waited).Seconds())
+				queued.Dec()
+
+				if item.err != nil {
+					return &rangeError{r: item.r, err: item.err}
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				started := time.Now()
+				err := recoverPanic(func() error {
+					return p.store(ctx, item.r, item.result)
+				})
+				pipelineStageSeconds.WithLabelValues(p.name, "store").Observe(time.Since(started).Seconds())
+				if err != nil {
+					return &rangeError{r: item.r, err: err}
+				}
+			}
+		})
+	}
+
+	err := g.Wait()
+	// Ranges fetched after a failure are dropped
+	for range queue {
+		queued.Dec()
+	}
+	return err
+}
+
+// produce fetches ranges concurrently, up to fetchers() at a time, and queues the results in
+// range order. It stops after queueing a failed fetch, or when ctx is done, waiting for the
+// fetches still running.
+func (p *pipeline[T]) produce(ctx context.Context, ranges []ethereum.BlockRange, queue chan<- fetched[T], queued prometheus.Gauge) error {
+	type pending struct {
+		result T
+		err    error
+		done   chan struct{}
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fetches := make([]*pending, len(ranges))
+	next := 0
+	for i, r := range ranges {
+		for next < len(ranges) && next < i+max(p.fetchers(), 1) {
+			if p.before != nil {
+				if err := p.before(ctx); err != nil {
+					return err
+				}
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			f := &pending{done: make(chan struct{})}
+			fetches[next] = f
+			wg.Add(1)
+			go func(r ethereum.BlockRange) {
+				defer wg.Done()
+				defer close(f.done)
+				started := time.Now()
+				f.err = recoverPanic(func() error {
+					var err error
+					f.result, err = p.fetch(ctx, r)
+					return err
+				})
+				pipelineStageSeconds.WithLabelValues(p.name, "fetch").Observe(time.Since(started).Seconds())
+			}(ranges[next])
+			next++
+		}
+
+		f := fetches[i]
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		fetches[i] = nil
+
+		waited := time.Now()
+		queued.Inc()
+		select {
+		case queue <- fetched[T]{r: r, result: f.result, err: f.err}:
+		case <-ctx.Done():
+			queued.Dec()
+			return ctx.Err()
+		}
+		pipelineStageBlocked.WithLabelValues(p.name, "fetch").Add(time.Since(waited).Seconds())
+
+		if f.err != nil {
+			return nil
+		}
+	}
+	return nil
+}
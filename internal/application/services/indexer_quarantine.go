@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	tokenPanics = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "indexer_token_panics_total",
+			Help: "Panics recovered while indexing a token, by token",
+		},
+		[]string{"token"},
+	)
+	quarantinedTokens = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "indexer_quarantined_tokens",
+		Help: "Tokens skipped after a panic until INDEXER_QUARANTINE_COOLDOWN has passed",
+	})
+)
+
+// panicError is a panic recovered while indexing, returned as an error
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// recoverPanic runs fn, returning a panic in it as a *panicError
+func recoverPanic(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &panicError{value: r, stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
+
+// quarantine reports whether err is a recovered panic and, if so, logs it and skips the token
+// for QuarantineCooldown, so one bad contract does not fail every pass for all tokens
+func (s *IndexerService) quarantine(tokenAddress string, err error) bool {
+	var p *panicError
+	if !errors.As(err, &p) {
+		return false
+	}
+
+	tokenPanics.WithLabelValues(tokenAddress).Inc()
+	s.logger.Error("Recovered panic while indexing token",
+		zap.String("token", tokenAddress),
+		zap.Any("panic", p.value),
+		zap.ByteString("stack", p.stack),
+	)
+	if s.config.QuarantineCooldown <= 0 {
+		return true
+	}
+
+	until := time.Now().Add(s.config.QuarantineCooldown)
+	s.progressMu.Lock()
+	s.tokenProgressLocked(tokenAddress).quarantinedUntil = until
+	s.progressMu.Unlock()
+	s.logger.Warn("Quarantined token", zap.String("token", tokenAddress), zap.Time("until", until))
+	return true
+}
+
+// skipQuarantined returns the tokens not in quarantine, releasing those whose cool-down is over
+func (s *IndexerService) skipQuarantined(tokenAddresses []string, now time.Time) []string {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+
+	kept := make([]string, 0, len(tokenAddresses))
+	for _, tokenAddress := range tokenAddresses {
+		p, ok := s.progress[tokenAddress]
+		if ok && !p.quarantinedUntil.IsZero() {
+			if now.Before(p.quarantinedUntil) {
+				continue
+			}
+			p.quarantinedUntil = time.Time{}
+			s.logger.Info("Released token from quarantine", zap.String("token", tokenAddress))
+		}
+		kept = append(kept, tokenAddress)
+	}
+	quarantinedTokens.Set(float64(len(tokenAddresses) - len(kept)))
+	return kept
+}
+
+// indexPass runs one pass of the indexing loop, recovering a panic outside any token's
+// indexing so the loop keeps running
+func (s *IndexerService) indexPass(ctx context.Context) {
+	err := recoverPanic(func() error {
+		s.indexNewBlocks(ctx)
+		return nil
+	})
+	var p *panicError
+	if errors.As(err, &p) {
+		s.logger.Error("Recovered panic in indexing loop", zap.Any("panic", p.value), zap.ByteString("stack", p.stack))
+		s.incrementErrorCount()
+		s.recordPass(err)
+	}
+}
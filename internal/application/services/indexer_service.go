@@ -2,50 +2,117 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/bimakw/chain-indexer/internal/config"
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
 	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/chain"
 	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
 )
 
 // IndexerService orchestrates the indexing process
 type IndexerService struct {
-	fetcher         *ethereum.Fetcher
-	ethClient       *ethereum.Client
-	metadataFetcher *ethereum.MetadataFetcher
-	tokenRepo       repositories.TokenRepository
-	transferRepo    repositories.TransferRepository
-	stateRepo       repositories.IndexerStateRepository
-	config          config.IndexerConfig
-	logger          *zap.Logger
-	metrics         *IndexerMetrics
-	stopCh          chan struct{}
-	wg              sync.WaitGroup
+	chain            ChainAdapter
+	fetcher          *ethereum.Fetcher // EVM only passes, nil on other chain families
+	metadataFetcher  *ethereum.MetadataFetcher
+	tokenRepo        repositories.TokenRepository
+	transferRepo     repositories.TransferRepository
+	stateRepo        repositories.IndexerStateRepository
+	config           config.IndexerConfig
+	logger           *zap.Logger
+	metrics          *IndexerMetrics
+	progressMu       sync.RWMutex
+	progress         map[string]*tokenProgress
+	eventRepos       map[string]repositories.EventRepository
+	dailyStatsRepo   repositories.DailyStatsRepository
+	walletStats      repositories.WalletStatsRepository
+	activeAddrs      repositories.ActiveAddressRepository
+	alerts           *AlertService
+	analytics        repositories.AnalyticsRepository
+	ethTransferRepo  repositories.EthTransferRepository
+	rawLogRepo       repositories.RawLogRepository
+	scopedState      repositories.ScopedEventStateRepository
+	indexedEvents    *cache.RedisCache
+	failedRanges     repositories.FailedRangeRepository
+	backfillInterval time.Duration // between backfill ranges, 0 when backfills are not paced
+	backfillYieldTo  []string      // live tokens a low priority backfill waits for
+	activeMu         sync.Mutex
+	activeTokens     []string // nil until first loaded by activeTokenAddresses
+	activeLoadedAt   time.Time
+	catchUp          catchUp
+	workers          atomic.Int64 // Current worker level, see adjustWorkers
+	startedAt        time.Time
+	stopCh           chan struct{}
+	wg               sync.WaitGroup
 }
 
+// tokenProgress tracks per-token state that is not persisted in indexer_state
+type tokenProgress struct {
+	lastError     string
+	lastErrorAt   time.Time
+	backfillBlock int64
+	checkpoint    int64 // Last block indexed live, for catch-up tracking and autoscaling
+	hasCheckpoint bool
+	lastActivity  time.Time // Last transfer seen live, or startup; drives idle poll backoff
+	idle          bool
+	idleBackoff   time.Duration
+	nextPoll      time.Time
+
+	quarantinedUntil time.Time // Skipped until then after a panic
+	failingFrom      int64     // First block of the range that failed last, see recordRangeFailure
+	rangeFailures    int       // Failures in a row of the range starting at failingFrom
+}
+
+// Live indexing only fetches blocks past a token's checkpoint, so a batch that is mostly
+// transfers already stored means the checkpoint went backwards. Backfills and replays overlap
+// stored data by design and are not judged.
+const (
+	duplicateWarnRatio    = 0.5
+	duplicateWarnMinBatch = 10 // smaller batches are too noisy to judge
+)
+
+var duplicateTransfers = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "indexer_duplicate_transfers_total",
+		Help: "Transfers skipped on insert because they were already stored, by token",
+	},
+	[]string{"token"},
+)
+
 // IndexerMetrics tracks indexer performance
 type IndexerMetrics struct {
 	mu                sync.RWMutex
 	BlocksIndexed     int64
 	TransfersIndexed  int64
+	DuplicatesSkipped int64
 	LastIndexedBlock  int64
 	LastIndexedTime   time.Time
 	IndexingLatencyMs int64
 	ErrorCount        int64
+
+	// Outcome of the indexing loop's passes, for health checks
+	ConsecutiveFailures int
+	LastPassError       string
+	LastPassErrorAt     time.Time
+	LastCheckpointAt    time.Time // Last time a token's checkpoint advanced
 }
 
 // NewIndexerService creates a new indexer service
 func NewIndexerService(
 	fetcher *ethereum.Fetcher,
-	ethClient *ethereum.Client,
 	metadataFetcher *ethereum.MetadataFetcher,
 	tokenRepo repositories.TokenRepository,
 	transferRepo repositories.TransferRepository,
@@ -53,9 +120,8 @@ func NewIndexerService(
 	cfg config.IndexerConfig,
 	logger *zap.Logger,
 ) *IndexerService {
-	return &IndexerService{
+	s := &IndexerService{
 		fetcher:         fetcher,
-		ethClient:       ethClient,
 		metadataFetcher: metadataFetcher,
 		tokenRepo:       tokenRepo,
 		transferRepo:    transferRepo,
@@ -63,8 +129,89 @@ func NewIndexerService(
 		config:          cfg,
 		logger:          logger,
 		metrics:         &IndexerMetrics{},
+		progress:        make(map[string]*tokenProgress),
+		eventRepos:      make(map[string]repositories.EventRepository),
 		stopCh:          make(chan struct{}),
 	}
+	if fetcher != nil {
+		s.chain = fetcher
+	}
+	return s
+}
+
+// RegisterEventRepository sets the repository that stores records of a registered event type.
+// It must be called before Start.
+func (s *IndexerService) RegisterEventRepository(eventName string, repo repositories.EventRepository) {
+	s.eventRepos[eventName] = repo
+}
+
+// WithDailyStats keeps the token_daily_stats rollup up to date for every day transfers are written to
+func (s *IndexerService) WithDailyStats(repo repositories.DailyStatsRepository) *IndexerService {
+	s.dailyStatsRepo = repo
+	return s
+}
+
+// WithWalletStats keeps the per wallet and token transfer totals up to date as transfers are written
+func (s *IndexerService) WithWalletStats(repo repositories.WalletStatsRepository) *IndexerService {
+	s.walletStats = repo
+	return s
+}
+
+// WithActiveAddresses records the senders and receivers of indexed transfers in per-day active address sketches
+func (s *IndexerService) WithActiveAddresses(repo repositories.ActiveAddressRepository) *IndexerService {
+	s.activeAddrs = repo
+	return s
+}
+
+// WithAlerts evaluates alert rules against each batch of newly indexed transfers.
+// Backfills and reindexes are not evaluated, so historical data never triggers notifications.
+func (s *IndexerService) WithAlerts(alerts *AlertService) *IndexerService {
+	s.alerts = alerts
+	return s
+}
+
+// WithEthTransfers captures native ETH transfers from block traces using the configured
+// trace method. Tracing keeps its own checkpoint, separate from the token checkpoints.
+func (s *IndexerService) WithEthTransfers(repo repositories.EthTransferRepository) *IndexerService {
+	s.ethTransferRepo = repo
+	return s
+}
+
+// WithRawLogs archives every fetched log, so Replay can re-decode a range without RPC.
+// The fetcher must be configured with StoreRawLogs for logs to be captured.
+func (s *IndexerService) WithRawLogs(repo repositories.RawLogRepository) *IndexerService {
+	s.rawLogRepo = repo
+	return s
+}
+
+// WithScopedEvents indexes the events registered with their own addresses, such as DEX pool
+// swaps, in a pass of their own: one getLogs call per block range for all of them, tracked by
+// their own checkpoint, instead of repeating them in every token's fetch.
+func (s *IndexerService) WithScopedEvents(repo repositories.ScopedEventStateRepository) *IndexerService {
+	s.scopedState = repo
+	return s
+}
+
+// WithAnalytics mirrors every transfer write to the analytics store.
+// PostgreSQL stays the system of record: mirror failures are logged and don't stop indexing,
+// and `chain-indexer reindex` rewrites a range in both stores.
+func (s *IndexerService) WithAnalytics(repo repositories.AnalyticsRepository) *IndexerService {
+	s.analytics = repo
+	return s
+}
+
+// WithIndexedEvents publishes the address of each token new transfers were stored for on
+// cache.IndexedTokensChannel, where API processes pick it up to warm their caches
+func (s *IndexerService) WithIndexedEvents(c *cache.RedisCache) *IndexerService {
+	s.indexedEvents = c
+	return s
+}
+
+// WithFailedRanges records block ranges that fail RangeMaxRetries times in a row, moving the
+// checkpoint past them under the skip policy
+func (s *IndexerService) WithFailedRanges(repo repositories.FailedRangeRepository) *IndexerService {
+	s.failedRanges = repo
+	return s
 }
 
 // Start begins the indexing process
@@ -72,9 +219,10 @@ func (s *IndexerService) Start(ctx context.Context) error {
 	s.logger.Info("Starting indexer service",
 		zap.Strings("tokens", s.config.TokenAddresses),
 	)
+	s.startedAt = time.Now()
 
 	// Initialize tokens in database
-	if err := s.initializeTokens(ctx); err != nil {
+	if err := s.InitializeTokens(ctx); err != nil {
 		return fmt.Errorf("failed to initialize tokens: %w", err)
 	}
 
@@ -82,6 +230,11 @@ func (s *IndexerService) Start(ctx context.Context) error {
 	s.wg.Add(1)
 	go s.runIndexingLoop(ctx)
 
+	if s.config.CatchUpThreshold > 0 && s.config.CatchUpLogInterval > 0 {
+		s.wg.Add(1)
+		go s.runCatchUpReporter(ctx)
+	}
+
 	return nil
 }
 
@@ -96,11 +249,24 @@ func (s *IndexerService) Stop() {
 func (s *IndexerService) GetMetrics() IndexerMetrics {
 	s.metrics.mu.RLock()
 	defer s.metrics.mu.RUnlock()
-	return *s.metrics
+	return IndexerMetrics{
+		BlocksIndexed:     s.metrics.BlocksIndexed,
+		TransfersIndexed:  s.metrics.TransfersIndexed,
+		DuplicatesSkipped: s.metrics.DuplicatesSkipped,
+		LastIndexedBlock:  s.metrics.LastIndexedBlock,
+		LastIndexedTime:   s.metrics.LastIndexedTime,
+		IndexingLatencyMs: s.metrics.IndexingLatencyMs,
+		ErrorCount:        s.metrics.ErrorCount,
+
+		ConsecutiveFailures: s.metrics.ConsecutiveFailures,
+		LastPassError:       s.metrics.LastPassError,
+		LastPassErrorAt:     s.metrics.LastPassErrorAt,
+		LastCheckpointAt:    s.metrics.LastCheckpointAt,
+	}
 }
 
-// initializeTokens ensures all configured tokens exist in the database
-func (s *IndexerService) initializeTokens(ctx context.Context) error {
+// InitializeTokens ensures all configured tokens exist in the database, fetching metadata for new ones
+func (s *IndexerService) InitializeTokens(ctx context.Context) error {
 	for _, addr := range s.config.TokenAddresses {
 		addr = strings.ToLower(addr)
 
@@ -147,6 +313,7 @@ func (s *IndexerService) initializeTokens(ctx context.Context) error {
 				Name:     name,
 				Symbol:   symbol,
 				Decimals: int(decimals),
+				Active:   true,
 			}
 
 			if err := s.tokenRepo.Upsert(ctx, token); err != nil {
@@ -156,7 +323,7 @@ func (s *IndexerService) initializeTokens(ctx context.Context) error {
 			// Initialize indexer state
 			state := &entities.IndexerState{
 				TokenAddress:     addr,
-				LastIndexedBlock: 0,
+				LastIndexedBlock: max(s.config.StartBlock-1, 0),
 			}
 			if err := s.stateRepo.Upsert(ctx, state); err != nil {
 				return fmt.Errorf("failed to create indexer state for %s: %w", addr, err)
@@ -177,7 +344,7 @@ func (s *IndexerService) runIndexingLoop(ctx context.Context) {
 	defer ticker.Stop()
 
 	// Run immediately on start
-	s.indexNewBlocks(ctx)
+	s.indexPass(ctx)
 
 	for {
 		select {
@@ -186,7 +353,7 @@ func (s *IndexerService) runIndexingLoop(ctx context.Context) {
 		case <-s.stopCh:
 			return
 		case <-ticker.C:
-			s.indexNewBlocks(ctx)
+			s.indexPass(ctx)
 		}
 	}
 }
@@ -195,38 +362,157 @@ func (s *IndexerService) runIndexingLoop(ctx context.Context) {
 func (s *IndexerService) indexNewBlocks(ctx context.Context) {
 	startTime := time.Now()
 
-	// Get safe block number (latest - confirmations)
-	safeBlock, err := s.fetcher.GetSafeBlockNumber(ctx)
+	// Tokens index up to the head minus their own confirmations; the rest uses the chain's default
+	head, err := s.chain.GetLatestBlockNumber(ctx)
 	if err != nil {
-		s.logger.Error("Failed to get safe block number", zap.Error(err))
+		s.logger.Error("Failed to get latest block number", zap.Error(err))
 		s.incrementErrorCount()
+		s.recordPass(err)
 		return
 	}
+	safeBlock := ethereum.ConfirmedBlock(head, s.config.BlockConfirmations)
+	s.catchUp.head.Store(head)
 
-	// Process each token
-	g, gCtx := errgroup.WithContext(ctx)
-	g.SetLimit(s.config.WorkerCount)
-
-	for _, tokenAddr := range s.config.TokenAddresses {
-		normalizedAddr := strings.ToLower(tokenAddr)
-		g.Go(func() error {
-			return s.indexTokenTransfers(gCtx, normalizedAddr, safeBlock)
-		})
+	// Scoped events go first: on a fresh start they begin after the lowest token checkpoint
+	var passErrs []error
+	if s.scopedState != nil && s.fetcher != nil && s.fetcher.HasScopedEvents() {
+		if err := s.indexScopedEvents(ctx, safeBlock); err != nil {
+			s.logger.Error("Error indexing scoped events", zap.Error(err))
+			s.incrementErrorCount()
+			passErrs = append(passErrs, err)
+		}
 	}
 
-	if err := g.Wait(); err != nil {
+	tokenAddresses, err := s.activeTokenAddresses(ctx)
+	if err == nil {
+		tokenAddresses = s.skipQuarantined(tokenAddresses, startTime)
+
+		// Idle tokens whose backoff has elapsed share one combined query, so transfers for any of
+		// them return it to every poll at once
+		busy, idleDue := s.scheduleTokens(tokenAddresses, head, startTime)
+		if s.config.CombinedFetch {
+			err = s.indexAllTokens(ctx, append(busy, idleDue...), head)
+		} else {
+			err = s.indexEachToken(ctx, busy, head)
+			if len(idleDue) > 0 {
+				err = errors.Join(err, s.indexAllTokens(ctx, idleDue, head))
+			}
+		}
+		s.scheduleIdlePolls(idleDue, startTime)
+	}
+	if err != nil {
 		s.logger.Error("Error indexing transfers", zap.Error(err))
 		s.incrementErrorCount()
+		passErrs = append(passErrs, err)
+	}
+
+	if s.ethTransferRepo != nil && s.fetcher != nil {
+		if err := s.indexEthTransfers(ctx, safeBlock); err != nil {
+			s.logger.Error("Error indexing ETH transfers", zap.Error(err))
+			s.incrementErrorCount()
+			passErrs = append(passErrs, err)
+		}
 	}
 
 	s.metrics.mu.Lock()
 	s.metrics.IndexingLatencyMs = time.Since(startTime).Milliseconds()
 	s.metrics.LastIndexedTime = time.Now()
 	s.metrics.mu.Unlock()
+	s.recordPass(errors.Join(passErrs...))
+
+	s.observeCatchUp(time.Now(), false)
+}
+
+// tokenAddresses returns the configured token addresses, lowercased
+func (s *IndexerService) tokenAddresses() []string {
+	addresses := make([]string, len(s.config.TokenAddresses))
+	for i, tokenAddr := range s.config.TokenAddresses {
+		addresses[i] = strings.ToLower(tokenAddr)
+	}
+	return addresses
+}
+
+// activeTokenAddresses returns the configured token addresses, lowercased, without the
+// deactivated ones. The set is reloaded once ActiveTokensRefresh has passed, so deactivation
+// takes effect without a restart and a re-activated token resumes from its stored checkpoint.
+// If a reload fails, the previous set is used until the next attempt.
+func (s *IndexerService) activeTokenAddresses(ctx context.Context) ([]string, error) {
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+
+	if s.activeTokens != nil && time.Since(s.activeLoadedAt) < s.config.ActiveTokensRefresh {
+		return s.activeTokens, nil
+	}
+
+	addresses := s.tokenAddresses()
+	tokens, err := s.tokenRepo.GetByAddresses(ctx, addresses)
+	if err != nil {
+		if s.activeTokens != nil {
+			s.logger.Warn("Failed to reload active tokens, using previous set", zap.Error(err))
+			return s.activeTokens, nil
+		}
+		return nil, fmt.Errorf("failed to get tokens: %w", err)
+	}
+
+	inactive := make(map[string]bool)
+	for _, t := range tokens {
+		if !t.Active {
+			inactive[t.Address] = true
+		}
+	}
+
+	active := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		if inactive[addr] {
+			s.logger.Debug("Skipping deactivated token", zap.String("token", addr))
+			continue
+		}
+		active = append(active, addr)
+	}
+
+	s.activeTokens = active
+	s.activeLoadedAt = time.Now()
+	return active, nil
+}
+
+// indexEachToken indexes the tokens concurrently up to their confirmed blocks below head,
+// each with its own getLogs calls
+func (s *IndexerService) indexEachToken(ctx context.Context, tokenAddresses []string, head int64) error {
+	// Workers go to tokens first; any left over let each token fetch ranges ahead
+	parallel := max(min(s.adjustWorkers(), len(tokenAddresses)), 1)
+	var g errgroup.Group
+	g.SetLimit(parallel)
+
+	// Errors are collected rather than returned to the group, so a token failing, or panicking
+	// and being quarantined, never cancels the others
+	var mu sync.Mutex
+	var errs []error
+	for _, tokenAddress := range tokenAddresses {
+		toBlock := ethereum.ConfirmedBlock(head, s.config.ConfirmationsFor(tokenAddress))
+		g.Go(func() error {
+			err := recoverPanic(func() error {
+				return s.indexTokenTransfers(ctx, tokenAddress, toBlock, parallel)
+			})
+			if err == nil {
+				return nil
+			}
+			s.recordTokenError(tokenAddress, err)
+			if s.quarantine(tokenAddress, err) {
+				err = fmt.Errorf("token %s: %w", tokenAddress, err)
+			}
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+	return errors.Join(errs...)
 }
 
 // indexTokenTransfers indexes transfers for a single token
-func (s *IndexerService) indexTokenTransfers(ctx context.Context, tokenAddress string, toBlock int64) error {
+func (s *IndexerService) indexTokenTransfers(ctx context.Context, tokenAddress string, toBlock int64, parallel int) error {
 	// Get current state
 	state, err := s.stateRepo.Get(ctx, tokenAddress)
 	if err != nil {
@@ -236,6 +522,7 @@ func (s *IndexerService) indexTokenTransfers(ctx context.Context, tokenAddress s
 	if state == nil {
 		return fmt.Errorf("indexer state not found for %s", tokenAddress)
 	}
+	s.recordCheckpoint(tokenAddress, state.LastIndexedBlock)
 
 	fromBlock := state.LastIndexedBlock + 1
 	if fromBlock > toBlock {
@@ -243,38 +530,43 @@ func (s *IndexerService) indexTokenTransfers(ctx context.Context, tokenAddress s
 		return nil
 	}
 
-	// Split into batches
+	// Split into batches, fetched ahead while the indexer has workers to spare and while the
+	// previous batch is stored
 	ranges := ethereum.SplitBlockRange(fromBlock, toBlock, s.config.BatchSize)
-
-	for _, r := range ranges {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
+	p := &pipeline[*chain.FetchResult]{
+		name:     pipelineLive,
+		fetchers: func() int { return s.rangeWindow(parallel) },
+		storers:  1,
+		depth:    s.config.PipelineDepth,
+	}
+	p.fetch = func(ctx context.Context, r ethereum.BlockRange) (*chain.FetchResult, error) {
+		result, err := s.chain.FetchTransfers(ctx, []string{tokenAddress}, r.From, r.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch transfers for blocks %d-%d: %w", r.From, r.To, err)
+		}
+		return result, nil
+	}
+	p.store = func(ctx context.Context, r ethereum.BlockRange, result *chain.FetchResult) error {
+		if err := s.storeRawLogs(ctx, result.RawLogs); err != nil {
+			return err
 		}
 
-		result, err := s.fetcher.FetchTransfers(ctx, []string{tokenAddress}, r.From, r.To)
+		stored, err := s.storeTokenTransfers(ctx, tokenAddress, r.To, result.Transfers)
 		if err != nil {
-			return fmt.Errorf("failed to fetch transfers for blocks %d-%d: %w", r.From, r.To, err)
+			return err
 		}
 
-		if len(result.Transfers) > 0 {
-			if err := s.transferRepo.BatchInsert(ctx, result.Transfers); err != nil {
-				return fmt.Errorf("failed to insert transfers: %w", err)
-			}
-
-			// Update token stats
-			if err := s.tokenRepo.UpdateStats(ctx, tokenAddress, int64(len(result.Transfers)), r.To); err != nil {
-				s.logger.Warn("Failed to update token stats", zap.Error(err))
-			}
+		if err := s.storeEvents(ctx, result.Events); err != nil {
+			return err
 		}
 
 		// Update checkpoint
 		if err := s.stateRepo.UpdateLastBlock(ctx, tokenAddress, r.To); err != nil {
 			return fmt.Errorf("failed to update checkpoint: %w", err)
 		}
+		s.recordCheckpoint(tokenAddress, r.To)
 
-		s.updateMetrics(r.To-r.From+1, int64(len(result.Transfers)), r.To)
+		s.updateMetrics(r.To-r.From+1, stored, r.To)
 
 		s.logger.Debug("Indexed block range",
 			zap.String("token", tokenAddress),
@@ -282,79 +574,1061 @@ func (s *IndexerService) indexTokenTransfers(ctx context.Context, tokenAddress s
 			zap.Int64("to", r.To),
 			zap.Int("transfers", len(result.Transfers)),
 		)
+		return nil
 	}
 
-	return nil
+	err = p.run(ctx, ranges)
+
+	// The next poll continues after a range skipped for failing too often
+	var failed *rangeError
+	if errors.As(err, &failed) && s.recordRangeFailure(ctx, tokenAddress, failed.r, failed.err) {
+		return nil
+	}
+	return err
 }
 
-// Backfill indexes historical blocks for a token
-func (s *IndexerService) Backfill(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) error {
-	tokenAddress = strings.ToLower(tokenAddress)
+// storeTokenTransfers inserts one token's transfers for a block range ending at toBlock
+// and updates everything derived from them, returning the number of transfers that were new.
+// The caller advances the checkpoint.
+func (s *IndexerService) storeTokenTransfers(ctx context.Context, tokenAddress string, toBlock int64, transfers []entities.Transfer) (int64, error) {
+	if len(transfers) == 0 {
+		return 0, nil
+	}
+	s.recordActivity(tokenAddress, time.Now())
 
-	s.logger.Info("Starting backfill",
-		zap.String("token", tokenAddress),
-		zap.Int64("from_block", fromBlock),
-		zap.Int64("to_block", toBlock),
-	)
+	inserted, err := s.insertTransfers(ctx, tokenAddress, transfers, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert transfers: %w", err)
+	}
 
-	// Mark as backfilling
-	if err := s.stateRepo.SetBackfilling(ctx, tokenAddress, true, &fromBlock, &toBlock); err != nil {
-		return fmt.Errorf("failed to set backfilling state: %w", err)
+	// Update token stats
+	if err := s.tokenRepo.UpdateStats(ctx, tokenAddress, inserted, toBlock); err != nil {
+		s.logger.Warn("Failed to update token stats", zap.Error(err))
 	}
 
-	defer func() {
-		_ = s.stateRepo.SetBackfilling(ctx, tokenAddress, false, nil, nil)
-	}()
+	if s.indexedEvents != nil && inserted > 0 {
+		if err := s.indexedEvents.Publish(ctx, cache.IndexedTokensChannel, tokenAddress); err != nil {
+			s.logger.Warn("Failed to announce indexed transfers", zap.String("token", tokenAddress), zap.Error(err))
+		}
+	}
 
-	ranges := ethereum.SplitBlockRange(fromBlock, toBlock, s.config.BackfillBatchSize)
+	if err := s.refreshDailyStats(ctx, tokenAddress, transfers); err != nil {
+		return 0, err
+	}
 
-	for i, r := range ranges {
+	if err := s.updateWalletStats(ctx, tokenAddress, transfers, inserted); err != nil {
+		return 0, err
+	}
+
+	s.recordActiveAddresses(ctx, tokenAddress, transfers)
+	s.mirrorTransfers(ctx, tokenAddress, transfers)
+
+	if s.alerts != nil {
+		s.alerts.Evaluate(ctx, transfers)
+	}
+
+	return inserted, nil
+}
+
+// insertTransfers stores one token's transfers and accounts for the ones skipped as already
+// stored, returning the number inserted. With warnOnReplay, a batch made up mostly of
+// duplicates is logged as a likely checkpoint regression.
+func (s *IndexerService) insertTransfers(ctx context.Context, tokenAddress string, transfers []entities.Transfer, warnOnReplay bool) (int64, error) {
+	inserted, err := s.transferRepo.BatchInsert(ctx, transfers)
+	if err != nil {
+		return 0, err
+	}
+
+	duplicates := int64(len(transfers)) - inserted
+	if duplicates <= 0 {
+		return inserted, nil
+	}
+
+	duplicateTransfers.WithLabelValues(tokenAddress).Add(float64(duplicates))
+	s.metrics.mu.Lock()
+	s.metrics.DuplicatesSkipped += duplicates
+	s.metrics.mu.Unlock()
+
+	if warnOnReplay && len(transfers) >= duplicateWarnMinBatch && float64(duplicates)/float64(len(transfers)) >= duplicateWarnRatio {
+		s.logger.Warn("Most fetched transfers were already stored; the checkpoint may have regressed",
+			zap.String("token", tokenAddress),
+			zap.Int64("from_block", transfers[0].BlockNumber),
+			zap.Int64("to_block", transfers[len(transfers)-1].BlockNumber),
+			zap.Int64("duplicates", duplicates),
+			zap.Int("transfers", len(transfers)),
+		)
+	}
+
+	return inserted, nil
+}
+
+// indexAllTokens indexes every tracked token, each up to its confirmed block below head, with
+// one getLogs call per block range. Ranges run from the lowest checkpoint to the highest
+// confirmed block; tokens further ahead join once a range passes their checkpoint, and blocks a
+// token has already indexed or that lack its confirmations are dropped before insert. A token
+// whose store step fails sits out the rest of the pass without holding back the others.
+func (s *IndexerService) indexAllTokens(ctx context.Context, tokenAddresses []string, head int64) error {
+	checkpoints := make(map[string]int64, len(tokenAddresses))
+	confirmed := make(map[string]int64, len(tokenAddresses))
+	fromBlock, toBlock := head+1, int64(0)
+	for _, tokenAddress := range tokenAddresses {
+		state, err := s.stateRepo.Get(ctx, tokenAddress)
+		if err != nil {
+			return fmt.Errorf("failed to get indexer state: %w", err)
+		}
+		if state == nil {
+			return fmt.Errorf("indexer state not found for %s", tokenAddress)
+		}
+		s.recordCheckpoint(tokenAddress, state.LastIndexedBlock)
+		tokenTo := ethereum.ConfirmedBlock(head, s.config.ConfirmationsFor(tokenAddress))
+		if state.LastIndexedBlock >= tokenTo {
+			// Already up to date
+			continue
+		}
+		checkpoints[tokenAddress] = state.LastIndexedBlock
+		confirmed[tokenAddress] = tokenTo
+		fromBlock = min(fromBlock, state.LastIndexedBlock+1)
+		toBlock = max(toBlock, tokenTo)
+	}
+
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	// Ranges are fetched ahead with all the workers while earlier ones are stored. A token is
+	// fetched for a range while its checkpoint is below the range's end, which earlier ranges still
+	// being stored cannot change, and no longer after one of its stores failed.
+	type rangeFetch struct {
+		tokens []string
+		result *chain.FetchResult
+	}
+	var mu sync.Mutex
+	var errs []error
+	fetch := func(ctx context.Context, r ethereum.BlockRange) (*rangeFetch, error) {
+		var active []string
+		mu.Lock()
+		for _, tokenAddress := range tokenAddresses {
+			if checkpoint, ok := checkpoints[tokenAddress]; ok && r.From <= confirmed[tokenAddress] && checkpoint < min(r.To, confirmed[tokenAddress]) {
+				active = append(active, tokenAddress)
+			}
+		}
+		mu.Unlock()
+		if len(active) == 0 {
+			return nil, nil
+		}
+
+		result, err := s.chain.FetchTransfers(ctx, active, r.From, r.To)
+		if err != nil {
+			err = fmt.Errorf("failed to fetch transfers for blocks %d-%d: %w", r.From, r.To, err)
+			for _, tokenAddress := range active {
+				s.recordTokenError(tokenAddress, err)
+			}
+			return nil, err
+		}
+		return &rangeFetch{tokens: active, result: result}, nil
+	}
+
+	p := &pipeline[*rangeFetch]{
+		name:     pipelineCombined,
+		fetchers: func() int { return s.rangeWindow(1) },
+		storers:  1,
+		depth:    s.config.PipelineDepth,
+		fetch:    fetch,
+	}
+	p.store = func(ctx context.Context, r ethereum.BlockRange, fetched *rangeFetch) error {
+		if fetched == nil {
+			return nil
+		}
+		result := fetched.result
+
+		if err := s.storeRawLogs(ctx, result.RawLogs); err != nil {
+			return err
+		}
+
+		if err := s.storeEvents(ctx, result.Events); err != nil {
+			return err
+		}
+
+		byToken := groupTransfersByToken(result.Transfers)
+		var stored int64
+		var tokens int
+		for _, tokenAddress := range fetched.tokens {
+			mu.Lock()
+			checkpoint, ok := checkpoints[tokenAddress]
+			mu.Unlock()
+			if !ok {
+				continue // Failed in an earlier range after this one was fetched
+			}
+			tokens++
+
+			end := min(r.To, confirmed[tokenAddress])
+			transfers := transfersThroughBlock(transfersAfterBlock(byToken[tokenAddress], checkpoint), end)
+
+			var inserted int64
+			err := recoverPanic(func() error {
+				var err error
+				if inserted, err = s.storeTokenTransfers(ctx, tokenAddress, end, transfers); err != nil {
+					return err
+				}
+				if err := s.stateRepo.UpdateLastBlock(ctx, tokenAddress, end); err != nil {
+					return fmt.Errorf("failed to update checkpoint: %w", err)
+				}
+				return nil
+			})
+			mu.Lock()
+			if err != nil {
+				delete(checkpoints, tokenAddress)
+			} else {
+				checkpoints[tokenAddress] = end
+			}
+			mu.Unlock()
+			if err != nil {
+				s.recordTokenError(tokenAddress, err)
+				s.quarantine(tokenAddress, err)
+				s.recordRangeFailure(ctx, tokenAddress, ethereum.BlockRange{From: checkpoint + 1, To: end}, err)
+				errs = append(errs, fmt.Errorf("token %s: %w", tokenAddress, err))
+				continue
+			}
+
+			s.recordCheckpoint(tokenAddress, end)
+			stored += inserted
+		}
+
+		s.updateMetrics(r.To-r.From+1, stored, r.To)
+
+		s.logger.Debug("Indexed block range for all tokens",
+			zap.Int("tokens", tokens),
+			zap.Int64("from", r.From),
+			zap.Int64("to", r.To),
+			zap.Int64("transfers", stored),
+		)
+		return nil
+	}
+
+	err := p.run(ctx, ethereum.SplitBlockRange(fromBlock, toBlock, s.config.BatchSize))
+	if err != nil {
+		// Earlier ranges are stored by now, so every token still in the pass failed at this range
+		var failed *rangeError
+		if errors.As(err, &failed) {
+			for _, tokenAddress := range tokenAddresses {
+				if checkpoint, ok := checkpoints[tokenAddress]; ok && failed.r.From <= confirmed[tokenAddress] && checkpoint < min(failed.r.To, confirmed[tokenAddress]) {
+					s.recordRangeFailure(ctx, tokenAddress, ethereum.BlockRange{From: checkpoint + 1, To: min(failed.r.To, confirmed[tokenAddress])}, failed.err)
+				}
+			}
+		}
+		return errors.Join(append(errs, err)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// indexScopedEvents fetches the scoped events, such as DEX pool swaps, since their checkpoint.
+// A fresh start begins after the lowest token checkpoint, where these events were picked up
+// when they still rode along with every token's fetch.
+func (s *IndexerService) indexScopedEvents(ctx context.Context, toBlock int64) error {
+	lastBlock, err := s.scopedState.GetLastBlock(ctx)
+	if err != nil {
+		return err
+	}
+
+	if lastBlock == 0 {
+		lastBlock = toBlock - 1
+		for _, tokenAddress := range s.tokenAddresses() {
+			state, err := s.stateRepo.Get(ctx, tokenAddress)
+			if err != nil {
+				return fmt.Errorf("failed to get indexer state: %w", err)
+			}
+			if state != nil && state.LastIndexedBlock < lastBlock {
+				lastBlock = state.LastIndexedBlock
+			}
+		}
+	}
+
+	if lastBlock >= toBlock {
+		// Already up to date
+		return nil
+	}
+
+	for _, r := range ethereum.SplitBlockRange(lastBlock+1, toBlock, s.config.BatchSize) {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
 
-		result, err := s.fetcher.FetchTransfers(ctx, []string{tokenAddress}, r.From, r.To)
+		result, err := s.fetcher.FetchScopedEvents(ctx, r.From, r.To)
 		if err != nil {
-			return fmt.Errorf("backfill failed at blocks %d-%d: %w", r.From, r.To, err)
+			return fmt.Errorf("failed to fetch scoped events for blocks %d-%d: %w", r.From, r.To, err)
 		}
 
-		if len(result.Transfers) > 0 {
-			if err := s.transferRepo.BatchInsert(ctx, result.Transfers); err != nil {
-				return fmt.Errorf("failed to insert backfill transfers: %w", err)
-			}
+		if err := s.storeRawLogs(ctx, result.RawLogs); err != nil {
+			return err
 		}
 
-		s.logger.Info("Backfill progress",
-			zap.String("token", tokenAddress),
-			zap.Int("batch", i+1),
-			zap.Int("total_batches", len(ranges)),
+		if err := s.storeEvents(ctx, result.Events); err != nil {
+			return err
+		}
+
+		if err := s.scopedState.UpdateLastBlock(ctx, r.To); err != nil {
+			return fmt.Errorf("failed to update scoped event checkpoint: %w", err)
+		}
+
+		s.logger.Debug("Indexed scoped events",
 			zap.Int64("from", r.From),
 			zap.Int64("to", r.To),
-			zap.Int("transfers", len(result.Transfers)),
 		)
 	}
 
-	s.logger.Info("Backfill completed",
-		zap.String("token", tokenAddress),
-		zap.Int64("from_block", fromBlock),
-		zap.Int64("to_block", toBlock),
-	)
+	return nil
+}
+
+// indexEthTransfers traces the blocks since the trace checkpoint and stores their native ETH transfers
+func (s *IndexerService) indexEthTransfers(ctx context.Context, toBlock int64) error {
+	lastBlock, err := s.ethTransferRepo.GetLastBlock(ctx)
+	if err != nil {
+		return err
+	}
+
+	if lastBlock == 0 {
+		// Tracing from genesis is rarely wanted, so a fresh start begins at the configured block or the head
+		lastBlock = toBlock - 1
+		if s.config.TraceStartBlock > 0 {
+			lastBlock = s.config.TraceStartBlock - 1
+		}
+	}
+
+	if lastBlock >= toBlock {
+		// Already up to date
+		return nil
+	}
+
+	for _, r := range ethereum.SplitBlockRange(lastBlock+1, toBlock, s.config.BatchSize) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		transfers, err := s.fetcher.FetchEthTransfers(ctx, r.From, r.To)
+		if err != nil {
+			return fmt.Errorf("failed to trace blocks %d-%d: %w", r.From, r.To, err)
+		}
+
+		if err := s.ethTransferRepo.BatchInsert(ctx, transfers); err != nil {
+			return fmt.Errorf("failed to insert eth transfers: %w", err)
+		}
+
+		if err := s.ethTransferRepo.UpdateLastBlock(ctx, r.To); err != nil {
+			return fmt.Errorf("failed to update trace checkpoint: %w", err)
+		}
+
+		s.logger.Debug("Traced block range",
+			zap.Int64("from", r.From),
+			zap.Int64("to", r.To),
+			zap.Int("eth_transfers", len(transfers)),
+		)
+	}
 
 	return nil
 }
 
-func (s *IndexerService) updateMetrics(blocks, transfers, lastBlock int64) {
-	s.metrics.mu.Lock()
-	defer s.metrics.mu.Unlock()
-	s.metrics.BlocksIndexed += blocks
-	s.metrics.TransfersIndexed += transfers
-	s.metrics.LastIndexedBlock = lastBlock
+// groupTransfersByToken splits the transfers of a multi-token fetch by token address
+func groupTransfersByToken(transfers []entities.Transfer) map[string][]entities.Transfer {
+	byToken := make(map[string][]entities.Transfer)
+	for _, t := range transfers {
+		byToken[t.TokenAddress] = append(byToken[t.TokenAddress], t)
+	}
+	return byToken
 }
 
-func (s *IndexerService) incrementErrorCount() {
-	s.metrics.mu.Lock()
-	defer s.metrics.mu.Unlock()
-	s.metrics.ErrorCount++
+// transfersAfterBlock drops transfers at or below a token's checkpoint
+func transfersAfterBlock(transfers []entities.Transfer, checkpoint int64) []entities.Transfer {
+	kept := transfers[:0:0]
+	for _, t := range transfers {
+		if t.BlockNumber > checkpoint {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// transfersThroughBlock drops transfers above the last block a token may index
+func transfersThroughBlock(transfers []entities.Transfer, lastBlock int64) []entities.Transfer {
+	kept := transfers[:0:0]
+	for _, t := range transfers {
+		if t.BlockNumber <= lastBlock {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// Backfill indexes historical blocks for a token
+func (s *IndexerService) Backfill(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) error {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	s.logger.Info("Starting backfill",
+		zap.String("token", tokenAddress),
+		zap.Int64("from_block", fromBlock),
+		zap.Int64("to_block", toBlock),
+	)
+
+	// An interrupted backfill of the same range resumes after its saved checkpoint
+	startBlock := fromBlock
+	state, err := s.stateRepo.Get(ctx, tokenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to get indexer state: %w", err)
+	}
+	if resume := resumeBlock(state, fromBlock, toBlock); resume > fromBlock {
+		startBlock = resume
+		s.logger.Info("Resuming backfill",
+			zap.String("token", tokenAddress),
+			zap.Int64("from_block", startBlock),
+		)
+	}
+
+	// Mark as backfilling
+	if err := s.stateRepo.SetBackfilling(ctx, tokenAddress, true, &fromBlock, &toBlock); err != nil {
+		return fmt.Errorf("failed to set backfilling state: %w", err)
+	}
+
+	// The range, and with it the checkpoint, is only cleared once the backfill completes.
+	// The state is written even when ctx was cancelled, so an interrupted run is resumable.
+	completed := false
+	defer func() {
+		stateCtx := context.WithoutCancel(ctx)
+		if completed {
+			_ = s.stateRepo.SetBackfilling(stateCtx, tokenAddress, false, nil, nil)
+		} else {
+			_ = s.stateRepo.SetBackfilling(stateCtx, tokenAddress, false, &fromBlock, &toBlock)
+		}
+		s.recordBackfillBlock(tokenAddress, 0)
+	}()
+
+	ranges := ethereum.SplitBlockRange(startBlock, toBlock, s.config.BackfillBatchSize)
+	tracker := newPrefixTracker(ranges)
+
+	// Checkpoints are saved in order: a range finishing late must not move the saved one back
+	var saveMu sync.Mutex
+	saved := startBlock - 1
+	saveCheckpoint := func(ctx context.Context, checkpoint int64) error {
+		saveMu.Lock()
+		defer saveMu.Unlock()
+		if checkpoint <= saved {
+			return nil
+		}
+		if err := s.stateRepo.UpdateBackfillCheckpoint(ctx, tokenAddress, checkpoint); err != nil {
+			return fmt.Errorf("failed to save backfill checkpoint: %w", err)
+		}
+		saved = checkpoint
+		return nil
+	}
+
+	// Ranges are fetched and stored concurrently, but progress only advances over
+	// a contiguous prefix of completed ranges, so it never skips over a gap
+	concurrency := s.config.BackfillConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	rangeIndex := make(map[int64]int, len(ranges))
+	for i, r := range ranges {
+		rangeIndex[r.From] = i
+	}
+	throttle := &backfillThrottle{s: s}
+
+	p := &pipeline[*chain.FetchResult]{
+		name:     pipelineBackfill,
+		fetchers: func() int { return concurrency },
+		storers:  concurrency,
+		depth:    s.config.PipelineDepth,
+		before: func(ctx context.Context) error {
+			return throttle.wait(ctx, tokenAddress)
+		},
+		fetch: func(ctx context.Context, r ethereum.BlockRange) (*chain.FetchResult, error) {
+			result, err := s.chain.FetchTransfers(ctx, []string{tokenAddress}, r.From, r.To)
+			if err != nil {
+				err = fmt.Errorf("backfill failed at blocks %d-%d: %w", r.From, r.To, err)
+				s.recordTokenError(tokenAddress, err)
+				return nil, err
+			}
+			return result, nil
+		},
+		store: func(ctx context.Context, r ethereum.BlockRange, result *chain.FetchResult) error {
+			if err := s.storeBackfillRange(ctx, tokenAddress, result); err != nil {
+				return err
+			}
+
+			checkpoint, done := tracker.complete(rangeIndex[r.From])
+			if checkpoint >= startBlock {
+				s.recordBackfillBlock(tokenAddress, checkpoint)
+				if err := saveCheckpoint(ctx, checkpoint); err != nil {
+					return err
+				}
+			}
+
+			s.logger.Info("Backfill progress",
+				zap.String("token", tokenAddress),
+				zap.Int("completed_batches", done),
+				zap.Int("total_batches", len(ranges)),
+				zap.Int64("from", r.From),
+				zap.Int64("to", r.To),
+				zap.Int64("checkpoint", checkpoint),
+				zap.Int("transfers", len(result.Transfers)),
+			)
+			return nil
+		},
+	}
+
+	if err := p.run(ctx, ranges); err != nil {
+		s.logger.Warn("Backfill stopped",
+			zap.String("token", tokenAddress),
+			zap.Int64("completed_through", tracker.checkpoint()),
+			zap.Error(err),
+		)
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	completed = true
+
+	s.logger.Info("Backfill completed",
+		zap.String("token", tokenAddress),
+		zap.Int64("from_block", fromBlock),
+		zap.Int64("to_block", toBlock),
+	)
+
+	return nil
+}
+
+// resumeBlock returns the block an interrupted backfill of [fromBlock, toBlock] continues from,
+// fromBlock when the saved checkpoint belongs to another range or there is none
+func resumeBlock(state *entities.IndexerState, fromBlock, toBlock int64) int64 {
+	if state == nil || state.BackfillCheckpoint == nil ||
+		state.BackfillFromBlock == nil || *state.BackfillFromBlock != fromBlock ||
+		state.BackfillToBlock == nil || *state.BackfillToBlock != toBlock {
+		return fromBlock
+	}
+	return min(max(*state.BackfillCheckpoint+1, fromBlock), toBlock+1)
+}
+
+// storeBackfillRange stores one fetched backfill range
+func (s *IndexerService) storeBackfillRange(ctx context.Context, tokenAddress string, result *chain.FetchResult) error {
+	if err := s.storeRawLogs(ctx, result.RawLogs); err != nil {
+		return err
+	}
+
+	if len(result.Transfers) > 0 {
+		inserted, err := s.insertTransfers(ctx, tokenAddress, result.Transfers, false)
+		if err != nil {
+			return fmt.Errorf("failed to insert backfill transfers: %w", err)
+		}
+
+		if err := s.refreshDailyStats(ctx, tokenAddress, result.Transfers); err != nil {
+			return err
+		}
+
+		if err := s.updateWalletStats(ctx, tokenAddress, result.Transfers, inserted); err != nil {
+			return err
+		}
+
+		s.recordActiveAddresses(ctx, tokenAddress, result.Transfers)
+		s.mirrorTransfers(ctx, tokenAddress, result.Transfers)
+	}
+
+	return s.storeEvents(ctx, result.Events)
+}
+
+// prefixTracker follows block ranges that complete out of order and reports the end of the
+// longest contiguous run of completed ranges from the start, the only safe checkpoint
+type prefixTracker struct {
+	mu        sync.Mutex
+	ranges    []ethereum.BlockRange
+	done      []bool
+	next      int // index of the first range not yet completed
+	completed int
+}
+
+func newPrefixTracker(ranges []ethereum.BlockRange) *prefixTracker {
+	return &prefixTracker{
+		ranges: ranges,
+		done:   make([]bool, len(ranges)),
+	}
+}
+
+// complete marks range i as done and returns the checkpoint and the number of completed ranges
+func (t *prefixTracker) complete(i int) (int64, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.done[i] {
+		t.done[i] = true
+		t.completed++
+	}
+	for t.next < len(t.done) && t.done[t.next] {
+		t.next++
+	}
+	return t.checkpointLocked(), t.completed
+}
+
+// checkpoint returns the last block of the contiguous completed prefix,
+// or one before the first range's start if nothing is complete yet
+func (t *prefixTracker) checkpoint() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.checkpointLocked()
+}
+
+func (t *prefixTracker) checkpointLocked() int64 {
+	if len(t.ranges) == 0 {
+		return 0
+	}
+	if t.next == 0 {
+		return t.ranges[0].From - 1
+	}
+	return t.ranges[t.next-1].To
+}
+
+// ReindexResult summarizes a completed reindex
+type ReindexResult struct {
+	Deleted  int64
+	Inserted int64
+}
+
+// Reindex replaces a token's stored transfers in [fromBlock, toBlock] with freshly fetched ones.
+// Each batch is replaced atomically, so an interrupted reindex can simply be re-run.
+func (s *IndexerService) Reindex(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) (*ReindexResult, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	if fromBlock < 0 || toBlock < fromBlock {
+		return nil, fmt.Errorf("invalid block range %d-%d", fromBlock, toBlock)
+	}
+
+	s.logger.Info("Starting reindex",
+		zap.String("token", tokenAddress),
+		zap.Int64("from_block", fromBlock),
+		zap.Int64("to_block", toBlock),
+	)
+
+	result := &ReindexResult{}
+	ranges := ethereum.SplitBlockRange(fromBlock, toBlock, s.config.BackfillBatchSize)
+
+	for i, r := range ranges {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		default:
+		}
+
+		fetched, err := s.chain.FetchTransfers(ctx, []string{tokenAddress}, r.From, r.To)
+		if err != nil {
+			return result, fmt.Errorf("reindex failed at blocks %d-%d: %w", r.From, r.To, err)
+		}
+
+		if err := s.storeRawLogs(ctx, fetched.RawLogs); err != nil {
+			return result, err
+		}
+
+		deleted, inserted, err := s.replaceRange(ctx, tokenAddress, r, fetched.Transfers)
+		if err != nil {
+			return result, err
+		}
+
+		result.Deleted += deleted
+		result.Inserted += inserted
+
+		s.logger.Info("Reindex progress",
+			zap.String("token", tokenAddress),
+			zap.Int("batch", i+1),
+			zap.Int("total_batches", len(ranges)),
+			zap.Int64("from", r.From),
+			zap.Int64("to", r.To),
+			zap.Int64("deleted", deleted),
+			zap.Int64("inserted", inserted),
+		)
+	}
+
+	s.recountTransfers(ctx, tokenAddress)
+
+	s.logger.Info("Reindex completed",
+		zap.String("token", tokenAddress),
+		zap.Int64("from_block", fromBlock),
+		zap.Int64("to_block", toBlock),
+		zap.Int64("deleted", result.Deleted),
+		zap.Int64("inserted", result.Inserted),
+	)
+
+	return result, nil
+}
+
+// Replay replaces a token's stored transfers in [fromBlock, toBlock] with ones decoded from the
+// raw log archive instead of fetched from RPC, e.g. after a parser fix. Blocks indexed while raw
+// logs were not archived have nothing to replay from and come back empty, so only replay ranges
+// that were archived in full.
+func (s *IndexerService) Replay(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) (*ReindexResult, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	if s.rawLogRepo == nil {
+		return nil, fmt.Errorf("raw log archive is not configured")
+	}
+	if fromBlock < 0 || toBlock < fromBlock {
+		return nil, fmt.Errorf("invalid block range %d-%d", fromBlock, toBlock)
+	}
+
+	s.logger.Info("Starting replay",
+		zap.String("token", tokenAddress),
+		zap.Int64("from_block", fromBlock),
+		zap.Int64("to_block", toBlock),
+	)
+
+	result := &ReindexResult{}
+	ranges := ethereum.SplitBlockRange(fromBlock, toBlock, s.config.BackfillBatchSize)
+
+	for i, r := range ranges {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		default:
+		}
+
+		raws, err := s.rawLogRepo.GetRange(ctx, tokenAddress, r.From, r.To)
+		if err != nil {
+			return result, fmt.Errorf("replay failed at blocks %d-%d: %w", r.From, r.To, err)
+		}
+
+		transfers, _, failed, err := s.chain.DecodeRawLogs(raws, []string{tokenAddress})
+		if err != nil {
+			return result, fmt.Errorf("replay failed at blocks %d-%d: %w", r.From, r.To, err)
+		}
+		if failed > 0 {
+			s.logger.Warn("Failed to decode some raw logs",
+				zap.Int("failed_count", failed),
+				zap.Int("total_logs", len(raws)),
+			)
+		}
+
+		deleted, inserted, err := s.replaceRange(ctx, tokenAddress, r, transfers)
+		if err != nil {
+			return result, err
+		}
+
+		result.Deleted += deleted
+		result.Inserted += inserted
+
+		s.logger.Info("Replay progress",
+			zap.String("token", tokenAddress),
+			zap.Int("batch", i+1),
+			zap.Int("total_batches", len(ranges)),
+			zap.Int64("from", r.From),
+			zap.Int64("to", r.To),
+			zap.Int("raw_logs", len(raws)),
+			zap.Int64("deleted", deleted),
+			zap.Int64("inserted", inserted),
+		)
+	}
+
+	s.recountTransfers(ctx, tokenAddress)
+
+	s.logger.Info("Replay completed",
+		zap.String("token", tokenAddress),
+		zap.Int64("from_block", fromBlock),
+		zap.Int64("to_block", toBlock),
+		zap.Int64("deleted", result.Deleted),
+		zap.Int64("inserted", result.Inserted),
+	)
+
+	return result, nil
+}
+
+// replaceRange swaps a token's stored transfers in one block range for the given ones and
+// brings the daily rollup, address sketches and analytics store in line
+func (s *IndexerService) replaceRange(ctx context.Context, tokenAddress string, r ethereum.BlockRange, transfers []entities.Transfer) (deleted, inserted int64, err error) {
+	// The wallets of the removed transfers are only known before they are removed
+	var replacedWallets []string
+	if s.walletStats != nil {
+		wallets, err := s.walletStats.GetRangeWallets(ctx, tokenAddress, r.From, r.To)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to get wallets of blocks %d-%d: %w", r.From, r.To, err)
+		}
+		replacedWallets = wallets
+	}
+
+	deleted, inserted, err = s.transferRepo.ReplaceRange(ctx, tokenAddress, r.From, r.To, transfers)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to replace transfers for blocks %d-%d: %w", r.From, r.To, err)
+	}
+
+	if err := s.refreshDailyStatsForBlocks(ctx, tokenAddress, r.From, r.To); err != nil {
+		return deleted, inserted, err
+	}
+
+	if err := s.refreshWalletStats(ctx, tokenAddress, replacedWallets, transfers); err != nil {
+		return deleted, inserted, err
+	}
+
+	// Sketches can't forget addresses; `chain-indexer rollup` rebuilds them exactly
+	s.recordActiveAddresses(ctx, tokenAddress, transfers)
+
+	if s.analytics != nil {
+		if err := s.analytics.ReplaceRange(ctx, tokenAddress, r.From, r.To, transfers); err != nil {
+			return deleted, inserted, fmt.Errorf("failed to replace analytics transfers for blocks %d-%d: %w", r.From, r.To, err)
+		}
+	}
+
+	return deleted, inserted, nil
+}
+
+// recountTransfers rederives the token's transfer count after stored transfers were replaced,
+// since replacing a range can change how many there are
+func (s *IndexerService) recountTransfers(ctx context.Context, tokenAddress string) {
+	if _, err := s.tokenRepo.RecountTransfers(ctx, tokenAddress); err != nil {
+		s.logger.Warn("Failed to recount token transfers", zap.String("token", tokenAddress), zap.Error(err))
+	}
+}
+
+// storeRawLogs archives fetched logs when the raw log archive is enabled
+func (s *IndexerService) storeRawLogs(ctx context.Context, logs []entities.RawLog) error {
+	if s.rawLogRepo == nil || len(logs) == 0 {
+		return nil
+	}
+
+	if err := s.rawLogRepo.BatchInsert(ctx, logs); err != nil {
+		return fmt.Errorf("failed to store raw logs: %w", err)
+	}
+	return nil
+}
+
+// refreshDailyStats recomputes the rollup for the days spanned by newly written transfers
+func (s *IndexerService) refreshDailyStats(ctx context.Context, tokenAddress string, transfers []entities.Transfer) error {
+	if s.dailyStatsRepo == nil || len(transfers) == 0 {
+		return nil
+	}
+
+	from, to := transfers[0].BlockTimestamp, transfers[0].BlockTimestamp
+	for _, t := range transfers[1:] {
+		if t.BlockTimestamp.Before(from) {
+			from = t.BlockTimestamp
+		}
+		if t.BlockTimestamp.After(to) {
+			to = t.BlockTimestamp
+		}
+	}
+
+	if err := s.dailyStatsRepo.RefreshDays(ctx, tokenAddress, from, to); err != nil {
+		return fmt.Errorf("failed to refresh daily stats: %w", err)
+	}
+	return nil
+}
+
+// refreshDailyStatsForBlocks recomputes the rollup for the days spanned by a block range.
+// Used after replacing transfers, where removed rows may fall on days the new ones don't.
+func (s *IndexerService) refreshDailyStatsForBlocks(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) error {
+	if s.dailyStatsRepo == nil {
+		return nil
+	}
+
+	from, err := s.chain.GetBlockTimestamp(ctx, fromBlock)
+	if err != nil {
+		return fmt.Errorf("failed to get timestamp of block %d: %w", fromBlock, err)
+	}
+	to, err := s.chain.GetBlockTimestamp(ctx, toBlock)
+	if err != nil {
+		return fmt.Errorf("failed to get timestamp of block %d: %w", toBlock, err)
+	}
+
+	if err := s.dailyStatsRepo.RefreshDays(ctx, tokenAddress, from, to); err != nil {
+		return fmt.Errorf("failed to refresh daily stats: %w", err)
+	}
+	return nil
+}
+
+// updateWalletStats adds newly written transfers to the wallet stats. When some of them were
+// already stored, which happens when a range is retried after a failure past the insert, the
+// wallets involved are recomputed instead, since adding the batch again would count them twice.
+func (s *IndexerService) updateWalletStats(ctx context.Context, tokenAddress string, transfers []entities.Transfer, inserted int64) error {
+	if s.walletStats == nil || len(transfers) == 0 {
+		return nil
+	}
+
+	if inserted < int64(len(transfers)) {
+		return s.refreshWalletStats(ctx, tokenAddress, nil, transfers)
+	}
+
+	if err := s.walletStats.Apply(ctx, transfers); err != nil {
+		return fmt.Errorf("failed to update wallet stats: %w", err)
+	}
+	return nil
+}
+
+// refreshWalletStats recomputes the wallet stats of the given wallets and of the senders and
+// receivers of transfers
+func (s *IndexerService) refreshWalletStats(ctx context.Context, tokenAddress string, wallets []string, transfers []entities.Transfer) error {
+	if s.walletStats == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(wallets)+2*len(transfers))
+	for _, w := range wallets {
+		seen[w] = true
+	}
+	for _, t := range transfers {
+		seen[t.FromAddress] = true
+		seen[t.ToAddress] = true
+	}
+	all := make([]string, 0, len(seen))
+	for w := range seen {
+		all = append(all, w)
+	}
+	sort.Strings(all)
+
+	if err := s.walletStats.Refresh(ctx, tokenAddress, all); err != nil {
+		return fmt.Errorf("failed to refresh wallet stats: %w", err)
+	}
+	return nil
+}
+
+// recordActiveAddresses adds the senders and receivers of transfers to their days' active address sketches.
+// Failures are logged but don't stop indexing, since the sketches can be rebuilt with `chain-indexer rollup`.
+func (s *IndexerService) recordActiveAddresses(ctx context.Context, tokenAddress string, transfers []entities.Transfer) {
+	if s.activeAddrs == nil || len(transfers) == 0 {
+		return
+	}
+
+	byDay := make(map[time.Time][]string)
+	for _, t := range transfers {
+		day := t.BlockTimestamp.UTC().Truncate(24 * time.Hour)
+		for _, addr := range []string{t.FromAddress, t.ToAddress} {
+			if addr != entities.ZeroAddress {
+				byDay[day] = append(byDay[day], addr)
+			}
+		}
+	}
+
+	for day, addresses := range byDay {
+		if err := s.activeAddrs.AddActiveAddresses(ctx, tokenAddress, day, addresses); err != nil {
+			s.logger.Warn("Failed to record active addresses",
+				zap.String("token", tokenAddress),
+				zap.Time("day", day),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// mirrorTransfers copies newly stored transfers to the analytics store, if configured.
+// Failures are logged but don't stop indexing.
+func (s *IndexerService) mirrorTransfers(ctx context.Context, tokenAddress string, transfers []entities.Transfer) {
+	if s.analytics == nil || len(transfers) == 0 {
+		return
+	}
+
+	if err := s.analytics.InsertTransfers(ctx, transfers); err != nil {
+		s.logger.Warn("Failed to mirror transfers to analytics store",
+			zap.String("token", tokenAddress),
+			zap.Int("transfers", len(transfers)),
+			zap.Error(err),
+		)
+	}
+}
+
+// RebuildDailyStats recomputes a token's rollup, and its active address sketches when configured,
+// for every UTC day in [from, to], one day at a time
+func (s *IndexerService) RebuildDailyStats(ctx context.Context, tokenAddress string, from, to time.Time) error {
+	if s.dailyStatsRepo == nil {
+		return fmt.Errorf("daily stats repository not configured")
+	}
+
+	tokenAddress = strings.ToLower(tokenAddress)
+	from = from.UTC().Truncate(24 * time.Hour)
+	to = to.UTC().Truncate(24 * time.Hour)
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if err := s.dailyStatsRepo.RefreshDays(ctx, tokenAddress, day, day); err != nil {
+			return fmt.Errorf("failed to rebuild daily stats for %s: %w", day.Format("2006-01-02"), err)
+		}
+
+		if s.activeAddrs != nil {
+			addresses, err := s.transferRepo.GetActiveAddresses(ctx, tokenAddress, day, day.AddDate(0, 0, 1))
+			if err != nil {
+				return fmt.Errorf("failed to get active addresses for %s: %w", day.Format("2006-01-02"), err)
+			}
+			if err := s.activeAddrs.ReplaceActiveAddresses(ctx, tokenAddress, day, addresses); err != nil {
+				return fmt.Errorf("failed to rebuild active addresses for %s: %w", day.Format("2006-01-02"), err)
+			}
+		}
+
+		s.logger.Debug("Rebuilt daily stats",
+			zap.String("token", tokenAddress),
+			zap.String("day", day.Format("2006-01-02")),
+		)
+	}
+
+	return nil
+}
+
+// storeEvents hands decoded non-Transfer events to their registered repositories
+func (s *IndexerService) storeEvents(ctx context.Context, events map[string][]any) error {
+	for name, records := range events {
+		if len(records) == 0 {
+			continue
+		}
+
+		repo, ok := s.eventRepos[name]
+		if !ok {
+			s.logger.Debug("No repository registered for event type, dropping records",
+				zap.String("event", name),
+				zap.Int("count", len(records)),
+			)
+			continue
+		}
+
+		if err := repo.StoreEvents(ctx, records); err != nil {
+			return fmt.Errorf("failed to store %s events: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *IndexerService) updateMetrics(blocks, transfers, lastBlock int64) {
+	s.metrics.mu.Lock()
+	defer s.metrics.mu.Unlock()
+	s.metrics.BlocksIndexed += blocks
+	s.metrics.TransfersIndexed += transfers
+	s.metrics.LastIndexedBlock = lastBlock
+}
+
+func (s *IndexerService) incrementErrorCount() {
+	s.metrics.mu.Lock()
+	defer s.metrics.mu.Unlock()
+	s.metrics.ErrorCount++
+}
+
+func (s *IndexerService) tokenProgressLocked(tokenAddress string) *tokenProgress {
+	p, ok := s.progress[tokenAddress]
+	if !ok {
+		p = &tokenProgress{}
+		s.progress[tokenAddress] = p
+	}
+	return p
+}
+
+func (s *IndexerService) recordTokenError(tokenAddress string, err error) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	p := s.tokenProgressLocked(tokenAddress)
+	p.lastError = err.Error()
+	p.lastErrorAt = time.Now()
+}
+
+func (s *IndexerService) recordBackfillBlock(tokenAddress string, blockNumber int64) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	s.tokenProgressLocked(tokenAddress).backfillBlock = blockNumber
 }
@@ -0,0 +1,1103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/chain"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestPrefixTracker(t *testing.T) {
+	tracker := newPrefixTracker(ethereum.SplitBlockRange(100, 499, 100))
+
+	if got := tracker.checkpoint(); got != 99 {
+		t.Errorf("expected checkpoint 99 before any range completes, got %d", got)
+	}
+
+	// Later ranges finishing first don't move the checkpoint past the gap
+	steps := []struct {
+		index      int
+		checkpoint int64
+		completed  int
+	}{
+		{2, 99, 1},
+		{1, 99, 2},
+		{0, 399, 3},
+		{0, 399, 3}, // completing a range twice is a no-op
+		{3, 499, 4},
+	}
+	for _, step := range steps {
+		checkpoint, completed := tracker.complete(step.index)
+		if checkpoint != step.checkpoint || completed != step.completed {
+			t.Errorf("complete(%d): expected checkpoint %d with %d completed, got %d with %d",
+				step.index, step.checkpoint, step.completed, checkpoint, completed)
+		}
+	}
+}
+
+func TestGroupTransfersByToken(t *testing.T) {
+	transfers := []entities.Transfer{
+		{TokenAddress: "0xaaa", BlockNumber: 10, LogIndex: 0},
+		{TokenAddress: "0xbbb", BlockNumber: 10, LogIndex: 1},
+		{TokenAddress: "0xaaa", BlockNumber: 12, LogIndex: 0},
+	}
+
+	byToken := groupTransfersByToken(transfers)
+	if len(byToken) != 2 {
+		t.Fatalf("expected 2 tokens, got %d", len(byToken))
+	}
+	if got := byToken["0xaaa"]; len(got) != 2 || got[0].BlockNumber != 10 || got[1].BlockNumber != 12 {
+		t.Errorf("unexpected transfers for 0xaaa: %+v", got)
+	}
+	if got := byToken["0xbbb"]; len(got) != 1 {
+		t.Errorf("expected 1 transfer for 0xbbb, got %d", len(got))
+	}
+}
+
+func TestTransfersAfterBlock(t *testing.T) {
+	transfers := []entities.Transfer{
+		{BlockNumber: 10},
+		{BlockNumber: 11},
+		{BlockNumber: 12},
+	}
+
+	kept := transfersAfterBlock(transfers, 11)
+	if len(kept) != 1 || kept[0].BlockNumber != 12 {
+		t.Errorf("expected only block 12, got %+v", kept)
+	}
+	if len(transfers) != 3 || transfers[1].BlockNumber != 11 {
+		t.Error("input slice was modified")
+	}
+}
+
+func TestActiveTokenAddresses(t *testing.T) {
+	tokenRepo := testutil.NewMockTokenRepository()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	tokenRepo.AddToken(testutil.CreateTestToken(
+		testutil.TokenWithAddress(testutil.USDCAddress),
+		testutil.TokenInactive(),
+	))
+
+	cfg := config.IndexerConfig{
+		TokenAddresses:      []string{testutil.USDTAddress, testutil.USDCAddress},
+		ActiveTokensRefresh: time.Hour,
+	}
+	service := NewIndexerService(nil, nil, tokenRepo, nil, nil, cfg, zap.NewNop())
+	ctx := context.Background()
+
+	active, err := service.activeTokenAddresses(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(active) != 1 || active[0] != testutil.USDTAddress {
+		t.Errorf("expected only %s, got %v", testutil.USDTAddress, active)
+	}
+
+	// Within the refresh interval the cached set is used without another query
+	if _, err := tokenRepo.SetActive(ctx, testutil.USDCAddress, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.activeTokenAddresses(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := countCalls(tokenRepo.Calls, "GetByAddresses"); n != 1 {
+		t.Errorf("expected 1 GetByAddresses call, got %d", n)
+	}
+
+	// Once it has passed, the re-activated token is picked up again
+	service.activeLoadedAt = time.Now().Add(-2 * time.Hour)
+	active, err = service.activeTokenAddresses(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(active) != 2 {
+		t.Errorf("expected both tokens after the refresh, got %v", active)
+	}
+}
+
+func TestInitializeTokens_StartBlock(t *testing.T) {
+	tokenRepo := testutil.NewMockTokenRepository()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	states := testutil.NewMockIndexerStateRepository()
+
+	cfg := config.IndexerConfig{
+		TokenAddresses: []string{testutil.USDTAddress, testutil.USDCAddress},
+		StartBlock:     19000000,
+	}
+	service := NewIndexerService(nil, nil, tokenRepo, nil, states, cfg, zap.NewNop())
+	ctx := context.Background()
+
+	if err := service.InitializeTokens(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the new token gets a checkpoint, just before the start block
+	state, err := states.Get(ctx, testutil.USDCAddress)
+	if err != nil || state == nil || state.LastIndexedBlock != 18999999 {
+		t.Errorf("expected USDC checkpoint 18999999, got %+v (%v)", state, err)
+	}
+	if state, _ := states.Get(ctx, testutil.USDTAddress); state != nil {
+		t.Errorf("expected the existing token left alone, got %+v", state)
+	}
+}
+
+func countCalls(calls []testutil.MockCall, method string) int {
+	n := 0
+	for _, c := range calls {
+		if c.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+// indexerTest wires an IndexerService to a mock node and in-memory repositories
+type indexerTest struct {
+	service   *IndexerService
+	rpc       *testutil.MockRPC
+	fetcher   *ethereum.Fetcher
+	transfers *testutil.MockTransferRepository
+	states    *testutil.MockIndexerStateRepository
+}
+
+// newIndexerTest indexes tokens from their given checkpoints up to head, in batches of 100 blocks
+func newIndexerTest(t *testing.T, head uint64, checkpoints map[string]int64, combined bool) *indexerTest {
+	t.Helper()
+
+	rpc := testutil.NewMockRPC(head)
+	t.Cleanup(rpc.Close)
+
+	logger := zap.NewNop()
+	client, err := ethereum.NewClient(config.EthereumConfig{
+		RPCURL:         rpc.URL,
+		ChainID:        1,
+		RequestTimeout: 5 * time.Second,
+	}, logger)
+	if err != nil {
+		t.Fatalf("failed to connect to mock node: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	var addresses []string
+	states := testutil.NewMockIndexerStateRepository()
+	for addr, checkpoint := range checkpoints {
+		addresses = append(addresses, addr)
+		states.AddState(&entities.IndexerState{TokenAddress: addr, LastIndexedBlock: checkpoint})
+	}
+
+	cfg := config.IndexerConfig{
+		BatchSize:      100,
+		WorkerCount:    2,
+		CombinedFetch:  combined,
+		TokenAddresses: addresses,
+	}
+	fetcher := ethereum.NewFetcher(client, cfg, logger)
+	transfers := testutil.NewMockTransferRepository()
+	service := NewIndexerService(fetcher, nil, testutil.NewMockTokenRepository(), transfers, states, cfg, logger)
+
+	return &indexerTest{service: service, rpc: rpc, fetcher: fetcher, transfers: transfers, states: states}
+}
+
+// checkpoint returns a token's stored checkpoint
+func (it *indexerTest) checkpoint(t *testing.T, tokenAddress string) int64 {
+	t.Helper()
+	state, err := it.states.Get(context.Background(), tokenAddress)
+	if err != nil || state == nil {
+		t.Fatalf("no indexer state for %s: %v", tokenAddress, err)
+	}
+	return state.LastIndexedBlock
+}
+
+type recordingEventRepo struct {
+	events []any
+}
+
+func (r *recordingEventRepo) StoreEvents(ctx context.Context, events []any) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func TestIndexNewBlocks_ScopedEventsFetchedOncePerRange(t *testing.T) {
+	it := newIndexerTest(t, 150, map[string]int64{
+		testutil.USDTAddress: 100,
+		testutil.USDCAddress: 100,
+	}, false)
+
+	pool := common.HexToAddress("0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640")
+	swapSig := common.HexToHash("0x01")
+	if err := it.fetcher.Registry().Register(ethereum.EventType{
+		Name:      "swap",
+		Signature: swapSig,
+		Addresses: []common.Address{pool},
+		Decode: func(log types.Log, blockTimestamp time.Time) (any, error) {
+			return log.BlockNumber, nil
+		},
+	}); err != nil {
+		t.Fatalf("failed to register event: %v", err)
+	}
+	swaps := &recordingEventRepo{}
+	scopedState := testutil.NewMockScopedEventStateRepository()
+	it.service.RegisterEventRepository("swap", swaps)
+	it.service.WithScopedEvents(scopedState)
+
+	it.rpc.AddLogs(
+		types.Log{Address: pool, Topics: []common.Hash{swapSig}, BlockNumber: 120, Index: 3},
+		testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 110, 0),
+	)
+
+	it.service.indexNewBlocks(context.Background())
+
+	var tokenQueries, poolQueries int
+	for _, q := range it.rpc.Queries() {
+		for _, a := range q.Addresses {
+			if a == pool {
+				poolQueries++
+				if len(q.Addresses) != 1 {
+					t.Errorf("expected pools to be fetched on their own, got %v", q.Addresses)
+				}
+			}
+		}
+		if len(q.Addresses) == 1 && q.Addresses[0] != pool {
+			tokenQueries++
+		}
+	}
+	if tokenQueries != 2 {
+		t.Errorf("expected one getLogs call per token, got %d", tokenQueries)
+	}
+	if poolQueries != 1 {
+		t.Errorf("expected one getLogs call for the pools, got %d", poolQueries)
+	}
+
+	if len(swaps.events) != 1 {
+		t.Errorf("expected the swap to be stored once, got %d", len(swaps.events))
+	}
+	if last, _ := scopedState.GetLastBlock(context.Background()); last != 150 {
+		t.Errorf("expected scoped checkpoint 150, got %d", last)
+	}
+	if got := it.checkpoint(t, testutil.USDTAddress); got != 150 {
+		t.Errorf("expected USDT checkpoint 150, got %d", got)
+	}
+}
+
+func TestIndexAllTokens_AdvancesEachCheckpoint(t *testing.T) {
+	it := newIndexerTest(t, 400, map[string]int64{
+		testutil.USDTAddress: 100,
+		testutil.USDCAddress: 250,
+	}, true)
+
+	it.rpc.AddLogs(
+		testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 150, 0),
+		// Already indexed by USDC, so dropped even though the range 201-300 is fetched for it
+		testutil.TransferLog(testutil.USDCAddress, testutil.AliceAddress, testutil.BobAddress, 7, 240, 0),
+		testutil.TransferLog(testutil.USDCAddress, testutil.BobAddress, testutil.AliceAddress, 9, 320, 1),
+	)
+
+	tokens := []string{testutil.USDTAddress, testutil.USDCAddress}
+	if err := it.service.indexAllTokens(context.Background(), tokens, 400); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, token := range tokens {
+		if got := it.checkpoint(t, token); got != 400 {
+			t.Errorf("expected %s checkpoint 400, got %d", token, got)
+		}
+	}
+
+	stored, err := it.transfers.GetByFilter(context.Background(), entities.TransferFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	blocks := map[int64]bool{}
+	for _, tr := range stored {
+		blocks[tr.BlockNumber] = true
+	}
+	if len(stored) != 2 || !blocks[150] || !blocks[320] {
+		t.Errorf("expected the transfers at blocks 150 and 320, got %v", blocks)
+	}
+
+	// One getLogs call per range; USDC joins once a range passes its checkpoint
+	queries := it.rpc.Queries()
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 getLogs calls, got %d", len(queries))
+	}
+	if len(queries[0].Addresses) != 1 || len(queries[1].Addresses) != 2 || len(queries[2].Addresses) != 2 {
+		t.Errorf("expected USDT alone in the first range and both tokens after, got %v", queries)
+	}
+}
+
+func TestIndexNewBlocks_PerTokenConfirmations(t *testing.T) {
+	for _, combined := range []bool{false, true} {
+		it := newIndexerTest(t, 400, map[string]int64{
+			testutil.USDTAddress: 100,
+			testutil.USDCAddress: 100,
+		}, combined)
+		it.service.config.BlockConfirmations = 10
+		// Addresses match regardless of case
+		it.service.config.TokenConfirmations = map[string]int{"0x" + strings.ToUpper(testutil.USDCAddress[2:]): 150}
+
+		it.rpc.AddLogs(
+			testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 380, 0),
+			testutil.TransferLog(testutil.USDCAddress, testutil.AliceAddress, testutil.BobAddress, 7, 240, 0),
+			// Not yet confirmed for USDC, although fetched along with USDT in combined mode
+			testutil.TransferLog(testutil.USDCAddress, testutil.BobAddress, testutil.AliceAddress, 9, 300, 1),
+		)
+
+		it.service.indexNewBlocks(context.Background())
+
+		if got := it.checkpoint(t, testutil.USDTAddress); got != 390 {
+			t.Errorf("combined=%v: expected USDT checkpoint 390, got %d", combined, got)
+		}
+		if got := it.checkpoint(t, testutil.USDCAddress); got != 250 {
+			t.Errorf("combined=%v: expected USDC checkpoint 250, got %d", combined, got)
+		}
+
+		stored, err := it.transfers.GetByFilter(context.Background(), entities.TransferFilter{Limit: 10})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		blocks := map[int64]bool{}
+		for _, tr := range stored {
+			blocks[tr.BlockNumber] = true
+		}
+		if len(stored) != 2 || !blocks[380] || !blocks[240] {
+			t.Errorf("combined=%v: expected the transfers at blocks 380 and 240, got %v", combined, blocks)
+		}
+	}
+}
+
+func TestIndexAllTokens_FailureDoesNotAdvanceOthers(t *testing.T) {
+	it := newIndexerTest(t, 500, map[string]int64{
+		testutil.USDTAddress: 100,
+		testutil.USDCAddress: 100,
+	}, true)
+	it.service.config.PipelineDepth = 1
+
+	it.rpc.AddLogs(
+		testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 150, 0),
+		testutil.TransferLog(testutil.USDCAddress, testutil.AliceAddress, testutil.BobAddress, 7, 150, 1),
+		testutil.TransferLog(testutil.USDTAddress, testutil.BobAddress, testutil.AliceAddress, 9, 250, 0),
+	)
+
+	var inserted []entities.Transfer
+	it.transfers.BatchInsertFunc = func(ctx context.Context, transfers []entities.Transfer) (int64, error) {
+		if transfers[0].TokenAddress == testutil.USDCAddress {
+			return 0, errors.New("disk full")
+		}
+		inserted = append(inserted, transfers...)
+		return int64(len(transfers)), nil
+	}
+
+	tokens := []string{testutil.USDTAddress, testutil.USDCAddress}
+	err := it.service.indexAllTokens(context.Background(), tokens, 500)
+	if err == nil || !strings.Contains(err.Error(), testutil.USDCAddress) {
+		t.Fatalf("expected an error naming USDC, got %v", err)
+	}
+
+	if got := it.checkpoint(t, testutil.USDTAddress); got != 500 {
+		t.Errorf("expected USDT to reach 500 despite USDC failing, got %d", got)
+	}
+	if got := it.checkpoint(t, testutil.USDCAddress); got != 100 {
+		t.Errorf("expected USDC checkpoint to stay at 100, got %d", got)
+	}
+	if len(inserted) != 2 {
+		t.Errorf("expected both USDT transfers to be stored, got %d", len(inserted))
+	}
+
+	// USDC sits out the rest of the pass once the ranges fetched while storing the first are
+	// drained from the queue
+	for _, q := range it.rpc.Queries()[3:] {
+		if len(q.Addresses) != 1 {
+			t.Errorf("expected only USDT to be fetched after the failure, got %v", q.Addresses)
+		}
+	}
+}
+
+func TestIndexTokenTransfers_CountsDuplicates(t *testing.T) {
+	it := newIndexerTest(t, 200, map[string]int64{testutil.USDTAddress: 100}, false)
+
+	it.rpc.AddLogs(
+		testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 150, 0),
+		testutil.TransferLog(testutil.USDTAddress, testutil.BobAddress, testutil.AliceAddress, 7, 160, 0),
+		testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.CharlieAddr, 9, 170, 0),
+	)
+	// The first transfer was already stored
+	it.transfers.BatchInsertFunc = func(ctx context.Context, transfers []entities.Transfer) (int64, error) {
+		return int64(len(transfers) - 1), nil
+	}
+
+	if err := it.service.indexTokenTransfers(context.Background(), testutil.USDTAddress, 200, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metrics := it.service.GetMetrics()
+	if metrics.TransfersIndexed != 2 || metrics.DuplicatesSkipped != 1 {
+		t.Errorf("expected 2 transfers indexed and 1 duplicate, got %d and %d", metrics.TransfersIndexed, metrics.DuplicatesSkipped)
+	}
+	if got := it.checkpoint(t, testutil.USDTAddress); got != 200 {
+		t.Errorf("expected checkpoint 200, got %d", got)
+	}
+}
+
+func TestReindex_CountsInsertedTransfers(t *testing.T) {
+	it := newIndexerTest(t, 200, map[string]int64{testutil.USDTAddress: 200}, false)
+
+	it.rpc.AddLogs(
+		testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 150, 0),
+		testutil.TransferLog(testutil.USDTAddress, testutil.BobAddress, testutil.AliceAddress, 7, 160, 0),
+	)
+	// One of the fetched transfers is a duplicate the repository skips
+	it.transfers.ReplaceRangeFunc = func(ctx context.Context, tokenAddress string, fromBlock, toBlock int64, transfers []entities.Transfer) (int64, int64, error) {
+		return 3, int64(len(transfers) - 1), nil
+	}
+	it.service.config.BackfillBatchSize = 100
+
+	result, err := it.service.Reindex(context.Background(), testutil.USDTAddress, 101, 200)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Deleted != 3 || result.Inserted != 1 {
+		t.Errorf("expected 3 deleted and 1 inserted, got %d and %d", result.Deleted, result.Inserted)
+	}
+}
+
+func TestBackfill_ResumesFromSavedCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	it := newIndexerTest(t, 1000, map[string]int64{testutil.USDTAddress: 900}, false)
+	it.service.config.BackfillBatchSize = 100
+	it.service.config.BackfillConcurrency = 1
+
+	it.rpc.AddLogs(
+		testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 150, 0),
+		testutil.TransferLog(testutil.USDTAddress, testutil.BobAddress, testutil.AliceAddress, 7, 350, 0),
+	)
+	it.transfers.BatchInsertFunc = func(ctx context.Context, transfers []entities.Transfer) (int64, error) {
+		if transfers[0].BlockNumber == 350 {
+			return 0, errors.New("connection reset")
+		}
+		return int64(len(transfers)), nil
+	}
+
+	if err := it.service.Backfill(ctx, testutil.USDTAddress, 101, 500); err == nil {
+		t.Fatal("expected the backfill to fail")
+	}
+
+	state, _ := it.states.Get(ctx, testutil.USDTAddress)
+	if state.BackfillCheckpoint == nil || *state.BackfillCheckpoint != 300 {
+		t.Fatalf("expected saved checkpoint 300, got %v", state.BackfillCheckpoint)
+	}
+	if state.IsBackfilling || state.BackfillFromBlock == nil || *state.BackfillFromBlock != 101 {
+		t.Errorf("expected the stopped backfill to keep its range, got %+v", state)
+	}
+
+	it.transfers.BatchInsertFunc = nil
+	before := len(it.rpc.Queries())
+
+	if err := it.service.Backfill(ctx, testutil.USDTAddress, 101, 500); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first run may have been fetching 401-500 ahead when it stopped
+	queries := it.rpc.Queries()[before:]
+	resumed := false
+	for _, q := range queries {
+		resumed = resumed || q.FromBlock == 301
+		if q.FromBlock < 301 {
+			t.Errorf("expected the rerun to fetch 301-500 only, got %v", queries)
+			break
+		}
+	}
+	if !resumed {
+		t.Errorf("expected the rerun to resume at 301, got %v", queries)
+	}
+	state, _ = it.states.Get(ctx, testutil.USDTAddress)
+	if state.BackfillCheckpoint != nil || state.BackfillFromBlock != nil {
+		t.Errorf("expected a completed backfill to clear its range and checkpoint, got %+v", state)
+	}
+}
+
+func TestBackfill_LowPriorityWaitsForLiveIndexing(t *testing.T) {
+	ctx := context.Background()
+	it := newIndexerTest(t, 1000, map[string]int64{
+		testutil.USDTAddress: 1000,
+		testutil.USDCAddress: 500, // live indexing 500 blocks behind
+	}, false)
+	it.service.config.BackfillBatchSize = 100
+	it.service.config.BackfillConcurrency = 1
+	it.service.config.PollInterval = 10 * time.Millisecond
+	it.service.config.BackfillPriority = BackfillPriorityLow
+	it.service.WithBackfillThrottle([]string{testutil.USDTAddress, testutil.USDCAddress})
+
+	// The throttle logs once when it pauses the backfill, before fetching anything
+	paused := make(chan struct{}, 1)
+	core, _ := observer.New(zapcore.InfoLevel)
+	it.service.logger = zap.New(zapcore.RegisterHooks(core, func(e zapcore.Entry) error {
+		if e.Message == "Backfill paused while live indexing catches up" {
+			select {
+			case paused <- struct{}{}:
+			default:
+			}
+		}
+		return nil
+	}))
+
+	done := make(chan error, 1)
+	go func() { done <- it.service.Backfill(ctx, testutil.USDTAddress, 101, 300) }()
+
+	select {
+	case <-paused:
+	case err := <-done:
+		t.Fatalf("expected the backfill to pause, it returned %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the backfill to pause for live indexing")
+	}
+	if queries := it.rpc.Queries(); len(queries) != 0 {
+		t.Fatalf("expected the backfill to wait for live indexing, got %v", queries)
+	}
+
+	// Within a batch of the head is caught up
+	if err := it.states.UpdateLastBlock(ctx, testutil.USDCAddress, 950); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the backfill to resume once live indexing caught up")
+	}
+	if queries := it.rpc.Queries(); len(queries) != 2 {
+		t.Errorf("expected the backfill to fetch its 2 ranges, got %v", queries)
+	}
+}
+
+func TestBackfill_PacedToBlocksPerMinute(t *testing.T) {
+	it := newIndexerTest(t, 1000, map[string]int64{testutil.USDTAddress: 1000}, false)
+	it.service.config.BackfillBatchSize = 100
+	it.service.config.BackfillConcurrency = 4
+	// A range every 100ms, the first without waiting
+	it.service.config.BackfillBlocksPerMinute = 60000
+	it.service.WithBackfillThrottle(nil)
+
+	start := time.Now()
+	if err := it.service.Backfill(context.Background(), testutil.USDTAddress, 1, 400); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("expected 4 ranges to take at least 300ms, took %v", elapsed)
+	}
+}
+
+func TestResumeBlock(t *testing.T) {
+	block := func(n int64) *int64 { return &n }
+	state := &entities.IndexerState{BackfillFromBlock: block(100), BackfillToBlock: block(500), BackfillCheckpoint: block(299)}
+
+	tests := []struct {
+		name     string
+		state    *entities.IndexerState
+		from, to int64
+		expected int64
+	}{
+		{"no state", nil, 100, 500, 100},
+		{"same range", state, 100, 500, 300},
+		{"other range", state, 100, 600, 100},
+		{"no checkpoint", &entities.IndexerState{BackfillFromBlock: block(100), BackfillToBlock: block(500)}, 100, 500, 100},
+		{"finished range", &entities.IndexerState{BackfillFromBlock: block(100), BackfillToBlock: block(500), BackfillCheckpoint: block(500)}, 100, 500, 501},
+	}
+	for _, tt := range tests {
+		if got := resumeBlock(tt.state, tt.from, tt.to); got != tt.expected {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestObserveCatchUp(t *testing.T) {
+	cfg := config.IndexerConfig{
+		TokenAddresses:   []string{testutil.USDTAddress, testutil.USDCAddress},
+		CatchUpThreshold: 1000,
+	}
+	s := NewIndexerService(nil, nil, nil, nil, nil, cfg, zap.NewNop())
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The lowest checkpoint decides: USDT is 5000 blocks behind
+	s.catchUp.head.Store(20000)
+	s.recordCheckpoint(testutil.USDTAddress, 15000)
+	s.recordCheckpoint(testutil.USDCAddress, 19990)
+	s.observeCatchUp(start, true)
+	status := s.catchUpStatus()
+	if status == nil || status.BlocksRemaining != 5000 || status.ETASeconds != nil {
+		t.Fatalf("expected catch-up with 5000 blocks remaining and no ETA yet, got %+v", status)
+	}
+
+	// 100 seconds later USDT advanced 2100 blocks while the head moved 100: 20 blocks/s closing
+	s.catchUp.head.Store(20100)
+	s.recordCheckpoint(testutil.USDTAddress, 17100)
+	s.observeCatchUp(start.Add(100*time.Second), true)
+	status = s.catchUpStatus()
+	if status == nil || status.BlocksRemaining != 3000 || status.BlocksPerSecond != 21 {
+		t.Fatalf("expected 3000 blocks remaining at 21 blocks/s, got %+v", status)
+	}
+	if status.ETASeconds == nil || *status.ETASeconds != 150 {
+		t.Errorf("expected an ETA of 150s, got %v", status.ETASeconds)
+	}
+
+	// Below the threshold catch-up ends
+	s.recordCheckpoint(testutil.USDTAddress, 19500)
+	s.observeCatchUp(start.Add(200*time.Second), false)
+	if status := s.catchUpStatus(); status != nil {
+		t.Errorf("expected catch-up to end, got %+v", status)
+	}
+
+	// Disabled with a 0 threshold
+	s.config.CatchUpThreshold = 0
+	s.recordCheckpoint(testutil.USDTAddress, 100)
+	s.observeCatchUp(start.Add(300*time.Second), false)
+	if status := s.catchUpStatus(); status != nil {
+		t.Errorf("expected no catch-up when disabled, got %+v", status)
+	}
+}
+
+func TestWorkerLevel(t *testing.T) {
+	s := NewIndexerService(nil, nil, nil, nil, nil, config.IndexerConfig{
+		WorkerCount:    2,
+		WorkerCountMax: 10,
+		AutoscaleLag:   1000,
+	}, zap.NewNop())
+
+	for lag, want := range map[int64]int{-5: 2, 0: 2, 500: 6, 999: 9, 1000: 10, 50000: 10} {
+		if got := s.workerLevel(lag); got != want {
+			t.Errorf("workerLevel(%d) = %d, expected %d", lag, got, want)
+		}
+	}
+
+	s.config.WorkerCountMax = 0
+	if got := s.workerLevel(50000); got != 2 {
+		t.Errorf("expected WorkerCount without autoscaling, got %d", got)
+	}
+	if got := s.rangeWindow(1); got != 1 {
+		t.Errorf("expected no fetching ahead without autoscaling, got %d", got)
+	}
+}
+
+func TestPipeline_OverlapsFetchAndStore(t *testing.T) {
+	ranges := ethereum.SplitBlockRange(1, 400, 100)
+	fetchedSecond := make(chan struct{})
+	var stored []int64
+
+	p := &pipeline[int64]{
+		name:     pipelineLive,
+		fetchers: func() int { return 1 },
+		storers:  1,
+		depth:    1,
+		fetch: func(ctx context.Context, r ethereum.BlockRange) (int64, error) {
+			if r.From == 101 {
+				close(fetchedSecond)
+			}
+			if r.From == 301 {
+				return 0, errors.New("rpc down")
+			}
+			return r.From, nil
+		},
+		store: func(ctx context.Context, r ethereum.BlockRange, from int64) error {
+			// The first range is only stored once the next one has been fetched
+			if from == 1 {
+				select {
+				case <-fetchedSecond:
+				case <-time.After(5 * time.Second):
+					t.Error("expected the next range to be fetched while storing the first")
+				}
+			}
+			stored = append(stored, from)
+			return nil
+		},
+	}
+
+	err := p.run(context.Background(), ranges)
+	var rangeErr *rangeError
+	if !errors.As(err, &rangeErr) || rangeErr.r.From != 301 {
+		t.Fatalf("expected the failed fetch of range 301 as a range error, got %v", err)
+	}
+	if !reflect.DeepEqual(stored, []int64{1, 101, 201}) {
+		t.Errorf("expected the ranges before the failure stored in order, got %v", stored)
+	}
+
+	// A failed store stops the pipeline at its range
+	stored = nil
+	p.fetch = func(ctx context.Context, r ethereum.BlockRange) (int64, error) { return r.From, nil }
+	p.store = func(ctx context.Context, r ethereum.BlockRange, from int64) error {
+		if from == 101 {
+			return errors.New("db down")
+		}
+		stored = append(stored, from)
+		return nil
+	}
+	err = p.run(context.Background(), ranges)
+	if !errors.As(err, &rangeErr) || rangeErr.r.From != 101 {
+		t.Fatalf("expected the failed store of range 101 as a range error, got %v", err)
+	}
+	if !reflect.DeepEqual(stored, []int64{1}) {
+		t.Errorf("expected only the first range stored, got %v", stored)
+	}
+}
+
+func TestIndexNewBlocks_AutoscaledFetchAhead(t *testing.T) {
+	for _, combined := range []bool{false, true} {
+		it := newIndexerTest(t, 1000, map[string]int64{
+			testutil.USDTAddress: 0,
+			testutil.USDCAddress: 600,
+		}, combined)
+		it.service.config.WorkerCountMax = 8
+		it.service.config.AutoscaleLag = 500
+
+		it.rpc.AddLogs(
+			testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 50, 0),
+			testutil.TransferLog(testutil.USDTAddress, testutil.BobAddress, testutil.AliceAddress, 7, 450, 0),
+			testutil.TransferLog(testutil.USDCAddress, testutil.AliceAddress, testutil.BobAddress, 9, 900, 0),
+			testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.CharlieAddr, 11, 990, 0),
+		)
+
+		it.service.indexNewBlocks(context.Background())
+
+		for _, token := range []string{testutil.USDTAddress, testutil.USDCAddress} {
+			if got := it.checkpoint(t, token); got != 1000 {
+				t.Errorf("combined=%v: expected %s checkpoint 1000, got %d", combined, token, got)
+			}
+		}
+		if got := it.service.GetMetrics().TransfersIndexed; got != 4 {
+			t.Errorf("combined=%v: expected 4 transfers, got %d", combined, got)
+		}
+		// Back at the head the workers drop to INDEXER_WORKER_COUNT
+		if got := it.service.workers.Load(); got != 2 {
+			t.Errorf("combined=%v: expected 2 workers at the head, got %d", combined, got)
+		}
+	}
+}
+
+func TestIndexNewBlocks_IdleTokenBackoff(t *testing.T) {
+	for _, combined := range []bool{false, true} {
+		it := newIndexerTest(t, 1000, map[string]int64{
+			testutil.USDTAddress: 1000,
+			testutil.USDCAddress: 1000,
+		}, combined)
+		it.service.config.PollInterval = 12 * time.Second
+		it.service.config.IdleAfter = time.Hour
+		it.service.config.IdlePollMax = time.Minute
+		ctx := context.Background()
+
+		setProgress := func(update func(p *tokenProgress)) {
+			it.service.progressMu.Lock()
+			defer it.service.progressMu.Unlock()
+			for _, token := range []string{testutil.USDTAddress, testutil.USDCAddress} {
+				update(it.service.tokenProgressLocked(token))
+			}
+		}
+		poll := func(head uint64) []testutil.LogQuery {
+			before := len(it.rpc.Queries())
+			it.rpc.SetBlockNumber(head)
+			it.service.indexNewBlocks(ctx)
+			return it.rpc.Queries()[before:]
+		}
+
+		// Tokens start out active
+		it.service.indexNewBlocks(ctx)
+		setProgress(func(p *tokenProgress) { p.lastActivity = time.Now().Add(-2 * time.Hour) })
+
+		// Both idle and due: one combined query, then backed off
+		if queries := poll(1010); len(queries) != 1 || len(queries[0].Addresses) != 2 {
+			t.Fatalf("combined=%v: expected one query for both idle tokens, got %+v", combined, queries)
+		}
+		if got := it.checkpoint(t, testutil.USDCAddress); got != 1010 {
+			t.Errorf("combined=%v: expected the idle poll to advance the checkpoint, got %d", combined, got)
+		}
+		it.rpc.AddLogs(testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 1015, 0))
+		if queries := poll(1020); len(queries) != 0 {
+			t.Fatalf("combined=%v: expected no queries before the backoff elapsed, got %+v", combined, queries)
+		}
+
+		// The transfer found by the combined query makes USDT active again; USDC backs off further
+		setProgress(func(p *tokenProgress) { p.nextPoll = time.Time{} })
+		poll(1020)
+		if got := it.checkpoint(t, testutil.USDTAddress); got != 1020 {
+			t.Errorf("combined=%v: expected USDT checkpoint 1020, got %d", combined, got)
+		}
+		it.service.progressMu.RLock()
+		usdt, usdc := *it.service.progress[testutil.USDTAddress], *it.service.progress[testutil.USDCAddress]
+		it.service.progressMu.RUnlock()
+		if usdt.idle || !usdc.idle || usdc.idleBackoff != 48*time.Second {
+			t.Errorf("combined=%v: expected USDT active and USDC backed off 48s, got %v and %v (%s)", combined, usdt.idle, usdc.idle, usdc.idleBackoff)
+		}
+
+		queries := poll(1030)
+		if len(queries) != 1 || len(queries[0].Addresses) != 1 || queries[0].Addresses[0] != common.HexToAddress(testutil.USDTAddress) {
+			t.Errorf("combined=%v: expected only USDT polled, got %+v", combined, queries)
+		}
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	it := newIndexerTest(t, 1000, map[string]int64{testutil.USDTAddress: 900}, false)
+	it.service.config.HealthMaxFailures = 2
+	it.service.config.HealthStallAfter = time.Hour
+	it.service.config.HealthMaxLag = 100
+	it.service.startedAt = time.Now()
+	ctx := context.Background()
+
+	it.service.indexNewBlocks(ctx)
+	health := it.service.CheckHealth(ctx)
+	if health.Status != "healthy" || health.LagBlocks == nil || *health.LagBlocks != 0 || health.LastPassAt == "" {
+		t.Fatalf("expected a healthy indexer at the head, got %+v", health)
+	}
+
+	// Far behind: a catch-up under way is healthy, one without checkpoints advancing is not
+	it.service.catchUp.head.Store(1500)
+	if health := it.service.CheckHealth(ctx); health.Status != "healthy" || health.Checks["lag"] != "catching up: 500 blocks behind" {
+		t.Errorf("expected a healthy catch-up, got %+v", health)
+	}
+	it.service.metrics.mu.Lock()
+	it.service.metrics.LastCheckpointAt = time.Now().Add(-2 * time.Hour)
+	it.service.metrics.mu.Unlock()
+	if health := it.service.CheckHealth(ctx); health.Status != "unhealthy" || health.Checks["indexing"] != "healthy" {
+		t.Errorf("expected only the lag to be unhealthy, got %+v", health)
+	}
+	it.service.catchUp.head.Store(1000)
+
+	// A single failed pass is tolerated, consecutive ones are not
+	it.service.recordPass(errors.New("rpc timeout"))
+	if health := it.service.CheckHealth(ctx); health.Status != "healthy" || health.LastError != "rpc timeout" {
+		t.Errorf("expected healthy with the last error, got %+v", health)
+	}
+	it.service.recordPass(errors.New("rpc timeout"))
+	if health := it.service.CheckHealth(ctx); health.Status != "unhealthy" || health.Checks["indexing"] != "unhealthy: last 2 passes failed" {
+		t.Errorf("expected failing passes to be unhealthy, got %+v", health)
+	}
+	it.service.recordPass(nil)
+
+	// A loop that stopped finishing passes is stuck
+	it.service.metrics.mu.Lock()
+	it.service.metrics.LastIndexedTime = time.Now().Add(-2 * time.Hour)
+	it.service.metrics.mu.Unlock()
+	if health := it.service.CheckHealth(ctx); health.Status != "unhealthy" || health.Checks["rpc"] != "healthy" {
+		t.Errorf("expected a stuck loop to be unhealthy, got %+v", health)
+	}
+}
+
+func TestIndexNewBlocks_QuarantinesPanickingToken(t *testing.T) {
+	for _, combined := range []bool{false, true} {
+		it := newIndexerTest(t, 1000, map[string]int64{
+			testutil.USDTAddress: 900,
+			testutil.USDCAddress: 900,
+		}, combined)
+		it.service.config.QuarantineCooldown = time.Hour
+		it.rpc.AddLogs(
+			testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 950, 0),
+			testutil.TransferLog(testutil.USDCAddress, testutil.AliceAddress, testutil.BobAddress, 5, 950, 1),
+		)
+		it.transfers.BatchInsertFunc = func(ctx context.Context, transfers []entities.Transfer) (int64, error) {
+			if transfers[0].TokenAddress == testutil.USDTAddress {
+				panic("undecodable transfer")
+			}
+			return int64(len(transfers)), nil
+		}
+		ctx := context.Background()
+
+		it.service.indexPass(ctx)
+		if got := it.checkpoint(t, testutil.USDCAddress); got != 1000 {
+			t.Errorf("combined=%v: expected USDC to be indexed to 1000, got %d", combined, got)
+		}
+		if got := it.checkpoint(t, testutil.USDTAddress); got != 900 {
+			t.Errorf("combined=%v: expected USDT to stay at 900, got %d", combined, got)
+		}
+		status := TokenStatus{TokenAddress: testutil.USDTAddress}
+		it.service.applyTokenProgress(&status)
+		if status.QuarantinedUntil == "" || !strings.Contains(status.LastError, "undecodable transfer") {
+			t.Errorf("combined=%v: expected USDT quarantined with the panic as its error, got %+v", combined, status)
+		}
+
+		// Quarantined tokens are skipped until the cool-down ends
+		before := len(it.rpc.Queries())
+		it.rpc.SetBlockNumber(1010)
+		it.service.indexPass(ctx)
+		for _, query := range it.rpc.Queries()[before:] {
+			for _, addr := range query.Addresses {
+				if addr == common.HexToAddress(testutil.USDTAddress) {
+					t.Errorf("combined=%v: expected quarantined USDT not to be queried", combined)
+				}
+			}
+		}
+		if kept := it.service.skipQuarantined([]string{testutil.USDTAddress}, time.Now().Add(2*time.Hour)); len(kept) != 1 {
+			t.Errorf("combined=%v: expected USDT released after the cool-down, got %v", combined, kept)
+		}
+	}
+}
+
+func TestIndexNewBlocks_RecordsFailingRange(t *testing.T) {
+	for _, combined := range []bool{false, true} {
+		it := newIndexerTest(t, 1000, map[string]int64{
+			testutil.USDTAddress: 900,
+			testutil.USDCAddress: 900,
+		}, combined)
+		failedRanges := testutil.NewMockFailedRangeRepository()
+		it.service.WithFailedRanges(failedRanges)
+		it.service.config.RangeMaxRetries = 2
+		it.service.config.FailedRangePolicy = FailedRangeHalt
+		it.rpc.AddLogs(
+			testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 950, 0),
+			testutil.TransferLog(testutil.USDCAddress, testutil.AliceAddress, testutil.BobAddress, 5, 950, 1),
+		)
+		it.transfers.BatchInsertFunc = func(ctx context.Context, transfers []entities.Transfer) (int64, error) {
+			if transfers[0].TokenAddress == testutil.USDTAddress {
+				return 0, errors.New("value out of range")
+			}
+			return int64(len(transfers)), nil
+		}
+		ctx := context.Background()
+
+		// The range is recorded once it failed RangeMaxRetries times, and retried under halt
+		it.service.indexNewBlocks(ctx)
+		if ranges, _ := failedRanges.List(ctx, true); len(ranges) != 0 {
+			t.Fatalf("combined=%v: expected nothing recorded after one failure, got %+v", combined, ranges)
+		}
+		it.service.indexNewBlocks(ctx)
+		ranges, _ := failedRanges.List(ctx, true)
+		if len(ranges) != 1 || ranges[0].TokenAddress != testutil.USDTAddress || ranges[0].FromBlock != 901 ||
+			ranges[0].ToBlock != 1000 || ranges[0].Attempts != 2 || ranges[0].Skipped ||
+			!strings.Contains(ranges[0].LastError, "value out of range") {
+			t.Fatalf("combined=%v: expected USDT blocks 901-1000 recorded after 2 attempts, got %+v", combined, ranges)
+		}
+		if got := it.checkpoint(t, testutil.USDTAddress); got != 900 {
+			t.Errorf("combined=%v: expected USDT to stay at 900 under halt, got %d", combined, got)
+		}
+		if got := it.checkpoint(t, testutil.USDCAddress); got != 1000 {
+			t.Errorf("combined=%v: expected USDC to be indexed to 1000, got %d", combined, got)
+		}
+
+		// Under skip the checkpoint moves past the range
+		it.service.config.FailedRangePolicy = FailedRangeSkip
+		it.service.indexNewBlocks(ctx)
+		if got := it.checkpoint(t, testutil.USDTAddress); got != 1000 {
+			t.Errorf("combined=%v: expected USDT to skip to 1000, got %d", combined, got)
+		}
+		ranges, _ = failedRanges.List(ctx, true)
+		if len(ranges) != 1 || !ranges[0].Skipped || ranges[0].Attempts != 3 {
+			t.Errorf("combined=%v: expected the range marked skipped after 3 attempts, got %+v", combined, ranges)
+		}
+	}
+}
+
+func TestIndexNewBlocks_UpdatesWalletStats(t *testing.T) {
+	for _, duplicates := range []bool{false, true} {
+		it := newIndexerTest(t, 1000, map[string]int64{testutil.USDTAddress: 900}, false)
+		walletStats := testutil.NewMockWalletStatsRepository()
+		it.service.WithWalletStats(walletStats)
+		it.rpc.AddLogs(testutil.TransferLog(testutil.USDTAddress, testutil.AliceAddress, testutil.BobAddress, 5, 950, 0))
+		it.transfers.BatchInsertFunc = func(ctx context.Context, transfers []entities.Transfer) (int64, error) {
+			if duplicates {
+				return 0, nil
+			}
+			return int64(len(transfers)), nil
+		}
+
+		it.service.indexNewBlocks(context.Background())
+
+		// New transfers are added; a retried batch recomputes its wallets instead of counting twice
+		calls := walletStats.Calls
+		if len(calls) != 1 {
+			t.Fatalf("duplicates=%v: expected one wallet stats update, got %+v", duplicates, calls)
+		}
+		switch {
+		case !duplicates && (calls[0].Method != "Apply" || len(calls[0].Args[0].([]entities.Transfer)) != 1):
+			t.Errorf("expected the new transfer applied, got %+v", calls[0])
+		case duplicates && (calls[0].Method != "Refresh" ||
+			!reflect.DeepEqual(calls[0].Args[1], []string{testutil.AliceAddress, testutil.BobAddress})):
+			t.Errorf("expected Alice and Bob refreshed, got %+v", calls[0])
+		}
+	}
+}
+
+// stubChain is a non-EVM chain whose transfers are given by block
+type stubChain struct {
+	head      int64
+	transfers []entities.Transfer
+	fetches   []ethereum.BlockRange
+}
+
+func (c *stubChain) Family() entities.ChainFamily { return entities.ChainFamilyUTXO }
+
+func (c *stubChain) GetLatestBlockNumber(ctx context.Context) (int64, error) { return c.head, nil }
+
+func (c *stubChain) GetBlockTimestamp(ctx context.Context, blockNumber int64) (time.Time, error) {
+	return time.Unix(blockNumber*600, 0).UTC(), nil
+}
+
+func (c *stubChain) FetchTransfers(ctx context.Context, tokenAddresses []string, fromBlock, toBlock int64) (*chain.FetchResult, error) {
+	c.fetches = append(c.fetches, ethereum.BlockRange{From: fromBlock, To: toBlock})
+	result := &chain.FetchResult{Events: map[string][]any{}, FromBlock: fromBlock, ToBlock: toBlock}
+	for _, tr := range c.transfers {
+		if tr.BlockNumber >= fromBlock && tr.BlockNumber <= toBlock && slices.Contains(tokenAddresses, tr.TokenAddress) {
+			result.Transfers = append(result.Transfers, tr)
+		}
+	}
+	return result, nil
+}
+
+func (c *stubChain) DecodeRawLogs(raws []entities.RawLog, tokenAddresses []string) ([]entities.Transfer, map[string][]any, int, error) {
+	return nil, nil, len(raws), nil
+}
+
+func TestIndexNewBlocks_ChainAdapter(t *testing.T) {
+	const token = "bc1qtoken000000000000000000000000000000"
+	states := testutil.NewMockIndexerStateRepository()
+	states.AddState(&entities.IndexerState{TokenAddress: token, LastIndexedBlock: 100})
+	transfers := testutil.NewMockTransferRepository()
+	ethTransfers := testutil.NewMockEthTransferRepository()
+
+	stub := &stubChain{
+		head: 350,
+		transfers: []entities.Transfer{
+			{TxHash: "0xutxo1", LogIndex: 0, BlockNumber: 180, TokenAddress: token, FromAddress: "bc1qalice", ToAddress: "bc1qbob", Value: big.NewInt(5)},
+			{TxHash: "0xutxo2", LogIndex: 0, BlockNumber: 320, TokenAddress: token, FromAddress: "bc1qbob", ToAddress: "bc1qalice", Value: big.NewInt(2)},
+		},
+	}
+	cfg := config.IndexerConfig{BatchSize: 100, WorkerCount: 2, TokenAddresses: []string{token}}
+	service := NewIndexerService(nil, nil, testutil.NewMockTokenRepository(), transfers, states, cfg, zap.NewNop()).
+		WithEthTransfers(ethTransfers).
+		WithChainAdapter(stub)
+
+	service.indexNewBlocks(context.Background())
+
+	state, err := states.Get(context.Background(), token)
+	if err != nil || state == nil || state.LastIndexedBlock != 350 {
+		t.Fatalf("expected the checkpoint to follow the adapter's head, got %+v (%v)", state, err)
+	}
+	if len(stub.fetches) != 3 || stub.fetches[0] != (ethereum.BlockRange{From: 101, To: 200}) {
+		t.Errorf("expected 3 ranges fetched from block 101, got %v", stub.fetches)
+	}
+	stored, err := transfers.GetByFilter(context.Background(), entities.TransferFilter{Limit: 10})
+	if err != nil || len(stored) != 2 {
+		t.Errorf("expected the adapter's 2 transfers stored, got %d (%v)", len(stored), err)
+	}
+	if len(ethTransfers.Calls) != 0 {
+		t.Errorf("expected no ETH transfer pass on a UTXO chain, got %v", ethTransfers.Calls)
+	}
+
+	health := service.CheckHealth(context.Background())
+	if health.Checks["rpc"] != "healthy" {
+		t.Errorf("expected the adapter's head to answer the rpc check, got %+v", health.Checks)
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// IndexerStatus is the API representation of the indexer's progress
+type IndexerStatus struct {
+	ChainHead         int64          `json:"chain_head"`
+	BlocksIndexed     int64          `json:"blocks_indexed"`
+	TransfersIndexed  int64          `json:"transfers_indexed"`
+	DuplicatesSkipped int64          `json:"duplicates_skipped"`
+	ErrorCount        int64          `json:"error_count"`
+	IndexingLatencyMs int64          `json:"indexing_latency_ms"`
+	LastIndexedTime   string         `json:"last_indexed_time,omitempty"`
+	CatchUp           *CatchUpStatus `json:"catch_up,omitempty"` // Present while far behind the chain head
+	Tokens            []TokenStatus  `json:"tokens"`
+}
+
+// TokenStatus describes the indexing progress of a single token
+type TokenStatus struct {
+	TokenAddress     string          `json:"token_address"`
+	LastIndexedBlock int64           `json:"last_indexed_block"`
+	ChainHead        int64           `json:"chain_head"`
+	LagBlocks        int64           `json:"lag_blocks"`
+	LagSeconds       *int64          `json:"lag_seconds,omitempty"`
+	Backfill         *BackfillStatus `json:"backfill,omitempty"`
+	LastError        string          `json:"last_error,omitempty"`
+	LastErrorAt      string          `json:"last_error_at,omitempty"`
+	Idle             bool            `json:"idle,omitempty"`              // Polls backed off for lack of transfers
+	NextPollAt       string          `json:"next_poll_at,omitempty"`      // Next poll of an idle token
+	QuarantinedUntil string          `json:"quarantined_until,omitempty"` // Skipped after a panic until then
+	UpdatedAt        string          `json:"updated_at,omitempty"`
+}
+
+// BackfillStatus describes a running backfill for a token
+type BackfillStatus struct {
+	FromBlock    int64   `json:"from_block"`
+	ToBlock      int64   `json:"to_block"`
+	CurrentBlock int64   `json:"current_block"`
+	Progress     float64 `json:"progress"` // 0..1
+}
+
+// GetStatus builds a per-token status report from metrics, indexer state and the chain head
+func (s *IndexerService) GetStatus(ctx context.Context) (*IndexerStatus, error) {
+	chainHead, err := s.chain.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain head: %w", err)
+	}
+
+	metrics := s.GetMetrics()
+	status := &IndexerStatus{
+		ChainHead:         chainHead,
+		BlocksIndexed:     metrics.BlocksIndexed,
+		TransfersIndexed:  metrics.TransfersIndexed,
+		DuplicatesSkipped: metrics.DuplicatesSkipped,
+		ErrorCount:        metrics.ErrorCount,
+		IndexingLatencyMs: metrics.IndexingLatencyMs,
+		CatchUp:           s.catchUpStatus(),
+		Tokens:            make([]TokenStatus, 0, len(s.config.TokenAddresses)),
+	}
+	if !metrics.LastIndexedTime.IsZero() {
+		status.LastIndexedTime = metrics.LastIndexedTime.UTC().Format(time.RFC3339)
+	}
+
+	// Head timestamp is only needed for lag in seconds; failures are non-fatal
+	headTime, headTimeErr := s.chain.GetBlockTimestamp(ctx, chainHead)
+	if headTimeErr != nil {
+		s.logger.Warn("Failed to get chain head timestamp", zap.Error(headTimeErr))
+	}
+
+	for _, addr := range s.config.TokenAddresses {
+		addr = strings.ToLower(addr)
+
+		state, err := s.stateRepo.Get(ctx, addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get indexer state for %s: %w", addr, err)
+		}
+
+		tokenStatus := TokenStatus{
+			TokenAddress: addr,
+			ChainHead:    chainHead,
+		}
+
+		if state != nil {
+			tokenStatus.LastIndexedBlock = state.LastIndexedBlock
+			tokenStatus.UpdatedAt = state.UpdatedAt.UTC().Format(time.RFC3339)
+
+			if state.IsBackfilling && state.BackfillFromBlock != nil && state.BackfillToBlock != nil {
+				tokenStatus.Backfill = &BackfillStatus{
+					FromBlock: *state.BackfillFromBlock,
+					ToBlock:   *state.BackfillToBlock,
+				}
+			}
+		}
+
+		tokenStatus.LagBlocks = chainHead - tokenStatus.LastIndexedBlock
+		if tokenStatus.LagBlocks < 0 {
+			tokenStatus.LagBlocks = 0
+		}
+
+		if headTimeErr == nil && tokenStatus.LastIndexedBlock > 0 {
+			lastTime, err := s.chain.GetBlockTimestamp(ctx, tokenStatus.LastIndexedBlock)
+			if err != nil {
+				s.logger.Warn("Failed to get last indexed block timestamp",
+					zap.String("token", addr),
+					zap.Error(err),
+				)
+			} else {
+				lag := int64(headTime.Sub(lastTime).Seconds())
+				if lag < 0 {
+					lag = 0
+				}
+				tokenStatus.LagSeconds = &lag
+			}
+		}
+
+		s.applyTokenProgress(&tokenStatus)
+		status.Tokens = append(status.Tokens, tokenStatus)
+	}
+
+	return status, nil
+}
+
+// applyTokenProgress fills in-memory progress (errors, backfill position) into a token status
+func (s *IndexerService) applyTokenProgress(status *TokenStatus) {
+	s.progressMu.RLock()
+	defer s.progressMu.RUnlock()
+
+	p, ok := s.progress[status.TokenAddress]
+	if !ok {
+		return
+	}
+
+	if p.lastError != "" {
+		status.LastError = p.lastError
+		status.LastErrorAt = p.lastErrorAt.UTC().Format(time.RFC3339)
+	}
+
+	if p.idle {
+		status.Idle = true
+		status.NextPollAt = p.nextPoll.UTC().Format(time.RFC3339)
+	}
+
+	if !p.quarantinedUntil.IsZero() {
+		status.QuarantinedUntil = p.quarantinedUntil.UTC().Format(time.RFC3339)
+	}
+
+	if b := status.Backfill; b != nil && p.backfillBlock > 0 {
+		b.CurrentBlock = p.backfillBlock
+		if total := b.ToBlock - b.FromBlock + 1; total > 0 {
+			b.Progress = float64(b.CurrentBlock-b.FromBlock+1) / float64(total)
+		}
+	}
+}
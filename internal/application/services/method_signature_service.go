@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// MethodSignatureService manages the selector -> function name table used to label transfers
+type MethodSignatureService struct {
+	repo   repositories.MethodSignatureRepository
+	logger *zap.Logger
+}
+
+// NewMethodSignatureService creates a new method signature service
+func NewMethodSignatureService(repo repositories.MethodSignatureRepository, logger *zap.Logger) *MethodSignatureService {
+	return &MethodSignatureService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// MethodSignatureDTO is the API representation of a method signature
+type MethodSignatureDTO struct {
+	Selector  string `json:"selector"`
+	Name      string `json:"name"`
+	Label     string `json:"label"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// MethodSignatureResponse is the API response for a single method signature
+type MethodSignatureResponse struct {
+	Data MethodSignatureDTO `json:"data"`
+}
+
+// MethodSignatureListResponse is the API response for the method signature table
+type MethodSignatureListResponse struct {
+	Data []MethodSignatureDTO `json:"data"`
+}
+
+// ListSignatures returns every known method signature
+func (s *MethodSignatureService) ListSignatures(ctx context.Context) (*MethodSignatureListResponse, error) {
+	sigs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list method signatures: %w", err)
+	}
+
+	dtos := make([]MethodSignatureDTO, len(sigs))
+	for i, sig := range sigs {
+		dtos[i] = toMethodSignatureDTO(sig)
+	}
+
+	return &MethodSignatureListResponse{Data: dtos}, nil
+}
+
+// SetSignature adds a selector or replaces its name and label. Selector must be lowercase.
+func (s *MethodSignatureService) SetSignature(ctx context.Context, selector, name, label string) (*MethodSignatureResponse, error) {
+	sig := &entities.MethodSignature{
+		Selector: selector,
+		Name:     name,
+		Label:    label,
+	}
+	if err := s.repo.Upsert(ctx, sig); err != nil {
+		return nil, fmt.Errorf("failed to set method signature: %w", err)
+	}
+
+	s.logger.Info("Method signature updated",
+		zap.String("selector", selector),
+		zap.String("name", name),
+		zap.String("label", label),
+	)
+
+	return &MethodSignatureResponse{Data: toMethodSignatureDTO(*sig)}, nil
+}
+
+func toMethodSignatureDTO(sig entities.MethodSignature) MethodSignatureDTO {
+	return MethodSignatureDTO{
+		Selector:  sig.Selector,
+		Name:      sig.Name,
+		Label:     sig.Label,
+		UpdatedAt: sig.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
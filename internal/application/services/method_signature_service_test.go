@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestMethodSignatureService_SetSignature(t *testing.T) {
+	ctx := context.Background()
+	service := NewMethodSignatureService(testutil.NewMockMethodSignatureRepository(), zap.NewNop())
+
+	if _, err := service.SetSignature(ctx, "0x12345678", "deposit(uint256)", "bridge"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Setting a known selector again relabels it
+	response, err := service.SetSignature(ctx, "0x12345678", "deposit(uint256)", "staking")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Data.Label != "staking" {
+		t.Errorf("expected label staking, got %q", response.Data.Label)
+	}
+
+	list, err := service.ListSignatures(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Selector != "0x12345678" || list.Data[0].Label != "staking" {
+		t.Errorf("expected one relabeled signature, got %+v", list.Data)
+	}
+}
+
+func TestTransferService_GetTransfers_MethodLabels(t *testing.T) {
+	ctx := context.Background()
+
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithMethodSelector("0x38ed1739")),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithMethodSelector("0xdeadbeef")),
+		testutil.CreateTestTransfer(testutil.WithID(3)),
+	)
+
+	signatures := testutil.NewMockMethodSignatureRepository()
+	signatures.AddSignatures(entities.MethodSignature{
+		Selector: "0x38ed1739",
+		Name:     "swapExactTokensForTokens(uint256,uint256,address[],address,uint256)",
+		Label:    "swap",
+	})
+	service := NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, zap.NewNop()).WithMethodSignatures(signatures)
+
+	response, err := service.GetTransfers(ctx, entities.TransferFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	labels := make(map[string]TransferDTO)
+	for _, dto := range response.Transfers {
+		labels[dto.MethodSelector] = dto
+	}
+	if dto := labels["0x38ed1739"]; dto.MethodLabel != "swap" || dto.MethodName == "" {
+		t.Errorf("expected known selector to be labeled swap, got %+v", dto)
+	}
+	if dto := labels["0xdeadbeef"]; dto.MethodLabel != "" || dto.MethodName != "" {
+		t.Errorf("expected unknown selector to stay unlabeled, got %+v", dto)
+	}
+	if dto := labels[""]; dto.MethodLabel != "" {
+		t.Errorf("expected transfer without a selector to stay unlabeled, got %+v", dto)
+	}
+	if len(signatures.Calls) != 1 {
+		t.Errorf("expected selectors to be looked up in one call, got %d", len(signatures.Calls))
+	}
+}
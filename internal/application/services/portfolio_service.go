@@ -3,20 +3,45 @@ package services
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"sort"
 	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
 	"github.com/bimakw/chain-indexer/internal/domain/repositories"
 	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/pricing"
+	"github.com/bimakw/chain-indexer/internal/pkg/units"
 )
 
+// NativeBalanceProvider reads the current native ETH balance of an address
+type NativeBalanceProvider interface {
+	GetBalance(ctx context.Context, address string) (*big.Int, error)
+}
+
+// DefaultPortfolioCacheTTL is how long portfolios are cached unless configured otherwise
+const DefaultPortfolioCacheTTL = 2 * time.Minute
+
+// WalletSummaryCacheTTL is how long wallet summaries are cached
+const WalletSummaryCacheTTL = 5 * time.Minute
+
 // PortfolioService provides business logic for wallet portfolios
 type PortfolioService struct {
-	portfolioRepo repositories.PortfolioRepository
-	cache         *cache.RedisCache
-	logger        *zap.Logger
+	portfolioRepo    repositories.PortfolioRepository
+	walletStats      repositories.WalletStatsRepository
+	cache            *cache.RedisCache
+	prices           pricing.Provider
+	nativeBalances   NativeBalanceProvider
+	nativeBalanceTTL time.Duration
+	breaker          *Breaker
+	hotWallets       *HotWallets
+	notes            *AddressNoteService
+	exposure         *TokenExposure
+	cacheTTL         time.Duration
+	logger           *zap.Logger
 }
 
 // NewPortfolioService creates a new portfolio service
@@ -28,10 +53,63 @@ func NewPortfolioService(
 	return &PortfolioService{
 		portfolioRepo: portfolioRepo,
 		cache:         cache,
+		cacheTTL:      DefaultPortfolioCacheTTL,
 		logger:        logger,
 	}
 }
 
+// WithCacheTTL sets how long portfolios and single token holdings are cached
+func (s *PortfolioService) WithCacheTTL(ttl time.Duration) *PortfolioService {
+	s.cacheTTL = ttl
+	return s
+}
+
+// CacheTTL returns how long portfolios and single token holdings are cached
+func (s *PortfolioService) CacheTTL() time.Duration {
+	return s.cacheTTL
+}
+
+// WithPriceProvider enables USD enrichment of portfolio responses
+func (s *PortfolioService) WithPriceProvider(provider pricing.Provider) *PortfolioService {
+	s.prices = provider
+	return s
+}
+
+// WithWalletStats reads wallet transfer summaries from the totals the indexer maintains instead of
+// scanning the wallet's transfers, falling back to the scan for wallets without totals
+func (s *PortfolioService) WithWalletStats(repo repositories.WalletStatsRepository) *PortfolioService {
+	s.walletStats = repo
+	return s
+}
+
+// WithBreaker retries portfolio and wallet reads through b and serves stale cached responses while
+// the database is unavailable
+func (s *PortfolioService) WithBreaker(b *Breaker) *PortfolioService {
+	s.breaker = b
+	return s
+}
+
+// WithHotWallets counts wallet summary requests in h, which keeps the most requested summaries cached
+func (s *PortfolioService) WithHotWallets(h *HotWallets) *PortfolioService {
+	s.hotWallets = h
+	return s
+}
+
+// WithAddressNotes returns the request tenant's note on the wallet with its portfolio, summary
+// and token list
+func (s *PortfolioService) WithAddressNotes(notes *AddressNoteService) *PortfolioService {
+	s.notes = notes
+	return s
+}
+
+// WithNativeBalances enables AddNativeBalance. Balances are cached for ttl, since they change with
+// every block and are read from the node rather than the index.
+func (s *PortfolioService) WithNativeBalances(provider NativeBalanceProvider, ttl time.Duration) *PortfolioService {
+	s.nativeBalances = provider
+	s.nativeBalanceTTL = ttl
+	return s
+}
+
 // TokenHoldingDTO is the API representation of a token holding
 type TokenHoldingDTO struct {
 	TokenAddress     string `json:"token_address"`
@@ -40,18 +118,30 @@ type TokenHoldingDTO struct {
 	Decimals         int    `json:"decimals"`
 	Balance          string `json:"balance"`           // Raw wei
 	BalanceFormatted string `json:"balance_formatted"` // Human readable
+	ValueUSD         string `json:"value_usd,omitempty"`
 }
 
 // PortfolioSummary contains summary information for a portfolio
 type PortfolioSummary struct {
-	TotalTokens       int   `json:"total_tokens"`
-	TotalTransfersIn  int64 `json:"total_transfers_in"`
-	TotalTransfersOut int64 `json:"total_transfers_out"`
+	TotalTokens       int    `json:"total_tokens"`
+	TotalTransfersIn  int64  `json:"total_transfers_in"`
+	TotalTransfersOut int64  `json:"total_transfers_out"`
+	TotalValueUSD     string `json:"total_value_usd,omitempty"` // Sum over holdings with a known price
+}
+
+// NativeBalanceDTO is the API representation of a wallet's native ETH balance
+type NativeBalanceDTO struct {
+	Symbol           string `json:"symbol"`
+	Decimals         int    `json:"decimals"`
+	Balance          string `json:"balance"`           // Raw wei
+	BalanceFormatted string `json:"balance_formatted"` // Human readable
 }
 
 // PortfolioDTO is the API representation of a wallet portfolio
 type PortfolioDTO struct {
 	WalletAddress string            `json:"wallet_address"`
+	Note          string            `json:"note,omitempty"`           // The API key's tenant's note on the wallet
+	NativeBalance *NativeBalanceDTO `json:"native_balance,omitempty"` // Only with ?include_native=true
 	Holdings      []TokenHoldingDTO `json:"holdings"`
 	Summary       PortfolioSummary  `json:"summary"`
 	UpdatedAt     string            `json:"updated_at"`
@@ -70,6 +160,7 @@ type TokenHoldingResponse struct {
 // WalletSummaryDTO is the API representation of wallet summary
 type WalletSummaryDTO struct {
 	WalletAddress     string  `json:"wallet_address"`
+	Note              string  `json:"note,omitempty"` // The API key's tenant's note on the wallet
 	TotalTransfersIn  int64   `json:"total_transfers_in"`
 	TotalTransfersOut int64   `json:"total_transfers_out"`
 	TotalVolumeIn     string  `json:"total_volume_in"`
@@ -84,6 +175,14 @@ type WalletSummaryResponse struct {
 	Data WalletSummaryDTO `json:"data"`
 }
 
+// WithExposure leaves the tokens e does not expose to the request out of holdings, token
+// activity, history and graphs. Responses are cached complete and narrowed as they are served,
+// except graphs, whose traversal depends on the tokens followed.
+func (s *PortfolioService) WithExposure(e *TokenExposure) *PortfolioService {
+	s.exposure = e
+	return s
+}
+
 // GetPortfolio retrieves complete portfolio for a wallet address
 func (s *PortfolioService) GetPortfolio(ctx context.Context, walletAddress string) (*PortfolioResponse, error) {
 	walletAddress = strings.ToLower(walletAddress)
@@ -96,10 +195,31 @@ func (s *PortfolioService) GetPortfolio(ctx context.Context, walletAddress strin
 	if s.cache != nil {
 		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
 			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			cached.Data.Note = s.walletNote(ctx, walletAddress)
+			s.exposeHoldings(ctx, &cached.Data)
 			return &cached, nil
 		}
 	}
 
+	response, err := guardedLoad(ctx, s.breaker, s.cache, s.logger, "portfolio.GetPortfolio", cacheKey, func(ctx context.Context) (*PortfolioResponse, error) {
+		return s.loadPortfolio(ctx, walletAddress, cacheKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+	response.Data.Note = s.walletNote(ctx, walletAddress)
+	s.exposeHoldings(ctx, &response.Data)
+	return response, nil
+}
+
+// exposeHoldings leaves the holdings of tokens the request may not see out of a portfolio
+func (s *PortfolioService) exposeHoldings(ctx context.Context, portfolio *PortfolioDTO) {
+	portfolio.Holdings = exposedOnly(ctx, s.exposure, portfolio.Holdings, func(h TokenHoldingDTO) string { return h.TokenAddress })
+	portfolio.Summary.TotalTokens = len(portfolio.Holdings)
+}
+
+// loadPortfolio reads a wallet's portfolio from the database and caches it under cacheKey
+func (s *PortfolioService) loadPortfolio(ctx context.Context, walletAddress, cacheKey string) (*PortfolioResponse, error) {
 	// Get holdings from database
 	holdings, err := s.portfolioRepo.GetWalletHoldings(ctx, walletAddress)
 	if err != nil {
@@ -107,7 +227,7 @@ func (s *PortfolioService) GetPortfolio(ctx context.Context, walletAddress strin
 	}
 
 	// Get transfer summary for the wallet
-	summary, err := s.portfolioRepo.GetWalletTransferSummary(ctx, walletAddress)
+	summary, err := s.walletTransferSummary(ctx, walletAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wallet summary: %w", err)
 	}
@@ -138,9 +258,9 @@ func (s *PortfolioService) GetPortfolio(ctx context.Context, walletAddress strin
 		},
 	}
 
-	// Cache the response (2 minutes TTL for portfolio)
+	// Cache the response for the portfolio TTL
 	if s.cache != nil {
-		if err := s.cache.SetWithTTL(ctx, cacheKey, response, 2*time.Minute); err != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, s.cacheTTL); err != nil {
 			s.logger.Warn("Failed to cache response", zap.Error(err))
 		}
 	}
@@ -148,10 +268,109 @@ func (s *PortfolioService) GetPortfolio(ctx context.Context, walletAddress strin
 	return response, nil
 }
 
+// AddNativeBalance fills the wallet's native ETH balance. It is a no-op when no balance provider
+// is configured; a failed lookup is logged and leaves the balance out.
+func (s *PortfolioService) AddNativeBalance(ctx context.Context, response *PortfolioResponse) {
+	if s.nativeBalances == nil || response == nil {
+		return
+	}
+
+	balance, err := s.getNativeBalance(ctx, response.Data.WalletAddress)
+	if err != nil {
+		s.logger.Warn("Failed to get native balance",
+			zap.String("address", response.Data.WalletAddress),
+			zap.Error(err),
+		)
+		return
+	}
+
+	response.Data.NativeBalance = &NativeBalanceDTO{
+		Symbol:           "ETH",
+		Decimals:         ethDecimals,
+		Balance:          balance,
+		BalanceFormatted: units.Format(balance, ethDecimals),
+	}
+}
+
+// getNativeBalance returns an address's balance in wei as a decimal string, cached for nativeBalanceTTL
+func (s *PortfolioService) getNativeBalance(ctx context.Context, walletAddress string) (string, error) {
+	cacheKey := fmt.Sprintf("native_balance:%s", walletAddress)
+
+	var cached string
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return cached, nil
+		}
+	}
+
+	balance, err := s.nativeBalances.GetBalance(ctx, walletAddress)
+	if err != nil {
+		return "", err
+	}
+
+	if s.cache != nil && s.nativeBalanceTTL > 0 {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, balance.String(), s.nativeBalanceTTL); err != nil {
+			s.logger.Warn("Failed to cache native balance", zap.Error(err))
+		}
+	}
+
+	return balance.String(), nil
+}
+
+// AddUSDValues fills value_usd for each holding at current prices, plus the portfolio total.
+// It is a no-op when no price provider is configured.
+func (s *PortfolioService) AddUSDValues(ctx context.Context, response *PortfolioResponse) {
+	if s.prices == nil || response == nil {
+		return
+	}
+
+	prices := newPriceLookup(s.prices, s.logger)
+	total := new(big.Float)
+	priced := false
+
+	for i := range response.Data.Holdings {
+		h := &response.Data.Holdings[i]
+		s.addHoldingUSDValue(ctx, prices, h)
+		if h.ValueUSD == "" {
+			continue
+		}
+		if v, ok := new(big.Float).SetString(h.ValueUSD); ok {
+			total.Add(total, v)
+			priced = true
+		}
+	}
+
+	if priced {
+		response.Data.Summary.TotalValueUSD = total.Text('f', 2)
+	}
+}
+
+// AddHoldingUSDValue fills value_usd for a single holding at the current price
+func (s *PortfolioService) AddHoldingUSDValue(ctx context.Context, response *TokenHoldingResponse) {
+	if s.prices == nil || response == nil {
+		return
+	}
+	s.addHoldingUSDValue(ctx, newPriceLookup(s.prices, s.logger), &response.Data)
+}
+
+func (s *PortfolioService) addHoldingUSDValue(ctx context.Context, prices *priceLookup, h *TokenHoldingDTO) {
+	price, ok := prices.current(ctx, h.TokenAddress)
+	if !ok {
+		return
+	}
+	if usd, ok := pricing.ValueUSD(h.BalanceFormatted, price); ok {
+		h.ValueUSD = usd
+	}
+}
+
 // GetPortfolioByToken retrieves holding for specific token in a wallet
 func (s *PortfolioService) GetPortfolioByToken(ctx context.Context, walletAddress, tokenAddress string) (*TokenHoldingResponse, error) {
 	walletAddress = strings.ToLower(walletAddress)
 	tokenAddress = strings.ToLower(tokenAddress)
+	if !s.exposure.Exposes(ctx, tokenAddress) {
+		return nil, nil
+	}
 
 	// Generate cache key
 	cacheKey := fmt.Sprintf("portfolio:%s:%s", walletAddress, tokenAddress)
@@ -165,6 +384,13 @@ func (s *PortfolioService) GetPortfolioByToken(ctx context.Context, walletAddres
 		}
 	}
 
+	return guardedLoad(ctx, s.breaker, s.cache, s.logger, "portfolio.GetPortfolioByToken", cacheKey, func(ctx context.Context) (*TokenHoldingResponse, error) {
+		return s.loadPortfolioByToken(ctx, walletAddress, tokenAddress, cacheKey)
+	})
+}
+
+// loadPortfolioByToken reads a wallet's holding of one token from the database and caches it under cacheKey
+func (s *PortfolioService) loadPortfolioByToken(ctx context.Context, walletAddress, tokenAddress, cacheKey string) (*TokenHoldingResponse, error) {
 	// Get holding from database
 	holding, err := s.portfolioRepo.GetWalletHoldingByToken(ctx, walletAddress, tokenAddress)
 	if err != nil {
@@ -186,9 +412,9 @@ func (s *PortfolioService) GetPortfolioByToken(ctx context.Context, walletAddres
 		},
 	}
 
-	// Cache the response (2 minutes TTL)
+	// Cache the response for the portfolio TTL
 	if s.cache != nil {
-		if err := s.cache.SetWithTTL(ctx, cacheKey, response, 2*time.Minute); err != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, s.cacheTTL); err != nil {
 			s.logger.Warn("Failed to cache response", zap.Error(err))
 		}
 	}
@@ -199,6 +425,9 @@ func (s *PortfolioService) GetPortfolioByToken(ctx context.Context, walletAddres
 // GetWalletSummary retrieves transfer summary for a wallet
 func (s *PortfolioService) GetWalletSummary(ctx context.Context, walletAddress string) (*WalletSummaryResponse, error) {
 	walletAddress = strings.ToLower(walletAddress)
+	if s.hotWallets != nil {
+		s.hotWallets.record(walletAddress)
+	}
 
 	// Generate cache key
 	cacheKey := fmt.Sprintf("wallet_summary:%s", walletAddress)
@@ -208,12 +437,58 @@ func (s *PortfolioService) GetWalletSummary(ctx context.Context, walletAddress s
 	if s.cache != nil {
 		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
 			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			cached.Data.Note = s.walletNote(ctx, walletAddress)
 			return &cached, nil
 		}
 	}
 
+	response, err := guardedLoad(ctx, s.breaker, s.cache, s.logger, "portfolio.GetWalletSummary", cacheKey, func(ctx context.Context) (*WalletSummaryResponse, error) {
+		return s.loadWalletSummary(ctx, walletAddress, cacheKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+	response.Data.Note = s.walletNote(ctx, walletAddress)
+	return response, nil
+}
+
+// walletNote returns the request tenant's note on a wallet. Notes are added after caching, since
+// cached responses are shared by all tenants.
+func (s *PortfolioService) walletNote(ctx context.Context, walletAddress string) string {
+	if s.notes == nil {
+		return ""
+	}
+	return s.notes.notesFor(ctx, []string{walletAddress})[walletAddress]
+}
+
+// walletTransferSummary reads a wallet's transfer summary from the wallet stats when configured.
+// Wallets without stats, and reads that fail, fall back to scanning the wallet's transfers.
+func (s *PortfolioService) walletTransferSummary(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, error) {
+	if s.walletStats != nil {
+		summary, found, err := s.walletStats.GetSummary(ctx, walletAddress)
+		switch {
+		case err != nil:
+			s.logger.Warn("Failed to read wallet stats, scanning transfers", zap.String("wallet", walletAddress), zap.Error(err))
+		case found:
+			return summary, nil
+		}
+	}
+	return s.portfolioRepo.GetWalletTransferSummary(ctx, walletAddress)
+}
+
+// warmWalletSummary reloads a wallet's cached summary
+func (s *PortfolioService) warmWalletSummary(ctx context.Context, walletAddress string) error {
+	cacheKey := fmt.Sprintf("wallet_summary:%s", walletAddress)
+	_, err := guardedLoad(ctx, s.breaker, s.cache, s.logger, "portfolio.GetWalletSummary", cacheKey, func(ctx context.Context) (*WalletSummaryResponse, error) {
+		return s.loadWalletSummary(ctx, walletAddress, cacheKey)
+	})
+	return err
+}
+
+// loadWalletSummary reads a wallet's transfer summary from the database and caches it under cacheKey
+func (s *PortfolioService) loadWalletSummary(ctx context.Context, walletAddress, cacheKey string) (*WalletSummaryResponse, error) {
 	// Get summary from database
-	summary, err := s.portfolioRepo.GetWalletTransferSummary(ctx, walletAddress)
+	summary, err := s.walletTransferSummary(ctx, walletAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wallet summary: %w", err)
 	}
@@ -242,7 +517,105 @@ func (s *PortfolioService) GetWalletSummary(ctx context.Context, walletAddress s
 		},
 	}
 
-	// Cache the response (5 minutes TTL for summary)
+	// Cache the response
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, WalletSummaryCacheTTL); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// WalletTokenDTO is the API representation of a wallet's activity in one token
+type WalletTokenDTO struct {
+	TokenAddress     string `json:"token_address"`
+	TokenName        string `json:"token_name"`
+	TokenSymbol      string `json:"token_symbol"`
+	Decimals         int    `json:"decimals"`
+	Balance          string `json:"balance"`           // Raw wei, may be zero
+	BalanceFormatted string `json:"balance_formatted"` // Human readable
+	TransfersIn      int64  `json:"transfers_in"`
+	TransfersOut     int64  `json:"transfers_out"`
+	FirstActivityAt  string `json:"first_activity_at"`
+	LastActivityAt   string `json:"last_activity_at"`
+}
+
+// WalletTokensDTO lists every token a wallet has interacted with
+type WalletTokensDTO struct {
+	WalletAddress string           `json:"wallet_address"`
+	Note          string           `json:"note,omitempty"` // The API key's tenant's note on the wallet
+	Tokens        []WalletTokenDTO `json:"tokens"`
+}
+
+// WalletTokensResponse wraps wallet token activity for API response
+type WalletTokensResponse struct {
+	Data WalletTokensDTO `json:"data"`
+}
+
+// GetWalletTokens lists every token a wallet has sent or received, most recently active first.
+// Unlike the portfolio, tokens the wallet no longer holds are included.
+func (s *PortfolioService) GetWalletTokens(ctx context.Context, walletAddress string) (*WalletTokensResponse, error) {
+	walletAddress = strings.ToLower(walletAddress)
+
+	// Generate cache key
+	cacheKey := fmt.Sprintf("wallet_tokens:%s", walletAddress)
+
+	// Try cache first
+	var cached WalletTokensResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			cached.Data.Note = s.walletNote(ctx, walletAddress)
+			cached.Data.Tokens = exposedOnly(ctx, s.exposure, cached.Data.Tokens, walletTokenAddress)
+			return &cached, nil
+		}
+	}
+
+	response, err := guardedLoad(ctx, s.breaker, s.cache, s.logger, "portfolio.GetWalletTokens", cacheKey, func(ctx context.Context) (*WalletTokensResponse, error) {
+		return s.loadWalletTokens(ctx, walletAddress, cacheKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+	response.Data.Note = s.walletNote(ctx, walletAddress)
+	response.Data.Tokens = exposedOnly(ctx, s.exposure, response.Data.Tokens, walletTokenAddress)
+	return response, nil
+}
+
+func walletTokenAddress(t WalletTokenDTO) string { return t.TokenAddress }
+
+// loadWalletTokens reads a wallet's token activity from the database and caches it under cacheKey
+func (s *PortfolioService) loadWalletTokens(ctx context.Context, walletAddress, cacheKey string) (*WalletTokensResponse, error) {
+	activity, err := s.portfolioRepo.GetWalletTokenActivity(ctx, walletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet token activity: %w", err)
+	}
+
+	tokens := make([]WalletTokenDTO, len(activity))
+	for i, a := range activity {
+		tokens[i] = WalletTokenDTO{
+			TokenAddress:     a.TokenAddress,
+			TokenName:        a.TokenName,
+			TokenSymbol:      a.TokenSymbol,
+			Decimals:         a.Decimals,
+			Balance:          a.Balance,
+			BalanceFormatted: units.Format(a.Balance, a.Decimals),
+			TransfersIn:      a.TransfersIn,
+			TransfersOut:     a.TransfersOut,
+			FirstActivityAt:  a.FirstActivityAt.Format(time.RFC3339),
+			LastActivityAt:   a.LastActivityAt.Format(time.RFC3339),
+		}
+	}
+
+	response := &WalletTokensResponse{
+		Data: WalletTokensDTO{
+			WalletAddress: walletAddress,
+			Tokens:        tokens,
+		},
+	}
+
+	// Cache the response (5 minutes TTL, like the summary)
 	if s.cache != nil {
 		if err := s.cache.SetWithTTL(ctx, cacheKey, response, 5*time.Minute); err != nil {
 			s.logger.Warn("Failed to cache response", zap.Error(err))
@@ -251,3 +624,190 @@ func (s *PortfolioService) GetWalletSummary(ctx context.Context, walletAddress s
 
 	return response, nil
 }
+
+// HistoryIntervalDay is the only supported portfolio history interval
+const HistoryIntervalDay = "day"
+
+// HistoricalHoldingDTO is a token balance at the end of a history interval
+type HistoricalHoldingDTO struct {
+	TokenAddress     string `json:"token_address"`
+	Balance          string `json:"balance"`
+	BalanceFormatted string `json:"balance_formatted"`
+	ValueUSD         string `json:"value_usd,omitempty"`
+}
+
+// PortfolioHistoryPoint is the portfolio value at the end of one interval
+type PortfolioHistoryPoint struct {
+	Date     string                 `json:"date"`
+	ValueUSD string                 `json:"value_usd,omitempty"` // Sum over holdings with a known price
+	Holdings []HistoricalHoldingDTO `json:"holdings"`
+}
+
+// PortfolioHistoryDTO is the API representation of a wallet's value over time
+type PortfolioHistoryDTO struct {
+	WalletAddress string                  `json:"wallet_address"`
+	Interval      string                  `json:"interval"`
+	Points        []PortfolioHistoryPoint `json:"points"`
+}
+
+// PortfolioHistoryResponse wraps portfolio history for API response
+type PortfolioHistoryResponse struct {
+	Data PortfolioHistoryDTO `json:"data"`
+}
+
+// GetPortfolioHistory reconstructs end-of-day balances for the last `days` days (including today)
+// from transfers and values them at historical prices. Completed days are cached as snapshots,
+// so repeated requests only re-price today and any day whose balances changed.
+func (s *PortfolioService) GetPortfolioHistory(ctx context.Context, walletAddress string, days int) (*PortfolioHistoryResponse, error) {
+	walletAddress = strings.ToLower(walletAddress)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -(days - 1))
+
+	opening, err := s.portfolioRepo.GetWalletBalancesAt(ctx, walletAddress, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get opening balances: %w", err)
+	}
+
+	changes, err := s.portfolioRepo.GetWalletDailyBalanceChanges(ctx, walletAddress, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily balance changes: %w", err)
+	}
+	opening = exposedOnly(ctx, s.exposure, opening, func(h entities.TokenHolding) string { return h.TokenAddress })
+	changes = exposedOnly(ctx, s.exposure, changes, func(c repositories.DailyBalanceChange) string { return c.TokenAddress })
+
+	balances := make(map[string]*big.Int)
+	decimals := make(map[string]int)
+	for _, h := range opening {
+		if b, ok := new(big.Int).SetString(h.BalanceStr, 10); ok {
+			balances[h.TokenAddress] = b
+			decimals[h.TokenAddress] = h.Decimals
+		}
+	}
+
+	var prices *priceLookup
+	if s.prices != nil {
+		prices = newPriceLookup(s.prices, s.logger)
+	}
+
+	points := make([]PortfolioHistoryPoint, 0, days)
+	next := 0
+	for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+		for ; next < len(changes) && !changes[next].Day.After(day); next++ {
+			c := changes[next]
+			delta, ok := new(big.Int).SetString(c.Change, 10)
+			if !ok {
+				continue
+			}
+			if balances[c.TokenAddress] == nil {
+				balances[c.TokenAddress] = new(big.Int)
+			}
+			balances[c.TokenAddress].Add(balances[c.TokenAddress], delta)
+			decimals[c.TokenAddress] = c.Decimals
+		}
+
+		point := PortfolioHistoryPoint{
+			Date:     day.Format("2006-01-02"),
+			Holdings: historicalHoldings(balances, decimals),
+		}
+		if prices != nil {
+			s.valueHistoryPoint(ctx, prices, walletAddress, day, day.Equal(today), &point)
+		}
+		points = append(points, point)
+	}
+
+	return &PortfolioHistoryResponse{
+		Data: PortfolioHistoryDTO{
+			WalletAddress: walletAddress,
+			Interval:      HistoryIntervalDay,
+			Points:        points,
+		},
+	}, nil
+}
+
+// historicalHoldings returns the non-zero balances sorted by token address
+func historicalHoldings(balances map[string]*big.Int, decimals map[string]int) []HistoricalHoldingDTO {
+	holdings := make([]HistoricalHoldingDTO, 0, len(balances))
+	for token, balance := range balances {
+		if balance.Sign() == 0 {
+			continue
+		}
+		raw := balance.String()
+		holdings = append(holdings, HistoricalHoldingDTO{
+			TokenAddress:     token,
+			Balance:          raw,
+			BalanceFormatted: units.Format(raw, decimals[token]),
+		})
+	}
+	sort.Slice(holdings, func(i, j int) bool {
+		return holdings[i].TokenAddress < holdings[j].TokenAddress
+	})
+	return holdings
+}
+
+// valueHistoryPoint prices a history point at the end of its day (current prices for today).
+// Completed days are reused from the snapshot cache when their balances are unchanged.
+func (s *PortfolioService) valueHistoryPoint(ctx context.Context, prices *priceLookup, walletAddress string, day time.Time, isToday bool, point *PortfolioHistoryPoint) {
+	cacheKey := fmt.Sprintf("portfolio_history:%s:%s", walletAddress, point.Date)
+
+	if !isToday && s.cache != nil {
+		var cached PortfolioHistoryPoint
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil && sameHoldings(cached.Holdings, point.Holdings) {
+			*point = cached
+			return
+		}
+	}
+
+	at := day.Add(24*time.Hour - time.Second)
+	total := new(big.Float)
+	priced := false
+
+	for i := range point.Holdings {
+		h := &point.Holdings[i]
+
+		var price float64
+		var ok bool
+		if isToday {
+			price, ok = prices.current(ctx, h.TokenAddress)
+		} else {
+			price, ok = prices.historical(ctx, h.TokenAddress, at)
+		}
+		if !ok {
+			continue
+		}
+
+		usd, ok := pricing.ValueUSD(h.BalanceFormatted, price)
+		if !ok {
+			continue
+		}
+		h.ValueUSD = usd
+		if v, ok := new(big.Float).SetString(usd); ok {
+			total.Add(total, v)
+			priced = true
+		}
+	}
+
+	if priced {
+		point.ValueUSD = total.Text('f', 2)
+	}
+
+	// Snapshot completed days (24 hours TTL, like historical prices)
+	if !isToday && s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, point, 24*time.Hour); err != nil {
+			s.logger.Warn("Failed to cache portfolio snapshot", zap.Error(err))
+		}
+	}
+}
+
+// sameHoldings reports whether a cached snapshot was computed from the same balances
+func sameHoldings(a, b []HistoricalHoldingDTO) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].TokenAddress != b[i].TokenAddress || a[i].Balance != b[i].Balance {
+			return false
+		}
+	}
+	return true
+}
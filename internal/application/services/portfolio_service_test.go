@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
@@ -276,3 +278,348 @@ func TestPortfolioService_GetWalletSummary(t *testing.T) {
 		}
 	})
 }
+
+func TestPortfolioService_GetWalletSummary_WalletStats(t *testing.T) {
+	ctx := context.Background()
+	const wallet = "0x1234567890123456789012345678901234567890"
+
+	mockRepo := testutil.NewMockPortfolioRepository()
+	mockRepo.GetWalletTransferSummaryFunc = func(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, error) {
+		return &repositories.WalletTransferSummary{TotalTransfersIn: 1, TotalVolumeIn: "10", TotalVolumeOut: "0", UniqueTokens: 1}, nil
+	}
+	walletStats := testutil.NewMockWalletStatsRepository()
+	service := NewPortfolioService(mockRepo, nil, zap.NewNop()).WithWalletStats(walletStats)
+
+	scans := func() int {
+		n := 0
+		for _, call := range mockRepo.Calls {
+			if call.Method == "GetWalletTransferSummary" {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Stats are preferred when the wallet has them
+	walletStats.GetSummaryFunc = func(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, bool, error) {
+		return &repositories.WalletTransferSummary{TotalTransfersIn: 150, TotalVolumeIn: "5000", TotalVolumeOut: "0", UniqueTokens: 5}, true, nil
+	}
+	result, err := service.GetWalletSummary(ctx, wallet)
+	if err != nil || result.Data.TotalTransfersIn != 150 || result.Data.UniqueTokens != 5 || scans() != 0 {
+		t.Fatalf("expected the summary from wallet stats without a scan, got %+v after %d scans (%v)", result, scans(), err)
+	}
+
+	// Wallets without stats, and failed reads, fall back to the scan
+	for _, stats := range []func(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, bool, error){
+		func(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, bool, error) {
+			return nil, false, nil
+		},
+		func(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, bool, error) {
+			return nil, false, errors.New("relation \"wallet_stats\" does not exist")
+		},
+	} {
+		walletStats.GetSummaryFunc = stats
+		before := scans()
+		result, err := service.GetWalletSummary(ctx, wallet)
+		if err != nil || result.Data.TotalTransfersIn != 1 || scans() != before+1 {
+			t.Errorf("expected the summary from the scan, got %+v (%v)", result, err)
+		}
+	}
+}
+
+func TestPortfolioService_AddUSDValues(t *testing.T) {
+	ctx := context.Background()
+
+	service := NewPortfolioService(testutil.NewMockPortfolioRepository(), nil, zap.NewNop()).
+		WithPriceProvider(testutil.NewMockPriceProvider(map[string]float64{
+			"0xdac17f958d2ee523a2206206994597c13d831ec7": 1,
+			"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": 0.5,
+		}))
+
+	response := &PortfolioResponse{Data: PortfolioDTO{Holdings: []TokenHoldingDTO{
+		{TokenAddress: "0xdac17f958d2ee523a2206206994597c13d831ec7", BalanceFormatted: "1000"},
+		{TokenAddress: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", BalanceFormatted: "10.5"},
+		{TokenAddress: "0x3333333333333333333333333333333333333333", BalanceFormatted: "99"},
+	}}}
+
+	service.AddUSDValues(ctx, response)
+
+	if response.Data.Holdings[0].ValueUSD != "1000.00" {
+		t.Errorf("expected 1000.00, got %q", response.Data.Holdings[0].ValueUSD)
+	}
+	if response.Data.Holdings[1].ValueUSD != "5.25" {
+		t.Errorf("expected 5.25, got %q", response.Data.Holdings[1].ValueUSD)
+	}
+	if response.Data.Holdings[2].ValueUSD != "" {
+		t.Errorf("expected no value for unpriced token, got %q", response.Data.Holdings[2].ValueUSD)
+	}
+	if response.Data.Summary.TotalValueUSD != "1005.25" {
+		t.Errorf("expected total 1005.25, got %q", response.Data.Summary.TotalValueUSD)
+	}
+}
+
+func TestPortfolioService_AddNativeBalance(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fills the wallet's ETH balance", func(t *testing.T) {
+		balances := testutil.NewMockNativeBalanceProvider(map[string]*big.Int{
+			testutil.AliceAddress: big.NewInt(2_500_000_000_000_000_000),
+		})
+		service := NewPortfolioService(testutil.NewMockPortfolioRepository(), nil, zap.NewNop()).
+			WithNativeBalances(balances, time.Minute)
+
+		response := &PortfolioResponse{Data: PortfolioDTO{WalletAddress: testutil.AliceAddress}}
+		service.AddNativeBalance(ctx, response)
+
+		native := response.Data.NativeBalance
+		if native == nil {
+			t.Fatal("expected native balance")
+		}
+		if native.Symbol != "ETH" || native.Decimals != 18 || native.Balance != "2500000000000000000" || native.BalanceFormatted != "2.5" {
+			t.Errorf("unexpected native balance: %+v", native)
+		}
+	})
+
+	t.Run("leaves the balance out when the lookup fails", func(t *testing.T) {
+		balances := testutil.NewMockNativeBalanceProvider(nil)
+		balances.GetBalanceFunc = func(ctx context.Context, address string) (*big.Int, error) {
+			return nil, errors.New("rpc unavailable")
+		}
+		service := NewPortfolioService(testutil.NewMockPortfolioRepository(), nil, zap.NewNop()).
+			WithNativeBalances(balances, time.Minute)
+
+		response := &PortfolioResponse{Data: PortfolioDTO{WalletAddress: testutil.AliceAddress}}
+		service.AddNativeBalance(ctx, response)
+
+		if response.Data.NativeBalance != nil {
+			t.Errorf("expected no native balance, got %+v", response.Data.NativeBalance)
+		}
+	})
+
+	t.Run("is a no-op without a provider", func(t *testing.T) {
+		service := NewPortfolioService(testutil.NewMockPortfolioRepository(), nil, zap.NewNop())
+
+		response := &PortfolioResponse{Data: PortfolioDTO{WalletAddress: testutil.AliceAddress}}
+		service.AddNativeBalance(ctx, response)
+
+		if response.Data.NativeBalance != nil {
+			t.Error("expected no native balance")
+		}
+	})
+}
+
+func TestPortfolioService_GetPortfolioHistory(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	const usdt = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+	const usdc = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	newRepo := func() *testutil.MockPortfolioRepository {
+		mockRepo := testutil.NewMockPortfolioRepository()
+		mockRepo.GetWalletBalancesAtFunc = func(ctx context.Context, walletAddress string, at time.Time) ([]entities.TokenHolding, error) {
+			if !at.Equal(today.AddDate(0, 0, -2)) {
+				t.Errorf("unexpected opening time %v", at)
+			}
+			return []entities.TokenHolding{{TokenAddress: usdt, Decimals: 6, BalanceStr: "1000000"}}, nil
+		}
+		mockRepo.GetWalletDailyBalanceChangesFunc = func(ctx context.Context, walletAddress string, from time.Time) ([]repositories.DailyBalanceChange, error) {
+			return []repositories.DailyBalanceChange{
+				{TokenAddress: usdc, Decimals: 6, Day: today.AddDate(0, 0, -1), Change: "2500000"},
+				{TokenAddress: usdt, Decimals: 6, Day: today, Change: "-1000000"},
+			}, nil
+		}
+		return mockRepo
+	}
+
+	t.Run("reconstructs daily balances", func(t *testing.T) {
+		service := NewPortfolioService(newRepo(), nil, logger)
+
+		result, err := service.GetPortfolioHistory(ctx, "0x1234567890123456789012345678901234567890", 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		points := result.Data.Points
+		if len(points) != 3 {
+			t.Fatalf("expected 3 points, got %d", len(points))
+		}
+		if points[0].Date != today.AddDate(0, 0, -2).Format("2006-01-02") || points[2].Date != today.Format("2006-01-02") {
+			t.Errorf("unexpected dates %s..%s", points[0].Date, points[2].Date)
+		}
+		if len(points[0].Holdings) != 1 || points[0].Holdings[0].BalanceFormatted != "1" {
+			t.Errorf("unexpected first point holdings: %+v", points[0].Holdings)
+		}
+		if len(points[1].Holdings) != 2 {
+			t.Errorf("expected 2 holdings on second day, got %d", len(points[1].Holdings))
+		}
+		if len(points[2].Holdings) != 1 || points[2].Holdings[0].TokenAddress != usdc {
+			t.Errorf("expected only USDC after USDT was sent, got %+v", points[2].Holdings)
+		}
+		if points[2].ValueUSD != "" {
+			t.Errorf("expected no value without price provider, got %q", points[2].ValueUSD)
+		}
+	})
+
+	t.Run("values past days at historical prices", func(t *testing.T) {
+		prices := testutil.NewMockPriceProvider(map[string]float64{usdc: 1})
+		prices.HistoricalPriceFunc = func(ctx context.Context, tokenAddress string, at time.Time) (float64, error) {
+			if tokenAddress == usdt {
+				return 2, nil
+			}
+			return 0.5, nil
+		}
+		service := NewPortfolioService(newRepo(), nil, logger).WithPriceProvider(prices)
+
+		result, err := service.GetPortfolioHistory(ctx, "0x1234567890123456789012345678901234567890", 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		points := result.Data.Points
+		if points[0].ValueUSD != "2.00" {
+			t.Errorf("expected 2.00, got %q", points[0].ValueUSD)
+		}
+		if points[1].ValueUSD != "3.25" {
+			t.Errorf("expected 3.25, got %q", points[1].ValueUSD)
+		}
+		if points[2].ValueUSD != "2.50" {
+			t.Errorf("expected today valued at current price 2.50, got %q", points[2].ValueUSD)
+		}
+	})
+
+	t.Run("returns error on repository failure", func(t *testing.T) {
+		mockRepo := testutil.NewMockPortfolioRepository()
+		mockRepo.GetWalletDailyBalanceChangesFunc = func(ctx context.Context, walletAddress string, from time.Time) ([]repositories.DailyBalanceChange, error) {
+			return nil, errors.New("database error")
+		}
+		service := NewPortfolioService(mockRepo, nil, logger)
+
+		if _, err := service.GetPortfolioHistory(ctx, "0x1234567890123456789012345678901234567890", 3); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestPortfolioService_GetWalletTokens(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("includes tokens with zero balance", func(t *testing.T) {
+		mockRepo := testutil.NewMockPortfolioRepository()
+		first := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+		last := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+		mockRepo.GetWalletTokenActivityFunc = func(ctx context.Context, walletAddress string) ([]repositories.WalletTokenActivity, error) {
+			if walletAddress != "0xabcdef1234567890abcdef1234567890abcdef12" {
+				t.Errorf("expected lowercased address, got %s", walletAddress)
+			}
+			return []repositories.WalletTokenActivity{
+				{TokenAddress: testutil.USDTAddress, TokenSymbol: "USDT", Decimals: 6, Balance: "1500000",
+					TransfersIn: 3, TransfersOut: 1, FirstActivityAt: first, LastActivityAt: last},
+				{TokenAddress: testutil.USDCAddress, TokenSymbol: "USDC", Decimals: 6, Balance: "0",
+					TransfersIn: 1, TransfersOut: 1, FirstActivityAt: first, LastActivityAt: first},
+			}, nil
+		}
+
+		service := NewPortfolioService(mockRepo, nil, logger)
+
+		result, err := service.GetWalletTokens(ctx, "0xABCDEF1234567890ABCDEF1234567890ABCDEF12")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		tokens := result.Data.Tokens
+		if len(tokens) != 2 {
+			t.Fatalf("expected 2 tokens, got %d", len(tokens))
+		}
+		if tokens[0].BalanceFormatted != "1.5" || tokens[0].LastActivityAt != "2024-03-01T08:00:00Z" || tokens[0].TransfersIn != 3 {
+			t.Errorf("unexpected first token: %+v", tokens[0])
+		}
+		if tokens[1].Balance != "0" || tokens[1].FirstActivityAt != "2024-01-15T10:30:00Z" {
+			t.Errorf("expected zero-balance token to be listed, got %+v", tokens[1])
+		}
+	})
+
+	t.Run("returns error on repository failure", func(t *testing.T) {
+		mockRepo := testutil.NewMockPortfolioRepository()
+		mockRepo.GetWalletTokenActivityFunc = func(ctx context.Context, walletAddress string) ([]repositories.WalletTokenActivity, error) {
+			return nil, errors.New("database error")
+		}
+
+		service := NewPortfolioService(mockRepo, nil, logger)
+
+		if _, err := service.GetWalletTokens(ctx, "0x1234567890123456789012345678901234567890"); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestPortfolioService_GetWalletGraph(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+	dave := "0x4444444444444444444444444444444444444444"
+	at := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	flow := func(from, to string, transfers int64) repositories.CounterpartyFlow {
+		return repositories.CounterpartyFlow{FromAddress: from, ToAddress: to, TokenAddress: testutil.USDTAddress,
+			TokenSymbol: "USDT", Decimals: 6, Transfers: transfers, Volume: "1500000", LastTransferAt: at}
+	}
+
+	t.Run("expands counterparties hop by hop", func(t *testing.T) {
+		mockRepo := testutil.NewMockPortfolioRepository()
+		var frontiers [][]string
+		mockRepo.GetCounterpartyFlowsFunc = func(ctx context.Context, addresses []string, scanLimit, limit int) ([]repositories.CounterpartyFlow, error) {
+			frontiers = append(frontiers, addresses)
+			if len(frontiers) == 1 {
+				return []repositories.CounterpartyFlow{
+					flow(testutil.AliceAddress, testutil.BobAddress, 3),
+					flow(testutil.CharlieAddr, testutil.AliceAddress, 2),
+					flow(entities.ZeroAddress, testutil.AliceAddress, 1),
+				}, nil
+			}
+			return []repositories.CounterpartyFlow{
+				flow(testutil.AliceAddress, testutil.BobAddress, 3),
+				flow(testutil.BobAddress, dave, 1),
+			}, nil
+		}
+
+		service := NewPortfolioService(mockRepo, nil, logger)
+		result, err := service.GetWalletGraph(ctx, testutil.AliceAddress, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// The zero address is a node but not expanded
+		if len(frontiers) != 2 || len(frontiers[1]) != 2 || frontiers[1][0] != testutil.BobAddress || frontiers[1][1] != testutil.CharlieAddr {
+			t.Errorf("expected Bob and Charlie expanded in the second hop, got %v", frontiers)
+		}
+		graph := result.Data
+		if len(graph.Nodes) != 5 || graph.Nodes[0].Address != testutil.AliceAddress || graph.Nodes[4].Address != dave || graph.Nodes[4].Depth != 2 {
+			t.Errorf("unexpected nodes: %+v", graph.Nodes)
+		}
+		// Alice to Bob is read again from Bob's side and kept once
+		if len(graph.Edges) != 4 || graph.Edges[0].VolumeFormatted != "1.5" || graph.Truncated {
+			t.Errorf("unexpected edges: %+v", graph.Edges)
+		}
+	})
+
+	t.Run("caps nodes", func(t *testing.T) {
+		mockRepo := testutil.NewMockPortfolioRepository()
+		mockRepo.GetCounterpartyFlowsFunc = func(ctx context.Context, addresses []string, scanLimit, limit int) ([]repositories.CounterpartyFlow, error) {
+			flows := make([]repositories.CounterpartyFlow, 0, limit)
+			for i := 0; i < limit; i++ {
+				flows = append(flows, flow(testutil.AliceAddress, fmt.Sprintf("0x%040x", i+1), 1))
+			}
+			return flows, nil
+		}
+
+		service := NewPortfolioService(mockRepo, nil, logger)
+		result, err := service.GetWalletGraph(ctx, testutil.AliceAddress, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Data.Nodes) != maxGraphNodes || len(result.Data.Edges) != maxGraphNodes-1 || !result.Data.Truncated {
+			t.Errorf("expected a truncated graph of %d nodes, got %d nodes and %d edges", maxGraphNodes, len(result.Data.Nodes), len(result.Data.Edges))
+		}
+	})
+}
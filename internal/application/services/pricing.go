@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/pricing"
+)
+
+// priceLookup memoizes price lookups while enriching a single response.
+// Failures are logged and reported as missing prices, so enrichment is best-effort.
+type priceLookup struct {
+	provider pricing.Provider
+	logger   *zap.Logger
+	prices   map[string]*float64
+}
+
+func newPriceLookup(provider pricing.Provider, logger *zap.Logger) *priceLookup {
+	return &priceLookup{
+		provider: provider,
+		logger:   logger,
+		prices:   make(map[string]*float64),
+	}
+}
+
+// current returns the current USD price of a token
+func (l *priceLookup) current(ctx context.Context, tokenAddress string) (float64, bool) {
+	return l.lookup(tokenAddress, func() (float64, error) {
+		return l.provider.CurrentPrice(ctx, tokenAddress)
+	})
+}
+
+// historical returns the USD price of a token at the hour containing at
+func (l *priceLookup) historical(ctx context.Context, tokenAddress string, at time.Time) (float64, bool) {
+	key := fmt.Sprintf("%s:%d", tokenAddress, at.UTC().Truncate(time.Hour).Unix())
+	return l.lookup(key, func() (float64, error) {
+		return l.provider.HistoricalPrice(ctx, tokenAddress, at)
+	})
+}
+
+func (l *priceLookup) lookup(key string, fetch func() (float64, error)) (float64, bool) {
+	if p, ok := l.prices[key]; ok {
+		if p == nil {
+			return 0, false
+		}
+		return *p, true
+	}
+
+	price, err := fetch()
+	if err != nil {
+		if !errors.Is(err, pricing.ErrPriceUnavailable) {
+			l.logger.Warn("Failed to get token price", zap.String("key", key), zap.Error(err))
+		}
+		l.prices[key] = nil
+		return 0, false
+	}
+
+	l.prices[key] = &price
+	return price, true
+}
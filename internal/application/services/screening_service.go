@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/denylist"
+)
+
+// ScreeningService checks addresses and transfers against imported deny lists
+type ScreeningService struct {
+	denyListRepo repositories.DenyListRepository
+	cache        *cache.RedisCache
+	logger       *zap.Logger
+
+	// load fetches a list from its source; replaced in tests
+	load func(ctx context.Context, listName, source string) ([]entities.DenyListEntry, error)
+}
+
+// NewScreeningService creates a new screening service
+func NewScreeningService(
+	denyListRepo repositories.DenyListRepository,
+	cache *cache.RedisCache,
+	logger *zap.Logger,
+) *ScreeningService {
+	return &ScreeningService{
+		denyListRepo: denyListRepo,
+		cache:        cache,
+		logger:       logger,
+		load:         denylist.Load,
+	}
+}
+
+// DenyListMatchDTO is one deny list an address appears on
+type DenyListMatchDTO struct {
+	List    string `json:"list"`
+	Label   string `json:"label,omitempty"`
+	AddedAt string `json:"added_at"`
+}
+
+// ScreeningResultDTO is the API representation of an address screening
+type ScreeningResultDTO struct {
+	Address string             `json:"address"`
+	Listed  bool               `json:"listed"`
+	Matches []DenyListMatchDTO `json:"matches"`
+}
+
+// ScreeningResponse is the API response for address screening
+type ScreeningResponse struct {
+	Data ScreeningResultDTO `json:"data"`
+}
+
+// ScreenAddress reports which deny lists an address appears on
+func (s *ScreeningService) ScreenAddress(ctx context.Context, address string) (*ScreeningResponse, error) {
+	address = strings.ToLower(address)
+
+	// Generate cache key
+	cacheKey := fmt.Sprintf("screening:%s", address)
+
+	// Try cache first
+	var cached ScreeningResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	entries, err := s.denyListRepo.FindByAddresses(ctx, []string{address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen address: %w", err)
+	}
+
+	matches := make([]DenyListMatchDTO, len(entries))
+	for i, e := range entries {
+		matches[i] = DenyListMatchDTO{
+			List:    e.ListName,
+			Label:   e.Label,
+			AddedAt: e.AddedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	response := &ScreeningResponse{
+		Data: ScreeningResultDTO{
+			Address: address,
+			Listed:  len(matches) > 0,
+			Matches: matches,
+		},
+	}
+
+	// Cache the response with shorter TTL (60 seconds), so list refreshes show up quickly
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, 60*time.Second); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// FlagTransfers sets screened on each transfer: true when its sender or receiver is on any deny list
+func (s *ScreeningService) FlagTransfers(ctx context.Context, transfers []TransferDTO) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	var addresses []string
+	seen := make(map[string]struct{})
+	for _, t := range transfers {
+		for _, addr := range []string{t.FromAddress, t.ToAddress} {
+			if _, ok := seen[addr]; !ok {
+				seen[addr] = struct{}{}
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+
+	entries, err := s.denyListRepo.FindByAddresses(ctx, addresses)
+	if err != nil {
+		return fmt.Errorf("failed to screen transfers: %w", err)
+	}
+
+	listed := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		listed[e.Address] = struct{}{}
+	}
+
+	for i := range transfers {
+		_, fromListed := listed[transfers[i].FromAddress]
+		_, toListed := listed[transfers[i].ToAddress]
+		screened := fromListed || toListed
+		transfers[i].Screened = &screened
+	}
+
+	return nil
+}
+
+// RefreshList downloads a deny list from source and replaces the stored list with it,
+// returning the number of entries. An empty download is rejected rather than clearing the list.
+func (s *ScreeningService) RefreshList(ctx context.Context, listName, source string) (int, error) {
+	entries, err := s.load(ctx, listName, source)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load deny list %s: %w", listName, err)
+	}
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("deny list %s from %s is empty", listName, source)
+	}
+
+	if err := s.denyListRepo.ReplaceList(ctx, listName, entries); err != nil {
+		return 0, fmt.Errorf("failed to store deny list %s: %w", listName, err)
+	}
+
+	return len(entries), nil
+}
+
+// RefreshAll refreshes every list in sources (list name -> URL or file path), in name order.
+// A failing list is logged and skipped, keeping its previous entries.
+func (s *ScreeningService) RefreshAll(ctx context.Context, sources map[string]string) error {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failed []string
+	for _, name := range names {
+		n, err := s.RefreshList(ctx, name, sources[name])
+		if err != nil {
+			s.logger.Warn("Failed to refresh deny list", zap.String("list", name), zap.Error(err))
+			failed = append(failed, name)
+			continue
+		}
+		s.logger.Info("Deny list refreshed", zap.String("list", name), zap.Int("entries", n))
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to refresh deny lists: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// RunRefreshLoop refreshes all lists immediately and then every interval until ctx is cancelled
+func (s *ScreeningService) RunRefreshLoop(ctx context.Context, sources map[string]string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = s.RefreshAll(ctx, sources)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
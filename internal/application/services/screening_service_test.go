@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestScreeningService_ScreenAddress(t *testing.T) {
+	ctx := context.Background()
+	repo := testutil.NewMockDenyListRepository()
+	repo.AddDenyListEntries("ofac", testutil.CharlieAddr)
+	service := NewScreeningService(repo, nil, zap.NewNop())
+
+	result, err := service.ScreenAddress(ctx, testutil.CharlieAddr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Data.Listed || len(result.Data.Matches) != 1 || result.Data.Matches[0].List != "ofac" {
+		t.Errorf("expected a single ofac match, got %+v", result.Data)
+	}
+
+	result, err = service.ScreenAddress(ctx, testutil.AliceAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Data.Listed || len(result.Data.Matches) != 0 {
+		t.Errorf("expected alice not to be listed, got %+v", result.Data)
+	}
+}
+
+func TestTransferService_GetTransfers_Screening(t *testing.T) {
+	ctx := context.Background()
+
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.CharlieAddr)),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.BobAddress)),
+	)
+
+	denyListRepo := testutil.NewMockDenyListRepository()
+	denyListRepo.AddDenyListEntries("ofac", testutil.CharlieAddr)
+	screening := NewScreeningService(denyListRepo, nil, zap.NewNop())
+	service := NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, zap.NewNop()).WithScreening(screening)
+
+	result, err := service.GetTransfers(ctx, entities.DefaultTransferFilter())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, dto := range result.Transfers {
+		if dto.Screened == nil {
+			t.Fatalf("expected screened to be set on %s", dto.TxHash)
+		}
+		want := dto.ToAddress == testutil.CharlieAddr
+		if *dto.Screened != want {
+			t.Errorf("%s: expected screened=%v, got %v", dto.TxHash, want, *dto.Screened)
+		}
+	}
+
+	t.Run("lookup failure leaves screened unset", func(t *testing.T) {
+		denyListRepo.FindByAddressesFunc = func(ctx context.Context, addresses []string) ([]entities.DenyListEntry, error) {
+			return nil, errors.New("database error")
+		}
+
+		result, err := service.GetTransfers(ctx, entities.DefaultTransferFilter())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, dto := range result.Transfers {
+			if dto.Screened != nil {
+				t.Errorf("expected screened to be unset, got %v", *dto.Screened)
+			}
+		}
+	})
+}
+
+func TestScreeningService_RefreshList(t *testing.T) {
+	ctx := context.Background()
+	repo := testutil.NewMockDenyListRepository()
+	service := NewScreeningService(repo, nil, zap.NewNop())
+
+	var loaded []entities.DenyListEntry
+	service.load = func(ctx context.Context, listName, source string) ([]entities.DenyListEntry, error) {
+		return loaded, nil
+	}
+
+	loaded = []entities.DenyListEntry{{Address: testutil.AliceAddress}, {Address: testutil.BobAddress}}
+	n, err := service.RefreshList(ctx, "ofac", "ofac.txt")
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 entries, got %d (%v)", n, err)
+	}
+
+	// Bob was delisted
+	loaded = []entities.DenyListEntry{{Address: testutil.AliceAddress}}
+	if _, err := service.RefreshList(ctx, "ofac", "ofac.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, _ := service.ScreenAddress(ctx, testutil.BobAddress)
+	if result.Data.Listed {
+		t.Error("expected bob to be removed from the list")
+	}
+
+	t.Run("empty download keeps the current list", func(t *testing.T) {
+		loaded = nil
+		if _, err := service.RefreshList(ctx, "ofac", "ofac.txt"); err == nil {
+			t.Error("expected error")
+		}
+		result, _ := service.ScreenAddress(ctx, testutil.AliceAddress)
+		if !result.Data.Listed {
+			t.Error("expected alice to remain listed")
+		}
+	})
+
+	t.Run("RefreshAll reports failed lists", func(t *testing.T) {
+		service.load = func(ctx context.Context, listName, source string) ([]entities.DenyListEntry, error) {
+			if listName == "broken" {
+				return nil, errors.New("download failed")
+			}
+			return []entities.DenyListEntry{{Address: testutil.CharlieAddr}}, nil
+		}
+
+		err := service.RefreshAll(ctx, map[string]string{"broken": "x", "internal": "y"})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		result, _ := service.ScreenAddress(ctx, testutil.CharlieAddr)
+		if !result.Data.Listed {
+			t.Error("expected the working list to be imported")
+		}
+	})
+}
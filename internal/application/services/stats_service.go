@@ -3,21 +3,55 @@ package services
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
 	"github.com/bimakw/chain-indexer/internal/domain/repositories"
 	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/pricing"
+	"github.com/bimakw/chain-indexer/internal/pkg/units"
 )
 
+// rawWindowLimit is the longest window read from raw transfers when the daily rollup is available
+const rawWindowLimit = 48 * time.Hour
+
+// MaxStatsRange is the longest custom time range token stats can be aggregated over
+const MaxStatsRange = 366 * 24 * time.Hour
+
+// heatmapCacheTTL is how long activity heatmaps are cached; they span days, so minutes of
+// staleness barely change them
+const heatmapCacheTTL = 5 * time.Minute
+
+// DefaultStatsCacheTTL is how long token, range and active address stats are cached unless
+// configured otherwise
+const DefaultStatsCacheTTL = 60 * time.Second
+
+// statsRangeRounding is the granularity custom stats ranges are rounded down to, so that
+// requests made within the same minute share a cache entry
+const statsRangeRounding = time.Minute
+
 // StatsService provides business logic for transfer statistics
 type StatsService struct {
-	transferRepo repositories.TransferRepository
-	tokenRepo    repositories.TokenRepository
-	cache        *cache.RedisCache
-	logger       *zap.Logger
+	transferRepo     repositories.TransferRepository
+	tokenRepo        repositories.TokenRepository
+	dailyStatsRepo   repositories.DailyStatsRepository
+	activeAddrs      repositories.ActiveAddressRepository
+	analytics        repositories.AnalyticsRepository
+	cache            *cache.RedisCache
+	holderExclusions HolderExclusions
+	holderCounts     repositories.HolderCountRepository
+	approxHolderMin  int64
+	advancedStats    repositories.AdvancedStatsRepository
+	transferFlags    repositories.TransferFlagRepository
+	entities         repositories.EntityRepository
+	breaker          *Breaker
+	prices           pricing.Provider
+	cacheTTL         time.Duration
+	logger           *zap.Logger
 }
 
 // NewStatsService creates a new stats service
@@ -31,10 +65,84 @@ func NewStatsService(
 		transferRepo: transferRepo,
 		tokenRepo:    tokenRepo,
 		cache:        cache,
+		cacheTTL:     DefaultStatsCacheTTL,
 		logger:       logger,
 	}
 }
 
+// WithCacheTTL sets how long token, range and active address stats are cached
+func (s *StatsService) WithCacheTTL(ttl time.Duration) *StatsService {
+	s.cacheTTL = ttl
+	return s
+}
+
+// CacheTTL returns how long token, range and active address stats are cached
+func (s *StatsService) CacheTTL() time.Duration {
+	return s.cacheTTL
+}
+
+// WithPriceProvider enables USD enrichment of stats responses
+func (s *StatsService) WithPriceProvider(provider pricing.Provider) *StatsService {
+	s.prices = provider
+	return s
+}
+
+// WithDailyStats makes token stats read windows longer than 48 hours from the token_daily_stats rollup
+func (s *StatsService) WithDailyStats(repo repositories.DailyStatsRepository) *StatsService {
+	s.dailyStatsRepo = repo
+	return s
+}
+
+// WithActiveAddresses enables approximate unique active address counts
+func (s *StatsService) WithActiveAddresses(repo repositories.ActiveAddressRepository) *StatsService {
+	s.activeAddrs = repo
+	return s
+}
+
+// WithAnalytics reads token stats and holder counts from the analytics store,
+// falling back to PostgreSQL when it fails
+func (s *StatsService) WithAnalytics(repo repositories.AnalyticsRepository) *StatsService {
+	s.analytics = repo
+	return s
+}
+
+// WithBreaker retries token stats reads through b and serves stale cached responses while the database is unavailable
+func (s *StatsService) WithBreaker(b *Breaker) *StatsService {
+	s.breaker = b
+	return s
+}
+
+// WithHolderExclusions leaves the given addresses out of holder counts unless a request asks to include them
+func (s *StatsService) WithHolderExclusions(exclusions HolderExclusions) *StatsService {
+	s.holderExclusions = exclusions
+	return s
+}
+
+// WithHolderCounts serves the holder count recorded with the last holder snapshot, instead of counting
+// on every request, for tokens whose recorded count is at least min. Requests can still ask for an exact count.
+func (s *StatsService) WithHolderCounts(repo repositories.HolderCountRepository, min int64) *StatsService {
+	s.holderCounts = repo
+	s.approxHolderMin = min
+	return s
+}
+
+// recordedHolderCount returns the token's recorded holder count when it is large enough to be served
+// approximately, nil when the token is counted exactly
+func (s *StatsService) recordedHolderCount(ctx context.Context, tokenAddress string) *repositories.HolderCount {
+	if s.holderCounts == nil {
+		return nil
+	}
+	recorded, err := s.holderCounts.Get(ctx, tokenAddress)
+	if err != nil {
+		s.logger.Warn("Failed to get recorded holder count, counting exactly", zap.String("token", tokenAddress), zap.Error(err))
+		return nil
+	}
+	if recorded == nil || recorded.Count < s.approxHolderMin {
+		return nil
+	}
+	return recorded
+}
+
 // TokenStats is the API representation of token transfer statistics
 type TokenStats struct {
 	TokenAddress        string `json:"token_address"`
@@ -48,6 +156,23 @@ type TokenStats struct {
 	Volume7d            string `json:"volume_7d"`
 	FirstTransferAt     string `json:"first_transfer_at"`
 	LastTransferAt      string `json:"last_transfer_at"`
+	TotalVolumeUSD      string `json:"total_volume_usd,omitempty"`
+	Volume24hUSD        string `json:"volume_24h_usd,omitempty"`
+	Volume7dUSD         string `json:"volume_7d_usd,omitempty"`
+	// Range holds the stats of a custom time range when one was requested
+	Range *RangeStats `json:"range,omitempty"`
+	// ExcludesFlagged is set when transfers flagged by the analysis pass were left out of the
+	// transfer counts and volumes
+	ExcludesFlagged bool `json:"excludes_flagged,omitempty"`
+}
+
+// RangeStats is the transfer count and volume of a token in [From, To)
+type RangeStats struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Transfers int64  `json:"transfers"`
+	Volume    string `json:"volume"`
+	VolumeUSD string `json:"volume_usd,omitempty"`
 }
 
 // HolderCountResponse is the API response for holder count queries
@@ -57,8 +182,70 @@ type HolderCountResponse struct {
 
 // HolderCountDTO represents the holder count data
 type HolderCountDTO struct {
-	TokenAddress string `json:"token_address"`
-	HolderCount  int64  `json:"holder_count"`
+	TokenAddress string     `json:"token_address"`
+	HolderCount  int64      `json:"holder_count"`
+	Approximate  bool       `json:"approximate"`
+	CountedAt    *time.Time `json:"counted_at,omitempty"` // When an approximate count was taken
+}
+
+// ActiveAddressesDTO represents the unique active address count of a token over a day range
+type ActiveAddressesDTO struct {
+	TokenAddress    string `json:"token_address"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	ActiveAddresses int64  `json:"active_addresses"`
+	Approximate     bool   `json:"approximate"` // HyperLogLog estimate, ~0.81% standard error
+}
+
+// ActiveAddressesResponse is the API response for active address queries
+type ActiveAddressesResponse struct {
+	Data ActiveAddressesDTO `json:"data"`
+}
+
+// NewHoldersDayDTO counts the addresses receiving a token on one UTC day: for the first time, and
+// again after an earlier receipt
+type NewHoldersDayDTO struct {
+	Day              string `json:"day"`
+	NewHolders       int64  `json:"new_holders"`
+	ReturningHolders int64  `json:"returning_holders"`
+}
+
+// NewHolderDTO is an address and its first receipt of a token
+type NewHolderDTO struct {
+	Address     string    `json:"address"`
+	FirstBlock  int64     `json:"first_block"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+}
+
+// NewHoldersDTO holds a token's first-time and returning receivers per day over a window of UTC
+// days, and the addresses that received it for the first time most recently
+type NewHoldersDTO struct {
+	TokenAddress string             `json:"token_address"`
+	From         string             `json:"from"`
+	To           string             `json:"to"`
+	NewHolders   int64              `json:"new_holders"`
+	Days         []NewHoldersDayDTO `json:"days"`
+	Newest       []NewHolderDTO     `json:"newest"`
+}
+
+// NewHoldersResponse is the API response for new holder queries
+type NewHoldersResponse struct {
+	Data NewHoldersDTO `json:"data"`
+}
+
+// ActivityHeatmapDTO holds a token's transfer counts by UTC weekday and hour over a window.
+// Counts[d][h] is the number of transfers on weekday d (0 = Sunday) during hour h.
+type ActivityHeatmapDTO struct {
+	TokenAddress   string       `json:"token_address"`
+	From           string       `json:"from"`
+	To             string       `json:"to"`
+	TotalTransfers int64        `json:"total_transfers"`
+	Counts         [7][24]int64 `json:"counts"`
+}
+
+// ActivityHeatmapResponse is the API response for activity heatmap queries
+type ActivityHeatmapResponse struct {
+	Data ActivityHeatmapDTO `json:"data"`
 }
 
 // TokenStatsResponse is the API response for token stats queries
@@ -82,6 +269,22 @@ func (s *StatsService) GetTokenStats(ctx context.Context, tokenAddress string) (
 		}
 	}
 
+	return guardedLoad(ctx, s.breaker, s.cache, s.logger, "stats.GetTokenStats", cacheKey, func(ctx context.Context) (*TokenStatsResponse, error) {
+		return s.loadTokenStats(ctx, tokenAddress, cacheKey)
+	})
+}
+
+// warmTokenStats reloads a token's stats into the cache, as GetTokenStats would on a miss
+func (s *StatsService) warmTokenStats(ctx context.Context, tokenAddress string) error {
+	cacheKey := fmt.Sprintf("stats:%s", tokenAddress)
+	_, err := guardedLoad(ctx, s.breaker, s.cache, s.logger, "stats.GetTokenStats", cacheKey, func(ctx context.Context) (*TokenStatsResponse, error) {
+		return s.loadTokenStats(ctx, tokenAddress, cacheKey)
+	})
+	return err
+}
+
+// loadTokenStats reads a token's stats from the analytics store or the database and caches them under cacheKey
+func (s *StatsService) loadTokenStats(ctx context.Context, tokenAddress, cacheKey string) (*TokenStatsResponse, error) {
 	// Check if token exists
 	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
 	if err != nil {
@@ -91,10 +294,24 @@ func (s *StatsService) GetTokenStats(ctx context.Context, tokenAddress string) (
 		return nil, nil // Token not found
 	}
 
-	// Get stats from database
-	stats, err := s.transferRepo.GetTokenStats(ctx, tokenAddress)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get token stats: %w", err)
+	// Get stats from the analytics store, or the database
+	var stats *repositories.TokenStatsResult
+	if s.analytics != nil {
+		stats, err = s.analytics.GetTokenStats(ctx, tokenAddress)
+		if err != nil {
+			s.logger.Warn("Analytics store query failed, falling back to database", zap.Error(err))
+			stats = nil
+		}
+	}
+	if stats == nil {
+		if s.dailyStatsRepo != nil {
+			stats, err = s.tokenStatsFromRollup(ctx, tokenAddress)
+		} else {
+			stats, err = s.transferRepo.GetTokenStats(ctx, tokenAddress)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token stats: %w", err)
+		}
 	}
 
 	// Build response
@@ -122,9 +339,9 @@ func (s *StatsService) GetTokenStats(ctx context.Context, tokenAddress string) (
 		response.Data.LastTransferAt = stats.LastTransferAt.Format("2006-01-02T15:04:05Z")
 	}
 
-	// Cache the response with shorter TTL (60 seconds for stats)
+	// Cache the response for the stats TTL
 	if s.cache != nil {
-		if err := s.cache.SetWithTTL(ctx, cacheKey, response, 60*time.Second); err != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, s.cacheTTL); err != nil {
 			s.logger.Warn("Failed to cache response", zap.Error(err))
 		}
 	}
@@ -132,12 +349,453 @@ func (s *StatsService) GetTokenStats(ctx context.Context, tokenAddress string) (
 	return response, nil
 }
 
-// GetHolderCount retrieves the total number of unique holders for a token
-func (s *StatsService) GetHolderCount(ctx context.Context, tokenAddress string) (*HolderCountResponse, error) {
+// tokenStatsFromRollup builds token stats from the daily rollup.
+// All-time totals sum every rollup row (new_senders/new_holders sum to the unique address counts),
+// the 24h window is read from raw transfers and the 7d window combines both.
+func (s *StatsService) tokenStatsFromRollup(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error) {
+	now := time.Now().UTC()
+
+	days, err := s.dailyStatsRepo.GetRange(ctx, tokenAddress, time.Time{}, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+
+	result := &repositories.TokenStatsResult{}
+	totalVolume := new(big.Int)
+	for _, d := range days {
+		result.TotalTransfers += d.Transfers
+		result.UniqueFromAddrs += d.NewSenders
+		result.UniqueToAddrs += d.NewHolders
+		addVolume(totalVolume, d.Volume)
+	}
+	result.TotalVolume = totalVolume.String()
+
+	if len(days) > 0 {
+		first := days[0].FirstTransferAt.UTC()
+		last := days[len(days)-1].LastTransferAt.UTC()
+		result.FirstTransferAt = &first
+		result.LastTransferAt = &last
+	}
+
+	w24h, err := s.windowStats(ctx, tokenAddress, days, now.Add(-24*time.Hour), now)
+	if err != nil {
+		return nil, err
+	}
+	result.Transfers24h, result.Volume24h = w24h.Transfers, w24h.Volume
+
+	w7d, err := s.windowStats(ctx, tokenAddress, days, now.Add(-7*24*time.Hour), now)
+	if err != nil {
+		return nil, err
+	}
+	result.Transfers7d, result.Volume7d = w7d.Transfers, w7d.Volume
+
+	return result, nil
+}
+
+// windowStats returns transfer count and volume in [from, to). Windows up to 48 hours are read
+// from raw transfers; longer ones sum rollup rows for whole days and read only the partial days
+// at either edge from raw transfers.
+func (s *StatsService) windowStats(ctx context.Context, tokenAddress string, days []entities.TokenDailyStats, from, to time.Time) (*repositories.WindowStats, error) {
+	if to.Sub(from) <= rawWindowLimit {
+		stats, err := s.transferRepo.GetWindowStats(ctx, tokenAddress, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get window stats: %w", err)
+		}
+		return stats, nil
+	}
+
+	firstFullDay := from.Truncate(24 * time.Hour)
+	if firstFullDay.Before(from) {
+		firstFullDay = firstFullDay.AddDate(0, 0, 1)
+	}
+	lastPartialDay := to.Truncate(24 * time.Hour)
+
+	var transfers int64
+	volume := new(big.Int)
+
+	for _, edge := range [][2]time.Time{{from, firstFullDay}, {lastPartialDay, to}} {
+		if !edge[0].Before(edge[1]) {
+			continue
+		}
+		stats, err := s.transferRepo.GetWindowStats(ctx, tokenAddress, edge[0], edge[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get window stats: %w", err)
+		}
+		transfers += stats.Transfers
+		addVolume(volume, stats.Volume)
+	}
+
+	for _, d := range days {
+		if d.Day.Before(firstFullDay) || !d.Day.Before(lastPartialDay) {
+			continue
+		}
+		transfers += d.Transfers
+		addVolume(volume, d.Volume)
+	}
+
+	return &repositories.WindowStats{Transfers: transfers, Volume: volume.String()}, nil
+}
+
+// GetRangeStats returns the transfer count and volume of a token in [from, to), both rounded down
+// to the minute. Ranges longer than 48 hours are summed from the daily rollup when it is
+// available. The range must be at most MaxStatsRange long.
+func (s *StatsService) GetRangeStats(ctx context.Context, tokenAddress string, from, to time.Time) (*RangeStats, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+	if to.Sub(from) > MaxStatsRange {
+		return nil, fmt.Errorf("stats range exceeds %s", MaxStatsRange)
+	}
+
+	from = from.UTC().Truncate(statsRangeRounding)
+	to = to.UTC().Truncate(statsRangeRounding)
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid stats range %s - %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	cacheKey := fmt.Sprintf("stats_range:%s:%d:%d", tokenAddress, from.Unix(), to.Unix())
+
+	var cached RangeStats
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	window, err := s.rangeWindowStats(ctx, tokenAddress, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &RangeStats{
+		From:      from.Format("2006-01-02T15:04:05Z"),
+		To:        to.Format("2006-01-02T15:04:05Z"),
+		Transfers: window.Transfers,
+		Volume:    window.Volume,
+	}
+
+	// Cache with the same short TTL as token stats
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, stats, s.cacheTTL); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return stats, nil
+}
+
+// rangeWindowStats returns transfer count and volume in [from, to), from the daily rollup when
+// it is available and from raw transfers otherwise
+func (s *StatsService) rangeWindowStats(ctx context.Context, tokenAddress string, from, to time.Time) (*repositories.WindowStats, error) {
+	if s.dailyStatsRepo == nil {
+		stats, err := s.transferRepo.GetWindowStats(ctx, tokenAddress, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get window stats: %w", err)
+		}
+		return stats, nil
+	}
+
+	days, err := s.dailyStatsRepo.GetRange(ctx, tokenAddress, from.Truncate(24*time.Hour), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+	return s.windowStats(ctx, tokenAddress, days, from, to)
+}
+
+// addVolume adds a raw decimal amount to total, ignoring malformed values
+func addVolume(total *big.Int, raw string) {
+	if v, ok := new(big.Int).SetString(raw, 10); ok {
+		total.Add(total, v)
+	}
+}
+
+// WithTransferFlags lets ExcludeFlagged leave flagged transfers out of token stats
+func (s *StatsService) WithTransferFlags(repo repositories.TransferFlagRepository) *StatsService {
+	s.transferFlags = repo
+	return s
+}
+
+// ExcludeFlagged subtracts the transfers flagged by the analysis pass, such as wash trades, from
+// the transfer counts and volumes of token stats, including a requested range. Unique address
+// counts are left as they are. Call it before AddUSDValues.
+func (s *StatsService) ExcludeFlagged(ctx context.Context, response *TokenStatsResponse) error {
+	if s.transferFlags == nil {
+		return fmt.Errorf("transfer flags not configured")
+	}
+	if response == nil {
+		return nil
+	}
+
+	data := &response.Data
+	now := time.Now().UTC()
+	exclude := func(from, to time.Time, transfers *int64, volume *string) error {
+		flagged, err := s.transferFlags.GetFlaggedStats(ctx, data.TokenAddress, from, to)
+		if err != nil {
+			return err
+		}
+		total, ok := new(big.Int).SetString(*volume, 10)
+		if !ok {
+			total = new(big.Int)
+		}
+		if v, ok := new(big.Int).SetString(flagged.Volume, 10); ok {
+			total.Sub(total, v)
+		}
+		*transfers = max(*transfers-flagged.Transfers, 0)
+		*volume = total.String()
+		return nil
+	}
+
+	if err := exclude(time.Time{}, now.Add(time.Second), &data.TotalTransfers, &data.TotalVolume); err != nil {
+		return err
+	}
+	if err := exclude(now.Add(-24*time.Hour), now, &data.Transfers24h, &data.Volume24h); err != nil {
+		return err
+	}
+	if err := exclude(now.Add(-7*24*time.Hour), now, &data.Transfers7d, &data.Volume7d); err != nil {
+		return err
+	}
+	if r := data.Range; r != nil {
+		from, errFrom := time.Parse(time.RFC3339, r.From)
+		to, errTo := time.Parse(time.RFC3339, r.To)
+		if errFrom != nil || errTo != nil {
+			return fmt.Errorf("invalid stats range %s - %s", r.From, r.To)
+		}
+		if err := exclude(from, to, &r.Transfers, &r.Volume); err != nil {
+			return err
+		}
+	}
+
+	data.ExcludesFlagged = true
+	return nil
+}
+
+// AddUSDValues fills the USD volume fields at the token's current price.
+// It is a no-op when no price provider is configured.
+func (s *StatsService) AddUSDValues(ctx context.Context, response *TokenStatsResponse) {
+	if s.prices == nil || response == nil {
+		return
+	}
+
+	token, err := s.tokenRepo.GetByAddress(ctx, response.Data.TokenAddress)
+	if err != nil || token == nil {
+		return
+	}
+
+	price, ok := newPriceLookup(s.prices, s.logger).current(ctx, token.Address)
+	if !ok {
+		return
+	}
+
+	usd := func(raw string) string {
+		v, _ := pricing.ValueUSD(units.Format(raw, token.Decimals), price)
+		return v
+	}
+	response.Data.TotalVolumeUSD = usd(response.Data.TotalVolume)
+	response.Data.Volume24hUSD = usd(response.Data.Volume24h)
+	response.Data.Volume7dUSD = usd(response.Data.Volume7d)
+	if response.Data.Range != nil {
+		response.Data.Range.VolumeUSD = usd(response.Data.Range.Volume)
+	}
+}
+
+// GetActiveAddresses returns the approximate number of distinct senders and receivers of a token
+// over the UTC days in [from, to]
+func (s *StatsService) GetActiveAddresses(ctx context.Context, tokenAddress string, from, to time.Time) (*ActiveAddressesResponse, error) {
+	if s.activeAddrs == nil {
+		return nil, fmt.Errorf("active address tracking not configured")
+	}
+
+	tokenAddress = strings.ToLower(tokenAddress)
+	fromDay := from.UTC().Format("2006-01-02")
+	toDay := to.UTC().Format("2006-01-02")
+
+	// Generate cache key
+	cacheKey := fmt.Sprintf("active_addresses:%s:%s:%s", tokenAddress, fromDay, toDay)
+
+	// Try cache first
+	var cached ActiveAddressesResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	// Check if token exists
+	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token: %w", err)
+	}
+	if token == nil {
+		return nil, nil // Token not found
+	}
+
+	count, err := s.activeAddrs.CountActiveAddresses(ctx, tokenAddress, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active addresses: %w", err)
+	}
+
+	response := &ActiveAddressesResponse{
+		Data: ActiveAddressesDTO{
+			TokenAddress:    tokenAddress,
+			From:            fromDay,
+			To:              toDay,
+			ActiveAddresses: count,
+			Approximate:     true,
+		},
+	}
+
+	// Cache the response for the stats TTL
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, s.cacheTTL); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// GetNewHolders returns, for each of the last days UTC days up to today, how many addresses
+// received a token for the first time and how many received it again, along with the limit
+// addresses whose first receipt is the most recent within the window. Counts come from the
+// daily rollup; days without transfers count zero. Returns nil if the token does not exist.
+func (s *StatsService) GetNewHolders(ctx context.Context, tokenAddress string, days, limit int) (*NewHoldersResponse, error) {
+	if s.dailyStatsRepo == nil {
+		return nil, fmt.Errorf("daily stats rollup not configured")
+	}
+
+	tokenAddress = strings.ToLower(tokenAddress)
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -(days - 1))
+
+	cacheKey := fmt.Sprintf("new_holders:%s:%s:%d:%d", tokenAddress, to.Format("2006-01-02"), days, limit)
+	var cached NewHoldersResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token: %w", err)
+	}
+	if token == nil {
+		return nil, nil // Token not found
+	}
+
+	rows, err := s.dailyStatsRepo.GetRange(ctx, tokenAddress, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+	newest, err := s.dailyStatsRepo.GetNewHolders(ctx, tokenAddress, from, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	byDay := make(map[string]entities.TokenDailyStats, len(rows))
+	for _, row := range rows {
+		byDay[row.Day.UTC().Format("2006-01-02")] = row
+	}
+
+	data := NewHoldersDTO{
+		TokenAddress: tokenAddress,
+		From:         from.Format("2006-01-02"),
+		To:           to.Format("2006-01-02"),
+		Days:         make([]NewHoldersDayDTO, 0, days),
+		Newest:       make([]NewHolderDTO, len(newest)),
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		row := byDay[day.Format("2006-01-02")]
+		data.NewHolders += row.NewHolders
+		data.Days = append(data.Days, NewHoldersDayDTO{
+			Day:              day.Format("2006-01-02"),
+			NewHolders:       row.NewHolders,
+			ReturningHolders: row.UniqueReceivers - row.NewHolders,
+		})
+	}
+	for i, h := range newest {
+		data.Newest[i] = NewHolderDTO{Address: h.Address, FirstBlock: h.FirstBlock, FirstSeenAt: h.FirstSeenAt.UTC()}
+	}
+
+	response := &NewHoldersResponse{Data: data}
+
+	// Cache the response for the stats TTL
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, s.cacheTTL); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// GetActivityHeatmap returns a token's transfer counts bucketed by UTC weekday and hour over the
+// last days days
+func (s *StatsService) GetActivityHeatmap(ctx context.Context, tokenAddress string, days int) (*ActivityHeatmapResponse, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	cacheKey := fmt.Sprintf("heatmap:%s:%d", tokenAddress, days)
+
+	var cached ActivityHeatmapResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token: %w", err)
+	}
+	if token == nil {
+		return nil, nil // Token not found
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -days)
+
+	buckets, err := s.transferRepo.GetActivityHeatmap(ctx, tokenAddress, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity heatmap: %w", err)
+	}
+
+	response := &ActivityHeatmapResponse{
+		Data: ActivityHeatmapDTO{
+			TokenAddress: tokenAddress,
+			From:         from.Format("2006-01-02T15:04:05Z"),
+			To:           to.Format("2006-01-02T15:04:05Z"),
+		},
+	}
+	for _, b := range buckets {
+		if b.DayOfWeek < 0 || b.DayOfWeek > 6 || b.Hour < 0 || b.Hour > 23 {
+			continue
+		}
+		response.Data.Counts[b.DayOfWeek][b.Hour] = b.Transfers
+		response.Data.TotalTransfers += b.Transfers
+	}
+
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, heatmapCacheTTL); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// GetHolderCount retrieves the total number of unique holders for a token.
+// includeExcluded also counts the addresses in the holder exclusions. Large tokens get the count
+// recorded with the last holder snapshot unless exact is set; see WithHolderCounts.
+func (s *StatsService) GetHolderCount(ctx context.Context, tokenAddress string, includeExcluded, exact bool) (*HolderCountResponse, error) {
 	tokenAddress = strings.ToLower(tokenAddress)
 
 	// Generate cache key
-	cacheKey := fmt.Sprintf("holder_count:%s", tokenAddress)
+	cacheKey := fmt.Sprintf("holder_count:%s%s", tokenAddress, exclusionCacheSuffix(includeExcluded))
+	if exact {
+		cacheKey += ":exact"
+	}
 
 	// Try cache first
 	var cached HolderCountResponse
@@ -157,10 +815,27 @@ func (s *StatsService) GetHolderCount(ctx context.Context, tokenAddress string)
 		return nil, nil // Token not found
 	}
 
-	// Get holder count from database
-	count, err := s.transferRepo.GetHolderCount(ctx, tokenAddress)
+	if !exact {
+		if recorded := s.recordedHolderCount(ctx, tokenAddress); recorded != nil {
+			return &HolderCountResponse{
+				Data: HolderCountDTO{
+					TokenAddress: tokenAddress,
+					HolderCount:  recorded.Count,
+					Approximate:  true,
+					CountedAt:    &recorded.CountedAt,
+				},
+			}, nil
+		}
+	}
+
+	// Get holder count from the analytics store, or the database
+	var exclude []string
+	if !includeExcluded {
+		exclude = s.holderExclusions.forToken(tokenAddress)
+	}
+	count, err := holderCount(ctx, s.analytics, s.transferRepo, tokenAddress, exclude, s.logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get holder count: %w", err)
+		return nil, err
 	}
 
 	// Build response
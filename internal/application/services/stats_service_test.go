@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"math/big"
 	"testing"
 	"time"
 
@@ -245,11 +246,11 @@ func TestStatsService_GetHolderCount_Success(t *testing.T) {
 	))
 
 	// Setup mock holder count response
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 4523891, nil
 	}
 
-	response, err := service.GetHolderCount(ctx, testutil.USDTAddress)
+	response, err := service.GetHolderCount(ctx, testutil.USDTAddress, false, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -267,11 +268,56 @@ func TestStatsService_GetHolderCount_Success(t *testing.T) {
 	}
 }
 
+func TestStatsService_GetHolderCount_Approximate(t *testing.T) {
+	service, transferRepo, tokenRepo := setupStatsServiceTest()
+	ctx := context.Background()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDCAddress)))
+
+	counts := testutil.NewMockHolderCountRepository()
+	countedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	_ = counts.Save(ctx, testutil.USDTAddress, 5_000_000, countedAt)
+	_ = counts.Save(ctx, testutil.USDCAddress, 999, countedAt)
+	service.WithHolderCounts(counts, 1_000_000)
+
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
+		return 4523891, nil
+	}
+
+	// A token past the threshold gets its recorded count without counting
+	response, err := service.GetHolderCount(ctx, testutil.USDTAddress, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data := response.Data; data.HolderCount != 5_000_000 || !data.Approximate || data.CountedAt == nil || !data.CountedAt.Equal(countedAt) {
+		t.Errorf("expected the recorded approximate count, got %+v", data)
+	}
+	for _, call := range transferRepo.Calls {
+		if call.Method == "GetHolderCount" {
+			t.Fatal("expected no exact count for an approximate response")
+		}
+	}
+
+	// exact=true and tokens below the threshold are counted exactly
+	for _, tc := range []struct {
+		token string
+		exact bool
+	}{{testutil.USDTAddress, true}, {testutil.USDCAddress, false}} {
+		response, err := service.GetHolderCount(ctx, tc.token, false, tc.exact)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if data := response.Data; data.HolderCount != 4523891 || data.Approximate || data.CountedAt != nil {
+			t.Errorf("%s exact=%v: expected the exact count, got %+v", tc.token, tc.exact, data)
+		}
+	}
+}
+
 func TestStatsService_GetHolderCount_TokenNotFound(t *testing.T) {
 	service, _, _ := setupStatsServiceTest()
 	ctx := context.Background()
 
-	response, err := service.GetHolderCount(ctx, testutil.USDTAddress)
+	response, err := service.GetHolderCount(ctx, testutil.USDTAddress, false, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -292,14 +338,14 @@ func TestStatsService_GetHolderCount_Lowercase(t *testing.T) {
 
 	// Track which address was queried
 	var queriedAddress string
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		queriedAddress = tokenAddress
 		return 1000, nil
 	}
 
 	// Use uppercase address
 	upperAddr := "0xDAC17F958D2EE523A2206206994597C13D831EC7"
-	_, err := service.GetHolderCount(ctx, upperAddr)
+	_, err := service.GetHolderCount(ctx, upperAddr, false, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -317,7 +363,7 @@ func TestStatsService_GetHolderCount_TokenRepoError(t *testing.T) {
 		return nil, errors.New("database connection failed")
 	}
 
-	_, err := service.GetHolderCount(ctx, testutil.USDTAddress)
+	_, err := service.GetHolderCount(ctx, testutil.USDTAddress, false, false)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -335,11 +381,11 @@ func TestStatsService_GetHolderCount_TransferRepoError(t *testing.T) {
 		testutil.TokenWithAddress(testutil.USDTAddress),
 	))
 
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 0, errors.New("query timeout")
 	}
 
-	_, err := service.GetHolderCount(ctx, testutil.USDTAddress)
+	_, err := service.GetHolderCount(ctx, testutil.USDTAddress, false, false)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -347,3 +393,512 @@ func TestStatsService_GetHolderCount_TransferRepoError(t *testing.T) {
 		t.Errorf("unexpected error message: %v", err)
 	}
 }
+
+func TestStatsService_AddUSDValues(t *testing.T) {
+	service, _, tokenRepo := setupStatsServiceTest()
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+	service.WithPriceProvider(testutil.NewMockPriceProvider(map[string]float64{testutil.USDTAddress: 1}))
+
+	response := &TokenStatsResponse{Data: TokenStats{
+		TokenAddress: testutil.USDTAddress,
+		TotalVolume:  "123450000",
+		Volume24h:    "1000000",
+		Volume7d:     "0",
+	}}
+
+	service.AddUSDValues(ctx, response)
+
+	if response.Data.TotalVolumeUSD != "123.45" {
+		t.Errorf("expected 123.45, got %q", response.Data.TotalVolumeUSD)
+	}
+	if response.Data.Volume24hUSD != "1.00" {
+		t.Errorf("expected 1.00, got %q", response.Data.Volume24hUSD)
+	}
+	if response.Data.Volume7dUSD != "0.00" {
+		t.Errorf("expected 0.00, got %q", response.Data.Volume7dUSD)
+	}
+}
+
+func TestStatsService_GetTokenStats_FromRollup(t *testing.T) {
+	service, transferRepo, tokenRepo := setupStatsServiceTest()
+	dailyStatsRepo := testutil.NewMockDailyStatsRepository()
+	service.WithDailyStats(dailyStatsRepo)
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	for i := 9; i >= 0; i-- {
+		day := today.AddDate(0, 0, -i)
+		dailyStatsRepo.AddDailyStats(entities.TokenDailyStats{
+			TokenAddress:    testutil.USDTAddress,
+			Day:             day,
+			Transfers:       10,
+			Volume:          "100",
+			UniqueSenders:   5,
+			UniqueReceivers: 5,
+			NewSenders:      1,
+			NewHolders:      2,
+			FirstTransferAt: day.Add(time.Hour),
+			LastTransferAt:  day.Add(2 * time.Hour),
+		})
+	}
+
+	// Raw transfers inside the partial days at either edge of the 7d window
+	weekAgo := now.Add(-7 * 24 * time.Hour)
+	firstFullDay := weekAgo.Truncate(24*time.Hour).AddDate(0, 0, 1)
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(
+			testutil.WithTxHash("0x01"),
+			testutil.WithBlockTimestamp(today.Add(now.Sub(today)/2)),
+			testutil.WithValue(big.NewInt(5)),
+		),
+		testutil.CreateTestTransfer(
+			testutil.WithTxHash("0x02"),
+			testutil.WithBlockTimestamp(weekAgo.Add(firstFullDay.Sub(weekAgo)/2)),
+			testutil.WithValue(big.NewInt(7)),
+		),
+	)
+
+	response, err := service.GetTokenStats(ctx, testutil.USDTAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := response.Data
+	if stats.TotalTransfers != 100 || stats.TotalVolume != "1000" {
+		t.Errorf("unexpected totals: %d transfers, volume %s", stats.TotalTransfers, stats.TotalVolume)
+	}
+	if stats.UniqueFromAddresses != 10 || stats.UniqueToAddresses != 20 {
+		t.Errorf("expected unique addresses from new senders/holders, got %d/%d", stats.UniqueFromAddresses, stats.UniqueToAddresses)
+	}
+	if stats.Transfers24h != 1 || stats.Volume24h != "5" {
+		t.Errorf("expected 24h window from raw transfers, got %d/%s", stats.Transfers24h, stats.Volume24h)
+	}
+	if stats.Transfers7d != 62 || stats.Volume7d != "612" {
+		t.Errorf("expected 6 rollup days plus raw edges for 7d, got %d/%s", stats.Transfers7d, stats.Volume7d)
+	}
+	if stats.FirstTransferAt != today.AddDate(0, 0, -9).Add(time.Hour).Format("2006-01-02T15:04:05Z") {
+		t.Errorf("unexpected first transfer %s", stats.FirstTransferAt)
+	}
+
+	for _, call := range transferRepo.Calls {
+		if call.Method == "GetTokenStats" {
+			t.Error("expected raw GetTokenStats not to be called when the rollup is configured")
+		}
+	}
+}
+
+func TestStatsService_GetTokenStats_RollupError(t *testing.T) {
+	service, _, tokenRepo := setupStatsServiceTest()
+	dailyStatsRepo := testutil.NewMockDailyStatsRepository()
+	dailyStatsRepo.GetRangeFunc = func(ctx context.Context, tokenAddress string, from, to time.Time) ([]entities.TokenDailyStats, error) {
+		return nil, errors.New("database error")
+	}
+	service.WithDailyStats(dailyStatsRepo)
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	if _, err := service.GetTokenStats(context.Background(), testutil.USDTAddress); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestStatsService_GetTokenStats_FromAnalytics(t *testing.T) {
+	service, transferRepo, tokenRepo := setupStatsServiceTest()
+	analytics := testutil.NewMockAnalyticsRepository()
+	analytics.GetTokenStatsFunc = func(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error) {
+		return &repositories.TokenStatsResult{TotalTransfers: 42, TotalVolume: "5000", Volume24h: "0", Volume7d: "0"}, nil
+	}
+	service.WithAnalytics(analytics)
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	response, err := service.GetTokenStats(context.Background(), testutil.USDTAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Data.TotalTransfers != 42 || response.Data.TotalVolume != "5000" {
+		t.Errorf("unexpected stats: %+v", response.Data)
+	}
+
+	for _, call := range transferRepo.Calls {
+		if call.Method == "GetTokenStats" {
+			t.Error("expected database not to be queried when the analytics store answers")
+		}
+	}
+}
+
+func TestStatsService_GetTokenStats_AnalyticsFallback(t *testing.T) {
+	service, transferRepo, tokenRepo := setupStatsServiceTest()
+	analytics := testutil.NewMockAnalyticsRepository()
+	analytics.GetTokenStatsFunc = func(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error) {
+		return nil, errors.New("connection refused")
+	}
+	service.WithAnalytics(analytics)
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+	transferRepo.GetTokenStatsFunc = func(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error) {
+		return &repositories.TokenStatsResult{TotalTransfers: 7, TotalVolume: "70", Volume24h: "0", Volume7d: "0"}, nil
+	}
+
+	response, err := service.GetTokenStats(context.Background(), testutil.USDTAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Data.TotalTransfers != 7 {
+		t.Errorf("expected database stats after analytics failure, got %d transfers", response.Data.TotalTransfers)
+	}
+}
+
+func TestStatsService_GetHolderCount_FromAnalytics(t *testing.T) {
+	service, _, tokenRepo := setupStatsServiceTest()
+	analytics := testutil.NewMockAnalyticsRepository()
+	analytics.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
+		return 321, nil
+	}
+	service.WithAnalytics(analytics)
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	response, err := service.GetHolderCount(context.Background(), testutil.USDTAddress, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Data.HolderCount != 321 {
+		t.Errorf("expected holder count 321, got %d", response.Data.HolderCount)
+	}
+}
+
+func TestStatsService_GetActiveAddresses(t *testing.T) {
+	ctx := context.Background()
+	day1 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	t.Run("merges days", func(t *testing.T) {
+		service, _, tokenRepo := setupStatsServiceTest()
+		activeAddrs := testutil.NewMockActiveAddressRepository()
+		service.WithActiveAddresses(activeAddrs)
+		tokenRepo.AddToken(testutil.CreateTestToken())
+
+		_ = activeAddrs.AddActiveAddresses(ctx, testutil.USDTAddress, day1, []string{testutil.AliceAddress, testutil.BobAddress})
+		_ = activeAddrs.AddActiveAddresses(ctx, testutil.USDTAddress, day2, []string{testutil.BobAddress, testutil.CharlieAddr})
+
+		response, err := service.GetActiveAddresses(ctx, testutil.USDTAddress, day1, day2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Data.ActiveAddresses != 3 {
+			t.Errorf("expected 3 active addresses, got %d", response.Data.ActiveAddresses)
+		}
+		if response.Data.From != "2024-01-15" || response.Data.To != "2024-01-16" || !response.Data.Approximate {
+			t.Errorf("unexpected response: %+v", response.Data)
+		}
+
+		single, _ := service.GetActiveAddresses(ctx, testutil.USDTAddress, day2, day2)
+		if single.Data.ActiveAddresses != 2 {
+			t.Errorf("expected 2 active addresses on one day, got %d", single.Data.ActiveAddresses)
+		}
+	})
+
+	t.Run("token not found", func(t *testing.T) {
+		service, _, _ := setupStatsServiceTest()
+		service.WithActiveAddresses(testutil.NewMockActiveAddressRepository())
+
+		response, err := service.GetActiveAddresses(ctx, testutil.USDTAddress, day1, day2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response != nil {
+			t.Error("expected nil response for unknown token")
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		service, _, _ := setupStatsServiceTest()
+
+		if _, err := service.GetActiveAddresses(ctx, testutil.USDTAddress, day1, day2); err == nil {
+			t.Error("expected error without active address repository")
+		}
+	})
+}
+
+func TestStatsService_GetRangeStats(t *testing.T) {
+	service, transferRepo, _ := setupStatsServiceTest()
+	ctx := context.Background()
+
+	from := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithBlockTimestamp(from.Add(-time.Minute)), testutil.WithValue(big.NewInt(1))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithBlockTimestamp(from), testutil.WithValue(big.NewInt(10))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x03"), testutil.WithBlockTimestamp(to.Add(-time.Second)), testutil.WithValue(big.NewInt(20))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x04"), testutil.WithBlockTimestamp(to), testutil.WithValue(big.NewInt(100))),
+	)
+
+	// Seconds are rounded down to the minute
+	stats, err := service.GetRangeStats(ctx, testutil.USDTAddress, from.Add(30*time.Second), to.Add(30*time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Transfers != 2 || stats.Volume != "30" {
+		t.Errorf("expected 2 transfers with volume 30, got %d/%s", stats.Transfers, stats.Volume)
+	}
+	if stats.From != "2024-01-10T00:00:00Z" || stats.To != "2024-01-20T00:00:00Z" {
+		t.Errorf("unexpected range %s..%s", stats.From, stats.To)
+	}
+}
+
+func TestStatsService_GetRangeStats_FromRollup(t *testing.T) {
+	service, transferRepo, _ := setupStatsServiceTest()
+	dailyStatsRepo := testutil.NewMockDailyStatsRepository()
+	service.WithDailyStats(dailyStatsRepo)
+	ctx := context.Background()
+
+	day := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		dailyStatsRepo.AddDailyStats(entities.TokenDailyStats{
+			TokenAddress: testutil.USDTAddress,
+			Day:          day.AddDate(0, 0, i),
+			Transfers:    10,
+			Volume:       "100",
+		})
+	}
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithBlockTimestamp(day.Add(18*time.Hour)), testutil.WithValue(big.NewInt(7))),
+	)
+
+	// Half of the first day is read raw; the next three days come from the rollup
+	stats, err := service.GetRangeStats(ctx, testutil.USDTAddress, day.Add(12*time.Hour), day.AddDate(0, 0, 4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Transfers != 31 || stats.Volume != "307" {
+		t.Errorf("expected 3 rollup days plus a raw edge, got %d/%s", stats.Transfers, stats.Volume)
+	}
+}
+
+func TestStatsService_GetRangeStats_InvalidRange(t *testing.T) {
+	service, _, _ := setupStatsServiceTest()
+	ctx := context.Background()
+
+	from := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		to   time.Time
+	}{
+		{"empty after rounding", from.Add(30 * time.Second)},
+		{"inverted", from.Add(-time.Hour)},
+		{"too long", from.Add(MaxStatsRange + time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.GetRangeStats(ctx, testutil.USDTAddress, from, tt.to); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestStatsService_GetActivityHeatmap(t *testing.T) {
+	service, transferRepo, tokenRepo := setupStatsServiceTest()
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	// Two transfers in the same hour of a known weekday, one outside the window
+	recent := time.Now().UTC().AddDate(0, 0, -2).Truncate(time.Hour)
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithBlockTimestamp(recent)),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithBlockTimestamp(recent.Add(30*time.Minute))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x03"), testutil.WithBlockTimestamp(recent.AddDate(0, 0, -30))),
+	)
+
+	response, err := service.GetActivityHeatmap(ctx, testutil.USDTAddress, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if response.Data.TotalTransfers != 2 {
+		t.Errorf("expected 2 transfers, got %d", response.Data.TotalTransfers)
+	}
+	if got := response.Data.Counts[recent.Weekday()][recent.Hour()]; got != 2 {
+		t.Errorf("expected 2 transfers on %s at %02d:00, got %d", recent.Weekday(), recent.Hour(), got)
+	}
+}
+
+func TestStatsService_GetActivityHeatmap_TokenNotFound(t *testing.T) {
+	service, _, _ := setupStatsServiceTest()
+
+	response, err := service.GetActivityHeatmap(context.Background(), testutil.USDTAddress, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response != nil {
+		t.Error("expected nil response for unknown token")
+	}
+}
+
+func TestStatsService_GetNewHolders(t *testing.T) {
+	ctx := context.Background()
+	service, _, tokenRepo := setupStatsServiceTest()
+	dailyStats := testutil.NewMockDailyStatsRepository()
+	service.WithDailyStats(dailyStats)
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	dailyStats.AddDailyStats(
+		entities.TokenDailyStats{TokenAddress: testutil.USDTAddress, Day: today.AddDate(0, 0, -10), UniqueReceivers: 9, NewHolders: 9},
+		entities.TokenDailyStats{TokenAddress: testutil.USDTAddress, Day: yesterday, UniqueReceivers: 5, NewHolders: 2},
+		entities.TokenDailyStats{TokenAddress: testutil.USDTAddress, Day: today, UniqueReceivers: 3, NewHolders: 1},
+	)
+	dailyStats.AddFirstSeen(
+		entities.HolderFirstSeen{TokenAddress: testutil.USDTAddress, Address: testutil.AliceAddress, FirstBlock: 100, FirstSeenAt: today.AddDate(0, 0, -10)},
+		entities.HolderFirstSeen{TokenAddress: testutil.USDTAddress, Address: testutil.BobAddress, FirstBlock: 200, FirstSeenAt: yesterday.Add(time.Hour)},
+		entities.HolderFirstSeen{TokenAddress: testutil.USDTAddress, Address: testutil.CharlieAddr, FirstBlock: 300, FirstSeenAt: today.Add(time.Hour)},
+	)
+
+	response, err := service.GetNewHolders(ctx, testutil.USDTAddress, 7, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := response.Data
+	if data.From != today.AddDate(0, 0, -6).Format("2006-01-02") || data.To != today.Format("2006-01-02") {
+		t.Errorf("expected the 7 days up to today, got %s to %s", data.From, data.To)
+	}
+	// Days without transfers count zero
+	if len(data.Days) != 7 || data.Days[0].NewHolders != 0 || data.NewHolders != 3 {
+		t.Fatalf("expected 7 days and 3 new holders, got %+v", data)
+	}
+	if last := data.Days[6]; last.NewHolders != 1 || last.ReturningHolders != 2 {
+		t.Errorf("expected 1 new and 2 returning holders today, got %+v", last)
+	}
+	if len(data.Newest) != 2 || data.Newest[0].Address != testutil.CharlieAddr || data.Newest[1].FirstBlock != 200 {
+		t.Errorf("expected the holders first seen within the window, newest first, got %+v", data.Newest)
+	}
+
+	t.Run("token not found", func(t *testing.T) {
+		response, err := service.GetNewHolders(ctx, testutil.USDCAddress, 7, 10)
+		if err != nil || response != nil {
+			t.Errorf("expected nil response for unknown token, got %+v (%v)", response, err)
+		}
+	})
+}
+
+func TestStatsService_AdvancedStats(t *testing.T) {
+	ctx := context.Background()
+	service, transferRepo, tokenRepo := setupStatsServiceTest()
+	advanced := testutil.NewMockAdvancedStatsRepository()
+	service.WithAdvancedStats(advanced)
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	now := time.Now().UTC()
+	transfer := func(id int64, from, to string, value int64, at time.Time) entities.Transfer {
+		return testutil.CreateTestTransfer(
+			testutil.WithID(id),
+			testutil.WithFromAddress(from),
+			testutil.WithToAddress(to),
+			testutil.WithValue(big.NewInt(value)),
+			testutil.WithBlockTimestamp(at),
+		)
+	}
+	transferRepo.AddTransfers(
+		transfer(1, entities.ZeroAddress, testutil.AliceAddress, 1000, now.AddDate(0, 0, -400)),
+		transfer(2, entities.ZeroAddress, testutil.CharlieAddr, 600, now.AddDate(0, 0, -400)),
+		transfer(3, testutil.AliceAddress, testutil.BobAddress, 400, now.AddDate(0, 0, -10)),
+		transfer(4, entities.ZeroAddress, testutil.BobAddress, 200, now.AddDate(0, 0, -5)),
+		transfer(5, testutil.BobAddress, testutil.BobAddress, 50, now.AddDate(0, 0, -3)),
+	)
+	// An airdrop of three mints in one transaction
+	for i := int64(0); i < 3; i++ {
+		airdrop := transfer(6+i, entities.ZeroAddress, testutil.BobAddress, 10, now.AddDate(0, 0, -2))
+		airdrop.TxHash, airdrop.LogIndex = "0xairdrop", int(i)
+		transferRepo.AddTransfers(airdrop)
+	}
+
+	if _, err := service.GetAdvancedStats(ctx, testutil.USDTAddress); !errors.Is(err, ErrNoAdvancedStats) {
+		t.Fatalf("expected ErrNoAdvancedStats before the first run, got %v", err)
+	}
+
+	service.ComputeAdvancedStats(ctx, []string{testutil.USDTAddress}, 30*24*time.Hour, 365*24*time.Hour, 2)
+
+	response, err := service.GetAdvancedStats(ctx, testutil.USDTAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The supply grew from 1600 to 1830 during the window, in which 680 moved
+	data := response.Data
+	if data.Volume != "680" || data.AverageSupply != "1715" || data.Velocity != "0.3965" {
+		t.Errorf("expected volume 680 over an average supply of 1715, got %+v", data)
+	}
+	// Only Charlie has not moved funds within the year; the zero address is not a holder
+	if data.DormantSupply != "600" || data.DormantHolders != 1 || data.DormantPercentage != "32.7869" {
+		t.Errorf("expected Charlie's 600 dormant, got %+v", data)
+	}
+	// Bob's transfer to himself, and the airdrop as the one transaction with more than 2 transfers from a sender
+	if data.SelfTransfers != 1 || data.SelfTransferVolume != "50" {
+		t.Errorf("expected one self-transfer of 50, got %+v", data)
+	}
+	if data.FanOutThreshold != 2 || data.FanOutTransactions != 1 || data.FanOutTransfers != 3 || data.FanOutVolume != "30" {
+		t.Errorf("expected the airdrop as one fan-out of 3 transfers, got %+v", data)
+	}
+
+	t.Run("token not found", func(t *testing.T) {
+		response, err := service.GetAdvancedStats(ctx, testutil.USDCAddress)
+		if err != nil || response != nil {
+			t.Errorf("expected nil response for unknown token, got %+v (%v)", response, err)
+		}
+	})
+}
+
+func TestStatsService_GetTokenFlows(t *testing.T) {
+	ctx := context.Background()
+	service, _, tokenRepo := setupStatsServiceTest()
+	entityRepo := testutil.NewMockEntityRepository()
+	service.WithEntities(entityRepo)
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	var window [2]time.Time
+	entityRepo.GetCategoryFlowsFunc = func(ctx context.Context, tokenAddress, category string, from, to time.Time) ([]repositories.EntityFlow, error) {
+		window = [2]time.Time{from, to}
+		return []repositories.EntityFlow{
+			{EntityID: 1, Name: "binance", TransfersIn: 3, TransfersOut: 1, VolumeIn: "5000000", VolumeOut: "1500000"},
+			{EntityID: 2, Name: "kraken", TransfersIn: 1, TransfersOut: 2, VolumeIn: "1000000", VolumeOut: "7000000"},
+		}, nil
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC)
+	to := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	response, err := service.GetTokenFlows(ctx, testutil.USDTAddress, entities.EntityCategoryExchange, from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !window[0].Equal(from.Truncate(time.Minute)) || !window[1].Equal(to) {
+		t.Errorf("expected the window rounded to the minute, got %v", window)
+	}
+
+	data := response.Data
+	if len(data.Entities) != 2 || data.Entities[0].NetFlow != "3500000" || data.Entities[0].NetFlowFormatted != "3.5" ||
+		data.Entities[1].NetFlowFormatted != "-6" {
+		t.Errorf("unexpected entity flows: %+v", data.Entities)
+	}
+	if data.TransfersIn != 4 || data.TransfersOut != 3 || data.VolumeIn != "6000000" || data.VolumeOutFormatted != "8.5" || data.NetFlow != "-2500000" {
+		t.Errorf("unexpected category totals: %+v", data)
+	}
+
+	t.Run("token not found", func(t *testing.T) {
+		response, err := service.GetTokenFlows(ctx, testutil.USDCAddress, entities.EntityCategoryExchange, from, to)
+		if err != nil || response != nil {
+			t.Errorf("expected nil response for unknown token, got %+v (%v)", response, err)
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+// SwapService provides business logic for DEX swap queries
+type SwapService struct {
+	swapRepo repositories.SwapRepository
+	breaker  *Breaker
+	cache    *cache.RedisCache
+	logger   *zap.Logger
+}
+
+// NewSwapService creates a new swap service
+func NewSwapService(
+	swapRepo repositories.SwapRepository,
+	cache *cache.RedisCache,
+	logger *zap.Logger,
+) *SwapService {
+	return &SwapService{
+		swapRepo: swapRepo,
+		cache:    cache,
+		logger:   logger,
+	}
+}
+
+// WithBreaker retries swap reads through b and serves stale cached responses while the database is unavailable
+func (s *SwapService) WithBreaker(b *Breaker) *SwapService {
+	s.breaker = b
+	return s
+}
+
+// SwapResponse is the API response for pool swap queries
+type SwapResponse struct {
+	Pool    PoolDTO   `json:"pool"`
+	Swaps   []SwapDTO `json:"swaps"`
+	Total   int64     `json:"total"`
+	Limit   int       `json:"limit"`
+	Offset  int       `json:"offset"`
+	HasMore bool      `json:"has_more"`
+}
+
+// PoolDTO is the API representation of a DEX pool
+type PoolDTO struct {
+	Address       string `json:"address"`
+	Token0Address string `json:"token0_address"`
+	Token1Address string `json:"token1_address"`
+}
+
+// SwapDTO is the API representation of a swap.
+// Amounts are signed from the pool's perspective: positive flowed in, negative flowed out.
+type SwapDTO struct {
+	TxHash         string `json:"tx_hash"`
+	LogIndex       int    `json:"log_index"`
+	BlockNumber    int64  `json:"block_number"`
+	BlockTimestamp string `json:"block_timestamp"`
+	Protocol       string `json:"protocol"`
+	Sender         string `json:"sender"`
+	Recipient      string `json:"recipient"`
+	Amount0        string `json:"amount0"`
+	Amount1        string `json:"amount1"`
+}
+
+// SwapVolumeStats is the API representation of a token's swap volume
+type SwapVolumeStats struct {
+	TokenAddress string `json:"token_address"`
+	SwapCount    int64  `json:"swap_count"`
+	PoolCount    int64  `json:"pool_count"`
+	TotalVolume  string `json:"total_volume"`
+	SwapCount24h int64  `json:"swap_count_24h"`
+	Volume24h    string `json:"volume_24h"`
+	SwapCount7d  int64  `json:"swap_count_7d"`
+	Volume7d     string `json:"volume_7d"`
+}
+
+// SwapVolumeResponse is the API response for token swap volume queries
+type SwapVolumeResponse struct {
+	Data SwapVolumeStats `json:"data"`
+}
+
+// GetPoolSwaps retrieves swaps for a pool, newest first
+func (s *SwapService) GetPoolSwaps(ctx context.Context, poolAddress string, limit, offset int) (*SwapResponse, error) {
+	poolAddress = strings.ToLower(poolAddress)
+
+	// Generate cache key
+	cacheKey := fmt.Sprintf("swaps:%s:%d:%d", poolAddress, limit, offset)
+
+	// Try cache first
+	var cached SwapResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	return guardedLoad(ctx, s.breaker, s.cache, s.logger, "swaps.GetPoolSwaps", cacheKey, func(ctx context.Context) (*SwapResponse, error) {
+		return s.loadPoolSwaps(ctx, poolAddress, limit, offset, cacheKey)
+	})
+}
+
+// loadPoolSwaps reads a page of a pool's swaps from the database and caches it under cacheKey
+func (s *SwapService) loadPoolSwaps(ctx context.Context, poolAddress string, limit, offset int, cacheKey string) (*SwapResponse, error) {
+	pool, err := s.swapRepo.GetPool(ctx, poolAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool: %w", err)
+	}
+	if pool == nil {
+		return nil, nil // Pool not indexed
+	}
+
+	filter := entities.SwapFilter{
+		PoolAddress: &poolAddress,
+		Limit:       limit,
+		Offset:      offset,
+	}
+
+	swaps, err := s.swapRepo.GetByFilter(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swaps: %w", err)
+	}
+
+	total, err := s.swapRepo.GetCount(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap count: %w", err)
+	}
+
+	dtos := make([]SwapDTO, len(swaps))
+	for i, sw := range swaps {
+		dtos[i] = SwapDTO{
+			TxHash:         sw.TxHash,
+			LogIndex:       sw.LogIndex,
+			BlockNumber:    sw.BlockNumber,
+			BlockTimestamp: sw.BlockTimestamp.Format("2006-01-02T15:04:05Z"),
+			Protocol:       sw.Protocol,
+			Sender:         sw.Sender,
+			Recipient:      sw.Recipient,
+			Amount0:        sw.Amount0String,
+			Amount1:        sw.Amount1String,
+		}
+	}
+
+	response := &SwapResponse{
+		Pool: PoolDTO{
+			Address:       pool.Address,
+			Token0Address: pool.Token0Address,
+			Token1Address: pool.Token1Address,
+		},
+		Swaps:   dtos,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: int64(offset+len(swaps)) < total,
+	}
+
+	// Cache the response
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, cacheKey, response); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// GetTokenSwapVolume retrieves swap volume for a token across all indexed pools
+func (s *SwapService) GetTokenSwapVolume(ctx context.Context, tokenAddress string) (*SwapVolumeResponse, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	// Generate cache key
+	cacheKey := fmt.Sprintf("swap_volume:%s", tokenAddress)
+
+	// Try cache first
+	var cached SwapVolumeResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	return guardedLoad(ctx, s.breaker, s.cache, s.logger, "swaps.GetTokenSwapVolume", cacheKey, func(ctx context.Context) (*SwapVolumeResponse, error) {
+		return s.loadTokenSwapVolume(ctx, tokenAddress, cacheKey)
+	})
+}
+
+// loadTokenSwapVolume reads a token's swap volume from the database and caches it under cacheKey
+func (s *SwapService) loadTokenSwapVolume(ctx context.Context, tokenAddress, cacheKey string) (*SwapVolumeResponse, error) {
+	volume, err := s.swapRepo.GetTokenSwapVolume(ctx, tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap volume: %w", err)
+	}
+
+	response := &SwapVolumeResponse{
+		Data: SwapVolumeStats{
+			TokenAddress: tokenAddress,
+			SwapCount:    volume.SwapCount,
+			PoolCount:    volume.PoolCount,
+			TotalVolume:  volume.TotalVolume,
+			SwapCount24h: volume.SwapCount24h,
+			Volume24h:    volume.Volume24h,
+			SwapCount7d:  volume.SwapCount7d,
+			Volume7d:     volume.Volume7d,
+		},
+	}
+
+	// Cache the response with shorter TTL (60 seconds for stats)
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, 60*time.Second); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+const testPoolAddress = "0x0d4a11d5eeaac28ec3f61d100daf4d40471f1852"
+
+func newTestSwapRepo(t *testing.T, swapCount int) *testutil.MockSwapRepository {
+	t.Helper()
+
+	repo := testutil.NewMockSwapRepository()
+	_ = repo.UpsertPool(context.Background(), &entities.DexPool{
+		Address:       testPoolAddress,
+		Token0Address: testutil.USDCAddress,
+		Token1Address: testutil.USDTAddress,
+	})
+	for i := 0; i < swapCount; i++ {
+		repo.AddSwap(entities.Swap{
+			TxHash:         "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			LogIndex:       i,
+			BlockNumber:    19000000,
+			BlockTimestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			PoolAddress:    testPoolAddress,
+			Protocol:       "uniswap_v2",
+			Sender:         testutil.AliceAddress,
+			Recipient:      testutil.BobAddress,
+			Token0Address:  testutil.USDCAddress,
+			Token1Address:  testutil.USDTAddress,
+			Amount0String:  "1000000",
+			Amount1String:  "-999000",
+		})
+	}
+	return repo
+}
+
+func TestSwapService_GetPoolSwaps(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("returns swaps with pool tokens", func(t *testing.T) {
+		repo := newTestSwapRepo(t, 3)
+		service := NewSwapService(repo, nil, logger)
+
+		result, err := service.GetPoolSwaps(ctx, testPoolAddress, 2, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result == nil {
+			t.Fatal("expected result, got nil")
+		}
+
+		if result.Pool.Token0Address != testutil.USDCAddress || result.Pool.Token1Address != testutil.USDTAddress {
+			t.Errorf("unexpected pool tokens: %+v", result.Pool)
+		}
+		if len(result.Swaps) != 2 {
+			t.Errorf("expected 2 swaps, got %d", len(result.Swaps))
+		}
+		if result.Total != 3 || !result.HasMore {
+			t.Errorf("expected total 3 with more, got total %d has_more %v", result.Total, result.HasMore)
+		}
+		if result.Swaps[0].Amount1 != "-999000" {
+			t.Errorf("expected amount1 -999000, got %s", result.Swaps[0].Amount1)
+		}
+	})
+
+	t.Run("returns nil for unknown pool", func(t *testing.T) {
+		service := NewSwapService(testutil.NewMockSwapRepository(), nil, logger)
+
+		result, err := service.GetPoolSwaps(ctx, testPoolAddress, 100, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != nil {
+			t.Errorf("expected nil result, got %+v", result)
+		}
+	})
+
+	t.Run("normalizes pool address", func(t *testing.T) {
+		repo := newTestSwapRepo(t, 1)
+		service := NewSwapService(repo, nil, logger)
+
+		result, err := service.GetPoolSwaps(ctx, "0x0D4A11D5EEAAC28EC3F61D100DAF4D40471F1852", 100, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result == nil || len(result.Swaps) != 1 {
+			t.Fatalf("expected 1 swap, got %+v", result)
+		}
+	})
+
+	t.Run("returns error on repository failure", func(t *testing.T) {
+		repo := newTestSwapRepo(t, 1)
+		repo.GetByFilterFunc = func(ctx context.Context, filter entities.SwapFilter) ([]entities.Swap, error) {
+			return nil, errors.New("database error")
+		}
+		service := NewSwapService(repo, nil, logger)
+
+		if _, err := service.GetPoolSwaps(ctx, testPoolAddress, 100, 0); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestSwapService_GetTokenSwapVolume(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("returns volume stats", func(t *testing.T) {
+		repo := testutil.NewMockSwapRepository()
+		repo.GetTokenSwapVolumeFunc = func(ctx context.Context, tokenAddress string) (*repositories.SwapVolumeResult, error) {
+			if tokenAddress != testutil.USDTAddress {
+				t.Errorf("expected normalized token address, got %s", tokenAddress)
+			}
+			return &repositories.SwapVolumeResult{
+				SwapCount:    10,
+				PoolCount:    2,
+				TotalVolume:  "5000000",
+				SwapCount24h: 1,
+				Volume24h:    "100",
+				SwapCount7d:  4,
+				Volume7d:     "2000",
+			}, nil
+		}
+		service := NewSwapService(repo, nil, logger)
+
+		result, err := service.GetTokenSwapVolume(ctx, "0xdAC17F958D2ee523a2206206994597C13D831ec7")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.Data.SwapCount != 10 || result.Data.PoolCount != 2 || result.Data.TotalVolume != "5000000" {
+			t.Errorf("unexpected stats: %+v", result.Data)
+		}
+	})
+
+	t.Run("returns error on repository failure", func(t *testing.T) {
+		repo := testutil.NewMockSwapRepository()
+		repo.GetTokenSwapVolumeFunc = func(ctx context.Context, tokenAddress string) (*repositories.SwapVolumeResult, error) {
+			return nil, errors.New("database error")
+		}
+		service := NewSwapService(repo, nil, logger)
+
+		if _, err := service.GetTokenSwapVolume(ctx, testutil.USDTAddress); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
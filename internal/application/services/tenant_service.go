@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+// MaxTenantTokens caps how many tokens one tenant can track
+const MaxTenantTokens = 1000
+
+// ErrTenantFull is returned when a token set would exceed MaxTenantTokens
+var ErrTenantFull = errors.New("tenant token limit exceeded")
+
+// tenantCacheTTL is how long a resolved API key is answered from memory. Token set and rate
+// limit changes made through another API instance take up to this long to apply.
+const tenantCacheTTL = 30 * time.Second
+
+// maxCachedTenantKeys bounds the resolved key cache; it is cleared when full
+const maxCachedTenantKeys = 10000
+
+// maxCachedUnknownKeys bounds the cache of keys that resolved to no tenant. It is kept apart
+// from the resolved keys and cleared on its own when full, so unknown keys sent in bulk neither
+// grow it without limit nor evict real tenants' keys.
+const maxCachedUnknownKeys = 10000
+
+type tenantContextKey struct{}
+
+// ContextWithTenant returns a copy of ctx scoped to tenant
+func ContextWithTenant(ctx context.Context, tenant *entities.Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant a request is scoped to, or nil for requests made
+// without an API key
+func TenantFromContext(ctx context.Context) *entities.Tenant {
+	tenant, _ := ctx.Value(tenantContextKey{}).(*entities.Tenant)
+	return tenant
+}
+
+// TenantService manages tenants and resolves their API keys
+type TenantService struct {
+	tenantRepo repositories.TenantRepository
+	cache      *cache.RedisCache
+	logger     *zap.Logger
+
+	mu      sync.Mutex
+	keys    map[string]cachedTenant
+	unknown map[string]time.Time // key digest to expiry
+}
+
+// cachedTenant is a resolved API key
+type cachedTenant struct {
+	tenant  *entities.Tenant
+	expires time.Time
+}
+
+// NewTenantService creates a new tenant service
+func NewTenantService(tenantRepo repositories.TenantRepository, cache *cache.RedisCache, logger *zap.Logger) *TenantService {
+	return &TenantService{
+		tenantRepo: tenantRepo,
+		cache:      cache,
+		logger:     logger,
+		keys:       make(map[string]cachedTenant),
+		unknown:    make(map[string]time.Time),
+	}
+}
+
+// TenantDTO is the API representation of a tenant
+type TenantDTO struct {
+	ID           int64    `json:"id"`
+	Name         string   `json:"name"`
+	RateLimitRPS int      `json:"rate_limit_rps"`
+	Tokens       []string `json:"tokens"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+// TenantResponse is the API response for tenant queries
+type TenantResponse struct {
+	Data TenantDTO `json:"data"`
+}
+
+// TenantKeyResponse is the API response carrying a new API key. The key is not stored and
+// cannot be shown again.
+type TenantKeyResponse struct {
+	Data   TenantDTO `json:"data"`
+	APIKey string    `json:"api_key"`
+}
+
+// CreateTenant creates a tenant tracking the given tokens and returns it with its first API key
+func (s *TenantService) CreateTenant(ctx context.Context, name string, rateLimitRPS int, tokens []string) (*TenantKeyResponse, error) {
+	tokens, err := normalizeTenantTokens(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant := &entities.Tenant{
+		Name:         name,
+		RateLimitRPS: rateLimitRPS,
+		Tokens:       tokens,
+	}
+	if err = s.tenantRepo.Create(ctx, tenant); err != nil {
+		return nil, fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	key, err := s.addAPIKey(ctx, tenant.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Tenant created", zap.Int64("tenant_id", tenant.ID), zap.String("name", tenant.Name))
+	return &TenantKeyResponse{Data: toTenantDTO(tenant), APIKey: key}, nil
+}
+
+// GetTenant retrieves a tenant, or nil if it does not exist
+func (s *TenantService) GetTenant(ctx context.Context, id int64) (*TenantResponse, error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	if tenant == nil {
+		return nil, nil
+	}
+
+	return &TenantResponse{Data: toTenantDTO(tenant)}, nil
+}
+
+// SetTokens replaces a tenant's token set and returns the updated tenant, or nil if it does not exist
+func (s *TenantService) SetTokens(ctx context.Context, id int64, tokens []string) (*TenantResponse, error) {
+	tokens, err := normalizeTenantTokens(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := s.tenantRepo.SetTokens(ctx, id, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set tenant tokens: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	// Keys resolved on this instance pick up the new token set on their next request
+	s.mu.Lock()
+	for hash, cached := range s.keys {
+		if cached.tenant.ID == id {
+			delete(s.keys, hash)
+		}
+	}
+	s.mu.Unlock()
+
+	if s.cache != nil {
+		if err := s.cache.DeletePattern(ctx, fmt.Sprintf("tokens:list:tenant:%d:*", id)); err != nil {
+			s.logger.Warn("Failed to invalidate cache", zap.Error(err))
+		}
+	}
+
+	return s.GetTenant(ctx, id)
+}
+
+// CreateAPIKey issues another API key for a tenant, or returns nil if the tenant does not exist
+func (s *TenantService) CreateAPIKey(ctx context.Context, id int64) (*TenantKeyResponse, error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	if tenant == nil {
+		return nil, nil
+	}
+
+	key, err := s.addAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Tenant API key created", zap.Int64("tenant_id", id))
+	return &TenantKeyResponse{Data: toTenantDTO(tenant), APIKey: key}, nil
+}
+
+// ResolveAPIKey returns the tenant owning an API key, or nil if the key is unknown
+func (s *TenantService) ResolveAPIKey(ctx context.Context, key string) (*entities.Tenant, error) {
+	hash := hashAPIKey(key)
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.keys[hash]
+	unknownUntil, unknown := s.unknown[hash]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.tenant, nil
+	}
+	if unknown && now.Before(unknownUntil) {
+		return nil, nil
+	}
+
+	tenant, err := s.tenantRepo.GetByAPIKey(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve API key: %w", err)
+	}
+
+	s.mu.Lock()
+	if tenant == nil {
+		if len(s.unknown) >= maxCachedUnknownKeys {
+			s.unknown = make(map[string]time.Time)
+		}
+		s.unknown[hash] = now.Add(tenantCacheTTL)
+	} else {
+		if len(s.keys) >= maxCachedTenantKeys {
+			s.keys = make(map[string]cachedTenant)
+		}
+		s.keys[hash] = cachedTenant{tenant: tenant, expires: now.Add(tenantCacheTTL)}
+	}
+	s.mu.Unlock()
+
+	return tenant, nil
+}
+
+// addAPIKey generates a random API key, stores its digest for the tenant and returns the key
+func (s *TenantService) addAPIKey(ctx context.Context, id int64) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := hex.EncodeToString(b)
+
+	if err := s.tenantRepo.AddAPIKey(ctx, id, hashAPIKey(key)); err != nil {
+		return "", fmt.Errorf("failed to store API key: %w", err)
+	}
+	return key, nil
+}
+
+// normalizeTenantTokens lowercases and deduplicates tokens into the address order tenants keep
+func normalizeTenantTokens(tokens []string) ([]string, error) {
+	tokens = normalizeAddresses(tokens)
+	if len(tokens) > MaxTenantTokens {
+		return nil, ErrTenantFull
+	}
+	sort.Strings(tokens)
+	return tokens, nil
+}
+
+// hashAPIKey returns the SHA-256 hex digest API keys are stored and looked up by
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// tenantHasToken reports whether a token is in a tenant's token set
+func tenantHasToken(tenant *entities.Tenant, token string) bool {
+	return containsString(tenant.Tokens, token)
+}
+
+func toTenantDTO(tenant *entities.Tenant) TenantDTO {
+	tokens := tenant.Tokens
+	if tokens == nil {
+		tokens = []string{}
+	}
+	return TenantDTO{
+		ID:           tenant.ID,
+		Name:         tenant.Name,
+		RateLimitRPS: tenant.RateLimitRPS,
+		Tokens:       tokens,
+		CreatedAt:    tenant.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
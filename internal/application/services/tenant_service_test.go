@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestTenantService_CreateAndResolve(t *testing.T) {
+	ctx := context.Background()
+	repo := testutil.NewMockTenantRepository(testutil.NewMockTokenRepository())
+	service := NewTenantService(repo, nil, zap.NewNop())
+
+	created, err := service.CreateTenant(ctx, "acme", 5, []string{testutil.USDTAddress, "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", testutil.USDTAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created.APIKey) != 64 {
+		t.Errorf("expected a 64 hex digit API key, got %q", created.APIKey)
+	}
+	if len(created.Data.Tokens) != 2 || created.Data.Tokens[0] != testutil.USDCAddress {
+		t.Errorf("expected the tokens lowercased, deduplicated and sorted, got %v", created.Data.Tokens)
+	}
+
+	tenant, err := service.ResolveAPIKey(ctx, created.APIKey)
+	if err != nil || tenant == nil || tenant.ID != created.Data.ID || tenant.RateLimitRPS != 5 {
+		t.Fatalf("expected the key to resolve to the tenant, got %+v (%v)", tenant, err)
+	}
+	if tenant, err := service.ResolveAPIKey(ctx, "unknown"); err != nil || tenant != nil {
+		t.Errorf("expected an unknown key to resolve to nothing, got %+v (%v)", tenant, err)
+	}
+
+	// A second key resolves to the same tenant; the stored digest never equals the key
+	second, err := service.CreateAPIKey(ctx, created.Data.ID)
+	if err != nil || second == nil || second.APIKey == created.APIKey {
+		t.Fatalf("expected a new key, got %+v (%v)", second, err)
+	}
+	if tenant, _ := service.ResolveAPIKey(ctx, second.APIKey); tenant == nil || tenant.ID != created.Data.ID {
+		t.Errorf("expected the second key to resolve to the tenant, got %+v", tenant)
+	}
+	for _, call := range repo.Calls {
+		if call.Method == "AddAPIKey" && call.Args[1] == created.APIKey {
+			t.Error("expected the API key to be stored hashed")
+		}
+	}
+
+	if missing, err := service.CreateAPIKey(ctx, 99); err != nil || missing != nil {
+		t.Errorf("expected no key for a missing tenant, got %+v (%v)", missing, err)
+	}
+}
+
+func TestTenantService_SetTokens(t *testing.T) {
+	ctx := context.Background()
+	service := NewTenantService(testutil.NewMockTenantRepository(testutil.NewMockTokenRepository()), nil, zap.NewNop())
+
+	created, err := service.CreateTenant(ctx, "acme", 0, []string{testutil.USDTAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.ResolveAPIKey(ctx, created.APIKey); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := service.SetTokens(ctx, created.Data.ID, []string{testutil.USDCAddress})
+	if err != nil || updated == nil || len(updated.Data.Tokens) != 1 || updated.Data.Tokens[0] != testutil.USDCAddress {
+		t.Fatalf("unexpected tenant: %+v (%v)", updated, err)
+	}
+
+	// The cached key picks up the new token set
+	tenant, err := service.ResolveAPIKey(ctx, created.APIKey)
+	if err != nil || len(tenant.Tokens) != 1 || tenant.Tokens[0] != testutil.USDCAddress {
+		t.Errorf("expected the resolved tenant to track the new tokens, got %+v (%v)", tenant, err)
+	}
+
+	if missing, err := service.SetTokens(ctx, 99, nil); err != nil || missing != nil {
+		t.Errorf("expected nil for a missing tenant, got %+v (%v)", missing, err)
+	}
+
+	tooMany := make([]string, MaxTenantTokens+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("0x%040x", i)
+	}
+	if _, err := service.SetTokens(ctx, created.Data.ID, tooMany); !errors.Is(err, ErrTenantFull) {
+		t.Errorf("expected ErrTenantFull, got %v", err)
+	}
+}
+
+func TestTenantService_UnknownKeysKeepTenantsCached(t *testing.T) {
+	ctx := context.Background()
+	repo := testutil.NewMockTenantRepository(testutil.NewMockTokenRepository())
+	service := NewTenantService(repo, nil, zap.NewNop())
+
+	created, err := service.CreateTenant(ctx, "acme", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant, err := service.ResolveAPIKey(ctx, created.APIKey); err != nil || tenant == nil {
+		t.Fatalf("expected the key to resolve, got %+v (%v)", tenant, err)
+	}
+
+	// Enough unknown keys to fill their cache do not evict the tenant's
+	for i := 0; i <= maxCachedUnknownKeys; i++ {
+		if tenant, err := service.ResolveAPIKey(ctx, fmt.Sprintf("unknown-%d", i)); err != nil || tenant != nil {
+			t.Fatalf("expected an unknown key to resolve to nothing, got %+v (%v)", tenant, err)
+		}
+	}
+
+	lookups := func() int {
+		n := 0
+		for _, call := range repo.Calls {
+			if call.Method == "GetByAPIKey" {
+				n++
+			}
+		}
+		return n
+	}
+	before := lookups()
+	if tenant, err := service.ResolveAPIKey(ctx, created.APIKey); err != nil || tenant == nil || lookups() != before {
+		t.Errorf("expected the tenant's key answered from the cache, got %+v (%v) after %d lookups", tenant, err, lookups()-before)
+	}
+	if _, err := service.ResolveAPIKey(ctx, "unknown-1"); err != nil || lookups() != before+1 {
+		t.Errorf("expected the cleared unknown key looked up again, got %d lookups (%v)", lookups()-before, err)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// TokenExposure decides which indexed tokens the public API serves: only the listed ones in
+// allow-list mode, or all but the listed ones in deny-list mode. Requests made with the admin
+// token see every token. A nil TokenExposure exposes every token.
+type TokenExposure struct {
+	listed  map[string]bool
+	allowed bool // listed is an allow-list rather than a deny-list
+	scope   repositories.TokenScope
+}
+
+// NewTokenExposure returns the exposure for the exposed (allow-list) or hidden (deny-list)
+// token addresses, of which at most one may be given. It returns nil when neither is.
+func NewTokenExposure(exposed, hidden []string) (*TokenExposure, error) {
+	exposed, hidden = trimAddresses(exposed), trimAddresses(hidden)
+	if len(exposed) > 0 && len(hidden) > 0 {
+		return nil, errors.New("exposed and hidden tokens cannot both be set")
+	}
+	if len(exposed) == 0 && len(hidden) == 0 {
+		return nil, nil
+	}
+
+	e := &TokenExposure{listed: make(map[string]bool), allowed: len(exposed) > 0}
+	addresses := hidden
+	if e.allowed {
+		addresses = exposed
+	}
+	for _, address := range addresses {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid token address %q", address)
+		}
+		address = strings.ToLower(address)
+		if !e.listed[address] {
+			e.listed[address] = true
+			if e.allowed {
+				e.scope.Only = append(e.scope.Only, address)
+			} else {
+				e.scope.Except = append(e.scope.Except, address)
+			}
+		}
+	}
+	return e, nil
+}
+
+// trimAddresses drops blank entries and surrounding spaces from a configured address list
+func trimAddresses(addresses []string) []string {
+	var result []string
+	for _, address := range addresses {
+		if address = strings.TrimSpace(address); address != "" {
+			result = append(result, address)
+		}
+	}
+	return result
+}
+
+type adminContextKey struct{}
+
+// ContextWithAdmin returns a copy of ctx marked as a request made with the admin token
+func ContextWithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminContextKey{}, true)
+}
+
+// IsAdmin reports whether a request was made with the admin token
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminContextKey{}).(bool)
+	return admin
+}
+
+// Exposes reports whether the request may see the token at address
+func (e *TokenExposure) Exposes(ctx context.Context, address string) bool {
+	if e == nil || IsAdmin(ctx) {
+		return true
+	}
+	return e.listed[strings.ToLower(address)] == e.allowed
+}
+
+// Scope returns the scope of the token listings the request may see
+func (e *TokenExposure) Scope(ctx context.Context) repositories.TokenScope {
+	if e == nil || IsAdmin(ctx) {
+		return repositories.TokenScope{}
+	}
+	return e.scope
+}
+
+// narrowed reports whether the request sees fewer tokens than an admin, so responses built for
+// it are cached apart from complete ones
+func (e *TokenExposure) narrowed(ctx context.Context) bool {
+	return e != nil && !IsAdmin(ctx)
+}
+
+// scopeTransfers narrows a transfer filter to the tokens the request may see
+func (e *TokenExposure) scopeTransfers(ctx context.Context, filter *entities.TransferFilter) {
+	scope := e.Scope(ctx)
+	filter.Tokens, filter.ExcludeTokens = scope.Only, scope.Except
+}
+
+// exposedOnly returns the items whose token the request may see
+func exposedOnly[T any](ctx context.Context, e *TokenExposure, items []T, token func(T) string) []T {
+	if !e.narrowed(ctx) {
+		return items
+	}
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if e.Exposes(ctx, token(item)) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
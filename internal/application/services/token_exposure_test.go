@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestNewTokenExposure(t *testing.T) {
+	if e, err := NewTokenExposure(nil, []string{" "}); err != nil || e != nil {
+		t.Errorf("expected no exposure without tokens, got %+v (%v)", e, err)
+	}
+	if _, err := NewTokenExposure([]string{testutil.USDTAddress}, []string{testutil.USDCAddress}); err == nil {
+		t.Error("expected an error with both exposed and hidden tokens")
+	}
+	if _, err := NewTokenExposure([]string{"0xinvalid"}, nil); err == nil {
+		t.Error("expected an error for an invalid address")
+	}
+}
+
+func TestTokenExposure_Exposes(t *testing.T) {
+	ctx := context.Background()
+	admin := ContextWithAdmin(ctx)
+
+	allow, err := NewTokenExposure([]string{"0xdAC17F958D2ee523a2206206994597C13D831ec7"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allow.Exposes(ctx, testutil.USDTAddress) || allow.Exposes(ctx, testutil.USDCAddress) || !allow.Exposes(admin, testutil.USDCAddress) {
+		t.Error("expected the allow-list to expose only its tokens, and every token to admins")
+	}
+	if scope := allow.Scope(ctx); len(scope.Only) != 1 || scope.Only[0] != testutil.USDTAddress {
+		t.Errorf("expected listings narrowed to the exposed token, got %+v", scope)
+	}
+	if scope := allow.Scope(admin); len(scope.Only) != 0 || len(scope.Except) != 0 {
+		t.Errorf("expected admins to list every token, got %+v", scope)
+	}
+
+	deny, err := NewTokenExposure(nil, []string{testutil.USDCAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deny.Exposes(ctx, testutil.USDTAddress) || deny.Exposes(ctx, testutil.USDCAddress) {
+		t.Error("expected the deny-list to hide only its tokens")
+	}
+
+	var none *TokenExposure
+	if !none.Exposes(ctx, testutil.USDCAddress) {
+		t.Error("expected every token to be exposed without an exposure")
+	}
+}
+
+func TestTokenService_Exposure(t *testing.T) {
+	ctx := context.Background()
+	tokenRepo := testutil.NewMockTokenRepository()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDCAddress)))
+
+	exposure, err := NewTokenExposure(nil, []string{testutil.USDCAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewTokenService(tokenRepo, nil, zap.NewNop()).WithExposure(exposure)
+
+	list, err := service.GetAllTokens(ctx, 100, 0, "symbol", "asc", false)
+	if err != nil || list.Pagination.Total != 1 || list.Data[0].Address != testutil.USDTAddress {
+		t.Errorf("expected only the exposed token listed, got %+v (%v)", list, err)
+	}
+	if list, err := service.GetAllTokens(ContextWithAdmin(ctx), 100, 0, "symbol", "asc", false); err != nil || list.Pagination.Total != 2 {
+		t.Errorf("expected admins to see both tokens, got %+v (%v)", list, err)
+	}
+
+	if token, err := service.GetByAddress(ctx, testutil.USDCAddress); err != nil || token != nil {
+		t.Errorf("expected the hidden token not to be found, got %+v (%v)", token, err)
+	}
+	if token, err := service.GetByAddress(ContextWithAdmin(ctx), testutil.USDCAddress); err != nil || token == nil {
+		t.Errorf("expected admins to find the hidden token, got %+v (%v)", token, err)
+	}
+
+	batch, err := service.GetByAddresses(ctx, []string{testutil.USDTAddress, testutil.USDCAddress})
+	if err != nil || len(batch.Data) != 1 || len(batch.NotFound) != 1 || batch.NotFound[0] != testutil.USDCAddress {
+		t.Errorf("expected the hidden token reported as not found, got %+v (%v)", batch, err)
+	}
+}
+
+func TestTransferService_Exposure(t *testing.T) {
+	ctx := context.Background()
+	admin := ContextWithAdmin(ctx)
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithLogIndex(0), testutil.WithTokenAddress(testutil.USDTAddress)),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithLogIndex(1), testutil.WithTokenAddress(testutil.USDCAddress)),
+	)
+
+	exposure, err := NewTokenExposure(nil, []string{testutil.USDCAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, zap.NewNop()).WithExposure(exposure)
+
+	// Feeds not filtered by token leave the hidden token out
+	feed, err := service.GetTransfers(ctx, entities.DefaultTransferFilter())
+	if err != nil || feed.Total != 1 || len(feed.Transfers) != 1 || feed.Transfers[0].TokenAddress != testutil.USDTAddress {
+		t.Errorf("expected only the exposed token's transfer, got %+v (%v)", feed, err)
+	}
+	page, err := service.GetTransferPage(ctx, entities.DefaultTransferFilter())
+	if err != nil || len(page.Data) != 1 || page.Data[0].TokenAddress != testutil.USDTAddress {
+		t.Errorf("expected only the exposed token's transfer on the page, got %+v (%v)", page, err)
+	}
+	events, err := service.GetTransfersAfter(ctx, entities.DefaultTransferFilter(), 0)
+	if err != nil || len(events) != 1 || events[0].Transfer.TokenAddress != testutil.USDTAddress {
+		t.Errorf("expected only the exposed token's transfer on the stream, got %+v (%v)", events, err)
+	}
+
+	// Watchlists and entities read through the same feed
+	alice := testutil.AliceAddress
+	filter := entities.DefaultTransferFilter()
+	filter.Addresses = []string{alice}
+	if watched, err := service.GetTransfers(ctx, filter); err != nil || watched.Total != 1 {
+		t.Errorf("expected the hidden token left out of address feeds, got %+v (%v)", watched, err)
+	}
+
+	if feed, err := service.GetTransfers(admin, entities.DefaultTransferFilter()); err != nil || feed.Total != 2 {
+		t.Errorf("expected admins to see both transfers, got %+v (%v)", feed, err)
+	}
+}
+
+func TestPortfolioService_Exposure(t *testing.T) {
+	ctx := context.Background()
+	admin := ContextWithAdmin(ctx)
+	wallet := testutil.AliceAddress
+
+	portfolioRepo := testutil.NewMockPortfolioRepository()
+	portfolioRepo.GetWalletHoldingsFunc = func(ctx context.Context, walletAddress string) ([]entities.TokenHolding, error) {
+		return []entities.TokenHolding{
+			{TokenAddress: testutil.USDTAddress, TokenSymbol: "USDT", BalanceStr: "100"},
+			{TokenAddress: testutil.USDCAddress, TokenSymbol: "USDC", BalanceStr: "200"},
+		}, nil
+	}
+	portfolioRepo.GetWalletTokenActivityFunc = func(ctx context.Context, walletAddress string) ([]repositories.WalletTokenActivity, error) {
+		return []repositories.WalletTokenActivity{
+			{TokenAddress: testutil.USDTAddress, Balance: "100"},
+			{TokenAddress: testutil.USDCAddress, Balance: "200"},
+		}, nil
+	}
+	portfolioRepo.GetEntityHoldingsFunc = func(ctx context.Context, addresses []string) ([]entities.TokenHolding, error) {
+		return portfolioRepo.GetWalletHoldingsFunc(ctx, addresses[0])
+	}
+
+	exposure, err := NewTokenExposure(nil, []string{testutil.USDCAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewPortfolioService(portfolioRepo, nil, zap.NewNop()).WithExposure(exposure)
+
+	portfolio, err := service.GetPortfolio(ctx, wallet)
+	if err != nil || len(portfolio.Data.Holdings) != 1 || portfolio.Data.Holdings[0].TokenAddress != testutil.USDTAddress || portfolio.Data.Summary.TotalTokens != 1 {
+		t.Errorf("expected only the exposed holding, got %+v (%v)", portfolio, err)
+	}
+	if holding, err := service.GetPortfolioByToken(ctx, wallet, testutil.USDCAddress); err != nil || holding != nil {
+		t.Errorf("expected no holding of the hidden token, got %+v (%v)", holding, err)
+	}
+	tokens, err := service.GetWalletTokens(ctx, wallet)
+	if err != nil || len(tokens.Data.Tokens) != 1 || tokens.Data.Tokens[0].TokenAddress != testutil.USDTAddress {
+		t.Errorf("expected only the exposed token's activity, got %+v (%v)", tokens, err)
+	}
+	if portfolio, err := service.GetPortfolio(admin, wallet); err != nil || len(portfolio.Data.Holdings) != 2 {
+		t.Errorf("expected admins to see both holdings, got %+v (%v)", portfolio, err)
+	}
+
+	entityRepo := testutil.NewMockEntityRepository()
+	entity := &entities.Entity{Name: "exchange", Addresses: []string{wallet}}
+	if err := entityRepo.Create(ctx, entity); err != nil {
+		t.Fatal(err)
+	}
+	entityService := NewEntityService(entityRepo, portfolioRepo, nil, zap.NewNop()).WithExposure(exposure)
+	if holdings, err := entityService.GetEntityPortfolio(ctx, entity.ID); err != nil || len(holdings.Data.Holdings) != 1 || holdings.Data.Holdings[0].TokenAddress != testutil.USDTAddress {
+		t.Errorf("expected only the exposed entity holding, got %+v (%v)", holdings, err)
+	}
+}
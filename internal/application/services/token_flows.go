@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/pkg/units"
+)
+
+// WithEntities lets GetTokenFlows report the flows of labeled entities
+func (s *StatsService) WithEntities(repo repositories.EntityRepository) *StatsService {
+	s.entities = repo
+	return s
+}
+
+// EntityFlowDTO is one entity's inflow and outflow of a token
+type EntityFlowDTO struct {
+	EntityID           int64  `json:"entity_id"`
+	Name               string `json:"name"`
+	TransfersIn        int64  `json:"transfers_in"`
+	TransfersOut       int64  `json:"transfers_out"`
+	VolumeIn           string `json:"volume_in"` // Raw amount
+	VolumeInFormatted  string `json:"volume_in_formatted"`
+	VolumeOut          string `json:"volume_out"`
+	VolumeOutFormatted string `json:"volume_out_formatted"`
+	NetFlow            string `json:"net_flow"` // VolumeIn - VolumeOut, negative for net outflow
+	NetFlowFormatted   string `json:"net_flow_formatted"`
+}
+
+// TokenFlowsDTO summarizes a token's flows into and out of the entities of a category
+type TokenFlowsDTO struct {
+	TokenAddress       string          `json:"token_address"`
+	Category           string          `json:"category"`
+	From               string          `json:"from"`
+	To                 string          `json:"to"`
+	TransfersIn        int64           `json:"transfers_in"`
+	TransfersOut       int64           `json:"transfers_out"`
+	VolumeIn           string          `json:"volume_in"`
+	VolumeInFormatted  string          `json:"volume_in_formatted"`
+	VolumeOut          string          `json:"volume_out"`
+	VolumeOutFormatted string          `json:"volume_out_formatted"`
+	NetFlow            string          `json:"net_flow"`
+	NetFlowFormatted   string          `json:"net_flow_formatted"`
+	Entities           []EntityFlowDTO `json:"entities"`
+}
+
+// TokenFlowsResponse wraps token flows for API response
+type TokenFlowsResponse struct {
+	Data TokenFlowsDTO `json:"data"`
+}
+
+// GetTokenFlows returns the transfers and volume of a token into and out of each entity of a
+// category in [from, to), both rounded down to the minute, with the category totals. Transfers
+// between addresses of one entity are not counted. Returns nil if the token does not exist.
+func (s *StatsService) GetTokenFlows(ctx context.Context, tokenAddress, category string, from, to time.Time) (*TokenFlowsResponse, error) {
+	if s.entities == nil {
+		return nil, fmt.Errorf("entities not configured")
+	}
+
+	tokenAddress = strings.ToLower(tokenAddress)
+	from = from.UTC().Truncate(statsRangeRounding)
+	to = to.UTC().Truncate(statsRangeRounding)
+
+	cacheKey := fmt.Sprintf("token_flows:%s:%s:%d:%d", tokenAddress, category, from.Unix(), to.Unix())
+	var cached TokenFlowsResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	token, err := s.tokenRepo.GetByAddress(ctx, tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token: %w", err)
+	}
+	if token == nil {
+		return nil, nil // Token not found
+	}
+
+	flows, err := s.entities.GetCategoryFlows(ctx, tokenAddress, category, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category flows: %w", err)
+	}
+
+	data := TokenFlowsDTO{
+		TokenAddress: tokenAddress,
+		Category:     category,
+		From:         from.Format(time.RFC3339),
+		To:           to.Format(time.RFC3339),
+		Entities:     make([]EntityFlowDTO, 0, len(flows)),
+	}
+	volumeIn, volumeOut := new(big.Int), new(big.Int)
+	for _, flow := range flows {
+		net := netFlow(flow.VolumeIn, flow.VolumeOut)
+		data.Entities = append(data.Entities, EntityFlowDTO{
+			EntityID:           flow.EntityID,
+			Name:               flow.Name,
+			TransfersIn:        flow.TransfersIn,
+			TransfersOut:       flow.TransfersOut,
+			VolumeIn:           flow.VolumeIn,
+			VolumeInFormatted:  units.Format(flow.VolumeIn, token.Decimals),
+			VolumeOut:          flow.VolumeOut,
+			VolumeOutFormatted: units.Format(flow.VolumeOut, token.Decimals),
+			NetFlow:            net,
+			NetFlowFormatted:   units.Format(net, token.Decimals),
+		})
+		data.TransfersIn += flow.TransfersIn
+		data.TransfersOut += flow.TransfersOut
+		addVolume(volumeIn, flow.VolumeIn)
+		addVolume(volumeOut, flow.VolumeOut)
+	}
+	data.VolumeIn = volumeIn.String()
+	data.VolumeInFormatted = units.Format(data.VolumeIn, token.Decimals)
+	data.VolumeOut = volumeOut.String()
+	data.VolumeOutFormatted = units.Format(data.VolumeOut, token.Decimals)
+	data.NetFlow = netFlow(data.VolumeIn, data.VolumeOut)
+	data.NetFlowFormatted = units.Format(data.NetFlow, token.Decimals)
+
+	response := &TokenFlowsResponse{Data: data}
+
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, s.cacheTTL); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// netFlow returns in - out as a raw decimal amount
+func netFlow(in, out string) string {
+	total := new(big.Int)
+	addVolume(total, in)
+	if v, ok := new(big.Int).SetString(out, 10); ok {
+		total.Sub(total, v)
+	}
+	return total.String()
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// MetadataReader reads a token's current on-chain metadata and the chain head block it was read
+// at; *ethereum.MetadataFetcher implements it
+type MetadataReader interface {
+	ReadMetadata(ctx context.Context, tokenAddress string) (*ethereum.TokenMetadata, uint64, error)
+}
+
+var tokenMetadataChanges = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "indexer_token_metadata_changes_total",
+		Help: "On-chain token metadata changes detected by the metadata refresh, by token",
+	},
+	[]string{"token"},
+)
+
+// WithMetadataReader enables RefreshMetadata
+func (s *TokenService) WithMetadataReader(reader MetadataReader) *TokenService {
+	s.metadata = reader
+	return s
+}
+
+// TokenMetadataChangeDTO is a recorded change of a token's on-chain metadata
+type TokenMetadataChangeDTO struct {
+	PreviousName     string `json:"previous_name"`
+	PreviousSymbol   string `json:"previous_symbol"`
+	PreviousDecimals int    `json:"previous_decimals"`
+	Name             string `json:"name"`
+	Symbol           string `json:"symbol"`
+	Decimals         int    `json:"decimals"`
+	BlockNumber      int64  `json:"block_number"` // Chain head when the change was detected
+	DetectedAt       string `json:"detected_at"`
+}
+
+// TokenMetadataHistoryResponse is the API response for token metadata history queries
+type TokenMetadataHistoryResponse struct {
+	Data []TokenMetadataChangeDTO `json:"data"` // Newest first
+}
+
+// GetMetadataHistory returns the on-chain metadata changes recorded for a token, newest first,
+// or nil when the token does not exist
+func (s *TokenService) GetMetadataHistory(ctx context.Context, address string) (*TokenMetadataHistoryResponse, error) {
+	address = strings.ToLower(address)
+
+	token, err := s.tokenRepo.GetByAddress(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if token == nil {
+		return nil, nil
+	}
+
+	changes, err := s.tokenRepo.GetMetadataHistory(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata history: %w", err)
+	}
+
+	response := &TokenMetadataHistoryResponse{Data: make([]TokenMetadataChangeDTO, len(changes))}
+	for i, c := range changes {
+		response.Data[i] = TokenMetadataChangeDTO{
+			PreviousName:     c.PreviousName,
+			PreviousSymbol:   c.PreviousSymbol,
+			PreviousDecimals: c.PreviousDecimals,
+			Name:             c.Name,
+			Symbol:           c.Symbol,
+			Decimals:         c.Decimals,
+			BlockNumber:      c.BlockNumber,
+			DetectedAt:       c.DetectedAt.UTC().Format(time.RFC3339),
+		}
+	}
+	return response, nil
+}
+
+// RefreshMetadata re-reads each token's name, symbol and decimals from the chain and records any
+// change, so amounts are formatted with the decimals a token has after a proxy upgrade. Tokens
+// whose metadata was overridden are skipped. Failures are logged and do not stop the remaining
+// tokens.
+func (s *TokenService) RefreshMetadata(ctx context.Context, tokenAddresses []string) {
+	for _, address := range tokenAddresses {
+		address = strings.ToLower(address)
+		if err := s.refreshMetadata(ctx, address); err != nil {
+			s.logger.Warn("Failed to refresh token metadata", zap.String("token", address), zap.Error(err))
+		}
+	}
+}
+
+// refreshMetadata compares one token's stored metadata with the chain and records a change
+func (s *TokenService) refreshMetadata(ctx context.Context, address string) error {
+	token, err := s.tokenRepo.GetByAddress(ctx, address)
+	if err != nil {
+		return fmt.Errorf("failed to get token: %w", err)
+	}
+	if token == nil || token.MetadataSource == entities.MetadataOverride {
+		return nil
+	}
+
+	current, block, err := s.metadata.ReadMetadata(ctx, address)
+	if err != nil {
+		return err
+	}
+	if current.Name == token.Name && current.Symbol == token.Symbol && int(current.Decimals) == token.Decimals {
+		return nil
+	}
+
+	change := &entities.TokenMetadataChange{
+		TokenAddress:     address,
+		PreviousName:     token.Name,
+		PreviousSymbol:   token.Symbol,
+		PreviousDecimals: token.Decimals,
+		Name:             current.Name,
+		Symbol:           current.Symbol,
+		Decimals:         int(current.Decimals),
+		BlockNumber:      int64(block),
+	}
+	recorded, err := s.tokenRepo.RecordMetadataChange(ctx, change)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		return nil // Overridden since it was read
+	}
+
+	tokenMetadataChanges.WithLabelValues(address).Inc()
+	s.logger.Warn("Token metadata changed on-chain",
+		zap.String("token", address),
+		zap.Int64("block", change.BlockNumber),
+		zap.String("previous_name", change.PreviousName),
+		zap.String("previous_symbol", change.PreviousSymbol),
+		zap.Int("previous_decimals", change.PreviousDecimals),
+		zap.String("name", change.Name),
+		zap.String("symbol", change.Symbol),
+		zap.Int("decimals", change.Decimals),
+	)
+
+	s.invalidateToken(ctx, address)
+	if change.Decimals != change.PreviousDecimals && s.cache != nil {
+		// Formatted amounts of the token may be cached under any key naming it
+		if err := s.cache.DeletePattern(ctx, "*"+address+"*"); err != nil {
+			s.logger.Warn("Failed to invalidate cache", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// RunMetadataRefreshLoop refreshes token metadata immediately and then every interval until ctx is cancelled
+func (s *TokenService) RunMetadataRefreshLoop(ctx context.Context, tokenAddresses []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.RefreshMetadata(ctx, tokenAddresses)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -12,11 +13,24 @@ import (
 	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
 )
 
+// MaxTokenBatchSize caps how many tokens one batch lookup can request
+const MaxTokenBatchSize = 100
+
+// ErrInvalidTokenMetadata is wrapped by validation errors from OverrideMetadata
+var ErrInvalidTokenMetadata = errors.New("invalid token metadata")
+
+// maxTokenMetadataLength caps the length of an overridden name or symbol
+const maxTokenMetadataLength = 128
+
 // TokenService provides business logic for token queries
 type TokenService struct {
-	tokenRepo repositories.TokenRepository
-	cache     *cache.RedisCache
-	logger    *zap.Logger
+	tokenRepo  repositories.TokenRepository
+	tenantRepo repositories.TenantRepository
+	exposure   *TokenExposure
+	metadata   MetadataReader
+	breaker    *Breaker
+	cache      *cache.RedisCache
+	logger     *zap.Logger
 }
 
 // NewTokenService creates a new token service
@@ -32,6 +46,25 @@ func NewTokenService(
 	}
 }
 
+// WithBreaker retries token reads through b and serves stale cached responses while the database is unavailable
+func (s *TokenService) WithBreaker(b *Breaker) *TokenService {
+	s.breaker = b
+	return s
+}
+
+// WithTenants lists only a tenant's tokens for requests scoped to one
+func (s *TokenService) WithTenants(tenantRepo repositories.TenantRepository) *TokenService {
+	s.tenantRepo = tenantRepo
+	return s
+}
+
+// WithExposure serves only the tokens e exposes to the request, answering as if the others were
+// not indexed
+func (s *TokenService) WithExposure(e *TokenExposure) *TokenService {
+	s.exposure = e
+	return s
+}
+
 // TokenDTO is the API representation of a token
 type TokenDTO struct {
 	Address               string `json:"address"`
@@ -41,6 +74,9 @@ type TokenDTO struct {
 	TotalIndexedTransfers int64  `json:"total_indexed_transfers"`
 	FirstSeenBlock        *int64 `json:"first_seen_block"`
 	LastSeenBlock         *int64 `json:"last_seen_block"`
+	Active                bool   `json:"active"`
+	DeactivatedAt         string `json:"deactivated_at,omitempty"`
+	MetadataSource        string `json:"metadata_source"`
 	CreatedAt             string `json:"created_at"`
 	UpdatedAt             string `json:"updated_at"`
 }
@@ -56,6 +92,12 @@ type TokenResponse struct {
 	Data TokenDTO `json:"data"`
 }
 
+// TokenBatchResponse is the API response for batch token lookups
+type TokenBatchResponse struct {
+	Data     []TokenDTO `json:"data"`      // In request order
+	NotFound []string   `json:"not_found"` // Requested addresses that are not indexed
+}
+
 // PaginationResponse contains pagination metadata
 type PaginationResponse struct {
 	Total  int64 `json:"total"`
@@ -63,10 +105,18 @@ type PaginationResponse struct {
 	Offset int   `json:"offset"`
 }
 
-// GetAllTokens retrieves all tokens with pagination and sorting
-func (s *TokenService) GetAllTokens(ctx context.Context, limit, offset int, sortBy, sortOrder string) (*TokenListResponse, error) {
-	// Generate cache key
-	cacheKey := fmt.Sprintf("tokens:list:%d:%d:%s:%s", limit, offset, sortBy, sortOrder)
+// GetAllTokens retrieves tokens with pagination and sorting; deactivated tokens are only
+// listed when includeInactive is set
+func (s *TokenService) GetAllTokens(ctx context.Context, limit, offset int, sortBy, sortOrder string, includeInactive bool) (*TokenListResponse, error) {
+	// Generate cache key; tenant listings are cached apart from the shared one, and listings
+	// narrowed to the exposed tokens apart from the complete ones admins see
+	cacheKey := fmt.Sprintf("tokens:list:%d:%d:%s:%s:%t", limit, offset, sortBy, sortOrder, includeInactive)
+	if tenant := s.scopedTenant(ctx); tenant != nil {
+		cacheKey = fmt.Sprintf("tokens:list:tenant:%d:%d:%d:%s:%s:%t", tenant.ID, limit, offset, sortBy, sortOrder, includeInactive)
+	}
+	if !IsAdmin(ctx) && s.exposure != nil {
+		cacheKey = strings.Replace(cacheKey, "tokens:list:", "tokens:list:exposed:", 1)
+	}
 
 	// Try cache first
 	var cached TokenListResponse
@@ -77,8 +127,23 @@ func (s *TokenService) GetAllTokens(ctx context.Context, limit, offset int, sort
 		}
 	}
 
+	return guardedLoad(ctx, s.breaker, s.cache, s.logger, "tokens.GetAllTokens", cacheKey, func(ctx context.Context) (*TokenListResponse, error) {
+		return s.loadAllTokens(ctx, limit, offset, sortBy, sortOrder, includeInactive, cacheKey)
+	})
+}
+
+// loadAllTokens reads a token list page from the database and caches it under cacheKey
+func (s *TokenService) loadAllTokens(ctx context.Context, limit, offset int, sortBy, sortOrder string, includeInactive bool, cacheKey string) (*TokenListResponse, error) {
 	// Query database
-	tokens, total, err := s.tokenRepo.GetAllPaginated(ctx, limit, offset, sortBy, sortOrder)
+	var tokens []*entities.Token
+	var total int64
+	var err error
+	scope := s.exposure.Scope(ctx)
+	if tenant := s.scopedTenant(ctx); tenant != nil {
+		tokens, total, err = s.tenantRepo.ListTokens(ctx, tenant.ID, limit, offset, sortBy, sortOrder, includeInactive, scope)
+	} else {
+		tokens, total, err = s.tokenRepo.GetAllPaginated(ctx, limit, offset, sortBy, sortOrder, includeInactive, scope)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tokens: %w", err)
 	}
@@ -108,9 +173,12 @@ func (s *TokenService) GetAllTokens(ctx context.Context, limit, offset int, sort
 	return response, nil
 }
 
-// GetByAddress retrieves a single token by address
+// GetByAddress retrieves a single token by address, or nil when it is not indexed or not exposed
 func (s *TokenService) GetByAddress(ctx context.Context, address string) (*TokenResponse, error) {
 	address = strings.ToLower(address)
+	if !s.exposure.Exposes(ctx, address) {
+		return nil, nil
+	}
 
 	// Generate cache key
 	cacheKey := fmt.Sprintf("tokens:%s", address)
@@ -124,6 +192,13 @@ func (s *TokenService) GetByAddress(ctx context.Context, address string) (*Token
 		}
 	}
 
+	return guardedLoad(ctx, s.breaker, s.cache, s.logger, "tokens.GetByAddress", cacheKey, func(ctx context.Context) (*TokenResponse, error) {
+		return s.loadByAddress(ctx, address, cacheKey)
+	})
+}
+
+// loadByAddress reads a token from the database and caches it under cacheKey
+func (s *TokenService) loadByAddress(ctx context.Context, address, cacheKey string) (*TokenResponse, error) {
 	// Query database
 	token, err := s.tokenRepo.GetByAddress(ctx, address)
 	if err != nil {
@@ -147,8 +222,179 @@ func (s *TokenService) GetByAddress(ctx context.Context, address string) (*Token
 	return response, nil
 }
 
+// SetActive activates or deactivates a token and returns it, or nil when the token does not
+// exist. Deactivated tokens keep their transfers and checkpoint; the indexer skips them from
+// its next poll and resumes from the checkpoint once they are activated again.
+func (s *TokenService) SetActive(ctx context.Context, address string, active bool) (*TokenResponse, error) {
+	address = strings.ToLower(address)
+
+	found, err := s.tokenRepo.SetActive(ctx, address, active)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update token: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	s.logger.Info("Token activation changed",
+		zap.String("token", address),
+		zap.Bool("active", active),
+	)
+
+	s.invalidateToken(ctx, address)
+
+	token, err := s.tokenRepo.GetByAddress(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if token == nil {
+		return nil, nil
+	}
+	return &TokenResponse{Data: tokenToDTO(token)}, nil
+}
+
+// OverrideMetadata replaces a token's name, symbol or decimals with operator-supplied values,
+// which later re-registrations keep. actor identifies the caller in the audit log. It returns
+// nil when the token does not exist; validation failures wrap ErrInvalidTokenMetadata.
+func (s *TokenService) OverrideMetadata(ctx context.Context, address string, override entities.TokenMetadataOverride, actor string) (*TokenResponse, error) {
+	address = strings.ToLower(address)
+
+	if err := validateMetadataOverride(override); err != nil {
+		return nil, err
+	}
+
+	previous, err := s.tokenRepo.GetByAddress(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if previous == nil {
+		return nil, nil
+	}
+	found, err := s.tokenRepo.OverrideMetadata(ctx, address, override)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update token: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	s.invalidateToken(ctx, address)
+
+	token, err := s.tokenRepo.GetByAddress(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	if token == nil {
+		return nil, nil
+	}
+
+	s.logger.Info("Token metadata overridden",
+		zap.Bool("audit", true),
+		zap.String("actor", actor),
+		zap.String("token", address),
+		zap.String("previous_source", previous.MetadataSource),
+		zap.String("previous_name", previous.Name),
+		zap.String("previous_symbol", previous.Symbol),
+		zap.Int("previous_decimals", previous.Decimals),
+		zap.String("name", token.Name),
+		zap.String("symbol", token.Symbol),
+		zap.Int("decimals", token.Decimals),
+	)
+
+	return &TokenResponse{Data: tokenToDTO(token)}, nil
+}
+
+// validateMetadataOverride checks that an override sets at least one field to a usable value
+func validateMetadataOverride(override entities.TokenMetadataOverride) error {
+	if override.Name == nil && override.Symbol == nil && override.Decimals == nil {
+		return fmt.Errorf("%w: at least one of name, symbol or decimals is required", ErrInvalidTokenMetadata)
+	}
+	if override.Name != nil && (strings.TrimSpace(*override.Name) == "" || len(*override.Name) > maxTokenMetadataLength) {
+		return fmt.Errorf("%w: name must be 1 to %d characters", ErrInvalidTokenMetadata, maxTokenMetadataLength)
+	}
+	if override.Symbol != nil && (strings.TrimSpace(*override.Symbol) == "" || len(*override.Symbol) > maxTokenMetadataLength) {
+		return fmt.Errorf("%w: symbol must be 1 to %d characters", ErrInvalidTokenMetadata, maxTokenMetadataLength)
+	}
+	if override.Decimals != nil && (*override.Decimals < 0 || *override.Decimals > 255) {
+		return fmt.Errorf("%w: decimals must be between 0 and 255", ErrInvalidTokenMetadata)
+	}
+	return nil
+}
+
+// scopedTenant returns the tenant the request is scoped to, or nil when listings are not scoped
+func (s *TokenService) scopedTenant(ctx context.Context) *entities.Tenant {
+	if s.tenantRepo == nil {
+		return nil
+	}
+	return TenantFromContext(ctx)
+}
+
+// invalidateToken drops cached copies of a token so listings reflect a change immediately
+func (s *TokenService) invalidateToken(ctx context.Context, address string) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Delete(ctx, fmt.Sprintf("tokens:%s", address)); err != nil {
+		s.logger.Warn("Failed to invalidate cache", zap.Error(err))
+	}
+	if err := s.cache.DeletePattern(ctx, "tokens:list:*"); err != nil {
+		s.logger.Warn("Failed to invalidate cache", zap.Error(err))
+	}
+}
+
+// GetByAddresses retrieves up to MaxTokenBatchSize tokens in one query.
+// Addresses are normalized and deduplicated; results keep the order of the request. Tokens
+// that are not exposed are reported as not found.
+func (s *TokenService) GetByAddresses(ctx context.Context, addresses []string) (*TokenBatchResponse, error) {
+	normalized := make([]string, 0, len(addresses))
+	seen := make(map[string]bool, len(addresses))
+	for _, addr := range addresses {
+		addr = strings.ToLower(addr)
+		if !seen[addr] {
+			seen[addr] = true
+			normalized = append(normalized, addr)
+		}
+	}
+	if len(normalized) > MaxTokenBatchSize {
+		return nil, fmt.Errorf("at most %d addresses can be requested at once", MaxTokenBatchSize)
+	}
+
+	tokens, err := s.tokenRepo.GetByAddresses(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens: %w", err)
+	}
+
+	byAddress := make(map[string]*entities.Token, len(tokens))
+	for _, t := range tokens {
+		byAddress[t.Address] = t
+	}
+
+	response := &TokenBatchResponse{
+		Data:     make([]TokenDTO, 0, len(tokens)),
+		NotFound: []string{},
+	}
+	for _, addr := range normalized {
+		if t, ok := byAddress[addr]; ok && s.exposure.Exposes(ctx, addr) {
+			response.Data = append(response.Data, tokenToDTO(t))
+		} else {
+			response.NotFound = append(response.NotFound, addr)
+		}
+	}
+
+	return response, nil
+}
+
 // tokenToDTO converts a token entity to a DTO
 func tokenToDTO(t *entities.Token) TokenDTO {
+	var deactivatedAt string
+	if !t.Active && t.DeactivatedAt != nil {
+		deactivatedAt = t.DeactivatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	metadataSource := t.MetadataSource
+	if metadataSource == "" {
+		metadataSource = entities.MetadataOnchain
+	}
+
 	return TokenDTO{
 		Address:               t.Address,
 		Name:                  t.Name,
@@ -157,6 +403,9 @@ func tokenToDTO(t *entities.Token) TokenDTO {
 		TotalIndexedTransfers: t.TotalIndexedTransfers,
 		FirstSeenBlock:        t.FirstSeenBlock,
 		LastSeenBlock:         t.LastSeenBlock,
+		Active:                t.Active,
+		DeactivatedAt:         deactivatedAt,
+		MetadataSource:        metadataSource,
 		CreatedAt:             t.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:             t.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
@@ -3,12 +3,15 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
 	"github.com/bimakw/chain-indexer/internal/testutil"
 )
 
@@ -42,7 +45,7 @@ func TestTokenService_GetAllTokens_Success(t *testing.T) {
 		testutil.TokenWithTotalTransfers(500),
 	))
 
-	response, err := service.GetAllTokens(ctx, 100, 0, "total_indexed_transfers", "desc")
+	response, err := service.GetAllTokens(ctx, 100, 0, "total_indexed_transfers", "desc", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -75,7 +78,7 @@ func TestTokenService_GetAllTokens_Pagination(t *testing.T) {
 	}
 
 	// First page
-	response, err := service.GetAllTokens(ctx, 2, 0, "symbol", "asc")
+	response, err := service.GetAllTokens(ctx, 2, 0, "symbol", "asc", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -88,7 +91,7 @@ func TestTokenService_GetAllTokens_Pagination(t *testing.T) {
 	}
 
 	// Second page
-	response, err = service.GetAllTokens(ctx, 2, 2, "symbol", "asc")
+	response, err = service.GetAllTokens(ctx, 2, 2, "symbol", "asc", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -98,7 +101,7 @@ func TestTokenService_GetAllTokens_Pagination(t *testing.T) {
 	}
 
 	// Last page
-	response, err = service.GetAllTokens(ctx, 2, 4, "symbol", "asc")
+	response, err = service.GetAllTokens(ctx, 2, 4, "symbol", "asc", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -112,7 +115,7 @@ func TestTokenService_GetAllTokens_EmptyResult(t *testing.T) {
 	service, _ := setupTokenServiceTest()
 	ctx := context.Background()
 
-	response, err := service.GetAllTokens(ctx, 100, 0, "symbol", "asc")
+	response, err := service.GetAllTokens(ctx, 100, 0, "symbol", "asc", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -129,11 +132,11 @@ func TestTokenService_GetAllTokens_RepositoryError(t *testing.T) {
 	service, tokenRepo := setupTokenServiceTest()
 	ctx := context.Background()
 
-	tokenRepo.GetAllPaginatedFunc = func(ctx context.Context, limit, offset int, sortBy, sortOrder string) ([]*entities.Token, int64, error) {
+	tokenRepo.GetAllPaginatedFunc = func(ctx context.Context, limit, offset int, sortBy, sortOrder string, includeInactive bool, scope repositories.TokenScope) ([]*entities.Token, int64, error) {
 		return nil, 0, errors.New("database connection failed")
 	}
 
-	_, err := service.GetAllTokens(ctx, 100, 0, "symbol", "asc")
+	_, err := service.GetAllTokens(ctx, 100, 0, "symbol", "asc", false)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -142,6 +145,33 @@ func TestTokenService_GetAllTokens_RepositoryError(t *testing.T) {
 	}
 }
 
+func TestTokenService_GetAllTokens_IncludeInactive(t *testing.T) {
+	service, tokenRepo := setupTokenServiceTest()
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	tokenRepo.AddToken(testutil.CreateTestToken(
+		testutil.TokenWithAddress(testutil.USDCAddress),
+		testutil.TokenInactive(),
+	))
+
+	response, err := service.GetAllTokens(ctx, 100, 0, "symbol", "asc", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Pagination.Total != 1 || response.Data[0].Address != testutil.USDTAddress {
+		t.Errorf("expected only the active token, got %+v", response.Data)
+	}
+
+	response, err = service.GetAllTokens(ctx, 100, 0, "symbol", "asc", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Pagination.Total != 2 {
+		t.Errorf("expected 2 tokens with include_inactive, got %d", response.Pagination.Total)
+	}
+}
+
 func TestTokenService_GetByAddress_Success(t *testing.T) {
 	service, tokenRepo := setupTokenServiceTest()
 	ctx := context.Background()
@@ -233,6 +263,213 @@ func TestTokenService_GetByAddress_RepositoryError(t *testing.T) {
 	}
 }
 
+func TestTokenService_SetActive(t *testing.T) {
+	service, tokenRepo := setupTokenServiceTest()
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	response, err := service.SetActive(ctx, testutil.USDTAddress, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response == nil {
+		t.Fatal("expected a response")
+	}
+	if response.Data.Active || response.Data.DeactivatedAt == "" {
+		t.Errorf("expected an inactive token with deactivated_at, got %+v", response.Data)
+	}
+
+	list, err := service.GetAllTokens(ctx, 100, 0, "symbol", "asc", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Pagination.Total != 0 {
+		t.Errorf("expected the deactivated token to be hidden, got %d tokens", list.Pagination.Total)
+	}
+
+	response, err = service.SetActive(ctx, testutil.USDTAddress, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !response.Data.Active || response.Data.DeactivatedAt != "" {
+		t.Errorf("expected an active token, got %+v", response.Data)
+	}
+}
+
+func TestTokenService_SetActive_NotFound(t *testing.T) {
+	service, _ := setupTokenServiceTest()
+
+	response, err := service.SetActive(context.Background(), testutil.USDTAddress, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response != nil {
+		t.Error("expected nil response for non-existent token")
+	}
+}
+
+func TestTokenService_SetActive_RepositoryError(t *testing.T) {
+	service, tokenRepo := setupTokenServiceTest()
+
+	tokenRepo.SetActiveFunc = func(ctx context.Context, address string, active bool) (bool, error) {
+		return false, errors.New("database connection failed")
+	}
+
+	if _, err := service.SetActive(context.Background(), testutil.USDTAddress, false); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestTokenService_OverrideMetadata(t *testing.T) {
+	service, tokenRepo := setupTokenServiceTest()
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress), testutil.TokenWithSymbol("???")))
+
+	symbol := "USDT"
+	response, err := service.OverrideMetadata(ctx, testutil.USDTAddress, entities.TokenMetadataOverride{Symbol: &symbol}, "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response == nil {
+		t.Fatal("expected a response")
+	}
+	if response.Data.Symbol != "USDT" || response.Data.MetadataSource != entities.MetadataOverride {
+		t.Errorf("expected an overridden symbol, got %+v", response.Data)
+	}
+
+	// Re-registering the token with on-chain metadata keeps the override
+	if err := tokenRepo.Upsert(ctx, testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress), testutil.TokenWithSymbol("???"))); err != nil {
+		t.Fatal(err)
+	}
+	got, err := service.GetByAddress(ctx, testutil.USDTAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Data.Symbol != "USDT" {
+		t.Errorf("expected the override to be kept, got %q", got.Data.Symbol)
+	}
+}
+
+func TestTokenService_OverrideMetadata_Invalid(t *testing.T) {
+	service, tokenRepo := setupTokenServiceTest()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	blank, negative, large := " ", -1, 256
+	tests := []struct {
+		name     string
+		override entities.TokenMetadataOverride
+	}{
+		{"empty", entities.TokenMetadataOverride{}},
+		{"blank name", entities.TokenMetadataOverride{Name: &blank}},
+		{"blank symbol", entities.TokenMetadataOverride{Symbol: &blank}},
+		{"negative decimals", entities.TokenMetadataOverride{Decimals: &negative}},
+		{"large decimals", entities.TokenMetadataOverride{Decimals: &large}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.OverrideMetadata(context.Background(), testutil.USDTAddress, tt.override, "test")
+			if !errors.Is(err, ErrInvalidTokenMetadata) {
+				t.Errorf("expected ErrInvalidTokenMetadata, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTokenService_OverrideMetadata_NotFound(t *testing.T) {
+	service, _ := setupTokenServiceTest()
+
+	symbol := "USDT"
+	response, err := service.OverrideMetadata(context.Background(), testutil.USDTAddress, entities.TokenMetadataOverride{Symbol: &symbol}, "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response != nil {
+		t.Error("expected nil response for non-existent token")
+	}
+}
+
+func TestTokenService_GetAllTokens_Tenant(t *testing.T) {
+	service, tokenRepo := setupTokenServiceTest()
+	tenantRepo := testutil.NewMockTenantRepository(tokenRepo)
+	service.WithTenants(tenantRepo)
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDCAddress)))
+	tenant := &entities.Tenant{Name: "acme", Tokens: []string{testutil.USDCAddress}}
+	if err := tenantRepo.Create(ctx, tenant); err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := service.GetAllTokens(ContextWithTenant(ctx, tenant), 100, 0, "symbol", "asc", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Pagination.Total != 1 || response.Data[0].Address != testutil.USDCAddress {
+		t.Errorf("expected only the tenant's token, got %+v", response.Data)
+	}
+
+	// Requests without a tenant list every token
+	response, err = service.GetAllTokens(ctx, 100, 0, "symbol", "asc", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Pagination.Total != 2 {
+		t.Errorf("expected every token, got %d", response.Pagination.Total)
+	}
+}
+
+func TestTokenService_GetByAddresses(t *testing.T) {
+	service, tokenRepo := setupTokenServiceTest()
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress), testutil.TokenWithSymbol("USDT")))
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDCAddress), testutil.TokenWithSymbol("USDC")))
+
+	response, err := service.GetByAddresses(ctx, []string{
+		"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", // USDC, checksummed
+		testutil.AliceAddress,
+		testutil.USDTAddress,
+		testutil.USDCAddress, // duplicate
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(response.Data) != 2 || response.Data[0].Symbol != "USDC" || response.Data[1].Symbol != "USDT" {
+		t.Errorf("expected USDC then USDT in request order, got %+v", response.Data)
+	}
+	if len(response.NotFound) != 1 || response.NotFound[0] != testutil.AliceAddress {
+		t.Errorf("expected Alice's address as not found, got %v", response.NotFound)
+	}
+
+	calls := 0
+	for _, call := range tokenRepo.Calls {
+		if call.Method == "GetByAddresses" {
+			calls++
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected a single repository query, got %d", calls)
+	}
+}
+
+func TestTokenService_GetByAddresses_TooMany(t *testing.T) {
+	service, _ := setupTokenServiceTest()
+
+	addresses := make([]string, MaxTokenBatchSize+1)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("0x%040x", i)
+	}
+
+	if _, err := service.GetByAddresses(context.Background(), addresses); err == nil {
+		t.Error("expected error for too many addresses")
+	}
+}
+
 func TestTokenDTO_Formatting(t *testing.T) {
 	service, tokenRepo := setupTokenServiceTest()
 	ctx := context.Background()
@@ -320,3 +557,48 @@ func TestTokenDTO_NilBlocks(t *testing.T) {
 		t.Errorf("expected nil LastSeenBlock, got %d", *dto.LastSeenBlock)
 	}
 }
+
+func TestTokenService_RefreshMetadata(t *testing.T) {
+	service, tokenRepo := setupTokenServiceTest()
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDCAddress)))
+	usdc, _ := tokenRepo.GetByAddress(ctx, testutil.USDCAddress)
+	usdc.MetadataSource = entities.MetadataOverride
+
+	reader := testutil.NewMockMetadataReader(map[string]*ethereum.TokenMetadata{
+		testutil.USDTAddress: {Name: "Tether USD", Symbol: "USDT", Decimals: 18},
+		testutil.USDCAddress: {Name: "USD Coin", Symbol: "USDC", Decimals: 18},
+	}, 19000000)
+	service.WithMetadataReader(reader)
+
+	// Unknown tokens and read failures are skipped
+	service.RefreshMetadata(ctx, []string{testutil.USDTAddress, testutil.USDCAddress, testutil.AliceAddress})
+
+	history, err := service.GetMetadataHistory(ctx, testutil.USDTAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history.Data) != 1 || history.Data[0].PreviousDecimals != 6 || history.Data[0].Decimals != 18 || history.Data[0].BlockNumber != 19000000 {
+		t.Errorf("expected the decimals change recorded, got %+v", history.Data)
+	}
+	if token, _ := tokenRepo.GetByAddress(ctx, testutil.USDTAddress); token.Decimals != 18 {
+		t.Errorf("expected the token updated to 18 decimals, got %d", token.Decimals)
+	}
+
+	// Overridden metadata is not refreshed
+	if usdc.Decimals != 6 {
+		t.Errorf("expected the overridden token kept, got %+v", usdc)
+	}
+
+	// Unchanged metadata records nothing
+	service.RefreshMetadata(ctx, []string{testutil.USDTAddress})
+	if history, _ := service.GetMetadataHistory(ctx, testutil.USDTAddress); len(history.Data) != 1 {
+		t.Errorf("expected no new change, got %+v", history.Data)
+	}
+
+	if history, err := service.GetMetadataHistory(ctx, testutil.AliceAddress); err != nil || history != nil {
+		t.Errorf("expected nil history for an unknown token, got %+v (%v)", history, err)
+	}
+}
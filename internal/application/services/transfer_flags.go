@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// flagScanPageSize is how many transfers the analysis pass reads per query
+const flagScanPageSize = 5000
+
+// WashTradeHeuristics decides which transfers are flagged as wash trades: runs of at least
+// MinTransfers transfers between two addresses that alternate direction, each sent within Window
+// of the previous one and for a value within ValueTolerance of it
+type WashTradeHeuristics struct {
+	Window         time.Duration
+	ValueTolerance float64 // Largest difference between consecutive values, as a fraction of the larger
+	MinTransfers   int
+}
+
+// TransferFlagService runs the analysis pass that labels transfers with heuristic flags
+type TransferFlagService struct {
+	transferRepo repositories.TransferRepository
+	flagRepo     repositories.TransferFlagRepository
+	heuristics   WashTradeHeuristics
+	logger       *zap.Logger
+}
+
+// NewTransferFlagService creates a new transfer flag service
+func NewTransferFlagService(
+	transferRepo repositories.TransferRepository,
+	flagRepo repositories.TransferFlagRepository,
+	heuristics WashTradeHeuristics,
+	logger *zap.Logger,
+) *TransferFlagService {
+	return &TransferFlagService{
+		transferRepo: transferRepo,
+		flagRepo:     flagRepo,
+		heuristics:   heuristics,
+		logger:       logger,
+	}
+}
+
+// FlagWashTrades flags a token's wash trades among its transfers between from and to, returning
+// the number of transfers newly flagged. Runs crossing from are only seen in part, so callers
+// overlap consecutive calls by the heuristics' window.
+func (s *TransferFlagService) FlagWashTrades(ctx context.Context, tokenAddress string, from, to time.Time) (int64, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	// Transfers grouped by the two addresses they move between; mints, burns, self-transfers and
+	// zero values are never wash trades
+	type pair struct{ a, b string }
+	byPair := make(map[pair][]entities.Transfer)
+	filter := entities.TransferFilter{
+		TokenAddress: &tokenAddress,
+		FromTime:     &from,
+		ToTime:       &to,
+		ExcludeZero:  true,
+		ExcludeSelf:  true,
+		Limit:        flagScanPageSize,
+	}
+	for {
+		page, err := s.transferRepo.GetByFilter(ctx, filter)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read transfers: %w", err)
+		}
+		for _, t := range page {
+			if t.FromAddress == entities.ZeroAddress || t.ToAddress == entities.ZeroAddress {
+				continue
+			}
+			key := pair{t.FromAddress, t.ToAddress}
+			if key.b < key.a {
+				key = pair{key.b, key.a}
+			}
+			byPair[key] = append(byPair[key], t)
+		}
+		if len(page) < flagScanPageSize {
+			break
+		}
+		cursor := entities.CursorAt(page[len(page)-1])
+		filter.After = &cursor
+	}
+
+	var flags []entities.TransferFlag
+	for _, transfers := range byPair {
+		// The feed is newest first
+		sort.Slice(transfers, func(i, j int) bool {
+			return entities.CursorAt(transfers[j]).Precedes(transfers[i])
+		})
+
+		start := 0
+		for i := 1; i <= len(transfers); i++ {
+			if i < len(transfers) && s.pingPong(transfers[i-1], transfers[i]) {
+				continue
+			}
+			if i-start >= max(s.heuristics.MinTransfers, 2) {
+				for _, t := range transfers[start:i] {
+					flags = append(flags, entities.TransferFlag{
+						TxHash:       t.TxHash,
+						LogIndex:     t.LogIndex,
+						Flag:         entities.TransferFlagWashTrade,
+						TokenAddress: tokenAddress,
+					})
+				}
+			}
+			start = i
+		}
+	}
+
+	added, err := s.flagRepo.Add(ctx, flags)
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// pingPong reports whether next sends prev back: in the opposite direction, within the window and
+// for about the same value
+func (s *TransferFlagService) pingPong(prev, next entities.Transfer) bool {
+	if next.FromAddress != prev.ToAddress || next.ToAddress != prev.FromAddress {
+		return false
+	}
+	if next.BlockTimestamp.Sub(prev.BlockTimestamp) > s.heuristics.Window {
+		return false
+	}
+
+	a, okA := new(big.Int).SetString(prev.ValueString, 10)
+	b, okB := new(big.Int).SetString(next.ValueString, 10)
+	if !okA || !okB {
+		return false
+	}
+	larger := a
+	if b.Cmp(a) > 0 {
+		larger = b
+	}
+	if larger.Sign() <= 0 {
+		return false
+	}
+	diff := new(big.Rat).SetFrac(new(big.Int).Abs(new(big.Int).Sub(a, b)), larger)
+	tolerance := new(big.Rat)
+	tolerance.SetFloat64(s.heuristics.ValueTolerance)
+	return diff.Cmp(tolerance) <= 0
+}
+
+// RunWashTradeLoop flags wash trades immediately and then every interval until ctx is cancelled.
+// The first run looks back lookback; later runs start the window before where the last one
+// ended, so runs of transfers spanning two passes are still seen whole. A token whose pass fails
+// is retried from the same start.
+func (s *TransferFlagService) RunWashTradeLoop(ctx context.Context, tokenAddresses []string, interval, lookback time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	from := make(map[string]time.Time, len(tokenAddresses))
+	for _, tokenAddress := range tokenAddresses {
+		from[strings.ToLower(tokenAddress)] = time.Now().Add(-lookback)
+	}
+
+	for {
+		to := time.Now()
+		for tokenAddress, start := range from {
+			flagged, err := s.FlagWashTrades(ctx, tokenAddress, start, to)
+			if err != nil {
+				s.logger.Warn("Failed to flag wash trades", zap.String("token", tokenAddress), zap.Error(err))
+				continue
+			}
+			from[tokenAddress] = to.Add(-s.heuristics.Window)
+			if flagged > 0 {
+				s.logger.Info("Flagged wash trades", zap.String("token", tokenAddress), zap.Int64("transfers", flagged))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// flagsByTransfer looks up the flags of the given transfers, keyed by transaction hash and log index
+func flagsByTransfer(ctx context.Context, repo repositories.TransferFlagRepository, transfers []TransferDTO) (map[string][]string, error) {
+	seen := make(map[string]struct{})
+	var txHashes []string
+	for _, t := range transfers {
+		if _, ok := seen[t.TxHash]; !ok {
+			seen[t.TxHash] = struct{}{}
+			txHashes = append(txHashes, t.TxHash)
+		}
+	}
+
+	stored, err := repo.GetByTxHashes(ctx, txHashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer flags: %w", err)
+	}
+	flags := make(map[string][]string, len(stored))
+	for _, f := range stored {
+		key := transferKey(f.TxHash, f.LogIndex)
+		flags[key] = append(flags[key], f.Flag)
+	}
+	return flags, nil
+}
+
+func transferKey(txHash string, logIndex int) string {
+	return fmt.Sprintf("%s:%d", txHash, logIndex)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestTransferFlagService_FlagWashTrades(t *testing.T) {
+	transferRepo := testutil.NewMockTransferRepository()
+	flagRepo := testutil.NewMockTransferFlagRepository(transferRepo)
+	service := NewTransferFlagService(transferRepo, flagRepo, WashTradeHeuristics{
+		Window:         5 * time.Minute,
+		ValueTolerance: 0.05,
+		MinTransfers:   2,
+	}, zap.NewNop())
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	transfer := func(id int64, from, to string, value int64, after time.Duration) entities.Transfer {
+		return testutil.CreateTestTransfer(
+			testutil.WithID(id),
+			testutil.WithTxHash(big.NewInt(id).Text(16)),
+			testutil.WithBlockNumber(100+id),
+			testutil.WithBlockTimestamp(start.Add(after)),
+			testutil.WithFromAddress(from),
+			testutil.WithToAddress(to),
+			testutil.WithValue(big.NewInt(value)),
+		)
+	}
+	transferRepo.AddTransfers(
+		// Sent back and forth within the window for about the same value
+		transfer(1, testutil.AliceAddress, testutil.BobAddress, 100, 0),
+		transfer(2, testutil.BobAddress, testutil.AliceAddress, 98, 2*time.Minute),
+		transfer(3, testutil.AliceAddress, testutil.BobAddress, 100, 4*time.Minute),
+		// Another pair, and a reply for a different value
+		transfer(4, testutil.AliceAddress, testutil.CharlieAddr, 100, 5*time.Minute),
+		transfer(5, testutil.BobAddress, testutil.AliceAddress, 50, 6*time.Minute),
+		// Sent back after the window
+		transfer(6, testutil.AliceAddress, testutil.BobAddress, 100, 2*time.Hour),
+		transfer(7, testutil.BobAddress, testutil.AliceAddress, 100, 3*time.Hour),
+		// A mint is never a wash trade
+		transfer(8, entities.ZeroAddress, testutil.AliceAddress, 100, 3*time.Hour+time.Minute),
+	)
+
+	flagged, err := service.FlagWashTrades(ctx, testutil.USDTAddress, start, start.Add(4*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flagged != 3 {
+		t.Errorf("expected 3 flagged transfers, got %d", flagged)
+	}
+
+	hashes := []string{"1", "2", "3", "4", "5", "6", "7", "8"}
+	flags, err := flagRepo.GetByTxHashes(ctx, hashes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := make(map[string]string)
+	for _, f := range flags {
+		got[f.TxHash] = f.Flag
+	}
+	for _, hash := range hashes {
+		want := ""
+		if hash <= "3" {
+			want = entities.TransferFlagWashTrade
+		}
+		if got[hash] != want {
+			t.Errorf("transfer %s: expected flag %q, got %q", hash, want, got[hash])
+		}
+	}
+
+	// A second pass over the same window adds nothing
+	flagged, err = service.FlagWashTrades(ctx, testutil.USDTAddress, start, start.Add(4*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flagged != 0 {
+		t.Errorf("expected no new flags, got %d", flagged)
+	}
+
+	// Transfer DTOs carry their flags
+	transfers := NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, zap.NewNop()).
+		WithTransferFlags(flagRepo)
+	response, err := transfers.GetTransfers(ctx, entities.TransferFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, dto := range response.Transfers {
+		wantFlagged := dto.TxHash <= "3"
+		if gotFlagged := len(dto.Flags) == 1 && dto.Flags[0] == entities.TransferFlagWashTrade; gotFlagged != wantFlagged {
+			t.Errorf("transfer %s: expected flagged %v, got flags %v", dto.TxHash, wantFlagged, dto.Flags)
+		}
+	}
+}
+
+func TestStatsService_ExcludeFlagged(t *testing.T) {
+	service, transferRepo, _ := setupStatsServiceTest()
+	flagRepo := testutil.NewMockTransferFlagRepository(transferRepo)
+	service.WithTransferFlags(flagRepo)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(
+			testutil.WithID(1),
+			testutil.WithTxHash("0x01"),
+			testutil.WithBlockTimestamp(now.Add(-time.Hour)),
+			testutil.WithValue(big.NewInt(300)),
+		),
+		testutil.CreateTestTransfer(
+			testutil.WithID(2),
+			testutil.WithTxHash("0x02"),
+			testutil.WithBlockTimestamp(now.Add(-72*time.Hour)),
+			testutil.WithValue(big.NewInt(200)),
+		),
+	)
+	if _, err := flagRepo.Add(ctx, []entities.TransferFlag{
+		{TxHash: "0x01", Flag: entities.TransferFlagWashTrade, TokenAddress: testutil.USDTAddress},
+		{TxHash: "0x02", Flag: entities.TransferFlagWashTrade, TokenAddress: testutil.USDTAddress},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	response := &TokenStatsResponse{Data: TokenStats{
+		TokenAddress:   testutil.USDTAddress,
+		TotalTransfers: 10,
+		TotalVolume:    "1000",
+		Transfers24h:   4,
+		Volume24h:      "600",
+		Transfers7d:    8,
+		Volume7d:       "900",
+	}}
+	if err := service.ExcludeFlagged(ctx, response); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := response.Data
+	if !stats.ExcludesFlagged {
+		t.Error("expected the stats to be marked as excluding flagged transfers")
+	}
+	if stats.TotalTransfers != 8 || stats.TotalVolume != "500" {
+		t.Errorf("expected total 8 transfers and volume 500, got %d and %s", stats.TotalTransfers, stats.TotalVolume)
+	}
+	if stats.Transfers24h != 3 || stats.Volume24h != "300" {
+		t.Errorf("expected 3 transfers and volume 300 in 24h, got %d and %s", stats.Transfers24h, stats.Volume24h)
+	}
+	if stats.Transfers7d != 6 || stats.Volume7d != "400" {
+		t.Errorf("expected 6 transfers and volume 400 in 7d, got %d and %s", stats.Transfers7d, stats.Volume7d)
+	}
+}
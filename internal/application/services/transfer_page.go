@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorPagination describes a page of a cursor-paginated feed.
+// NextCursor is omitted on the last page.
+type CursorPagination struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// TransferPageResponse is the v2 transfer feed: transfers newest first, paged by cursor
+type TransferPageResponse struct {
+	Data       []TransferDTO    `json:"data"`
+	Pagination CursorPagination `json:"pagination"`
+}
+
+// EncodeTransferCursor returns the opaque cursor for a feed position
+func EncodeTransferCursor(c entities.TransferCursor) string {
+	raw := fmt.Sprintf("%d:%d:%d", c.BlockTimestamp.Unix(), c.BlockNumber, c.LogIndex)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeTransferCursor parses a cursor returned by EncodeTransferCursor
+func DecodeTransferCursor(cursor string) (*entities.TransferCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var unix, blockNumber int64
+	var logIndex int
+	if n, err := fmt.Sscanf(string(raw), "%d:%d:%d", &unix, &blockNumber, &logIndex); err != nil || n != 3 {
+		return nil, ErrInvalidCursor
+	}
+	if blockNumber < 0 || logIndex < 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &entities.TransferCursor{
+		BlockTimestamp: time.Unix(unix, 0).UTC(),
+		BlockNumber:    blockNumber,
+		LogIndex:       logIndex,
+	}, nil
+}
+
+// GetTransferPage retrieves one page of transfers older than filter.After, newest first.
+// Unlike GetTransfers it never counts the matching rows, so deep pages cost the same as the first.
+func (s *TransferService) GetTransferPage(ctx context.Context, filter entities.TransferFilter) (*TransferPageResponse, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = entities.DefaultTransferFilter().Limit
+	}
+	filter.Limit = limit + 1
+	filter.Offset = 0
+	s.exposure.scopeTransfers(ctx, &filter)
+
+	// Pages are not cached, but a stale copy is kept for when the database is unavailable
+	pageKey := "page:" + s.generateCacheKey(filter)
+	if filter.After != nil {
+		pageKey += ":" + EncodeTransferCursor(*filter.After)
+	}
+
+	response, err := guardedLoad(ctx, s.breaker, s.cache, s.logger, "transfers.GetTransferPage", pageKey, func(ctx context.Context) (*TransferPageResponse, error) {
+		return s.loadTransferPage(ctx, filter, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.screening != nil {
+		if err := s.screening.FlagTransfers(ctx, response.Data); err != nil {
+			s.logger.Warn("Failed to screen transfers", zap.Error(err))
+		}
+	}
+	s.addFlags(ctx, response.Data)
+	s.addNotes(ctx, response.Data)
+
+	return response, nil
+}
+
+// loadTransferPage reads limit transfers of filter from the database; filter.Limit is one more,
+// to learn whether another page follows
+func (s *TransferService) loadTransferPage(ctx context.Context, filter entities.TransferFilter, limit int) (*TransferPageResponse, error) {
+	transfers, err := s.transferRepo.GetByFilter(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfers: %w", err)
+	}
+
+	response := &TransferPageResponse{
+		Pagination: CursorPagination{Limit: limit},
+	}
+	if len(transfers) > limit {
+		transfers = transfers[:limit]
+		response.Pagination.HasMore = true
+		response.Pagination.NextCursor = EncodeTransferCursor(entities.CursorAt(transfers[limit-1]))
+	}
+	response.Data = s.toTransferDTOs(ctx, transfers)
+
+	return response, nil
+}
+
+// AddPageUSDValues fills value_usd on a transfer page, like AddUSDValues
+func (s *TransferService) AddPageUSDValues(ctx context.Context, page *TransferPageResponse, historical bool) {
+	if page == nil {
+		return
+	}
+	s.addUSDValues(ctx, page.Data, historical)
+}
+
+// ApplyPageValueFormat strips value fields from a transfer page, like ApplyValueFormat
+func ApplyPageValueFormat(page *TransferPageResponse, format string) {
+	if page == nil {
+		return
+	}
+	applyValueFormat(page.Data, format)
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestTransferCursor_RoundTrip(t *testing.T) {
+	cursor := entities.TransferCursor{
+		BlockTimestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		BlockNumber:    19000000,
+		LogIndex:       42,
+	}
+
+	decoded, err := DecodeTransferCursor(EncodeTransferCursor(cursor))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *decoded != cursor {
+		t.Errorf("expected %+v, got %+v", cursor, *decoded)
+	}
+
+	for _, invalid := range []string{"not base64!", "Zm9v", EncodeTransferCursor(entities.TransferCursor{LogIndex: -1})} {
+		if _, err := DecodeTransferCursor(invalid); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor for %q, got %v", invalid, err)
+		}
+	}
+}
+
+func TestTransferService_GetTransferPage(t *testing.T) {
+	ctx := context.Background()
+	transferRepo := testutil.NewMockTransferRepository()
+	base := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	// Newest first, as the repository returns them; the last two share a block
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x04"), testutil.WithBlockNumber(103), testutil.WithBlockTimestamp(base.Add(3*time.Minute))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x03"), testutil.WithBlockNumber(102), testutil.WithBlockTimestamp(base.Add(2*time.Minute))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithBlockNumber(101), testutil.WithBlockTimestamp(base), testutil.WithLogIndex(1)),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithBlockNumber(101), testutil.WithBlockTimestamp(base), testutil.WithLogIndex(0)),
+	)
+	service := NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, zap.NewNop())
+
+	var hashes []string
+	filter := entities.DefaultTransferFilter()
+	filter.Limit = 3
+	for page := 0; page < 3; page++ {
+		response, err := service.GetTransferPage(ctx, filter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, transfer := range response.Data {
+			hashes = append(hashes, transfer.TxHash)
+		}
+		if !response.Pagination.HasMore {
+			if response.Pagination.NextCursor != "" {
+				t.Errorf("expected no cursor on the last page, got %q", response.Pagination.NextCursor)
+			}
+			break
+		}
+
+		cursor, err := DecodeTransferCursor(response.Pagination.NextCursor)
+		if err != nil {
+			t.Fatalf("unexpected cursor error: %v", err)
+		}
+		filter.After = cursor
+	}
+
+	want := []string{"0x04", "0x03", "0x02", "0x01"}
+	if len(hashes) != len(want) {
+		t.Fatalf("expected %v, got %v", want, hashes)
+	}
+	for i := range want {
+		if hashes[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, hashes)
+			break
+		}
+	}
+
+	// Pages never count the matching rows
+	for _, call := range transferRepo.Calls {
+		if call.Method == "GetCount" {
+			t.Error("expected GetTransferPage not to call GetCount")
+		}
+	}
+}
@@ -5,13 +5,17 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
 	"github.com/bimakw/chain-indexer/internal/domain/repositories"
 	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/pricing"
+	"github.com/bimakw/chain-indexer/internal/pkg/units"
 )
 
 // TransferService provides business logic for transfer queries
@@ -19,6 +23,13 @@ type TransferService struct {
 	transferRepo repositories.TransferRepository
 	tokenRepo    repositories.TokenRepository
 	cache        *cache.RedisCache
+	prices       pricing.Provider
+	screening    *ScreeningService
+	flags        repositories.TransferFlagRepository
+	signatures   repositories.MethodSignatureRepository
+	notes        *AddressNoteService
+	breaker      *Breaker
+	exposure     *TokenExposure
 	logger       *zap.Logger
 }
 
@@ -37,6 +48,48 @@ func NewTransferService(
 	}
 }
 
+// WithPriceProvider enables USD enrichment of transfer responses
+func (s *TransferService) WithPriceProvider(provider pricing.Provider) *TransferService {
+	s.prices = provider
+	return s
+}
+
+// WithScreening flags transfers touching deny-listed addresses in every response
+func (s *TransferService) WithScreening(screening *ScreeningService) *TransferService {
+	s.screening = screening
+	return s
+}
+
+// WithTransferFlags lists the flags of the analysis pass, such as wash_trade, on every transfer
+func (s *TransferService) WithTransferFlags(repo repositories.TransferFlagRepository) *TransferService {
+	s.flags = repo
+	return s
+}
+
+// WithMethodSignatures labels transfers with the function their transaction called
+func (s *TransferService) WithMethodSignatures(repo repositories.MethodSignatureRepository) *TransferService {
+	s.signatures = repo
+	return s
+}
+
+// WithAddressNotes returns the request tenant's notes on the sender and receiver of every transfer
+func (s *TransferService) WithAddressNotes(notes *AddressNoteService) *TransferService {
+	s.notes = notes
+	return s
+}
+
+// WithBreaker retries transfer page reads through b and serves stale cached pages while the database is unavailable
+func (s *TransferService) WithBreaker(b *Breaker) *TransferService {
+	s.breaker = b
+	return s
+}
+
+// WithExposure leaves the tokens e does not expose to the request out of every transfer feed
+func (s *TransferService) WithExposure(e *TokenExposure) *TransferService {
+	s.exposure = e
+	return s
+}
+
 // TransferResponse is the API response for transfer queries
 type TransferResponse struct {
 	Transfers []TransferDTO `json:"transfers"`
@@ -48,18 +101,30 @@ type TransferResponse struct {
 
 // TransferDTO is the API representation of a transfer
 type TransferDTO struct {
-	TxHash         string `json:"tx_hash"`
-	LogIndex       int    `json:"log_index"`
-	BlockNumber    int64  `json:"block_number"`
-	BlockTimestamp string `json:"block_timestamp"`
-	TokenAddress   string `json:"token_address"`
-	FromAddress    string `json:"from_address"`
-	ToAddress      string `json:"to_address"`
-	Value          string `json:"value"`
+	TxHash         string   `json:"tx_hash"`
+	LogIndex       int      `json:"log_index"`
+	BlockNumber    int64    `json:"block_number"`
+	BlockTimestamp string   `json:"block_timestamp"`
+	TokenAddress   string   `json:"token_address"`
+	FromAddress    string   `json:"from_address"`
+	ToAddress      string   `json:"to_address"`
+	Initiator      string   `json:"initiator,omitempty"` // Sender of the transaction; set when initiator enrichment is enabled
+	MethodSelector string   `json:"method_selector,omitempty"`
+	MethodName     string   `json:"method_name,omitempty"`  // Function signature, when the selector is known
+	MethodLabel    string   `json:"method_label,omitempty"` // e.g. swap, bridge or transferFrom, when the selector is known
+	Value          string   `json:"value,omitempty"`
+	ValueFormatted string   `json:"value_formatted,omitempty"` // Value scaled by the token's decimals
+	ValueUSD       string   `json:"value_usd,omitempty"`
+	Screened       *bool    `json:"screened,omitempty"`  // Sender or receiver is on a deny list; omitted when screening is unavailable
+	Flags          []string `json:"flags,omitempty"`     // Heuristic labels from the analysis pass, e.g. wash_trade
+	FromNote       string   `json:"from_note,omitempty"` // The API key's tenant's note on the sender
+	ToNote         string   `json:"to_note,omitempty"`   // The API key's tenant's note on the receiver
 }
 
 // GetTransfers retrieves transfers based on filter
 func (s *TransferService) GetTransfers(ctx context.Context, filter entities.TransferFilter) (*TransferResponse, error) {
+	s.exposure.scopeTransfers(ctx, &filter)
+
 	// Generate cache key
 	cacheKey := s.generateCacheKey(filter)
 
@@ -68,10 +133,45 @@ func (s *TransferService) GetTransfers(ctx context.Context, filter entities.Tran
 	if s.cache != nil {
 		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
 			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			s.addScreening(ctx, &cached)
+			s.addFlags(ctx, cached.Transfers)
+			s.addNotes(ctx, cached.Transfers)
 			return &cached, nil
 		}
 	}
 
+	response, err := guardedLoad(ctx, s.breaker, s.cache, s.logger, "transfers.GetTransfers", cacheKey, func(ctx context.Context) (*TransferResponse, error) {
+		return s.loadTransfers(ctx, filter, cacheKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Screened and flagged after caching, so deny list refreshes and new flags apply to cached pages immediately
+	s.addScreening(ctx, response)
+	s.addFlags(ctx, response.Transfers)
+	s.addNotes(ctx, response.Transfers)
+
+	return response, nil
+}
+
+// warmTokenTransfers reloads the first page of a token's transfers into the cache, as
+// GetTransfersByToken would on a miss
+func (s *TransferService) warmTokenTransfers(ctx context.Context, tokenAddress string, limit int) error {
+	filter := entities.TransferFilter{
+		TokenAddress: &tokenAddress,
+		Limit:        limit,
+	}
+	s.exposure.scopeTransfers(ctx, &filter)
+	cacheKey := s.generateCacheKey(filter)
+	_, err := guardedLoad(ctx, s.breaker, s.cache, s.logger, "transfers.GetTransfers", cacheKey, func(ctx context.Context) (*TransferResponse, error) {
+		return s.loadTransfers(ctx, filter, cacheKey)
+	})
+	return err
+}
+
+// loadTransfers reads a transfer page from the database and caches it under cacheKey
+func (s *TransferService) loadTransfers(ctx context.Context, filter entities.TransferFilter, cacheKey string) (*TransferResponse, error) {
 	// Query database
 	transfers, err := s.transferRepo.GetByFilter(ctx, filter)
 	if err != nil {
@@ -83,7 +183,65 @@ func (s *TransferService) GetTransfers(ctx context.Context, filter entities.Tran
 		return nil, fmt.Errorf("failed to get transfer count: %w", err)
 	}
 
-	// Convert to DTOs
+	response := &TransferResponse{
+		Transfers: s.toTransferDTOs(ctx, transfers),
+		Total:     total,
+		Limit:     filter.Limit,
+		Offset:    filter.Offset,
+		HasMore:   int64(filter.Offset+len(transfers)) < total,
+	}
+
+	// Cache the response
+	if s.cache != nil {
+		if err := s.cache.Set(ctx, cacheKey, response); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// TransferEvent is a transfer on the live feed, identified by its insertion ID
+type TransferEvent struct {
+	ID       int64
+	Transfer TransferDTO
+}
+
+// LatestTransferID returns the ID of the most recently inserted transfer, 0 if there are none
+func (s *TransferService) LatestTransferID(ctx context.Context) (int64, error) {
+	id, err := s.transferRepo.GetLatestID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest transfer id: %w", err)
+	}
+	return id, nil
+}
+
+// GetTransfersAfter retrieves up to filter.Limit transfers matching the filter that were inserted
+// after afterID, oldest first. It is not cached since every poll asks for a new range.
+func (s *TransferService) GetTransfersAfter(ctx context.Context, filter entities.TransferFilter, afterID int64) ([]TransferEvent, error) {
+	s.exposure.scopeTransfers(ctx, &filter)
+	transfers, err := s.transferRepo.GetAfterID(ctx, filter, afterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfers: %w", err)
+	}
+
+	response := &TransferResponse{Transfers: s.toTransferDTOs(ctx, transfers)}
+	s.addScreening(ctx, response)
+	s.addFlags(ctx, response.Transfers)
+	s.addNotes(ctx, response.Transfers)
+
+	events := make([]TransferEvent, len(transfers))
+	for i, t := range transfers {
+		events[i] = TransferEvent{ID: t.ID, Transfer: response.Transfers[i]}
+	}
+	return events, nil
+}
+
+// toTransferDTOs converts transfers to DTOs, formatting values by their token's decimals
+func (s *TransferService) toTransferDTOs(ctx context.Context, transfers []entities.Transfer) []TransferDTO {
+	decimals := s.tokenDecimals(ctx, transfers)
+	signatures := s.methodSignatures(ctx, transfers)
+
 	dtos := make([]TransferDTO, len(transfers))
 	for i, t := range transfers {
 		dtos[i] = TransferDTO{
@@ -96,24 +254,191 @@ func (s *TransferService) GetTransfers(ctx context.Context, filter entities.Tran
 			ToAddress:      t.ToAddress,
 			Value:          t.ValueString,
 		}
+		if t.Initiator != nil {
+			dtos[i].Initiator = *t.Initiator
+		}
+		if t.MethodSelector != nil {
+			dtos[i].MethodSelector = *t.MethodSelector
+			if sig, ok := signatures[*t.MethodSelector]; ok {
+				dtos[i].MethodName = sig.Name
+				dtos[i].MethodLabel = sig.Label
+			}
+		}
+		if d, ok := decimals[t.TokenAddress]; ok {
+			dtos[i].ValueFormatted = units.Format(t.ValueString, d)
+		}
 	}
+	return dtos
+}
 
-	response := &TransferResponse{
-		Transfers: dtos,
-		Total:     total,
-		Limit:     filter.Limit,
-		Offset:    filter.Offset,
-		HasMore:   int64(filter.Offset+len(transfers)) < total,
+// addScreening flags transfers touching deny-listed addresses.
+// On failure screened is left unset rather than reported as false.
+func (s *TransferService) addScreening(ctx context.Context, response *TransferResponse) {
+	if s.screening == nil {
+		return
+	}
+	if err := s.screening.FlagTransfers(ctx, response.Transfers); err != nil {
+		s.logger.Warn("Failed to screen transfers", zap.Error(err))
 	}
+}
 
-	// Cache the response
-	if s.cache != nil {
-		if err := s.cache.Set(ctx, cacheKey, response); err != nil {
-			s.logger.Warn("Failed to cache response", zap.Error(err))
+// addFlags sets the flags of each transfer. On failure they are left out.
+func (s *TransferService) addFlags(ctx context.Context, transfers []TransferDTO) {
+	if s.flags == nil || len(transfers) == 0 {
+		return
+	}
+	flags, err := flagsByTransfer(ctx, s.flags, transfers)
+	if err != nil {
+		s.logger.Warn("Failed to flag transfers", zap.Error(err))
+		return
+	}
+	for i := range transfers {
+		transfers[i].Flags = flags[transferKey(transfers[i].TxHash, transfers[i].LogIndex)]
+	}
+}
+
+// addNotes sets the request tenant's notes on the sender and receiver of each transfer. Notes are
+// added after caching, since cached responses are shared by all tenants.
+func (s *TransferService) addNotes(ctx context.Context, transfers []TransferDTO) {
+	if s.notes == nil || len(transfers) == 0 {
+		return
+	}
+	addresses := make([]string, 0, 2*len(transfers))
+	for _, t := range transfers {
+		addresses = append(addresses, t.FromAddress, t.ToAddress)
+	}
+	notes := s.notes.notesFor(ctx, addresses)
+	for i := range transfers {
+		transfers[i].FromNote = notes[transfers[i].FromAddress]
+		transfers[i].ToNote = notes[transfers[i].ToAddress]
+	}
+}
+
+// tokenDecimals looks up decimals for the distinct tokens in transfers.
+// Tokens that cannot be resolved are left out, so their values stay unformatted.
+func (s *TransferService) tokenDecimals(ctx context.Context, transfers []entities.Transfer) map[string]int {
+	decimals := make(map[string]int)
+	if s.tokenRepo == nil {
+		return decimals
+	}
+
+	seen := make(map[string]struct{})
+	for _, t := range transfers {
+		if _, ok := seen[t.TokenAddress]; ok {
+			continue
+		}
+		seen[t.TokenAddress] = struct{}{}
+
+		token, err := s.tokenRepo.GetByAddress(ctx, t.TokenAddress)
+		if err != nil {
+			s.logger.Warn("Failed to get token decimals", zap.String("token", t.TokenAddress), zap.Error(err))
+			continue
+		}
+		if token != nil {
+			decimals[t.TokenAddress] = token.Decimals
 		}
 	}
 
-	return response, nil
+	return decimals
+}
+
+// methodSignatures looks up the signatures of the transfers' method selectors
+func (s *TransferService) methodSignatures(ctx context.Context, transfers []entities.Transfer) map[string]entities.MethodSignature {
+	signatures := make(map[string]entities.MethodSignature)
+	if s.signatures == nil {
+		return signatures
+	}
+
+	seen := make(map[string]struct{})
+	var selectors []string
+	for _, t := range transfers {
+		if t.MethodSelector == nil {
+			continue
+		}
+		if _, ok := seen[*t.MethodSelector]; !ok {
+			seen[*t.MethodSelector] = struct{}{}
+			selectors = append(selectors, *t.MethodSelector)
+		}
+	}
+	if len(selectors) == 0 {
+		return signatures
+	}
+
+	sigs, err := s.signatures.GetBySelectors(ctx, selectors)
+	if err != nil {
+		s.logger.Warn("Failed to get method signatures", zap.Error(err))
+		return signatures
+	}
+	for _, sig := range sigs {
+		signatures[sig.Selector] = sig
+	}
+
+	return signatures
+}
+
+// AddUSDValues fills value_usd for transfers with a known token decimals.
+// Historical uses the price at each transfer's block time, otherwise the current price.
+// It is a no-op when no price provider is configured.
+func (s *TransferService) AddUSDValues(ctx context.Context, response *TransferResponse, historical bool) {
+	if response == nil {
+		return
+	}
+	s.addUSDValues(ctx, response.Transfers, historical)
+}
+
+func (s *TransferService) addUSDValues(ctx context.Context, transfers []TransferDTO, historical bool) {
+	if s.prices == nil {
+		return
+	}
+
+	prices := newPriceLookup(s.prices, s.logger)
+	for i := range transfers {
+		dto := &transfers[i]
+		if dto.ValueFormatted == "" {
+			continue
+		}
+
+		var price float64
+		var ok bool
+		if historical {
+			at, err := time.Parse("2006-01-02T15:04:05Z", dto.BlockTimestamp)
+			if err != nil {
+				continue
+			}
+			price, ok = prices.historical(ctx, dto.TokenAddress, at)
+		} else {
+			price, ok = prices.current(ctx, dto.TokenAddress)
+		}
+		if !ok {
+			continue
+		}
+
+		if usd, ok := pricing.ValueUSD(dto.ValueFormatted, price); ok {
+			dto.ValueUSD = usd
+		}
+	}
+}
+
+// ApplyValueFormat strips value fields from a response according to the requested format:
+// "raw" keeps only value, "formatted" keeps only value_formatted, anything else keeps both.
+func ApplyValueFormat(response *TransferResponse, format string) {
+	if response == nil {
+		return
+	}
+	applyValueFormat(response.Transfers, format)
+}
+
+func applyValueFormat(transfers []TransferDTO, format string) {
+	for i := range transfers {
+		switch format {
+		case "raw":
+			transfers[i].ValueFormatted = ""
+		case "formatted":
+			if transfers[i].ValueFormatted != "" {
+				transfers[i].Value = ""
+			}
+		}
+	}
 }
 
 // GetTransfersByAddress retrieves transfers involving a specific address
@@ -145,6 +470,12 @@ func (s *TransferService) generateCacheKey(filter entities.TransferFilter) strin
 	if filter.TokenAddress != nil {
 		parts = append(parts, "token:"+*filter.TokenAddress)
 	}
+	if len(filter.Tokens) > 0 {
+		parts = append(parts, "tokens:"+strings.Join(filter.Tokens, ","))
+	}
+	if len(filter.ExcludeTokens) > 0 {
+		parts = append(parts, "notokens:"+strings.Join(filter.ExcludeTokens, ","))
+	}
 	if filter.FromAddress != nil {
 		parts = append(parts, "from:"+*filter.FromAddress)
 	}
@@ -154,12 +485,38 @@ func (s *TransferService) generateCacheKey(filter entities.TransferFilter) strin
 	if filter.Address != nil {
 		parts = append(parts, "addr:"+*filter.Address)
 	}
+	if len(filter.Addresses) > 0 {
+		addrs := append([]string(nil), filter.Addresses...)
+		sort.Strings(addrs)
+		parts = append(parts, "addrs:"+strings.Join(addrs, ","))
+	}
+	if filter.Initiator != nil {
+		parts = append(parts, "init:"+*filter.Initiator)
+	}
+	if filter.Selector != nil {
+		parts = append(parts, "sel:"+*filter.Selector)
+	}
+	if filter.Method != nil {
+		parts = append(parts, "method:"+*filter.Method)
+	}
 	if filter.FromBlock != nil {
 		parts = append(parts, fmt.Sprintf("fb:%d", *filter.FromBlock))
 	}
 	if filter.ToBlock != nil {
 		parts = append(parts, fmt.Sprintf("tb:%d", *filter.ToBlock))
 	}
+	if filter.MinValue != nil {
+		parts = append(parts, "minv:"+filter.MinValue.String())
+	}
+	if filter.MaxValue != nil {
+		parts = append(parts, "maxv:"+filter.MaxValue.String())
+	}
+	if filter.ExcludeZero {
+		parts = append(parts, "nozero")
+	}
+	if filter.ExcludeSelf {
+		parts = append(parts, "noself")
+	}
 
 	parts = append(parts, fmt.Sprintf("l:%d:o:%d", filter.Limit, filter.Offset))
 
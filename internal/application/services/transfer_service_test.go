@@ -191,6 +191,31 @@ func TestTransferService_GetTransfers_FilterByAddress(t *testing.T) {
 	}
 }
 
+func TestTransferService_GetTransfers_FilterByInitiator(t *testing.T) {
+	service, transferRepo, _ := setupTransferServiceTest()
+	ctx := context.Background()
+
+	// A router moving Bob's tokens on Alice's behalf, next to a transfer Bob sent himself
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithFromAddress(testutil.BobAddress), testutil.WithInitiator(testutil.AliceAddress)),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithFromAddress(testutil.BobAddress), testutil.WithInitiator(testutil.BobAddress)),
+		testutil.CreateTestTransfer(testutil.WithID(3), testutil.WithFromAddress(testutil.CharlieAddr)),
+	)
+
+	aliceAddr := testutil.AliceAddress
+	response, err := service.GetTransfers(ctx, entities.TransferFilter{Initiator: &aliceAddr, Limit: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if response.Total != 1 || len(response.Transfers) != 1 {
+		t.Fatalf("expected 1 transfer, got total %d", response.Total)
+	}
+	if response.Transfers[0].Initiator != testutil.AliceAddress || response.Transfers[0].FromAddress != testutil.BobAddress {
+		t.Errorf("unexpected transfer %+v", response.Transfers[0])
+	}
+}
+
 func TestTransferService_GetTransfers_FilterByBlockRange(t *testing.T) {
 	service, transferRepo, _ := setupTransferServiceTest()
 	ctx := context.Background()
@@ -418,6 +443,65 @@ func TestTransferDTO_Formatting(t *testing.T) {
 	}
 }
 
+func TestTransferDTO_ValueFormatted(t *testing.T) {
+	service, transferRepo, tokenRepo := setupTransferServiceTest()
+	ctx := context.Background()
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithValue(big.NewInt(1500000))),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithTokenAddress(testutil.USDCAddress)),
+	)
+
+	response, err := service.GetTransfers(ctx, entities.TransferFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	formatted := make(map[string]string)
+	for _, dto := range response.Transfers {
+		formatted[dto.TokenAddress] = dto.ValueFormatted
+	}
+
+	if formatted[testutil.USDTAddress] != "1.5" {
+		t.Errorf("expected USDT value_formatted 1.5, got %q", formatted[testutil.USDTAddress])
+	}
+	// Unknown token: left unformatted rather than guessing decimals
+	if formatted[testutil.USDCAddress] != "" {
+		t.Errorf("expected empty value_formatted for unknown token, got %q", formatted[testutil.USDCAddress])
+	}
+}
+
+func TestApplyValueFormat(t *testing.T) {
+	newResponse := func() *TransferResponse {
+		return &TransferResponse{Transfers: []TransferDTO{
+			{Value: "1500000", ValueFormatted: "1.5"},
+			{Value: "42"},
+		}}
+	}
+
+	raw := newResponse()
+	ApplyValueFormat(raw, "raw")
+	if raw.Transfers[0].Value != "1500000" || raw.Transfers[0].ValueFormatted != "" {
+		t.Errorf("raw: unexpected %+v", raw.Transfers[0])
+	}
+
+	formatted := newResponse()
+	ApplyValueFormat(formatted, "formatted")
+	if formatted.Transfers[0].Value != "" || formatted.Transfers[0].ValueFormatted != "1.5" {
+		t.Errorf("formatted: unexpected %+v", formatted.Transfers[0])
+	}
+	if formatted.Transfers[1].Value != "42" {
+		t.Errorf("formatted: expected raw value kept when no formatted value, got %+v", formatted.Transfers[1])
+	}
+
+	both := newResponse()
+	ApplyValueFormat(both, "both")
+	if both.Transfers[0].Value != "1500000" || both.Transfers[0].ValueFormatted != "1.5" {
+		t.Errorf("both: unexpected %+v", both.Transfers[0])
+	}
+}
+
 func TestGenerateCacheKey_DifferentFilters(t *testing.T) {
 	service, _, _ := setupTransferServiceTest()
 
@@ -458,6 +542,18 @@ func TestGenerateCacheKey_DifferentFilters(t *testing.T) {
 			filter2: entities.TransferFilter{TokenAddress: &tokenAddr, Limit: 100, Offset: 10},
 			same:    false,
 		},
+		{
+			name:    "different value range produces different key",
+			filter1: entities.TransferFilter{MinValue: big.NewInt(100), Limit: 100},
+			filter2: entities.TransferFilter{MaxValue: big.NewInt(100), Limit: 100},
+			same:    false,
+		},
+		{
+			name:    "exclusion flags produce different key",
+			filter1: entities.TransferFilter{ExcludeZero: true, Limit: 100},
+			filter2: entities.TransferFilter{ExcludeSelf: true, Limit: 100},
+			same:    false,
+		},
 		{
 			name:    "all filters combined",
 			filter1: entities.TransferFilter{TokenAddress: &tokenAddr, FromAddress: &fromAddr, ToAddress: &toAddr, Address: &addr, FromBlock: &fromBlock, ToBlock: &toBlock, Limit: 100},
@@ -498,3 +594,91 @@ func TestGenerateCacheKey_Format(t *testing.T) {
 		t.Errorf("expected key length %d, got %d", expectedLen, len(key))
 	}
 }
+
+func BenchmarkGenerateCacheKey(b *testing.B) {
+	service, _, _ := setupTransferServiceTest()
+
+	tokenAddr := testutil.USDTAddress
+	fromBlock := int64(19000000)
+	filter := entities.TransferFilter{
+		TokenAddress: &tokenAddr,
+		Addresses:    []string{testutil.CharlieAddr, testutil.AliceAddress, testutil.BobAddress},
+		FromBlock:    &fromBlock,
+		MinValue:     big.NewInt(1000000),
+		ExcludeZero:  true,
+		Limit:        100,
+		Offset:       200,
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = service.generateCacheKey(filter)
+	}
+}
+
+func TestTransferService_AddUSDValues(t *testing.T) {
+	ctx := context.Background()
+
+	newResponse := func() *TransferResponse {
+		return &TransferResponse{Transfers: []TransferDTO{
+			{TokenAddress: testutil.USDTAddress, BlockTimestamp: "2024-01-15T10:30:00Z", ValueFormatted: "1.5"},
+			{TokenAddress: testutil.USDCAddress, BlockTimestamp: "2024-01-15T10:30:00Z", ValueFormatted: "2"},
+			{TokenAddress: testutil.USDTAddress, BlockTimestamp: "2024-01-15T10:30:00Z", Value: "7"},
+		}}
+	}
+
+	t.Run("no-op without provider", func(t *testing.T) {
+		service, _, _ := setupTransferServiceTest()
+		response := newResponse()
+
+		service.AddUSDValues(ctx, response, false)
+
+		for _, dto := range response.Transfers {
+			if dto.ValueUSD != "" {
+				t.Errorf("expected no value_usd, got %q", dto.ValueUSD)
+			}
+		}
+	})
+
+	t.Run("current prices", func(t *testing.T) {
+		service, _, _ := setupTransferServiceTest()
+		prices := testutil.NewMockPriceProvider(map[string]float64{testutil.USDTAddress: 2})
+		service.WithPriceProvider(prices)
+		response := newResponse()
+
+		service.AddUSDValues(ctx, response, false)
+
+		if response.Transfers[0].ValueUSD != "3.00" {
+			t.Errorf("expected 3.00, got %q", response.Transfers[0].ValueUSD)
+		}
+		if response.Transfers[1].ValueUSD != "" {
+			t.Errorf("expected no value_usd for unpriced token, got %q", response.Transfers[1].ValueUSD)
+		}
+		if response.Transfers[2].ValueUSD != "" {
+			t.Errorf("expected no value_usd without formatted value, got %q", response.Transfers[2].ValueUSD)
+		}
+		if len(prices.Calls) != 2 {
+			t.Errorf("expected one price lookup per token, got %d", len(prices.Calls))
+		}
+	})
+
+	t.Run("historical prices use block time", func(t *testing.T) {
+		service, _, _ := setupTransferServiceTest()
+		prices := testutil.NewMockPriceProvider(nil)
+		prices.HistoricalPriceFunc = func(ctx context.Context, tokenAddress string, at time.Time) (float64, error) {
+			if !at.Equal(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)) {
+				t.Errorf("unexpected price time %v", at)
+			}
+			return 4, nil
+		}
+		service.WithPriceProvider(prices)
+		response := newResponse()
+
+		service.AddUSDValues(ctx, response, true)
+
+		if response.Transfers[0].ValueUSD != "6.00" || response.Transfers[1].ValueUSD != "8.00" {
+			t.Errorf("unexpected historical values: %q %q", response.Transfers[0].ValueUSD, response.Transfers[1].ValueUSD)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/pkg/units"
+)
+
+// MaxGraphDepth is how many hops from the wallet a transfer graph can reach
+const MaxGraphDepth = 2
+
+// Transfer graph bounds: the nodes and edges a graph holds, and the most recent transfers read in
+// each direction per hop, which keeps expanding an exchange or router affordable
+const (
+	maxGraphNodes  = 100
+	maxGraphEdges  = 250
+	graphScanLimit = 5000
+)
+
+// graphCacheTTL is how long transfer graphs are cached
+const graphCacheTTL = 5 * time.Minute
+
+// GraphNodeDTO is an address in a transfer graph, at Depth hops from the wallet
+type GraphNodeDTO struct {
+	Address string `json:"address"`
+	Depth   int    `json:"depth"`
+}
+
+// GraphEdgeDTO is the aggregate flow of one token from one address to another
+type GraphEdgeDTO struct {
+	From            string `json:"from"`
+	To              string `json:"to"`
+	TokenAddress    string `json:"token_address"`
+	TokenSymbol     string `json:"token_symbol"`
+	Transfers       int64  `json:"transfers"`
+	Volume          string `json:"volume"`           // Raw amount
+	VolumeFormatted string `json:"volume_formatted"` // Human readable
+	LastTransferAt  string `json:"last_transfer_at"`
+}
+
+// WalletGraphDTO is a wallet's transfer neighborhood
+type WalletGraphDTO struct {
+	WalletAddress string         `json:"wallet_address"`
+	Depth         int            `json:"depth"`
+	Nodes         []GraphNodeDTO `json:"nodes"`
+	Edges         []GraphEdgeDTO `json:"edges"`
+	Truncated     bool           `json:"truncated"` // The node or edge cap left flows out
+}
+
+// WalletGraphResponse wraps a wallet transfer graph for API response
+type WalletGraphResponse struct {
+	Data WalletGraphDTO `json:"data"`
+}
+
+// GetWalletGraph returns the addresses within depth hops of a wallet and the flows between them,
+// expanding the busiest counterparties first. The zero address is kept as a node but not
+// expanded, since it is a counterparty of every mint and burn.
+func (s *PortfolioService) GetWalletGraph(ctx context.Context, walletAddress string, depth int) (*WalletGraphResponse, error) {
+	walletAddress = strings.ToLower(walletAddress)
+	depth = max(1, min(depth, MaxGraphDepth))
+
+	cacheKey := fmt.Sprintf("wallet_graph:%s:%d", walletAddress, depth)
+	if s.exposure.narrowed(ctx) {
+		cacheKey = "wallet_graph:exposed:" + strings.TrimPrefix(cacheKey, "wallet_graph:")
+	}
+	var cached WalletGraphResponse
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+			s.logger.Debug("Cache hit", zap.String("key", cacheKey))
+			return &cached, nil
+		}
+	}
+
+	return guardedLoad(ctx, s.breaker, s.cache, s.logger, "portfolio.GetWalletGraph", cacheKey, func(ctx context.Context) (*WalletGraphResponse, error) {
+		return s.loadWalletGraph(ctx, walletAddress, depth, cacheKey)
+	})
+}
+
+// loadWalletGraph traverses the graph one hop at a time and caches it under cacheKey
+func (s *PortfolioService) loadWalletGraph(ctx context.Context, walletAddress string, depth int, cacheKey string) (*WalletGraphResponse, error) {
+	graph := WalletGraphDTO{
+		WalletAddress: walletAddress,
+		Depth:         depth,
+		Nodes:         []GraphNodeDTO{{Address: walletAddress}},
+		Edges:         []GraphEdgeDTO{},
+	}
+	seen := map[string]bool{walletAddress: true}
+	edges := make(map[string]bool)
+
+	frontier := []string{walletAddress}
+	for hop := 1; hop <= depth && len(frontier) > 0; hop++ {
+		limit := maxGraphEdges - len(graph.Edges)
+		if limit <= 0 {
+			graph.Truncated = true
+			break
+		}
+		flows, err := s.portfolioRepo.GetCounterpartyFlows(ctx, frontier, graphScanLimit, limit+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get counterparty flows: %w", err)
+		}
+		if len(flows) > limit {
+			flows = flows[:limit]
+			graph.Truncated = true
+		}
+
+		var next []string
+		for _, flow := range flows {
+			// Flows of tokens the request may not see are not followed
+			if !s.exposure.Exposes(ctx, flow.TokenAddress) {
+				continue
+			}
+
+			// Flows with an address of an earlier hop were already read with it
+			key := flow.FromAddress + ":" + flow.ToAddress + ":" + flow.TokenAddress
+			if edges[key] {
+				continue
+			}
+
+			var added []string
+			for _, address := range []string{flow.FromAddress, flow.ToAddress} {
+				if !seen[address] && !slices.Contains(added, address) {
+					added = append(added, address)
+				}
+			}
+			if len(graph.Nodes)+len(added) > maxGraphNodes {
+				graph.Truncated = true
+				continue
+			}
+			for _, address := range added {
+				seen[address] = true
+				graph.Nodes = append(graph.Nodes, GraphNodeDTO{Address: address, Depth: hop})
+				if address != entities.ZeroAddress {
+					next = append(next, address)
+				}
+			}
+
+			edges[key] = true
+			graph.Edges = append(graph.Edges, GraphEdgeDTO{
+				From:            flow.FromAddress,
+				To:              flow.ToAddress,
+				TokenAddress:    flow.TokenAddress,
+				TokenSymbol:     flow.TokenSymbol,
+				Transfers:       flow.Transfers,
+				Volume:          flow.Volume,
+				VolumeFormatted: units.Format(flow.Volume, flow.Decimals),
+				LastTransferAt:  flow.LastTransferAt.Format(time.RFC3339),
+			})
+		}
+		frontier = next
+	}
+
+	response := &WalletGraphResponse{Data: graph}
+
+	if s.cache != nil {
+		if err := s.cache.SetWithTTL(ctx, cacheKey, response, graphCacheTTL); err != nil {
+			s.logger.Warn("Failed to cache response", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// MaxWatchlistAddresses caps how many addresses one watchlist can hold,
+// keeping the IN-list of the merged feed query bounded
+const MaxWatchlistAddresses = 100
+
+// ErrWatchlistFull is returned when adding addresses would exceed MaxWatchlistAddresses
+var ErrWatchlistFull = errors.New("watchlist address limit exceeded")
+
+// WatchlistService provides business logic for watchlists and their transfer feed
+type WatchlistService struct {
+	watchlistRepo   repositories.WatchlistRepository
+	transferService *TransferService
+	breaker         *Breaker
+	logger          *zap.Logger
+}
+
+// NewWatchlistService creates a new watchlist service.
+// Feed queries go through transferService so they share its response cache.
+func NewWatchlistService(
+	watchlistRepo repositories.WatchlistRepository,
+	transferService *TransferService,
+	logger *zap.Logger,
+) *WatchlistService {
+	return &WatchlistService{
+		watchlistRepo:   watchlistRepo,
+		transferService: transferService,
+		logger:          logger,
+	}
+}
+
+// WithBreaker retries watchlist reads through b, answering with an *UnavailableError while the
+// database is unavailable. Watchlists are not cached, so there are no stale copies to serve.
+func (s *WatchlistService) WithBreaker(b *Breaker) *WatchlistService {
+	s.breaker = b
+	return s
+}
+
+// WatchlistDTO is the API representation of a watchlist
+type WatchlistDTO struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// WatchlistResponse is the API response for watchlist queries
+type WatchlistResponse struct {
+	Data WatchlistDTO `json:"data"`
+}
+
+// CreateWatchlist creates a watchlist holding the given addresses
+func (s *WatchlistService) CreateWatchlist(ctx context.Context, name string, addresses []string) (*WatchlistResponse, error) {
+	addresses = normalizeAddresses(addresses)
+	if len(addresses) > MaxWatchlistAddresses {
+		return nil, ErrWatchlistFull
+	}
+
+	watchlist := &entities.Watchlist{
+		Name:      name,
+		Addresses: addresses,
+	}
+	if err := s.watchlistRepo.Create(ctx, watchlist); err != nil {
+		return nil, fmt.Errorf("failed to create watchlist: %w", err)
+	}
+
+	return toWatchlistResponse(watchlist), nil
+}
+
+// GetWatchlist retrieves a watchlist, or nil if it does not exist
+func (s *WatchlistService) GetWatchlist(ctx context.Context, id int64) (*WatchlistResponse, error) {
+	watchlist, err := s.getWatchlist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if watchlist == nil {
+		return nil, nil
+	}
+
+	return toWatchlistResponse(watchlist), nil
+}
+
+// DeleteWatchlist removes a watchlist, reporting whether it existed
+func (s *WatchlistService) DeleteWatchlist(ctx context.Context, id int64) (bool, error) {
+	deleted, err := s.watchlistRepo.Delete(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete watchlist: %w", err)
+	}
+	return deleted, nil
+}
+
+// AddAddresses adds addresses to a watchlist and returns the updated watchlist,
+// or nil if it does not exist
+func (s *WatchlistService) AddAddresses(ctx context.Context, id int64, addresses []string) (*WatchlistResponse, error) {
+	watchlist, err := s.watchlistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+	if watchlist == nil {
+		return nil, nil
+	}
+
+	var added []string
+	for _, addr := range normalizeAddresses(addresses) {
+		if !containsString(watchlist.Addresses, addr) {
+			added = append(added, addr)
+		}
+	}
+	if len(watchlist.Addresses)+len(added) > MaxWatchlistAddresses {
+		return nil, ErrWatchlistFull
+	}
+
+	if len(added) > 0 {
+		if err := s.watchlistRepo.AddAddresses(ctx, id, added); err != nil {
+			return nil, fmt.Errorf("failed to add watchlist addresses: %w", err)
+		}
+	}
+
+	return s.GetWatchlist(ctx, id)
+}
+
+// RemoveAddress removes an address from a watchlist and returns the updated watchlist,
+// or nil if it does not exist. Removing an address that is not watched is a no-op.
+func (s *WatchlistService) RemoveAddress(ctx context.Context, id int64, address string) (*WatchlistResponse, error) {
+	if _, err := s.watchlistRepo.RemoveAddress(ctx, id, strings.ToLower(address)); err != nil {
+		return nil, fmt.Errorf("failed to remove watchlist address: %w", err)
+	}
+
+	return s.GetWatchlist(ctx, id)
+}
+
+// GetWatchlistTransfers retrieves transfers sent or received by any watched address, newest first,
+// or nil if the watchlist does not exist
+func (s *WatchlistService) GetWatchlistTransfers(ctx context.Context, id int64, limit, offset int) (*TransferResponse, error) {
+	watchlist, err := s.getWatchlist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if watchlist == nil {
+		return nil, nil
+	}
+
+	// An empty Addresses filter would match every transfer
+	if len(watchlist.Addresses) == 0 {
+		return &TransferResponse{
+			Transfers: []TransferDTO{},
+			Limit:     limit,
+			Offset:    offset,
+		}, nil
+	}
+
+	filter := entities.DefaultTransferFilter()
+	filter.Addresses = watchlist.Addresses
+	filter.Limit = limit
+	filter.Offset = offset
+
+	return s.transferService.GetTransfers(ctx, filter)
+}
+
+// getWatchlist reads a watchlist through the breaker, nil if it does not exist
+func (s *WatchlistService) getWatchlist(ctx context.Context, id int64) (*entities.Watchlist, error) {
+	return guardedLoad(ctx, s.breaker, nil, s.logger, "watchlists.GetWatchlist", "", func(ctx context.Context) (*entities.Watchlist, error) {
+		watchlist, err := s.watchlistRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get watchlist: %w", err)
+		}
+		return watchlist, nil
+	})
+}
+
+func toWatchlistResponse(watchlist *entities.Watchlist) *WatchlistResponse {
+	addresses := watchlist.Addresses
+	if addresses == nil {
+		addresses = []string{}
+	}
+
+	return &WatchlistResponse{
+		Data: WatchlistDTO{
+			ID:        watchlist.ID,
+			Name:      watchlist.Name,
+			Addresses: addresses,
+			CreatedAt: watchlist.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			UpdatedAt: watchlist.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		},
+	}
+}
+
+// normalizeAddresses lowercases addresses and drops duplicates, keeping first-seen order
+func normalizeAddresses(addresses []string) []string {
+	result := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		addr = strings.ToLower(addr)
+		if !containsString(result, addr) {
+			result = append(result, addr)
+		}
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func newTestWatchlistService(transferRepo *testutil.MockTransferRepository, watchlistRepo *testutil.MockWatchlistRepository) *WatchlistService {
+	logger := zap.NewNop()
+	transferService := NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, logger)
+	return NewWatchlistService(watchlistRepo, transferService, logger)
+}
+
+func TestWatchlistService_CreateWatchlist(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("normalizes and deduplicates addresses", func(t *testing.T) {
+		service := newTestWatchlistService(testutil.NewMockTransferRepository(), testutil.NewMockWatchlistRepository())
+
+		result, err := service.CreateWatchlist(ctx, "whales", []string{
+			"0xABCDEF0000000000000000000000000000000001",
+			testutil.BobAddress,
+			strings.ToUpper(testutil.BobAddress[:2]) + testutil.BobAddress[2:],
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Data.ID == 0 {
+			t.Error("expected an ID to be assigned")
+		}
+		want := []string{"0xabcdef0000000000000000000000000000000001", testutil.BobAddress}
+		if fmt.Sprint(result.Data.Addresses) != fmt.Sprint(want) {
+			t.Errorf("expected addresses %v, got %v", want, result.Data.Addresses)
+		}
+	})
+
+	t.Run("rejects too many addresses", func(t *testing.T) {
+		service := newTestWatchlistService(testutil.NewMockTransferRepository(), testutil.NewMockWatchlistRepository())
+
+		addresses := make([]string, MaxWatchlistAddresses+1)
+		for i := range addresses {
+			addresses[i] = fmt.Sprintf("0x%040x", i+1)
+		}
+
+		_, err := service.CreateWatchlist(ctx, "too big", addresses)
+		if !errors.Is(err, ErrWatchlistFull) {
+			t.Errorf("expected ErrWatchlistFull, got %v", err)
+		}
+	})
+
+	t.Run("propagates repository errors", func(t *testing.T) {
+		watchlistRepo := testutil.NewMockWatchlistRepository()
+		watchlistRepo.CreateFunc = func(ctx context.Context, watchlist *entities.Watchlist) error {
+			return errors.New("database error")
+		}
+		service := newTestWatchlistService(testutil.NewMockTransferRepository(), watchlistRepo)
+
+		if _, err := service.CreateWatchlist(ctx, "whales", nil); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestWatchlistService_AddRemoveAddresses(t *testing.T) {
+	ctx := context.Background()
+	watchlistRepo := testutil.NewMockWatchlistRepository()
+	service := newTestWatchlistService(testutil.NewMockTransferRepository(), watchlistRepo)
+
+	created, err := service.CreateWatchlist(ctx, "team", []string{testutil.AliceAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id := created.Data.ID
+
+	result, err := service.AddAddresses(ctx, id, []string{testutil.AliceAddress, testutil.BobAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Data.Addresses) != 2 {
+		t.Errorf("expected 2 addresses, got %v", result.Data.Addresses)
+	}
+
+	result, err = service.RemoveAddress(ctx, id, testutil.AliceAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(result.Data.Addresses) != fmt.Sprint([]string{testutil.BobAddress}) {
+		t.Errorf("expected only bob to remain, got %v", result.Data.Addresses)
+	}
+
+	t.Run("rejects exceeding the limit", func(t *testing.T) {
+		addresses := make([]string, MaxWatchlistAddresses)
+		for i := range addresses {
+			addresses[i] = fmt.Sprintf("0x%040x", i+1)
+		}
+
+		_, err := service.AddAddresses(ctx, id, addresses)
+		if !errors.Is(err, ErrWatchlistFull) {
+			t.Errorf("expected ErrWatchlistFull, got %v", err)
+		}
+	})
+
+	t.Run("returns nil for unknown watchlist", func(t *testing.T) {
+		result, err := service.AddAddresses(ctx, 999, []string{testutil.CharlieAddr})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != nil {
+			t.Error("expected nil result")
+		}
+	})
+}
+
+func TestWatchlistService_GetWatchlistTransfers(t *testing.T) {
+	ctx := context.Background()
+
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.CharlieAddr)),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithFromAddress(testutil.CharlieAddr), testutil.WithToAddress(testutil.BobAddress)),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x03"), testutil.WithFromAddress(testutil.CharlieAddr), testutil.WithToAddress(testutil.CharlieAddr)),
+	)
+	service := newTestWatchlistService(transferRepo, testutil.NewMockWatchlistRepository())
+
+	t.Run("merges transfers of all watched addresses", func(t *testing.T) {
+		created, _ := service.CreateWatchlist(ctx, "pair", []string{testutil.AliceAddress, testutil.BobAddress})
+
+		result, err := service.GetWatchlistTransfers(ctx, created.Data.ID, 1, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Total != 2 || len(result.Transfers) != 1 || !result.HasMore {
+			t.Errorf("unexpected pagination: %d transfers, total %d, has_more %v", len(result.Transfers), result.Total, result.HasMore)
+		}
+	})
+
+	t.Run("empty watchlist returns no transfers", func(t *testing.T) {
+		created, _ := service.CreateWatchlist(ctx, "empty", nil)
+
+		result, err := service.GetWatchlistTransfers(ctx, created.Data.ID, 100, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Total != 0 || len(result.Transfers) != 0 {
+			t.Errorf("expected no transfers, got %d", len(result.Transfers))
+		}
+	})
+
+	t.Run("returns nil for unknown watchlist", func(t *testing.T) {
+		result, err := service.GetWatchlistTransfers(ctx, 999, 100, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != nil {
+			t.Error("expected nil result")
+		}
+	})
+}
@@ -0,0 +1,28 @@
+// Package bootstrap holds what every chain-indexer binary does around its work: loading the
+// configuration and building the logger, connecting to PostgreSQL, Redis and the RPC node with
+// retries, and shutting down on SIGINT or SIGTERM. New binaries use it instead of copying main.
+package bootstrap
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/logging"
+)
+
+// Load loads configuration from sources layered over the defaults and builds the logger it
+// configures
+func Load(sources config.Sources) (*config.Config, *zap.Logger, error) {
+	cfg, err := config.LoadFrom(sources)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logger, err := logging.New(cfg.Log)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to set up logging: %w", err)
+	}
+	return cfg, logger, nil
+}
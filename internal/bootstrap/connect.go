@@ -0,0 +1,79 @@
+package bootstrap
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/database"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// maxConnectBackoff caps the doubling wait between connection attempts
+const maxConnectBackoff = 30 * time.Second
+
+// Retry calls connect until it succeeds, at most cfg.ConnectAttempts times, waiting
+// cfg.ConnectBackoff after the first failure and twice as long after each further one. It
+// returns the last error once the attempts are used up or ctx is done.
+func Retry(ctx context.Context, cfg config.StartupConfig, what string, logger *zap.Logger, connect func() error) error {
+	backoff := cfg.ConnectBackoff
+	for attempt := 1; ; attempt++ {
+		err := connect()
+		if err == nil || attempt >= cfg.ConnectAttempts {
+			return err
+		}
+
+		logger.Warn("Failed to connect, retrying",
+			zap.String("to", what),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
+
+// OpenStore opens the database described by db, usually cfg.Database tuned for the command with
+// ForAPI or ForIndexer
+func OpenStore(ctx context.Context, cfg *config.Config, db config.DatabaseConfig, logger *zap.Logger) (*database.Store, error) {
+	logger = logger.Named("database")
+
+	var store *database.Store
+	err := Retry(ctx, cfg.Startup, "database", logger, func() (err error) {
+		store, err = database.Open(db, logger)
+		return err
+	})
+	return store, err
+}
+
+// ConnectRedis connects to Redis, caching values for ttl by default
+func ConnectRedis(ctx context.Context, cfg *config.Config, ttl time.Duration, logger *zap.Logger) (*cache.RedisCache, error) {
+	logger = logger.Named("cache")
+
+	var redisCache *cache.RedisCache
+	err := Retry(ctx, cfg.Startup, "redis", logger, func() (err error) {
+		redisCache, err = cache.NewRedisCache(cfg.Redis, ttl, logger)
+		return err
+	})
+	return redisCache, err
+}
+
+// ConnectRPC connects to the Ethereum node and checks its chain ID
+func ConnectRPC(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*ethereum.Client, error) {
+	logger = logger.Named("ethereum")
+
+	var client *ethereum.Client
+	err := Retry(ctx, cfg.Startup, "ethereum", logger, func() (err error) {
+		client, err = ethereum.NewClient(cfg.Ethereum, logger)
+		return err
+	})
+	return client, err
+}
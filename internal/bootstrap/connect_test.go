@@ -0,0 +1,62 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+func TestRetry(t *testing.T) {
+	cfg := config.StartupConfig{ConnectAttempts: 3, ConnectBackoff: time.Millisecond}
+	refused := errors.New("connection refused")
+
+	// Succeeds once the dependency comes up
+	calls := 0
+	err := Retry(context.Background(), cfg, "database", zap.NewNop(), func() error {
+		calls++
+		if calls < 3 {
+			return refused
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("expected success on the third attempt, got %v after %d", err, calls)
+	}
+
+	// Gives up after the configured attempts with the last error
+	calls = 0
+	err = Retry(context.Background(), cfg, "database", zap.NewNop(), func() error {
+		calls++
+		return refused
+	})
+	if !errors.Is(err, refused) || calls != 3 {
+		t.Errorf("expected the connection error after 3 attempts, got %v after %d", err, calls)
+	}
+
+	// A single attempt when retries are disabled
+	calls = 0
+	_ = Retry(context.Background(), config.StartupConfig{}, "database", zap.NewNop(), func() error {
+		calls++
+		return refused
+	})
+	if calls != 1 {
+		t.Errorf("expected 1 attempt without retries, got %d", calls)
+	}
+
+	// Stops waiting when interrupted
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	err = Retry(ctx, config.StartupConfig{ConnectAttempts: 5, ConnectBackoff: time.Hour}, "database", zap.NewNop(), func() error {
+		calls++
+		cancel()
+		return refused
+	})
+	if !errors.Is(err, refused) || calls != 1 {
+		t.Errorf("expected to stop after the interrupted attempt, got %v after %d", err, calls)
+	}
+}
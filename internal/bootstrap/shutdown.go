@@ -0,0 +1,46 @@
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SignalContext returns a context canceled on SIGINT or SIGTERM, for commands that stop their
+// work when interrupted. stop releases the signal handler.
+func SignalContext() (ctx context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+}
+
+// WaitForSignal blocks until SIGINT or SIGTERM, for services that run until told to stop
+func WaitForSignal() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	<-sigCh
+}
+
+// Serve runs server in the background. The process exits if it fails other than by being shut down.
+func Serve(server *http.Server, logger *zap.Logger) {
+	go func() {
+		logger.Info("API server starting", zap.String("addr", server.Addr))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("Server error", zap.Error(err))
+		}
+	}()
+}
+
+// Shutdown stops server, waiting up to timeout for the requests in flight to finish
+func Shutdown(server *http.Server, timeout time.Duration, logger *zap.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error("Server shutdown error", zap.Error(err))
+	}
+}
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
 	"time"
-
-	"github.com/kelseyhightower/envconfig"
 )
 
 // Config holds all configuration for the application
@@ -14,6 +16,9 @@ type Config struct {
 	// Database configuration
 	Database DatabaseConfig
 
+	// ClickHouse analytics store configuration
+	ClickHouse ClickHouseConfig
+
 	// Redis configuration
 	Redis RedisConfig
 
@@ -23,8 +28,23 @@ type Config struct {
 	// Indexer configuration
 	Indexer IndexerConfig
 
+	// Price oracle configuration
+	Price PriceConfig
+
+	// Alert notification configuration
+	Alert AlertConfig
+
+	// Deny list screening configuration
+	Screening ScreeningConfig
+
+	// Parquet archival configuration
+	Archive ArchiveConfig
+
 	// Logging configuration
 	Log LogConfig
+
+	// Dependency connection retries at startup
+	Startup StartupConfig
 }
 
 // EthereumConfig holds Ethereum node connection settings
@@ -34,10 +54,44 @@ type EthereumConfig struct {
 	RequestTimeout time.Duration `envconfig:"ETH_REQUEST_TIMEOUT" default:"30s"`
 	MaxRetries     int           `envconfig:"ETH_MAX_RETRIES" default:"3"`
 	RetryDelay     time.Duration `envconfig:"ETH_RETRY_DELAY" default:"1s"`
+
+	// Client-side throttling of RPC requests (0 disables): requests per second and requests in flight
+	RPCRateLimit     float64 `envconfig:"ETH_RPC_RATE_LIMIT" default:"0"`
+	RPCMaxConcurrent int     `envconfig:"ETH_RPC_MAX_CONCURRENT" default:"0"`
+
+	// Per-provider overrides of the two limits as url=rps/concurrency pairs (comma-separated)
+	RPCLimits []string `envconfig:"ETH_RPC_LIMITS"`
+}
+
+// RateLimitsFor returns the request rate and concurrency limits for an RPC URL:
+// its ETH_RPC_LIMITS entry if there is one, otherwise ETH_RPC_RATE_LIMIT and ETH_RPC_MAX_CONCURRENT
+func (c EthereumConfig) RateLimitsFor(rpcURL string) (float64, int, error) {
+	for _, entry := range c.RPCLimits {
+		// Split on the last '=' since URLs may carry query parameters
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 {
+			return 0, 0, fmt.Errorf("invalid ETH_RPC_LIMITS entry %q, expected url=rps/concurrency", entry)
+		}
+		url, limits := strings.TrimSpace(entry[:i]), strings.TrimSpace(entry[i+1:])
+
+		rpsStr, concurrencyStr, ok := strings.Cut(limits, "/")
+		rps, rpsErr := strconv.ParseFloat(rpsStr, 64)
+		concurrency, concurrencyErr := strconv.Atoi(concurrencyStr)
+		if !ok || rpsErr != nil || concurrencyErr != nil || rps < 0 || concurrency < 0 {
+			return 0, 0, fmt.Errorf("invalid ETH_RPC_LIMITS entry %q, expected url=rps/concurrency", entry)
+		}
+
+		if url == rpcURL {
+			return rps, concurrency, nil
+		}
+	}
+	return c.RPCRateLimit, c.RPCMaxConcurrent, nil
 }
 
-// DatabaseConfig holds PostgreSQL connection settings
+// DatabaseConfig holds database connection settings
 type DatabaseConfig struct {
+	Driver          string        `envconfig:"DB_DRIVER" default:"postgres"`       // storage backend: postgres or sqlite
+	Path            string        `envconfig:"DB_PATH" default:"chain-indexer.db"` // sqlite database file
 	Host            string        `envconfig:"DB_HOST" default:"localhost"`
 	Port            int           `envconfig:"DB_PORT" default:"5432"`
 	User            string        `envconfig:"DB_USER" default:"indexer"`
@@ -47,6 +101,57 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `envconfig:"DB_MAX_OPEN_CONNS" default:"25"`
 	MaxIdleConns    int           `envconfig:"DB_MAX_IDLE_CONNS" default:"5"`
 	ConnMaxLifetime time.Duration `envconfig:"DB_CONN_MAX_LIFETIME" default:"5m"`
+	ConnMaxIdleTime time.Duration `envconfig:"DB_CONN_MAX_IDLE_TIME" default:"0s"` // 0 keeps idle connections until ConnMaxLifetime
+
+	// Per-process pool overrides for the API server and the indexer (0 keeps the shared value above)
+	APIMaxOpenConns        int           `envconfig:"DB_API_MAX_OPEN_CONNS"`
+	APIMaxIdleConns        int           `envconfig:"DB_API_MAX_IDLE_CONNS"`
+	APIConnMaxLifetime     time.Duration `envconfig:"DB_API_CONN_MAX_LIFETIME"`
+	APIConnMaxIdleTime     time.Duration `envconfig:"DB_API_CONN_MAX_IDLE_TIME"`
+	IndexerMaxOpenConns    int           `envconfig:"DB_INDEXER_MAX_OPEN_CONNS"`
+	IndexerMaxIdleConns    int           `envconfig:"DB_INDEXER_MAX_IDLE_CONNS"`
+	IndexerConnMaxLifetime time.Duration `envconfig:"DB_INDEXER_CONN_MAX_LIFETIME"`
+	IndexerConnMaxIdleTime time.Duration `envconfig:"DB_INDEXER_CONN_MAX_IDLE_TIME"`
+
+	// Queries slower than this are logged (0 disables slow query logging)
+	SlowQueryThreshold time.Duration `envconfig:"DB_SLOW_QUERY_THRESHOLD" default:"500ms"`
+}
+
+// ForAPI returns the database settings with the API server's pool overrides applied
+func (c DatabaseConfig) ForAPI() DatabaseConfig {
+	return c.withPool(c.APIMaxOpenConns, c.APIMaxIdleConns, c.APIConnMaxLifetime, c.APIConnMaxIdleTime)
+}
+
+// ForIndexer returns the database settings with the indexer's pool overrides applied
+func (c DatabaseConfig) ForIndexer() DatabaseConfig {
+	return c.withPool(c.IndexerMaxOpenConns, c.IndexerMaxIdleConns, c.IndexerConnMaxLifetime, c.IndexerConnMaxIdleTime)
+}
+
+// withPool replaces the shared pool settings with the non-zero overrides
+func (c DatabaseConfig) withPool(maxOpen, maxIdle int, maxLifetime, maxIdleTime time.Duration) DatabaseConfig {
+	if maxOpen > 0 {
+		c.MaxOpenConns = maxOpen
+	}
+	if maxIdle > 0 {
+		c.MaxIdleConns = maxIdle
+	}
+	if maxLifetime > 0 {
+		c.ConnMaxLifetime = maxLifetime
+	}
+	if maxIdleTime > 0 {
+		c.ConnMaxIdleTime = maxIdleTime
+	}
+	return c
+}
+
+// ClickHouseConfig holds settings for the optional ClickHouse analytics store
+type ClickHouseConfig struct {
+	// HTTP interface URL (empty disables the analytics store)
+	URL      string        `envconfig:"CLICKHOUSE_URL"`
+	Database string        `envconfig:"CLICKHOUSE_DATABASE" default:"chain_indexer"`
+	User     string        `envconfig:"CLICKHOUSE_USER" default:"default"`
+	Password string        `envconfig:"CLICKHOUSE_PASSWORD"`
+	Timeout  time.Duration `envconfig:"CLICKHOUSE_TIMEOUT" default:"30s"`
 }
 
 // RedisConfig holds Redis connection settings
@@ -55,6 +160,10 @@ type RedisConfig struct {
 	Port     int    `envconfig:"REDIS_PORT" default:"6379"`
 	Password string `envconfig:"REDIS_PASSWORD" default:""`
 	DB       int    `envconfig:"REDIS_DB" default:"0"`
+
+	// Cached values this many bytes or longer are stored zstd-compressed, 0 disables.
+	// Large holder and transfer pages shrink several times over.
+	CompressMinBytes int `envconfig:"REDIS_COMPRESS_MIN_BYTES" default:"16384"`
 }
 
 // APIConfig holds API server settings
@@ -66,6 +175,124 @@ type APIConfig struct {
 	ShutdownTimeout time.Duration `envconfig:"API_SHUTDOWN_TIMEOUT" default:"30s"`
 	RateLimitRPS    int           `envconfig:"API_RATE_LIMIT_RPS" default:"100"`
 	CacheTTL        time.Duration `envconfig:"API_CACHE_TTL" default:"30s"`
+
+	// Proxies whose X-Forwarded-For and X-Real-IP headers are believed, as IPs or CIDRs
+	// (comma-separated). Empty trusts none and uses the connecting address as the client IP.
+	TrustedProxies []string `envconfig:"API_TRUSTED_PROXIES"`
+
+	// Slow and oversized requests: headers must arrive within ReadHeaderTimeout, larger headers
+	// get 431 and larger bodies 413
+	ReadHeaderTimeout time.Duration `envconfig:"API_READ_HEADER_TIMEOUT" default:"5s"`
+	MaxHeaderBytes    int           `envconfig:"API_MAX_HEADER_BYTES" default:"16384"`
+	MaxBodyBytes      int64         `envconfig:"API_MAX_BODY_BYTES" default:"1048576"`
+
+	// Responses to write requests sent with an Idempotency-Key header are kept this long and
+	// replayed to retries with the same key. Requires Redis; 0 disables.
+	IdempotencyTTL time.Duration `envconfig:"API_IDEMPOTENCY_TTL" default:"24h"`
+
+	// Holder, stats and export routes may run this long instead of WriteTimeout; after it their
+	// queries are cancelled and requests still without a response get 503
+	HeavyRouteTimeout time.Duration `envconfig:"API_HEAVY_ROUTE_TIMEOUT" default:"60s"`
+
+	// Cache TTLs of the stats, holder ranking and portfolio endpoints, which also send them to
+	// clients as Cache-Control max-age
+	StatsCacheTTL     time.Duration `envconfig:"API_STATS_CACHE_TTL" default:"60s"`
+	HoldersCacheTTL   time.Duration `envconfig:"API_HOLDERS_CACHE_TTL" default:"5m"`
+	PortfolioCacheTTL time.Duration `envconfig:"API_PORTFOLIO_CACHE_TTL" default:"2m"`
+
+	// Cached responses reloaded when the indexer stores new transfers for a token: any of stats,
+	// holders and transfers, empty disables. A token is warmed at most once per CacheWarmInterval.
+	CacheWarmTargets  []string      `envconfig:"API_CACHE_WARM_TARGETS"`
+	CacheWarmInterval time.Duration `envconfig:"API_CACHE_WARM_INTERVAL" default:"30s"`
+
+	// The summaries of the HotWallets most requested wallets are reloaded every HotWalletsInterval,
+	// which must be shorter than the 5 minute summary cache TTL; 0 disables. Needs Redis.
+	HotWallets         int           `envconfig:"API_HOT_WALLETS" default:"50"`
+	HotWalletsInterval time.Duration `envconfig:"API_HOT_WALLETS_INTERVAL" default:"1m"`
+
+	// Where the API hears of new transfers for cache warming and the transfer stream: redis, the
+	// indexer's pub/sub, or postgres, LISTEN on the transfers insert trigger without the indexer
+	IndexedEvents string `envconfig:"API_INDEXED_EVENTS" default:"redis"`
+
+	// Report "degraded" in /health when the latest indexed block is older than this (0 disables)
+	MaxDataAge time.Duration `envconfig:"API_MAX_DATA_AGE" default:"0s"`
+
+	// Announced in the Sunset header of v1 routes that have a v2 successor (RFC 3339, empty omits the header)
+	V1Sunset time.Time `envconfig:"API_V1_SUNSET"`
+
+	// Server-Sent Events transfer stream: how often new transfers are polled and idle heartbeats sent
+	StreamPollInterval      time.Duration `envconfig:"API_STREAM_POLL_INTERVAL" default:"2s"`
+	StreamHeartbeatInterval time.Duration `envconfig:"API_STREAM_HEARTBEAT_INTERVAL" default:"15s"`
+
+	// Native ETH balances for ?include_native=true on portfolios, read via eth_getBalance and cached briefly
+	NativeBalanceEnabled  bool          `envconfig:"API_NATIVE_BALANCE_ENABLED" default:"false"`
+	NativeBalanceCacheTTL time.Duration `envconfig:"API_NATIVE_BALANCE_CACHE_TTL" default:"15s"`
+
+	// Addresses left out of holder rankings and counts unless ?include_excluded=true; burn addresses by default.
+	// HolderExcludeToken also leaves out each token's own contract.
+	HolderExclusions   []string `envconfig:"API_HOLDER_EXCLUSIONS" default:"0x0000000000000000000000000000000000000000,0x000000000000000000000000000000000000dEaD"`
+	HolderExcludeToken bool     `envconfig:"API_HOLDER_EXCLUDE_TOKEN" default:"true"`
+
+	// Tokens whose holder count, recorded by the indexer with each holder snapshot, is at least this
+	// are served that count marked approximate unless ?exact=true; 0 always counts exactly
+	HolderCountApproxMin int64 `envconfig:"API_HOLDER_COUNT_APPROX_MIN" default:"1000000"`
+
+	// Page sizes of list endpoints: DefaultPageSize when a request sets no limit and at most MaxPageSize.
+	// EndpointMaxPageSize caps single endpoints as endpoint:size pairs, e.g. holders:200,transfers:500
+	// (endpoints: transfers, holders, tokens, eth_transfers, swaps, watchlists, entities, contract_events).
+	DefaultPageSize     int            `envconfig:"API_DEFAULT_PAGE_SIZE" default:"100"`
+	MaxPageSize         int            `envconfig:"API_MAX_PAGE_SIZE" default:"1000"`
+	EndpointMaxPageSize map[string]int `envconfig:"API_ENDPOINT_MAX_PAGE_SIZE"`
+
+	// Transfer, token, holder and stats reads retry transient database failures with exponential backoff.
+	// After DBBreakerThreshold consecutive failures they stop reaching the database for DBBreakerCooldown,
+	// serving copies of cached responses kept for StaleCacheTTL, or 503 with Retry-After without one.
+	// A threshold of 0 disables retries and circuit breaking.
+	DBRetries          int           `envconfig:"API_DB_RETRIES" default:"2"`
+	DBRetryBackoff     time.Duration `envconfig:"API_DB_RETRY_BACKOFF" default:"100ms"`
+	DBBreakerThreshold int           `envconfig:"API_DB_BREAKER_THRESHOLD" default:"5"`
+	DBBreakerCooldown  time.Duration `envconfig:"API_DB_BREAKER_COOLDOWN" default:"10s"`
+	StaleCacheTTL      time.Duration `envconfig:"API_STALE_CACHE_TTL" default:"1h"`
+
+	// Wallet transfer exports are written to this object store URL, in the ARCHIVE_URL format and with
+	// the ARCHIVE_S3_* credentials (empty leaves the export routes unregistered). ExportWorkers exports
+	// run at once per API instance.
+	ExportURL     string `envconfig:"API_EXPORT_URL"`
+	ExportWorkers int    `envconfig:"API_EXPORT_WORKERS" default:"2"`
+
+	// Bearer token for /api/v1/admin routes; empty leaves the admin routes unregistered
+	AdminToken string `envconfig:"API_ADMIN_TOKEN"`
+
+	// Tokens the API serves: only ExposedTokens (allow-list) or all but HiddenTokens (deny-list), at
+	// most one of which may be set. Requests about other tokens get 404 unless they carry AdminToken.
+	ExposedTokens []string `envconfig:"API_EXPOSED_TOKENS"`
+	HiddenTokens  []string `envconfig:"API_HIDDEN_TOKENS"`
+}
+
+// TrustedProxyPrefixes returns the trusted proxies as prefixes; a bare IP is a single-address prefix
+func (c *APIConfig) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.TrustedProxies))
+	for _, entry := range c.TrustedProxies {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid API_TRUSTED_PROXIES entry %q: %w", entry, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API_TRUSTED_PROXIES entry %q: %w", entry, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
 }
 
 // IndexerConfig holds indexer-specific settings
@@ -77,23 +304,214 @@ type IndexerConfig struct {
 	BackfillBatchSize  int           `envconfig:"INDEXER_BACKFILL_BATCH_SIZE" default:"1000"`
 	WorkerCount        int           `envconfig:"INDEXER_WORKER_COUNT" default:"4"`
 
+	// Workers scale from WorkerCount at the chain head up to WorkerCountMax at AutoscaleLag blocks
+	// behind, first indexing tokens in parallel and then fetching ranges ahead (max <= WorkerCount disables)
+	WorkerCountMax int   `envconfig:"INDEXER_WORKER_COUNT_MAX" default:"0"`
+	AutoscaleLag   int64 `envconfig:"INDEXER_AUTOSCALE_LAG" default:"10000"`
+
+	// Fetched block ranges queued between the fetch and store stages, so RPC fetches of the next
+	// ranges overlap storing the current one; bounds the fetched results held in memory
+	PipelineDepth int `envconfig:"INDEXER_PIPELINE_DEPTH" default:"2"`
+
+	// Block newly configured tokens start indexing from; 0 starts at genesis
+	StartBlock int64 `envconfig:"INDEXER_START_BLOCK" default:"0"`
+
+	// Backfill ranges fetched and stored at once
+	BackfillConcurrency int `envconfig:"INDEXER_BACKFILL_CONCURRENCY" default:"4"`
+
+	// Backfill throttling, so `chain-indexer backfill` leaves RPC and database capacity to live indexing
+	// (0 disables each): blocks backfilled per minute and RPC requests the backfill keeps in flight.
+	// A "low" priority backfill also pauses while live indexing of the configured tokens is more
+	// than a batch behind; "normal" never waits.
+	BackfillBlocksPerMinute  int64  `envconfig:"INDEXER_BACKFILL_BLOCKS_PER_MINUTE" default:"0"`
+	BackfillRPCMaxConcurrent int    `envconfig:"INDEXER_BACKFILL_RPC_MAX_CONCURRENT" default:"0"`
+	BackfillPriority         string `envconfig:"INDEXER_BACKFILL_PRIORITY" default:"normal"`
+
+	// Fetch all tokens with one getLogs call per range instead of one call per token
+	CombinedFetch bool `envconfig:"INDEXER_COMBINED_FETCH" default:"false"`
+
+	// Caught-up tokens without transfers for IdleAfter (0 disables) skip polls, backing off
+	// exponentially from twice the poll interval up to IdlePollMax; due idle tokens share one
+	// combined query, and a transfer returns a token to every poll
+	IdleAfter   time.Duration `envconfig:"INDEXER_IDLE_AFTER" default:"0"`
+	IdlePollMax time.Duration `envconfig:"INDEXER_IDLE_POLL_MAX" default:"5m"`
+
+	// Catch-up mode while the lowest token checkpoint is more than CatchUpThreshold blocks behind
+	// the chain head (0 disables): progress is reported in /status and metrics and logged every
+	// CatchUpLogInterval
+	CatchUpThreshold   int64         `envconfig:"INDEXER_CATCHUP_THRESHOLD" default:"1000"`
+	CatchUpLogInterval time.Duration `envconfig:"INDEXER_CATCHUP_LOG_INTERVAL" default:"30s"`
+
+	// /health on the metrics port returns 503 when the RPC node or database does not answer, after
+	// HealthMaxFailures failed indexing passes in a row (0 disables), when no pass finished within
+	// HealthStallAfter (0 disables), or while more than HealthMaxLag blocks behind (0 disables)
+	// without a checkpoint advancing within HealthStallAfter
+	HealthMaxFailures int           `envconfig:"INDEXER_HEALTH_MAX_FAILURES" default:"3"`
+	HealthStallAfter  time.Duration `envconfig:"INDEXER_HEALTH_STALL_AFTER" default:"10m"`
+	HealthMaxLag      int64         `envconfig:"INDEXER_HEALTH_MAX_LAG" default:"300"`
+
+	// A block range of a token failing RangeMaxRetries times in a row (0 disables) is recorded in
+	// failed_ranges for repair; FailedRangePolicy "halt" keeps retrying it, "skip" moves the
+	// checkpoint past it
+	RangeMaxRetries   int    `envconfig:"INDEXER_RANGE_MAX_RETRIES" default:"5"`
+	FailedRangePolicy string `envconfig:"INDEXER_FAILED_RANGE_POLICY" default:"halt"`
+
+	// A token whose indexing panics is skipped for QuarantineCooldown while the others carry on
+	// (0 retries it on the next poll)
+	QuarantineCooldown time.Duration `envconfig:"INDEXER_QUARANTINE_COOLDOWN" default:"10m"`
+
+	// How often the set of deactivated tokens is re-read from the database
+	ActiveTokensRefresh time.Duration `envconfig:"INDEXER_ACTIVE_TOKENS_REFRESH" default:"1m"`
+
+	// Archive every fetched log in raw_logs so `chain-indexer replay` can re-decode without RPC
+	StoreRawLogs bool `envconfig:"INDEXER_STORE_RAW_LOGS" default:"false"`
+
+	// EnrichInitiator stores each transfer's transaction sender (tx.origin) and method selector,
+	// looked up in batch while indexing
+	EnrichInitiator bool `envconfig:"INDEXER_ENRICH_INITIATOR" default:"false"`
+
 	// Tokens to index (comma-separated addresses)
 	TokenAddresses []string `envconfig:"INDEXER_TOKEN_ADDRESSES" default:"0xdAC17F958D2ee523a2206206994597C13D831ec7,0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"`
+
+	// Uniswap V2/V3 pools whose Swap events are indexed (comma-separated addresses, empty disables)
+	DexPools []string `envconfig:"INDEXER_DEX_POOLS"`
+
+	// Native ETH transfer capture from block traces: "debug" (debug_traceBlockByNumber) or
+	// "trace" (trace_block); empty disables. A fresh start begins at TraceStartBlock, or at
+	// the chain head when it is 0.
+	TraceMethod     string `envconfig:"INDEXER_TRACE_METHOD"`
+	TraceStartBlock int64  `envconfig:"INDEXER_TRACE_START_BLOCK" default:"0"`
+
+	// Periodic snapshots of each token's top holders, diffed by the holder changes endpoint (0 interval disables)
+	HolderSnapshotInterval  time.Duration `envconfig:"INDEXER_HOLDER_SNAPSHOT_INTERVAL" default:"1h"`
+	HolderSnapshotSize      int           `envconfig:"INDEXER_HOLDER_SNAPSHOT_SIZE" default:"1000"`
+	HolderSnapshotRetention time.Duration `envconfig:"INDEXER_HOLDER_SNAPSHOT_RETENTION" default:"720h"`
+
+	// Periodic velocity and dormancy aggregation for the advanced stats endpoint (0 interval disables):
+	// velocity is the volume over VelocityWindow divided by the average supply, and dormant supply
+	// the balance of addresses without a transfer for DormantAfter. Transactions with more than
+	// AirdropFanOut transfers from one sender in the window are counted as fan-outs.
+	AdvancedStatsInterval time.Duration `envconfig:"INDEXER_ADVANCED_STATS_INTERVAL" default:"6h"`
+	VelocityWindow        time.Duration `envconfig:"INDEXER_VELOCITY_WINDOW" default:"720h"`
+	DormantAfter          time.Duration `envconfig:"INDEXER_DORMANT_AFTER" default:"8760h"`
+	AirdropFanOut         int           `envconfig:"INDEXER_AIRDROP_FANOUT" default:"10"`
+
+	// Periodic analysis flagging wash trades (0 interval disables): runs of at least
+	// WashTradeMinTransfers transfers between two addresses alternating direction, each within
+	// WashTradeWindow of the previous and within WashTradeTolerance (a fraction) of its value.
+	// The first run looks back WashTradeLookback.
+	WashTradeInterval     time.Duration `envconfig:"INDEXER_WASH_TRADE_INTERVAL" default:"10m"`
+	WashTradeWindow       time.Duration `envconfig:"INDEXER_WASH_TRADE_WINDOW" default:"10m"`
+	WashTradeTolerance    float64       `envconfig:"INDEXER_WASH_TRADE_TOLERANCE" default:"0.05"`
+	WashTradeMinTransfers int           `envconfig:"INDEXER_WASH_TRADE_MIN_TRANSFERS" default:"2"`
+	WashTradeLookback     time.Duration `envconfig:"INDEXER_WASH_TRADE_LOOKBACK" default:"24h"`
+
+	// How often token names, symbols and decimals are re-read from the chain, so a proxy upgrade
+	// changing them is picked up and recorded in the token's metadata history (0 disables)
+	MetadataRefreshInterval time.Duration `envconfig:"INDEXER_METADATA_REFRESH_INTERVAL" default:"24h"`
+
+	// Token address -> block confirmations, for tokens that should wait longer or shorter than
+	// BlockConfirmations, the chain's default (token:confirmations,token:confirmations)
+	TokenConfirmations map[string]int `envconfig:"INDEXER_TOKEN_CONFIRMATIONS"`
+}
+
+// ConfirmationsFor returns the block confirmations a token waits for before its transfers are indexed:
+// its INDEXER_TOKEN_CONFIRMATIONS entry if there is one, otherwise INDEXER_BLOCK_CONFIRMATIONS
+func (c IndexerConfig) ConfirmationsFor(tokenAddress string) int {
+	for addr, confirmations := range c.TokenConfirmations {
+		if strings.EqualFold(addr, tokenAddress) {
+			return confirmations
+		}
+	}
+	return c.BlockConfirmations
+}
+
+// PriceConfig holds price oracle settings used for USD enrichment
+type PriceConfig struct {
+	Enabled  bool          `envconfig:"PRICE_ENABLED" default:"false"`
+	Provider string        `envconfig:"PRICE_PROVIDER" default:"coingecko"` // coingecko or chainlink
+	CacheTTL time.Duration `envconfig:"PRICE_CACHE_TTL" default:"5m"`
+
+	CoinGeckoURL    string `envconfig:"PRICE_COINGECKO_URL" default:"https://api.coingecko.com/api/v3"`
+	CoinGeckoAPIKey string `envconfig:"PRICE_COINGECKO_API_KEY"`
+
+	// Token address -> Chainlink TOKEN/USD feed address (token:feed,token:feed)
+	ChainlinkFeeds map[string]string `envconfig:"PRICE_CHAINLINK_FEEDS"`
+}
+
+// AlertConfig holds settings for the alert notification drivers
+type AlertConfig struct {
+	WebhookTimeout time.Duration `envconfig:"ALERT_WEBHOOK_TIMEOUT" default:"5s"`
+
+	// SMTP server for the email driver (empty host disables email alerts)
+	SMTPHost     string `envconfig:"ALERT_SMTP_HOST"`
+	SMTPPort     int    `envconfig:"ALERT_SMTP_PORT" default:"587"`
+	SMTPUsername string `envconfig:"ALERT_SMTP_USERNAME"`
+	SMTPPassword string `envconfig:"ALERT_SMTP_PASSWORD"`
+	SMTPFrom     string `envconfig:"ALERT_SMTP_FROM" default:"alerts@chain-indexer.local"`
+}
+
+// ScreeningConfig holds deny list refresh settings
+type ScreeningConfig struct {
+	// Deny lists refreshed by the indexer as name=source pairs (comma-separated, empty disables);
+	// a source is an http(s) URL or a file path
+	Lists           []string      `envconfig:"SCREENING_LISTS"`
+	RefreshInterval time.Duration `envconfig:"SCREENING_REFRESH_INTERVAL" default:"24h"`
+}
+
+// Sources returns the configured deny lists as list name -> source
+func (c *ScreeningConfig) Sources() (map[string]string, error) {
+	sources := make(map[string]string, len(c.Lists))
+	for _, pair := range c.Lists {
+		name, source, ok := strings.Cut(pair, "=")
+		name, source = strings.TrimSpace(name), strings.TrimSpace(source)
+		if !ok || name == "" || source == "" {
+			return nil, fmt.Errorf("invalid SCREENING_LISTS entry %q, expected name=source", pair)
+		}
+		sources[name] = source
+	}
+	return sources, nil
+}
+
+// ArchiveConfig holds settings for exporting transfers as Parquet files to object storage
+type ArchiveConfig struct {
+	// s3://bucket/prefix, gs://bucket/prefix or file:///path (empty disables archival)
+	URL string `envconfig:"ARCHIVE_URL"`
+
+	// Blocks per archive file; ranges are aligned to multiples of this size
+	RangeSize int64         `envconfig:"ARCHIVE_RANGE_SIZE" default:"100000"`
+	Interval  time.Duration `envconfig:"ARCHIVE_INTERVAL" default:"1h"`
+	Timeout   time.Duration `envconfig:"ARCHIVE_TIMEOUT" default:"5m"`
+
+	// Object storage credentials (GCS uses HMAC keys)
+	S3Endpoint      string `envconfig:"ARCHIVE_S3_ENDPOINT"` // S3-compatible endpoint; default is AWS for ARCHIVE_S3_REGION
+	S3Region        string `envconfig:"ARCHIVE_S3_REGION" default:"us-east-1"`
+	AccessKeyID     string `envconfig:"ARCHIVE_ACCESS_KEY_ID"`
+	SecretAccessKey string `envconfig:"ARCHIVE_SECRET_ACCESS_KEY"`
 }
 
 // LogConfig holds logging settings
 type LogConfig struct {
 	Level  string `envconfig:"LOG_LEVEL" default:"info"`
-	Format string `envconfig:"LOG_FORMAT" default:"json"`
+	Format string `envconfig:"LOG_FORMAT" default:"json"` // json or console
+
+	// Component levels overriding Level, such as "ethereum=debug,database=warn"
+	Levels string `envconfig:"LOG_LEVELS"`
+
+	// Debug lines with the same message are sampled past DebugSampleInitial per second, keeping
+	// every DebugSampleThereafter-th (0 DebugSampleInitial disables sampling)
+	DebugSampleInitial    int `envconfig:"LOG_DEBUG_SAMPLE_INITIAL" default:"100"`
+	DebugSampleThereafter int `envconfig:"LOG_DEBUG_SAMPLE_THEREAFTER" default:"100"`
 }
 
-// Load loads configuration from environment variables
-func Load() (*Config, error) {
-	var cfg Config
-	if err := envconfig.Process("", &cfg); err != nil {
-		return nil, err
-	}
-	return &cfg, nil
+// StartupConfig holds how commands connect to their dependencies when they start
+type StartupConfig struct {
+	// Connecting to PostgreSQL, Redis and the RPC node is tried this many times before a command
+	// gives up on it, so services started together with their dependencies wait for them
+	ConnectAttempts int `envconfig:"STARTUP_CONNECT_ATTEMPTS" default:"5"`
+
+	// Wait after the first failed attempt, doubled after each further one up to 30s
+	ConnectBackoff time.Duration `envconfig:"STARTUP_CONNECT_BACKOFF" default:"1s"`
 }
 
 // DSN returns the PostgreSQL connection string
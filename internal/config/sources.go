@@ -0,0 +1,181 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// ConfigFileEnv lists configuration files (comma-separated) read beneath the environment
+const ConfigFileEnv = "CONFIG_FILE"
+
+// secretFileSuffix marks a setting read from a file: DB_PASSWORD_FILE=/run/secrets/db sets
+// DB_PASSWORD to the contents of /run/secrets/db, as Docker and Kubernetes mount secrets
+const secretFileSuffix = "_FILE"
+
+// Sources are the configuration layers above the built-in defaults, lowest precedence first:
+// the CONFIG_FILE files, Files, the environment and Overrides. Every layer uses the environment
+// variable names, and in every layer a NAME_FILE setting reads NAME from a file.
+type Sources struct {
+	Files     []string          // KEY=VALUE files, later files overriding earlier ones
+	Overrides map[string]string // Settings given on the command line
+}
+
+// Set adds a KEY=VALUE override
+func (s *Sources) Set(pair string) error {
+	key, value, ok := strings.Cut(pair, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("invalid setting %q (want KEY=VALUE)", pair)
+	}
+	if s.Overrides == nil {
+		s.Overrides = make(map[string]string)
+	}
+	s.Overrides[strings.TrimSpace(key)] = value
+	return nil
+}
+
+// Load loads configuration from the CONFIG_FILE files and environment variables
+func Load() (*Config, error) {
+	return LoadFrom(Sources{})
+}
+
+// LoadFrom loads configuration from sources layered over the defaults
+func LoadFrom(sources Sources) (*Config, error) {
+	settings, err := resolve(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	// envconfig reads the process environment, so the merged settings are put there while it
+	// runs and the environment is restored afterwards
+	restore := setEnv(settings)
+	defer restore()
+
+	var cfg Config
+	if err := envconfig.Process("", &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// resolve merges the layers of sources into one set of settings
+func resolve(sources Sources) (map[string]string, error) {
+	var files []string
+	for _, path := range strings.Split(os.Getenv(ConfigFileEnv), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			files = append(files, path)
+		}
+	}
+	files = append(files, sources.Files...)
+
+	settings := make(map[string]string)
+	for _, path := range files {
+		layer, err := readSettingsFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := merge(settings, layer, path); err != nil {
+			return nil, err
+		}
+	}
+
+	env := make(map[string]string)
+	for _, pair := range os.Environ() {
+		if key, value, ok := strings.Cut(pair, "="); ok {
+			env[key] = value
+		}
+	}
+	if err := merge(settings, env, "environment"); err != nil {
+		return nil, err
+	}
+
+	if err := merge(settings, sources.Overrides, "command line"); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// merge copies a layer's settings over dst, reading NAME_FILE settings into NAME. A layer may
+// not set both NAME and NAME_FILE.
+func merge(dst, layer map[string]string, source string) error {
+	for key, value := range layer {
+		name, isSecretFile := strings.CutSuffix(key, secretFileSuffix)
+		if !isSecretFile || key == ConfigFileEnv || name == "" {
+			dst[key] = value
+			continue
+		}
+		if _, ok := layer[name]; ok {
+			return fmt.Errorf("%s sets both %s and %s", source, name, key)
+		}
+		secret, err := os.ReadFile(value)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", key, err)
+		}
+		dst[name] = strings.TrimRight(string(secret), "\r\n")
+	}
+	return nil
+}
+
+// readSettingsFile reads KEY=VALUE lines, as in .env.example. Blank lines and lines starting with
+// # are skipped, an export prefix is allowed and values may be quoted.
+func readSettingsFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	settings := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		settings[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return settings, nil
+}
+
+// setEnv sets the process environment to settings and returns a function restoring it
+func setEnv(settings map[string]string) func() {
+	previous := make(map[string]*string)
+	for key, value := range settings {
+		old, ok := os.LookupEnv(key)
+		if ok && old == value {
+			continue
+		}
+		if ok {
+			previous[key] = &old
+		} else {
+			previous[key] = nil
+		}
+		os.Setenv(key, value)
+	}
+
+	return func() {
+		for key, old := range previous {
+			if old == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *old)
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	base := writeFile(t, "base.env", `
+# Shared settings
+ETH_CHAIN_ID=5
+DB_HOST=base-db
+DB_NAME=base
+export LOG_LEVEL="debug"
+`)
+	profile := writeFile(t, "production.env", "DB_HOST=prod-db\nDB_NAME=prod\n")
+	t.Setenv(ConfigFileEnv, base)
+	t.Setenv("DB_NAME", "from-env")
+	t.Setenv("DB_USER", "from-env")
+
+	sources := Sources{Files: []string{profile}}
+	if err := sources.Set("DB_USER=from-flag"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFrom(sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// defaults < CONFIG_FILE < -config files < environment < -set
+	if cfg.Ethereum.RPCURL != "http://localhost:8545" {
+		t.Errorf("expected the default RPC URL, got %s", cfg.Ethereum.RPCURL)
+	}
+	if cfg.Ethereum.ChainID != 5 || cfg.Log.Level != "debug" {
+		t.Errorf("expected the base file's chain ID and quoted level, got %d and %s", cfg.Ethereum.ChainID, cfg.Log.Level)
+	}
+	if cfg.Database.Host != "prod-db" {
+		t.Errorf("expected the later file to win, got %s", cfg.Database.Host)
+	}
+	if cfg.Database.Name != "from-env" {
+		t.Errorf("expected the environment over the files, got %s", cfg.Database.Name)
+	}
+	if cfg.Database.User != "from-flag" {
+		t.Errorf("expected the override over the environment, got %s", cfg.Database.User)
+	}
+
+	// File settings are not left in the environment
+	if _, ok := os.LookupEnv("DB_HOST"); ok {
+		t.Error("expected DB_HOST to be unset after loading")
+	}
+	if os.Getenv("DB_USER") != "from-env" {
+		t.Errorf("expected DB_USER to be restored, got %s", os.Getenv("DB_USER"))
+	}
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	secret := writeFile(t, "db_password", "s3cret\n")
+	apiKey := writeFile(t, "coingecko", "cg-key")
+
+	// A secret file in the environment overrides a plain value in a config file
+	t.Setenv(ConfigFileEnv, writeFile(t, "app.env", "DB_PASSWORD=plain\nPRICE_COINGECKO_API_KEY_FILE="+apiKey+"\n"))
+	t.Setenv("DB_PASSWORD_FILE", secret)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Database.Password != "s3cret" {
+		t.Errorf("expected the password from its file without the newline, got %q", cfg.Database.Password)
+	}
+	if cfg.Price.CoinGeckoAPIKey != "cg-key" {
+		t.Errorf("expected the API key from its file, got %q", cfg.Price.CoinGeckoAPIKey)
+	}
+
+	// A plain override beats the secret file
+	sources := Sources{}
+	_ = sources.Set("DB_PASSWORD=override")
+	if cfg, err = LoadFrom(sources); err != nil || cfg.Database.Password != "override" {
+		t.Errorf("expected the override, got %v (%v)", cfg, err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	secret := writeFile(t, "secret", "x")
+
+	t.Run("both value and file in one layer", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "x")
+		t.Setenv("DB_PASSWORD_FILE", secret)
+		if _, err := Load(); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("missing secret file", func(t *testing.T) {
+		t.Setenv("REDIS_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+		if _, err := Load(); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("missing config file", func(t *testing.T) {
+		if _, err := LoadFrom(Sources{Files: []string{filepath.Join(t.TempDir(), "missing.env")}}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("malformed config file", func(t *testing.T) {
+		if _, err := LoadFrom(Sources{Files: []string{writeFile(t, "bad.env", "DB_HOST\n")}}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("malformed override", func(t *testing.T) {
+		if err := (&Sources{}).Set("DB_HOST"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChainFamily identifies a family of chains sharing an address format
+type ChainFamily string
+
+// Chain families whose addresses can be parsed
+const (
+	ChainFamilyEVM  ChainFamily = "evm"  // 0x-prefixed 20-byte hex, normalized to lowercase
+	ChainFamilyUTXO ChainFamily = "utxo" // Bitcoin-style base58 or bech32, bech32 normalized to lowercase
+)
+
+// base58Alphabet is the digit set of base58 addresses, which leaves out 0, O, I and l
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// bech32Charset is the data character set of bech32 addresses
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// ErrInvalidAddress is wrapped by the errors of ParseAddress
+var ErrInvalidAddress = errors.New("invalid address")
+
+// Address is a validated, normalized address on a chain family. The zero value is no address.
+// Addresses compare equal when they are the same address, so they can key maps.
+//
+// JSON and SQL values are the normalized string, as raw address strings are today; decoding and
+// scanning read EVM addresses, the only family stored so far.
+type Address struct {
+	family ChainFamily
+	value  string
+}
+
+// ParseAddress validates s as an address of family and normalizes it
+func ParseAddress(family ChainFamily, s string) (Address, error) {
+	switch family {
+	case ChainFamilyEVM:
+		hexDigits, ok := strings.CutPrefix(s, "0x")
+		if !ok || len(hexDigits) != 40 {
+			return Address{}, fmt.Errorf("%w: %q is not 0x followed by 40 hex digits", ErrInvalidAddress, s)
+		}
+		if _, err := hex.DecodeString(hexDigits); err != nil {
+			return Address{}, fmt.Errorf("%w: %q is not 0x followed by 40 hex digits", ErrInvalidAddress, s)
+		}
+		return Address{family: family, value: strings.ToLower(s)}, nil
+	case ChainFamilyUTXO:
+		// Checksums are not verified: this checks the shape of the address only
+		if hrp, data, ok := strings.Cut(strings.ToLower(s), "1"); ok && (hrp == "bc" || hrp == "tb") {
+			if (s == strings.ToLower(s) || s == strings.ToUpper(s)) && len(data) >= 8 && len(data) <= 87 && containsOnly(data, bech32Charset) {
+				return Address{family: family, value: strings.ToLower(s)}, nil
+			}
+		} else if len(s) >= 26 && len(s) <= 35 && containsOnly(s, base58Alphabet) {
+			return Address{family: family, value: s}, nil
+		}
+		return Address{}, fmt.Errorf("%w: %q is not a base58 or bech32 address", ErrInvalidAddress, s)
+	default:
+		return Address{}, fmt.Errorf("%w: unknown chain family %q", ErrInvalidAddress, family)
+	}
+}
+
+// containsOnly reports whether every character of s is in chars
+func containsOnly(s, chars string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune(chars, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseEVMAddress validates s as an EVM address and lowercases it
+func ParseEVMAddress(s string) (Address, error) {
+	return ParseAddress(ChainFamilyEVM, s)
+}
+
+// String returns the normalized address, or "" for the zero value
+func (a Address) String() string {
+	return a.value
+}
+
+// Family returns the chain family of the address
+func (a Address) Family() ChainFamily {
+	return a.family
+}
+
+// IsZero reports whether a is the zero value, not an address
+func (a Address) IsZero() bool {
+	return a.value == ""
+}
+
+// MarshalJSON encodes the address as its normalized string, or null for the zero value
+func (a Address) MarshalJSON() ([]byte, error) {
+	if a.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(a.value)
+}
+
+// UnmarshalJSON decodes an EVM address string; null leaves the zero value
+func (a *Address) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*a = Address{}
+		return nil
+	}
+	parsed, err := ParseEVMAddress(*s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value stores the address as its normalized string, or NULL for the zero value
+func (a Address) Value() (driver.Value, error) {
+	if a.IsZero() {
+		return nil, nil
+	}
+	return a.value, nil
+}
+
+// Scan reads an EVM address stored as text; NULL leaves the zero value
+func (a *Address) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*a = Address{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into an address", src)
+	}
+	parsed, err := ParseEVMAddress(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
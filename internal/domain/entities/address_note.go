@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// AddressNote is a tenant's private note on an address, such as an analyst's bookmark
+type AddressNote struct {
+	TenantID  int64     `db:"tenant_id"`
+	Address   Address   `db:"address"`
+	Note      string    `db:"note"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"math/big"
+	"time"
+)
+
+// Alert rule directions, relative to the rule's address
+const (
+	AlertDirectionIn  = "in"  // the address receives
+	AlertDirectionOut = "out" // the address sends
+	AlertDirectionAny = "any"
+)
+
+// AlertRule describes transfers that should trigger a notification.
+// All set conditions must hold for a transfer to match.
+type AlertRule struct {
+	ID           int64
+	Name         string
+	TokenAddress *string  // nil matches every token
+	Address      *string  // watched address; nil matches transfers between any addresses
+	Direction    string   // AlertDirectionIn, AlertDirectionOut or AlertDirectionAny
+	MinValue     *big.Int // inclusive threshold on raw value; nil disables
+
+	// Counterparties restricts matches to transfers with one of these addresses on the other side.
+	// Without an Address, they are matched against the receiver (in), sender (out) or either (any).
+	Counterparties []string
+
+	Channel   string // notification driver name, e.g. "webhook" or "email"
+	Target    string // driver-specific destination: URL or email address
+	TenantID  *int64 // owning tenant; nil for rules created without an API key
+	CreatedAt time.Time
+}
+
+// Matches reports whether a transfer satisfies the rule
+func (r *AlertRule) Matches(t *Transfer) bool {
+	if r.TokenAddress != nil && t.TokenAddress != *r.TokenAddress {
+		return false
+	}
+	if r.MinValue != nil && (t.Value == nil || t.Value.Cmp(r.MinValue) < 0) {
+		return false
+	}
+
+	if r.Address == nil {
+		if len(r.Counterparties) == 0 {
+			return true
+		}
+		switch r.Direction {
+		case AlertDirectionIn:
+			return r.isCounterparty(t.ToAddress)
+		case AlertDirectionOut:
+			return r.isCounterparty(t.FromAddress)
+		default:
+			return r.isCounterparty(t.FromAddress) || r.isCounterparty(t.ToAddress)
+		}
+	}
+
+	sends := t.FromAddress == *r.Address && r.Direction != AlertDirectionIn
+	receives := t.ToAddress == *r.Address && r.Direction != AlertDirectionOut
+	if len(r.Counterparties) == 0 {
+		return sends || receives
+	}
+	return (sends && r.isCounterparty(t.ToAddress)) || (receives && r.isCounterparty(t.FromAddress))
+}
+
+func (r *AlertRule) isCounterparty(addr string) bool {
+	for _, c := range r.Counterparties {
+		if c == addr {
+			return true
+		}
+	}
+	return false
+}
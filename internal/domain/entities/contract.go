@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Contract is a contract whose ABI events are decoded into ContractEvent records
+type Contract struct {
+	Address   string    `db:"address"`
+	Name      string    `db:"name"`
+	ABI       string    `db:"abi"` // JSON ABI as uploaded
+	Events    []string  // Names of the ABI events indexed
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// ContractEvent is a decoded log of an event selected from a contract's ABI
+type ContractEvent struct {
+	ID              int64           `db:"id"`
+	ContractAddress string          `db:"contract_address"`
+	EventName       string          `db:"event_name"`
+	TxHash          string          `db:"tx_hash"`
+	LogIndex        int             `db:"log_index"`
+	BlockNumber     int64           `db:"block_number"`
+	BlockTimestamp  time.Time       `db:"block_timestamp"`
+	Topics          []string        // topic0, the event signature, then the indexed arguments
+	Args            json.RawMessage // Decoded arguments as a JSON object keyed by argument name
+	CreatedAt       time.Time       `db:"created_at"`
+}
+
+// ContractEventFilter contains filters for querying a contract's events
+type ContractEventFilter struct {
+	ContractAddress string
+	EventName       *string
+	Args            map[string]json.RawMessage // Arguments that must equal the given JSON values
+	Conditions      []ContractArgCondition     // Other comparisons, all of which must hold
+	Limit           int
+	Offset          int
+}
+
+// ContractArgOperator is one of the comparisons allowed in contract event argument filters
+type ContractArgOperator string
+
+// Contract event argument operators
+const (
+	ContractArgEq  ContractArgOperator = "="
+	ContractArgNe  ContractArgOperator = "!="
+	ContractArgGt  ContractArgOperator = ">"
+	ContractArgGte ContractArgOperator = ">="
+	ContractArgLt  ContractArgOperator = "<"
+	ContractArgLte ContractArgOperator = "<="
+)
+
+// ContractArgOperators are the allowed operators, two-character ones first so that a clause is
+// split at the longest operator
+var ContractArgOperators = []ContractArgOperator{
+	ContractArgNe, ContractArgGte, ContractArgLte, ContractArgEq, ContractArgGt, ContractArgLt,
+}
+
+// IsRange reports whether the operator orders values, which only integer arguments support
+func (op ContractArgOperator) IsRange() bool {
+	return op == ContractArgGt || op == ContractArgGte || op == ContractArgLt || op == ContractArgLte
+}
+
+// ContractArgCondition compares a decoded argument with a JSON value. Range operators compare
+// integers numerically; = and != compare values as stored.
+type ContractArgCondition struct {
+	Arg      string
+	Operator ContractArgOperator
+	Value    json.RawMessage
+}
@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// DenyListEntry is an address on an imported deny list, such as a sanctions list
+type DenyListEntry struct {
+	ListName string    `db:"list_name"`
+	Address  string    `db:"address"`
+	Label    string    `db:"label"` // Optional description from the source, e.g. the sanctioned entity
+	AddedAt  time.Time `db:"added_at"`
+}
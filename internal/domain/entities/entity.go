@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// Entity categories, the kinds of counterparty whose token flows are reported
+const (
+	EntityCategoryExchange = "exchange"
+	EntityCategoryBridge   = "bridge"
+)
+
+// EntityCategories lists the supported entity categories; an entity may also have none
+var EntityCategories = []string{EntityCategoryExchange, EntityCategoryBridge}
+
+// Entity is a named group of addresses controlled by one party, such as a fund or an exchange,
+// whose holdings and activity are reported as one
+type Entity struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Category  string    `db:"category"` // One of EntityCategories, or empty
+	Addresses []string  `db:"-"`        // Lowercase, ordered by when they were added
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
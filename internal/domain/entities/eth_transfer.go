@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"math/big"
+	"time"
+)
+
+// Call types of an EthTransfer
+const (
+	EthCallTypeCall         = "call"
+	EthCallTypeCreate       = "create"
+	EthCallTypeSelfDestruct = "selfdestruct"
+)
+
+// EthTransfer represents a native ETH movement captured from a block trace: either a
+// transaction's own value or an internal call that moved ETH
+type EthTransfer struct {
+	ID     int64  `db:"id"`
+	TxHash string `db:"tx_hash"`
+	// TraceAddress is the call's path in the transaction's call tree, as dash-separated
+	// subcall indexes; empty for the top-level call
+	TraceAddress   string    `db:"trace_address"`
+	BlockNumber    int64     `db:"block_number"`
+	BlockTimestamp time.Time `db:"block_timestamp"`
+	FromAddress    string    `db:"from_address"`
+	ToAddress      string    `db:"to_address"`
+	Value          *big.Int  `db:"-"` // Handled separately due to NUMERIC type
+	ValueString    string    `db:"value"`
+	CallType       string    `db:"call_type"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// IsInternal reports whether the transfer was made by a contract rather than by the transaction itself
+func (t EthTransfer) IsInternal() bool {
+	return t.TraceAddress != ""
+}
+
+// EthTransferFilter contains filters for querying ETH transfers
+type EthTransferFilter struct {
+	Address     *string // either sender or receiver
+	FromAddress *string
+	ToAddress   *string
+	Limit       int
+	Offset      int
+}
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// FailedRange is a block range of a token that kept failing to index, kept for later repair
+type FailedRange struct {
+	ID            int64      `db:"id"`
+	TokenAddress  string     `db:"token_address"`
+	FromBlock     int64      `db:"from_block"`
+	ToBlock       int64      `db:"to_block"`
+	Attempts      int        `db:"attempts"`
+	LastError     string     `db:"last_error"`
+	Skipped       bool       `db:"skipped"` // The checkpoint was moved past the range without its transfers
+	FirstFailedAt time.Time  `db:"first_failed_at"`
+	LastFailedAt  time.Time  `db:"last_failed_at"`
+	ResolvedAt    *time.Time `db:"resolved_at"`
+}
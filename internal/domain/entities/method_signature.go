@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// MethodSignature names the function behind a 4-byte selector so transfers can be labeled by the
+// call that caused them
+type MethodSignature struct {
+	Selector  string    `db:"selector"` // Lowercase 0x-prefixed, e.g. 0xa9059cbb
+	Name      string    `db:"name"`     // Function signature, e.g. transfer(address,uint256)
+	Label     string    `db:"label"`    // What the call does, e.g. swap, bridge or transferFrom
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+// RawLog is an event log exactly as returned by eth_getLogs, kept so that decoding can be
+// replayed from storage. Log holds the JSON encoding of the go-ethereum types.Log.
+type RawLog struct {
+	BlockNumber    int64     `db:"block_number"`
+	TxHash         string    `db:"tx_hash"`
+	LogIndex       int       `db:"log_index"`
+	Address        string    `db:"address"`
+	BlockTimestamp time.Time `db:"block_timestamp"`
+	Log            []byte    `db:"log"`
+	CreatedAt      time.Time `db:"created_at"`
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"math/big"
+	"time"
+)
+
+// DexPool represents a DEX liquidity pool whose swaps are indexed
+type DexPool struct {
+	Address       string    `db:"address"`
+	Token0Address string    `db:"token0_address"`
+	Token1Address string    `db:"token1_address"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// Swap represents a DEX Swap event.
+// Amounts are normalized to the pool's perspective for both Uniswap V2 and V3:
+// positive means the token flowed into the pool, negative means it flowed out.
+type Swap struct {
+	ID             int64     `db:"id"`
+	TxHash         string    `db:"tx_hash"`
+	LogIndex       int       `db:"log_index"`
+	BlockNumber    int64     `db:"block_number"`
+	BlockTimestamp time.Time `db:"block_timestamp"`
+	PoolAddress    string    `db:"pool_address"`
+	Protocol       string    `db:"protocol"`
+	Sender         string    `db:"sender"`
+	Recipient      string    `db:"recipient"`
+	Token0Address  string    `db:"token0_address"`
+	Token1Address  string    `db:"token1_address"`
+	Amount0        *big.Int  `db:"-"` // Handled separately due to NUMERIC type
+	Amount0String  string    `db:"amount0"`
+	Amount1        *big.Int  `db:"-"`
+	Amount1String  string    `db:"amount1"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// SwapFilter contains filters for querying swaps
+type SwapFilter struct {
+	PoolAddress *string
+	Limit       int
+	Offset      int
+}
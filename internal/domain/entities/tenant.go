@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// Tenant is a namespace with its own tracked token set, API keys, rate limit and alert rules.
+// All tenants share the indexed transfer data.
+type Tenant struct {
+	ID           int64     `db:"id"`
+	Name         string    `db:"name"`
+	RateLimitRPS int       `db:"rate_limit_rps"` // Requests per second; 0 uses API_RATE_LIMIT_RPS
+	Tokens       []string  `db:"-"`              // Lowercase token addresses, in address order
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -6,13 +6,45 @@ import (
 
 // Token represents an ERC-20 token being indexed
 type Token struct {
-	Address               string    `db:"address"`
-	Name                  string    `db:"name"`
-	Symbol                string    `db:"symbol"`
-	Decimals              int       `db:"decimals"`
-	TotalIndexedTransfers int64     `db:"total_indexed_transfers"`
-	FirstSeenBlock        *int64    `db:"first_seen_block"`
-	LastSeenBlock         *int64    `db:"last_seen_block"`
-	CreatedAt             time.Time `db:"created_at"`
-	UpdatedAt             time.Time `db:"updated_at"`
+	Address               string     `db:"address"`
+	Name                  string     `db:"name"`
+	Symbol                string     `db:"symbol"`
+	Decimals              int        `db:"decimals"`
+	TotalIndexedTransfers int64      `db:"total_indexed_transfers"`
+	FirstSeenBlock        *int64     `db:"first_seen_block"`
+	LastSeenBlock         *int64     `db:"last_seen_block"`
+	Active                bool       `db:"active"`          // Deactivated tokens are not indexed
+	DeactivatedAt         *time.Time `db:"deactivated_at"`  // When the token was last deactivated
+	MetadataSource        string     `db:"metadata_source"` // MetadataOnchain or MetadataOverride
+	CreatedAt             time.Time  `db:"created_at"`
+	UpdatedAt             time.Time  `db:"updated_at"`
+}
+
+// Token metadata sources
+const (
+	MetadataOnchain  = "onchain"  // Read from the contract
+	MetadataOverride = "override" // Set by an operator; kept when the token is re-registered
+)
+
+// TokenMetadataOverride holds the metadata fields an operator replaces. Nil fields are kept.
+type TokenMetadataOverride struct {
+	Name     *string
+	Symbol   *string
+	Decimals *int
+}
+
+// TokenMetadataChange records an on-chain metadata change of a token, such as a proxy upgrade
+// changing its decimals. BlockNumber is the chain head when the change was detected; the change
+// took effect at or before it.
+type TokenMetadataChange struct {
+	ID               int64     `db:"id"`
+	TokenAddress     string    `db:"token_address"`
+	PreviousName     string    `db:"previous_name"`
+	PreviousSymbol   string    `db:"previous_symbol"`
+	PreviousDecimals int       `db:"previous_decimals"`
+	Name             string    `db:"name"`
+	Symbol           string    `db:"symbol"`
+	Decimals         int       `db:"decimals"`
+	BlockNumber      int64     `db:"block_number"`
+	DetectedAt       time.Time `db:"detected_at"`
 }
@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// TokenDailyStats is one UTC day of rolled-up transfer statistics for a token
+type TokenDailyStats struct {
+	TokenAddress    string    `db:"token_address"`
+	Day             time.Time `db:"day"`
+	Transfers       int64     `db:"transfers"`
+	Volume          string    `db:"volume"` // Raw amount, NUMERIC as string
+	UniqueSenders   int64     `db:"unique_senders"`
+	UniqueReceivers int64     `db:"unique_receivers"`
+	NewSenders      int64     `db:"new_senders"` // Addresses sending the token for the first time
+	NewHolders      int64     `db:"new_holders"` // Addresses receiving the token for the first time
+	FirstTransferAt time.Time `db:"first_transfer_at"`
+	LastTransferAt  time.Time `db:"last_transfer_at"`
+}
+
+// HolderFirstSeen is the first receipt of a token by an address
+type HolderFirstSeen struct {
+	TokenAddress string    `db:"token_address"`
+	Address      string    `db:"address"`
+	FirstBlock   int64     `db:"first_block"`
+	FirstSeenAt  time.Time `db:"first_seen_at"`
+}
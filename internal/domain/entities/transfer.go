@@ -5,6 +5,9 @@ import (
 	"time"
 )
 
+// ZeroAddress is the sender of mints and the receiver of burns
+const ZeroAddress = "0x0000000000000000000000000000000000000000"
+
 // Transfer represents an ERC-20 Transfer event
 type Transfer struct {
 	ID             int64     `db:"id"`
@@ -17,21 +20,61 @@ type Transfer struct {
 	ToAddress      string    `db:"to_address"`
 	Value          *big.Int  `db:"-"` // Handled separately due to NUMERIC type
 	ValueString    string    `db:"value"`
+	Initiator      *string   `db:"initiator"`       // Transaction sender (tx.origin); nil unless enrichment is enabled
+	MethodSelector *string   `db:"method_selector"` // 4-byte selector of the transaction input; nil unless enrichment is enabled
 	CreatedAt      time.Time `db:"created_at"`
 }
 
 // TransferFilter contains filters for querying transfers
 type TransferFilter struct {
-	TokenAddress *string
-	FromAddress  *string
-	ToAddress    *string
-	Address      *string // matches either from or to
-	FromBlock    *int64
-	ToBlock      *int64
-	FromTime     *time.Time
-	ToTime       *time.Time
-	Limit        int
-	Offset       int
+	TokenAddress  *string
+	Tokens        []string // matches transfers of any of these tokens
+	ExcludeTokens []string // skips transfers of these tokens
+	FromAddress   *string
+	ToAddress     *string
+	Address       *string  // matches either from or to
+	Addresses     []string // matches either from or to of any of these
+	Initiator     *string  // matches the transaction sender
+	Selector      *string  // matches the transaction's 4-byte method selector
+	Method        *string  // matches transactions whose selector has this method_signatures label
+	FromBlock     *int64
+	ToBlock       *int64
+	FromTime      *time.Time
+	ToTime        *time.Time
+	MinValue      *big.Int        // inclusive lower bound on raw value
+	MaxValue      *big.Int        // inclusive upper bound on raw value
+	ExcludeZero   bool            // skip zero-value transfers
+	ExcludeSelf   bool            // skip transfers where from == to
+	After         *TransferCursor // keyset pagination: only transfers older than this position
+	Limit         int
+	Offset        int
+}
+
+// TransferCursor is a position in the newest-first transfer feed
+type TransferCursor struct {
+	BlockTimestamp time.Time
+	BlockNumber    int64
+	LogIndex       int
+}
+
+// CursorAt returns the cursor positioned at a transfer
+func CursorAt(t Transfer) TransferCursor {
+	return TransferCursor{
+		BlockTimestamp: t.BlockTimestamp,
+		BlockNumber:    t.BlockNumber,
+		LogIndex:       t.LogIndex,
+	}
+}
+
+// Precedes reports whether t comes after the cursor in the newest-first feed
+func (c TransferCursor) Precedes(t Transfer) bool {
+	if !t.BlockTimestamp.Equal(c.BlockTimestamp) {
+		return t.BlockTimestamp.Before(c.BlockTimestamp)
+	}
+	if t.BlockNumber != c.BlockNumber {
+		return t.BlockNumber < c.BlockNumber
+	}
+	return t.LogIndex < c.LogIndex
 }
 
 // DefaultTransferFilter returns a filter with sensible defaults
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// TransferFlagWashTrade marks a transfer ping-ponging between two addresses: sent back, for about
+// the same value, shortly after it arrived
+const TransferFlagWashTrade = "wash_trade"
+
+// TransferFlag is a heuristic label attached to a transfer by the indexer's analysis pass
+type TransferFlag struct {
+	TxHash       string    `db:"tx_hash"`
+	LogIndex     int       `db:"log_index"`
+	Flag         string    `db:"flag"`
+	TokenAddress string    `db:"token_address"`
+	FlaggedAt    time.Time `db:"flagged_at"`
+}
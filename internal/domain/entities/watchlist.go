@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// Watchlist is a named group of addresses whose transfers are served as one feed
+type Watchlist struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Addresses []string  `db:"-"` // Lowercase, ordered by when they were added
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
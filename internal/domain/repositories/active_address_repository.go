@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// ActiveAddressRepository tracks approximate unique active addresses (senders and receivers) per token per UTC day
+type ActiveAddressRepository interface {
+	// AddActiveAddresses records addresses as active for a token on the UTC day containing day
+	AddActiveAddresses(ctx context.Context, tokenAddress string, day time.Time, addresses []string) error
+
+	// ReplaceActiveAddresses resets a token's UTC day to exactly the given addresses
+	ReplaceActiveAddresses(ctx context.Context, tokenAddress string, day time.Time, addresses []string) error
+
+	// CountActiveAddresses returns the approximate number of distinct addresses active on any UTC day in [from, to]
+	CountActiveAddresses(ctx context.Context, tokenAddress string, from, to time.Time) (int64, error)
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// AddressNoteRepository defines the interface for tenants' notes on addresses
+type AddressNoteRepository interface {
+	// Put creates or replaces a tenant's note on an address and sets its timestamps
+	Put(ctx context.Context, note *entities.AddressNote) error
+
+	// Get retrieves a tenant's note on an address, or nil if it has none
+	Get(ctx context.Context, tenantID int64, address entities.Address) (*entities.AddressNote, error)
+
+	// Delete removes a tenant's note on an address, reporting whether it existed
+	Delete(ctx context.Context, tenantID int64, address entities.Address) (bool, error)
+
+	// GetByAddresses returns a tenant's notes on any of the given normalized addresses
+	GetByAddresses(ctx context.Context, tenantID int64, addresses []string) ([]entities.AddressNote, error)
+}
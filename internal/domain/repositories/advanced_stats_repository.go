@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// AdvancedStats is a token's velocity and dormancy inputs as of ComputedAt: the volume and
+// average circulating supply over [WindowFrom, WindowTo), the supply held by addresses inactive
+// since InactiveSince, and the self-transfers and airdrop-like fan-outs within the window.
+// Amounts are big numbers as strings to preserve precision.
+type AdvancedStats struct {
+	TokenAddress      string    `db:"token_address"`
+	WindowFrom        time.Time `db:"window_from"`
+	WindowTo          time.Time `db:"window_to"`
+	Volume            string    `db:"volume"`
+	AverageSupply     string    `db:"average_supply"`
+	CirculatingSupply string    `db:"circulating_supply"`
+	InactiveSince     time.Time `db:"inactive_since"`
+	DormantSupply     string    `db:"dormant_supply"`
+	DormantHolders    int64     `db:"dormant_holders"`
+
+	SelfTransfers      int64  `db:"self_transfers"`
+	SelfTransferVolume string `db:"self_transfer_volume"`
+	FanOutThreshold    int    `db:"fan_out_threshold"` // A fan-out has more transfers from one sender in a transaction
+	FanOutTransactions int64  `db:"fan_out_transactions"`
+	FanOutTransfers    int64  `db:"fan_out_transfers"`
+	FanOutVolume       string `db:"fan_out_volume"`
+
+	ComputedAt time.Time `db:"computed_at"`
+}
+
+// AdvancedStatsRepository defines the interface for the advanced stats computed by the indexer's aggregation job
+type AdvancedStatsRepository interface {
+	// Save records a token's advanced stats, replacing the previous ones
+	Save(ctx context.Context, stats AdvancedStats) error
+
+	// Get returns a token's last computed advanced stats (nil if none were computed)
+	Get(ctx context.Context, tokenAddress string) (*AdvancedStats, error)
+}
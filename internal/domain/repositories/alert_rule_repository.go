@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// AlertRuleRepository defines the interface for alert rule operations
+type AlertRuleRepository interface {
+	// Create inserts a rule and sets its ID and creation time
+	Create(ctx context.Context, rule *entities.AlertRule) error
+
+	// GetByID retrieves a rule, or nil if it does not exist
+	GetByID(ctx context.Context, id int64) (*entities.AlertRule, error)
+
+	// List retrieves all rules, oldest first
+	List(ctx context.Context) ([]entities.AlertRule, error)
+
+	// Delete removes a rule, reporting whether it existed
+	Delete(ctx context.Context, id int64) (bool, error)
+}
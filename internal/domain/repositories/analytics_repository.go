@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// AnalyticsRepository defines the interface for the optional analytical transfer store.
+// It mirrors transfers written to the system of record and answers the aggregate queries
+// that are expensive to run against it.
+type AnalyticsRepository interface {
+	// InsertTransfers appends transfers; re-inserting a transfer must not double count it
+	InsertTransfers(ctx context.Context, transfers []entities.Transfer) error
+
+	// ReplaceRange replaces a token's transfers in [fromBlock, toBlock] with the given ones
+	ReplaceRange(ctx context.Context, tokenAddress string, fromBlock, toBlock int64, transfers []entities.Transfer) error
+
+	// GetTokenStats returns aggregated transfer statistics for a token
+	GetTokenStats(ctx context.Context, tokenAddress string) (*TokenStatsResult, error)
+
+	// GetHolderCount returns the count of unique holders with positive balance, leaving out the addresses in exclude
+	GetHolderCount(ctx context.Context, tokenAddress string, exclude []string) (int64, error)
+
+	// GetTopHolders returns token holders sorted by balance with pagination offset, leaving out the
+	// addresses in exclude
+	GetTopHolders(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]HolderBalance, error)
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// ContractRepository defines the interface for contracts registered by ABI and their decoded events
+type ContractRepository interface {
+	// StoreEvents inserts decoded *entities.ContractEvent records, skipping duplicates
+	StoreEvents(ctx context.Context, events []any) error
+
+	// UpsertContract registers a contract, replacing the ABI and events of an earlier registration
+	UpsertContract(ctx context.Context, contract *entities.Contract) error
+
+	// GetContract retrieves a contract by address, or nil if it is not registered
+	GetContract(ctx context.Context, address string) (*entities.Contract, error)
+
+	// ListContracts retrieves every registered contract, by address
+	ListContracts(ctx context.Context) ([]entities.Contract, error)
+
+	// GetEvents retrieves contract events matching the filter, newest first
+	GetEvents(ctx context.Context, filter entities.ContractEventFilter) ([]entities.ContractEvent, error)
+
+	// GetEventCount returns the count of contract events matching the filter
+	GetEventCount(ctx context.Context, filter entities.ContractEventFilter) (int64, error)
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// DailyStatsRepository defines the interface for the per-token daily rollup
+type DailyStatsRepository interface {
+	// RefreshDays recomputes a token's rollup rows for every UTC day in [from, to] from raw transfers,
+	// along with the first receipts of the addresses whose first receipt falls on those days
+	RefreshDays(ctx context.Context, tokenAddress string, from, to time.Time) error
+
+	// GetRange returns a token's rollup rows for UTC days in [from, to], oldest first.
+	// Days without transfers have no row.
+	GetRange(ctx context.Context, tokenAddress string, from, to time.Time) ([]entities.TokenDailyStats, error)
+
+	// GetNewHolders returns up to limit addresses that first received the token at or after since, newest first
+	GetNewHolders(ctx context.Context, tokenAddress string, since time.Time, limit int) ([]entities.HolderFirstSeen, error)
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// DenyListRepository defines the interface for deny list operations
+type DenyListRepository interface {
+	// ReplaceList atomically replaces all entries of a list, keeping added_at for addresses already on it
+	ReplaceList(ctx context.Context, listName string, entries []entities.DenyListEntry) error
+
+	// FindByAddresses returns every entry, across all lists, for the given addresses
+	FindByAddresses(ctx context.Context, addresses []string) ([]entities.DenyListEntry, error)
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// EntityFlow is an entity's inflow and outflow of one token. Transfers between two of its
+// addresses are internal and counted in neither.
+type EntityFlow struct {
+	EntityID     int64  `db:"entity_id"`
+	Name         string `db:"name"`
+	TransfersIn  int64  `db:"transfers_in"`
+	TransfersOut int64  `db:"transfers_out"`
+	VolumeIn     string `db:"volume_in"` // Raw amount
+	VolumeOut    string `db:"volume_out"`
+}
+
+// EntityRepository defines the interface for entity operations
+type EntityRepository interface {
+	// Create inserts an entity with its addresses and sets its ID and timestamps
+	Create(ctx context.Context, entity *entities.Entity) error
+
+	// GetByID retrieves an entity with its addresses, or nil if it does not exist
+	GetByID(ctx context.Context, id int64) (*entities.Entity, error)
+
+	// Delete removes an entity and its addresses, reporting whether it existed
+	Delete(ctx context.Context, id int64) (bool, error)
+
+	// AddAddresses adds addresses to an entity, skipping ones already present
+	AddAddresses(ctx context.Context, id int64, addresses []string) error
+
+	// RemoveAddress removes an address from an entity, reporting whether it was present
+	RemoveAddress(ctx context.Context, id int64, address string) (bool, error)
+
+	// GetCategoryFlows returns the token flows in [from, to) of each entity in a category that
+	// sent or received the token, ordered by ID
+	GetCategoryFlows(ctx context.Context, tokenAddress, category string, from, to time.Time) ([]EntityFlow, error)
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// EthTransferRepository defines the interface for native ETH transfer operations
+type EthTransferRepository interface {
+	// BatchInsert inserts ETH transfers, skipping duplicates
+	BatchInsert(ctx context.Context, transfers []entities.EthTransfer) error
+
+	// GetByFilter retrieves ETH transfers matching the filter, newest first
+	GetByFilter(ctx context.Context, filter entities.EthTransferFilter) ([]entities.EthTransfer, error)
+
+	// GetCount returns the count of ETH transfers matching the filter
+	GetCount(ctx context.Context, filter entities.EthTransferFilter) (int64, error)
+
+	// GetLastBlock returns the last block whose traces were indexed, or 0 if none were
+	GetLastBlock(ctx context.Context) (int64, error)
+
+	// UpdateLastBlock records the last block whose traces were indexed
+	UpdateLastBlock(ctx context.Context, blockNumber int64) error
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+)
+
+// EventRepository defines the interface for storing decoded events of a registered event type
+type EventRepository interface {
+	// StoreEvents persists decoded event records, skipping duplicates
+	StoreEvents(ctx context.Context, events []any) error
+}
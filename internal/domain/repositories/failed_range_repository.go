@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// FailedRangeRepository defines the interface for block ranges that kept failing to index
+type FailedRangeRepository interface {
+	// Record stores a failed range, or updates the unresolved record of the same token and blocks
+	// with the new attempts, error and skipped state
+	Record(ctx context.Context, r entities.FailedRange) error
+
+	// List returns the failed ranges, oldest first; unresolved limits them to those not yet repaired
+	List(ctx context.Context, unresolved bool) ([]entities.FailedRange, error)
+
+	// Resolve marks a failed range as repaired, reporting whether an unresolved range was found
+	Resolve(ctx context.Context, id int64) (bool, error)
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// HolderCount is a token's holder count as of CountedAt
+type HolderCount struct {
+	TokenAddress string    `db:"token_address"`
+	Count        int64     `db:"holder_count"`
+	CountedAt    time.Time `db:"counted_at"`
+}
+
+// HolderCountRepository defines the interface for the holder counts recorded with each holder snapshot
+type HolderCountRepository interface {
+	// Save records a token's holder count, replacing the previous one
+	Save(ctx context.Context, tokenAddress string, count int64, countedAt time.Time) error
+
+	// Get returns a token's last recorded holder count (nil if none was recorded)
+	Get(ctx context.Context, tokenAddress string) (*HolderCount, error)
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// HolderSnapshot is a token's top holders as of TakenAt, ordered by rank
+type HolderSnapshot struct {
+	TokenAddress string
+	TakenAt      time.Time
+	Holders      []HolderBalance
+}
+
+// HolderSnapshotRepository defines the interface for holder snapshot operations
+type HolderSnapshotRepository interface {
+	// Save stores a token's top holders as the snapshot taken at takenAt
+	Save(ctx context.Context, tokenAddress string, takenAt time.Time, holders []HolderBalance) error
+
+	// GetAt returns the latest snapshot taken at or before at, falling back to the
+	// earliest one when all are newer (nil if the token has none)
+	GetAt(ctx context.Context, tokenAddress string, at time.Time) (*HolderSnapshot, error)
+
+	// DeleteBefore removes snapshots taken before the given time and returns the number of deleted rows
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
 )
@@ -17,6 +18,14 @@ type IndexerStateRepository interface {
 	// UpdateLastBlock updates the last indexed block for a token
 	UpdateLastBlock(ctx context.Context, tokenAddress string, blockNumber int64) error
 
-	// SetBackfilling sets the backfilling state for a token
+	// GetOldestCheckpointTime returns when the least recently advanced checkpoint of an active
+	// token last moved (nil if there are none)
+	GetOldestCheckpointTime(ctx context.Context) (*time.Time, error)
+
+	// SetBackfilling sets the backfilling state for a token. The backfill checkpoint is kept
+	// while the range stays the same and cleared when it changes.
 	SetBackfilling(ctx context.Context, tokenAddress string, isBackfilling bool, fromBlock, toBlock *int64) error
+
+	// UpdateBackfillCheckpoint records the last block of the completed prefix of the backfill range
+	UpdateBackfillCheckpoint(ctx context.Context, tokenAddress string, blockNumber int64) error
 }
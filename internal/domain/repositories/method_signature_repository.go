@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// MethodSignatureRepository defines the interface for method signature operations
+type MethodSignatureRepository interface {
+	// Upsert adds a signature or replaces the name and label of an existing selector, setting its timestamps
+	Upsert(ctx context.Context, sig *entities.MethodSignature) error
+
+	// List returns every signature ordered by label and selector
+	List(ctx context.Context) ([]entities.MethodSignature, error)
+
+	// GetBySelectors returns the signatures known for the given selectors
+	GetBySelectors(ctx context.Context, selectors []string) ([]entities.MethodSignature, error)
+}
@@ -18,6 +18,55 @@ type WalletTransferSummary struct {
 	LastTransferAt    *time.Time
 }
 
+// DailyBalanceChange holds a wallet's net balance change in one token over a UTC day
+type DailyBalanceChange struct {
+	TokenAddress string
+	Decimals     int
+	Day          time.Time
+	Change       string // Signed raw amount
+}
+
+// WalletTokenActivity holds a wallet's activity in one token over all time, including tokens it no longer holds
+type WalletTokenActivity struct {
+	TokenAddress    string
+	TokenName       string
+	TokenSymbol     string
+	Decimals        int
+	Balance         string // Raw current balance, may be zero
+	TransfersIn     int64
+	TransfersOut    int64
+	FirstActivityAt time.Time
+	LastActivityAt  time.Time
+}
+
+// EntityTokenVolume holds a group of addresses' flows in one token. Transfers between two
+// members are counted as internal, not as both an inflow and an outflow.
+type EntityTokenVolume struct {
+	TokenAddress      string
+	TokenSymbol       string
+	Decimals          int
+	TransfersIn       int64
+	TransfersOut      int64
+	InternalTransfers int64
+	VolumeIn          string // Raw amount
+	VolumeOut         string
+	InternalVolume    string
+	FirstActivityAt   time.Time
+	LastActivityAt    time.Time
+}
+
+// CounterpartyFlow aggregates the transfers of one token from one address to another
+type CounterpartyFlow struct {
+	FromAddress    string
+	ToAddress      string
+	TokenAddress   string
+	TokenSymbol    string
+	Decimals       int
+	Transfers      int64
+	Volume         string // Raw amount
+	LastTransferAt time.Time
+}
+
 // PortfolioRepository defines interface for portfolio data operations
 type PortfolioRepository interface {
 	// GetWalletHoldings retrieves all token holdings for a wallet
@@ -32,4 +81,24 @@ type PortfolioRepository interface {
 
 	// GetWalletTransferSummary returns transfer stats for a wallet
 	GetWalletTransferSummary(ctx context.Context, walletAddress string) (*WalletTransferSummary, error)
+
+	// GetWalletBalancesAt returns non-zero token balances from transfers before the given time
+	GetWalletBalancesAt(ctx context.Context, walletAddress string, at time.Time) ([]entities.TokenHolding, error)
+
+	// GetWalletDailyBalanceChanges returns per-day net balance changes since the given time, oldest first
+	GetWalletDailyBalanceChanges(ctx context.Context, walletAddress string, from time.Time) ([]DailyBalanceChange, error)
+
+	// GetWalletTokenActivity returns every token the wallet has sent or received, most recently active first
+	GetWalletTokenActivity(ctx context.Context, walletAddress string) ([]WalletTokenActivity, error)
+
+	// GetEntityHoldings retrieves the combined positive token balances of a group of addresses
+	GetEntityHoldings(ctx context.Context, addresses []string) ([]entities.TokenHolding, error)
+
+	// GetEntityTokenVolumes returns a group of addresses' per-token flows, most recently active first
+	GetEntityTokenVolumes(ctx context.Context, addresses []string) ([]EntityTokenVolume, error)
+
+	// GetCounterpartyFlows aggregates the transfers sent and received by a set of addresses into
+	// flows, reading only the scanLimit most recent transfers in each direction. It returns the
+	// limit flows with the most transfers, most recent first on ties.
+	GetCounterpartyFlows(ctx context.Context, addresses []string, scanLimit, limit int) ([]CounterpartyFlow, error)
 }
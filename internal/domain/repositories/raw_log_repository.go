@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// RawLogRepository defines the interface for raw log archive operations
+type RawLogRepository interface {
+	// BatchInsert stores raw logs, skipping ones already stored
+	BatchInsert(ctx context.Context, logs []entities.RawLog) error
+
+	// GetRange retrieves the logs emitted by an address in a block range, in chain order
+	GetRange(ctx context.Context, address string, fromBlock, toBlock int64) ([]entities.RawLog, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import "context"
+
+// ScopedEventStateRepository stores the checkpoint of events scoped to their own contracts, such
+// as DEX pool swaps, which are indexed once per block range rather than per token
+type ScopedEventStateRepository interface {
+	// GetLastBlock returns the last block whose scoped events were indexed, or 0 if none were
+	GetLastBlock(ctx context.Context) (int64, error)
+
+	// UpdateLastBlock records the last block whose scoped events were indexed
+	UpdateLastBlock(ctx context.Context, blockNumber int64) error
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// SwapVolumeResult holds swap volume statistics for a token across all indexed pools
+type SwapVolumeResult struct {
+	SwapCount    int64
+	PoolCount    int64
+	TotalVolume  string
+	SwapCount24h int64
+	Volume24h    string
+	SwapCount7d  int64
+	Volume7d     string
+}
+
+// SwapRepository defines the interface for DEX pool and swap operations
+type SwapRepository interface {
+	// StoreEvents inserts decoded *entities.Swap records, skipping duplicates
+	StoreEvents(ctx context.Context, events []any) error
+
+	// UpsertPool creates or updates a DEX pool
+	UpsertPool(ctx context.Context, pool *entities.DexPool) error
+
+	// GetPool retrieves a pool by address
+	GetPool(ctx context.Context, address string) (*entities.DexPool, error)
+
+	// GetByFilter retrieves swaps matching the filter, newest first
+	GetByFilter(ctx context.Context, filter entities.SwapFilter) ([]entities.Swap, error)
+
+	// GetCount returns the count of swaps matching the filter
+	GetCount(ctx context.Context, filter entities.SwapFilter) (int64, error)
+
+	// GetTokenSwapVolume returns swap volume for a token, summing absolute amounts on its side of each pool
+	GetTokenSwapVolume(ctx context.Context, tokenAddress string) (*SwapVolumeResult, error)
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// TenantRepository defines the interface for tenant operations
+type TenantRepository interface {
+	// Create inserts a tenant with its tokens and sets its ID and creation time
+	Create(ctx context.Context, tenant *entities.Tenant) error
+
+	// GetByID retrieves a tenant with its tokens, or nil if it does not exist
+	GetByID(ctx context.Context, id int64) (*entities.Tenant, error)
+
+	// GetByAPIKey retrieves the tenant owning an API key by the key's SHA-256 hex digest,
+	// or nil if no tenant owns it
+	GetByAPIKey(ctx context.Context, keyHash string) (*entities.Tenant, error)
+
+	// AddAPIKey stores the SHA-256 hex digest of a new API key for a tenant
+	AddAPIKey(ctx context.Context, id int64, keyHash string) error
+
+	// SetTokens replaces a tenant's token set, reporting whether the tenant exists
+	SetTokens(ctx context.Context, id int64, tokens []string) (bool, error)
+
+	// ListTokens retrieves a page of the tenant's tokens with sorting, like
+	// TokenRepository.GetAllPaginated
+	ListTokens(ctx context.Context, id int64, limit, offset int, sortBy, sortOrder string, includeInactive bool, scope TokenScope) ([]*entities.Token, int64, error)
+}
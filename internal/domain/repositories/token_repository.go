@@ -6,23 +6,57 @@ import (
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
 )
 
+// TokenScope narrows token listings to the addresses in Only, when it is not empty, and leaves
+// out the addresses in Except. The zero value lists every token.
+type TokenScope struct {
+	Only   []string
+	Except []string
+}
+
 // TokenRepository defines the interface for token data operations
 type TokenRepository interface {
 	// GetByAddress retrieves a token by its address
 	GetByAddress(ctx context.Context, address string) (*entities.Token, error)
 
+	// GetByAddresses retrieves the tokens with the given addresses in one query; unknown addresses are omitted
+	GetByAddresses(ctx context.Context, addresses []string) ([]*entities.Token, error)
+
 	// GetAll retrieves all tokens
 	GetAll(ctx context.Context) ([]entities.Token, error)
 
-	// GetAllPaginated retrieves tokens with pagination and sorting
-	GetAllPaginated(ctx context.Context, limit, offset int, sortBy, sortOrder string) ([]*entities.Token, int64, error)
+	// GetAllPaginated retrieves the tokens in scope with pagination and sorting; deactivated
+	// tokens are skipped unless includeInactive is set
+	GetAllPaginated(ctx context.Context, limit, offset int, sortBy, sortOrder string, includeInactive bool, scope TokenScope) ([]*entities.Token, int64, error)
 
 	// Count returns the total number of tokens
 	Count(ctx context.Context) (int64, error)
 
-	// Upsert creates or updates a token
+	// Upsert creates or updates a token. The name, symbol and decimals of a token whose
+	// metadata was overridden are kept.
 	Upsert(ctx context.Context, token *entities.Token) error
 
-	// UpdateStats updates token statistics
+	// SetActive activates or deactivates a token, keeping its transfers and checkpoint.
+	// It returns false when the token does not exist.
+	SetActive(ctx context.Context, address string, active bool) (bool, error)
+
+	// OverrideMetadata replaces the given metadata fields and sets the token's metadata source
+	// to override. It returns false when the token does not exist.
+	OverrideMetadata(ctx context.Context, address string, override entities.TokenMetadataOverride) (bool, error)
+
+	// RecordMetadataChange sets a token's name, symbol and decimals to the new values of change
+	// and appends change to its metadata history, setting its ID and detection time. Tokens
+	// whose metadata was overridden are left unchanged and false is returned, as for unknown tokens.
+	RecordMetadataChange(ctx context.Context, change *entities.TokenMetadataChange) (bool, error)
+
+	// GetMetadataHistory returns a token's recorded metadata changes, newest first
+	GetMetadataHistory(ctx context.Context, address string) ([]entities.TokenMetadataChange, error)
+
+	// UpdateStats adds transferCount to the token's indexed transfer count and raises its last
+	// seen block. transferCount must be the number of rows actually inserted, not fetched, so
+	// re-running a range does not count its transfers twice.
 	UpdateStats(ctx context.Context, address string, transferCount int64, lastBlock int64) error
+
+	// RecountTransfers sets the token's indexed transfer count to the number of stored
+	// transfers, after they were replaced or restored, and returns it
+	RecountTransfers(ctx context.Context, address string) (int64, error)
 }
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// TransferFlagRepository defines the interface for the flags the analysis pass attaches to transfers
+type TransferFlagRepository interface {
+	// Add stores flags, stamped with the current time, skipping those already stored, and returns the number added
+	Add(ctx context.Context, flags []entities.TransferFlag) (int64, error)
+
+	// GetByTxHashes returns the flags of the transfers in the given transactions
+	GetByTxHashes(ctx context.Context, txHashes []string) ([]entities.TransferFlag, error)
+
+	// GetFlaggedStats counts a token's flagged transfers in [from, to) and sums their value; a
+	// zero from counts from the first transfer. Each transfer counts once however many flags it has.
+	GetFlaggedStats(ctx context.Context, tokenAddress string, from, to time.Time) (*WindowStats, error)
+}
@@ -21,6 +21,19 @@ type TokenStatsResult struct {
 	LastTransferAt  *time.Time
 }
 
+// WindowStats holds transfer count and volume for a time window
+type WindowStats struct {
+	Transfers int64
+	Volume    string
+}
+
+// HeatmapBucket holds the transfer count of one UTC weekday and hour of day
+type HeatmapBucket struct {
+	DayOfWeek int // 0 = Sunday
+	Hour      int
+	Transfers int64
+}
+
 // HolderBalance represents an address and its token balance
 type HolderBalance struct {
 	Address string
@@ -28,6 +41,56 @@ type HolderBalance struct {
 	Rank    int
 }
 
+// NegativeBalances summarizes the addresses whose balance derived from indexed transfers is
+// negative, a sign that transfers into them were never indexed
+type NegativeBalances struct {
+	Count int64
+	Worst []HolderBalance // most negative first, ranked from 1
+}
+
+// BalanceChange is an address's net balance change over a period
+type BalanceChange struct {
+	Address string
+	Change  string // signed big number as string to preserve precision
+}
+
+// TransferPatterns counts transfers of a token within a time window that move no value between
+// holders: self-transfers, and fan-outs where one transaction carries more than a threshold of
+// transfers from the same sender, as airdrops do. Volumes are big numbers as strings.
+type TransferPatterns struct {
+	SelfTransfers      int64  `db:"self_transfers"`
+	SelfTransferVolume string `db:"self_transfer_volume"`
+	FanOutTransactions int64  `db:"fan_out_transactions"`
+	FanOutTransfers    int64  `db:"fan_out_transfers"`
+	FanOutVolume       string `db:"fan_out_volume"`
+}
+
+// DormantSupply is the balance held by addresses that have not sent or received a token since a given time
+type DormantSupply struct {
+	Balance string `db:"balance"` // big number as string to preserve precision
+	Holders int64  `db:"holders"`
+}
+
+// HolderBalanceEvent is one transfer in a holder's ledger with the balance it left them with
+type HolderBalanceEvent struct {
+	BlockNumber    int64
+	BlockTimestamp time.Time
+	TxHash         string
+	LogIndex       int
+	Counterparty   string
+	Change         string // signed; zero for self-transfers
+	Balance        string // running balance after this transfer
+}
+
+// HolderHistoryFilter selects a page of a holder's ledger.
+// The time range limits which events are returned, not the running balance, which always covers all history.
+type HolderHistoryFilter struct {
+	FromTime *time.Time // inclusive
+	ToTime   *time.Time // exclusive
+	Limit    int
+	Offset   int
+}
+
 // TransferRepository defines the interface for transfer data operations
 type TransferRepository interface {
 	// GetByFilter retrieves transfers matching the given filter
@@ -36,24 +99,76 @@ type TransferRepository interface {
 	// GetCount returns the count of transfers matching the filter
 	GetCount(ctx context.Context, filter entities.TransferFilter) (int64, error)
 
-	// BatchInsert inserts multiple transfers in a single transaction
-	BatchInsert(ctx context.Context, transfers []entities.Transfer) error
+	// BatchInsert inserts multiple transfers in a single transaction, skipping ones already stored.
+	// Returns the number of transfers actually inserted; the rest were duplicates.
+	BatchInsert(ctx context.Context, transfers []entities.Transfer) (int64, error)
+
+	// ReplaceRange atomically deletes a token's transfers in [fromBlock, toBlock] and inserts the given ones.
+	// Returns the number of deleted rows and of transfers actually inserted; duplicates are skipped.
+	ReplaceRange(ctx context.Context, tokenAddress string, fromBlock, toBlock int64, transfers []entities.Transfer) (deleted, inserted int64, err error)
 
 	// GetLatestBlock returns the latest indexed block for a token
 	GetLatestBlock(ctx context.Context, tokenAddress string) (int64, error)
 
+	// GetLatestID returns the highest transfer ID, or 0 if there are no transfers
+	GetLatestID(ctx context.Context) (int64, error)
+
+	// GetAfterID retrieves up to filter.Limit transfers matching the filter with an ID above afterID,
+	// in insertion order. filter.Offset is ignored.
+	GetAfterID(ctx context.Context, filter entities.TransferFilter, afterID int64) ([]entities.Transfer, error)
+
 	// GetTokenStats returns aggregated transfer statistics for a token
 	GetTokenStats(ctx context.Context, tokenAddress string) (*TokenStatsResult, error)
 
-	// GetTopHolders returns top token holders sorted by balance
+	// GetWindowStats returns transfer count and volume for a token in [from, to)
+	GetWindowStats(ctx context.Context, tokenAddress string, from, to time.Time) (*WindowStats, error)
+
+	// GetActivityHeatmap returns a token's transfer counts in [from, to) grouped by UTC weekday and hour.
+	// Buckets without transfers are omitted.
+	GetActivityHeatmap(ctx context.Context, tokenAddress string, from, to time.Time) ([]HeatmapBucket, error)
+
+	// GetActiveAddresses returns the distinct senders and receivers of a token in [from, to), excluding the zero address
+	GetActiveAddresses(ctx context.Context, tokenAddress string, from, to time.Time) ([]string, error)
+
+	// GetTopHolders returns top token holders sorted by balance; the same as GetTopHoldersWithOffset
+	// at offset 0 with no exclusions
 	GetTopHolders(ctx context.Context, tokenAddress string, limit int) ([]HolderBalance, error)
 
+	// GetTopHoldersWithOffset returns one page of token holders sorted by balance, leaving out the addresses
+	// in exclude. Ranks are positions among the remaining holders, so a page at offset N starts at rank N+1.
+	GetTopHoldersWithOffset(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]HolderBalance, error)
+
 	// GetHolderBalance returns balance for a specific holder
 	GetHolderBalance(ctx context.Context, tokenAddress, holderAddress string) (*HolderBalance, error)
 
-	// GetHolderCount returns the count of unique holders with positive balance
-	GetHolderCount(ctx context.Context, tokenAddress string) (int64, error)
+	// GetHolderCount returns the count of unique holders with positive balance, leaving out the addresses in exclude
+	GetHolderCount(ctx context.Context, tokenAddress string, exclude []string) (int64, error)
+
+	// GetCirculatingSupply returns a token's indexed mints minus burns as a decimal string, both
+	// tracked as transfers from and to the zero address
+	GetCirculatingSupply(ctx context.Context, tokenAddress string) (string, error)
+
+	// GetCirculatingSupplyAt returns a token's circulating supply as of the given time, counting
+	// the mints and burns before it
+	GetCirculatingSupplyAt(ctx context.Context, tokenAddress string, at time.Time) (string, error)
+
+	// GetDormantSupply sums the positive balances of the addresses whose last transfer of the
+	// token, sent or received, was before inactiveSince, excluding the zero address
+	GetDormantSupply(ctx context.Context, tokenAddress string, inactiveSince time.Time) (*DormantSupply, error)
+
+	// GetTransferPatterns counts the self-transfers in [from, to) and the transactions in it with
+	// more than fanOut transfers from one sender
+	GetTransferPatterns(ctx context.Context, tokenAddress string, from, to time.Time, fanOut int) (*TransferPatterns, error)
+
+	// GetNegativeBalances counts a token's negative balances, leaving out the zero address whose
+	// balance is minus the supply, and returns the limit most negative
+	GetNegativeBalances(ctx context.Context, tokenAddress string, limit int) (*NegativeBalances, error)
+
+	// GetBalanceChanges returns the addresses whose balance changed the most since the given time,
+	// ordered by absolute change and excluding the zero address
+	GetBalanceChanges(ctx context.Context, tokenAddress string, since time.Time, limit int) ([]BalanceChange, error)
 
-	// GetTopHoldersWithOffset returns top token holders with pagination offset
-	GetTopHoldersWithOffset(ctx context.Context, tokenAddress string, limit, offset int) ([]HolderBalance, error)
+	// StreamHolderHistory calls fn for each event of a holder's ledger, oldest first, without buffering the page.
+	// Iteration stops at the first error from fn, which is returned.
+	StreamHolderHistory(ctx context.Context, tokenAddress, holderAddress string, filter HolderHistoryFilter, fn func(HolderBalanceEvent) error) error
 }
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// WalletStatsRepository defines the interface for the per wallet and token transfer totals the
+// indexer maintains as it stores transfers
+type WalletStatsRepository interface {
+	// Apply adds newly stored transfers to the totals of their senders and receivers. Every
+	// transfer must be new: one already counted would be counted twice.
+	Apply(ctx context.Context, transfers []entities.Transfer) error
+
+	// Refresh recomputes a token's totals for the given wallets from stored transfers, dropping
+	// the rows of wallets left without transfers of the token
+	Refresh(ctx context.Context, tokenAddress string, wallets []string) error
+
+	// Rebuild recomputes all of a token's totals from stored transfers
+	Rebuild(ctx context.Context, tokenAddress string) error
+
+	// GetRangeWallets returns the senders and receivers of a token's stored transfers in [fromBlock, toBlock]
+	GetRangeWallets(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) ([]string, error)
+
+	// GetSummary sums a wallet's totals across tokens; found is false when it has none
+	GetSummary(ctx context.Context, walletAddress string) (summary *WalletTransferSummary, found bool, err error)
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// WatchlistRepository defines the interface for watchlist operations
+type WatchlistRepository interface {
+	// Create inserts a watchlist with its addresses and sets its ID and timestamps
+	Create(ctx context.Context, watchlist *entities.Watchlist) error
+
+	// GetByID retrieves a watchlist with its addresses, or nil if it does not exist
+	GetByID(ctx context.Context, id int64) (*entities.Watchlist, error)
+
+	// Delete removes a watchlist and its addresses, reporting whether it existed
+	Delete(ctx context.Context, id int64) (bool, error)
+
+	// AddAddresses adds addresses to a watchlist, skipping ones already present
+	AddAddresses(ctx context.Context, id int64, addresses []string) error
+
+	// RemoveAddress removes an address from a watchlist, reporting whether it was present
+	RemoveAddress(ctx context.Context, id int64, address string) (bool, error)
+}
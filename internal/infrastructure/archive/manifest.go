@@ -0,0 +1,112 @@
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestKey is the object key of the archive manifest
+const ManifestKey = "manifest.json"
+
+// Manifest lists every archived file. It is the source of truth for what has been archived:
+// a block range may only be pruned from the database once a file covering it is listed here.
+type Manifest struct {
+	Files []ManifestFile `json:"files"`
+}
+
+// ManifestFile describes one Parquet file of a token's transfers in [FromBlock, ToBlock]
+type ManifestFile struct {
+	TokenAddress string    `json:"token_address"`
+	FromBlock    int64     `json:"from_block"`
+	ToBlock      int64     `json:"to_block"`
+	Rows         int       `json:"rows"`
+	Key          string    `json:"key"`
+	SHA256       string    `json:"sha256"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// FileKey returns the object key of a token's archive file for a block range
+func FileKey(tokenAddress string, fromBlock, toBlock int64) string {
+	return fmt.Sprintf("transfers/%s/%012d-%012d.parquet", strings.ToLower(tokenAddress), fromBlock, toBlock)
+}
+
+// Checksum returns the hex SHA-256 of an archive file
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadManifest reads the manifest, returning an empty one if none has been written yet
+func LoadManifest(ctx context.Context, store ObjectStore) (*Manifest, error) {
+	data, err := store.Get(ctx, ManifestKey)
+	if errors.Is(err, ErrNotFound) {
+		return &Manifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archive manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse archive manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// SaveManifest writes the manifest
+func SaveManifest(ctx context.Context, store ObjectStore, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive manifest: %w", err)
+	}
+	if err := store.Put(ctx, ManifestKey, data); err != nil {
+		return fmt.Errorf("failed to save archive manifest: %w", err)
+	}
+	return nil
+}
+
+// Add records a file, replacing any earlier entry for the same token and range
+func (m *Manifest) Add(f ManifestFile) {
+	for i, existing := range m.Files {
+		if existing.TokenAddress == f.TokenAddress && existing.FromBlock == f.FromBlock && existing.ToBlock == f.ToBlock {
+			m.Files[i] = f
+			return
+		}
+	}
+	m.Files = append(m.Files, f)
+	sort.Slice(m.Files, func(i, j int) bool {
+		if m.Files[i].TokenAddress != m.Files[j].TokenAddress {
+			return m.Files[i].TokenAddress < m.Files[j].TokenAddress
+		}
+		return m.Files[i].FromBlock < m.Files[j].FromBlock
+	})
+}
+
+// LastArchivedBlock returns the highest block archived for a token, or -1 if none is
+func (m *Manifest) LastArchivedBlock(tokenAddress string) int64 {
+	last := int64(-1)
+	for _, f := range m.Files {
+		if f.TokenAddress == tokenAddress && f.ToBlock > last {
+			last = f.ToBlock
+		}
+	}
+	return last
+}
+
+// Overlapping returns a token's files that contain any block in [fromBlock, toBlock], in block order
+func (m *Manifest) Overlapping(tokenAddress string, fromBlock, toBlock int64) []ManifestFile {
+	var files []ManifestFile
+	for _, f := range m.Files {
+		if f.TokenAddress == tokenAddress && f.FromBlock <= toBlock && f.ToBlock >= fromBlock {
+			files = append(files, f)
+		}
+	}
+	return files
+}
@@ -0,0 +1,404 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// Transfers are archived as Parquet files with one required column per field, PLAIN encoding
+// and GZIP-compressed pages, so they can be read by any Parquet consumer (DuckDB, Spark, pandas).
+// value is stored as a decimal string because uint256 exceeds every Parquet integer type.
+
+const (
+	parquetMagic = "PAR1"
+
+	// rowGroupSize bounds the rows per row group, and so the size of each column page
+	rowGroupSize = 100_000
+)
+
+// Parquet physical types, converted types, encodings and codecs used by the archive
+const (
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecUncompressed = 0
+	codecGzip         = 2
+
+	pageTypeData = 0
+)
+
+// parquetColumn describes one archived column and how it is read from and written to a transfer
+type parquetColumn struct {
+	name      string
+	physical  int32
+	converted int32 // -1 for none
+	write     func(buf *bytes.Buffer, t *entities.Transfer)
+	read      func(r *bytes.Reader, t *entities.Transfer) error
+}
+
+var transferColumns = []parquetColumn{
+	{"token_address", parquetByteArray, convertedUTF8,
+		func(b *bytes.Buffer, t *entities.Transfer) { writeByteArray(b, t.TokenAddress) },
+		func(r *bytes.Reader, t *entities.Transfer) (err error) {
+			t.TokenAddress, err = readByteArray(r)
+			return
+		}},
+	{"tx_hash", parquetByteArray, convertedUTF8,
+		func(b *bytes.Buffer, t *entities.Transfer) { writeByteArray(b, t.TxHash) },
+		func(r *bytes.Reader, t *entities.Transfer) (err error) { t.TxHash, err = readByteArray(r); return }},
+	{"log_index", parquetInt32, -1,
+		func(b *bytes.Buffer, t *entities.Transfer) {
+			_ = binary.Write(b, binary.LittleEndian, int32(t.LogIndex))
+		},
+		func(r *bytes.Reader, t *entities.Transfer) error {
+			var v int32
+			err := binary.Read(r, binary.LittleEndian, &v)
+			t.LogIndex = int(v)
+			return err
+		}},
+	{"block_number", parquetInt64, -1,
+		func(b *bytes.Buffer, t *entities.Transfer) {
+			_ = binary.Write(b, binary.LittleEndian, t.BlockNumber)
+		},
+		func(r *bytes.Reader, t *entities.Transfer) error {
+			return binary.Read(r, binary.LittleEndian, &t.BlockNumber)
+		}},
+	{"block_timestamp", parquetInt64, convertedTimestampMillis,
+		func(b *bytes.Buffer, t *entities.Transfer) {
+			_ = binary.Write(b, binary.LittleEndian, t.BlockTimestamp.UnixMilli())
+		},
+		func(r *bytes.Reader, t *entities.Transfer) error {
+			var v int64
+			err := binary.Read(r, binary.LittleEndian, &v)
+			t.BlockTimestamp = time.UnixMilli(v).UTC()
+			return err
+		}},
+	{"from_address", parquetByteArray, convertedUTF8,
+		func(b *bytes.Buffer, t *entities.Transfer) { writeByteArray(b, t.FromAddress) },
+		func(r *bytes.Reader, t *entities.Transfer) (err error) { t.FromAddress, err = readByteArray(r); return }},
+	{"to_address", parquetByteArray, convertedUTF8,
+		func(b *bytes.Buffer, t *entities.Transfer) { writeByteArray(b, t.ToAddress) },
+		func(r *bytes.Reader, t *entities.Transfer) (err error) { t.ToAddress, err = readByteArray(r); return }},
+	{"value", parquetByteArray, convertedUTF8,
+		func(b *bytes.Buffer, t *entities.Transfer) {
+			value := t.ValueString
+			if t.Value != nil {
+				value = t.Value.String()
+			}
+			writeByteArray(b, value)
+		},
+		func(r *bytes.Reader, t *entities.Transfer) error {
+			s, err := readByteArray(r)
+			if err != nil {
+				return err
+			}
+			v, ok := new(big.Int).SetString(s, 10)
+			if !ok {
+				return fmt.Errorf("invalid value %q", s)
+			}
+			t.Value, t.ValueString = v, s
+			return nil
+		}},
+}
+
+// WriteTransfers writes transfers to w as a Parquet file, in the given order
+func WriteTransfers(w io.Writer, transfers []entities.Transfer) error {
+	out := &countingWriter{w: bufio.NewWriter(w)}
+	if _, err := out.Write([]byte(parquetMagic)); err != nil {
+		return err
+	}
+
+	var rowGroups []any
+	for start := 0; start < len(transfers); start += rowGroupSize {
+		rg, err := writeRowGroup(out, transfers[start:min(start+rowGroupSize, len(transfers))])
+		if err != nil {
+			return err
+		}
+		rowGroups = append(rowGroups, rg)
+	}
+
+	schema := []any{tStruct{
+		{4, "schema"},
+		{5, int32(len(transferColumns))},
+	}}
+	for _, c := range transferColumns {
+		el := tStruct{{1, c.physical}, {3, int32(0)}, {4, c.name}} // REQUIRED
+		if c.converted >= 0 {
+			el = append(el, tField{6, c.converted})
+		}
+		schema = append(schema, el)
+	}
+
+	footer := tStruct{
+		{1, int32(1)},
+		{2, tList{thriftStruct, schema}},
+		{3, int64(len(transfers))},
+		{4, tList{thriftStruct, rowGroups}},
+		{6, "chain-indexer"},
+	}
+
+	encoded, err := encodeStruct(footer)
+	if err != nil {
+		return fmt.Errorf("failed to write parquet footer: %w", err)
+	}
+	if _, err := out.Write(encoded); err != nil {
+		return err
+	}
+
+	var tail [8]byte
+	binary.LittleEndian.PutUint32(tail[:4], uint32(len(encoded)))
+	copy(tail[4:], parquetMagic)
+	if _, err := out.Write(tail[:]); err != nil {
+		return err
+	}
+	return out.w.Flush()
+}
+
+// writeRowGroup writes one column chunk of a single data page per column and returns the row group metadata
+func writeRowGroup(out *countingWriter, rows []entities.Transfer) (tStruct, error) {
+	var columns []any
+	var totalSize int64
+
+	for _, c := range transferColumns {
+		var plain bytes.Buffer
+		for i := range rows {
+			c.write(&plain, &rows[i])
+		}
+
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		if _, err := gz.Write(plain.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+
+		header, err := encodeStruct(tStruct{
+			{1, int32(pageTypeData)},
+			{2, int32(plain.Len())},
+			{3, int32(compressed.Len())},
+			{5, tStruct{
+				{1, int32(len(rows))},
+				{2, int32(encodingPlain)},
+				{3, int32(encodingRLE)},
+				{4, int32(encodingRLE)},
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		offset := out.n
+		if _, err := out.Write(header); err != nil {
+			return nil, err
+		}
+		if _, err := out.Write(compressed.Bytes()); err != nil {
+			return nil, err
+		}
+
+		uncompressedSize := int64(len(header) + plain.Len())
+		totalSize += uncompressedSize
+		columns = append(columns, tStruct{
+			{2, offset},
+			{3, tStruct{
+				{1, c.physical},
+				{2, tList{thriftI32, []any{int32(encodingPlain), int32(encodingRLE)}}},
+				{3, tList{thriftBinary, []any{c.name}}},
+				{4, int32(codecGzip)},
+				{5, int64(len(rows))},
+				{6, uncompressedSize},
+				{7, int64(len(header) + compressed.Len())},
+				{9, offset},
+			}},
+		})
+	}
+
+	return tStruct{
+		{1, tList{thriftStruct, columns}},
+		{2, totalSize},
+		{3, int64(len(rows))},
+	}, nil
+}
+
+// ReadTransfers decodes a Parquet file written by WriteTransfers. Files from other writers are
+// supported as long as they use the same columns, required fields, PLAIN encoding and no
+// dictionary pages.
+func ReadTransfers(data []byte) ([]entities.Transfer, error) {
+	if len(data) < 12 || string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		return nil, errors.New("not a parquet file")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8 : len(data)-4]))
+	if footerLen <= 0 || footerLen > len(data)-12 {
+		return nil, errors.New("invalid parquet footer length")
+	}
+
+	meta, err := readStruct(bytes.NewReader(data[len(data)-8-footerLen : len(data)-8]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parquet footer: %w", err)
+	}
+
+	columnIndex, err := schemaColumns(meta[2])
+	if err != nil {
+		return nil, err
+	}
+
+	numRows, _ := meta[3].(int64)
+	transfers := make([]entities.Transfer, 0, numRows)
+
+	rowGroups, _ := meta[4].([]any)
+	for _, rgValue := range rowGroups {
+		rg, _ := rgValue.(map[int16]any)
+		rows, _ := rg[3].(int64)
+		base := len(transfers)
+		transfers = append(transfers, make([]entities.Transfer, rows)...)
+
+		chunks, _ := rg[1].([]any)
+		for _, chunkValue := range chunks {
+			chunk, _ := chunkValue.(map[int16]any)
+			cm, _ := chunk[3].(map[int16]any)
+			path, _ := cm[3].([]any)
+			if len(path) != 1 {
+				return nil, errors.New("unsupported nested parquet column")
+			}
+			name, _ := path[0].([]byte)
+			c, ok := columnIndex[string(name)]
+			if !ok {
+				continue
+			}
+			if err := readColumnChunk(data, cm, c, transfers[base:]); err != nil {
+				return nil, fmt.Errorf("column %s: %w", c.name, err)
+			}
+		}
+	}
+
+	return transfers, nil
+}
+
+// schemaColumns checks the file schema holds every archived column as a required leaf of the right type
+func schemaColumns(value any) (map[string]parquetColumn, error) {
+	elements, _ := value.([]any)
+	found := make(map[string]parquetColumn)
+	for _, elValue := range elements[min(1, len(elements)):] {
+		el, _ := elValue.(map[int16]any)
+		name, _ := el[4].([]byte)
+		for _, c := range transferColumns {
+			if c.name != string(name) {
+				continue
+			}
+			if el[1] != int64(c.physical) || el[3] != int64(0) {
+				return nil, fmt.Errorf("parquet column %s must be a required %d", c.name, c.physical)
+			}
+			found[c.name] = c
+		}
+	}
+	for _, c := range transferColumns {
+		if _, ok := found[c.name]; !ok {
+			return nil, fmt.Errorf("parquet file is missing column %s", c.name)
+		}
+	}
+	return found, nil
+}
+
+// readColumnChunk decodes the data pages of a column chunk into rows
+func readColumnChunk(data []byte, cm map[int16]any, c parquetColumn, rows []entities.Transfer) error {
+	codec, _ := cm[4].(int64)
+	offset, _ := cm[9].(int64)
+	if offset <= 0 || offset >= int64(len(data)) {
+		return errors.New("invalid data page offset")
+	}
+
+	pages := bytes.NewReader(data[offset:])
+	row := 0
+	for row < len(rows) {
+		header, err := readStruct(pages)
+		if err != nil {
+			return fmt.Errorf("failed to read page header: %w", err)
+		}
+		if header[1] != int64(pageTypeData) {
+			return fmt.Errorf("unsupported page type %v", header[1])
+		}
+		dph, _ := header[5].(map[int16]any)
+		if dph[2] != int64(encodingPlain) {
+			return fmt.Errorf("unsupported encoding %v", dph[2])
+		}
+		numValues, _ := dph[1].(int64)
+		size, _ := header[3].(int64)
+		if size < 0 || size > int64(pages.Len()) || numValues > int64(len(rows)-row) {
+			return errors.New("invalid page size")
+		}
+
+		raw := make([]byte, size)
+		if _, err := io.ReadFull(pages, raw); err != nil {
+			return err
+		}
+		switch codec {
+		case codecUncompressed:
+		case codecGzip:
+			gz, err := gzip.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				return fmt.Errorf("failed to decompress page: %w", err)
+			}
+			if raw, err = io.ReadAll(gz); err != nil {
+				return fmt.Errorf("failed to decompress page: %w", err)
+			}
+		default:
+			return fmt.Errorf("unsupported compression codec %d", codec)
+		}
+
+		values := bytes.NewReader(raw)
+		for i := int64(0); i < numValues; i++ {
+			if err := c.read(values, &rows[row]); err != nil {
+				return err
+			}
+			row++
+		}
+	}
+	return nil
+}
+
+func writeByteArray(b *bytes.Buffer, s string) {
+	_ = binary.Write(b, binary.LittleEndian, uint32(len(s)))
+	b.WriteString(s)
+}
+
+func readByteArray(r *bytes.Reader) (string, error) {
+	var n uint32
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return "", err
+	}
+	if int64(n) > int64(r.Len()) {
+		return "", io.ErrUnexpectedEOF
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+	return string(b), err
+}
+
+// countingWriter tracks the file offset for column chunk metadata
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
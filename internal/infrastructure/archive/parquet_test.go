@@ -0,0 +1,154 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+func testTransfers(n int) []entities.Transfer {
+	transfers := make([]entities.Transfer, n)
+	for i := range transfers {
+		value, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+		value.Sub(value, big.NewInt(int64(i)))
+		transfers[i] = entities.Transfer{
+			TxHash:         "0xabc",
+			LogIndex:       i % 300,
+			BlockNumber:    19000000 + int64(i),
+			BlockTimestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC).Add(time.Duration(i) * 12 * time.Second),
+			TokenAddress:   "0xdac17f958d2ee523a2206206994597c13d831ec7",
+			FromAddress:    "0x1111111111111111111111111111111111111111",
+			ToAddress:      "0x2222222222222222222222222222222222222222",
+			Value:          value,
+			ValueString:    value.String(),
+		}
+	}
+	return transfers
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	for _, n := range []int{0, 1, 20, rowGroupSize + 3} {
+		want := testTransfers(n)
+
+		var buf bytes.Buffer
+		if err := WriteTransfers(&buf, want); err != nil {
+			t.Fatalf("n=%d: write failed: %v", n, err)
+		}
+
+		got, err := ReadTransfers(buf.Bytes())
+		if err != nil {
+			t.Fatalf("n=%d: read failed: %v", n, err)
+		}
+		if len(got) != n {
+			t.Fatalf("n=%d: expected %d transfers, got %d", n, n, len(got))
+		}
+		for i := range got {
+			g, w := got[i], want[i]
+			if g.TxHash != w.TxHash || g.LogIndex != w.LogIndex || g.BlockNumber != w.BlockNumber ||
+				!g.BlockTimestamp.Equal(w.BlockTimestamp) || g.TokenAddress != w.TokenAddress ||
+				g.FromAddress != w.FromAddress || g.ToAddress != w.ToAddress || g.Value.Cmp(w.Value) != 0 {
+				t.Fatalf("n=%d: transfer %d mismatch: got %+v, want %+v", n, i, g, w)
+			}
+		}
+	}
+}
+
+func TestReadTransfers_RejectsInvalidFiles(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTransfers(&buf, testTransfers(3)); err != nil {
+		t.Fatal(err)
+	}
+	truncated := buf.Bytes()[:buf.Len()-20]
+
+	for name, data := range map[string][]byte{
+		"empty":       nil,
+		"not parquet": []byte("address,label\n0x1111,alice\n"),
+		"truncated":   append(truncated, []byte(parquetMagic)...),
+	} {
+		if _, err := ReadTransfers(data); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// pyarrowReadScript prints the rows of a Parquet file read by pyarrow as JSON, with timestamps as
+// epoch milliseconds
+const pyarrowReadScript = `
+import json, sys
+import pyarrow as pa, pyarrow.parquet as pq
+t = pq.read_table(sys.argv[1])
+i = t.schema.get_field_index("block_timestamp")
+t = t.set_column(i, "block_timestamp", t.column(i).cast(pa.int64()))
+json.dump({"columns": t.schema.names, "rows": t.to_pylist()}, sys.stdout)
+`
+
+// TestParquetReferenceReader checks that the files WriteTransfers produces are read back
+// unchanged by pyarrow, the reference Parquet implementation. It is skipped where pyarrow is
+// not installed; CI installs it.
+func TestParquetReferenceReader(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err == nil {
+		err = exec.Command(python, "-c", "import pyarrow.parquet").Run()
+	}
+	if err != nil {
+		t.Skip("pyarrow is not installed")
+	}
+
+	want := testTransfers(rowGroupSize + 3)
+	var buf bytes.Buffer
+	if err := WriteTransfers(&buf, want); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "transfers.parquet")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command(python, "-c", pyarrowReadScript, path).Output()
+	if err != nil {
+		t.Fatalf("pyarrow failed to read the file: %v", err)
+	}
+	var got struct {
+		Columns []string `json:"columns"`
+		Rows    []struct {
+			TokenAddress   string `json:"token_address"`
+			TxHash         string `json:"tx_hash"`
+			LogIndex       int    `json:"log_index"`
+			BlockNumber    int64  `json:"block_number"`
+			BlockTimestamp int64  `json:"block_timestamp"`
+			FromAddress    string `json:"from_address"`
+			ToAddress      string `json:"to_address"`
+			Value          string `json:"value"`
+		} `json:"rows"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("failed to decode pyarrow output: %v", err)
+	}
+
+	if len(got.Columns) != len(transferColumns) {
+		t.Fatalf("expected %d columns, got %v", len(transferColumns), got.Columns)
+	}
+	for i, c := range transferColumns {
+		if got.Columns[i] != c.name {
+			t.Errorf("column %d: expected %s, got %s", i, c.name, got.Columns[i])
+		}
+	}
+	if len(got.Rows) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(got.Rows))
+	}
+	for i, g := range got.Rows {
+		w := want[i]
+		if g.TxHash != w.TxHash || g.LogIndex != w.LogIndex || g.BlockNumber != w.BlockNumber ||
+			g.BlockTimestamp != w.BlockTimestamp.UnixMilli() || g.TokenAddress != w.TokenAddress ||
+			g.FromAddress != w.FromAddress || g.ToAddress != w.ToAddress || g.Value != w.Value.String() {
+			t.Fatalf("row %d mismatch: got %+v, want %+v", i, g, w)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+// ErrNotFound is returned by ObjectStore.Get when the key does not exist
+var ErrNotFound = errors.New("object not found")
+
+// ObjectStore stores archive files and the manifest under string keys
+type ObjectStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// Open returns the object store for cfg.URL:
+//   - s3://bucket/prefix for Amazon S3 or an S3-compatible endpoint (ARCHIVE_S3_ENDPOINT)
+//   - gs://bucket/prefix for Google Cloud Storage through its S3-compatible API (HMAC keys)
+//   - file:///path for a local directory
+func Open(cfg config.ArchiveConfig) (ObjectStore, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive URL: %w", err)
+	}
+	prefix := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case "s3":
+		endpoint := cfg.S3Endpoint
+		if endpoint == "" {
+			endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.S3Region)
+		}
+		return NewS3Store(endpoint, cfg.S3Region, u.Host, prefix, cfg.AccessKeyID, cfg.SecretAccessKey, cfg.Timeout), nil
+	case "gs":
+		return NewS3Store("https://storage.googleapis.com", "auto", u.Host, prefix, cfg.AccessKeyID, cfg.SecretAccessKey, cfg.Timeout), nil
+	case "file":
+		return NewFileStore(u.Path), nil
+	default:
+		return nil, fmt.Errorf("unsupported archive URL scheme %q (available: s3, gs, file)", u.Scheme)
+	}
+}
+
+// FileStore keeps objects as files below a local directory
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a file store rooted at dir
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Put writes an object, replacing it atomically if it exists
+func (s *FileStore) Put(ctx context.Context, key string, data []byte) error {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get reads an object
+func (s *FileStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return data, nil
+}
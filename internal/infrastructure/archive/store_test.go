@@ -0,0 +1,144 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(t.TempDir())
+
+	if _, err := store.Get(ctx, "missing.parquet"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	key := FileKey("0xDAC17F958D2ee523a2206206994597C13D831ec7", 0, 99999)
+	if err := store.Put(ctx, key, []byte("data")); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	data, err := store.Get(ctx, key)
+	if err != nil || string(data) != "data" {
+		t.Errorf("expected stored data, got %q (%v)", data, err)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	objects := make(map[string][]byte)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(auth, "/us-east-1/s3/aws4_request") {
+			t.Errorf("unexpected authorization header %q", auth)
+		}
+		if r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+			t.Error("expected Signature V4 date and payload hash headers")
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(data)
+		}
+	}))
+	defer server.Close()
+
+	store := NewS3Store(server.URL, "us-east-1", "archive", "chain-indexer", "AKID", "secret", time.Second)
+
+	if err := store.Put(ctx, ManifestKey, []byte(`{"files":[]}`)); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if _, ok := objects["/archive/chain-indexer/manifest.json"]; !ok {
+		t.Errorf("expected path-style key below prefix, got %v", objects)
+	}
+
+	data, err := store.Get(ctx, ManifestKey)
+	if err != nil || string(data) != `{"files":[]}` {
+		t.Errorf("expected stored data, got %q (%v)", data, err)
+	}
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	for rawURL, want := range map[string]string{
+		"s3://bucket/prefix":  "*archive.S3Store",
+		"gs://bucket":         "*archive.S3Store",
+		"file:///tmp/archive": "*archive.FileStore",
+		"ftp://example.com/x": "",
+		"://not a url":        "",
+	} {
+		store, err := Open(config.ArchiveConfig{URL: rawURL, S3Region: "us-east-1"})
+		if want == "" {
+			if err == nil {
+				t.Errorf("%s: expected error", rawURL)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", rawURL, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", store); got != want {
+			t.Errorf("%s: expected %s, got %s", rawURL, want, got)
+		}
+	}
+}
+
+func TestManifest(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(t.TempDir())
+
+	m, err := LoadManifest(ctx, store)
+	if err != nil || len(m.Files) != 0 {
+		t.Fatalf("expected empty manifest, got %+v (%v)", m, err)
+	}
+
+	const token = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+	m.Add(ManifestFile{TokenAddress: token, FromBlock: 100000, ToBlock: 199999, Rows: 5})
+	m.Add(ManifestFile{TokenAddress: token, FromBlock: 0, ToBlock: 99999, Rows: 3})
+	m.Add(ManifestFile{TokenAddress: token, FromBlock: 100000, ToBlock: 199999, Rows: 6})
+
+	if err := SaveManifest(ctx, store, m); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	m, err = LoadManifest(ctx, store)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	if len(m.Files) != 2 || m.Files[0].FromBlock != 0 || m.Files[1].Rows != 6 {
+		t.Errorf("unexpected files: %+v", m.Files)
+	}
+	if last := m.LastArchivedBlock(token); last != 199999 {
+		t.Errorf("expected last archived block 199999, got %d", last)
+	}
+	if last := m.LastArchivedBlock("0xother"); last != -1 {
+		t.Errorf("expected -1 for unarchived token, got %d", last)
+	}
+	if files := m.Overlapping(token, 99999, 100000); len(files) != 2 {
+		t.Errorf("expected both files to overlap, got %d", len(files))
+	}
+	if files := m.Overlapping(token, 200000, 300000); len(files) != 0 {
+		t.Errorf("expected no overlap, got %d", len(files))
+	}
+}
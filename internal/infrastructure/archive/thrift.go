@@ -0,0 +1,254 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Parquet file and page metadata is serialized with the Thrift compact protocol.
+// Only the subset Parquet uses is implemented: structs, lists, i32, i64, binary and bool.
+
+// Thrift compact protocol type ids
+const (
+	thriftBoolTrue  = 1
+	thriftBoolFalse = 2
+	thriftI32       = 5
+	thriftI64       = 6
+	thriftBinary    = 8
+	thriftList      = 9
+	thriftStruct    = 12
+)
+
+// tField is one field of a Thrift struct. value is int32, int64, bool, string, []byte, tStruct or tList.
+type tField struct {
+	id    int16
+	value any
+}
+
+// tStruct is a Thrift struct; fields must be in ascending id order
+type tStruct []tField
+
+// tList is a Thrift list whose items are all of elemType
+type tList struct {
+	elemType byte
+	items    []any
+}
+
+// encodeStruct returns the compact protocol encoding of s
+func encodeStruct(s tStruct) ([]byte, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	if err := writeStruct(w, s); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeStruct encodes s in the compact protocol
+func writeStruct(w *bufio.Writer, s tStruct) error {
+	var lastID int16
+	for _, f := range s {
+		typ, err := thriftType(f.value)
+		if err != nil {
+			return fmt.Errorf("field %d: %w", f.id, err)
+		}
+		if b, ok := f.value.(bool); ok && !b {
+			typ = thriftBoolFalse
+		}
+
+		if delta := f.id - lastID; delta > 0 && delta <= 15 {
+			_ = w.WriteByte(byte(delta)<<4 | typ)
+		} else {
+			_ = w.WriteByte(typ)
+			writeVarint(w, zigzag(int64(f.id)))
+		}
+		lastID = f.id
+
+		if typ == thriftBoolTrue || typ == thriftBoolFalse {
+			continue
+		}
+		if err := writeValue(w, f.value); err != nil {
+			return fmt.Errorf("field %d: %w", f.id, err)
+		}
+	}
+	return w.WriteByte(0) // stop
+}
+
+// writeValue encodes a non-bool value
+func writeValue(w *bufio.Writer, v any) error {
+	switch v := v.(type) {
+	case int32:
+		writeVarint(w, zigzag(int64(v)))
+	case int64:
+		writeVarint(w, zigzag(v))
+	case string:
+		writeVarint(w, uint64(len(v)))
+		_, _ = w.WriteString(v)
+	case []byte:
+		writeVarint(w, uint64(len(v)))
+		_, _ = w.Write(v)
+	case tStruct:
+		return writeStruct(w, v)
+	case tList:
+		if len(v.items) < 15 {
+			_ = w.WriteByte(byte(len(v.items))<<4 | v.elemType)
+		} else {
+			_ = w.WriteByte(0xf0 | v.elemType)
+			writeVarint(w, uint64(len(v.items)))
+		}
+		for _, item := range v.items {
+			if err := writeValue(w, item); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported thrift value %T", v)
+	}
+	return nil
+}
+
+// thriftType returns the compact type id of a Go value
+func thriftType(v any) (byte, error) {
+	switch v.(type) {
+	case bool:
+		return thriftBoolTrue, nil
+	case int32:
+		return thriftI32, nil
+	case int64:
+		return thriftI64, nil
+	case string, []byte:
+		return thriftBinary, nil
+	case tList:
+		return thriftList, nil
+	case tStruct:
+		return thriftStruct, nil
+	default:
+		return 0, fmt.Errorf("unsupported thrift value %T", v)
+	}
+}
+
+func writeVarint(w *bufio.Writer, v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	_, _ = w.Write(buf[:n])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// readStruct decodes a compact protocol struct into a map of field id to value.
+// i32 and i64 both decode to int64, binary to []byte, lists to []any and structs to map[int16]any.
+func readStruct(r io.ByteReader) (map[int16]any, error) {
+	fields := make(map[int16]any)
+	var lastID int16
+	for {
+		header, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if header == 0 {
+			return fields, nil
+		}
+
+		typ := header & 0x0f
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			raw, err := binary.ReadUvarint(r)
+			if err != nil {
+				return nil, err
+			}
+			id = int16(unzigzag(raw))
+		}
+		lastID = id
+
+		var value any
+		switch typ {
+		case thriftBoolTrue:
+			value = true
+		case thriftBoolFalse:
+			value = false
+		default:
+			if value, err = readValue(r, typ); err != nil {
+				return nil, fmt.Errorf("field %d: %w", id, err)
+			}
+		}
+		fields[id] = value
+	}
+}
+
+// readValue decodes a value of the given compact type
+func readValue(r io.ByteReader, typ byte) (any, error) {
+	switch typ {
+	case 3: // byte
+		b, err := r.ReadByte()
+		return int64(int8(b)), err
+	case 4, thriftI32, thriftI64: // i16, i32, i64
+		raw, err := binary.ReadUvarint(r)
+		return unzigzag(raw), err
+	case 7: // double
+		for i := 0; i < 8; i++ {
+			if _, err := r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case thriftBinary:
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		if n > 1<<30 {
+			return nil, errors.New("binary field too large")
+		}
+		b := make([]byte, n)
+		for i := range b {
+			if b[i], err = r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case thriftList, 10: // list, set
+		header, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(header >> 4)
+		if size == 15 {
+			if size, err = binary.ReadUvarint(r); err != nil {
+				return nil, err
+			}
+		}
+		elemType := header & 0x0f
+		items := make([]any, 0, min(size, 1024))
+		for i := uint64(0); i < size; i++ {
+			var item any
+			if elemType == thriftBoolTrue || elemType == thriftBoolFalse {
+				b, err := r.ReadByte()
+				if err != nil {
+					return nil, err
+				}
+				item = b == thriftBoolTrue
+			} else if item, err = readValue(r, elemType); err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case thriftStruct:
+		return readStruct(r)
+	default:
+		return nil, fmt.Errorf("unsupported thrift type %d", typ)
+	}
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
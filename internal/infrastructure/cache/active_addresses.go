@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure ActiveAddressSketches implements ActiveAddressRepository
+var _ repositories.ActiveAddressRepository = (*ActiveAddressSketches)(nil)
+
+// ActiveAddressSketches keeps one Redis HyperLogLog per token per UTC day.
+// Counting a range merges the daily sketches, so the cost depends on the number of days, not transfers.
+// Counts have a standard error of about 0.81%.
+type ActiveAddressSketches struct {
+	cache *RedisCache
+}
+
+// NewActiveAddressSketches creates active address sketches stored in the given Redis cache
+func NewActiveAddressSketches(cache *RedisCache) *ActiveAddressSketches {
+	return &ActiveAddressSketches{cache: cache}
+}
+
+// activeAddressKey returns the sketch key of a token's UTC day
+func activeAddressKey(tokenAddress string, day time.Time) string {
+	return fmt.Sprintf("hll:active:%s:%s", tokenAddress, day.UTC().Format("2006-01-02"))
+}
+
+// AddActiveAddresses records addresses as active for a token on the UTC day containing day
+func (s *ActiveAddressSketches) AddActiveAddresses(ctx context.Context, tokenAddress string, day time.Time, addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	if err := s.cache.client.PFAdd(ctx, activeAddressKey(tokenAddress, day), hllMembers(addresses)...).Err(); err != nil {
+		return fmt.Errorf("failed to add active addresses: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceActiveAddresses resets a token's UTC day to exactly the given addresses
+func (s *ActiveAddressSketches) ReplaceActiveAddresses(ctx context.Context, tokenAddress string, day time.Time, addresses []string) error {
+	key := activeAddressKey(tokenAddress, day)
+
+	_, err := s.cache.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(addresses) > 0 {
+			pipe.PFAdd(ctx, key, hllMembers(addresses)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace active addresses: %w", err)
+	}
+
+	return nil
+}
+
+func hllMembers(addresses []string) []interface{} {
+	members := make([]interface{}, len(addresses))
+	for i, addr := range addresses {
+		members[i] = addr
+	}
+	return members
+}
+
+// CountActiveAddresses returns the approximate number of distinct addresses active on any UTC day in [from, to]
+func (s *ActiveAddressSketches) CountActiveAddresses(ctx context.Context, tokenAddress string, from, to time.Time) (int64, error) {
+	from = from.UTC().Truncate(24 * time.Hour)
+	to = to.UTC().Truncate(24 * time.Hour)
+
+	var keys []string
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		keys = append(keys, activeAddressKey(tokenAddress, day))
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	count, err := s.cache.client.PFCount(ctx, keys...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active addresses: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,40 @@
+//go:build integration
+
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+	"github.com/bimakw/chain-indexer/internal/testutil/integration"
+)
+
+func TestActiveAddressSketches_Integration(t *testing.T) {
+	sketches := cache.NewActiveAddressSketches(integration.Redis(t, time.Minute))
+	ctx := context.Background()
+	day := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	next := day.AddDate(0, 0, 1)
+
+	if err := sketches.AddActiveAddresses(ctx, testutil.USDTAddress, day, []string{testutil.AliceAddress, testutil.BobAddress}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sketches.AddActiveAddresses(ctx, testutil.USDTAddress, next, []string{testutil.BobAddress, testutil.CharlieAddr}); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := sketches.CountActiveAddresses(ctx, testutil.USDTAddress, day, next)
+	if err != nil || count != 3 {
+		t.Errorf("expected 3 distinct addresses over both days, got %d (%v)", count, err)
+	}
+
+	if err := sketches.ReplaceActiveAddresses(ctx, testutil.USDTAddress, next, nil); err != nil {
+		t.Fatal(err)
+	}
+	count, err = sketches.CountActiveAddresses(ctx, testutil.USDTAddress, next, next)
+	if err != nil || count != 0 {
+		t.Errorf("expected replaced day to be empty, got %d (%v)", count, err)
+	}
+}
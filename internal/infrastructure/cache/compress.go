@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Values at least REDIS_COMPRESS_MIN_BYTES long are stored as zstd frames. JSON never starts with
+// the frame magic number, so values are told apart by it and those written before compression
+// was enabled are still read.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// maxDecompressedBytes bounds the memory decoding one cached value may take
+const maxDecompressedBytes = 64 << 20
+
+// The encoder and decoder are safe for concurrent EncodeAll and DecodeAll calls
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedBytes), zstd.WithDecoderConcurrency(0))
+)
+
+var (
+	compressionRatio = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cache_compression_ratio",
+			Help:    "Uncompressed to compressed size of cache values stored compressed, by key prefix",
+			Buckets: []float64{1, 1.5, 2, 3, 5, 8, 13, 20},
+		},
+		[]string{"prefix"},
+	)
+	compressionSeconds = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cache_compression_seconds",
+			Help:    "Time spent compressing and decompressing cache values, by operation (compress or decompress)",
+			Buckets: []float64{.00005, .0001, .00025, .0005, .001, .0025, .005, .01, .025},
+		},
+		[]string{"operation"},
+	)
+)
+
+// compressValue returns data as a zstd frame when it is at least minBytes long, and as is
+// otherwise or when minBytes is 0 or compression would not make it smaller
+func compressValue(key string, data []byte, minBytes int) []byte {
+	if minBytes <= 0 || len(data) < minBytes {
+		return data
+	}
+
+	start := time.Now()
+	compressed := zstdEncoder.EncodeAll(data, make([]byte, 0, len(data)/4))
+	compressionSeconds.WithLabelValues("compress").Observe(time.Since(start).Seconds())
+
+	if len(compressed) >= len(data) {
+		return data
+	}
+	compressionRatio.WithLabelValues(KeyPrefix(key)).Observe(float64(len(data)) / float64(len(compressed)))
+	return compressed
+}
+
+// decompressValue returns the JSON of a stored value, decoding it if it is a zstd frame
+func decompressValue(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, zstdMagic) {
+		return data, nil
+	}
+
+	start := time.Now()
+	decoded, err := zstdDecoder.DecodeAll(data, nil)
+	compressionSeconds.WithLabelValues("decompress").Observe(time.Since(start).Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress cached value: %w", err)
+	}
+	return decoded, nil
+}
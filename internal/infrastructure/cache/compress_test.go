@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCompressValue(t *testing.T) {
+	rows := make([]map[string]string, 500)
+	for i := range rows {
+		rows[i] = map[string]string{"address": "0xdac17f958d2ee523a2206206994597c13d831ec7", "balance": "1000000"}
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compressed := compressValue("holders:0xdac17f958d2ee523a2206206994597c13d831ec7:20:0", data, 1024)
+	if !bytes.HasPrefix(compressed, zstdMagic) || len(compressed) >= len(data) {
+		t.Fatalf("expected a smaller zstd frame, got %d of %d bytes", len(compressed), len(data))
+	}
+	decoded, err := decompressValue(compressed)
+	if err != nil || !bytes.Equal(decoded, data) {
+		t.Fatalf("expected the value back, got %d bytes (%v)", len(decoded), err)
+	}
+
+	// Small values, and all values with compression disabled, are stored as they are
+	small := []byte(`{"total":3}`)
+	if got := compressValue("stats:x", small, 1024); !bytes.Equal(got, small) {
+		t.Errorf("expected a small value left as is, got %q", got)
+	}
+	if got := compressValue("holders:x", data, 0); !bytes.Equal(got, data) {
+		t.Error("expected no compression with a threshold of 0")
+	}
+
+	// Values written uncompressed are read as they are
+	if got, err := decompressValue(small); err != nil || !bytes.Equal(got, small) {
+		t.Errorf("expected an uncompressed value read as is, got %q (%v)", got, err)
+	}
+	if _, err := decompressValue(append(append([]byte{}, zstdMagic...), 0xff, 0xff)); err == nil {
+		t.Error("expected a corrupt frame to fail")
+	}
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IndexedTokensChannel carries the address of each token the indexer has stored new transfers for
+const IndexedTokensChannel = "indexed_tokens"
+
+// subscriptionBuffer is how many messages a subscription holds for a busy receiver before dropping more
+const subscriptionBuffer = 256
+
+// Publish sends message to the current subscribers of channel. Messages are not queued, so
+// subscribers that are not listening miss them.
+func (c *RedisCache) Publish(ctx context.Context, channel, message string) error {
+	if c.mem != nil {
+		c.mem.publish(channel, message)
+		return nil
+	}
+	if err := c.client.Publish(ctx, channel, message).Err(); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", channel, err)
+	}
+	return nil
+}
+
+// Subscribe returns the messages published to channel until ctx is done, when the returned
+// channel is closed. Messages arriving while the receiver is busy are buffered, and dropped
+// once the buffer is full. Redis subscriptions reconnect on their own after connection errors.
+func (c *RedisCache) Subscribe(ctx context.Context, channel string) <-chan string {
+	out := make(chan string, subscriptionBuffer)
+	if c.mem != nil {
+		c.mem.subscribe(ctx, channel, out)
+		return out
+	}
+
+	pubsub := c.client.Subscribe(ctx, channel)
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case out <- msg.Payload:
+				default:
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// TryLock sets key for ttl unless it is already set, and reports whether it did, so only one
+// of several processes sharing the cache takes on a piece of work
+func (c *RedisCache) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if c.mem != nil {
+		return c.mem.setNX(key, []byte("1"), ttl), nil
+	}
+	ok, err := c.client.SetNX(ctx, key, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to lock %s: %w", key, err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// NewMemoryCache creates a cache held in process memory, for single-process runs without Redis.
+// Entries are lost on restart and are not shared with other processes; Client returns nil.
+func NewMemoryCache(ttl time.Duration, logger *zap.Logger) *RedisCache {
+	return &RedisCache{
+		mem:    &memoryStore{entries: make(map[string]memoryEntry)},
+		logger: logger,
+		ttl:    ttl,
+	}
+}
+
+// memoryStore is the backing map of a memory cache, and the subscribers of its channels
+type memoryStore struct {
+	mu          sync.Mutex
+	entries     map[string]memoryEntry
+	subscribers map[string][]chan string
+	scores      map[string]map[string]float64 // Sorted sets by key, member to score
+}
+
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time // zero never expires
+}
+
+func (m *memoryStore) get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return entry.data, true
+}
+
+func (m *memoryStore) set(key string, data []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := memoryEntry{data: data}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	m.entries[key] = entry
+}
+
+// setNX sets key unless it holds an unexpired entry and reports whether it did
+func (m *memoryStore) setNX(key string, data []byte, ttl time.Duration) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, ok := m.entries[key]; ok && (entry.expiresAt.IsZero() || time.Now().Before(entry.expiresAt)) {
+		return false
+	}
+	entry := memoryEntry{data: data}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	m.entries[key] = entry
+	return true
+}
+
+func (m *memoryStore) delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+// deletePattern removes the keys matching a glob pattern, as Redis SCAN MATCH does
+func (m *memoryStore) deletePattern(pattern string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.entries {
+		matched, err := path.Match(pattern, key)
+		if err != nil {
+			return err
+		}
+		if matched {
+			delete(m.entries, key)
+		}
+	}
+	return nil
+}
+
+// publish hands message to each subscriber of channel with room for it
+func (m *memoryStore) publish(channel, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, sub := range m.subscribers[channel] {
+		select {
+		case sub <- message:
+		default:
+		}
+	}
+}
+
+// subscribe adds out to the subscribers of channel, and removes and closes it once ctx is done
+func (m *memoryStore) subscribe(ctx context.Context, channel string, out chan string) {
+	m.mu.Lock()
+	if m.subscribers == nil {
+		m.subscribers = make(map[string][]chan string)
+	}
+	m.subscribers[channel] = append(m.subscribers[channel], out)
+	m.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		subs := m.subscribers[channel]
+		for i, sub := range subs {
+			if sub == out {
+				m.subscribers[channel] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(out)
+	}()
+}
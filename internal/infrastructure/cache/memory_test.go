@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+)
+
+func TestMemoryCache(t *testing.T) {
+	c := NewMemoryCache(time.Minute, zap.NewNop())
+	ctx := context.Background()
+
+	var got map[string]int
+	if err := c.Get(ctx, "stats:a", &got); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("expected a miss, got %v", err)
+	}
+
+	for _, key := range []string{"stats:a", "stats:b", "tokens:a"} {
+		if err := c.Set(ctx, key, map[string]int{"n": 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Get(ctx, "stats:a", &got); err != nil || got["n"] != 1 {
+		t.Errorf("expected the stored value, got %v (%v)", got, err)
+	}
+
+	if err := c.DeletePattern(ctx, "stats:*"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, "stats:b", &got); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected stats:b deleted by the pattern, got %v", err)
+	}
+	if err := c.Get(ctx, "tokens:a", &got); err != nil {
+		t.Errorf("expected tokens:a kept, got %v", err)
+	}
+
+	if err := c.SetWithTTL(ctx, "short", 1, time.Nanosecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	var n int
+	if err := c.Get(ctx, "short", &n); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected the entry to expire, got %v", err)
+	}
+}
+
+func TestMemoryCache_PublishSubscribe(t *testing.T) {
+	c := NewMemoryCache(time.Minute, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	messages := c.Subscribe(ctx, IndexedTokensChannel)
+	if err := c.Publish(ctx, IndexedTokensChannel, "0xabc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Publish(ctx, "other", "0xdef"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-messages:
+		if msg != "0xabc" {
+			t.Errorf("expected 0xabc, got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the published message")
+	}
+
+	cancel()
+	select {
+	case msg, ok := <-messages:
+		if ok {
+			t.Errorf("expected the subscription to close, got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the subscription to close")
+	}
+	// Publishing after the subscriber left must not block or panic
+	if err := c.Publish(context.Background(), IndexedTokensChannel, "0xabc"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryCache_TryLock(t *testing.T) {
+	c := NewMemoryCache(time.Minute, zap.NewNop())
+	ctx := context.Background()
+
+	if ok, err := c.TryLock(ctx, "warm_lock:0xabc", time.Minute); err != nil || !ok {
+		t.Fatalf("expected the first lock to succeed, got %v (%v)", ok, err)
+	}
+	if ok, _ := c.TryLock(ctx, "warm_lock:0xabc", time.Minute); ok {
+		t.Error("expected a held lock to be refused")
+	}
+
+	if ok, _ := c.TryLock(ctx, "warm_lock:0xdef", time.Nanosecond); !ok {
+		t.Fatal("expected a lock on another key to succeed")
+	}
+	time.Sleep(time.Millisecond)
+	if ok, _ := c.TryLock(ctx, "warm_lock:0xdef", time.Minute); !ok {
+		t.Error("expected an expired lock to be taken again")
+	}
+}
+
+func TestMemoryCache_Scores(t *testing.T) {
+	c := NewMemoryCache(time.Minute, zap.NewNop())
+	ctx := context.Background()
+
+	if err := c.IncrementScores(ctx, "hot", map[string]float64{"a": 1, "b": 4, "c": 2}); err != nil {
+		t.Fatal(err)
+	}
+	_ = c.IncrementScores(ctx, "hot", map[string]float64{"a": 4})
+
+	top, err := c.TopScores(ctx, "hot", 2, 2)
+	if err != nil || len(top) != 2 || top[0] != "a" || top[1] != "b" {
+		t.Errorf("expected a and b highest first, got %v (%v)", top, err)
+	}
+
+	// Halving drops c below the minimum of 1.5
+	if err := c.DecayScores(ctx, "hot", 0.5, 1.5); err != nil {
+		t.Fatal(err)
+	}
+	if top, _ := c.TopScores(ctx, "hot", 10, 0); len(top) != 2 || top[0] != "a" || top[1] != "b" {
+		t.Errorf("expected c removed by the decay, got %v", top)
+	}
+}
+
+func TestCacheLookupMetrics(t *testing.T) {
+	c := NewMemoryCache(time.Minute, zap.NewNop())
+	ctx := context.Background()
+
+	hits := cacheLookups.WithLabelValues("portfolio", resultHit)
+	misses := cacheLookups.WithLabelValues("portfolio", resultMiss)
+	errs := cacheLookups.WithLabelValues("portfolio", resultError)
+	hitsBefore, missesBefore, errsBefore := testutil.ToFloat64(hits), testutil.ToFloat64(misses), testutil.ToFloat64(errs)
+
+	var got int
+	_ = c.Get(ctx, "portfolio:0xabc", &got)
+	if err := c.Set(ctx, "portfolio:0xabc", 1); err != nil {
+		t.Fatal(err)
+	}
+	_ = c.Get(ctx, "portfolio:0xabc", &got)
+	var wrongType []string
+	_ = c.Get(ctx, "portfolio:0xabc", &wrongType)
+
+	if d := testutil.ToFloat64(hits) - hitsBefore; d != 1 {
+		t.Errorf("expected 1 hit, got %v", d)
+	}
+	if d := testutil.ToFloat64(misses) - missesBefore; d != 1 {
+		t.Errorf("expected 1 miss, got %v", d)
+	}
+	if d := testutil.ToFloat64(errs) - errsBefore; d != 1 {
+		t.Errorf("expected 1 error, got %v", d)
+	}
+}
+
+func TestKeyPrefix(t *testing.T) {
+	tests := map[string]string{
+		"stats:0xabc":         "stats",
+		"tokens:list:10:0":    "tokens",
+		"stale:holders:0xabc": "stale",
+		"unprefixed":          "unprefixed",
+	}
+	for key, want := range tests {
+		if got := KeyPrefix(key); got != want {
+			t.Errorf("KeyPrefix(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Cache lookup results
+const (
+	resultHit   = "hit"
+	resultMiss  = "miss"
+	resultError = "error"
+)
+
+var cacheLookups = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cache_lookups_total",
+		Help: "Cache lookups by key prefix and result: hit, miss or error",
+	},
+	[]string{"prefix", "result"},
+)
+
+// KeyPrefix returns the part of a cache key before its first colon, which names the cached
+// resource: "transfers", "stats", "holders", "portfolio" and so on. Stale copies kept for
+// database outages are labelled "stale".
+func KeyPrefix(key string) string {
+	prefix, _, _ := strings.Cut(key, ":")
+	return prefix
+}
+
+func recordLookup(ctx context.Context, key, result string) {
+	cacheLookups.WithLabelValues(KeyPrefix(key), result).Inc()
+	if lookups, ok := ctx.Value(requestLookupsKey{}).(*RequestLookups); ok {
+		if result == resultHit {
+			lookups.hits.Add(1)
+		} else {
+			lookups.misses.Add(1)
+		}
+	}
+}
+
+// RequestLookups counts the cache lookups made while serving one request, for its access log
+type RequestLookups struct {
+	hits   atomic.Int64
+	misses atomic.Int64 // Including failed lookups
+}
+
+type requestLookupsKey struct{}
+
+// ContextWithRequestLookups returns a context whose cache lookups are counted in lookups
+func ContextWithRequestLookups(ctx context.Context, lookups *RequestLookups) context.Context {
+	return context.WithValue(ctx, requestLookupsKey{}, lookups)
+}
+
+// Counts returns the number of lookups that hit and that missed or failed
+func (l *RequestLookups) Counts() (hits, misses int64) {
+	return l.hits.Load(), l.misses.Load()
+}
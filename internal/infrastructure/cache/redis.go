@@ -13,11 +13,15 @@ import (
 	"github.com/bimakw/chain-indexer/internal/config"
 )
 
-// RedisCache provides caching functionality using Redis
+// RedisCache provides caching functionality using Redis, or process memory when created
+// with NewMemoryCache
 type RedisCache struct {
 	client *redis.Client
+	mem    *memoryStore // Used instead of client by memory caches
 	logger *zap.Logger
 	ttl    time.Duration
+
+	compressMinBytes int // Values this long or longer are stored compressed, 0 disables
 }
 
 // NewRedisCache creates a new Redis cache instance
@@ -41,20 +45,48 @@ func NewRedisCache(cfg config.RedisConfig, ttl time.Duration, logger *zap.Logger
 	)
 
 	return &RedisCache{
-		client: client,
-		logger: logger,
-		ttl:    ttl,
+		client:           client,
+		logger:           logger,
+		ttl:              ttl,
+		compressMinBytes: cfg.CompressMinBytes,
 	}, nil
 }
 
 // Close closes the Redis connection
 func (c *RedisCache) Close() error {
+	if c.mem != nil {
+		return nil
+	}
 	return c.client.Close()
 }
 
-// Get retrieves a value from cache
+// Get retrieves a value from cache, counting the lookup in cache_lookups_total
 func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
-	val, err := c.client.Get(ctx, key).Result()
+	err := c.get(ctx, key, dest)
+	switch {
+	case err == nil:
+		recordLookup(ctx, key, resultHit)
+	case errors.Is(err, ErrCacheMiss):
+		recordLookup(ctx, key, resultMiss)
+	default:
+		recordLookup(ctx, key, resultError)
+	}
+	return err
+}
+
+func (c *RedisCache) get(ctx context.Context, key string, dest interface{}) error {
+	if c.mem != nil {
+		data, ok := c.mem.get(key)
+		if !ok {
+			return ErrCacheMiss
+		}
+		if err := json.Unmarshal(data, dest); err != nil {
+			return fmt.Errorf("failed to unmarshal cached value: %w", err)
+		}
+		return nil
+	}
+
+	val, err := c.client.Get(ctx, key).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return ErrCacheMiss
@@ -62,7 +94,11 @@ func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) erro
 		return fmt.Errorf("failed to get from cache: %w", err)
 	}
 
-	if err := json.Unmarshal([]byte(val), dest); err != nil {
+	data, err := decompressValue(val)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
 		return fmt.Errorf("failed to unmarshal cached value: %w", err)
 	}
 
@@ -71,16 +107,7 @@ func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) erro
 
 // Set stores a value in cache
 func (c *RedisCache) Set(ctx context.Context, key string, value interface{}) error {
-	data, err := json.Marshal(value)
-	if err != nil {
-		return fmt.Errorf("failed to marshal value: %w", err)
-	}
-
-	if err := c.client.Set(ctx, key, data, c.ttl).Err(); err != nil {
-		return fmt.Errorf("failed to set cache: %w", err)
-	}
-
-	return nil
+	return c.SetWithTTL(ctx, key, value, c.ttl)
 }
 
 // SetWithTTL stores a value in cache with custom TTL
@@ -90,6 +117,12 @@ func (c *RedisCache) SetWithTTL(ctx context.Context, key string, value interface
 		return fmt.Errorf("failed to marshal value: %w", err)
 	}
 
+	if c.mem != nil {
+		c.mem.set(key, data, ttl)
+		return nil
+	}
+
+	data = compressValue(key, data, c.compressMinBytes)
 	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
 		return fmt.Errorf("failed to set cache: %w", err)
 	}
@@ -99,6 +132,10 @@ func (c *RedisCache) SetWithTTL(ctx context.Context, key string, value interface
 
 // Delete removes a value from cache
 func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	if c.mem != nil {
+		c.mem.delete(key)
+		return nil
+	}
 	if err := c.client.Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete from cache: %w", err)
 	}
@@ -107,6 +144,9 @@ func (c *RedisCache) Delete(ctx context.Context, key string) error {
 
 // DeletePattern removes all keys matching a pattern
 func (c *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
+	if c.mem != nil {
+		return c.mem.deletePattern(pattern)
+	}
 	iter := c.client.Scan(ctx, 0, pattern, 0).Iterator()
 	for iter.Next(ctx) {
 		if err := c.client.Del(ctx, iter.Val()).Err(); err != nil {
@@ -121,10 +161,13 @@ func (c *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
 
 // HealthCheck checks if Redis is reachable
 func (c *RedisCache) HealthCheck(ctx context.Context) error {
+	if c.mem != nil {
+		return nil
+	}
 	return c.client.Ping(ctx).Err()
 }
 
-// Client returns the underlying Redis client
+// Client returns the underlying Redis client, nil for a memory cache
 func (c *RedisCache) Client() *redis.Client {
 	return c.client
 }
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IncrementScores adds each member's increment to its score in the sorted set at key, creating
+// the set and members as needed
+func (c *RedisCache) IncrementScores(ctx context.Context, key string, increments map[string]float64) error {
+	if len(increments) == 0 {
+		return nil
+	}
+	if c.mem != nil {
+		c.mem.incrementScores(key, increments)
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	for member, by := range increments {
+		pipe.ZIncrBy(ctx, key, by, member)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to increment scores of %s: %w", key, err)
+	}
+	return nil
+}
+
+// TopScores returns up to n members of the sorted set at key with a score of at least min,
+// highest first
+func (c *RedisCache) TopScores(ctx context.Context, key string, n int, min float64) ([]string, error) {
+	if c.mem != nil {
+		return c.mem.topScores(key, n, min), nil
+	}
+
+	members, err := c.client.ZRevRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min:   strconv.FormatFloat(min, 'f', -1, 64),
+		Max:   "+inf",
+		Count: int64(n),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read top scores of %s: %w", key, err)
+	}
+	return members, nil
+}
+
+// DecayScores multiplies every score in the sorted set at key by factor and removes the members
+// left below min, so the scores follow recent increments
+func (c *RedisCache) DecayScores(ctx context.Context, key string, factor, min float64) error {
+	if c.mem != nil {
+		c.mem.decayScores(key, factor, min)
+		return nil
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.ZUnionStore(ctx, key, &redis.ZStore{Keys: []string{key}, Weights: []float64{factor}})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatFloat(min, 'f', -1, 64))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to decay scores of %s: %w", key, err)
+	}
+	return nil
+}
+
+func (m *memoryStore) incrementScores(key string, increments map[string]float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.scores == nil {
+		m.scores = make(map[string]map[string]float64)
+	}
+	set := m.scores[key]
+	if set == nil {
+		set = make(map[string]float64)
+		m.scores[key] = set
+	}
+	for member, by := range increments {
+		set[member] += by
+	}
+}
+
+func (m *memoryStore) topScores(key string, n int, min float64) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var members []string
+	for member, score := range m.scores[key] {
+		if score >= min {
+			members = append(members, member)
+		}
+	}
+	set := m.scores[key]
+	sort.Slice(members, func(i, j int) bool {
+		if set[members[i]] != set[members[j]] {
+			return set[members[i]] > set[members[j]]
+		}
+		return members[i] > members[j] // Redis orders equal scores in reverse lexicographic order
+	})
+	if len(members) > n {
+		members = members[:n]
+	}
+	return members
+}
+
+func (m *memoryStore) decayScores(key string, factor, min float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	set := m.scores[key]
+	for member, score := range set {
+		if score *= factor; score < min {
+			delete(set, member)
+		} else {
+			set[member] = score
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// Package chain holds the types shared by the chain adapters the indexer fetches through
+package chain
+
+import "github.com/bimakw/chain-indexer/internal/domain/entities"
+
+// FetchResult contains the result of fetching transfers
+type FetchResult struct {
+	Transfers []entities.Transfer
+	// Events holds decoded records of non-Transfer event types, keyed by event type name
+	Events map[string][]any
+	// RawLogs holds every fetched log, decodable or not, when INDEXER_STORE_RAW_LOGS is set
+	RawLogs        []entities.RawLog
+	FromBlock      int64
+	ToBlock        int64
+	FailedLogCount int
+}
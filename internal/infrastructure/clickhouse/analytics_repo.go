@@ -0,0 +1,233 @@
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// clickhouseTimeLayout is how ClickHouse renders DateTime values as text
+const clickhouseTimeLayout = "2006-01-02 15:04:05"
+
+// AnalyticsRepo implements repositories.AnalyticsRepository on the ClickHouse transfers table.
+// The table is a ReplacingMergeTree keyed on the transfer's identity, and reads use FINAL,
+// so re-inserted transfers are counted once.
+type AnalyticsRepo struct {
+	client *Client
+}
+
+// NewAnalyticsRepo creates a new ClickHouse analytics repository
+func NewAnalyticsRepo(client *Client) *AnalyticsRepo {
+	return &AnalyticsRepo{client: client}
+}
+
+var _ repositories.AnalyticsRepository = (*AnalyticsRepo)(nil)
+
+// transferRow is the JSONEachRow representation of a transfer
+type transferRow struct {
+	TokenAddress   string `json:"token_address"`
+	TxHash         string `json:"tx_hash"`
+	LogIndex       int    `json:"log_index"`
+	BlockNumber    int64  `json:"block_number"`
+	BlockTimestamp int64  `json:"block_timestamp"`
+	FromAddress    string `json:"from_address"`
+	ToAddress      string `json:"to_address"`
+	Value          string `json:"value"`
+}
+
+// InsertTransfers appends transfers to the transfers table
+func (r *AnalyticsRepo) InsertTransfers(ctx context.Context, transfers []entities.Transfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, t := range transfers {
+		value := t.ValueString
+		if t.Value != nil {
+			value = t.Value.String()
+		}
+		row := transferRow{
+			TokenAddress:   t.TokenAddress,
+			TxHash:         t.TxHash,
+			LogIndex:       t.LogIndex,
+			BlockNumber:    t.BlockNumber,
+			BlockTimestamp: t.BlockTimestamp.Unix(),
+			FromAddress:    t.FromAddress,
+			ToAddress:      t.ToAddress,
+			Value:          value,
+		}
+		if err := enc.Encode(row); err != nil {
+			return fmt.Errorf("failed to encode transfer: %w", err)
+		}
+	}
+
+	query := `INSERT INTO transfers
+		(token_address, tx_hash, log_index, block_number, block_timestamp, from_address, to_address, value)
+		FORMAT JSONEachRow`
+	if err := r.client.Exec(ctx, query, nil, &body); err != nil {
+		return fmt.Errorf("failed to insert transfers: %w", err)
+	}
+	return nil
+}
+
+// ReplaceRange deletes a token's transfers in [fromBlock, toBlock] and inserts the given ones.
+// ClickHouse has no transactions, so a failure in between leaves the range empty until it is replaced again.
+func (r *AnalyticsRepo) ReplaceRange(ctx context.Context, tokenAddress string, fromBlock, toBlock int64, transfers []entities.Transfer) error {
+	query := `DELETE FROM transfers
+		WHERE token_address = {token:String}
+		AND block_number BETWEEN {from_block:UInt64} AND {to_block:UInt64}`
+	params := map[string]string{
+		"token":      tokenAddress,
+		"from_block": strconv.FormatInt(fromBlock, 10),
+		"to_block":   strconv.FormatInt(toBlock, 10),
+	}
+	if err := r.client.Exec(ctx, query, params, nil); err != nil {
+		return fmt.Errorf("failed to delete transfers: %w", err)
+	}
+
+	return r.InsertTransfers(ctx, transfers)
+}
+
+// tokenStatsRow is the result row of GetTokenStats; 64-bit integers arrive as JSON strings
+type tokenStatsRow struct {
+	TotalTransfers int64  `json:"total_transfers,string"`
+	UniqueFrom     int64  `json:"unique_from,string"`
+	UniqueTo       int64  `json:"unique_to,string"`
+	TotalVolume    string `json:"total_volume"`
+	FirstTransfer  string `json:"first_transfer"`
+	LastTransfer   string `json:"last_transfer"`
+	Transfers24h   int64  `json:"transfers_24h,string"`
+	Volume24h      string `json:"volume_24h"`
+	Transfers7d    int64  `json:"transfers_7d,string"`
+	Volume7d       string `json:"volume_7d"`
+}
+
+// GetTokenStats returns aggregated transfer statistics for a token in a single scan
+func (r *AnalyticsRepo) GetTokenStats(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error) {
+	query := `
+		SELECT
+			count() AS total_transfers,
+			uniqExact(from_address) AS unique_from,
+			uniqExact(to_address) AS unique_to,
+			toString(sum(value)) AS total_volume,
+			toString(min(block_timestamp)) AS first_transfer,
+			toString(max(block_timestamp)) AS last_transfer,
+			countIf(block_timestamp >= now() - INTERVAL 24 HOUR) AS transfers_24h,
+			toString(sumIf(value, block_timestamp >= now() - INTERVAL 24 HOUR)) AS volume_24h,
+			countIf(block_timestamp >= now() - INTERVAL 7 DAY) AS transfers_7d,
+			toString(sumIf(value, block_timestamp >= now() - INTERVAL 7 DAY)) AS volume_7d
+		FROM transfers FINAL
+		WHERE token_address = {token:String}`
+
+	rows, err := queryRows[tokenStatsRow](ctx, r.client, query, map[string]string{"token": tokenAddress})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token stats: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("failed to get token stats: no result row")
+	}
+	row := rows[0]
+
+	result := &repositories.TokenStatsResult{
+		TotalTransfers:  row.TotalTransfers,
+		UniqueFromAddrs: row.UniqueFrom,
+		UniqueToAddrs:   row.UniqueTo,
+		TotalVolume:     row.TotalVolume,
+		Transfers24h:    row.Transfers24h,
+		Volume24h:       row.Volume24h,
+		Transfers7d:     row.Transfers7d,
+		Volume7d:        row.Volume7d,
+	}
+
+	// min/max over no rows yield the epoch rather than NULL
+	if row.TotalTransfers > 0 {
+		if t, err := time.ParseInLocation(clickhouseTimeLayout, row.FirstTransfer, time.UTC); err == nil {
+			result.FirstTransferAt = &t
+		}
+		if t, err := time.ParseInLocation(clickhouseTimeLayout, row.LastTransfer, time.UTC); err == nil {
+			result.LastTransferAt = &t
+		}
+	}
+
+	return result, nil
+}
+
+// balancesQuery computes each address's positive balance of {token:String}, leaving out the
+// addresses in {exclude:Array(String)}
+const balancesQuery = `
+	SELECT address, sum(amount) AS balance
+	FROM (
+		SELECT to_address AS address, toInt256(value) AS amount
+		FROM transfers FINAL
+		WHERE token_address = {token:String}
+
+		UNION ALL
+
+		SELECT from_address AS address, -toInt256(value) AS amount
+		FROM transfers FINAL
+		WHERE token_address = {token:String}
+	)
+	GROUP BY address
+	HAVING balance > 0 AND address NOT IN {exclude:Array(String)}`
+
+// GetHolderCount returns the count of unique holders with positive balance, leaving out exclude
+func (r *AnalyticsRepo) GetHolderCount(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
+	type countRow struct {
+		Holders int64 `json:"holders,string"`
+	}
+
+	query := `SELECT count() AS holders FROM (` + balancesQuery + `)`
+	params := map[string]string{
+		"token":   tokenAddress,
+		"exclude": stringArrayParam(exclude),
+	}
+	rows, err := queryRows[countRow](ctx, r.client, query, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get holder count: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Holders, nil
+}
+
+// GetTopHolders returns token holders sorted by balance with pagination offset, leaving out exclude
+func (r *AnalyticsRepo) GetTopHolders(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+	type holderRow struct {
+		Address string `json:"address"`
+		Balance string `json:"balance"`
+	}
+
+	query := `SELECT address, toString(balance) AS balance FROM (` + balancesQuery + `)
+		ORDER BY balance DESC, address
+		LIMIT {limit:UInt32} OFFSET {offset:UInt32}`
+	params := map[string]string{
+		"token":   tokenAddress,
+		"limit":   strconv.Itoa(limit),
+		"offset":  strconv.Itoa(offset),
+		"exclude": stringArrayParam(exclude),
+	}
+
+	rows, err := queryRows[holderRow](ctx, r.client, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top holders: %w", err)
+	}
+
+	result := make([]repositories.HolderBalance, len(rows))
+	for i, row := range rows {
+		result[i] = repositories.HolderBalance{
+			Address: row.Address,
+			Balance: row.Balance,
+			Rank:    offset + i + 1,
+		}
+	}
+	return result, nil
+}
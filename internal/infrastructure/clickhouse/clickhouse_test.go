@@ -0,0 +1,144 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+const testToken = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+
+func newTestRepo(t *testing.T, handler http.HandlerFunc) *AnalyticsRepo {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewAnalyticsRepo(NewClient(config.ClickHouseConfig{
+		URL:      server.URL,
+		Database: "chain_indexer",
+		User:     "indexer",
+		Password: "secret",
+		Timeout:  time.Second,
+	}))
+}
+
+func TestAnalyticsRepo_InsertTransfers(t *testing.T) {
+	var query string
+	var rows []transferRow
+	repo := newTestRepo(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		if r.URL.Query().Get("database") != "chain_indexer" || r.Header.Get("X-ClickHouse-User") != "indexer" || r.Header.Get("X-ClickHouse-Key") != "secret" {
+			t.Errorf("unexpected request: %s %v", r.URL, r.Header)
+		}
+		dec := json.NewDecoder(r.Body)
+		for {
+			var row transferRow
+			if err := dec.Decode(&row); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("bad row: %v", err)
+			}
+			rows = append(rows, row)
+		}
+	})
+
+	ts := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	err := repo.InsertTransfers(context.Background(), []entities.Transfer{{
+		TxHash:         "0xabc",
+		LogIndex:       3,
+		BlockNumber:    19000000,
+		BlockTimestamp: ts,
+		TokenAddress:   testToken,
+		FromAddress:    "0x1111111111111111111111111111111111111111",
+		ToAddress:      "0x2222222222222222222222222222222222222222",
+		Value:          big.NewInt(1000000),
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(query, "INSERT INTO transfers") || !strings.Contains(query, "FORMAT JSONEachRow") {
+		t.Errorf("unexpected query: %s", query)
+	}
+	if len(rows) != 1 || rows[0].Value != "1000000" || rows[0].BlockTimestamp != ts.Unix() || rows[0].LogIndex != 3 {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+}
+
+func TestAnalyticsRepo_GetTokenStats(t *testing.T) {
+	t.Run("decodes stats", func(t *testing.T) {
+		repo := newTestRepo(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("param_token") != testToken {
+				t.Errorf("expected token parameter, got %q", r.URL.Query().Get("param_token"))
+			}
+			_, _ = w.Write([]byte(`{"total_transfers":"42","unique_from":"10","unique_to":"12","total_volume":"5000","first_transfer":"2024-01-01 00:00:00","last_transfer":"2024-01-15 10:30:00","transfers_24h":"3","volume_24h":"300","transfers_7d":"8","volume_7d":"800"}` + "\n"))
+		})
+
+		stats, err := repo.GetTokenStats(context.Background(), testToken)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stats.TotalTransfers != 42 || stats.UniqueToAddrs != 12 || stats.TotalVolume != "5000" || stats.Volume7d != "800" {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+		if stats.LastTransferAt == nil || !stats.LastTransferAt.Equal(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)) {
+			t.Errorf("unexpected last transfer: %v", stats.LastTransferAt)
+		}
+	})
+
+	t.Run("no transfers", func(t *testing.T) {
+		repo := newTestRepo(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"total_transfers":"0","unique_from":"0","unique_to":"0","total_volume":"0","first_transfer":"1970-01-01 00:00:00","last_transfer":"1970-01-01 00:00:00","transfers_24h":"0","volume_24h":"0","transfers_7d":"0","volume_7d":"0"}` + "\n"))
+		})
+
+		stats, err := repo.GetTokenStats(context.Background(), testToken)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stats.FirstTransferAt != nil || stats.LastTransferAt != nil {
+			t.Error("expected no timestamps for a token without transfers")
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		repo := newTestRepo(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Code: 60. DB::Exception: Table chain_indexer.transfers doesn't exist", http.StatusNotFound)
+		})
+
+		_, err := repo.GetTokenStats(context.Background(), testToken)
+		if err == nil || !strings.Contains(err.Error(), "doesn't exist") {
+			t.Errorf("expected server message in error, got %v", err)
+		}
+	})
+}
+
+func TestAnalyticsRepo_GetTopHolders(t *testing.T) {
+	repo := newTestRepo(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("param_limit") != "2" || r.URL.Query().Get("param_offset") != "10" {
+			t.Errorf("unexpected pagination parameters: %s", r.URL.RawQuery)
+		}
+		if got := r.URL.Query().Get("param_exclude"); got != "['0x000000000000000000000000000000000000dead']" {
+			t.Errorf("unexpected exclude parameter %q", got)
+		}
+		_, _ = w.Write([]byte(`{"address":"0x1111111111111111111111111111111111111111","balance":"900"}
+{"address":"0x2222222222222222222222222222222222222222","balance":"100"}
+`))
+	})
+
+	holders, err := repo.GetTopHolders(context.Background(), testToken, 2, 10, []string{"0x000000000000000000000000000000000000dead"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(holders) != 2 || holders[0].Rank != 11 || holders[1].Rank != 12 || holders[0].Balance != "900" {
+		t.Errorf("unexpected holders: %+v", holders)
+	}
+}
@@ -0,0 +1,130 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+// Client talks to ClickHouse over its HTTP interface.
+// Query values are always sent as server-side parameters ({name:Type} placeholders),
+// never interpolated into the SQL.
+type Client struct {
+	baseURL    string
+	database   string
+	user       string
+	password   string
+	httpClient *http.Client
+}
+
+// NewClient creates a ClickHouse client from configuration
+func NewClient(cfg config.ClickHouseConfig) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(cfg.URL, "/"),
+		database:   cfg.Database,
+		user:       cfg.User,
+		password:   cfg.Password,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Ping verifies the server is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/ping", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create clickhouse request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to ping clickhouse: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("clickhouse ping returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Exec runs a statement that returns no rows. For INSERT ... FORMAT statements body carries the data.
+func (c *Client) Exec(ctx context.Context, query string, params map[string]string, body io.Reader) error {
+	resp, err := c.do(ctx, query, params, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	_, _ = io.Copy(io.Discard, resp)
+	return nil
+}
+
+// queryRows runs a SELECT and decodes each result row into a T
+func queryRows[T any](ctx context.Context, c *Client, query string, params map[string]string) ([]T, error) {
+	resp, err := c.do(ctx, query+" FORMAT JSONEachRow", params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	var rows []T
+	dec := json.NewDecoder(resp)
+	for {
+		var row T
+		if err := dec.Decode(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				return rows, nil
+			}
+			return nil, fmt.Errorf("failed to decode clickhouse row: %w", err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+// stringArrayParam renders values as an Array(String) query parameter, e.g. ['a','b']
+func stringArrayParam(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		v = strings.ReplaceAll(v, `\`, `\\`)
+		quoted[i] = "'" + strings.ReplaceAll(v, "'", `\'`) + "'"
+	}
+	return "[" + strings.Join(quoted, ",") + "]"
+}
+
+// do POSTs a query and returns the response body, treating any non-200 response as an error
+func (c *Client) do(ctx context.Context, query string, params map[string]string, body io.Reader) (io.ReadCloser, error) {
+	values := url.Values{}
+	values.Set("database", c.database)
+	values.Set("query", query)
+	for name, value := range params {
+		values.Set("param_"+name, value)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/?"+values.Encode(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clickhouse request: %w", err)
+	}
+	req.Header.Set("X-ClickHouse-User", c.user)
+	if c.password != "" {
+		req.Header.Set("X-ClickHouse-Key", c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query clickhouse: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("clickhouse returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure AdvancedStatsRepo implements AdvancedStatsRepository
+var _ repositories.AdvancedStatsRepository = (*AdvancedStatsRepo)(nil)
+
+// AdvancedStatsRepo implements AdvancedStatsRepository using PostgreSQL
+type AdvancedStatsRepo struct {
+	db *sqlx.DB
+}
+
+// NewAdvancedStatsRepo creates a new advanced stats repository
+func NewAdvancedStatsRepo(db *sqlx.DB) *AdvancedStatsRepo {
+	return &AdvancedStatsRepo{db: db}
+}
+
+const advancedStatsColumns = `token_address, window_from, window_to, volume, average_supply, circulating_supply,
+	inactive_since, dormant_supply, dormant_holders, self_transfers, self_transfer_volume, fan_out_threshold,
+	fan_out_transactions, fan_out_transfers, fan_out_volume, computed_at`
+
+// Save records a token's advanced stats, replacing the previous ones
+func (r *AdvancedStatsRepo) Save(ctx context.Context, stats repositories.AdvancedStats) error {
+	ctx = withQueryName(ctx, "token_advanced_stats.Save")
+
+	query := `
+		INSERT INTO token_advanced_stats (` + advancedStatsColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (token_address) DO UPDATE SET
+			window_from = EXCLUDED.window_from,
+			window_to = EXCLUDED.window_to,
+			volume = EXCLUDED.volume,
+			average_supply = EXCLUDED.average_supply,
+			circulating_supply = EXCLUDED.circulating_supply,
+			inactive_since = EXCLUDED.inactive_since,
+			dormant_supply = EXCLUDED.dormant_supply,
+			dormant_holders = EXCLUDED.dormant_holders,
+			self_transfers = EXCLUDED.self_transfers,
+			self_transfer_volume = EXCLUDED.self_transfer_volume,
+			fan_out_threshold = EXCLUDED.fan_out_threshold,
+			fan_out_transactions = EXCLUDED.fan_out_transactions,
+			fan_out_transfers = EXCLUDED.fan_out_transfers,
+			fan_out_volume = EXCLUDED.fan_out_volume,
+			computed_at = EXCLUDED.computed_at
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		stats.TokenAddress, stats.WindowFrom, stats.WindowTo, stats.Volume, stats.AverageSupply, stats.CirculatingSupply,
+		stats.InactiveSince, stats.DormantSupply, stats.DormantHolders, stats.SelfTransfers, stats.SelfTransferVolume,
+		stats.FanOutThreshold, stats.FanOutTransactions, stats.FanOutTransfers, stats.FanOutVolume, stats.ComputedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save advanced stats: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns a token's last computed advanced stats
+func (r *AdvancedStatsRepo) Get(ctx context.Context, tokenAddress string) (*repositories.AdvancedStats, error) {
+	ctx = withQueryName(ctx, "token_advanced_stats.Get")
+
+	query := `
+		SELECT token_address, window_from, window_to, volume::TEXT, average_supply::TEXT, circulating_supply::TEXT,
+			inactive_since, dormant_supply::TEXT, dormant_holders, self_transfers, self_transfer_volume::TEXT,
+			fan_out_threshold, fan_out_transactions, fan_out_transfers, fan_out_volume::TEXT, computed_at
+		FROM token_advanced_stats
+		WHERE token_address = $1
+	`
+
+	var stats repositories.AdvancedStats
+	if err := r.db.GetContext(ctx, &stats, query, tokenAddress); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get advanced stats: %w", err)
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure AlertRuleRepo implements AlertRuleRepository
+var _ repositories.AlertRuleRepository = (*AlertRuleRepo)(nil)
+
+// AlertRuleRepo implements AlertRuleRepository using PostgreSQL
+type AlertRuleRepo struct {
+	db *sqlx.DB
+}
+
+// NewAlertRuleRepo creates a new alert rule repository
+func NewAlertRuleRepo(db *sqlx.DB) *AlertRuleRepo {
+	return &AlertRuleRepo{db: db}
+}
+
+type alertRuleRow struct {
+	ID             int64          `db:"id"`
+	Name           string         `db:"name"`
+	TokenAddress   *string        `db:"token_address"`
+	Address        *string        `db:"address"`
+	Direction      string         `db:"direction"`
+	MinValue       *string        `db:"min_value"`
+	Counterparties pq.StringArray `db:"counterparties"`
+	Channel        string         `db:"channel"`
+	Target         string         `db:"target"`
+	TenantID       *int64         `db:"tenant_id"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+func (row *alertRuleRow) toEntity() entities.AlertRule {
+	rule := entities.AlertRule{
+		ID:             row.ID,
+		Name:           row.Name,
+		TokenAddress:   row.TokenAddress,
+		Address:        row.Address,
+		Direction:      row.Direction,
+		Counterparties: []string(row.Counterparties),
+		Channel:        row.Channel,
+		Target:         row.Target,
+		TenantID:       row.TenantID,
+		CreatedAt:      row.CreatedAt,
+	}
+	if row.MinValue != nil {
+		rule.MinValue, _ = new(big.Int).SetString(*row.MinValue, 10)
+	}
+	return rule
+}
+
+const alertRuleColumns = `id, name, token_address, address, direction, min_value, counterparties, channel, target, tenant_id, created_at`
+
+// Create inserts a rule
+func (r *AlertRuleRepo) Create(ctx context.Context, rule *entities.AlertRule) error {
+	ctx = withQueryName(ctx, "alert_rules.Create")
+
+	var minValue *string
+	if rule.MinValue != nil {
+		v := rule.MinValue.String()
+		minValue = &v
+	}
+
+	counterparties := rule.Counterparties
+	if counterparties == nil {
+		counterparties = []string{}
+	}
+
+	query := `
+		INSERT INTO alert_rules (name, token_address, address, direction, min_value, counterparties, channel, target, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`
+	row := r.db.QueryRowxContext(ctx, query,
+		rule.Name,
+		rule.TokenAddress,
+		rule.Address,
+		rule.Direction,
+		minValue,
+		pq.Array(counterparties),
+		rule.Channel,
+		rule.Target,
+		rule.TenantID,
+	)
+	if err := row.Scan(&rule.ID, &rule.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a rule by ID
+func (r *AlertRuleRepo) GetByID(ctx context.Context, id int64) (*entities.AlertRule, error) {
+	ctx = withQueryName(ctx, "alert_rules.GetByID")
+
+	var row alertRuleRow
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = $1`
+
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+
+	rule := row.toEntity()
+	return &rule, nil
+}
+
+// List retrieves all rules, oldest first
+func (r *AlertRuleRepo) List(ctx context.Context) ([]entities.AlertRule, error) {
+	ctx = withQueryName(ctx, "alert_rules.List")
+
+	var rows []alertRuleRow
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules ORDER BY id`
+
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	rules := make([]entities.AlertRule, len(rows))
+	for i := range rows {
+		rules[i] = rows[i].toEntity()
+	}
+
+	return rules, nil
+}
+
+// Delete removes a rule
+func (r *AlertRuleRepo) Delete(ctx context.Context, id int64) (bool, error) {
+	ctx = withQueryName(ctx, "alert_rules.Delete")
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return n > 0, nil
+}
@@ -0,0 +1,294 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure ContractRepo implements ContractRepository
+var _ repositories.ContractRepository = (*ContractRepo)(nil)
+
+// ContractRepo implements ContractRepository using PostgreSQL
+type ContractRepo struct {
+	db *sqlx.DB
+}
+
+// NewContractRepo creates a new contract repository
+func NewContractRepo(db *sqlx.DB) *ContractRepo {
+	return &ContractRepo{db: db}
+}
+
+type contractRow struct {
+	Address   string         `db:"address"`
+	Name      string         `db:"name"`
+	ABI       string         `db:"abi"`
+	Events    pq.StringArray `db:"events"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func (row *contractRow) toEntity() entities.Contract {
+	return entities.Contract{
+		Address:   row.Address,
+		Name:      row.Name,
+		ABI:       row.ABI,
+		Events:    []string(row.Events),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+type contractEventRow struct {
+	ID              int64          `db:"id"`
+	ContractAddress string         `db:"contract_address"`
+	EventName       string         `db:"event_name"`
+	TxHash          string         `db:"tx_hash"`
+	LogIndex        int            `db:"log_index"`
+	BlockNumber     int64          `db:"block_number"`
+	BlockTimestamp  time.Time      `db:"block_timestamp"`
+	Topics          pq.StringArray `db:"topics"`
+	Args            []byte         `db:"args"`
+	CreatedAt       time.Time      `db:"created_at"`
+}
+
+func (row *contractEventRow) toEntity() entities.ContractEvent {
+	return entities.ContractEvent{
+		ID:              row.ID,
+		ContractAddress: row.ContractAddress,
+		EventName:       row.EventName,
+		TxHash:          row.TxHash,
+		LogIndex:        row.LogIndex,
+		BlockNumber:     row.BlockNumber,
+		BlockTimestamp:  row.BlockTimestamp,
+		Topics:          []string(row.Topics),
+		Args:            json.RawMessage(row.Args),
+		CreatedAt:       row.CreatedAt,
+	}
+}
+
+const contractEventColumns = `id, contract_address, event_name, tx_hash, log_index, block_number, block_timestamp, topics, args, created_at`
+
+// StoreEvents inserts decoded contract events in a single transaction, skipping duplicates
+func (r *ContractRepo) StoreEvents(ctx context.Context, events []any) error {
+	ctx = withQueryName(ctx, "contract_events.StoreEvents")
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO contract_events (contract_address, event_name, tx_hash, log_index, block_number,
+									 block_timestamp, topics, args)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (tx_hash, log_index, block_timestamp) DO NOTHING
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		e, ok := event.(*entities.ContractEvent)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T", event)
+		}
+
+		_, err := stmt.ExecContext(ctx,
+			e.ContractAddress,
+			e.EventName,
+			e.TxHash,
+			e.LogIndex,
+			e.BlockNumber,
+			e.BlockTimestamp,
+			pq.Array(e.Topics),
+			string(e.Args),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert contract event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertContract registers a contract, replacing the ABI and events of an earlier registration
+func (r *ContractRepo) UpsertContract(ctx context.Context, contract *entities.Contract) error {
+	ctx = withQueryName(ctx, "contracts.UpsertContract")
+
+	query := `
+		INSERT INTO contracts (address, name, abi, events)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (address) DO UPDATE SET
+			name = EXCLUDED.name,
+			abi = EXCLUDED.abi,
+			events = EXCLUDED.events,
+			updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+	row := r.db.QueryRowxContext(ctx, query, contract.Address, contract.Name, contract.ABI, pq.Array(contract.Events))
+	if err := row.Scan(&contract.CreatedAt, &contract.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to upsert contract: %w", err)
+	}
+
+	return nil
+}
+
+// GetContract retrieves a contract by address, or nil if it is not registered
+func (r *ContractRepo) GetContract(ctx context.Context, address string) (*entities.Contract, error) {
+	ctx = withQueryName(ctx, "contracts.GetContract")
+
+	var row contractRow
+	query := `SELECT address, name, abi, events, created_at, updated_at FROM contracts WHERE address = $1`
+
+	if err := r.db.GetContext(ctx, &row, query, address); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	contract := row.toEntity()
+	return &contract, nil
+}
+
+// ListContracts retrieves every registered contract, by address
+func (r *ContractRepo) ListContracts(ctx context.Context) ([]entities.Contract, error) {
+	ctx = withQueryName(ctx, "contracts.ListContracts")
+
+	var rows []contractRow
+	query := `SELECT address, name, abi, events, created_at, updated_at FROM contracts ORDER BY address`
+
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list contracts: %w", err)
+	}
+
+	contracts := make([]entities.Contract, len(rows))
+	for i := range rows {
+		contracts[i] = rows[i].toEntity()
+	}
+
+	return contracts, nil
+}
+
+// GetEvents retrieves contract events matching the filter, newest first
+func (r *ContractRepo) GetEvents(ctx context.Context, filter entities.ContractEventFilter) ([]entities.ContractEvent, error) {
+	ctx = withQueryName(ctx, "contract_events.GetEvents")
+
+	where, args, err := buildContractEventConditions(filter)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM contract_events%s
+		ORDER BY block_timestamp DESC, block_number DESC, log_index DESC
+		LIMIT $%d OFFSET $%d
+	`, contractEventColumns, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	var rows []contractEventRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get contract events: %w", err)
+	}
+
+	events := make([]entities.ContractEvent, len(rows))
+	for i := range rows {
+		events[i] = rows[i].toEntity()
+	}
+
+	return events, nil
+}
+
+// GetEventCount returns the count of contract events matching the filter
+func (r *ContractRepo) GetEventCount(ctx context.Context, filter entities.ContractEventFilter) (int64, error) {
+	ctx = withQueryName(ctx, "contract_events.GetEventCount")
+
+	where, args, err := buildContractEventConditions(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM contract_events"+where, args...); err != nil {
+		return 0, fmt.Errorf("failed to get contract event count: %w", err)
+	}
+
+	return count, nil
+}
+
+// contractArgRangeSQL maps the range operators of argument conditions to SQL; nothing else is
+// ever written into a query
+var contractArgRangeSQL = map[entities.ContractArgOperator]string{
+	entities.ContractArgGt:  ">",
+	entities.ContractArgGte: ">=",
+	entities.ContractArgLt:  "<",
+	entities.ContractArgLte: "<=",
+}
+
+// buildContractEventConditions builds the WHERE clause for a contract event filter. Equalities
+// are matched by JSONB containment, which the GIN index on args serves, and != by its negation.
+// Ranges compare the argument as numeric, exact at any size. Argument names and values are always
+// bound as parameters.
+func buildContractEventConditions(filter entities.ContractEventFilter) (string, []interface{}, error) {
+	conditions := []string{"contract_address = $1"}
+	args := []interface{}{filter.ContractAddress}
+
+	if filter.EventName != nil {
+		args = append(args, *filter.EventName)
+		conditions = append(conditions, fmt.Sprintf("event_name = $%d", len(args)))
+	}
+	if len(filter.Args) > 0 {
+		contained, err := json.Marshal(filter.Args)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode argument filter: %w", err)
+		}
+		args = append(args, string(contained))
+		conditions = append(conditions, fmt.Sprintf("args @> $%d::jsonb", len(args)))
+	}
+
+	for _, c := range filter.Conditions {
+		switch {
+		case c.Operator == entities.ContractArgEq || c.Operator == entities.ContractArgNe:
+			contained, err := json.Marshal(map[string]json.RawMessage{c.Arg: c.Value})
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to encode argument filter: %w", err)
+			}
+			args = append(args, string(contained))
+			condition := fmt.Sprintf("args @> $%d::jsonb", len(args))
+			if c.Operator == entities.ContractArgNe {
+				condition = "NOT " + condition
+			}
+			conditions = append(conditions, condition)
+		case contractArgRangeSQL[c.Operator] != "":
+			args = append(args, c.Arg, string(c.Value))
+			conditions = append(conditions, fmt.Sprintf("(args ->> $%d)::numeric %s $%d::numeric", len(args)-1, contractArgRangeSQL[c.Operator], len(args)))
+		default:
+			return "", nil, fmt.Errorf("unsupported argument operator %q", c.Operator)
+		}
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+func TestBuildContractEventConditions(t *testing.T) {
+	event := "Transfer"
+	filter := entities.ContractEventFilter{
+		ContractAddress: "0xdac17f958d2ee523a2206206994597c13d831ec7",
+		EventName:       &event,
+		Args:            map[string]json.RawMessage{"to": json.RawMessage(`"0x1111111111111111111111111111111111111111"`)},
+		Conditions: []entities.ContractArgCondition{
+			{Arg: "from", Operator: entities.ContractArgNe, Value: json.RawMessage(`"0x2222222222222222222222222222222222222222"`)},
+			{Arg: "value", Operator: entities.ContractArgGte, Value: json.RawMessage(`1180591620717411303424`)},
+		},
+	}
+
+	where, args, err := buildContractEventConditions(filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := " WHERE contract_address = $1 AND event_name = $2 AND args @> $3::jsonb AND NOT args @> $4::jsonb AND (args ->> $5)::numeric >= $6::numeric"
+	if where != want {
+		t.Errorf("expected %q, got %q", want, where)
+	}
+	if len(args) != 6 || args[2] != `{"to":"0x1111111111111111111111111111111111111111"}` ||
+		args[3] != `{"from":"0x2222222222222222222222222222222222222222"}` || args[4] != "value" || args[5] != "1180591620717411303424" {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	// Only allow-listed operators reach the query
+	filter.Conditions = []entities.ContractArgCondition{{Arg: "value", Operator: "; DROP TABLE contract_events; --", Value: json.RawMessage(`1`)}}
+	if where, _, err := buildContractEventConditions(filter); err == nil || strings.Contains(where, "DROP") {
+		t.Errorf("expected an unsupported operator to be rejected, got %q (%v)", where, err)
+	}
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure DenyListRepo implements DenyListRepository
+var _ repositories.DenyListRepository = (*DenyListRepo)(nil)
+
+// DenyListRepo implements DenyListRepository using PostgreSQL
+type DenyListRepo struct {
+	db *sqlx.DB
+}
+
+// NewDenyListRepo creates a new deny list repository
+func NewDenyListRepo(db *sqlx.DB) *DenyListRepo {
+	return &DenyListRepo{db: db}
+}
+
+// ReplaceList replaces all entries of a list in one transaction
+func (r *DenyListRepo) ReplaceList(ctx context.Context, listName string, entries []entities.DenyListEntry) error {
+	ctx = withQueryName(ctx, "deny_list.ReplaceList")
+
+	addresses := make([]string, len(entries))
+	labels := make([]string, len(entries))
+	for i, e := range entries {
+		addresses[i] = e.Address
+		labels[i] = e.Label
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	deleteQuery := `DELETE FROM deny_list WHERE list_name = $1 AND NOT (address = ANY($2))`
+	if _, err := tx.ExecContext(ctx, deleteQuery, listName, pq.Array(addresses)); err != nil {
+		return fmt.Errorf("failed to delete removed deny list entries: %w", err)
+	}
+
+	// Upsert so addresses that stay on the list keep their original added_at
+	upsertQuery := `
+		INSERT INTO deny_list (list_name, address, label)
+		SELECT $1, a.address, a.label
+		FROM UNNEST($2::varchar[], $3::text[]) AS a(address, label)
+		ON CONFLICT (list_name, address) DO UPDATE SET label = EXCLUDED.label
+	`
+	if _, err := tx.ExecContext(ctx, upsertQuery, listName, pq.Array(addresses), pq.Array(labels)); err != nil {
+		return fmt.Errorf("failed to upsert deny list entries: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// FindByAddresses returns every entry for the given addresses
+func (r *DenyListRepo) FindByAddresses(ctx context.Context, addresses []string) ([]entities.DenyListEntry, error) {
+	ctx = withQueryName(ctx, "deny_list.FindByAddresses")
+
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	var entries []entities.DenyListEntry
+	query := `
+		SELECT list_name, address, label, added_at
+		FROM deny_list
+		WHERE address = ANY($1)
+		ORDER BY address, list_name
+	`
+
+	if err := r.db.SelectContext(ctx, &entries, query, pq.Array(addresses)); err != nil {
+		return nil, fmt.Errorf("failed to find deny list entries: %w", err)
+	}
+
+	return entries, nil
+}
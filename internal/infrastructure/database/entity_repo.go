@@ -0,0 +1,224 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure EntityRepo implements EntityRepository
+var _ repositories.EntityRepository = (*EntityRepo)(nil)
+
+// EntityRepo implements EntityRepository using PostgreSQL
+type EntityRepo struct {
+	db *sqlx.DB
+}
+
+// NewEntityRepo creates a new entity repository
+func NewEntityRepo(db *sqlx.DB) *EntityRepo {
+	return &EntityRepo{db: db}
+}
+
+// Create inserts an entity with its addresses
+func (r *EntityRepo) Create(ctx context.Context, entity *entities.Entity) error {
+	ctx = withQueryName(ctx, "entities.Create")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO entities (name, category)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+	row := tx.QueryRowxContext(ctx, query, entity.Name, entity.Category)
+	if err := row.Scan(&entity.ID, &entity.CreatedAt, &entity.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create entity: %w", err)
+	}
+
+	if err := insertEntityAddresses(ctx, tx, entity.ID, entity.Addresses); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an entity with its addresses
+func (r *EntityRepo) GetByID(ctx context.Context, id int64) (*entities.Entity, error) {
+	ctx = withQueryName(ctx, "entities.GetByID")
+
+	var entity entities.Entity
+	query := `SELECT id, name, category, created_at, updated_at FROM entities WHERE id = $1`
+
+	if err := r.db.GetContext(ctx, &entity, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get entity: %w", err)
+	}
+
+	addrQuery := `
+		SELECT address FROM entity_addresses
+		WHERE entity_id = $1
+		ORDER BY added_at, address
+	`
+	if err := r.db.SelectContext(ctx, &entity.Addresses, addrQuery, id); err != nil {
+		return nil, fmt.Errorf("failed to get entity addresses: %w", err)
+	}
+
+	return &entity, nil
+}
+
+// Delete removes an entity; its addresses are removed by ON DELETE CASCADE
+func (r *EntityRepo) Delete(ctx context.Context, id int64) (bool, error) {
+	ctx = withQueryName(ctx, "entities.Delete")
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM entities WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete entity: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// AddAddresses adds addresses to an entity, skipping ones already present
+func (r *EntityRepo) AddAddresses(ctx context.Context, id int64, addresses []string) error {
+	ctx = withQueryName(ctx, "entities.AddAddresses")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := insertEntityAddresses(ctx, tx, id, addresses); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE entities SET updated_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to touch entity: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveAddress removes an address from an entity
+func (r *EntityRepo) RemoveAddress(ctx context.Context, id int64, address string) (bool, error) {
+	ctx = withQueryName(ctx, "entities.RemoveAddress")
+
+	query := `DELETE FROM entity_addresses WHERE entity_id = $1 AND address = $2`
+	result, err := r.db.ExecContext(ctx, query, id, address)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove entity address: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if n > 0 {
+		if _, err := r.db.ExecContext(ctx, `UPDATE entities SET updated_at = NOW() WHERE id = $1`, id); err != nil {
+			return false, fmt.Errorf("failed to touch entity: %w", err)
+		}
+	}
+
+	return n > 0, nil
+}
+
+func insertEntityAddresses(ctx context.Context, tx *sqlx.Tx, id int64, addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO entity_addresses (entity_id, address)
+		SELECT $1, UNNEST($2::varchar[])
+		ON CONFLICT (entity_id, address) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, id, pq.Array(addresses)); err != nil {
+		return fmt.Errorf("failed to insert entity addresses: %w", err)
+	}
+
+	return nil
+}
+
+// GetCategoryFlows returns the token flows of each entity in a category
+func (r *EntityRepo) GetCategoryFlows(ctx context.Context, tokenAddress, category string, from, to time.Time) ([]repositories.EntityFlow, error) {
+	ctx = withQueryName(ctx, "entities.GetCategoryFlows")
+
+	query := `
+		WITH members AS (
+			SELECT ea.entity_id, ea.address
+			FROM entity_addresses ea
+			JOIN entities e ON e.id = ea.entity_id
+			WHERE e.category = $2
+		),
+		window_transfers AS (
+			SELECT from_address, to_address, value
+			FROM transfers
+			WHERE token_address = $1 AND block_timestamp >= $3 AND block_timestamp < $4
+		),
+		inflows AS (
+			SELECT m.entity_id, COUNT(*) as transfers, SUM(t.value) as volume
+			FROM window_transfers t
+			JOIN members m ON m.address = t.to_address
+			WHERE NOT EXISTS (
+				SELECT 1 FROM members s WHERE s.entity_id = m.entity_id AND s.address = t.from_address
+			)
+			GROUP BY m.entity_id
+		),
+		outflows AS (
+			SELECT m.entity_id, COUNT(*) as transfers, SUM(t.value) as volume
+			FROM window_transfers t
+			JOIN members m ON m.address = t.from_address
+			WHERE NOT EXISTS (
+				SELECT 1 FROM members s WHERE s.entity_id = m.entity_id AND s.address = t.to_address
+			)
+			GROUP BY m.entity_id
+		)
+		SELECT
+			e.id as entity_id,
+			e.name,
+			COALESCE(i.transfers, 0) as transfers_in,
+			COALESCE(o.transfers, 0) as transfers_out,
+			COALESCE(i.volume, 0)::TEXT as volume_in,
+			COALESCE(o.volume, 0)::TEXT as volume_out
+		FROM entities e
+		LEFT JOIN inflows i ON i.entity_id = e.id
+		LEFT JOIN outflows o ON o.entity_id = e.id
+		WHERE e.category = $2 AND (i.entity_id IS NOT NULL OR o.entity_id IS NOT NULL)
+		ORDER BY e.id
+	`
+
+	var flows []repositories.EntityFlow
+	if err := r.db.SelectContext(ctx, &flows, query, tokenAddress, category, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get category flows: %w", err)
+	}
+
+	return flows, nil
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure EthTransferRepo implements EthTransferRepository
+var _ repositories.EthTransferRepository = (*EthTransferRepo)(nil)
+
+// EthTransferRepo implements EthTransferRepository using PostgreSQL
+type EthTransferRepo struct {
+	db *sqlx.DB
+}
+
+// NewEthTransferRepo creates a new ETH transfer repository
+func NewEthTransferRepo(db *sqlx.DB) *EthTransferRepo {
+	return &EthTransferRepo{db: db}
+}
+
+// BatchInsert inserts ETH transfers in a single transaction, skipping duplicates
+func (r *EthTransferRepo) BatchInsert(ctx context.Context, transfers []entities.EthTransfer) error {
+	ctx = withQueryName(ctx, "eth_transfers.BatchInsert")
+
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO eth_transfers (tx_hash, trace_address, block_number, block_timestamp,
+								   from_address, to_address, value, call_type)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (tx_hash, trace_address, block_timestamp) DO NOTHING
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, t := range transfers {
+		_, err := stmt.ExecContext(ctx,
+			t.TxHash,
+			t.TraceAddress,
+			t.BlockNumber,
+			t.BlockTimestamp,
+			t.FromAddress,
+			t.ToAddress,
+			t.ValueString,
+			t.CallType,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert eth transfer: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByFilter retrieves ETH transfers matching the filter, newest first
+func (r *EthTransferRepo) GetByFilter(ctx context.Context, filter entities.EthTransferFilter) ([]entities.EthTransfer, error) {
+	ctx = withQueryName(ctx, "eth_transfers.GetByFilter")
+
+	where, args := buildEthTransferConditions(filter)
+	query := fmt.Sprintf(`
+		SELECT id, tx_hash, trace_address, block_number, block_timestamp, from_address, to_address,
+			   value::TEXT AS value, call_type, created_at
+		FROM eth_transfers%s
+		ORDER BY block_timestamp DESC, block_number DESC, tx_hash, trace_address
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	var transfers []entities.EthTransfer
+	if err := r.db.SelectContext(ctx, &transfers, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get eth transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// GetCount returns the count of ETH transfers matching the filter
+func (r *EthTransferRepo) GetCount(ctx context.Context, filter entities.EthTransferFilter) (int64, error) {
+	ctx = withQueryName(ctx, "eth_transfers.GetCount")
+
+	where, args := buildEthTransferConditions(filter)
+	query := "SELECT COUNT(*) FROM eth_transfers" + where
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to get eth transfer count: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetLastBlock returns the last block whose traces were indexed, or 0 if none were
+func (r *EthTransferRepo) GetLastBlock(ctx context.Context) (int64, error) {
+	ctx = withQueryName(ctx, "eth_transfers.GetLastBlock")
+
+	var blockNumber int64
+	query := `SELECT COALESCE(MAX(last_indexed_block), 0) FROM eth_trace_state`
+	if err := r.db.GetContext(ctx, &blockNumber, query); err != nil {
+		return 0, fmt.Errorf("failed to get trace checkpoint: %w", err)
+	}
+
+	return blockNumber, nil
+}
+
+// UpdateLastBlock records the last block whose traces were indexed
+func (r *EthTransferRepo) UpdateLastBlock(ctx context.Context, blockNumber int64) error {
+	ctx = withQueryName(ctx, "eth_transfers.UpdateLastBlock")
+
+	query := `
+		INSERT INTO eth_trace_state (id, last_indexed_block)
+		VALUES (TRUE, $1)
+		ON CONFLICT (id) DO UPDATE SET
+			last_indexed_block = EXCLUDED.last_indexed_block,
+			updated_at = NOW()
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, blockNumber); err != nil {
+		return fmt.Errorf("failed to update trace checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// buildEthTransferConditions builds the WHERE clause for an ETH transfer filter
+func buildEthTransferConditions(filter entities.EthTransferFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Address != nil {
+		args = append(args, *filter.Address)
+		conditions = append(conditions, fmt.Sprintf("(from_address = $%d OR to_address = $%d)", len(args), len(args)))
+	}
+
+	if filter.FromAddress != nil {
+		args = append(args, *filter.FromAddress)
+		conditions = append(conditions, fmt.Sprintf("from_address = $%d", len(args)))
+	}
+
+	if filter.ToAddress != nil {
+		args = append(args, *filter.ToAddress)
+		conditions = append(conditions, fmt.Sprintf("to_address = $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure FailedRangeRepo implements FailedRangeRepository
+var _ repositories.FailedRangeRepository = (*FailedRangeRepo)(nil)
+
+// FailedRangeRepo implements FailedRangeRepository using PostgreSQL
+type FailedRangeRepo struct {
+	db *sqlx.DB
+}
+
+// NewFailedRangeRepo creates a new failed range repository
+func NewFailedRangeRepo(db *sqlx.DB) *FailedRangeRepo {
+	return &FailedRangeRepo{db: db}
+}
+
+// Record stores a failed range or updates its unresolved record
+func (r *FailedRangeRepo) Record(ctx context.Context, fr entities.FailedRange) error {
+	ctx = withQueryName(ctx, "failed_ranges.Record")
+
+	query := `
+		INSERT INTO failed_ranges (token_address, from_block, to_block, attempts, last_error, skipped)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (token_address, from_block, to_block) WHERE resolved_at IS NULL DO UPDATE SET
+			attempts = EXCLUDED.attempts,
+			last_error = EXCLUDED.last_error,
+			skipped = failed_ranges.skipped OR EXCLUDED.skipped,
+			last_failed_at = NOW()
+	`
+	if _, err := r.db.ExecContext(ctx, query, fr.TokenAddress, fr.FromBlock, fr.ToBlock, fr.Attempts, fr.LastError, fr.Skipped); err != nil {
+		return fmt.Errorf("failed to record failed range: %w", err)
+	}
+	return nil
+}
+
+// List returns the failed ranges, oldest first
+func (r *FailedRangeRepo) List(ctx context.Context, unresolved bool) ([]entities.FailedRange, error) {
+	ctx = withQueryName(ctx, "failed_ranges.List")
+
+	var ranges []entities.FailedRange
+	query := `
+		SELECT id, token_address, from_block, to_block, attempts, last_error, skipped,
+			first_failed_at, last_failed_at, resolved_at
+		FROM failed_ranges
+		WHERE NOT $1 OR resolved_at IS NULL
+		ORDER BY id
+	`
+	if err := r.db.SelectContext(ctx, &ranges, query, unresolved); err != nil {
+		return nil, fmt.Errorf("failed to list failed ranges: %w", err)
+	}
+	return ranges, nil
+}
+
+// Resolve marks a failed range as repaired
+func (r *FailedRangeRepo) Resolve(ctx context.Context, id int64) (bool, error) {
+	ctx = withQueryName(ctx, "failed_ranges.Resolve")
+
+	result, err := r.db.ExecContext(ctx, `UPDATE failed_ranges SET resolved_at = NOW() WHERE id = $1 AND resolved_at IS NULL`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve failed range: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve failed range: %w", err)
+	}
+	return n > 0, nil
+}
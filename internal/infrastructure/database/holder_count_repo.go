@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure HolderCountRepo implements HolderCountRepository
+var _ repositories.HolderCountRepository = (*HolderCountRepo)(nil)
+
+// HolderCountRepo implements HolderCountRepository using PostgreSQL
+type HolderCountRepo struct {
+	db *sqlx.DB
+}
+
+// NewHolderCountRepo creates a new holder count repository
+func NewHolderCountRepo(db *sqlx.DB) *HolderCountRepo {
+	return &HolderCountRepo{db: db}
+}
+
+// Save records a token's holder count, replacing the previous one
+func (r *HolderCountRepo) Save(ctx context.Context, tokenAddress string, count int64, countedAt time.Time) error {
+	ctx = withQueryName(ctx, "holder_counts.Save")
+
+	query := `
+		INSERT INTO holder_counts (token_address, holder_count, counted_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (token_address) DO UPDATE SET
+			holder_count = EXCLUDED.holder_count,
+			counted_at = EXCLUDED.counted_at
+	`
+	if _, err := r.db.ExecContext(ctx, query, tokenAddress, count, countedAt); err != nil {
+		return fmt.Errorf("failed to save holder count: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns a token's last recorded holder count
+func (r *HolderCountRepo) Get(ctx context.Context, tokenAddress string) (*repositories.HolderCount, error) {
+	ctx = withQueryName(ctx, "holder_counts.Get")
+
+	var count repositories.HolderCount
+	query := `SELECT token_address, holder_count, counted_at FROM holder_counts WHERE token_address = $1`
+	if err := r.db.GetContext(ctx, &count, query, tokenAddress); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get holder count: %w", err)
+	}
+
+	return &count, nil
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure HolderSnapshotRepo implements HolderSnapshotRepository
+var _ repositories.HolderSnapshotRepository = (*HolderSnapshotRepo)(nil)
+
+// HolderSnapshotRepo implements HolderSnapshotRepository using PostgreSQL
+type HolderSnapshotRepo struct {
+	db *sqlx.DB
+}
+
+// NewHolderSnapshotRepo creates a new holder snapshot repository
+func NewHolderSnapshotRepo(db *sqlx.DB) *HolderSnapshotRepo {
+	return &HolderSnapshotRepo{db: db}
+}
+
+// Save stores a token's top holders as the snapshot taken at takenAt
+func (r *HolderSnapshotRepo) Save(ctx context.Context, tokenAddress string, takenAt time.Time, holders []repositories.HolderBalance) error {
+	ctx = withQueryName(ctx, "holder_snapshots.Save")
+
+	addresses := make([]string, len(holders))
+	balances := make([]string, len(holders))
+	ranks := make([]int64, len(holders))
+	for i, h := range holders {
+		addresses[i] = h.Address
+		balances[i] = h.Balance
+		ranks[i] = int64(h.Rank)
+	}
+
+	query := `
+		INSERT INTO holder_snapshots (token_address, taken_at, address, balance, rank)
+		SELECT $1, $2, h.address, h.balance, h.rank
+		FROM UNNEST($3::varchar[], $4::numeric[], $5::integer[]) AS h(address, balance, rank)
+		ON CONFLICT (token_address, taken_at, address) DO NOTHING
+	`
+	if _, err := r.db.ExecContext(ctx, query, tokenAddress, takenAt, pq.Array(addresses), pq.Array(balances), pq.Array(ranks)); err != nil {
+		return fmt.Errorf("failed to save holder snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetAt returns the latest snapshot taken at or before at, falling back to the earliest one
+func (r *HolderSnapshotRepo) GetAt(ctx context.Context, tokenAddress string, at time.Time) (*repositories.HolderSnapshot, error) {
+	ctx = withQueryName(ctx, "holder_snapshots.GetAt")
+
+	// Snapshots at or before at sort first, newest first; later ones follow oldest first
+	var takenAt time.Time
+	timeQuery := `
+		SELECT taken_at
+		FROM holder_snapshots
+		WHERE token_address = $1
+		GROUP BY taken_at
+		ORDER BY taken_at > $2, CASE WHEN taken_at <= $2 THEN taken_at END DESC, taken_at
+		LIMIT 1
+	`
+	if err := r.db.GetContext(ctx, &takenAt, timeQuery, tokenAddress, at); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find holder snapshot: %w", err)
+	}
+
+	var rows []holderBalanceRow
+	query := `
+		SELECT address, balance::TEXT as balance, rank
+		FROM holder_snapshots
+		WHERE token_address = $1 AND taken_at = $2
+		ORDER BY rank
+	`
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, takenAt); err != nil {
+		return nil, fmt.Errorf("failed to get holder snapshot: %w", err)
+	}
+
+	snapshot := &repositories.HolderSnapshot{
+		TokenAddress: tokenAddress,
+		TakenAt:      takenAt,
+		Holders:      make([]repositories.HolderBalance, len(rows)),
+	}
+	for i, row := range rows {
+		snapshot.Holders[i] = repositories.HolderBalance{
+			Address: row.Address,
+			Balance: row.Balance,
+			Rank:    row.Rank,
+		}
+	}
+
+	return snapshot, nil
+}
+
+// DeleteBefore removes snapshots taken before the given time
+func (r *HolderSnapshotRepo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withQueryName(ctx, "holder_snapshots.DeleteBefore")
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM holder_snapshots WHERE taken_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete holder snapshots: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return deleted, nil
+}
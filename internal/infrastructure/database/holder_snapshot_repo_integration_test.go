@@ -0,0 +1,54 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+	"github.com/bimakw/chain-indexer/internal/testutil/integration"
+)
+
+func TestHolderSnapshotRepo_Integration(t *testing.T) {
+	db := integration.Postgres(t)
+	repo := NewHolderSnapshotRepo(db)
+	ctx := context.Background()
+	token := testutil.USDTAddress
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	for i, holder := range []string{testutil.AliceAddress, testutil.BobAddress, testutil.CharlieAddr} {
+		holders := []repositories.HolderBalance{{Address: holder, Balance: tokens(int64(i + 1)).String(), Rank: 1}}
+		if err := repo.Save(ctx, token, day.Add(time.Duration(i)*time.Hour), holders); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		at     time.Time
+		holder string
+	}{
+		{day.Add(90 * time.Minute), testutil.BobAddress}, // latest at or before
+		{day.Add(-time.Hour), testutil.AliceAddress},     // all newer: earliest
+		{day.Add(24 * time.Hour), testutil.CharlieAddr},  // all older: latest
+	} {
+		snapshot, err := repo.GetAt(ctx, token, tc.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snapshot == nil || len(snapshot.Holders) != 1 || snapshot.Holders[0].Address != tc.holder {
+			t.Errorf("at %v: expected %s, got %+v", tc.at, tc.holder, snapshot)
+		}
+	}
+
+	deleted, err := repo.DeleteBefore(ctx, day.Add(time.Hour))
+	if err != nil || deleted != 1 {
+		t.Errorf("expected 1 deleted row, got %d (%v)", deleted, err)
+	}
+
+	if snapshot, err := repo.GetAt(ctx, testutil.USDCAddress, day); err != nil || snapshot != nil {
+		t.Errorf("expected no snapshot for another token, got %+v (%v)", snapshot, err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -27,6 +28,8 @@ func NewIndexerStateRepo(db *sqlx.DB) *IndexerStateRepo {
 
 // Get retrieves the indexer state for a token
 func (r *IndexerStateRepo) Get(ctx context.Context, tokenAddress string) (*entities.IndexerState, error) {
+	ctx = withQueryName(ctx, "indexer_state.Get")
+
 	var state entities.IndexerState
 	query := `SELECT * FROM indexer_state WHERE token_address = $1`
 
@@ -42,6 +45,8 @@ func (r *IndexerStateRepo) Get(ctx context.Context, tokenAddress string) (*entit
 
 // Upsert creates or updates the indexer state
 func (r *IndexerStateRepo) Upsert(ctx context.Context, state *entities.IndexerState) error {
+	ctx = withQueryName(ctx, "indexer_state.Upsert")
+
 	query := `
 		INSERT INTO indexer_state (token_address, last_indexed_block, is_backfilling, backfill_from_block, backfill_to_block)
 		VALUES ($1, $2, $3, $4, $5)
@@ -69,6 +74,8 @@ func (r *IndexerStateRepo) Upsert(ctx context.Context, state *entities.IndexerSt
 
 // UpdateLastBlock updates the last indexed block for a token
 func (r *IndexerStateRepo) UpdateLastBlock(ctx context.Context, tokenAddress string, blockNumber int64) error {
+	ctx = withQueryName(ctx, "indexer_state.UpdateLastBlock")
+
 	query := `
 		UPDATE indexer_state SET
 			last_indexed_block = $2,
@@ -97,13 +104,39 @@ func (r *IndexerStateRepo) UpdateLastBlock(ctx context.Context, tokenAddress str
 	return nil
 }
 
-// SetBackfilling sets the backfilling state for a token
+// GetOldestCheckpointTime returns when the least recently advanced checkpoint of an active token
+// last moved. Deactivated tokens are left out, since their checkpoints stop on purpose.
+func (r *IndexerStateRepo) GetOldestCheckpointTime(ctx context.Context) (*time.Time, error) {
+	ctx = withQueryName(ctx, "indexer_state.GetOldestCheckpointTime")
+
+	query := `
+		SELECT MIN(s.updated_at)
+		FROM indexer_state s
+		JOIN tokens t ON t.address = s.token_address
+		WHERE t.active
+	`
+
+	var ts *time.Time
+	if err := r.db.GetContext(ctx, &ts, query); err != nil {
+		return nil, fmt.Errorf("failed to get oldest checkpoint time: %w", err)
+	}
+
+	return ts, nil
+}
+
+// SetBackfilling sets the backfilling state for a token, clearing the backfill checkpoint
+// unless the range is the one it was recorded for
 func (r *IndexerStateRepo) SetBackfilling(ctx context.Context, tokenAddress string, isBackfilling bool, fromBlock, toBlock *int64) error {
+	ctx = withQueryName(ctx, "indexer_state.SetBackfilling")
+
 	query := `
 		UPDATE indexer_state SET
 			is_backfilling = $2,
 			backfill_from_block = $3,
 			backfill_to_block = $4,
+			backfill_checkpoint = CASE
+				WHEN backfill_from_block IS NOT DISTINCT FROM $3 AND backfill_to_block IS NOT DISTINCT FROM $4 THEN backfill_checkpoint
+			END,
 			updated_at = NOW()
 		WHERE token_address = $1
 	`
@@ -115,3 +148,17 @@ func (r *IndexerStateRepo) SetBackfilling(ctx context.Context, tokenAddress stri
 
 	return nil
 }
+
+// UpdateBackfillCheckpoint records the last block of the completed prefix of the backfill range.
+// updated_at is left alone: it dates the live checkpoint, which health freshness is measured by.
+func (r *IndexerStateRepo) UpdateBackfillCheckpoint(ctx context.Context, tokenAddress string, blockNumber int64) error {
+	ctx = withQueryName(ctx, "indexer_state.UpdateBackfillCheckpoint")
+
+	query := `UPDATE indexer_state SET backfill_checkpoint = $2 WHERE token_address = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, tokenAddress, blockNumber); err != nil {
+		return fmt.Errorf("failed to update backfill checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var dbQueryDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Database query duration in seconds by named query",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	},
+	[]string{"query", "status"},
+)
+
+// queryNameKey is the context key carrying the name of the current query
+type queryNameKey struct{}
+
+// withQueryName annotates ctx so the instrumented driver can label the query
+func withQueryName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+// queryName returns the query name stored in ctx, or "unnamed"
+func queryName(ctx context.Context) string {
+	if name, ok := ctx.Value(queryNameKey{}).(string); ok && name != "" {
+		return name
+	}
+	return "unnamed"
+}
+
+// instrumentation holds the settings shared by all instrumented connections
+type instrumentation struct {
+	logger        *zap.Logger
+	slowThreshold time.Duration
+}
+
+// observe records the duration of a query and logs it if it was slow.
+// Arguments are never logged, only their count, so values stay redacted.
+func (in *instrumentation) observe(ctx context.Context, query string, argCount int, start time.Time, err error) {
+	duration := time.Since(start)
+	name := queryName(ctx)
+
+	status := "ok"
+	if err != nil && !errors.Is(err, driver.ErrSkip) {
+		status = "error"
+	}
+	dbQueryDuration.WithLabelValues(name, status).Observe(duration.Seconds())
+
+	if in.slowThreshold > 0 && duration >= in.slowThreshold {
+		in.logger.Warn("Slow query",
+			zap.String("query_name", name),
+			zap.Duration("duration", duration),
+			zap.String("sql", compactSQL(query)),
+			zap.Int("arg_count", argCount),
+		)
+	}
+}
+
+// compactSQL collapses whitespace so multi-line queries log on a single line
+func compactSQL(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// instrumentedConnector wraps a driver.Connector so every connection is instrumented
+type instrumentedConnector struct {
+	base driver.Connector
+	in   *instrumentation
+}
+
+func (c *instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn, in: c.in}, nil
+}
+
+func (c *instrumentedConnector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// instrumentedConn times queries and executions on the wrapped connection
+type instrumentedConn struct {
+	driver.Conn
+	in *instrumentation
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	c.in.observe(ctx, query, len(args), start, err)
+	return rows, err
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	c.in.observe(ctx, query, len(args), start, err)
+	return result, err
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedStmt{Stmt: stmt, query: query, in: c.in}, nil
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck // fallback for drivers without BeginTx
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// instrumentedStmt times executions of a prepared statement
+type instrumentedStmt struct {
+	driver.Stmt
+	query string
+	in    *instrumentation
+}
+
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := s.Stmt.(driver.StmtExecContext)
+	if !ok {
+		return nil, errors.New("prepared statement does not support ExecContext")
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, args)
+	s.in.observe(ctx, s.query, len(args), start, err)
+	return result, err
+}
+
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := s.Stmt.(driver.StmtQueryContext)
+	if !ok {
+		return nil, errors.New("prepared statement does not support QueryContext")
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, args)
+	s.in.observe(ctx, s.query, len(args), start, err)
+	return rows, err
+}
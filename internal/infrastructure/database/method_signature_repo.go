@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure MethodSignatureRepo implements MethodSignatureRepository
+var _ repositories.MethodSignatureRepository = (*MethodSignatureRepo)(nil)
+
+// MethodSignatureRepo implements MethodSignatureRepository using PostgreSQL
+type MethodSignatureRepo struct {
+	db *sqlx.DB
+}
+
+// NewMethodSignatureRepo creates a new method signature repository
+func NewMethodSignatureRepo(db *sqlx.DB) *MethodSignatureRepo {
+	return &MethodSignatureRepo{db: db}
+}
+
+// Upsert inserts a signature or updates the name and label of an existing selector
+func (r *MethodSignatureRepo) Upsert(ctx context.Context, sig *entities.MethodSignature) error {
+	ctx = withQueryName(ctx, "method_signatures.Upsert")
+
+	query := `
+		INSERT INTO method_signatures (selector, name, label)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (selector) DO UPDATE SET
+			name = EXCLUDED.name,
+			label = EXCLUDED.label,
+			updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+	row := r.db.QueryRowxContext(ctx, query, sig.Selector, sig.Name, sig.Label)
+	if err := row.Scan(&sig.CreatedAt, &sig.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to upsert method signature: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every signature
+func (r *MethodSignatureRepo) List(ctx context.Context) ([]entities.MethodSignature, error) {
+	ctx = withQueryName(ctx, "method_signatures.List")
+
+	var sigs []entities.MethodSignature
+	query := `
+		SELECT selector, name, label, created_at, updated_at
+		FROM method_signatures
+		ORDER BY label, selector
+	`
+
+	if err := r.db.SelectContext(ctx, &sigs, query); err != nil {
+		return nil, fmt.Errorf("failed to list method signatures: %w", err)
+	}
+
+	return sigs, nil
+}
+
+// GetBySelectors returns the signatures known for the given selectors
+func (r *MethodSignatureRepo) GetBySelectors(ctx context.Context, selectors []string) ([]entities.MethodSignature, error) {
+	ctx = withQueryName(ctx, "method_signatures.GetBySelectors")
+
+	if len(selectors) == 0 {
+		return nil, nil
+	}
+
+	var sigs []entities.MethodSignature
+	query := `
+		SELECT selector, name, label, created_at, updated_at
+		FROM method_signatures
+		WHERE selector = ANY($1)
+	`
+
+	if err := r.db.SelectContext(ctx, &sigs, query, pq.Array(selectors)); err != nil {
+		return nil, fmt.Errorf("failed to get method signatures: %w", err)
+	}
+
+	return sigs, nil
+}
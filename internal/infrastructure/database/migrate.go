@@ -0,0 +1,272 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// migrationLockID is the advisory lock held while migrating, so concurrent deploys apply
+// each migration once
+const migrationLockID = 7_302_114_519
+
+// migrationFile matches NNNNNN_name.up.sql and NNNNNN_name.down.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// ErrDirtySchema is returned when a previous migration failed halfway
+var ErrDirtySchema = errors.New("schema is dirty")
+
+// Migration is one numbered schema change
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// LoadMigrations reads the migrations in fsys, ordered by version. Every version needs both
+// an up and a down file.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrator applies migrations and records the version in the same schema_migrations table
+// golang-migrate uses, so either tool can take over from the other
+type Migrator struct {
+	db         *PostgresDB
+	migrations []Migration
+	logger     *zap.Logger
+}
+
+// NewMigrator creates a migrator for migrations ordered by version
+func NewMigrator(db *PostgresDB, migrations []Migration, logger *zap.Logger) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		logger:     logger,
+	}
+}
+
+// Up applies up to n pending migrations, or all of them when n <= 0, and returns how many ran
+func (m *Migrator) Up(ctx context.Context, n int) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			if n > 0 && applied == n {
+				break
+			}
+			if err := m.apply(ctx, conn, migration, migration.Up, migration.Version); err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the n most recent migrations and returns how many ran
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	reverted := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if current == 0 {
+			return nil
+		}
+
+		idx := m.index(current)
+		if idx < 0 {
+			return fmt.Errorf("schema version %d has no migration in this build", current)
+		}
+		for ; idx >= 0 && reverted < n; idx-- {
+			var previous int64
+			if idx > 0 {
+				previous = m.migrations[idx-1].Version
+			}
+			migration := m.migrations[idx]
+			if err := m.apply(ctx, conn, migration, migration.Down, previous); err != nil {
+				return err
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force records version as applied and clean without running anything, to recover from a
+// failed migration after fixing the schema by hand or to adopt a schema created another way
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("no migration with version %d", version)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		return setSchemaVersion(ctx, conn, version, false)
+	})
+}
+
+// Latest returns the version of the newest migration
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// apply runs one direction of a migration, marking the schema dirty at the migration's version
+// while it runs and clean at version once it succeeded
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration, query string, version int64) error {
+	m.logger.Info("Applying migration",
+		zap.Int64("version", migration.Version),
+		zap.String("name", migration.Name),
+		zap.Int64("target", version),
+	)
+
+	if err := setSchemaVersion(ctx, conn, migration.Version, true); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return setSchemaVersion(ctx, conn, version, false)
+}
+
+// cleanVersion returns the current schema version, refusing to continue from a dirty one
+func (m *Migrator) cleanVersion(ctx context.Context, conn *sql.Conn) (int64, error) {
+	var version int64
+	var dirty bool
+	err := conn.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d: fix the schema, then force the version", ErrDirtySchema, version)
+	}
+	return version, nil
+}
+
+// index returns the position of version in the migrations, or -1
+func (m *Migrator) index(version int64) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory lock, creating
+// schema_migrations first if needed
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.DB().Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Use a fresh context so the lock is released even when ctx was canceled
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID); err != nil {
+			m.logger.Warn("Failed to release migration lock", zap.Error(err))
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			dirty BOOLEAN NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// setSchemaVersion replaces the single schema_migrations row; version 0 means no migrations
+func setSchemaVersion(ctx context.Context, conn *sql.Conn, version int64, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return fmt.Errorf("failed to clear schema version: %w", err)
+	}
+	if version > 0 || dirty {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)`, version, dirty); err != nil {
+			return fmt.Errorf("failed to set schema version: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit schema version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/bimakw/chain-indexer/migrations"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_tokens.up.sql":     {Data: []byte("CREATE TABLE tokens ();")},
+		"000002_tokens.down.sql":   {Data: []byte("DROP TABLE tokens;")},
+		"000001_init.up.sql":       {Data: []byte("CREATE TABLE init ();")},
+		"000001_init.down.sql":     {Data: []byte("DROP TABLE init;")},
+		"embed.go":                 {Data: []byte("package migrations")},
+		"clickhouse/000001.up.sql": {Data: []byte("CREATE TABLE x ();")},
+	}
+
+	got, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("LoadMigrations() returned %d migrations, want 2", len(got))
+	}
+	if got[0].Version != 1 || got[0].Name != "init" || got[1].Version != 2 || got[1].Name != "tokens" {
+		t.Errorf("LoadMigrations() = %+v, want init then tokens", got)
+	}
+	if got[1].Up != "CREATE TABLE tokens ();" || got[1].Down != "DROP TABLE tokens;" {
+		t.Errorf("migration 2 = %+v, want its up and down SQL", got[1])
+	}
+}
+
+func TestLoadMigrations_MissingDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_init.up.sql": {Data: []byte("CREATE TABLE init ();")},
+	}
+
+	if _, err := LoadMigrations(fsys); err == nil {
+		t.Error("LoadMigrations() error = nil, want error for missing down file")
+	}
+}
+
+func TestSchemaVersion_MatchesLatestMigration(t *testing.T) {
+	all, err := LoadMigrations(migrations.Files)
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+
+	latest := NewMigrator(nil, all, nil).Latest()
+	if latest != SchemaVersion {
+		t.Errorf("latest migration = %d, SchemaVersion = %d; bump SchemaVersion with each migration", latest, SchemaVersion)
+	}
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+// IndexedTokensNotifyChannel is the channel the transfers insert trigger notifies with each
+// token address that got new transfers
+const IndexedTokensNotifyChannel = "indexed_tokens"
+
+// notificationBuffer is how many token addresses are held for a busy receiver before dropping more
+const notificationBuffer = 256
+
+// ListenIndexedTokens returns the token addresses the database announces as transfers are
+// inserted, until ctx is done, when the returned channel is closed. It needs the PostgreSQL
+// primary, since notifications are not sent to replicas. The listener reconnects on its own;
+// notifications sent while it is disconnected are lost.
+func ListenIndexedTokens(ctx context.Context, cfg config.DatabaseConfig, logger *zap.Logger) (<-chan string, error) {
+	if cfg.Driver != "postgres" {
+		return nil, fmt.Errorf("database notifications require postgres, not %s", cfg.Driver)
+	}
+
+	listener := pq.NewListener(postgresDSN(cfg), time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			logger.Warn("Lost database notification connection", zap.Error(err))
+		case pq.ListenerEventReconnected:
+			logger.Info("Reconnected database notification connection")
+		case pq.ListenerEventConnectionAttemptFailed:
+			logger.Warn("Failed to reconnect database notification connection", zap.Error(err))
+		}
+	})
+	if err := listener.Listen(IndexedTokensNotifyChannel); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", IndexedTokensNotifyChannel, err)
+	}
+
+	out := make(chan string, notificationBuffer)
+	go func() {
+		defer close(out)
+		defer listener.Close()
+
+		// Pings detect a dead connection that would otherwise wait for notifications forever
+		ping := time.NewTicker(time.Minute)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n, ok := <-listener.Notify:
+				if !ok {
+					return
+				}
+				// A nil notification follows a reconnect
+				if n == nil {
+					continue
+				}
+				select {
+				case out <- n.Extra:
+				default:
+				}
+			case <-ping.C:
+				_ = listener.Ping()
+			}
+		}
+	}()
+	return out, nil
+}
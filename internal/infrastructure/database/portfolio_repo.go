@@ -3,11 +3,14 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
 	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/pkg/units"
 )
 
 // Ensure PortfolioRepo implements PortfolioRepository
@@ -34,6 +37,8 @@ type holdingRow struct {
 
 // GetWalletHoldings retrieves all token holdings for a wallet
 func (r *PortfolioRepo) GetWalletHoldings(ctx context.Context, walletAddress string) ([]entities.TokenHolding, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletHoldings")
+
 	query := `
 		WITH balances AS (
 			SELECT
@@ -70,7 +75,7 @@ func (r *PortfolioRepo) GetWalletHoldings(ctx context.Context, walletAddress str
 			TokenSymbol:  row.TokenSymbol,
 			Decimals:     row.Decimals,
 			BalanceStr:   row.Balance,
-			BalanceHuman: formatBalance(row.Balance, row.Decimals),
+			BalanceHuman: units.Format(row.Balance, row.Decimals),
 		}
 	}
 
@@ -79,6 +84,8 @@ func (r *PortfolioRepo) GetWalletHoldings(ctx context.Context, walletAddress str
 
 // GetWalletHoldingByToken retrieves holding for specific token
 func (r *PortfolioRepo) GetWalletHoldingByToken(ctx context.Context, walletAddress, tokenAddress string) (*entities.TokenHolding, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletHoldingByToken")
+
 	query := `
 		SELECT
 			$2 as token_address,
@@ -108,12 +115,14 @@ func (r *PortfolioRepo) GetWalletHoldingByToken(ctx context.Context, walletAddre
 		TokenSymbol:  row.TokenSymbol,
 		Decimals:     row.Decimals,
 		BalanceStr:   row.Balance,
-		BalanceHuman: formatBalance(row.Balance, row.Decimals),
+		BalanceHuman: units.Format(row.Balance, row.Decimals),
 	}, nil
 }
 
 // GetWalletTokenCount returns count of tokens held by wallet
 func (r *PortfolioRepo) GetWalletTokenCount(ctx context.Context, walletAddress string) (int64, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletTokenCount")
+
 	query := `
 		WITH balances AS (
 			SELECT
@@ -150,6 +159,8 @@ type summaryRow struct {
 
 // GetWalletTransferSummary returns transfer stats for a wallet
 func (r *PortfolioRepo) GetWalletTransferSummary(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletTransferSummary")
+
 	query := `
 		SELECT
 			COUNT(*) FILTER (WHERE to_address = $1) as total_in,
@@ -193,43 +204,348 @@ func (r *PortfolioRepo) GetWalletTransferSummary(ctx context.Context, walletAddr
 	return result, nil
 }
 
-// formatBalance converts raw balance to human readable format with decimals
-func formatBalance(balance string, decimals int) string {
-	if balance == "" || balance == "0" {
-		return "0"
+// GetWalletBalancesAt returns non-zero token balances from transfers before the given time
+func (r *PortfolioRepo) GetWalletBalancesAt(ctx context.Context, walletAddress string, at time.Time) ([]entities.TokenHolding, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletBalancesAt")
+
+	query := `
+		WITH balances AS (
+			SELECT
+				token_address,
+				SUM(CASE WHEN to_address = $1 THEN value ELSE 0 END) -
+				SUM(CASE WHEN from_address = $1 THEN value ELSE 0 END) as balance
+			FROM transfers
+			WHERE (from_address = $1 OR to_address = $1)
+				AND block_timestamp < $2
+			GROUP BY token_address
+		)
+		SELECT
+			b.token_address,
+			t.name,
+			t.symbol,
+			t.decimals,
+			b.balance::text as balance
+		FROM balances b
+		JOIN tokens t ON t.address = b.token_address
+		WHERE b.balance <> 0
+	`
+
+	var rows []holdingRow
+	if err := r.db.SelectContext(ctx, &rows, query, walletAddress, at); err != nil {
+		return nil, fmt.Errorf("failed to get wallet balances: %w", err)
 	}
 
-	// Pad with leading zeros if necessary
-	for len(balance) <= decimals {
-		balance = "0" + balance
+	holdings := make([]entities.TokenHolding, len(rows))
+	for i, row := range rows {
+		holdings[i] = entities.TokenHolding{
+			TokenAddress: row.TokenAddress,
+			TokenName:    row.TokenName,
+			TokenSymbol:  row.TokenSymbol,
+			Decimals:     row.Decimals,
+			BalanceStr:   row.Balance,
+			BalanceHuman: units.Format(row.Balance, row.Decimals),
+		}
 	}
 
-	// Insert decimal point
-	if decimals > 0 {
-		insertPos := len(balance) - decimals
-		intPart := balance[:insertPos]
-		decPart := balance[insertPos:]
+	return holdings, nil
+}
 
-		// Trim trailing zeros from decimal part
-		decPart = trimTrailingZeros(decPart)
+// dailyChangeRow holds the result of the daily balance change query
+type dailyChangeRow struct {
+	TokenAddress string    `db:"token_address"`
+	Decimals     int       `db:"decimals"`
+	Day          time.Time `db:"day"`
+	Change       string    `db:"change"`
+}
+
+// GetWalletDailyBalanceChanges returns per-day net balance changes since the given time, oldest first
+func (r *PortfolioRepo) GetWalletDailyBalanceChanges(ctx context.Context, walletAddress string, from time.Time) ([]repositories.DailyBalanceChange, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletDailyBalanceChanges")
+
+	query := `
+		SELECT
+			tr.token_address,
+			t.decimals,
+			time_bucket('1 day', tr.block_timestamp) as day,
+			(SUM(CASE WHEN tr.to_address = $1 THEN tr.value ELSE 0 END) -
+			 SUM(CASE WHEN tr.from_address = $1 THEN tr.value ELSE 0 END))::text as change
+		FROM transfers tr
+		JOIN tokens t ON t.address = tr.token_address
+		WHERE (tr.from_address = $1 OR tr.to_address = $1)
+			AND tr.block_timestamp >= $2
+		GROUP BY tr.token_address, t.decimals, day
+		ORDER BY day
+	`
+
+	var rows []dailyChangeRow
+	if err := r.db.SelectContext(ctx, &rows, query, walletAddress, from); err != nil {
+		return nil, fmt.Errorf("failed to get wallet daily balance changes: %w", err)
+	}
 
-		if decPart == "" {
-			return intPart
+	changes := make([]repositories.DailyBalanceChange, len(rows))
+	for i, row := range rows {
+		changes[i] = repositories.DailyBalanceChange{
+			TokenAddress: row.TokenAddress,
+			Decimals:     row.Decimals,
+			Day:          row.Day.UTC(),
+			Change:       row.Change,
 		}
-		return intPart + "." + decPart
 	}
 
-	return balance
+	return changes, nil
+}
+
+// tokenActivityRow holds the result of the token activity query
+type tokenActivityRow struct {
+	TokenAddress  string    `db:"token_address"`
+	TokenName     string    `db:"name"`
+	TokenSymbol   string    `db:"symbol"`
+	Decimals      int       `db:"decimals"`
+	Balance       string    `db:"balance"`
+	TransfersIn   int64     `db:"transfers_in"`
+	TransfersOut  int64     `db:"transfers_out"`
+	FirstActivity time.Time `db:"first_activity"`
+	LastActivity  time.Time `db:"last_activity"`
 }
 
-// trimTrailingZeros removes trailing zeros from a string
-func trimTrailingZeros(s string) string {
-	i := len(s) - 1
-	for i >= 0 && s[i] == '0' {
-		i--
+// GetWalletTokenActivity returns every token the wallet has sent or received, most recently active first
+func (r *PortfolioRepo) GetWalletTokenActivity(ctx context.Context, walletAddress string) ([]repositories.WalletTokenActivity, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletTokenActivity")
+
+	query := `
+		SELECT
+			tr.token_address,
+			t.name,
+			t.symbol,
+			t.decimals,
+			(
+				COALESCE(SUM(tr.value) FILTER (WHERE tr.to_address = $1), 0) -
+				COALESCE(SUM(tr.value) FILTER (WHERE tr.from_address = $1), 0)
+			)::text as balance,
+			COUNT(*) FILTER (WHERE tr.to_address = $1) as transfers_in,
+			COUNT(*) FILTER (WHERE tr.from_address = $1) as transfers_out,
+			MIN(tr.block_timestamp) as first_activity,
+			MAX(tr.block_timestamp) as last_activity
+		FROM transfers tr
+		JOIN tokens t ON t.address = tr.token_address
+		WHERE tr.from_address = $1 OR tr.to_address = $1
+		GROUP BY tr.token_address, t.name, t.symbol, t.decimals
+		ORDER BY MAX(tr.block_timestamp) DESC, tr.token_address
+	`
+
+	var rows []tokenActivityRow
+	if err := r.db.SelectContext(ctx, &rows, query, walletAddress); err != nil {
+		return nil, fmt.Errorf("failed to get wallet token activity: %w", err)
 	}
-	if i < 0 {
-		return ""
+
+	result := make([]repositories.WalletTokenActivity, len(rows))
+	for i, row := range rows {
+		result[i] = repositories.WalletTokenActivity{
+			TokenAddress:    row.TokenAddress,
+			TokenName:       row.TokenName,
+			TokenSymbol:     row.TokenSymbol,
+			Decimals:        row.Decimals,
+			Balance:         row.Balance,
+			TransfersIn:     row.TransfersIn,
+			TransfersOut:    row.TransfersOut,
+			FirstActivityAt: row.FirstActivity,
+			LastActivityAt:  row.LastActivity,
+		}
 	}
-	return s[:i+1]
+
+	return result, nil
+}
+
+// GetEntityHoldings retrieves the combined positive token balances of a group of addresses.
+// Transfers between members add and subtract the same amount, so they cancel out.
+func (r *PortfolioRepo) GetEntityHoldings(ctx context.Context, addresses []string) ([]entities.TokenHolding, error) {
+	ctx = withQueryName(ctx, "portfolio.GetEntityHoldings")
+
+	query := `
+		WITH balances AS (
+			SELECT
+				token_address,
+				SUM(CASE WHEN to_address = ANY($1) THEN value ELSE 0 END) -
+				SUM(CASE WHEN from_address = ANY($1) THEN value ELSE 0 END) as balance
+			FROM transfers
+			WHERE from_address = ANY($1) OR to_address = ANY($1)
+			GROUP BY token_address
+			HAVING SUM(CASE WHEN to_address = ANY($1) THEN value ELSE 0 END) -
+				   SUM(CASE WHEN from_address = ANY($1) THEN value ELSE 0 END) > 0
+		)
+		SELECT
+			b.token_address,
+			t.name,
+			t.symbol,
+			t.decimals,
+			b.balance::text as balance
+		FROM balances b
+		JOIN tokens t ON t.address = b.token_address
+		ORDER BY b.balance DESC
+	`
+
+	var rows []holdingRow
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(addresses)); err != nil {
+		return nil, fmt.Errorf("failed to get entity holdings: %w", err)
+	}
+
+	holdings := make([]entities.TokenHolding, len(rows))
+	for i, row := range rows {
+		holdings[i] = entities.TokenHolding{
+			TokenAddress: row.TokenAddress,
+			TokenName:    row.TokenName,
+			TokenSymbol:  row.TokenSymbol,
+			Decimals:     row.Decimals,
+			BalanceStr:   row.Balance,
+			BalanceHuman: units.Format(row.Balance, row.Decimals),
+		}
+	}
+
+	return holdings, nil
+}
+
+// entityVolumeRow holds the result of the entity volume query
+type entityVolumeRow struct {
+	TokenAddress      string    `db:"token_address"`
+	TokenSymbol       string    `db:"symbol"`
+	Decimals          int       `db:"decimals"`
+	TransfersIn       int64     `db:"transfers_in"`
+	TransfersOut      int64     `db:"transfers_out"`
+	InternalTransfers int64     `db:"internal_transfers"`
+	VolumeIn          string    `db:"volume_in"`
+	VolumeOut         string    `db:"volume_out"`
+	InternalVolume    string    `db:"internal_volume"`
+	FirstActivity     time.Time `db:"first_activity"`
+	LastActivity      time.Time `db:"last_activity"`
+}
+
+// GetEntityTokenVolumes returns a group of addresses' per-token flows, most recently active first
+func (r *PortfolioRepo) GetEntityTokenVolumes(ctx context.Context, addresses []string) ([]repositories.EntityTokenVolume, error) {
+	ctx = withQueryName(ctx, "portfolio.GetEntityTokenVolumes")
+
+	query := `
+		WITH flows AS (
+			SELECT
+				token_address,
+				value,
+				block_timestamp,
+				from_address = ANY($1) as from_member,
+				to_address = ANY($1) as to_member
+			FROM transfers
+			WHERE from_address = ANY($1) OR to_address = ANY($1)
+		)
+		SELECT
+			f.token_address,
+			t.symbol,
+			t.decimals,
+			COUNT(*) FILTER (WHERE f.to_member AND NOT f.from_member) as transfers_in,
+			COUNT(*) FILTER (WHERE f.from_member AND NOT f.to_member) as transfers_out,
+			COUNT(*) FILTER (WHERE f.from_member AND f.to_member) as internal_transfers,
+			COALESCE(SUM(f.value) FILTER (WHERE f.to_member AND NOT f.from_member), 0)::text as volume_in,
+			COALESCE(SUM(f.value) FILTER (WHERE f.from_member AND NOT f.to_member), 0)::text as volume_out,
+			COALESCE(SUM(f.value) FILTER (WHERE f.from_member AND f.to_member), 0)::text as internal_volume,
+			MIN(f.block_timestamp) as first_activity,
+			MAX(f.block_timestamp) as last_activity
+		FROM flows f
+		JOIN tokens t ON t.address = f.token_address
+		GROUP BY f.token_address, t.symbol, t.decimals
+		ORDER BY MAX(f.block_timestamp) DESC, f.token_address
+	`
+
+	var rows []entityVolumeRow
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(addresses)); err != nil {
+		return nil, fmt.Errorf("failed to get entity token volumes: %w", err)
+	}
+
+	result := make([]repositories.EntityTokenVolume, len(rows))
+	for i, row := range rows {
+		result[i] = repositories.EntityTokenVolume{
+			TokenAddress:      row.TokenAddress,
+			TokenSymbol:       row.TokenSymbol,
+			Decimals:          row.Decimals,
+			TransfersIn:       row.TransfersIn,
+			TransfersOut:      row.TransfersOut,
+			InternalTransfers: row.InternalTransfers,
+			VolumeIn:          row.VolumeIn,
+			VolumeOut:         row.VolumeOut,
+			InternalVolume:    row.InternalVolume,
+			FirstActivityAt:   row.FirstActivity,
+			LastActivityAt:    row.LastActivity,
+		}
+	}
+
+	return result, nil
+}
+
+// counterpartyFlowRow holds the result of the counterparty flow query
+type counterpartyFlowRow struct {
+	FromAddress    string    `db:"from_address"`
+	ToAddress      string    `db:"to_address"`
+	TokenAddress   string    `db:"token_address"`
+	TokenSymbol    string    `db:"symbol"`
+	Decimals       int       `db:"decimals"`
+	Transfers      int64     `db:"transfers"`
+	Volume         string    `db:"volume"`
+	LastTransferAt time.Time `db:"last_transfer_at"`
+}
+
+// GetCounterpartyFlows aggregates a set of addresses' recent transfers into per-token flows
+func (r *PortfolioRepo) GetCounterpartyFlows(ctx context.Context, addresses []string, scanLimit, limit int) ([]repositories.CounterpartyFlow, error) {
+	ctx = withQueryName(ctx, "portfolio.GetCounterpartyFlows")
+
+	// Transfers between two of the addresses are read with the outgoing ones only
+	query := `
+		WITH recent AS (
+			(
+				SELECT from_address, to_address, token_address, value, block_timestamp
+				FROM transfers
+				WHERE from_address = ANY($1)
+				ORDER BY block_timestamp DESC
+				LIMIT $2
+			)
+			UNION ALL
+			(
+				SELECT from_address, to_address, token_address, value, block_timestamp
+				FROM transfers
+				WHERE to_address = ANY($1) AND NOT (from_address = ANY($1))
+				ORDER BY block_timestamp DESC
+				LIMIT $2
+			)
+		)
+		SELECT
+			r.from_address,
+			r.to_address,
+			r.token_address,
+			t.symbol,
+			t.decimals,
+			COUNT(*) as transfers,
+			SUM(r.value)::TEXT as volume,
+			MAX(r.block_timestamp) as last_transfer_at
+		FROM recent r
+		JOIN tokens t ON t.address = r.token_address
+		GROUP BY r.from_address, r.to_address, r.token_address, t.symbol, t.decimals
+		ORDER BY COUNT(*) DESC, MAX(r.block_timestamp) DESC, r.from_address, r.to_address, r.token_address
+		LIMIT $3
+	`
+
+	var rows []counterpartyFlowRow
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(addresses), scanLimit, limit); err != nil {
+		return nil, fmt.Errorf("failed to get counterparty flows: %w", err)
+	}
+
+	result := make([]repositories.CounterpartyFlow, len(rows))
+	for i, row := range rows {
+		result[i] = repositories.CounterpartyFlow{
+			FromAddress:    row.FromAddress,
+			ToAddress:      row.ToAddress,
+			TokenAddress:   row.TokenAddress,
+			TokenSymbol:    row.TokenSymbol,
+			Decimals:       row.Decimals,
+			Transfers:      row.Transfers,
+			Volume:         row.Volume,
+			LastTransferAt: row.LastTransferAt,
+		}
+	}
+
+	return result, nil
 }
@@ -0,0 +1,117 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/testutil"
+	"github.com/bimakw/chain-indexer/internal/testutil/integration"
+)
+
+func TestPortfolioRepo_Integration(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewPortfolioRepo(db)
+	ctx := context.Background()
+
+	holdings, err := repo.GetWalletHoldings(ctx, testutil.BobAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holdings) != 1 || holdings[0].TokenAddress != testutil.USDTAddress || holdings[0].BalanceStr != tokens(600).String() {
+		t.Errorf("unexpected holdings: %+v", holdings)
+	}
+
+	for holder, want := range map[string]int64{
+		testutil.AliceAddress: 1,
+		testutil.CharlieAddr:  0,
+	} {
+		count, err := repo.GetWalletTokenCount(ctx, holder)
+		if err != nil || count != want {
+			t.Errorf("%s: expected %d tokens, got %d (%v)", holder, want, count, err)
+		}
+	}
+
+	holding, err := repo.GetWalletHoldingByToken(ctx, testutil.CharlieAddr, testutil.USDTAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if holding.BalanceStr != "0" || holding.TokenSymbol != "USDT" {
+		t.Errorf("unexpected holding: %+v", holding)
+	}
+
+	summary, err := repo.GetWalletTransferSummary(ctx, testutil.AliceAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalTransfersIn != 1 || summary.TotalTransfersOut != 2 ||
+		summary.TotalVolumeOut != tokens(600).String() || summary.UniqueTokens != 1 || summary.FirstTransferAt == nil {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	seeded := testutil.CreateTestTransfer().BlockTimestamp
+	balances, err := repo.GetWalletBalancesAt(ctx, testutil.AliceAddress, seeded)
+	if err != nil || len(balances) != 0 {
+		t.Errorf("expected no balances before the first transfer, got %+v (%v)", balances, err)
+	}
+	balances, err = repo.GetWalletBalancesAt(ctx, testutil.AliceAddress, seeded.Add(time.Second))
+	if err != nil || len(balances) != 1 || balances[0].BalanceStr != tokens(400).String() {
+		t.Errorf("expected Alice's balance after the transfers, got %+v (%v)", balances, err)
+	}
+}
+
+func TestPortfolioRepo_Integration_TokenActivity(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewPortfolioRepo(db)
+	ctx := context.Background()
+
+	// Charlie received and passed on 200 USDT: no balance, but the token is still listed
+	activity, err := repo.GetWalletTokenActivity(ctx, testutil.CharlieAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seeded := testutil.CreateTestTransfer().BlockTimestamp
+	if len(activity) != 1 ||
+		activity[0].TokenAddress != testutil.USDTAddress || activity[0].TokenSymbol != "USDT" || activity[0].Balance != "0" ||
+		activity[0].TransfersIn != 1 || activity[0].TransfersOut != 1 ||
+		!activity[0].FirstActivityAt.Equal(seeded) || !activity[0].LastActivityAt.Equal(seeded) {
+		t.Errorf("unexpected activity: %+v", activity)
+	}
+
+	activity, err = repo.GetWalletTokenActivity(ctx, testutil.USDCAddress)
+	if err != nil || len(activity) != 0 {
+		t.Errorf("expected no activity for an unknown wallet, got %+v (%v)", activity, err)
+	}
+}
+
+func TestPortfolioRepo_Integration_Entity(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewPortfolioRepo(db)
+	ctx := context.Background()
+	members := []string{testutil.AliceAddress, testutil.CharlieAddr}
+
+	holdings, err := repo.GetEntityHoldings(ctx, members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holdings) != 1 || holdings[0].BalanceStr != tokens(400).String() {
+		t.Errorf("expected the members' combined 400 USDT, got %+v", holdings)
+	}
+
+	// Alice -> Charlie is internal; the mint is the only inflow and the two transfers to Bob the outflows
+	volumes, err := repo.GetEntityTokenVolumes(ctx, members)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(volumes) != 1 ||
+		volumes[0].TransfersIn != 1 || volumes[0].TransfersOut != 2 || volumes[0].InternalTransfers != 1 ||
+		volumes[0].VolumeIn != tokens(1000).String() || volumes[0].VolumeOut != tokens(600).String() ||
+		volumes[0].InternalVolume != tokens(200).String() {
+		t.Errorf("unexpected volumes: %+v", volumes)
+	}
+}
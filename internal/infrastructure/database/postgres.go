@@ -2,51 +2,95 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"go.uber.org/zap"
 
 	"github.com/bimakw/chain-indexer/internal/config"
 )
 
+// SchemaVersion is the number of the latest migration in migrations/ that this build expects
+const SchemaVersion = 30
+
+// ErrNoSchemaVersion is returned when the database has no schema_migrations table, as when the
+// schema was loaded by docker-entrypoint-initdb.d rather than `make migrate-up`
+var ErrNoSchemaVersion = errors.New("no schema_migrations table")
+
 // PostgresDB wraps the sqlx database connection
 type PostgresDB struct {
 	db     *sqlx.DB
 	logger *zap.Logger
 }
 
-// NewPostgresDB creates a new PostgreSQL connection
-func NewPostgresDB(cfg config.DatabaseConfig, logger *zap.Logger) (*PostgresDB, error) {
-	dsn := fmt.Sprintf(
+// postgresDSN returns the lib/pq connection string for cfg
+func postgresDSN(cfg config.DatabaseConfig) string {
+	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode,
 	)
+}
 
-	db, err := sqlx.Connect("postgres", dsn)
+// NewPostgresDB creates a new PostgreSQL connection
+func NewPostgresDB(cfg config.DatabaseConfig, logger *zap.Logger) (*PostgresDB, error) {
+	connector, err := pq.NewConnector(postgresDSN(cfg))
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("failed to create database connector: %w", err)
 	}
 
+	// Wrap the driver so every query is timed and slow queries are logged
+	sqlDB := sql.OpenDB(&instrumentedConnector{
+		base: connector,
+		in: &instrumentation{
+			logger:        logger,
+			slowThreshold: cfg.SlowQueryThreshold,
+		},
+	})
+	db := sqlx.NewDb(sqlDB, "postgres")
+
 	// Configure connection pool
 	db.SetMaxOpenConns(cfg.MaxOpenConns)
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
 	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	// Expose connection pool stats (open, in use, idle, wait count/duration) and saturation
+	for _, collector := range []prometheus.Collector{
+		collectors.NewDBStatsCollector(sqlDB, cfg.Name),
+		newPoolSaturationGauge(sqlDB, cfg.Name),
+	} {
+		if err := prometheus.Register(collector); err != nil {
+			var already prometheus.AlreadyRegisteredError
+			if !errors.As(err, &already) {
+				logger.Warn("Failed to register database pool metrics", zap.Error(err))
+			}
+		}
+	}
+
 	logger.Info("Connected to PostgreSQL",
 		zap.String("host", cfg.Host),
 		zap.Int("port", cfg.Port),
 		zap.String("database", cfg.Name),
+		zap.Int("max_open_conns", cfg.MaxOpenConns),
+		zap.Int("max_idle_conns", cfg.MaxIdleConns),
 	)
 
 	return &PostgresDB{
@@ -55,6 +99,22 @@ func NewPostgresDB(cfg config.DatabaseConfig, logger *zap.Logger) (*PostgresDB,
 	}, nil
 }
 
+// newPoolSaturationGauge reports the share of the connection limit in use. Near 1 means queries
+// are waiting for connections (see go_sql_wait_count_total) and DB_MAX_OPEN_CONNS is the bottleneck.
+func newPoolSaturationGauge(db *sql.DB, dbName string) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "db_pool_saturation",
+		Help:        "Connections in use divided by the maximum open connections (0 when unlimited)",
+		ConstLabels: prometheus.Labels{"db_name": dbName},
+	}, func() float64 {
+		stats := db.Stats()
+		if stats.MaxOpenConnections <= 0 {
+			return 0
+		}
+		return float64(stats.InUse) / float64(stats.MaxOpenConnections)
+	})
+}
+
 // Close closes the database connection
 func (p *PostgresDB) Close() error {
 	return p.db.Close()
@@ -69,3 +129,89 @@ func (p *PostgresDB) DB() *sqlx.DB {
 func (p *PostgresDB) HealthCheck(ctx context.Context) error {
 	return p.db.PingContext(ctx)
 }
+
+// SchemaVersion returns the migration version recorded by golang-migrate and whether a migration
+// failed halfway (dirty), or ErrNoSchemaVersion when migrations were not applied with it
+func (p *PostgresDB) SchemaVersion(ctx context.Context) (int64, bool, error) {
+	ctx = withQueryName(ctx, "schema_migrations.SchemaVersion")
+
+	var exists bool
+	if err := p.db.GetContext(ctx, &exists, `SELECT to_regclass('schema_migrations') IS NOT NULL`); err != nil {
+		return 0, false, fmt.Errorf("failed to check schema_migrations: %w", err)
+	}
+	if !exists {
+		return 0, false, ErrNoSchemaVersion
+	}
+
+	var row struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+	if err := p.db.GetContext(ctx, &row, `SELECT version, dirty FROM schema_migrations LIMIT 1`); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return row.Version, row.Dirty, nil
+}
+
+// MissingTables returns the given tables that do not exist on the search path
+func (p *PostgresDB) MissingTables(ctx context.Context, tables []string) ([]string, error) {
+	ctx = withQueryName(ctx, "schema.MissingTables")
+
+	var missing []string
+	query := `SELECT t FROM unnest($1::text[]) AS t WHERE to_regclass(t) IS NULL ORDER BY t`
+	if err := p.db.SelectContext(ctx, &missing, query, pq.Array(tables)); err != nil {
+		return nil, fmt.Errorf("failed to check tables: %w", err)
+	}
+	return missing, nil
+}
+
+// MissingColumns returns the given table.column names that do not exist on the search path
+func (p *PostgresDB) MissingColumns(ctx context.Context, columns []string) ([]string, error) {
+	ctx = withQueryName(ctx, "schema.MissingColumns")
+
+	var missing []string
+	query := `
+		SELECT c FROM unnest($1::text[]) AS c
+		WHERE NOT EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_schema = ANY(current_schemas(false))
+				AND table_name = split_part(c, '.', 1)
+				AND column_name = split_part(c, '.', 2)
+		)
+		ORDER BY c
+	`
+	if err := p.db.SelectContext(ctx, &missing, query, pq.Array(columns)); err != nil {
+		return nil, fmt.Errorf("failed to check columns: %w", err)
+	}
+	return missing, nil
+}
+
+// IsTransient reports whether err means PostgreSQL could not be reached or was failing over,
+// as opposed to a query that failed on its own, so retrying the query later may succeed
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03", // cannot_connect_now
+			"53300": // too_many_connections
+			return true
+		}
+		// Class 08: connection exception
+		return pqErr.Code.Class() == "08"
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
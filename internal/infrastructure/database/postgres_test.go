@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"bad connection", fmt.Errorf("failed to get token: %w", driver.ErrBadConn), true},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"admin shutdown", &pq.Error{Code: "57P01"}, true},
+		{"connection failure", fmt.Errorf("failed to get transfers: %w", &pq.Error{Code: "08006"}), true},
+		{"syntax error", &pq.Error{Code: "42601"}, false},
+		{"query canceled", &pq.Error{Code: "57014"}, false},
+		{"context canceled", context.Canceled, false},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure RawLogRepo implements RawLogRepository
+var _ repositories.RawLogRepository = (*RawLogRepo)(nil)
+
+// RawLogRepo implements RawLogRepository using PostgreSQL
+type RawLogRepo struct {
+	db *sqlx.DB
+}
+
+// NewRawLogRepo creates a new raw log repository
+func NewRawLogRepo(db *sqlx.DB) *RawLogRepo {
+	return &RawLogRepo{db: db}
+}
+
+// BatchInsert stores raw logs in a single transaction, skipping ones already stored
+func (r *RawLogRepo) BatchInsert(ctx context.Context, logs []entities.RawLog) error {
+	ctx = withQueryName(ctx, "raw_logs.BatchInsert")
+
+	if len(logs) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO raw_logs (block_number, tx_hash, log_index, address, block_timestamp, log)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (block_number, tx_hash, log_index) DO NOTHING
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, l := range logs {
+		// Passed as text: lib/pq would send []byte as bytea, which JSONB rejects
+		_, err := stmt.ExecContext(ctx,
+			l.BlockNumber,
+			l.TxHash,
+			l.LogIndex,
+			l.Address,
+			l.BlockTimestamp,
+			string(l.Log),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert raw log: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetRange retrieves the logs emitted by an address in a block range, in chain order
+func (r *RawLogRepo) GetRange(ctx context.Context, address string, fromBlock, toBlock int64) ([]entities.RawLog, error) {
+	ctx = withQueryName(ctx, "raw_logs.GetRange")
+
+	query := `
+		SELECT block_number, tx_hash, log_index, address, block_timestamp, log, created_at
+		FROM raw_logs
+		WHERE address = $1 AND block_number >= $2 AND block_number <= $3
+		ORDER BY block_number, log_index
+	`
+
+	var logs []entities.RawLog
+	if err := r.db.SelectContext(ctx, &logs, query, address, fromBlock, toBlock); err != nil {
+		return nil, fmt.Errorf("failed to get raw logs: %w", err)
+	}
+
+	return logs, nil
+}
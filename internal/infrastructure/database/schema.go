@@ -0,0 +1,211 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Feature is a part of the API or indexer that depends on tables added after the initial schema,
+// so it can be turned off while a rolling deployment runs ahead of its migrations
+type Feature string
+
+// Features and the tables they need, see featureTables
+const (
+	FeatureSwaps            Feature = "swaps"
+	FeatureDailyStats       Feature = "daily_stats"
+	FeatureWatchlists       Feature = "watchlists"
+	FeatureAlerts           Feature = "alerts"
+	FeatureScreening        Feature = "screening"
+	FeatureHolderSnapshots  Feature = "holder_snapshots"
+	FeatureEntities         Feature = "entities"
+	FeatureEthTransfers     Feature = "eth_transfers"
+	FeatureRawLogs          Feature = "raw_logs"
+	FeatureMethodSignatures Feature = "method_signatures"
+	FeatureHolderCounts     Feature = "holder_counts"
+	FeatureTenants          Feature = "tenants"
+	FeatureNewHolders       Feature = "new_holders"
+	FeatureAdvancedStats    Feature = "advanced_stats"
+	FeatureMetadataHistory  Feature = "metadata_history"
+	FeatureTransferFlags    Feature = "transfer_flags"
+	FeatureFailedRanges     Feature = "failed_ranges"
+	FeatureWalletStats      Feature = "wallet_stats"
+	FeatureAddressNotes     Feature = "address_notes"
+	FeatureContractEvents   Feature = "contract_events"
+)
+
+// featureTables lists the tables each feature reads or writes. The tables of the initial
+// migration (tokens, transfers, indexer_state) are required by everything and not listed.
+var featureTables = map[Feature][]string{
+	FeatureSwaps:            {"dex_pools", "swaps", "scoped_event_state"},
+	FeatureDailyStats:       {"token_daily_stats"},
+	FeatureWatchlists:       {"watchlists", "watchlist_addresses"},
+	FeatureAlerts:           {"alert_rules", "tenants"},
+	FeatureScreening:        {"deny_list"},
+	FeatureHolderSnapshots:  {"holder_snapshots"},
+	FeatureEntities:         {"entities", "entity_addresses"},
+	FeatureEthTransfers:     {"eth_transfers", "eth_trace_state"},
+	FeatureRawLogs:          {"raw_logs"},
+	FeatureMethodSignatures: {"method_signatures"},
+	FeatureHolderCounts:     {"holder_counts"},
+	FeatureTenants:          {"tenants", "tenant_api_keys", "tenant_tokens"},
+	FeatureNewHolders:       {"holder_first_seen"},
+	FeatureAdvancedStats:    {"token_advanced_stats"},
+	FeatureMetadataHistory:  {"token_metadata_history"},
+	FeatureTransferFlags:    {"transfer_flags"},
+	FeatureFailedRanges:     {"failed_ranges"},
+	FeatureWalletStats:      {"wallet_stats"},
+	FeatureAddressNotes:     {"address_notes", "tenants"},
+	FeatureContractEvents:   {"contracts", "contract_events", "scoped_event_state"},
+}
+
+// featureColumns lists the columns, as table.column, that later migrations add to the tables of
+// a feature. A schema created from an incomplete set of migration files has the tables but may
+// lack these, and no schema_migrations version tells it apart.
+var featureColumns = map[Feature][]string{
+	FeatureAlerts:   {"alert_rules.tenant_id"},
+	FeatureEntities: {"entities.category"},
+	FeatureAdvancedStats: {
+		"token_advanced_stats.self_transfers", "token_advanced_stats.self_transfer_volume",
+		"token_advanced_stats.fan_out_threshold", "token_advanced_stats.fan_out_transactions",
+		"token_advanced_stats.fan_out_transfers", "token_advanced_stats.fan_out_volume",
+	},
+}
+
+// requiredTables are the tables nothing works without, so a missing one is never degraded
+var requiredTables = []string{"tokens", "transfers", "indexer_state"}
+
+// requiredColumns are the columns later migrations add to the required tables. Every query of
+// those tables selects them, so a missing one is never degraded either.
+var requiredColumns = []string{
+	"transfers.initiator", "transfers.method_selector",
+	"tokens.active", "tokens.deactivated_at", "tokens.metadata_source",
+	"indexer_state.backfill_checkpoint",
+}
+
+// ErrSchemaIncompatible is returned by SchemaStatus.Compatible when the database is behind this build
+var ErrSchemaIncompatible = errors.New("database schema is behind this build")
+
+// SchemaStatus compares the database schema with the one this build expects
+type SchemaStatus struct {
+	Version int64 // Applied migration version, 0 when unknown
+	Known   bool  // False when migrations were not recorded in schema_migrations
+	Dirty   bool  // The last migration failed halfway
+
+	// Disabled maps each feature whose tables or columns are missing to those tables and columns
+	Disabled map[Feature][]string
+}
+
+// CheckSchema reads the applied migration version and which features' tables and columns exist.
+// Tables and columns the whole build depends on are an error, since no degraded mode can serve
+// without them.
+func CheckSchema(ctx context.Context, store *Store) (*SchemaStatus, error) {
+	status := &SchemaStatus{Known: true, Disabled: make(map[Feature][]string)}
+	version, dirty, err := store.SchemaVersion(ctx)
+	switch {
+	case errors.Is(err, ErrNoSchemaVersion):
+		status.Known = false
+	case err != nil:
+		return nil, err
+	default:
+		status.Version, status.Dirty = version, dirty
+	}
+
+	tables := append([]string(nil), requiredTables...)
+	for _, featureTables := range featureTables {
+		tables = append(tables, featureTables...)
+	}
+	missing, err := store.MissingTables(ctx, tables)
+	if err != nil {
+		return nil, err
+	}
+	absent := make(map[string]bool, len(missing))
+	for _, table := range missing {
+		absent[table] = true
+	}
+
+	var required []string
+	for _, table := range requiredTables {
+		if absent[table] {
+			required = append(required, table)
+		}
+	}
+	if len(required) > 0 {
+		return nil, fmt.Errorf("%w: required tables %s do not exist; run `chain-indexer migrate up`",
+			ErrSchemaIncompatible, strings.Join(required, ", "))
+	}
+
+	columns := append([]string(nil), requiredColumns...)
+	for _, featureColumns := range featureColumns {
+		columns = append(columns, featureColumns...)
+	}
+	missing, err = store.MissingColumns(ctx, columns)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range missing {
+		absent[column] = true
+	}
+
+	for _, column := range requiredColumns {
+		if absent[column] {
+			required = append(required, column)
+		}
+	}
+	if len(required) > 0 {
+		return nil, fmt.Errorf("%w: required columns %s do not exist; run `chain-indexer migrate up`",
+			ErrSchemaIncompatible, strings.Join(required, ", "))
+	}
+
+	for feature, tables := range featureTables {
+		for _, table := range tables {
+			if absent[table] {
+				status.Disabled[feature] = append(status.Disabled[feature], table)
+			}
+		}
+	}
+	for feature, columns := range featureColumns {
+		for _, column := range columns {
+			table, _, _ := strings.Cut(column, ".")
+			// A missing table already disables the feature and has no columns to list
+			if absent[column] && !absent[table] {
+				status.Disabled[feature] = append(status.Disabled[feature], column)
+			}
+		}
+	}
+	return status, nil
+}
+
+// Enabled reports whether a feature's tables and columns exist
+func (s *SchemaStatus) Enabled(feature Feature) bool {
+	_, disabled := s.Disabled[feature]
+	return !disabled
+}
+
+// DisabledFeatures returns the disabled features in name order
+func (s *SchemaStatus) DisabledFeatures() []string {
+	names := make([]string, 0, len(s.Disabled))
+	for feature := range s.Disabled {
+		names = append(names, string(feature))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Compatible returns an error wrapping ErrSchemaIncompatible when this build cannot run on the
+// schema in full: the last migration failed halfway, the version is below SchemaVersion, or
+// tables or columns are missing. A newer schema is compatible, since migrations only add to the schema
+// before the code using them is rolled out.
+func (s *SchemaStatus) Compatible() error {
+	switch {
+	case s.Dirty:
+		return fmt.Errorf("%w: migration %d failed halfway (dirty)", ErrSchemaIncompatible, s.Version)
+	case s.Known && s.Version < SchemaVersion:
+		return fmt.Errorf("%w: version %d, this build expects %d", ErrSchemaIncompatible, s.Version, SchemaVersion)
+	case len(s.Disabled) > 0:
+		return fmt.Errorf("%w: tables or columns of %s do not exist", ErrSchemaIncompatible, strings.Join(s.DisabledFeatures(), ", "))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bimakw/chain-indexer/internal/testutil/integration"
+)
+
+func TestPostgresDB_Integration_MissingColumns(t *testing.T) {
+	db := &PostgresDB{db: integration.Postgres(t)}
+	ctx := context.Background()
+
+	columns := append([]string{"indexer_state.no_such_column", "no_such_table.id"}, requiredColumns...)
+	for _, featureColumns := range featureColumns {
+		columns = append(columns, featureColumns...)
+	}
+	missing, err := db.MissingColumns(ctx, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 2 || missing[0] != "indexer_state.no_such_column" || missing[1] != "no_such_table.id" {
+		t.Errorf("expected only the unknown columns missing, got %v", missing)
+	}
+
+	if _, err := db.db.ExecContext(ctx, `ALTER TABLE indexer_state DROP COLUMN backfill_checkpoint`); err != nil {
+		t.Fatal(err)
+	}
+	if missing, err := db.MissingColumns(ctx, requiredColumns); err != nil || len(missing) != 1 || missing[0] != "indexer_state.backfill_checkpoint" {
+		t.Errorf("expected the dropped column missing, got %v (%v)", missing, err)
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure ScopedEventStateRepo implements ScopedEventStateRepository
+var _ repositories.ScopedEventStateRepository = (*ScopedEventStateRepo)(nil)
+
+// ScopedEventStateRepo implements ScopedEventStateRepository using PostgreSQL
+type ScopedEventStateRepo struct {
+	db *sqlx.DB
+}
+
+// NewScopedEventStateRepo creates a new scoped event checkpoint repository
+func NewScopedEventStateRepo(db *sqlx.DB) *ScopedEventStateRepo {
+	return &ScopedEventStateRepo{db: db}
+}
+
+// GetLastBlock returns the last block whose scoped events were indexed, or 0 if none were
+func (r *ScopedEventStateRepo) GetLastBlock(ctx context.Context) (int64, error) {
+	ctx = withQueryName(ctx, "scoped_event_state.GetLastBlock")
+
+	var blockNumber int64
+	query := `SELECT COALESCE(MAX(last_indexed_block), 0) FROM scoped_event_state`
+	if err := r.db.GetContext(ctx, &blockNumber, query); err != nil {
+		return 0, fmt.Errorf("failed to get scoped event checkpoint: %w", err)
+	}
+
+	return blockNumber, nil
+}
+
+// UpdateLastBlock records the last block whose scoped events were indexed
+func (r *ScopedEventStateRepo) UpdateLastBlock(ctx context.Context, blockNumber int64) error {
+	ctx = withQueryName(ctx, "scoped_event_state.UpdateLastBlock")
+
+	query := `
+		INSERT INTO scoped_event_state (id, last_indexed_block)
+		VALUES (TRUE, $1)
+		ON CONFLICT (id) DO UPDATE SET
+			last_indexed_block = EXCLUDED.last_indexed_block,
+			updated_at = NOW()
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, blockNumber); err != nil {
+		return fmt.Errorf("failed to update scoped event checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+// sqliteSchema is the SQLite equivalent of migrations/ up to SchemaVersion. NUMERIC(78, 0)
+// columns are stored as decimal TEXT and summed with the big_* functions registered below.
+//
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+// sqliteNow is the SQLite expression for NOW(), in the UTC format timestamps are stored in
+const sqliteNow = `strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')`
+
+// sqliteDriver registers the uint256 arithmetic the repositories need on every connection.
+// SQLite's own SUM overflows past int64 and falls back to floating point.
+var sqliteDriver = &sqlite3.SQLiteDriver{
+	ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+		funcs := map[string]interface{}{
+			"big_add": bigAdd,
+			"big_neg": bigNeg,
+			"big_sub": bigSub,
+			"big_abs": bigAbs,
+			"big_cmp": bigCmp,
+			"big_key": bigKey,
+		}
+		for name, fn := range funcs {
+			if err := conn.RegisterFunc(name, fn, true); err != nil {
+				return fmt.Errorf("failed to register %s: %w", name, err)
+			}
+		}
+		return conn.RegisterAggregator("big_sum", newBigSum, true)
+	},
+}
+
+// SQLiteDB wraps a single-file SQLite database holding the whole schema
+type SQLiteDB struct {
+	db     *sqlx.DB
+	logger *zap.Logger
+}
+
+// NewSQLiteDB opens (creating if needed) the SQLite database at cfg.Path and loads the schema
+// into a new file. It needs a cgo build; ":memory:" gives a throwaway database.
+func NewSQLiteDB(cfg config.DatabaseConfig, logger *zap.Logger) (*SQLiteDB, error) {
+	dsn := "file:" + cfg.Path + "?_foreign_keys=on&_busy_timeout=5000"
+	if cfg.Path != ":memory:" {
+		dsn += "&_journal_mode=WAL"
+	}
+
+	sqlDB := sql.OpenDB(&instrumentedConnector{
+		base: sqliteConnector{dsn: dsn},
+		in: &instrumentation{
+			logger:        logger,
+			slowThreshold: cfg.SlowQueryThreshold,
+		},
+	})
+	db := sqlx.NewDb(sqlDB, "sqlite3")
+
+	// SQLite allows one writer at a time, and each connection to ":memory:" is its own database
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	s := &SQLiteDB{db: db, logger: logger}
+	if err := s.ensureSchema(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	logger.Info("Opened SQLite database", zap.String("path", cfg.Path))
+
+	return s, nil
+}
+
+// sqliteConnector opens connections to one DSN through sqliteDriver
+type sqliteConnector struct {
+	dsn string
+}
+
+func (c sqliteConnector) Connect(context.Context) (driver.Conn, error) {
+	return sqliteDriver.Open(c.dsn)
+}
+
+func (c sqliteConnector) Driver() driver.Driver {
+	return sqliteDriver
+}
+
+// ensureSchema loads the schema into an empty database. A file written by an older build is
+// rejected rather than migrated: the SQLite backend holds local and test data only.
+func (s *SQLiteDB) ensureSchema(ctx context.Context) error {
+	version, _, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case version == 0:
+		if _, err := s.db.ExecContext(ctx, sqliteSchema); err != nil {
+			return fmt.Errorf("failed to create sqlite schema: %w", err)
+		}
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion)); err != nil {
+			return fmt.Errorf("failed to record sqlite schema version: %w", err)
+		}
+		return nil
+	case version != SchemaVersion:
+		return fmt.Errorf("sqlite database has schema version %d, this build expects %d; delete the file to recreate it", version, SchemaVersion)
+	default:
+		return nil
+	}
+}
+
+// Close closes the database
+func (s *SQLiteDB) Close() error {
+	return s.db.Close()
+}
+
+// DB returns the underlying sqlx.DB
+func (s *SQLiteDB) DB() *sqlx.DB {
+	return s.db
+}
+
+// HealthCheck performs a health check on the database
+func (s *SQLiteDB) HealthCheck(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// SchemaVersion returns the schema version recorded in the file's user_version, 0 for a new file.
+// SQLite schemas are never left half-applied, so dirty is always false.
+func (s *SQLiteDB) SchemaVersion(ctx context.Context) (int64, bool, error) {
+	ctx = withQueryName(ctx, "sqlite.SchemaVersion")
+
+	var version int64
+	if err := s.db.GetContext(ctx, &version, `PRAGMA user_version`); err != nil {
+		return 0, false, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, false, nil
+}
+
+// MissingTables returns the given tables that do not exist in the file
+func (s *SQLiteDB) MissingTables(ctx context.Context, tables []string) ([]string, error) {
+	ctx = withQueryName(ctx, "sqlite.MissingTables")
+
+	var missing []string
+	query := `
+		SELECT value FROM json_each(?1)
+		WHERE value NOT IN (SELECT name FROM sqlite_master WHERE type = 'table')
+		ORDER BY value
+	`
+	if err := s.db.SelectContext(ctx, &missing, query, sqliteList(tables)); err != nil {
+		return nil, fmt.Errorf("failed to check tables: %w", err)
+	}
+	return missing, nil
+}
+
+// MissingColumns returns the given table.column names that do not exist in the file
+func (s *SQLiteDB) MissingColumns(ctx context.Context, columns []string) ([]string, error) {
+	ctx = withQueryName(ctx, "sqlite.MissingColumns")
+
+	var missing []string
+	query := `
+		SELECT value FROM json_each(?1)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM pragma_table_info(substr(value, 1, instr(value, '.') - 1))
+			WHERE name = substr(value, instr(value, '.') + 1)
+		)
+		ORDER BY value
+	`
+	if err := s.db.SelectContext(ctx, &missing, query, sqliteList(columns)); err != nil {
+		return nil, fmt.Errorf("failed to check columns: %w", err)
+	}
+	return missing, nil
+}
+
+// sqliteTime converts a time argument to UTC, since the driver stores times in their own zone
+// and timestamps are compared as text
+func sqliteTime(t time.Time) time.Time {
+	return t.UTC()
+}
+
+// sqliteNullTime is sqliteTime for optional arguments
+func sqliteNullTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// sqliteList encodes values as a JSON array for `IN (SELECT value FROM json_each(?))`,
+// SQLite's stand-in for = ANY($1)
+func sqliteList(values []string) string {
+	if values == nil {
+		values = []string{}
+	}
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
+
+// sqliteTimestampFormats are the layouts timestamps are written in: by the driver for time
+// arguments, and by sqliteNow and date() in SQL
+var sqliteTimestampFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// sqliteTimestamp parses an aggregated timestamp column, which SQLite returns as plain text
+func sqliteTimestamp(s *string) *time.Time {
+	if s == nil || *s == "" {
+		return nil
+	}
+	for _, format := range sqliteTimestampFormats {
+		if t, err := time.Parse(format, *s); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
+
+// sqliteTimeValue is sqliteTimestamp for columns that are never NULL, zero if unparseable
+func sqliteTimeValue(s string) time.Time {
+	if t := sqliteTimestamp(&s); t != nil {
+		return *t
+	}
+	return time.Time{}
+}
+
+// bigValue parses an argument of the big_* functions: decimal TEXT, an INTEGER, or NULL (as 0)
+func bigValue(v interface{}) *big.Int {
+	switch v := v.(type) {
+	case int64:
+		return big.NewInt(v)
+	case string:
+		n, _ := new(big.Int).SetString(v, 10)
+		if n != nil {
+			return n
+		}
+	case []byte:
+		n, _ := new(big.Int).SetString(string(v), 10)
+		if n != nil {
+			return n
+		}
+	case float64:
+		n, _ := big.NewFloat(v).Int(nil)
+		return n
+	}
+	return new(big.Int)
+}
+
+func bigNeg(v interface{}) string {
+	return new(big.Int).Neg(bigValue(v)).String()
+}
+
+func bigAdd(a, b interface{}) string {
+	return new(big.Int).Add(bigValue(a), bigValue(b)).String()
+}
+
+func bigSub(a, b interface{}) string {
+	return new(big.Int).Sub(bigValue(a), bigValue(b)).String()
+}
+
+func bigAbs(v interface{}) string {
+	return new(big.Int).Abs(bigValue(v)).String()
+}
+
+func bigCmp(a, b interface{}) int64 {
+	return int64(bigValue(a).Cmp(bigValue(b)))
+}
+
+// bigKeyDigits covers uint256 (78 digits) with room for sums of many of them
+const bigKeyDigits = 90
+
+// bigKey maps a value to text that sorts in numeric order, for ORDER BY on big_* results:
+// non-negative values get a "1" prefix and are zero-padded, negative ones a "0" prefix
+// followed by the padded complement.
+func bigKey(v interface{}) string {
+	n := bigValue(v)
+	prefix := "1"
+	if n.Sign() < 0 {
+		prefix = "0"
+		n.Add(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(bigKeyDigits), nil))
+	}
+	digits := n.String()
+	return prefix + strings.Repeat("0", max(bigKeyDigits-len(digits), 0)) + digits
+}
+
+// bigSum is the big_sum aggregate: an exact SUM over decimal TEXT, returned as TEXT
+type bigSum struct {
+	total *big.Int
+}
+
+func newBigSum() *bigSum {
+	return &bigSum{total: new(big.Int)}
+}
+
+func (s *bigSum) Step(v interface{}) {
+	if v != nil {
+		s.total.Add(s.total, bigValue(v))
+	}
+}
+
+func (s *bigSum) Done() string {
+	return s.total.String()
+}
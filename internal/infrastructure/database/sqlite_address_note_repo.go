@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteAddressNoteRepo implements AddressNoteRepository
+var _ repositories.AddressNoteRepository = (*SQLiteAddressNoteRepo)(nil)
+
+// SQLiteAddressNoteRepo implements AddressNoteRepository using SQLite
+type SQLiteAddressNoteRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteAddressNoteRepo creates a new SQLite address note repository
+func NewSQLiteAddressNoteRepo(db *sqlx.DB) *SQLiteAddressNoteRepo {
+	return &SQLiteAddressNoteRepo{db: db}
+}
+
+// Put creates or replaces a tenant's note on an address, keeping the creation time of a replaced note
+func (r *SQLiteAddressNoteRepo) Put(ctx context.Context, note *entities.AddressNote) error {
+	ctx = withQueryName(ctx, "address_notes.Put")
+
+	query := `
+		INSERT INTO address_notes (tenant_id, address, note)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (tenant_id, address) DO UPDATE SET
+			note = excluded.note,
+			updated_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')
+		RETURNING created_at, updated_at
+	`
+	row := r.db.QueryRowxContext(ctx, query, note.TenantID, note.Address, note.Note)
+	if err := row.Scan(&note.CreatedAt, &note.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to put address note: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a tenant's note on an address
+func (r *SQLiteAddressNoteRepo) Get(ctx context.Context, tenantID int64, address entities.Address) (*entities.AddressNote, error) {
+	ctx = withQueryName(ctx, "address_notes.Get")
+
+	var note entities.AddressNote
+	query := `
+		SELECT tenant_id, address, note, created_at, updated_at
+		FROM address_notes
+		WHERE tenant_id = ?1 AND address = ?2
+	`
+	if err := r.db.GetContext(ctx, &note, query, tenantID, address); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get address note: %w", err)
+	}
+
+	return &note, nil
+}
+
+// Delete removes a tenant's note on an address
+func (r *SQLiteAddressNoteRepo) Delete(ctx context.Context, tenantID int64, address entities.Address) (bool, error) {
+	ctx = withQueryName(ctx, "address_notes.Delete")
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM address_notes WHERE tenant_id = ?1 AND address = ?2`, tenantID, address)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete address note: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// GetByAddresses returns a tenant's notes on any of the given addresses
+func (r *SQLiteAddressNoteRepo) GetByAddresses(ctx context.Context, tenantID int64, addresses []string) ([]entities.AddressNote, error) {
+	ctx = withQueryName(ctx, "address_notes.GetByAddresses")
+
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	var notes []entities.AddressNote
+	query := `
+		SELECT tenant_id, address, note, created_at, updated_at
+		FROM address_notes
+		WHERE tenant_id = ?1 AND address IN (SELECT value FROM json_each(?2))
+		ORDER BY address
+	`
+	if err := r.db.SelectContext(ctx, &notes, query, tenantID, sqliteList(addresses)); err != nil {
+		return nil, fmt.Errorf("failed to get address notes: %w", err)
+	}
+
+	return notes, nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteAdvancedStatsRepo implements AdvancedStatsRepository
+var _ repositories.AdvancedStatsRepository = (*SQLiteAdvancedStatsRepo)(nil)
+
+// SQLiteAdvancedStatsRepo implements AdvancedStatsRepository using SQLite
+type SQLiteAdvancedStatsRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteAdvancedStatsRepo creates a new SQLite advanced stats repository
+func NewSQLiteAdvancedStatsRepo(db *sqlx.DB) *SQLiteAdvancedStatsRepo {
+	return &SQLiteAdvancedStatsRepo{db: db}
+}
+
+// Save records a token's advanced stats, replacing the previous ones
+func (r *SQLiteAdvancedStatsRepo) Save(ctx context.Context, stats repositories.AdvancedStats) error {
+	ctx = withQueryName(ctx, "token_advanced_stats.Save")
+
+	query := `
+		INSERT INTO token_advanced_stats (` + advancedStatsColumns + `)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13, ?14, ?15, ?16)
+		ON CONFLICT (token_address) DO UPDATE SET
+			window_from = excluded.window_from,
+			window_to = excluded.window_to,
+			volume = excluded.volume,
+			average_supply = excluded.average_supply,
+			circulating_supply = excluded.circulating_supply,
+			inactive_since = excluded.inactive_since,
+			dormant_supply = excluded.dormant_supply,
+			dormant_holders = excluded.dormant_holders,
+			self_transfers = excluded.self_transfers,
+			self_transfer_volume = excluded.self_transfer_volume,
+			fan_out_threshold = excluded.fan_out_threshold,
+			fan_out_transactions = excluded.fan_out_transactions,
+			fan_out_transfers = excluded.fan_out_transfers,
+			fan_out_volume = excluded.fan_out_volume,
+			computed_at = excluded.computed_at
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		stats.TokenAddress, sqliteTime(stats.WindowFrom), sqliteTime(stats.WindowTo),
+		stats.Volume, stats.AverageSupply, stats.CirculatingSupply,
+		sqliteTime(stats.InactiveSince), stats.DormantSupply, stats.DormantHolders, stats.SelfTransfers, stats.SelfTransferVolume,
+		stats.FanOutThreshold, stats.FanOutTransactions, stats.FanOutTransfers, stats.FanOutVolume, sqliteTime(stats.ComputedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save advanced stats: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns a token's last computed advanced stats
+func (r *SQLiteAdvancedStatsRepo) Get(ctx context.Context, tokenAddress string) (*repositories.AdvancedStats, error) {
+	ctx = withQueryName(ctx, "token_advanced_stats.Get")
+
+	var stats repositories.AdvancedStats
+	query := `SELECT ` + advancedStatsColumns + ` FROM token_advanced_stats WHERE token_address = ?1`
+	if err := r.db.GetContext(ctx, &stats, query, tokenAddress); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get advanced stats: %w", err)
+	}
+
+	return &stats, nil
+}
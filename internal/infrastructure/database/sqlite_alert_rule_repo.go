@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteAlertRuleRepo implements AlertRuleRepository
+var _ repositories.AlertRuleRepository = (*SQLiteAlertRuleRepo)(nil)
+
+// SQLiteAlertRuleRepo implements AlertRuleRepository using SQLite
+type SQLiteAlertRuleRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteAlertRuleRepo creates a new SQLite alert rule repository
+func NewSQLiteAlertRuleRepo(db *sqlx.DB) *SQLiteAlertRuleRepo {
+	return &SQLiteAlertRuleRepo{db: db}
+}
+
+// sqliteAlertRuleRow is alertRuleRow with counterparties stored as a JSON array
+type sqliteAlertRuleRow struct {
+	ID             int64     `db:"id"`
+	Name           string    `db:"name"`
+	TokenAddress   *string   `db:"token_address"`
+	Address        *string   `db:"address"`
+	Direction      string    `db:"direction"`
+	MinValue       *string   `db:"min_value"`
+	Counterparties string    `db:"counterparties"`
+	Channel        string    `db:"channel"`
+	Target         string    `db:"target"`
+	TenantID       *int64    `db:"tenant_id"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (row *sqliteAlertRuleRow) toEntity() (entities.AlertRule, error) {
+	var counterparties []string
+	if err := json.Unmarshal([]byte(row.Counterparties), &counterparties); err != nil {
+		return entities.AlertRule{}, fmt.Errorf("failed to decode counterparties of alert rule %d: %w", row.ID, err)
+	}
+
+	rule := entities.AlertRule{
+		ID:             row.ID,
+		Name:           row.Name,
+		TokenAddress:   row.TokenAddress,
+		Address:        row.Address,
+		Direction:      row.Direction,
+		Counterparties: counterparties,
+		Channel:        row.Channel,
+		Target:         row.Target,
+		TenantID:       row.TenantID,
+		CreatedAt:      row.CreatedAt,
+	}
+	if row.MinValue != nil {
+		rule.MinValue, _ = new(big.Int).SetString(*row.MinValue, 10)
+	}
+	return rule, nil
+}
+
+// Create inserts a rule
+func (r *SQLiteAlertRuleRepo) Create(ctx context.Context, rule *entities.AlertRule) error {
+	ctx = withQueryName(ctx, "alert_rules.Create")
+
+	var minValue *string
+	if rule.MinValue != nil {
+		v := rule.MinValue.String()
+		minValue = &v
+	}
+
+	query := `
+		INSERT INTO alert_rules (name, token_address, address, direction, min_value, counterparties, channel, target, tenant_id)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9)
+		RETURNING id, created_at
+	`
+	row := r.db.QueryRowxContext(ctx, query,
+		rule.Name,
+		rule.TokenAddress,
+		rule.Address,
+		rule.Direction,
+		minValue,
+		sqliteList(rule.Counterparties),
+		rule.Channel,
+		rule.Target,
+		rule.TenantID,
+	)
+	if err := row.Scan(&rule.ID, &rule.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a rule by ID
+func (r *SQLiteAlertRuleRepo) GetByID(ctx context.Context, id int64) (*entities.AlertRule, error) {
+	ctx = withQueryName(ctx, "alert_rules.GetByID")
+
+	var row sqliteAlertRuleRow
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = ?1`
+
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+
+	rule, err := row.toEntity()
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// List retrieves all rules, oldest first
+func (r *SQLiteAlertRuleRepo) List(ctx context.Context) ([]entities.AlertRule, error) {
+	ctx = withQueryName(ctx, "alert_rules.List")
+
+	var rows []sqliteAlertRuleRow
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules ORDER BY id`
+
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	rules := make([]entities.AlertRule, len(rows))
+	for i := range rows {
+		rule, err := rows[i].toEntity()
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = rule
+	}
+
+	return rules, nil
+}
+
+// Delete removes a rule
+func (r *SQLiteAlertRuleRepo) Delete(ctx context.Context, id int64) (bool, error) {
+	ctx = withQueryName(ctx, "alert_rules.Delete")
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = ?1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return n > 0, nil
+}
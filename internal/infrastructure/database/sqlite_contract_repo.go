@@ -0,0 +1,314 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteContractRepo implements ContractRepository
+var _ repositories.ContractRepository = (*SQLiteContractRepo)(nil)
+
+// SQLiteContractRepo implements ContractRepository using SQLite
+type SQLiteContractRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteContractRepo creates a new SQLite contract repository
+func NewSQLiteContractRepo(db *sqlx.DB) *SQLiteContractRepo {
+	return &SQLiteContractRepo{db: db}
+}
+
+// sqliteContractRow is contractRow with events stored as a JSON array
+type sqliteContractRow struct {
+	Address   string    `db:"address"`
+	Name      string    `db:"name"`
+	ABI       string    `db:"abi"`
+	Events    string    `db:"events"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (row *sqliteContractRow) toEntity() (entities.Contract, error) {
+	var events []string
+	if err := json.Unmarshal([]byte(row.Events), &events); err != nil {
+		return entities.Contract{}, fmt.Errorf("failed to decode events of contract %s: %w", row.Address, err)
+	}
+
+	return entities.Contract{
+		Address:   row.Address,
+		Name:      row.Name,
+		ABI:       row.ABI,
+		Events:    events,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
+
+// sqliteContractEventRow is contractEventRow with topics stored as a JSON array
+type sqliteContractEventRow struct {
+	ID              int64     `db:"id"`
+	ContractAddress string    `db:"contract_address"`
+	EventName       string    `db:"event_name"`
+	TxHash          string    `db:"tx_hash"`
+	LogIndex        int       `db:"log_index"`
+	BlockNumber     int64     `db:"block_number"`
+	BlockTimestamp  time.Time `db:"block_timestamp"`
+	Topics          string    `db:"topics"`
+	Args            string    `db:"args"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+func (row *sqliteContractEventRow) toEntity() (entities.ContractEvent, error) {
+	var topics []string
+	if err := json.Unmarshal([]byte(row.Topics), &topics); err != nil {
+		return entities.ContractEvent{}, fmt.Errorf("failed to decode topics of contract event %d: %w", row.ID, err)
+	}
+
+	return entities.ContractEvent{
+		ID:              row.ID,
+		ContractAddress: row.ContractAddress,
+		EventName:       row.EventName,
+		TxHash:          row.TxHash,
+		LogIndex:        row.LogIndex,
+		BlockNumber:     row.BlockNumber,
+		BlockTimestamp:  row.BlockTimestamp,
+		Topics:          topics,
+		Args:            json.RawMessage(row.Args),
+		CreatedAt:       row.CreatedAt,
+	}, nil
+}
+
+// StoreEvents inserts decoded contract events in a single transaction, skipping duplicates
+func (r *SQLiteContractRepo) StoreEvents(ctx context.Context, events []any) error {
+	ctx = withQueryName(ctx, "contract_events.StoreEvents")
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO contract_events (contract_address, event_name, tx_hash, log_index, block_number,
+									 block_timestamp, topics, args)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+		ON CONFLICT (tx_hash, log_index, block_timestamp) DO NOTHING
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		e, ok := event.(*entities.ContractEvent)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T", event)
+		}
+
+		_, err := stmt.ExecContext(ctx,
+			e.ContractAddress,
+			e.EventName,
+			e.TxHash,
+			e.LogIndex,
+			e.BlockNumber,
+			sqliteTime(e.BlockTimestamp),
+			sqliteList(e.Topics),
+			string(e.Args),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert contract event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertContract registers a contract, replacing the ABI and events of an earlier registration
+func (r *SQLiteContractRepo) UpsertContract(ctx context.Context, contract *entities.Contract) error {
+	ctx = withQueryName(ctx, "contracts.UpsertContract")
+
+	query := `
+		INSERT INTO contracts (address, name, abi, events)
+		VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (address) DO UPDATE SET
+			name = excluded.name,
+			abi = excluded.abi,
+			events = excluded.events,
+			updated_at = ?5
+		RETURNING created_at, updated_at
+	`
+	row := r.db.QueryRowxContext(ctx, query, contract.Address, contract.Name, contract.ABI, sqliteList(contract.Events), sqliteTime(time.Now()))
+	if err := row.Scan(&contract.CreatedAt, &contract.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to upsert contract: %w", err)
+	}
+
+	return nil
+}
+
+// GetContract retrieves a contract by address, or nil if it is not registered
+func (r *SQLiteContractRepo) GetContract(ctx context.Context, address string) (*entities.Contract, error) {
+	ctx = withQueryName(ctx, "contracts.GetContract")
+
+	var row sqliteContractRow
+	query := `SELECT address, name, abi, events, created_at, updated_at FROM contracts WHERE address = ?1`
+
+	if err := r.db.GetContext(ctx, &row, query, address); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	contract, err := row.toEntity()
+	if err != nil {
+		return nil, err
+	}
+	return &contract, nil
+}
+
+// ListContracts retrieves every registered contract, by address
+func (r *SQLiteContractRepo) ListContracts(ctx context.Context) ([]entities.Contract, error) {
+	ctx = withQueryName(ctx, "contracts.ListContracts")
+
+	var rows []sqliteContractRow
+	query := `SELECT address, name, abi, events, created_at, updated_at FROM contracts ORDER BY address`
+
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list contracts: %w", err)
+	}
+
+	contracts := make([]entities.Contract, len(rows))
+	for i := range rows {
+		contract, err := rows[i].toEntity()
+		if err != nil {
+			return nil, err
+		}
+		contracts[i] = contract
+	}
+
+	return contracts, nil
+}
+
+// GetEvents retrieves contract events matching the filter, newest first
+func (r *SQLiteContractRepo) GetEvents(ctx context.Context, filter entities.ContractEventFilter) ([]entities.ContractEvent, error) {
+	ctx = withQueryName(ctx, "contract_events.GetEvents")
+
+	where, args, err := sqliteContractEventConditions(filter)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM contract_events%s
+		ORDER BY block_timestamp DESC, block_number DESC, log_index DESC
+		LIMIT ?%d OFFSET ?%d
+	`, contractEventColumns, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	var rows []sqliteContractEventRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get contract events: %w", err)
+	}
+
+	events := make([]entities.ContractEvent, len(rows))
+	for i := range rows {
+		event, err := rows[i].toEntity()
+		if err != nil {
+			return nil, err
+		}
+		events[i] = event
+	}
+
+	return events, nil
+}
+
+// GetEventCount returns the count of contract events matching the filter
+func (r *SQLiteContractRepo) GetEventCount(ctx context.Context, filter entities.ContractEventFilter) (int64, error) {
+	ctx = withQueryName(ctx, "contract_events.GetEventCount")
+
+	where, args, err := sqliteContractEventConditions(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM contract_events"+where, args...); err != nil {
+		return 0, fmt.Errorf("failed to get contract event count: %w", err)
+	}
+
+	return count, nil
+}
+
+// sqliteContractArgRangeSQL maps the range operators of argument conditions to comparisons of
+// big_cmp with 0; nothing else is ever written into a query
+var sqliteContractArgRangeSQL = map[entities.ContractArgOperator]string{
+	entities.ContractArgGt:  "> 0",
+	entities.ContractArgGte: ">= 0",
+	entities.ContractArgLt:  "< 0",
+	entities.ContractArgLte: "<= 0",
+}
+
+// sqliteContractEventConditions builds the WHERE clause for a contract event filter. Argument
+// values are compared as JSON text, which -> returns with numbers as written, so integers beyond
+// 64 bits match exactly; ranges compare them with big_cmp for the same reason.
+func sqliteContractEventConditions(filter entities.ContractEventFilter) (string, []interface{}, error) {
+	conditions := []string{"contract_address = ?1"}
+	args := []interface{}{filter.ContractAddress}
+
+	if filter.EventName != nil {
+		args = append(args, *filter.EventName)
+		conditions = append(conditions, fmt.Sprintf("event_name = ?%d", len(args)))
+	}
+
+	names := make([]string, 0, len(filter.Args))
+	for name := range filter.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, sqliteJSONPath(name), string(filter.Args[name]))
+		conditions = append(conditions, fmt.Sprintf("args -> ?%d = ?%d", len(args)-1, len(args)))
+	}
+
+	for _, c := range filter.Conditions {
+		args = append(args, sqliteJSONPath(c.Arg), string(c.Value))
+		switch {
+		case c.Operator == entities.ContractArgEq:
+			conditions = append(conditions, fmt.Sprintf("args -> ?%d = ?%d", len(args)-1, len(args)))
+		case c.Operator == entities.ContractArgNe:
+			conditions = append(conditions, fmt.Sprintf("args -> ?%d != ?%d", len(args)-1, len(args)))
+		case sqliteContractArgRangeSQL[c.Operator] != "":
+			conditions = append(conditions, fmt.Sprintf("big_cmp(args -> ?%d, ?%d) %s", len(args)-1, len(args), sqliteContractArgRangeSQL[c.Operator]))
+		default:
+			return "", nil, fmt.Errorf("unsupported argument operator %q", c.Operator)
+		}
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// sqliteJSONPath returns the JSON path of a top-level key
+func sqliteJSONPath(key string) string {
+	return `$."` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+}
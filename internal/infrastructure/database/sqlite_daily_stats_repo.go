@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteDailyStatsRepo implements DailyStatsRepository
+var _ repositories.DailyStatsRepository = (*SQLiteDailyStatsRepo)(nil)
+
+// SQLiteDailyStatsRepo implements DailyStatsRepository using SQLite
+type SQLiteDailyStatsRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteDailyStatsRepo creates a new SQLite daily stats repository
+func NewSQLiteDailyStatsRepo(db *sqlx.DB) *SQLiteDailyStatsRepo {
+	return &SQLiteDailyStatsRepo{db: db}
+}
+
+// RefreshDays recomputes a token's rollup rows for every UTC day in [from, to] from raw transfers.
+// Rows are replaced in a single transaction, so refreshing a day is idempotent.
+func (r *SQLiteDailyStatsRepo) RefreshDays(ctx context.Context, tokenAddress string, from, to time.Time) error {
+	ctx = withQueryName(ctx, "token_daily_stats.RefreshDays")
+
+	start := from.UTC().Truncate(24 * time.Hour)
+	end := to.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	deleteQuery := `DELETE FROM token_daily_stats WHERE token_address = ?1 AND day >= ?2 AND day < ?3`
+	if _, err := tx.ExecContext(ctx, deleteQuery, tokenAddress, start.Format("2006-01-02"), end.Format("2006-01-02")); err != nil {
+		return fmt.Errorf("failed to delete daily stats: %w", err)
+	}
+
+	// new_senders/new_holders: addresses whose earliest send/receipt of the token falls on the day
+	insertQuery := `
+		WITH day_transfers AS (
+			SELECT date(block_timestamp) as day, block_timestamp, from_address, to_address, value
+			FROM transfers
+			WHERE token_address = ?1
+				AND block_timestamp >= ?2 AND block_timestamp < ?3
+		),
+		first_sent AS (
+			SELECT date(MIN(block_timestamp)) as day
+			FROM transfers
+			WHERE token_address = ?1
+				AND from_address IN (SELECT DISTINCT from_address FROM day_transfers)
+			GROUP BY from_address
+		),
+		first_received AS (
+			SELECT date(MIN(block_timestamp)) as day
+			FROM transfers
+			WHERE token_address = ?1
+				AND to_address IN (SELECT DISTINCT to_address FROM day_transfers)
+			GROUP BY to_address
+		)
+		INSERT INTO token_daily_stats (
+			token_address, day, transfers, volume, unique_senders, unique_receivers,
+			new_senders, new_holders, first_transfer_at, last_transfer_at
+		)
+		SELECT
+			?1,
+			d.day,
+			COUNT(*),
+			big_sum(d.value),
+			COUNT(DISTINCT d.from_address),
+			COUNT(DISTINCT d.to_address),
+			(SELECT COUNT(*) FROM first_sent fs WHERE fs.day = d.day),
+			(SELECT COUNT(*) FROM first_received fr WHERE fr.day = d.day),
+			MIN(d.block_timestamp),
+			MAX(d.block_timestamp)
+		FROM day_transfers d
+		GROUP BY d.day
+	`
+	if _, err := tx.ExecContext(ctx, insertQuery, tokenAddress, start, end); err != nil {
+		return fmt.Errorf("failed to insert daily stats: %w", err)
+	}
+
+	// Addresses are first seen on the days refreshed if their earliest receipt falls on one of them.
+	// Rows for the days are replaced, so a receipt removed by a reindex stops counting.
+	deleteFirstSeenQuery := `DELETE FROM holder_first_seen WHERE token_address = ?1 AND first_seen_at >= ?2 AND first_seen_at < ?3`
+	if _, err := tx.ExecContext(ctx, deleteFirstSeenQuery, tokenAddress, start, end); err != nil {
+		return fmt.Errorf("failed to delete first seen holders: %w", err)
+	}
+	insertFirstSeenQuery := `
+		INSERT INTO holder_first_seen (token_address, address, first_block, first_seen_at)
+		SELECT ?1, to_address, MIN(block_number), MIN(block_timestamp)
+		FROM transfers
+		WHERE token_address = ?1
+			AND to_address IN (
+				SELECT DISTINCT to_address FROM transfers
+				WHERE token_address = ?1 AND block_timestamp >= ?2 AND block_timestamp < ?3
+			)
+		GROUP BY to_address
+		HAVING MIN(block_timestamp) >= ?2
+		ON CONFLICT (token_address, address) DO UPDATE SET
+			first_block = excluded.first_block,
+			first_seen_at = excluded.first_seen_at
+	`
+	if _, err := tx.ExecContext(ctx, insertFirstSeenQuery, tokenAddress, start, end); err != nil {
+		return fmt.Errorf("failed to insert first seen holders: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetRange returns a token's rollup rows for UTC days in [from, to], oldest first
+func (r *SQLiteDailyStatsRepo) GetRange(ctx context.Context, tokenAddress string, from, to time.Time) ([]entities.TokenDailyStats, error) {
+	ctx = withQueryName(ctx, "token_daily_stats.GetRange")
+
+	query := `
+		SELECT
+			token_address, day, transfers, volume,
+			unique_senders, unique_receivers, new_senders, new_holders,
+			first_transfer_at, last_transfer_at
+		FROM token_daily_stats
+		WHERE token_address = ?1
+			AND day >= ?2 AND day <= ?3
+		ORDER BY day
+	`
+
+	var rows []entities.TokenDailyStats
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02")); err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GetNewHolders returns up to limit addresses that first received the token at or after since, newest first
+func (r *SQLiteDailyStatsRepo) GetNewHolders(ctx context.Context, tokenAddress string, since time.Time, limit int) ([]entities.HolderFirstSeen, error) {
+	ctx = withQueryName(ctx, "holder_first_seen.GetNewHolders")
+
+	query := `
+		SELECT token_address, address, first_block, first_seen_at
+		FROM holder_first_seen
+		WHERE token_address = ?1 AND first_seen_at >= ?2
+		ORDER BY first_seen_at DESC, first_block DESC, address
+		LIMIT ?3
+	`
+
+	rows := make([]entities.HolderFirstSeen, 0, limit)
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, sqliteTime(since), limit); err != nil {
+		return nil, fmt.Errorf("failed to get new holders: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteDenyListRepo implements DenyListRepository
+var _ repositories.DenyListRepository = (*SQLiteDenyListRepo)(nil)
+
+// SQLiteDenyListRepo implements DenyListRepository using SQLite
+type SQLiteDenyListRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteDenyListRepo creates a new SQLite deny list repository
+func NewSQLiteDenyListRepo(db *sqlx.DB) *SQLiteDenyListRepo {
+	return &SQLiteDenyListRepo{db: db}
+}
+
+// ReplaceList replaces all entries of a list in one transaction
+func (r *SQLiteDenyListRepo) ReplaceList(ctx context.Context, listName string, entries []entities.DenyListEntry) error {
+	ctx = withQueryName(ctx, "deny_list.ReplaceList")
+
+	addresses := make([]string, len(entries))
+	for i, e := range entries {
+		addresses[i] = e.Address
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	deleteQuery := `DELETE FROM deny_list WHERE list_name = ?1 AND address NOT IN (SELECT value FROM json_each(?2))`
+	if _, err := tx.ExecContext(ctx, deleteQuery, listName, sqliteList(addresses)); err != nil {
+		return fmt.Errorf("failed to delete removed deny list entries: %w", err)
+	}
+
+	// Upsert so addresses that stay on the list keep their original added_at
+	upsertQuery := `
+		INSERT INTO deny_list (list_name, address, label)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (list_name, address) DO UPDATE SET label = excluded.label
+	`
+	stmt, err := tx.PrepareContext(ctx, upsertQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, e := range entries {
+		if _, err := stmt.ExecContext(ctx, listName, e.Address, e.Label); err != nil {
+			return fmt.Errorf("failed to upsert deny list entries: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// FindByAddresses returns every entry for the given addresses
+func (r *SQLiteDenyListRepo) FindByAddresses(ctx context.Context, addresses []string) ([]entities.DenyListEntry, error) {
+	ctx = withQueryName(ctx, "deny_list.FindByAddresses")
+
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	var entries []entities.DenyListEntry
+	query := `
+		SELECT list_name, address, label, added_at
+		FROM deny_list
+		WHERE address IN (SELECT value FROM json_each(?1))
+		ORDER BY address, list_name
+	`
+
+	if err := r.db.SelectContext(ctx, &entries, query, sqliteList(addresses)); err != nil {
+		return nil, fmt.Errorf("failed to find deny list entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteFailedRangeRepo implements FailedRangeRepository
+var _ repositories.FailedRangeRepository = (*SQLiteFailedRangeRepo)(nil)
+
+// SQLiteFailedRangeRepo implements FailedRangeRepository using SQLite
+type SQLiteFailedRangeRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteFailedRangeRepo creates a new SQLite failed range repository
+func NewSQLiteFailedRangeRepo(db *sqlx.DB) *SQLiteFailedRangeRepo {
+	return &SQLiteFailedRangeRepo{db: db}
+}
+
+// Record stores a failed range or updates its unresolved record
+func (r *SQLiteFailedRangeRepo) Record(ctx context.Context, fr entities.FailedRange) error {
+	ctx = withQueryName(ctx, "failed_ranges.Record")
+
+	query := `
+		INSERT INTO failed_ranges (token_address, from_block, to_block, attempts, last_error, skipped)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+		ON CONFLICT (token_address, from_block, to_block) WHERE resolved_at IS NULL DO UPDATE SET
+			attempts = excluded.attempts,
+			last_error = excluded.last_error,
+			skipped = failed_ranges.skipped OR excluded.skipped,
+			last_failed_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')
+	`
+	if _, err := r.db.ExecContext(ctx, query, fr.TokenAddress, fr.FromBlock, fr.ToBlock, fr.Attempts, fr.LastError, fr.Skipped); err != nil {
+		return fmt.Errorf("failed to record failed range: %w", err)
+	}
+	return nil
+}
+
+// List returns the failed ranges, oldest first
+func (r *SQLiteFailedRangeRepo) List(ctx context.Context, unresolved bool) ([]entities.FailedRange, error) {
+	ctx = withQueryName(ctx, "failed_ranges.List")
+
+	var ranges []entities.FailedRange
+	query := `
+		SELECT id, token_address, from_block, to_block, attempts, last_error, skipped,
+			first_failed_at, last_failed_at, resolved_at
+		FROM failed_ranges
+		WHERE NOT ?1 OR resolved_at IS NULL
+		ORDER BY id
+	`
+	if err := r.db.SelectContext(ctx, &ranges, query, unresolved); err != nil {
+		return nil, fmt.Errorf("failed to list failed ranges: %w", err)
+	}
+	return ranges, nil
+}
+
+// Resolve marks a failed range as repaired
+func (r *SQLiteFailedRangeRepo) Resolve(ctx context.Context, id int64) (bool, error) {
+	ctx = withQueryName(ctx, "failed_ranges.Resolve")
+
+	query := `
+		UPDATE failed_ranges SET resolved_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')
+		WHERE id = ?1 AND resolved_at IS NULL
+	`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve failed range: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve failed range: %w", err)
+	}
+	return n > 0, nil
+}
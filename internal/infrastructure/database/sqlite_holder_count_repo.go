@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteHolderCountRepo implements HolderCountRepository
+var _ repositories.HolderCountRepository = (*SQLiteHolderCountRepo)(nil)
+
+// SQLiteHolderCountRepo implements HolderCountRepository using SQLite
+type SQLiteHolderCountRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteHolderCountRepo creates a new SQLite holder count repository
+func NewSQLiteHolderCountRepo(db *sqlx.DB) *SQLiteHolderCountRepo {
+	return &SQLiteHolderCountRepo{db: db}
+}
+
+// Save records a token's holder count, replacing the previous one
+func (r *SQLiteHolderCountRepo) Save(ctx context.Context, tokenAddress string, count int64, countedAt time.Time) error {
+	ctx = withQueryName(ctx, "holder_counts.Save")
+
+	query := `
+		INSERT INTO holder_counts (token_address, holder_count, counted_at)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (token_address) DO UPDATE SET
+			holder_count = excluded.holder_count,
+			counted_at = excluded.counted_at
+	`
+	if _, err := r.db.ExecContext(ctx, query, tokenAddress, count, sqliteTime(countedAt)); err != nil {
+		return fmt.Errorf("failed to save holder count: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns a token's last recorded holder count
+func (r *SQLiteHolderCountRepo) Get(ctx context.Context, tokenAddress string) (*repositories.HolderCount, error) {
+	ctx = withQueryName(ctx, "holder_counts.Get")
+
+	var count repositories.HolderCount
+	query := `SELECT token_address, holder_count, counted_at FROM holder_counts WHERE token_address = ?1`
+	if err := r.db.GetContext(ctx, &count, query, tokenAddress); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get holder count: %w", err)
+	}
+
+	return &count, nil
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteHolderSnapshotRepo implements HolderSnapshotRepository
+var _ repositories.HolderSnapshotRepository = (*SQLiteHolderSnapshotRepo)(nil)
+
+// SQLiteHolderSnapshotRepo implements HolderSnapshotRepository using SQLite
+type SQLiteHolderSnapshotRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteHolderSnapshotRepo creates a new SQLite holder snapshot repository
+func NewSQLiteHolderSnapshotRepo(db *sqlx.DB) *SQLiteHolderSnapshotRepo {
+	return &SQLiteHolderSnapshotRepo{db: db}
+}
+
+// Save stores a token's top holders as the snapshot taken at takenAt
+func (r *SQLiteHolderSnapshotRepo) Save(ctx context.Context, tokenAddress string, takenAt time.Time, holders []repositories.HolderBalance) error {
+	ctx = withQueryName(ctx, "holder_snapshots.Save")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO holder_snapshots (token_address, taken_at, address, balance, rank)
+		VALUES (?1, ?2, ?3, ?4, ?5)
+		ON CONFLICT (token_address, taken_at, address) DO NOTHING
+	`
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, h := range holders {
+		if _, err := stmt.ExecContext(ctx, tokenAddress, sqliteTime(takenAt), h.Address, h.Balance, h.Rank); err != nil {
+			return fmt.Errorf("failed to save holder snapshot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetAt returns the latest snapshot taken at or before at, falling back to the earliest one
+func (r *SQLiteHolderSnapshotRepo) GetAt(ctx context.Context, tokenAddress string, at time.Time) (*repositories.HolderSnapshot, error) {
+	ctx = withQueryName(ctx, "holder_snapshots.GetAt")
+
+	// Snapshots at or before at sort first, newest first; later ones follow oldest first
+	var takenAt time.Time
+	timeQuery := `
+		SELECT taken_at
+		FROM holder_snapshots
+		WHERE token_address = ?1
+		GROUP BY taken_at
+		ORDER BY taken_at > ?2, CASE WHEN taken_at <= ?2 THEN taken_at END DESC, taken_at
+		LIMIT 1
+	`
+	if err := r.db.GetContext(ctx, &takenAt, timeQuery, tokenAddress, sqliteTime(at)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find holder snapshot: %w", err)
+	}
+
+	var rows []holderBalanceRow
+	query := `
+		SELECT address, balance, rank
+		FROM holder_snapshots
+		WHERE token_address = ?1 AND taken_at = ?2
+		ORDER BY rank
+	`
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, sqliteTime(takenAt)); err != nil {
+		return nil, fmt.Errorf("failed to get holder snapshot: %w", err)
+	}
+
+	snapshot := &repositories.HolderSnapshot{
+		TokenAddress: tokenAddress,
+		TakenAt:      takenAt,
+		Holders:      make([]repositories.HolderBalance, len(rows)),
+	}
+	for i, row := range rows {
+		snapshot.Holders[i] = repositories.HolderBalance(row)
+	}
+
+	return snapshot, nil
+}
+
+// DeleteBefore removes snapshots taken before the given time
+func (r *SQLiteHolderSnapshotRepo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withQueryName(ctx, "holder_snapshots.DeleteBefore")
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM holder_snapshots WHERE taken_at < ?1`, sqliteTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete holder snapshots: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return deleted, nil
+}
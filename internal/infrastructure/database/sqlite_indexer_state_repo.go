@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteIndexerStateRepo implements IndexerStateRepository
+var _ repositories.IndexerStateRepository = (*SQLiteIndexerStateRepo)(nil)
+
+// SQLiteIndexerStateRepo implements IndexerStateRepository using SQLite
+type SQLiteIndexerStateRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteIndexerStateRepo creates a new SQLite indexer state repository
+func NewSQLiteIndexerStateRepo(db *sqlx.DB) *SQLiteIndexerStateRepo {
+	return &SQLiteIndexerStateRepo{db: db}
+}
+
+// Get retrieves the indexer state for a token
+func (r *SQLiteIndexerStateRepo) Get(ctx context.Context, tokenAddress string) (*entities.IndexerState, error) {
+	ctx = withQueryName(ctx, "indexer_state.Get")
+
+	var state entities.IndexerState
+	query := `SELECT * FROM indexer_state WHERE token_address = ?1`
+
+	if err := r.db.GetContext(ctx, &state, query, tokenAddress); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get indexer state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// Upsert creates or updates the indexer state
+func (r *SQLiteIndexerStateRepo) Upsert(ctx context.Context, state *entities.IndexerState) error {
+	ctx = withQueryName(ctx, "indexer_state.Upsert")
+
+	query := `
+		INSERT INTO indexer_state (token_address, last_indexed_block, is_backfilling, backfill_from_block, backfill_to_block)
+		VALUES (?1, ?2, ?3, ?4, ?5)
+		ON CONFLICT (token_address) DO UPDATE SET
+			last_indexed_block = excluded.last_indexed_block,
+			is_backfilling = excluded.is_backfilling,
+			backfill_from_block = excluded.backfill_from_block,
+			backfill_to_block = excluded.backfill_to_block,
+			updated_at = ` + sqliteNow + `
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		state.TokenAddress,
+		state.LastIndexedBlock,
+		state.IsBackfilling,
+		state.BackfillFromBlock,
+		state.BackfillToBlock,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert indexer state: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateLastBlock updates the last indexed block for a token
+func (r *SQLiteIndexerStateRepo) UpdateLastBlock(ctx context.Context, tokenAddress string, blockNumber int64) error {
+	ctx = withQueryName(ctx, "indexer_state.UpdateLastBlock")
+
+	query := `
+		INSERT INTO indexer_state (token_address, last_indexed_block)
+		VALUES (?1, ?2)
+		ON CONFLICT (token_address) DO UPDATE SET
+			last_indexed_block = excluded.last_indexed_block,
+			updated_at = ` + sqliteNow + `
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, tokenAddress, blockNumber); err != nil {
+		return fmt.Errorf("failed to update last block: %w", err)
+	}
+
+	return nil
+}
+
+// GetOldestCheckpointTime returns when the least recently advanced checkpoint of an active token
+// last moved. Deactivated tokens are left out, since their checkpoints stop on purpose.
+func (r *SQLiteIndexerStateRepo) GetOldestCheckpointTime(ctx context.Context) (*time.Time, error) {
+	ctx = withQueryName(ctx, "indexer_state.GetOldestCheckpointTime")
+
+	query := `
+		SELECT MIN(s.updated_at)
+		FROM indexer_state s
+		JOIN tokens t ON t.address = s.token_address
+		WHERE t.active
+	`
+
+	var ts *string
+	if err := r.db.GetContext(ctx, &ts, query); err != nil {
+		return nil, fmt.Errorf("failed to get oldest checkpoint time: %w", err)
+	}
+
+	return sqliteTimestamp(ts), nil
+}
+
+// SetBackfilling sets the backfilling state for a token, clearing the backfill checkpoint
+// unless the range is the one it was recorded for
+func (r *SQLiteIndexerStateRepo) SetBackfilling(ctx context.Context, tokenAddress string, isBackfilling bool, fromBlock, toBlock *int64) error {
+	ctx = withQueryName(ctx, "indexer_state.SetBackfilling")
+
+	query := `
+		UPDATE indexer_state SET
+			is_backfilling = ?2,
+			backfill_from_block = ?3,
+			backfill_to_block = ?4,
+			backfill_checkpoint = CASE
+				WHEN backfill_from_block IS ?3 AND backfill_to_block IS ?4 THEN backfill_checkpoint
+			END,
+			updated_at = ` + sqliteNow + `
+		WHERE token_address = ?1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, tokenAddress, isBackfilling, fromBlock, toBlock)
+	if err != nil {
+		return fmt.Errorf("failed to set backfilling: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateBackfillCheckpoint records the last block of the completed prefix of the backfill range.
+// updated_at is left alone: it dates the live checkpoint, which health freshness is measured by.
+func (r *SQLiteIndexerStateRepo) UpdateBackfillCheckpoint(ctx context.Context, tokenAddress string, blockNumber int64) error {
+	ctx = withQueryName(ctx, "indexer_state.UpdateBackfillCheckpoint")
+
+	query := `UPDATE indexer_state SET backfill_checkpoint = ?2 WHERE token_address = ?1`
+
+	if _, err := r.db.ExecContext(ctx, query, tokenAddress, blockNumber); err != nil {
+		return fmt.Errorf("failed to update backfill checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Ensure SQLiteScopedEventStateRepo implements ScopedEventStateRepository
+var _ repositories.ScopedEventStateRepository = (*SQLiteScopedEventStateRepo)(nil)
+
+// SQLiteScopedEventStateRepo implements ScopedEventStateRepository using SQLite
+type SQLiteScopedEventStateRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteScopedEventStateRepo creates a new SQLite scoped event checkpoint repository
+func NewSQLiteScopedEventStateRepo(db *sqlx.DB) *SQLiteScopedEventStateRepo {
+	return &SQLiteScopedEventStateRepo{db: db}
+}
+
+// GetLastBlock returns the last block whose scoped events were indexed, or 0 if none were
+func (r *SQLiteScopedEventStateRepo) GetLastBlock(ctx context.Context) (int64, error) {
+	ctx = withQueryName(ctx, "scoped_event_state.GetLastBlock")
+
+	var blockNumber int64
+	query := `SELECT COALESCE(MAX(last_indexed_block), 0) FROM scoped_event_state`
+	if err := r.db.GetContext(ctx, &blockNumber, query); err != nil {
+		return 0, fmt.Errorf("failed to get scoped event checkpoint: %w", err)
+	}
+
+	return blockNumber, nil
+}
+
+// UpdateLastBlock records the last block whose scoped events were indexed
+func (r *SQLiteScopedEventStateRepo) UpdateLastBlock(ctx context.Context, blockNumber int64) error {
+	ctx = withQueryName(ctx, "scoped_event_state.UpdateLastBlock")
+
+	query := `
+		INSERT INTO scoped_event_state (id, last_indexed_block)
+		VALUES (TRUE, ?1)
+		ON CONFLICT (id) DO UPDATE SET
+			last_indexed_block = excluded.last_indexed_block,
+			updated_at = ` + sqliteNow + `
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, blockNumber); err != nil {
+		return fmt.Errorf("failed to update scoped event checkpoint: %w", err)
+	}
+
+	return nil
+}
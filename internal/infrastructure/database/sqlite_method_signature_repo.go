@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteMethodSignatureRepo implements MethodSignatureRepository
+var _ repositories.MethodSignatureRepository = (*SQLiteMethodSignatureRepo)(nil)
+
+// SQLiteMethodSignatureRepo implements MethodSignatureRepository using SQLite
+type SQLiteMethodSignatureRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteMethodSignatureRepo creates a new SQLite method signature repository
+func NewSQLiteMethodSignatureRepo(db *sqlx.DB) *SQLiteMethodSignatureRepo {
+	return &SQLiteMethodSignatureRepo{db: db}
+}
+
+// Upsert inserts a signature or updates the name and label of an existing selector
+func (r *SQLiteMethodSignatureRepo) Upsert(ctx context.Context, sig *entities.MethodSignature) error {
+	ctx = withQueryName(ctx, "method_signatures.Upsert")
+
+	query := `
+		INSERT INTO method_signatures (selector, name, label)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (selector) DO UPDATE SET
+			name = excluded.name,
+			label = excluded.label,
+			updated_at = ` + sqliteNow + `
+		RETURNING created_at, updated_at
+	`
+	row := r.db.QueryRowxContext(ctx, query, sig.Selector, sig.Name, sig.Label)
+	if err := row.Scan(&sig.CreatedAt, &sig.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to upsert method signature: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every signature
+func (r *SQLiteMethodSignatureRepo) List(ctx context.Context) ([]entities.MethodSignature, error) {
+	ctx = withQueryName(ctx, "method_signatures.List")
+
+	var sigs []entities.MethodSignature
+	query := `
+		SELECT selector, name, label, created_at, updated_at
+		FROM method_signatures
+		ORDER BY label, selector
+	`
+
+	if err := r.db.SelectContext(ctx, &sigs, query); err != nil {
+		return nil, fmt.Errorf("failed to list method signatures: %w", err)
+	}
+
+	return sigs, nil
+}
+
+// GetBySelectors returns the signatures known for the given selectors
+func (r *SQLiteMethodSignatureRepo) GetBySelectors(ctx context.Context, selectors []string) ([]entities.MethodSignature, error) {
+	ctx = withQueryName(ctx, "method_signatures.GetBySelectors")
+
+	if len(selectors) == 0 {
+		return nil, nil
+	}
+
+	var sigs []entities.MethodSignature
+	query := `
+		SELECT selector, name, label, created_at, updated_at
+		FROM method_signatures
+		WHERE selector IN (SELECT value FROM json_each(?1))
+	`
+
+	if err := r.db.SelectContext(ctx, &sigs, query, sqliteList(selectors)); err != nil {
+		return nil, fmt.Errorf("failed to get method signatures: %w", err)
+	}
+
+	return sigs, nil
+}
@@ -0,0 +1,469 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/pkg/units"
+)
+
+// Ensure SQLitePortfolioRepo implements PortfolioRepository
+var _ repositories.PortfolioRepository = (*SQLitePortfolioRepo)(nil)
+
+// SQLitePortfolioRepo implements PortfolioRepository using SQLite
+type SQLitePortfolioRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLitePortfolioRepo creates a new SQLite portfolio repository
+func NewSQLitePortfolioRepo(db *sqlx.DB) *SQLitePortfolioRepo {
+	return &SQLitePortfolioRepo{db: db}
+}
+
+// sqliteWalletChange is a transfer's signed effect on the balance of wallet ?1
+const sqliteWalletChange = `
+	CASE
+		WHEN from_address = ?1 AND to_address = ?1 THEN 0
+		WHEN to_address = ?1 THEN value
+		ELSE big_neg(value)
+	END`
+
+// sqliteMembersChange is a transfer's signed effect on the combined balance of the JSON list ?1
+const sqliteMembersChange = `
+	big_sub(
+		CASE WHEN to_address IN (SELECT value FROM json_each(?1)) THEN value ELSE 0 END,
+		CASE WHEN from_address IN (SELECT value FROM json_each(?1)) THEN value ELSE 0 END
+	)`
+
+// sqliteHoldings converts holding rows to entities
+func sqliteHoldings(rows []holdingRow) []entities.TokenHolding {
+	holdings := make([]entities.TokenHolding, len(rows))
+	for i, row := range rows {
+		holdings[i] = entities.TokenHolding{
+			TokenAddress: row.TokenAddress,
+			TokenName:    row.TokenName,
+			TokenSymbol:  row.TokenSymbol,
+			Decimals:     row.Decimals,
+			BalanceStr:   row.Balance,
+			BalanceHuman: units.Format(row.Balance, row.Decimals),
+		}
+	}
+	return holdings
+}
+
+// GetWalletHoldings retrieves all token holdings for a wallet
+func (r *SQLitePortfolioRepo) GetWalletHoldings(ctx context.Context, walletAddress string) ([]entities.TokenHolding, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletHoldings")
+
+	query := `
+		WITH balances AS (
+			SELECT token_address, big_sum(` + sqliteWalletChange + `) as balance
+			FROM transfers
+			WHERE from_address = ?1 OR to_address = ?1
+			GROUP BY token_address
+		)
+		SELECT b.token_address, t.name, t.symbol, t.decimals, b.balance
+		FROM balances b
+		JOIN tokens t ON t.address = b.token_address
+		WHERE big_cmp(b.balance, 0) > 0
+		ORDER BY big_key(b.balance) DESC
+	`
+
+	var rows []holdingRow
+	if err := r.db.SelectContext(ctx, &rows, query, walletAddress); err != nil {
+		return nil, fmt.Errorf("failed to get wallet holdings: %w", err)
+	}
+
+	return sqliteHoldings(rows), nil
+}
+
+// GetWalletHoldingByToken retrieves holding for specific token
+func (r *SQLitePortfolioRepo) GetWalletHoldingByToken(ctx context.Context, walletAddress, tokenAddress string) (*entities.TokenHolding, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletHoldingByToken")
+
+	query := `
+		SELECT
+			t.address as token_address,
+			t.name,
+			t.symbol,
+			t.decimals,
+			big_sum(CASE
+				WHEN tr.from_address = ?1 AND tr.to_address = ?1 THEN 0
+				WHEN tr.to_address = ?1 THEN tr.value
+				WHEN tr.from_address = ?1 THEN big_neg(tr.value)
+			END) as balance
+		FROM tokens t
+		LEFT JOIN transfers tr ON tr.token_address = t.address
+			AND (tr.from_address = ?1 OR tr.to_address = ?1)
+		WHERE t.address = ?2
+		GROUP BY t.address, t.name, t.symbol, t.decimals
+	`
+
+	var row holdingRow
+	if err := r.db.GetContext(ctx, &row, query, walletAddress, tokenAddress); err != nil {
+		return nil, fmt.Errorf("failed to get wallet holding by token: %w", err)
+	}
+
+	return &sqliteHoldings([]holdingRow{row})[0], nil
+}
+
+// GetWalletTokenCount returns count of tokens held by wallet
+func (r *SQLitePortfolioRepo) GetWalletTokenCount(ctx context.Context, walletAddress string) (int64, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletTokenCount")
+
+	query := `
+		WITH balances AS (
+			SELECT token_address, big_sum(` + sqliteWalletChange + `) as balance
+			FROM transfers
+			WHERE from_address = ?1 OR to_address = ?1
+			GROUP BY token_address
+		)
+		SELECT COUNT(*) FROM balances WHERE big_cmp(balance, 0) > 0
+	`
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, query, walletAddress); err != nil {
+		return 0, fmt.Errorf("failed to get wallet token count: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetWalletTransferSummary returns transfer stats for a wallet
+func (r *SQLitePortfolioRepo) GetWalletTransferSummary(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletTransferSummary")
+
+	query := `
+		SELECT
+			COUNT(CASE WHEN to_address = ?1 THEN 1 END) as total_in,
+			COUNT(CASE WHEN from_address = ?1 THEN 1 END) as total_out,
+			big_sum(CASE WHEN to_address = ?1 THEN value END) as volume_in,
+			big_sum(CASE WHEN from_address = ?1 THEN value END) as volume_out,
+			COUNT(DISTINCT token_address) as unique_tokens,
+			MIN(block_timestamp) as first_transfer,
+			MAX(block_timestamp) as last_transfer
+		FROM transfers
+		WHERE from_address = ?1 OR to_address = ?1
+	`
+
+	var row summaryRow
+	if err := r.db.GetContext(ctx, &row, query, walletAddress); err != nil {
+		return nil, fmt.Errorf("failed to get wallet transfer summary: %w", err)
+	}
+
+	return &repositories.WalletTransferSummary{
+		TotalTransfersIn:  row.TotalIn,
+		TotalTransfersOut: row.TotalOut,
+		TotalVolumeIn:     row.VolumeIn,
+		TotalVolumeOut:    row.VolumeOut,
+		UniqueTokens:      row.UniqueTokens,
+		FirstTransferAt:   sqliteTimestamp(row.FirstTransfer),
+		LastTransferAt:    sqliteTimestamp(row.LastTransfer),
+	}, nil
+}
+
+// GetWalletBalancesAt returns non-zero token balances from transfers before the given time
+func (r *SQLitePortfolioRepo) GetWalletBalancesAt(ctx context.Context, walletAddress string, at time.Time) ([]entities.TokenHolding, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletBalancesAt")
+
+	query := `
+		WITH balances AS (
+			SELECT token_address, big_sum(` + sqliteWalletChange + `) as balance
+			FROM transfers
+			WHERE (from_address = ?1 OR to_address = ?1)
+				AND block_timestamp < ?2
+			GROUP BY token_address
+		)
+		SELECT b.token_address, t.name, t.symbol, t.decimals, b.balance
+		FROM balances b
+		JOIN tokens t ON t.address = b.token_address
+		WHERE big_cmp(b.balance, 0) <> 0
+	`
+
+	var rows []holdingRow
+	if err := r.db.SelectContext(ctx, &rows, query, walletAddress, sqliteTime(at)); err != nil {
+		return nil, fmt.Errorf("failed to get wallet balances: %w", err)
+	}
+
+	return sqliteHoldings(rows), nil
+}
+
+// GetWalletDailyBalanceChanges returns per-day net balance changes since the given time, oldest first
+func (r *SQLitePortfolioRepo) GetWalletDailyBalanceChanges(ctx context.Context, walletAddress string, from time.Time) ([]repositories.DailyBalanceChange, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletDailyBalanceChanges")
+
+	query := `
+		SELECT
+			tr.token_address,
+			t.decimals,
+			date(tr.block_timestamp) as day,
+			big_sum(CASE
+				WHEN tr.from_address = ?1 AND tr.to_address = ?1 THEN 0
+				WHEN tr.to_address = ?1 THEN tr.value
+				ELSE big_neg(tr.value)
+			END) as change
+		FROM transfers tr
+		JOIN tokens t ON t.address = tr.token_address
+		WHERE (tr.from_address = ?1 OR tr.to_address = ?1)
+			AND tr.block_timestamp >= ?2
+		GROUP BY tr.token_address, t.decimals, day
+		ORDER BY day
+	`
+
+	var rows []struct {
+		TokenAddress string `db:"token_address"`
+		Decimals     int    `db:"decimals"`
+		Day          string `db:"day"`
+		Change       string `db:"change"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, walletAddress, sqliteTime(from)); err != nil {
+		return nil, fmt.Errorf("failed to get wallet daily balance changes: %w", err)
+	}
+
+	changes := make([]repositories.DailyBalanceChange, len(rows))
+	for i, row := range rows {
+		day, err := time.Parse("2006-01-02", row.Day)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse day %q: %w", row.Day, err)
+		}
+		changes[i] = repositories.DailyBalanceChange{
+			TokenAddress: row.TokenAddress,
+			Decimals:     row.Decimals,
+			Day:          day,
+			Change:       row.Change,
+		}
+	}
+
+	return changes, nil
+}
+
+// GetWalletTokenActivity returns every token the wallet has sent or received, most recently active first
+func (r *SQLitePortfolioRepo) GetWalletTokenActivity(ctx context.Context, walletAddress string) ([]repositories.WalletTokenActivity, error) {
+	ctx = withQueryName(ctx, "portfolio.GetWalletTokenActivity")
+
+	query := `
+		SELECT
+			tr.token_address,
+			t.name,
+			t.symbol,
+			t.decimals,
+			big_sub(
+				big_sum(CASE WHEN tr.to_address = ?1 THEN tr.value END),
+				big_sum(CASE WHEN tr.from_address = ?1 THEN tr.value END)
+			) as balance,
+			COUNT(CASE WHEN tr.to_address = ?1 THEN 1 END) as transfers_in,
+			COUNT(CASE WHEN tr.from_address = ?1 THEN 1 END) as transfers_out,
+			MIN(tr.block_timestamp) as first_activity,
+			MAX(tr.block_timestamp) as last_activity
+		FROM transfers tr
+		JOIN tokens t ON t.address = tr.token_address
+		WHERE tr.from_address = ?1 OR tr.to_address = ?1
+		GROUP BY tr.token_address, t.name, t.symbol, t.decimals
+		ORDER BY MAX(tr.block_timestamp) DESC, tr.token_address
+	`
+
+	var rows []struct {
+		TokenAddress  string `db:"token_address"`
+		TokenName     string `db:"name"`
+		TokenSymbol   string `db:"symbol"`
+		Decimals      int    `db:"decimals"`
+		Balance       string `db:"balance"`
+		TransfersIn   int64  `db:"transfers_in"`
+		TransfersOut  int64  `db:"transfers_out"`
+		FirstActivity string `db:"first_activity"`
+		LastActivity  string `db:"last_activity"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, walletAddress); err != nil {
+		return nil, fmt.Errorf("failed to get wallet token activity: %w", err)
+	}
+
+	result := make([]repositories.WalletTokenActivity, len(rows))
+	for i, row := range rows {
+		result[i] = repositories.WalletTokenActivity{
+			TokenAddress:    row.TokenAddress,
+			TokenName:       row.TokenName,
+			TokenSymbol:     row.TokenSymbol,
+			Decimals:        row.Decimals,
+			Balance:         row.Balance,
+			TransfersIn:     row.TransfersIn,
+			TransfersOut:    row.TransfersOut,
+			FirstActivityAt: sqliteTimeValue(row.FirstActivity),
+			LastActivityAt:  sqliteTimeValue(row.LastActivity),
+		}
+	}
+
+	return result, nil
+}
+
+// GetEntityHoldings retrieves the combined positive token balances of a group of addresses.
+// Transfers between members add and subtract the same amount, so they cancel out.
+func (r *SQLitePortfolioRepo) GetEntityHoldings(ctx context.Context, addresses []string) ([]entities.TokenHolding, error) {
+	ctx = withQueryName(ctx, "portfolio.GetEntityHoldings")
+
+	query := `
+		WITH balances AS (
+			SELECT token_address, big_sum(` + sqliteMembersChange + `) as balance
+			FROM transfers
+			WHERE from_address IN (SELECT value FROM json_each(?1)) OR to_address IN (SELECT value FROM json_each(?1))
+			GROUP BY token_address
+		)
+		SELECT b.token_address, t.name, t.symbol, t.decimals, b.balance
+		FROM balances b
+		JOIN tokens t ON t.address = b.token_address
+		WHERE big_cmp(b.balance, 0) > 0
+		ORDER BY big_key(b.balance) DESC
+	`
+
+	var rows []holdingRow
+	if err := r.db.SelectContext(ctx, &rows, query, sqliteList(addresses)); err != nil {
+		return nil, fmt.Errorf("failed to get entity holdings: %w", err)
+	}
+
+	return sqliteHoldings(rows), nil
+}
+
+// GetEntityTokenVolumes returns a group of addresses' per-token flows, most recently active first
+func (r *SQLitePortfolioRepo) GetEntityTokenVolumes(ctx context.Context, addresses []string) ([]repositories.EntityTokenVolume, error) {
+	ctx = withQueryName(ctx, "portfolio.GetEntityTokenVolumes")
+
+	query := `
+		WITH flows AS (
+			SELECT
+				token_address,
+				value,
+				block_timestamp,
+				from_address IN (SELECT value FROM json_each(?1)) as from_member,
+				to_address IN (SELECT value FROM json_each(?1)) as to_member
+			FROM transfers
+			WHERE from_address IN (SELECT value FROM json_each(?1)) OR to_address IN (SELECT value FROM json_each(?1))
+		)
+		SELECT
+			f.token_address,
+			t.symbol,
+			t.decimals,
+			COUNT(CASE WHEN f.to_member AND NOT f.from_member THEN 1 END) as transfers_in,
+			COUNT(CASE WHEN f.from_member AND NOT f.to_member THEN 1 END) as transfers_out,
+			COUNT(CASE WHEN f.from_member AND f.to_member THEN 1 END) as internal_transfers,
+			big_sum(CASE WHEN f.to_member AND NOT f.from_member THEN f.value END) as volume_in,
+			big_sum(CASE WHEN f.from_member AND NOT f.to_member THEN f.value END) as volume_out,
+			big_sum(CASE WHEN f.from_member AND f.to_member THEN f.value END) as internal_volume,
+			MIN(f.block_timestamp) as first_activity,
+			MAX(f.block_timestamp) as last_activity
+		FROM flows f
+		JOIN tokens t ON t.address = f.token_address
+		GROUP BY f.token_address, t.symbol, t.decimals
+		ORDER BY MAX(f.block_timestamp) DESC, f.token_address
+	`
+
+	var rows []struct {
+		TokenAddress      string `db:"token_address"`
+		TokenSymbol       string `db:"symbol"`
+		Decimals          int    `db:"decimals"`
+		TransfersIn       int64  `db:"transfers_in"`
+		TransfersOut      int64  `db:"transfers_out"`
+		InternalTransfers int64  `db:"internal_transfers"`
+		VolumeIn          string `db:"volume_in"`
+		VolumeOut         string `db:"volume_out"`
+		InternalVolume    string `db:"internal_volume"`
+		FirstActivity     string `db:"first_activity"`
+		LastActivity      string `db:"last_activity"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, sqliteList(addresses)); err != nil {
+		return nil, fmt.Errorf("failed to get entity token volumes: %w", err)
+	}
+
+	result := make([]repositories.EntityTokenVolume, len(rows))
+	for i, row := range rows {
+		result[i] = repositories.EntityTokenVolume{
+			TokenAddress:      row.TokenAddress,
+			TokenSymbol:       row.TokenSymbol,
+			Decimals:          row.Decimals,
+			TransfersIn:       row.TransfersIn,
+			TransfersOut:      row.TransfersOut,
+			InternalTransfers: row.InternalTransfers,
+			VolumeIn:          row.VolumeIn,
+			VolumeOut:         row.VolumeOut,
+			InternalVolume:    row.InternalVolume,
+			FirstActivityAt:   sqliteTimeValue(row.FirstActivity),
+			LastActivityAt:    sqliteTimeValue(row.LastActivity),
+		}
+	}
+
+	return result, nil
+}
+
+// GetCounterpartyFlows aggregates a set of addresses' recent transfers into per-token flows
+func (r *SQLitePortfolioRepo) GetCounterpartyFlows(ctx context.Context, addresses []string, scanLimit, limit int) ([]repositories.CounterpartyFlow, error) {
+	ctx = withQueryName(ctx, "portfolio.GetCounterpartyFlows")
+
+	// Transfers between two of the addresses are read with the outgoing ones only
+	query := `
+		WITH recent AS (
+			SELECT * FROM (
+				SELECT from_address, to_address, token_address, value, block_timestamp
+				FROM transfers
+				WHERE from_address IN (SELECT value FROM json_each(?1))
+				ORDER BY block_timestamp DESC
+				LIMIT ?2
+			)
+			UNION ALL
+			SELECT * FROM (
+				SELECT from_address, to_address, token_address, value, block_timestamp
+				FROM transfers
+				WHERE to_address IN (SELECT value FROM json_each(?1))
+				AND from_address NOT IN (SELECT value FROM json_each(?1))
+				ORDER BY block_timestamp DESC
+				LIMIT ?2
+			)
+		)
+		SELECT
+			r.from_address,
+			r.to_address,
+			r.token_address,
+			t.symbol,
+			t.decimals,
+			COUNT(*) as transfers,
+			big_sum(r.value) as volume,
+			MAX(r.block_timestamp) as last_transfer_at
+		FROM recent r
+		JOIN tokens t ON t.address = r.token_address
+		GROUP BY r.from_address, r.to_address, r.token_address, t.symbol, t.decimals
+		ORDER BY COUNT(*) DESC, MAX(r.block_timestamp) DESC, r.from_address, r.to_address, r.token_address
+		LIMIT ?3
+	`
+
+	var rows []struct {
+		FromAddress    string `db:"from_address"`
+		ToAddress      string `db:"to_address"`
+		TokenAddress   string `db:"token_address"`
+		TokenSymbol    string `db:"symbol"`
+		Decimals       int    `db:"decimals"`
+		Transfers      int64  `db:"transfers"`
+		Volume         string `db:"volume"`
+		LastTransferAt string `db:"last_transfer_at"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, sqliteList(addresses), scanLimit, limit); err != nil {
+		return nil, fmt.Errorf("failed to get counterparty flows: %w", err)
+	}
+
+	result := make([]repositories.CounterpartyFlow, len(rows))
+	for i, row := range rows {
+		result[i] = repositories.CounterpartyFlow{
+			FromAddress:    row.FromAddress,
+			ToAddress:      row.ToAddress,
+			TokenAddress:   row.TokenAddress,
+			TokenSymbol:    row.TokenSymbol,
+			Decimals:       row.Decimals,
+			Transfers:      row.Transfers,
+			Volume:         row.Volume,
+			LastTransferAt: sqliteTimeValue(row.LastTransferAt),
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,374 @@
+-- SQLite schema for DB_DRIVER=sqlite, equivalent to migrations/ up to SchemaVersion.
+-- uint256 amounts are decimal TEXT; timestamps are UTC text ('2006-01-02 15:04:05.999999999+00:00').
+
+CREATE TABLE IF NOT EXISTS tokens (
+    address TEXT PRIMARY KEY,
+    name TEXT,
+    symbol TEXT,
+    decimals INTEGER DEFAULT 18,
+    total_indexed_transfers INTEGER DEFAULT 0,
+    first_seen_block INTEGER,
+    last_seen_block INTEGER,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    deactivated_at TIMESTAMP,
+    metadata_source TEXT NOT NULL DEFAULT 'onchain',
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS token_metadata_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_address TEXT NOT NULL REFERENCES tokens(address),
+    previous_name TEXT NOT NULL,
+    previous_symbol TEXT NOT NULL,
+    previous_decimals INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    symbol TEXT NOT NULL,
+    decimals INTEGER NOT NULL,
+    block_number INTEGER NOT NULL,
+    detected_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_metadata_history_token ON token_metadata_history (token_address, id);
+
+CREATE TABLE IF NOT EXISTS transfers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tx_hash TEXT NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_number INTEGER NOT NULL,
+    block_timestamp TIMESTAMP NOT NULL,
+    token_address TEXT NOT NULL REFERENCES tokens(address),
+    from_address TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    value TEXT NOT NULL,
+    initiator TEXT,
+    method_selector TEXT,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_token ON transfers (token_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_transfers_from ON transfers (from_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_transfers_to ON transfers (to_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_transfers_block ON transfers (block_number);
+CREATE INDEX IF NOT EXISTS idx_transfers_tx_hash ON transfers (tx_hash);
+CREATE INDEX IF NOT EXISTS idx_transfers_initiator ON transfers (initiator, block_timestamp DESC);
+
+CREATE TABLE IF NOT EXISTS transfer_flags (
+    tx_hash TEXT NOT NULL,
+    log_index INTEGER NOT NULL,
+    flag TEXT NOT NULL,
+    token_address TEXT NOT NULL,
+    flagged_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    PRIMARY KEY (tx_hash, log_index, flag)
+);
+CREATE INDEX IF NOT EXISTS idx_transfers_method_selector ON transfers (method_selector, block_timestamp DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfers_unique ON transfers (tx_hash, log_index, block_timestamp);
+
+CREATE TABLE IF NOT EXISTS indexer_state (
+    token_address TEXT PRIMARY KEY REFERENCES tokens(address),
+    last_indexed_block INTEGER NOT NULL DEFAULT 0,
+    is_backfilling BOOLEAN DEFAULT FALSE,
+    backfill_from_block INTEGER,
+    backfill_to_block INTEGER,
+    backfill_checkpoint INTEGER,
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS dex_pools (
+    address TEXT PRIMARY KEY,
+    token0_address TEXT NOT NULL,
+    token1_address TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS swaps (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tx_hash TEXT NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_number INTEGER NOT NULL,
+    block_timestamp TIMESTAMP NOT NULL,
+    pool_address TEXT NOT NULL REFERENCES dex_pools(address),
+    protocol TEXT NOT NULL,
+    sender TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    token0_address TEXT NOT NULL,
+    token1_address TEXT NOT NULL,
+    amount0 TEXT NOT NULL,
+    amount1 TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_swaps_pool ON swaps (pool_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_swaps_token0 ON swaps (token0_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_swaps_token1 ON swaps (token1_address, block_timestamp DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_swaps_unique ON swaps (tx_hash, log_index, block_timestamp);
+
+CREATE TABLE IF NOT EXISTS token_daily_stats (
+    token_address TEXT NOT NULL REFERENCES tokens(address),
+    day DATE NOT NULL,
+    transfers INTEGER NOT NULL DEFAULT 0,
+    volume TEXT NOT NULL DEFAULT '0',
+    unique_senders INTEGER NOT NULL DEFAULT 0,
+    unique_receivers INTEGER NOT NULL DEFAULT 0,
+    new_senders INTEGER NOT NULL DEFAULT 0,
+    new_holders INTEGER NOT NULL DEFAULT 0,
+    first_transfer_at TIMESTAMP NOT NULL,
+    last_transfer_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    PRIMARY KEY (token_address, day)
+);
+
+CREATE TABLE IF NOT EXISTS holder_first_seen (
+    token_address TEXT NOT NULL,
+    address TEXT NOT NULL,
+    first_block INTEGER NOT NULL,
+    first_seen_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (token_address, address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_holder_first_seen_newest ON holder_first_seen (token_address, first_seen_at DESC);
+
+CREATE TABLE IF NOT EXISTS watchlists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS watchlist_addresses (
+    watchlist_id INTEGER NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    address TEXT NOT NULL,
+    added_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    PRIMARY KEY (watchlist_id, address)
+);
+
+-- counterparties is a JSON array of addresses
+CREATE TABLE IF NOT EXISTS tenants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    rate_limit_rps INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS tenant_api_keys (
+    key_hash TEXT PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_api_keys_tenant ON tenant_api_keys (tenant_id);
+
+CREATE TABLE IF NOT EXISTS tenant_tokens (
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    token_address TEXT NOT NULL,
+    PRIMARY KEY (tenant_id, token_address)
+);
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    token_address TEXT,
+    address TEXT,
+    direction TEXT NOT NULL DEFAULT 'any',
+    min_value TEXT,
+    counterparties TEXT NOT NULL DEFAULT '[]',
+    channel TEXT NOT NULL,
+    target TEXT NOT NULL,
+    tenant_id INTEGER REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS deny_list (
+    list_name TEXT NOT NULL,
+    address TEXT NOT NULL,
+    label TEXT NOT NULL DEFAULT '',
+    added_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    PRIMARY KEY (list_name, address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_deny_list_address ON deny_list (address);
+
+CREATE TABLE IF NOT EXISTS holder_snapshots (
+    token_address TEXT NOT NULL,
+    taken_at TIMESTAMP NOT NULL,
+    address TEXT NOT NULL,
+    balance TEXT NOT NULL,
+    rank INTEGER NOT NULL,
+    PRIMARY KEY (token_address, taken_at, address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_holder_snapshots_taken_at ON holder_snapshots (taken_at);
+
+CREATE TABLE IF NOT EXISTS holder_counts (
+    token_address TEXT PRIMARY KEY,
+    holder_count INTEGER NOT NULL,
+    counted_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS token_advanced_stats (
+    token_address TEXT PRIMARY KEY,
+    window_from TIMESTAMP NOT NULL,
+    window_to TIMESTAMP NOT NULL,
+    volume TEXT NOT NULL,
+    average_supply TEXT NOT NULL,
+    circulating_supply TEXT NOT NULL,
+    inactive_since TIMESTAMP NOT NULL,
+    dormant_supply TEXT NOT NULL,
+    dormant_holders INTEGER NOT NULL,
+    self_transfers INTEGER NOT NULL DEFAULT 0,
+    self_transfer_volume TEXT NOT NULL DEFAULT '0',
+    fan_out_threshold INTEGER NOT NULL DEFAULT 0,
+    fan_out_transactions INTEGER NOT NULL DEFAULT 0,
+    fan_out_transfers INTEGER NOT NULL DEFAULT 0,
+    fan_out_volume TEXT NOT NULL DEFAULT '0',
+    computed_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS entities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS entity_addresses (
+    entity_id INTEGER NOT NULL REFERENCES entities(id) ON DELETE CASCADE,
+    address TEXT NOT NULL,
+    added_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    PRIMARY KEY (entity_id, address)
+);
+
+CREATE TABLE IF NOT EXISTS eth_transfers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tx_hash TEXT NOT NULL,
+    trace_address TEXT NOT NULL,
+    block_number INTEGER NOT NULL,
+    block_timestamp TIMESTAMP NOT NULL,
+    from_address TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    value TEXT NOT NULL,
+    call_type TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_eth_transfers_from ON eth_transfers (from_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_eth_transfers_to ON eth_transfers (to_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_eth_transfers_block ON eth_transfers (block_number);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_eth_transfers_unique ON eth_transfers (tx_hash, trace_address, block_timestamp);
+
+CREATE TABLE IF NOT EXISTS eth_trace_state (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_indexed_block INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS raw_logs (
+    block_number INTEGER NOT NULL,
+    tx_hash TEXT NOT NULL,
+    log_index INTEGER NOT NULL,
+    address TEXT NOT NULL,
+    block_timestamp TIMESTAMP NOT NULL,
+    log TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    PRIMARY KEY (block_number, tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_raw_logs_address ON raw_logs (address, block_number);
+
+CREATE TABLE IF NOT EXISTS method_signatures (
+    selector TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    label TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_method_signatures_label ON method_signatures (label);
+
+INSERT INTO method_signatures (selector, name, label) VALUES
+    ('0xa9059cbb', 'transfer(address,uint256)', 'transfer'),
+    ('0x23b872dd', 'transferFrom(address,address,uint256)', 'transferFrom'),
+    ('0x095ea7b3', 'approve(address,uint256)', 'approve'),
+    ('0x38ed1739', 'swapExactTokensForTokens(uint256,uint256,address[],address,uint256)', 'swap'),
+    ('0x8803dbee', 'swapTokensForExactTokens(uint256,uint256,address[],address,uint256)', 'swap'),
+    ('0x7ff36ab5', 'swapExactETHForTokens(uint256,address[],address,uint256)', 'swap'),
+    ('0x18cbafe5', 'swapExactTokensForETH(uint256,uint256,address[],address,uint256)', 'swap'),
+    ('0x414bf389', 'exactInputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))', 'swap'),
+    ('0xc04b8d59', 'exactInput((bytes,address,uint256,uint256,uint256))', 'swap'),
+    ('0x3593564c', 'execute(bytes,bytes[],uint256)', 'swap'),
+    ('0xac9650d8', 'multicall(bytes[])', 'multicall'),
+    ('0x5ae401dc', 'multicall(uint256,bytes[])', 'multicall'),
+    ('0x0f5287b0', 'transferTokens(address,uint256,uint16,bytes32,uint256,uint32)', 'bridge'),
+    ('0xd2ce7d65', 'outboundTransfer(address,address,uint256,uint256,uint256,bytes)', 'bridge')
+ON CONFLICT (selector) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS scoped_event_state (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_indexed_block INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS failed_ranges (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_address TEXT NOT NULL,
+    from_block INTEGER NOT NULL,
+    to_block INTEGER NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    skipped BOOLEAN NOT NULL DEFAULT FALSE,
+    first_failed_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    last_failed_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    resolved_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_failed_ranges_unresolved
+    ON failed_ranges (token_address, from_block, to_block)
+    WHERE resolved_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS wallet_stats (
+    wallet_address TEXT NOT NULL,
+    token_address TEXT NOT NULL,
+    transfers_in INTEGER NOT NULL DEFAULT 0,
+    transfers_out INTEGER NOT NULL DEFAULT 0,
+    volume_in TEXT NOT NULL DEFAULT '0',
+    volume_out TEXT NOT NULL DEFAULT '0',
+    first_transfer_at TIMESTAMP NOT NULL,
+    last_transfer_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    PRIMARY KEY (wallet_address, token_address)
+);
+
+CREATE TABLE IF NOT EXISTS address_notes (
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    address TEXT NOT NULL,
+    note TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    PRIMARY KEY (tenant_id, address)
+);
+
+CREATE TABLE IF NOT EXISTS contracts (
+    address TEXT PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    abi TEXT NOT NULL,
+    events TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS contract_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    contract_address TEXT NOT NULL REFERENCES contracts(address) ON DELETE CASCADE,
+    event_name TEXT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_number INTEGER NOT NULL,
+    block_timestamp TIMESTAMP NOT NULL,
+    topics TEXT NOT NULL,
+    args TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_contract_events_contract ON contract_events (contract_address, event_name, block_timestamp DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contract_events_unique ON contract_events (tx_hash, log_index, block_timestamp);
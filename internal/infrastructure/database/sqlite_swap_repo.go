@@ -0,0 +1,204 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteSwapRepo implements SwapRepository
+var _ repositories.SwapRepository = (*SQLiteSwapRepo)(nil)
+
+// SQLiteSwapRepo implements SwapRepository using SQLite
+type SQLiteSwapRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteSwapRepo creates a new SQLite swap repository
+func NewSQLiteSwapRepo(db *sqlx.DB) *SQLiteSwapRepo {
+	return &SQLiteSwapRepo{db: db}
+}
+
+// StoreEvents inserts decoded swaps in a single transaction, skipping duplicates
+func (r *SQLiteSwapRepo) StoreEvents(ctx context.Context, events []any) error {
+	ctx = withQueryName(ctx, "swaps.StoreEvents")
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO swaps (tx_hash, log_index, block_number, block_timestamp, pool_address, protocol,
+						   sender, recipient, token0_address, token1_address, amount0, amount1)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12)
+		ON CONFLICT (tx_hash, log_index, block_timestamp) DO NOTHING
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		s, ok := event.(*entities.Swap)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T", event)
+		}
+
+		_, err := stmt.ExecContext(ctx,
+			s.TxHash,
+			s.LogIndex,
+			s.BlockNumber,
+			sqliteTime(s.BlockTimestamp),
+			s.PoolAddress,
+			s.Protocol,
+			s.Sender,
+			s.Recipient,
+			s.Token0Address,
+			s.Token1Address,
+			s.Amount0String,
+			s.Amount1String,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert swap: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertPool creates or updates a DEX pool
+func (r *SQLiteSwapRepo) UpsertPool(ctx context.Context, pool *entities.DexPool) error {
+	ctx = withQueryName(ctx, "swaps.UpsertPool")
+
+	query := `
+		INSERT INTO dex_pools (address, token0_address, token1_address)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (address) DO UPDATE SET
+			token0_address = excluded.token0_address,
+			token1_address = excluded.token1_address
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, pool.Address, pool.Token0Address, pool.Token1Address); err != nil {
+		return fmt.Errorf("failed to upsert pool: %w", err)
+	}
+
+	return nil
+}
+
+// GetPool retrieves a pool by address
+func (r *SQLiteSwapRepo) GetPool(ctx context.Context, address string) (*entities.DexPool, error) {
+	ctx = withQueryName(ctx, "swaps.GetPool")
+
+	var pool entities.DexPool
+	query := `SELECT * FROM dex_pools WHERE address = ?1`
+
+	if err := r.db.GetContext(ctx, &pool, query, address); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pool: %w", err)
+	}
+
+	return &pool, nil
+}
+
+// GetByFilter retrieves swaps matching the filter, newest first
+func (r *SQLiteSwapRepo) GetByFilter(ctx context.Context, filter entities.SwapFilter) ([]entities.Swap, error) {
+	ctx = withQueryName(ctx, "swaps.GetByFilter")
+
+	where, args := sqliteSwapConditions(filter)
+	query := fmt.Sprintf(`
+		SELECT id, tx_hash, log_index, block_number, block_timestamp, pool_address, protocol,
+			   sender, recipient, token0_address, token1_address, amount0, amount1, created_at
+		FROM swaps%s
+		ORDER BY block_timestamp DESC, log_index DESC
+		LIMIT ?%d OFFSET ?%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	var swaps []entities.Swap
+	if err := r.db.SelectContext(ctx, &swaps, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get swaps: %w", err)
+	}
+
+	return swaps, nil
+}
+
+// GetCount returns the count of swaps matching the filter
+func (r *SQLiteSwapRepo) GetCount(ctx context.Context, filter entities.SwapFilter) (int64, error) {
+	ctx = withQueryName(ctx, "swaps.GetCount")
+
+	where, args := sqliteSwapConditions(filter)
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM swaps"+where, args...); err != nil {
+		return 0, fmt.Errorf("failed to get swap count: %w", err)
+	}
+
+	return count, nil
+}
+
+// sqliteSwapConditions builds the WHERE clause for a swap filter
+func sqliteSwapConditions(filter entities.SwapFilter) (string, []interface{}) {
+	if filter.PoolAddress == nil {
+		return "", nil
+	}
+	return " WHERE pool_address = ?1", []interface{}{*filter.PoolAddress}
+}
+
+// GetTokenSwapVolume returns swap volume for a token across all pools it trades in
+func (r *SQLiteSwapRepo) GetTokenSwapVolume(ctx context.Context, tokenAddress string) (*repositories.SwapVolumeResult, error) {
+	ctx = withQueryName(ctx, "swaps.GetTokenSwapVolume")
+
+	now := time.Now().UTC()
+	query := `
+		WITH token_swaps AS (
+			SELECT pool_address, block_timestamp, big_abs(amount0) AS amount FROM swaps WHERE token0_address = ?1
+			UNION ALL
+			SELECT pool_address, block_timestamp, big_abs(amount1) AS amount FROM swaps WHERE token1_address = ?1
+		)
+		SELECT
+			COUNT(*) AS swap_count,
+			COUNT(DISTINCT pool_address) AS pool_count,
+			big_sum(amount) AS total_volume,
+			COUNT(CASE WHEN block_timestamp >= ?2 THEN 1 END) AS swap_count_24h,
+			big_sum(CASE WHEN block_timestamp >= ?2 THEN amount END) AS volume_24h,
+			COUNT(CASE WHEN block_timestamp >= ?3 THEN 1 END) AS swap_count_7d,
+			big_sum(CASE WHEN block_timestamp >= ?3 THEN amount END) AS volume_7d
+		FROM token_swaps
+	`
+
+	var row swapVolumeRow
+	if err := r.db.GetContext(ctx, &row, query, tokenAddress, now.Add(-24*time.Hour), now.AddDate(0, 0, -7)); err != nil {
+		return nil, fmt.Errorf("failed to get swap volume: %w", err)
+	}
+
+	return &repositories.SwapVolumeResult{
+		SwapCount:    row.SwapCount,
+		PoolCount:    row.PoolCount,
+		TotalVolume:  row.TotalVolume,
+		SwapCount24h: row.SwapCount24h,
+		Volume24h:    row.Volume24h,
+		SwapCount7d:  row.SwapCount7d,
+		Volume7d:     row.Volume7d,
+	}, nil
+}
@@ -0,0 +1,197 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteTenantRepo implements TenantRepository
+var _ repositories.TenantRepository = (*SQLiteTenantRepo)(nil)
+
+// SQLiteTenantRepo implements TenantRepository using SQLite
+type SQLiteTenantRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteTenantRepo creates a new SQLite tenant repository
+func NewSQLiteTenantRepo(db *sqlx.DB) *SQLiteTenantRepo {
+	return &SQLiteTenantRepo{db: db}
+}
+
+// Create inserts a tenant with its tokens
+func (r *SQLiteTenantRepo) Create(ctx context.Context, tenant *entities.Tenant) error {
+	ctx = withQueryName(ctx, "tenants.Create")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO tenants (name, rate_limit_rps)
+		VALUES (?1, ?2)
+		RETURNING id, created_at
+	`
+	row := tx.QueryRowxContext(ctx, query, tenant.Name, tenant.RateLimitRPS)
+	if err := row.Scan(&tenant.ID, &tenant.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	if err := sqliteInsertTenantTokens(ctx, tx, tenant.ID, tenant.Tokens); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a tenant with its tokens
+func (r *SQLiteTenantRepo) GetByID(ctx context.Context, id int64) (*entities.Tenant, error) {
+	ctx = withQueryName(ctx, "tenants.GetByID")
+
+	query := `SELECT id, name, rate_limit_rps, created_at FROM tenants WHERE id = ?1`
+	return r.get(ctx, query, id)
+}
+
+// GetByAPIKey retrieves the tenant owning an API key digest
+func (r *SQLiteTenantRepo) GetByAPIKey(ctx context.Context, keyHash string) (*entities.Tenant, error) {
+	ctx = withQueryName(ctx, "tenants.GetByAPIKey")
+
+	query := `
+		SELECT t.id, t.name, t.rate_limit_rps, t.created_at
+		FROM tenants t
+		JOIN tenant_api_keys k ON k.tenant_id = t.id
+		WHERE k.key_hash = ?1
+	`
+	return r.get(ctx, query, keyHash)
+}
+
+// get reads one tenant with query and loads its tokens
+func (r *SQLiteTenantRepo) get(ctx context.Context, query string, arg interface{}) (*entities.Tenant, error) {
+	var tenant entities.Tenant
+	if err := r.db.GetContext(ctx, &tenant, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	tokenQuery := `SELECT token_address FROM tenant_tokens WHERE tenant_id = ?1 ORDER BY token_address`
+	if err := r.db.SelectContext(ctx, &tenant.Tokens, tokenQuery, tenant.ID); err != nil {
+		return nil, fmt.Errorf("failed to get tenant tokens: %w", err)
+	}
+
+	return &tenant, nil
+}
+
+// AddAPIKey stores an API key digest for a tenant
+func (r *SQLiteTenantRepo) AddAPIKey(ctx context.Context, id int64, keyHash string) error {
+	ctx = withQueryName(ctx, "tenants.AddAPIKey")
+
+	query := `INSERT INTO tenant_api_keys (key_hash, tenant_id) VALUES (?1, ?2)`
+	if _, err := r.db.ExecContext(ctx, query, keyHash, id); err != nil {
+		return fmt.Errorf("failed to add tenant API key: %w", err)
+	}
+
+	return nil
+}
+
+// SetTokens replaces a tenant's token set
+func (r *SQLiteTenantRepo) SetTokens(ctx context.Context, id int64, tokens []string) (bool, error) {
+	ctx = withQueryName(ctx, "tenants.SetTokens")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var found bool
+	if err := tx.GetContext(ctx, &found, `SELECT EXISTS (SELECT 1 FROM tenants WHERE id = ?1)`, id); err != nil {
+		return false, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	if !found {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tenant_tokens WHERE tenant_id = ?1`, id); err != nil {
+		return false, fmt.Errorf("failed to clear tenant tokens: %w", err)
+	}
+	if err := sqliteInsertTenantTokens(ctx, tx, id, tokens); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// ListTokens retrieves a page of the tenant's tokens
+func (r *SQLiteTenantRepo) ListTokens(ctx context.Context, id int64, limit, offset int, sortBy, sortOrder string, includeInactive bool, scope repositories.TokenScope) ([]*entities.Token, int64, error) {
+	ctx = withQueryName(ctx, "tenants.ListTokens")
+
+	// Validate sort column
+	if !validSortColumns[sortBy] {
+		sortBy = "total_indexed_transfers"
+	}
+
+	// Validate sort order
+	if sortOrder != "asc" && sortOrder != "desc" {
+		sortOrder = "desc"
+	}
+
+	from := `FROM tokens t JOIN tenant_tokens tt ON tt.token_address = t.address AND tt.tenant_id = ?1`
+	var conditions []string
+	if !includeInactive {
+		conditions = append(conditions, "t.active")
+	}
+	conditions, args := sqliteTokenScopeConditions("t.address", scope, conditions, []interface{}{id})
+	if len(conditions) > 0 {
+		from += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) `+from, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count tenant tokens: %w", err)
+	}
+
+	query := fmt.Sprintf(`SELECT t.* %s ORDER BY t.%s %s LIMIT ?%d OFFSET ?%d`, from, sortBy, sortOrder, len(args)+1, len(args)+2)
+	var tokens []*entities.Token
+	if err := r.db.SelectContext(ctx, &tokens, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to get tenant tokens: %w", err)
+	}
+
+	return tokens, total, nil
+}
+
+func sqliteInsertTenantTokens(ctx context.Context, tx *sqlx.Tx, id int64, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO tenant_tokens (tenant_id, token_address)
+		SELECT ?1, value FROM json_each(?2)
+		WHERE true -- keeps SQLite from reading ON CONFLICT as part of the SELECT
+		ON CONFLICT (tenant_id, token_address) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, id, sqliteList(tokens)); err != nil {
+		return fmt.Errorf("failed to insert tenant tokens: %w", err)
+	}
+
+	return nil
+}
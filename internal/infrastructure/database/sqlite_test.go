@@ -0,0 +1,1166 @@
+//go:build cgo
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+// ether returns n whole tokens of 18 decimals, past what SQLite's own SUM can hold
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+}
+
+// openSQLite opens an in-memory SQLite store with the same holders as seedHolders:
+// Bob 600, Alice 400, Charlie 0 and the zero address -1000
+func openSQLite(t *testing.T) *Store {
+	t.Helper()
+	ctx := context.Background()
+
+	db, err := NewSQLiteDB(config.DatabaseConfig{Path: ":memory:"}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewSQLiteStore(db)
+	t.Cleanup(func() { _ = store.Close() })
+
+	if err := store.Tokens.Upsert(ctx, testutil.CreateTestToken()); err != nil {
+		t.Fatal(err)
+	}
+	transfers := []entities.Transfer{
+		testutil.CreateTestTransfer(testutil.WithLogIndex(0), testutil.WithBlockNumber(100),
+			testutil.WithFromAddress(entities.ZeroAddress), testutil.WithToAddress(testutil.AliceAddress), testutil.WithValue(ether(1000))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(1), testutil.WithBlockNumber(101),
+			testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.BobAddress), testutil.WithValue(ether(400))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(2), testutil.WithBlockNumber(102),
+			testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.CharlieAddr), testutil.WithValue(ether(200))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(3), testutil.WithBlockNumber(103),
+			testutil.WithFromAddress(testutil.CharlieAddr), testutil.WithToAddress(testutil.BobAddress), testutil.WithValue(ether(200))),
+	}
+	if _, err := store.Transfers.BatchInsert(ctx, transfers); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestSQLiteStore_SchemaVersion(t *testing.T) {
+	store := openSQLite(t)
+
+	version, dirty, err := store.SchemaVersion(context.Background())
+	if err != nil || version != SchemaVersion || dirty {
+		t.Errorf("expected schema version %d, got %d dirty=%v (%v)", SchemaVersion, version, dirty, err)
+	}
+	if err := store.HealthCheck(context.Background()); err != nil {
+		t.Errorf("health check failed: %v", err)
+	}
+}
+
+func TestSQLiteStore_Filter(t *testing.T) {
+	store := openSQLite(t)
+	repo := store.Transfers
+	ctx := context.Background()
+
+	// Duplicates are skipped by the unique key and left out of the inserted count
+	inserted, err := repo.BatchInsert(ctx, []entities.Transfer{testutil.CreateTestTransfer(testutil.WithLogIndex(1))})
+	if err != nil || inserted != 0 {
+		t.Fatalf("expected the duplicate to be skipped, got %d inserted (%v)", inserted, err)
+	}
+
+	token := testutil.USDTAddress
+	count, err := repo.GetCount(ctx, entities.TransferFilter{TokenAddress: &token})
+	if err != nil || count != 4 {
+		t.Errorf("expected 4 transfers, got %d (%v)", count, err)
+	}
+
+	// Token lists narrow feeds to, or leave out, the listed tokens
+	for filter, want := range map[*entities.TransferFilter]int64{
+		{Tokens: []string{token, testutil.USDCAddress}}: 4,
+		{Tokens: []string{testutil.USDCAddress}}:        0,
+		{ExcludeTokens: []string{token}}:                0,
+		{ExcludeTokens: []string{testutil.USDCAddress}}: 4,
+	} {
+		if count, err := repo.GetCount(ctx, *filter); err != nil || count != want {
+			t.Errorf("%+v: expected %d transfers, got %d (%v)", *filter, want, count, err)
+		}
+	}
+
+	from := testutil.AliceAddress
+	transfers, err := repo.GetByFilter(ctx, entities.TransferFilter{FromAddress: &from, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 2 || transfers[0].LogIndex != 2 || transfers[1].LogIndex != 1 {
+		t.Fatalf("expected Alice's transfers newest first, got %+v", transfers)
+	}
+	if transfers[1].ValueString != ether(400).String() || !transfers[1].BlockTimestamp.Equal(testutil.CreateTestTransfer().BlockTimestamp) {
+		t.Errorf("unexpected transfer: %+v", transfers[1])
+	}
+
+	// Value bounds compare as numbers, not text
+	transfers, err = repo.GetByFilter(ctx, entities.TransferFilter{MinValue: ether(300), Limit: 10})
+	if err != nil || len(transfers) != 2 {
+		t.Errorf("expected the 1000 and 400 transfers, got %+v (%v)", transfers, err)
+	}
+
+	cursor := entities.CursorAt(transfers[0])
+	rest, err := repo.GetByFilter(ctx, entities.TransferFilter{After: &cursor, Limit: 10})
+	if err != nil || len(rest) != 1 || rest[0].BlockNumber != 100 {
+		t.Errorf("expected only block 100 after the cursor, got %+v (%v)", rest, err)
+	}
+
+	latest, err := repo.GetLatestBlock(ctx, token)
+	if err != nil || latest != 103 {
+		t.Errorf("expected latest block 103, got %d (%v)", latest, err)
+	}
+}
+
+func TestSQLiteStore_Holders(t *testing.T) {
+	store := openSQLite(t)
+	repo := store.Transfers
+	ctx := context.Background()
+	token := testutil.USDTAddress
+
+	count, err := repo.GetHolderCount(ctx, token, nil)
+	if err != nil || count != 2 {
+		t.Errorf("expected 2 holders, got %d (%v)", count, err)
+	}
+
+	top, err := repo.GetTopHolders(ctx, token, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 ||
+		top[0].Address != testutil.BobAddress || top[0].Balance != ether(600).String() || top[0].Rank != 1 ||
+		top[1].Address != testutil.AliceAddress || top[1].Balance != ether(400).String() || top[1].Rank != 2 {
+		t.Errorf("unexpected top holders: %+v", top)
+	}
+
+	excluded, err := repo.GetTopHoldersWithOffset(ctx, token, 10, 0, []string{testutil.BobAddress})
+	if err != nil || len(excluded) != 1 || excluded[0].Address != testutil.AliceAddress || excluded[0].Rank != 1 {
+		t.Errorf("unexpected holders excluding bob: %+v (%v)", excluded, err)
+	}
+
+	holder, err := repo.GetHolderBalance(ctx, token, testutil.CharlieAddr)
+	if err != nil || holder.Balance != "0" || holder.Rank != 3 {
+		t.Errorf("expected Charlie at 0 ranked 3, got %+v (%v)", holder, err)
+	}
+
+	since := testutil.CreateTestTransfer().BlockTimestamp
+	changes, err := repo.GetBalanceChanges(ctx, token, since, 10)
+	if err != nil || len(changes) != 2 || changes[0].Address != testutil.BobAddress || changes[0].Change != ether(600).String() {
+		t.Errorf("unexpected balance changes: %+v (%v)", changes, err)
+	}
+}
+
+func TestSQLiteStore_NegativeBalances(t *testing.T) {
+	store := openSQLite(t)
+	repo := store.Transfers
+	ctx := context.Background()
+	token := testutil.USDTAddress
+
+	// The zero address is minus the supply and is not reported
+	negative, err := repo.GetNegativeBalances(ctx, token, 10)
+	if err != nil || negative.Count != 0 || len(negative.Worst) != 0 {
+		t.Fatalf("expected no negative balances, got %+v (%v)", negative, err)
+	}
+
+	// Spends from addresses whose incoming transfers are missing
+	dave := "0x4444444444444444444444444444444444444444"
+	eve := "0x5555555555555555555555555555555555555555"
+	transfers := []entities.Transfer{
+		testutil.CreateTestTransfer(testutil.WithLogIndex(4), testutil.WithBlockNumber(104),
+			testutil.WithFromAddress(dave), testutil.WithToAddress(testutil.AliceAddress), testutil.WithValue(ether(5))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(5), testutil.WithBlockNumber(105),
+			testutil.WithFromAddress(eve), testutil.WithToAddress(testutil.AliceAddress), testutil.WithValue(ether(50))),
+	}
+	if _, err := repo.BatchInsert(ctx, transfers); err != nil {
+		t.Fatal(err)
+	}
+
+	negative, err = repo.GetNegativeBalances(ctx, token, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if negative.Count != 2 || len(negative.Worst) != 1 ||
+		negative.Worst[0].Address != eve || negative.Worst[0].Balance != "-"+ether(50).String() || negative.Worst[0].Rank != 1 {
+		t.Errorf("expected 2 negative balances with eve worst, got %+v", negative)
+	}
+}
+
+func TestSQLiteStore_HolderHistory(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+
+	var events []repositories.HolderBalanceEvent
+	collect := func(e repositories.HolderBalanceEvent) error {
+		events = append(events, e)
+		return nil
+	}
+
+	if err := store.Transfers.StreamHolderHistory(ctx, testutil.USDTAddress, testutil.AliceAddress,
+		repositories.HolderHistoryFilter{Limit: 10}, collect); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{ether(1000).String(), ether(600).String(), ether(400).String()}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, balance := range want {
+		if events[i].Balance != balance {
+			t.Errorf("event %d: expected balance %s, got %+v", i, balance, events[i])
+		}
+	}
+
+	// Pages keep the balance accumulated over earlier history
+	events = nil
+	if err := store.Transfers.StreamHolderHistory(ctx, testutil.USDTAddress, testutil.AliceAddress,
+		repositories.HolderHistoryFilter{Limit: 1, Offset: 2}, collect); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Balance != ether(400).String() || events[0].BlockNumber != 102 {
+		t.Errorf("unexpected page: %+v", events)
+	}
+}
+
+func TestSQLiteStore_Portfolio(t *testing.T) {
+	store := openSQLite(t)
+	repo := store.Portfolio
+	ctx := context.Background()
+
+	holdings, err := repo.GetWalletHoldings(ctx, testutil.BobAddress)
+	if err != nil || len(holdings) != 1 || holdings[0].BalanceStr != ether(600).String() {
+		t.Errorf("unexpected holdings: %+v (%v)", holdings, err)
+	}
+
+	summary, err := repo.GetWalletTransferSummary(ctx, testutil.AliceAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalTransfersIn != 1 || summary.TotalTransfersOut != 2 ||
+		summary.TotalVolumeOut != ether(600).String() || summary.FirstTransferAt == nil {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	seeded := testutil.CreateTestTransfer().BlockTimestamp
+	balances, err := repo.GetWalletBalancesAt(ctx, testutil.AliceAddress, seeded.Add(time.Second))
+	if err != nil || len(balances) != 1 || balances[0].BalanceStr != ether(400).String() {
+		t.Errorf("expected Alice's balance after the transfers, got %+v (%v)", balances, err)
+	}
+
+	volumes, err := repo.GetEntityTokenVolumes(ctx, []string{testutil.AliceAddress, testutil.CharlieAddr})
+	if err != nil || len(volumes) != 1 || volumes[0].InternalVolume != ether(200).String() || volumes[0].VolumeOut != ether(600).String() {
+		t.Errorf("unexpected volumes: %+v (%v)", volumes, err)
+	}
+
+	// Alice to Charlie is read once, as an outgoing transfer
+	flows, err := repo.GetCounterpartyFlows(ctx, []string{testutil.AliceAddress, testutil.CharlieAddr}, 100, 10)
+	if err != nil || len(flows) != 4 || flows[0].FromAddress != entities.ZeroAddress || flows[0].Volume != ether(1000).String() ||
+		!flows[0].LastTransferAt.Equal(seeded) || flows[0].TokenSymbol != "USDT" {
+		t.Errorf("unexpected counterparty flows: %+v (%v)", flows, err)
+	}
+	if flows, err := repo.GetCounterpartyFlows(ctx, []string{testutil.AliceAddress}, 100, 2); err != nil || len(flows) != 2 {
+		t.Errorf("expected 2 flows, got %+v (%v)", flows, err)
+	}
+}
+
+func TestSQLiteStore_HolderSnapshots(t *testing.T) {
+	store := openSQLite(t)
+	repo := store.HolderSnapshots
+	ctx := context.Background()
+	token := testutil.USDTAddress
+
+	// Times in another zone are stored, compared and returned as UTC
+	day := time.Date(2024, 1, 15, 7, 0, 0, 0, time.FixedZone("UTC+7", 7*3600))
+	for i, holder := range []string{testutil.AliceAddress, testutil.BobAddress, testutil.CharlieAddr} {
+		holders := []repositories.HolderBalance{{Address: holder, Balance: ether(int64(i + 1)).String(), Rank: 1}}
+		if err := repo.Save(ctx, token, day.Add(time.Duration(i)*time.Hour), holders); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		at     time.Time
+		holder string
+	}{
+		{day.Add(90 * time.Minute), testutil.BobAddress},
+		{day.Add(-time.Hour), testutil.AliceAddress},
+		{day.Add(24 * time.Hour), testutil.CharlieAddr},
+	} {
+		snapshot, err := repo.GetAt(ctx, token, tc.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snapshot == nil || len(snapshot.Holders) != 1 || snapshot.Holders[0].Address != tc.holder {
+			t.Errorf("at %v: expected %s, got %+v", tc.at, tc.holder, snapshot)
+		}
+	}
+
+	deleted, err := repo.DeleteBefore(ctx, day.Add(time.Hour))
+	if err != nil || deleted != 1 {
+		t.Errorf("expected 1 deleted row, got %d (%v)", deleted, err)
+	}
+}
+
+func TestSQLiteStore_HolderCounts(t *testing.T) {
+	store := openSQLite(t)
+	repo := store.HolderCounts
+	ctx := context.Background()
+
+	if count, err := repo.Get(ctx, testutil.USDTAddress); err != nil || count != nil {
+		t.Fatalf("expected no recorded count, got %+v (%v)", count, err)
+	}
+
+	// A later count replaces the earlier one
+	day := time.Date(2024, 1, 15, 7, 0, 0, 0, time.FixedZone("UTC+7", 7*3600))
+	for i, n := range []int64{10, 12} {
+		if err := repo.Save(ctx, testutil.USDTAddress, n, day.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := repo.Get(ctx, testutil.USDTAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count == nil || count.Count != 12 || !count.CountedAt.Equal(day.Add(time.Hour)) {
+		t.Errorf("expected 12 holders counted at %v, got %+v", day.Add(time.Hour), count)
+	}
+}
+
+func TestSQLiteStore_AdvancedStats(t *testing.T) {
+	store := openSQLite(t)
+	repo := store.AdvancedStats
+	ctx := context.Background()
+
+	if stats, err := repo.Get(ctx, testutil.USDTAddress); err != nil || stats != nil {
+		t.Fatalf("expected no advanced stats, got %+v (%v)", stats, err)
+	}
+
+	computedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for _, volume := range []string{"1", ether(3).String()} {
+		err := repo.Save(ctx, repositories.AdvancedStats{
+			TokenAddress:      testutil.USDTAddress,
+			WindowFrom:        computedAt.AddDate(0, 0, -30),
+			WindowTo:          computedAt,
+			Volume:            volume,
+			AverageSupply:     ether(2).String(),
+			CirculatingSupply: ether(2).String(),
+			InactiveSince:     computedAt.AddDate(-1, 0, 0),
+			DormantSupply:     ether(1).String(),
+			DormantHolders:    4,
+			SelfTransfers:     2,
+			FanOutVolume:      ether(5).String(),
+			ComputedAt:        computedAt,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := repo.Get(ctx, testutil.USDTAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats == nil || stats.Volume != ether(3).String() || stats.DormantHolders != 4 || !stats.WindowFrom.Equal(computedAt.AddDate(0, 0, -30)) ||
+		stats.SelfTransfers != 2 || stats.FanOutVolume != ether(5).String() {
+		t.Errorf("expected the last saved stats, got %+v", stats)
+	}
+}
+
+func TestSQLiteStore_TransferFlags(t *testing.T) {
+	store := openSQLite(t)
+	repo := store.TransferFlags
+	ctx := context.Background()
+
+	txHash := testutil.CreateTestTransfer().TxHash
+	flags := []entities.TransferFlag{
+		{TxHash: txHash, LogIndex: 1, Flag: entities.TransferFlagWashTrade, TokenAddress: testutil.USDTAddress},
+		{TxHash: txHash, LogIndex: 3, Flag: entities.TransferFlagWashTrade, TokenAddress: testutil.USDTAddress},
+	}
+	for i, want := range []int64{2, 0} {
+		added, err := repo.Add(ctx, flags)
+		if err != nil || added != want {
+			t.Fatalf("pass %d: expected %d flags added, got %d (%v)", i, want, added, err)
+		}
+	}
+
+	stored, err := repo.GetByTxHashes(ctx, []string{txHash, "0xother"})
+	if err != nil || len(stored) != 2 || stored[0].FlaggedAt.IsZero() {
+		t.Fatalf("expected both flags with their time, got %+v (%v)", stored, err)
+	}
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	stats, err := repo.GetFlaggedStats(ctx, testutil.USDTAddress, from, from.AddDate(0, 0, 1))
+	if err != nil || stats.Transfers != 2 || stats.Volume != ether(600).String() {
+		t.Errorf("expected 2 flagged transfers of 600, got %+v (%v)", stats, err)
+	}
+	stats, err = repo.GetFlaggedStats(ctx, testutil.USDTAddress, from.AddDate(0, 0, 1), from.AddDate(0, 0, 2))
+	if err != nil || stats.Transfers != 0 {
+		t.Errorf("expected no flagged transfers outside the window, got %+v (%v)", stats, err)
+	}
+}
+
+func TestSQLiteStore_FailedRanges(t *testing.T) {
+	store := openSQLite(t)
+	repo := store.FailedRanges
+	ctx := context.Background()
+
+	r := entities.FailedRange{TokenAddress: testutil.USDTAddress, FromBlock: 901, ToBlock: 1000, Attempts: 5, LastError: "timeout"}
+	if err := repo.Record(ctx, r); err != nil {
+		t.Fatalf("failed to record range: %v", err)
+	}
+	r.Attempts, r.LastError, r.Skipped = 6, "value out of range", true
+	if err := repo.Record(ctx, r); err != nil {
+		t.Fatalf("failed to record range again: %v", err)
+	}
+
+	ranges, err := repo.List(ctx, true)
+	if err != nil || len(ranges) != 1 {
+		t.Fatalf("expected one unresolved range, got %+v (%v)", ranges, err)
+	}
+	got := ranges[0]
+	if got.Attempts != 6 || got.LastError != "value out of range" || !got.Skipped || got.FirstFailedAt.IsZero() || got.ResolvedAt != nil {
+		t.Errorf("expected the record updated in place, got %+v", got)
+	}
+
+	for i, want := range []bool{true, false} {
+		if found, err := repo.Resolve(ctx, got.ID); err != nil || found != want {
+			t.Fatalf("resolve %d: expected found=%v, got %v (%v)", i, want, found, err)
+		}
+	}
+
+	// A resolved range failing again is recorded anew
+	if err := repo.Record(ctx, r); err != nil {
+		t.Fatalf("failed to record range after resolving: %v", err)
+	}
+	unresolved, _ := repo.List(ctx, true)
+	all, err := repo.List(ctx, false)
+	if err != nil || len(unresolved) != 1 || len(all) != 2 || all[0].ResolvedAt == nil {
+		t.Errorf("expected one resolved and one new range, got %+v and %+v (%v)", unresolved, all, err)
+	}
+}
+
+func TestSQLiteStore_WalletStats(t *testing.T) {
+	store := openSQLite(t)
+	repo := store.WalletStats
+	ctx := context.Background()
+	wallets := []string{entities.ZeroAddress, testutil.AliceAddress, testutil.BobAddress, testutil.CharlieAddr}
+
+	// Every wallet's totals must match the scan of its transfers
+	assertMatchesScan := func(step string) {
+		t.Helper()
+		for _, wallet := range wallets {
+			want, err := store.Portfolio.GetWalletTransferSummary(ctx, wallet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, found, err := repo.GetSummary(ctx, wallet)
+			if err != nil || found != (want.UniqueTokens > 0) {
+				t.Fatalf("%s: expected found=%v for %s, got %v (%v)", step, want.UniqueTokens > 0, wallet, found, err)
+			}
+			if !found {
+				continue
+			}
+			if got.TotalTransfersIn != want.TotalTransfersIn || got.TotalTransfersOut != want.TotalTransfersOut ||
+				got.TotalVolumeIn != want.TotalVolumeIn || got.TotalVolumeOut != want.TotalVolumeOut ||
+				got.UniqueTokens != want.UniqueTokens ||
+				!got.FirstTransferAt.Equal(*want.FirstTransferAt) || !got.LastTransferAt.Equal(*want.LastTransferAt) {
+				t.Errorf("%s: %s has totals %+v, the scan gives %+v", step, wallet, got, want)
+			}
+		}
+	}
+
+	if _, found, err := repo.GetSummary(ctx, testutil.AliceAddress); err != nil || found {
+		t.Fatalf("expected no totals before the rebuild, got found=%v (%v)", found, err)
+	}
+	if err := repo.Rebuild(ctx, testutil.USDTAddress); err != nil {
+		t.Fatalf("failed to rebuild: %v", err)
+	}
+	assertMatchesScan("rebuild")
+
+	seeded := testutil.CreateTestTransfer().BlockTimestamp
+	added := []entities.Transfer{
+		testutil.CreateTestTransfer(testutil.WithLogIndex(4), testutil.WithBlockNumber(104), testutil.WithBlockTimestamp(seeded.Add(time.Hour)),
+			testutil.WithFromAddress(testutil.BobAddress), testutil.WithToAddress(testutil.AliceAddress), testutil.WithValue(ether(100))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(5), testutil.WithBlockNumber(104), testutil.WithBlockTimestamp(seeded.Add(time.Hour)),
+			testutil.WithFromAddress(testutil.BobAddress), testutil.WithToAddress(testutil.BobAddress), testutil.WithValue(ether(50))),
+	}
+	if _, err := store.Transfers.BatchInsert(ctx, added); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Apply(ctx, added); err != nil {
+		t.Fatalf("failed to apply transfers: %v", err)
+	}
+	assertMatchesScan("apply")
+
+	// Removing Charlie to Bob leaves Charlie with Alice's transfer only
+	replaced, err := repo.GetRangeWallets(ctx, testutil.USDTAddress, 103, 103)
+	if err != nil || len(replaced) != 2 {
+		t.Fatalf("expected Charlie and Bob in block 103, got %v (%v)", replaced, err)
+	}
+	if _, _, err := store.Transfers.ReplaceRange(ctx, testutil.USDTAddress, 103, 103, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Refresh(ctx, testutil.USDTAddress, replaced); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	assertMatchesScan("refresh")
+}
+
+func TestSQLiteStore_AlertRulesAndDenyList(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+
+	rule := &entities.AlertRule{
+		Name:           "whales",
+		Direction:      "in",
+		MinValue:       ether(100),
+		Counterparties: []string{testutil.AliceAddress},
+		Channel:        "webhook",
+		Target:         "https://example.com/hook",
+	}
+	if err := store.AlertRules.Create(ctx, rule); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.AlertRules.GetByID(ctx, rule.ID)
+	if err != nil || got == nil || got.MinValue.Cmp(ether(100)) != 0 ||
+		len(got.Counterparties) != 1 || got.Counterparties[0] != testutil.AliceAddress {
+		t.Errorf("unexpected rule: %+v (%v)", got, err)
+	}
+
+	entries := []entities.DenyListEntry{{Address: testutil.AliceAddress}, {Address: testutil.BobAddress, Label: "b"}}
+	if err := store.DenyList.ReplaceList(ctx, "ofac", entries); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DenyList.ReplaceList(ctx, "ofac", entries[1:]); err != nil {
+		t.Fatal(err)
+	}
+	found, err := store.DenyList.FindByAddresses(ctx, []string{testutil.AliceAddress, testutil.BobAddress})
+	if err != nil || len(found) != 1 || found[0].Address != testutil.BobAddress || found[0].Label != "b" {
+		t.Errorf("expected only Bob left on the list, got %+v (%v)", found, err)
+	}
+}
+
+func TestBigKey(t *testing.T) {
+	values := []string{"-" + ether(1000).String(), "-5", "0", "7", "10", ether(600).String()}
+	for i := 1; i < len(values); i++ {
+		if bigKey(values[i-1]) >= bigKey(values[i]) {
+			t.Errorf("expected key of %s below key of %s", values[i-1], values[i])
+		}
+	}
+}
+
+func TestSQLiteStore_Stats(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+	token := testutil.USDTAddress
+	seeded := testutil.CreateTestTransfer().BlockTimestamp
+	day := seeded.Truncate(24 * time.Hour)
+
+	stats, err := store.Transfers.GetTokenStats(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TotalTransfers != 4 || stats.TotalVolume != ether(1800).String() ||
+		stats.FirstTransferAt == nil || !stats.FirstTransferAt.Equal(seeded) {
+		t.Errorf("unexpected token stats: %+v", stats)
+	}
+
+	window, err := store.Transfers.GetWindowStats(ctx, token, day, day.Add(24*time.Hour))
+	if err != nil || window.Transfers != 4 || window.Volume != ether(1800).String() {
+		t.Errorf("unexpected window stats: %+v (%v)", window, err)
+	}
+
+	heatmap, err := store.Transfers.GetActivityHeatmap(ctx, token, day, day.Add(24*time.Hour))
+	if err != nil || len(heatmap) != 1 || heatmap[0].DayOfWeek != int(seeded.Weekday()) || heatmap[0].Hour != 10 || heatmap[0].Transfers != 4 {
+		t.Errorf("unexpected heatmap: %+v (%v)", heatmap, err)
+	}
+
+	active, err := store.Transfers.GetActiveAddresses(ctx, token, day, day.Add(24*time.Hour))
+	if err != nil || len(active) != 3 {
+		t.Errorf("expected 3 active addresses besides the zero address, got %+v (%v)", active, err)
+	}
+
+	supply, err := store.Transfers.GetCirculatingSupply(ctx, token)
+	if err != nil || supply != ether(1000).String() {
+		t.Errorf("expected circulating supply %s, got %s (%v)", ether(1000), supply, err)
+	}
+	if before, err := store.Transfers.GetCirculatingSupplyAt(ctx, token, seeded); err != nil || before != "0" {
+		t.Errorf("expected no supply before the mint, got %s (%v)", before, err)
+	}
+
+	// Every holder last moved funds at seeded; Charlie's zero balance is not dormant supply
+	dormant, err := store.Transfers.GetDormantSupply(ctx, token, seeded.Add(time.Second))
+	if err != nil || dormant.Balance != ether(1000).String() || dormant.Holders != 2 {
+		t.Errorf("expected Alice's and Bob's %s dormant, got %+v (%v)", ether(1000), dormant, err)
+	}
+	if dormant, err := store.Transfers.GetDormantSupply(ctx, token, seeded); err != nil || dormant.Balance != "0" || dormant.Holders != 0 {
+		t.Errorf("expected nothing dormant, got %+v (%v)", dormant, err)
+	}
+
+	// The seeded transfers share a transaction, in which Alice sends two
+	patterns, err := store.Transfers.GetTransferPatterns(ctx, token, day, day.Add(24*time.Hour), 1)
+	if err != nil || patterns.SelfTransfers != 0 || patterns.SelfTransferVolume != "0" ||
+		patterns.FanOutTransactions != 1 || patterns.FanOutTransfers != 2 || patterns.FanOutVolume != ether(600).String() {
+		t.Errorf("expected Alice's two transfers as a fan-out, got %+v (%v)", patterns, err)
+	}
+	if patterns, err := store.Transfers.GetTransferPatterns(ctx, token, day, day.Add(24*time.Hour), 2); err != nil || patterns.FanOutTransactions != 0 || patterns.FanOutVolume != "0" {
+		t.Errorf("expected no fan-outs above 2 transfers, got %+v (%v)", patterns, err)
+	}
+
+	if err := store.DailyStats.RefreshDays(ctx, token, day, day); err != nil {
+		t.Fatal(err)
+	}
+	days, err := store.DailyStats.GetRange(ctx, token, day, day)
+	if err != nil || len(days) != 1 || !days[0].Day.Equal(day) || days[0].Transfers != 4 || days[0].Volume != ether(1800).String() {
+		t.Errorf("unexpected daily stats: %+v (%v)", days, err)
+	}
+	if days[0].NewHolders != 3 || days[0].UniqueReceivers != 3 {
+		t.Errorf("expected 3 first-time receivers, got %+v", days[0])
+	}
+
+	// Bob's second receipt does not move his first one
+	newest, err := store.DailyStats.GetNewHolders(ctx, token, day, 2)
+	if err != nil || len(newest) != 2 || newest[0].Address != testutil.CharlieAddr || newest[1].Address != testutil.BobAddress ||
+		newest[1].FirstBlock != 101 || !newest[1].FirstSeenAt.Equal(seeded) {
+		t.Errorf("expected Charlie then Bob as newest holders, got %+v (%v)", newest, err)
+	}
+	if later, err := store.DailyStats.GetNewHolders(ctx, token, day.AddDate(0, 0, 1), 10); err != nil || len(later) != 0 {
+		t.Errorf("expected no holders first seen after the day, got %+v (%v)", later, err)
+	}
+
+	// Refreshing again replaces the day's first receipts rather than adding to them
+	if err := store.DailyStats.RefreshDays(ctx, token, day, day); err != nil {
+		t.Fatal(err)
+	}
+	if all, err := store.DailyStats.GetNewHolders(ctx, token, day, 10); err != nil || len(all) != 3 {
+		t.Errorf("expected 3 first seen holders after a refresh, got %+v (%v)", all, err)
+	}
+
+	changes, err := store.Portfolio.GetWalletDailyBalanceChanges(ctx, testutil.AliceAddress, day)
+	if err != nil || len(changes) != 1 || !changes[0].Day.Equal(day) || changes[0].Change != ether(400).String() {
+		t.Errorf("unexpected daily balance changes: %+v (%v)", changes, err)
+	}
+
+	activity, err := store.Portfolio.GetWalletTokenActivity(ctx, testutil.CharlieAddr)
+	if err != nil || len(activity) != 1 || activity[0].Balance != "0" || !activity[0].FirstActivityAt.Equal(seeded) {
+		t.Errorf("unexpected activity: %+v (%v)", activity, err)
+	}
+}
+
+func TestSQLiteStore_Tokens(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+	token := testutil.USDTAddress
+
+	if err := store.Tokens.UpdateStats(ctx, token, 4, 103); err != nil {
+		t.Fatal(err)
+	}
+	tokens, total, err := store.Tokens.GetAllPaginated(ctx, 10, 0, "total_transfers", "desc", false, repositories.TokenScope{})
+	if err != nil || total != 1 || len(tokens) != 1 || tokens[0].LastSeenBlock == nil || *tokens[0].LastSeenBlock != 103 {
+		t.Errorf("unexpected tokens: %+v total=%d (%v)", tokens, total, err)
+	}
+	if _, total, err := store.Tokens.GetAllPaginated(ctx, 10, 0, "", "", false, repositories.TokenScope{Only: []string{token, testutil.USDCAddress}}); err != nil || total != 1 {
+		t.Errorf("expected the token within the scope, got %d (%v)", total, err)
+	}
+	if tokens, total, err := store.Tokens.GetAllPaginated(ctx, 10, 0, "", "", false, repositories.TokenScope{Except: []string{token}}); err != nil || total != 0 || len(tokens) != 0 {
+		t.Errorf("expected the excepted token to be left out, got %d (%v)", total, err)
+	}
+
+	if changed, err := store.Tokens.SetActive(ctx, token, false); err != nil || !changed {
+		t.Errorf("expected the token to be deactivated, got %v (%v)", changed, err)
+	}
+	if _, total, err := store.Tokens.GetAllPaginated(ctx, 10, 0, "", "", false, repositories.TokenScope{}); err != nil || total != 0 {
+		t.Errorf("expected no active tokens, got %d (%v)", total, err)
+	}
+
+	if err := store.IndexerState.UpdateLastBlock(ctx, token, 103); err != nil {
+		t.Fatal(err)
+	}
+	from, to := int64(50), int64(99)
+	if err := store.IndexerState.SetBackfilling(ctx, token, true, &from, &to); err != nil {
+		t.Fatal(err)
+	}
+	state, err := store.IndexerState.Get(ctx, token)
+	if err != nil || state == nil || state.LastIndexedBlock != 103 || !state.IsBackfilling || *state.BackfillToBlock != 99 {
+		t.Errorf("unexpected indexer state: %+v (%v)", state, err)
+	}
+
+	// The backfill checkpoint survives re-marking the same range and is cleared by another one
+	if err := store.IndexerState.UpdateBackfillCheckpoint(ctx, token, 74); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.IndexerState.SetBackfilling(ctx, token, false, &from, &to); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := store.IndexerState.Get(ctx, token); state.BackfillCheckpoint == nil || *state.BackfillCheckpoint != 74 {
+		t.Errorf("expected checkpoint 74 to be kept, got %v", state.BackfillCheckpoint)
+	}
+	if err := store.IndexerState.SetBackfilling(ctx, token, false, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := store.IndexerState.Get(ctx, token); state.BackfillCheckpoint != nil {
+		t.Errorf("expected the checkpoint to be cleared, got %d", *state.BackfillCheckpoint)
+	}
+
+	if oldest, err := store.IndexerState.GetOldestCheckpointTime(ctx); err != nil || oldest != nil {
+		t.Errorf("expected no checkpoint time for a deactivated token, got %v (%v)", oldest, err)
+	}
+	if _, err := store.Tokens.SetActive(ctx, token, true); err != nil {
+		t.Fatal(err)
+	}
+	if oldest, err := store.IndexerState.GetOldestCheckpointTime(ctx); err != nil || oldest == nil || time.Since(*oldest) > time.Minute {
+		t.Errorf("expected the checkpoint just written, got %v (%v)", oldest, err)
+	}
+
+	replacement := testutil.CreateTestTransfer(testutil.WithLogIndex(7), testutil.WithBlockNumber(102))
+	// The duplicate is skipped and not counted as inserted
+	deleted, inserted, err := store.Transfers.ReplaceRange(ctx, token, 102, 103, []entities.Transfer{replacement, replacement})
+	if err != nil || deleted != 2 || inserted != 1 {
+		t.Errorf("expected 2 deleted and 1 inserted, got %d and %d (%v)", deleted, inserted, err)
+	}
+	latest, err := store.Transfers.GetLatestID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := store.Transfers.GetAfterID(ctx, entities.TransferFilter{Limit: 10}, 0)
+	if err != nil || len(after) != 3 || after[2].ID != latest {
+		t.Errorf("expected 3 transfers in insertion order, got %+v (%v)", after, err)
+	}
+
+	// Replacing a range drops the transfer count, which recounting rederives
+	if count, err := store.Tokens.RecountTransfers(ctx, token); err != nil || count != 3 {
+		t.Errorf("expected a recount of 3, got %d (%v)", count, err)
+	}
+	if got, err := store.Tokens.GetByAddress(ctx, token); err != nil || got.TotalIndexedTransfers != 3 {
+		t.Errorf("expected 3 indexed transfers, got %+v (%v)", got, err)
+	}
+	if count, err := store.Tokens.RecountTransfers(ctx, "0x0000000000000000000000000000000000000001"); err != nil || count != 0 {
+		t.Errorf("expected no count for an unknown token, got %d (%v)", count, err)
+	}
+}
+
+func TestSQLiteStore_TokenMetadataOverride(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+	address := "0x000000000000000000000000000000000000dead"
+
+	if err := store.Tokens.Upsert(ctx, &entities.Token{Address: address, Name: "\x00garbage", Symbol: "???", Decimals: 0}); err != nil {
+		t.Fatal(err)
+	}
+	symbol, decimals := "DEAD", 6
+	found, err := store.Tokens.OverrideMetadata(ctx, address, entities.TokenMetadataOverride{Symbol: &symbol, Decimals: &decimals})
+	if err != nil || !found {
+		t.Fatalf("expected the token to be overridden, got %v (%v)", found, err)
+	}
+	if found, err := store.Tokens.OverrideMetadata(ctx, testutil.AliceAddress, entities.TokenMetadataOverride{Symbol: &symbol}); err != nil || found {
+		t.Errorf("expected no token to override, got %v (%v)", found, err)
+	}
+
+	// Re-registering the token keeps the override
+	if err := store.Tokens.Upsert(ctx, &entities.Token{Address: address, Name: "onchain", Symbol: "???", Decimals: 0}); err != nil {
+		t.Fatal(err)
+	}
+	token, err := store.Tokens.GetByAddress(ctx, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Name != "\x00garbage" || token.Symbol != "DEAD" || token.Decimals != 6 || token.MetadataSource != entities.MetadataOverride {
+		t.Errorf("unexpected token after override: %+v", token)
+	}
+}
+
+func TestSQLiteStore_TokenMetadataHistory(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+
+	change := &entities.TokenMetadataChange{
+		TokenAddress:     testutil.USDTAddress,
+		PreviousName:     "Tether USD",
+		PreviousSymbol:   "USDT",
+		PreviousDecimals: 6,
+		Name:             "Tether USD",
+		Symbol:           "USDT",
+		Decimals:         18,
+		BlockNumber:      19000000,
+	}
+	if recorded, err := store.Tokens.RecordMetadataChange(ctx, change); err != nil || !recorded || change.ID == 0 || change.DetectedAt.IsZero() {
+		t.Fatalf("expected the change recorded, got %v %+v (%v)", recorded, change, err)
+	}
+	if token, err := store.Tokens.GetByAddress(ctx, testutil.USDTAddress); err != nil || token.Decimals != 18 {
+		t.Errorf("expected the token updated, got %+v (%v)", token, err)
+	}
+
+	// Overridden metadata is kept
+	decimals := 6
+	if _, err := store.Tokens.OverrideMetadata(ctx, testutil.USDTAddress, entities.TokenMetadataOverride{Decimals: &decimals}); err != nil {
+		t.Fatal(err)
+	}
+	if recorded, err := store.Tokens.RecordMetadataChange(ctx, &entities.TokenMetadataChange{TokenAddress: testutil.USDTAddress, Decimals: 8}); err != nil || recorded {
+		t.Errorf("expected no change recorded for an overridden token, got %v (%v)", recorded, err)
+	}
+
+	history, err := store.Tokens.GetMetadataHistory(ctx, testutil.USDTAddress)
+	if err != nil || len(history) != 1 || history[0].PreviousDecimals != 6 || history[0].Decimals != 18 ||
+		history[0].BlockNumber != 19000000 || history[0].DetectedAt.IsZero() {
+		t.Errorf("unexpected metadata history: %+v (%v)", history, err)
+	}
+}
+
+func TestSQLiteStore_Tenants(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+
+	tenant := &entities.Tenant{Name: "acme", RateLimitRPS: 5, Tokens: []string{testutil.USDTAddress, testutil.USDCAddress}}
+	if err := store.Tenants.Create(ctx, tenant); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Tenants.AddAPIKey(ctx, tenant.ID, "digest"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Tenants.GetByAPIKey(ctx, "digest")
+	if err != nil || got == nil || got.ID != tenant.ID || got.RateLimitRPS != 5 || len(got.Tokens) != 2 || got.Tokens[0] != testutil.USDCAddress {
+		t.Fatalf("unexpected tenant: %+v (%v)", got, err)
+	}
+	if got, err := store.Tenants.GetByAPIKey(ctx, "other"); err != nil || got != nil {
+		t.Errorf("expected no tenant for an unknown key, got %+v (%v)", got, err)
+	}
+
+	// Only the seeded token is indexed, so it is the only one listed
+	tokens, total, err := store.Tenants.ListTokens(ctx, tenant.ID, 10, 0, "symbol", "asc", false, repositories.TokenScope{})
+	if err != nil || total != 1 || len(tokens) != 1 || tokens[0].Address != testutil.USDTAddress {
+		t.Errorf("unexpected tenant tokens: %+v total=%d (%v)", tokens, total, err)
+	}
+	if _, total, err := store.Tenants.ListTokens(ctx, tenant.ID, 10, 0, "", "", false, repositories.TokenScope{Except: []string{testutil.USDTAddress}}); err != nil || total != 0 {
+		t.Errorf("expected the excepted token to be left out, got %d (%v)", total, err)
+	}
+
+	if found, err := store.Tenants.SetTokens(ctx, tenant.ID, []string{testutil.USDCAddress}); err != nil || !found {
+		t.Fatalf("expected the tokens to be replaced, got %v (%v)", found, err)
+	}
+	if _, total, err := store.Tenants.ListTokens(ctx, tenant.ID, 10, 0, "", "", false, repositories.TokenScope{}); err != nil || total != 0 {
+		t.Errorf("expected no indexed tokens, got %d (%v)", total, err)
+	}
+	if found, err := store.Tenants.SetTokens(ctx, tenant.ID+1, nil); err != nil || found {
+		t.Errorf("expected no tenant to update, got %v (%v)", found, err)
+	}
+
+	rule := &entities.AlertRule{Name: "acme", Direction: entities.AlertDirectionAny, Channel: "webhook", Target: "https://example.com", TenantID: &tenant.ID}
+	if err := store.AlertRules.Create(ctx, rule); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.AlertRules.GetByID(ctx, rule.ID); err != nil || got.TenantID == nil || *got.TenantID != tenant.ID {
+		t.Errorf("expected the rule to keep its tenant, got %+v (%v)", got, err)
+	}
+}
+
+func TestSQLiteStore_AddressNotes(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+
+	alice, err := entities.ParseEVMAddress(testutil.AliceAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenant := &entities.Tenant{Name: "acme"}
+	if err := store.Tenants.Create(ctx, tenant); err != nil {
+		t.Fatal(err)
+	}
+
+	note := &entities.AddressNote{TenantID: tenant.ID, Address: alice, Note: "hot wallet"}
+	if err := store.AddressNotes.Put(ctx, note); err != nil {
+		t.Fatal(err)
+	}
+	if note.CreatedAt.IsZero() || note.UpdatedAt.IsZero() {
+		t.Errorf("expected the timestamps to be set, got %+v", note)
+	}
+	if err := store.AddressNotes.Put(ctx, &entities.AddressNote{TenantID: tenant.ID, Address: alice, Note: "cold wallet"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.AddressNotes.Get(ctx, tenant.ID, alice)
+	if err != nil || got == nil || got.Note != "cold wallet" {
+		t.Fatalf("expected the replaced note, got %+v (%v)", got, err)
+	}
+	if got, err := store.AddressNotes.Get(ctx, tenant.ID+1, alice); err != nil || got != nil {
+		t.Errorf("expected no note for another tenant, got %+v (%v)", got, err)
+	}
+
+	notes, err := store.AddressNotes.GetByAddresses(ctx, tenant.ID, []string{testutil.AliceAddress, testutil.BobAddress})
+	if err != nil || len(notes) != 1 || notes[0].Address != alice {
+		t.Errorf("unexpected notes: %+v (%v)", notes, err)
+	}
+
+	if deleted, err := store.AddressNotes.Delete(ctx, tenant.ID, alice); err != nil || !deleted {
+		t.Errorf("expected the note to be deleted, got %v (%v)", deleted, err)
+	}
+	if deleted, err := store.AddressNotes.Delete(ctx, tenant.ID, alice); err != nil || deleted {
+		t.Errorf("expected nothing left to delete, got %v (%v)", deleted, err)
+	}
+}
+
+func TestSQLiteStore_Lists(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+
+	watchlist := &entities.Watchlist{Name: "team", Addresses: []string{testutil.AliceAddress}}
+	if err := store.Watchlists.Create(ctx, watchlist); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Watchlists.AddAddresses(ctx, watchlist.ID, []string{testutil.AliceAddress, testutil.BobAddress}); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := store.Watchlists.RemoveAddress(ctx, watchlist.ID, testutil.AliceAddress); err != nil || !removed {
+		t.Errorf("expected Alice removed, got %v (%v)", removed, err)
+	}
+	got, err := store.Watchlists.GetByID(ctx, watchlist.ID)
+	if err != nil || got == nil || len(got.Addresses) != 1 || got.Addresses[0] != testutil.BobAddress {
+		t.Errorf("unexpected watchlist: %+v (%v)", got, err)
+	}
+
+	entity := &entities.Entity{Name: "exchange", Category: entities.EntityCategoryExchange, Addresses: []string{testutil.AliceAddress, testutil.CharlieAddr}}
+	if err := store.Entities.Create(ctx, entity); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.Entities.GetByID(ctx, entity.ID); err != nil || got == nil || len(got.Addresses) != 2 || got.Category != entities.EntityCategoryExchange {
+		t.Errorf("unexpected entity: %+v (%v)", got, err)
+	}
+	if err := store.Entities.Create(ctx, &entities.Entity{Name: "bridge", Category: entities.EntityCategoryBridge, Addresses: []string{testutil.BobAddress}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Alice to Charlie stays within the entity
+	seeded := testutil.CreateTestTransfer().BlockTimestamp
+	flows, err := store.Entities.GetCategoryFlows(ctx, testutil.USDTAddress, entities.EntityCategoryExchange, seeded.Add(-time.Hour), seeded.Add(time.Hour))
+	if err != nil || len(flows) != 1 || flows[0].EntityID != entity.ID || flows[0].TransfersIn != 1 || flows[0].TransfersOut != 2 ||
+		flows[0].VolumeIn != ether(1000).String() || flows[0].VolumeOut != ether(600).String() {
+		t.Errorf("unexpected category flows: %+v (%v)", flows, err)
+	}
+	if flows, err := store.Entities.GetCategoryFlows(ctx, testutil.USDTAddress, entities.EntityCategoryExchange, seeded.Add(time.Hour), seeded.Add(2*time.Hour)); err != nil || len(flows) != 0 {
+		t.Errorf("expected no flows outside the window, got %+v (%v)", flows, err)
+	}
+	if deleted, err := store.Entities.Delete(ctx, entity.ID); err != nil || !deleted {
+		t.Errorf("expected the entity deleted, got %v (%v)", deleted, err)
+	}
+
+	sig := &entities.MethodSignature{Selector: "0xa9059cbb", Name: "transfer(address,uint256)", Label: "send"}
+	if err := store.Signatures.Upsert(ctx, sig); err != nil || sig.CreatedAt.IsZero() {
+		t.Errorf("unexpected upsert: %+v (%v)", sig, err)
+	}
+	sigs, err := store.Signatures.GetBySelectors(ctx, []string{"0xa9059cbb", "0x095ea7b3"})
+	if err != nil || len(sigs) != 2 {
+		t.Errorf("expected 2 signatures, got %+v (%v)", sigs, err)
+	}
+}
+
+func TestSQLiteStore_Events(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+	seeded := testutil.CreateTestTransfer().BlockTimestamp
+	pool := "0x4444444444444444444444444444444444444444"
+
+	if err := store.Swaps.UpsertPool(ctx, &entities.DexPool{Address: pool, Token0Address: testutil.USDTAddress, Token1Address: testutil.USDCAddress}); err != nil {
+		t.Fatal(err)
+	}
+	swap := &entities.Swap{
+		TxHash: "0xbb", BlockNumber: 100, BlockTimestamp: seeded, PoolAddress: pool, Protocol: "uniswap_v2",
+		Sender: testutil.AliceAddress, Recipient: testutil.AliceAddress,
+		Token0Address: testutil.USDTAddress, Token1Address: testutil.USDCAddress,
+		Amount0String: ether(5).String(), Amount1String: "-" + ether(5).String(),
+	}
+	if err := store.Swaps.StoreEvents(ctx, []any{swap}); err != nil {
+		t.Fatal(err)
+	}
+	swaps, err := store.Swaps.GetByFilter(ctx, entities.SwapFilter{PoolAddress: &pool, Limit: 10})
+	if err != nil || len(swaps) != 1 || swaps[0].Amount1String != "-"+ether(5).String() {
+		t.Errorf("unexpected swaps: %+v (%v)", swaps, err)
+	}
+	volume, err := store.Swaps.GetTokenSwapVolume(ctx, testutil.USDCAddress)
+	if err != nil || volume.SwapCount != 1 || volume.TotalVolume != ether(5).String() {
+		t.Errorf("unexpected swap volume: %+v (%v)", volume, err)
+	}
+
+	eth := entities.EthTransfer{
+		TxHash: "0xcc", BlockNumber: 100, BlockTimestamp: seeded,
+		FromAddress: testutil.AliceAddress, ToAddress: testutil.BobAddress, ValueString: ether(2).String(), CallType: "call",
+	}
+	if err := store.EthTransfers.BatchInsert(ctx, []entities.EthTransfer{eth, eth}); err != nil {
+		t.Fatal(err)
+	}
+	bob := testutil.BobAddress
+	if count, err := store.EthTransfers.GetCount(ctx, entities.EthTransferFilter{Address: &bob}); err != nil || count != 1 {
+		t.Errorf("expected 1 ETH transfer, got %d (%v)", count, err)
+	}
+	if err := store.EthTransfers.UpdateLastBlock(ctx, 100); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := store.EthTransfers.GetLastBlock(ctx); err != nil || last != 100 {
+		t.Errorf("expected trace checkpoint 100, got %d (%v)", last, err)
+	}
+
+	raw := entities.RawLog{BlockNumber: 100, TxHash: "0xdd", Address: pool, BlockTimestamp: seeded, Log: []byte(`{"data":"0x"}`)}
+	if err := store.RawLogs.BatchInsert(ctx, []entities.RawLog{raw}); err != nil {
+		t.Fatal(err)
+	}
+	logs, err := store.RawLogs.GetRange(ctx, pool, 100, 100)
+	if err != nil || len(logs) != 1 || string(logs[0].Log) != `{"data":"0x"}` {
+		t.Errorf("unexpected raw logs: %+v (%v)", logs, err)
+	}
+
+	if err := store.ScopedEvents.UpdateLastBlock(ctx, 42); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := store.ScopedEvents.GetLastBlock(ctx); err != nil || last != 42 {
+		t.Errorf("expected scoped checkpoint 42, got %d (%v)", last, err)
+	}
+}
+
+func TestSQLiteStore_ContractEvents(t *testing.T) {
+	store := openSQLite(t)
+	ctx := context.Background()
+	seeded := testutil.CreateTestTransfer().BlockTimestamp
+
+	contract := &entities.Contract{Address: testutil.USDTAddress, Name: "Tether", ABI: testutil.ERC20EventsABI, Events: []string{"Transfer"}}
+	if err := store.Contracts.UpsertContract(ctx, contract); err != nil || contract.CreatedAt.IsZero() {
+		t.Fatalf("unexpected upsert: %+v (%v)", contract, err)
+	}
+	contract.Events = []string{"Approval", "Transfer"}
+	if err := store.Contracts.UpsertContract(ctx, contract); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Contracts.GetContract(ctx, testutil.USDTAddress)
+	if err != nil || got == nil || len(got.Events) != 2 || got.Name != "Tether" {
+		t.Errorf("expected the replaced registration, got %+v (%v)", got, err)
+	}
+	if got, err := store.Contracts.GetContract(ctx, testutil.USDCAddress); err != nil || got != nil {
+		t.Errorf("expected no contract, got %+v (%v)", got, err)
+	}
+	if contracts, err := store.Contracts.ListContracts(ctx); err != nil || len(contracts) != 1 {
+		t.Errorf("expected 1 contract, got %+v (%v)", contracts, err)
+	}
+
+	// The second value is 2^70, past 64 bits
+	transfer := func(txHash, to, value string) *entities.ContractEvent {
+		return &entities.ContractEvent{
+			ContractAddress: testutil.USDTAddress, EventName: "Transfer", TxHash: txHash, BlockNumber: 100, BlockTimestamp: seeded,
+			Topics: []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+			Args:   []byte(`{"from":"` + testutil.AliceAddress + `","to":"` + to + `","value":` + value + `}`),
+		}
+	}
+	events := []any{
+		transfer("0xa1", testutil.BobAddress, "5"),
+		transfer("0xa2", testutil.BobAddress, "1180591620717411303424"),
+		transfer("0xa3", testutil.CharlieAddr, "5"),
+		transfer("0xa3", testutil.CharlieAddr, "5"),
+	}
+	if err := store.Contracts.StoreEvents(ctx, events); err != nil {
+		t.Fatal(err)
+	}
+
+	name := "Transfer"
+	filter := entities.ContractEventFilter{ContractAddress: testutil.USDTAddress, EventName: &name, Limit: 10}
+	if count, err := store.Contracts.GetEventCount(ctx, filter); err != nil || count != 3 {
+		t.Errorf("expected 3 events without the duplicate, got %d (%v)", count, err)
+	}
+
+	filter.Args = map[string]json.RawMessage{"to": json.RawMessage(`"` + testutil.BobAddress + `"`), "value": json.RawMessage(`1180591620717411303424`)}
+	found, err := store.Contracts.GetEvents(ctx, filter)
+	if err != nil || len(found) != 1 || found[0].TxHash != "0xa2" || len(found[0].Topics) != 1 {
+		t.Errorf("expected the 2^70 transfer to Bob, got %+v (%v)", found, err)
+	}
+	filter.Args["value"] = json.RawMessage(`1180591620717411303425`)
+	if count, err := store.Contracts.GetEventCount(ctx, filter); err != nil || count != 0 {
+		t.Errorf("expected no transfer one unit above, got %d (%v)", count, err)
+	}
+
+	// Ranges compare values past 64 bits exactly
+	filter.Args = nil
+	for want, conditions := range map[int64][]entities.ContractArgCondition{
+		1: {{Arg: "value", Operator: entities.ContractArgGt, Value: json.RawMessage(`1180591620717411303423`)}},
+		2: {{Arg: "value", Operator: entities.ContractArgLte, Value: json.RawMessage(`5`)}},
+		0: {{Arg: "value", Operator: entities.ContractArgLt, Value: json.RawMessage(`5`)}},
+		3: {{Arg: "value", Operator: entities.ContractArgGte, Value: json.RawMessage(`5`)}},
+	} {
+		filter.Conditions = conditions
+		if count, err := store.Contracts.GetEventCount(ctx, filter); err != nil || count != want {
+			t.Errorf("%s %s: expected %d events, got %d (%v)", conditions[0].Operator, conditions[0].Value, want, count, err)
+		}
+	}
+	filter.Conditions = []entities.ContractArgCondition{
+		{Arg: "to", Operator: entities.ContractArgNe, Value: json.RawMessage(`"` + testutil.BobAddress + `"`)},
+		{Arg: "value", Operator: entities.ContractArgEq, Value: json.RawMessage(`5`)},
+	}
+	if found, err := store.Contracts.GetEvents(ctx, filter); err != nil || len(found) != 1 || found[0].TxHash != "0xa3" {
+		t.Errorf("expected the transfer to Charlie, got %+v (%v)", found, err)
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewSQLiteDB(config.DatabaseConfig{Path: ":memory:"}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewSQLiteStore(db)
+	t.Cleanup(func() { _ = store.Close() })
+
+	schema, err := CheckSchema(ctx, store)
+	if err != nil || schema.Version != SchemaVersion || schema.Compatible() != nil {
+		t.Fatalf("expected a fresh schema to be compatible, got %+v (%v)", schema, err)
+	}
+
+	// A table of a later migration missing disables its feature only
+	if _, err := db.DB().ExecContext(ctx, `DROP TABLE swaps`); err != nil {
+		t.Fatal(err)
+	}
+	schema, err = CheckSchema(ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Enabled(FeatureSwaps) || !schema.Enabled(FeatureWatchlists) || len(schema.Disabled[FeatureSwaps]) != 1 {
+		t.Errorf("expected only swaps disabled, got %v", schema.Disabled)
+	}
+	if err := schema.Compatible(); !errors.Is(err, ErrSchemaIncompatible) {
+		t.Errorf("expected a missing table to be incompatible, got %v", err)
+	}
+
+	// So does a column a later migration added to an existing table
+	if _, err := db.DB().ExecContext(ctx, `DROP INDEX IF EXISTS idx_entities_category; ALTER TABLE entities DROP COLUMN category`); err != nil {
+		t.Fatal(err)
+	}
+	schema, err = CheckSchema(ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if columns := schema.Disabled[FeatureEntities]; len(columns) != 1 || columns[0] != "entities.category" {
+		t.Errorf("expected entities disabled for its category column, got %v", schema.Disabled)
+	}
+
+	// Without a column or table everything needs there is no degraded mode
+	if _, err := db.DB().ExecContext(ctx, `ALTER TABLE indexer_state DROP COLUMN backfill_checkpoint`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CheckSchema(ctx, store); !errors.Is(err, ErrSchemaIncompatible) || !strings.Contains(err.Error(), "indexer_state.backfill_checkpoint") {
+		t.Errorf("expected a missing required column to fail, got %v", err)
+	}
+	if _, err := db.DB().ExecContext(ctx, `DROP TABLE indexer_state`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CheckSchema(ctx, store); !errors.Is(err, ErrSchemaIncompatible) {
+		t.Errorf("expected a missing required table to fail, got %v", err)
+	}
+}
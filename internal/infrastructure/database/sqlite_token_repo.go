@@ -0,0 +1,336 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteTokenRepo implements TokenRepository
+var _ repositories.TokenRepository = (*SQLiteTokenRepo)(nil)
+
+// SQLiteTokenRepo implements TokenRepository using SQLite
+type SQLiteTokenRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteTokenRepo creates a new SQLite token repository
+func NewSQLiteTokenRepo(db *sqlx.DB) *SQLiteTokenRepo {
+	return &SQLiteTokenRepo{db: db}
+}
+
+// GetByAddress retrieves a token by its address
+func (r *SQLiteTokenRepo) GetByAddress(ctx context.Context, address string) (*entities.Token, error) {
+	ctx = withQueryName(ctx, "tokens.GetByAddress")
+
+	var token entities.Token
+	query := `SELECT * FROM tokens WHERE address = ?1`
+
+	if err := r.db.GetContext(ctx, &token, query, address); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// GetByAddresses retrieves the tokens with the given addresses in one query; unknown addresses are omitted
+func (r *SQLiteTokenRepo) GetByAddresses(ctx context.Context, addresses []string) ([]*entities.Token, error) {
+	ctx = withQueryName(ctx, "tokens.GetByAddresses")
+
+	var tokens []*entities.Token
+	query := `SELECT * FROM tokens WHERE address IN (SELECT value FROM json_each(?1))`
+
+	if err := r.db.SelectContext(ctx, &tokens, query, sqliteList(addresses)); err != nil {
+		return nil, fmt.Errorf("failed to get tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// GetAll retrieves all tokens
+func (r *SQLiteTokenRepo) GetAll(ctx context.Context) ([]entities.Token, error) {
+	ctx = withQueryName(ctx, "tokens.GetAll")
+
+	var tokens []entities.Token
+	query := `SELECT * FROM tokens ORDER BY symbol`
+
+	if err := r.db.SelectContext(ctx, &tokens, query); err != nil {
+		return nil, fmt.Errorf("failed to get tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Upsert creates or updates a token. Overridden metadata is kept.
+func (r *SQLiteTokenRepo) Upsert(ctx context.Context, token *entities.Token) error {
+	ctx = withQueryName(ctx, "tokens.Upsert")
+
+	query := `
+		INSERT INTO tokens (address, name, symbol, decimals, first_seen_block)
+		VALUES (?1, ?2, ?3, ?4, ?5)
+		ON CONFLICT (address) DO UPDATE SET
+			name = CASE WHEN tokens.metadata_source = 'override' THEN tokens.name ELSE excluded.name END,
+			symbol = CASE WHEN tokens.metadata_source = 'override' THEN tokens.symbol ELSE excluded.symbol END,
+			decimals = CASE WHEN tokens.metadata_source = 'override' THEN tokens.decimals ELSE excluded.decimals END,
+			updated_at = ` + sqliteNow + `
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.Address,
+		token.Name,
+		token.Symbol,
+		token.Decimals,
+		token.FirstSeenBlock,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert token: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStats updates token statistics
+func (r *SQLiteTokenRepo) UpdateStats(ctx context.Context, address string, transferCount int64, lastBlock int64) error {
+	ctx = withQueryName(ctx, "tokens.UpdateStats")
+
+	query := `
+		UPDATE tokens SET
+			total_indexed_transfers = total_indexed_transfers + ?2,
+			last_seen_block = MAX(COALESCE(last_seen_block, 0), ?3),
+			updated_at = ` + sqliteNow + `
+		WHERE address = ?1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, address, transferCount, lastBlock)
+	if err != nil {
+		return fmt.Errorf("failed to update token stats: %w", err)
+	}
+
+	return nil
+}
+
+// RecountTransfers sets total_indexed_transfers to the number of stored transfers
+func (r *SQLiteTokenRepo) RecountTransfers(ctx context.Context, address string) (int64, error) {
+	ctx = withQueryName(ctx, "tokens.RecountTransfers")
+
+	query := `
+		UPDATE tokens SET
+			total_indexed_transfers = (SELECT COUNT(*) FROM transfers WHERE token_address = ?1),
+			updated_at = ` + sqliteNow + `
+		WHERE address = ?1
+		RETURNING total_indexed_transfers
+	`
+
+	var count int64
+	err := r.db.GetContext(ctx, &count, query, address)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to recount token transfers: %w", err)
+	}
+
+	return count, nil
+}
+
+// SetActive activates or deactivates a token. Deactivating records deactivated_at; the
+// transfers and indexer checkpoint are left untouched so indexing resumes where it stopped.
+func (r *SQLiteTokenRepo) SetActive(ctx context.Context, address string, active bool) (bool, error) {
+	ctx = withQueryName(ctx, "tokens.SetActive")
+
+	query := `
+		UPDATE tokens SET
+			active = ?2,
+			deactivated_at = CASE WHEN ?2 THEN deactivated_at ELSE ` + sqliteNow + ` END,
+			updated_at = ` + sqliteNow + `
+		WHERE address = ?1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, address, active)
+	if err != nil {
+		return false, fmt.Errorf("failed to set token active: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set token active: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// OverrideMetadata replaces the given metadata fields and marks the token's metadata as overridden
+func (r *SQLiteTokenRepo) OverrideMetadata(ctx context.Context, address string, override entities.TokenMetadataOverride) (bool, error) {
+	ctx = withQueryName(ctx, "tokens.OverrideMetadata")
+
+	query := `
+		UPDATE tokens SET
+			name = COALESCE(?2, name),
+			symbol = COALESCE(?3, symbol),
+			decimals = COALESCE(?4, decimals),
+			metadata_source = 'override',
+			updated_at = ` + sqliteNow + `
+		WHERE address = ?1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, address, override.Name, override.Symbol, override.Decimals)
+	if err != nil {
+		return false, fmt.Errorf("failed to override token metadata: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to override token metadata: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// RecordMetadataChange applies an on-chain metadata change and appends it to the token's history
+func (r *SQLiteTokenRepo) RecordMetadataChange(ctx context.Context, change *entities.TokenMetadataChange) (bool, error) {
+	ctx = withQueryName(ctx, "tokens.RecordMetadataChange")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	update := `
+		UPDATE tokens SET
+			name = ?2,
+			symbol = ?3,
+			decimals = ?4,
+			updated_at = ` + sqliteNow + `
+		WHERE address = ?1 AND metadata_source <> 'override'
+	`
+	result, err := tx.ExecContext(ctx, update, change.TokenAddress, change.Name, change.Symbol, change.Decimals)
+	if err != nil {
+		return false, fmt.Errorf("failed to update token metadata: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update token metadata: %w", err)
+	}
+	if rows == 0 {
+		return false, nil // Unknown or overridden
+	}
+
+	insert := `
+		INSERT INTO token_metadata_history (
+			token_address, previous_name, previous_symbol, previous_decimals,
+			name, symbol, decimals, block_number
+		) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+		RETURNING id, detected_at
+	`
+	row := tx.QueryRowxContext(ctx, insert,
+		change.TokenAddress, change.PreviousName, change.PreviousSymbol, change.PreviousDecimals,
+		change.Name, change.Symbol, change.Decimals, change.BlockNumber,
+	)
+	if err := row.Scan(&change.ID, &change.DetectedAt); err != nil {
+		return false, fmt.Errorf("failed to record token metadata change: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetMetadataHistory returns a token's recorded metadata changes, newest first
+func (r *SQLiteTokenRepo) GetMetadataHistory(ctx context.Context, address string) ([]entities.TokenMetadataChange, error) {
+	ctx = withQueryName(ctx, "tokens.GetMetadataHistory")
+
+	query := `
+		SELECT id, token_address, previous_name, previous_symbol, previous_decimals,
+			name, symbol, decimals, block_number, detected_at
+		FROM token_metadata_history
+		WHERE token_address = ?1
+		ORDER BY id DESC
+	`
+
+	var changes []entities.TokenMetadataChange
+	if err := r.db.SelectContext(ctx, &changes, query, address); err != nil {
+		return nil, fmt.Errorf("failed to get token metadata history: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetAllPaginated retrieves tokens with pagination and sorting
+func (r *SQLiteTokenRepo) GetAllPaginated(ctx context.Context, limit, offset int, sortBy, sortOrder string, includeInactive bool, scope repositories.TokenScope) ([]*entities.Token, int64, error) {
+	ctx = withQueryName(ctx, "tokens.GetAllPaginated")
+
+	// Validate sort column
+	if !validSortColumns[sortBy] {
+		sortBy = "total_indexed_transfers"
+	}
+
+	// Validate sort order
+	if sortOrder != "asc" && sortOrder != "desc" {
+		sortOrder = "desc"
+	}
+
+	var conditions []string
+	if !includeInactive {
+		conditions = append(conditions, "active")
+	}
+	conditions, args := sqliteTokenScopeConditions("address", scope, conditions, nil)
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Get total count
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM tokens ` + where
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count tokens: %w", err)
+	}
+
+	// Get paginated tokens
+	query := fmt.Sprintf(`SELECT * FROM tokens %s ORDER BY %s %s LIMIT ?%d OFFSET ?%d`, where, sortBy, sortOrder, len(args)+1, len(args)+2)
+	var tokens []*entities.Token
+	if err := r.db.SelectContext(ctx, &tokens, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to get tokens: %w", err)
+	}
+
+	return tokens, total, nil
+}
+
+// sqliteTokenScopeConditions appends the conditions restricting column to scope, and their
+// arguments, numbered after the arguments already given
+func sqliteTokenScopeConditions(column string, scope repositories.TokenScope, conditions []string, args []interface{}) ([]string, []interface{}) {
+	if len(scope.Only) > 0 {
+		args = append(args, sqliteList(scope.Only))
+		conditions = append(conditions, fmt.Sprintf("%s IN (SELECT value FROM json_each(?%d))", column, len(args)))
+	}
+	if len(scope.Except) > 0 {
+		args = append(args, sqliteList(scope.Except))
+		conditions = append(conditions, fmt.Sprintf("%s NOT IN (SELECT value FROM json_each(?%d))", column, len(args)))
+	}
+	return conditions, args
+}
+
+// Count returns the total number of tokens
+func (r *SQLiteTokenRepo) Count(ctx context.Context) (int64, error) {
+	ctx = withQueryName(ctx, "tokens.Count")
+
+	var count int64
+	query := `SELECT COUNT(*) FROM tokens`
+
+	if err := r.db.GetContext(ctx, &count, query); err != nil {
+		return 0, fmt.Errorf("failed to count tokens: %w", err)
+	}
+
+	return count, nil
+}
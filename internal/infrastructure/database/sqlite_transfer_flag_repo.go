@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteTransferFlagRepo implements TransferFlagRepository
+var _ repositories.TransferFlagRepository = (*SQLiteTransferFlagRepo)(nil)
+
+// SQLiteTransferFlagRepo implements TransferFlagRepository using SQLite
+type SQLiteTransferFlagRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteTransferFlagRepo creates a new SQLite transfer flag repository
+func NewSQLiteTransferFlagRepo(db *sqlx.DB) *SQLiteTransferFlagRepo {
+	return &SQLiteTransferFlagRepo{db: db}
+}
+
+// Add stores flags, skipping those already stored, and returns the number added
+func (r *SQLiteTransferFlagRepo) Add(ctx context.Context, flags []entities.TransferFlag) (int64, error) {
+	ctx = withQueryName(ctx, "transfer_flags.Add")
+
+	if len(flags) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO transfer_flags (tx_hash, log_index, flag, token_address)
+		VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (tx_hash, log_index, flag) DO NOTHING
+	`
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var added int64
+	for _, f := range flags {
+		result, err := stmt.ExecContext(ctx, f.TxHash, f.LogIndex, f.Flag, f.TokenAddress)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add transfer flags: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		added += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return added, nil
+}
+
+// GetByTxHashes returns the flags of the transfers in the given transactions
+func (r *SQLiteTransferFlagRepo) GetByTxHashes(ctx context.Context, txHashes []string) ([]entities.TransferFlag, error) {
+	ctx = withQueryName(ctx, "transfer_flags.GetByTxHashes")
+
+	if len(txHashes) == 0 {
+		return nil, nil
+	}
+
+	var flags []entities.TransferFlag
+	query := `
+		SELECT tx_hash, log_index, flag, token_address, flagged_at
+		FROM transfer_flags
+		WHERE tx_hash IN (SELECT value FROM json_each(?1))
+		ORDER BY tx_hash, log_index, flag
+	`
+	if err := r.db.SelectContext(ctx, &flags, query, sqliteList(txHashes)); err != nil {
+		return nil, fmt.Errorf("failed to get transfer flags: %w", err)
+	}
+
+	return flags, nil
+}
+
+// GetFlaggedStats counts a token's flagged transfers in [from, to) and sums their value. Transfers
+// are joined so flags of transfers removed by a reorg are not counted.
+func (r *SQLiteTransferFlagRepo) GetFlaggedStats(ctx context.Context, tokenAddress string, from, to time.Time) (*repositories.WindowStats, error) {
+	ctx = withQueryName(ctx, "transfer_flags.GetFlaggedStats")
+
+	query := `
+		SELECT COUNT(*) as transfers, big_sum(t.value) as volume
+		FROM transfers t
+		WHERE t.token_address = ?1
+		AND t.block_timestamp >= ?2 AND t.block_timestamp < ?3
+		AND EXISTS (
+			SELECT 1 FROM transfer_flags f
+			WHERE f.tx_hash = t.tx_hash AND f.log_index = t.log_index
+		)
+	`
+
+	var row windowStatsRow
+	if err := r.db.GetContext(ctx, &row, query, tokenAddress, sqliteTime(from), sqliteTime(to)); err != nil {
+		return nil, fmt.Errorf("failed to get flagged transfer stats: %w", err)
+	}
+
+	return &repositories.WindowStats{
+		Transfers: row.Transfers,
+		Volume:    row.Volume,
+	}, nil
+}
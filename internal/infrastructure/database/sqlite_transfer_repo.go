@@ -0,0 +1,810 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteTransferRepo implements TransferRepository
+var _ repositories.TransferRepository = (*SQLiteTransferRepo)(nil)
+
+// SQLiteTransferRepo implements TransferRepository using SQLite
+type SQLiteTransferRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteTransferRepo creates a new SQLite transfer repository
+func NewSQLiteTransferRepo(db *sqlx.DB) *SQLiteTransferRepo {
+	return &SQLiteTransferRepo{db: db}
+}
+
+const sqliteTransferColumns = `id, tx_hash, log_index, block_number, block_timestamp,
+	token_address, from_address, to_address, value, initiator, method_selector, created_at`
+
+// sqliteBalances is the per-address balance of token ?1, the SQLite form of the balances CTE
+const sqliteBalances = `
+	SELECT address, big_sum(amount) as balance
+	FROM (
+		SELECT to_address as address, value as amount
+		FROM transfers WHERE token_address = ?1
+		UNION ALL
+		SELECT from_address as address, big_neg(value) as amount
+		FROM transfers WHERE token_address = ?1
+	) t
+	GROUP BY address
+`
+
+// GetByFilter retrieves transfers matching the given filter
+func (r *SQLiteTransferRepo) GetByFilter(ctx context.Context, filter entities.TransferFilter) ([]entities.Transfer, error) {
+	ctx = withQueryName(ctx, "transfers.GetByFilter")
+
+	whereClause, args := sqliteFilterWhereClause(filter)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transfers
+		%s
+		ORDER BY block_timestamp DESC, block_number DESC, log_index DESC
+		LIMIT ?%d OFFSET ?%d
+	`, sqliteTransferColumns, whereClause, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	var transfers []entities.Transfer
+	if err := r.db.SelectContext(ctx, &transfers, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// GetCount returns the count of transfers matching the filter
+func (r *SQLiteTransferRepo) GetCount(ctx context.Context, filter entities.TransferFilter) (int64, error) {
+	ctx = withQueryName(ctx, "transfers.GetCount")
+
+	whereClause, args := sqliteFilterWhereClause(filter)
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM transfers "+whereClause, args...); err != nil {
+		return 0, fmt.Errorf("failed to get transfer count: %w", err)
+	}
+
+	return count, nil
+}
+
+// sqliteFilterWhereClause is filterWhereClause for SQLite: numbered ?N parameters, JSON lists
+// instead of arrays and big_cmp for value bounds
+func sqliteFilterWhereClause(filter entities.TransferFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, values ...interface{}) {
+		refs := make([]interface{}, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			refs[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, refs...))
+	}
+
+	if filter.TokenAddress != nil {
+		add("token_address = ?%d", *filter.TokenAddress)
+	}
+	if len(filter.Tokens) > 0 {
+		add("token_address IN (SELECT value FROM json_each(?%d))", sqliteList(filter.Tokens))
+	}
+	if len(filter.ExcludeTokens) > 0 {
+		add("token_address NOT IN (SELECT value FROM json_each(?%d))", sqliteList(filter.ExcludeTokens))
+	}
+	if filter.FromAddress != nil {
+		add("from_address = ?%d", *filter.FromAddress)
+	}
+	if filter.ToAddress != nil {
+		add("to_address = ?%d", *filter.ToAddress)
+	}
+	if filter.Address != nil {
+		args = append(args, *filter.Address)
+		conditions = append(conditions, fmt.Sprintf("(from_address = ?%d OR to_address = ?%d)", len(args), len(args)))
+	}
+	if len(filter.Addresses) > 0 {
+		args = append(args, sqliteList(filter.Addresses))
+		conditions = append(conditions, fmt.Sprintf(
+			"(from_address IN (SELECT value FROM json_each(?%d)) OR to_address IN (SELECT value FROM json_each(?%d)))", len(args), len(args)))
+	}
+	if filter.Initiator != nil {
+		add("initiator = ?%d", *filter.Initiator)
+	}
+	if filter.Selector != nil {
+		add("method_selector = ?%d", *filter.Selector)
+	}
+	if filter.Method != nil {
+		add("method_selector IN (SELECT selector FROM method_signatures WHERE label = ?%d)", *filter.Method)
+	}
+	if filter.FromBlock != nil {
+		add("block_number >= ?%d", *filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		add("block_number <= ?%d", *filter.ToBlock)
+	}
+	if filter.FromTime != nil {
+		add("block_timestamp >= ?%d", sqliteTime(*filter.FromTime))
+	}
+	if filter.ToTime != nil {
+		add("block_timestamp <= ?%d", sqliteTime(*filter.ToTime))
+	}
+	if filter.MinValue != nil {
+		add("big_cmp(value, ?%d) >= 0", filter.MinValue.String())
+	}
+	if filter.MaxValue != nil {
+		add("big_cmp(value, ?%d) <= 0", filter.MaxValue.String())
+	}
+	if filter.ExcludeZero {
+		conditions = append(conditions, "value <> '0'")
+	}
+	if filter.ExcludeSelf {
+		conditions = append(conditions, "from_address <> to_address")
+	}
+	if filter.After != nil {
+		add("(block_timestamp, block_number, log_index) < (?%d, ?%d, ?%d)",
+			sqliteTime(filter.After.BlockTimestamp), filter.After.BlockNumber, filter.After.LogIndex)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// BatchInsert inserts multiple transfers in a single transaction, returning how many were new
+func (r *SQLiteTransferRepo) BatchInsert(ctx context.Context, transfers []entities.Transfer) (int64, error) {
+	ctx = withQueryName(ctx, "transfers.BatchInsert")
+
+	if len(transfers) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	inserted, err := sqliteInsertTransfers(ctx, tx, transfers)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// ReplaceRange deletes a token's transfers in a block range and inserts the given ones in one transaction
+func (r *SQLiteTransferRepo) ReplaceRange(ctx context.Context, tokenAddress string, fromBlock, toBlock int64, transfers []entities.Transfer) (int64, int64, error) {
+	ctx = withQueryName(ctx, "transfers.ReplaceRange")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM transfers WHERE token_address = ?1 AND block_number >= ?2 AND block_number <= ?3`,
+		tokenAddress, fromBlock, toBlock,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to delete transfers: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	inserted, err := sqliteInsertTransfers(ctx, tx, transfers)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return deleted, inserted, nil
+}
+
+// sqliteInsertTransfers inserts transfers within an open transaction, skipping duplicates, and
+// returns the number inserted
+func sqliteInsertTransfers(ctx context.Context, tx *sqlx.Tx, transfers []entities.Transfer) (int64, error) {
+	if len(transfers) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		INSERT INTO transfers (tx_hash, log_index, block_number, block_timestamp,
+							   token_address, from_address, to_address, value, initiator, method_selector)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10)
+		ON CONFLICT (tx_hash, log_index, block_timestamp) DO NOTHING
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var inserted int64
+	for _, t := range transfers {
+		value, err := transferValue(t)
+		if err != nil {
+			return 0, err
+		}
+		result, err := stmt.ExecContext(ctx,
+			t.TxHash,
+			t.LogIndex,
+			t.BlockNumber,
+			sqliteTime(t.BlockTimestamp),
+			t.TokenAddress,
+			t.FromAddress,
+			t.ToAddress,
+			value,
+			t.Initiator,
+			t.MethodSelector,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert transfer: %w", err)
+		}
+		// ON CONFLICT DO NOTHING affects no row for a duplicate
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		inserted += n
+	}
+
+	return inserted, nil
+}
+
+// GetLatestBlock returns the latest indexed block for a token
+func (r *SQLiteTransferRepo) GetLatestBlock(ctx context.Context, tokenAddress string) (int64, error) {
+	ctx = withQueryName(ctx, "transfers.GetLatestBlock")
+
+	var blockNumber int64
+	query := `SELECT COALESCE(MAX(block_number), 0) FROM transfers WHERE token_address = ?1`
+	if err := r.db.GetContext(ctx, &blockNumber, query, tokenAddress); err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	return blockNumber, nil
+}
+
+// GetLatestID returns the highest transfer ID, or 0 if there are no transfers
+func (r *SQLiteTransferRepo) GetLatestID(ctx context.Context) (int64, error) {
+	ctx = withQueryName(ctx, "transfers.GetLatestID")
+
+	var id int64
+	if err := r.db.GetContext(ctx, &id, `SELECT COALESCE(MAX(id), 0) FROM transfers`); err != nil {
+		return 0, fmt.Errorf("failed to get latest transfer id: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetAfterID retrieves transfers matching the filter with an ID above afterID, in insertion order
+func (r *SQLiteTransferRepo) GetAfterID(ctx context.Context, filter entities.TransferFilter, afterID int64) ([]entities.Transfer, error) {
+	ctx = withQueryName(ctx, "transfers.GetAfterID")
+
+	whereClause, args := sqliteFilterWhereClause(filter)
+	idCondition := fmt.Sprintf("id > ?%d", len(args)+1)
+	if whereClause == "" {
+		whereClause = "WHERE " + idCondition
+	} else {
+		whereClause += " AND " + idCondition
+	}
+	args = append(args, afterID, filter.Limit)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transfers
+		%s
+		ORDER BY id
+		LIMIT ?%d
+	`, sqliteTransferColumns, whereClause, len(args))
+
+	var transfers []entities.Transfer
+	if err := r.db.SelectContext(ctx, &transfers, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get transfers after id: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// GetTokenStats returns aggregated transfer statistics for a token
+func (r *SQLiteTransferRepo) GetTokenStats(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error) {
+	ctx = withQueryName(ctx, "transfers.GetTokenStats")
+
+	now := time.Now().UTC()
+	query := `
+		SELECT
+			COUNT(*) as total_transfers,
+			COUNT(DISTINCT from_address) as unique_from,
+			COUNT(DISTINCT to_address) as unique_to,
+			big_sum(value) as total_volume,
+			MIN(block_timestamp) as first_transfer,
+			MAX(block_timestamp) as last_transfer,
+			COUNT(CASE WHEN block_timestamp >= ?2 THEN 1 END) as transfers_24h,
+			big_sum(CASE WHEN block_timestamp >= ?2 THEN value END) as volume_24h,
+			COUNT(CASE WHEN block_timestamp >= ?3 THEN 1 END) as transfers_7d,
+			big_sum(CASE WHEN block_timestamp >= ?3 THEN value END) as volume_7d
+		FROM transfers
+		WHERE token_address = ?1
+	`
+
+	var row statsRow
+	if err := r.db.GetContext(ctx, &row, query, tokenAddress, now.Add(-24*time.Hour), now.AddDate(0, 0, -7)); err != nil {
+		return nil, fmt.Errorf("failed to get token stats: %w", err)
+	}
+
+	return &repositories.TokenStatsResult{
+		TotalTransfers:  row.TotalTransfers,
+		UniqueFromAddrs: row.UniqueFrom,
+		UniqueToAddrs:   row.UniqueTo,
+		TotalVolume:     row.TotalVolume,
+		FirstTransferAt: sqliteTimestamp(row.FirstTransfer),
+		LastTransferAt:  sqliteTimestamp(row.LastTransfer),
+		Transfers24h:    row.Transfers24h,
+		Volume24h:       row.Volume24h,
+		Transfers7d:     row.Transfers7d,
+		Volume7d:        row.Volume7d,
+	}, nil
+}
+
+// GetWindowStats returns transfer count and volume for a token in [from, to)
+func (r *SQLiteTransferRepo) GetWindowStats(ctx context.Context, tokenAddress string, from, to time.Time) (*repositories.WindowStats, error) {
+	ctx = withQueryName(ctx, "transfers.GetWindowStats")
+
+	query := `
+		SELECT COUNT(*) as transfers, big_sum(value) as volume
+		FROM transfers
+		WHERE token_address = ?1
+		AND block_timestamp >= ?2 AND block_timestamp < ?3
+	`
+
+	var row windowStatsRow
+	if err := r.db.GetContext(ctx, &row, query, tokenAddress, sqliteTime(from), sqliteTime(to)); err != nil {
+		return nil, fmt.Errorf("failed to get window stats: %w", err)
+	}
+
+	return &repositories.WindowStats{
+		Transfers: row.Transfers,
+		Volume:    row.Volume,
+	}, nil
+}
+
+// GetActivityHeatmap returns a token's transfer counts in [from, to) grouped by UTC weekday and hour
+func (r *SQLiteTransferRepo) GetActivityHeatmap(ctx context.Context, tokenAddress string, from, to time.Time) ([]repositories.HeatmapBucket, error) {
+	ctx = withQueryName(ctx, "transfers.GetActivityHeatmap")
+
+	query := `
+		SELECT
+			CAST(strftime('%w', block_timestamp) AS INTEGER) as day_of_week,
+			CAST(strftime('%H', block_timestamp) AS INTEGER) as hour,
+			COUNT(*) as transfers
+		FROM transfers
+		WHERE token_address = ?1
+		AND block_timestamp >= ?2 AND block_timestamp < ?3
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+	`
+
+	var rows []heatmapRow
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, sqliteTime(from), sqliteTime(to)); err != nil {
+		return nil, fmt.Errorf("failed to get activity heatmap: %w", err)
+	}
+
+	buckets := make([]repositories.HeatmapBucket, len(rows))
+	for i, row := range rows {
+		buckets[i] = repositories.HeatmapBucket(row)
+	}
+	return buckets, nil
+}
+
+// GetActiveAddresses returns the distinct senders and receivers of a token in [from, to), excluding the zero address
+func (r *SQLiteTransferRepo) GetActiveAddresses(ctx context.Context, tokenAddress string, from, to time.Time) ([]string, error) {
+	ctx = withQueryName(ctx, "transfers.GetActiveAddresses")
+
+	query := `
+		SELECT from_address AS address FROM transfers
+		WHERE token_address = ?1 AND block_timestamp >= ?2 AND block_timestamp < ?3
+		UNION
+		SELECT to_address AS address FROM transfers
+		WHERE token_address = ?1 AND block_timestamp >= ?2 AND block_timestamp < ?3
+	`
+
+	var addresses []string
+	if err := r.db.SelectContext(ctx, &addresses, query, tokenAddress, sqliteTime(from), sqliteTime(to)); err != nil {
+		return nil, fmt.Errorf("failed to get active addresses: %w", err)
+	}
+
+	result := addresses[:0]
+	for _, addr := range addresses {
+		if addr != entities.ZeroAddress {
+			result = append(result, addr)
+		}
+	}
+
+	return result, nil
+}
+
+// GetTopHolders returns top token holders sorted by balance
+func (r *SQLiteTransferRepo) GetTopHolders(ctx context.Context, tokenAddress string, limit int) ([]repositories.HolderBalance, error) {
+	ctx = withQueryName(ctx, "transfers.GetTopHolders")
+
+	holders, err := r.topHolders(ctx, tokenAddress, limit, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top holders: %w", err)
+	}
+	return holders, nil
+}
+
+// GetTopHoldersWithOffset returns top token holders with pagination offset, leaving out exclude
+func (r *SQLiteTransferRepo) GetTopHoldersWithOffset(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+	ctx = withQueryName(ctx, "transfers.GetTopHoldersWithOffset")
+
+	holders, err := r.topHolders(ctx, tokenAddress, limit, offset, exclude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top holders: %w", err)
+	}
+	return holders, nil
+}
+
+// topHolders ranks the positive balances of a token, ranks counting from the first row returned
+// like ROW_NUMBER() in the PostgreSQL query
+func (r *SQLiteTransferRepo) topHolders(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+	query := `
+		WITH balances AS (` + sqliteBalances + `)
+		SELECT address, balance, ROW_NUMBER() OVER (ORDER BY big_key(balance) DESC) as rank
+		FROM balances
+		WHERE big_cmp(balance, 0) > 0 AND address NOT IN (SELECT value FROM json_each(?4))
+		ORDER BY big_key(balance) DESC
+		LIMIT ?2 OFFSET ?3
+	`
+
+	var rows []holderBalanceRow
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, limit, offset, sqliteList(exclude)); err != nil {
+		return nil, err
+	}
+
+	result := make([]repositories.HolderBalance, len(rows))
+	for i, row := range rows {
+		result[i] = repositories.HolderBalance(row)
+	}
+	return result, nil
+}
+
+// GetHolderBalance returns balance for a specific holder
+func (r *SQLiteTransferRepo) GetHolderBalance(ctx context.Context, tokenAddress, holderAddress string) (*repositories.HolderBalance, error) {
+	ctx = withQueryName(ctx, "transfers.GetHolderBalance")
+
+	balanceQuery := `
+		SELECT big_sum(
+			CASE
+				WHEN to_address = ?2 THEN value
+				WHEN from_address = ?2 THEN big_neg(value)
+				ELSE 0
+			END
+		) as balance
+		FROM transfers
+		WHERE token_address = ?1
+		AND (to_address = ?2 OR from_address = ?2)
+	`
+
+	var balance string
+	if err := r.db.GetContext(ctx, &balance, balanceQuery, tokenAddress, holderAddress); err != nil {
+		return nil, fmt.Errorf("failed to get holder balance: %w", err)
+	}
+
+	rankQuery := `
+		WITH balances AS (` + sqliteBalances + `)
+		SELECT COUNT(*) + 1 as rank
+		FROM balances
+		WHERE big_cmp(balance, 0) > 0 AND big_cmp(balance, ?2) > 0
+	`
+
+	var rank int
+	if err := r.db.GetContext(ctx, &rank, rankQuery, tokenAddress, balance); err != nil {
+		return nil, fmt.Errorf("failed to get holder rank: %w", err)
+	}
+
+	return &repositories.HolderBalance{
+		Address: holderAddress,
+		Balance: balance,
+		Rank:    rank,
+	}, nil
+}
+
+// GetHolderCount returns the count of unique holders with positive balance, leaving out exclude
+func (r *SQLiteTransferRepo) GetHolderCount(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
+	ctx = withQueryName(ctx, "transfers.GetHolderCount")
+
+	query := `
+		WITH balances AS (` + sqliteBalances + `)
+		SELECT COUNT(*) FROM balances
+		WHERE big_cmp(balance, 0) > 0 AND address NOT IN (SELECT value FROM json_each(?2))
+	`
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, query, tokenAddress, sqliteList(exclude)); err != nil {
+		return 0, fmt.Errorf("failed to get holder count: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetCirculatingSupply returns a token's indexed mints minus burns
+func (r *SQLiteTransferRepo) GetCirculatingSupply(ctx context.Context, tokenAddress string) (string, error) {
+	ctx = withQueryName(ctx, "transfers.GetCirculatingSupply")
+
+	query := `
+		SELECT big_sum(
+			CASE
+				WHEN from_address = ?2 THEN value
+				WHEN to_address = ?2 THEN big_neg(value)
+				ELSE 0
+			END
+		) as supply
+		FROM transfers
+		WHERE token_address = ?1
+		AND (from_address = ?2 OR to_address = ?2)
+	`
+
+	var supply string
+	if err := r.db.GetContext(ctx, &supply, query, tokenAddress, entities.ZeroAddress); err != nil {
+		return "", fmt.Errorf("failed to get circulating supply: %w", err)
+	}
+
+	return supply, nil
+}
+
+// GetCirculatingSupplyAt returns a token's indexed mints minus burns before the given time
+func (r *SQLiteTransferRepo) GetCirculatingSupplyAt(ctx context.Context, tokenAddress string, at time.Time) (string, error) {
+	ctx = withQueryName(ctx, "transfers.GetCirculatingSupplyAt")
+
+	query := `
+		SELECT big_sum(
+			CASE
+				WHEN from_address = ?2 THEN value
+				WHEN to_address = ?2 THEN big_neg(value)
+				ELSE 0
+			END
+		) as supply
+		FROM transfers
+		WHERE token_address = ?1
+		AND (from_address = ?2 OR to_address = ?2)
+		AND block_timestamp < ?3
+	`
+
+	var supply string
+	if err := r.db.GetContext(ctx, &supply, query, tokenAddress, entities.ZeroAddress, sqliteTime(at)); err != nil {
+		return "", fmt.Errorf("failed to get circulating supply: %w", err)
+	}
+
+	return supply, nil
+}
+
+// GetDormantSupply sums the positive balances of addresses inactive since the given time
+func (r *SQLiteTransferRepo) GetDormantSupply(ctx context.Context, tokenAddress string, inactiveSince time.Time) (*repositories.DormantSupply, error) {
+	ctx = withQueryName(ctx, "transfers.GetDormantSupply")
+
+	query := `
+		SELECT big_sum(balance) as balance, COUNT(*) as holders
+		FROM (
+			SELECT address, big_sum(amount) as balance, MAX(block_timestamp) as last_active_at
+			FROM (
+				SELECT to_address as address, value as amount, block_timestamp
+				FROM transfers WHERE token_address = ?1
+				UNION ALL
+				SELECT from_address as address, big_neg(value) as amount, block_timestamp
+				FROM transfers WHERE token_address = ?1
+			) t
+			GROUP BY address
+		) balances
+		WHERE big_cmp(balance, 0) > 0 AND last_active_at < ?2 AND address <> ?3
+	`
+
+	var dormant repositories.DormantSupply
+	if err := r.db.GetContext(ctx, &dormant, query, tokenAddress, sqliteTime(inactiveSince), entities.ZeroAddress); err != nil {
+		return nil, fmt.Errorf("failed to get dormant supply: %w", err)
+	}
+
+	return &dormant, nil
+}
+
+// GetTransferPatterns counts self-transfers and fan-out transactions within a time window
+func (r *SQLiteTransferRepo) GetTransferPatterns(ctx context.Context, tokenAddress string, from, to time.Time, fanOut int) (*repositories.TransferPatterns, error) {
+	ctx = withQueryName(ctx, "transfers.GetTransferPatterns")
+
+	query := `
+		WITH window_transfers AS (
+			SELECT tx_hash, from_address, to_address, value
+			FROM transfers
+			WHERE token_address = ?1 AND block_timestamp >= ?2 AND block_timestamp < ?3
+		),
+		fan_outs AS (
+			SELECT COUNT(*) as transfers, big_sum(value) as volume
+			FROM window_transfers
+			GROUP BY tx_hash, from_address
+			HAVING COUNT(*) > ?4
+		)
+		SELECT
+			(SELECT COUNT(*) FROM window_transfers WHERE from_address = to_address) as self_transfers,
+			(SELECT big_sum(value) FROM window_transfers WHERE from_address = to_address) as self_transfer_volume,
+			COUNT(*) as fan_out_transactions,
+			COALESCE(SUM(transfers), 0) as fan_out_transfers,
+			big_sum(volume) as fan_out_volume
+		FROM fan_outs
+	`
+
+	var patterns repositories.TransferPatterns
+	if err := r.db.GetContext(ctx, &patterns, query, tokenAddress, sqliteTime(from), sqliteTime(to), fanOut); err != nil {
+		return nil, fmt.Errorf("failed to get transfer patterns: %w", err)
+	}
+
+	return &patterns, nil
+}
+
+// GetNegativeBalances counts a token's negative balances and returns the limit most negative
+func (r *SQLiteTransferRepo) GetNegativeBalances(ctx context.Context, tokenAddress string, limit int) (*repositories.NegativeBalances, error) {
+	ctx = withQueryName(ctx, "transfers.GetNegativeBalances")
+
+	query := `
+		WITH balances AS (` + sqliteBalances + `)
+		SELECT
+			address,
+			balance,
+			ROW_NUMBER() OVER (ORDER BY big_key(balance) ASC, address) as rank,
+			COUNT(*) OVER () as total
+		FROM balances
+		WHERE big_cmp(balance, 0) < 0 AND address <> ?3
+		ORDER BY big_key(balance) ASC, address
+		LIMIT ?2
+	`
+
+	var rows []negativeBalanceRow
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, limit, entities.ZeroAddress); err != nil {
+		return nil, fmt.Errorf("failed to get negative balances: %w", err)
+	}
+
+	return toNegativeBalances(rows), nil
+}
+
+// GetBalanceChanges returns the addresses whose balance changed the most since the given time
+func (r *SQLiteTransferRepo) GetBalanceChanges(ctx context.Context, tokenAddress string, since time.Time, limit int) ([]repositories.BalanceChange, error) {
+	ctx = withQueryName(ctx, "transfers.GetBalanceChanges")
+
+	query := `
+		WITH changes AS (
+			SELECT address, big_sum(amount) as change
+			FROM (
+				SELECT to_address as address, value as amount
+				FROM transfers
+				WHERE token_address = ?1 AND block_timestamp >= ?2
+				UNION ALL
+				SELECT from_address as address, big_neg(value) as amount
+				FROM transfers
+				WHERE token_address = ?1 AND block_timestamp >= ?2
+			) t
+			WHERE address <> ?4
+			GROUP BY address
+		)
+		SELECT address, change
+		FROM changes
+		WHERE big_cmp(change, 0) <> 0
+		ORDER BY big_key(big_abs(change)) DESC, address
+		LIMIT ?3
+	`
+
+	var rows []struct {
+		Address string `db:"address"`
+		Change  string `db:"change"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, sqliteTime(since), limit, entities.ZeroAddress); err != nil {
+		return nil, fmt.Errorf("failed to get balance changes: %w", err)
+	}
+
+	result := make([]repositories.BalanceChange, len(rows))
+	for i, row := range rows {
+		result[i] = repositories.BalanceChange(row)
+	}
+
+	return result, nil
+}
+
+// StreamHolderHistory calls fn for each event of a holder's ledger, oldest first. SQLite cannot
+// run big_sum as a window function, so the running balance is kept here while reading the
+// holder's full history; the time range and page are applied as rows go by.
+func (r *SQLiteTransferRepo) StreamHolderHistory(ctx context.Context, tokenAddress, holderAddress string, filter repositories.HolderHistoryFilter, fn func(repositories.HolderBalanceEvent) error) error {
+	ctx = withQueryName(ctx, "transfers.StreamHolderHistory")
+
+	query := `
+		SELECT
+			block_number,
+			block_timestamp,
+			tx_hash,
+			log_index,
+			CASE WHEN to_address = ?2 THEN from_address ELSE to_address END as counterparty,
+			CASE
+				WHEN from_address = ?2 AND to_address = ?2 THEN '0'
+				WHEN to_address = ?2 THEN value
+				ELSE big_neg(value)
+			END as change
+		FROM transfers
+		WHERE token_address = ?1 AND (from_address = ?2 OR to_address = ?2)
+		ORDER BY block_number, log_index
+	`
+
+	rows, err := r.db.QueryxContext(ctx, query, tokenAddress, holderAddress)
+	if err != nil {
+		return fmt.Errorf("failed to query holder history: %w", err)
+	}
+	defer rows.Close()
+
+	balance := new(big.Int)
+	skipped, sent := 0, 0
+	for rows.Next() {
+		var row struct {
+			BlockNumber    int64     `db:"block_number"`
+			BlockTimestamp time.Time `db:"block_timestamp"`
+			TxHash         string    `db:"tx_hash"`
+			LogIndex       int       `db:"log_index"`
+			Counterparty   string    `db:"counterparty"`
+			Change         string    `db:"change"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("failed to scan holder history: %w", err)
+		}
+		balance.Add(balance, bigValue(row.Change))
+
+		if filter.FromTime != nil && row.BlockTimestamp.Before(*filter.FromTime) {
+			continue
+		}
+		if filter.ToTime != nil && !row.BlockTimestamp.Before(*filter.ToTime) {
+			continue
+		}
+		if skipped < filter.Offset {
+			skipped++
+			continue
+		}
+		if sent == filter.Limit {
+			break
+		}
+		sent++
+
+		if err := fn(repositories.HolderBalanceEvent{
+			BlockNumber:    row.BlockNumber,
+			BlockTimestamp: row.BlockTimestamp,
+			TxHash:         row.TxHash,
+			LogIndex:       row.LogIndex,
+			Counterparty:   row.Counterparty,
+			Change:         row.Change,
+			Balance:        balance.String(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read holder history: %w", err)
+	}
+
+	return nil
+}
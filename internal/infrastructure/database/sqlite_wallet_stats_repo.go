@@ -0,0 +1,209 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteWalletStatsRepo implements WalletStatsRepository
+var _ repositories.WalletStatsRepository = (*SQLiteWalletStatsRepo)(nil)
+
+// SQLiteWalletStatsRepo implements WalletStatsRepository using SQLite
+type SQLiteWalletStatsRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteWalletStatsRepo creates a new SQLite wallet stats repository
+func NewSQLiteWalletStatsRepo(db *sqlx.DB) *SQLiteWalletStatsRepo {
+	return &SQLiteWalletStatsRepo{db: db}
+}
+
+// Apply adds newly stored transfers to their senders' and receivers' totals in one transaction
+func (r *SQLiteWalletStatsRepo) Apply(ctx context.Context, transfers []entities.Transfer) error {
+	ctx = withQueryName(ctx, "wallet_stats.Apply")
+
+	deltas, err := walletStatDeltas(transfers)
+	if err != nil || len(deltas) == 0 {
+		return err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO wallet_stats (wallet_address, token_address, transfers_in, transfers_out,
+								  volume_in, volume_out, first_transfer_at, last_transfer_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+		ON CONFLICT (wallet_address, token_address) DO UPDATE SET
+			transfers_in = wallet_stats.transfers_in + excluded.transfers_in,
+			transfers_out = wallet_stats.transfers_out + excluded.transfers_out,
+			volume_in = big_add(wallet_stats.volume_in, excluded.volume_in),
+			volume_out = big_add(wallet_stats.volume_out, excluded.volume_out),
+			first_transfer_at = MIN(wallet_stats.first_transfer_at, excluded.first_transfer_at),
+			last_transfer_at = MAX(wallet_stats.last_transfer_at, excluded.last_transfer_at),
+			updated_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, d := range deltas {
+		if _, err := stmt.ExecContext(ctx,
+			d.wallet, d.token, d.transfersIn, d.transfersOut,
+			d.volumeIn.String(), d.volumeOut.String(), sqliteTime(d.first), sqliteTime(d.last),
+		); err != nil {
+			return fmt.Errorf("failed to update wallet stats: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// sqliteWalletStatsTotalsQuery recomputes the totals of token ?1 from stored transfers. The %[1]s
+// and %[2]s conditions restrict the receivers and senders counted, for a refresh of some wallets.
+const sqliteWalletStatsTotalsQuery = `
+	INSERT INTO wallet_stats (wallet_address, token_address, transfers_in, transfers_out,
+							  volume_in, volume_out, first_transfer_at, last_transfer_at)
+	SELECT wallet, ?1, SUM(transfers_in), SUM(transfers_out),
+		big_sum(volume_in), big_sum(volume_out), MIN(first_transfer_at), MAX(last_transfer_at)
+	FROM (
+		SELECT to_address AS wallet, COUNT(*) AS transfers_in, 0 AS transfers_out,
+			big_sum(value) AS volume_in, 0 AS volume_out,
+			MIN(block_timestamp) AS first_transfer_at, MAX(block_timestamp) AS last_transfer_at
+		FROM transfers
+		WHERE token_address = ?1 %[1]s
+		GROUP BY to_address
+		UNION ALL
+		SELECT from_address, 0, COUNT(*), 0, big_sum(value), MIN(block_timestamp), MAX(block_timestamp)
+		FROM transfers
+		WHERE token_address = ?1 %[2]s
+		GROUP BY from_address
+	) totals
+	GROUP BY wallet
+`
+
+// Refresh recomputes a token's totals for the given wallets in one transaction
+func (r *SQLiteWalletStatsRepo) Refresh(ctx context.Context, tokenAddress string, wallets []string) error {
+	ctx = withQueryName(ctx, "wallet_stats.Refresh")
+
+	if len(wallets) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	deleteQuery := `DELETE FROM wallet_stats WHERE token_address = ?1 AND wallet_address IN (SELECT value FROM json_each(?2))`
+	if _, err := tx.ExecContext(ctx, deleteQuery, tokenAddress, sqliteList(wallets)); err != nil {
+		return fmt.Errorf("failed to delete wallet stats: %w", err)
+	}
+
+	insertQuery := fmt.Sprintf(sqliteWalletStatsTotalsQuery,
+		"AND to_address IN (SELECT value FROM json_each(?2))",
+		"AND from_address IN (SELECT value FROM json_each(?2))",
+	)
+	if _, err := tx.ExecContext(ctx, insertQuery, tokenAddress, sqliteList(wallets)); err != nil {
+		return fmt.Errorf("failed to insert wallet stats: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Rebuild recomputes all of a token's totals in one transaction
+func (r *SQLiteWalletStatsRepo) Rebuild(ctx context.Context, tokenAddress string) error {
+	ctx = withQueryName(ctx, "wallet_stats.Rebuild")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM wallet_stats WHERE token_address = ?1`, tokenAddress); err != nil {
+		return fmt.Errorf("failed to delete wallet stats: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(sqliteWalletStatsTotalsQuery, "", ""), tokenAddress); err != nil {
+		return fmt.Errorf("failed to insert wallet stats: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetRangeWallets returns the senders and receivers of a token's transfers in [fromBlock, toBlock]
+func (r *SQLiteWalletStatsRepo) GetRangeWallets(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) ([]string, error) {
+	ctx = withQueryName(ctx, "wallet_stats.GetRangeWallets")
+
+	query := `
+		SELECT from_address FROM transfers
+		WHERE token_address = ?1 AND block_number >= ?2 AND block_number <= ?3
+		UNION
+		SELECT to_address FROM transfers
+		WHERE token_address = ?1 AND block_number >= ?2 AND block_number <= ?3
+	`
+
+	var wallets []string
+	if err := r.db.SelectContext(ctx, &wallets, query, tokenAddress, fromBlock, toBlock); err != nil {
+		return nil, fmt.Errorf("failed to get range wallets: %w", err)
+	}
+	return wallets, nil
+}
+
+// GetSummary sums a wallet's totals across tokens
+func (r *SQLiteWalletStatsRepo) GetSummary(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, bool, error) {
+	ctx = withQueryName(ctx, "wallet_stats.GetSummary")
+
+	query := `
+		SELECT
+			COALESCE(SUM(transfers_in), 0) as total_in,
+			COALESCE(SUM(transfers_out), 0) as total_out,
+			big_sum(volume_in) as volume_in,
+			big_sum(volume_out) as volume_out,
+			COUNT(*) as unique_tokens,
+			MIN(first_transfer_at) as first_transfer,
+			MAX(last_transfer_at) as last_transfer
+		FROM wallet_stats
+		WHERE wallet_address = ?1
+	`
+
+	var row summaryRow
+	if err := r.db.GetContext(ctx, &row, query, walletAddress); err != nil {
+		return nil, false, fmt.Errorf("failed to get wallet stats: %w", err)
+	}
+	if row.UniqueTokens == 0 {
+		return nil, false, nil
+	}
+
+	return &repositories.WalletTransferSummary{
+		TotalTransfersIn:  row.TotalIn,
+		TotalTransfersOut: row.TotalOut,
+		TotalVolumeIn:     row.VolumeIn,
+		TotalVolumeOut:    row.VolumeOut,
+		UniqueTokens:      row.UniqueTokens,
+		FirstTransferAt:   sqliteTimestamp(row.FirstTransfer),
+		LastTransferAt:    sqliteTimestamp(row.LastTransfer),
+	}, true, nil
+}
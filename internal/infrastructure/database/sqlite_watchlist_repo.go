@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SQLiteWatchlistRepo implements WatchlistRepository
+var _ repositories.WatchlistRepository = (*SQLiteWatchlistRepo)(nil)
+
+// SQLiteWatchlistRepo implements WatchlistRepository using SQLite
+type SQLiteWatchlistRepo struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteWatchlistRepo creates a new SQLite watchlist repository
+func NewSQLiteWatchlistRepo(db *sqlx.DB) *SQLiteWatchlistRepo {
+	return &SQLiteWatchlistRepo{db: db}
+}
+
+// Create inserts a watchlist with its addresses
+func (r *SQLiteWatchlistRepo) Create(ctx context.Context, watchlist *entities.Watchlist) error {
+	ctx = withQueryName(ctx, "watchlists.Create")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO watchlists (name)
+		VALUES (?1)
+		RETURNING id, created_at, updated_at
+	`
+	row := tx.QueryRowxContext(ctx, query, watchlist.Name)
+	if err := row.Scan(&watchlist.ID, &watchlist.CreatedAt, &watchlist.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create watchlist: %w", err)
+	}
+
+	if err := sqliteInsertWatchlistAddresses(ctx, tx, watchlist.ID, watchlist.Addresses); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a watchlist with its addresses
+func (r *SQLiteWatchlistRepo) GetByID(ctx context.Context, id int64) (*entities.Watchlist, error) {
+	ctx = withQueryName(ctx, "watchlists.GetByID")
+
+	var watchlist entities.Watchlist
+	query := `SELECT id, name, created_at, updated_at FROM watchlists WHERE id = ?1`
+
+	if err := r.db.GetContext(ctx, &watchlist, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+
+	addrQuery := `
+		SELECT address FROM watchlist_addresses
+		WHERE watchlist_id = ?1
+		ORDER BY added_at, address
+	`
+	if err := r.db.SelectContext(ctx, &watchlist.Addresses, addrQuery, id); err != nil {
+		return nil, fmt.Errorf("failed to get watchlist addresses: %w", err)
+	}
+
+	return &watchlist, nil
+}
+
+// Delete removes a watchlist; its addresses are removed by ON DELETE CASCADE
+func (r *SQLiteWatchlistRepo) Delete(ctx context.Context, id int64) (bool, error) {
+	ctx = withQueryName(ctx, "watchlists.Delete")
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM watchlists WHERE id = ?1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete watchlist: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// AddAddresses adds addresses to a watchlist, skipping ones already present
+func (r *SQLiteWatchlistRepo) AddAddresses(ctx context.Context, id int64, addresses []string) error {
+	ctx = withQueryName(ctx, "watchlists.AddAddresses")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := sqliteInsertWatchlistAddresses(ctx, tx, id, addresses); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE watchlists SET updated_at = `+sqliteNow+` WHERE id = ?1`, id); err != nil {
+		return fmt.Errorf("failed to touch watchlist: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveAddress removes an address from a watchlist
+func (r *SQLiteWatchlistRepo) RemoveAddress(ctx context.Context, id int64, address string) (bool, error) {
+	ctx = withQueryName(ctx, "watchlists.RemoveAddress")
+
+	query := `DELETE FROM watchlist_addresses WHERE watchlist_id = ?1 AND address = ?2`
+	result, err := r.db.ExecContext(ctx, query, id, address)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove watchlist address: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if n > 0 {
+		if _, err := r.db.ExecContext(ctx, `UPDATE watchlists SET updated_at = `+sqliteNow+` WHERE id = ?1`, id); err != nil {
+			return false, fmt.Errorf("failed to touch watchlist: %w", err)
+		}
+	}
+
+	return n > 0, nil
+}
+
+func sqliteInsertWatchlistAddresses(ctx context.Context, tx *sqlx.Tx, id int64, addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO watchlist_addresses (watchlist_id, address)
+		SELECT ?1, value FROM json_each(?2)
+		WHERE true -- keeps SQLite from reading ON CONFLICT as part of the SELECT
+		ON CONFLICT (watchlist_id, address) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, id, sqliteList(addresses)); err != nil {
+		return fmt.Errorf("failed to insert watchlist addresses: %w", err)
+	}
+
+	return nil
+}
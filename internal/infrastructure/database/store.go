@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Store bundles the repositories of one storage backend.
+// Services and commands depend only on the repository interfaces, so a backend is
+// added by implementing them and selecting it in Open.
+type Store struct {
+	Tokens          repositories.TokenRepository
+	Transfers       repositories.TransferRepository
+	TransferFlags   repositories.TransferFlagRepository
+	FailedRanges    repositories.FailedRangeRepository
+	IndexerState    repositories.IndexerStateRepository
+	Portfolio       repositories.PortfolioRepository
+	WalletStats     repositories.WalletStatsRepository
+	Swaps           repositories.SwapRepository
+	DailyStats      repositories.DailyStatsRepository
+	Watchlists      repositories.WatchlistRepository
+	AlertRules      repositories.AlertRuleRepository
+	DenyList        repositories.DenyListRepository
+	HolderSnapshots repositories.HolderSnapshotRepository
+	HolderCounts    repositories.HolderCountRepository
+	AdvancedStats   repositories.AdvancedStatsRepository
+	Entities        repositories.EntityRepository
+	EthTransfers    repositories.EthTransferRepository
+	RawLogs         repositories.RawLogRepository
+	Signatures      repositories.MethodSignatureRepository
+	ScopedEvents    repositories.ScopedEventStateRepository
+	Tenants         repositories.TenantRepository
+	AddressNotes    repositories.AddressNoteRepository
+	Contracts       repositories.ContractRepository
+
+	healthCheck    func(ctx context.Context) error
+	schemaVersion  func(ctx context.Context) (int64, bool, error)
+	missingTables  func(ctx context.Context, tables []string) ([]string, error)
+	missingColumns func(ctx context.Context, columns []string) ([]string, error)
+	close          func() error
+}
+
+// Open connects to the backend selected by cfg.Driver
+func Open(cfg config.DatabaseConfig, logger *zap.Logger) (*Store, error) {
+	switch cfg.Driver {
+	case "postgres":
+		db, err := NewPostgresDB(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return NewPostgresStore(db), nil
+	case "sqlite":
+		db, err := NewSQLiteDB(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return NewSQLiteStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q (available: postgres, sqlite)", cfg.Driver)
+	}
+}
+
+// NewPostgresStore creates the PostgreSQL repositories on an open connection
+func NewPostgresStore(db *PostgresDB) *Store {
+	return &Store{
+		Tokens:          NewTokenRepo(db.DB()),
+		Transfers:       NewTransferRepo(db.DB()),
+		TransferFlags:   NewTransferFlagRepo(db.DB()),
+		FailedRanges:    NewFailedRangeRepo(db.DB()),
+		IndexerState:    NewIndexerStateRepo(db.DB()),
+		Portfolio:       NewPortfolioRepo(db.DB()),
+		WalletStats:     NewWalletStatsRepo(db.DB()),
+		Swaps:           NewSwapRepo(db.DB()),
+		DailyStats:      NewDailyStatsRepo(db.DB()),
+		Watchlists:      NewWatchlistRepo(db.DB()),
+		AlertRules:      NewAlertRuleRepo(db.DB()),
+		DenyList:        NewDenyListRepo(db.DB()),
+		HolderSnapshots: NewHolderSnapshotRepo(db.DB()),
+		HolderCounts:    NewHolderCountRepo(db.DB()),
+		AdvancedStats:   NewAdvancedStatsRepo(db.DB()),
+		Entities:        NewEntityRepo(db.DB()),
+		EthTransfers:    NewEthTransferRepo(db.DB()),
+		RawLogs:         NewRawLogRepo(db.DB()),
+		Signatures:      NewMethodSignatureRepo(db.DB()),
+		ScopedEvents:    NewScopedEventStateRepo(db.DB()),
+		Tenants:         NewTenantRepo(db.DB()),
+		AddressNotes:    NewAddressNoteRepo(db.DB()),
+		Contracts:       NewContractRepo(db.DB()),
+		healthCheck:     db.HealthCheck,
+		schemaVersion:   db.SchemaVersion,
+		missingTables:   db.MissingTables,
+		missingColumns:  db.MissingColumns,
+		close:           db.Close,
+	}
+}
+
+// NewSQLiteStore creates the SQLite repositories on an open database
+func NewSQLiteStore(db *SQLiteDB) *Store {
+	return &Store{
+		Tokens:          NewSQLiteTokenRepo(db.DB()),
+		Transfers:       NewSQLiteTransferRepo(db.DB()),
+		TransferFlags:   NewSQLiteTransferFlagRepo(db.DB()),
+		FailedRanges:    NewSQLiteFailedRangeRepo(db.DB()),
+		IndexerState:    NewSQLiteIndexerStateRepo(db.DB()),
+		Portfolio:       NewSQLitePortfolioRepo(db.DB()),
+		WalletStats:     NewSQLiteWalletStatsRepo(db.DB()),
+		Swaps:           NewSQLiteSwapRepo(db.DB()),
+		DailyStats:      NewSQLiteDailyStatsRepo(db.DB()),
+		Watchlists:      NewSQLiteWatchlistRepo(db.DB()),
+		AlertRules:      NewSQLiteAlertRuleRepo(db.DB()),
+		DenyList:        NewSQLiteDenyListRepo(db.DB()),
+		HolderSnapshots: NewSQLiteHolderSnapshotRepo(db.DB()),
+		HolderCounts:    NewSQLiteHolderCountRepo(db.DB()),
+		AdvancedStats:   NewSQLiteAdvancedStatsRepo(db.DB()),
+		Entities:        NewSQLiteEntityRepo(db.DB()),
+		EthTransfers:    NewSQLiteEthTransferRepo(db.DB()),
+		RawLogs:         NewSQLiteRawLogRepo(db.DB()),
+		Signatures:      NewSQLiteMethodSignatureRepo(db.DB()),
+		ScopedEvents:    NewSQLiteScopedEventStateRepo(db.DB()),
+		Tenants:         NewSQLiteTenantRepo(db.DB()),
+		AddressNotes:    NewSQLiteAddressNoteRepo(db.DB()),
+		Contracts:       NewSQLiteContractRepo(db.DB()),
+		healthCheck:     db.HealthCheck,
+		schemaVersion:   db.SchemaVersion,
+		missingTables:   db.MissingTables,
+		missingColumns:  db.MissingColumns,
+		close:           db.Close,
+	}
+}
+
+// HealthCheck verifies the backend is reachable
+func (s *Store) HealthCheck(ctx context.Context) error {
+	return s.healthCheck(ctx)
+}
+
+// SchemaVersion returns the backend's applied migration version and whether the last migration
+// failed halfway; compare it with the package's SchemaVersion
+func (s *Store) SchemaVersion(ctx context.Context) (int64, bool, error) {
+	return s.schemaVersion(ctx)
+}
+
+// MissingTables returns the given tables that do not exist in the database
+func (s *Store) MissingTables(ctx context.Context, tables []string) ([]string, error) {
+	return s.missingTables(ctx, tables)
+}
+
+// MissingColumns returns the given table.column names that do not exist in the database
+func (s *Store) MissingColumns(ctx context.Context, columns []string) ([]string, error) {
+	return s.missingColumns(ctx, columns)
+}
+
+// Close releases the backend's connections
+func (s *Store) Close() error {
+	return s.close()
+}
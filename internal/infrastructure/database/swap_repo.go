@@ -0,0 +1,223 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure SwapRepo implements SwapRepository
+var _ repositories.SwapRepository = (*SwapRepo)(nil)
+
+// SwapRepo implements SwapRepository using PostgreSQL
+type SwapRepo struct {
+	db *sqlx.DB
+}
+
+// NewSwapRepo creates a new swap repository
+func NewSwapRepo(db *sqlx.DB) *SwapRepo {
+	return &SwapRepo{db: db}
+}
+
+// StoreEvents inserts decoded swaps in a single transaction, skipping duplicates
+func (r *SwapRepo) StoreEvents(ctx context.Context, events []any) error {
+	ctx = withQueryName(ctx, "swaps.StoreEvents")
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO swaps (tx_hash, log_index, block_number, block_timestamp, pool_address, protocol,
+						   sender, recipient, token0_address, token1_address, amount0, amount1)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (tx_hash, log_index, block_timestamp) DO NOTHING
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		s, ok := event.(*entities.Swap)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T", event)
+		}
+
+		_, err := stmt.ExecContext(ctx,
+			s.TxHash,
+			s.LogIndex,
+			s.BlockNumber,
+			s.BlockTimestamp,
+			s.PoolAddress,
+			s.Protocol,
+			s.Sender,
+			s.Recipient,
+			s.Token0Address,
+			s.Token1Address,
+			s.Amount0String,
+			s.Amount1String,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert swap: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertPool creates or updates a DEX pool
+func (r *SwapRepo) UpsertPool(ctx context.Context, pool *entities.DexPool) error {
+	ctx = withQueryName(ctx, "swaps.UpsertPool")
+
+	query := `
+		INSERT INTO dex_pools (address, token0_address, token1_address)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (address) DO UPDATE SET
+			token0_address = EXCLUDED.token0_address,
+			token1_address = EXCLUDED.token1_address
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, pool.Address, pool.Token0Address, pool.Token1Address); err != nil {
+		return fmt.Errorf("failed to upsert pool: %w", err)
+	}
+
+	return nil
+}
+
+// GetPool retrieves a pool by address
+func (r *SwapRepo) GetPool(ctx context.Context, address string) (*entities.DexPool, error) {
+	ctx = withQueryName(ctx, "swaps.GetPool")
+
+	var pool entities.DexPool
+	query := `SELECT * FROM dex_pools WHERE address = $1`
+
+	if err := r.db.GetContext(ctx, &pool, query, address); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pool: %w", err)
+	}
+
+	return &pool, nil
+}
+
+// GetByFilter retrieves swaps matching the filter, newest first
+func (r *SwapRepo) GetByFilter(ctx context.Context, filter entities.SwapFilter) ([]entities.Swap, error) {
+	ctx = withQueryName(ctx, "swaps.GetByFilter")
+
+	where, args := buildSwapConditions(filter)
+	query := fmt.Sprintf(`
+		SELECT id, tx_hash, log_index, block_number, block_timestamp, pool_address, protocol,
+			   sender, recipient, token0_address, token1_address,
+			   amount0::TEXT AS amount0, amount1::TEXT AS amount1, created_at
+		FROM swaps%s
+		ORDER BY block_timestamp DESC, log_index DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	var swaps []entities.Swap
+	if err := r.db.SelectContext(ctx, &swaps, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get swaps: %w", err)
+	}
+
+	return swaps, nil
+}
+
+// GetCount returns the count of swaps matching the filter
+func (r *SwapRepo) GetCount(ctx context.Context, filter entities.SwapFilter) (int64, error) {
+	ctx = withQueryName(ctx, "swaps.GetCount")
+
+	where, args := buildSwapConditions(filter)
+	query := "SELECT COUNT(*) FROM swaps" + where
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to get swap count: %w", err)
+	}
+
+	return count, nil
+}
+
+// buildSwapConditions builds the WHERE clause for a swap filter
+func buildSwapConditions(filter entities.SwapFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.PoolAddress != nil {
+		args = append(args, *filter.PoolAddress)
+		conditions = append(conditions, fmt.Sprintf("pool_address = $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+type swapVolumeRow struct {
+	SwapCount    int64  `db:"swap_count"`
+	PoolCount    int64  `db:"pool_count"`
+	TotalVolume  string `db:"total_volume"`
+	SwapCount24h int64  `db:"swap_count_24h"`
+	Volume24h    string `db:"volume_24h"`
+	SwapCount7d  int64  `db:"swap_count_7d"`
+	Volume7d     string `db:"volume_7d"`
+}
+
+// GetTokenSwapVolume returns swap volume for a token across all pools it trades in
+func (r *SwapRepo) GetTokenSwapVolume(ctx context.Context, tokenAddress string) (*repositories.SwapVolumeResult, error) {
+	ctx = withQueryName(ctx, "swaps.GetTokenSwapVolume")
+
+	query := `
+		WITH token_swaps AS (
+			SELECT pool_address, block_timestamp, ABS(amount0) AS amount FROM swaps WHERE token0_address = $1
+			UNION ALL
+			SELECT pool_address, block_timestamp, ABS(amount1) AS amount FROM swaps WHERE token1_address = $1
+		)
+		SELECT
+			COUNT(*) AS swap_count,
+			COUNT(DISTINCT pool_address) AS pool_count,
+			COALESCE(SUM(amount), 0)::TEXT AS total_volume,
+			COUNT(*) FILTER (WHERE block_timestamp >= NOW() - INTERVAL '24 hours') AS swap_count_24h,
+			COALESCE(SUM(amount) FILTER (WHERE block_timestamp >= NOW() - INTERVAL '24 hours'), 0)::TEXT AS volume_24h,
+			COUNT(*) FILTER (WHERE block_timestamp >= NOW() - INTERVAL '7 days') AS swap_count_7d,
+			COALESCE(SUM(amount) FILTER (WHERE block_timestamp >= NOW() - INTERVAL '7 days'), 0)::TEXT AS volume_7d
+		FROM token_swaps
+	`
+
+	var row swapVolumeRow
+	if err := r.db.GetContext(ctx, &row, query, tokenAddress); err != nil {
+		return nil, fmt.Errorf("failed to get swap volume: %w", err)
+	}
+
+	return &repositories.SwapVolumeResult{
+		SwapCount:    row.SwapCount,
+		PoolCount:    row.PoolCount,
+		TotalVolume:  row.TotalVolume,
+		SwapCount24h: row.SwapCount24h,
+		Volume24h:    row.Volume24h,
+		SwapCount7d:  row.SwapCount7d,
+		Volume7d:     row.Volume7d,
+	}, nil
+}
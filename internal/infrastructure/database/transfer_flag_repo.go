@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure TransferFlagRepo implements TransferFlagRepository
+var _ repositories.TransferFlagRepository = (*TransferFlagRepo)(nil)
+
+// TransferFlagRepo implements TransferFlagRepository using PostgreSQL
+type TransferFlagRepo struct {
+	db *sqlx.DB
+}
+
+// NewTransferFlagRepo creates a new transfer flag repository
+func NewTransferFlagRepo(db *sqlx.DB) *TransferFlagRepo {
+	return &TransferFlagRepo{db: db}
+}
+
+// Add stores flags, skipping those already stored, and returns the number added
+func (r *TransferFlagRepo) Add(ctx context.Context, flags []entities.TransferFlag) (int64, error) {
+	ctx = withQueryName(ctx, "transfer_flags.Add")
+
+	if len(flags) == 0 {
+		return 0, nil
+	}
+
+	txHashes := make([]string, len(flags))
+	logIndexes := make([]int64, len(flags))
+	names := make([]string, len(flags))
+	tokens := make([]string, len(flags))
+	for i, f := range flags {
+		txHashes[i], logIndexes[i], names[i], tokens[i] = f.TxHash, int64(f.LogIndex), f.Flag, f.TokenAddress
+	}
+
+	query := `
+		INSERT INTO transfer_flags (tx_hash, log_index, flag, token_address)
+		SELECT * FROM UNNEST($1::varchar[], $2::int[], $3::varchar[], $4::varchar[])
+		ON CONFLICT (tx_hash, log_index, flag) DO NOTHING
+	`
+	result, err := r.db.ExecContext(ctx, query, pq.Array(txHashes), pq.Array(logIndexes), pq.Array(names), pq.Array(tokens))
+	if err != nil {
+		return 0, fmt.Errorf("failed to add transfer flags: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// GetByTxHashes returns the flags of the transfers in the given transactions
+func (r *TransferFlagRepo) GetByTxHashes(ctx context.Context, txHashes []string) ([]entities.TransferFlag, error) {
+	ctx = withQueryName(ctx, "transfer_flags.GetByTxHashes")
+
+	if len(txHashes) == 0 {
+		return nil, nil
+	}
+
+	var flags []entities.TransferFlag
+	query := `
+		SELECT tx_hash, log_index, flag, token_address, flagged_at
+		FROM transfer_flags
+		WHERE tx_hash = ANY($1)
+		ORDER BY tx_hash, log_index, flag
+	`
+	if err := r.db.SelectContext(ctx, &flags, query, pq.Array(txHashes)); err != nil {
+		return nil, fmt.Errorf("failed to get transfer flags: %w", err)
+	}
+
+	return flags, nil
+}
+
+// GetFlaggedStats counts a token's flagged transfers in [from, to) and sums their value. Transfers
+// are joined so flags of transfers removed by a reorg are not counted.
+func (r *TransferFlagRepo) GetFlaggedStats(ctx context.Context, tokenAddress string, from, to time.Time) (*repositories.WindowStats, error) {
+	ctx = withQueryName(ctx, "transfer_flags.GetFlaggedStats")
+
+	query := `
+		SELECT
+			COUNT(*) as transfers,
+			COALESCE(SUM(t.value), 0)::TEXT as volume
+		FROM transfers t
+		WHERE t.token_address = $1
+		AND t.block_timestamp >= $2 AND t.block_timestamp < $3
+		AND EXISTS (
+			SELECT 1 FROM transfer_flags f
+			WHERE f.tx_hash = t.tx_hash AND f.log_index = t.log_index
+		)
+	`
+
+	var row windowStatsRow
+	if err := r.db.GetContext(ctx, &row, query, tokenAddress, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get flagged transfer stats: %w", err)
+	}
+
+	return &repositories.WindowStats{
+		Transfers: row.Transfers,
+		Volume:    row.Volume,
+	}, nil
+}
@@ -3,10 +3,12 @@ package database
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
 	"github.com/bimakw/chain-indexer/internal/domain/repositories"
@@ -27,6 +29,8 @@ func NewTransferRepo(db *sqlx.DB) *TransferRepo {
 
 // GetByFilter retrieves transfers matching the given filter
 func (r *TransferRepo) GetByFilter(ctx context.Context, filter entities.TransferFilter) ([]entities.Transfer, error) {
+	ctx = withQueryName(ctx, "transfers.GetByFilter")
+
 	query, args := r.buildFilterQuery(filter, false)
 
 	var transfers []entities.Transfer
@@ -39,6 +43,8 @@ func (r *TransferRepo) GetByFilter(ctx context.Context, filter entities.Transfer
 
 // GetCount returns the count of transfers matching the filter
 func (r *TransferRepo) GetCount(ctx context.Context, filter entities.TransferFilter) (int64, error) {
+	ctx = withQueryName(ctx, "transfers.GetCount")
+
 	query, args := r.buildFilterQuery(filter, true)
 
 	var count int64
@@ -51,6 +57,30 @@ func (r *TransferRepo) GetCount(ctx context.Context, filter entities.TransferFil
 
 // buildFilterQuery builds the SQL query for filtering transfers
 func (r *TransferRepo) buildFilterQuery(filter entities.TransferFilter, countOnly bool) (string, []interface{}) {
+	whereClause, args := filterWhereClause(filter)
+	argIdx := len(args) + 1
+
+	if countOnly {
+		return fmt.Sprintf("SELECT COUNT(*) FROM transfers %s", whereClause), args
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tx_hash, log_index, block_number, block_timestamp,
+			   token_address, from_address, to_address, value, initiator, method_selector, created_at
+		FROM transfers
+		%s
+		ORDER BY block_timestamp DESC, block_number DESC, log_index DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIdx, argIdx+1)
+
+	args = append(args, filter.Limit, filter.Offset)
+
+	return query, args
+}
+
+// filterWhereClause builds the WHERE clause for a filter, empty when it matches every transfer.
+// Limit and Offset are left to the caller.
+func filterWhereClause(filter entities.TransferFilter) (string, []interface{}) {
 	var conditions []string
 	var args []interface{}
 	argIdx := 1
@@ -61,6 +91,18 @@ func (r *TransferRepo) buildFilterQuery(filter entities.TransferFilter, countOnl
 		argIdx++
 	}
 
+	if len(filter.Tokens) > 0 {
+		conditions = append(conditions, fmt.Sprintf("token_address = ANY($%d)", argIdx))
+		args = append(args, pq.Array(filter.Tokens))
+		argIdx++
+	}
+
+	if len(filter.ExcludeTokens) > 0 {
+		conditions = append(conditions, fmt.Sprintf("token_address <> ALL($%d)", argIdx))
+		args = append(args, pq.Array(filter.ExcludeTokens))
+		argIdx++
+	}
+
 	if filter.FromAddress != nil {
 		conditions = append(conditions, fmt.Sprintf("from_address = $%d", argIdx))
 		args = append(args, *filter.FromAddress)
@@ -79,6 +121,31 @@ func (r *TransferRepo) buildFilterQuery(filter entities.TransferFilter, countOnl
 		argIdx++
 	}
 
+	if len(filter.Addresses) > 0 {
+		// = ANY over an array parameter lets the planner combine idx_transfers_from and idx_transfers_to
+		conditions = append(conditions, fmt.Sprintf("(from_address = ANY($%d) OR to_address = ANY($%d))", argIdx, argIdx))
+		args = append(args, pq.Array(filter.Addresses))
+		argIdx++
+	}
+
+	if filter.Initiator != nil {
+		conditions = append(conditions, fmt.Sprintf("initiator = $%d", argIdx))
+		args = append(args, *filter.Initiator)
+		argIdx++
+	}
+
+	if filter.Selector != nil {
+		conditions = append(conditions, fmt.Sprintf("method_selector = $%d", argIdx))
+		args = append(args, *filter.Selector)
+		argIdx++
+	}
+
+	if filter.Method != nil {
+		conditions = append(conditions, fmt.Sprintf("method_selector IN (SELECT selector FROM method_signatures WHERE label = $%d)", argIdx))
+		args = append(args, *filter.Method)
+		argIdx++
+	}
+
 	if filter.FromBlock != nil {
 		conditions = append(conditions, fmt.Sprintf("block_number >= $%d", argIdx))
 		args = append(args, *filter.FromBlock)
@@ -103,56 +170,126 @@ func (r *TransferRepo) buildFilterQuery(filter entities.TransferFilter, countOnl
 		argIdx++
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	if filter.MinValue != nil {
+		conditions = append(conditions, fmt.Sprintf("value >= $%d::NUMERIC", argIdx))
+		args = append(args, filter.MinValue.String())
+		argIdx++
 	}
 
-	if countOnly {
-		return fmt.Sprintf("SELECT COUNT(*) FROM transfers %s", whereClause), args
+	if filter.MaxValue != nil {
+		conditions = append(conditions, fmt.Sprintf("value <= $%d::NUMERIC", argIdx))
+		args = append(args, filter.MaxValue.String())
+		argIdx++
 	}
 
-	query := fmt.Sprintf(`
-		SELECT id, tx_hash, log_index, block_number, block_timestamp,
-			   token_address, from_address, to_address, value, created_at
-		FROM transfers
-		%s
-		ORDER BY block_timestamp DESC, log_index DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIdx, argIdx+1)
+	if filter.ExcludeZero {
+		conditions = append(conditions, "value > 0")
+	}
 
-	args = append(args, filter.Limit, filter.Offset)
+	if filter.ExcludeSelf {
+		conditions = append(conditions, "from_address <> to_address")
+	}
 
-	return query, args
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(block_timestamp, block_number, log_index) < ($%d, $%d, $%d)", argIdx, argIdx+1, argIdx+2))
+		args = append(args, filter.After.BlockTimestamp, filter.After.BlockNumber, filter.After.LogIndex)
+		argIdx += 3
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
 }
 
-// BatchInsert inserts multiple transfers in a single transaction
-func (r *TransferRepo) BatchInsert(ctx context.Context, transfers []entities.Transfer) error {
+// BatchInsert inserts multiple transfers in a single transaction, returning how many were new
+func (r *TransferRepo) BatchInsert(ctx context.Context, transfers []entities.Transfer) (int64, error) {
+	ctx = withQueryName(ctx, "transfers.BatchInsert")
+
 	if len(transfers) == 0 {
-		return nil
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	inserted, err := insertTransfers(ctx, tx, transfers)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	return inserted, nil
+}
+
+// ReplaceRange deletes a token's transfers in a block range and inserts the given ones in one transaction
+func (r *TransferRepo) ReplaceRange(ctx context.Context, tokenAddress string, fromBlock, toBlock int64, transfers []entities.Transfer) (int64, int64, error) {
+	ctx = withQueryName(ctx, "transfers.ReplaceRange")
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM transfers WHERE token_address = $1 AND block_number >= $2 AND block_number <= $3`,
+		tokenAddress, fromBlock, toBlock,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to delete transfers: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	inserted, err := insertTransfers(ctx, tx, transfers)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return deleted, inserted, nil
+}
+
+// insertTransfers inserts transfers within an open transaction, skipping duplicates, and returns
+// the number inserted
+func insertTransfers(ctx context.Context, tx *sqlx.Tx, transfers []entities.Transfer) (int64, error) {
+	if len(transfers) == 0 {
+		return 0, nil
+	}
+
 	query := `
 		INSERT INTO transfers (tx_hash, log_index, block_number, block_timestamp,
-							   token_address, from_address, to_address, value)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+							   token_address, from_address, to_address, value, initiator, method_selector)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		ON CONFLICT (tx_hash, log_index, block_timestamp) DO NOTHING
 	`
 
 	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
+	var inserted int64
 	for _, t := range transfers {
-		_, err := stmt.ExecContext(ctx,
+		value, err := transferValue(t)
+		if err != nil {
+			return 0, err
+		}
+		result, err := stmt.ExecContext(ctx,
 			t.TxHash,
 			t.LogIndex,
 			t.BlockNumber,
@@ -160,22 +297,46 @@ func (r *TransferRepo) BatchInsert(ctx context.Context, transfers []entities.Tra
 			t.TokenAddress,
 			t.FromAddress,
 			t.ToAddress,
-			t.ValueString,
+			value,
+			t.Initiator,
+			t.MethodSelector,
 		)
 		if err != nil {
-			return fmt.Errorf("failed to insert transfer: %w", err)
+			return 0, fmt.Errorf("failed to insert transfer: %w", err)
 		}
+		// ON CONFLICT DO NOTHING affects no row for a duplicate
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		inserted += n
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
+	return inserted, nil
+}
 
-	return nil
+// transferValue returns a transfer's value as the decimal bound to the NUMERIC(78,0) value
+// column. It is taken from Value when the parser set it, so that the stored number is the
+// decoded uint256 rather than whatever ValueString holds, and anything that is not a uint256
+// is rejected instead of being rounded or truncated by the database.
+func transferValue(t entities.Transfer) (string, error) {
+	value := t.Value
+	if value == nil {
+		var ok bool
+		if value, ok = new(big.Int).SetString(t.ValueString, 10); !ok {
+			return "", fmt.Errorf("invalid value %q for transfer %s:%d", t.ValueString, t.TxHash, t.LogIndex)
+		}
+	}
+	if value.Sign() < 0 || value.BitLen() > 256 {
+		return "", fmt.Errorf("value %s for transfer %s:%d is not a uint256", value, t.TxHash, t.LogIndex)
+	}
+	return value.String(), nil
 }
 
 // GetLatestBlock returns the latest indexed block for a token
 func (r *TransferRepo) GetLatestBlock(ctx context.Context, tokenAddress string) (int64, error) {
+	ctx = withQueryName(ctx, "transfers.GetLatestBlock")
+
 	query := `SELECT COALESCE(MAX(block_number), 0) FROM transfers WHERE token_address = $1`
 
 	var blockNumber int64
@@ -186,6 +347,50 @@ func (r *TransferRepo) GetLatestBlock(ctx context.Context, tokenAddress string)
 	return blockNumber, nil
 }
 
+// GetLatestID returns the highest transfer ID, or 0 if there are no transfers
+func (r *TransferRepo) GetLatestID(ctx context.Context) (int64, error) {
+	ctx = withQueryName(ctx, "transfers.GetLatestID")
+
+	query := `SELECT COALESCE(MAX(id), 0) FROM transfers`
+
+	var id int64
+	if err := r.db.GetContext(ctx, &id, query); err != nil {
+		return 0, fmt.Errorf("failed to get latest transfer id: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetAfterID retrieves transfers matching the filter with an ID above afterID, in insertion order
+func (r *TransferRepo) GetAfterID(ctx context.Context, filter entities.TransferFilter, afterID int64) ([]entities.Transfer, error) {
+	ctx = withQueryName(ctx, "transfers.GetAfterID")
+
+	whereClause, args := filterWhereClause(filter)
+	idCondition := fmt.Sprintf("id > $%d", len(args)+1)
+	if whereClause == "" {
+		whereClause = "WHERE " + idCondition
+	} else {
+		whereClause += " AND " + idCondition
+	}
+	args = append(args, afterID, filter.Limit)
+
+	query := fmt.Sprintf(`
+		SELECT id, tx_hash, log_index, block_number, block_timestamp,
+			   token_address, from_address, to_address, value, initiator, method_selector, created_at
+		FROM transfers
+		%s
+		ORDER BY id
+		LIMIT $%d
+	`, whereClause, len(args))
+
+	var transfers []entities.Transfer
+	if err := r.db.SelectContext(ctx, &transfers, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get transfers after id: %w", err)
+	}
+
+	return transfers, nil
+}
+
 // statsRow holds the result of the stats query
 type statsRow struct {
 	TotalTransfers int64   `db:"total_transfers"`
@@ -202,6 +407,8 @@ type statsRow struct {
 
 // GetTokenStats returns aggregated transfer statistics for a token
 func (r *TransferRepo) GetTokenStats(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error) {
+	ctx = withQueryName(ctx, "transfers.GetTokenStats")
+
 	query := `
 		WITH stats AS (
 			SELECT
@@ -271,6 +478,102 @@ func (r *TransferRepo) GetTokenStats(ctx context.Context, tokenAddress string) (
 	return result, nil
 }
 
+// windowStatsRow holds the result of the window stats query
+type windowStatsRow struct {
+	Transfers int64  `db:"transfers"`
+	Volume    string `db:"volume"`
+}
+
+// GetWindowStats returns transfer count and volume for a token in [from, to)
+func (r *TransferRepo) GetWindowStats(ctx context.Context, tokenAddress string, from, to time.Time) (*repositories.WindowStats, error) {
+	ctx = withQueryName(ctx, "transfers.GetWindowStats")
+
+	query := `
+		SELECT
+			COUNT(*) as transfers,
+			COALESCE(SUM(value), 0)::TEXT as volume
+		FROM transfers
+		WHERE token_address = $1
+		AND block_timestamp >= $2 AND block_timestamp < $3
+	`
+
+	var row windowStatsRow
+	if err := r.db.GetContext(ctx, &row, query, tokenAddress, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get window stats: %w", err)
+	}
+
+	return &repositories.WindowStats{
+		Transfers: row.Transfers,
+		Volume:    row.Volume,
+	}, nil
+}
+
+// heatmapRow holds one bucket of the activity heatmap query
+type heatmapRow struct {
+	DayOfWeek int   `db:"day_of_week"`
+	Hour      int   `db:"hour"`
+	Transfers int64 `db:"transfers"`
+}
+
+// GetActivityHeatmap returns a token's transfer counts in [from, to) grouped by UTC weekday and hour
+func (r *TransferRepo) GetActivityHeatmap(ctx context.Context, tokenAddress string, from, to time.Time) ([]repositories.HeatmapBucket, error) {
+	ctx = withQueryName(ctx, "transfers.GetActivityHeatmap")
+
+	query := `
+		SELECT
+			date_part('dow', block_timestamp AT TIME ZONE 'UTC')::INT as day_of_week,
+			date_part('hour', block_timestamp AT TIME ZONE 'UTC')::INT as hour,
+			COUNT(*) as transfers
+		FROM transfers
+		WHERE token_address = $1
+		AND block_timestamp >= $2 AND block_timestamp < $3
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+	`
+
+	var rows []heatmapRow
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get activity heatmap: %w", err)
+	}
+
+	buckets := make([]repositories.HeatmapBucket, len(rows))
+	for i, row := range rows {
+		buckets[i] = repositories.HeatmapBucket{
+			DayOfWeek: row.DayOfWeek,
+			Hour:      row.Hour,
+			Transfers: row.Transfers,
+		}
+	}
+	return buckets, nil
+}
+
+// GetActiveAddresses returns the distinct senders and receivers of a token in [from, to), excluding the zero address
+func (r *TransferRepo) GetActiveAddresses(ctx context.Context, tokenAddress string, from, to time.Time) ([]string, error) {
+	ctx = withQueryName(ctx, "transfers.GetActiveAddresses")
+
+	query := `
+		SELECT from_address AS address FROM transfers
+		WHERE token_address = $1 AND block_timestamp >= $2 AND block_timestamp < $3
+		UNION
+		SELECT to_address AS address FROM transfers
+		WHERE token_address = $1 AND block_timestamp >= $2 AND block_timestamp < $3
+	`
+
+	var addresses []string
+	if err := r.db.SelectContext(ctx, &addresses, query, tokenAddress, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get active addresses: %w", err)
+	}
+
+	result := addresses[:0]
+	for _, addr := range addresses {
+		if addr != entities.ZeroAddress {
+			result = append(result, addr)
+		}
+	}
+
+	return result, nil
+}
+
 // parseTimestamp parses a timestamp string from the database
 func parseTimestamp(s string) (time.Time, error) {
 	// Try parsing various formats
@@ -296,57 +599,87 @@ type holderBalanceRow struct {
 	Rank    int    `db:"rank"`
 }
 
+// balancesCTE is the per-address balance of token $1, the body of the balances CTE shared by the
+// holder queries. Callers filter out non-positive balances.
+const balancesCTE = `
+	SELECT address, SUM(amount) as balance
+	FROM (
+		-- Incoming transfers (positive)
+		SELECT to_address as address, value as amount
+		FROM transfers
+		WHERE token_address = $1
+
+		UNION ALL
+
+		-- Outgoing transfers (negative)
+		SELECT from_address as address, -value as amount
+		FROM transfers
+		WHERE token_address = $1
+	) t
+	GROUP BY address
+`
+
 // GetTopHolders returns top token holders sorted by balance
 func (r *TransferRepo) GetTopHolders(ctx context.Context, tokenAddress string, limit int) ([]repositories.HolderBalance, error) {
-	query := `
-		WITH balances AS (
-			SELECT
-				address,
-				SUM(amount) as balance
-			FROM (
-				-- Incoming transfers (positive)
-				SELECT to_address as address, value as amount
-				FROM transfers
-				WHERE token_address = $1
+	ctx = withQueryName(ctx, "transfers.GetTopHolders")
 
-				UNION ALL
+	holders, err := r.topHolders(ctx, tokenAddress, limit, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top holders: %w", err)
+	}
+	return holders, nil
+}
 
-				-- Outgoing transfers (negative)
-				SELECT from_address as address, -value as amount
-				FROM transfers
-				WHERE token_address = $1
-			) t
-			GROUP BY address
-			HAVING SUM(amount) > 0
-		)
+// GetTopHoldersWithOffset returns top token holders with pagination offset, leaving out exclude
+func (r *TransferRepo) GetTopHoldersWithOffset(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+	ctx = withQueryName(ctx, "transfers.GetTopHoldersWithOffset")
+
+	holders, err := r.topHolders(ctx, tokenAddress, limit, offset, exclude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top holders: %w", err)
+	}
+	return holders, nil
+}
+
+// excludeArray binds an address exclusion list for `NOT (address = ANY($n))`. pq encodes a nil
+// slice as NULL, which would make the condition NULL and leave out every row.
+func excludeArray(exclude []string) interface{} {
+	if exclude == nil {
+		exclude = []string{}
+	}
+	return pq.Array(exclude)
+}
+
+// topHolders ranks the holders with a positive balance, leaving out exclude, and returns one page of them
+func (r *TransferRepo) topHolders(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+	query := `
+		WITH balances AS (` + balancesCTE + `)
 		SELECT
 			address,
 			balance::TEXT as balance,
 			ROW_NUMBER() OVER (ORDER BY balance DESC)::INTEGER as rank
 		FROM balances
+		WHERE balance > 0 AND NOT (address = ANY($4))
 		ORDER BY balance DESC
-		LIMIT $2
+		LIMIT $2 OFFSET $3
 	`
 
 	var rows []holderBalanceRow
-	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, limit); err != nil {
-		return nil, fmt.Errorf("failed to get top holders: %w", err)
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, limit, offset, excludeArray(exclude)); err != nil {
+		return nil, err
 	}
 
 	result := make([]repositories.HolderBalance, len(rows))
 	for i, row := range rows {
-		result[i] = repositories.HolderBalance{
-			Address: row.Address,
-			Balance: row.Balance,
-			Rank:    row.Rank,
-		}
+		result[i] = repositories.HolderBalance(row)
 	}
-
 	return result, nil
 }
 
 // GetHolderBalance returns balance for a specific holder
 func (r *TransferRepo) GetHolderBalance(ctx context.Context, tokenAddress, holderAddress string) (*repositories.HolderBalance, error) {
+	ctx = withQueryName(ctx, "transfers.GetHolderBalance")
+
 	// First get the balance
 	balanceQuery := `
 		SELECT
@@ -369,42 +702,14 @@ func (r *TransferRepo) GetHolderBalance(ctx context.Context, tokenAddress, holde
 
 	// Get the rank by counting addresses with higher balance
 	rankQuery := `
-		WITH balances AS (
-			SELECT
-				address,
-				SUM(amount) as balance
-			FROM (
-				SELECT to_address as address, value as amount
-				FROM transfers
-				WHERE token_address = $1
-
-				UNION ALL
-
-				SELECT from_address as address, -value as amount
-				FROM transfers
-				WHERE token_address = $1
-			) t
-			GROUP BY address
-			HAVING SUM(amount) > 0
-		)
+		WITH balances AS (` + balancesCTE + `)
 		SELECT COUNT(*) + 1 as rank
 		FROM balances
-		WHERE balance > (
-			SELECT COALESCE(SUM(
-				CASE
-					WHEN to_address = $2 THEN value
-					WHEN from_address = $2 THEN -value
-					ELSE 0
-				END
-			), 0)
-			FROM transfers
-			WHERE token_address = $1
-			AND (to_address = $2 OR from_address = $2)
-		)
+		WHERE balance > 0 AND balance > $2::NUMERIC
 	`
 
 	var rank int
-	if err := r.db.GetContext(ctx, &rank, rankQuery, tokenAddress, holderAddress); err != nil {
+	if err := r.db.GetContext(ctx, &rank, rankQuery, tokenAddress, balance); err != nil {
 		return nil, fmt.Errorf("failed to get holder rank: %w", err)
 	}
 
@@ -415,77 +720,301 @@ func (r *TransferRepo) GetHolderBalance(ctx context.Context, tokenAddress, holde
 	}, nil
 }
 
-// GetHolderCount returns the count of unique holders with positive balance
-func (r *TransferRepo) GetHolderCount(ctx context.Context, tokenAddress string) (int64, error) {
+// GetHolderCount returns the count of unique holders with positive balance, leaving out exclude
+func (r *TransferRepo) GetHolderCount(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
+	ctx = withQueryName(ctx, "transfers.GetHolderCount")
+
 	query := `
-		WITH balances AS (
-			SELECT address, SUM(amount) as balance
-			FROM (
-				SELECT to_address as address, value as amount
-				FROM transfers WHERE token_address = $1
-				UNION ALL
-				SELECT from_address as address, -value as amount
-				FROM transfers WHERE token_address = $1
-			) t
-			GROUP BY address
-			HAVING SUM(amount) > 0
-		)
+		WITH balances AS (` + balancesCTE + `)
 		SELECT COUNT(*) FROM balances
+		WHERE balance > 0 AND NOT (address = ANY($2))
 	`
 
 	var count int64
-	if err := r.db.GetContext(ctx, &count, query, tokenAddress); err != nil {
+	if err := r.db.GetContext(ctx, &count, query, tokenAddress, excludeArray(exclude)); err != nil {
 		return 0, fmt.Errorf("failed to get holder count: %w", err)
 	}
 
 	return count, nil
 }
 
-// GetTopHoldersWithOffset returns top token holders with pagination offset
-func (r *TransferRepo) GetTopHoldersWithOffset(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+// GetCirculatingSupply returns a token's indexed mints minus burns
+func (r *TransferRepo) GetCirculatingSupply(ctx context.Context, tokenAddress string) (string, error) {
+	ctx = withQueryName(ctx, "transfers.GetCirculatingSupply")
+
 	query := `
-		WITH balances AS (
-			SELECT
-				address,
-				SUM(amount) as balance
+		SELECT
+			COALESCE(SUM(
+				CASE
+					WHEN from_address = $2 THEN value
+					WHEN to_address = $2 THEN -value
+					ELSE 0
+				END
+			), 0)::TEXT as supply
+		FROM transfers
+		WHERE token_address = $1
+		AND (from_address = $2 OR to_address = $2)
+	`
+
+	var supply string
+	if err := r.db.GetContext(ctx, &supply, query, tokenAddress, entities.ZeroAddress); err != nil {
+		return "", fmt.Errorf("failed to get circulating supply: %w", err)
+	}
+
+	return supply, nil
+}
+
+// GetCirculatingSupplyAt returns a token's indexed mints minus burns before the given time
+func (r *TransferRepo) GetCirculatingSupplyAt(ctx context.Context, tokenAddress string, at time.Time) (string, error) {
+	ctx = withQueryName(ctx, "transfers.GetCirculatingSupplyAt")
+
+	query := `
+		SELECT
+			COALESCE(SUM(
+				CASE
+					WHEN from_address = $2 THEN value
+					WHEN to_address = $2 THEN -value
+					ELSE 0
+				END
+			), 0)::TEXT as supply
+		FROM transfers
+		WHERE token_address = $1
+		AND (from_address = $2 OR to_address = $2)
+		AND block_timestamp < $3
+	`
+
+	var supply string
+	if err := r.db.GetContext(ctx, &supply, query, tokenAddress, entities.ZeroAddress, at); err != nil {
+		return "", fmt.Errorf("failed to get circulating supply: %w", err)
+	}
+
+	return supply, nil
+}
+
+// GetDormantSupply sums the positive balances of addresses inactive since the given time
+func (r *TransferRepo) GetDormantSupply(ctx context.Context, tokenAddress string, inactiveSince time.Time) (*repositories.DormantSupply, error) {
+	ctx = withQueryName(ctx, "transfers.GetDormantSupply")
+
+	query := `
+		SELECT COALESCE(SUM(balance), 0)::TEXT as balance, COUNT(*) as holders
+		FROM (
+			SELECT address, SUM(amount) as balance, MAX(block_timestamp) as last_active_at
 			FROM (
-				-- Incoming transfers (positive)
-				SELECT to_address as address, value as amount
+				SELECT to_address as address, value as amount, block_timestamp
 				FROM transfers
 				WHERE token_address = $1
 
 				UNION ALL
 
-				-- Outgoing transfers (negative)
-				SELECT from_address as address, -value as amount
+				SELECT from_address as address, -value as amount, block_timestamp
 				FROM transfers
 				WHERE token_address = $1
 			) t
 			GROUP BY address
-			HAVING SUM(amount) > 0
+		) balances
+		WHERE balance > 0 AND last_active_at < $2 AND address <> $3
+	`
+
+	var dormant repositories.DormantSupply
+	if err := r.db.GetContext(ctx, &dormant, query, tokenAddress, inactiveSince, entities.ZeroAddress); err != nil {
+		return nil, fmt.Errorf("failed to get dormant supply: %w", err)
+	}
+
+	return &dormant, nil
+}
+
+// GetTransferPatterns counts self-transfers and fan-out transactions within a time window
+func (r *TransferRepo) GetTransferPatterns(ctx context.Context, tokenAddress string, from, to time.Time, fanOut int) (*repositories.TransferPatterns, error) {
+	ctx = withQueryName(ctx, "transfers.GetTransferPatterns")
+
+	query := `
+		WITH window_transfers AS (
+			SELECT tx_hash, from_address, to_address, value
+			FROM transfers
+			WHERE token_address = $1 AND block_timestamp >= $2 AND block_timestamp < $3
+		),
+		fan_outs AS (
+			SELECT COUNT(*) as transfers, SUM(value) as volume
+			FROM window_transfers
+			GROUP BY tx_hash, from_address
+			HAVING COUNT(*) > $4
 		)
+		SELECT
+			(SELECT COUNT(*) FROM window_transfers WHERE from_address = to_address) as self_transfers,
+			(SELECT COALESCE(SUM(value), 0) FROM window_transfers WHERE from_address = to_address)::TEXT as self_transfer_volume,
+			COUNT(*) as fan_out_transactions,
+			COALESCE(SUM(transfers), 0) as fan_out_transfers,
+			COALESCE(SUM(volume), 0)::TEXT as fan_out_volume
+		FROM fan_outs
+	`
+
+	var patterns repositories.TransferPatterns
+	if err := r.db.GetContext(ctx, &patterns, query, tokenAddress, from, to, fanOut); err != nil {
+		return nil, fmt.Errorf("failed to get transfer patterns: %w", err)
+	}
+
+	return &patterns, nil
+}
+
+// GetNegativeBalances counts a token's negative balances and returns the limit most negative
+func (r *TransferRepo) GetNegativeBalances(ctx context.Context, tokenAddress string, limit int) (*repositories.NegativeBalances, error) {
+	ctx = withQueryName(ctx, "transfers.GetNegativeBalances")
+
+	query := `
+		WITH balances AS (` + balancesCTE + `)
 		SELECT
 			address,
 			balance::TEXT as balance,
-			ROW_NUMBER() OVER (ORDER BY balance DESC)::INTEGER as rank
+			ROW_NUMBER() OVER (ORDER BY balance ASC, address)::INTEGER as rank,
+			COUNT(*) OVER () as total
 		FROM balances
-		ORDER BY balance DESC
-		LIMIT $2 OFFSET $3
+		WHERE balance < 0 AND address <> $3
+		ORDER BY balance ASC, address
+		LIMIT $2
 	`
 
-	var rows []holderBalanceRow
-	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, limit, offset); err != nil {
-		return nil, fmt.Errorf("failed to get top holders: %w", err)
+	var rows []negativeBalanceRow
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, limit, entities.ZeroAddress); err != nil {
+		return nil, fmt.Errorf("failed to get negative balances: %w", err)
 	}
 
-	result := make([]repositories.HolderBalance, len(rows))
+	return toNegativeBalances(rows), nil
+}
+
+// negativeBalanceRow is a negative balance with the count of all of them
+type negativeBalanceRow struct {
+	holderBalanceRow
+	Total int64 `db:"total"`
+}
+
+func toNegativeBalances(rows []negativeBalanceRow) *repositories.NegativeBalances {
+	result := &repositories.NegativeBalances{Worst: make([]repositories.HolderBalance, len(rows))}
 	for i, row := range rows {
-		result[i] = repositories.HolderBalance{
+		result.Count = row.Total
+		result.Worst[i] = repositories.HolderBalance(row.holderBalanceRow)
+	}
+	return result
+}
+
+// GetBalanceChanges returns the addresses whose balance changed the most since the given time
+func (r *TransferRepo) GetBalanceChanges(ctx context.Context, tokenAddress string, since time.Time, limit int) ([]repositories.BalanceChange, error) {
+	ctx = withQueryName(ctx, "transfers.GetBalanceChanges")
+
+	query := `
+		WITH changes AS (
+			SELECT
+				address,
+				SUM(amount) as change
+			FROM (
+				SELECT to_address as address, value as amount
+				FROM transfers
+				WHERE token_address = $1 AND block_timestamp >= $2
+
+				UNION ALL
+
+				SELECT from_address as address, -value as amount
+				FROM transfers
+				WHERE token_address = $1 AND block_timestamp >= $2
+			) t
+			WHERE address <> $4
+			GROUP BY address
+			HAVING SUM(amount) <> 0
+		)
+		SELECT address, change::TEXT as change
+		FROM changes
+		ORDER BY ABS(change) DESC, address
+		LIMIT $3
+	`
+
+	var rows []struct {
+		Address string `db:"address"`
+		Change  string `db:"change"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, tokenAddress, since, limit, entities.ZeroAddress); err != nil {
+		return nil, fmt.Errorf("failed to get balance changes: %w", err)
+	}
+
+	result := make([]repositories.BalanceChange, len(rows))
+	for i, row := range rows {
+		result[i] = repositories.BalanceChange{
 			Address: row.Address,
-			Balance: row.Balance,
-			Rank:    row.Rank,
+			Change:  row.Change,
 		}
 	}
 
 	return result, nil
 }
+
+// StreamHolderHistory calls fn for each event of a holder's ledger, oldest first
+func (r *TransferRepo) StreamHolderHistory(ctx context.Context, tokenAddress, holderAddress string, filter repositories.HolderHistoryFilter, fn func(repositories.HolderBalanceEvent) error) error {
+	ctx = withQueryName(ctx, "transfers.StreamHolderHistory")
+
+	// The running balance is computed over the holder's full history before the time range is applied
+	query := `
+		WITH movements AS (
+			SELECT
+				block_number,
+				block_timestamp,
+				tx_hash,
+				log_index,
+				CASE WHEN to_address = $2 THEN from_address ELSE to_address END as counterparty,
+				CASE
+					WHEN from_address = $2 AND to_address = $2 THEN 0
+					WHEN to_address = $2 THEN value
+					ELSE -value
+				END as change
+			FROM transfers
+			WHERE token_address = $1 AND (from_address = $2 OR to_address = $2)
+		),
+		ledger AS (
+			SELECT
+				*,
+				SUM(change) OVER (ORDER BY block_number, log_index ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) as balance
+			FROM movements
+		)
+		SELECT
+			block_number,
+			block_timestamp,
+			tx_hash,
+			log_index,
+			counterparty,
+			change::TEXT as change,
+			balance::TEXT as balance
+		FROM ledger
+		WHERE ($3::TIMESTAMPTZ IS NULL OR block_timestamp >= $3)
+			AND ($4::TIMESTAMPTZ IS NULL OR block_timestamp < $4)
+		ORDER BY block_number, log_index
+		LIMIT $5 OFFSET $6
+	`
+
+	rows, err := r.db.QueryxContext(ctx, query, tokenAddress, holderAddress, filter.FromTime, filter.ToTime, filter.Limit, filter.Offset)
+	if err != nil {
+		return fmt.Errorf("failed to query holder history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row struct {
+			BlockNumber    int64     `db:"block_number"`
+			BlockTimestamp time.Time `db:"block_timestamp"`
+			TxHash         string    `db:"tx_hash"`
+			LogIndex       int       `db:"log_index"`
+			Counterparty   string    `db:"counterparty"`
+			Change         string    `db:"change"`
+			Balance        string    `db:"balance"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("failed to scan holder history: %w", err)
+		}
+
+		if err := fn(repositories.HolderBalanceEvent(row)); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read holder history: %w", err)
+	}
+
+	return nil
+}
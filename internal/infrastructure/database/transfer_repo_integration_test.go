@@ -0,0 +1,441 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+	"github.com/bimakw/chain-indexer/internal/testutil/integration"
+	"github.com/bimakw/chain-indexer/internal/testutil/seed"
+)
+
+// tokens converts a whole token amount to 18-decimal raw units, past the int64 range
+func tokens(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+}
+
+// seedHolders stores USDT and four transfers leaving Bob with 600, Alice with 400,
+// Charlie with 0 and the zero address (the mint source) with -1000
+func seedHolders(t *testing.T, db *sqlx.DB) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := NewTokenRepo(db).Upsert(ctx, testutil.CreateTestToken()); err != nil {
+		t.Fatal(err)
+	}
+
+	transfers := []entities.Transfer{
+		testutil.CreateTestTransfer(testutil.WithLogIndex(0), testutil.WithBlockNumber(100),
+			testutil.WithFromAddress(entities.ZeroAddress), testutil.WithToAddress(testutil.AliceAddress), testutil.WithValue(tokens(1000))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(1), testutil.WithBlockNumber(101),
+			testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.BobAddress), testutil.WithValue(tokens(400))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(2), testutil.WithBlockNumber(102),
+			testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.CharlieAddr), testutil.WithValue(tokens(200))),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(3), testutil.WithBlockNumber(103),
+			testutil.WithFromAddress(testutil.CharlieAddr), testutil.WithToAddress(testutil.BobAddress), testutil.WithValue(tokens(200))),
+	}
+	if _, err := NewTransferRepo(db).BatchInsert(ctx, transfers); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTransferRepo_Integration_Filter(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewTransferRepo(db)
+	ctx := context.Background()
+
+	// Duplicates are skipped by the unique key
+	if _, err := repo.BatchInsert(ctx, []entities.Transfer{testutil.CreateTestTransfer(testutil.WithLogIndex(1))}); err != nil {
+		t.Fatal(err)
+	}
+
+	token := testutil.USDTAddress
+	count, err := repo.GetCount(ctx, entities.TransferFilter{TokenAddress: &token})
+	if err != nil || count != 4 {
+		t.Errorf("expected 4 transfers, got %d (%v)", count, err)
+	}
+
+	// Token lists narrow feeds to, or leave out, the listed tokens
+	for filter, want := range map[*entities.TransferFilter]int64{
+		{Tokens: []string{token, testutil.USDCAddress}}: 4,
+		{Tokens: []string{testutil.USDCAddress}}:        0,
+		{ExcludeTokens: []string{token}}:                0,
+		{ExcludeTokens: []string{testutil.USDCAddress}}: 4,
+	} {
+		if count, err := repo.GetCount(ctx, *filter); err != nil || count != want {
+			t.Errorf("%+v: expected %d transfers, got %d (%v)", *filter, want, count, err)
+		}
+	}
+
+	from := testutil.AliceAddress
+	transfers, err := repo.GetByFilter(ctx, entities.TransferFilter{FromAddress: &from, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 2 || transfers[0].LogIndex != 2 || transfers[1].LogIndex != 1 {
+		t.Errorf("expected Alice's transfers newest first, got %+v", transfers)
+	}
+	if transfers[1].ValueString != tokens(400).String() {
+		t.Errorf("expected value %s, got %s", tokens(400), transfers[1].ValueString)
+	}
+
+	latest, err := repo.GetLatestBlock(ctx, token)
+	if err != nil || latest != 103 {
+		t.Errorf("expected latest block 103, got %d (%v)", latest, err)
+	}
+}
+
+func TestTransferRepo_Integration_Cursor(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewTransferRepo(db)
+	ctx := context.Background()
+
+	// All seeded transfers share a timestamp, so the block number breaks the tie
+	first, err := repo.GetByFilter(ctx, entities.TransferFilter{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 2 || first[0].BlockNumber != 103 || first[1].BlockNumber != 102 {
+		t.Fatalf("expected blocks 103 and 102 first, got %+v", first)
+	}
+
+	cursor := entities.CursorAt(first[1])
+	rest, err := repo.GetByFilter(ctx, entities.TransferFilter{After: &cursor, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 2 || rest[0].BlockNumber != 101 || rest[1].BlockNumber != 100 {
+		t.Errorf("expected blocks 101 and 100 after the cursor, got %+v", rest)
+	}
+}
+
+func TestTransferRepo_Integration_AfterID(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewTransferRepo(db)
+	ctx := context.Background()
+
+	latest, err := repo.GetLatestID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := repo.GetAfterID(ctx, entities.TransferFilter{Limit: 10}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[0].BlockNumber != 100 || all[3].ID != latest {
+		t.Fatalf("expected all transfers in insertion order, got %+v", all)
+	}
+
+	// Bob received the second and fourth transfers
+	bob := testutil.BobAddress
+	rest, err := repo.GetAfterID(ctx, entities.TransferFilter{Address: &bob, Limit: 10}, all[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 1 || rest[0].ID != latest {
+		t.Errorf("expected only Bob's latest transfer, got %+v", rest)
+	}
+
+	none, err := repo.GetAfterID(ctx, entities.TransferFilter{Limit: 10}, latest)
+	if err != nil || len(none) != 0 {
+		t.Errorf("expected nothing after the latest ID, got %+v (%v)", none, err)
+	}
+}
+
+func TestTransferRepo_Integration_ReplaceRange(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewTransferRepo(db)
+	ctx := context.Background()
+
+	replacement := testutil.CreateTestTransfer(testutil.WithLogIndex(7), testutil.WithBlockNumber(102))
+	// The duplicate is skipped and not counted as inserted
+	deleted, inserted, err := repo.ReplaceRange(ctx, testutil.USDTAddress, 102, 103, []entities.Transfer{replacement, replacement})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 || inserted != 1 {
+		t.Errorf("expected 2 deleted and 1 inserted, got %d and %d", deleted, inserted)
+	}
+
+	token := testutil.USDTAddress
+	count, err := repo.GetCount(ctx, entities.TransferFilter{TokenAddress: &token})
+	if err != nil || count != 3 {
+		t.Errorf("expected 3 transfers after replace, got %d (%v)", count, err)
+	}
+}
+
+func TestTransferRepo_Integration_Holders(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewTransferRepo(db)
+	ctx := context.Background()
+	token := testutil.USDTAddress
+
+	// Charlie (zero balance) and the zero address (negative) are not holders
+	count, err := repo.GetHolderCount(ctx, token, nil)
+	if err != nil || count != 2 {
+		t.Errorf("expected 2 holders, got %d (%v)", count, err)
+	}
+
+	// Excluded addresses are not counted or ranked
+	count, err = repo.GetHolderCount(ctx, token, []string{testutil.BobAddress})
+	if err != nil || count != 1 {
+		t.Errorf("expected 1 holder excluding bob, got %d (%v)", count, err)
+	}
+	excluded, err := repo.GetTopHoldersWithOffset(ctx, token, 10, 0, []string{testutil.BobAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(excluded) != 1 || excluded[0].Address != testutil.AliceAddress || excluded[0].Rank != 1 {
+		t.Errorf("unexpected holders excluding bob: %+v", excluded)
+	}
+
+	top, err := repo.GetTopHolders(ctx, token, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 ||
+		top[0].Address != testutil.BobAddress || top[0].Balance != tokens(600).String() || top[0].Rank != 1 ||
+		top[1].Address != testutil.AliceAddress || top[1].Balance != tokens(400).String() || top[1].Rank != 2 {
+		t.Errorf("unexpected top holders: %+v", top)
+	}
+
+	// Ranks are computed before the page is cut
+	page, err := repo.GetTopHoldersWithOffset(ctx, token, 1, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].Address != testutil.AliceAddress || page[0].Rank != 2 {
+		t.Errorf("unexpected second page: %+v", page)
+	}
+
+	for _, tc := range []struct {
+		holder  string
+		balance string
+		rank    int
+	}{
+		{testutil.BobAddress, tokens(600).String(), 1},
+		{testutil.AliceAddress, tokens(400).String(), 2},
+		{testutil.CharlieAddr, "0", 3},
+	} {
+		holder, err := repo.GetHolderBalance(ctx, token, tc.holder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if holder.Balance != tc.balance || holder.Rank != tc.rank {
+			t.Errorf("%s: expected balance %s rank %d, got %+v", tc.holder, tc.balance, tc.rank, holder)
+		}
+	}
+}
+
+func TestTransferRepo_Integration_BalanceChanges(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewTransferRepo(db)
+	ctx := context.Background()
+
+	// All seeded transfers share one timestamp, so everything counts; the zero address is excluded
+	since := testutil.CreateTestTransfer().BlockTimestamp
+	changes, err := repo.GetBalanceChanges(ctx, testutil.USDTAddress, since, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 ||
+		changes[0].Address != testutil.BobAddress || changes[0].Change != tokens(600).String() ||
+		changes[1].Address != testutil.AliceAddress || changes[1].Change != tokens(400).String() {
+		t.Errorf("unexpected balance changes: %+v", changes)
+	}
+
+	changes, err = repo.GetBalanceChanges(ctx, testutil.USDTAddress, since.Add(time.Second), 10)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("expected no changes after the last transfer, got %+v (%v)", changes, err)
+	}
+}
+
+func TestTransferRepo_Integration_NegativeBalances(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewTransferRepo(db)
+	ctx := context.Background()
+
+	// The zero address is minus the supply and is not reported
+	negative, err := repo.GetNegativeBalances(ctx, testutil.USDTAddress, 10)
+	if err != nil || negative.Count != 0 || len(negative.Worst) != 0 {
+		t.Fatalf("expected no negative balances, got %+v (%v)", negative, err)
+	}
+
+	// A spend from an address whose incoming transfer is missing
+	dave := "0x4444444444444444444444444444444444444444"
+	missing := testutil.CreateTestTransfer(testutil.WithLogIndex(4), testutil.WithBlockNumber(104),
+		testutil.WithFromAddress(dave), testutil.WithToAddress(testutil.AliceAddress), testutil.WithValue(tokens(5)))
+	if _, err := repo.BatchInsert(ctx, []entities.Transfer{missing}); err != nil {
+		t.Fatal(err)
+	}
+
+	negative, err = repo.GetNegativeBalances(ctx, testutil.USDTAddress, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if negative.Count != 1 || len(negative.Worst) != 1 ||
+		negative.Worst[0].Address != dave || negative.Worst[0].Balance != "-"+tokens(5).String() || negative.Worst[0].Rank != 1 {
+		t.Errorf("expected dave at -5, got %+v", negative)
+	}
+}
+
+func TestTransferRepo_Integration_HolderHistory(t *testing.T) {
+	db := integration.Postgres(t)
+	seedHolders(t, db)
+	repo := NewTransferRepo(db)
+	ctx := context.Background()
+
+	var events []repositories.HolderBalanceEvent
+	collect := func(e repositories.HolderBalanceEvent) error {
+		events = append(events, e)
+		return nil
+	}
+
+	if err := repo.StreamHolderHistory(ctx, testutil.USDTAddress, testutil.AliceAddress,
+		repositories.HolderHistoryFilter{Limit: 10}, collect); err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		counterparty, change, balance string
+	}{
+		{entities.ZeroAddress, tokens(1000).String(), tokens(1000).String()},
+		{testutil.BobAddress, tokens(-400).String(), tokens(600).String()},
+		{testutil.CharlieAddr, tokens(-200).String(), tokens(400).String()},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Counterparty != w.counterparty || events[i].Change != w.change || events[i].Balance != w.balance {
+			t.Errorf("event %d: expected %+v, got %+v", i, w, events[i])
+		}
+	}
+
+	// Pages keep the balance accumulated over earlier history
+	events = nil
+	if err := repo.StreamHolderHistory(ctx, testutil.USDTAddress, testutil.AliceAddress,
+		repositories.HolderHistoryFilter{Limit: 1, Offset: 2}, collect); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Balance != tokens(400).String() || events[0].BlockNumber != 102 {
+		t.Errorf("unexpected page: %+v", events)
+	}
+
+	// The time range filters events, not the balance
+	after := testutil.CreateTestTransfer().BlockTimestamp.Add(time.Second)
+	events = nil
+	if err := repo.StreamHolderHistory(ctx, testutil.USDTAddress, testutil.AliceAddress,
+		repositories.HolderHistoryFilter{FromTime: &after, Limit: 10}, collect); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected no events after the last transfer, got %+v", events)
+	}
+}
+
+// seedGenerated stores a generated dataset and returns its first token
+func seedGenerated(b *testing.B, db *sqlx.DB, cfg seed.Config) string {
+	b.Helper()
+	ctx := context.Background()
+
+	generator, err := seed.NewGenerator(cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, token := range generator.Tokens() {
+		if err := NewTokenRepo(db).Upsert(ctx, token); err != nil {
+			b.Fatal(err)
+		}
+	}
+	repo := NewTransferRepo(db)
+	for batch := generator.Next(5000); batch != nil; batch = generator.Next(5000) {
+		if _, err := repo.BatchInsert(ctx, batch); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return generator.Tokens()[0].Address
+}
+
+func BenchmarkTransferRepo_BatchInsert(b *testing.B) {
+	db := integration.Postgres(b)
+	repo := NewTransferRepo(db)
+	ctx := context.Background()
+
+	const batchSize = 1000
+	cfg := seed.DefaultConfig()
+	cfg.Tokens = 1
+	cfg.Transfers = int64(b.N) * batchSize
+	generator, err := seed.NewGenerator(cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := NewTokenRepo(db).Upsert(ctx, generator.Tokens()[0]); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		batch := generator.Next(batchSize)
+		b.StartTimer()
+
+		if _, err := repo.BatchInsert(ctx, batch); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(b.N*batchSize)/b.Elapsed().Seconds(), "rows/s")
+}
+
+func BenchmarkTransferRepo_Holders(b *testing.B) {
+	db := integration.Postgres(b)
+	cfg := seed.DefaultConfig()
+	cfg.Tokens = 1
+	cfg.Holders = 10_000
+	cfg.Transfers = 100_000
+	token := seedGenerated(b, db, cfg)
+
+	repo := NewTransferRepo(db)
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "ANALYZE transfers"); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("GetTopHoldersWithOffset", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetTopHoldersWithOffset(ctx, token, 100, 1000, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("GetHolderCount", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetHolderCount(ctx, token, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("GetHolderBalance", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetHolderBalance(ctx, token, entities.ZeroAddress); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+func BenchmarkTransferRepo_buildFilterQuery(b *testing.B) {
+	repo := &TransferRepo{}
+
+	token := "0xdac17f958d2ee523a2206206994597c13d831ec7"
+	fromBlock, toBlock := int64(19000000), int64(19100000)
+	filter := entities.TransferFilter{
+		TokenAddress: &token,
+		Addresses:    []string{"0x1111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222"},
+		FromBlock:    &fromBlock,
+		ToBlock:      &toBlock,
+		MinValue:     big.NewInt(1000000),
+		ExcludeZero:  true,
+		ExcludeSelf:  true,
+		Limit:        100,
+		Offset:       200,
+	}
+
+	query, args := repo.buildFilterQuery(filter, false)
+	if !strings.Contains(query, "LIMIT") || len(args) == 0 {
+		b.Fatalf("unexpected query %q with %d args", query, len(args))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = repo.buildFilterQuery(filter, false)
+	}
+}
+
+func TestTransferValue(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	tests := []struct {
+		name     string
+		transfer entities.Transfer
+		want     string
+		wantErr  bool
+	}{
+		{"value wins over string", entities.Transfer{Value: big.NewInt(42), ValueString: "41"}, "42", false},
+		{"string only", entities.Transfer{ValueString: "0001000"}, "1000", false},
+		{"max uint256", entities.Transfer{Value: maxUint256}, maxUint256.String(), false},
+		{"overflow", entities.Transfer{Value: new(big.Int).Add(maxUint256, big.NewInt(1))}, "", true},
+		{"negative", entities.Transfer{Value: big.NewInt(-1)}, "", true},
+		{"empty", entities.Transfer{}, "", true},
+		{"decimal", entities.Transfer{ValueString: "1.5"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := transferValue(tt.transfer)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("transferValue() = %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+)
+
+// Ensure WalletStatsRepo implements WalletStatsRepository
+var _ repositories.WalletStatsRepository = (*WalletStatsRepo)(nil)
+
+// WalletStatsRepo implements WalletStatsRepository using PostgreSQL
+type WalletStatsRepo struct {
+	db *sqlx.DB
+}
+
+// NewWalletStatsRepo creates a new wallet stats repository
+func NewWalletStatsRepo(db *sqlx.DB) *WalletStatsRepo {
+	return &WalletStatsRepo{db: db}
+}
+
+// walletStatDelta is what a batch of transfers adds to one wallet's totals for one token
+type walletStatDelta struct {
+	wallet, token             string
+	transfersIn, transfersOut int64
+	volumeIn, volumeOut       *big.Int
+	first, last               time.Time
+}
+
+// walletStatDeltas sums transfers into one delta per wallet and token, sorted by wallet and token
+// so that concurrent batches lock the rows they share in the same order
+func walletStatDeltas(transfers []entities.Transfer) ([]*walletStatDelta, error) {
+	type key struct{ wallet, token string }
+	byKey := make(map[key]*walletStatDelta)
+	deltaFor := func(wallet, token string, at time.Time) *walletStatDelta {
+		d, ok := byKey[key{wallet, token}]
+		if !ok {
+			d = &walletStatDelta{
+				wallet:    wallet,
+				token:     token,
+				volumeIn:  new(big.Int),
+				volumeOut: new(big.Int),
+				first:     at,
+				last:      at,
+			}
+			byKey[key{wallet, token}] = d
+		}
+		if at.Before(d.first) {
+			d.first = at
+		}
+		if at.After(d.last) {
+			d.last = at
+		}
+		return d
+	}
+
+	for _, t := range transfers {
+		value, err := transferValue(t)
+		if err != nil {
+			return nil, err
+		}
+		amount, _ := new(big.Int).SetString(value, 10)
+
+		in := deltaFor(t.ToAddress, t.TokenAddress, t.BlockTimestamp)
+		in.transfersIn++
+		in.volumeIn.Add(in.volumeIn, amount)
+
+		out := deltaFor(t.FromAddress, t.TokenAddress, t.BlockTimestamp)
+		out.transfersOut++
+		out.volumeOut.Add(out.volumeOut, amount)
+	}
+
+	deltas := make([]*walletStatDelta, 0, len(byKey))
+	for _, d := range byKey {
+		deltas = append(deltas, d)
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].wallet != deltas[j].wallet {
+			return deltas[i].wallet < deltas[j].wallet
+		}
+		return deltas[i].token < deltas[j].token
+	})
+	return deltas, nil
+}
+
+// Apply adds newly stored transfers to their senders' and receivers' totals in one transaction
+func (r *WalletStatsRepo) Apply(ctx context.Context, transfers []entities.Transfer) error {
+	ctx = withQueryName(ctx, "wallet_stats.Apply")
+
+	deltas, err := walletStatDeltas(transfers)
+	if err != nil || len(deltas) == 0 {
+		return err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO wallet_stats (wallet_address, token_address, transfers_in, transfers_out,
+								  volume_in, volume_out, first_transfer_at, last_transfer_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (wallet_address, token_address) DO UPDATE SET
+			transfers_in = wallet_stats.transfers_in + EXCLUDED.transfers_in,
+			transfers_out = wallet_stats.transfers_out + EXCLUDED.transfers_out,
+			volume_in = wallet_stats.volume_in + EXCLUDED.volume_in,
+			volume_out = wallet_stats.volume_out + EXCLUDED.volume_out,
+			first_transfer_at = LEAST(wallet_stats.first_transfer_at, EXCLUDED.first_transfer_at),
+			last_transfer_at = GREATEST(wallet_stats.last_transfer_at, EXCLUDED.last_transfer_at),
+			updated_at = NOW()
+	`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, d := range deltas {
+		if _, err := stmt.ExecContext(ctx,
+			d.wallet, d.token, d.transfersIn, d.transfersOut,
+			d.volumeIn.String(), d.volumeOut.String(), d.first, d.last,
+		); err != nil {
+			return fmt.Errorf("failed to update wallet stats: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// walletStatsTotalsQuery recomputes the totals of token $1 from stored transfers. The %[1]s and
+// %[2]s conditions restrict the receivers and senders counted, for a refresh of some wallets.
+const walletStatsTotalsQuery = `
+	INSERT INTO wallet_stats (wallet_address, token_address, transfers_in, transfers_out,
+							  volume_in, volume_out, first_transfer_at, last_transfer_at)
+	SELECT wallet, $1, SUM(transfers_in), SUM(transfers_out),
+		SUM(volume_in), SUM(volume_out), MIN(first_transfer_at), MAX(last_transfer_at)
+	FROM (
+		SELECT to_address AS wallet, COUNT(*) AS transfers_in, 0 AS transfers_out,
+			SUM(value) AS volume_in, 0 AS volume_out,
+			MIN(block_timestamp) AS first_transfer_at, MAX(block_timestamp) AS last_transfer_at
+		FROM transfers
+		WHERE token_address = $1 %[1]s
+		GROUP BY to_address
+		UNION ALL
+		SELECT from_address, 0, COUNT(*), 0, SUM(value), MIN(block_timestamp), MAX(block_timestamp)
+		FROM transfers
+		WHERE token_address = $1 %[2]s
+		GROUP BY from_address
+	) totals
+	GROUP BY wallet
+`
+
+// Refresh recomputes a token's totals for the given wallets in one transaction
+func (r *WalletStatsRepo) Refresh(ctx context.Context, tokenAddress string, wallets []string) error {
+	ctx = withQueryName(ctx, "wallet_stats.Refresh")
+
+	if len(wallets) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	deleteQuery := `DELETE FROM wallet_stats WHERE token_address = $1 AND wallet_address = ANY($2)`
+	if _, err := tx.ExecContext(ctx, deleteQuery, tokenAddress, pq.Array(wallets)); err != nil {
+		return fmt.Errorf("failed to delete wallet stats: %w", err)
+	}
+
+	insertQuery := fmt.Sprintf(walletStatsTotalsQuery, "AND to_address = ANY($2)", "AND from_address = ANY($2)")
+	if _, err := tx.ExecContext(ctx, insertQuery, tokenAddress, pq.Array(wallets)); err != nil {
+		return fmt.Errorf("failed to insert wallet stats: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Rebuild recomputes all of a token's totals in one transaction
+func (r *WalletStatsRepo) Rebuild(ctx context.Context, tokenAddress string) error {
+	ctx = withQueryName(ctx, "wallet_stats.Rebuild")
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM wallet_stats WHERE token_address = $1`, tokenAddress); err != nil {
+		return fmt.Errorf("failed to delete wallet stats: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(walletStatsTotalsQuery, "", ""), tokenAddress); err != nil {
+		return fmt.Errorf("failed to insert wallet stats: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetRangeWallets returns the senders and receivers of a token's transfers in [fromBlock, toBlock]
+func (r *WalletStatsRepo) GetRangeWallets(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) ([]string, error) {
+	ctx = withQueryName(ctx, "wallet_stats.GetRangeWallets")
+
+	query := `
+		SELECT from_address FROM transfers
+		WHERE token_address = $1 AND block_number >= $2 AND block_number <= $3
+		UNION
+		SELECT to_address FROM transfers
+		WHERE token_address = $1 AND block_number >= $2 AND block_number <= $3
+	`
+
+	var wallets []string
+	if err := r.db.SelectContext(ctx, &wallets, query, tokenAddress, fromBlock, toBlock); err != nil {
+		return nil, fmt.Errorf("failed to get range wallets: %w", err)
+	}
+	return wallets, nil
+}
+
+// GetSummary sums a wallet's totals across tokens
+func (r *WalletStatsRepo) GetSummary(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, bool, error) {
+	ctx = withQueryName(ctx, "wallet_stats.GetSummary")
+
+	query := `
+		SELECT
+			COALESCE(SUM(transfers_in), 0) as total_in,
+			COALESCE(SUM(transfers_out), 0) as total_out,
+			COALESCE(SUM(volume_in), 0)::text as volume_in,
+			COALESCE(SUM(volume_out), 0)::text as volume_out,
+			COUNT(*) as unique_tokens,
+			MIN(first_transfer_at)::text as first_transfer,
+			MAX(last_transfer_at)::text as last_transfer
+		FROM wallet_stats
+		WHERE wallet_address = $1
+	`
+
+	var row summaryRow
+	if err := r.db.GetContext(ctx, &row, query, walletAddress); err != nil {
+		return nil, false, fmt.Errorf("failed to get wallet stats: %w", err)
+	}
+	if row.UniqueTokens == 0 {
+		return nil, false, nil
+	}
+
+	result := &repositories.WalletTransferSummary{
+		TotalTransfersIn:  row.TotalIn,
+		TotalTransfersOut: row.TotalOut,
+		TotalVolumeIn:     row.VolumeIn,
+		TotalVolumeOut:    row.VolumeOut,
+		UniqueTokens:      row.UniqueTokens,
+	}
+	if row.FirstTransfer != nil {
+		if t, err := parseTimestamp(*row.FirstTransfer); err == nil {
+			result.FirstTransferAt = &t
+		}
+	}
+	if row.LastTransfer != nil {
+		if t, err := parseTimestamp(*row.LastTransfer); err == nil {
+			result.LastTransferAt = &t
+		}
+	}
+	return result, true, nil
+}
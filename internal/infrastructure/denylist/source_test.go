@@ -0,0 +1,86 @@
+package denylist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleList = `# OFAC SDN Ethereum addresses
+0x8589427373D6D84E98730D7795D8f6f8731FDA16,Tornado Cash
+
+0x722122dF12D4e14e13Ac3b6895a86e84145b6967
+0x722122df12d4e14e13ac3b6895a86e84145b6967,duplicate
+`
+
+func TestParse(t *testing.T) {
+	entries, err := Parse(strings.NewReader(sampleList), "ofac")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Address != "0x8589427373d6d84e98730d7795d8f6f8731fda16" || entries[0].Label != "Tornado Cash" || entries[0].ListName != "ofac" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Label != "" {
+		t.Errorf("expected duplicate to keep the first label, got %q", entries[1].Label)
+	}
+}
+
+func TestParse_RejectsMalformedLines(t *testing.T) {
+	for _, input := range []string{
+		"<html>Not Found</html>",
+		"8589427373D6D84E98730D7795D8f6f8731FDA16",
+		"0x1234",
+	} {
+		if _, err := Parse(strings.NewReader(input), "ofac"); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("from URL", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(sampleList))
+		}))
+		defer server.Close()
+
+		entries, err := Load(ctx, "ofac", server.URL)
+		if err != nil || len(entries) != 2 {
+			t.Errorf("expected 2 entries, got %d (%v)", len(entries), err)
+		}
+	})
+
+	t.Run("URL error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		if _, err := Load(ctx, "ofac", server.URL); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ofac.txt")
+		if err := os.WriteFile(path, []byte(sampleList), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		entries, err := Load(ctx, "ofac", path)
+		if err != nil || len(entries) != 2 {
+			t.Errorf("expected 2 entries, got %d (%v)", len(entries), err)
+		}
+	})
+}
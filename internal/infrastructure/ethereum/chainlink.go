@@ -0,0 +1,76 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/pricing"
+)
+
+// latestRoundData() -> 0xfeaf968c
+var latestRoundDataSig = common.FromHex("0xfeaf968c")
+
+// ChainlinkPriceProvider reads USD prices from Chainlink aggregator feeds via eth_call
+type ChainlinkPriceProvider struct {
+	client *Client
+	feeds  map[string]common.Address // token address -> TOKEN/USD feed
+}
+
+// Ensure ChainlinkPriceProvider implements pricing.Provider
+var _ pricing.Provider = (*ChainlinkPriceProvider)(nil)
+
+// NewChainlinkPriceProvider creates a provider from a token address -> feed address map
+func NewChainlinkPriceProvider(client *Client, feeds map[string]string) *ChainlinkPriceProvider {
+	normalized := make(map[string]common.Address, len(feeds))
+	for token, feed := range feeds {
+		normalized[strings.ToLower(token)] = common.HexToAddress(feed)
+	}
+	return &ChainlinkPriceProvider{
+		client: client,
+		feeds:  normalized,
+	}
+}
+
+// CurrentPrice returns the latest answer of the token's USD feed
+func (p *ChainlinkPriceProvider) CurrentPrice(ctx context.Context, tokenAddress string) (float64, error) {
+	feed, ok := p.feeds[strings.ToLower(tokenAddress)]
+	if !ok {
+		return 0, pricing.ErrPriceUnavailable
+	}
+
+	decimalsResult, err := p.client.CallContract(ctx, feed, decimalsSig)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch feed decimals: %w", err)
+	}
+	if len(decimalsResult) < 32 {
+		return 0, fmt.Errorf("invalid decimals response length: %d", len(decimalsResult))
+	}
+
+	roundResult, err := p.client.CallContract(ctx, feed, latestRoundDataSig)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch latest round: %w", err)
+	}
+	// roundId, answer, startedAt, updatedAt, answeredInRound
+	if len(roundResult) < 160 {
+		return 0, fmt.Errorf("invalid latestRoundData response length: %d", len(roundResult))
+	}
+
+	answer := decodeInt256(roundResult[32:64])
+	if answer.Sign() <= 0 {
+		return 0, pricing.ErrPriceUnavailable
+	}
+
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimalsResult[31])), nil))
+	price, _ := new(big.Float).Quo(new(big.Float).SetInt(answer), scale).Float64()
+	return price, nil
+}
+
+// HistoricalPrice is not supported; Chainlink feeds only expose rounds, not timestamps
+func (p *ChainlinkPriceProvider) HistoricalPrice(ctx context.Context, tokenAddress string, at time.Time) (float64, error) {
+	return 0, pricing.ErrPriceUnavailable
+}
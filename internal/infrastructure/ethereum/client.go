@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"go.uber.org/zap"
 
 	"github.com/bimakw/chain-indexer/internal/config"
@@ -19,12 +21,18 @@ import (
 type Client struct {
 	client  *ethclient.Client
 	config  config.EthereumConfig
+	limiter *RateLimiter
 	logger  *zap.Logger
 	chainID *big.Int
 }
 
 // NewClient creates a new Ethereum client
 func NewClient(cfg config.EthereumConfig, logger *zap.Logger) (*Client, error) {
+	rps, maxConcurrent, err := cfg.RateLimitsFor(cfg.RPCURL)
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := ethclient.Dial(cfg.RPCURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Ethereum node: %w", err)
@@ -33,7 +41,18 @@ func NewClient(cfg config.EthereumConfig, logger *zap.Logger) (*Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.RequestTimeout)
 	defer cancel()
 
-	chainID, err := client.ChainID(ctx)
+	c := &Client{
+		client:  client,
+		config:  cfg,
+		limiter: NewRateLimiter(rps, maxConcurrent),
+		logger:  logger,
+	}
+
+	var chainID *big.Int
+	err = c.call(ctx, func() (err error) {
+		chainID, err = client.ChainID(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chain ID: %w", err)
 	}
@@ -45,14 +64,29 @@ func NewClient(cfg config.EthereumConfig, logger *zap.Logger) (*Client, error) {
 	logger.Info("Connected to Ethereum node",
 		zap.String("rpc_url", cfg.RPCURL),
 		zap.Int64("chain_id", chainID.Int64()),
+		zap.Float64("rate_limit_rps", rps),
+		zap.Int("max_concurrent_requests", maxConcurrent),
 	)
 
-	return &Client{
-		client:  client,
-		config:  cfg,
-		logger:  logger,
-		chainID: chainID,
-	}, nil
+	c.chainID = chainID
+	return c, nil
+}
+
+// WithLimits replaces the client's rate and concurrency limits, e.g. with tighter ones for a
+// backfill sharing the provider with live indexing. Zero disables either limit.
+func (c *Client) WithLimits(requestsPerSecond float64, maxConcurrent int) *Client {
+	c.limiter = NewRateLimiter(requestsPerSecond, maxConcurrent)
+	return c
+}
+
+// call sends one RPC request within the provider's rate and concurrency limits
+func (c *Client) call(ctx context.Context, fn func() error) error {
+	release, err := c.limiter.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
 }
 
 // Close closes the Ethereum client connection
@@ -66,7 +100,10 @@ func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
 	var err error
 
 	for i := 0; i <= c.config.MaxRetries; i++ {
-		blockNumber, err = c.client.BlockNumber(ctx)
+		err = c.call(ctx, func() (err error) {
+			blockNumber, err = c.client.BlockNumber(ctx)
+			return err
+		})
 		if err == nil {
 			return blockNumber, nil
 		}
@@ -90,7 +127,10 @@ func (c *Client) GetBlockByNumber(ctx context.Context, blockNumber *big.Int) (*t
 	var err error
 
 	for i := 0; i <= c.config.MaxRetries; i++ {
-		block, err = c.client.BlockByNumber(ctx, blockNumber)
+		err = c.call(ctx, func() (err error) {
+			block, err = c.client.BlockByNumber(ctx, blockNumber)
+			return err
+		})
 		if err == nil {
 			return block, nil
 		}
@@ -115,7 +155,10 @@ func (c *Client) GetLogs(ctx context.Context, query ethereum.FilterQuery) ([]typ
 	var err error
 
 	for i := 0; i <= c.config.MaxRetries; i++ {
-		logs, err = c.client.FilterLogs(ctx, query)
+		err = c.call(ctx, func() (err error) {
+			logs, err = c.client.FilterLogs(ctx, query)
+			return err
+		})
 		if err == nil {
 			return logs, nil
 		}
@@ -144,17 +187,16 @@ func (c *Client) GetBlockTimestamp(ctx context.Context, blockNumber uint64) (tim
 
 // BuildFilterQuery builds a filter query for ERC-20 Transfer events
 func (c *Client) BuildFilterQuery(fromBlock, toBlock *big.Int, addresses []common.Address) ethereum.FilterQuery {
-	// ERC-20 Transfer event signature: Transfer(address,address,uint256)
-	// keccak256("Transfer(address,address,uint256)") = 0xddf252ad...
-	transferEventSig := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	return c.BuildEventFilterQuery(fromBlock, toBlock, addresses, []common.Hash{TransferEventSignature})
+}
 
+// BuildEventFilterQuery builds a filter query matching any of the given event signatures
+func (c *Client) BuildEventFilterQuery(fromBlock, toBlock *big.Int, addresses []common.Address, topics []common.Hash) ethereum.FilterQuery {
 	return ethereum.FilterQuery{
 		FromBlock: fromBlock,
 		ToBlock:   toBlock,
 		Addresses: addresses,
-		Topics: [][]common.Hash{
-			{transferEventSig},
-		},
+		Topics:    [][]common.Hash{topics},
 	}
 }
 
@@ -179,7 +221,10 @@ func (c *Client) CallContract(ctx context.Context, contractAddr common.Address,
 	}
 
 	for i := 0; i <= c.config.MaxRetries; i++ {
-		result, err = c.client.CallContract(ctx, msg, nil)
+		err = c.call(ctx, func() (err error) {
+			result, err = c.client.CallContract(ctx, msg, nil)
+			return err
+		})
 		if err == nil {
 			return result, nil
 		}
@@ -197,3 +242,125 @@ func (c *Client) CallContract(ctx context.Context, contractAddr common.Address,
 
 	return nil, fmt.Errorf("failed to call contract %s after %d retries: %w", contractAddr.Hex(), c.config.MaxRetries, err)
 }
+
+// GetBalance returns the latest native ETH balance of an address, in wei
+func (c *Client) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	var balance *big.Int
+	var err error
+
+	account := common.HexToAddress(address)
+
+	for i := 0; i <= c.config.MaxRetries; i++ {
+		err = c.call(ctx, func() (err error) {
+			balance, err = c.client.BalanceAt(ctx, account, nil)
+			return err
+		})
+		if err == nil {
+			return balance, nil
+		}
+
+		c.logger.Warn("Failed to get balance, retrying",
+			zap.String("address", address),
+			zap.Int("attempt", i+1),
+			zap.Error(err),
+		)
+
+		if i < c.config.MaxRetries {
+			time.Sleep(c.config.RetryDelay)
+		}
+	}
+
+	return nil, fmt.Errorf("failed to get balance of %s after %d retries: %w", address, c.config.MaxRetries, err)
+}
+
+// txSummaryBatchSize is the number of transactions looked up per JSON-RPC batch
+const txSummaryBatchSize = 100
+
+// TxSummary is the part of a transaction stored with the transfers it emitted
+type TxSummary struct {
+	From     string // Lowercase sender, the EOA that signed the transaction
+	Selector string // Lowercase 4-byte selector of the input, e.g. 0xa9059cbb; empty for calls without one
+}
+
+// GetTransactionSummaries returns the sender and input selector of each transaction, keyed by
+// lowercase tx hash. Transactions are looked up with batched eth_getTransactionByHash calls;
+// ones the node does not know are left out.
+func (c *Client) GetTransactionSummaries(ctx context.Context, txHashes []string) (map[string]TxSummary, error) {
+	summaries := make(map[string]TxSummary, len(txHashes))
+
+	for start := 0; start < len(txHashes); start += txSummaryBatchSize {
+		end := start + txSummaryBatchSize
+		if end > len(txHashes) {
+			end = len(txHashes)
+		}
+		if err := c.getTransactionSummaryBatch(ctx, txHashes[start:end], summaries); err != nil {
+			return nil, err
+		}
+	}
+
+	return summaries, nil
+}
+
+func (c *Client) getTransactionSummaryBatch(ctx context.Context, txHashes []string, summaries map[string]TxSummary) error {
+	type txResult struct {
+		From  string `json:"from"`
+		Input string `json:"input"`
+	}
+
+	var err error
+	for i := 0; i <= c.config.MaxRetries; i++ {
+		results := make([]*txResult, len(txHashes))
+		batch := make([]rpc.BatchElem, len(txHashes))
+		for j, hash := range txHashes {
+			batch[j] = rpc.BatchElem{
+				Method: "eth_getTransactionByHash",
+				Args:   []interface{}{hash},
+				Result: &results[j],
+			}
+		}
+
+		err = c.call(ctx, func() error {
+			if err := c.client.Client().BatchCallContext(ctx, batch); err != nil {
+				return err
+			}
+			for _, elem := range batch {
+				if elem.Error != nil {
+					return elem.Error
+				}
+			}
+			return nil
+		})
+		if err == nil {
+			for j, result := range results {
+				if result != nil && result.From != "" {
+					summaries[strings.ToLower(txHashes[j])] = TxSummary{
+						From:     strings.ToLower(result.From),
+						Selector: inputSelector(result.Input),
+					}
+				}
+			}
+			return nil
+		}
+
+		c.logger.Warn("Failed to get transactions, retrying",
+			zap.Int("batch_size", len(txHashes)),
+			zap.Int("attempt", i+1),
+			zap.Error(err),
+		)
+
+		if i < c.config.MaxRetries {
+			time.Sleep(c.config.RetryDelay)
+		}
+	}
+
+	return fmt.Errorf("failed to get transactions after %d retries: %w", c.config.MaxRetries, err)
+}
+
+// inputSelector returns the lowercase 0x-prefixed 4-byte selector of hex call data, or "" when the
+// input is too short to hold one
+func inputSelector(input string) string {
+	if len(input) < 10 || !strings.HasPrefix(input, "0x") {
+		return ""
+	}
+	return strings.ToLower(input[:10])
+}
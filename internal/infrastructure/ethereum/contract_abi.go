@@ -0,0 +1,308 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// ErrInvalidContractABI is returned for ABIs that do not parse or lack the selected events
+var ErrInvalidContractABI = errors.New("invalid contract ABI")
+
+// ContractEventNamePrefix starts the registry names of contract ABI event decoders, which are
+// "contract:<address>:<event>"
+const ContractEventNamePrefix = "contract:"
+
+// ContractABI is a contract ABI narrowed to the events selected for indexing
+type ContractABI struct {
+	events map[string]abi.Event
+	names  []string // in selection order
+}
+
+// ParseContractABI parses a JSON ABI and selects the named events, or every event when none are
+// named. Events are named as in the ABI; overloaded events get go-ethereum's numbered names, such
+// as Transfer0. Anonymous events have no signature topic to match and cannot be selected.
+func ParseContractABI(abiJSON string, eventNames []string) (*ContractABI, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContractABI, err)
+	}
+
+	if len(eventNames) == 0 {
+		for name, event := range parsed.Events {
+			if !event.Anonymous {
+				eventNames = append(eventNames, name)
+			}
+		}
+		if len(eventNames) == 0 {
+			return nil, fmt.Errorf("%w: the ABI has no events to index", ErrInvalidContractABI)
+		}
+		// Map order is random; keep the selection stable
+		slices.Sort(eventNames)
+	}
+
+	c := &ContractABI{events: make(map[string]abi.Event, len(eventNames))}
+	for _, name := range eventNames {
+		event, ok := parsed.Events[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: no event %q in the ABI", ErrInvalidContractABI, name)
+		}
+		if event.Anonymous {
+			return nil, fmt.Errorf("%w: event %q is anonymous", ErrInvalidContractABI, name)
+		}
+		if _, dup := c.events[name]; !dup {
+			c.events[name] = event
+			c.names = append(c.names, name)
+		}
+	}
+	return c, nil
+}
+
+// EventNames returns the selected event names
+func (c *ContractABI) EventNames() []string {
+	return c.names
+}
+
+// HasEvent reports whether the event is selected
+func (c *ContractABI) HasEvent(name string) bool {
+	_, ok := c.events[name]
+	return ok
+}
+
+// EventTypes returns registry entries decoding the selected events of the contract at address
+// into *entities.ContractEvent records
+func (c *ContractABI) EventTypes(address string) []EventType {
+	contract := common.HexToAddress(address)
+	contractAddress := strings.ToLower(contract.Hex())
+
+	eventTypes := make([]EventType, 0, len(c.names))
+	for _, name := range c.names {
+		event := c.events[name]
+		eventTypes = append(eventTypes, EventType{
+			Name:      ContractEventNamePrefix + contractAddress + ":" + name,
+			Signature: event.ID,
+			Addresses: []common.Address{contract},
+			Decode: func(log types.Log, blockTimestamp time.Time) (any, error) {
+				return ParseContractEvent(log, blockTimestamp, name, event)
+			},
+		})
+	}
+	return eventTypes
+}
+
+// ParseContractEvent decodes a log of an ABI event. Arguments are keyed by name, unnamed ones as
+// arg0, arg1, ... by position. Integers become JSON numbers, addresses, hashes and bytes lowercase
+// 0x hex; indexed strings, bytes and arrays are only known by the hash in their topic.
+func ParseContractEvent(log types.Log, blockTimestamp time.Time, name string, event abi.Event) (*entities.ContractEvent, error) {
+	if len(log.Topics) == 0 || log.Topics[0] != event.ID {
+		return nil, fmt.Errorf("not a %s event", name)
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(log.Topics)-1 != len(indexed) {
+		return nil, fmt.Errorf("invalid number of topics: expected %d, got %d", len(indexed)+1, len(log.Topics))
+	}
+
+	values := make(map[string]any, len(event.Inputs))
+	if err := event.Inputs.NonIndexed().UnpackIntoMap(values, log.Data); err != nil {
+		return nil, fmt.Errorf("failed to decode %s data: %w", name, err)
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("failed to decode %s topics: %w", name, err)
+	}
+
+	args := make(map[string]any, len(values))
+	for key, value := range values {
+		args[key] = contractArgJSON(reflect.ValueOf(value))
+	}
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s arguments: %w", name, err)
+	}
+
+	topics := make([]string, len(log.Topics))
+	for i, topic := range log.Topics {
+		topics[i] = topic.Hex()
+	}
+
+	return &entities.ContractEvent{
+		ContractAddress: strings.ToLower(log.Address.Hex()),
+		EventName:       name,
+		TxHash:          log.TxHash.Hex(),
+		LogIndex:        int(log.Index),
+		BlockNumber:     int64(log.BlockNumber),
+		BlockTimestamp:  blockTimestamp,
+		Topics:          topics,
+		Args:            encoded,
+	}, nil
+}
+
+var (
+	bigIntPtrType  = reflect.TypeOf((*big.Int)(nil))
+	addressType    = reflect.TypeOf(common.Address{})
+	hashType       = reflect.TypeOf(common.Hash{})
+	byteSliceType  = reflect.TypeOf([]byte(nil))
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+)
+
+// contractArgJSON converts a decoded ABI value to the JSON value it is stored as
+func contractArgJSON(v reflect.Value) any {
+	switch {
+	case !v.IsValid():
+		return nil
+	case v.Type() == bigIntPtrType:
+		if v.IsNil() {
+			return nil
+		}
+		return json.Number(v.Interface().(*big.Int).String())
+	case v.Type() == addressType:
+		return strings.ToLower(v.Interface().(common.Address).Hex())
+	case v.Type() == hashType:
+		return v.Interface().(common.Hash).Hex()
+	case v.Type() == byteSliceType:
+		return "0x" + hex.EncodeToString(v.Bytes())
+	case v.Type() == jsonNumberType:
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Number(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return json.Number(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Array:
+		// Fixed-size bytes, such as bytes32 and function pointers
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return "0x" + hex.EncodeToString(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = contractArgJSON(v.Index(i))
+		}
+		return items
+	case reflect.Struct:
+		// Tuples decode to structs whose fields carry the ABI component names as json tags
+		fields := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" {
+				name = field.Name
+			}
+			fields[name] = contractArgJSON(v.Field(i))
+		}
+		return fields
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return contractArgJSON(v.Elem())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// ArgValue parses a filter value for an event argument into the JSON value the argument is
+// stored as: a number for integers, true or false for bools, lowercase 0x hex for addresses,
+// hashes and bytes, and the string itself for strings. Indexed strings, bytes and arrays are
+// matched by their 32-byte topic hash. Array and tuple arguments cannot be filtered on.
+func (c *ContractABI) ArgValue(eventName, argName, value string) (json.RawMessage, error) {
+	event, ok := c.events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", eventName)
+	}
+
+	for _, arg := range event.Inputs {
+		if arg.Name != argName {
+			continue
+		}
+
+		var parsed any
+		switch {
+		case arg.Indexed && (arg.Type.T == abi.StringTy || arg.Type.T == abi.BytesTy || arg.Type.T == abi.SliceTy || arg.Type.T == abi.ArrayTy):
+			b, ok := parseHexBytes(value)
+			if !ok || len(b) != common.HashLength {
+				return nil, fmt.Errorf("%s is indexed and must be its 32-byte topic hash", argName)
+			}
+			parsed = "0x" + hex.EncodeToString(b)
+		case arg.Type.T == abi.IntTy || arg.Type.T == abi.UintTy:
+			n, ok := new(big.Int).SetString(value, 10)
+			if !ok || (arg.Type.T == abi.UintTy && n.Sign() < 0) {
+				return nil, fmt.Errorf("%s must be a base 10 integer", argName)
+			}
+			parsed = json.Number(n.String())
+		case arg.Type.T == abi.BoolTy:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be true or false", argName)
+			}
+			parsed = b
+		case arg.Type.T == abi.AddressTy:
+			address, err := entities.ParseEVMAddress(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be an address", argName)
+			}
+			parsed = address.String()
+		case arg.Type.T == abi.StringTy:
+			parsed = value
+		case arg.Type.T == abi.BytesTy || arg.Type.T == abi.FixedBytesTy || arg.Type.T == abi.HashTy || arg.Type.T == abi.FunctionTy:
+			b, ok := parseHexBytes(value)
+			if !ok {
+				return nil, fmt.Errorf("%s must be 0x-prefixed hex", argName)
+			}
+			parsed = "0x" + hex.EncodeToString(b)
+		default:
+			return nil, fmt.Errorf("%s is a %s and cannot be filtered on", argName, arg.Type.String())
+		}
+		return json.Marshal(parsed)
+	}
+
+	return nil, fmt.Errorf("event %s has no argument %q", eventName, argName)
+}
+
+// IsIntegerArg reports whether an event argument is an int or uint stored as a JSON number, the
+// arguments that range filters apply to
+func (c *ContractABI) IsIntegerArg(eventName, argName string) bool {
+	for _, arg := range c.events[eventName].Inputs {
+		if arg.Name == argName {
+			return arg.Type.T == abi.IntTy || arg.Type.T == abi.UintTy
+		}
+	}
+	return false
+}
+
+// parseHexBytes decodes 0x-prefixed hex
+func parseHexBytes(s string) ([]byte, bool) {
+	digits, ok := strings.CutPrefix(s, "0x")
+	if !ok {
+		return nil, false
+	}
+	b, err := hex.DecodeString(digits)
+	return b, err == nil
+}
@@ -0,0 +1,206 @@
+package ethereum
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+const testContractABI = `[
+	{"type":"event","name":"Transfer","inputs":[
+		{"name":"from","type":"address","indexed":true},
+		{"name":"to","type":"address","indexed":true},
+		{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Memo","inputs":[
+		{"name":"tag","type":"string","indexed":true},
+		{"name":"text","type":"string","indexed":false},
+		{"name":"ok","type":"bool","indexed":false}]},
+	{"type":"event","name":"Hidden","anonymous":true,"inputs":[]},
+	{"type":"function","name":"transfer","inputs":[
+		{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[]}
+]`
+
+const testContractAddress = "0x5c69bee701ef814a2b6a3edd4b1652cb9cc5aa6f"
+
+func TestParseContractABI_SelectsEvents(t *testing.T) {
+	all, err := ParseContractABI(testContractABI, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := all.EventNames(); len(names) != 2 || names[0] != "Memo" || names[1] != "Transfer" {
+		t.Errorf("expected the non-anonymous events in order, got %v", names)
+	}
+
+	one, err := ParseContractABI(testContractABI, []string{"Transfer", "Transfer"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !one.HasEvent("Transfer") || one.HasEvent("Memo") || len(one.EventNames()) != 1 {
+		t.Errorf("expected only Transfer, got %v", one.EventNames())
+	}
+
+	for name, tc := range map[string]struct {
+		abi    string
+		events []string
+	}{
+		"malformed":     {`[{"type":`, nil},
+		"missing event": {testContractABI, []string{"Swap"}},
+		"anonymous":     {testContractABI, []string{"Hidden"}},
+		"no events":     {`[{"type":"function","name":"f","inputs":[],"outputs":[]}]`, nil},
+	} {
+		if _, err := ParseContractABI(tc.abi, tc.events); !errors.Is(err, ErrInvalidContractABI) {
+			t.Errorf("%s: expected ErrInvalidContractABI, got %v", name, err)
+		}
+	}
+}
+
+func TestContractABI_EventTypes(t *testing.T) {
+	parsed, err := ParseContractABI(testContractABI, []string{"Transfer"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eventTypes := parsed.EventTypes("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f")
+	if len(eventTypes) != 1 {
+		t.Fatalf("expected one event type, got %d", len(eventTypes))
+	}
+	et := eventTypes[0]
+	if et.Name != "contract:"+testContractAddress+":Transfer" {
+		t.Errorf("unexpected name %s", et.Name)
+	}
+	if et.Signature != TransferEventSignature {
+		t.Errorf("expected the Transfer signature, got %s", et.Signature.Hex())
+	}
+	if len(et.Addresses) != 1 || et.Addresses[0] != common.HexToAddress(testContractAddress) {
+		t.Errorf("expected the event scoped to the contract, got %v", et.Addresses)
+	}
+}
+
+func TestParseContractEvent_DecodesArgs(t *testing.T) {
+	parsed, err := ParseContractABI(testContractABI, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts := time.Unix(1700000000, 0)
+
+	// 2^70 does not fit in 64 bits and must keep every digit
+	value, _ := new(big.Int).SetString("1180591620717411303424", 10)
+	log := types.Log{
+		Address: common.HexToAddress(testContractAddress),
+		Topics: []common.Hash{
+			TransferEventSignature,
+			common.BytesToHash(common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes()),
+			common.BytesToHash(common.HexToAddress("0xABCDEF0123456789ABCDEF0123456789ABCDEF01").Bytes()),
+		},
+		Data:        common.LeftPadBytes(value.Bytes(), 32),
+		BlockNumber: 19000000,
+		TxHash:      common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111"),
+		Index:       3,
+	}
+
+	result, err := parsed.EventTypes(testContractAddress)[1].Decode(log, ts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	event := result.(*entities.ContractEvent)
+
+	if event.ContractAddress != testContractAddress || event.EventName != "Transfer" || event.LogIndex != 3 || event.BlockNumber != 19000000 || !event.BlockTimestamp.Equal(ts) {
+		t.Errorf("unexpected event fields: %+v", event)
+	}
+	if len(event.Topics) != 3 || event.Topics[0] != TransferEventSignature.Hex() {
+		t.Errorf("expected the signature and two indexed topics, got %v", event.Topics)
+	}
+	want := `{"from":"0x1111111111111111111111111111111111111111","to":"0xabcdef0123456789abcdef0123456789abcdef01","value":1180591620717411303424}`
+	if string(event.Args) != want {
+		t.Errorf("expected args %s, got %s", want, event.Args)
+	}
+
+	// A log of another event is rejected
+	log.Topics[0] = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+	if _, err := ParseContractEvent(log, ts, "Transfer", parsed.events["Transfer"]); err == nil {
+		t.Error("expected an error for a log of another event")
+	}
+}
+
+func TestParseContractEvent_IndexedString(t *testing.T) {
+	parsed, err := ParseContractABI(testContractABI, []string{"Memo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	memo := parsed.events["Memo"]
+
+	data, err := memo.Inputs.NonIndexed().Pack("hello", true)
+	if err != nil {
+		t.Fatalf("failed to pack data: %v", err)
+	}
+	tag := crypto.Keccak256Hash([]byte("greeting"))
+	log := types.Log{
+		Address: common.HexToAddress(testContractAddress),
+		Topics:  []common.Hash{memo.ID, tag},
+		Data:    data,
+	}
+
+	event, err := ParseContractEvent(log, time.Unix(1700000000, 0), "Memo", memo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"ok":true,"tag":"` + tag.Hex() + `","text":"hello"}`
+	if string(event.Args) != want {
+		t.Errorf("expected args %s, got %s", want, event.Args)
+	}
+
+	log.Topics = log.Topics[:1]
+	if _, err := ParseContractEvent(log, time.Unix(1700000000, 0), "Memo", memo); err == nil {
+		t.Error("expected an error for a missing indexed topic")
+	}
+}
+
+func TestContractABI_ArgValue(t *testing.T) {
+	parsed, err := ParseContractABI(testContractABI, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tag := crypto.Keccak256Hash([]byte("greeting")).Hex()
+
+	tests := []struct {
+		event, arg, value string
+		want              string // empty when the value is rejected
+	}{
+		{"Transfer", "to", "0xABCDEF0123456789ABCDEF0123456789ABCDEF01", `"0xabcdef0123456789abcdef0123456789abcdef01"`},
+		{"Transfer", "to", "0xabc", ""},
+		{"Transfer", "value", "1180591620717411303424", `1180591620717411303424`},
+		{"Transfer", "value", "-1", ""},
+		{"Transfer", "value", "1e18", ""},
+		{"Transfer", "amount", "1", ""},
+		{"Memo", "ok", "true", `true`},
+		{"Memo", "ok", "yes", ""},
+		{"Memo", "text", "hello", `"hello"`},
+		{"Memo", "tag", tag, `"` + tag + `"`},
+		{"Memo", "tag", "greeting", ""},
+		{"Swap", "to", "0x1111111111111111111111111111111111111111", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := parsed.ArgValue(tt.event, tt.arg, tt.value)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s.%s=%s: expected an error, got %s", tt.event, tt.arg, tt.value, got)
+			}
+			continue
+		}
+		if err != nil || string(got) != tt.want {
+			t.Errorf("%s.%s=%s: expected %s, got %s (%v)", tt.event, tt.arg, tt.value, tt.want, got, err)
+		}
+	}
+
+	if !parsed.IsIntegerArg("Transfer", "value") || parsed.IsIntegerArg("Transfer", "to") || parsed.IsIntegerArg("Swap", "value") {
+		t.Error("expected only Transfer.value to be an integer argument")
+	}
+}
@@ -0,0 +1,176 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// Registry names of the DEX swap decoders
+const (
+	SwapV2EventName = "uniswap_v2_swap"
+	SwapV3EventName = "uniswap_v3_swap"
+)
+
+var (
+	// SwapV2EventSignature is keccak256 of Swap(address,uint256,uint256,uint256,uint256,address)
+	SwapV2EventSignature = common.HexToHash("0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822")
+	// SwapV3EventSignature is keccak256 of Swap(address,address,int256,int256,uint160,uint128,int24)
+	SwapV3EventSignature = common.HexToHash("0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67")
+)
+
+// Pool function selectors
+var (
+	// token0() -> 0x0dfe1681
+	token0Sig = common.FromHex("0x0dfe1681")
+	// token1() -> 0xd21220a7
+	token1Sig = common.FromHex("0xd21220a7")
+)
+
+// two256 is 2^256, used to decode two's complement int256 values
+var two256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// FetchPool reads token0 and token1 of a Uniswap-style pool via eth_call
+func (c *Client) FetchPool(ctx context.Context, poolAddress string) (*entities.DexPool, error) {
+	addr := common.HexToAddress(poolAddress)
+
+	token0, err := c.callAddress(ctx, addr, token0Sig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token0: %w", err)
+	}
+
+	token1, err := c.callAddress(ctx, addr, token1Sig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token1: %w", err)
+	}
+
+	return &entities.DexPool{
+		Address:       strings.ToLower(addr.Hex()),
+		Token0Address: strings.ToLower(token0.Hex()),
+		Token1Address: strings.ToLower(token1.Hex()),
+	}, nil
+}
+
+// callAddress executes a call returning a single ABI-encoded address
+func (c *Client) callAddress(ctx context.Context, contract common.Address, data []byte) (common.Address, error) {
+	result, err := c.CallContract(ctx, contract, data)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(result) < 32 {
+		return common.Address{}, fmt.Errorf("invalid address response length: %d", len(result))
+	}
+	return common.BytesToAddress(result[12:32]), nil
+}
+
+// SwapEventTypes returns registry entries decoding Uniswap V2 and V3 Swap events emitted by the given pools
+func SwapEventTypes(pools []entities.DexPool) []EventType {
+	byAddress := make(map[common.Address]entities.DexPool, len(pools))
+	addresses := make([]common.Address, 0, len(pools))
+	for _, p := range pools {
+		addr := common.HexToAddress(p.Address)
+		byAddress[addr] = p
+		addresses = append(addresses, addr)
+	}
+
+	return []EventType{
+		{
+			Name:      SwapV2EventName,
+			Signature: SwapV2EventSignature,
+			Addresses: addresses,
+			Decode: func(log types.Log, blockTimestamp time.Time) (any, error) {
+				return ParseSwapV2Event(log, blockTimestamp, byAddress[log.Address])
+			},
+		},
+		{
+			Name:      SwapV3EventName,
+			Signature: SwapV3EventSignature,
+			Addresses: addresses,
+			Decode: func(log types.Log, blockTimestamp time.Time) (any, error) {
+				return ParseSwapV3Event(log, blockTimestamp, byAddress[log.Address])
+			},
+		},
+	}
+}
+
+// ParseSwapV2Event parses a Uniswap V2 Swap log.
+// V2 reports gross in/out amounts; they are netted into signed pool-side deltas.
+func ParseSwapV2Event(log types.Log, blockTimestamp time.Time, pool entities.DexPool) (*entities.Swap, error) {
+	if len(log.Topics) != 3 {
+		return nil, fmt.Errorf("invalid number of topics: expected 3, got %d", len(log.Topics))
+	}
+	if log.Topics[0] != SwapV2EventSignature {
+		return nil, fmt.Errorf("not a Uniswap V2 Swap event")
+	}
+	if len(log.Data) != 128 {
+		return nil, fmt.Errorf("invalid data length: expected 128, got %d", len(log.Data))
+	}
+
+	amount0In := new(big.Int).SetBytes(log.Data[0:32])
+	amount1In := new(big.Int).SetBytes(log.Data[32:64])
+	amount0Out := new(big.Int).SetBytes(log.Data[64:96])
+	amount1Out := new(big.Int).SetBytes(log.Data[96:128])
+
+	amount0 := new(big.Int).Sub(amount0In, amount0Out)
+	amount1 := new(big.Int).Sub(amount1In, amount1Out)
+
+	return newSwap(log, blockTimestamp, pool, "uniswap_v2", amount0, amount1), nil
+}
+
+// ParseSwapV3Event parses a Uniswap V3 Swap log, whose amounts are already signed pool-side deltas
+func ParseSwapV3Event(log types.Log, blockTimestamp time.Time, pool entities.DexPool) (*entities.Swap, error) {
+	if len(log.Topics) != 3 {
+		return nil, fmt.Errorf("invalid number of topics: expected 3, got %d", len(log.Topics))
+	}
+	if log.Topics[0] != SwapV3EventSignature {
+		return nil, fmt.Errorf("not a Uniswap V3 Swap event")
+	}
+	// amount0, amount1, sqrtPriceX96, liquidity, tick
+	if len(log.Data) != 160 {
+		return nil, fmt.Errorf("invalid data length: expected 160, got %d", len(log.Data))
+	}
+
+	amount0 := decodeInt256(log.Data[0:32])
+	amount1 := decodeInt256(log.Data[32:64])
+
+	return newSwap(log, blockTimestamp, pool, "uniswap_v3", amount0, amount1), nil
+}
+
+// newSwap builds a Swap entity from a log whose topics are [signature, sender, recipient]
+func newSwap(log types.Log, blockTimestamp time.Time, pool entities.DexPool, protocol string, amount0, amount1 *big.Int) *entities.Swap {
+	sender := common.BytesToAddress(log.Topics[1].Bytes())
+	recipient := common.BytesToAddress(log.Topics[2].Bytes())
+
+	return &entities.Swap{
+		TxHash:         log.TxHash.Hex(),
+		LogIndex:       int(log.Index),
+		BlockNumber:    int64(log.BlockNumber),
+		BlockTimestamp: blockTimestamp,
+		PoolAddress:    strings.ToLower(log.Address.Hex()),
+		Protocol:       protocol,
+		Sender:         strings.ToLower(sender.Hex()),
+		Recipient:      strings.ToLower(recipient.Hex()),
+		Token0Address:  pool.Token0Address,
+		Token1Address:  pool.Token1Address,
+		Amount0:        amount0,
+		Amount0String:  amount0.String(),
+		Amount1:        amount1,
+		Amount1String:  amount1.String(),
+	}
+}
+
+// decodeInt256 decodes a 32-byte two's complement signed integer
+func decodeInt256(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v.Sub(v, two256)
+	}
+	return v
+}
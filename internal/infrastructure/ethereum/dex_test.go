@@ -0,0 +1,148 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+var testPool = entities.DexPool{
+	Address:       "0x0d4a11d5eeaac28ec3f61d100daf4d40471f1852",
+	Token0Address: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+	Token1Address: "0xdac17f958d2ee523a2206206994597c13d831ec7",
+}
+
+func swapLog(sig common.Hash, words ...*big.Int) types.Log {
+	data := make([]byte, 0, 32*len(words))
+	for _, w := range words {
+		b := w
+		if w.Sign() < 0 {
+			b = new(big.Int).Add(w, two256)
+		}
+		data = append(data, common.LeftPadBytes(b.Bytes(), 32)...)
+	}
+
+	return types.Log{
+		Address: common.HexToAddress(testPool.Address),
+		Topics: []common.Hash{
+			sig,
+			common.BytesToHash(common.HexToAddress("0x7a250d5630b4cf539739df2c5dacb4c659f2488d").Bytes()),
+			common.BytesToHash(common.HexToAddress("0x1234567890123456789012345678901234567890").Bytes()),
+		},
+		Data:        data,
+		BlockNumber: 19000000,
+		TxHash:      common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111"),
+		Index:       7,
+	}
+}
+
+func TestSwapEventSignatures(t *testing.T) {
+	v2 := crypto.Keccak256Hash([]byte("Swap(address,uint256,uint256,uint256,uint256,address)"))
+	if SwapV2EventSignature != v2 {
+		t.Errorf("SwapV2EventSignature mismatch: expected %s, got %s", v2.Hex(), SwapV2EventSignature.Hex())
+	}
+
+	v3 := crypto.Keccak256Hash([]byte("Swap(address,address,int256,int256,uint160,uint128,int24)"))
+	if SwapV3EventSignature != v3 {
+		t.Errorf("SwapV3EventSignature mismatch: expected %s, got %s", v3.Hex(), SwapV3EventSignature.Hex())
+	}
+}
+
+func TestParseSwapV2Event_NetsAmounts(t *testing.T) {
+	// 1 WETH in, 3000 USDT out
+	log := swapLog(SwapV2EventSignature,
+		big.NewInt(1e18), big.NewInt(0), big.NewInt(0), big.NewInt(3000e6))
+	ts := time.Unix(1700000000, 0)
+
+	swap, err := ParseSwapV2Event(log, ts, testPool)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if swap.Amount0String != "1000000000000000000" {
+		t.Errorf("expected amount0 1e18, got %s", swap.Amount0String)
+	}
+	if swap.Amount1String != "-3000000000" {
+		t.Errorf("expected amount1 -3000000000, got %s", swap.Amount1String)
+	}
+	if swap.Protocol != "uniswap_v2" {
+		t.Errorf("expected protocol uniswap_v2, got %s", swap.Protocol)
+	}
+	if swap.PoolAddress != testPool.Address || swap.Token0Address != testPool.Token0Address || swap.Token1Address != testPool.Token1Address {
+		t.Errorf("unexpected pool fields: %+v", swap)
+	}
+	if swap.Sender != "0x7a250d5630b4cf539739df2c5dacb4c659f2488d" {
+		t.Errorf("unexpected sender %s", swap.Sender)
+	}
+	if swap.LogIndex != 7 || swap.BlockNumber != 19000000 || !swap.BlockTimestamp.Equal(ts) {
+		t.Errorf("unexpected log position: %+v", swap)
+	}
+}
+
+func TestParseSwapV3Event_SignedAmounts(t *testing.T) {
+	log := swapLog(SwapV3EventSignature,
+		big.NewInt(-2500e6), big.NewInt(1e18), big.NewInt(1), big.NewInt(1), big.NewInt(1))
+
+	swap, err := ParseSwapV3Event(log, time.Now(), testPool)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if swap.Amount0String != "-2500000000" {
+		t.Errorf("expected amount0 -2500000000, got %s", swap.Amount0String)
+	}
+	if swap.Amount1String != "1000000000000000000" {
+		t.Errorf("expected amount1 1e18, got %s", swap.Amount1String)
+	}
+	if swap.Protocol != "uniswap_v3" {
+		t.Errorf("expected protocol uniswap_v3, got %s", swap.Protocol)
+	}
+}
+
+func TestParseSwapEvent_InvalidData(t *testing.T) {
+	v2 := swapLog(SwapV2EventSignature, big.NewInt(1))
+	if _, err := ParseSwapV2Event(v2, time.Now(), testPool); err == nil {
+		t.Error("expected error for short V2 data")
+	}
+
+	v3 := swapLog(SwapV3EventSignature, big.NewInt(1), big.NewInt(1))
+	if _, err := ParseSwapV3Event(v3, time.Now(), testPool); err == nil {
+		t.Error("expected error for short V3 data")
+	}
+
+	wrongSig := swapLog(TransferEventSignature, big.NewInt(1), big.NewInt(1), big.NewInt(1), big.NewInt(1))
+	if _, err := ParseSwapV2Event(wrongSig, time.Now(), testPool); err == nil {
+		t.Error("expected error for wrong signature")
+	}
+}
+
+func TestSwapEventTypes_Registered(t *testing.T) {
+	r := DefaultEventRegistry()
+	for _, et := range SwapEventTypes([]entities.DexPool{testPool}) {
+		if err := r.Register(et); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	log := swapLog(SwapV3EventSignature,
+		big.NewInt(1), big.NewInt(-1), big.NewInt(1), big.NewInt(1), big.NewInt(1))
+
+	et, ok := r.Match(log, map[common.Address]struct{}{})
+	if !ok || et.Name != SwapV3EventName {
+		t.Fatalf("expected %s match, got %q (matched=%v)", SwapV3EventName, et.Name, ok)
+	}
+
+	record, err := et.Decode(log, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if swap, ok := record.(*entities.Swap); !ok || swap.Token0Address != testPool.Token0Address {
+		t.Errorf("expected swap with pool tokens, got %#v", record)
+	}
+}
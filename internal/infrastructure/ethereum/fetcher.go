@@ -2,62 +2,88 @@ package ethereum
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/bimakw/chain-indexer/internal/config"
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/chain"
 )
 
 // Fetcher handles fetching and parsing blockchain data
 type Fetcher struct {
-	client *Client
-	config config.IndexerConfig
-	logger *zap.Logger
+	client   *Client
+	registry *EventRegistry
+	config   config.IndexerConfig
+	logger   *zap.Logger
 }
 
 // NewFetcher creates a new blockchain data fetcher
 func NewFetcher(client *Client, cfg config.IndexerConfig, logger *zap.Logger) *Fetcher {
 	return &Fetcher{
-		client: client,
-		config: cfg,
-		logger: logger,
+		client:   client,
+		registry: DefaultEventRegistry(),
+		config:   cfg,
+		logger:   logger,
 	}
 }
 
-// FetchResult contains the result of fetching transfers
-type FetchResult struct {
-	Transfers      []entities.Transfer
-	FromBlock      int64
-	ToBlock        int64
-	FailedLogCount int
+// Registry returns the event registry used to build log queries and decode logs
+func (f *Fetcher) Registry() *EventRegistry {
+	return f.registry
 }
 
-// FetchTransfers fetches Transfer events for a range of blocks
-func (f *Fetcher) FetchTransfers(ctx context.Context, tokenAddresses []string, fromBlock, toBlock int64) (*FetchResult, error) {
+// FetchTransfers fetches Transfer events, and any other registered events matched against the
+// token addresses, for a range of blocks. Their topics are requested in a single getLogs call.
+// Events scoped to their own addresses are fetched separately by FetchScopedEvents.
+func (f *Fetcher) FetchTransfers(ctx context.Context, tokenAddresses []string, fromBlock, toBlock int64) (*chain.FetchResult, error) {
 	// Convert addresses to common.Address
+	targets := make(map[common.Address]struct{}, len(tokenAddresses))
 	addresses := make([]common.Address, len(tokenAddresses))
 	for i, addr := range tokenAddresses {
 		addresses[i] = common.HexToAddress(addr)
+		targets[addresses[i]] = struct{}{}
 	}
 
+	return f.fetchLogs(ctx, addresses, f.registry.TokenTopics(), targets, fromBlock, toBlock)
+}
+
+// HasScopedEvents reports whether any registered event is scoped to its own addresses
+func (f *Fetcher) HasScopedEvents() bool {
+	return len(f.registry.ScopedAddresses()) > 0
+}
+
+// FetchScopedEvents fetches the events scoped to their own addresses, such as DEX pool swaps, for
+// a range of blocks in a single getLogs call. Callers fetch each range once, whatever the number
+// of tracked tokens.
+func (f *Fetcher) FetchScopedEvents(ctx context.Context, fromBlock, toBlock int64) (*chain.FetchResult, error) {
+	return f.fetchLogs(ctx, f.registry.ScopedAddresses(), f.registry.ScopedTopics(), nil, fromBlock, toBlock)
+}
+
+// fetchLogs runs one getLogs query and decodes its logs; targets are the addresses unscoped
+// event types are matched against
+func (f *Fetcher) fetchLogs(ctx context.Context, addresses []common.Address, topics []common.Hash, targets map[common.Address]struct{}, fromBlock, toBlock int64) (*chain.FetchResult, error) {
 	// Build and execute filter query
-	query := f.client.BuildFilterQuery(
+	query := f.client.BuildEventFilterQuery(
 		big.NewInt(fromBlock),
 		big.NewInt(toBlock),
 		addresses,
+		topics,
 	)
 
 	f.logger.Debug("Fetching logs",
 		zap.Int64("from_block", fromBlock),
 		zap.Int64("to_block", toBlock),
-		zap.Int("token_count", len(tokenAddresses)),
+		zap.Int("address_count", len(addresses)),
 	)
 
 	logs, err := f.client.GetLogs(ctx, query)
@@ -66,8 +92,9 @@ func (f *Fetcher) FetchTransfers(ctx context.Context, tokenAddresses []string, f
 	}
 
 	if len(logs) == 0 {
-		return &FetchResult{
+		return &chain.FetchResult{
 			Transfers: []entities.Transfer{},
+			Events:    map[string][]any{},
 			FromBlock: fromBlock,
 			ToBlock:   toBlock,
 		}, nil
@@ -84,28 +111,155 @@ func (f *Fetcher) FetchTransfers(ctx context.Context, tokenAddresses []string, f
 		return nil, fmt.Errorf("failed to fetch block timestamps: %w", err)
 	}
 
-	// Parse logs into transfers
-	transfers, failedIndices := ParseTransferLogs(logs, blockTimestamps)
+	// Decode logs with their registered event types
+	transfers, events, failedCount := f.decodeLogs(logs, blockTimestamps, targets)
 
-	if len(failedIndices) > 0 {
+	if failedCount > 0 {
 		f.logger.Warn("Failed to parse some logs",
-			zap.Int("failed_count", len(failedIndices)),
+			zap.Int("failed_count", failedCount),
 			zap.Int("total_logs", len(logs)),
 		)
 	}
 
+	if f.config.EnrichInitiator {
+		if err := f.EnrichTransactions(ctx, transfers); err != nil {
+			return nil, err
+		}
+	}
+
 	f.logger.Info("Fetched transfers",
 		zap.Int64("from_block", fromBlock),
 		zap.Int64("to_block", toBlock),
 		zap.Int("transfer_count", len(transfers)),
 	)
 
-	return &FetchResult{
+	result := &chain.FetchResult{
 		Transfers:      transfers,
+		Events:         events,
 		FromBlock:      fromBlock,
 		ToBlock:        toBlock,
-		FailedLogCount: len(failedIndices),
-	}, nil
+		FailedLogCount: failedCount,
+	}
+
+	if f.config.StoreRawLogs {
+		result.RawLogs, err = toRawLogs(logs, blockTimestamps)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// toRawLogs encodes logs for the raw log archive
+func toRawLogs(logs []types.Log, blockTimestamps map[uint64]time.Time) ([]entities.RawLog, error) {
+	raws := make([]entities.RawLog, len(logs))
+	for i := range logs {
+		encoded, err := json.Marshal(&logs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode log: %w", err)
+		}
+		raws[i] = entities.RawLog{
+			BlockNumber:    int64(logs[i].BlockNumber),
+			TxHash:         lowerHex(logs[i].TxHash[:]),
+			LogIndex:       int(logs[i].Index),
+			Address:        lowerHex(logs[i].Address[:]),
+			BlockTimestamp: blockTimestamps[logs[i].BlockNumber],
+			Log:            encoded,
+		}
+	}
+	return raws, nil
+}
+
+// DecodeRawLogs decodes archived logs with the registered event types, as FetchTransfers would
+// have when they were fetched. Only logs of the given token addresses yield transfers.
+func (f *Fetcher) DecodeRawLogs(raws []entities.RawLog, tokenAddresses []string) ([]entities.Transfer, map[string][]any, int, error) {
+	targets := make(map[common.Address]struct{}, len(tokenAddresses))
+	for _, addr := range tokenAddresses {
+		targets[common.HexToAddress(addr)] = struct{}{}
+	}
+
+	logs := make([]types.Log, len(raws))
+	blockTimestamps := make(map[uint64]time.Time)
+	for i, raw := range raws {
+		if err := json.Unmarshal(raw.Log, &logs[i]); err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to decode raw log %s:%d: %w", raw.TxHash, raw.LogIndex, err)
+		}
+		blockTimestamps[logs[i].BlockNumber] = raw.BlockTimestamp
+	}
+
+	transfers, events, failed := f.decodeLogs(logs, blockTimestamps, targets)
+	return transfers, events, failed, nil
+}
+
+// decodeLogs dispatches each log to its registered event type.
+// Transfers are returned separately; other records are grouped by event type name.
+func (f *Fetcher) decodeLogs(logs []types.Log, blockTimestamps map[uint64]time.Time, targets map[common.Address]struct{}) ([]entities.Transfer, map[string][]any, int) {
+	transfers := make([]entities.Transfer, 0, len(logs))
+	events := make(map[string][]any)
+	failed := 0
+
+	for _, log := range logs {
+		et, ok := f.registry.Match(log, targets)
+		if !ok {
+			continue
+		}
+
+		timestamp, ok := blockTimestamps[log.BlockNumber]
+		if !ok {
+			failed++
+			continue
+		}
+
+		record, err := et.Decode(log, timestamp)
+		if err != nil {
+			failed++
+			continue
+		}
+
+		if transfer, ok := record.(*entities.Transfer); ok && et.Name == TransferEventName {
+			transfers = append(transfers, *transfer)
+			continue
+		}
+		events[et.Name] = append(events[et.Name], record)
+	}
+
+	return transfers, events, failed
+}
+
+// EnrichTransactions sets each transfer's Initiator and MethodSelector from its transaction,
+// looking up every distinct transaction once
+func (f *Fetcher) EnrichTransactions(ctx context.Context, transfers []entities.Transfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var txHashes []string
+	for _, t := range transfers {
+		hash := strings.ToLower(t.TxHash)
+		if _, ok := seen[hash]; !ok {
+			seen[hash] = struct{}{}
+			txHashes = append(txHashes, hash)
+		}
+	}
+
+	summaries, err := f.client.GetTransactionSummaries(ctx, txHashes)
+	if err != nil {
+		return fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	for i := range transfers {
+		summary, ok := summaries[strings.ToLower(transfers[i].TxHash)]
+		if !ok {
+			continue
+		}
+		transfers[i].Initiator = &summary.From
+		if summary.Selector != "" {
+			transfers[i].MethodSelector = &summary.Selector
+		}
+	}
+	return nil
 }
 
 // fetchBlockTimestamps fetches timestamps for multiple blocks concurrently
@@ -139,19 +293,82 @@ func (f *Fetcher) fetchBlockTimestamps(ctx context.Context, blockNumbers map[uin
 	return timestamps, nil
 }
 
-// GetSafeBlockNumber returns the latest block number minus confirmations
-func (f *Fetcher) GetSafeBlockNumber(ctx context.Context) (int64, error) {
+// FetchEthTransfers traces every block in a range and returns its native ETH transfers in block order.
+// Blocks are traced concurrently, one trace call per block.
+func (f *Fetcher) FetchEthTransfers(ctx context.Context, fromBlock, toBlock int64) ([]entities.EthTransfer, error) {
+	perBlock := make([][]entities.EthTransfer, toBlock-fromBlock+1)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(f.config.WorkerCount)
+
+	for blockNum := fromBlock; blockNum <= toBlock; blockNum++ {
+		blockNum := blockNum // capture
+		g.Go(func() error {
+			transfers, err := f.client.TraceBlock(gctx, blockNum, f.config.TraceMethod)
+			if err != nil {
+				return err
+			}
+			perBlock[blockNum-fromBlock] = transfers
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	blockNumbers := make(map[uint64]struct{})
+	var transfers []entities.EthTransfer
+	for _, blockTransfers := range perBlock {
+		for _, t := range blockTransfers {
+			blockNumbers[uint64(t.BlockNumber)] = struct{}{}
+		}
+		transfers = append(transfers, blockTransfers...)
+	}
+
+	if len(transfers) == 0 {
+		return transfers, nil
+	}
+
+	blockTimestamps, err := f.fetchBlockTimestamps(ctx, blockNumbers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block timestamps: %w", err)
+	}
+	for i := range transfers {
+		transfers[i].BlockTimestamp = blockTimestamps[uint64(transfers[i].BlockNumber)]
+	}
+
+	f.logger.Debug("Fetched ETH transfers",
+		zap.Int64("from_block", fromBlock),
+		zap.Int64("to_block", toBlock),
+		zap.Int("transfer_count", len(transfers)),
+	)
+
+	return transfers, nil
+}
+
+// GetLatestBlockNumber returns the chain head; ConfirmedBlock turns it into the last block to index
+func (f *Fetcher) GetLatestBlockNumber(ctx context.Context) (int64, error) {
 	latestBlock, err := f.client.GetLatestBlockNumber(ctx)
 	if err != nil {
 		return 0, err
 	}
+	return int64(latestBlock), nil
+}
 
-	safeBlock := int64(latestBlock) - int64(f.config.BlockConfirmations)
-	if safeBlock < 0 {
-		safeBlock = 0
-	}
+// GetBlockTimestamp returns the time a block was mined
+func (f *Fetcher) GetBlockTimestamp(ctx context.Context, blockNumber int64) (time.Time, error) {
+	return f.client.GetBlockTimestamp(ctx, uint64(blockNumber))
+}
+
+// Family returns the address family of EVM chains, whose tokens the fetcher indexes
+func (f *Fetcher) Family() entities.ChainFamily {
+	return entities.ChainFamilyEVM
+}
 
-	return safeBlock, nil
+// ConfirmedBlock returns the latest block with the given number of confirmations on top of it, at least 0
+func ConfirmedBlock(head int64, confirmations int) int64 {
+	return max(head-int64(confirmations), 0)
 }
 
 // BlockRange represents a range of blocks to fetch
@@ -0,0 +1,90 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+func TestDecodeRawLogs_RoundTrip(t *testing.T) {
+	tokenAddr := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	otherAddr := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	fromAddr := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	toAddr := common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcdefabcd")
+	blockTimestamp := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	transferLog := func(token common.Address, index uint) types.Log {
+		return types.Log{
+			Address: token,
+			Topics: []common.Hash{
+				TransferEventSignature,
+				common.BytesToHash(fromAddr.Bytes()),
+				common.BytesToHash(toAddr.Bytes()),
+			},
+			Data:        common.LeftPadBytes(big.NewInt(1000000).Bytes(), 32),
+			BlockNumber: 12345678,
+			TxHash:      common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111"),
+			Index:       index,
+		}
+	}
+
+	logs := []types.Log{transferLog(tokenAddr, 5), transferLog(otherAddr, 6)}
+	raws, err := toRawLogs(logs, map[uint64]time.Time{12345678: blockTimestamp})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(raws) != 2 {
+		t.Fatalf("expected 2 raw logs, got %d", len(raws))
+	}
+	if raws[0].BlockNumber != 12345678 || raws[0].LogIndex != 5 {
+		t.Errorf("unexpected raw log key: block %d, index %d", raws[0].BlockNumber, raws[0].LogIndex)
+	}
+	if raws[0].Address != "0xdac17f958d2ee523a2206206994597c13d831ec7" {
+		t.Errorf("expected lowercase address, got %s", raws[0].Address)
+	}
+
+	fetcher := NewFetcher(nil, config.IndexerConfig{}, zap.NewNop())
+	transfers, _, failed, err := fetcher.DecodeRawLogs(raws, []string{tokenAddr.Hex()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if failed != 0 {
+		t.Errorf("expected no failed logs, got %d", failed)
+	}
+	// Only the requested token's log yields a transfer
+	if len(transfers) != 1 {
+		t.Fatalf("expected 1 transfer, got %d", len(transfers))
+	}
+
+	transfer := transfers[0]
+	if transfer.LogIndex != 5 {
+		t.Errorf("LogIndex mismatch: expected 5, got %d", transfer.LogIndex)
+	}
+	if transfer.ValueString != "1000000" {
+		t.Errorf("Value mismatch: expected 1000000, got %s", transfer.ValueString)
+	}
+	if !transfer.BlockTimestamp.Equal(blockTimestamp) {
+		t.Errorf("BlockTimestamp mismatch: expected %v, got %v", blockTimestamp, transfer.BlockTimestamp)
+	}
+}
+
+func TestDecodeRawLogs_InvalidJSON(t *testing.T) {
+	raws, err := toRawLogs([]types.Log{{BlockNumber: 1}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raws[0].Log = []byte("not json")
+
+	fetcher := NewFetcher(nil, config.IndexerConfig{}, zap.NewNop())
+	if _, _, _, err := fetcher.DecodeRawLogs(raws, nil); err == nil {
+		t.Error("expected error for malformed raw log")
+	}
+}
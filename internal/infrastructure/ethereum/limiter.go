@@ -0,0 +1,111 @@
+package ethereum
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// RateLimiter throttles requests to one RPC provider: a token bucket bounds the request rate
+// and a semaphore bounds how many requests are in flight. A nil *RateLimiter allows everything.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second; 0 disables the rate limit
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	inflight *semaphore.Weighted // nil disables the concurrency cap
+}
+
+// NewRateLimiter creates a limiter allowing requestsPerSecond requests with bursts of up to
+// one second's worth, and at most maxConcurrent requests at once. Zero disables either limit;
+// nil is returned when both are disabled.
+func NewRateLimiter(requestsPerSecond float64, maxConcurrent int) *RateLimiter {
+	if requestsPerSecond <= 0 && maxConcurrent <= 0 {
+		return nil
+	}
+
+	l := &RateLimiter{}
+	if requestsPerSecond > 0 {
+		l.rate = requestsPerSecond
+		l.burst = requestsPerSecond
+		if l.burst < 1 {
+			l.burst = 1
+		}
+		l.tokens = l.burst
+		l.last = time.Now()
+	}
+	if maxConcurrent > 0 {
+		l.inflight = semaphore.NewWeighted(int64(maxConcurrent))
+	}
+	return l
+}
+
+// Acquire waits until a request may be sent. The returned func must be called when the request
+// completes to free its concurrency slot.
+func (l *RateLimiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	if l.inflight != nil {
+		if err := l.inflight.Acquire(ctx, 1); err != nil {
+			return nil, err
+		}
+	}
+	release := func() {
+		if l.inflight != nil {
+			l.inflight.Release(1)
+		}
+	}
+
+	if err := l.wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// wait takes a token from the bucket, sleeping until one is available
+func (l *RateLimiter) wait(ctx context.Context) error {
+	if l.rate == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// Reserve a token up front so concurrent waiters queue behind each other
+	l.tokens--
+	delay := time.Duration(0)
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Hand the reserved token back
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
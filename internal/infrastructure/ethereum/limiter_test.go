@@ -0,0 +1,92 @@
+package ethereum
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Rate(t *testing.T) {
+	limiter := NewRateLimiter(20, 0)
+	ctx := context.Background()
+
+	// The first second's burst passes immediately, the next five wait for refills
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		release, err := limiter.Acquire(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected requests beyond the burst to be throttled, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_Concurrency(t *testing.T) {
+	limiter := NewRateLimiter(0, 2)
+	ctx := context.Background()
+
+	var inflight, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := limiter.Acquire(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+
+			n := atomic.AddInt32(&inflight, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&inflight, -1)
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", peak)
+	}
+}
+
+func TestRateLimiter_Cancel(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// The slot is taken, so a second request waits until its context ends
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(ctx); err == nil {
+		t.Error("expected an error once the context is done")
+	}
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	if limiter := NewRateLimiter(0, 0); limiter != nil {
+		t.Fatalf("expected nil limiter, got %+v", limiter)
+	}
+
+	var limiter *RateLimiter
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
@@ -89,6 +89,50 @@ func (f *MetadataFetcher) FetchMetadata(ctx context.Context, tokenAddress string
 	}, nil
 }
 
+// VerifyERC20 checks that tokenAddress answers the ERC-20 symbol() and decimals() calls, returning
+// an error instead of the fallbacks FetchMetadata uses. Contracts that do not implement them, and
+// addresses without code, fail.
+func (f *MetadataFetcher) VerifyERC20(ctx context.Context, tokenAddress string) (*TokenMetadata, error) {
+	addr := common.HexToAddress(tokenAddress)
+
+	symbol, err := f.fetchSymbol(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("symbol() failed: %w", err)
+	}
+	decimals, err := f.fetchDecimals(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("decimals() failed: %w", err)
+	}
+
+	return &TokenMetadata{Symbol: symbol, Decimals: decimals}, nil
+}
+
+// ReadMetadata reads a token's current name, symbol and decimals along with the chain head block
+// read just before them. Unlike FetchMetadata, a failed call is returned as an error rather than
+// replaced by a fallback, so the result can be compared with stored metadata.
+func (f *MetadataFetcher) ReadMetadata(ctx context.Context, tokenAddress string) (*TokenMetadata, uint64, error) {
+	addr := common.HexToAddress(tokenAddress)
+
+	block, err := f.client.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	name, err := f.fetchName(ctx, addr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("name() failed: %w", err)
+	}
+	symbol, err := f.fetchSymbol(ctx, addr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("symbol() failed: %w", err)
+	}
+	decimals, err := f.fetchDecimals(ctx, addr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decimals() failed: %w", err)
+	}
+
+	return &TokenMetadata{Name: name, Symbol: symbol, Decimals: decimals}, block, nil
+}
+
 // fetchName fetches token name via eth_call
 func (f *MetadataFetcher) fetchName(ctx context.Context, addr common.Address) (string, error) {
 	result, err := f.client.CallContract(ctx, addr, nameSig)
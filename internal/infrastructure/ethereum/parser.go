@@ -1,9 +1,9 @@
 package ethereum
 
 import (
+	"encoding/hex"
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -17,64 +17,78 @@ var TransferEventSignature = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378da
 
 // ParseTransferEvent parses a raw log into a Transfer entity
 func ParseTransferEvent(log types.Log, blockTimestamp time.Time) (*entities.Transfer, error) {
+	var transfer entities.Transfer
+	if err := parseTransferEvent(&log, blockTimestamp, &transfer); err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// parseTransferEvent parses a raw log into dst, so batches can be decoded in place
+func parseTransferEvent(log *types.Log, blockTimestamp time.Time, dst *entities.Transfer) error {
 	// Validate log has correct topic structure
 	if len(log.Topics) != 3 {
-		return nil, fmt.Errorf("invalid number of topics: expected 3, got %d", len(log.Topics))
+		return fmt.Errorf("invalid number of topics: expected 3, got %d", len(log.Topics))
 	}
 
 	// Verify this is a Transfer event
 	if log.Topics[0] != TransferEventSignature {
-		return nil, fmt.Errorf("not a Transfer event")
+		return fmt.Errorf("not a Transfer event")
 	}
 
-	// Parse addresses from topics (indexed parameters)
-	// Topics[1] = from address (padded to 32 bytes)
-	// Topics[2] = to address (padded to 32 bytes)
-	fromAddress := common.BytesToAddress(log.Topics[1].Bytes())
-	toAddress := common.BytesToAddress(log.Topics[2].Bytes())
-
 	// Parse value from data (non-indexed parameter)
 	if len(log.Data) != 32 {
-		return nil, fmt.Errorf("invalid data length: expected 32, got %d", len(log.Data))
+		return fmt.Errorf("invalid data length: expected 32, got %d", len(log.Data))
 	}
 	value := new(big.Int).SetBytes(log.Data)
 
-	return &entities.Transfer{
-		TxHash:         log.TxHash.Hex(),
+	// Addresses are stored lowercase, so they are hex-encoded directly instead of via
+	// Address.Hex, whose EIP-55 checksum costs a keccak256 per address.
+	// Topics[1] = from address, Topics[2] = to address (left-padded to 32 bytes)
+	*dst = entities.Transfer{
+		TxHash:         lowerHex(log.TxHash[:]),
 		LogIndex:       int(log.Index),
 		BlockNumber:    int64(log.BlockNumber),
 		BlockTimestamp: blockTimestamp,
-		TokenAddress:   strings.ToLower(log.Address.Hex()),
-		FromAddress:    strings.ToLower(fromAddress.Hex()),
-		ToAddress:      strings.ToLower(toAddress.Hex()),
+		TokenAddress:   lowerHex(log.Address[:]),
+		FromAddress:    lowerHex(log.Topics[1][common.HashLength-common.AddressLength:]),
+		ToAddress:      lowerHex(log.Topics[2][common.HashLength-common.AddressLength:]),
 		Value:          value,
 		ValueString:    value.String(),
-	}, nil
+	}
+	return nil
+}
+
+// lowerHex returns b (at most a hash long) as a 0x-prefixed lowercase hex string
+func lowerHex(b []byte) string {
+	var buf [2 + 2*common.HashLength]byte
+	copy(buf[:], "0x")
+	n := hex.Encode(buf[2:], b)
+	return string(buf[:2+n])
 }
 
 // ParseTransferLogs parses multiple logs into Transfer entities
 // Returns parsed transfers and a list of failed log indices
 func ParseTransferLogs(logs []types.Log, blockTimestamps map[uint64]time.Time) ([]entities.Transfer, []int) {
-	transfers := make([]entities.Transfer, 0, len(logs))
-	failedIndices := make([]int, 0)
+	transfers := make([]entities.Transfer, len(logs))
+	var failedIndices []int
 
-	for i, log := range logs {
-		timestamp, ok := blockTimestamps[log.BlockNumber]
+	n := 0
+	for i := range logs {
+		timestamp, ok := blockTimestamps[logs[i].BlockNumber]
 		if !ok {
 			failedIndices = append(failedIndices, i)
 			continue
 		}
 
-		transfer, err := ParseTransferEvent(log, timestamp)
-		if err != nil {
+		if err := parseTransferEvent(&logs[i], timestamp, &transfers[n]); err != nil {
 			failedIndices = append(failedIndices, i)
 			continue
 		}
-
-		transfers = append(transfers, *transfer)
+		n++
 	}
 
-	return transfers, failedIndices
+	return transfers[:n], failedIndices
 }
 
 // IsTransferEvent checks if a log is a Transfer event
@@ -423,6 +423,26 @@ func TestIsTransferEvent_WrongTopicCount(t *testing.T) {
 	}
 }
 
+// BenchmarkParseTransferLogs parses one block range worth of logs; watch allocs/op for regressions
+func BenchmarkParseTransferLogs(b *testing.B) {
+	logs := make([]types.Log, 1000)
+	timestamps := make(map[uint64]time.Time)
+	for i := range logs {
+		blockNumber := uint64(19000000 + i/50)
+		logs[i] = createValidTransferLog(blockNumber, uint(i%50))
+		timestamps[blockNumber] = time.Unix(int64(blockNumber), 0)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		transfers, failed := ParseTransferLogs(logs, timestamps)
+		if len(transfers) != len(logs) || len(failed) != 0 {
+			b.Fatalf("unexpected result: %d transfers, %d failed", len(transfers), len(failed))
+		}
+	}
+}
+
 // Helper functions
 
 func createValidTransferLog(blockNumber uint64, index uint) types.Log {
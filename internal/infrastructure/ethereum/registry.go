@@ -0,0 +1,144 @@
+package ethereum
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TransferEventName is the registry name of the ERC-20 Transfer decoder
+const TransferEventName = "transfer"
+
+// EventType describes how to decode one kind of event log
+type EventType struct {
+	// Name identifies the event type and the repository its records are stored in
+	Name string
+	// Signature is topic0 of the event
+	Signature common.Hash
+	// Addresses restricts the event to specific emitting contracts.
+	// When empty, the event is matched against the addresses passed to the fetch.
+	Addresses []common.Address
+	// Decode turns a raw log into a record for the event's repository
+	Decode func(log types.Log, blockTimestamp time.Time) (any, error)
+}
+
+// EventRegistry maps event signatures to their decoders
+type EventRegistry struct {
+	types []EventType
+	bySig map[common.Hash][]int
+	names map[string]struct{}
+}
+
+// NewEventRegistry creates an empty event registry
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{
+		bySig: make(map[common.Hash][]int),
+		names: make(map[string]struct{}),
+	}
+}
+
+// DefaultEventRegistry creates a registry with the ERC-20 Transfer decoder registered
+func DefaultEventRegistry() *EventRegistry {
+	r := NewEventRegistry()
+	_ = r.Register(EventType{
+		Name:      TransferEventName,
+		Signature: TransferEventSignature,
+		Decode: func(log types.Log, blockTimestamp time.Time) (any, error) {
+			return ParseTransferEvent(log, blockTimestamp)
+		},
+	})
+	return r
+}
+
+// Register adds an event type. Names must be unique; several types may share a signature
+// as long as their address scopes differ.
+func (r *EventRegistry) Register(et EventType) error {
+	if et.Name == "" {
+		return fmt.Errorf("event type name is required")
+	}
+	if et.Decode == nil {
+		return fmt.Errorf("event type %s has no decoder", et.Name)
+	}
+	if _, ok := r.names[et.Name]; ok {
+		return fmt.Errorf("event type %s already registered", et.Name)
+	}
+
+	r.names[et.Name] = struct{}{}
+	r.types = append(r.types, et)
+	r.bySig[et.Signature] = append(r.bySig[et.Signature], len(r.types)-1)
+	return nil
+}
+
+// Topics returns the distinct signatures of all registered events, in registration order
+func (r *EventRegistry) Topics() []common.Hash {
+	return r.topics(func(EventType) bool { return true })
+}
+
+// TokenTopics returns the distinct signatures of events matched against the fetch's target
+// addresses, such as Transfer
+func (r *EventRegistry) TokenTopics() []common.Hash {
+	return r.topics(func(et EventType) bool { return len(et.Addresses) == 0 })
+}
+
+// ScopedTopics returns the distinct signatures of events restricted to their own addresses
+func (r *EventRegistry) ScopedTopics() []common.Hash {
+	return r.topics(func(et EventType) bool { return len(et.Addresses) > 0 })
+}
+
+func (r *EventRegistry) topics(include func(EventType) bool) []common.Hash {
+	topics := make([]common.Hash, 0, len(r.bySig))
+	seen := make(map[common.Hash]struct{}, len(r.bySig))
+	for _, et := range r.types {
+		if !include(et) {
+			continue
+		}
+		if _, ok := seen[et.Signature]; ok {
+			continue
+		}
+		seen[et.Signature] = struct{}{}
+		topics = append(topics, et.Signature)
+	}
+	return topics
+}
+
+// ScopedAddresses returns the distinct addresses scoped by registered events, such as DEX pools.
+// They are fetched once per block range rather than with every token's logs.
+func (r *EventRegistry) ScopedAddresses() []common.Address {
+	seen := make(map[common.Address]struct{})
+	var addresses []common.Address
+	for _, et := range r.types {
+		for _, a := range et.Addresses {
+			if _, ok := seen[a]; !ok {
+				seen[a] = struct{}{}
+				addresses = append(addresses, a)
+			}
+		}
+	}
+	return addresses
+}
+
+// Match returns the event type that handles a log, given the fetch's target addresses
+func (r *EventRegistry) Match(log types.Log, targets map[common.Address]struct{}) (EventType, bool) {
+	if len(log.Topics) == 0 {
+		return EventType{}, false
+	}
+
+	for _, i := range r.bySig[log.Topics[0]] {
+		et := r.types[i]
+		if len(et.Addresses) == 0 {
+			if _, ok := targets[log.Address]; ok {
+				return et, true
+			}
+			continue
+		}
+		for _, a := range et.Addresses {
+			if a == log.Address {
+				return et, true
+			}
+		}
+	}
+
+	return EventType{}, false
+}
@@ -0,0 +1,98 @@
+package ethereum
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestDefaultEventRegistry_Topics(t *testing.T) {
+	r := DefaultEventRegistry()
+
+	topics := r.Topics()
+	if len(topics) != 1 || topics[0] != TransferEventSignature {
+		t.Errorf("expected only the Transfer signature, got %v", topics)
+	}
+}
+
+func TestEventRegistry_RegisterDuplicateName(t *testing.T) {
+	r := DefaultEventRegistry()
+
+	err := r.Register(EventType{
+		Name:      TransferEventName,
+		Signature: common.HexToHash("0x01"),
+		Decode:    func(types.Log, time.Time) (any, error) { return nil, nil },
+	})
+	if err == nil {
+		t.Error("expected error for duplicate event name")
+	}
+}
+
+func TestEventRegistry_RegisterWithoutDecoder(t *testing.T) {
+	r := NewEventRegistry()
+
+	if err := r.Register(EventType{Name: "swap", Signature: common.HexToHash("0x01")}); err == nil {
+		t.Error("expected error for missing decoder")
+	}
+}
+
+func TestEventRegistry_Match(t *testing.T) {
+	token := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	pool := common.HexToAddress("0x0d4a11d5EEaaC28EC3F61d100daF4d40471f1852")
+	swapSig := common.HexToHash("0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822")
+
+	r := DefaultEventRegistry()
+	if err := r.Register(EventType{
+		Name:      "swap",
+		Signature: swapSig,
+		Addresses: []common.Address{pool},
+		Decode:    func(types.Log, time.Time) (any, error) { return "swap", nil },
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	targets := map[common.Address]struct{}{token: {}}
+
+	tests := []struct {
+		name     string
+		log      types.Log
+		expected string
+	}{
+		{"transfer from target", types.Log{Address: token, Topics: []common.Hash{TransferEventSignature}}, TransferEventName},
+		{"transfer from pool", types.Log{Address: pool, Topics: []common.Hash{TransferEventSignature}}, ""},
+		{"swap from pool", types.Log{Address: pool, Topics: []common.Hash{swapSig}}, "swap"},
+		{"swap from target", types.Log{Address: token, Topics: []common.Hash{swapSig}}, ""},
+		{"no topics", types.Log{Address: token}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			et, ok := r.Match(tt.log, targets)
+			if tt.expected == "" {
+				if ok {
+					t.Errorf("expected no match, got %s", et.Name)
+				}
+				return
+			}
+			if !ok || et.Name != tt.expected {
+				t.Errorf("expected %s, got %s (matched=%v)", tt.expected, et.Name, ok)
+			}
+		})
+	}
+
+	addresses := r.ScopedAddresses()
+	if len(addresses) != 1 || addresses[0] != pool {
+		t.Errorf("expected [pool], got %v", addresses)
+	}
+	if len(r.Topics()) != 2 {
+		t.Errorf("expected 2 topics, got %d", len(r.Topics()))
+	}
+	if topics := r.TokenTopics(); len(topics) != 1 || topics[0] != TransferEventSignature {
+		t.Errorf("expected only the Transfer topic for tokens, got %v", topics)
+	}
+	if topics := r.ScopedTopics(); len(topics) != 1 || topics[0] != swapSig {
+		t.Errorf("expected only the swap topic for scoped addresses, got %v", topics)
+	}
+}
@@ -0,0 +1,240 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// Trace methods supported for native ETH transfer capture
+const (
+	// TraceMethodDebug calls debug_traceBlockByNumber with the built-in callTracer (Geth, Erigon, Reth)
+	TraceMethodDebug = "debug"
+	// TraceMethodTrace calls the Parity-style trace_block (Erigon, Nethermind, Reth)
+	TraceMethodTrace = "trace"
+)
+
+// callFrame is one call of a callTracer result; Calls holds its subcalls in execution order
+type callFrame struct {
+	Type  string       `json:"type"`
+	From  string       `json:"from"`
+	To    string       `json:"to"`
+	Value *hexutil.Big `json:"value"`
+	Error string       `json:"error"`
+	Calls []callFrame  `json:"calls"`
+}
+
+// txCallTrace is one transaction's entry in a debug_traceBlockByNumber result
+type txCallTrace struct {
+	TxHash string    `json:"txHash"`
+	Result callFrame `json:"result"`
+	Error  string    `json:"error"`
+}
+
+// parityTrace is one entry of a trace_block result. Calls, creates and self-destructs
+// use different action fields.
+type parityTrace struct {
+	Type   string `json:"type"`
+	Action struct {
+		CallType      string       `json:"callType"`
+		From          string       `json:"from"`
+		To            string       `json:"to"`
+		Value         *hexutil.Big `json:"value"`
+		Address       string       `json:"address"`
+		RefundAddress string       `json:"refundAddress"`
+		Balance       *hexutil.Big `json:"balance"`
+	} `json:"action"`
+	Result *struct {
+		Address string `json:"address"`
+	} `json:"result"`
+	Error           string `json:"error"`
+	TraceAddress    []int  `json:"traceAddress"`
+	TransactionHash string `json:"transactionHash"`
+}
+
+// TraceBlock returns the native ETH transfers of a block, read from its traces with the given
+// method. Block timestamps are left for the caller to fill in.
+func (c *Client) TraceBlock(ctx context.Context, blockNumber int64, method string) ([]entities.EthTransfer, error) {
+	var rpcMethod string
+	var args []interface{}
+	switch method {
+	case TraceMethodDebug:
+		rpcMethod = "debug_traceBlockByNumber"
+		args = []interface{}{hexutil.EncodeUint64(uint64(blockNumber)), map[string]string{"tracer": "callTracer"}}
+	case TraceMethodTrace:
+		rpcMethod = "trace_block"
+		args = []interface{}{hexutil.EncodeUint64(uint64(blockNumber))}
+	default:
+		return nil, fmt.Errorf("unknown trace method %q (available: %s, %s)", method, TraceMethodDebug, TraceMethodTrace)
+	}
+
+	var raw json.RawMessage
+	var err error
+
+	for i := 0; i <= c.config.MaxRetries; i++ {
+		err = c.call(ctx, func() error {
+			return c.client.Client().CallContext(ctx, &raw, rpcMethod, args...)
+		})
+		if err == nil {
+			break
+		}
+
+		c.logger.Warn("Failed to trace block, retrying",
+			zap.Int64("block_number", blockNumber),
+			zap.Int("attempt", i+1),
+			zap.Error(err),
+		)
+
+		if i < c.config.MaxRetries {
+			time.Sleep(c.config.RetryDelay)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to trace block %d after %d retries: %w", blockNumber, c.config.MaxRetries, err)
+	}
+
+	if method == TraceMethodDebug {
+		return parseCallTracerResult(raw, blockNumber)
+	}
+	return parseTraceBlockResult(raw, blockNumber)
+}
+
+// parseCallTracerResult extracts ETH transfers from a debug_traceBlockByNumber callTracer result.
+// Calls that failed are skipped together with their subcalls, since everything they did was reverted.
+func parseCallTracerResult(raw []byte, blockNumber int64) ([]entities.EthTransfer, error) {
+	var traces []txCallTrace
+	if err := json.Unmarshal(raw, &traces); err != nil {
+		return nil, fmt.Errorf("failed to decode block trace: %w", err)
+	}
+
+	var transfers []entities.EthTransfer
+	for _, trace := range traces {
+		if trace.Error != "" {
+			return nil, fmt.Errorf("failed to trace transaction %s: %s", trace.TxHash, trace.Error)
+		}
+		if trace.TxHash == "" {
+			return nil, fmt.Errorf("block trace has no transaction hashes; the node's callTracer is too old")
+		}
+		transfers = appendFrameTransfers(transfers, &trace.Result, nil, strings.ToLower(trace.TxHash), blockNumber)
+	}
+
+	return transfers, nil
+}
+
+// appendFrameTransfers appends the transfer made by frame, if any, followed by those of its subcalls
+func appendFrameTransfers(transfers []entities.EthTransfer, frame *callFrame, path []int, txHash string, blockNumber int64) []entities.EthTransfer {
+	if frame.Error != "" {
+		return transfers
+	}
+
+	var callType string
+	switch strings.ToUpper(frame.Type) {
+	case "CALL":
+		callType = entities.EthCallTypeCall
+	case "CREATE", "CREATE2":
+		callType = entities.EthCallTypeCreate
+	case "SELFDESTRUCT":
+		callType = entities.EthCallTypeSelfDestruct
+	}
+
+	// DELEGATECALL, STATICCALL and CALLCODE never move ETH to another account
+	if callType != "" && frame.Value != nil && frame.Value.ToInt().Sign() > 0 {
+		transfers = append(transfers, newEthTransfer(txHash, path, blockNumber, frame.From, frame.To, frame.Value.ToInt(), callType))
+	}
+
+	for i := range frame.Calls {
+		transfers = appendFrameTransfers(transfers, &frame.Calls[i], append(path[:len(path):len(path)], i), txHash, blockNumber)
+	}
+	return transfers
+}
+
+// parseTraceBlockResult extracts ETH transfers from a trace_block result.
+// Parent traces precede their subtraces, so a failed call's subtree is skipped by its trace address prefix.
+func parseTraceBlockResult(raw []byte, blockNumber int64) ([]entities.EthTransfer, error) {
+	var traces []parityTrace
+	if err := json.Unmarshal(raw, &traces); err != nil {
+		return nil, fmt.Errorf("failed to decode block trace: %w", err)
+	}
+
+	var transfers []entities.EthTransfer
+	failed := make(map[string]struct{})
+	for _, trace := range traces {
+		// Block and uncle rewards have no transaction
+		if trace.TransactionHash == "" {
+			continue
+		}
+
+		txHash := strings.ToLower(trace.TransactionHash)
+		if trace.Error != "" || hasFailedAncestor(failed, txHash, trace.TraceAddress) {
+			failed[txHash+":"+formatTraceAddress(trace.TraceAddress)] = struct{}{}
+			continue
+		}
+
+		var from, to, callType string
+		var value *hexutil.Big
+		switch trace.Type {
+		case "call":
+			if trace.Action.CallType != "call" {
+				continue
+			}
+			from, to, value, callType = trace.Action.From, trace.Action.To, trace.Action.Value, entities.EthCallTypeCall
+		case "create":
+			if trace.Result == nil {
+				continue
+			}
+			from, to, value, callType = trace.Action.From, trace.Result.Address, trace.Action.Value, entities.EthCallTypeCreate
+		case "suicide":
+			from, to, value, callType = trace.Action.Address, trace.Action.RefundAddress, trace.Action.Balance, entities.EthCallTypeSelfDestruct
+		default:
+			continue
+		}
+
+		if value == nil || value.ToInt().Sign() <= 0 {
+			continue
+		}
+		transfers = append(transfers, newEthTransfer(txHash, trace.TraceAddress, blockNumber, from, to, value.ToInt(), callType))
+	}
+
+	return transfers, nil
+}
+
+// hasFailedAncestor reports whether any call above path in the transaction's call tree failed
+func hasFailedAncestor(failed map[string]struct{}, txHash string, path []int) bool {
+	for i := 0; i < len(path); i++ {
+		if _, ok := failed[txHash+":"+formatTraceAddress(path[:i])]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func newEthTransfer(txHash string, path []int, blockNumber int64, from, to string, value *big.Int, callType string) entities.EthTransfer {
+	return entities.EthTransfer{
+		TxHash:       txHash,
+		TraceAddress: formatTraceAddress(path),
+		BlockNumber:  blockNumber,
+		FromAddress:  strings.ToLower(from),
+		ToAddress:    strings.ToLower(to),
+		Value:        value,
+		ValueString:  value.String(),
+		CallType:     callType,
+	}
+}
+
+// formatTraceAddress renders a call tree path as dash-separated indexes, e.g. [0 2] as "0-2"
+func formatTraceAddress(path []int) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = strconv.Itoa(p)
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,147 @@
+package ethereum
+
+import (
+	"testing"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+const (
+	traceTxA = "0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	traceTxB = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func TestParseCallTracerResult(t *testing.T) {
+	// Tx A: EOA sends 1 ETH to a contract, which forwards 0.4 ETH, delegatecalls a library,
+	// and makes a reverted call whose subcall transfer is reverted with it.
+	// Tx B: plain call without value.
+	raw := []byte(`[
+		{"txHash": "` + traceTxA + `", "result": {
+			"type": "CALL", "from": "0x1111111111111111111111111111111111111111",
+			"to": "0x2222222222222222222222222222222222222222", "value": "0xde0b6b3a7640000",
+			"calls": [
+				{"type": "CALL", "from": "0x2222222222222222222222222222222222222222",
+				 "to": "0x3333333333333333333333333333333333333333", "value": "0x58d15e176280000"},
+				{"type": "DELEGATECALL", "from": "0x2222222222222222222222222222222222222222",
+				 "to": "0x4444444444444444444444444444444444444444", "value": "0x1",
+				 "calls": [
+					{"type": "STATICCALL", "from": "0x2222222222222222222222222222222222222222",
+					 "to": "0x5555555555555555555555555555555555555555"}
+				 ]},
+				{"type": "CALL", "from": "0x2222222222222222222222222222222222222222",
+				 "to": "0x6666666666666666666666666666666666666666", "value": "0x5", "error": "execution reverted",
+				 "calls": [
+					{"type": "CALL", "from": "0x6666666666666666666666666666666666666666",
+					 "to": "0x7777777777777777777777777777777777777777", "value": "0x5"}
+				 ]}
+			]}},
+		{"txHash": "` + traceTxB + `", "result": {
+			"type": "CALL", "from": "0x1111111111111111111111111111111111111111",
+			"to": "0x2222222222222222222222222222222222222222", "value": "0x0"}}
+	]`)
+
+	transfers, err := parseCallTracerResult(raw, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []entities.EthTransfer{
+		{TraceAddress: "", FromAddress: "0x1111111111111111111111111111111111111111", ToAddress: "0x2222222222222222222222222222222222222222", ValueString: "1000000000000000000"},
+		{TraceAddress: "0", FromAddress: "0x2222222222222222222222222222222222222222", ToAddress: "0x3333333333333333333333333333333333333333", ValueString: "400000000000000000"},
+	}
+	if len(transfers) != len(want) {
+		t.Fatalf("expected %d transfers, got %d: %+v", len(want), len(transfers), transfers)
+	}
+	for i, w := range want {
+		got := transfers[i]
+		if got.TraceAddress != w.TraceAddress || got.FromAddress != w.FromAddress || got.ToAddress != w.ToAddress || got.ValueString != w.ValueString {
+			t.Errorf("transfer %d: expected %+v, got %+v", i, w, got)
+		}
+		if got.TxHash != "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa" {
+			t.Errorf("transfer %d: expected lowercase tx hash, got %s", i, got.TxHash)
+		}
+		if got.BlockNumber != 100 || got.CallType != entities.EthCallTypeCall {
+			t.Errorf("transfer %d: unexpected block %d or call type %s", i, got.BlockNumber, got.CallType)
+		}
+	}
+	if transfers[0].IsInternal() || !transfers[1].IsInternal() {
+		t.Error("expected only the subcall to be internal")
+	}
+}
+
+func TestParseCallTracerResult_MissingTxHash(t *testing.T) {
+	raw := []byte(`[{"result": {"type": "CALL", "from": "0x1111111111111111111111111111111111111111", "to": "0x2222222222222222222222222222222222222222", "value": "0x1"}}]`)
+
+	if _, err := parseCallTracerResult(raw, 100); err == nil {
+		t.Error("expected error for traces without transaction hashes")
+	}
+}
+
+func TestParseTraceBlockResult(t *testing.T) {
+	raw := []byte(`[
+		{"type": "call", "action": {"callType": "call", "from": "0x1111111111111111111111111111111111111111",
+		 "to": "0x2222222222222222222222222222222222222222", "value": "0x10"},
+		 "traceAddress": [], "transactionHash": "` + traceTxA + `"},
+		{"type": "create", "action": {"from": "0x2222222222222222222222222222222222222222", "value": "0x3"},
+		 "result": {"address": "0x8888888888888888888888888888888888888888"},
+		 "traceAddress": [0], "transactionHash": "` + traceTxA + `"},
+		{"type": "call", "action": {"callType": "call", "from": "0x2222222222222222222222222222222222222222",
+		 "to": "0x6666666666666666666666666666666666666666", "value": "0x5"},
+		 "error": "Reverted", "traceAddress": [1], "transactionHash": "` + traceTxA + `"},
+		{"type": "call", "action": {"callType": "call", "from": "0x6666666666666666666666666666666666666666",
+		 "to": "0x7777777777777777777777777777777777777777", "value": "0x5"},
+		 "traceAddress": [1, 0], "transactionHash": "` + traceTxA + `"},
+		{"type": "call", "action": {"callType": "delegatecall", "from": "0x2222222222222222222222222222222222222222",
+		 "to": "0x4444444444444444444444444444444444444444", "value": "0x10"},
+		 "traceAddress": [2], "transactionHash": "` + traceTxA + `"},
+		{"type": "suicide", "action": {"address": "0x8888888888888888888888888888888888888888",
+		 "refundAddress": "0x1111111111111111111111111111111111111111", "balance": "0x2"},
+		 "traceAddress": [3, 0], "transactionHash": "` + traceTxA + `"},
+		{"type": "reward", "action": {"author": "0x9999999999999999999999999999999999999999", "value": "0x1bc16d674ec80000"},
+		 "traceAddress": []}
+	]`)
+
+	transfers, err := parseTraceBlockResult(raw, 200)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		traceAddress string
+		to           string
+		value        string
+		callType     string
+	}{
+		{"", "0x2222222222222222222222222222222222222222", "16", entities.EthCallTypeCall},
+		{"0", "0x8888888888888888888888888888888888888888", "3", entities.EthCallTypeCreate},
+		{"3-0", "0x1111111111111111111111111111111111111111", "2", entities.EthCallTypeSelfDestruct},
+	}
+	if len(transfers) != len(want) {
+		t.Fatalf("expected %d transfers, got %d: %+v", len(want), len(transfers), transfers)
+	}
+	for i, w := range want {
+		got := transfers[i]
+		if got.TraceAddress != w.traceAddress || got.ToAddress != w.to || got.ValueString != w.value || got.CallType != w.callType {
+			t.Errorf("transfer %d: expected %+v, got %+v", i, w, got)
+		}
+		if got.BlockNumber != 200 {
+			t.Errorf("transfer %d: expected block 200, got %d", i, got.BlockNumber)
+		}
+	}
+}
+
+func TestFormatTraceAddress(t *testing.T) {
+	tests := []struct {
+		path []int
+		want string
+	}{
+		{nil, ""},
+		{[]int{0}, "0"},
+		{[]int{1, 0, 12}, "1-0-12"},
+	}
+	for _, tt := range tests {
+		if got := formatTraceAddress(tt.path); got != tt.want {
+			t.Errorf("formatTraceAddress(%v) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
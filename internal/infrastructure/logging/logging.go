@@ -0,0 +1,123 @@
+// Package logging builds the zap logger from LOG_* settings: output encoding, sampling of
+// repetitive debug lines and per-component levels.
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+// Log encodings
+const (
+	FormatJSON    = "json"    // One JSON object per line, for log shippers
+	FormatConsole = "console" // Human readable, for development
+)
+
+// New builds a logger writing to stdout. Debug entries with the same message beyond
+// cfg.DebugSampleInitial per second are sampled, keeping every cfg.DebugSampleThereafter-th;
+// other levels are never sampled. Loggers named after a component in cfg.Levels, and their
+// children, log at that component's level instead of cfg.Level.
+func New(cfg config.LogConfig) (*zap.Logger, error) {
+	level, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+	components, err := ParseLevels(cfg.Levels)
+	if err != nil {
+		return nil, err
+	}
+
+	var encoder zapcore.Encoder
+	switch strings.ToLower(cfg.Format) {
+	case FormatJSON, "":
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	case FormatConsole:
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q (available: json, console)", cfg.Format)
+	}
+
+	// The core admits the lowest level any component logs at; componentCore filters by name
+	lowest := level
+	for _, l := range components {
+		lowest = min(lowest, l)
+	}
+	out := zapcore.Lock(os.Stdout)
+	debug := zapcore.NewCore(encoder, out, zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l == zapcore.DebugLevel && l >= lowest
+	}))
+	if cfg.DebugSampleInitial > 0 {
+		debug = zapcore.NewSamplerWithOptions(debug, time.Second, cfg.DebugSampleInitial, cfg.DebugSampleThereafter)
+	}
+	rest := zapcore.NewCore(encoder, out, zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l > zapcore.DebugLevel && l >= lowest
+	}))
+
+	core := &componentCore{Core: zapcore.NewTee(debug, rest), level: level, components: components}
+	return zap.New(core), nil
+}
+
+// ParseLevels parses per-component levels such as "ethereum=debug,database=warn"
+func ParseLevels(s string) (map[string]zapcore.Level, error) {
+	levels := make(map[string]zapcore.Level)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid LOG_LEVELS entry %q (want component=level)", pair)
+		}
+		level, err := zapcore.ParseLevel(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVELS entry %q: %w", pair, err)
+		}
+		levels[strings.TrimSpace(name)] = level
+	}
+	return levels, nil
+}
+
+// componentCore applies the level of the component an entry's logger is named after, or the
+// default level for loggers of no configured component
+type componentCore struct {
+	zapcore.Core
+	level      zapcore.Level
+	components map[string]zapcore.Level
+}
+
+func (c *componentCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentCore{Core: c.Core.With(fields), level: c.level, components: c.components}
+}
+
+func (c *componentCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < c.levelOf(entry.LoggerName) {
+		return ce
+	}
+	return c.Core.Check(entry, ce)
+}
+
+// levelOf returns the level of the most specific configured component a logger name falls
+// under: "ethereum.fetcher" takes the level of "ethereum" unless it has its own
+func (c *componentCore) levelOf(name string) zapcore.Level {
+	for name != "" {
+		if level, ok := c.components[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return c.level
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels(" ethereum=debug, database=WARN,,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 2 || levels["ethereum"] != zapcore.DebugLevel || levels["database"] != zapcore.WarnLevel {
+		t.Fatalf("unexpected levels %v", levels)
+	}
+
+	for _, s := range []string{"ethereum", "=debug", "ethereum=loud"} {
+		if _, err := ParseLevels(s); err == nil {
+			t.Errorf("expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestComponentLevels(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(&componentCore{
+		Core:       observed,
+		level:      zapcore.InfoLevel,
+		components: map[string]zapcore.Level{"ethereum": zapcore.DebugLevel, "database": zapcore.WarnLevel},
+	})
+
+	logger.Debug("default debug")
+	logger.Info("default info")
+	logger.Named("ethereum").Named("fetcher").Debug("ethereum debug")
+	logger.Named("database").Info("database info")
+	logger.Named("database").With(zap.String("k", "v")).Warn("database warn")
+
+	var got []string
+	for _, entry := range logs.All() {
+		got = append(got, entry.Message)
+	}
+	want := []string{"default info", "ethereum debug", "database warn"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	for _, cfg := range []config.LogConfig{
+		{Level: "loud", Format: FormatJSON},
+		{Level: "info", Format: "xml"},
+		{Level: "info", Format: FormatJSON, Levels: "ethereum"},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("expected %+v to be rejected", cfg)
+		}
+	}
+	if _, err := New(config.LogConfig{Level: "info", Format: FormatConsole, Levels: "ethereum=debug", DebugSampleInitial: 10}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// EmailDriver sends alerts as plain-text email over SMTP
+type EmailDriver struct {
+	addr     string
+	auth     smtp.Auth
+	from     string
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailDriver creates an email driver. Authentication is skipped when username is empty.
+func NewEmailDriver(host string, port int, username, password, from string) *EmailDriver {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &EmailDriver{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		auth:     auth,
+		from:     from,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Name returns the channel name of the email driver
+func (d *EmailDriver) Name() string {
+	return DriverEmail
+}
+
+// Validate requires a single plain email address
+func (d *EmailDriver) Validate(target string) error {
+	addr, err := mail.ParseAddress(target)
+	if err != nil || addr.Address != target {
+		return fmt.Errorf("email target must be an email address")
+	}
+	return nil
+}
+
+// Send emails a summary of the alerts to target
+func (d *EmailDriver) Send(ctx context.Context, target string, alerts []Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := d.sendMail(d.addr, d.auth, d.from, []string{target}, d.message(target, alerts)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+func (d *EmailDriver) message(target string, alerts []Alert) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "From: %s\r\n", d.from)
+	fmt.Fprintf(&b, "To: %s\r\n", target)
+	fmt.Fprintf(&b, "Subject: [chain-indexer] %s: %d matching transfer(s)\r\n", alerts[0].RuleName, len(alerts))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+
+	fmt.Fprintf(&b, "Alert rule %q (#%d) matched %d transfer(s):\r\n\r\n", alerts[0].RuleName, alerts[0].RuleID, len(alerts))
+	for _, a := range alerts {
+		fmt.Fprintf(&b, "%s  block %d  tx %s:%d\r\n", a.BlockTimestamp, a.BlockNumber, a.TxHash, a.LogIndex)
+		fmt.Fprintf(&b, "  token %s\r\n", a.TokenAddress)
+		fmt.Fprintf(&b, "  %s -> %s  value %s\r\n\r\n", a.FromAddress, a.ToAddress, a.Value)
+	}
+
+	return []byte(b.String())
+}
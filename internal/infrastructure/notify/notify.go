@@ -0,0 +1,104 @@
+// Package notify delivers alert notifications through pluggable drivers.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+// Built-in driver names, used as an alert rule's channel
+const (
+	DriverWebhook = "webhook"
+	DriverEmail   = "email"
+)
+
+// ErrUnknownDriver is returned when no driver is registered for a channel
+var ErrUnknownDriver = errors.New("unknown notification driver")
+
+// Alert is one transfer that matched an alert rule
+type Alert struct {
+	RuleID         int64  `json:"rule_id"`
+	RuleName       string `json:"rule_name"`
+	TxHash         string `json:"tx_hash"`
+	LogIndex       int    `json:"log_index"`
+	BlockNumber    int64  `json:"block_number"`
+	BlockTimestamp string `json:"block_timestamp"`
+	TokenAddress   string `json:"token_address"`
+	FromAddress    string `json:"from_address"`
+	ToAddress      string `json:"to_address"`
+	Value          string `json:"value"`
+}
+
+// Driver sends alerts to a destination
+type Driver interface {
+	// Name returns the channel name rules use to select the driver
+	Name() string
+
+	// Validate reports whether target is a usable destination for this driver
+	Validate(target string) error
+
+	// Send delivers a batch of alerts, all for the same rule, to target
+	Send(ctx context.Context, target string, alerts []Alert) error
+}
+
+// Registry holds the available notification drivers by name
+type Registry struct {
+	mu      sync.RWMutex
+	drivers map[string]Driver
+}
+
+// NewRegistry creates a registry with the given drivers
+func NewRegistry(drivers ...Driver) *Registry {
+	r := &Registry{drivers: make(map[string]Driver)}
+	for _, d := range drivers {
+		r.Register(d)
+	}
+	return r
+}
+
+// NewRegistryFromConfig creates a registry with the webhook driver and,
+// when an SMTP host is configured, the email driver
+func NewRegistryFromConfig(cfg config.AlertConfig) *Registry {
+	r := NewRegistry(NewWebhookDriver(cfg.WebhookTimeout))
+	if cfg.SMTPHost != "" {
+		r.Register(NewEmailDriver(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom))
+	}
+	return r
+}
+
+// Register adds a driver, replacing any driver with the same name
+func (r *Registry) Register(d Driver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.drivers[d.Name()] = d
+}
+
+// Get returns the driver registered for a channel
+func (r *Registry) Get(name string) (Driver, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.drivers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDriver, name)
+	}
+	return d, nil
+}
+
+// Names returns the registered driver names, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.drivers))
+	for name := range r.drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
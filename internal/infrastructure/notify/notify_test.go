@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/config"
+)
+
+var testAlerts = []Alert{{
+	RuleID:         7,
+	RuleName:       "whale",
+	TxHash:         "0xabc",
+	BlockNumber:    19000000,
+	BlockTimestamp: "2024-01-15T10:30:00Z",
+	TokenAddress:   "0xdac17f958d2ee523a2206206994597c13d831ec7",
+	FromAddress:    "0x1111111111111111111111111111111111111111",
+	ToAddress:      "0x2222222222222222222222222222222222222222",
+	Value:          "1000000000000",
+}}
+
+func TestWebhookDriver_Send(t *testing.T) {
+	t.Run("posts alerts as JSON", func(t *testing.T) {
+		var received webhookPayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+			}
+			_ = json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		if err := NewWebhookDriver(time.Second).Send(context.Background(), server.URL, testAlerts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(received.Alerts) != 1 || received.Alerts[0].RuleID != 7 {
+			t.Errorf("unexpected payload: %+v", received)
+		}
+	})
+
+	t.Run("fails on non-2xx status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		if err := NewWebhookDriver(time.Second).Send(context.Background(), server.URL, testAlerts); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestWebhookDriver_Validate(t *testing.T) {
+	d := NewWebhookDriver(time.Second)
+
+	for target, valid := range map[string]bool{
+		"https://example.com/hook": true,
+		"http://localhost:9000":    true,
+		"ftp://example.com":        false,
+		"example.com/hook":         false,
+		"":                         false,
+	} {
+		if err := d.Validate(target); (err == nil) != valid {
+			t.Errorf("Validate(%q): expected valid=%v, got %v", target, valid, err)
+		}
+	}
+}
+
+func TestEmailDriver_Send(t *testing.T) {
+	d := NewEmailDriver("smtp.example.com", 587, "user", "secret", "alerts@example.com")
+
+	var gotAddr string
+	var gotTo []string
+	var gotMsg string
+	d.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+
+	if err := d.Send(context.Background(), "ops@example.com", testAlerts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" || len(gotTo) != 1 || gotTo[0] != "ops@example.com" {
+		t.Errorf("unexpected envelope: %s %v", gotAddr, gotTo)
+	}
+	if !strings.Contains(gotMsg, "Subject: [chain-indexer] whale: 1 matching transfer(s)") || !strings.Contains(gotMsg, "0xabc") {
+		t.Errorf("unexpected message:\n%s", gotMsg)
+	}
+
+	d.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		return errors.New("connection refused")
+	}
+	if err := d.Send(context.Background(), "ops@example.com", testAlerts); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestEmailDriver_Validate(t *testing.T) {
+	d := NewEmailDriver("smtp.example.com", 587, "", "", "alerts@example.com")
+
+	if err := d.Validate("ops@example.com"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := d.Validate("Ops <ops@example.com>"); err == nil {
+		t.Error("expected display-name addresses to be rejected")
+	}
+}
+
+func TestNewRegistryFromConfig(t *testing.T) {
+	r := NewRegistryFromConfig(config.AlertConfig{WebhookTimeout: time.Second})
+	if names := r.Names(); len(names) != 1 || names[0] != DriverWebhook {
+		t.Errorf("expected only the webhook driver, got %v", names)
+	}
+	if _, err := r.Get(DriverEmail); !errors.Is(err, ErrUnknownDriver) {
+		t.Errorf("expected ErrUnknownDriver, got %v", err)
+	}
+
+	r = NewRegistryFromConfig(config.AlertConfig{WebhookTimeout: time.Second, SMTPHost: "smtp.example.com", SMTPPort: 25})
+	if _, err := r.Get(DriverEmail); err != nil {
+		t.Errorf("expected email driver, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WebhookDriver POSTs alerts as JSON to an HTTP endpoint
+type WebhookDriver struct {
+	httpClient *http.Client
+}
+
+// NewWebhookDriver creates a webhook driver with the given request timeout
+func NewWebhookDriver(timeout time.Duration) *WebhookDriver {
+	return &WebhookDriver{
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// webhookPayload is the request body sent to webhook targets
+type webhookPayload struct {
+	Alerts []Alert `json:"alerts"`
+}
+
+// Name returns the channel name of the webhook driver
+func (d *WebhookDriver) Name() string {
+	return DriverWebhook
+}
+
+// Validate requires an absolute http or https URL
+func (d *WebhookDriver) Validate(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook target must be an http or https URL")
+	}
+	return nil
+}
+
+// Send POSTs the alerts to target, treating any non-2xx response as a failure
+func (d *WebhookDriver) Send(ctx context.Context, target string, alerts []Alert) error {
+	body, err := json.Marshal(webhookPayload{Alerts: alerts})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
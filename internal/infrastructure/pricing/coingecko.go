@@ -0,0 +1,116 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CoinGeckoProvider fetches token prices from the CoinGecko API by contract address
+type CoinGeckoProvider struct {
+	baseURL    string
+	apiKey     string
+	platform   string
+	httpClient *http.Client
+}
+
+// NewCoinGeckoProvider creates a CoinGecko price provider for Ethereum mainnet contracts
+func NewCoinGeckoProvider(baseURL, apiKey string) *CoinGeckoProvider {
+	return &CoinGeckoProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		platform:   "ethereum",
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// CurrentPrice returns the latest USD price of a token
+func (p *CoinGeckoProvider) CurrentPrice(ctx context.Context, tokenAddress string) (float64, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	params := url.Values{}
+	params.Set("contract_addresses", tokenAddress)
+	params.Set("vs_currencies", "usd")
+
+	var result map[string]map[string]float64
+	if err := p.get(ctx, "/simple/token_price/"+p.platform, params, &result); err != nil {
+		return 0, err
+	}
+
+	price, ok := result[tokenAddress]["usd"]
+	if !ok {
+		return 0, ErrPriceUnavailable
+	}
+	return price, nil
+}
+
+// HistoricalPrice returns the USD price closest to at, searching a two-hour window around it
+func (p *CoinGeckoProvider) HistoricalPrice(ctx context.Context, tokenAddress string, at time.Time) (float64, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+
+	params := url.Values{}
+	params.Set("vs_currency", "usd")
+	params.Set("from", fmt.Sprintf("%d", at.Add(-time.Hour).Unix()))
+	params.Set("to", fmt.Sprintf("%d", at.Add(time.Hour).Unix()))
+
+	var result struct {
+		Prices [][2]float64 `json:"prices"` // [unix ms, price]
+	}
+	path := fmt.Sprintf("/coins/%s/contract/%s/market_chart/range", p.platform, tokenAddress)
+	if err := p.get(ctx, path, params, &result); err != nil {
+		return 0, err
+	}
+
+	if len(result.Prices) == 0 {
+		return 0, ErrPriceUnavailable
+	}
+
+	target := float64(at.UnixMilli())
+	best := result.Prices[0]
+	for _, point := range result.Prices[1:] {
+		if abs(point[0]-target) < abs(best[0]-target) {
+			best = point
+		}
+	}
+	return best[1], nil
+}
+
+func (p *CoinGeckoProvider) get(ctx context.Context, path string, params url.Values, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("x-cg-pro-api-key", p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query CoinGecko: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrPriceUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("CoinGecko returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("failed to decode CoinGecko response: %w", err)
+	}
+	return nil
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,142 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const usdt = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+
+func TestValueUSD(t *testing.T) {
+	tests := []struct {
+		amount   string
+		price    float64
+		expected string
+		ok       bool
+	}{
+		{"1.5", 2.0, "3.00", true},
+		{"1000", 0.9998, "999.80", true},
+		{"0.000001", 1.0, "0.00", true},
+		{"-2.5", 2.0, "-5.00", true},
+		{"", 1.0, "", false},
+		{"abc", 1.0, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ValueUSD(tt.amount, tt.price)
+		if ok != tt.ok || got != tt.expected {
+			t.Errorf("ValueUSD(%q, %v) = %q, %v; expected %q, %v", tt.amount, tt.price, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestCoinGeckoProvider_CurrentPrice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/simple/token_price/ethereum" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("contract_addresses") != usdt {
+			t.Errorf("expected lowercase contract address, got %s", r.URL.Query().Get("contract_addresses"))
+		}
+		if r.Header.Get("x-cg-pro-api-key") != "secret" {
+			t.Errorf("expected API key header")
+		}
+		_, _ = w.Write([]byte(`{"` + usdt + `":{"usd":0.9995}}`))
+	}))
+	defer server.Close()
+
+	provider := NewCoinGeckoProvider(server.URL+"/", "secret")
+
+	price, err := provider.CurrentPrice(context.Background(), "0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if price != 0.9995 {
+		t.Errorf("expected 0.9995, got %v", price)
+	}
+}
+
+func TestCoinGeckoProvider_CurrentPrice_Unknown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	_, err := NewCoinGeckoProvider(server.URL, "").CurrentPrice(context.Background(), usdt)
+	if !errors.Is(err, ErrPriceUnavailable) {
+		t.Errorf("expected ErrPriceUnavailable, got %v", err)
+	}
+}
+
+func TestCoinGeckoProvider_HistoricalPrice_Closest(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/ethereum/contract/"+usdt+"/market_chart/range" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"prices":[[1705308000000,0.99],[1705312800000,1.01],[1705316400000,1.02]]}`))
+	}))
+	defer server.Close()
+
+	price, err := NewCoinGeckoProvider(server.URL, "").HistoricalPrice(context.Background(), usdt, at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 1705312800000 is 10:00 UTC
+	if price != 1.01 {
+		t.Errorf("expected closest price 1.01, got %v", price)
+	}
+}
+
+func TestCoinGeckoProvider_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := NewCoinGeckoProvider(server.URL, "").CurrentPrice(context.Background(), usdt)
+	if err == nil || errors.Is(err, ErrPriceUnavailable) {
+		t.Errorf("expected rate limit error, got %v", err)
+	}
+}
+
+type staticProvider struct {
+	calls int
+	at    time.Time
+}
+
+func (p *staticProvider) CurrentPrice(ctx context.Context, tokenAddress string) (float64, error) {
+	p.calls++
+	return 1, nil
+}
+
+func (p *staticProvider) HistoricalPrice(ctx context.Context, tokenAddress string, at time.Time) (float64, error) {
+	p.calls++
+	p.at = at
+	return 2, nil
+}
+
+func TestCachedProvider_WithoutCache(t *testing.T) {
+	inner := &staticProvider{}
+	provider := NewCachedProvider(inner, nil, time.Minute, zap.NewNop())
+
+	at := time.Date(2024, 1, 15, 10, 42, 7, 0, time.UTC)
+	price, err := provider.HistoricalPrice(context.Background(), usdt, at)
+	if err != nil || price != 2 {
+		t.Fatalf("expected 2, got %v (%v)", price, err)
+	}
+	if !inner.at.Equal(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected lookup bucketed to the hour, got %v", inner.at)
+	}
+
+	if price, err := provider.CurrentPrice(context.Background(), usdt); err != nil || price != 1 {
+		t.Errorf("expected 1, got %v (%v)", price, err)
+	}
+}
@@ -0,0 +1,107 @@
+// Package pricing provides USD token prices from external oracles.
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+// ErrPriceUnavailable is returned when a provider has no price for a token or time
+var ErrPriceUnavailable = errors.New("price unavailable")
+
+// Provider returns USD prices for one whole token unit
+type Provider interface {
+	// CurrentPrice returns the latest USD price of a token
+	CurrentPrice(ctx context.Context, tokenAddress string) (float64, error)
+
+	// HistoricalPrice returns the USD price of a token closest to the given time
+	HistoricalPrice(ctx context.Context, tokenAddress string, at time.Time) (float64, error)
+}
+
+// CachedProvider wraps a Provider with a Redis price cache.
+// Historical prices are bucketed by hour, so nearby lookups share one oracle call.
+type CachedProvider struct {
+	provider      Provider
+	cache         *cache.RedisCache
+	ttl           time.Duration
+	historicalTTL time.Duration
+	logger        *zap.Logger
+}
+
+// NewCachedProvider creates a caching price provider. A nil cache disables caching.
+func NewCachedProvider(provider Provider, cache *cache.RedisCache, ttl time.Duration, logger *zap.Logger) *CachedProvider {
+	return &CachedProvider{
+		provider:      provider,
+		cache:         cache,
+		ttl:           ttl,
+		historicalTTL: 24 * time.Hour,
+		logger:        logger,
+	}
+}
+
+// CurrentPrice returns the cached current price, fetching it on a miss
+func (p *CachedProvider) CurrentPrice(ctx context.Context, tokenAddress string) (float64, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+	cacheKey := fmt.Sprintf("price:%s:current", tokenAddress)
+
+	return p.cached(ctx, cacheKey, p.ttl, func() (float64, error) {
+		return p.provider.CurrentPrice(ctx, tokenAddress)
+	})
+}
+
+// HistoricalPrice returns the cached price for the hour containing at, fetching it on a miss
+func (p *CachedProvider) HistoricalPrice(ctx context.Context, tokenAddress string, at time.Time) (float64, error) {
+	tokenAddress = strings.ToLower(tokenAddress)
+	hour := at.UTC().Truncate(time.Hour)
+	cacheKey := fmt.Sprintf("price:%s:%d", tokenAddress, hour.Unix())
+
+	return p.cached(ctx, cacheKey, p.historicalTTL, func() (float64, error) {
+		return p.provider.HistoricalPrice(ctx, tokenAddress, hour)
+	})
+}
+
+func (p *CachedProvider) cached(ctx context.Context, cacheKey string, ttl time.Duration, fetch func() (float64, error)) (float64, error) {
+	var price float64
+	if p.cache != nil {
+		if err := p.cache.Get(ctx, cacheKey, &price); err == nil {
+			return price, nil
+		}
+	}
+
+	price, err := fetch()
+	if err != nil {
+		return 0, err
+	}
+
+	if p.cache != nil {
+		if err := p.cache.SetWithTTL(ctx, cacheKey, price, ttl); err != nil {
+			p.logger.Warn("Failed to cache price", zap.String("key", cacheKey), zap.Error(err))
+		}
+	}
+
+	return price, nil
+}
+
+// ValueUSD multiplies a decimal token amount (e.g. "1.5") by a USD price, rounded to cents.
+// Returns false if the amount cannot be parsed.
+func ValueUSD(amount string, price float64) (string, bool) {
+	if amount == "" {
+		return "", false
+	}
+
+	value, ok := new(big.Float).SetPrec(256).SetString(amount)
+	if !ok {
+		return "", false
+	}
+
+	value.Mul(value, new(big.Float).SetPrec(256).SetFloat64(price))
+	return value.Text('f', 2), true
+}
@@ -0,0 +1,87 @@
+package utxo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newNode serves getblockcount, getblockhash and getblockheader for a chain of height blocks
+// mined ten minutes apart
+func newNode(t *testing.T, height int64) *httptest.Server {
+	t.Helper()
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "rpc" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var result any
+		switch req.Method {
+		case "getblockcount":
+			result = height
+		case "getblockhash":
+			number := int64(req.Params[0].(float64))
+			if number > height {
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"result": nil,
+					"error":  map[string]any{"code": -8, "message": "Block height out of range"},
+				})
+				return
+			}
+			result = fmt.Sprintf("hash-%d", number)
+		case "getblockheader":
+			number, _ := strconv.ParseInt(strings.TrimPrefix(req.Params[0].(string), "hash-"), 10, 64)
+			result = map[string]any{"time": 1231006505 + 600*number}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": result, "error": nil})
+	}))
+	t.Cleanup(node.Close)
+	return node
+}
+
+func TestAdapter(t *testing.T) {
+	node := newNode(t, 5)
+	adapter := NewAdapter(strings.Replace(node.URL, "http://", "http://rpc:secret@", 1), 5*time.Second)
+	ctx := context.Background()
+
+	head, err := adapter.GetLatestBlockNumber(ctx)
+	if err != nil || head != 5 {
+		t.Fatalf("expected head 5, got %d (%v)", head, err)
+	}
+
+	at, err := adapter.GetBlockTimestamp(ctx, 3)
+	if err != nil || !at.Equal(time.Unix(1231006505+1800, 0)) {
+		t.Errorf("expected the header time of block 3, got %v (%v)", at, err)
+	}
+
+	if _, err := adapter.GetBlockTimestamp(ctx, 9); err == nil || !strings.Contains(err.Error(), "Block height out of range") {
+		t.Errorf("expected the node's error for a block past the head, got %v", err)
+	}
+
+	result, err := adapter.FetchTransfers(ctx, []string{"bc1qtoken"}, 1, 5)
+	if err != nil || len(result.Transfers) != 0 || result.FromBlock != 1 || result.ToBlock != 5 {
+		t.Errorf("expected an empty result for the range, got %+v (%v)", result, err)
+	}
+
+	unauthorized := NewAdapter(node.URL, 5*time.Second)
+	if _, err := unauthorized.GetLatestBlockNumber(ctx); err == nil {
+		t.Error("expected an error without credentials")
+	}
+}
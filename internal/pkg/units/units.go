@@ -0,0 +1,37 @@
+// Package units converts raw integer token amounts to human-readable decimal strings.
+package units
+
+import "strings"
+
+// Format converts a raw integer amount (e.g. wei) to a decimal string using the token's decimals.
+// Trailing fractional zeros are trimmed and negative amounts keep their sign.
+func Format(raw string, decimals int) string {
+	if raw == "" || raw == "0" {
+		return "0"
+	}
+
+	sign := ""
+	if strings.HasPrefix(raw, "-") {
+		sign = "-"
+		raw = raw[1:]
+	}
+
+	if decimals <= 0 {
+		return sign + raw
+	}
+
+	// Pad with leading zeros if necessary
+	if len(raw) <= decimals {
+		raw = strings.Repeat("0", decimals-len(raw)+1) + raw
+	}
+
+	// Insert decimal point
+	insertPos := len(raw) - decimals
+	intPart := raw[:insertPos]
+	decPart := strings.TrimRight(raw[insertPos:], "0")
+
+	if decPart == "" {
+		return sign + intPart
+	}
+	return sign + intPart + "." + decPart
+}
@@ -0,0 +1,31 @@
+package units
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		decimals int
+		expected string
+	}{
+		{"empty", "", 18, "0"},
+		{"zero", "0", 6, "0"},
+		{"whole amount", "1000000", 6, "1"},
+		{"fractional amount", "1500000", 6, "1.5"},
+		{"smaller than one unit", "1", 6, "0.000001"},
+		{"exactly decimals digits", "123456", 6, "0.123456"},
+		{"large 18 decimals", "123456789000000000000", 18, "123.456789"},
+		{"no decimals", "42", 0, "42"},
+		{"negative", "-2500000", 6, "-2.5"},
+		{"negative fraction", "-5", 6, "-0.000005"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.raw, tt.decimals); got != tt.expected {
+				t.Errorf("Format(%q, %d) = %q, expected %q", tt.raw, tt.decimals, got, tt.expected)
+			}
+		})
+	}
+}
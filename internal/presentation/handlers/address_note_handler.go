@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// AddressNoteHandler handles HTTP requests for the notes API key tenants keep on addresses
+type AddressNoteHandler struct {
+	service *services.AddressNoteService
+	logger  *zap.Logger
+}
+
+// NewAddressNoteHandler creates a new address note handler
+func NewAddressNoteHandler(service *services.AddressNoteService, logger *zap.Logger) *AddressNoteHandler {
+	return &AddressNoteHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the address note routes
+func (h *AddressNoteHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/me/addresses/{address}/note", func(r chi.Router) {
+		r.Put("/", h.PutNote)
+		r.Get("/", h.GetNote)
+		r.Delete("/", h.DeleteNote)
+	})
+}
+
+type putAddressNoteRequest struct {
+	Note string `json:"note"`
+}
+
+// PutNote handles PUT /api/v1/me/addresses/{address}/note
+func (h *AddressNoteHandler) PutNote(w http.ResponseWriter, r *http.Request) {
+	address, ok := h.address(w, r)
+	if !ok {
+		return
+	}
+
+	var req putAddressNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := h.service.PutNote(r.Context(), address, req.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAPIKeyRequired):
+			h.respondError(w, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, services.ErrInvalidAddressNote):
+			h.respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.logger.Error("Failed to put address note", zap.Error(err), zap.String("address", address))
+			h.respondError(w, http.StatusInternalServerError, "Failed to put address note")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetNote handles GET /api/v1/me/addresses/{address}/note
+func (h *AddressNoteHandler) GetNote(w http.ResponseWriter, r *http.Request) {
+	address, ok := h.address(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetNote(r.Context(), address)
+	if err != nil {
+		if errors.Is(err, services.ErrAPIKeyRequired) {
+			h.respondError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		h.logger.Error("Failed to get address note", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get address note")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "address note not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// DeleteNote handles DELETE /api/v1/me/addresses/{address}/note
+func (h *AddressNoteHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	address, ok := h.address(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.service.DeleteNote(r.Context(), address)
+	if err != nil {
+		if errors.Is(err, services.ErrAPIKeyRequired) {
+			h.respondError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		h.logger.Error("Failed to delete address note", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to delete address note")
+		return
+	}
+
+	if !deleted {
+		h.respondError(w, http.StatusNotFound, "address note not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// address returns the lowercased {address} of the route, answering 400 when it is invalid
+func (h *AddressNoteHandler) address(w http.ResponseWriter, r *http.Request) (string, bool) {
+	address, err := entities.ParseEVMAddress(chi.URLParam(r, "address"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return "", false
+	}
+	return address.String(), true
+}
+
+func (h *AddressNoteHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *AddressNoteHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
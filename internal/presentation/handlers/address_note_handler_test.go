@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+// setupAddressNoteRouter serves the address note routes, as tenant when it is not nil
+func setupAddressNoteRouter(repo *testutil.MockAddressNoteRepository, tenant *entities.Tenant) *chi.Mux {
+	handler := NewAddressNoteHandler(services.NewAddressNoteService(repo, zap.NewNop()), zap.NewNop())
+
+	r := chi.NewRouter()
+	if tenant != nil {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r.WithContext(services.ContextWithTenant(r.Context(), tenant)))
+			})
+		})
+	}
+	handler.RegisterRoutes(r)
+	return r
+}
+
+func TestAddressNoteHandler_Lifecycle(t *testing.T) {
+	r := setupAddressNoteRouter(testutil.NewMockAddressNoteRepository(), &entities.Tenant{ID: 1})
+	path := "/me/addresses/" + testutil.AliceAddress + "/note"
+
+	w := serveWatchlist(r, "PUT", path, `{"note":"exchange hot wallet"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = serveWatchlist(r, "GET", path, "")
+	var got services.AddressNoteResponse
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || got.Data.Note != "exchange hot wallet" || got.Data.Address != testutil.AliceAddress {
+		t.Errorf("expected the note, got %d %+v", w.Code, got.Data)
+	}
+
+	if w = serveWatchlist(r, "DELETE", path, ""); w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+	if w = serveWatchlist(r, "GET", path, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 after deleting, got %d", w.Code)
+	}
+}
+
+func TestAddressNoteHandler_Errors(t *testing.T) {
+	repo := testutil.NewMockAddressNoteRepository()
+	r := setupAddressNoteRouter(repo, &entities.Tenant{ID: 1})
+	anonymous := setupAddressNoteRouter(repo, nil)
+	path := "/me/addresses/" + testutil.AliceAddress + "/note"
+
+	tests := []struct {
+		router http.Handler
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{r, "PUT", "/me/addresses/0xinvalid/note", `{"note":"x"}`, http.StatusBadRequest},
+		{r, "PUT", path, `not json`, http.StatusBadRequest},
+		{r, "PUT", path, `{"note":""}`, http.StatusBadRequest},
+		{r, "DELETE", path, "", http.StatusNotFound},
+		{anonymous, "PUT", path, `{"note":"x"}`, http.StatusUnauthorized},
+		{anonymous, "GET", path, "", http.StatusUnauthorized},
+		{anonymous, "DELETE", path, "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		if w := serveWatchlist(tt.router, tt.method, tt.path, tt.body); w.Code != tt.want {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.method, tt.path, tt.want, w.Code, w.Body.String())
+		}
+	}
+}
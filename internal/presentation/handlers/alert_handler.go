@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// maxAlertRuleNameLength matches the alert_rules.name column
+const maxAlertRuleNameLength = 255
+
+// AlertHandler handles HTTP requests for alert rule endpoints
+type AlertHandler struct {
+	service *services.AlertService
+	logger  *zap.Logger
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(service *services.AlertService, logger *zap.Logger) *AlertHandler {
+	return &AlertHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the alert rule routes
+func (h *AlertHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/alert-rules", func(r chi.Router) {
+		r.Post("/", h.CreateRule)
+		r.Get("/", h.ListRules)
+		r.Get("/{id}", h.GetRule)
+		r.Delete("/{id}", h.DeleteRule)
+	})
+}
+
+type createAlertRuleRequest struct {
+	Name           string   `json:"name"`
+	TokenAddress   *string  `json:"token_address"`
+	Address        *string  `json:"address"`
+	Direction      string   `json:"direction"`
+	MinValue       *string  `json:"min_value"` // Raw amount in the token's smallest unit
+	Counterparties []string `json:"counterparties"`
+	Channel        string   `json:"channel"`
+	Target         string   `json:"target"`
+}
+
+// CreateRule handles POST /api/v1/alert-rules
+func (h *AlertHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req createAlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxAlertRuleNameLength {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("name is required and must be at most %d characters", maxAlertRuleNameLength))
+		return
+	}
+	if (req.TokenAddress != nil && !isValidAddress(*req.TokenAddress)) || (req.Address != nil && !isValidAddress(*req.Address)) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+	for _, addr := range req.Counterparties {
+		if !isValidAddress(addr) {
+			h.respondError(w, http.StatusBadRequest, "Invalid address format")
+			return
+		}
+	}
+
+	rule := &entities.AlertRule{
+		Name:           req.Name,
+		TokenAddress:   req.TokenAddress,
+		Address:        req.Address,
+		Direction:      req.Direction,
+		Counterparties: req.Counterparties,
+		Channel:        req.Channel,
+		Target:         req.Target,
+	}
+	if req.MinValue != nil {
+		value, ok := new(big.Int).SetString(*req.MinValue, 10)
+		if !ok {
+			h.respondError(w, http.StatusBadRequest, "min_value must be an integer amount")
+			return
+		}
+		rule.MinValue = value
+	}
+
+	response, err := h.service.CreateRule(ctx, rule)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAlertRule) {
+			h.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.logger.Error("Failed to create alert rule", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to create alert rule")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, response)
+}
+
+// ListRules handles GET /api/v1/alert-rules
+func (h *AlertHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.ListRules(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list alert rules", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list alert rules")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetRule handles GET /api/v1/alert-rules/{id}
+func (h *AlertHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid alert rule ID")
+		return
+	}
+
+	response, err := h.service.GetRule(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to get alert rule", zap.Error(err), zap.Int64("id", id))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get alert rule")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "alert rule not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// DeleteRule handles DELETE /api/v1/alert-rules/{id}
+func (h *AlertHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid alert rule ID")
+		return
+	}
+
+	deleted, err := h.service.DeleteRule(ctx, id)
+	if err != nil {
+		h.logger.Error("Failed to delete alert rule", zap.Error(err), zap.Int64("id", id))
+		h.respondError(w, http.StatusInternalServerError, "Failed to delete alert rule")
+		return
+	}
+
+	if !deleted {
+		h.respondError(w, http.StatusNotFound, "alert rule not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AlertHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *AlertHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
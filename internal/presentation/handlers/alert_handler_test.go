@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/notify"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func setupAlertRouter() *chi.Mux {
+	logger := zap.NewNop()
+	service := services.NewAlertService(testutil.NewMockAlertRuleRepository(), notify.NewRegistry(testutil.NewMockNotifyDriver("webhook")), logger)
+	handler := NewAlertHandler(service, logger)
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	return r
+}
+
+func TestAlertHandler_Lifecycle(t *testing.T) {
+	r := setupAlertRouter()
+
+	body := fmt.Sprintf(`{"name":"whale","address":%q,"direction":"out","min_value":"1000000000000","channel":"webhook","target":"https://example.com/hook"}`, testutil.AliceAddress)
+	req := httptest.NewRequest("POST", "/alert-rules", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created services.AlertRuleResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.Data.MinValue != "1000000000000" || created.Data.Direction != "out" {
+		t.Errorf("unexpected rule: %+v", created.Data)
+	}
+
+	req = httptest.NewRequest("GET", "/alert-rules", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var list services.AlertRuleListResponse
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(list.Data) != 1 {
+		t.Errorf("expected 1 rule, got %d", len(list.Data))
+	}
+
+	path := fmt.Sprintf("/alert-rules/%d", created.Data.ID)
+	req = httptest.NewRequest("DELETE", path, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", path, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 after delete, got %d", w.Code)
+	}
+}
+
+func TestAlertHandler_CreateRule_Validation(t *testing.T) {
+	r := setupAlertRouter()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"malformed body", `{`},
+		{"missing name", `{"min_value":"1","channel":"webhook","target":"https://example.com"}`},
+		{"invalid counterparty", `{"name":"x","counterparties":["0x1"],"channel":"webhook","target":"https://example.com"}`},
+		{"non-numeric min_value", `{"name":"x","min_value":"1e6","channel":"webhook","target":"https://example.com"}`},
+		{"no conditions", `{"name":"x","channel":"webhook","target":"https://example.com"}`},
+		{"unavailable channel", `{"name":"x","min_value":"1","channel":"email","target":"ops@example.com"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/alert-rules", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// FieldError describes one invalid query parameter
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned by bindQuery when one or more query parameters are invalid
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + " " + f.Message
+	}
+	return "invalid query parameters: " + strings.Join(parts, "; ")
+}
+
+// validationErrorResponse is the 400 body returned for invalid query parameters
+type validationErrorResponse struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields"`
+}
+
+// queryValidator is implemented by query structs with checks spanning several fields, such as
+// block or time ranges. It runs after every field was bound without error.
+type queryValidator interface {
+	validate() []FieldError
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	bigIntType   = reflect.TypeOf(big.Int{})
+)
+
+// bindQuery fills the struct pointed to by dst from the request's query string. Fields are bound
+// by their `query` tag and may declare:
+//
+//	default:"100"      value used when the parameter is absent
+//	min:"1" max:"365"  inclusive bounds for integers and durations
+//	format:"address"   strings must be addresses; they are lowercased
+//	format:"selector"  strings must be 4-byte method selectors; they are lowercased
+//	format:"date"      times are YYYY-MM-DD instead of RFC 3339
+//	oneof:"asc desc"   strings must be one of the listed values, matched case-insensitively
+//
+// Supported field types are string, bool, int, int64, time.Duration, time.Time and *big.Int, and
+// pointers to them; pointer fields stay nil when their parameter is absent. Every invalid parameter
+// is reported in the returned *ValidationError rather than replaced by its default.
+func bindQuery(r *http.Request, dst interface{}) error {
+	errs := bindFields(r.URL.Query(), reflect.ValueOf(dst).Elem())
+
+	if len(errs) == 0 {
+		if qv, ok := dst.(queryValidator); ok {
+			errs = qv.validate()
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+// bindFields binds the tagged fields of struct v, descending into embedded structs such as pageQuery
+func bindFields(query url.Values, v reflect.Value) []FieldError {
+	t := v.Type()
+
+	var errs []FieldError
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			errs = append(errs, bindFields(query, v.Field(i))...)
+			continue
+		}
+
+		name := field.Tag.Get("query")
+		if name == "" {
+			continue
+		}
+
+		raw := query.Get(name)
+		if raw == "" {
+			raw = field.Tag.Get("default")
+		}
+		if raw == "" {
+			continue
+		}
+
+		if msg := setField(v.Field(i), field.Tag, raw); msg != "" {
+			errs = append(errs, FieldError{Field: name, Message: msg})
+		}
+	}
+	return errs
+}
+
+// setField parses raw into the field and returns a message describing why it is invalid, if it is
+func setField(fv reflect.Value, tag reflect.StructTag, raw string) string {
+	if fv.Kind() == reflect.Ptr {
+		if fv.Type().Elem() == bigIntType {
+			value, ok := new(big.Int).SetString(raw, 10)
+			if !ok || value.Sign() < 0 {
+				return "must be a non-negative integer"
+			}
+			fv.Set(reflect.ValueOf(value))
+			return ""
+		}
+
+		elem := reflect.New(fv.Type().Elem())
+		if msg := setField(elem.Elem(), tag, raw); msg != "" {
+			return msg
+		}
+		fv.Set(elem)
+		return ""
+	}
+
+	switch {
+	case fv.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return "must be a duration such as 24h"
+		}
+		if msg := checkBounds(tag, int64(d), func(s string) (int64, error) {
+			bound, err := time.ParseDuration(s)
+			return int64(bound), err
+		}, func(n int64) string { return time.Duration(n).String() }); msg != "" {
+			return msg
+		}
+		fv.SetInt(int64(d))
+
+	case fv.Type() == timeType:
+		layout, want := time.RFC3339, "an RFC 3339 timestamp"
+		if tag.Get("format") == "date" {
+			layout, want = "2006-01-02", "a date (YYYY-MM-DD)"
+		}
+		ts, err := time.Parse(layout, raw)
+		if err != nil {
+			return "must be " + want
+		}
+		fv.Set(reflect.ValueOf(ts))
+
+	case fv.Kind() == reflect.String:
+		if tag.Get("format") == "address" {
+			address, err := entities.ParseEVMAddress(raw)
+			if err != nil {
+				return "must be a 0x-prefixed address"
+			}
+			raw = address.String()
+		}
+		if tag.Get("format") == "selector" {
+			if !isValidSelector(raw) {
+				return "must be a 0x-prefixed 4-byte selector"
+			}
+			raw = strings.ToLower(raw)
+		}
+		if options := tag.Get("oneof"); options != "" {
+			allowed := strings.Fields(options)
+			matched := ""
+			for _, option := range allowed {
+				if strings.EqualFold(raw, option) {
+					matched = option
+				}
+			}
+			if matched == "" {
+				return "must be one of " + strings.Join(allowed, ", ")
+			}
+			raw = matched
+		}
+		fv.SetString(raw)
+
+	case fv.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "must be true or false"
+		}
+		fv.SetBool(b)
+
+	case fv.Kind() == reflect.Int || fv.Kind() == reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return "must be an integer"
+		}
+		if msg := checkBounds(tag, n, func(s string) (int64, error) {
+			return strconv.ParseInt(s, 10, 64)
+		}, func(n int64) string { return strconv.FormatInt(n, 10) }); msg != "" {
+			return msg
+		}
+		fv.SetInt(n)
+
+	default:
+		panic(fmt.Sprintf("bindQuery: unsupported field type %s", fv.Type()))
+	}
+
+	return ""
+}
+
+// checkBounds applies a field's min and max tags to n
+func checkBounds(tag reflect.StructTag, n int64, parse func(string) (int64, error), format func(int64) string) string {
+	if s := tag.Get("min"); s != "" {
+		if bound, err := parse(s); err == nil && n < bound {
+			return "must be at least " + format(bound)
+		}
+	}
+	if s := tag.Get("max"); s != "" {
+		if bound, err := parse(s); err == nil && n > bound {
+			return "must be at most " + format(bound)
+		}
+	}
+	return ""
+}
+
+// respondValidationError writes a 400 listing the invalid query parameters in err, or a 422 when
+// err is a *LimitExceededError
+func respondValidationError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	response := validationErrorResponse{Error: "Invalid query parameters"}
+
+	var verr *ValidationError
+	var lerr *LimitExceededError
+	switch {
+	case errors.As(err, &verr):
+		response.Fields = verr.Fields
+	case errors.As(err, &lerr):
+		status = http.StatusUnprocessableEntity
+		response.Error = lerr.Error()
+		response.Fields = []FieldError{{Field: "limit", Message: fmt.Sprintf("must be at most %d", lerr.Max)}}
+	default:
+		response.Error = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// pageQuery is the limit/offset pair shared by paginated endpoints. The limit's default and
+// maximum are the endpoint's PageLimits, applied after binding.
+type pageQuery struct {
+	Limit  int `query:"limit" default:"100" min:"1"`
+	Offset int `query:"offset" min:"0"`
+}
+
+// PageLimits are the page sizes of list endpoints: Default when a request sets no limit and at
+// most Max, unless EndpointMax caps an endpoint differently
+type PageLimits struct {
+	Default     int
+	Max         int
+	EndpointMax map[string]int // keyed by one of PageEndpoints
+}
+
+// DefaultPageLimits are the page sizes used unless configured otherwise
+var DefaultPageLimits = PageLimits{Default: 100, Max: 1000}
+
+// PageEndpoints are the endpoints whose maximum page size can be set in PageLimits.EndpointMax
+var PageEndpoints = []string{"transfers", "holders", "tokens", "eth_transfers", "swaps", "watchlists", "entities", "contract_events"}
+
+// Validate rejects page sizes that are not positive and caps for unknown endpoints
+func (p PageLimits) Validate() error {
+	if p.Default < 1 || p.Max < 1 {
+		return fmt.Errorf("page sizes must be positive, got default %d and max %d", p.Default, p.Max)
+	}
+	for endpoint, size := range p.EndpointMax {
+		if !slices.Contains(PageEndpoints, endpoint) {
+			return fmt.Errorf("unknown endpoint %q, expected one of %s", endpoint, strings.Join(PageEndpoints, ", "))
+		}
+		if size < 1 {
+			return fmt.Errorf("page size of %s must be positive, got %d", endpoint, size)
+		}
+	}
+	return nil
+}
+
+// MaxFor returns the largest page an endpoint serves
+func (p PageLimits) MaxFor(endpoint string) int {
+	if size, ok := p.EndpointMax[endpoint]; ok {
+		return size
+	}
+	return p.Max
+}
+
+// DefaultFor returns the page size an endpoint serves when the request sets no limit
+func (p PageLimits) DefaultFor(endpoint string) int {
+	return min(p.Default, p.MaxFor(endpoint))
+}
+
+// apply replaces the bound limit with the configured default when the request set none, and
+// rejects one above the endpoint's maximum
+func (p PageLimits) apply(r *http.Request, endpoint string, limit *int) error {
+	if r.URL.Query().Get("limit") == "" {
+		*limit = p.DefaultFor(endpoint)
+	}
+	return checkLimit(*limit, p.MaxFor(endpoint))
+}
+
+// parsePage reads limit (the configured default, at most the endpoint's maximum) and offset (default 0)
+func (p PageLimits) parsePage(r *http.Request, endpoint string) (pageQuery, error) {
+	var q pageQuery
+	if err := bindQuery(r, &q); err != nil {
+		return q, err
+	}
+	return q, p.apply(r, endpoint, &q.Limit)
+}
+
+// LimitExceededError reports a limit above an endpoint's maximum. It is answered with 422 instead
+// of clamping the limit, so a client never mistakes a capped page for the page it asked for.
+type LimitExceededError struct {
+	Limit int
+	Max   int
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("limit %d exceeds the maximum of %d for this endpoint", e.Limit, e.Max)
+}
+
+// checkLimit rejects a limit above maxLimit
+func checkLimit(limit, maxLimit int) error {
+	if limit > maxLimit {
+		return &LimitExceededError{Limit: limit, Max: maxLimit}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type bindingTestQuery struct {
+	Address  *string       `query:"address" format:"address"`
+	Selector *string       `query:"selector" format:"selector"`
+	Block    *int64        `query:"block" min:"0"`
+	Since    time.Duration `query:"since" default:"1h" max:"24h"`
+	Day      *time.Time    `query:"day" format:"date"`
+	At       *time.Time    `query:"at"`
+	Value    *big.Int      `query:"value"`
+	Detailed bool          `query:"detailed"`
+	pageQuery
+}
+
+func TestBindQuery_Defaults(t *testing.T) {
+	var q bindingTestQuery
+	if err := bindQuery(httptest.NewRequest(http.MethodGet, "/", nil), &q); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if q.Limit != 100 || q.Offset != 0 {
+		t.Errorf("expected default page 100/0, got %d/%d", q.Limit, q.Offset)
+	}
+	if q.Since != time.Hour {
+		t.Errorf("expected default since 1h, got %s", q.Since)
+	}
+	if q.Address != nil || q.Block != nil || q.Day != nil || q.At != nil || q.Value != nil {
+		t.Errorf("expected absent optional parameters to stay nil, got %+v", q)
+	}
+}
+
+func TestBindQuery_Values(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet,
+		"/?address=0xDAC17F958D2EE523A2206206994597C13D831EC7&selector=0xA9059CBB&block=42&since=2h&day=2024-01-15"+
+			"&at=2024-01-15T10:30:00Z&value=1000000000000000000000&detailed=true&limit=5&offset=10", nil)
+
+	var q bindingTestQuery
+	if err := bindQuery(req, &q); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if q.Address == nil || *q.Address != "0xdac17f958d2ee523a2206206994597c13d831ec7" {
+		t.Errorf("expected lowercased address, got %v", q.Address)
+	}
+	if q.Selector == nil || *q.Selector != "0xa9059cbb" {
+		t.Errorf("expected lowercased selector, got %v", q.Selector)
+	}
+	if q.Block == nil || *q.Block != 42 {
+		t.Errorf("expected block 42, got %v", q.Block)
+	}
+	if q.Since != 2*time.Hour {
+		t.Errorf("expected since 2h, got %s", q.Since)
+	}
+	if q.Day == nil || !q.Day.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected day %v", q.Day)
+	}
+	if q.At == nil || !q.At.Equal(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected timestamp %v", q.At)
+	}
+	if q.Value == nil || q.Value.String() != "1000000000000000000000" {
+		t.Errorf("unexpected value %v", q.Value)
+	}
+	if !q.Detailed || q.Limit != 5 || q.Offset != 10 {
+		t.Errorf("unexpected detailed/limit/offset %v/%d/%d", q.Detailed, q.Limit, q.Offset)
+	}
+}
+
+func TestBindQuery_Errors(t *testing.T) {
+	tests := []struct {
+		query   string
+		field   string
+		message string
+	}{
+		{"address=0x123", "address", "must be a 0x-prefixed address"},
+		{"selector=0xa9059cbz", "selector", "must be a 0x-prefixed 4-byte selector"},
+		{"block=-1", "block", "must be at least 0"},
+		{"block=latest", "block", "must be an integer"},
+		{"since=48h", "since", "must be at most 24h0m0s"},
+		{"since=soon", "since", "must be a duration such as 24h"},
+		{"day=15/01/2024", "day", "must be a date (YYYY-MM-DD)"},
+		{"at=2024-01-15", "at", "must be an RFC 3339 timestamp"},
+		{"value=-1", "value", "must be a non-negative integer"},
+		{"detailed=yes", "detailed", "must be true or false"},
+		{"limit=0", "limit", "must be at least 1"},
+		{"offset=-5", "offset", "must be at least 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var q bindingTestQuery
+			err := bindQuery(httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil), &q)
+
+			verr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("expected *ValidationError, got %v", err)
+			}
+			if len(verr.Fields) != 1 || verr.Fields[0].Field != tt.field || verr.Fields[0].Message != tt.message {
+				t.Errorf("expected %s %q, got %+v", tt.field, tt.message, verr.Fields)
+			}
+		})
+	}
+}
+
+func TestRespondValidationError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	respondValidationError(rec, &ValidationError{Fields: []FieldError{{Field: "limit", Message: "must be an integer"}}})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	var response validationErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Error != "Invalid query parameters" || len(response.Fields) != 1 || response.Fields[0].Field != "limit" {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	small := PageLimits{Default: 50, Max: 500, EndpointMax: map[string]int{"holders": 20}}
+
+	tests := []struct {
+		name          string
+		pages         PageLimits
+		endpoint      string
+		query         string
+		expectedLimit int
+		expectedErr   bool
+	}{
+		{"default", DefaultPageLimits, "transfers", "", 100, false},
+		{"explicit", DefaultPageLimits, "transfers", "limit=50", 50, false},
+		{"at max", DefaultPageLimits, "transfers", "limit=1000", 1000, false},
+		{"above max", DefaultPageLimits, "transfers", "limit=1001", 0, true},
+		{"configured default", small, "transfers", "", 50, false},
+		{"configured max", small, "transfers", "limit=501", 0, true},
+		{"endpoint max", small, "holders", "limit=21", 0, true},
+		{"default within endpoint max", small, "holders", "", 20, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := tt.pages.parsePage(httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil), tt.endpoint)
+			if tt.expectedErr {
+				if _, ok := err.(*LimitExceededError); !ok {
+					t.Fatalf("expected *LimitExceededError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if page.Limit != tt.expectedLimit {
+				t.Errorf("expected limit %d, got %d", tt.expectedLimit, page.Limit)
+			}
+		})
+	}
+}
+
+func TestPageLimits_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pages   PageLimits
+		wantErr bool
+	}{
+		{"defaults", DefaultPageLimits, false},
+		{"endpoint override", PageLimits{Default: 100, Max: 1000, EndpointMax: map[string]int{"holders": 200}}, false},
+		{"zero max", PageLimits{Default: 100}, true},
+		{"unknown endpoint", PageLimits{Default: 100, Max: 1000, EndpointMax: map[string]int{"holder": 200}}, true},
+		{"zero endpoint max", PageLimits{Default: 100, Max: 1000, EndpointMax: map[string]int{"holders": 0}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.pages.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRespondValidationError_LimitExceeded(t *testing.T) {
+	rec := httptest.NewRecorder()
+	respondValidationError(rec, &LimitExceededError{Limit: 5000, Max: 1000})
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, rec.Code)
+	}
+
+	var response validationErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Fields) != 1 || response.Fields[0].Message != "must be at most 1000" {
+		t.Errorf("unexpected fields %+v", response.Fields)
+	}
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// setMaxAge sets "Cache-Control: max-age" to the TTL the response is cached for on the server, in
+// whole seconds, so clients can cache it as long. Nothing is set for a TTL under a second.
+func setMaxAge(w http.ResponseWriter, ttl time.Duration) {
+	seconds := int(ttl / time.Second)
+	if seconds < 1 {
+		return
+	}
+	w.Header().Set("Cache-Control", "max-age="+strconv.Itoa(seconds))
+}
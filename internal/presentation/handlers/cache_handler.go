@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// maxCacheKeyLength bounds the keys accepted by the flush endpoint
+const maxCacheKeyLength = 512
+
+// cachePrefixPattern matches the resource prefixes cache keys start with, such as "stats" or "holders"
+var cachePrefixPattern = regexp.MustCompile(`^[a-z_]+$`)
+
+// CacheFlusher removes cached entries by key or key pattern
+type CacheFlusher interface {
+	Delete(ctx context.Context, key string) error
+	DeletePattern(ctx context.Context, pattern string) error
+}
+
+// CacheHandler handles HTTP requests for cache administration
+type CacheHandler struct {
+	cache  CacheFlusher
+	logger *zap.Logger
+}
+
+// NewCacheHandler creates a new cache handler
+func NewCacheHandler(cache CacheFlusher, logger *zap.Logger) *CacheHandler {
+	return &CacheHandler{
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// RegisterAdminRoutes registers the cache admin routes; callers protect them with middleware.AdminAuth
+func (h *CacheHandler) RegisterAdminRoutes(r chi.Router) {
+	r.Post("/admin/cache/flush", h.Flush)
+}
+
+// flushCacheRequest names either a key prefix or a single key to flush
+type flushCacheRequest struct {
+	Prefix string `json:"prefix"`
+	Key    string `json:"key"`
+}
+
+// Flush handles POST /api/v1/admin/cache/flush. Stale copies kept for database outages are
+// flushed along with the entries they shadow.
+func (h *CacheHandler) Flush(w http.ResponseWriter, r *http.Request) {
+	var req flushCacheRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if (req.Prefix == "") == (req.Key == "") {
+		h.respondError(w, http.StatusBadRequest, "exactly one of prefix or key is required")
+		return
+	}
+
+	ctx := r.Context()
+	var err error
+	if req.Prefix != "" {
+		if !cachePrefixPattern.MatchString(req.Prefix) {
+			h.respondError(w, http.StatusBadRequest, "prefix must contain only lowercase letters and underscores")
+			return
+		}
+		if err = h.cache.DeletePattern(ctx, req.Prefix+":*"); err == nil {
+			err = h.cache.DeletePattern(ctx, "stale:"+req.Prefix+":*")
+		}
+	} else {
+		if len(req.Key) > maxCacheKeyLength || strings.ContainsAny(req.Key, "*?[") {
+			h.respondError(w, http.StatusBadRequest, "key must be an exact cache key")
+			return
+		}
+		if err = h.cache.Delete(ctx, req.Key); err == nil {
+			err = h.cache.Delete(ctx, "stale:"+req.Key)
+		}
+	}
+	if err != nil {
+		h.logger.Error("Failed to flush cache", zap.Error(err), zap.String("prefix", req.Prefix), zap.String("key", req.Key))
+		h.respondError(w, http.StatusInternalServerError, "Failed to flush cache")
+		return
+	}
+
+	h.logger.Info("Cache flushed", zap.String("prefix", req.Prefix), zap.String("key", req.Key))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CacheHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *CacheHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+func TestCacheHandler_Flush(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(time.Minute, zap.NewNop())
+	for _, key := range []string{"stats:0xa", "stale:stats:0xa", "holders:0xa", "stale:holders:0xa", "holders:0xb"} {
+		if err := c.Set(ctx, key, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := chi.NewRouter()
+	NewCacheHandler(c, zap.NewNop()).RegisterAdminRoutes(r)
+
+	cached := func(key string) bool {
+		var v int
+		return !errors.Is(c.Get(ctx, key, &v), cache.ErrCacheMiss)
+	}
+
+	if w := serveWatchlist(r, "POST", "/admin/cache/flush", `{"prefix":"stats"}`); w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if cached("stats:0xa") || cached("stale:stats:0xa") {
+		t.Error("expected the stats prefix and its stale copies to be flushed")
+	}
+	if !cached("holders:0xa") {
+		t.Error("expected other prefixes to be kept")
+	}
+
+	if w := serveWatchlist(r, "POST", "/admin/cache/flush", `{"key":"holders:0xa"}`); w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if cached("holders:0xa") || cached("stale:holders:0xa") {
+		t.Error("expected the key and its stale copy to be flushed")
+	}
+	if !cached("holders:0xb") {
+		t.Error("expected other keys to be kept")
+	}
+
+	for _, body := range []string{`{}`, `{"prefix":"stats","key":"stats:0xa"}`, `{"prefix":"*"}`, `{"key":"holders:*"}`, `not json`} {
+		if w := serveWatchlist(r, "POST", "/admin/cache/flush", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, w.Code)
+		}
+	}
+}
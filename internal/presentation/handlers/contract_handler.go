@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+)
+
+// contractArgParamPrefix starts the argument clauses that may also be given as query parameters
+// of their own, as in args.to=0x...
+const contractArgParamPrefix = "args."
+
+// contractWhereParam holds argument clauses joined by &, as in where=args.to=0x...%26args.value>1000
+const contractWhereParam = "where"
+
+// ContractHandler handles HTTP requests for contracts registered by ABI and their events
+type ContractHandler struct {
+	service *services.ContractService
+	logger  *zap.Logger
+	pages   PageLimits
+}
+
+// NewContractHandler creates a new contract handler
+func NewContractHandler(service *services.ContractService, logger *zap.Logger) *ContractHandler {
+	return &ContractHandler{
+		service: service,
+		logger:  logger,
+		pages:   DefaultPageLimits,
+	}
+}
+
+// WithPageLimits sets the endpoints' default and maximum page sizes
+func (h *ContractHandler) WithPageLimits(pages PageLimits) *ContractHandler {
+	h.pages = pages
+	return h
+}
+
+// RegisterRoutes registers the contract event routes
+func (h *ContractHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/contracts/{address}/events", h.GetContractEvents)
+}
+
+// RegisterAdminRoutes registers the contract admin routes; callers protect them with middleware.AdminAuth
+func (h *ContractHandler) RegisterAdminRoutes(r chi.Router) {
+	r.Put("/admin/contracts/{address}", h.RegisterContract)
+}
+
+type registerContractRequest struct {
+	Name string `json:"name"`
+	// ABI is the JSON ABI array, or a string holding it
+	ABI    json.RawMessage `json:"abi"`
+	Events []string        `json:"events"`
+}
+
+// RegisterContract handles PUT /api/v1/admin/contracts/{address}
+func (h *ContractHandler) RegisterContract(w http.ResponseWriter, r *http.Request) {
+	address, err := entities.ParseEVMAddress(chi.URLParam(r, "address"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid contract address format")
+		return
+	}
+
+	var req registerContractRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	abiJSON := string(req.ABI)
+	var quoted string
+	if err := json.Unmarshal(req.ABI, &quoted); err == nil {
+		abiJSON = quoted
+	}
+
+	response, err := h.service.RegisterContract(r.Context(), address.String(), req.Name, abiJSON, req.Events)
+	if err != nil {
+		if errors.Is(err, ethereum.ErrInvalidContractABI) {
+			h.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.logger.Error("Failed to register contract", zap.Error(err), zap.String("address", address.String()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to register contract")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+type contractEventsQuery struct {
+	pageQuery
+	Event string `query:"event"`
+}
+
+// GetContractEvents handles GET /api/v1/contracts/{address}/events
+func (h *ContractHandler) GetContractEvents(w http.ResponseWriter, r *http.Request) {
+	address, err := entities.ParseEVMAddress(chi.URLParam(r, "address"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid contract address format")
+		return
+	}
+
+	var q contractEventsQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+	if err := h.pages.apply(r, "contract_events", &q.Limit); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	where, err := contractArgClauses(r)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	response, err := h.service.GetContractEvents(r.Context(), address.String(), q.Event, where, q.Limit, q.Offset)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidContractEventFilter) {
+			h.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.logger.Error("Failed to get contract events", zap.Error(err), zap.String("address", address.String()))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get contract events")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "contract not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// contractArgClauses returns the argument clauses of the where parameters followed by those given
+// as parameters of their own. The raw query is split by hand: a clause such as args.value>=1000
+// is not a key=value pair, and where's value may itself hold & and = once unescaped.
+func contractArgClauses(r *http.Request) ([]string, error) {
+	var where, params []string
+	for _, part := range strings.Split(r.URL.RawQuery, "&") {
+		decoded, err := url.QueryUnescape(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query parameter %q", part)
+		}
+
+		if value, ok := strings.CutPrefix(decoded, contractWhereParam+"="); ok {
+			for _, clause := range strings.Split(value, "&") {
+				if clause != "" {
+					where = append(where, clause)
+				}
+			}
+		} else if strings.HasPrefix(decoded, contractArgParamPrefix) {
+			params = append(params, decoded)
+		}
+	}
+	return append(where, params...), nil
+}
+
+func (h *ContractHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *ContractHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
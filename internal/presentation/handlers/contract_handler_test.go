@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func setupContractRouter(mockRepo *testutil.MockContractRepository) *chi.Mux {
+	logger := zap.NewNop()
+	handler := NewContractHandler(services.NewContractService(mockRepo, logger), logger)
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	handler.RegisterAdminRoutes(r)
+	return r
+}
+
+func TestContractHandler_RegisterContract(t *testing.T) {
+	mockRepo := testutil.NewMockContractRepository()
+	router := setupContractRouter(mockRepo)
+
+	// The ABI can be sent as JSON or as a string holding it
+	quoted := strconv.Quote(testutil.ERC20EventsABI)
+	for _, abi := range []string{testutil.ERC20EventsABI, quoted} {
+		body := `{"name":"Tether","abi":` + abi + `,"events":["Transfer"]}`
+		req := httptest.NewRequest("PUT", "/admin/contracts/"+testutil.USDTAddress, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response services.ContractResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if response.Data.Address != testutil.USDTAddress || len(response.Data.Events) != 1 {
+			t.Errorf("unexpected registration: %+v", response.Data)
+		}
+	}
+
+	req := httptest.NewRequest("PUT", "/admin/contracts/0xinvalid", strings.NewReader(`{"abi":`+testutil.ERC20EventsABI+`}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid address, got %d", w.Code)
+	}
+
+	for name, body := range map[string]string{
+		"malformed body": `{"abi":`,
+		"missing event":  `{"abi":` + testutil.ERC20EventsABI + `,"events":["Swap"]}`,
+		"malformed ABI":  `{"abi":"not an abi"}`,
+	} {
+		req := httptest.NewRequest("PUT", "/admin/contracts/"+testutil.USDTAddress, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", name, w.Code)
+		}
+	}
+}
+
+func TestContractHandler_GetContractEvents(t *testing.T) {
+	mockRepo := testutil.NewMockContractRepository()
+	router := setupContractRouter(mockRepo)
+	ctx := context.Background()
+
+	t.Run("returns 404 for an unregistered contract", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/contracts/"+testutil.USDTAddress+"/events", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	_ = mockRepo.UpsertContract(ctx, &entities.Contract{Address: testutil.USDTAddress, Name: "Tether", ABI: testutil.ERC20EventsABI, Events: []string{"Transfer"}})
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for i, to := range []string{testutil.BobAddress, testutil.CharlieAddr, testutil.BobAddress} {
+		_ = mockRepo.StoreEvents(ctx, []any{&entities.ContractEvent{
+			ContractAddress: testutil.USDTAddress, EventName: "Transfer", TxHash: "0xa" + strconv.Itoa(i), BlockNumber: int64(i), BlockTimestamp: ts.Add(time.Duration(i) * time.Minute),
+			Args: []byte(`{"from":"` + testutil.AliceAddress + `","to":"` + to + `","value":5}`),
+		}})
+	}
+
+	t.Run("filters by argument", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/contracts/"+testutil.USDTAddress+"/events?event=Transfer&args.to="+testutil.BobAddress+"&args.value=5&limit=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response services.ContractEventsResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if response.Total != 2 || len(response.Events) != 1 || !response.HasMore || response.Events[0].TxHash != "0xa2" {
+			t.Errorf("expected the newest of Bob's 2 transfers, got %+v", response)
+		}
+	})
+
+	t.Run("filters with where clauses", func(t *testing.T) {
+		for query, want := range map[string]int64{
+			// Unescaped, the second clause reaches the handler as a parameter of its own
+			"where=args.to=" + testutil.BobAddress + "&args.value>4":                     2,
+			"where=" + url.QueryEscape("args.to!="+testutil.BobAddress+"&args.value>=5"): 1,
+			"where=args.value<5": 0,
+		} {
+			req := httptest.NewRequest("GET", "/contracts/"+testutil.USDTAddress+"/events?event=Transfer&"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s: expected status 200, got %d: %s", query, w.Code, w.Body.String())
+			}
+
+			var response services.ContractEventsResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Total != want {
+				t.Errorf("%s: expected %d events, got %d", query, want, response.Total)
+			}
+		}
+	})
+
+	t.Run("rejects invalid filters", func(t *testing.T) {
+		for _, query := range []string{"args.to=" + testutil.BobAddress, "event=Approval", "event=Transfer&args.value=five", "limit=0",
+			"event=Transfer&where=args.to>" + testutil.BobAddress, "event=Transfer&where=args.value~5", "event=Transfer&where=%zz"} {
+			req := httptest.NewRequest("GET", "/contracts/"+testutil.USDTAddress+"/events?"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected status 400, got %d", query, w.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// maxEntityNameLength matches the entities.name column
+const maxEntityNameLength = 255
+
+// EntityHandler handles HTTP requests for entity endpoints
+type EntityHandler struct {
+	service         *services.EntityService
+	transferService *services.TransferService
+	logger          *zap.Logger
+	pages           PageLimits
+}
+
+// NewEntityHandler creates a new entity handler.
+// transferService enriches the feed for ?include_usd=true.
+func NewEntityHandler(service *services.EntityService, transferService *services.TransferService, logger *zap.Logger) *EntityHandler {
+	return &EntityHandler{
+		service:         service,
+		transferService: transferService,
+		logger:          logger,
+		pages:           DefaultPageLimits,
+	}
+}
+
+// WithPageLimits sets the endpoints' default and maximum page sizes
+func (h *EntityHandler) WithPageLimits(pages PageLimits) *EntityHandler {
+	h.pages = pages
+	return h
+}
+
+// RegisterRoutes registers the entity routes
+func (h *EntityHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/entities", func(r chi.Router) {
+		r.Post("/", h.CreateEntity)
+		r.Get("/{id}", h.GetEntity)
+		r.Delete("/{id}", h.DeleteEntity)
+		r.Post("/{id}/addresses", h.AddAddresses)
+		r.Delete("/{id}/addresses/{address}", h.RemoveAddress)
+		r.Get("/{id}/portfolio", h.GetEntityPortfolio)
+		r.Get("/{id}/summary", h.GetEntitySummary)
+		r.Get("/{id}/transfers", h.GetEntityTransfers)
+	})
+}
+
+type createEntityRequest struct {
+	Name      string   `json:"name"`
+	Category  string   `json:"category"`
+	Addresses []string `json:"addresses"`
+}
+
+type addEntityAddressesRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+// CreateEntity handles POST /api/v1/entities
+func (h *EntityHandler) CreateEntity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req createEntityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxEntityNameLength {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("name is required and must be at most %d characters", maxEntityNameLength))
+		return
+	}
+	req.Category = strings.ToLower(req.Category)
+	if req.Category != "" && !slices.Contains(entities.EntityCategories, req.Category) {
+		h.respondError(w, http.StatusBadRequest, "category must be one of "+strings.Join(entities.EntityCategories, ", "))
+		return
+	}
+	if !h.validAddresses(w, req.Addresses) {
+		return
+	}
+
+	response, err := h.service.CreateEntity(ctx, req.Name, req.Category, req.Addresses)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to create entity")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, response)
+}
+
+// GetEntity handles GET /api/v1/entities/{id}
+func (h *EntityHandler) GetEntity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetEntity(ctx, id)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to get entity")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "entity not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// DeleteEntity handles DELETE /api/v1/entities/{id}
+func (h *EntityHandler) DeleteEntity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.service.DeleteEntity(ctx, id)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to delete entity")
+		return
+	}
+
+	if !deleted {
+		h.respondError(w, http.StatusNotFound, "entity not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddAddresses handles POST /api/v1/entities/{id}/addresses
+func (h *EntityHandler) AddAddresses(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req addEntityAddressesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if len(req.Addresses) == 0 {
+		h.respondError(w, http.StatusBadRequest, "addresses is required")
+		return
+	}
+	if !h.validAddresses(w, req.Addresses) {
+		return
+	}
+
+	response, err := h.service.AddAddresses(ctx, id, req.Addresses)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to add entity addresses")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "entity not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// RemoveAddress handles DELETE /api/v1/entities/{id}/addresses/{address}
+func (h *EntityHandler) RemoveAddress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	address := chi.URLParam(r, "address")
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	response, err := h.service.RemoveAddress(ctx, id, address)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to remove entity address")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "entity not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetEntityPortfolio handles GET /api/v1/entities/{id}/portfolio
+func (h *EntityHandler) GetEntityPortfolio(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetEntityPortfolio(ctx, id)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to get entity portfolio")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "entity not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
+}
+
+// GetEntitySummary handles GET /api/v1/entities/{id}/summary
+func (h *EntityHandler) GetEntitySummary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetEntitySummary(ctx, id)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to get entity summary")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "entity not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetEntityTransfers handles GET /api/v1/entities/{id}/transfers
+func (h *EntityHandler) GetEntityTransfers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.pages.parsePage(r, "entities")
+	if err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	withUSD, err := includeUSD(r)
+	if err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.GetEntityTransfers(ctx, id, page.Limit, page.Offset)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to get entity transfers")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "entity not found")
+		return
+	}
+
+	if withUSD {
+		h.transferService.AddUSDValues(ctx, response, r.URL.Query().Get("price_at") == "historical")
+	}
+	services.ApplyValueFormat(response, r.URL.Query().Get("decimals"))
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
+}
+
+func (h *EntityHandler) parseID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid entity ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *EntityHandler) validAddresses(w http.ResponseWriter, addresses []string) bool {
+	if len(addresses) > services.MaxEntityAddresses {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("an entity can hold at most %d addresses", services.MaxEntityAddresses))
+		return false
+	}
+	for _, addr := range addresses {
+		if !isValidAddress(addr) {
+			h.respondError(w, http.StatusBadRequest, "Invalid address format")
+			return false
+		}
+	}
+	return true
+}
+
+func (h *EntityHandler) handleServiceError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, services.ErrEntityFull) {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("an entity can hold at most %d addresses", services.MaxEntityAddresses))
+		return
+	}
+	if respondUnavailable(w, err) {
+		return
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	h.respondError(w, http.StatusInternalServerError, message)
+}
+
+func (h *EntityHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *EntityHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
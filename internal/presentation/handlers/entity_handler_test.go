@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func setupEntityRouter(portfolioRepo *testutil.MockPortfolioRepository) *chi.Mux {
+	logger := zap.NewNop()
+	transferService := services.NewTransferService(testutil.NewMockTransferRepository(), testutil.NewMockTokenRepository(), nil, logger)
+	entityService := services.NewEntityService(testutil.NewMockEntityRepository(), portfolioRepo, transferService, logger)
+	handler := NewEntityHandler(entityService, transferService, logger)
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	return r
+}
+
+func TestEntityHandler_Lifecycle(t *testing.T) {
+	portfolioRepo := testutil.NewMockPortfolioRepository()
+	portfolioRepo.GetEntityTokenVolumesFunc = func(ctx context.Context, addresses []string) ([]repositories.EntityTokenVolume, error) {
+		return []repositories.EntityTokenVolume{
+			{TokenAddress: testutil.USDTAddress, Decimals: 6, InternalTransfers: 1, VolumeIn: "0", VolumeOut: "0", InternalVolume: "1000000"},
+		}, nil
+	}
+	r := setupEntityRouter(portfolioRepo)
+
+	w := serveWatchlist(r, "POST", "/entities", fmt.Sprintf(`{"name":"exchange","addresses":[%q,%q]}`, testutil.AliceAddress, testutil.BobAddress))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created services.EntityResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	base := fmt.Sprintf("/entities/%d", created.Data.ID)
+
+	for _, path := range []string{base, base + "/portfolio", base + "/summary", base + "/transfers"} {
+		if w := serveWatchlist(r, "GET", path, ""); w.Code != http.StatusOK {
+			t.Errorf("GET %s: expected status 200, got %d", path, w.Code)
+		}
+	}
+
+	w = serveWatchlist(r, "GET", base+"/summary", "")
+	var summary services.EntitySummaryResponse
+	if err := json.NewDecoder(w.Body).Decode(&summary); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if summary.Data.AddressCount != 2 || summary.Data.InternalTransfers != 1 {
+		t.Errorf("unexpected summary: %+v", summary.Data)
+	}
+
+	w = serveWatchlist(r, "DELETE", base+"/addresses/"+testutil.BobAddress, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	if w := serveWatchlist(r, "DELETE", base, ""); w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+	for _, path := range []string{base, base + "/portfolio", base + "/summary", base + "/transfers"} {
+		if w := serveWatchlist(r, "GET", path, ""); w.Code != http.StatusNotFound {
+			t.Errorf("GET %s after delete: expected status 404, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestEntityHandler_Validation(t *testing.T) {
+	r := setupEntityRouter(testutil.NewMockPortfolioRepository())
+
+	for _, tc := range []struct {
+		name, method, path, body string
+	}{
+		{"missing name", "POST", "/entities", `{"addresses":[]}`},
+		{"invalid address", "POST", "/entities", `{"name":"x","addresses":["0x123"]}`},
+		{"invalid body", "POST", "/entities", `{`},
+		{"unknown category", "POST", "/entities", `{"name":"x","category":"dex","addresses":[]}`},
+		{"invalid id", "GET", "/entities/abc/summary", ""},
+	} {
+		if w := serveWatchlist(r, tc.method, tc.path, tc.body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", tc.name, w.Code)
+		}
+	}
+}
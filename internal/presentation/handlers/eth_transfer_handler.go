@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// EthTransferHandler handles HTTP requests for native ETH transfer endpoints
+type EthTransferHandler struct {
+	service *services.EthTransferService
+	logger  *zap.Logger
+	pages   PageLimits
+}
+
+// NewEthTransferHandler creates a new ETH transfer handler
+func NewEthTransferHandler(service *services.EthTransferService, logger *zap.Logger) *EthTransferHandler {
+	return &EthTransferHandler{
+		service: service,
+		logger:  logger,
+		pages:   DefaultPageLimits,
+	}
+}
+
+// WithPageLimits sets the endpoints' default and maximum page sizes
+func (h *EthTransferHandler) WithPageLimits(pages PageLimits) *EthTransferHandler {
+	h.pages = pages
+	return h
+}
+
+// RegisterRoutes registers the ETH transfer routes
+func (h *EthTransferHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/eth-transfers", h.GetEthTransfers)
+	r.Get("/eth-transfers/address/{address}", h.GetEthTransfersByAddress)
+}
+
+// GetEthTransfers handles GET /api/v1/eth-transfers
+func (h *EthTransferHandler) GetEthTransfers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	page, err := h.pages.parsePage(r, "eth_transfers")
+	if err != nil {
+		respondValidationError(w, err)
+		return
+	}
+	filter := entities.EthTransferFilter{
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+
+	for param, dst := range map[string]**string{
+		"address": &filter.Address,
+		"from":    &filter.FromAddress,
+		"to":      &filter.ToAddress,
+	} {
+		v := query.Get(param)
+		if v == "" {
+			continue
+		}
+		if !isValidAddress(v) {
+			h.respondError(w, http.StatusBadRequest, "Invalid "+param+" address format")
+			return
+		}
+		addr := strings.ToLower(v)
+		*dst = &addr
+	}
+
+	response, err := h.service.GetEthTransfers(ctx, filter)
+	if err != nil {
+		h.logger.Error("Failed to get ETH transfers", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get ETH transfers")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
+}
+
+// GetEthTransfersByAddress handles GET /api/v1/eth-transfers/address/{address}
+func (h *EthTransferHandler) GetEthTransfersByAddress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	page, err := h.pages.parsePage(r, "eth_transfers")
+	if err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.GetEthTransfersByAddress(ctx, address, page.Limit, page.Offset)
+	if err != nil {
+		h.logger.Error("Failed to get ETH transfers by address", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get ETH transfers")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
+}
+
+func (h *EthTransferHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *EthTransferHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func setupEthTransferRouter(mockRepo *testutil.MockEthTransferRepository) *chi.Mux {
+	logger := zap.NewNop()
+	handler := NewEthTransferHandler(services.NewEthTransferService(mockRepo, nil, logger), logger)
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	return r
+}
+
+func TestEthTransferHandler_GetEthTransfers(t *testing.T) {
+	mockRepo := testutil.NewMockEthTransferRepository()
+	mockRepo.AddEthTransfers(
+		entities.EthTransfer{FromAddress: testutil.AliceAddress, ToAddress: testutil.BobAddress, ValueString: "5"},
+		entities.EthTransfer{FromAddress: testutil.BobAddress, ToAddress: testutil.CharlieAddr, ValueString: "3"},
+	)
+
+	t.Run("filters by sender", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/eth-transfers?from="+testutil.BobAddress, nil)
+		w := httptest.NewRecorder()
+		setupEthTransferRouter(mockRepo).ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var response services.EthTransferResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(response.Transfers) != 1 || response.Transfers[0].ToAddress != testutil.CharlieAddr {
+			t.Errorf("unexpected transfers: %+v", response.Transfers)
+		}
+	})
+
+	t.Run("rejects invalid address filters", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/eth-transfers?to=0x123", nil)
+		w := httptest.NewRecorder()
+		setupEthTransferRouter(mockRepo).ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestEthTransferHandler_GetEthTransfersByAddress(t *testing.T) {
+	mockRepo := testutil.NewMockEthTransferRepository()
+	mockRepo.AddEthTransfers(
+		entities.EthTransfer{FromAddress: testutil.AliceAddress, ToAddress: testutil.BobAddress, ValueString: "5"},
+		entities.EthTransfer{FromAddress: testutil.BobAddress, ToAddress: testutil.CharlieAddr, ValueString: "3"},
+	)
+
+	t.Run("returns transfers sent or received", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/eth-transfers/address/"+testutil.BobAddress+"?limit=1", nil)
+		w := httptest.NewRecorder()
+		setupEthTransferRouter(mockRepo).ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var response services.EthTransferResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(response.Transfers) != 1 || response.Total != 2 || !response.HasMore {
+			t.Errorf("unexpected pagination: %d transfers, total %d, has_more %v", len(response.Transfers), response.Total, response.HasMore)
+		}
+	})
+
+	t.Run("returns 400 for invalid address", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/eth-transfers/address/not-an-address", nil)
+		w := httptest.NewRecorder()
+		setupEthTransferRouter(mockRepo).ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+// ExportHandler handles HTTP requests for wallet transfer exports
+type ExportHandler struct {
+	service *services.ExportService
+	logger  *zap.Logger
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(service *services.ExportService, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the export routes. They are registered with full paths rather than
+// under a /wallets route, which the portfolio handler owns.
+func (h *ExportHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/wallets/{address}/exports", h.CreateExport)
+	r.Get("/wallets/{address}/exports/{id}", h.GetExport)
+	r.Get("/wallets/{address}/exports/{id}/download", h.DownloadExport)
+}
+
+// exportQuery holds the query parameters of POST /api/v1/wallets/{address}/exports
+type exportQuery struct {
+	Format string `query:"format" default:"csv" oneof:"csv json"`
+}
+
+// CreateExport handles POST /api/v1/wallets/{address}/exports
+func (h *ExportHandler) CreateExport(w http.ResponseWriter, r *http.Request) {
+	address := chi.URLParam(r, "address")
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid wallet address format")
+		return
+	}
+	address = strings.ToLower(address)
+
+	var q exportQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	job, err := h.service.StartExport(r.Context(), address, strings.ToLower(q.Format))
+	if errors.Is(err, services.ErrExportQueueFull) {
+		w.Header().Set("Retry-After", strconv.Itoa(60))
+		h.respondError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to start export", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to start export")
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/wallets/"+address+"/exports/"+job.ID)
+	h.respondJSON(w, http.StatusAccepted, job)
+}
+
+// GetExport handles GET /api/v1/wallets/{address}/exports/{id}
+func (h *ExportHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.loadJob(w, r)
+	if !ok {
+		return
+	}
+	h.respondJSON(w, http.StatusOK, job)
+}
+
+// DownloadExport handles GET /api/v1/wallets/{address}/exports/{id}/download
+func (h *ExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.loadJob(w, r)
+	if !ok {
+		return
+	}
+	if job.Status != services.ExportDone {
+		h.respondError(w, http.StatusConflict, "export is "+job.Status)
+		return
+	}
+
+	data, err := h.service.GetExportFile(r.Context(), job)
+	if err != nil {
+		h.logger.Error("Failed to read export file", zap.Error(err), zap.String("id", job.ID))
+		h.respondError(w, http.StatusInternalServerError, "Failed to read export file")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+services.ExportFileName(job)+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// loadJob validates the path parameters and returns the export job, responding itself when there is none
+func (h *ExportHandler) loadJob(w http.ResponseWriter, r *http.Request) (*services.ExportJob, bool) {
+	address := chi.URLParam(r, "address")
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid wallet address format")
+		return nil, false
+	}
+	id := chi.URLParam(r, "id")
+	if !services.IsExportID(id) {
+		h.respondError(w, http.StatusBadRequest, "Invalid export ID")
+		return nil, false
+	}
+
+	job, err := h.service.GetExport(r.Context(), address, id)
+	if err != nil {
+		h.logger.Error("Failed to get export", zap.Error(err), zap.String("id", id))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get export")
+		return nil, false
+	}
+	if job == nil {
+		h.respondError(w, http.StatusNotFound, "export not found")
+		return nil, false
+	}
+	return job, true
+}
+
+func (h *ExportHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *ExportHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/archive"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func setupExportRouter(t *testing.T) *chi.Mux {
+	logger := zap.NewNop()
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(testutil.CreateTestTransfer())
+
+	service := services.NewExportService(transferRepo, archive.NewFileStore(t.TempDir()), 1, logger)
+	t.Cleanup(service.Close)
+
+	// The portfolio handler owns the /wallets route; the export routes must coexist with it
+	r := chi.NewRouter()
+	NewPortfolioHandler(nil, logger).RegisterRoutes(r)
+	NewExportHandler(service, logger).RegisterRoutes(r)
+	return r
+}
+
+func TestExportHandler_CreateAndDownload(t *testing.T) {
+	router := setupExportRouter(t)
+	base := "/wallets/" + testutil.AliceAddress + "/exports"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, base+"?format=json", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var job services.ExportJob
+	if err := json.NewDecoder(w.Body).Decode(&job); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if job.Format != services.ExportJSON || w.Header().Get("Location") != "/api/v1"+base+"/"+job.ID {
+		t.Fatalf("unexpected job %+v at %q", job, w.Header().Get("Location"))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status != services.ExportDone && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, base+"/"+job.ID, nil))
+		if err := json.NewDecoder(w.Body).Decode(&job); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	if job.Status != services.ExportDone || job.Rows != 1 {
+		t.Fatalf("expected a finished export of 1 row, got %+v", job)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, base+"/"+job.ID+"/download", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/zip" {
+		t.Fatalf("expected a ZIP download, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Header().Get("Content-Disposition"), job.ID) || w.Body.Len() == 0 {
+		t.Errorf("unexpected download headers %v", w.Header())
+	}
+}
+
+func TestExportHandler_Validation(t *testing.T) {
+	router := setupExportRouter(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"invalid address", http.MethodPost, "/wallets/0x123/exports", http.StatusBadRequest},
+		{"unknown format", http.MethodPost, "/wallets/" + testutil.AliceAddress + "/exports?format=xml", http.StatusBadRequest},
+		{"invalid id", http.MethodGet, "/wallets/" + testutil.AliceAddress + "/exports/..%2Fsecret", http.StatusBadRequest},
+		{"unknown id", http.MethodGet, "/wallets/" + testutil.AliceAddress + "/exports/" + strings.Repeat("0", 32), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// withFields applies a ?fields= sparse fieldset to a response. fields is a comma-separated
+// list of keys kept on each list item (transfers, holdings, ...); envelope fields such as
+// pagination are always kept. Unknown keys are ignored. Without ?fields= data is returned as is.
+func withFields(r *http.Request, data interface{}) interface{} {
+	fields := parseFields(r.URL.Query().Get("fields"))
+	if len(fields) == 0 {
+		return data
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+
+	// UseNumber keeps integers such as block numbers exact
+	var tree interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return data
+	}
+
+	return pruneListItems(tree, fields)
+}
+
+func parseFields(v string) map[string]bool {
+	fields := make(map[string]bool)
+	for _, field := range strings.Split(v, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields[field] = true
+		}
+	}
+	return fields
+}
+
+// pruneListItems drops keys not in fields from objects found in the outermost lists of node
+func pruneListItems(node interface{}, fields map[string]bool) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = pruneListItems(child, fields)
+		}
+	case []interface{}:
+		for _, item := range v {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for key := range obj {
+				if !fields[key] {
+					delete(obj, key)
+				}
+			}
+		}
+	}
+	return node
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"time"
+
+	"go.uber.org/zap"
 )
 
 // HealthChecker defines the interface for health checking components
@@ -12,25 +14,44 @@ type HealthChecker interface {
 	HealthCheck(ctx context.Context) error
 }
 
+// FreshnessSource reports when the indexer last advanced its slowest checkpoint. Checkpoints move
+// with every indexed block range, so a token without recent transfers does not look stale.
+type FreshnessSource interface {
+	GetOldestCheckpointTime(ctx context.Context) (*time.Time, error)
+}
+
 // HealthHandler handles health check requests
 type HealthHandler struct {
-	db    HealthChecker
-	cache HealthChecker
+	db         HealthChecker
+	cache      HealthChecker
+	freshness  FreshnessSource
+	maxDataAge time.Duration
+	logger     *zap.Logger
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(db, cache HealthChecker) *HealthHandler {
+func NewHealthHandler(db, cache HealthChecker, logger *zap.Logger) *HealthHandler {
 	return &HealthHandler{
-		db:    db,
-		cache: cache,
+		db:     db,
+		cache:  cache,
+		logger: logger,
 	}
 }
 
+// WithFreshnessCheck enables reporting "degraded" when the indexer's checkpoints have not
+// advanced for longer than maxAge
+func (h *HealthHandler) WithFreshnessCheck(source FreshnessSource, maxAge time.Duration) *HealthHandler {
+	h.freshness = source
+	h.maxDataAge = maxAge
+	return h
+}
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status    string            `json:"status"`
-	Timestamp string            `json:"timestamp"`
-	Services  map[string]string `json:"services"`
+	Status         string            `json:"status"`
+	Timestamp      string            `json:"timestamp"`
+	Services       map[string]string `json:"services"`
+	DataLagSeconds *int64            `json:"data_lag_seconds,omitempty"`
 }
 
 // Health handles GET /health
@@ -62,6 +83,11 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Check data freshness
+	if h.freshness != nil && h.maxDataAge > 0 {
+		h.checkFreshness(ctx, &response)
+	}
+
 	status := http.StatusOK
 	if response.Status == "unhealthy" {
 		status = http.StatusServiceUnavailable
@@ -72,6 +98,38 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(response)
 }
 
+// checkFreshness marks the response degraded when a checkpoint has not advanced for too long
+func (h *HealthHandler) checkFreshness(ctx context.Context, response *HealthResponse) {
+	latest, err := h.freshness.GetOldestCheckpointTime(ctx)
+	if err != nil {
+		h.logger.Warn("Failed to check indexer freshness", zap.Error(err))
+		response.Services["indexer"] = "unknown"
+		return
+	}
+
+	if latest == nil {
+		response.Services["indexer"] = "stale: no indexed data"
+		if response.Status == "healthy" {
+			response.Status = "degraded"
+		}
+		return
+	}
+
+	lag := time.Since(*latest)
+	lagSeconds := int64(lag.Seconds())
+	response.DataLagSeconds = &lagSeconds
+
+	if lag > h.maxDataAge {
+		response.Services["indexer"] = "stale: checkpoint lag " + lag.Truncate(time.Second).String()
+		if response.Status == "healthy" {
+			response.Status = "degraded"
+		}
+		return
+	}
+
+	response.Services["indexer"] = "healthy"
+}
+
 // Ready handles GET /ready (Kubernetes readiness probe)
 func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
 	"github.com/bimakw/chain-indexer/internal/testutil"
 )
 
@@ -13,7 +19,7 @@ func TestNewHealthHandler(t *testing.T) {
 	db := testutil.NewMockHealthChecker(true)
 	cache := testutil.NewMockHealthChecker(true)
 
-	handler := NewHealthHandler(db, cache)
+	handler := NewHealthHandler(db, cache, zap.NewNop())
 	if handler == nil {
 		t.Fatal("expected non-nil handler")
 	}
@@ -22,7 +28,7 @@ func TestNewHealthHandler(t *testing.T) {
 func TestHealthHandler_Health_AllHealthy(t *testing.T) {
 	db := testutil.NewMockHealthChecker(true)
 	cache := testutil.NewMockHealthChecker(true)
-	handler := NewHealthHandler(db, cache)
+	handler := NewHealthHandler(db, cache, zap.NewNop())
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rec := httptest.NewRecorder()
@@ -55,7 +61,7 @@ func TestHealthHandler_Health_AllHealthy(t *testing.T) {
 func TestHealthHandler_Health_DatabaseUnhealthy(t *testing.T) {
 	db := testutil.NewMockHealthChecker(false)
 	cache := testutil.NewMockHealthChecker(true)
-	handler := NewHealthHandler(db, cache)
+	handler := NewHealthHandler(db, cache, zap.NewNop())
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rec := httptest.NewRecorder()
@@ -80,7 +86,7 @@ func TestHealthHandler_Health_DatabaseUnhealthy(t *testing.T) {
 func TestHealthHandler_Health_CacheUnhealthy(t *testing.T) {
 	db := testutil.NewMockHealthChecker(true)
 	cache := testutil.NewMockHealthChecker(false)
-	handler := NewHealthHandler(db, cache)
+	handler := NewHealthHandler(db, cache, zap.NewNop())
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rec := httptest.NewRecorder()
@@ -105,7 +111,7 @@ func TestHealthHandler_Health_CacheUnhealthy(t *testing.T) {
 
 func TestHealthHandler_Health_NoCache(t *testing.T) {
 	db := testutil.NewMockHealthChecker(true)
-	handler := NewHealthHandler(db, nil) // No cache
+	handler := NewHealthHandler(db, nil, zap.NewNop()) // No cache
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rec := httptest.NewRecorder()
@@ -130,7 +136,7 @@ func TestHealthHandler_Health_NoCache(t *testing.T) {
 
 func TestHealthHandler_Health_ContentType(t *testing.T) {
 	db := testutil.NewMockHealthChecker(true)
-	handler := NewHealthHandler(db, nil)
+	handler := NewHealthHandler(db, nil, zap.NewNop())
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rec := httptest.NewRecorder()
@@ -145,7 +151,7 @@ func TestHealthHandler_Health_ContentType(t *testing.T) {
 
 func TestHealthHandler_Ready_Healthy(t *testing.T) {
 	db := testutil.NewMockHealthChecker(true)
-	handler := NewHealthHandler(db, nil)
+	handler := NewHealthHandler(db, nil, zap.NewNop())
 
 	req := httptest.NewRequest(http.MethodGet, "/ready", nil)
 	rec := httptest.NewRecorder()
@@ -164,7 +170,7 @@ func TestHealthHandler_Ready_Healthy(t *testing.T) {
 
 func TestHealthHandler_Ready_Unhealthy(t *testing.T) {
 	db := testutil.NewMockHealthChecker(false)
-	handler := NewHealthHandler(db, nil)
+	handler := NewHealthHandler(db, nil, zap.NewNop())
 
 	req := httptest.NewRequest(http.MethodGet, "/ready", nil)
 	rec := httptest.NewRecorder()
@@ -178,7 +184,7 @@ func TestHealthHandler_Ready_Unhealthy(t *testing.T) {
 
 func TestHealthHandler_Live(t *testing.T) {
 	db := testutil.NewMockHealthChecker(true)
-	handler := NewHealthHandler(db, nil)
+	handler := NewHealthHandler(db, nil, zap.NewNop())
 
 	req := httptest.NewRequest(http.MethodGet, "/live", nil)
 	rec := httptest.NewRecorder()
@@ -198,7 +204,7 @@ func TestHealthHandler_Live(t *testing.T) {
 func TestHealthHandler_Live_AlwaysAlive(t *testing.T) {
 	// Even when DB is unhealthy, liveness should pass
 	db := testutil.NewMockHealthChecker(false)
-	handler := NewHealthHandler(db, nil)
+	handler := NewHealthHandler(db, nil, zap.NewNop())
 
 	req := httptest.NewRequest(http.MethodGet, "/live", nil)
 	rec := httptest.NewRecorder()
@@ -214,7 +220,7 @@ func TestHealthHandler_Live_AlwaysAlive(t *testing.T) {
 func TestHealthResponse_Structure(t *testing.T) {
 	db := testutil.NewMockHealthChecker(true)
 	cache := testutil.NewMockHealthChecker(true)
-	handler := NewHealthHandler(db, cache)
+	handler := NewHealthHandler(db, cache, zap.NewNop())
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rec := httptest.NewRecorder()
@@ -241,3 +247,140 @@ func TestHealthResponse_Structure(t *testing.T) {
 		t.Error("missing database in services")
 	}
 }
+
+type mockFreshnessSource struct {
+	latest *time.Time
+	err    error
+}
+
+func (m *mockFreshnessSource) GetOldestCheckpointTime(ctx context.Context) (*time.Time, error) {
+	return m.latest, m.err
+}
+
+func TestHealthHandler_Health_Freshness(t *testing.T) {
+	recent := time.Now().Add(-30 * time.Second)
+	stale := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		source         *mockFreshnessSource
+		expectedStatus string
+		expectLag      bool
+	}{
+		{"fresh data", &mockFreshnessSource{latest: &recent}, "healthy", true},
+		{"stale data", &mockFreshnessSource{latest: &stale}, "degraded", true},
+		{"no data", &mockFreshnessSource{}, "degraded", false},
+		{"source error", &mockFreshnessSource{err: errors.New("query failed")}, "healthy", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testutil.NewMockHealthChecker(true)
+			handler := NewHealthHandler(db, nil, zap.NewNop()).WithFreshnessCheck(tt.source, 5*time.Minute)
+
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			rec := httptest.NewRecorder()
+
+			handler.Health(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected status 200, got %d", rec.Code)
+			}
+
+			var response HealthResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if response.Status != tt.expectedStatus {
+				t.Errorf("expected status %s, got %s", tt.expectedStatus, response.Status)
+			}
+			if tt.expectLag && response.DataLagSeconds == nil {
+				t.Error("expected data_lag_seconds to be set")
+			}
+			if _, ok := response.Services["indexer"]; !ok {
+				t.Error("expected indexer service entry")
+			}
+		})
+	}
+}
+
+func TestHealthHandler_Health_FreshnessErrorNotExposed(t *testing.T) {
+	db := testutil.NewMockHealthChecker(true)
+	source := &mockFreshnessSource{err: errors.New("pq: connection refused to 10.0.0.5")}
+	handler := NewHealthHandler(db, nil, zap.NewNop()).WithFreshnessCheck(source, 5*time.Minute)
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Health(rec, req)
+
+	var response HealthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Services["indexer"] != "unknown" {
+		t.Errorf("expected indexer status unknown, got %q", response.Services["indexer"])
+	}
+}
+
+func TestHealthHandler_Health_FreshnessDisabled(t *testing.T) {
+	db := testutil.NewMockHealthChecker(true)
+	handler := NewHealthHandler(db, nil, zap.NewNop()).WithFreshnessCheck(&mockFreshnessSource{}, 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Health(rec, req)
+
+	var response HealthResponse
+	json.NewDecoder(rec.Body).Decode(&response)
+
+	if response.Status != "healthy" {
+		t.Errorf("expected status healthy, got %s", response.Status)
+	}
+	if _, ok := response.Services["indexer"]; ok {
+		t.Error("expected no indexer entry when freshness check is disabled")
+	}
+}
+
+type fakeIndexerHealth struct{ failRPC bool }
+
+func (f fakeIndexerHealth) CheckHealth(ctx context.Context) *services.IndexerHealth {
+	health := &services.IndexerHealth{Status: "healthy", Checks: map[string]string{"rpc": "healthy"}}
+	if f.failRPC {
+		health.Fail("rpc", "connection refused")
+	}
+	return health
+}
+
+func TestIndexerHealthHandler_Health(t *testing.T) {
+	tests := []struct {
+		name       string
+		failRPC    bool
+		dbHealthy  bool
+		wantStatus int
+	}{
+		{"healthy", false, true, http.StatusOK},
+		{"rpc unreachable", true, true, http.StatusServiceUnavailable},
+		{"database unreachable", false, false, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewIndexerHealthHandler(fakeIndexerHealth{failRPC: tt.failRPC}, testutil.NewMockHealthChecker(tt.dbHealthy), zap.NewNop())
+			rec := httptest.NewRecorder()
+			handler.Health(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			var body services.IndexerHealth
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if wantHealthy := tt.wantStatus == http.StatusOK; (body.Status == "healthy") != wantHealthy || body.Checks["database"] == "" {
+				t.Errorf("unexpected body %+v", body)
+			}
+		})
+	}
+}
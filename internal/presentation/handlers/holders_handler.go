@@ -2,20 +2,24 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
 	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
 )
 
 // HoldersHandler handles HTTP requests for token holders
 type HoldersHandler struct {
 	service *services.HoldersService
 	logger  *zap.Logger
+	pages   PageLimits
 }
 
 // NewHoldersHandler creates a new holders handler
@@ -23,9 +27,21 @@ func NewHoldersHandler(service *services.HoldersService, logger *zap.Logger) *Ho
 	return &HoldersHandler{
 		service: service,
 		logger:  logger,
+		pages:   DefaultPageLimits,
 	}
 }
 
+// WithPageLimits sets the endpoints' default and maximum page sizes
+func (h *HoldersHandler) WithPageLimits(pages PageLimits) *HoldersHandler {
+	h.pages = pages
+	return h
+}
+
+// RegisterAdminRoutes registers the holder diagnostics routes; callers protect them with middleware.AdminAuth
+func (h *HoldersHandler) RegisterAdminRoutes(r chi.Router) {
+	r.Get("/admin/diagnostics/negative-balances", h.GetNegativeBalances)
+}
+
 // GetTopHolders handles GET /api/v1/tokens/{address}/holders
 func (h *HoldersHandler) GetTopHolders(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -38,27 +54,22 @@ func (h *HoldersHandler) GetTopHolders(w http.ResponseWriter, r *http.Request) {
 
 	address = strings.ToLower(address)
 
-	// Parse limit parameter (default 100, max 1000)
-	limit := 100
-	if v := r.URL.Query().Get("limit"); v != "" {
-		if l, err := strconv.Atoi(v); err == nil && l > 0 {
-			if l > 1000 {
-				l = 1000
-			}
-			limit = l
-		}
+	// Parse limit (configured default and maximum), offset (default 0), include_excluded and include_percentage
+	var q topHoldersQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
 	}
-
-	// Parse offset parameter (default 0)
-	offset := 0
-	if v := r.URL.Query().Get("offset"); v != "" {
-		if o, err := strconv.Atoi(v); err == nil && o >= 0 {
-			offset = o
-		}
+	if err := h.pages.apply(r, "holders", &q.Limit); err != nil {
+		respondValidationError(w, err)
+		return
 	}
 
-	response, err := h.service.GetTopHolders(ctx, address, limit, offset)
+	response, err := h.service.GetTopHolders(ctx, address, q.Limit, q.Offset, q.IncludeExcluded)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get top holders", zap.Error(err), zap.String("address", address))
 		h.respondError(w, http.StatusInternalServerError, "Failed to get top holders")
 		return
@@ -69,6 +80,11 @@ func (h *HoldersHandler) GetTopHolders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if q.IncludePercentage {
+		h.service.AddPercentages(ctx, address, response)
+	}
+
+	setMaxAge(w, h.service.CacheTTL())
 	h.respondJSON(w, http.StatusOK, response)
 }
 
@@ -91,8 +107,17 @@ func (h *HoldersHandler) GetHolderBalance(w http.ResponseWriter, r *http.Request
 	tokenAddress = strings.ToLower(tokenAddress)
 	holderAddress = strings.ToLower(holderAddress)
 
+	var q percentageQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
 	response, err := h.service.GetHolderBalance(ctx, tokenAddress, holderAddress)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get holder balance",
 			zap.Error(err),
 			zap.String("token", tokenAddress),
@@ -107,9 +132,268 @@ func (h *HoldersHandler) GetHolderBalance(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if q.IncludePercentage {
+		h.service.AddBalancePercentage(ctx, tokenAddress, response)
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetHolderChanges handles GET /api/v1/tokens/{address}/holders/changes
+func (h *HoldersHandler) GetHolderChanges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	var q holderChangesQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+	if err := checkLimit(q.Limit, maxHolderChangesLimit); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.GetHolderChanges(ctx, address, q.Since, q.Limit, q.IncludeExcluded)
+	if errors.Is(err, services.ErrNoHolderSnapshot) {
+		h.respondError(w, http.StatusNotFound, "no holder snapshot available yet")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get holder changes", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get holder changes")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// CompareHolderSnapshots handles GET /api/v1/tokens/{address}/holders/compare
+func (h *HoldersHandler) CompareHolderSnapshots(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	var q holderCompareQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.CompareHolderSnapshots(ctx, address, *q.From, *q.To, q.IncludeExcluded)
+	if errors.Is(err, services.ErrNoHolderSnapshot) {
+		h.respondError(w, http.StatusNotFound, "no holder snapshot available yet")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to compare holder snapshots", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to compare holder snapshots")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetHolderHistory handles GET /api/v1/tokens/{address}/holders/{holder_address}/history
+func (h *HoldersHandler) GetHolderHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tokenAddress := chi.URLParam(r, "address")
+	holderAddress := chi.URLParam(r, "holder_address")
+
+	if !isValidAddress(tokenAddress) {
+		h.respondError(w, http.StatusBadRequest, "Invalid token address format")
+		return
+	}
+
+	if !isValidAddress(holderAddress) {
+		h.respondError(w, http.StatusBadRequest, "Invalid holder address format")
+		return
+	}
+
+	tokenAddress = strings.ToLower(tokenAddress)
+	holderAddress = strings.ToLower(holderAddress)
+
+	// Parse limit (default 100, max 10000), offset (default 0) and the time range
+	var q holderHistoryQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+	if err := checkLimit(q.Limit, services.MaxHolderHistoryLimit); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+	filter := repositories.HolderHistoryFilter{
+		FromTime: q.FromTime,
+		ToTime:   q.ToTime,
+		Limit:    q.Limit,
+		Offset:   q.Offset,
+	}
+
+	// Entries are written as they are read, so a large page is never held in memory.
+	// The status goes out with the first entry; a later failure can only abort the response.
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, `{"data":[`)
+		started = true
+	}
+	enc := json.NewEncoder(w)
+
+	pagination, err := h.service.StreamHolderHistory(ctx, tokenAddress, holderAddress, filter, func(e services.HolderHistoryEntryDTO) error {
+		if !started {
+			start()
+		} else if _, err := io.WriteString(w, ","); err != nil {
+			return err
+		}
+		return enc.Encode(e)
+	})
+	if err != nil {
+		h.logger.Error("Failed to get holder history",
+			zap.Error(err),
+			zap.String("token", tokenAddress),
+			zap.String("holder", holderAddress),
+		)
+		if started {
+			panic(http.ErrAbortHandler)
+		}
+		h.respondError(w, http.StatusInternalServerError, "Failed to get holder history")
+		return
+	}
+
+	if pagination == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	if !started {
+		start()
+	}
+	_, _ = io.WriteString(w, `],"pagination":`)
+	_ = enc.Encode(pagination)
+	_, _ = io.WriteString(w, "}")
+}
+
+// GetNegativeBalances handles GET /api/v1/admin/diagnostics/negative-balances
+func (h *HoldersHandler) GetNegativeBalances(w http.ResponseWriter, r *http.Request) {
+	var q negativeBalancesQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	var token string
+	if q.Token != nil {
+		token = *q.Token
+	}
+	response, err := h.service.GetNegativeBalances(r.Context(), token, q.Limit)
+	if err != nil {
+		h.logger.Error("Failed to get negative balances", zap.Error(err), zap.String("token", token))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get negative balances")
+		return
+	}
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+// negativeBalancesQuery holds the negative balance diagnostics query parameters; without a token
+// every registered token is checked
+type negativeBalancesQuery struct {
+	Token *string `query:"token" format:"address"`
+	Limit int     `query:"limit" default:"10" min:"1" max:"100"`
+}
+
+// maxHolderChangesLimit is the largest number of gainers and losers a holder changes request returns
+const maxHolderChangesLimit = 100
+
+// exclusionQuery is embedded by holder endpoints that leave the configured holder exclusions out
+type exclusionQuery struct {
+	IncludeExcluded bool `query:"include_excluded"`
+}
+
+// percentageQuery holds the include_percentage parameter shared by holder endpoints
+type percentageQuery struct {
+	IncludePercentage bool `query:"include_percentage"`
+}
+
+// topHoldersQuery holds the top holders query parameters
+type topHoldersQuery struct {
+	exclusionQuery
+	percentageQuery
+	pageQuery
+}
+
+// holderChangesQuery holds the holder changes query parameters; since is at most
+// services.MaxHolderChangesWindow
+type holderChangesQuery struct {
+	Since time.Duration `query:"since" default:"24h" min:"1ns" max:"720h"`
+	Limit int           `query:"limit" default:"20" min:"1"`
+	exclusionQuery
+}
+
+// holderCompareQuery holds the days whose holder snapshots are compared
+type holderCompareQuery struct {
+	From *time.Time `query:"from" format:"date"`
+	To   *time.Time `query:"to" format:"date"`
+	exclusionQuery
+}
+
+func (q *holderCompareQuery) validate() []FieldError {
+	var errs []FieldError
+	if q.From == nil {
+		errs = append(errs, FieldError{Field: "from", Message: "is required"})
+	}
+	if q.To == nil {
+		errs = append(errs, FieldError{Field: "to", Message: "is required"})
+	}
+	if q.From != nil && q.To != nil && q.From.After(*q.To) {
+		errs = append(errs, FieldError{Field: "to", Message: "must not be before from"})
+	}
+	return errs
+}
+
+// holderHistoryQuery holds the holder history query parameters
+type holderHistoryQuery struct {
+	FromTime *time.Time `query:"from_time"`
+	ToTime   *time.Time `query:"to_time"`
+	pageQuery
+}
+
+func (q *holderHistoryQuery) validate() []FieldError {
+	if q.FromTime != nil && q.ToTime != nil && q.FromTime.After(*q.ToTime) {
+		return []FieldError{{Field: "to_time", Message: "must not be before from_time"}}
+	}
+	return nil
+}
+
 func (h *HoldersHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
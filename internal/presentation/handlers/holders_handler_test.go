@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -45,12 +47,12 @@ func TestHoldersHandler_GetTopHolders_Success(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 100, nil
 	}
 
 	// Setup mock holders response
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		return []repositories.HolderBalance{
 			{Address: "0x47ac0fb4f2d84898e4d9e7b4dab3c24507a6d503", Balance: "999999999999999999999", Rank: 1},
 			{Address: "0x1111111111111111111111111111111111111111", Balance: "500000000000000000000", Rank: 2},
@@ -108,12 +110,12 @@ func TestHoldersHandler_GetTopHolders_WithLimit(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 100, nil
 	}
 
 	var capturedLimit int
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		capturedLimit = limit
 		return []repositories.HolderBalance{
 			{Address: "0x47ac0fb4f2d84898e4d9e7b4dab3c24507a6d503", Balance: "1000", Rank: 1},
@@ -145,12 +147,12 @@ func TestHoldersHandler_GetTopHolders_MaxLimit(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 100, nil
 	}
 
 	var capturedLimit int
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		capturedLimit = limit
 		return []repositories.HolderBalance{}, nil
 	}
@@ -163,12 +165,26 @@ func TestHoldersHandler_GetTopHolders_MaxLimit(t *testing.T) {
 
 	r.ServeHTTP(rec, req)
 
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got %d", rec.Code)
+	}
+
+	if capturedLimit != 0 {
+		t.Errorf("expected no repository call, got limit %d", capturedLimit)
+	}
+
+	// The maximum itself is accepted
+	req = httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holders?limit=1000", nil)
+	rec = httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
 	if rec.Code != http.StatusOK {
 		t.Errorf("expected status 200, got %d", rec.Code)
 	}
 
 	if capturedLimit != 1000 {
-		t.Errorf("expected max limit 1000, got %d", capturedLimit)
+		t.Errorf("expected limit 1000, got %d", capturedLimit)
 	}
 }
 
@@ -237,11 +253,11 @@ func TestHoldersHandler_GetTopHolders_UppercaseAddress(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 100, nil
 	}
 
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		return []repositories.HolderBalance{
 			{Address: "0x47ac0fb4f2d84898e4d9e7b4dab3c24507a6d503", Balance: "1000", Rank: 1},
 		}, nil
@@ -296,11 +312,11 @@ func TestHoldersHandler_GetTopHolders_EmptyResult(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 0, nil
 	}
 
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		return []repositories.HolderBalance{}, nil
 	}
 
@@ -332,12 +348,12 @@ func TestHoldersHandler_GetTopHolders_WithOffset(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 500, nil
 	}
 
 	var capturedOffset int
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		capturedOffset = offset
 		return []repositories.HolderBalance{
 			{Address: "0x47ac0fb4f2d84898e4d9e7b4dab3c24507a6d503", Balance: "1000", Rank: offset + 1},
@@ -374,6 +390,108 @@ func TestHoldersHandler_GetTopHolders_WithOffset(t *testing.T) {
 	}
 }
 
+func TestHoldersHandler_GetTopHolders_IncludeExcluded(t *testing.T) {
+	handler, transferRepo, tokenRepo := setupHoldersHandlerTest()
+	handler.service.WithHolderExclusions(services.HolderExclusions{Addresses: []string{testutil.CharlieAddr}})
+
+	tokenRepo.AddToken(testutil.CreateTestToken(
+		testutil.TokenWithAddress(testutil.USDTAddress),
+	))
+
+	var captured []string
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+		captured = exclude
+		return nil, nil
+	}
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/holders", handler.GetTopHolders)
+
+	for _, tt := range []struct {
+		query    string
+		expected int
+	}{
+		{"", 1},
+		{"?include_excluded=true", 0},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holders"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d", tt.query, rec.Code)
+		}
+		if len(captured) != tt.expected {
+			t.Errorf("%q: expected %d excluded addresses, got %v", tt.query, tt.expected, captured)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holders?include_excluded=maybe", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid include_excluded, got %d", rec.Code)
+	}
+}
+
+func TestHoldersHandler_IncludePercentage(t *testing.T) {
+	handler, transferRepo, tokenRepo := setupHoldersHandlerTest()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(
+		testutil.TokenWithAddress(testutil.USDTAddress),
+	))
+
+	transferRepo.GetCirculatingSupplyFunc = func(ctx context.Context, tokenAddress string) (string, error) {
+		return "4000000000000000000", nil
+	}
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+		return []repositories.HolderBalance{{Address: testutil.AliceAddress, Balance: "1000000000000000000", Rank: 1}}, nil
+	}
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/holders", handler.GetTopHolders)
+	r.Get("/tokens/{address}/holders/{holder_address}", handler.GetHolderBalance)
+
+	for _, tt := range []struct {
+		query    string
+		expected string
+	}{
+		{"", ""},
+		{"?include_percentage=true", "25.0000"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holders"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		var holders services.TopHoldersResponse
+		if err := json.NewDecoder(rec.Body).Decode(&holders); err != nil {
+			t.Fatalf("%q: failed to decode response: %v", tt.query, err)
+		}
+		if len(holders.Data) != 1 || holders.Data[0].Percentage != tt.expected {
+			t.Errorf("%q: expected top holder percentage %q, got %+v", tt.query, tt.expected, holders.Data)
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holders/"+testutil.AliceAddress+tt.query, nil)
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		var balance services.HolderBalanceResponse
+		if err := json.NewDecoder(rec.Body).Decode(&balance); err != nil {
+			t.Fatalf("%q: failed to decode response: %v", tt.query, err)
+		}
+		if balance.Data.Percentage != tt.expected {
+			t.Errorf("%q: expected holder percentage %q, got %q", tt.query, tt.expected, balance.Data.Percentage)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holders/"+testutil.AliceAddress+"?include_percentage=maybe", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid include_percentage, got %d", rec.Code)
+	}
+}
+
 func TestHoldersHandler_GetHolderBalance_Success(t *testing.T) {
 	handler, transferRepo, tokenRepo := setupHoldersHandlerTest()
 
@@ -568,11 +686,11 @@ func TestHoldersHandler_ResponseContentType(t *testing.T) {
 	))
 
 	// Setup mock holder count
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 0, nil
 	}
 
-	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+	transferRepo.GetTopHoldersWithOffsetFunc = func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 		return []repositories.HolderBalance{}, nil
 	}
 
@@ -589,3 +707,247 @@ func TestHoldersHandler_ResponseContentType(t *testing.T) {
 		t.Errorf("expected Content-Type application/json, got %s", contentType)
 	}
 }
+
+func TestHoldersHandler_GetHolderChanges(t *testing.T) {
+	handler, transferRepo, tokenRepo := setupHoldersHandlerTest()
+	snapshots := testutil.NewMockHolderSnapshotRepository()
+	handler.service.WithSnapshots(snapshots, 100)
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	_ = snapshots.Save(context.Background(), testutil.USDTAddress, time.Now().Add(-2*time.Hour), []repositories.HolderBalance{
+		{Address: testutil.AliceAddress, Balance: "500", Rank: 1},
+	})
+	transferRepo.GetTopHoldersFunc = func(ctx context.Context, tokenAddress string, limit int) ([]repositories.HolderBalance, error) {
+		return []repositories.HolderBalance{{Address: testutil.BobAddress, Balance: "500", Rank: 1}}, nil
+	}
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/holders/changes", handler.GetHolderChanges)
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holders/changes?since=1h&limit=5", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var response services.HolderChangesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Data.Entered) != 1 || response.Data.Entered[0].Address != testutil.BobAddress {
+		t.Errorf("expected Bob to enter, got %+v", response.Data.Entered)
+	}
+	if len(response.Data.Exited) != 1 || response.Data.Exited[0].Address != testutil.AliceAddress {
+		t.Errorf("expected Alice to exit, got %+v", response.Data.Exited)
+	}
+
+	// The balance changes query is capped by limit
+	for _, call := range transferRepo.Calls {
+		if call.Method == "GetBalanceChanges" && call.Args[2] != 5 {
+			t.Errorf("expected limit 5, got %v", call.Args[2])
+		}
+	}
+}
+
+func TestHoldersHandler_GetHolderChanges_Errors(t *testing.T) {
+	handler, _, tokenRepo := setupHoldersHandlerTest()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/holders/changes", handler.GetHolderChanges)
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"invalid address", "/tokens/0x123/holders/changes", http.StatusBadRequest},
+		{"invalid since", "/tokens/" + testutil.USDTAddress + "/holders/changes?since=yesterday", http.StatusBadRequest},
+		{"since too long", "/tokens/" + testutil.USDTAddress + "/holders/changes?since=1000h", http.StatusBadRequest},
+		{"no snapshot", "/tokens/" + testutil.USDTAddress + "/holders/changes", http.StatusNotFound},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.status, rec.Code)
+		}
+	}
+}
+
+func TestHoldersHandler_CompareHolderSnapshots(t *testing.T) {
+	handler, _, tokenRepo := setupHoldersHandlerTest()
+	snapshots := testutil.NewMockHolderSnapshotRepository()
+	handler.service.WithSnapshots(snapshots, 100)
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	_ = snapshots.Save(context.Background(), testutil.USDTAddress, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), []repositories.HolderBalance{
+		{Address: testutil.AliceAddress, Balance: "500", Rank: 1},
+	})
+	_ = snapshots.Save(context.Background(), testutil.USDTAddress, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC), []repositories.HolderBalance{
+		{Address: testutil.AliceAddress, Balance: "800", Rank: 1},
+	})
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/holders/compare", handler.CompareHolderSnapshots)
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holders/compare?from=2024-01-01&to=2024-02-01", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var response services.HolderComparisonResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Data.Changes) != 1 || response.Data.Changes[0].BalanceDelta != "300" {
+		t.Errorf("expected Alice's balance to grow by 300, got %+v", response.Data.Changes)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"invalid address", "/tokens/0x123/holders/compare?from=2024-01-01&to=2024-02-01", http.StatusBadRequest},
+		{"missing dates", "/tokens/" + testutil.USDTAddress + "/holders/compare", http.StatusBadRequest},
+		{"invalid date", "/tokens/" + testutil.USDTAddress + "/holders/compare?from=yesterday&to=2024-02-01", http.StatusBadRequest},
+		{"reversed dates", "/tokens/" + testutil.USDTAddress + "/holders/compare?from=2024-02-01&to=2024-01-01", http.StatusBadRequest},
+		{"unknown token", "/tokens/" + testutil.USDCAddress + "/holders/compare?from=2024-01-01&to=2024-02-01", http.StatusNotFound},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.status, rec.Code)
+		}
+	}
+}
+
+func TestHoldersHandler_GetHolderHistory(t *testing.T) {
+	handler, transferRepo, tokenRepo := setupHoldersHandlerTest()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithLogIndex(0), testutil.WithToAddress(testutil.AliceAddress)),
+		testutil.CreateTestTransfer(testutil.WithLogIndex(1), testutil.WithFromAddress(testutil.AliceAddress)),
+	)
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/holders/{holder_address}/history", handler.GetHolderHistory)
+
+	for _, tc := range []struct {
+		query   string
+		entries int
+		hasMore bool
+	}{
+		{"", 2, false},
+		{"?limit=1", 1, true},
+		{"?from_time=2030-01-01T00:00:00Z", 0, false},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holders/"+testutil.AliceAddress+"/history"+tc.query, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d", tc.query, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%q: expected JSON content type, got %s", tc.query, ct)
+		}
+
+		var response struct {
+			Data       []services.HolderHistoryEntryDTO `json:"data"`
+			Pagination services.HolderHistoryPagination `json:"pagination"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatalf("%q: failed to decode response: %v", tc.query, err)
+		}
+		if len(response.Data) != tc.entries || response.Pagination.HasMore != tc.hasMore {
+			t.Errorf("%q: expected %d entries (has_more %v), got %+v", tc.query, tc.entries, tc.hasMore, response)
+		}
+	}
+}
+
+func TestHoldersHandler_GetHolderHistory_Errors(t *testing.T) {
+	handler, transferRepo, tokenRepo := setupHoldersHandlerTest()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	transferRepo.StreamHolderHistoryFunc = func(ctx context.Context, tokenAddress, holderAddress string, filter repositories.HolderHistoryFilter, fn func(repositories.HolderBalanceEvent) error) error {
+		if holderAddress == testutil.BobAddress {
+			return errors.New("database error")
+		}
+		return nil
+	}
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/holders/{holder_address}/history", handler.GetHolderHistory)
+
+	for _, tc := range []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"invalid holder", "/tokens/" + testutil.USDTAddress + "/holders/0x123/history", http.StatusBadRequest},
+		{"invalid time", "/tokens/" + testutil.USDTAddress + "/holders/" + testutil.AliceAddress + "/history?to_time=yesterday", http.StatusBadRequest},
+		{"unknown token", "/tokens/" + testutil.USDCAddress + "/holders/" + testutil.AliceAddress + "/history", http.StatusNotFound},
+		{"service error", "/tokens/" + testutil.USDTAddress + "/holders/" + testutil.BobAddress + "/history", http.StatusInternalServerError},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.status, rec.Code)
+		}
+	}
+}
+
+func TestHoldersHandler_GetNegativeBalances(t *testing.T) {
+	handler, transferRepo, tokenRepo := setupHoldersHandlerTest()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	// Bob's incoming transfer was never indexed, so his outgoing ones leave him below zero
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithFromAddress(entities.ZeroAddress), testutil.WithToAddress(testutil.AliceAddress), testutil.WithValue(big.NewInt(100))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithFromAddress(testutil.BobAddress), testutil.WithToAddress(testutil.CharlieAddr), testutil.WithValue(big.NewInt(40))),
+	)
+
+	r := chi.NewRouter()
+	handler.RegisterAdminRoutes(r)
+
+	req := httptest.NewRequest("GET", "/admin/diagnostics/negative-balances", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response services.NegativeBalancesResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data) != 1 || response.Data[0].Count != 1 {
+		t.Fatalf("expected one token with one negative balance, got %+v", response.Data)
+	}
+	if worst := response.Data[0].Worst; len(worst) != 1 || worst[0].Address != testutil.BobAddress || worst[0].Balance != "-40" {
+		t.Errorf("expected Bob at -40, got %+v", worst)
+	}
+
+	for path, want := range map[string]int{
+		"/admin/diagnostics/negative-balances?token=" + testutil.USDCAddress: http.StatusNotFound,
+		"/admin/diagnostics/negative-balances?token=bad":                     http.StatusBadRequest,
+		"/admin/diagnostics/negative-balances?limit=1000":                    http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+}
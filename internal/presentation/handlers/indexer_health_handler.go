@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+// IndexerHealthSource defines the interface for components checking the indexer's own health
+type IndexerHealthSource interface {
+	CheckHealth(ctx context.Context) *services.IndexerHealth
+}
+
+// IndexerHealthHandler serves the indexer's health check on its metrics port
+type IndexerHealthHandler struct {
+	source IndexerHealthSource
+	db     HealthChecker
+	logger *zap.Logger
+}
+
+// NewIndexerHealthHandler creates a new indexer health handler
+func NewIndexerHealthHandler(source IndexerHealthSource, db HealthChecker, logger *zap.Logger) *IndexerHealthHandler {
+	return &IndexerHealthHandler{
+		source: source,
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Health handles GET /health, answering 503 when any check fails so orchestrators restart a
+// stuck indexer
+func (h *IndexerHealthHandler) Health(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	health := h.source.CheckHealth(ctx)
+	if err := h.db.HealthCheck(ctx); err != nil {
+		health.Fail("database", err.Error())
+	} else {
+		health.Checks["database"] = "healthy"
+	}
+
+	status := http.StatusOK
+	if health.Status != "healthy" {
+		status = http.StatusServiceUnavailable
+		h.logger.Warn("Indexer is unhealthy", zap.Any("checks", health.Checks))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(health)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+const (
+	// maxMethodNameLength matches the method_signatures.name column
+	maxMethodNameLength = 255
+	// maxMethodLabelLength matches the method_signatures.label column
+	maxMethodLabelLength = 64
+)
+
+// MethodSignatureHandler handles HTTP requests for the method signature table
+type MethodSignatureHandler struct {
+	service *services.MethodSignatureService
+	logger  *zap.Logger
+}
+
+// NewMethodSignatureHandler creates a new method signature handler
+func NewMethodSignatureHandler(service *services.MethodSignatureService, logger *zap.Logger) *MethodSignatureHandler {
+	return &MethodSignatureHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the method signature routes
+func (h *MethodSignatureHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/method-signatures", h.ListSignatures)
+	r.Put("/method-signatures/{selector}", h.SetSignature)
+}
+
+type setMethodSignatureRequest struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
+
+// ListSignatures handles GET /api/v1/method-signatures
+func (h *MethodSignatureHandler) ListSignatures(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.ListSignatures(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list method signatures", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list method signatures")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// SetSignature handles PUT /api/v1/method-signatures/{selector}
+func (h *MethodSignatureHandler) SetSignature(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	selector := chi.URLParam(r, "selector")
+
+	if !isValidSelector(selector) {
+		h.respondError(w, http.StatusBadRequest, "Invalid selector format")
+		return
+	}
+
+	var req setMethodSignatureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Label = strings.TrimSpace(req.Label)
+	if req.Name == "" || len(req.Name) > maxMethodNameLength {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("name is required and must be at most %d characters", maxMethodNameLength))
+		return
+	}
+	if req.Label == "" || len(req.Label) > maxMethodLabelLength {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("label is required and must be at most %d characters", maxMethodLabelLength))
+		return
+	}
+
+	response, err := h.service.SetSignature(ctx, strings.ToLower(selector), req.Name, req.Label)
+	if err != nil {
+		h.logger.Error("Failed to set method signature", zap.Error(err), zap.String("selector", selector))
+		h.respondError(w, http.StatusInternalServerError, "Failed to set method signature")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// isValidSelector reports whether s is a 0x-prefixed 4-byte hex selector
+func isValidSelector(s string) bool {
+	if len(s) != 10 || !strings.HasPrefix(s, "0x") {
+		return false
+	}
+	_, err := hex.DecodeString(s[2:])
+	return err == nil
+}
+
+func (h *MethodSignatureHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *MethodSignatureHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestMethodSignatureHandler_SetSignature(t *testing.T) {
+	logger := zap.NewNop()
+	handler := NewMethodSignatureHandler(services.NewMethodSignatureService(testutil.NewMockMethodSignatureRepository(), logger), logger)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	tests := []struct {
+		name           string
+		selector       string
+		body           string
+		expectedStatus int
+	}{
+		{"valid", "0xA9059CBB", `{"name":"transfer(address,uint256)","label":"transfer"}`, http.StatusOK},
+		{"short selector", "0xa9059c", `{"name":"transfer(address,uint256)","label":"transfer"}`, http.StatusBadRequest},
+		{"non-hex selector", "0xa9059cbz", `{"name":"transfer(address,uint256)","label":"transfer"}`, http.StatusBadRequest},
+		{"missing label", "0xa9059cbb", `{"name":"transfer(address,uint256)"}`, http.StatusBadRequest},
+		{"invalid body", "0xa9059cbb", `{`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/method-signatures/"+tt.selector, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/method-signatures", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var response services.MethodSignatureListResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data[0].Selector != "0xa9059cbb" {
+		t.Errorf("expected the lowercased selector to be stored, got %+v", response.Data)
+	}
+}
@@ -29,8 +29,10 @@ func NewPortfolioHandler(service *services.PortfolioService, logger *zap.Logger)
 func (h *PortfolioHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/wallets", func(r chi.Router) {
 		r.Get("/{address}/portfolio", h.GetPortfolio)
+		r.Get("/{address}/portfolio/history", h.GetPortfolioHistory)
 		r.Get("/{address}/portfolio/tokens/{tokenAddress}", h.GetTokenHolding)
 		r.Get("/{address}/summary", h.GetWalletSummary)
+		r.Get("/{address}/tokens", h.GetWalletTokens)
 	})
 }
 
@@ -46,8 +48,17 @@ func (h *PortfolioHandler) GetPortfolio(w http.ResponseWriter, r *http.Request)
 
 	address = strings.ToLower(address)
 
+	var q portfolioQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
 	response, err := h.service.GetPortfolio(ctx, address)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get portfolio",
 			zap.Error(err),
 			zap.String("address", address),
@@ -56,7 +67,63 @@ func (h *PortfolioHandler) GetPortfolio(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
+	if q.IncludeNative {
+		h.service.AddNativeBalance(ctx, response)
+	}
+	if q.IncludeUSD {
+		h.service.AddUSDValues(ctx, response)
+	}
+
+	setMaxAge(w, h.service.CacheTTL())
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
+}
+
+// portfolioQuery holds the query parameters of GET /api/v1/wallets/{address}/portfolio
+type portfolioQuery struct {
+	IncludeNative bool `query:"include_native"`
+	IncludeUSD    bool `query:"include_usd"`
+}
+
+// GetPortfolioHistory handles GET /api/v1/wallets/{address}/portfolio/history
+func (h *PortfolioHandler) GetPortfolioHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid wallet address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	if interval := r.URL.Query().Get("interval"); interval != "" && interval != services.HistoryIntervalDay {
+		h.respondError(w, http.StatusBadRequest, "Unsupported interval")
+		return
+	}
+
+	// Parse days parameter (default 30, max 365)
+	var q struct {
+		Days int `query:"days" default:"30" min:"1"`
+	}
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+	if q.Days > 365 {
+		q.Days = 365
+	}
+
+	response, err := h.service.GetPortfolioHistory(ctx, address, q.Days)
+	if err != nil {
+		h.logger.Error("Failed to get portfolio history",
+			zap.Error(err),
+			zap.String("address", address),
+		)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get portfolio history")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
 }
 
 // GetTokenHolding handles GET /api/v1/wallets/{address}/portfolio/tokens/{tokenAddress}
@@ -78,8 +145,17 @@ func (h *PortfolioHandler) GetTokenHolding(w http.ResponseWriter, r *http.Reques
 	walletAddress = strings.ToLower(walletAddress)
 	tokenAddress = strings.ToLower(tokenAddress)
 
+	withUSD, err := includeUSD(r)
+	if err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
 	response, err := h.service.GetPortfolioByToken(ctx, walletAddress, tokenAddress)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get token holding",
 			zap.Error(err),
 			zap.String("wallet", walletAddress),
@@ -94,6 +170,11 @@ func (h *PortfolioHandler) GetTokenHolding(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if withUSD {
+		h.service.AddHoldingUSDValue(ctx, response)
+	}
+
+	setMaxAge(w, h.service.CacheTTL())
 	h.respondJSON(w, http.StatusOK, response)
 }
 
@@ -111,6 +192,9 @@ func (h *PortfolioHandler) GetWalletSummary(w http.ResponseWriter, r *http.Reque
 
 	response, err := h.service.GetWalletSummary(ctx, address)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get wallet summary",
 			zap.Error(err),
 			zap.String("address", address),
@@ -122,6 +206,74 @@ func (h *PortfolioHandler) GetWalletSummary(w http.ResponseWriter, r *http.Reque
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+// GetWalletTokens handles GET /api/v1/wallets/{address}/tokens
+func (h *PortfolioHandler) GetWalletTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid wallet address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	response, err := h.service.GetWalletTokens(ctx, address)
+	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
+		h.logger.Error("Failed to get wallet tokens",
+			zap.Error(err),
+			zap.String("address", address),
+		)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get wallet tokens")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
+}
+
+// walletGraphQuery holds the query parameters of GET /api/v1/wallets/{address}/graph
+type walletGraphQuery struct {
+	Depth int `query:"depth" default:"1" min:"1" max:"2"`
+}
+
+// GetWalletGraph handles GET /api/v1/wallets/{address}/graph. It is registered by the caller
+// with the aggregation routes rather than under RegisterRoutes.
+func (h *PortfolioHandler) GetWalletGraph(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid wallet address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	var q walletGraphQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.GetWalletGraph(ctx, address, q.Depth)
+	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
+		h.logger.Error("Failed to get wallet graph",
+			zap.Error(err),
+			zap.String("address", address),
+		)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get wallet graph")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
 func (h *PortfolioHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -72,6 +74,83 @@ func TestPortfolioHandler_GetPortfolio(t *testing.T) {
 		}
 	})
 
+	t.Run("keeps only requested holding fields", func(t *testing.T) {
+		mockRepo := testutil.NewMockPortfolioRepository()
+		mockRepo.GetWalletHoldingsFunc = func(ctx context.Context, walletAddress string) ([]entities.TokenHolding, error) {
+			return []entities.TokenHolding{
+				{TokenAddress: testutil.USDTAddress, TokenSymbol: "USDT", Decimals: 6, BalanceStr: "1000000", BalanceHuman: "1"},
+			}, nil
+		}
+
+		r := chi.NewRouter()
+		r.Get("/wallets/{address}/portfolio", setupPortfolioHandler(mockRepo).GetPortfolio)
+
+		req := httptest.NewRequest("GET", "/wallets/0x1234567890123456789012345678901234567890/portfolio?fields=token_symbol,balance", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var response struct {
+			Data struct {
+				WalletAddress string                   `json:"wallet_address"`
+				Holdings      []map[string]interface{} `json:"holdings"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		if response.Data.WalletAddress == "" {
+			t.Error("expected wallet_address to be kept")
+		}
+		if len(response.Data.Holdings) != 1 || len(response.Data.Holdings[0]) != 2 || response.Data.Holdings[0]["balance"] != "1000000" {
+			t.Errorf("unexpected holdings: %v", response.Data.Holdings)
+		}
+	})
+
+	t.Run("includes native balance only when requested", func(t *testing.T) {
+		const wallet = "0x1234567890123456789012345678901234567890"
+		logger := zap.NewNop()
+		service := services.NewPortfolioService(testutil.NewMockPortfolioRepository(), nil, logger).
+			WithNativeBalances(testutil.NewMockNativeBalanceProvider(map[string]*big.Int{wallet: big.NewInt(1e18)}), 0)
+
+		r := chi.NewRouter()
+		r.Get("/wallets/{address}/portfolio", NewPortfolioHandler(service, logger).GetPortfolio)
+
+		for query, want := range map[string]bool{"": false, "?include_native=true": true} {
+			req := httptest.NewRequest("GET", "/wallets/"+wallet+"/portfolio"+query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var response services.PortfolioResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got := response.Data.NativeBalance != nil; got != want {
+				t.Errorf("query %q: expected native balance %v, got %v", query, want, got)
+			}
+			if want && response.Data.NativeBalance.BalanceFormatted != "1" {
+				t.Errorf("expected 1 ETH, got %s", response.Data.NativeBalance.BalanceFormatted)
+			}
+		}
+	})
+
+	t.Run("rejects non-boolean include flags", func(t *testing.T) {
+		handler := setupPortfolioHandler(testutil.NewMockPortfolioRepository())
+
+		r := chi.NewRouter()
+		r.Get("/wallets/{address}/portfolio", handler.GetPortfolio)
+
+		for _, query := range []string{"?include_native=2", "?include_usd=on"} {
+			req := httptest.NewRequest("GET", "/wallets/0x1234567890123456789012345678901234567890/portfolio"+query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("query %q: expected status 400, got %d", query, w.Code)
+			}
+		}
+	})
+
 	t.Run("returns error for invalid address", func(t *testing.T) {
 		mockRepo := testutil.NewMockPortfolioRepository()
 		handler := setupPortfolioHandler(mockRepo)
@@ -284,3 +363,214 @@ func TestPortfolioHandler_GetWalletSummary(t *testing.T) {
 		}
 	})
 }
+
+func TestPortfolioHandler_GetPortfolioHistory(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode int
+		expectedDays int
+	}{
+		{"defaults to 30 days", "", http.StatusOK, 30},
+		{"daily interval", "?interval=day&days=7", http.StatusOK, 7},
+		{"caps days", "?days=1000", http.StatusOK, 365},
+		{"rejects invalid days", "?days=abc", http.StatusBadRequest, 0},
+		{"rejects zero days", "?days=0", http.StatusBadRequest, 0},
+		{"rejects unsupported interval", "?interval=hour", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := setupPortfolioHandler(testutil.NewMockPortfolioRepository())
+
+			r := chi.NewRouter()
+			r.Get("/wallets/{address}/portfolio/history", handler.GetPortfolioHistory)
+
+			req := httptest.NewRequest("GET", "/wallets/0x1234567890123456789012345678901234567890/portfolio/history"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response services.PortfolioHistoryResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(response.Data.Points) != tt.expectedDays {
+				t.Errorf("expected %d points, got %d", tt.expectedDays, len(response.Data.Points))
+			}
+			if response.Data.Interval != "day" {
+				t.Errorf("expected interval day, got %q", response.Data.Interval)
+			}
+		})
+	}
+
+	t.Run("returns 400 for invalid address", func(t *testing.T) {
+		handler := setupPortfolioHandler(testutil.NewMockPortfolioRepository())
+
+		r := chi.NewRouter()
+		r.Get("/wallets/{address}/portfolio/history", handler.GetPortfolioHistory)
+
+		req := httptest.NewRequest("GET", "/wallets/invalid/portfolio/history", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestPortfolioHandler_GetWalletTokens(t *testing.T) {
+	t.Run("returns wallet tokens successfully", func(t *testing.T) {
+		mockRepo := testutil.NewMockPortfolioRepository()
+		mockRepo.GetWalletTokenActivityFunc = func(ctx context.Context, walletAddress string) ([]repositories.WalletTokenActivity, error) {
+			return []repositories.WalletTokenActivity{
+				{TokenAddress: testutil.USDTAddress, TokenSymbol: "USDT", Decimals: 6, Balance: "0", TransfersIn: 2, TransfersOut: 2},
+			}, nil
+		}
+
+		handler := setupPortfolioHandler(mockRepo)
+
+		r := chi.NewRouter()
+		r.Get("/wallets/{address}/tokens", handler.GetWalletTokens)
+
+		req := httptest.NewRequest("GET", "/wallets/0x1234567890123456789012345678901234567890/tokens", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var response services.WalletTokensResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		if len(response.Data.Tokens) != 1 || response.Data.Tokens[0].TransfersOut != 2 {
+			t.Errorf("unexpected tokens: %+v", response.Data.Tokens)
+		}
+	})
+
+	t.Run("returns error for invalid address", func(t *testing.T) {
+		handler := setupPortfolioHandler(testutil.NewMockPortfolioRepository())
+
+		r := chi.NewRouter()
+		r.Get("/wallets/{address}/tokens", handler.GetWalletTokens)
+
+		req := httptest.NewRequest("GET", "/wallets/invalid/tokens", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns error on service failure", func(t *testing.T) {
+		mockRepo := testutil.NewMockPortfolioRepository()
+		mockRepo.GetWalletTokenActivityFunc = func(ctx context.Context, walletAddress string) ([]repositories.WalletTokenActivity, error) {
+			return nil, errors.New("database error")
+		}
+		handler := setupPortfolioHandler(mockRepo)
+
+		r := chi.NewRouter()
+		r.Get("/wallets/{address}/tokens", handler.GetWalletTokens)
+
+		req := httptest.NewRequest("GET", "/wallets/0x1234567890123456789012345678901234567890/tokens", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("expected status 500, got %d", w.Code)
+		}
+	})
+}
+
+func TestPortfolioHandler_GetPortfolioCacheControl(t *testing.T) {
+	mockRepo := testutil.NewMockPortfolioRepository()
+	handler := setupPortfolioHandler(mockRepo)
+	handler.service.WithCacheTTL(90 * time.Second)
+
+	r := chi.NewRouter()
+	r.Get("/wallets/{address}/portfolio", handler.GetPortfolio)
+
+	req := httptest.NewRequest("GET", "/wallets/0x1234567890123456789012345678901234567890/portfolio", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "max-age=90" {
+		t.Errorf("expected Cache-Control max-age=90, got %q", got)
+	}
+}
+
+func TestPortfolioHandler_GetWalletGraph(t *testing.T) {
+	mockRepo := testutil.NewMockPortfolioRepository()
+	mockRepo.GetCounterpartyFlowsFunc = func(ctx context.Context, addresses []string, scanLimit, limit int) ([]repositories.CounterpartyFlow, error) {
+		if len(addresses) != 1 || addresses[0] != testutil.AliceAddress {
+			return nil, nil
+		}
+		return []repositories.CounterpartyFlow{
+			{FromAddress: testutil.AliceAddress, ToAddress: testutil.BobAddress, TokenAddress: testutil.USDTAddress, Transfers: 2, Volume: "10"},
+		}, nil
+	}
+
+	handler := setupPortfolioHandler(mockRepo)
+	r := chi.NewRouter()
+	r.Get("/wallets/{address}/graph", handler.GetWalletGraph)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"default depth", "", http.StatusOK},
+		{"depth 2", "?depth=2", http.StatusOK},
+		{"depth too deep", "?depth=3", http.StatusBadRequest},
+		{"depth zero", "?depth=0", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/wallets/"+testutil.AliceAddress+"/graph"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("expected status %d, got %d", tt.want, w.Code)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var response services.WalletGraphResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if len(response.Data.Nodes) != 2 || len(response.Data.Edges) != 1 {
+				t.Errorf("expected Alice, Bob and the flow between them, got %+v", response.Data)
+			}
+		})
+	}
+
+	t.Run("invalid address", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/wallets/0xinvalid/graph", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+// ScreeningHandler handles HTTP requests for deny list screening
+type ScreeningHandler struct {
+	service *services.ScreeningService
+	logger  *zap.Logger
+}
+
+// NewScreeningHandler creates a new screening handler
+func NewScreeningHandler(service *services.ScreeningService, logger *zap.Logger) *ScreeningHandler {
+	return &ScreeningHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the screening routes
+func (h *ScreeningHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/screening/{address}", h.ScreenAddress)
+}
+
+// ScreenAddress handles GET /api/v1/screening/{address}
+func (h *ScreeningHandler) ScreenAddress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	response, err := h.service.ScreenAddress(ctx, address)
+	if err != nil {
+		h.logger.Error("Failed to screen address", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to screen address")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func (h *ScreeningHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *ScreeningHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestScreeningHandler_ScreenAddress(t *testing.T) {
+	repo := testutil.NewMockDenyListRepository()
+	repo.AddDenyListEntries("ofac", testutil.CharlieAddr)
+
+	logger := zap.NewNop()
+	handler := NewScreeningHandler(services.NewScreeningService(repo, nil, logger), logger)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	t.Run("listed address", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/screening/0x3333333333333333333333333333333333333333", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		var response services.ScreeningResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if !response.Data.Listed {
+			t.Error("expected address to be listed")
+		}
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/screening/0x123", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
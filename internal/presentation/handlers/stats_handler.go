@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -37,8 +40,24 @@ func (h *StatsHandler) GetTokenStats(w http.ResponseWriter, r *http.Request) {
 
 	address = strings.ToLower(address)
 
+	// Parse the optional custom range (RFC 3339, to defaults to now and from to 24h before to)
+	var q statsRangeQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	withUSD, err := includeUSD(r)
+	if err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
 	response, err := h.service.GetTokenStats(ctx, address)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get token stats", zap.Error(err), zap.String("address", address))
 		h.respondError(w, http.StatusInternalServerError, "Failed to get token stats")
 		return
@@ -49,6 +68,218 @@ func (h *StatsHandler) GetTokenStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if q.From != nil || q.To != nil {
+		from, to := q.bounds(time.Now())
+		rangeStats, err := h.service.GetRangeStats(ctx, address, from, to)
+		if err != nil {
+			h.logger.Error("Failed to get range stats", zap.Error(err), zap.String("address", address))
+			h.respondError(w, http.StatusInternalServerError, "Failed to get token stats")
+			return
+		}
+		response.Data.Range = rangeStats
+	}
+
+	if q.ExcludeFlagged {
+		if err := h.service.ExcludeFlagged(ctx, response); err != nil {
+			h.logger.Error("Failed to exclude flagged transfers", zap.Error(err), zap.String("address", address))
+			h.respondError(w, http.StatusInternalServerError, "Failed to get token stats")
+			return
+		}
+	}
+
+	if withUSD {
+		h.service.AddUSDValues(ctx, response)
+	}
+
+	setMaxAge(w, h.service.CacheTTL())
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetActivityHeatmap handles GET /api/v1/tokens/{address}/activity/heatmap
+func (h *StatsHandler) GetActivityHeatmap(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	// Parse the window in days (default 30, max 365)
+	var q struct {
+		Days int `query:"days" default:"30" min:"1" max:"365"`
+	}
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.GetActivityHeatmap(ctx, address, q.Days)
+	if err != nil {
+		h.logger.Error("Failed to get activity heatmap", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get activity heatmap")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetActiveAddresses handles GET /api/v1/tokens/{address}/active-addresses
+func (h *StatsHandler) GetActiveAddresses(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	// Parse day range (YYYY-MM-DD, default last 7 days, at most 365 days)
+	var q dayRangeQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if q.To != nil {
+		to = *q.To
+	}
+	from := to.AddDate(0, 0, -6)
+	if q.From != nil {
+		from = *q.From
+	}
+	if from.After(to) {
+		respondValidationError(w, &ValidationError{Fields: []FieldError{{Field: "from", Message: "must not be after to"}}})
+		return
+	}
+	if earliest := to.AddDate(0, 0, -364); from.Before(earliest) {
+		from = earliest
+	}
+
+	response, err := h.service.GetActiveAddresses(ctx, address, from, to)
+	if err != nil {
+		h.logger.Error("Failed to get active addresses", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get active addresses")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	setMaxAge(w, h.service.CacheTTL())
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetNewHolders handles GET /api/v1/tokens/{address}/holders/new
+func (h *StatsHandler) GetNewHolders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	var q newHoldersQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.GetNewHolders(ctx, address, q.days, q.Limit)
+	if err != nil {
+		h.logger.Error("Failed to get new holders", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get new holders")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	setMaxAge(w, h.service.CacheTTL())
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetAdvancedStats handles GET /api/v1/tokens/{address}/stats/advanced
+func (h *StatsHandler) GetAdvancedStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	response, err := h.service.GetAdvancedStats(ctx, address)
+	if errors.Is(err, services.ErrNoAdvancedStats) {
+		h.respondError(w, http.StatusNotFound, "advanced stats not computed yet")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get advanced stats", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get advanced stats")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	setMaxAge(w, h.service.CacheTTL())
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetTokenFlows handles GET /api/v1/tokens/{address}/flows
+func (h *StatsHandler) GetTokenFlows(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	// Parse the category and the optional range (RFC 3339, to defaults to now and from to 7 days before to)
+	var q tokenFlowsQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+	from, to := q.bounds(time.Now())
+
+	response, err := h.service.GetTokenFlows(ctx, address, q.Category, from, to)
+	if err != nil {
+		h.logger.Error("Failed to get token flows", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get token flows")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	setMaxAge(w, h.service.CacheTTL())
 	h.respondJSON(w, http.StatusOK, response)
 }
 
@@ -64,7 +295,13 @@ func (h *StatsHandler) GetHolderCount(w http.ResponseWriter, r *http.Request) {
 
 	address = strings.ToLower(address)
 
-	response, err := h.service.GetHolderCount(ctx, address)
+	var q holderCountQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.GetHolderCount(ctx, address, q.IncludeExcluded, q.Exact)
 	if err != nil {
 		h.logger.Error("Failed to get holder count", zap.Error(err), zap.String("address", address))
 		h.respondError(w, http.StatusInternalServerError, "Failed to get holder count")
@@ -79,6 +316,108 @@ func (h *StatsHandler) GetHolderCount(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+// holderCountQuery holds the holder count query parameters
+type holderCountQuery struct {
+	exclusionQuery
+	Exact bool `query:"exact"`
+}
+
+// statsRangeQuery holds the optional custom time range of token stats
+type statsRangeQuery struct {
+	From *time.Time `query:"from"`
+	To   *time.Time `query:"to"`
+
+	// Leave transfers flagged by the analysis pass, such as wash trades, out of counts and volumes
+	ExcludeFlagged bool `query:"exclude_flagged"`
+}
+
+// bounds returns the requested range, filling in to as now and from as 24 hours before to
+func (q *statsRangeQuery) bounds(now time.Time) (time.Time, time.Time) {
+	to := now
+	if q.To != nil {
+		to = *q.To
+	}
+	from := to.Add(-24 * time.Hour)
+	if q.From != nil {
+		from = *q.From
+	}
+	return from, to
+}
+
+func (q *statsRangeQuery) validate() []FieldError {
+	if q.From == nil && q.To == nil {
+		return nil
+	}
+
+	from, to := q.bounds(time.Now())
+	switch {
+	case to.Sub(from) < time.Minute:
+		return []FieldError{{Field: "to", Message: "must be at least 1 minute after from"}}
+	case to.Sub(from) > services.MaxStatsRange:
+		return []FieldError{{Field: "to", Message: "must be at most 366 days after from"}}
+	}
+	return nil
+}
+
+// tokenFlowsQuery holds the entity category and optional time range of token flows
+type tokenFlowsQuery struct {
+	Category string     `query:"category" oneof:"exchange bridge"`
+	From     *time.Time `query:"from"`
+	To       *time.Time `query:"to"`
+}
+
+// bounds returns the requested range, filling in to as now and from as 7 days before to
+func (q *tokenFlowsQuery) bounds(now time.Time) (time.Time, time.Time) {
+	to := now
+	if q.To != nil {
+		to = *q.To
+	}
+	from := to.AddDate(0, 0, -7)
+	if q.From != nil {
+		from = *q.From
+	}
+	return from, to
+}
+
+func (q *tokenFlowsQuery) validate() []FieldError {
+	if q.Category == "" {
+		return []FieldError{{Field: "category", Message: "is required"}}
+	}
+
+	from, to := q.bounds(time.Now())
+	switch {
+	case to.Sub(from) < time.Minute:
+		return []FieldError{{Field: "to", Message: "must be at least 1 minute after from"}}
+	case to.Sub(from) > services.MaxStatsRange:
+		return []FieldError{{Field: "to", Message: "must be at most 366 days after from"}}
+	}
+	return nil
+}
+
+// newHoldersQuery holds the new holders query parameters: a window of 1d to 365d ending today
+// and the number of newest holders listed
+type newHoldersQuery struct {
+	Window string `query:"window" default:"7d"`
+	Limit  int    `query:"limit" default:"20" min:"1" max:"100"`
+
+	days int // Parsed from Window
+}
+
+func (q *newHoldersQuery) validate() []FieldError {
+	days, err := strconv.Atoi(strings.TrimSuffix(q.Window, "d"))
+	if err != nil || !strings.HasSuffix(q.Window, "d") || days < 1 || days > 365 {
+		return []FieldError{{Field: "window", Message: "must be a number of days from 1d to 365d"}}
+	}
+	q.days = days
+	return nil
+}
+
+// dayRangeQuery holds an optional from/to range of UTC days
+type dayRangeQuery struct {
+	From *time.Time `query:"from" format:"date"`
+	To   *time.Time `query:"to" format:"date"`
+}
+
 func (h *StatsHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
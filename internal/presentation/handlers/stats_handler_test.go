@@ -315,7 +315,7 @@ func TestStatsHandler_GetHolderCount_Success(t *testing.T) {
 	))
 
 	// Setup mock holder count response
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 4523891, nil
 	}
 
@@ -345,6 +345,22 @@ func TestStatsHandler_GetHolderCount_Success(t *testing.T) {
 	}
 }
 
+func TestStatsHandler_GetHolderCount_InvalidExact(t *testing.T) {
+	handler, _, tokenRepo := setupStatsHandlerTest()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/holder-count", handler.GetHolderCount)
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holder-count?exact=maybe", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rec.Code)
+	}
+}
+
 func TestStatsHandler_GetHolderCount_NotFound(t *testing.T) {
 	handler, _, _ := setupStatsHandlerTest()
 
@@ -409,7 +425,7 @@ func TestStatsHandler_GetHolderCount_UppercaseAddress(t *testing.T) {
 		testutil.TokenWithAddress(testutil.USDTAddress),
 	))
 
-	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string) (int64, error) {
+	transferRepo.GetHolderCountFunc = func(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 		return 1000, nil
 	}
 
@@ -461,3 +477,310 @@ func TestStatsHandler_GetHolderCount_ServiceError(t *testing.T) {
 		t.Errorf("unexpected error message: %s", response["error"])
 	}
 }
+
+func TestStatsHandler_GetActiveAddresses(t *testing.T) {
+	transferRepo := testutil.NewMockTransferRepository()
+	tokenRepo := testutil.NewMockTokenRepository()
+	activeAddrs := testutil.NewMockActiveAddressRepository()
+	logger := zap.NewNop()
+
+	service := services.NewStatsService(transferRepo, tokenRepo, nil, logger).WithActiveAddresses(activeAddrs)
+	handler := NewStatsHandler(service, logger)
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/active-addresses", handler.GetActiveAddresses)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedFrom string
+		expectedTo   string
+	}{
+		{"defaults to last 7 days", "/tokens/" + testutil.USDTAddress + "/active-addresses", http.StatusOK,
+			today.AddDate(0, 0, -6).Format("2006-01-02"), today.Format("2006-01-02")},
+		{"explicit range", "/tokens/" + testutil.USDTAddress + "/active-addresses?from=2024-01-01&to=2024-01-31", http.StatusOK,
+			"2024-01-01", "2024-01-31"},
+		{"caps range at 365 days", "/tokens/" + testutil.USDTAddress + "/active-addresses?from=2020-01-01&to=2024-12-31", http.StatusOK,
+			"2024-01-02", "2024-12-31"},
+		{"rejects from after to", "/tokens/" + testutil.USDTAddress + "/active-addresses?from=2024-02-01&to=2024-01-31", http.StatusBadRequest, "", ""},
+		{"rejects malformed date", "/tokens/" + testutil.USDTAddress + "/active-addresses?to=31-01-2024", http.StatusBadRequest, "", ""},
+		{"invalid address", "/tokens/invalid/active-addresses", http.StatusBadRequest, "", ""},
+		{"unknown token", "/tokens/" + testutil.USDCAddress + "/active-addresses", http.StatusNotFound, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response services.ActiveAddressesResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Data.From != tt.expectedFrom || response.Data.To != tt.expectedTo {
+				t.Errorf("expected range %s..%s, got %s..%s", tt.expectedFrom, tt.expectedTo, response.Data.From, response.Data.To)
+			}
+		})
+	}
+}
+
+func TestStatsHandler_GetTokenStats_Range(t *testing.T) {
+	handler, transferRepo, tokenRepo := setupStatsHandlerTest()
+	tokenRepo.AddToken(testutil.CreateTestToken())
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithBlockTimestamp(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))),
+	)
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/stats", handler.GetTokenStats)
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedCode int
+		expectRange  bool
+	}{
+		{"no range", "", http.StatusOK, false},
+		{"explicit range", "?from=2024-01-15T00:00:00Z&to=2024-01-16T00:00:00Z", http.StatusOK, true},
+		{"malformed from", "?from=yesterday", http.StatusBadRequest, false},
+		{"inverted range", "?from=2024-01-16T00:00:00Z&to=2024-01-15T00:00:00Z", http.StatusBadRequest, false},
+		{"range too long", "?from=2022-01-01T00:00:00Z&to=2024-01-01T00:00:00Z", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/stats"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response services.TokenStatsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if (response.Data.Range != nil) != tt.expectRange {
+				t.Fatalf("expected range %v, got %+v", tt.expectRange, response.Data.Range)
+			}
+			if tt.expectRange && response.Data.Range.Transfers != 1 {
+				t.Errorf("expected 1 transfer in range, got %d", response.Data.Range.Transfers)
+			}
+		})
+	}
+}
+
+func TestStatsHandler_GetActivityHeatmap(t *testing.T) {
+	handler, transferRepo, tokenRepo := setupStatsHandlerTest()
+	tokenRepo.AddToken(testutil.CreateTestToken())
+
+	var capturedFrom, capturedTo time.Time
+	transferRepo.GetActivityHeatmapFunc = func(ctx context.Context, tokenAddress string, from, to time.Time) ([]repositories.HeatmapBucket, error) {
+		capturedFrom, capturedTo = from, to
+		return []repositories.HeatmapBucket{{DayOfWeek: 1, Hour: 14, Transfers: 5}}, nil
+	}
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/activity/heatmap", handler.GetActivityHeatmap)
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedDays int
+	}{
+		{"defaults to 30 days", "/tokens/" + testutil.USDTAddress + "/activity/heatmap", http.StatusOK, 30},
+		{"explicit window", "/tokens/" + testutil.USDTAddress + "/activity/heatmap?days=7", http.StatusOK, 7},
+		{"window too long", "/tokens/" + testutil.USDTAddress + "/activity/heatmap?days=400", http.StatusBadRequest, 0},
+		{"invalid address", "/tokens/invalid/activity/heatmap", http.StatusBadRequest, 0},
+		{"unknown token", "/tokens/" + testutil.USDCAddress + "/activity/heatmap", http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var response services.ActivityHeatmapResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Data.Counts[1][14] != 5 || response.Data.TotalTransfers != 5 {
+				t.Errorf("unexpected heatmap %+v", response.Data)
+			}
+			if days := int(capturedTo.Sub(capturedFrom).Hours() / 24); days != tt.expectedDays {
+				t.Errorf("expected a %d day window, got %d", tt.expectedDays, days)
+			}
+		})
+	}
+}
+
+func TestStatsHandler_GetNewHolders_Window(t *testing.T) {
+	handler, _, tokenRepo := setupStatsHandlerTest()
+	handler.service.WithDailyStats(testutil.NewMockDailyStatsRepository())
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/holders/new", handler.GetNewHolders)
+
+	tests := []struct {
+		query    string
+		want     int
+		wantDays int
+	}{
+		{"", http.StatusOK, 7},
+		{"?window=30d", http.StatusOK, 30},
+		{"?window=0d", http.StatusBadRequest, 0},
+		{"?window=366d", http.StatusBadRequest, 0},
+		{"?window=7", http.StatusBadRequest, 0},
+		{"?window=1w", http.StatusBadRequest, 0},
+		{"?limit=101", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress+"/holders/new"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%q: expected status %d, got %d", tt.query, tt.want, rec.Code)
+			continue
+		}
+		if tt.want == http.StatusOK {
+			var response services.NewHoldersResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil || len(response.Data.Days) != tt.wantDays {
+				t.Errorf("%q: expected %d days, got %+v (%v)", tt.query, tt.wantDays, response.Data, err)
+			}
+		}
+	}
+}
+
+func TestStatsHandler_GetAdvancedStats(t *testing.T) {
+	handler, _, tokenRepo := setupStatsHandlerTest()
+	advanced := testutil.NewMockAdvancedStatsRepository()
+	handler.service.WithAdvancedStats(advanced)
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/stats/advanced", handler.GetAdvancedStats)
+
+	get := func(address string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/tokens/"+address+"/stats/advanced", nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Not computed yet
+	if rec := get(testutil.USDTAddress); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 before the first aggregation, got %d", rec.Code)
+	}
+
+	now := time.Now().UTC()
+	if err := advanced.Save(context.Background(), repositories.AdvancedStats{
+		TokenAddress:      testutil.USDTAddress,
+		WindowFrom:        now.AddDate(0, 0, -30),
+		WindowTo:          now,
+		Volume:            "500",
+		AverageSupply:     "1000",
+		CirculatingSupply: "1000",
+		InactiveSince:     now.AddDate(-1, 0, 0),
+		DormantSupply:     "250",
+		DormantHolders:    3,
+		ComputedAt:        now,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := get(testutil.USDTAddress)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var response services.AdvancedStatsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Data.Velocity != "0.5000" || response.Data.DormantPercentage != "25.0000" || response.Data.DormantHolders != 3 {
+		t.Errorf("unexpected advanced stats: %+v", response.Data)
+	}
+
+	if rec := get(testutil.USDCAddress); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown token, got %d", rec.Code)
+	}
+	if rec := get("0xinvalid"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid address, got %d", rec.Code)
+	}
+}
+
+func TestStatsHandler_GetTokenFlows(t *testing.T) {
+	handler, _, tokenRepo := setupStatsHandlerTest()
+	entityRepo := testutil.NewMockEntityRepository()
+	handler.service.WithEntities(entityRepo)
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	var category string
+	var window time.Duration
+	entityRepo.GetCategoryFlowsFunc = func(ctx context.Context, tokenAddress, c string, from, to time.Time) ([]repositories.EntityFlow, error) {
+		category, window = c, to.Sub(from)
+		return []repositories.EntityFlow{{EntityID: 1, Name: "bridge", TransfersIn: 1, VolumeIn: "1000000", VolumeOut: "0"}}, nil
+	}
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}/flows", handler.GetTokenFlows)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/tokens/" + testutil.USDTAddress + "/flows?category=Bridge")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var response services.TokenFlowsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if category != "bridge" || window != 7*24*time.Hour || response.Data.NetFlowFormatted != "1" {
+		t.Errorf("expected bridge flows over the last 7 days, got %q over %s: %+v", category, window, response.Data)
+	}
+
+	for _, query := range []string{"", "?category=dex", "?category=exchange&from=2024-01-02T00:00:00Z&to=2024-01-01T00:00:00Z"} {
+		if rec := get("/tokens/" + testutil.USDTAddress + "/flows" + query); rec.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for %q, got %d", query, rec.Code)
+		}
+	}
+
+	if rec := get("/tokens/" + testutil.USDCAddress + "/flows?category=exchange"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown token, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+// StatusProvider defines the interface for components reporting indexer status
+type StatusProvider interface {
+	GetStatus(ctx context.Context) (*services.IndexerStatus, error)
+}
+
+// StatusHandler handles indexer status requests
+type StatusHandler struct {
+	provider StatusProvider
+	logger   *zap.Logger
+}
+
+// NewStatusHandler creates a new status handler
+func NewStatusHandler(provider StatusProvider, logger *zap.Logger) *StatusHandler {
+	return &StatusHandler{
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+// Status handles GET /status
+func (h *StatusHandler) Status(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	status, err := h.provider.GetStatus(ctx)
+	if err != nil {
+		h.logger.Error("Failed to get indexer status", zap.Error(err))
+		h.respondError(w, http.StatusServiceUnavailable, "Failed to get indexer status")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, status)
+}
+
+func (h *StatusHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *StatusHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+type mockStatusProvider struct {
+	status *services.IndexerStatus
+	err    error
+}
+
+func (m *mockStatusProvider) GetStatus(ctx context.Context) (*services.IndexerStatus, error) {
+	return m.status, m.err
+}
+
+func TestStatusHandler_Status_Success(t *testing.T) {
+	lag := int64(144)
+	provider := &mockStatusProvider{
+		status: &services.IndexerStatus{
+			ChainHead: 19000100,
+			Tokens: []services.TokenStatus{
+				{
+					TokenAddress:     testutil.USDTAddress,
+					LastIndexedBlock: 19000088,
+					ChainHead:        19000100,
+					LagBlocks:        12,
+					LagSeconds:       &lag,
+				},
+			},
+		},
+	}
+	handler := NewStatusHandler(provider, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Status(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+
+	var response services.IndexerStatus
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(response.Tokens) != 1 {
+		t.Fatalf("expected 1 token, got %d", len(response.Tokens))
+	}
+	if response.Tokens[0].LagBlocks != 12 {
+		t.Errorf("expected lag 12 blocks, got %d", response.Tokens[0].LagBlocks)
+	}
+	if response.Tokens[0].LagSeconds == nil || *response.Tokens[0].LagSeconds != 144 {
+		t.Errorf("expected lag 144 seconds, got %v", response.Tokens[0].LagSeconds)
+	}
+}
+
+func TestStatusHandler_Status_Error(t *testing.T) {
+	provider := &mockStatusProvider{err: errors.New("rpc unavailable")}
+	handler := NewStatusHandler(provider, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Status(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", rec.Code)
+	}
+
+	var response map[string]string
+	json.NewDecoder(rec.Body).Decode(&response)
+	if response["error"] != "Failed to get indexer status" {
+		t.Errorf("unexpected error message: %s", response["error"])
+	}
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// streamBatchSize caps how many transfers one poll reads; a backlog is drained over consecutive reads
+const streamBatchSize = 100
+
+// StreamHandler serves live transfer feeds as Server-Sent Events
+type StreamHandler struct {
+	service           *services.TransferService
+	pollInterval      time.Duration
+	heartbeatInterval time.Duration
+	logger            *zap.Logger
+
+	// Streams waiting for new transfers, woken by WithIndexedTokens
+	waitersMu sync.Mutex
+	waiters   map[*streamWaiter]struct{}
+}
+
+// streamWaiter wakes one stream when its token, or any token if empty, gets new transfers
+type streamWaiter struct {
+	token string
+	wake  chan struct{}
+}
+
+// NewStreamHandler creates a new stream handler.
+// New transfers are polled from the database every pollInterval, and a comment line is sent
+// every heartbeatInterval so proxies keep idle connections open.
+func NewStreamHandler(service *services.TransferService, pollInterval, heartbeatInterval time.Duration, logger *zap.Logger) *StreamHandler {
+	return &StreamHandler{
+		service:           service,
+		pollInterval:      pollInterval,
+		heartbeatInterval: heartbeatInterval,
+		logger:            logger,
+	}
+}
+
+// WithIndexedTokens wakes streams as soon as a token address arrives on tokens rather than at
+// their next poll, which remains the fallback for missed announcements
+func (h *StreamHandler) WithIndexedTokens(tokens <-chan string) *StreamHandler {
+	h.waiters = make(map[*streamWaiter]struct{})
+	go func() {
+		for token := range tokens {
+			token = strings.ToLower(token)
+			h.waitersMu.Lock()
+			for waiter := range h.waiters {
+				if waiter.token != "" && waiter.token != token {
+					continue
+				}
+				select {
+				case waiter.wake <- struct{}{}:
+				default:
+				}
+			}
+			h.waitersMu.Unlock()
+		}
+	}()
+	return h
+}
+
+// addWaiter registers a stream for wakeups, returning a nil channel without WithIndexedTokens
+func (h *StreamHandler) addWaiter(token string) (<-chan struct{}, func()) {
+	if h.waiters == nil {
+		return nil, func() {}
+	}
+	waiter := &streamWaiter{token: token, wake: make(chan struct{}, 1)}
+	h.waitersMu.Lock()
+	h.waiters[waiter] = struct{}{}
+	h.waitersMu.Unlock()
+	return waiter.wake, func() {
+		h.waitersMu.Lock()
+		delete(h.waiters, waiter)
+		h.waitersMu.Unlock()
+	}
+}
+
+// RegisterRoutes registers the stream routes
+func (h *StreamHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/stream/transfers", h.StreamTransfers)
+}
+
+// StreamTransfers handles GET /api/v1/stream/transfers.
+// Each event carries the transfer's ID; a reconnecting client sends it back in Last-Event-ID
+// (or ?last_event_id=) to resume without gaps. Without one the stream starts at the newest transfer.
+func (h *StreamHandler) StreamTransfers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter := entities.DefaultTransferFilter()
+	filter.Limit = streamBatchSize
+	if v := r.URL.Query().Get("token"); v != "" {
+		if !isValidAddress(v) {
+			h.respondError(w, http.StatusBadRequest, "Invalid token address format")
+			return
+		}
+		addr := strings.ToLower(v)
+		filter.TokenAddress = &addr
+	}
+	if v := r.URL.Query().Get("address"); v != "" {
+		if !isValidAddress(v) {
+			h.respondError(w, http.StatusBadRequest, "Invalid address format")
+			return
+		}
+		addr := strings.ToLower(v)
+		filter.Address = &addr
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	var lastID int64
+	if lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || id < 0 {
+			h.respondError(w, http.StatusBadRequest, "Invalid Last-Event-ID")
+			return
+		}
+		lastID = id
+	} else {
+		id, err := h.service.LatestTransferID(ctx)
+		if err != nil {
+			h.logger.Error("Failed to start transfer stream", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to start transfer stream")
+			return
+		}
+		lastID = id
+	}
+
+	// The server's write timeout would otherwise cut the stream off
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		h.logger.Error("Transfer stream requires a flushable response writer", zap.Error(err))
+		return
+	}
+
+	poll := time.NewTicker(h.pollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+	var token string
+	if filter.TokenAddress != nil {
+		token = *filter.TokenAddress
+	}
+	wake, removeWaiter := h.addWaiter(token)
+	defer removeWaiter()
+
+	// sendNew writes every matching transfer inserted after lastID
+	sendNew := func() error {
+		for {
+			events, err := h.service.GetTransfersAfter(ctx, filter, lastID)
+			if err != nil {
+				// Transient database errors should not drop the client; the next poll retries
+				h.logger.Warn("Failed to poll transfer stream", zap.Error(err))
+				return nil
+			}
+			for _, event := range events {
+				data, err := json.Marshal(event.Transfer)
+				if err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(w, "id: %d\nevent: transfer\ndata: %s\n\n", event.ID, data); err != nil {
+					return err
+				}
+				lastID = event.ID
+			}
+			if len(events) > 0 {
+				if err := rc.Flush(); err != nil {
+					return err
+				}
+			}
+			if len(events) < streamBatchSize {
+				return nil
+			}
+		}
+	}
+
+	// Catch up immediately on resume instead of waiting for the first tick
+	if err := sendNew(); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			if err := sendNew(); err != nil {
+				return
+			}
+		case <-wake:
+			if err := sendNew(); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *StreamHandler) respondError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func setupStreamServer(t *testing.T, transferRepo *testutil.MockTransferRepository) *httptest.Server {
+	logger := zap.NewNop()
+	service := services.NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, logger)
+	handler := NewStreamHandler(service, 10*time.Millisecond, 20*time.Millisecond, logger)
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// readEvents collects event IDs until n transfers and a heartbeat have been seen
+func readEvents(t *testing.T, resp *http.Response, n int) []string {
+	t.Helper()
+
+	var ids []string
+	heartbeat := false
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			ids = append(ids, strings.TrimPrefix(line, "id: "))
+		case line == ": heartbeat":
+			heartbeat = true
+		}
+		if len(ids) >= n && heartbeat {
+			return ids
+		}
+	}
+	t.Fatalf("stream ended after events %v (heartbeat %v): %v", ids, heartbeat, scanner.Err())
+	return nil
+}
+
+func TestStreamHandler_StreamTransfers(t *testing.T) {
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithLogIndex(1)),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithLogIndex(2)),
+		testutil.CreateTestTransfer(testutil.WithID(3), testutil.WithLogIndex(3), testutil.WithTokenAddress(testutil.USDCAddress)),
+	)
+	server := setupStreamServer(t, transferRepo)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Resume after ID 1: transfer 2 is replayed, transfer 3 is another token, and 4 arrives live
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream/transfers?token="+testutil.USDTAddress, nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	transferRepo.AddTransfers(testutil.CreateTestTransfer(testutil.WithID(4), testutil.WithLogIndex(4)))
+
+	ids := readEvents(t, resp, 2)
+	if len(ids) != 2 || ids[0] != "2" || ids[1] != "4" {
+		t.Errorf("expected events 2 and 4, got %v", ids)
+	}
+}
+
+func TestStreamHandler_StartsAtNewestTransfer(t *testing.T) {
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(testutil.CreateTestTransfer(testutil.WithID(7)))
+	server := setupStreamServer(t, transferRepo)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream/transfers", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	transferRepo.AddTransfers(testutil.CreateTestTransfer(testutil.WithID(8), testutil.WithLogIndex(8)))
+
+	if ids := readEvents(t, resp, 1); ids[0] != "8" {
+		t.Errorf("expected only the new transfer, got %v", ids)
+	}
+}
+
+func TestStreamHandler_Validation(t *testing.T) {
+	server := setupStreamServer(t, testutil.NewMockTransferRepository())
+
+	for _, path := range []string{
+		"/stream/transfers?token=0x123",
+		"/stream/transfers?address=0x123",
+		"/stream/transfers?last_event_id=abc",
+	} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestStreamHandler_WakesOnIndexedTokens(t *testing.T) {
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithLogIndex(1)))
+
+	// Polls never come, so only the announcement can deliver the live transfer
+	logger := zap.NewNop()
+	service := services.NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, logger)
+	tokens := make(chan string, 1)
+	defer close(tokens)
+	handler := NewStreamHandler(service, time.Hour, time.Hour, logger).WithIndexedTokens(tokens)
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream/transfers?token="+testutil.USDTAddress, nil)
+	req.Header.Set("Last-Event-ID", "0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for _, want := range []string{"1", "2"} {
+		for scanner.Scan() && !strings.HasPrefix(scanner.Text(), "id: ") {
+		}
+		if got := strings.TrimPrefix(scanner.Text(), "id: "); got != want {
+			t.Fatalf("expected event %s, got %q (%v)", want, got, scanner.Err())
+		}
+		if want == "1" {
+			transferRepo.AddTransfers(testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithLogIndex(2)))
+			tokens <- strings.ToUpper(testutil.USDTAddress)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+// SwapHandler handles HTTP requests for DEX swap endpoints
+type SwapHandler struct {
+	service *services.SwapService
+	logger  *zap.Logger
+	pages   PageLimits
+}
+
+// NewSwapHandler creates a new swap handler
+func NewSwapHandler(service *services.SwapService, logger *zap.Logger) *SwapHandler {
+	return &SwapHandler{
+		service: service,
+		logger:  logger,
+		pages:   DefaultPageLimits,
+	}
+}
+
+// WithPageLimits sets the endpoints' default and maximum page sizes
+func (h *SwapHandler) WithPageLimits(pages PageLimits) *SwapHandler {
+	h.pages = pages
+	return h
+}
+
+// RegisterRoutes registers the swap routes
+func (h *SwapHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/pools/{address}/swaps", h.GetPoolSwaps)
+	r.Get("/tokens/{address}/swap-volume", h.GetTokenSwapVolume)
+}
+
+// GetPoolSwaps handles GET /api/v1/pools/{address}/swaps
+func (h *SwapHandler) GetPoolSwaps(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid pool address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	// Parse limit (configured default and maximum) and offset (default 0)
+	page, err := h.pages.parsePage(r, "swaps")
+	if err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.GetPoolSwaps(ctx, address, page.Limit, page.Offset)
+	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
+		h.logger.Error("Failed to get pool swaps", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get pool swaps")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "pool not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetTokenSwapVolume handles GET /api/v1/tokens/{address}/swap-volume
+func (h *SwapHandler) GetTokenSwapVolume(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	response, err := h.service.GetTokenSwapVolume(ctx, address)
+	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
+		h.logger.Error("Failed to get swap volume", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get swap volume")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func (h *SwapHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *SwapHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+const testPoolAddress = "0x0d4a11d5eeaac28ec3f61d100daf4d40471f1852"
+
+func setupSwapRouter(mockRepo *testutil.MockSwapRepository) *chi.Mux {
+	logger := zap.NewNop()
+	handler := NewSwapHandler(services.NewSwapService(mockRepo, nil, logger), logger)
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	return r
+}
+
+func TestSwapHandler_GetPoolSwaps(t *testing.T) {
+	t.Run("returns swaps", func(t *testing.T) {
+		mockRepo := testutil.NewMockSwapRepository()
+		_ = mockRepo.UpsertPool(context.Background(), &entities.DexPool{
+			Address:       testPoolAddress,
+			Token0Address: testutil.USDCAddress,
+			Token1Address: testutil.USDTAddress,
+		})
+		mockRepo.AddSwap(entities.Swap{PoolAddress: testPoolAddress, Amount0String: "5", Amount1String: "-4"})
+		mockRepo.AddSwap(entities.Swap{PoolAddress: testPoolAddress, Amount0String: "-3", Amount1String: "2"})
+
+		req := httptest.NewRequest("GET", "/pools/"+testPoolAddress+"/swaps?limit=1", nil)
+		w := httptest.NewRecorder()
+		setupSwapRouter(mockRepo).ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var response services.SwapResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(response.Swaps) != 1 || response.Total != 2 || !response.HasMore {
+			t.Errorf("unexpected pagination: %d swaps, total %d, has_more %v", len(response.Swaps), response.Total, response.HasMore)
+		}
+	})
+
+	t.Run("returns 404 for unknown pool", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/pools/"+testPoolAddress+"/swaps", nil)
+		w := httptest.NewRecorder()
+		setupSwapRouter(testutil.NewMockSwapRepository()).ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns 400 for invalid address", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/pools/invalid/swaps", nil)
+		w := httptest.NewRecorder()
+		setupSwapRouter(testutil.NewMockSwapRepository()).ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("returns 500 on service error", func(t *testing.T) {
+		mockRepo := testutil.NewMockSwapRepository()
+		mockRepo.GetPoolFunc = func(ctx context.Context, address string) (*entities.DexPool, error) {
+			return nil, errors.New("database error")
+		}
+
+		req := httptest.NewRequest("GET", "/pools/"+testPoolAddress+"/swaps", nil)
+		w := httptest.NewRecorder()
+		setupSwapRouter(mockRepo).ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("expected status 500, got %d", w.Code)
+		}
+	})
+}
+
+func TestSwapHandler_GetTokenSwapVolume(t *testing.T) {
+	t.Run("returns volume", func(t *testing.T) {
+		mockRepo := testutil.NewMockSwapRepository()
+		mockRepo.GetTokenSwapVolumeFunc = func(ctx context.Context, tokenAddress string) (*repositories.SwapVolumeResult, error) {
+			return &repositories.SwapVolumeResult{SwapCount: 7, PoolCount: 1, TotalVolume: "42"}, nil
+		}
+
+		req := httptest.NewRequest("GET", "/tokens/"+testutil.USDTAddress+"/swap-volume", nil)
+		w := httptest.NewRecorder()
+		setupSwapRouter(mockRepo).ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var response services.SwapVolumeResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if response.Data.SwapCount != 7 || response.Data.TotalVolume != "42" {
+			t.Errorf("unexpected stats: %+v", response.Data)
+		}
+	})
+
+	t.Run("returns 400 for invalid address", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/tokens/0x123/swap-volume", nil)
+		w := httptest.NewRecorder()
+		setupSwapRouter(testutil.NewMockSwapRepository()).ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+// maxTenantNameLength matches the tenants.name column
+const maxTenantNameLength = 255
+
+// TenantHandler handles HTTP requests for tenant administration
+type TenantHandler struct {
+	service *services.TenantService
+	logger  *zap.Logger
+}
+
+// NewTenantHandler creates a new tenant handler
+func NewTenantHandler(service *services.TenantService, logger *zap.Logger) *TenantHandler {
+	return &TenantHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterAdminRoutes registers the tenant admin routes; callers protect them with middleware.AdminAuth
+func (h *TenantHandler) RegisterAdminRoutes(r chi.Router) {
+	r.Post("/admin/tenants", h.CreateTenant)
+	r.Get("/admin/tenants/{id}", h.GetTenant)
+	r.Put("/admin/tenants/{id}/tokens", h.SetTokens)
+	r.Post("/admin/tenants/{id}/keys", h.CreateAPIKey)
+}
+
+type createTenantRequest struct {
+	Name         string   `json:"name"`
+	RateLimitRPS int      `json:"rate_limit_rps"`
+	Tokens       []string `json:"tokens"`
+}
+
+type setTenantTokensRequest struct {
+	Tokens []string `json:"tokens"`
+}
+
+// CreateTenant handles POST /api/v1/admin/tenants
+func (h *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var req createTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxTenantNameLength {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("name is required and must be at most %d characters", maxTenantNameLength))
+		return
+	}
+	if req.RateLimitRPS < 0 {
+		h.respondError(w, http.StatusBadRequest, "rate_limit_rps must not be negative")
+		return
+	}
+	if !h.validTokens(w, req.Tokens) {
+		return
+	}
+
+	response, err := h.service.CreateTenant(r.Context(), req.Name, req.RateLimitRPS, req.Tokens)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to create tenant")
+		return
+	}
+
+	// The response carries the plaintext API key, which must not be cached or kept for replays
+	w.Header().Set("Cache-Control", "no-store")
+	h.respondJSON(w, http.StatusCreated, response)
+}
+
+// GetTenant handles GET /api/v1/admin/tenants/{id}
+func (h *TenantHandler) GetTenant(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetTenant(r.Context(), id)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to get tenant")
+		return
+	}
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "tenant not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// SetTokens handles PUT /api/v1/admin/tenants/{id}/tokens
+func (h *TenantHandler) SetTokens(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req setTenantTokensRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if !h.validTokens(w, req.Tokens) {
+		return
+	}
+
+	response, err := h.service.SetTokens(r.Context(), id, req.Tokens)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to set tenant tokens")
+		return
+	}
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "tenant not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// CreateAPIKey handles POST /api/v1/admin/tenants/{id}/keys
+func (h *TenantHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.CreateAPIKey(r.Context(), id)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to create API key")
+		return
+	}
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "tenant not found")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.respondJSON(w, http.StatusCreated, response)
+}
+
+func (h *TenantHandler) parseID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid tenant ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *TenantHandler) validTokens(w http.ResponseWriter, tokens []string) bool {
+	if len(tokens) > services.MaxTenantTokens {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("a tenant can track at most %d tokens", services.MaxTenantTokens))
+		return false
+	}
+	for _, addr := range tokens {
+		if !isValidAddress(addr) {
+			h.respondError(w, http.StatusBadRequest, "Invalid address format")
+			return false
+		}
+	}
+	return true
+}
+
+func (h *TenantHandler) handleServiceError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, services.ErrTenantFull) {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("a tenant can track at most %d tokens", services.MaxTenantTokens))
+		return
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	h.respondError(w, http.StatusInternalServerError, message)
+}
+
+func (h *TenantHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *TenantHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func setupTenantRouter() *chi.Mux {
+	repo := testutil.NewMockTenantRepository(testutil.NewMockTokenRepository())
+	handler := NewTenantHandler(services.NewTenantService(repo, nil, zap.NewNop()), zap.NewNop())
+
+	r := chi.NewRouter()
+	handler.RegisterAdminRoutes(r)
+	return r
+}
+
+func TestTenantHandler_Lifecycle(t *testing.T) {
+	r := setupTenantRouter()
+
+	w := serveWatchlist(r, "POST", "/admin/tenants", fmt.Sprintf(`{"name":"acme","rate_limit_rps":5,"tokens":[%q]}`, testutil.USDTAddress))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created services.TenantKeyResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.APIKey == "" || created.Data.RateLimitRPS != 5 || len(created.Data.Tokens) != 1 {
+		t.Errorf("unexpected tenant: %+v", created)
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("expected the API key response marked no-store, got %q", w.Header().Get("Cache-Control"))
+	}
+	path := fmt.Sprintf("/admin/tenants/%d", created.Data.ID)
+
+	w = serveWatchlist(r, "PUT", path+"/tokens", fmt.Sprintf(`{"tokens":[%q,%q]}`, testutil.USDTAddress, testutil.USDCAddress))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = serveWatchlist(r, "GET", path, "")
+	var got services.TenantResponse
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || len(got.Data.Tokens) != 2 {
+		t.Errorf("expected the tenant with two tokens, got %d %+v", w.Code, got.Data)
+	}
+
+	w = serveWatchlist(r, "POST", path+"/keys", "")
+	var key services.TenantKeyResponse
+	if err := json.NewDecoder(w.Body).Decode(&key); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusCreated || key.APIKey == "" || key.APIKey == created.APIKey {
+		t.Errorf("expected a new API key, got %d %+v", w.Code, key)
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("expected the API key response marked no-store, got %q", w.Header().Get("Cache-Control"))
+	}
+}
+
+func TestTenantHandler_Errors(t *testing.T) {
+	r := setupTenantRouter()
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"POST", "/admin/tenants", `{"name":""}`, http.StatusBadRequest},
+		{"POST", "/admin/tenants", `{"name":"acme","rate_limit_rps":-1}`, http.StatusBadRequest},
+		{"POST", "/admin/tenants", `{"name":"acme","tokens":["0xinvalid"]}`, http.StatusBadRequest},
+		{"POST", "/admin/tenants", `not json`, http.StatusBadRequest},
+		{"GET", "/admin/tenants/abc", "", http.StatusBadRequest},
+		{"GET", "/admin/tenants/1", "", http.StatusNotFound},
+		{"PUT", "/admin/tenants/1/tokens", `{"tokens":[]}`, http.StatusNotFound},
+		{"POST", "/admin/tenants/1/keys", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := serveWatchlist(r, tt.method, tt.path, tt.body)
+		if w.Code != tt.want {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.want, w.Code)
+		}
+	}
+}
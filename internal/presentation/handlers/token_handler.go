@@ -2,20 +2,25 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
 
 	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
 )
 
 // TokenHandler handles HTTP requests for tokens
 type TokenHandler struct {
-	service *services.TokenService
-	logger  *zap.Logger
+	service     *services.TokenService
+	logger      *zap.Logger
+	pages       PageLimits
+	historyGate []func(http.Handler) http.Handler
 }
 
 // NewTokenHandler creates a new token handler
@@ -23,47 +28,67 @@ func NewTokenHandler(service *services.TokenService, logger *zap.Logger) *TokenH
 	return &TokenHandler{
 		service: service,
 		logger:  logger,
+		pages:   DefaultPageLimits,
 	}
 }
 
+// WithPageLimits sets the endpoints' default and maximum page sizes
+func (h *TokenHandler) WithPageLimits(pages PageLimits) *TokenHandler {
+	h.pages = pages
+	return h
+}
+
+// WithMetadataHistoryGate serves the metadata history route behind mw, such as a check that its
+// table exists
+func (h *TokenHandler) WithMetadataHistoryGate(mw func(http.Handler) http.Handler) *TokenHandler {
+	h.historyGate = append(h.historyGate, mw)
+	return h
+}
+
 // RegisterRoutes registers the token routes
 func (h *TokenHandler) RegisterRoutes(r chi.Router) {
 	r.Get("/tokens", h.GetAllTokens)
 	r.Get("/tokens/{address}", h.GetByAddress)
+	r.With(h.historyGate...).Get("/tokens/{address}/metadata/history", h.GetMetadataHistory)
+}
+
+// RegisterAdminRoutes registers the token admin routes; callers protect them with middleware.AdminAuth
+func (h *TokenHandler) RegisterAdminRoutes(r chi.Router) {
+	r.Post("/admin/tokens/{address}/deactivate", h.Deactivate)
+	r.Post("/admin/tokens/{address}/activate", h.Activate)
+	r.Patch("/admin/tokens/{address}", h.OverrideMetadata)
+}
+
+// overrideMetadataRequest is the body of PATCH /api/v1/admin/tokens/{address}
+type overrideMetadataRequest struct {
+	Name     *string `json:"name"`
+	Symbol   *string `json:"symbol"`
+	Decimals *int    `json:"decimals"`
 }
 
-// GetAllTokens handles GET /api/v1/tokens
+// GetAllTokens handles GET /api/v1/tokens, or a batch lookup when ?addresses=a,b,c is given
 func (h *TokenHandler) GetAllTokens(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Parse query parameters with defaults
-	limit := 100
-	offset := 0
-	sortBy := "total_indexed_transfers"
-	sortOrder := "desc"
-
-	if v := r.URL.Query().Get("limit"); v != "" {
-		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 1000 {
-			limit = l
-		}
-	}
-	if v := r.URL.Query().Get("offset"); v != "" {
-		if o, err := strconv.Atoi(v); err == nil && o >= 0 {
-			offset = o
-		}
+	if r.URL.Query().Has("addresses") {
+		h.getByAddresses(w, r)
+		return
 	}
-	if v := r.URL.Query().Get("sort_by"); v != "" {
-		sortBy = v
+
+	var q tokenListQuery
+	if err := bindQuery(r, &q); err != nil {
+		respondValidationError(w, err)
+		return
 	}
-	if v := r.URL.Query().Get("sort_order"); v != "" {
-		v = strings.ToLower(v)
-		if v == "asc" || v == "desc" {
-			sortOrder = v
-		}
+	if err := h.pages.apply(r, "tokens", &q.Limit); err != nil {
+		respondValidationError(w, err)
+		return
 	}
-
-	response, err := h.service.GetAllTokens(ctx, limit, offset, sortBy, sortOrder)
+	response, err := h.service.GetAllTokens(ctx, q.Limit, q.Offset, q.SortBy, q.SortOrder, q.IncludeInactive)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get tokens", zap.Error(err))
 		h.respondError(w, http.StatusInternalServerError, "Failed to get tokens")
 		return
@@ -86,6 +111,9 @@ func (h *TokenHandler) GetByAddress(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.service.GetByAddress(ctx, address)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get token", zap.Error(err), zap.String("address", address))
 		h.respondError(w, http.StatusInternalServerError, "Failed to get token")
 		return
@@ -99,6 +127,144 @@ func (h *TokenHandler) GetByAddress(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+// GetMetadataHistory handles GET /api/v1/tokens/{address}/metadata/history
+func (h *TokenHandler) GetMetadataHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	address := chi.URLParam(r, "address")
+
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	address = strings.ToLower(address)
+
+	response, err := h.service.GetMetadataHistory(ctx, address)
+	if err != nil {
+		h.logger.Error("Failed to get token metadata history", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get token metadata history")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// Deactivate handles POST /api/v1/admin/tokens/{address}/deactivate
+func (h *TokenHandler) Deactivate(w http.ResponseWriter, r *http.Request) {
+	h.setActive(w, r, false)
+}
+
+// Activate handles POST /api/v1/admin/tokens/{address}/activate
+func (h *TokenHandler) Activate(w http.ResponseWriter, r *http.Request) {
+	h.setActive(w, r, true)
+}
+
+func (h *TokenHandler) setActive(w http.ResponseWriter, r *http.Request, active bool) {
+	address := chi.URLParam(r, "address")
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	response, err := h.service.SetActive(r.Context(), address, active)
+	if err != nil {
+		h.logger.Error("Failed to update token", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to update token")
+		return
+	}
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// OverrideMetadata handles PATCH /api/v1/admin/tokens/{address}
+func (h *TokenHandler) OverrideMetadata(w http.ResponseWriter, r *http.Request) {
+	address := chi.URLParam(r, "address")
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	var req overrideMetadataRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	override := entities.TokenMetadataOverride{Name: req.Name, Symbol: req.Symbol, Decimals: req.Decimals}
+	actor := r.RemoteAddr
+	if id := chimiddleware.GetReqID(r.Context()); id != "" {
+		actor += " request_id=" + id
+	}
+
+	response, err := h.service.OverrideMetadata(r.Context(), address, override, actor)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTokenMetadata) {
+			h.respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.logger.Error("Failed to override token metadata", zap.Error(err), zap.String("address", address))
+		h.respondError(w, http.StatusInternalServerError, "Failed to update token")
+		return
+	}
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// getByAddresses handles GET /api/v1/tokens?addresses=a,b,c
+func (h *TokenHandler) getByAddresses(w http.ResponseWriter, r *http.Request) {
+	var addresses []string
+	for _, addr := range strings.Split(r.URL.Query().Get("addresses"), ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		if !isValidAddress(addr) {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid address format: %s", addr))
+			return
+		}
+		addresses = append(addresses, addr)
+	}
+
+	if len(addresses) == 0 {
+		h.respondError(w, http.StatusBadRequest, "addresses must list at least one address")
+		return
+	}
+	if len(addresses) > services.MaxTokenBatchSize {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("at most %d addresses can be requested at once", services.MaxTokenBatchSize))
+		return
+	}
+
+	response, err := h.service.GetByAddresses(r.Context(), addresses)
+	if err != nil {
+		h.logger.Error("Failed to get tokens", zap.Error(err), zap.Int("addresses", len(addresses)))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get tokens")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// tokenListQuery holds the query parameters of GET /api/v1/tokens
+type tokenListQuery struct {
+	pageQuery
+	SortBy          string `query:"sort_by" default:"total_indexed_transfers" oneof:"address name symbol decimals total_indexed_transfers first_seen_block last_seen_block created_at updated_at"`
+	SortOrder       string `query:"sort_order" default:"desc" oneof:"asc desc"`
+	IncludeInactive bool   `query:"include_inactive"`
+}
+
 func (h *TokenHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
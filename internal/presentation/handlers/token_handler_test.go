@@ -6,13 +6,16 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
 	"github.com/bimakw/chain-indexer/internal/application/services"
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/domain/repositories"
 	"github.com/bimakw/chain-indexer/internal/testutil"
 )
 
@@ -120,38 +123,100 @@ func TestTokenHandler_GetAllTokens_DefaultParams(t *testing.T) {
 func TestTokenHandler_GetAllTokens_InvalidLimit(t *testing.T) {
 	handler, _ := setupTokenHandlerTest()
 
-	// Test with limit > 1000 (should use default)
+	// Test with limit > 1000 (should be rejected)
 	req := httptest.NewRequest(http.MethodGet, "/tokens?limit=5000", nil)
 	rec := httptest.NewRecorder()
 
 	handler.GetAllTokens(rec, req)
 
-	var response services.TokenListResponse
-	json.NewDecoder(rec.Body).Decode(&response)
-
-	if response.Pagination.Limit != 100 {
-		t.Errorf("expected default limit 100, got %d", response.Pagination.Limit)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, rec.Code)
 	}
 }
 
-func TestTokenHandler_GetAllTokens_InvalidSortOrder(t *testing.T) {
+func TestTokenHandler_GetAllTokens_InvalidSort(t *testing.T) {
 	handler, _ := setupTokenHandlerTest()
 
-	req := httptest.NewRequest(http.MethodGet, "/tokens?sort_order=INVALID", nil)
+	for _, query := range []string{"sort_order=INVALID", "sort_by=balance"} {
+		req := httptest.NewRequest(http.MethodGet, "/tokens?"+query, nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetAllTokens(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestTokenHandler_GetAllTokens_SortOrderCaseInsensitive(t *testing.T) {
+	handler, tokenRepo := setupTokenHandlerTest()
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens?sort_order=ASC", nil)
 	rec := httptest.NewRecorder()
 
 	handler.GetAllTokens(rec, req)
 
-	// Should still succeed with default sort order
 	if rec.Code != http.StatusOK {
-		t.Errorf("expected status 200, got %d", rec.Code)
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	call := tokenRepo.Calls[len(tokenRepo.Calls)-1]
+	if call.Args[3] != "asc" {
+		t.Errorf("expected sort order asc, got %v", call.Args[3])
+	}
+}
+
+func TestTokenHandler_GetAllTokens_IncludeInactive(t *testing.T) {
+	handler, tokenRepo := setupTokenHandlerTest()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	tokenRepo.AddToken(testutil.CreateTestToken(
+		testutil.TokenWithAddress(testutil.USDCAddress),
+		testutil.TokenInactive(),
+	))
+
+	tests := []struct {
+		query string
+		want  int64
+	}{
+		{"", 1},
+		{"?include_inactive=false", 1},
+		{"?include_inactive=true", 2},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/tokens"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		handler.GetAllTokens(rec, req)
+
+		var response services.TokenListResponse
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatalf("%q: failed to decode response: %v", tt.query, err)
+		}
+		if response.Pagination.Total != tt.want {
+			t.Errorf("%q: expected %d tokens, got %d", tt.query, tt.want, response.Pagination.Total)
+		}
+	}
+}
+
+func TestTokenHandler_GetAllTokens_InvalidIncludeInactive(t *testing.T) {
+	handler, _ := setupTokenHandlerTest()
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens?include_inactive=yes", nil)
+	rec := httptest.NewRecorder()
+	handler.GetAllTokens(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "include_inactive") {
+		t.Errorf("expected include_inactive in the field errors, got %s", rec.Body.String())
 	}
 }
 
 func TestTokenHandler_GetAllTokens_ServiceError(t *testing.T) {
 	handler, tokenRepo := setupTokenHandlerTest()
 
-	tokenRepo.GetAllPaginatedFunc = func(ctx context.Context, limit, offset int, sortBy, sortOrder string) ([]*entities.Token, int64, error) {
+	tokenRepo.GetAllPaginatedFunc = func(ctx context.Context, limit, offset int, sortBy, sortOrder string, includeInactive bool, scope repositories.TokenScope) ([]*entities.Token, int64, error) {
 		return nil, 0, errors.New("database error")
 	}
 
@@ -171,6 +236,53 @@ func TestTokenHandler_GetAllTokens_ServiceError(t *testing.T) {
 	}
 }
 
+func TestTokenHandler_GetAllTokens_Batch(t *testing.T) {
+	handler, tokenRepo := setupTokenHandlerTest()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens?addresses="+testutil.USDTAddress+","+testutil.USDCAddress, nil)
+	rec := httptest.NewRecorder()
+
+	handler.GetAllTokens(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var response services.TokenBatchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data[0].Address != testutil.USDTAddress {
+		t.Errorf("expected USDT only, got %+v", response.Data)
+	}
+	if len(response.NotFound) != 1 || response.NotFound[0] != testutil.USDCAddress {
+		t.Errorf("expected USDC as not found, got %v", response.NotFound)
+	}
+}
+
+func TestTokenHandler_GetAllTokens_BatchInvalid(t *testing.T) {
+	handler, _ := setupTokenHandlerTest()
+
+	tooMany := strings.Repeat(testutil.USDTAddress+",", services.MaxTokenBatchSize) + testutil.USDCAddress
+
+	for name, query := range map[string]string{
+		"empty":           "addresses=",
+		"invalid address": "addresses=" + testutil.USDTAddress + ",0x123",
+		"too many":        "addresses=" + tooMany,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/tokens?"+query, nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetAllTokens(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", name, rec.Code)
+		}
+	}
+}
+
 func TestTokenHandler_GetByAddress_Success(t *testing.T) {
 	handler, tokenRepo := setupTokenHandlerTest()
 
@@ -349,6 +461,107 @@ func TestTokenHandler_RegisterRoutes(t *testing.T) {
 	}
 }
 
+func TestTokenHandler_AdminRoutes(t *testing.T) {
+	handler, tokenRepo := setupTokenHandlerTest()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	r := chi.NewRouter()
+	handler.RegisterAdminRoutes(r)
+
+	steps := []struct {
+		action string
+		active bool
+	}{
+		{"deactivate", false},
+		{"activate", true},
+	}
+	for _, step := range steps {
+		req := httptest.NewRequest(http.MethodPost, "/admin/tokens/"+testutil.USDTAddress+"/"+step.action, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", step.action, rec.Code)
+		}
+		var response services.TokenResponse
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", step.action, err)
+		}
+		if response.Data.Active != step.active {
+			t.Errorf("%s: expected active=%t, got %t", step.action, step.active, response.Data.Active)
+		}
+	}
+}
+
+func TestTokenHandler_AdminRoutes_Errors(t *testing.T) {
+	handler, _ := setupTokenHandlerTest()
+
+	r := chi.NewRouter()
+	handler.RegisterAdminRoutes(r)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/admin/tokens/" + testutil.USDTAddress + "/deactivate", http.StatusNotFound},
+		{"/admin/tokens/0xinvalid/activate", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.want, rec.Code)
+		}
+	}
+}
+
+func TestTokenHandler_OverrideMetadata(t *testing.T) {
+	handler, tokenRepo := setupTokenHandlerTest()
+
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+
+	r := chi.NewRouter()
+	handler.RegisterAdminRoutes(r)
+
+	tests := []struct {
+		name    string
+		address string
+		body    string
+		want    int
+	}{
+		{"override", testutil.USDTAddress, `{"name":"Tether USD","decimals":6}`, http.StatusOK},
+		{"no fields", testutil.USDTAddress, `{}`, http.StatusBadRequest},
+		{"bad decimals", testutil.USDTAddress, `{"decimals":300}`, http.StatusBadRequest},
+		{"bad body", testutil.USDTAddress, `{"decimals":"six"}`, http.StatusBadRequest},
+		{"bad address", "0xinvalid", `{"decimals":6}`, http.StatusBadRequest},
+		{"unknown token", testutil.USDCAddress, `{"decimals":6}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/admin/tokens/"+tt.address, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var response services.TokenResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Data.Name != "Tether USD" || response.Data.Decimals != 6 || response.Data.MetadataSource != "override" {
+				t.Errorf("unexpected token: %+v", response.Data)
+			}
+		})
+	}
+}
+
 func TestTokenHandler_ResponseContentType(t *testing.T) {
 	handler, _ := setupTokenHandlerTest()
 
@@ -385,3 +598,71 @@ func TestTokenHandler_EmptyList(t *testing.T) {
 		t.Errorf("expected 0 tokens in data, got %d", len(response.Data))
 	}
 }
+
+func TestTokenHandler_GetByAddress_DatabaseUnavailable(t *testing.T) {
+	handler, tokenRepo := setupTokenHandlerTest()
+	handler.service.WithBreaker(services.NewBreaker(services.BreakerConfig{Threshold: 1, Cooldown: 30 * time.Second},
+		func(err error) bool { return true }, zap.NewNop()))
+
+	tokenRepo.GetByAddressFunc = func(ctx context.Context, address string) (*entities.Token, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	r := chi.NewRouter()
+	r.Get("/tokens/{address}", handler.GetByAddress)
+
+	req := httptest.NewRequest(http.MethodGet, "/tokens/"+testutil.USDTAddress, nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("expected Retry-After 30, got %q", got)
+	}
+}
+
+func TestTokenHandler_GetMetadataHistory(t *testing.T) {
+	handler, tokenRepo := setupTokenHandlerTest()
+	tokenRepo.AddToken(testutil.CreateTestToken(testutil.TokenWithAddress(testutil.USDTAddress)))
+	if _, err := tokenRepo.RecordMetadataChange(context.Background(), &entities.TokenMetadataChange{
+		TokenAddress:     testutil.USDTAddress,
+		PreviousDecimals: 6,
+		Decimals:         18,
+		BlockNumber:      19000000,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	get := func(address string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/tokens/"+address+"/metadata/history", nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get(strings.ToUpper(testutil.USDTAddress[2:]))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an address without 0x, got %d", rec.Code)
+	}
+
+	rec = get(testutil.USDTAddress)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var response services.TokenMetadataHistoryResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data) != 1 || response.Data[0].Decimals != 18 || response.Data[0].BlockNumber != 19000000 {
+		t.Errorf("unexpected metadata history: %+v", response.Data)
+	}
+
+	if rec := get(testutil.USDCAddress); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown token, got %d", rec.Code)
+	}
+}
@@ -2,9 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"math/big"
 	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -18,6 +17,7 @@ import (
 type TransferHandler struct {
 	service *services.TransferService
 	logger  *zap.Logger
+	pages   PageLimits
 }
 
 // NewTransferHandler creates a new transfer handler
@@ -25,9 +25,16 @@ func NewTransferHandler(service *services.TransferService, logger *zap.Logger) *
 	return &TransferHandler{
 		service: service,
 		logger:  logger,
+		pages:   DefaultPageLimits,
 	}
 }
 
+// WithPageLimits sets the endpoints' default and maximum page sizes
+func (h *TransferHandler) WithPageLimits(pages PageLimits) *TransferHandler {
+	h.pages = pages
+	return h
+}
+
 // RegisterRoutes registers the transfer routes
 func (h *TransferHandler) RegisterRoutes(r chi.Router) {
 	r.Get("/transfers", h.GetTransfers)
@@ -39,64 +46,100 @@ func (h *TransferHandler) RegisterRoutes(r chi.Router) {
 func (h *TransferHandler) GetTransfers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	filter := entities.DefaultTransferFilter()
-
-	// Parse query parameters
-	if v := r.URL.Query().Get("token"); v != "" {
-		addr := strings.ToLower(v)
-		filter.TokenAddress = &addr
-	}
-	if v := r.URL.Query().Get("from"); v != "" {
-		addr := strings.ToLower(v)
-		filter.FromAddress = &addr
-	}
-	if v := r.URL.Query().Get("to"); v != "" {
-		addr := strings.ToLower(v)
-		filter.ToAddress = &addr
-	}
-	if v := r.URL.Query().Get("address"); v != "" {
-		addr := strings.ToLower(v)
-		filter.Address = &addr
-	}
-	if v := r.URL.Query().Get("from_block"); v != "" {
-		if block, err := strconv.ParseInt(v, 10, 64); err == nil {
-			filter.FromBlock = &block
-		}
-	}
-	if v := r.URL.Query().Get("to_block"); v != "" {
-		if block, err := strconv.ParseInt(v, 10, 64); err == nil {
-			filter.ToBlock = &block
-		}
-	}
-	if v := r.URL.Query().Get("from_time"); v != "" {
-		if t, err := time.Parse(time.RFC3339, v); err == nil {
-			filter.FromTime = &t
-		}
-	}
-	if v := r.URL.Query().Get("to_time"); v != "" {
-		if t, err := time.Parse(time.RFC3339, v); err == nil {
-			filter.ToTime = &t
-		}
-	}
-	if v := r.URL.Query().Get("limit"); v != "" {
-		if limit, err := strconv.Atoi(v); err == nil && limit > 0 && limit <= 1000 {
-			filter.Limit = limit
-		}
+	filter, err := h.parseTransferFilter(r)
+	if err != nil {
+		respondValidationError(w, err)
+		return
 	}
-	if v := r.URL.Query().Get("offset"); v != "" {
-		if offset, err := strconv.Atoi(v); err == nil && offset >= 0 {
-			filter.Offset = offset
-		}
+
+	withUSD, err := includeUSD(r)
+	if err != nil {
+		respondValidationError(w, err)
+		return
 	}
 
 	response, err := h.service.GetTransfers(ctx, filter)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get transfers", zap.Error(err))
 		h.respondError(w, http.StatusInternalServerError, "Failed to get transfers")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
+	if withUSD {
+		h.service.AddUSDValues(ctx, response, r.URL.Query().Get("price_at") == "historical")
+	}
+	services.ApplyValueFormat(response, r.URL.Query().Get("decimals"))
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
+}
+
+// transferQuery holds the /transfers query parameters
+type transferQuery struct {
+	Token       *string    `query:"token" format:"address"`
+	From        *string    `query:"from" format:"address"`
+	To          *string    `query:"to" format:"address"`
+	Address     *string    `query:"address" format:"address"`
+	Initiator   *string    `query:"initiator" format:"address"`
+	Selector    *string    `query:"selector" format:"selector"`
+	Method      *string    `query:"method"`
+	FromBlock   *int64     `query:"from_block" min:"0"`
+	ToBlock     *int64     `query:"to_block" min:"0"`
+	FromTime    *time.Time `query:"from_time"`
+	ToTime      *time.Time `query:"to_time"`
+	MinValue    *big.Int   `query:"min_value"`
+	MaxValue    *big.Int   `query:"max_value"`
+	ExcludeZero bool       `query:"exclude_zero"`
+	ExcludeSelf bool       `query:"exclude_self"`
+	pageQuery
+}
+
+func (q *transferQuery) validate() []FieldError {
+	var errs []FieldError
+	if q.FromBlock != nil && q.ToBlock != nil && *q.FromBlock > *q.ToBlock {
+		errs = append(errs, FieldError{Field: "to_block", Message: "must not be before from_block"})
+	}
+	if q.FromTime != nil && q.ToTime != nil && q.FromTime.After(*q.ToTime) {
+		errs = append(errs, FieldError{Field: "to_time", Message: "must not be before from_time"})
+	}
+	if q.MinValue != nil && q.MaxValue != nil && q.MinValue.Cmp(q.MaxValue) > 0 {
+		errs = append(errs, FieldError{Field: "max_value", Message: "must not be less than min_value"})
+	}
+	return errs
+}
+
+// parseTransferFilter reads the /transfers query parameters
+func (h *TransferHandler) parseTransferFilter(r *http.Request) (entities.TransferFilter, error) {
+	filter := entities.DefaultTransferFilter()
+
+	var q transferQuery
+	if err := bindQuery(r, &q); err != nil {
+		return filter, err
+	}
+	if err := h.pages.apply(r, "transfers", &q.Limit); err != nil {
+		return filter, err
+	}
+
+	filter.TokenAddress = q.Token
+	filter.FromAddress = q.From
+	filter.ToAddress = q.To
+	filter.Address = q.Address
+	filter.Initiator = q.Initiator
+	filter.Selector = q.Selector
+	filter.Method = q.Method
+	filter.FromBlock = q.FromBlock
+	filter.ToBlock = q.ToBlock
+	filter.FromTime = q.FromTime
+	filter.ToTime = q.ToTime
+	filter.MinValue = q.MinValue
+	filter.MaxValue = q.MaxValue
+	filter.ExcludeZero = q.ExcludeZero
+	filter.ExcludeSelf = q.ExcludeSelf
+	filter.Limit = q.Limit
+	filter.Offset = q.Offset
+
+	return filter, nil
 }
 
 // GetTransfersByAddress handles GET /transfers/address/{address}
@@ -109,28 +152,33 @@ func (h *TransferHandler) GetTransfersByAddress(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	limit := 100
-	offset := 0
-
-	if v := r.URL.Query().Get("limit"); v != "" {
-		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 1000 {
-			limit = l
-		}
+	page, err := h.pages.parsePage(r, "transfers")
+	if err != nil {
+		respondValidationError(w, err)
+		return
 	}
-	if v := r.URL.Query().Get("offset"); v != "" {
-		if o, err := strconv.Atoi(v); err == nil && o >= 0 {
-			offset = o
-		}
+
+	withUSD, err := includeUSD(r)
+	if err != nil {
+		respondValidationError(w, err)
+		return
 	}
 
-	response, err := h.service.GetTransfersByAddress(ctx, address, limit, offset)
+	response, err := h.service.GetTransfersByAddress(ctx, address, page.Limit, page.Offset)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get transfers by address", zap.Error(err))
 		h.respondError(w, http.StatusInternalServerError, "Failed to get transfers")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
+	if withUSD {
+		h.service.AddUSDValues(ctx, response, r.URL.Query().Get("price_at") == "historical")
+	}
+	services.ApplyValueFormat(response, r.URL.Query().Get("decimals"))
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
 }
 
 // GetTransfersByToken handles GET /tokens/{tokenAddress}/transfers
@@ -143,28 +191,33 @@ func (h *TransferHandler) GetTransfersByToken(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	limit := 100
-	offset := 0
-
-	if v := r.URL.Query().Get("limit"); v != "" {
-		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 1000 {
-			limit = l
-		}
+	page, err := h.pages.parsePage(r, "transfers")
+	if err != nil {
+		respondValidationError(w, err)
+		return
 	}
-	if v := r.URL.Query().Get("offset"); v != "" {
-		if o, err := strconv.Atoi(v); err == nil && o >= 0 {
-			offset = o
-		}
+
+	withUSD, err := includeUSD(r)
+	if err != nil {
+		respondValidationError(w, err)
+		return
 	}
 
-	response, err := h.service.GetTransfersByToken(ctx, tokenAddress, limit, offset)
+	response, err := h.service.GetTransfersByToken(ctx, tokenAddress, page.Limit, page.Offset)
 	if err != nil {
+		if respondUnavailable(w, err) {
+			return
+		}
 		h.logger.Error("Failed to get transfers by token", zap.Error(err))
 		h.respondError(w, http.StatusInternalServerError, "Failed to get transfers")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
+	if withUSD {
+		h.service.AddUSDValues(ctx, response, r.URL.Query().Get("price_at") == "historical")
+	}
+	services.ApplyValueFormat(response, r.URL.Query().Get("decimals"))
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
 }
 
 func (h *TransferHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -177,12 +230,18 @@ func (h *TransferHandler) respondError(w http.ResponseWriter, status int, messag
 	h.respondJSON(w, status, map[string]string{"error": message})
 }
 
+// isValidAddress reports whether addr is an EVM address, in any letter case
 func isValidAddress(addr string) bool {
-	if len(addr) != 42 {
-		return false
-	}
-	if !strings.HasPrefix(addr, "0x") {
-		return false
+	_, err := entities.ParseEVMAddress(addr)
+	return err == nil
+}
+
+// includeUSD reports whether the request asked for USD values via ?include_usd=true, rejecting
+// values that are not booleans
+func includeUSD(r *http.Request) (bool, error) {
+	var q struct {
+		IncludeUSD bool `query:"include_usd"`
 	}
-	return true
+	err := bindQuery(r, &q)
+	return q.IncludeUSD, err
 }
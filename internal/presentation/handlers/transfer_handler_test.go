@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -121,18 +123,24 @@ func TestTransferHandler_GetTransfers_Pagination(t *testing.T) {
 func TestTransferHandler_GetTransfers_InvalidLimit(t *testing.T) {
 	handler, _, _ := setupTransferHandlerTest()
 
-	// Test with limit > 1000 (should use default)
+	// Test with limit > 1000 (should be rejected with an explanation)
 	req := httptest.NewRequest(http.MethodGet, "/transfers?limit=5000", nil)
 	rec := httptest.NewRecorder()
 
 	handler.GetTransfers(rec, req)
 
-	var response services.TransferResponse
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, rec.Code)
+	}
+
+	var response validationErrorResponse
 	json.NewDecoder(rec.Body).Decode(&response)
 
-	// Should use default limit (100)
-	if response.Limit != 100 {
-		t.Errorf("expected default limit 100, got %d", response.Limit)
+	if response.Error != "limit 5000 exceeds the maximum of 1000 for this endpoint" {
+		t.Errorf("unexpected error %q", response.Error)
+	}
+	if len(response.Fields) != 1 || response.Fields[0].Field != "limit" {
+		t.Errorf("expected a limit field error, got %+v", response.Fields)
 	}
 }
 
@@ -158,6 +166,210 @@ func TestTransferHandler_GetTransfers_BlockRange(t *testing.T) {
 	}
 }
 
+func TestTransferHandler_GetTransfers_ValueRange(t *testing.T) {
+	handler, transferRepo, _ := setupTransferHandlerTest()
+
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithValue(big.NewInt(10))),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithValue(big.NewInt(5000))),
+		testutil.CreateTestTransfer(testutil.WithID(3), testutil.WithValue(big.NewInt(1000000))),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/transfers?min_value=100&max_value=5000", nil)
+	rec := httptest.NewRecorder()
+
+	handler.GetTransfers(rec, req)
+
+	var response services.TransferResponse
+	json.NewDecoder(rec.Body).Decode(&response)
+
+	if response.Total != 1 {
+		t.Errorf("expected 1 transfer, got %d", response.Total)
+	}
+
+	// Invalid values are rejected
+	req = httptest.NewRequest(http.MethodGet, "/transfers?min_value=abc&max_value=-1", nil)
+	rec = httptest.NewRecorder()
+
+	handler.GetTransfers(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestTransferHandler_GetTransfers_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		fields []string
+	}{
+		{"malformed limit", "?limit=abc", []string{"limit"}},
+		{"zero limit", "?limit=0", []string{"limit"}},
+		{"negative offset", "?offset=-1", []string{"offset"}},
+		{"malformed block", "?from_block=latest", []string{"from_block"}},
+		{"inverted block range", "?from_block=200&to_block=100", []string{"to_block"}},
+		{"malformed timestamp", "?from_time=yesterday", []string{"from_time"}},
+		{"inverted time range", "?from_time=2024-02-01T00:00:00Z&to_time=2024-01-01T00:00:00Z", []string{"to_time"}},
+		{"malformed bool", "?exclude_zero=maybe", []string{"exclude_zero"}},
+		{"invalid address", "?token=usdt", []string{"token"}},
+		{"several fields", "?limit=abc&offset=x&min_value=-5", []string{"min_value", "limit", "offset"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _, _ := setupTransferHandlerTest()
+
+			req := httptest.NewRequest(http.MethodGet, "/transfers"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.GetTransfers(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+			}
+
+			var response validationErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(response.Fields) != len(tt.fields) {
+				t.Fatalf("expected %d field errors, got %+v", len(tt.fields), response.Fields)
+			}
+			for i, field := range tt.fields {
+				if response.Fields[i].Field != field {
+					t.Errorf("expected error %d on %s, got %s", i, field, response.Fields[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestTransferHandler_GetTransfers_ExcludeZeroAndSelf(t *testing.T) {
+	handler, transferRepo, _ := setupTransferHandlerTest()
+
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1)),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithValue(big.NewInt(0))),
+		testutil.CreateTestTransfer(testutil.WithID(3), testutil.WithToAddress(testutil.AliceAddress)),
+	)
+
+	tests := []struct {
+		query    string
+		expected int64
+	}{
+		{"", 3},
+		{"?exclude_zero=true", 2},
+		{"?exclude_self=true", 2},
+		{"?exclude_zero=true&exclude_self=true", 1},
+		{"?exclude_zero=false", 3},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/transfers"+tt.query, nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetTransfers(rec, req)
+
+		var response services.TransferResponse
+		json.NewDecoder(rec.Body).Decode(&response)
+
+		if response.Total != tt.expected {
+			t.Errorf("%q: expected %d transfers, got %d", tt.query, tt.expected, response.Total)
+		}
+	}
+}
+
+func TestTransferHandler_GetTransfers_DecimalsParam(t *testing.T) {
+	handler, transferRepo, tokenRepo := setupTransferHandlerTest()
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+	transferRepo.AddTransfers(testutil.CreateTestTransfer(testutil.WithValue(big.NewInt(2500000))))
+
+	tests := []struct {
+		query             string
+		expectedValue     string
+		expectedFormatted string
+	}{
+		{"", "2500000", "2.5"},
+		{"?decimals=both", "2500000", "2.5"},
+		{"?decimals=raw", "2500000", ""},
+		{"?decimals=formatted", "", "2.5"},
+		{"?decimals=bogus", "2500000", "2.5"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/transfers"+tt.query, nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetTransfers(rec, req)
+
+		var response services.TransferResponse
+		json.NewDecoder(rec.Body).Decode(&response)
+
+		if len(response.Transfers) != 1 {
+			t.Fatalf("%q: expected 1 transfer, got %d", tt.query, len(response.Transfers))
+		}
+		dto := response.Transfers[0]
+		if dto.Value != tt.expectedValue || dto.ValueFormatted != tt.expectedFormatted {
+			t.Errorf("%q: expected value %q formatted %q, got %q %q",
+				tt.query, tt.expectedValue, tt.expectedFormatted, dto.Value, dto.ValueFormatted)
+		}
+	}
+}
+
+func TestTransferHandler_GetTransfers_IncludeUSD(t *testing.T) {
+	transferRepo := testutil.NewMockTransferRepository()
+	tokenRepo := testutil.NewMockTokenRepository()
+	logger := zap.NewNop()
+	service := services.NewTransferService(transferRepo, tokenRepo, nil, logger).
+		WithPriceProvider(testutil.NewMockPriceProvider(map[string]float64{testutil.USDTAddress: 2}))
+	handler := NewTransferHandler(service, logger)
+
+	tokenRepo.AddToken(testutil.CreateTestToken())
+	transferRepo.AddTransfers(testutil.CreateTestTransfer(testutil.WithValue(big.NewInt(2500000))))
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"", ""},
+		{"?include_usd=false", ""},
+		{"?include_usd=true", "5.00"},
+		{"?include_usd=true&decimals=raw", "5.00"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/transfers"+tt.query, nil)
+		rec := httptest.NewRecorder()
+
+		handler.GetTransfers(rec, req)
+
+		var response services.TransferResponse
+		json.NewDecoder(rec.Body).Decode(&response)
+
+		if len(response.Transfers) != 1 {
+			t.Fatalf("%q: expected 1 transfer, got %d", tt.query, len(response.Transfers))
+		}
+		if response.Transfers[0].ValueUSD != tt.expected {
+			t.Errorf("%q: expected value_usd %q, got %q", tt.query, tt.expected, response.Transfers[0].ValueUSD)
+		}
+	}
+}
+
+func TestTransferHandler_GetTransfers_InvalidIncludeUSD(t *testing.T) {
+	handler, _, _ := setupTransferHandlerTest()
+
+	req := httptest.NewRequest(http.MethodGet, "/transfers?include_usd=yes-please", nil)
+	rec := httptest.NewRecorder()
+
+	handler.GetTransfers(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rec.Code)
+	}
+}
+
 func TestTransferHandler_GetTransfers_AddressFilters(t *testing.T) {
 	handler, transferRepo, _ := setupTransferHandlerTest()
 
@@ -200,6 +412,56 @@ func TestTransferHandler_GetTransfers_AddressFilters(t *testing.T) {
 	}
 }
 
+func TestTransferHandler_GetTransfers_InitiatorFilter(t *testing.T) {
+	handler, transferRepo, _ := setupTransferHandlerTest()
+
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithFromAddress(testutil.BobAddress), testutil.WithInitiator(testutil.AliceAddress)),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithFromAddress(testutil.CharlieAddr), testutil.WithInitiator(testutil.CharlieAddr)),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/transfers?initiator="+strings.ToUpper(testutil.AliceAddress[2:]), nil)
+	rec := httptest.NewRecorder()
+	handler.GetTransfers(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unprefixed initiator, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/transfers?initiator=0x"+strings.ToUpper(testutil.AliceAddress[2:]), nil)
+	rec = httptest.NewRecorder()
+	handler.GetTransfers(rec, req)
+
+	var response services.TransferResponse
+	json.NewDecoder(rec.Body).Decode(&response)
+	if response.Total != 1 || response.Transfers[0].Initiator != testutil.AliceAddress {
+		t.Errorf("initiator filter: expected Alice's transfer, got %+v", response)
+	}
+}
+
+func TestTransferHandler_GetTransfers_MethodFilters(t *testing.T) {
+	handler, transferRepo, _ := setupTransferHandlerTest()
+
+	var captured entities.TransferFilter
+	transferRepo.GetByFilterFunc = func(ctx context.Context, filter entities.TransferFilter) ([]entities.Transfer, error) {
+		captured = filter
+		return nil, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/transfers?selector=0x38ED1739&method=swap", nil)
+	rec := httptest.NewRecorder()
+	handler.GetTransfers(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if captured.Selector == nil || *captured.Selector != "0x38ed1739" {
+		t.Errorf("expected lowercased selector filter, got %v", captured.Selector)
+	}
+	if captured.Method == nil || *captured.Method != "swap" {
+		t.Errorf("expected method filter swap, got %v", captured.Method)
+	}
+}
+
 func TestTransferHandler_GetTransfers_ServiceError(t *testing.T) {
 	handler, transferRepo, _ := setupTransferHandlerTest()
 
@@ -430,3 +692,41 @@ func TestTransferHandler_ResponseContentType(t *testing.T) {
 		t.Errorf("expected Content-Type application/json, got %s", contentType)
 	}
 }
+
+func TestTransferHandler_GetTransfers_Fields(t *testing.T) {
+	handler, transferRepo, _ := setupTransferHandlerTest()
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithID(1), testutil.WithLogIndex(0)),
+		testutil.CreateTestTransfer(testutil.WithID(2), testutil.WithLogIndex(1)),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/transfers?fields=tx_hash,%20value,block_timestamp,unknown", nil)
+	rec := httptest.NewRecorder()
+	handler.GetTransfers(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var response struct {
+		Transfers []map[string]interface{} `json:"transfers"`
+		Total     int64                    `json:"total"`
+		HasMore   *bool                    `json:"has_more"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	// Envelope fields are kept; each transfer only has the requested keys
+	if response.Total != 2 || response.HasMore == nil {
+		t.Errorf("expected pagination to be kept, got %+v", response)
+	}
+	if len(response.Transfers) != 2 {
+		t.Fatalf("expected 2 transfers, got %d", len(response.Transfers))
+	}
+	for _, transfer := range response.Transfers {
+		if len(transfer) != 3 || transfer["tx_hash"] == nil || transfer["value"] == nil || transfer["block_timestamp"] == nil {
+			t.Errorf("unexpected transfer fields: %v", transfer)
+		}
+	}
+}
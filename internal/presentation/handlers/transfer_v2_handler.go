@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// Error codes returned in the v2 error envelope
+const (
+	errCodeInvalidParameter = "invalid_parameter"
+	errCodeInvalidCursor    = "invalid_cursor"
+	errCodeInternal         = "internal_error"
+	errCodeUnavailable      = "unavailable"
+)
+
+// v2ErrorResponse is the v2 error envelope: a stable machine-readable code plus a human-readable message
+type v2ErrorResponse struct {
+	Error v2Error `json:"error"`
+}
+
+type v2Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RegisterV2Routes registers the cursor-paginated v2 transfer routes
+func (h *TransferHandler) RegisterV2Routes(r chi.Router) {
+	r.Get("/transfers", h.GetTransfersV2)
+	r.Get("/tokens/{tokenAddress}/transfers", h.GetTransfersByTokenV2)
+}
+
+// GetTransfersV2 handles GET /api/v2/transfers
+func (h *TransferHandler) GetTransfersV2(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.parseV2TransferFilter(w, r)
+	if !ok {
+		return
+	}
+	h.respondTransferPage(w, r, filter)
+}
+
+// GetTransfersByTokenV2 handles GET /api/v2/tokens/{tokenAddress}/transfers
+func (h *TransferHandler) GetTransfersByTokenV2(w http.ResponseWriter, r *http.Request) {
+	tokenAddress := chi.URLParam(r, "tokenAddress")
+	if !isValidAddress(tokenAddress) {
+		h.respondV2Error(w, http.StatusBadRequest, errCodeInvalidParameter, "Invalid token address format")
+		return
+	}
+
+	filter, ok := h.parseV2TransferFilter(w, r)
+	if !ok {
+		return
+	}
+	tokenAddress = strings.ToLower(tokenAddress)
+	filter.TokenAddress = &tokenAddress
+
+	h.respondTransferPage(w, r, filter)
+}
+
+func (h *TransferHandler) respondTransferPage(w http.ResponseWriter, r *http.Request, filter entities.TransferFilter) {
+	ctx := r.Context()
+
+	withUSD, err := includeUSD(r)
+	if err != nil {
+		h.respondV2ValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.GetTransferPage(ctx, filter)
+	if err != nil {
+		var unavailable *services.UnavailableError
+		if errors.As(err, &unavailable) {
+			setRetryAfter(w, unavailable.RetryAfter)
+			h.respondV2Error(w, http.StatusServiceUnavailable, errCodeUnavailable, "Database temporarily unavailable, retry later")
+			return
+		}
+		h.logger.Error("Failed to get transfers", zap.Error(err))
+		h.respondV2Error(w, http.StatusInternalServerError, errCodeInternal, "Failed to get transfers")
+		return
+	}
+
+	if withUSD {
+		h.service.AddPageUSDValues(ctx, response, r.URL.Query().Get("price_at") == "historical")
+	}
+	services.ApplyPageValueFormat(response, r.URL.Query().Get("decimals"))
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
+}
+
+// parseV2TransferFilter reads the same filters as v1, reporting the first invalid parameter in the
+// v2 error format, and pages by cursor rather than offset
+func (h *TransferHandler) parseV2TransferFilter(w http.ResponseWriter, r *http.Request) (entities.TransferFilter, bool) {
+	query := r.URL.Query()
+	var filter entities.TransferFilter
+
+	for _, param := range []string{"token", "from", "to", "address", "initiator"} {
+		if v := query.Get(param); v != "" && !isValidAddress(v) {
+			h.respondV2Error(w, http.StatusBadRequest, errCodeInvalidParameter, fmt.Sprintf("Invalid %s address format", param))
+			return filter, false
+		}
+	}
+
+	if query.Has("offset") {
+		h.respondV2Error(w, http.StatusBadRequest, errCodeInvalidParameter, "offset is not supported, use cursor")
+		return filter, false
+	}
+
+	var page pageQuery
+	err := bindQuery(r, &page)
+	if err == nil {
+		err = h.pages.apply(r, "transfers", &page.Limit)
+	}
+	if err != nil {
+		h.respondV2ValidationError(w, err)
+		return filter, false
+	}
+
+	filter, err = h.parseTransferFilter(r)
+	if err != nil {
+		h.respondV2ValidationError(w, err)
+		return filter, false
+	}
+
+	if v := query.Get("cursor"); v != "" {
+		cursor, err := services.DecodeTransferCursor(v)
+		if err != nil {
+			h.respondV2Error(w, http.StatusBadRequest, errCodeInvalidCursor, "Invalid cursor")
+			return filter, false
+		}
+		filter.After = cursor
+	}
+
+	return filter, true
+}
+
+// respondV2ValidationError reports the first invalid parameter of a bindQuery or checkLimit error
+// in the v2 error format
+func (h *TransferHandler) respondV2ValidationError(w http.ResponseWriter, err error) {
+	var verr *ValidationError
+	var lerr *LimitExceededError
+	switch {
+	case errors.As(err, &verr):
+		f := verr.Fields[0]
+		h.respondV2Error(w, http.StatusBadRequest, errCodeInvalidParameter, f.Field+" "+f.Message)
+	case errors.As(err, &lerr):
+		h.respondV2Error(w, http.StatusUnprocessableEntity, errCodeInvalidParameter, lerr.Error())
+	default:
+		h.respondV2Error(w, http.StatusBadRequest, errCodeInvalidParameter, err.Error())
+	}
+}
+
+func (h *TransferHandler) respondV2Error(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v2ErrorResponse{Error: v2Error{Code: code, Message: message}})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func TestTransferHandler_GetTransfersV2_CursorPagination(t *testing.T) {
+	handler, transferRepo, _ := setupTransferHandlerTest()
+	base := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x03"), testutil.WithBlockNumber(102), testutil.WithBlockTimestamp(base.Add(2*time.Minute))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithBlockNumber(101), testutil.WithBlockTimestamp(base.Add(time.Minute))),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithBlockNumber(100), testutil.WithBlockTimestamp(base)),
+	)
+
+	r := chi.NewRouter()
+	handler.RegisterV2Routes(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/transfers?limit=2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var first services.TransferPageResponse
+	if err := json.NewDecoder(rec.Body).Decode(&first); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(first.Data) != 2 || !first.Pagination.HasMore || first.Pagination.NextCursor == "" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/transfers?limit=2&cursor="+first.Pagination.NextCursor, nil))
+
+	var second services.TransferPageResponse
+	if err := json.NewDecoder(rec.Body).Decode(&second); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(second.Data) != 1 || second.Data[0].TxHash != "0x01" || second.Pagination.HasMore {
+		t.Errorf("unexpected second page: %+v", second)
+	}
+}
+
+func TestTransferHandler_GetTransfersV2_Errors(t *testing.T) {
+	handler, _, _ := setupTransferHandlerTest()
+
+	r := chi.NewRouter()
+	handler.RegisterV2Routes(r)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		code   string
+	}{
+		{"invalid cursor", "/transfers?cursor=garbage!", http.StatusBadRequest, errCodeInvalidCursor},
+		{"offset", "/transfers?offset=100", http.StatusBadRequest, errCodeInvalidParameter},
+		{"limit too large", "/transfers?limit=5000", http.StatusUnprocessableEntity, errCodeInvalidParameter},
+		{"malformed limit", "/transfers?limit=abc", http.StatusBadRequest, errCodeInvalidParameter},
+		{"zero limit", "/transfers?limit=0", http.StatusBadRequest, errCodeInvalidParameter},
+		{"malformed include_usd", "/transfers?include_usd=maybe", http.StatusBadRequest, errCodeInvalidParameter},
+		{"invalid filter address", "/transfers?from=0x123", http.StatusBadRequest, errCodeInvalidParameter},
+		{"invalid token", "/tokens/0x123/transfers", http.StatusBadRequest, errCodeInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, rec.Code)
+			}
+			var response v2ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Error.Code != tt.code || response.Error.Message == "" {
+				t.Errorf("expected code %s, got %+v", tt.code, response.Error)
+			}
+		})
+	}
+}
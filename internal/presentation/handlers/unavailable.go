@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+// respondUnavailable writes a 503 with a Retry-After header when err is a *services.UnavailableError,
+// which services return while the database cannot be reached, and reports whether it did
+func respondUnavailable(w http.ResponseWriter, err error) bool {
+	var unavailable *services.UnavailableError
+	if !errors.As(err, &unavailable) {
+		return false
+	}
+
+	setRetryAfter(w, unavailable.RetryAfter)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": "Database temporarily unavailable, retry later"})
+	return true
+}
+
+// setRetryAfter sets the Retry-After header to wait rounded up to whole seconds, at least one
+func setRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
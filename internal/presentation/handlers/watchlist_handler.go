@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+// maxWatchlistNameLength matches the watchlists.name column
+const maxWatchlistNameLength = 255
+
+// WatchlistHandler handles HTTP requests for watchlist endpoints
+type WatchlistHandler struct {
+	service         *services.WatchlistService
+	transferService *services.TransferService
+	logger          *zap.Logger
+	pages           PageLimits
+}
+
+// NewWatchlistHandler creates a new watchlist handler.
+// transferService enriches the feed for ?include_usd=true.
+func NewWatchlistHandler(service *services.WatchlistService, transferService *services.TransferService, logger *zap.Logger) *WatchlistHandler {
+	return &WatchlistHandler{
+		service:         service,
+		transferService: transferService,
+		logger:          logger,
+		pages:           DefaultPageLimits,
+	}
+}
+
+// WithPageLimits sets the endpoints' default and maximum page sizes
+func (h *WatchlistHandler) WithPageLimits(pages PageLimits) *WatchlistHandler {
+	h.pages = pages
+	return h
+}
+
+// RegisterRoutes registers the watchlist routes
+func (h *WatchlistHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/watchlists", func(r chi.Router) {
+		r.Post("/", h.CreateWatchlist)
+		r.Get("/{id}", h.GetWatchlist)
+		r.Delete("/{id}", h.DeleteWatchlist)
+		r.Post("/{id}/addresses", h.AddAddresses)
+		r.Delete("/{id}/addresses/{address}", h.RemoveAddress)
+		r.Get("/{id}/transfers", h.GetWatchlistTransfers)
+	})
+}
+
+type createWatchlistRequest struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+}
+
+type addAddressesRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+// CreateWatchlist handles POST /api/v1/watchlists
+func (h *WatchlistHandler) CreateWatchlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req createWatchlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxWatchlistNameLength {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("name is required and must be at most %d characters", maxWatchlistNameLength))
+		return
+	}
+	if !h.validAddresses(w, req.Addresses) {
+		return
+	}
+
+	response, err := h.service.CreateWatchlist(ctx, req.Name, req.Addresses)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to create watchlist")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, response)
+}
+
+// GetWatchlist handles GET /api/v1/watchlists/{id}
+func (h *WatchlistHandler) GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetWatchlist(ctx, id)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to get watchlist")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "watchlist not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// DeleteWatchlist handles DELETE /api/v1/watchlists/{id}
+func (h *WatchlistHandler) DeleteWatchlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.service.DeleteWatchlist(ctx, id)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to delete watchlist")
+		return
+	}
+
+	if !deleted {
+		h.respondError(w, http.StatusNotFound, "watchlist not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddAddresses handles POST /api/v1/watchlists/{id}/addresses
+func (h *WatchlistHandler) AddAddresses(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req addAddressesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if len(req.Addresses) == 0 {
+		h.respondError(w, http.StatusBadRequest, "addresses is required")
+		return
+	}
+	if !h.validAddresses(w, req.Addresses) {
+		return
+	}
+
+	response, err := h.service.AddAddresses(ctx, id, req.Addresses)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to add watchlist addresses")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "watchlist not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// RemoveAddress handles DELETE /api/v1/watchlists/{id}/addresses/{address}
+func (h *WatchlistHandler) RemoveAddress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	address := chi.URLParam(r, "address")
+	if !isValidAddress(address) {
+		h.respondError(w, http.StatusBadRequest, "Invalid address format")
+		return
+	}
+
+	response, err := h.service.RemoveAddress(ctx, id, address)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to remove watchlist address")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "watchlist not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetWatchlistTransfers handles GET /api/v1/watchlists/{id}/transfers
+func (h *WatchlistHandler) GetWatchlistTransfers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.parseID(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.pages.parsePage(r, "watchlists")
+	if err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	withUSD, err := includeUSD(r)
+	if err != nil {
+		respondValidationError(w, err)
+		return
+	}
+
+	response, err := h.service.GetWatchlistTransfers(ctx, id, page.Limit, page.Offset)
+	if err != nil {
+		h.handleServiceError(w, err, "Failed to get watchlist transfers")
+		return
+	}
+
+	if response == nil {
+		h.respondError(w, http.StatusNotFound, "watchlist not found")
+		return
+	}
+
+	if withUSD {
+		h.transferService.AddUSDValues(ctx, response, r.URL.Query().Get("price_at") == "historical")
+	}
+	services.ApplyValueFormat(response, r.URL.Query().Get("decimals"))
+	h.respondJSON(w, http.StatusOK, withFields(r, response))
+}
+
+func (h *WatchlistHandler) parseID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid watchlist ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *WatchlistHandler) validAddresses(w http.ResponseWriter, addresses []string) bool {
+	if len(addresses) > services.MaxWatchlistAddresses {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("a watchlist can hold at most %d addresses", services.MaxWatchlistAddresses))
+		return false
+	}
+	for _, addr := range addresses {
+		if !isValidAddress(addr) {
+			h.respondError(w, http.StatusBadRequest, "Invalid address format")
+			return false
+		}
+	}
+	return true
+}
+
+func (h *WatchlistHandler) handleServiceError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, services.ErrWatchlistFull) {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("a watchlist can hold at most %d addresses", services.MaxWatchlistAddresses))
+		return
+	}
+	if respondUnavailable(w, err) {
+		return
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	h.respondError(w, http.StatusInternalServerError, message)
+}
+
+func (h *WatchlistHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *WatchlistHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/testutil"
+)
+
+func setupWatchlistRouter(transferRepo *testutil.MockTransferRepository) *chi.Mux {
+	logger := zap.NewNop()
+	transferService := services.NewTransferService(transferRepo, testutil.NewMockTokenRepository(), nil, logger)
+	watchlistService := services.NewWatchlistService(testutil.NewMockWatchlistRepository(), transferService, logger)
+	handler := NewWatchlistHandler(watchlistService, transferService, logger)
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+	return r
+}
+
+func serveWatchlist(r http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestWatchlistHandler_Lifecycle(t *testing.T) {
+	transferRepo := testutil.NewMockTransferRepository()
+	transferRepo.AddTransfers(
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x01"), testutil.WithFromAddress(testutil.AliceAddress), testutil.WithToAddress(testutil.CharlieAddr)),
+		testutil.CreateTestTransfer(testutil.WithTxHash("0x02"), testutil.WithFromAddress(testutil.CharlieAddr), testutil.WithToAddress(testutil.BobAddress)),
+	)
+	r := setupWatchlistRouter(transferRepo)
+
+	w := serveWatchlist(r, "POST", "/watchlists", fmt.Sprintf(`{"name":"team","addresses":[%q]}`, testutil.AliceAddress))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created services.WatchlistResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	base := fmt.Sprintf("/watchlists/%d", created.Data.ID)
+
+	w = serveWatchlist(r, "POST", base+"/addresses", fmt.Sprintf(`{"addresses":[%q]}`, testutil.BobAddress))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	w = serveWatchlist(r, "GET", base+"/transfers", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var feed services.TransferResponse
+	if err := json.NewDecoder(w.Body).Decode(&feed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if feed.Total != 2 {
+		t.Errorf("expected 2 transfers in feed, got %d", feed.Total)
+	}
+
+	w = serveWatchlist(r, "DELETE", base+"/addresses/"+testutil.BobAddress, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	w = serveWatchlist(r, "DELETE", base, "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+
+	w = serveWatchlist(r, "GET", base, "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 after delete, got %d", w.Code)
+	}
+}
+
+func TestWatchlistHandler_Validation(t *testing.T) {
+	r := setupWatchlistRouter(testutil.NewMockTransferRepository())
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"malformed body", "POST", "/watchlists", `{`, http.StatusBadRequest},
+		{"missing name", "POST", "/watchlists", `{"addresses":[]}`, http.StatusBadRequest},
+		{"invalid address", "POST", "/watchlists", `{"name":"x","addresses":["0x123"]}`, http.StatusBadRequest},
+		{"invalid id", "GET", "/watchlists/abc", "", http.StatusBadRequest},
+		{"unknown watchlist", "GET", "/watchlists/42", "", http.StatusNotFound},
+		{"unknown watchlist feed", "GET", "/watchlists/42/transfers", "", http.StatusNotFound},
+		{"empty address list", "POST", "/watchlists/42/addresses", `{"addresses":[]}`, http.StatusBadRequest},
+		{"delete unknown", "DELETE", "/watchlists/42", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWatchlist(r, tt.method, tt.path, tt.body)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
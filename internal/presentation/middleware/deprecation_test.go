@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeprecated(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	Deprecated("/api/v2/transfers", sunset)(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/transfers", nil))
+
+	if got := rec.Header().Get("Deprecation"); got != "true" {
+		t.Errorf("expected Deprecation: true, got %q", got)
+	}
+	if got := rec.Header().Get("Link"); got != `</api/v2/transfers>; rel="successor-version"` {
+		t.Errorf("unexpected Link header: %q", got)
+	}
+	if got := rec.Header().Get("Sunset"); got != "Fri, 01 Jan 2027 00:00:00 GMT" {
+		t.Errorf("unexpected Sunset header: %q", got)
+	}
+
+	// No sunset date configured
+	rec = httptest.NewRecorder()
+	Deprecated("/api/v2/transfers", time.Time{})(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/transfers", nil))
+	if _, set := rec.Header()["Sunset"]; set {
+		t.Error("expected no Sunset header without a sunset date")
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// RequireFeature answers 503 on the routes of a feature when enabled is false, as when the
+// database has not been migrated to the feature's tables yet during a rolling deployment.
+// Retry-After is not set, since the wait depends on when the migrations are applied.
+func RequireFeature(feature string, enabled bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			respondError(w, http.StatusServiceUnavailable, "feature "+feature+" is unavailable until the database is migrated")
+		})
+	}
+}
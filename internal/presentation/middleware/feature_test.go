@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireFeature(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	RequireFeature("swaps", true)(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/swaps", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected an enabled feature to be served, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	RequireFeature("swaps", false)(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/swaps", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "feature swaps is unavailable") {
+		t.Errorf("expected 503 naming the disabled feature, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+// Idempotency headers: the key a client sends with a write request, and the marker on
+// responses replayed from an earlier request with the same key
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+const (
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentResponseSize = 1 << 20 // larger responses are not stored, so retries run again
+
+	// idempotencyLockTTL bounds how long a request holds its key, so a crashed instance cannot
+	// keep a key locked for the whole retention period
+	idempotencyLockTTL = time.Minute
+)
+
+// IdempotencyStore keeps idempotent responses; *cache.RedisCache implements it. Get returns
+// cache.ErrCacheMiss for unknown keys.
+type IdempotencyStore interface {
+	Get(ctx context.Context, key string, dest interface{}) error
+	SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// idempotentResponse is a stored response and the hash of the request that produced it
+type idempotentResponse struct {
+	RequestHash string      `json:"request_hash"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header"`
+	Body        []byte      `json:"body"`
+}
+
+// Idempotency lets clients retry POST, PUT, PATCH and DELETE requests safely: a request carrying
+// an Idempotency-Key header is served once, and its response is kept for ttl and replayed to
+// retries with the same key, method, path and body, marked with Idempotent-Replayed: true.
+// Keys are scoped to the caller's tenant, or client IP without one, and to the credentials the
+// request carries, so only a caller presenting the same ones gets a stored response back; this
+// runs before AdminAuth. Reusing a key for a different request gets 422, and retrying while the
+// first request is still running gets 409. Server errors are not kept, so a retry after one runs
+// again, and neither are responses marked Cache-Control: no-store, such as those issuing API
+// keys. Requests without the header and reads are passed through.
+func Idempotency(store IdempotencyStore, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !isWriteMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				respondError(w, http.StatusBadRequest, "Idempotency-Key must be at most "+strconv.Itoa(maxIdempotencyKeyLength)+" characters")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			storeKey := "idempotency:" + idempotencyScope(r) + ":" + key
+			hash := requestHash(r, body)
+
+			var stored idempotentResponse
+			switch err := store.Get(ctx, storeKey, &stored); {
+			case err == nil:
+				replayResponse(w, &stored, hash)
+				return
+			case !errors.Is(err, cache.ErrCacheMiss):
+				respondError(w, http.StatusServiceUnavailable, "failed to check Idempotency-Key")
+				return
+			}
+
+			locked, err := store.TryLock(ctx, storeKey+":lock", idempotencyLockTTL)
+			if err != nil {
+				respondError(w, http.StatusServiceUnavailable, "failed to check Idempotency-Key")
+				return
+			}
+			if !locked {
+				respondError(w, http.StatusConflict, "a request with this Idempotency-Key is in progress")
+				return
+			}
+			defer func() { _ = store.Delete(context.WithoutCancel(ctx), storeKey+":lock") }()
+
+			rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			if rec.status >= http.StatusInternalServerError || rec.overflow || isNoStore(w.Header()) {
+				return
+			}
+			_ = store.SetWithTTL(context.WithoutCancel(ctx), storeKey, idempotentResponse{
+				RequestHash: hash,
+				Status:      rec.status,
+				Header:      replayedHeaders(w.Header()),
+				Body:        rec.body.Bytes(),
+			}, ttl)
+		})
+	}
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// idempotencyScope keeps clients from replaying each other's responses
+func idempotencyScope(r *http.Request) string {
+	scope := "ip:" + r.RemoteAddr
+	if tenant := services.TenantFromContext(r.Context()); tenant != nil {
+		scope = "tenant:" + strconv.FormatInt(tenant.ID, 10)
+	} else if ip, ok := remoteAddr(r); ok {
+		scope = "ip:" + ip.String()
+	}
+	return scope + ":cred:" + credentialHash(r)
+}
+
+// credentialHash digests the credentials a request carries, so the store never holds them
+func credentialHash(r *http.Request) string {
+	h := sha256.Sum256([]byte(r.Header.Get("Authorization") + "\n" + r.Header.Get(APIKeyHeader)))
+	return hex.EncodeToString(h[:])
+}
+
+// requestHash identifies a request by method, path, query, credentials and body
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n" + credentialHash(r) + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isNoStore reports whether a response is marked Cache-Control: no-store, as responses carrying
+// secrets are
+func isNoStore(header http.Header) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// replayResponse answers a retry with the stored response, or 422 if the key was first used
+// for a different request
+func replayResponse(w http.ResponseWriter, stored *idempotentResponse, hash string) {
+	if stored.RequestHash != hash {
+		respondError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+		return
+	}
+	for name, values := range stored.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(stored.Status)
+	_, _ = w.Write(stored.Body)
+}
+
+// replayedHeaders picks the response headers worth replaying
+func replayedHeaders(header http.Header) http.Header {
+	kept := make(http.Header)
+	for _, name := range []string{"Content-Type", "Location", "Cache-Control"} {
+		if values := header.Values(name); len(values) > 0 {
+			kept[name] = values
+		}
+	}
+	return kept
+}
+
+// recordingWriter copies a response as it is written, up to maxIdempotentResponseSize
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	overflow    bool
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	if !rw.wroteHeader {
+		rw.status = code
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	if !rw.overflow {
+		if rw.body.Len()+len(b) > maxIdempotentResponseSize {
+			rw.overflow = true
+			rw.body.Reset()
+		} else {
+			rw.body.Write(b)
+		}
+	}
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rw *recordingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+func TestIdempotency(t *testing.T) {
+	created := 0
+	handler := Idempotency(cache.NewMemoryCache(time.Minute, zap.NewNop()), time.Hour)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("fail") != "" {
+				respondError(w, http.StatusServiceUnavailable, "database unavailable")
+				return
+			}
+			created++
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Location", fmt.Sprintf("/watchlists/%d", created))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":%d}`, created)
+		}),
+	)
+
+	send := func(method, target, key, body, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := send(http.MethodPost, "/watchlists", "key-1", `{"name":"a"}`, "192.0.2.1:1234")
+	if first.Code != http.StatusCreated || first.Body.String() != `{"id":1}` {
+		t.Fatalf("expected the request served, got %d %s", first.Code, first.Body)
+	}
+
+	retry := send(http.MethodPost, "/watchlists", "key-1", `{"name":"a"}`, "192.0.2.1:5678")
+	if retry.Code != http.StatusCreated || retry.Body.String() != `{"id":1}` || created != 1 {
+		t.Errorf("expected the first response replayed, got %d %s after %d creations", retry.Code, retry.Body, created)
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" || retry.Header().Get("Location") != "/watchlists/1" {
+		t.Errorf("expected replayed headers, got %v", retry.Header())
+	}
+
+	if rec := send(http.MethodPost, "/watchlists", "key-1", `{"name":"b"}`, "192.0.2.1:1234"); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected a reused key with another body to get 422, got %d", rec.Code)
+	}
+
+	// Keys are scoped to the client
+	if rec := send(http.MethodPost, "/watchlists", "key-1", `{"name":"a"}`, "198.51.100.7:1234"); rec.Body.String() != `{"id":2}` {
+		t.Errorf("expected another client's key served on its own, got %s", rec.Body)
+	}
+
+	// Server errors are not kept, so the retry runs again
+	if rec := send(http.MethodPost, "/watchlists?fail=1", "key-2", "", "192.0.2.1:1234"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+	if rec := send(http.MethodPost, "/watchlists", "key-2", "", "192.0.2.1:1234"); rec.Code == http.StatusServiceUnavailable {
+		t.Error("expected a retry after a server error to run again")
+	}
+
+	// Requests without a key, and reads, are not deduplicated
+	before := created
+	send(http.MethodPost, "/watchlists", "", `{"name":"a"}`, "192.0.2.1:1234")
+	send(http.MethodPost, "/watchlists", "", `{"name":"a"}`, "192.0.2.1:1234")
+	send(http.MethodGet, "/watchlists", "key-3", "", "192.0.2.1:1234")
+	send(http.MethodGet, "/watchlists", "key-3", "", "192.0.2.1:1234")
+	if created != before+4 {
+		t.Errorf("expected 4 requests served, got %d", created-before)
+	}
+
+	if rec := send(http.MethodPost, "/watchlists", strings.Repeat("k", 256), "", "192.0.2.1:1234"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected an overlong key to get 400, got %d", rec.Code)
+	}
+}
+
+func TestIdempotency_ConcurrentRetry(t *testing.T) {
+	store := cache.NewMemoryCache(time.Minute, zap.NewNop())
+	var handler http.Handler
+	handler = Idempotency(store, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The retry arrives while the first request is still running
+		req := httptest.NewRequest(http.MethodPost, "/exports", nil)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusConflict {
+			t.Errorf("expected the concurrent retry to get 409, got %d", rec.Code)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/exports", nil)
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("expected 202, got %d", rec.Code)
+	}
+}
+
+func TestIdempotency_Credentials(t *testing.T) {
+	issued := 0
+	handler := Idempotency(cache.NewMemoryCache(time.Minute, zap.NewNop()), time.Hour)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			issued++
+			if r.URL.Path == "/admin/tenants/1/keys" {
+				w.Header().Set("Cache-Control", "no-store")
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"api_key":"secret-%d"}`, issued)
+		}),
+	)
+
+	send := func(target, key, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set(IdempotencyKeyHeader, key)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send("/admin/tenants", "key-1", "Bearer admin"); rec.Body.String() != `{"api_key":"secret-1"}` {
+		t.Fatalf("expected the request served, got %s", rec.Body)
+	}
+	if rec := send("/admin/tenants", "key-1", "Bearer admin"); rec.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("expected a retry with the same credentials replayed, got %s", rec.Body)
+	}
+
+	// The same key from the same address without the credentials gets nothing stored
+	for _, authorization := range []string{"", "Bearer guess"} {
+		if rec := send("/admin/tenants", "key-1", authorization); rec.Code != http.StatusCreated || rec.Body.String() == `{"api_key":"secret-1"}` {
+			t.Errorf("%q: expected no replay of another caller's response, got %s", authorization, rec.Body)
+		}
+	}
+
+	// Responses marked no-store are never kept
+	send("/admin/tenants/1/keys", "key-2", "Bearer admin")
+	if rec := send("/admin/tenants/1/keys", "key-2", "Bearer admin"); rec.Code != http.StatusCreated || rec.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Errorf("expected a no-store response not to be replayed, got %s", rec.Body)
+	}
+}
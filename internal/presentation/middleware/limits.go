@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// timeoutGrace is added to a route's write deadline so a timed out request can still be answered
+const timeoutGrace = time.Second
+
+// MaxBodySize rejects request bodies larger than maxBytes: a declared Content-Length gets 413
+// at once, and reads past the limit of bodies sent without one fail, so handlers answer 400
+func MaxBodySize(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				respondError(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Timeout bounds a route at d, which may be longer than the server's write timeout: the request
+// context is cancelled after d and the connection's write deadline moved to match, so a slow
+// client cannot hold it longer. Like http.TimeoutHandler, a request that has not started its
+// response when d passes is answered 503, and whatever the handler writes after that is dropped.
+// Unlike it, responses are not buffered, so streams keep streaming until the deadline.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d + timeoutGrace))
+
+			tw := &timeoutWriter{ResponseWriter: w, ctx: ctx}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			tw.finish()
+		})
+	}
+}
+
+// timeoutWriter answers 503 in place of a response started after its context expired
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx context.Context
+
+	mu       sync.Mutex
+	started  bool
+	timedOut bool
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.start(code)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.start(http.StatusOK)
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer to flush streams
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// start sends the response header once, or the 503 instead if the context has expired
+func (tw *timeoutWriter) start(code int) {
+	if tw.started {
+		return
+	}
+	tw.started = true
+	if errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.timedOut = true
+		// Drop headers describing the response the handler meant to send
+		for _, header := range []string{"Cache-Control", "Content-Disposition", "Content-Length"} {
+			tw.ResponseWriter.Header().Del(header)
+		}
+		respondError(tw.ResponseWriter, http.StatusServiceUnavailable, "request timed out")
+		return
+	}
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+// finish answers a request whose handler returned without a response
+func (tw *timeoutWriter) finish() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.started && errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.start(http.StatusServiceUnavailable)
+	}
+}
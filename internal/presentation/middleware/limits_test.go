@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	var readErr error
+	handler := MaxBodySize(8)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("small body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"a":1}`)))
+		if rec.Code != http.StatusOK || readErr != nil {
+			t.Errorf("expected the body to be read, got %d %v", rec.Code, readErr)
+		}
+	})
+
+	t.Run("declared length too large", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"a":"long"}`)))
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected status 413, got %d", rec.Code)
+		}
+	})
+
+	t.Run("undeclared length too large", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"a":"long"}`))
+		req.ContentLength = -1
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if readErr == nil {
+			t.Error("expected reading past the limit to fail")
+		}
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	t.Run("fast handler", func(t *testing.T) {
+		handler := Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			_, _ = w.Write([]byte("ok"))
+		}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != "ok" || rec.Header().Get("Cache-Control") != "max-age=60" {
+			t.Errorf("expected the handler's response, got %d %q", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("slow handler", func(t *testing.T) {
+		var deadlineSet bool
+		handler := Timeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, deadlineSet = r.Context().Deadline()
+			<-r.Context().Done()
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("late"))
+		}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if !deadlineSet {
+			t.Error("expected the request context to carry the deadline")
+		}
+		if rec.Code != http.StatusServiceUnavailable || strings.Contains(rec.Body.String(), "late") {
+			t.Errorf("expected 503 without the late body, got %d %q", rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("Cache-Control") != "" {
+			t.Error("expected the handler's caching headers to be dropped")
+		}
+	})
+
+	t.Run("handler returns without a response", func(t *testing.T) {
+		handler := Timeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", rec.Code)
+		}
+	})
+}
@@ -1,16 +1,25 @@
 package middleware
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
 )
 
-// responseWriter wraps http.ResponseWriter to capture status code
+// responseWriter wraps http.ResponseWriter to capture status code and response size
 type responseWriter struct {
 	http.ResponseWriter
 	status int
+	bytes  int64
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -18,26 +27,95 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// Logger returns a middleware that logs HTTP requests
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer to flush streams and clear deadlines
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// accessLog collects what inner middleware learn about a request for its access log line
+type accessLog struct {
+	tenantID int64
+}
+
+type accessLogKey struct{}
+
+// noteTenant records the tenant a request was scoped to in the request's access log
+func noteTenant(r *http.Request, tenant *entities.Tenant) {
+	if entry, ok := r.Context().Value(accessLogKey{}).(*accessLog); ok {
+		entry.tenantID = tenant.ID
+	}
+}
+
+// Logger returns a middleware that logs HTTP requests. Besides the request and response it logs
+// the matched route template and address parameters, the response size, the tenant and API key
+// fingerprint of scoped requests, and whether every cache lookup made for the request hit.
 func Logger(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
+			entry := &accessLog{}
+			lookups := &cache.RequestLookups{}
+			ctx := context.WithValue(r.Context(), accessLogKey{}, entry)
+			ctx = cache.ContextWithRequestLookups(ctx, lookups)
+
 			wrapped := &responseWriter{ResponseWriter: w, status: http.StatusOK}
-			next.ServeHTTP(wrapped, r)
+			next.ServeHTTP(wrapped, r.WithContext(ctx))
 
 			duration := time.Since(start)
 
-			logger.Info("HTTP request",
+			fields := []zap.Field{
 				zap.String("method", r.Method),
 				zap.String("path", r.URL.Path),
+				zap.String("route", routePattern(r)),
 				zap.String("query", r.URL.RawQuery),
 				zap.Int("status", wrapped.status),
+				zap.Int64("bytes", wrapped.bytes),
 				zap.Duration("duration", duration),
 				zap.String("ip", r.RemoteAddr),
 				zap.String("user_agent", r.UserAgent()),
-			)
+			}
+			fields = append(fields, addressParams(r)...)
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				fields = append(fields, zap.String("api_key", keyFingerprint(key)))
+			}
+			if entry.tenantID != 0 {
+				fields = append(fields, zap.Int64("tenant_id", entry.tenantID))
+			}
+			if hits, misses := lookups.Counts(); hits+misses > 0 {
+				fields = append(fields, zap.Bool("cache_hit", misses == 0))
+			}
+
+			logger.Info("HTTP request", fields...)
 		})
 	}
 }
+
+// addressParams returns the address parameters of the matched route ({address},
+// {holder_address}), lowercased so log searches match however the client cased them
+func addressParams(r *http.Request) []zap.Field {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil
+	}
+	var fields []zap.Field
+	for i, key := range rctx.URLParams.Keys {
+		if strings.HasSuffix(key, "address") && i < len(rctx.URLParams.Values) {
+			fields = append(fields, zap.String(key, strings.ToLower(rctx.URLParams.Values[i])))
+		}
+	}
+	return fields
+}
+
+// keyFingerprint identifies an API key without logging it: the first 12 hex digits of its
+// SHA-256, which is how tenant API keys are stored
+func keyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/bimakw/chain-indexer/internal/infrastructure/cache"
+)
+
+func TestLoggerEnrichesAccessLog(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	memCache := cache.NewMemoryCache(time.Minute, zap.NewNop())
+	resolver := stubResolver{"key-1": {ID: 7, Name: "acme"}}
+
+	r := chi.NewRouter()
+	r.Use(Logger(zap.New(core)))
+	r.Use(Tenants(resolver, 100))
+	r.Get("/api/v1/tokens/{address}", func(w http.ResponseWriter, req *http.Request) {
+		var v string
+		_ = memCache.Get(req.Context(), "stats:"+strings.ToLower(chi.URLParam(req, "address")), &v)
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	})
+
+	_ = memCache.Set(context.Background(), "stats:0xabc", "cached")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tokens/0xABC", nil)
+	req.Header.Set(APIKeyHeader, "key-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if logs.Len() != 1 {
+		t.Fatalf("expected one access log, got %d", logs.Len())
+	}
+	fields := logs.All()[0].ContextMap()
+	if fields["route"] != "/api/v1/tokens/{address}" || fields["address"] != "0xabc" {
+		t.Errorf("expected the route template and address, got %v", fields)
+	}
+	if fields["tenant_id"] != int64(7) || fields["api_key"] != keyFingerprint("key-1") || len(keyFingerprint("key-1")) != 12 {
+		t.Errorf("expected the tenant and key fingerprint, got %v", fields)
+	}
+	if fields["bytes"] != int64(len(`{"data":{}}`)) || fields["cache_hit"] != true {
+		t.Errorf("expected the response size and a cache hit, got %v", fields)
+	}
+
+	// A miss, and no tenant fields without an API key
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/tokens/0xdef", nil))
+	fields = logs.All()[1].ContextMap()
+	if fields["cache_hit"] != false {
+		t.Errorf("expected a cache miss, got %v", fields["cache_hit"])
+	}
+	if _, ok := fields["tenant_id"]; ok {
+		t.Errorf("expected no tenant, got %v", fields)
+	}
+
+	// No cache field when the request made no lookups
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope", nil))
+	fields = logs.All()[2].ContextMap()
+	if _, ok := fields["cache_hit"]; ok || fields["route"] != "unmatched" {
+		t.Errorf("expected an unmatched route without cache_hit, got %v", fields)
+	}
+}
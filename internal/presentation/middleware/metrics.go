@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -15,7 +16,7 @@ var (
 			Name: "http_requests_total",
 			Help: "Total number of HTTP requests",
 		},
-		[]string{"method", "path", "status"},
+		[]string{"method", "route", "status_class"},
 	)
 
 	httpRequestDuration = promauto.NewHistogramVec(
@@ -24,7 +25,7 @@ var (
 			Help:    "HTTP request duration in seconds",
 			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
 		},
-		[]string{"method", "path"},
+		[]string{"method", "route", "status_class"},
 	)
 
 	httpRequestsInFlight = promauto.NewGauge(
@@ -47,22 +48,36 @@ func Metrics() func(http.Handler) http.Handler {
 			next.ServeHTTP(wrapped, r)
 
 			duration := time.Since(start).Seconds()
-			status := strconv.Itoa(wrapped.status)
 
-			// Normalize path to avoid high cardinality
-			path := normalizePath(r.URL.Path)
+			// Label by route template rather than raw path to avoid high cardinality
+			route := routePattern(r)
+			class := statusClass(wrapped.status)
 
-			httpRequestsTotal.WithLabelValues(r.Method, path, status).Inc()
-			httpRequestDuration.WithLabelValues(r.Method, path).Observe(duration)
+			httpRequestsTotal.WithLabelValues(r.Method, route, class).Inc()
+			httpRequestDuration.WithLabelValues(r.Method, route, class).Observe(duration)
 		})
 	}
 }
 
-// normalizePath normalizes the path to reduce cardinality
-func normalizePath(path string) string {
-	// For now, return the path as-is
-	// In production, you might want to replace UUIDs, IDs, etc.
-	return path
+// routePattern returns the matched chi route template (e.g. /api/v1/tokens/{address}),
+// or "unmatched" when no route handled the request
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return "unmatched"
+	}
+	if pattern := rctx.RoutePattern(); pattern != "" {
+		return pattern
+	}
+	return "unmatched"
+}
+
+// statusClass collapses a status code into its class (2xx, 4xx, ...)
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
 }
 
 // IndexerMetrics holds Prometheus metrics for the indexer
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestStatusClass(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected string
+	}{
+		{200, "2xx"},
+		{204, "2xx"},
+		{304, "3xx"},
+		{404, "4xx"},
+		{503, "5xx"},
+		{0, "unknown"},
+		{999, "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := statusClass(tt.status); got != tt.expected {
+			t.Errorf("statusClass(%d) = %s, expected %s", tt.status, got, tt.expected)
+		}
+	}
+}
+
+func TestRoutePattern(t *testing.T) {
+	var captured string
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req)
+			captured = routePattern(req)
+		})
+	})
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/tokens/{address}", func(w http.ResponseWriter, _ *http.Request) {})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tokens/0xdac17f958d2ee523a2206206994597c13d831ec7", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if captured != "/api/v1/tokens/{address}" {
+		t.Errorf("expected templated route, got %s", captured)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/nope", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if captured != "unmatched" {
+		t.Errorf("expected unmatched, got %s", captured)
+	}
+}
+
+func TestRoutePattern_NoRouteContext(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	if got := routePattern(req); got != "unmatched" {
+		t.Errorf("expected unmatched, got %s", got)
+	}
+}
@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// NumberFormatParam is the query parameter selecting how raw integer amounts are encoded
+const NumberFormatParam = "number_format"
+
+// Number formats for raw integer amounts
+const (
+	NumberFormatString       = "string"        // Decimal string, the default
+	NumberFormatHex          = "hex"           // 0x-prefixed hex string, -0x for negative amounts
+	NumberFormatDecimalParts = "decimal-parts" // Array of JSON numbers in base 10^15, most significant first
+)
+
+// decimalPartBase keeps every part of a decimal-parts amount exactly representable as a float64
+var decimalPartBase = big.NewInt(1_000_000_000_000_000)
+
+// rawAmountFields are the JSON keys of the raw integer amounts in API responses. Formatted and
+// USD amounts are decimals for display and keep their own keys.
+var rawAmountFields = map[string]bool{
+	"value":              true,
+	"volume":             true,
+	"balance":            true,
+	"change":             true,
+	"amount0":            true,
+	"amount1":            true,
+	"min_value":          true,
+	"total_volume":       true,
+	"total_volume_in":    true,
+	"total_volume_out":   true,
+	"volume_24h":         true,
+	"volume_7d":          true,
+	"volume_in":          true,
+	"volume_out":         true,
+	"internal_volume":    true,
+	"net_flow":           true,
+	"average_supply":     true,
+	"circulating_supply": true,
+	"dormant_supply":     true,
+}
+
+var integerPattern = regexp.MustCompile(`^-?[0-9]+$`)
+
+// NumberFormat re-encodes the raw integer amounts of JSON responses when a request asks for
+// ?number_format=hex or ?number_format=decimal-parts, for clients that lose precision parsing
+// decimal strings or expect hex. It works on the marshaled response, so every endpoint supports
+// it without handler changes. string, the default, and non-JSON responses such as event streams
+// and exports are passed through; unknown formats get 400.
+func NumberFormat() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			format := strings.ToLower(r.URL.Query().Get(NumberFormatParam))
+			switch format {
+			case "", NumberFormatString:
+				next.ServeHTTP(w, r)
+				return
+			case NumberFormatHex, NumberFormatDecimalParts:
+			default:
+				respondError(w, http.StatusBadRequest, NumberFormatParam+" must be one of string, hex, decimal-parts")
+				return
+			}
+
+			nw := &numberFormatWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(nw, r)
+			if nw.buffering {
+				nw.flushConverted(format)
+			}
+		})
+	}
+}
+
+// numberFormatWriter holds back JSON responses until they can be converted and passes any
+// other response through
+type numberFormatWriter struct {
+	http.ResponseWriter
+	status    int
+	decided   bool
+	buffering bool
+	body      bytes.Buffer
+}
+
+func (nw *numberFormatWriter) WriteHeader(code int) {
+	if nw.decided {
+		return
+	}
+	nw.decided = true
+	mediaType, _, _ := mime.ParseMediaType(nw.Header().Get("Content-Type"))
+	if mediaType == "application/json" {
+		nw.buffering = true
+		nw.status = code
+		return
+	}
+	nw.ResponseWriter.WriteHeader(code)
+}
+
+func (nw *numberFormatWriter) Write(b []byte) (int, error) {
+	if !nw.decided {
+		nw.WriteHeader(http.StatusOK)
+	}
+	if nw.buffering {
+		return nw.body.Write(b)
+	}
+	return nw.ResponseWriter.Write(b)
+}
+
+// Flush sends passed-through responses on; held back JSON is sent once converted
+func (nw *numberFormatWriter) Flush() {
+	if !nw.buffering {
+		_ = http.NewResponseController(nw.ResponseWriter).Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer to clear deadlines
+func (nw *numberFormatWriter) Unwrap() http.ResponseWriter {
+	return nw.ResponseWriter
+}
+
+// flushConverted writes the held back response with its amounts converted, or unchanged if it
+// is not valid JSON
+func (nw *numberFormatWriter) flushConverted(format string) {
+	body := nw.body.Bytes()
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err == nil {
+		var out bytes.Buffer
+		if err := json.NewEncoder(&out).Encode(convertAmounts(doc, "", format)); err == nil {
+			body = out.Bytes()
+		}
+	}
+
+	nw.Header().Del("Content-Length")
+	nw.ResponseWriter.WriteHeader(nw.status)
+	_, _ = nw.ResponseWriter.Write(body)
+}
+
+// convertAmounts re-encodes the raw amounts found under rawAmountFields keys anywhere in v
+func convertAmounts(v interface{}, key, format string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			v[k] = convertAmounts(field, k, format)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertAmounts(item, key, format)
+		}
+	case string:
+		if rawAmountFields[key] && integerPattern.MatchString(v) {
+			amount, _ := new(big.Int).SetString(v, 10)
+			return encodeAmount(amount, format)
+		}
+	}
+	return v
+}
+
+// encodeAmount encodes a raw amount as hex or decimal parts
+func encodeAmount(amount *big.Int, format string) interface{} {
+	if format == NumberFormatHex {
+		if amount.Sign() < 0 {
+			return "-0x" + new(big.Int).Neg(amount).Text(16)
+		}
+		return "0x" + amount.Text(16)
+	}
+
+	rest := new(big.Int).Abs(amount)
+	var parts []interface{}
+	for {
+		part := new(big.Int)
+		rest.QuoRem(rest, decimalPartBase, part)
+		parts = append([]interface{}{part.Int64()}, parts...)
+		if rest.Sign() == 0 {
+			break
+		}
+	}
+	if amount.Sign() < 0 {
+		parts[0] = -parts[0].(int64)
+	}
+	return parts
+}
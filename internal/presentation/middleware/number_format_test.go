@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	handler := NumberFormat()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"value\":\"255\"}\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"data":[{"value":"1000000000000000001","value_formatted":"1.000000000000000001","block_number":7},`+
+			`{"value":"-255","change":"","balance":"12"}],"volume":"0"}`)
+	}))
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	for _, tc := range []struct {
+		query, want string
+	}{
+		{"", `{"data":[{"value":"1000000000000000001","value_formatted":"1.000000000000000001","block_number":7},{"value":"-255","change":"","balance":"12"}],"volume":"0"}`},
+		{"?number_format=string", `{"data":[{"value":"1000000000000000001","value_formatted":"1.000000000000000001","block_number":7},{"value":"-255","change":"","balance":"12"}],"volume":"0"}`},
+		{"?number_format=HEX", `{"data":[{"block_number":7,"value":"0xde0b6b3a7640001","value_formatted":"1.000000000000000001"},{"balance":"0xc","change":"","value":"-0xff"}],"volume":"0x0"}` + "\n"},
+		{"?number_format=decimal-parts", `{"data":[{"block_number":7,"value":[1000,1],"value_formatted":"1.000000000000000001"},{"balance":[12],"change":"","value":[-255]}],"volume":[0]}` + "\n"},
+	} {
+		rec := get("/transfers" + tc.query)
+		if rec.Code != http.StatusCreated || rec.Body.String() != tc.want {
+			t.Errorf("%q: expected 201 %s, got %d %s", tc.query, tc.want, rec.Code, rec.Body)
+		}
+	}
+
+	if rec := get("/stream?number_format=hex"); rec.Body.String() != "data: {\"value\":\"255\"}\n\n" {
+		t.Errorf("expected event streams passed through, got %s", rec.Body)
+	}
+	if rec := get("/transfers?number_format=float"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown format, got %d", rec.Code)
+	}
+}
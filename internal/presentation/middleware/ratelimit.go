@@ -7,7 +7,21 @@ import (
 	"github.com/go-chi/httprate"
 )
 
-// RateLimiter creates a rate limiting middleware
+// RateLimiter creates a rate limiting middleware keyed by client IP, as set by RealIP
 func RateLimiter(requestsPerSecond int) func(http.Handler) http.Handler {
-	return httprate.LimitByIP(requestsPerSecond, time.Second)
+	return httprate.Limit(requestsPerSecond, time.Second, httprate.WithKeyFuncs(keyByClientIP))
+}
+
+// keyByClientIP keys IPv4 clients by address and IPv6 clients by /64, the smallest block
+// usually assigned to one host, so a client cannot rotate addresses to escape its limit
+func keyByClientIP(r *http.Request) (string, error) {
+	ip, ok := remoteAddr(r)
+	if !ok {
+		return r.RemoteAddr, nil
+	}
+	if ip.Is6() {
+		prefix, _ := ip.Prefix(64)
+		return prefix.String(), nil
+	}
+	return ip.String(), nil
 }
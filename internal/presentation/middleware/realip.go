@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIP sets r.RemoteAddr to the client IP, without a port, for logging and rate limiting.
+// Forwarding headers are only believed when the connecting address is a trusted proxy: then
+// X-Forwarded-For is walked from the right, past trusted proxies, to the first address that
+// is not one, falling back to X-Real-IP when there is no X-Forwarded-For. Without trusted
+// proxies the connecting address is always the client, so clients cannot choose their own IP.
+func RealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if peer, ok := remoteAddr(r); ok {
+				r.RemoteAddr = clientIP(r, peer, trusted).String()
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP resolves the client behind peer, the connecting address
+func clientIP(r *http.Request, peer netip.Addr, trusted []netip.Prefix) netip.Addr {
+	if !isTrusted(peer, trusted) {
+		return peer
+	}
+
+	hops := forwardedFor(r)
+	if len(hops) == 0 {
+		if ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+			return ip.Unmap()
+		}
+		return peer
+	}
+
+	// Each proxy appends the address it received the request from, so entries left of the first
+	// untrusted one could have been sent by the client. An unparsable entry ends the walk at the
+	// proxy that appended it.
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, err := netip.ParseAddr(hops[i])
+		if err != nil {
+			break
+		}
+		client = ip.Unmap()
+		if !isTrusted(client, trusted) {
+			break
+		}
+	}
+	return client
+}
+
+// forwardedFor returns the X-Forwarded-For entries of all such headers, in order
+func forwardedFor(r *http.Request) []string {
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// remoteAddr parses the connecting address, with or without a port
+func remoteAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap(), true
+}
+
+func isTrusted(ip netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
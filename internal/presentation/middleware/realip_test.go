@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("fd00::/8")}
+
+	tests := []struct {
+		name       string
+		trusted    []netip.Prefix
+		remoteAddr string
+		forwarded  []string
+		realIP     string
+		want       string
+	}{
+		{"no trusted proxies ignores headers", nil, "203.0.113.9:4000", []string{"198.51.100.1"}, "198.51.100.2", "203.0.113.9"},
+		{"untrusted peer ignores headers", trusted, "203.0.113.9:4000", []string{"198.51.100.1"}, "", "203.0.113.9"},
+		{"trusted peer", trusted, "10.0.0.1:4000", []string{"198.51.100.1"}, "", "198.51.100.1"},
+		{"spoofed entries left of the client are skipped", trusted, "10.0.0.1:4000", []string{"1.2.3.4, 198.51.100.1, 10.0.0.2"}, "", "198.51.100.1"},
+		{"multiple headers", trusted, "10.0.0.1:4000", []string{"1.2.3.4", "198.51.100.1"}, "", "198.51.100.1"},
+		{"all trusted uses leftmost", trusted, "10.0.0.1:4000", []string{"10.0.0.3, 10.0.0.2"}, "", "10.0.0.3"},
+		{"garbage stops the walk", trusted, "10.0.0.1:4000", []string{"198.51.100.1, garbage, 10.0.0.2"}, "", "10.0.0.2"},
+		{"X-Real-IP without X-Forwarded-For", trusted, "10.0.0.1:4000", nil, "198.51.100.7", "198.51.100.7"},
+		{"no headers keeps the peer", trusted, "10.0.0.1:4000", nil, "", "10.0.0.1"},
+		{"IPv6 proxy and client", trusted, "[fd00::1]:4000", []string{"2001:db8::1"}, "", "2001:db8::1"},
+		{"IPv4-mapped peer", nil, "[::ffff:203.0.113.9]:4000", nil, "", "203.0.113.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := RealIP(tt.trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("expected client IP %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestKeyByClientIP(t *testing.T) {
+	tests := map[string]string{
+		"203.0.113.9":          "203.0.113.9",
+		"203.0.113.9:4000":     "203.0.113.9",
+		"2001:db8:1:2:3::4":    "2001:db8:1:2::/64",
+		"[2001:db8:1:2::9]:80": "2001:db8:1:2::/64",
+	}
+	for remoteAddr, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		if got, _ := keyByClientIP(req); got != want {
+			t.Errorf("keyByClientIP(%q) = %q, want %q", remoteAddr, got, want)
+		}
+	}
+}
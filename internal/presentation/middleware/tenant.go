@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/httprate"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// APIKeyHeader is the request header carrying a tenant API key
+const APIKeyHeader = "X-API-Key"
+
+// A client sending more than maxFailedKeyLookups unknown keys within failedKeyLookupWindow gets
+// 429 for the rest of the window without its keys being looked up, so keys cannot be guessed,
+// or the key lookup flooded, faster than that
+const (
+	maxFailedKeyLookups   = 20
+	failedKeyLookupWindow = time.Minute
+)
+
+// TenantResolver returns the tenant owning an API key, or nil if the key is unknown
+type TenantResolver interface {
+	ResolveAPIKey(ctx context.Context, key string) (*entities.Tenant, error)
+}
+
+// Tenants scopes requests carrying an API key to the key's tenant and rate limits them per
+// tenant, at the tenant's own rate or defaultRPS. Unknown keys get 401 with a JSON error body,
+// and clients sending too many of them get 429 before their keys are looked up. Requests
+// without a key are not scoped and are rate limited by IP at defaultRPS.
+func Tenants(resolver TenantResolver, defaultRPS int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		byIP := RateLimiter(defaultRPS)(next)
+		limiters := &tenantLimiters{next: next, byRate: make(map[int]http.Handler)}
+		failures := &failedLookups{counts: make(map[string]int)}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				byIP.ServeHTTP(w, r)
+				return
+			}
+
+			client, _ := keyByClientIP(r)
+			if failures.exceeded(client, time.Now()) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(failedKeyLookupWindow.Seconds())))
+				respondError(w, http.StatusTooManyRequests, "too many invalid API keys")
+				return
+			}
+
+			tenant, err := resolver.ResolveAPIKey(r.Context(), key)
+			if err != nil {
+				respondError(w, http.StatusServiceUnavailable, "failed to resolve API key")
+				return
+			}
+			if tenant == nil {
+				failures.add(client, time.Now())
+				respondError(w, http.StatusUnauthorized, "invalid API key")
+				return
+			}
+
+			noteTenant(r, tenant)
+
+			rps := tenant.RateLimitRPS
+			if rps <= 0 {
+				rps = defaultRPS
+			}
+			limiters.get(rps).ServeHTTP(w, r.WithContext(services.ContextWithTenant(r.Context(), tenant)))
+		})
+	}
+}
+
+// tenantLimiters holds one rate limiter per distinct tenant rate, each keyed by tenant ID
+type tenantLimiters struct {
+	next   http.Handler
+	mu     sync.Mutex
+	byRate map[int]http.Handler
+}
+
+func (l *tenantLimiters) get(rps int) http.Handler {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.byRate[rps]
+	if !ok {
+		limiter = httprate.Limit(rps, time.Second, httprate.WithKeyFuncs(keyByTenant))(l.next)
+		l.byRate[rps] = limiter
+	}
+	return limiter
+}
+
+// failedLookups counts unknown keys per client in fixed windows of failedKeyLookupWindow; the
+// counts are dropped as each window ends, so they only ever hold the current window's clients
+type failedLookups struct {
+	mu     sync.Mutex
+	window time.Time
+	counts map[string]int
+}
+
+func (f *failedLookups) exceeded(client string, now time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.roll(now)
+	return f.counts[client] >= maxFailedKeyLookups
+}
+
+func (f *failedLookups) add(client string, now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.roll(now)
+	f.counts[client]++
+}
+
+func (f *failedLookups) roll(now time.Time) {
+	if window := now.Truncate(failedKeyLookupWindow); !window.Equal(f.window) {
+		f.window = window
+		clear(f.counts)
+	}
+}
+
+func keyByTenant(r *http.Request) (string, error) {
+	return "tenant:" + strconv.FormatInt(services.TenantFromContext(r.Context()).ID, 10), nil
+}
+
+// respondError writes a JSON error body, as the handlers do
+func respondError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+type stubResolver map[string]*entities.Tenant
+
+func (s stubResolver) ResolveAPIKey(ctx context.Context, key string) (*entities.Tenant, error) {
+	if key == "broken" {
+		return nil, errors.New("database error")
+	}
+	return s[key], nil
+}
+
+// countingResolver counts the lookups reaching it
+type countingResolver struct {
+	stubResolver
+	lookups int
+}
+
+func (c *countingResolver) ResolveAPIKey(ctx context.Context, key string) (*entities.Tenant, error) {
+	c.lookups++
+	return c.stubResolver.ResolveAPIKey(ctx, key)
+}
+
+func TestTenants(t *testing.T) {
+	resolver := stubResolver{"acme-key": {ID: 7, Name: "acme"}}
+
+	var scoped *entities.Tenant
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scoped = services.TenantFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		key        string
+		want       int
+		wantTenant int64
+	}{
+		{"no key", "", http.StatusOK, 0},
+		{"known key", "acme-key", http.StatusOK, 7},
+		{"unknown key", "other", http.StatusUnauthorized, 0},
+		{"resolver failure", "broken", http.StatusServiceUnavailable, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scoped = nil
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tokens", nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			Tenants(resolver, 100)(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("expected status %d, got %d", tt.want, rec.Code)
+			}
+			var got int64
+			if scoped != nil {
+				got = scoped.ID
+			}
+			if got != tt.wantTenant {
+				t.Errorf("expected tenant %d, got %d", tt.wantTenant, got)
+			}
+		})
+	}
+}
+
+func TestTenants_ThrottlesFailedLookups(t *testing.T) {
+	resolver := &countingResolver{stubResolver: stubResolver{"acme-key": {ID: 7, Name: "acme"}}}
+	handler := Tenants(resolver, 100)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(key, remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tokens", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(APIKeyHeader, key)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < maxFailedKeyLookups; i++ {
+		if code := send(fmt.Sprintf("guess-%d", i), "192.0.2.1:1234"); code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: expected 401, got %d", i, code)
+		}
+	}
+
+	// Past the limit the client's keys are not looked up at all, even valid ones
+	before := resolver.lookups
+	if code := send("guess-more", "192.0.2.1:1234"); code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 past the limit, got %d", code)
+	}
+	if code := send("acme-key", "192.0.2.1:5678"); code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 for the throttled client, got %d", code)
+	}
+	if resolver.lookups != before {
+		t.Errorf("expected no lookups for a throttled client, got %d", resolver.lookups-before)
+	}
+
+	// Other clients are unaffected
+	if code := send("acme-key", "198.51.100.7:1234"); code != http.StatusOK {
+		t.Errorf("expected another client served, got %d", code)
+	}
+}
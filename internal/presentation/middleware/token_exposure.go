@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+// TokenExposure answers 404 for requests about tokens exposure does not expose: tokens named by
+// a path segment following "tokens", as in /api/v1/tokens/{address}/holders, or by the token query
+// parameter. Requests carrying adminToken as "Authorization: Bearer <token>" are marked with
+// services.ContextWithAdmin and see every token; an empty adminToken marks none.
+func TokenExposure(exposure *services.TokenExposure, adminToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && adminToken != "" &&
+				subtle.ConstantTimeCompare([]byte(given), []byte(adminToken)) == 1 {
+				next.ServeHTTP(w, r.WithContext(services.ContextWithAdmin(r.Context())))
+				return
+			}
+
+			for _, address := range requestedTokens(r) {
+				if !exposure.Exposes(r.Context(), address) {
+					respondError(w, http.StatusNotFound, "token not found")
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestedTokens returns the token addresses a request names in its path or token parameter
+func requestedTokens(r *http.Request) []string {
+	var addresses []string
+	segments := strings.Split(r.URL.Path, "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "tokens" && looksLikeAddress(segments[i+1]) {
+			addresses = append(addresses, segments[i+1])
+		}
+	}
+	for _, v := range r.URL.Query()["token"] {
+		if looksLikeAddress(v) {
+			addresses = append(addresses, v)
+		}
+	}
+	return addresses
+}
+
+// looksLikeAddress reports whether s has the shape of an address, as the handlers check it
+func looksLikeAddress(s string) bool {
+	return len(s) == 42 && strings.HasPrefix(s, "0x")
+}
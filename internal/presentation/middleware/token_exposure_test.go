@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bimakw/chain-indexer/internal/application/services"
+)
+
+const (
+	exposedToken = "0xdac17f958d2ee523a2206206994597c13d831ec7"
+	hiddenToken  = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+)
+
+func TestTokenExposure(t *testing.T) {
+	exposure, err := services.NewTokenExposure([]string{"0xdAC17F958D2ee523a2206206994597C13D831ec7"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sawAdmin bool
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawAdmin = services.IsAdmin(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := TokenExposure(exposure, "s3cret")(ok)
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+		admin  bool
+	}{
+		{"exposed token", "/api/v1/tokens/" + exposedToken + "/holders", "", http.StatusOK, false},
+		{"hidden token", "/api/v1/tokens/" + hiddenToken, "", http.StatusNotFound, false},
+		{"hidden token in a wallet route", "/api/v1/wallets/0x1111111111111111111111111111111111111111/portfolio/tokens/" + hiddenToken, "", http.StatusNotFound, false},
+		{"hidden token parameter", "/api/v2/transfers?token=" + hiddenToken, "", http.StatusNotFound, false},
+		{"no token", "/api/v1/transfers", "", http.StatusOK, false},
+		{"admin token", "/api/v1/tokens/" + hiddenToken, "Bearer s3cret", http.StatusOK, true},
+		{"wrong admin token", "/api/v1/tokens/" + hiddenToken, "Bearer other", http.StatusNotFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sawAdmin = false
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, rec.Code)
+			}
+			if sawAdmin != tt.admin {
+				t.Errorf("expected admin %v, got %v", tt.admin, sawAdmin)
+			}
+		})
+	}
+}
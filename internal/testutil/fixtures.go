@@ -16,6 +16,18 @@ const (
 	CharlieAddr  = "0x3333333333333333333333333333333333333333"
 )
 
+// ERC20EventsABI is the JSON ABI of the ERC-20 Transfer and Approval events
+const ERC20EventsABI = `[
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[
+		{"name":"from","type":"address","indexed":true},
+		{"name":"to","type":"address","indexed":true},
+		{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Approval","anonymous":false,"inputs":[
+		{"name":"owner","type":"address","indexed":true},
+		{"name":"spender","type":"address","indexed":true},
+		{"name":"value","type":"uint256","indexed":false}]}
+]`
+
 // CreateTestTransfer creates a test transfer with default values
 func CreateTestTransfer(opts ...TransferOption) entities.Transfer {
 	t := entities.Transfer{
@@ -89,6 +101,18 @@ func WithToAddress(addr string) TransferOption {
 	}
 }
 
+func WithInitiator(addr string) TransferOption {
+	return func(t *entities.Transfer) {
+		t.Initiator = &addr
+	}
+}
+
+func WithMethodSelector(selector string) TransferOption {
+	return func(t *entities.Transfer) {
+		t.MethodSelector = &selector
+	}
+}
+
 func WithValue(val *big.Int) TransferOption {
 	return func(t *entities.Transfer) {
 		t.Value = val
@@ -108,6 +132,7 @@ func CreateTestToken(opts ...TokenOption) *entities.Token {
 		TotalIndexedTransfers: 0,
 		FirstSeenBlock:        &firstSeenBlock,
 		LastSeenBlock:         &lastSeenBlock,
+		Active:                true,
 		CreatedAt:             time.Now(),
 		UpdatedAt:             time.Now(),
 	}
@@ -145,6 +170,12 @@ func TokenWithDecimals(dec int) TokenOption {
 	}
 }
 
+func TokenInactive() TokenOption {
+	return func(t *entities.Token) {
+		t.Active = false
+	}
+}
+
 func TokenWithTotalTransfers(count int64) TokenOption {
 	return func(t *entities.Token) {
 		t.TotalIndexedTransfers = count
@@ -1,12 +1,22 @@
 package testutil
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
 	"github.com/bimakw/chain-indexer/internal/domain/repositories"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/ethereum"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/notify"
+	"github.com/bimakw/chain-indexer/internal/infrastructure/pricing"
 )
 
 // MockTransferRepository is a mock implementation of TransferRepository
@@ -17,13 +27,25 @@ type MockTransferRepository struct {
 	// Function hooks for custom behavior
 	GetByFilterFunc             func(ctx context.Context, filter entities.TransferFilter) ([]entities.Transfer, error)
 	GetCountFunc                func(ctx context.Context, filter entities.TransferFilter) (int64, error)
-	BatchInsertFunc             func(ctx context.Context, transfers []entities.Transfer) error
+	BatchInsertFunc             func(ctx context.Context, transfers []entities.Transfer) (int64, error)
+	ReplaceRangeFunc            func(ctx context.Context, tokenAddress string, fromBlock, toBlock int64, transfers []entities.Transfer) (int64, int64, error)
 	GetLatestBlockFunc          func(ctx context.Context, tokenAddress string) (int64, error)
+	GetLatestIDFunc             func(ctx context.Context) (int64, error)
+	GetAfterIDFunc              func(ctx context.Context, filter entities.TransferFilter, afterID int64) ([]entities.Transfer, error)
 	GetTokenStatsFunc           func(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error)
+	GetWindowStatsFunc          func(ctx context.Context, tokenAddress string, from, to time.Time) (*repositories.WindowStats, error)
+	GetActivityHeatmapFunc      func(ctx context.Context, tokenAddress string, from, to time.Time) ([]repositories.HeatmapBucket, error)
+	GetActiveAddressesFunc      func(ctx context.Context, tokenAddress string, from, to time.Time) ([]string, error)
 	GetTopHoldersFunc           func(ctx context.Context, tokenAddress string, limit int) ([]repositories.HolderBalance, error)
 	GetHolderBalanceFunc        func(ctx context.Context, tokenAddress, holderAddress string) (*repositories.HolderBalance, error)
-	GetHolderCountFunc          func(ctx context.Context, tokenAddress string) (int64, error)
-	GetTopHoldersWithOffsetFunc func(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error)
+	GetHolderCountFunc          func(ctx context.Context, tokenAddress string, exclude []string) (int64, error)
+	GetCirculatingSupplyFunc    func(ctx context.Context, tokenAddress string) (string, error)
+	GetDormantSupplyFunc        func(ctx context.Context, tokenAddress string, inactiveSince time.Time) (*repositories.DormantSupply, error)
+	GetTransferPatternsFunc     func(ctx context.Context, tokenAddress string, from, to time.Time, fanOut int) (*repositories.TransferPatterns, error)
+	GetTopHoldersWithOffsetFunc func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error)
+	GetNegativeBalancesFunc     func(ctx context.Context, tokenAddress string, limit int) (*repositories.NegativeBalances, error)
+	GetBalanceChangesFunc       func(ctx context.Context, tokenAddress string, since time.Time, limit int) ([]repositories.BalanceChange, error)
+	StreamHolderHistoryFunc     func(ctx context.Context, tokenAddress, holderAddress string, filter repositories.HolderHistoryFilter, fn func(repositories.HolderBalanceEvent) error) error
 
 	// Call tracking
 	Calls []MockCall
@@ -56,22 +78,7 @@ func (m *MockTransferRepository) GetByFilter(ctx context.Context, filter entitie
 	// Simple filtering implementation
 	result := make([]entities.Transfer, 0)
 	for _, t := range m.transfers {
-		if filter.TokenAddress != nil && t.TokenAddress != *filter.TokenAddress {
-			continue
-		}
-		if filter.FromAddress != nil && t.FromAddress != *filter.FromAddress {
-			continue
-		}
-		if filter.ToAddress != nil && t.ToAddress != *filter.ToAddress {
-			continue
-		}
-		if filter.Address != nil && t.FromAddress != *filter.Address && t.ToAddress != *filter.Address {
-			continue
-		}
-		if filter.FromBlock != nil && t.BlockNumber < *filter.FromBlock {
-			continue
-		}
-		if filter.ToBlock != nil && t.BlockNumber > *filter.ToBlock {
+		if !matchesTransferFilter(t, filter) {
 			continue
 		}
 		result = append(result, t)
@@ -90,6 +97,74 @@ func (m *MockTransferRepository) GetByFilter(ctx context.Context, filter entitie
 	return result[start:end], nil
 }
 
+func containsAddress(addresses []string, addr string) bool {
+	for _, a := range addresses {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTransferFilter applies a filter's conditions, ignoring Limit and Offset
+func matchesTransferFilter(t entities.Transfer, filter entities.TransferFilter) bool {
+	if filter.TokenAddress != nil && t.TokenAddress != *filter.TokenAddress {
+		return false
+	}
+	if len(filter.Tokens) > 0 && !containsAddress(filter.Tokens, t.TokenAddress) {
+		return false
+	}
+	if containsAddress(filter.ExcludeTokens, t.TokenAddress) {
+		return false
+	}
+	if filter.FromAddress != nil && t.FromAddress != *filter.FromAddress {
+		return false
+	}
+	if filter.ToAddress != nil && t.ToAddress != *filter.ToAddress {
+		return false
+	}
+	if filter.Address != nil && t.FromAddress != *filter.Address && t.ToAddress != *filter.Address {
+		return false
+	}
+	if len(filter.Addresses) > 0 && !containsAddress(filter.Addresses, t.FromAddress) && !containsAddress(filter.Addresses, t.ToAddress) {
+		return false
+	}
+	if filter.Initiator != nil && (t.Initiator == nil || *t.Initiator != *filter.Initiator) {
+		return false
+	}
+	if filter.Selector != nil && (t.MethodSelector == nil || *t.MethodSelector != *filter.Selector) {
+		return false
+	}
+	if filter.FromBlock != nil && t.BlockNumber < *filter.FromBlock {
+		return false
+	}
+	if filter.ToBlock != nil && t.BlockNumber > *filter.ToBlock {
+		return false
+	}
+	if filter.FromTime != nil && t.BlockTimestamp.Before(*filter.FromTime) {
+		return false
+	}
+	if filter.ToTime != nil && t.BlockTimestamp.After(*filter.ToTime) {
+		return false
+	}
+	if filter.MinValue != nil && transferValue(t).Cmp(filter.MinValue) < 0 {
+		return false
+	}
+	if filter.MaxValue != nil && transferValue(t).Cmp(filter.MaxValue) > 0 {
+		return false
+	}
+	if filter.ExcludeZero && transferValue(t).Sign() == 0 {
+		return false
+	}
+	if filter.ExcludeSelf && t.FromAddress == t.ToAddress {
+		return false
+	}
+	if filter.After != nil && !filter.After.Precedes(t) {
+		return false
+	}
+	return true
+}
+
 func (m *MockTransferRepository) GetCount(ctx context.Context, filter entities.TransferFilter) (int64, error) {
 	m.mu.Lock()
 	m.Calls = append(m.Calls, MockCall{Method: "GetCount", Args: []interface{}{filter}})
@@ -100,16 +175,26 @@ func (m *MockTransferRepository) GetCount(ctx context.Context, filter entities.T
 	}
 
 	transfers, err := m.GetByFilter(ctx, entities.TransferFilter{
-		TokenAddress: filter.TokenAddress,
-		FromAddress:  filter.FromAddress,
-		ToAddress:    filter.ToAddress,
-		Address:      filter.Address,
-		FromBlock:    filter.FromBlock,
-		ToBlock:      filter.ToBlock,
-		FromTime:     filter.FromTime,
-		ToTime:       filter.ToTime,
-		Limit:        1000000,
-		Offset:       0,
+		TokenAddress:  filter.TokenAddress,
+		Tokens:        filter.Tokens,
+		ExcludeTokens: filter.ExcludeTokens,
+		FromAddress:   filter.FromAddress,
+		ToAddress:     filter.ToAddress,
+		Address:       filter.Address,
+		Addresses:     filter.Addresses,
+		Initiator:     filter.Initiator,
+		Selector:      filter.Selector,
+		Method:        filter.Method,
+		FromBlock:     filter.FromBlock,
+		ToBlock:       filter.ToBlock,
+		FromTime:      filter.FromTime,
+		ToTime:        filter.ToTime,
+		MinValue:      filter.MinValue,
+		MaxValue:      filter.MaxValue,
+		ExcludeZero:   filter.ExcludeZero,
+		ExcludeSelf:   filter.ExcludeSelf,
+		Limit:         1000000,
+		Offset:        0,
 	})
 	if err != nil {
 		return 0, err
@@ -117,7 +202,7 @@ func (m *MockTransferRepository) GetCount(ctx context.Context, filter entities.T
 	return int64(len(transfers)), nil
 }
 
-func (m *MockTransferRepository) BatchInsert(ctx context.Context, transfers []entities.Transfer) error {
+func (m *MockTransferRepository) BatchInsert(ctx context.Context, transfers []entities.Transfer) (int64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -128,7 +213,30 @@ func (m *MockTransferRepository) BatchInsert(ctx context.Context, transfers []en
 	}
 
 	m.transfers = append(m.transfers, transfers...)
-	return nil
+	return int64(len(transfers)), nil
+}
+
+func (m *MockTransferRepository) ReplaceRange(ctx context.Context, tokenAddress string, fromBlock, toBlock int64, transfers []entities.Transfer) (int64, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "ReplaceRange", Args: []interface{}{tokenAddress, fromBlock, toBlock, transfers}})
+
+	if m.ReplaceRangeFunc != nil {
+		return m.ReplaceRangeFunc(ctx, tokenAddress, fromBlock, toBlock, transfers)
+	}
+
+	kept := make([]entities.Transfer, 0, len(m.transfers))
+	var deleted int64
+	for _, t := range m.transfers {
+		if t.TokenAddress == tokenAddress && t.BlockNumber >= fromBlock && t.BlockNumber <= toBlock {
+			deleted++
+			continue
+		}
+		kept = append(kept, t)
+	}
+	m.transfers = append(kept, transfers...)
+	return deleted, int64(len(transfers)), nil
 }
 
 func (m *MockTransferRepository) GetLatestBlock(ctx context.Context, tokenAddress string) (int64, error) {
@@ -152,6 +260,52 @@ func (m *MockTransferRepository) GetLatestBlock(ctx context.Context, tokenAddres
 	return latest, nil
 }
 
+func (m *MockTransferRepository) GetLatestID(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetLatestID", Args: nil})
+	m.mu.Unlock()
+
+	if m.GetLatestIDFunc != nil {
+		return m.GetLatestIDFunc(ctx)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest int64
+	for _, t := range m.transfers {
+		if t.ID > latest {
+			latest = t.ID
+		}
+	}
+	return latest, nil
+}
+
+func (m *MockTransferRepository) GetAfterID(ctx context.Context, filter entities.TransferFilter, afterID int64) ([]entities.Transfer, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetAfterID", Args: []interface{}{filter, afterID}})
+	m.mu.Unlock()
+
+	if m.GetAfterIDFunc != nil {
+		return m.GetAfterIDFunc(ctx, filter, afterID)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]entities.Transfer, 0)
+	for _, t := range m.transfers {
+		if t.ID > afterID && matchesTransferFilter(t, filter) {
+			result = append(result, t)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
 func (m *MockTransferRepository) GetTokenStats(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error) {
 	m.mu.Lock()
 	m.Calls = append(m.Calls, MockCall{Method: "GetTokenStats", Args: []interface{}{tokenAddress}})
@@ -188,40 +342,92 @@ func (m *MockTransferRepository) GetTokenStats(ctx context.Context, tokenAddress
 	}, nil
 }
 
-func (m *MockTransferRepository) GetTopHolders(ctx context.Context, tokenAddress string, limit int) ([]repositories.HolderBalance, error) {
+func (m *MockTransferRepository) GetWindowStats(ctx context.Context, tokenAddress string, from, to time.Time) (*repositories.WindowStats, error) {
 	m.mu.Lock()
-	m.Calls = append(m.Calls, MockCall{Method: "GetTopHolders", Args: []interface{}{tokenAddress, limit}})
+	m.Calls = append(m.Calls, MockCall{Method: "GetWindowStats", Args: []interface{}{tokenAddress, from, to}})
 	m.mu.Unlock()
 
-	if m.GetTopHoldersFunc != nil {
-		return m.GetTopHoldersFunc(ctx, tokenAddress, limit)
+	if m.GetWindowStatsFunc != nil {
+		return m.GetWindowStatsFunc(ctx, tokenAddress, from, to)
 	}
 
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Calculate balances from transfers
-	balances := make(map[string]int64)
+	// Default mock implementation - sum stored transfers in the window
+	var count int64
+	volume := new(big.Int)
 	for _, t := range m.transfers {
-		if t.TokenAddress == tokenAddress {
-			balances[t.ToAddress]++
-			balances[t.FromAddress]--
+		if t.TokenAddress != tokenAddress || t.BlockTimestamp.Before(from) || !t.BlockTimestamp.Before(to) {
+			continue
+		}
+		count++
+		if v, ok := new(big.Int).SetString(t.ValueString, 10); ok {
+			volume.Add(volume, v)
 		}
 	}
 
-	// Build result
-	var result []repositories.HolderBalance
-	rank := 1
-	for addr, bal := range balances {
-		if bal > 0 {
-			result = append(result, repositories.HolderBalance{
-				Address: addr,
-				Balance: "1000000000000000000", // Mock balance
-				Rank:    rank,
-			})
-			rank++
-			if rank > limit {
-				break
+	return &repositories.WindowStats{
+		Transfers: count,
+		Volume:    volume.String(),
+	}, nil
+}
+
+func (m *MockTransferRepository) GetActivityHeatmap(ctx context.Context, tokenAddress string, from, to time.Time) ([]repositories.HeatmapBucket, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetActivityHeatmap", Args: []interface{}{tokenAddress, from, to}})
+	m.mu.Unlock()
+
+	if m.GetActivityHeatmapFunc != nil {
+		return m.GetActivityHeatmapFunc(ctx, tokenAddress, from, to)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Default mock implementation - bucket stored transfers in the window
+	var counts [7][24]int64
+	for _, t := range m.transfers {
+		if t.TokenAddress != tokenAddress || t.BlockTimestamp.Before(from) || !t.BlockTimestamp.Before(to) {
+			continue
+		}
+		ts := t.BlockTimestamp.UTC()
+		counts[ts.Weekday()][ts.Hour()]++
+	}
+
+	var buckets []repositories.HeatmapBucket
+	for day := range counts {
+		for hour, n := range counts[day] {
+			if n > 0 {
+				buckets = append(buckets, repositories.HeatmapBucket{DayOfWeek: day, Hour: hour, Transfers: n})
+			}
+		}
+	}
+	return buckets, nil
+}
+
+func (m *MockTransferRepository) GetActiveAddresses(ctx context.Context, tokenAddress string, from, to time.Time) ([]string, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetActiveAddresses", Args: []interface{}{tokenAddress, from, to}})
+	m.mu.Unlock()
+
+	if m.GetActiveAddressesFunc != nil {
+		return m.GetActiveAddressesFunc(ctx, tokenAddress, from, to)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, t := range m.transfers {
+		if t.TokenAddress != tokenAddress || t.BlockTimestamp.Before(from) || !t.BlockTimestamp.Before(to) {
+			continue
+		}
+		for _, addr := range []string{t.FromAddress, t.ToAddress} {
+			if addr != entities.ZeroAddress && !seen[addr] {
+				seen[addr] = true
+				result = append(result, addr)
 			}
 		}
 	}
@@ -229,6 +435,21 @@ func (m *MockTransferRepository) GetTopHolders(ctx context.Context, tokenAddress
 	return result, nil
 }
 
+func (m *MockTransferRepository) GetTopHolders(ctx context.Context, tokenAddress string, limit int) ([]repositories.HolderBalance, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetTopHolders", Args: []interface{}{tokenAddress, limit}})
+	m.mu.Unlock()
+
+	if m.GetTopHoldersFunc != nil {
+		return m.GetTopHoldersFunc(ctx, tokenAddress, limit)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return pageHolders(m.rankedHolders(tokenAddress, nil), limit, 0), nil
+}
+
 func (m *MockTransferRepository) GetHolderBalance(ctx context.Context, tokenAddress, holderAddress string) (*repositories.HolderBalance, error) {
 	m.mu.Lock()
 	m.Calls = append(m.Calls, MockCall{Method: "GetHolderBalance", Args: []interface{}{tokenAddress, holderAddress}})
@@ -245,202 +466,549 @@ func (m *MockTransferRepository) GetHolderBalance(ctx context.Context, tokenAddr
 	}, nil
 }
 
-func (m *MockTransferRepository) GetHolderCount(ctx context.Context, tokenAddress string) (int64, error) {
+func (m *MockTransferRepository) GetHolderCount(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
 	m.mu.Lock()
-	m.Calls = append(m.Calls, MockCall{Method: "GetHolderCount", Args: []interface{}{tokenAddress}})
+	m.Calls = append(m.Calls, MockCall{Method: "GetHolderCount", Args: []interface{}{tokenAddress, exclude}})
 	m.mu.Unlock()
 
 	if m.GetHolderCountFunc != nil {
-		return m.GetHolderCountFunc(ctx, tokenAddress)
+		return m.GetHolderCountFunc(ctx, tokenAddress, exclude)
 	}
 
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Count unique addresses with positive balance from transfers
-	balances := make(map[string]int64)
-	for _, t := range m.transfers {
-		if t.TokenAddress == tokenAddress {
-			balances[t.ToAddress]++
-			balances[t.FromAddress]--
-		}
-	}
-
-	var count int64
-	for _, bal := range balances {
-		if bal > 0 {
-			count++
-		}
-	}
-	return count, nil
+	return int64(len(m.rankedHolders(tokenAddress, exclude))), nil
 }
 
-func (m *MockTransferRepository) GetTopHoldersWithOffset(ctx context.Context, tokenAddress string, limit, offset int) ([]repositories.HolderBalance, error) {
+func (m *MockTransferRepository) GetCirculatingSupply(ctx context.Context, tokenAddress string) (string, error) {
 	m.mu.Lock()
-	m.Calls = append(m.Calls, MockCall{Method: "GetTopHoldersWithOffset", Args: []interface{}{tokenAddress, limit, offset}})
+	m.Calls = append(m.Calls, MockCall{Method: "GetCirculatingSupply", Args: []interface{}{tokenAddress}})
 	m.mu.Unlock()
 
-	if m.GetTopHoldersWithOffsetFunc != nil {
-		return m.GetTopHoldersWithOffsetFunc(ctx, tokenAddress, limit, offset)
+	if m.GetCirculatingSupplyFunc != nil {
+		return m.GetCirculatingSupplyFunc(ctx, tokenAddress)
 	}
 
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Calculate balances from transfers
-	balances := make(map[string]int64)
+	// Mints minus burns
+	supply := new(big.Int)
 	for _, t := range m.transfers {
-		if t.TokenAddress == tokenAddress {
-			balances[t.ToAddress]++
-			balances[t.FromAddress]--
+		if t.TokenAddress != tokenAddress || t.Value == nil {
+			continue
 		}
-	}
-
-	// Build result
-	var result []repositories.HolderBalance
-	rank := offset + 1
-	skipped := 0
-	for addr, bal := range balances {
-		if bal > 0 {
-			if skipped < offset {
-				skipped++
-				continue
-			}
-			result = append(result, repositories.HolderBalance{
-				Address: addr,
-				Balance: "1000000000000000000", // Mock balance
-				Rank:    rank,
-			})
-			rank++
-			if len(result) >= limit {
-				break
-			}
+		if t.FromAddress == entities.ZeroAddress {
+			supply.Add(supply, t.Value)
+		}
+		if t.ToAddress == entities.ZeroAddress {
+			supply.Sub(supply, t.Value)
 		}
 	}
-
-	return result, nil
+	return supply.String(), nil
 }
 
-// AddTransfers adds transfers to the mock store
-func (m *MockTransferRepository) AddTransfers(transfers ...entities.Transfer) {
+func (m *MockTransferRepository) GetCirculatingSupplyAt(ctx context.Context, tokenAddress string, at time.Time) (string, error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.transfers = append(m.transfers, transfers...)
-}
+	m.Calls = append(m.Calls, MockCall{Method: "GetCirculatingSupplyAt", Args: []interface{}{tokenAddress, at}})
+	m.mu.Unlock()
 
-// Reset clears all stored data and calls
-func (m *MockTransferRepository) Reset() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.transfers = make([]entities.Transfer, 0)
-	m.Calls = make([]MockCall, 0)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	supply := new(big.Int)
+	for _, t := range m.transfers {
+		if t.TokenAddress != tokenAddress || !t.BlockTimestamp.Before(at) {
+			continue
+		}
+		if t.FromAddress == entities.ZeroAddress {
+			supply.Add(supply, transferValue(t))
+		}
+		if t.ToAddress == entities.ZeroAddress {
+			supply.Sub(supply, transferValue(t))
+		}
+	}
+	return supply.String(), nil
 }
 
-// MockTokenRepository is a mock implementation of TokenRepository
-type MockTokenRepository struct {
-	mu     sync.RWMutex
-	tokens map[string]*entities.Token
+func (m *MockTransferRepository) GetDormantSupply(ctx context.Context, tokenAddress string, inactiveSince time.Time) (*repositories.DormantSupply, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetDormantSupply", Args: []interface{}{tokenAddress, inactiveSince}})
+	m.mu.Unlock()
 
-	// Function hooks
-	GetByAddressFunc    func(ctx context.Context, address string) (*entities.Token, error)
-	GetAllFunc          func(ctx context.Context) ([]entities.Token, error)
-	GetAllPaginatedFunc func(ctx context.Context, limit, offset int, sortBy, sortOrder string) ([]*entities.Token, int64, error)
-	CountFunc           func(ctx context.Context) (int64, error)
-	UpsertFunc          func(ctx context.Context, token *entities.Token) error
-	UpdateStatsFunc     func(ctx context.Context, address string, transferCount int64, lastBlock int64) error
+	if m.GetDormantSupplyFunc != nil {
+		return m.GetDormantSupplyFunc(ctx, tokenAddress, inactiveSince)
+	}
 
-	Calls []MockCall
-}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-func NewMockTokenRepository() *MockTokenRepository {
-	return &MockTokenRepository{
-		tokens: make(map[string]*entities.Token),
-		Calls:  make([]MockCall, 0),
+	active := make(map[string]bool)
+	for _, t := range m.transfers {
+		if t.TokenAddress == tokenAddress && !t.BlockTimestamp.Before(inactiveSince) {
+			active[t.FromAddress] = true
+			active[t.ToAddress] = true
+		}
+	}
+	dormant := &repositories.DormantSupply{}
+	total := new(big.Int)
+	for _, h := range m.rankedHolders(tokenAddress, []string{entities.ZeroAddress}) {
+		if active[h.Address] {
+			continue
+		}
+		if v, ok := new(big.Int).SetString(h.Balance, 10); ok {
+			total.Add(total, v)
+		}
+		dormant.Holders++
 	}
+	dormant.Balance = total.String()
+	return dormant, nil
 }
 
-func (m *MockTokenRepository) GetByAddress(ctx context.Context, address string) (*entities.Token, error) {
+func (m *MockTransferRepository) GetTransferPatterns(ctx context.Context, tokenAddress string, from, to time.Time, fanOut int) (*repositories.TransferPatterns, error) {
 	m.mu.Lock()
-	m.Calls = append(m.Calls, MockCall{Method: "GetByAddress", Args: []interface{}{address}})
+	m.Calls = append(m.Calls, MockCall{Method: "GetTransferPatterns", Args: []interface{}{tokenAddress, from, to, fanOut}})
 	m.mu.Unlock()
 
-	if m.GetByAddressFunc != nil {
-		return m.GetByAddressFunc(ctx, address)
+	if m.GetTransferPatternsFunc != nil {
+		return m.GetTransferPatternsFunc(ctx, tokenAddress, from, to, fanOut)
 	}
 
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if token, ok := m.tokens[address]; ok {
-		return token, nil
+	type sender struct{ txHash, from string }
+	selfVolume := new(big.Int)
+	groups := make(map[sender][]entities.Transfer)
+	patterns := &repositories.TransferPatterns{}
+	for _, t := range m.transfers {
+		if t.TokenAddress != tokenAddress || t.BlockTimestamp.Before(from) || !t.BlockTimestamp.Before(to) {
+			continue
+		}
+		if t.FromAddress == t.ToAddress {
+			patterns.SelfTransfers++
+			selfVolume.Add(selfVolume, transferValue(t))
+		}
+		key := sender{t.TxHash, t.FromAddress}
+		groups[key] = append(groups[key], t)
 	}
-	return nil, nil
+	fanOutVolume := new(big.Int)
+	for _, group := range groups {
+		if len(group) <= fanOut {
+			continue
+		}
+		patterns.FanOutTransactions++
+		patterns.FanOutTransfers += int64(len(group))
+		for _, t := range group {
+			fanOutVolume.Add(fanOutVolume, transferValue(t))
+		}
+	}
+	patterns.SelfTransferVolume = selfVolume.String()
+	patterns.FanOutVolume = fanOutVolume.String()
+	return patterns, nil
 }
 
-func (m *MockTokenRepository) GetAll(ctx context.Context) ([]entities.Token, error) {
+func (m *MockTransferRepository) GetTopHoldersWithOffset(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
 	m.mu.Lock()
-	m.Calls = append(m.Calls, MockCall{Method: "GetAll", Args: nil})
+	m.Calls = append(m.Calls, MockCall{Method: "GetTopHoldersWithOffset", Args: []interface{}{tokenAddress, limit, offset, exclude}})
 	m.mu.Unlock()
 
-	if m.GetAllFunc != nil {
-		return m.GetAllFunc(ctx)
+	if m.GetTopHoldersWithOffsetFunc != nil {
+		return m.GetTopHoldersWithOffsetFunc(ctx, tokenAddress, limit, offset, exclude)
 	}
 
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	result := make([]entities.Token, 0, len(m.tokens))
-	for _, token := range m.tokens {
-		result = append(result, *token)
-	}
-	return result, nil
+	return pageHolders(m.rankedHolders(tokenAddress, exclude), limit, offset), nil
 }
 
-func (m *MockTokenRepository) GetAllPaginated(ctx context.Context, limit, offset int, sortBy, sortOrder string) ([]*entities.Token, int64, error) {
+func (m *MockTransferRepository) GetBalanceChanges(ctx context.Context, tokenAddress string, since time.Time, limit int) ([]repositories.BalanceChange, error) {
 	m.mu.Lock()
-	m.Calls = append(m.Calls, MockCall{Method: "GetAllPaginated", Args: []interface{}{limit, offset, sortBy, sortOrder}})
+	m.Calls = append(m.Calls, MockCall{Method: "GetBalanceChanges", Args: []interface{}{tokenAddress, since, limit}})
 	m.mu.Unlock()
 
-	if m.GetAllPaginatedFunc != nil {
-		return m.GetAllPaginatedFunc(ctx, limit, offset, sortBy, sortOrder)
+	if m.GetBalanceChangesFunc != nil {
+		return m.GetBalanceChangesFunc(ctx, tokenAddress, since, limit)
 	}
 
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	result := make([]*entities.Token, 0, len(m.tokens))
-	for _, token := range m.tokens {
-		result = append(result, token)
+	changes := make(map[string]*big.Int)
+	add := func(addr string, v *big.Int) {
+		if addr == entities.ZeroAddress {
+			return
+		}
+		if changes[addr] == nil {
+			changes[addr] = new(big.Int)
+		}
+		changes[addr].Add(changes[addr], v)
+	}
+	for _, t := range m.transfers {
+		if t.TokenAddress != tokenAddress || t.BlockTimestamp.Before(since) {
+			continue
+		}
+		v := transferValue(t)
+		add(t.ToAddress, v)
+		add(t.FromAddress, new(big.Int).Neg(v))
 	}
 
-	total := int64(len(result))
-
-	// Apply pagination
-	start := offset
-	if start > len(result) {
-		return []*entities.Token{}, total, nil
+	var result []repositories.BalanceChange
+	for addr, change := range changes {
+		if change.Sign() != 0 {
+			result = append(result, repositories.BalanceChange{Address: addr, Change: change.String()})
+		}
 	}
-	end := start + limit
-	if end > len(result) {
-		end = len(result)
+	sort.Slice(result, func(i, j int) bool {
+		a, _ := new(big.Int).SetString(result[i].Change, 10)
+		b, _ := new(big.Int).SetString(result[j].Change, 10)
+		if c := a.CmpAbs(b); c != 0 {
+			return c > 0
+		}
+		return result[i].Address < result[j].Address
+	})
+	if len(result) > limit {
+		result = result[:limit]
 	}
 
-	return result[start:end], total, nil
+	return result, nil
 }
 
-func (m *MockTokenRepository) Count(ctx context.Context) (int64, error) {
+func (m *MockTransferRepository) StreamHolderHistory(ctx context.Context, tokenAddress, holderAddress string, filter repositories.HolderHistoryFilter, fn func(repositories.HolderBalanceEvent) error) error {
 	m.mu.Lock()
-	m.Calls = append(m.Calls, MockCall{Method: "Count", Args: nil})
+	m.Calls = append(m.Calls, MockCall{Method: "StreamHolderHistory", Args: []interface{}{tokenAddress, holderAddress, filter}})
 	m.mu.Unlock()
 
-	if m.CountFunc != nil {
-		return m.CountFunc(ctx)
+	if m.StreamHolderHistoryFunc != nil {
+		return m.StreamHolderHistoryFunc(ctx, tokenAddress, holderAddress, filter, fn)
 	}
 
 	m.mu.RLock()
-	defer m.mu.RUnlock()
+	var transfers []entities.Transfer
+	for _, t := range m.transfers {
+		if t.TokenAddress == tokenAddress && (t.FromAddress == holderAddress || t.ToAddress == holderAddress) {
+			transfers = append(transfers, t)
+		}
+	}
+	m.mu.RUnlock()
 
-	return int64(len(m.tokens)), nil
+	sort.SliceStable(transfers, func(i, j int) bool {
+		if transfers[i].BlockNumber != transfers[j].BlockNumber {
+			return transfers[i].BlockNumber < transfers[j].BlockNumber
+		}
+		return transfers[i].LogIndex < transfers[j].LogIndex
+	})
+
+	balance := new(big.Int)
+	skipped, emitted := 0, 0
+	for _, t := range transfers {
+		change := new(big.Int)
+		counterparty := t.ToAddress
+		switch {
+		case t.FromAddress == holderAddress && t.ToAddress == holderAddress:
+		case t.ToAddress == holderAddress:
+			change.Set(transferValue(t))
+			counterparty = t.FromAddress
+		default:
+			change.Neg(transferValue(t))
+		}
+		balance.Add(balance, change)
+
+		if (filter.FromTime != nil && t.BlockTimestamp.Before(*filter.FromTime)) ||
+			(filter.ToTime != nil && !t.BlockTimestamp.Before(*filter.ToTime)) {
+			continue
+		}
+		if skipped < filter.Offset {
+			skipped++
+			continue
+		}
+		if emitted >= filter.Limit {
+			break
+		}
+		emitted++
+
+		if err := fn(repositories.HolderBalanceEvent{
+			BlockNumber:    t.BlockNumber,
+			BlockTimestamp: t.BlockTimestamp,
+			TxHash:         t.TxHash,
+			LogIndex:       t.LogIndex,
+			Counterparty:   counterparty,
+			Change:         change.String(),
+			Balance:        balance.String(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rankedHolders returns the token's holders with a positive balance, leaving out exclude, ranked by
+// balance like the repositories do (ties by address, for a stable order). Callers hold m.mu.
+func (m *MockTransferRepository) rankedHolders(tokenAddress string, exclude []string) []repositories.HolderBalance {
+	balances := make(map[string]*big.Int)
+	add := func(addr string, v *big.Int) {
+		if balances[addr] == nil {
+			balances[addr] = new(big.Int)
+		}
+		balances[addr].Add(balances[addr], v)
+	}
+	for _, t := range m.transfers {
+		if t.TokenAddress == tokenAddress {
+			v := transferValue(t)
+			add(t.ToAddress, v)
+			add(t.FromAddress, new(big.Int).Neg(v))
+		}
+	}
+
+	type holder struct {
+		addr    string
+		balance *big.Int
+	}
+	var holders []holder
+	for addr, bal := range balances {
+		if bal.Sign() > 0 && !containsAddress(exclude, addr) {
+			holders = append(holders, holder{addr, bal})
+		}
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if c := holders[i].balance.Cmp(holders[j].balance); c != 0 {
+			return c > 0
+		}
+		return holders[i].addr < holders[j].addr
+	})
+
+	result := make([]repositories.HolderBalance, len(holders))
+	for i, h := range holders {
+		result[i] = repositories.HolderBalance{Address: h.addr, Balance: h.balance.String(), Rank: i + 1}
+	}
+	return result
+}
+
+func (m *MockTransferRepository) GetNegativeBalances(ctx context.Context, tokenAddress string, limit int) (*repositories.NegativeBalances, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetNegativeBalances", Args: []interface{}{tokenAddress, limit}})
+	m.mu.Unlock()
+
+	if m.GetNegativeBalancesFunc != nil {
+		return m.GetNegativeBalancesFunc(ctx, tokenAddress, limit)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	balances := make(map[string]*big.Int)
+	add := func(addr string, v *big.Int) {
+		if balances[addr] == nil {
+			balances[addr] = new(big.Int)
+		}
+		balances[addr].Add(balances[addr], v)
+	}
+	for _, t := range m.transfers {
+		if t.TokenAddress == tokenAddress {
+			v := transferValue(t)
+			add(t.ToAddress, v)
+			add(t.FromAddress, new(big.Int).Neg(v))
+		}
+	}
+
+	var negative []repositories.HolderBalance
+	for addr, bal := range balances {
+		if bal.Sign() < 0 && addr != entities.ZeroAddress {
+			negative = append(negative, repositories.HolderBalance{Address: addr, Balance: bal.String()})
+		}
+	}
+	sort.Slice(negative, func(i, j int) bool {
+		bi, _ := new(big.Int).SetString(negative[i].Balance, 10)
+		bj, _ := new(big.Int).SetString(negative[j].Balance, 10)
+		if c := bi.Cmp(bj); c != 0 {
+			return c < 0
+		}
+		return negative[i].Address < negative[j].Address
+	})
+
+	result := &repositories.NegativeBalances{Count: int64(len(negative)), Worst: pageHolders(negative, limit, 0)}
+	for i := range result.Worst {
+		result.Worst[i].Rank = i + 1
+	}
+	return result, nil
+}
+
+// pageHolders returns holders[offset:offset+limit], bounded by the slice
+func pageHolders(holders []repositories.HolderBalance, limit, offset int) []repositories.HolderBalance {
+	if offset >= len(holders) {
+		return nil
+	}
+	holders = holders[offset:]
+	if limit < len(holders) {
+		holders = holders[:limit]
+	}
+	return holders
+}
+
+// transferValue returns the transfer value, falling back to ValueString
+func transferValue(t entities.Transfer) *big.Int {
+	if t.Value != nil {
+		return t.Value
+	}
+	v, ok := new(big.Int).SetString(t.ValueString, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return v
+}
+
+// AddTransfers adds transfers to the mock store
+func (m *MockTransferRepository) AddTransfers(transfers ...entities.Transfer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transfers = append(m.transfers, transfers...)
+}
+
+// Reset clears all stored data and calls
+func (m *MockTransferRepository) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transfers = make([]entities.Transfer, 0)
+	m.Calls = make([]MockCall, 0)
+}
+
+// MockTokenRepository is a mock implementation of TokenRepository
+type MockTokenRepository struct {
+	mu      sync.RWMutex
+	tokens  map[string]*entities.Token
+	history []entities.TokenMetadataChange
+
+	// Function hooks
+	GetByAddressFunc         func(ctx context.Context, address string) (*entities.Token, error)
+	GetByAddressesFunc       func(ctx context.Context, addresses []string) ([]*entities.Token, error)
+	GetAllFunc               func(ctx context.Context) ([]entities.Token, error)
+	GetAllPaginatedFunc      func(ctx context.Context, limit, offset int, sortBy, sortOrder string, includeInactive bool, scope repositories.TokenScope) ([]*entities.Token, int64, error)
+	CountFunc                func(ctx context.Context) (int64, error)
+	UpsertFunc               func(ctx context.Context, token *entities.Token) error
+	SetActiveFunc            func(ctx context.Context, address string, active bool) (bool, error)
+	OverrideMetadataFunc     func(ctx context.Context, address string, override entities.TokenMetadataOverride) (bool, error)
+	RecordMetadataChangeFunc func(ctx context.Context, change *entities.TokenMetadataChange) (bool, error)
+	UpdateStatsFunc          func(ctx context.Context, address string, transferCount int64, lastBlock int64) error
+	RecountTransfersFunc     func(ctx context.Context, address string) (int64, error)
+
+	Calls []MockCall
+}
+
+func NewMockTokenRepository() *MockTokenRepository {
+	return &MockTokenRepository{
+		tokens: make(map[string]*entities.Token),
+		Calls:  make([]MockCall, 0),
+	}
+}
+
+func (m *MockTokenRepository) GetByAddress(ctx context.Context, address string) (*entities.Token, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetByAddress", Args: []interface{}{address}})
+	m.mu.Unlock()
+
+	if m.GetByAddressFunc != nil {
+		return m.GetByAddressFunc(ctx, address)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if token, ok := m.tokens[address]; ok {
+		return token, nil
+	}
+	return nil, nil
+}
+
+func (m *MockTokenRepository) GetByAddresses(ctx context.Context, addresses []string) ([]*entities.Token, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetByAddresses", Args: []interface{}{addresses}})
+	m.mu.Unlock()
+
+	if m.GetByAddressesFunc != nil {
+		return m.GetByAddressesFunc(ctx, addresses)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]*entities.Token, 0, len(addresses))
+	for _, address := range addresses {
+		if token, ok := m.tokens[address]; ok {
+			result = append(result, token)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockTokenRepository) GetAll(ctx context.Context) ([]entities.Token, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetAll", Args: nil})
+	m.mu.Unlock()
+
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc(ctx)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]entities.Token, 0, len(m.tokens))
+	for _, token := range m.tokens {
+		result = append(result, *token)
+	}
+	return result, nil
+}
+
+func (m *MockTokenRepository) GetAllPaginated(ctx context.Context, limit, offset int, sortBy, sortOrder string, includeInactive bool, scope repositories.TokenScope) ([]*entities.Token, int64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetAllPaginated", Args: []interface{}{limit, offset, sortBy, sortOrder, includeInactive, scope}})
+	m.mu.Unlock()
+
+	if m.GetAllPaginatedFunc != nil {
+		return m.GetAllPaginatedFunc(ctx, limit, offset, sortBy, sortOrder, includeInactive, scope)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]*entities.Token, 0, len(m.tokens))
+	for _, token := range m.tokens {
+		if (token.Active || includeInactive) && inTokenScope(token.Address, scope) {
+			result = append(result, token)
+		}
+	}
+
+	total := int64(len(result))
+
+	// Apply pagination
+	start := offset
+	if start > len(result) {
+		return []*entities.Token{}, total, nil
+	}
+	end := start + limit
+	if end > len(result) {
+		end = len(result)
+	}
+
+	return result[start:end], total, nil
+}
+
+func (m *MockTokenRepository) Count(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "Count", Args: nil})
+	m.mu.Unlock()
+
+	if m.CountFunc != nil {
+		return m.CountFunc(ctx)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return int64(len(m.tokens)), nil
 }
 
 func (m *MockTokenRepository) Upsert(ctx context.Context, token *entities.Token) error {
@@ -453,10 +1021,65 @@ func (m *MockTokenRepository) Upsert(ctx context.Context, token *entities.Token)
 		return m.UpsertFunc(ctx, token)
 	}
 
+	if existing, ok := m.tokens[token.Address]; ok && existing.MetadataSource == entities.MetadataOverride {
+		return nil
+	}
+	if token.MetadataSource == "" {
+		token.MetadataSource = entities.MetadataOnchain
+	}
 	m.tokens[token.Address] = token
 	return nil
 }
 
+func (m *MockTokenRepository) OverrideMetadata(ctx context.Context, address string, override entities.TokenMetadataOverride) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "OverrideMetadata", Args: []interface{}{address, override}})
+
+	if m.OverrideMetadataFunc != nil {
+		return m.OverrideMetadataFunc(ctx, address, override)
+	}
+
+	token, ok := m.tokens[address]
+	if !ok {
+		return false, nil
+	}
+	if override.Name != nil {
+		token.Name = *override.Name
+	}
+	if override.Symbol != nil {
+		token.Symbol = *override.Symbol
+	}
+	if override.Decimals != nil {
+		token.Decimals = *override.Decimals
+	}
+	token.MetadataSource = entities.MetadataOverride
+	return true, nil
+}
+
+func (m *MockTokenRepository) SetActive(ctx context.Context, address string, active bool) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "SetActive", Args: []interface{}{address, active}})
+
+	if m.SetActiveFunc != nil {
+		return m.SetActiveFunc(ctx, address, active)
+	}
+
+	token, ok := m.tokens[address]
+	if !ok {
+		return false, nil
+	}
+	token.Active = active
+	if !active {
+		now := time.Now()
+		token.DeactivatedAt = &now
+	}
+	return true, nil
+}
+
 func (m *MockTokenRepository) UpdateStats(ctx context.Context, address string, transferCount int64, lastBlock int64) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -476,6 +1099,61 @@ func (m *MockTokenRepository) UpdateStats(ctx context.Context, address string, t
 	return nil
 }
 
+// RecountTransfers has no transfers to count by default, so it reports the current count
+func (m *MockTokenRepository) RecountTransfers(ctx context.Context, address string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "RecountTransfers", Args: []interface{}{address}})
+
+	if m.RecountTransfersFunc != nil {
+		return m.RecountTransfersFunc(ctx, address)
+	}
+
+	if token, ok := m.tokens[address]; ok {
+		return token.TotalIndexedTransfers, nil
+	}
+	return 0, nil
+}
+
+func (m *MockTokenRepository) RecordMetadataChange(ctx context.Context, change *entities.TokenMetadataChange) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "RecordMetadataChange", Args: []interface{}{*change}})
+
+	if m.RecordMetadataChangeFunc != nil {
+		return m.RecordMetadataChangeFunc(ctx, change)
+	}
+
+	token, ok := m.tokens[change.TokenAddress]
+	if !ok || token.MetadataSource == entities.MetadataOverride {
+		return false, nil
+	}
+	token.Name = change.Name
+	token.Symbol = change.Symbol
+	token.Decimals = change.Decimals
+	change.ID = int64(len(m.history) + 1)
+	change.DetectedAt = time.Now()
+	m.history = append(m.history, *change)
+	return true, nil
+}
+
+func (m *MockTokenRepository) GetMetadataHistory(ctx context.Context, address string) ([]entities.TokenMetadataChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "GetMetadataHistory", Args: []interface{}{address}})
+
+	var changes []entities.TokenMetadataChange
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if m.history[i].TokenAddress == address {
+			changes = append(changes, m.history[i])
+		}
+	}
+	return changes, nil
+}
+
 // AddToken adds a token to the mock store
 func (m *MockTokenRepository) AddToken(token *entities.Token) {
 	m.mu.Lock()
@@ -488,6 +1166,7 @@ func (m *MockTokenRepository) Reset() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.tokens = make(map[string]*entities.Token)
+	m.history = nil
 	m.Calls = make([]MockCall, 0)
 }
 
@@ -502,6 +1181,10 @@ type MockIndexerStateRepository struct {
 	UpdateLastBlockFunc func(ctx context.Context, tokenAddress string, blockNumber int64) error
 	SetBackfillingFunc  func(ctx context.Context, tokenAddress string, isBackfilling bool, fromBlock, toBlock *int64) error
 
+	UpdateBackfillCheckpointFunc func(ctx context.Context, tokenAddress string, blockNumber int64) error
+
+	GetOldestCheckpointTimeFunc func(ctx context.Context) (*time.Time, error)
+
 	Calls []MockCall
 }
 
@@ -524,8 +1207,10 @@ func (m *MockIndexerStateRepository) Get(ctx context.Context, tokenAddress strin
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	// A copy, so callers never share the state the other methods update
 	if state, ok := m.states[tokenAddress]; ok {
-		return state, nil
+		copied := *state
+		return &copied, nil
 	}
 	return nil, nil
 }
@@ -560,6 +1245,28 @@ func (m *MockIndexerStateRepository) UpdateLastBlock(ctx context.Context, tokenA
 	return nil
 }
 
+func (m *MockIndexerStateRepository) GetOldestCheckpointTime(ctx context.Context) (*time.Time, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetOldestCheckpointTime", Args: nil})
+	m.mu.Unlock()
+
+	if m.GetOldestCheckpointTimeFunc != nil {
+		return m.GetOldestCheckpointTimeFunc(ctx)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var oldest *time.Time
+	for _, state := range m.states {
+		if oldest == nil || state.UpdatedAt.Before(*oldest) {
+			updatedAt := state.UpdatedAt
+			oldest = &updatedAt
+		}
+	}
+	return oldest, nil
+}
+
 func (m *MockIndexerStateRepository) SetBackfilling(ctx context.Context, tokenAddress string, isBackfilling bool, fromBlock, toBlock *int64) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -571,6 +1278,9 @@ func (m *MockIndexerStateRepository) SetBackfilling(ctx context.Context, tokenAd
 	}
 
 	if state, ok := m.states[tokenAddress]; ok {
+		if !sameBlock(state.BackfillFromBlock, fromBlock) || !sameBlock(state.BackfillToBlock, toBlock) {
+			state.BackfillCheckpoint = nil
+		}
 		state.IsBackfilling = isBackfilling
 		state.BackfillFromBlock = fromBlock
 		state.BackfillToBlock = toBlock
@@ -578,8 +1288,32 @@ func (m *MockIndexerStateRepository) SetBackfilling(ctx context.Context, tokenAd
 	return nil
 }
 
-// AddState adds a state to the mock store
-func (m *MockIndexerStateRepository) AddState(state *entities.IndexerState) {
+func (m *MockIndexerStateRepository) UpdateBackfillCheckpoint(ctx context.Context, tokenAddress string, blockNumber int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "UpdateBackfillCheckpoint", Args: []interface{}{tokenAddress, blockNumber}})
+
+	if m.UpdateBackfillCheckpointFunc != nil {
+		return m.UpdateBackfillCheckpointFunc(ctx, tokenAddress, blockNumber)
+	}
+
+	if state, ok := m.states[tokenAddress]; ok {
+		state.BackfillCheckpoint = &blockNumber
+	}
+	return nil
+}
+
+// sameBlock compares optional block numbers, with nil equal only to nil
+func sameBlock(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// AddState adds a state to the mock store
+func (m *MockIndexerStateRepository) AddState(state *entities.IndexerState) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.states[state.TokenAddress] = state
@@ -593,6 +1327,33 @@ func (m *MockIndexerStateRepository) Reset() {
 	m.Calls = make([]MockCall, 0)
 }
 
+// MockScopedEventStateRepository is a mock implementation of ScopedEventStateRepository
+type MockScopedEventStateRepository struct {
+	mu        sync.RWMutex
+	lastBlock int64
+
+	Calls []MockCall
+}
+
+func NewMockScopedEventStateRepository() *MockScopedEventStateRepository {
+	return &MockScopedEventStateRepository{Calls: make([]MockCall, 0)}
+}
+
+func (m *MockScopedEventStateRepository) GetLastBlock(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetLastBlock", Args: nil})
+	return m.lastBlock, nil
+}
+
+func (m *MockScopedEventStateRepository) UpdateLastBlock(ctx context.Context, blockNumber int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "UpdateLastBlock", Args: []interface{}{blockNumber}})
+	m.lastBlock = blockNumber
+	return nil
+}
+
 // MockHealthChecker is a mock implementation of HealthChecker
 type MockHealthChecker struct {
 	mu sync.RWMutex
@@ -638,10 +1399,16 @@ type MockPortfolioRepository struct {
 	mu sync.RWMutex
 
 	// Function hooks for custom behavior
-	GetWalletHoldingsFunc        func(ctx context.Context, walletAddress string) ([]entities.TokenHolding, error)
-	GetWalletHoldingByTokenFunc  func(ctx context.Context, walletAddress, tokenAddress string) (*entities.TokenHolding, error)
-	GetWalletTokenCountFunc      func(ctx context.Context, walletAddress string) (int64, error)
-	GetWalletTransferSummaryFunc func(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, error)
+	GetWalletHoldingsFunc            func(ctx context.Context, walletAddress string) ([]entities.TokenHolding, error)
+	GetWalletHoldingByTokenFunc      func(ctx context.Context, walletAddress, tokenAddress string) (*entities.TokenHolding, error)
+	GetWalletTokenCountFunc          func(ctx context.Context, walletAddress string) (int64, error)
+	GetWalletTransferSummaryFunc     func(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, error)
+	GetWalletBalancesAtFunc          func(ctx context.Context, walletAddress string, at time.Time) ([]entities.TokenHolding, error)
+	GetWalletDailyBalanceChangesFunc func(ctx context.Context, walletAddress string, from time.Time) ([]repositories.DailyBalanceChange, error)
+	GetWalletTokenActivityFunc       func(ctx context.Context, walletAddress string) ([]repositories.WalletTokenActivity, error)
+	GetEntityHoldingsFunc            func(ctx context.Context, addresses []string) ([]entities.TokenHolding, error)
+	GetEntityTokenVolumesFunc        func(ctx context.Context, addresses []string) ([]repositories.EntityTokenVolume, error)
+	GetCounterpartyFlowsFunc         func(ctx context.Context, addresses []string, scanLimit, limit int) ([]repositories.CounterpartyFlow, error)
 
 	// Call tracking
 	Calls []MockCall
@@ -726,9 +1493,2097 @@ func (m *MockPortfolioRepository) GetWalletTransferSummary(ctx context.Context,
 	}, nil
 }
 
+func (m *MockPortfolioRepository) GetWalletBalancesAt(ctx context.Context, walletAddress string, at time.Time) ([]entities.TokenHolding, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetWalletBalancesAt", Args: []interface{}{walletAddress, at}})
+	m.mu.Unlock()
+
+	if m.GetWalletBalancesAtFunc != nil {
+		return m.GetWalletBalancesAtFunc(ctx, walletAddress, at)
+	}
+
+	return []entities.TokenHolding{}, nil
+}
+
+func (m *MockPortfolioRepository) GetWalletDailyBalanceChanges(ctx context.Context, walletAddress string, from time.Time) ([]repositories.DailyBalanceChange, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetWalletDailyBalanceChanges", Args: []interface{}{walletAddress, from}})
+	m.mu.Unlock()
+
+	if m.GetWalletDailyBalanceChangesFunc != nil {
+		return m.GetWalletDailyBalanceChangesFunc(ctx, walletAddress, from)
+	}
+
+	return []repositories.DailyBalanceChange{}, nil
+}
+
+func (m *MockPortfolioRepository) GetWalletTokenActivity(ctx context.Context, walletAddress string) ([]repositories.WalletTokenActivity, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetWalletTokenActivity", Args: []interface{}{walletAddress}})
+	m.mu.Unlock()
+
+	if m.GetWalletTokenActivityFunc != nil {
+		return m.GetWalletTokenActivityFunc(ctx, walletAddress)
+	}
+
+	return []repositories.WalletTokenActivity{}, nil
+}
+
+func (m *MockPortfolioRepository) GetEntityHoldings(ctx context.Context, addresses []string) ([]entities.TokenHolding, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetEntityHoldings", Args: []interface{}{addresses}})
+	m.mu.Unlock()
+
+	if m.GetEntityHoldingsFunc != nil {
+		return m.GetEntityHoldingsFunc(ctx, addresses)
+	}
+
+	return []entities.TokenHolding{}, nil
+}
+
+func (m *MockPortfolioRepository) GetEntityTokenVolumes(ctx context.Context, addresses []string) ([]repositories.EntityTokenVolume, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetEntityTokenVolumes", Args: []interface{}{addresses}})
+	m.mu.Unlock()
+
+	if m.GetEntityTokenVolumesFunc != nil {
+		return m.GetEntityTokenVolumesFunc(ctx, addresses)
+	}
+
+	return []repositories.EntityTokenVolume{}, nil
+}
+
+func (m *MockPortfolioRepository) GetCounterpartyFlows(ctx context.Context, addresses []string, scanLimit, limit int) ([]repositories.CounterpartyFlow, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetCounterpartyFlows", Args: []interface{}{addresses, scanLimit, limit}})
+	m.mu.Unlock()
+
+	if m.GetCounterpartyFlowsFunc != nil {
+		return m.GetCounterpartyFlowsFunc(ctx, addresses, scanLimit, limit)
+	}
+
+	return []repositories.CounterpartyFlow{}, nil
+}
+
 // Reset clears all calls
 func (m *MockPortfolioRepository) Reset() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.Calls = make([]MockCall, 0)
 }
+
+// MockDailyStatsRepository is a mock implementation of DailyStatsRepository
+type MockDailyStatsRepository struct {
+	mu        sync.RWMutex
+	rows      []entities.TokenDailyStats
+	firstSeen []entities.HolderFirstSeen
+
+	// Function hooks for custom behavior
+	RefreshDaysFunc   func(ctx context.Context, tokenAddress string, from, to time.Time) error
+	GetRangeFunc      func(ctx context.Context, tokenAddress string, from, to time.Time) ([]entities.TokenDailyStats, error)
+	GetNewHoldersFunc func(ctx context.Context, tokenAddress string, since time.Time, limit int) ([]entities.HolderFirstSeen, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockDailyStatsRepository() *MockDailyStatsRepository {
+	return &MockDailyStatsRepository{
+		rows:  make([]entities.TokenDailyStats, 0),
+		Calls: make([]MockCall, 0),
+	}
+}
+
+func (m *MockDailyStatsRepository) RefreshDays(ctx context.Context, tokenAddress string, from, to time.Time) error {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "RefreshDays", Args: []interface{}{tokenAddress, from, to}})
+	m.mu.Unlock()
+
+	if m.RefreshDaysFunc != nil {
+		return m.RefreshDaysFunc(ctx, tokenAddress, from, to)
+	}
+
+	return nil
+}
+
+func (m *MockDailyStatsRepository) GetRange(ctx context.Context, tokenAddress string, from, to time.Time) ([]entities.TokenDailyStats, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetRange", Args: []interface{}{tokenAddress, from, to}})
+	m.mu.Unlock()
+
+	if m.GetRangeFunc != nil {
+		return m.GetRangeFunc(ctx, tokenAddress, from, to)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	fromDay := from.UTC().Truncate(24 * time.Hour)
+	toDay := to.UTC().Truncate(24 * time.Hour)
+
+	var result []entities.TokenDailyStats
+	for _, row := range m.rows {
+		if row.TokenAddress == tokenAddress && !row.Day.Before(fromDay) && !row.Day.After(toDay) {
+			result = append(result, row)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *MockDailyStatsRepository) GetNewHolders(ctx context.Context, tokenAddress string, since time.Time, limit int) ([]entities.HolderFirstSeen, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetNewHolders", Args: []interface{}{tokenAddress, since, limit}})
+	m.mu.Unlock()
+
+	if m.GetNewHoldersFunc != nil {
+		return m.GetNewHoldersFunc(ctx, tokenAddress, since, limit)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]entities.HolderFirstSeen, 0)
+	for i := len(m.firstSeen) - 1; i >= 0 && len(result) < limit; i-- {
+		h := m.firstSeen[i]
+		if h.TokenAddress == tokenAddress && !h.FirstSeenAt.Before(since) {
+			result = append(result, h)
+		}
+	}
+
+	return result, nil
+}
+
+// AddFirstSeen adds first receipts to the mock repository; callers add them oldest first
+func (m *MockDailyStatsRepository) AddFirstSeen(holders ...entities.HolderFirstSeen) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.firstSeen = append(m.firstSeen, holders...)
+}
+
+// AddDailyStats adds rollup rows to the mock repository; callers add them oldest first
+func (m *MockDailyStatsRepository) AddDailyStats(rows ...entities.TokenDailyStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rows = append(m.rows, rows...)
+}
+
+// MockActiveAddressRepository is an exact in-memory implementation of ActiveAddressRepository
+type MockActiveAddressRepository struct {
+	mu   sync.RWMutex
+	days map[string]map[string]map[string]struct{} // token -> day -> address set
+
+	// Function hooks for custom behavior
+	CountActiveAddressesFunc func(ctx context.Context, tokenAddress string, from, to time.Time) (int64, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockActiveAddressRepository() *MockActiveAddressRepository {
+	return &MockActiveAddressRepository{
+		days:  make(map[string]map[string]map[string]struct{}),
+		Calls: make([]MockCall, 0),
+	}
+}
+
+func (m *MockActiveAddressRepository) AddActiveAddresses(ctx context.Context, tokenAddress string, day time.Time, addresses []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "AddActiveAddresses", Args: []interface{}{tokenAddress, day, addresses}})
+
+	key := day.UTC().Format("2006-01-02")
+	if m.days[tokenAddress] == nil {
+		m.days[tokenAddress] = make(map[string]map[string]struct{})
+	}
+	if m.days[tokenAddress][key] == nil {
+		m.days[tokenAddress][key] = make(map[string]struct{})
+	}
+	for _, addr := range addresses {
+		m.days[tokenAddress][key][addr] = struct{}{}
+	}
+
+	return nil
+}
+
+func (m *MockActiveAddressRepository) ReplaceActiveAddresses(ctx context.Context, tokenAddress string, day time.Time, addresses []string) error {
+	m.mu.Lock()
+	if m.days[tokenAddress] != nil {
+		delete(m.days[tokenAddress], day.UTC().Format("2006-01-02"))
+	}
+	m.mu.Unlock()
+
+	return m.AddActiveAddresses(ctx, tokenAddress, day, addresses)
+}
+
+func (m *MockActiveAddressRepository) CountActiveAddresses(ctx context.Context, tokenAddress string, from, to time.Time) (int64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "CountActiveAddresses", Args: []interface{}{tokenAddress, from, to}})
+	m.mu.Unlock()
+
+	if m.CountActiveAddressesFunc != nil {
+		return m.CountActiveAddressesFunc(ctx, tokenAddress, from, to)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	union := make(map[string]struct{})
+	for day := from.UTC().Truncate(24 * time.Hour); !day.After(to); day = day.AddDate(0, 0, 1) {
+		for addr := range m.days[tokenAddress][day.Format("2006-01-02")] {
+			union[addr] = struct{}{}
+		}
+	}
+
+	return int64(len(union)), nil
+}
+
+// MockWatchlistRepository is an in-memory implementation of WatchlistRepository
+type MockWatchlistRepository struct {
+	mu         sync.RWMutex
+	watchlists map[int64]*entities.Watchlist
+	nextID     int64
+
+	// Function hooks for custom behavior
+	CreateFunc  func(ctx context.Context, watchlist *entities.Watchlist) error
+	GetByIDFunc func(ctx context.Context, id int64) (*entities.Watchlist, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockWatchlistRepository() *MockWatchlistRepository {
+	return &MockWatchlistRepository{
+		watchlists: make(map[int64]*entities.Watchlist),
+		nextID:     1,
+		Calls:      make([]MockCall, 0),
+	}
+}
+
+func (m *MockWatchlistRepository) Create(ctx context.Context, watchlist *entities.Watchlist) error {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "Create", Args: []interface{}{watchlist}})
+	m.mu.Unlock()
+
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, watchlist)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	watchlist.ID = m.nextID
+	watchlist.CreatedAt = now
+	watchlist.UpdatedAt = now
+	m.nextID++
+
+	stored := *watchlist
+	stored.Addresses = appendMissing(nil, watchlist.Addresses)
+	m.watchlists[stored.ID] = &stored
+
+	return nil
+}
+
+func (m *MockWatchlistRepository) GetByID(ctx context.Context, id int64) (*entities.Watchlist, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetByID", Args: []interface{}{id}})
+	m.mu.Unlock()
+
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	w, ok := m.watchlists[id]
+	if !ok {
+		return nil, nil
+	}
+
+	result := *w
+	result.Addresses = append([]string(nil), w.Addresses...)
+	return &result, nil
+}
+
+func (m *MockWatchlistRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Delete", Args: []interface{}{id}})
+
+	if _, ok := m.watchlists[id]; !ok {
+		return false, nil
+	}
+	delete(m.watchlists, id)
+	return true, nil
+}
+
+func (m *MockWatchlistRepository) AddAddresses(ctx context.Context, id int64, addresses []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "AddAddresses", Args: []interface{}{id, addresses}})
+
+	if w, ok := m.watchlists[id]; ok {
+		w.Addresses = appendMissing(w.Addresses, addresses)
+		w.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (m *MockWatchlistRepository) RemoveAddress(ctx context.Context, id int64, address string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "RemoveAddress", Args: []interface{}{id, address}})
+
+	w, ok := m.watchlists[id]
+	if !ok {
+		return false, nil
+	}
+	for i, a := range w.Addresses {
+		if a == address {
+			w.Addresses = append(w.Addresses[:i], w.Addresses[i+1:]...)
+			w.UpdatedAt = time.Now()
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func appendMissing(dst, addresses []string) []string {
+	for _, addr := range addresses {
+		if !containsAddress(dst, addr) {
+			dst = append(dst, addr)
+		}
+	}
+	return dst
+}
+
+// MockEntityRepository is an in-memory implementation of EntityRepository
+type MockEntityRepository struct {
+	mu           sync.RWMutex
+	entitiesByID map[int64]*entities.Entity
+	nextID       int64
+
+	// Function hooks for custom behavior
+	CreateFunc           func(ctx context.Context, entity *entities.Entity) error
+	GetByIDFunc          func(ctx context.Context, id int64) (*entities.Entity, error)
+	GetCategoryFlowsFunc func(ctx context.Context, tokenAddress, category string, from, to time.Time) ([]repositories.EntityFlow, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockEntityRepository() *MockEntityRepository {
+	return &MockEntityRepository{
+		entitiesByID: make(map[int64]*entities.Entity),
+		nextID:       1,
+		Calls:        make([]MockCall, 0),
+	}
+}
+
+func (m *MockEntityRepository) Create(ctx context.Context, entity *entities.Entity) error {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "Create", Args: []interface{}{entity}})
+	m.mu.Unlock()
+
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, entity)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	entity.ID = m.nextID
+	entity.CreatedAt = now
+	entity.UpdatedAt = now
+	m.nextID++
+
+	stored := *entity
+	stored.Addresses = appendMissing(nil, entity.Addresses)
+	m.entitiesByID[stored.ID] = &stored
+
+	return nil
+}
+
+func (m *MockEntityRepository) GetCategoryFlows(ctx context.Context, tokenAddress, category string, from, to time.Time) ([]repositories.EntityFlow, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetCategoryFlows", Args: []interface{}{tokenAddress, category, from, to}})
+	m.mu.Unlock()
+
+	if m.GetCategoryFlowsFunc != nil {
+		return m.GetCategoryFlowsFunc(ctx, tokenAddress, category, from, to)
+	}
+
+	return []repositories.EntityFlow{}, nil
+}
+
+func (m *MockEntityRepository) GetByID(ctx context.Context, id int64) (*entities.Entity, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetByID", Args: []interface{}{id}})
+	m.mu.Unlock()
+
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	w, ok := m.entitiesByID[id]
+	if !ok {
+		return nil, nil
+	}
+
+	result := *w
+	result.Addresses = append([]string(nil), w.Addresses...)
+	return &result, nil
+}
+
+func (m *MockEntityRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Delete", Args: []interface{}{id}})
+
+	if _, ok := m.entitiesByID[id]; !ok {
+		return false, nil
+	}
+	delete(m.entitiesByID, id)
+	return true, nil
+}
+
+func (m *MockEntityRepository) AddAddresses(ctx context.Context, id int64, addresses []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "AddAddresses", Args: []interface{}{id, addresses}})
+
+	if w, ok := m.entitiesByID[id]; ok {
+		w.Addresses = appendMissing(w.Addresses, addresses)
+		w.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (m *MockEntityRepository) RemoveAddress(ctx context.Context, id int64, address string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "RemoveAddress", Args: []interface{}{id, address}})
+
+	w, ok := m.entitiesByID[id]
+	if !ok {
+		return false, nil
+	}
+	for i, a := range w.Addresses {
+		if a == address {
+			w.Addresses = append(w.Addresses[:i], w.Addresses[i+1:]...)
+			w.UpdatedAt = time.Now()
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MockAlertRuleRepository is an in-memory implementation of AlertRuleRepository
+type MockAlertRuleRepository struct {
+	mu     sync.RWMutex
+	rules  []entities.AlertRule
+	nextID int64
+
+	// Function hooks for custom behavior
+	ListFunc func(ctx context.Context) ([]entities.AlertRule, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockAlertRuleRepository() *MockAlertRuleRepository {
+	return &MockAlertRuleRepository{
+		nextID: 1,
+		Calls:  make([]MockCall, 0),
+	}
+}
+
+func (m *MockAlertRuleRepository) Create(ctx context.Context, rule *entities.AlertRule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Create", Args: []interface{}{rule}})
+
+	rule.ID = m.nextID
+	rule.CreatedAt = time.Now()
+	m.nextID++
+	m.rules = append(m.rules, *rule)
+
+	return nil
+}
+
+func (m *MockAlertRuleRepository) GetByID(ctx context.Context, id int64) (*entities.AlertRule, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetByID", Args: []interface{}{id}})
+	m.mu.Unlock()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, r := range m.rules {
+		if r.ID == id {
+			rule := r
+			return &rule, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockAlertRuleRepository) List(ctx context.Context) ([]entities.AlertRule, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "List", Args: nil})
+	m.mu.Unlock()
+
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return append([]entities.AlertRule(nil), m.rules...), nil
+}
+
+func (m *MockAlertRuleRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Delete", Args: []interface{}{id}})
+
+	for i, r := range m.rules {
+		if r.ID == id {
+			m.rules = append(m.rules[:i], m.rules[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MockNotifyDriver is a notification driver that records sent alerts
+type MockNotifyDriver struct {
+	mu      sync.Mutex
+	name    string
+	Sent    map[string][][]notify.Alert // target -> batches
+	SendErr error
+}
+
+func NewMockNotifyDriver(name string) *MockNotifyDriver {
+	return &MockNotifyDriver{
+		name: name,
+		Sent: make(map[string][][]notify.Alert),
+	}
+}
+
+func (d *MockNotifyDriver) Name() string {
+	return d.name
+}
+
+func (d *MockNotifyDriver) Validate(target string) error {
+	if target == "" {
+		return errors.New("target is required")
+	}
+	return nil
+}
+
+func (d *MockNotifyDriver) Send(ctx context.Context, target string, alerts []notify.Alert) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.SendErr != nil {
+		return d.SendErr
+	}
+	d.Sent[target] = append(d.Sent[target], alerts)
+	return nil
+}
+
+// MockDenyListRepository is an in-memory implementation of DenyListRepository
+type MockDenyListRepository struct {
+	mu      sync.RWMutex
+	entries map[string]map[string]entities.DenyListEntry // list -> address -> entry
+
+	// Function hooks for custom behavior
+	FindByAddressesFunc func(ctx context.Context, addresses []string) ([]entities.DenyListEntry, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockDenyListRepository() *MockDenyListRepository {
+	return &MockDenyListRepository{
+		entries: make(map[string]map[string]entities.DenyListEntry),
+		Calls:   make([]MockCall, 0),
+	}
+}
+
+func (m *MockDenyListRepository) ReplaceList(ctx context.Context, listName string, entries []entities.DenyListEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "ReplaceList", Args: []interface{}{listName, entries}})
+
+	previous := m.entries[listName]
+	list := make(map[string]entities.DenyListEntry, len(entries))
+	for _, e := range entries {
+		if old, ok := previous[e.Address]; ok {
+			e.AddedAt = old.AddedAt
+		} else {
+			e.AddedAt = time.Now()
+		}
+		e.ListName = listName
+		list[e.Address] = e
+	}
+	m.entries[listName] = list
+
+	return nil
+}
+
+func (m *MockDenyListRepository) FindByAddresses(ctx context.Context, addresses []string) ([]entities.DenyListEntry, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "FindByAddresses", Args: []interface{}{addresses}})
+	m.mu.Unlock()
+
+	if m.FindByAddressesFunc != nil {
+		return m.FindByAddressesFunc(ctx, addresses)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []entities.DenyListEntry
+	for _, list := range m.entries {
+		for _, addr := range addresses {
+			if e, ok := list[addr]; ok {
+				result = append(result, e)
+			}
+		}
+	}
+	return result, nil
+}
+
+// AddDenyListEntries adds addresses to a list without replacing it
+func (m *MockDenyListRepository) AddDenyListEntries(listName string, addresses ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.entries[listName] == nil {
+		m.entries[listName] = make(map[string]entities.DenyListEntry)
+	}
+	for _, addr := range addresses {
+		m.entries[listName][addr] = entities.DenyListEntry{ListName: listName, Address: addr, AddedAt: time.Now()}
+	}
+}
+
+// MockHolderSnapshotRepository is an in-memory implementation of HolderSnapshotRepository
+type MockHolderSnapshotRepository struct {
+	mu        sync.RWMutex
+	snapshots []repositories.HolderSnapshot // in save order
+
+	// Function hooks for custom behavior
+	GetAtFunc func(ctx context.Context, tokenAddress string, at time.Time) (*repositories.HolderSnapshot, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockHolderSnapshotRepository() *MockHolderSnapshotRepository {
+	return &MockHolderSnapshotRepository{
+		Calls: make([]MockCall, 0),
+	}
+}
+
+func (m *MockHolderSnapshotRepository) Save(ctx context.Context, tokenAddress string, takenAt time.Time, holders []repositories.HolderBalance) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Save", Args: []interface{}{tokenAddress, takenAt, holders}})
+	m.snapshots = append(m.snapshots, repositories.HolderSnapshot{TokenAddress: tokenAddress, TakenAt: takenAt, Holders: holders})
+	return nil
+}
+
+func (m *MockHolderSnapshotRepository) GetAt(ctx context.Context, tokenAddress string, at time.Time) (*repositories.HolderSnapshot, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetAt", Args: []interface{}{tokenAddress, at}})
+	m.mu.Unlock()
+
+	if m.GetAtFunc != nil {
+		return m.GetAtFunc(ctx, tokenAddress, at)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var before, after *repositories.HolderSnapshot
+	for i := range m.snapshots {
+		s := &m.snapshots[i]
+		if s.TokenAddress != tokenAddress {
+			continue
+		}
+		if !s.TakenAt.After(at) {
+			if before == nil || s.TakenAt.After(before.TakenAt) {
+				before = s
+			}
+		} else if after == nil || s.TakenAt.Before(after.TakenAt) {
+			after = s
+		}
+	}
+	if before != nil {
+		return before, nil
+	}
+	return after, nil
+}
+
+func (m *MockHolderSnapshotRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "DeleteBefore", Args: []interface{}{before}})
+
+	var deleted int64
+	kept := m.snapshots[:0]
+	for _, s := range m.snapshots {
+		if s.TakenAt.Before(before) {
+			deleted += int64(len(s.Holders))
+			continue
+		}
+		kept = append(kept, s)
+	}
+	m.snapshots = kept
+	return deleted, nil
+}
+
+// MockHolderCountRepository is an in-memory implementation of HolderCountRepository
+type MockHolderCountRepository struct {
+	mu     sync.RWMutex
+	counts map[string]repositories.HolderCount
+
+	// Function hooks for custom behavior
+	GetFunc func(ctx context.Context, tokenAddress string) (*repositories.HolderCount, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockHolderCountRepository() *MockHolderCountRepository {
+	return &MockHolderCountRepository{
+		counts: make(map[string]repositories.HolderCount),
+		Calls:  make([]MockCall, 0),
+	}
+}
+
+func (m *MockHolderCountRepository) Save(ctx context.Context, tokenAddress string, count int64, countedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Save", Args: []interface{}{tokenAddress, count, countedAt}})
+	m.counts[tokenAddress] = repositories.HolderCount{TokenAddress: tokenAddress, Count: count, CountedAt: countedAt}
+	return nil
+}
+
+func (m *MockHolderCountRepository) Get(ctx context.Context, tokenAddress string) (*repositories.HolderCount, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "Get", Args: []interface{}{tokenAddress}})
+	m.mu.Unlock()
+
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, tokenAddress)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count, ok := m.counts[tokenAddress]
+	if !ok {
+		return nil, nil
+	}
+	return &count, nil
+}
+
+// MockAdvancedStatsRepository is an in-memory implementation of AdvancedStatsRepository
+type MockAdvancedStatsRepository struct {
+	mu    sync.RWMutex
+	stats map[string]repositories.AdvancedStats
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockAdvancedStatsRepository() *MockAdvancedStatsRepository {
+	return &MockAdvancedStatsRepository{
+		stats: make(map[string]repositories.AdvancedStats),
+		Calls: make([]MockCall, 0),
+	}
+}
+
+func (m *MockAdvancedStatsRepository) Save(ctx context.Context, stats repositories.AdvancedStats) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Save", Args: []interface{}{stats}})
+	m.stats[stats.TokenAddress] = stats
+	return nil
+}
+
+func (m *MockAdvancedStatsRepository) Get(ctx context.Context, tokenAddress string) (*repositories.AdvancedStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Get", Args: []interface{}{tokenAddress}})
+	stats, ok := m.stats[tokenAddress]
+	if !ok {
+		return nil, nil
+	}
+	return &stats, nil
+}
+
+// MockAnalyticsRepository is an in-memory implementation of AnalyticsRepository
+type MockAnalyticsRepository struct {
+	mu        sync.RWMutex
+	transfers []entities.Transfer
+
+	// Function hooks for custom behavior
+	InsertTransfersFunc func(ctx context.Context, transfers []entities.Transfer) error
+	GetTokenStatsFunc   func(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error)
+	GetHolderCountFunc  func(ctx context.Context, tokenAddress string, exclude []string) (int64, error)
+	GetTopHoldersFunc   func(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockAnalyticsRepository() *MockAnalyticsRepository {
+	return &MockAnalyticsRepository{
+		Calls: make([]MockCall, 0),
+	}
+}
+
+func (m *MockAnalyticsRepository) InsertTransfers(ctx context.Context, transfers []entities.Transfer) error {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "InsertTransfers", Args: []interface{}{transfers}})
+	m.mu.Unlock()
+
+	if m.InsertTransfersFunc != nil {
+		return m.InsertTransfersFunc(ctx, transfers)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transfers = append(m.transfers, transfers...)
+	return nil
+}
+
+func (m *MockAnalyticsRepository) ReplaceRange(ctx context.Context, tokenAddress string, fromBlock, toBlock int64, transfers []entities.Transfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "ReplaceRange", Args: []interface{}{tokenAddress, fromBlock, toBlock, transfers}})
+
+	kept := m.transfers[:0]
+	for _, t := range m.transfers {
+		if t.TokenAddress != tokenAddress || t.BlockNumber < fromBlock || t.BlockNumber > toBlock {
+			kept = append(kept, t)
+		}
+	}
+	m.transfers = append(kept, transfers...)
+	return nil
+}
+
+func (m *MockAnalyticsRepository) GetTokenStats(ctx context.Context, tokenAddress string) (*repositories.TokenStatsResult, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetTokenStats", Args: []interface{}{tokenAddress}})
+	m.mu.Unlock()
+
+	if m.GetTokenStatsFunc != nil {
+		return m.GetTokenStatsFunc(ctx, tokenAddress)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := &repositories.TokenStatsResult{TotalVolume: "0", Volume24h: "0", Volume7d: "0"}
+	for _, t := range m.transfers {
+		if t.TokenAddress == tokenAddress {
+			result.TotalTransfers++
+		}
+	}
+	return result, nil
+}
+
+func (m *MockAnalyticsRepository) GetHolderCount(ctx context.Context, tokenAddress string, exclude []string) (int64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetHolderCount", Args: []interface{}{tokenAddress, exclude}})
+	m.mu.Unlock()
+
+	if m.GetHolderCountFunc != nil {
+		return m.GetHolderCountFunc(ctx, tokenAddress, exclude)
+	}
+	return 0, nil
+}
+
+func (m *MockAnalyticsRepository) GetTopHolders(ctx context.Context, tokenAddress string, limit, offset int, exclude []string) ([]repositories.HolderBalance, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetTopHolders", Args: []interface{}{tokenAddress, limit, offset, exclude}})
+	m.mu.Unlock()
+
+	if m.GetTopHoldersFunc != nil {
+		return m.GetTopHoldersFunc(ctx, tokenAddress, limit, offset, exclude)
+	}
+	return []repositories.HolderBalance{}, nil
+}
+
+// GetTransfers returns the transfers mirrored into the mock
+func (m *MockAnalyticsRepository) GetTransfers() []entities.Transfer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]entities.Transfer(nil), m.transfers...)
+}
+
+// MockSwapRepository is a mock implementation of SwapRepository
+type MockSwapRepository struct {
+	mu    sync.RWMutex
+	pools map[string]*entities.DexPool
+	swaps []entities.Swap
+
+	// Function hooks for custom behavior
+	StoreEventsFunc        func(ctx context.Context, events []any) error
+	GetPoolFunc            func(ctx context.Context, address string) (*entities.DexPool, error)
+	GetByFilterFunc        func(ctx context.Context, filter entities.SwapFilter) ([]entities.Swap, error)
+	GetCountFunc           func(ctx context.Context, filter entities.SwapFilter) (int64, error)
+	GetTokenSwapVolumeFunc func(ctx context.Context, tokenAddress string) (*repositories.SwapVolumeResult, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockSwapRepository() *MockSwapRepository {
+	return &MockSwapRepository{
+		pools: make(map[string]*entities.DexPool),
+		swaps: make([]entities.Swap, 0),
+		Calls: make([]MockCall, 0),
+	}
+}
+
+func (m *MockSwapRepository) StoreEvents(ctx context.Context, events []any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "StoreEvents", Args: []interface{}{events}})
+
+	if m.StoreEventsFunc != nil {
+		return m.StoreEventsFunc(ctx, events)
+	}
+
+	for _, e := range events {
+		if s, ok := e.(*entities.Swap); ok {
+			m.swaps = append(m.swaps, *s)
+		}
+	}
+	return nil
+}
+
+func (m *MockSwapRepository) UpsertPool(ctx context.Context, pool *entities.DexPool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "UpsertPool", Args: []interface{}{pool}})
+	m.pools[pool.Address] = pool
+	return nil
+}
+
+func (m *MockSwapRepository) GetPool(ctx context.Context, address string) (*entities.DexPool, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetPool", Args: []interface{}{address}})
+	m.mu.Unlock()
+
+	if m.GetPoolFunc != nil {
+		return m.GetPoolFunc(ctx, address)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pools[address], nil
+}
+
+func (m *MockSwapRepository) GetByFilter(ctx context.Context, filter entities.SwapFilter) ([]entities.Swap, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetByFilter", Args: []interface{}{filter}})
+	m.mu.Unlock()
+
+	if m.GetByFilterFunc != nil {
+		return m.GetByFilterFunc(ctx, filter)
+	}
+
+	matched := m.filterSwaps(filter)
+	start := filter.Offset
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + filter.Limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+	return matched[start:end], nil
+}
+
+func (m *MockSwapRepository) GetCount(ctx context.Context, filter entities.SwapFilter) (int64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetCount", Args: []interface{}{filter}})
+	m.mu.Unlock()
+
+	if m.GetCountFunc != nil {
+		return m.GetCountFunc(ctx, filter)
+	}
+
+	return int64(len(m.filterSwaps(filter))), nil
+}
+
+func (m *MockSwapRepository) filterSwaps(filter entities.SwapFilter) []entities.Swap {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matched := make([]entities.Swap, 0, len(m.swaps))
+	for _, s := range m.swaps {
+		if filter.PoolAddress != nil && s.PoolAddress != *filter.PoolAddress {
+			continue
+		}
+		matched = append(matched, s)
+	}
+	return matched
+}
+
+func (m *MockSwapRepository) GetTokenSwapVolume(ctx context.Context, tokenAddress string) (*repositories.SwapVolumeResult, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetTokenSwapVolume", Args: []interface{}{tokenAddress}})
+	m.mu.Unlock()
+
+	if m.GetTokenSwapVolumeFunc != nil {
+		return m.GetTokenSwapVolumeFunc(ctx, tokenAddress)
+	}
+
+	return &repositories.SwapVolumeResult{
+		TotalVolume: "0",
+		Volume24h:   "0",
+		Volume7d:    "0",
+	}, nil
+}
+
+// AddSwap adds a swap to the mock repository
+func (m *MockSwapRepository) AddSwap(swap entities.Swap) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.swaps = append(m.swaps, swap)
+}
+
+// Reset clears all data and calls
+func (m *MockSwapRepository) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pools = make(map[string]*entities.DexPool)
+	m.swaps = make([]entities.Swap, 0)
+	m.Calls = make([]MockCall, 0)
+}
+
+// MockEthTransferRepository is a mock implementation of EthTransferRepository
+type MockEthTransferRepository struct {
+	mu        sync.RWMutex
+	transfers []entities.EthTransfer
+	lastBlock int64
+
+	// Function hooks for custom behavior
+	BatchInsertFunc func(ctx context.Context, transfers []entities.EthTransfer) error
+	GetByFilterFunc func(ctx context.Context, filter entities.EthTransferFilter) ([]entities.EthTransfer, error)
+	GetCountFunc    func(ctx context.Context, filter entities.EthTransferFilter) (int64, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockEthTransferRepository() *MockEthTransferRepository {
+	return &MockEthTransferRepository{
+		transfers: make([]entities.EthTransfer, 0),
+		Calls:     make([]MockCall, 0),
+	}
+}
+
+func (m *MockEthTransferRepository) BatchInsert(ctx context.Context, transfers []entities.EthTransfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "BatchInsert", Args: []interface{}{transfers}})
+
+	if m.BatchInsertFunc != nil {
+		return m.BatchInsertFunc(ctx, transfers)
+	}
+
+	m.transfers = append(m.transfers, transfers...)
+	return nil
+}
+
+func (m *MockEthTransferRepository) GetByFilter(ctx context.Context, filter entities.EthTransferFilter) ([]entities.EthTransfer, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetByFilter", Args: []interface{}{filter}})
+	m.mu.Unlock()
+
+	if m.GetByFilterFunc != nil {
+		return m.GetByFilterFunc(ctx, filter)
+	}
+
+	matched := m.filterEthTransfers(filter)
+	start := filter.Offset
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + filter.Limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+	return matched[start:end], nil
+}
+
+func (m *MockEthTransferRepository) GetCount(ctx context.Context, filter entities.EthTransferFilter) (int64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetCount", Args: []interface{}{filter}})
+	m.mu.Unlock()
+
+	if m.GetCountFunc != nil {
+		return m.GetCountFunc(ctx, filter)
+	}
+
+	return int64(len(m.filterEthTransfers(filter))), nil
+}
+
+func (m *MockEthTransferRepository) filterEthTransfers(filter entities.EthTransferFilter) []entities.EthTransfer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matched := make([]entities.EthTransfer, 0, len(m.transfers))
+	for _, t := range m.transfers {
+		if filter.Address != nil && t.FromAddress != *filter.Address && t.ToAddress != *filter.Address {
+			continue
+		}
+		if filter.FromAddress != nil && t.FromAddress != *filter.FromAddress {
+			continue
+		}
+		if filter.ToAddress != nil && t.ToAddress != *filter.ToAddress {
+			continue
+		}
+		matched = append(matched, t)
+	}
+	return matched
+}
+
+func (m *MockEthTransferRepository) GetLastBlock(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "GetLastBlock"})
+	return m.lastBlock, nil
+}
+
+func (m *MockEthTransferRepository) UpdateLastBlock(ctx context.Context, blockNumber int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "UpdateLastBlock", Args: []interface{}{blockNumber}})
+	m.lastBlock = blockNumber
+	return nil
+}
+
+// AddEthTransfers adds ETH transfers to the mock repository
+func (m *MockEthTransferRepository) AddEthTransfers(transfers ...entities.EthTransfer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transfers = append(m.transfers, transfers...)
+}
+
+// MockMethodSignatureRepository is a mock implementation of MethodSignatureRepository
+type MockMethodSignatureRepository struct {
+	mu         sync.RWMutex
+	signatures map[string]entities.MethodSignature
+
+	// Function hooks for custom behavior
+	UpsertFunc         func(ctx context.Context, sig *entities.MethodSignature) error
+	GetBySelectorsFunc func(ctx context.Context, selectors []string) ([]entities.MethodSignature, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockMethodSignatureRepository() *MockMethodSignatureRepository {
+	return &MockMethodSignatureRepository{
+		signatures: make(map[string]entities.MethodSignature),
+		Calls:      make([]MockCall, 0),
+	}
+}
+
+func (m *MockMethodSignatureRepository) Upsert(ctx context.Context, sig *entities.MethodSignature) error {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "Upsert", Args: []interface{}{sig}})
+	m.mu.Unlock()
+
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, sig)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	sig.CreatedAt = now
+	if existing, ok := m.signatures[sig.Selector]; ok {
+		sig.CreatedAt = existing.CreatedAt
+	}
+	sig.UpdatedAt = now
+	m.signatures[sig.Selector] = *sig
+
+	return nil
+}
+
+func (m *MockMethodSignatureRepository) List(ctx context.Context) ([]entities.MethodSignature, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "List"})
+	m.mu.Unlock()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]entities.MethodSignature, 0, len(m.signatures))
+	for _, sig := range m.signatures {
+		result = append(result, sig)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Label != result[j].Label {
+			return result[i].Label < result[j].Label
+		}
+		return result[i].Selector < result[j].Selector
+	})
+	return result, nil
+}
+
+func (m *MockMethodSignatureRepository) GetBySelectors(ctx context.Context, selectors []string) ([]entities.MethodSignature, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetBySelectors", Args: []interface{}{selectors}})
+	m.mu.Unlock()
+
+	if m.GetBySelectorsFunc != nil {
+		return m.GetBySelectorsFunc(ctx, selectors)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []entities.MethodSignature
+	for _, selector := range selectors {
+		if sig, ok := m.signatures[selector]; ok {
+			result = append(result, sig)
+		}
+	}
+	return result, nil
+}
+
+// AddSignatures stores signatures directly
+func (m *MockMethodSignatureRepository) AddSignatures(sigs ...entities.MethodSignature) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, sig := range sigs {
+		m.signatures[sig.Selector] = sig
+	}
+}
+
+// MockPriceProvider is a mock implementation of pricing.Provider with fixed per-token prices
+type MockPriceProvider struct {
+	mu sync.RWMutex
+
+	// Prices maps lowercase token addresses to their current USD price
+	Prices map[string]float64
+
+	// Function hooks for custom behavior
+	HistoricalPriceFunc func(ctx context.Context, tokenAddress string, at time.Time) (float64, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockPriceProvider(prices map[string]float64) *MockPriceProvider {
+	return &MockPriceProvider{
+		Prices: prices,
+		Calls:  make([]MockCall, 0),
+	}
+}
+
+func (m *MockPriceProvider) CurrentPrice(ctx context.Context, tokenAddress string) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "CurrentPrice", Args: []interface{}{tokenAddress}})
+
+	price, ok := m.Prices[tokenAddress]
+	if !ok {
+		return 0, pricing.ErrPriceUnavailable
+	}
+	return price, nil
+}
+
+func (m *MockPriceProvider) HistoricalPrice(ctx context.Context, tokenAddress string, at time.Time) (float64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "HistoricalPrice", Args: []interface{}{tokenAddress, at}})
+	m.mu.Unlock()
+
+	if m.HistoricalPriceFunc != nil {
+		return m.HistoricalPriceFunc(ctx, tokenAddress, at)
+	}
+	return 0, pricing.ErrPriceUnavailable
+}
+
+// MockNativeBalanceProvider is a mock implementation of services.NativeBalanceProvider with fixed balances
+type MockNativeBalanceProvider struct {
+	mu sync.RWMutex
+
+	// Balances maps lowercase addresses to their balance in wei; unknown addresses have none
+	Balances map[string]*big.Int
+
+	// Function hooks for custom behavior
+	GetBalanceFunc func(ctx context.Context, address string) (*big.Int, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockNativeBalanceProvider(balances map[string]*big.Int) *MockNativeBalanceProvider {
+	return &MockNativeBalanceProvider{
+		Balances: balances,
+		Calls:    make([]MockCall, 0),
+	}
+}
+
+func (m *MockNativeBalanceProvider) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetBalance", Args: []interface{}{address}})
+	m.mu.Unlock()
+
+	if m.GetBalanceFunc != nil {
+		return m.GetBalanceFunc(ctx, address)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if balance, ok := m.Balances[address]; ok {
+		return new(big.Int).Set(balance), nil
+	}
+	return big.NewInt(0), nil
+}
+
+// MockMetadataReader is a mock implementation of services.MetadataReader serving fixed metadata
+type MockMetadataReader struct {
+	mu sync.RWMutex
+
+	// Metadata maps lowercase token addresses to their on-chain metadata; unknown tokens fail
+	Metadata map[string]*ethereum.TokenMetadata
+	Block    uint64
+
+	// Function hooks for custom behavior
+	ReadMetadataFunc func(ctx context.Context, tokenAddress string) (*ethereum.TokenMetadata, uint64, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockMetadataReader(metadata map[string]*ethereum.TokenMetadata, block uint64) *MockMetadataReader {
+	return &MockMetadataReader{
+		Metadata: metadata,
+		Block:    block,
+		Calls:    make([]MockCall, 0),
+	}
+}
+
+func (m *MockMetadataReader) ReadMetadata(ctx context.Context, tokenAddress string) (*ethereum.TokenMetadata, uint64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "ReadMetadata", Args: []interface{}{tokenAddress}})
+	m.mu.Unlock()
+
+	if m.ReadMetadataFunc != nil {
+		return m.ReadMetadataFunc(ctx, tokenAddress)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	metadata, ok := m.Metadata[tokenAddress]
+	if !ok {
+		return nil, 0, fmt.Errorf("execution reverted")
+	}
+	copied := *metadata
+	return &copied, m.Block, nil
+}
+
+// MockTenantRepository is an in-memory implementation of TenantRepository. ListTokens reads the
+// tokens of the token repository it was created with.
+type MockTenantRepository struct {
+	mu          sync.RWMutex
+	tenantsByID map[int64]*entities.Tenant
+	keys        map[string]int64
+	nextID      int64
+	tokenRepo   *MockTokenRepository
+
+	// Function hooks for custom behavior
+	GetByAPIKeyFunc func(ctx context.Context, keyHash string) (*entities.Tenant, error)
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockTenantRepository(tokenRepo *MockTokenRepository) *MockTenantRepository {
+	return &MockTenantRepository{
+		tenantsByID: make(map[int64]*entities.Tenant),
+		keys:        make(map[string]int64),
+		nextID:      1,
+		tokenRepo:   tokenRepo,
+		Calls:       make([]MockCall, 0),
+	}
+}
+
+func (m *MockTenantRepository) Create(ctx context.Context, tenant *entities.Tenant) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Create", Args: []interface{}{tenant}})
+
+	for _, existing := range m.tenantsByID {
+		if existing.Name == tenant.Name {
+			return fmt.Errorf("tenant %q already exists", tenant.Name)
+		}
+	}
+
+	tenant.ID = m.nextID
+	tenant.CreatedAt = time.Now()
+	m.nextID++
+
+	stored := *tenant
+	stored.Tokens = sortedAddresses(tenant.Tokens)
+	m.tenantsByID[stored.ID] = &stored
+	return nil
+}
+
+func (m *MockTenantRepository) GetByID(ctx context.Context, id int64) (*entities.Tenant, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "GetByID", Args: []interface{}{id}})
+
+	return m.copyTenant(id), nil
+}
+
+func (m *MockTenantRepository) GetByAPIKey(ctx context.Context, keyHash string) (*entities.Tenant, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetByAPIKey", Args: []interface{}{keyHash}})
+	m.mu.Unlock()
+
+	if m.GetByAPIKeyFunc != nil {
+		return m.GetByAPIKeyFunc(ctx, keyHash)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.keys[keyHash]
+	if !ok {
+		return nil, nil
+	}
+	return m.copyTenant(id), nil
+}
+
+func (m *MockTenantRepository) AddAPIKey(ctx context.Context, id int64, keyHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "AddAPIKey", Args: []interface{}{id, keyHash}})
+
+	if _, ok := m.tenantsByID[id]; !ok {
+		return fmt.Errorf("tenant %d does not exist", id)
+	}
+	m.keys[keyHash] = id
+	return nil
+}
+
+func (m *MockTenantRepository) SetTokens(ctx context.Context, id int64, tokens []string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "SetTokens", Args: []interface{}{id, tokens}})
+
+	tenant, ok := m.tenantsByID[id]
+	if !ok {
+		return false, nil
+	}
+	tenant.Tokens = sortedAddresses(tokens)
+	return true, nil
+}
+
+func (m *MockTenantRepository) ListTokens(ctx context.Context, id int64, limit, offset int, sortBy, sortOrder string, includeInactive bool, scope repositories.TokenScope) ([]*entities.Token, int64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "ListTokens", Args: []interface{}{id, limit, offset, sortBy, sortOrder, includeInactive, scope}})
+	tenant := m.copyTenant(id)
+	m.mu.Unlock()
+
+	if tenant == nil {
+		return []*entities.Token{}, 0, nil
+	}
+
+	m.tokenRepo.mu.RLock()
+	defer m.tokenRepo.mu.RUnlock()
+
+	result := make([]*entities.Token, 0, len(tenant.Tokens))
+	for _, address := range tenant.Tokens {
+		if token, ok := m.tokenRepo.tokens[address]; ok && (token.Active || includeInactive) && inTokenScope(address, scope) {
+			result = append(result, token)
+		}
+	}
+
+	total := int64(len(result))
+	start := min(offset, len(result))
+	end := min(start+limit, len(result))
+	return result[start:end], total, nil
+}
+
+// inTokenScope reports whether a token listing narrowed to scope includes address
+func inTokenScope(address string, scope repositories.TokenScope) bool {
+	if len(scope.Only) > 0 && !slices.Contains(scope.Only, address) {
+		return false
+	}
+	return !slices.Contains(scope.Except, address)
+}
+
+// copyTenant returns a copy of a stored tenant, or nil; callers hold mu
+func (m *MockTenantRepository) copyTenant(id int64) *entities.Tenant {
+	tenant, ok := m.tenantsByID[id]
+	if !ok {
+		return nil
+	}
+	result := *tenant
+	result.Tokens = append([]string(nil), tenant.Tokens...)
+	return &result
+}
+
+// sortedAddresses returns the distinct addresses in order, as the tenant repositories store them
+func sortedAddresses(addresses []string) []string {
+	result := appendMissing([]string{}, addresses)
+	sort.Strings(result)
+	return result
+}
+
+// MockTransferFlagRepository is an in-memory implementation of TransferFlagRepository. Flagged
+// stats are computed over the transfers of the given transfer repository.
+type MockTransferFlagRepository struct {
+	mu        sync.RWMutex
+	flags     []entities.TransferFlag
+	transfers *MockTransferRepository
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockTransferFlagRepository(transfers *MockTransferRepository) *MockTransferFlagRepository {
+	return &MockTransferFlagRepository{
+		transfers: transfers,
+		Calls:     make([]MockCall, 0),
+	}
+}
+
+func (m *MockTransferFlagRepository) Add(ctx context.Context, flags []entities.TransferFlag) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Add", Args: []interface{}{flags}})
+
+	var added int64
+	for _, f := range flags {
+		if m.hasLocked(f.TxHash, f.LogIndex, f.Flag) {
+			continue
+		}
+		f.FlaggedAt = time.Now()
+		m.flags = append(m.flags, f)
+		added++
+	}
+	return added, nil
+}
+
+func (m *MockTransferFlagRepository) hasLocked(txHash string, logIndex int, flag string) bool {
+	for _, f := range m.flags {
+		if f.TxHash == txHash && f.LogIndex == logIndex && (flag == "" || f.Flag == flag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MockTransferFlagRepository) GetByTxHashes(ctx context.Context, txHashes []string) ([]entities.TransferFlag, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetByTxHashes", Args: []interface{}{txHashes}})
+	m.mu.Unlock()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(txHashes))
+	for _, h := range txHashes {
+		wanted[h] = true
+	}
+	var result []entities.TransferFlag
+	for _, f := range m.flags {
+		if wanted[f.TxHash] {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockTransferFlagRepository) GetFlaggedStats(ctx context.Context, tokenAddress string, from, to time.Time) (*repositories.WindowStats, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetFlaggedStats", Args: []interface{}{tokenAddress, from, to}})
+	m.mu.Unlock()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.transfers.mu.RLock()
+	defer m.transfers.mu.RUnlock()
+
+	stats := &repositories.WindowStats{}
+	volume := new(big.Int)
+	for _, t := range m.transfers.transfers {
+		if t.TokenAddress != tokenAddress || t.BlockTimestamp.Before(from) || !t.BlockTimestamp.Before(to) {
+			continue
+		}
+		if m.hasLocked(t.TxHash, t.LogIndex, "") {
+			stats.Transfers++
+			volume.Add(volume, transferValue(t))
+		}
+	}
+	stats.Volume = volume.String()
+	return stats, nil
+}
+
+// MockFailedRangeRepository is an in-memory implementation of FailedRangeRepository
+type MockFailedRangeRepository struct {
+	mu     sync.RWMutex
+	ranges []entities.FailedRange
+	nextID int64
+
+	// Call tracking
+	Calls []MockCall
+}
+
+func NewMockFailedRangeRepository() *MockFailedRangeRepository {
+	return &MockFailedRangeRepository{
+		Calls: make([]MockCall, 0),
+	}
+}
+
+func (m *MockFailedRangeRepository) Record(ctx context.Context, r entities.FailedRange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Record", Args: []interface{}{r}})
+
+	now := time.Now()
+	for i := range m.ranges {
+		existing := &m.ranges[i]
+		if existing.ResolvedAt == nil && existing.TokenAddress == r.TokenAddress &&
+			existing.FromBlock == r.FromBlock && existing.ToBlock == r.ToBlock {
+			existing.Attempts = r.Attempts
+			existing.LastError = r.LastError
+			existing.Skipped = existing.Skipped || r.Skipped
+			existing.LastFailedAt = now
+			return nil
+		}
+	}
+
+	m.nextID++
+	r.ID = m.nextID
+	r.FirstFailedAt, r.LastFailedAt, r.ResolvedAt = now, now, nil
+	m.ranges = append(m.ranges, r)
+	return nil
+}
+
+func (m *MockFailedRangeRepository) List(ctx context.Context, unresolved bool) ([]entities.FailedRange, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "List", Args: []interface{}{unresolved}})
+	m.mu.Unlock()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []entities.FailedRange
+	for _, r := range m.ranges {
+		if !unresolved || r.ResolvedAt == nil {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockFailedRangeRepository) Resolve(ctx context.Context, id int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Calls = append(m.Calls, MockCall{Method: "Resolve", Args: []interface{}{id}})
+
+	for i := range m.ranges {
+		if m.ranges[i].ID == id && m.ranges[i].ResolvedAt == nil {
+			now := time.Now()
+			m.ranges[i].ResolvedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MockWalletStatsRepository records wallet stats updates; summaries come from GetSummaryFunc
+type MockWalletStatsRepository struct {
+	mu sync.Mutex
+
+	// Call tracking
+	Calls []MockCall
+
+	// Optional function overrides
+	GetSummaryFunc      func(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, bool, error)
+	GetRangeWalletsFunc func(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) ([]string, error)
+}
+
+func NewMockWalletStatsRepository() *MockWalletStatsRepository {
+	return &MockWalletStatsRepository{
+		Calls: make([]MockCall, 0),
+	}
+}
+
+func (m *MockWalletStatsRepository) Apply(ctx context.Context, transfers []entities.Transfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "Apply", Args: []interface{}{transfers}})
+	return nil
+}
+
+func (m *MockWalletStatsRepository) Refresh(ctx context.Context, tokenAddress string, wallets []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "Refresh", Args: []interface{}{tokenAddress, wallets}})
+	return nil
+}
+
+func (m *MockWalletStatsRepository) Rebuild(ctx context.Context, tokenAddress string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "Rebuild", Args: []interface{}{tokenAddress}})
+	return nil
+}
+
+func (m *MockWalletStatsRepository) GetRangeWallets(ctx context.Context, tokenAddress string, fromBlock, toBlock int64) ([]string, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetRangeWallets", Args: []interface{}{tokenAddress, fromBlock, toBlock}})
+	m.mu.Unlock()
+
+	if m.GetRangeWalletsFunc != nil {
+		return m.GetRangeWalletsFunc(ctx, tokenAddress, fromBlock, toBlock)
+	}
+	return nil, nil
+}
+
+func (m *MockWalletStatsRepository) GetSummary(ctx context.Context, walletAddress string) (*repositories.WalletTransferSummary, bool, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetSummary", Args: []interface{}{walletAddress}})
+	m.mu.Unlock()
+
+	if m.GetSummaryFunc != nil {
+		return m.GetSummaryFunc(ctx, walletAddress)
+	}
+	return nil, false, nil
+}
+
+type addressNoteKey struct {
+	tenantID int64
+	address  string
+}
+
+// MockAddressNoteRepository is an in-memory implementation of AddressNoteRepository
+type MockAddressNoteRepository struct {
+	mu    sync.Mutex
+	notes map[addressNoteKey]entities.AddressNote
+
+	// Call tracking
+	Calls []MockCall
+
+	// Optional function overrides
+	GetByAddressesFunc func(ctx context.Context, tenantID int64, addresses []string) ([]entities.AddressNote, error)
+}
+
+func NewMockAddressNoteRepository() *MockAddressNoteRepository {
+	return &MockAddressNoteRepository{
+		notes: make(map[addressNoteKey]entities.AddressNote),
+		Calls: make([]MockCall, 0),
+	}
+}
+
+func (m *MockAddressNoteRepository) Put(ctx context.Context, note *entities.AddressNote) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "Put", Args: []interface{}{note}})
+
+	key := addressNoteKey{note.TenantID, note.Address.String()}
+	now := time.Now()
+	note.CreatedAt = now
+	if existing, ok := m.notes[key]; ok {
+		note.CreatedAt = existing.CreatedAt
+	}
+	note.UpdatedAt = now
+	m.notes[key] = *note
+	return nil
+}
+
+func (m *MockAddressNoteRepository) Get(ctx context.Context, tenantID int64, address entities.Address) (*entities.AddressNote, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "Get", Args: []interface{}{tenantID, address}})
+
+	note, ok := m.notes[addressNoteKey{tenantID, address.String()}]
+	if !ok {
+		return nil, nil
+	}
+	return &note, nil
+}
+
+func (m *MockAddressNoteRepository) Delete(ctx context.Context, tenantID int64, address entities.Address) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "Delete", Args: []interface{}{tenantID, address}})
+
+	key := addressNoteKey{tenantID, address.String()}
+	_, ok := m.notes[key]
+	delete(m.notes, key)
+	return ok, nil
+}
+
+func (m *MockAddressNoteRepository) GetByAddresses(ctx context.Context, tenantID int64, addresses []string) ([]entities.AddressNote, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetByAddresses", Args: []interface{}{tenantID, addresses}})
+	m.mu.Unlock()
+
+	if m.GetByAddressesFunc != nil {
+		return m.GetByAddressesFunc(ctx, tenantID, addresses)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var notes []entities.AddressNote
+	for _, address := range addresses {
+		if note, ok := m.notes[addressNoteKey{tenantID, address}]; ok {
+			notes = append(notes, note)
+		}
+	}
+	return notes, nil
+}
+
+// MockContractRepository is an in-memory implementation of ContractRepository
+type MockContractRepository struct {
+	mu        sync.Mutex
+	contracts map[string]entities.Contract
+	events    []entities.ContractEvent
+
+	// Call tracking
+	Calls []MockCall
+
+	// Optional function overrides
+	GetEventsFunc func(ctx context.Context, filter entities.ContractEventFilter) ([]entities.ContractEvent, error)
+}
+
+func NewMockContractRepository() *MockContractRepository {
+	return &MockContractRepository{
+		contracts: make(map[string]entities.Contract),
+		Calls:     make([]MockCall, 0),
+	}
+}
+
+func (m *MockContractRepository) StoreEvents(ctx context.Context, events []any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "StoreEvents", Args: []interface{}{events}})
+
+	for _, event := range events {
+		e, ok := event.(*entities.ContractEvent)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T", event)
+		}
+		m.events = append(m.events, *e)
+	}
+	return nil
+}
+
+func (m *MockContractRepository) UpsertContract(ctx context.Context, contract *entities.Contract) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "UpsertContract", Args: []interface{}{contract}})
+
+	now := time.Now()
+	contract.CreatedAt = now
+	if existing, ok := m.contracts[contract.Address]; ok {
+		contract.CreatedAt = existing.CreatedAt
+	}
+	contract.UpdatedAt = now
+	m.contracts[contract.Address] = *contract
+	return nil
+}
+
+func (m *MockContractRepository) GetContract(ctx context.Context, address string) (*entities.Contract, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetContract", Args: []interface{}{address}})
+
+	contract, ok := m.contracts[address]
+	if !ok {
+		return nil, nil
+	}
+	return &contract, nil
+}
+
+func (m *MockContractRepository) ListContracts(ctx context.Context) ([]entities.Contract, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCall{Method: "ListContracts"})
+
+	contracts := make([]entities.Contract, 0, len(m.contracts))
+	for _, contract := range m.contracts {
+		contracts = append(contracts, contract)
+	}
+	sort.Slice(contracts, func(i, j int) bool { return contracts[i].Address < contracts[j].Address })
+	return contracts, nil
+}
+
+func (m *MockContractRepository) GetEvents(ctx context.Context, filter entities.ContractEventFilter) ([]entities.ContractEvent, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetEvents", Args: []interface{}{filter}})
+	m.mu.Unlock()
+
+	if m.GetEventsFunc != nil {
+		return m.GetEventsFunc(ctx, filter)
+	}
+
+	events := m.matchingEvents(filter)
+	if filter.Offset >= len(events) {
+		return []entities.ContractEvent{}, nil
+	}
+	end := len(events)
+	if filter.Limit > 0 && filter.Offset+filter.Limit < end {
+		end = filter.Offset + filter.Limit
+	}
+	return events[filter.Offset:end], nil
+}
+
+func (m *MockContractRepository) GetEventCount(ctx context.Context, filter entities.ContractEventFilter) (int64, error) {
+	m.mu.Lock()
+	m.Calls = append(m.Calls, MockCall{Method: "GetEventCount", Args: []interface{}{filter}})
+	m.mu.Unlock()
+
+	return int64(len(m.matchingEvents(filter))), nil
+}
+
+// matchingEvents returns the stored events matching the filter, newest first. Argument values
+// are compared as compact JSON.
+func (m *MockContractRepository) matchingEvents(filter entities.ContractEventFilter) []entities.ContractEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []entities.ContractEvent
+	for _, e := range m.events {
+		if e.ContractAddress != filter.ContractAddress {
+			continue
+		}
+		if filter.EventName != nil && e.EventName != *filter.EventName {
+			continue
+		}
+		if len(filter.Args) > 0 {
+			var args map[string]json.RawMessage
+			if err := json.Unmarshal(e.Args, &args); err != nil {
+				continue
+			}
+			matched := true
+			for name, want := range filter.Args {
+				if !jsonEqual(args[name], want) {
+					matched = false
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		if len(filter.Conditions) > 0 && !matchesContractArgConditions(e.Args, filter.Conditions) {
+			continue
+		}
+		events = append(events, e)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if !a.BlockTimestamp.Equal(b.BlockTimestamp) {
+			return a.BlockTimestamp.After(b.BlockTimestamp)
+		}
+		if a.BlockNumber != b.BlockNumber {
+			return a.BlockNumber > b.BlockNumber
+		}
+		return a.LogIndex > b.LogIndex
+	})
+	return events
+}
+
+// matchesContractArgConditions reports whether decoded args meet every condition, comparing
+// ranges as big integers
+func matchesContractArgConditions(raw json.RawMessage, conditions []entities.ContractArgCondition) bool {
+	var args map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return false
+	}
+
+	for _, c := range conditions {
+		value, ok := args[c.Arg]
+		if !ok {
+			return false
+		}
+		switch c.Operator {
+		case entities.ContractArgEq, entities.ContractArgNe:
+			if jsonEqual(value, c.Value) != (c.Operator == entities.ContractArgEq) {
+				return false
+			}
+		default:
+			got, ok1 := new(big.Int).SetString(string(value), 10)
+			want, ok2 := new(big.Int).SetString(string(c.Value), 10)
+			if !ok1 || !ok2 {
+				return false
+			}
+			cmp := got.Cmp(want)
+			if (c.Operator == entities.ContractArgGt && cmp <= 0) || (c.Operator == entities.ContractArgGte && cmp < 0) ||
+				(c.Operator == entities.ContractArgLt && cmp >= 0) || (c.Operator == entities.ContractArgLte && cmp > 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// jsonEqual reports whether two JSON values are written the same, ignoring whitespace
+func jsonEqual(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
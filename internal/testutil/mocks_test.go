@@ -2,6 +2,7 @@ package testutil
 
 import (
 	"context"
+	"math/big"
 	"testing"
 
 	"github.com/bimakw/chain-indexer/internal/domain/entities"
@@ -60,10 +61,13 @@ func TestMockTransferRepository_BatchInsert(t *testing.T) {
 	ctx := context.Background()
 
 	transfers := CreateMultipleTransfers(5)
-	err := repo.BatchInsert(ctx, transfers)
+	inserted, err := repo.BatchInsert(ctx, transfers)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if inserted != 5 {
+		t.Errorf("expected 5 inserted, got %d", inserted)
+	}
 
 	// Verify transfers were added
 	all, err := repo.GetByFilter(ctx, entities.TransferFilter{Limit: 100})
@@ -103,6 +107,39 @@ func TestMockTransferRepository_GetLatestBlock(t *testing.T) {
 	}
 }
 
+func TestMockTransferRepository_Holders(t *testing.T) {
+	repo := NewMockTransferRepository()
+	mint := func(to string, value int64) entities.Transfer {
+		return CreateTestTransfer(WithTokenAddress(USDTAddress), WithFromAddress(entities.ZeroAddress),
+			WithToAddress(to), WithValue(big.NewInt(value)))
+	}
+	repo.AddTransfers(mint(AliceAddress, 100), mint(BobAddress, 300), mint(CharlieAddr, 200))
+	ctx := context.Background()
+
+	// Holders are ranked by balance, as the repositories rank them
+	top, err := repo.GetTopHolders(ctx, USDTAddress, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(top) != 3 || top[0].Address != BobAddress || top[0].Balance != "300" || top[2].Address != AliceAddress {
+		t.Errorf("expected Bob, Charlie, Alice by balance, got %+v", top)
+	}
+
+	// A page keeps the ranks of the whole list, after exclusions
+	page, err := repo.GetTopHoldersWithOffset(ctx, USDTAddress, 1, 1, []string{BobAddress})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page) != 1 || page[0].Address != AliceAddress || page[0].Rank != 2 {
+		t.Errorf("expected Alice at rank 2, got %+v", page)
+	}
+
+	count, err := repo.GetHolderCount(ctx, USDTAddress, []string{BobAddress})
+	if err != nil || count != 2 {
+		t.Errorf("expected 2 holders, got %d (%v)", count, err)
+	}
+}
+
 func TestMockTokenRepository(t *testing.T) {
 	repo := NewMockTokenRepository()
 	ctx := context.Background()
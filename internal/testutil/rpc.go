@@ -0,0 +1,199 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// MockRPC is a JSON-RPC node serving the calls the fetcher makes (eth_chainId, eth_blockNumber,
+// eth_getLogs and eth_getBlockByNumber) from canned logs, so an ethereum.Client can be pointed at it
+type MockRPC struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	chainID     int64
+	blockNumber uint64
+	logs        []types.Log
+	queries     []LogQuery
+}
+
+// LogQuery records the range and addresses of one eth_getLogs call
+type LogQuery struct {
+	FromBlock uint64
+	ToBlock   uint64
+	Addresses []common.Address
+}
+
+// NewMockRPC starts a mock node for chain ID 1 at head block blockNumber; call Close when done
+func NewMockRPC(blockNumber uint64) *MockRPC {
+	m := &MockRPC{chainID: 1, blockNumber: blockNumber}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serve))
+	return m
+}
+
+// AddLogs adds logs returned by eth_getLogs when they match a query's range and addresses
+func (m *MockRPC) AddLogs(logs ...types.Log) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, logs...)
+}
+
+// SetBlockNumber moves the head block the node reports
+func (m *MockRPC) SetBlockNumber(blockNumber uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blockNumber = blockNumber
+}
+
+// Queries returns the eth_getLogs calls received so far
+func (m *MockRPC) Queries() []LogQuery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]LogQuery(nil), m.queries...)
+}
+
+// MockBlockTime is the timestamp the mock node reports for a block
+func MockBlockTime(blockNumber uint64) time.Time {
+	return time.Unix(1700000000+int64(blockNumber)*12, 0)
+}
+
+// TransferLog builds an ERC-20 Transfer log of value from one address to another
+func TransferLog(token, from, to string, value int64, blockNumber uint64, logIndex uint) types.Log {
+	return types.Log{
+		Address: common.HexToAddress(token),
+		Topics: []common.Hash{
+			common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"),
+			common.BytesToHash(common.HexToAddress(from).Bytes()),
+			common.BytesToHash(common.HexToAddress(to).Bytes()),
+		},
+		Data:        common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+		BlockNumber: blockNumber,
+		TxHash:      common.BigToHash(big.NewInt(int64(blockNumber)*1000 + int64(logIndex))),
+		Index:       logIndex,
+	}
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (m *MockRPC) serve(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := m.handle(req)
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if err != nil {
+		response["error"] = map[string]interface{}{"code": -32000, "message": err.Error()}
+	} else {
+		response["result"] = result
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (m *MockRPC) handle(req rpcRequest) (interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch req.Method {
+	case "eth_chainId":
+		return hexutil.EncodeUint64(uint64(m.chainID)), nil
+	case "eth_blockNumber":
+		return hexutil.EncodeUint64(m.blockNumber), nil
+	case "eth_getLogs":
+		return m.getLogs(req.Params)
+	case "eth_getBlockByNumber":
+		return m.getBlock(req.Params)
+	default:
+		return nil, fmt.Errorf("method %s not supported by mock node", req.Method)
+	}
+}
+
+func (m *MockRPC) getLogs(params []json.RawMessage) (interface{}, error) {
+	var filter struct {
+		FromBlock string           `json:"fromBlock"`
+		ToBlock   string           `json:"toBlock"`
+		Address   []common.Address `json:"address"`
+	}
+	if len(params) != 1 {
+		return nil, fmt.Errorf("expected one filter")
+	}
+	if err := json.Unmarshal(params[0], &filter); err != nil {
+		return nil, err
+	}
+
+	from, err := hexutil.DecodeUint64(filter.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := hexutil.DecodeUint64(filter.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+	m.queries = append(m.queries, LogQuery{FromBlock: from, ToBlock: to, Addresses: filter.Address})
+
+	addresses := make(map[common.Address]bool, len(filter.Address))
+	for _, a := range filter.Address {
+		addresses[a] = true
+	}
+
+	matched := []types.Log{}
+	for _, log := range m.logs {
+		if log.BlockNumber >= from && log.BlockNumber <= to && addresses[log.Address] {
+			matched = append(matched, log)
+		}
+	}
+	return matched, nil
+}
+
+func (m *MockRPC) getBlock(params []json.RawMessage) (interface{}, error) {
+	var tag string
+	if len(params) == 0 {
+		return nil, fmt.Errorf("expected a block number")
+	}
+	if err := json.Unmarshal(params[0], &tag); err != nil {
+		return nil, err
+	}
+	number, err := strconv.ParseUint(strings.TrimPrefix(tag, "0x"), 16, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported block tag %q", tag)
+	}
+
+	header := &types.Header{
+		UncleHash:  types.EmptyUncleHash,
+		TxHash:     types.EmptyTxsHash,
+		Difficulty: big.NewInt(0),
+		Number:     new(big.Int).SetUint64(number),
+		Time:       uint64(MockBlockTime(number).Unix()),
+	}
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var block map[string]interface{}
+	if err := json.Unmarshal(encoded, &block); err != nil {
+		return nil, err
+	}
+	block["transactions"] = []interface{}{}
+	block["uncles"] = []interface{}{}
+	return block, nil
+}
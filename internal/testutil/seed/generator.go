@@ -0,0 +1,204 @@
+// Package seed generates deterministic synthetic ERC-20 transfers for load testing and benchmarks.
+//
+// Holder activity follows a Zipf distribution: a few addresses send and receive most transfers,
+// as on mainnet, which is what makes the holders and stats queries expensive. Tokens are minted
+// from the zero address on demand, so no balance ever goes negative and holder counts stay realistic.
+package seed
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+// microUnits is the generator's internal precision: balances are tracked in millionths of a token
+const microUnits = 1_000_000
+
+// mintRate is the share of transfers minted from the zero address to keep new holders appearing
+const mintRate = 0.02
+
+// Config controls the shape of the generated data
+type Config struct {
+	Tokens            int           // number of tokens
+	Holders           int           // size of the address pool shared by all tokens
+	Transfers         int64         // total transfers to generate
+	Skew              float64       // Zipf exponent (> 1); higher concentrates activity on fewer holders
+	Seed              int64         // same seed and config produce the same data
+	StartBlock        int64         // block number of the first transfer
+	EndTime           time.Time     // timestamp of the last block; earlier blocks are spaced BlockTime apart
+	BlockTime         time.Duration // time between blocks
+	TransfersPerBlock int           // transfers in each block
+}
+
+// DefaultConfig returns a config for one million transfers over five tokens
+func DefaultConfig() Config {
+	return Config{
+		Tokens:            5,
+		Holders:           100_000,
+		Transfers:         1_000_000,
+		Skew:              1.2,
+		Seed:              1,
+		StartBlock:        18_000_000,
+		EndTime:           time.Now().UTC().Truncate(24 * time.Hour),
+		BlockTime:         12 * time.Second,
+		TransfersPerBlock: 50,
+	}
+}
+
+// Validate checks the config for values the generator cannot work with
+func (c Config) Validate() error {
+	switch {
+	case c.Tokens <= 0:
+		return fmt.Errorf("tokens must be positive")
+	case c.Holders < 2:
+		return fmt.Errorf("holders must be at least 2")
+	case c.Transfers < 0:
+		return fmt.Errorf("transfers must not be negative")
+	case c.Skew <= 1:
+		return fmt.Errorf("skew must be greater than 1")
+	case c.StartBlock < 0:
+		return fmt.Errorf("start block must not be negative")
+	case c.BlockTime <= 0:
+		return fmt.Errorf("block time must be positive")
+	case c.TransfersPerBlock <= 0:
+		return fmt.Errorf("transfers per block must be positive")
+	}
+	return nil
+}
+
+// Blocks returns the number of blocks the generated transfers span
+func (c Config) Blocks() int64 {
+	return (c.Transfers + int64(c.TransfersPerBlock) - 1) / int64(c.TransfersPerBlock)
+}
+
+// Generator produces transfers in block order. It is not safe for concurrent use.
+type Generator struct {
+	cfg       Config
+	rng       *rand.Rand
+	zipf      *rand.Zipf
+	tokens    []*entities.Token
+	scales    []*big.Int // 10^(decimals-6) per token, converting micro units to raw units
+	holders   []string
+	balances  [][]uint64 // [token][holder] in micro units
+	startTime time.Time
+	generated int64
+}
+
+// NewGenerator creates a generator for the config
+func NewGenerator(cfg Config) (*Generator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	g := &Generator{
+		cfg:       cfg,
+		rng:       rng,
+		zipf:      rand.NewZipf(rng, cfg.Skew, 1, uint64(cfg.Holders-1)),
+		holders:   make([]string, cfg.Holders),
+		balances:  make([][]uint64, cfg.Tokens),
+		startTime: cfg.EndTime.Add(-time.Duration(max(cfg.Blocks()-1, 0)) * cfg.BlockTime),
+	}
+
+	firstBlock := cfg.StartBlock
+	for i := 0; i < cfg.Tokens; i++ {
+		decimals := 18
+		if i%2 == 1 {
+			decimals = 6
+		}
+		g.tokens = append(g.tokens, &entities.Token{
+			Address:        g.randomHex(20),
+			Name:           fmt.Sprintf("Seed Token %d", i+1),
+			Symbol:         fmt.Sprintf("SEED%d", i+1),
+			Decimals:       decimals,
+			FirstSeenBlock: &firstBlock,
+			Active:         true,
+		})
+		g.scales = append(g.scales, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-6)), nil))
+		g.balances[i] = make([]uint64, cfg.Holders)
+	}
+	for i := range g.holders {
+		g.holders[i] = g.randomHex(20)
+	}
+
+	return g, nil
+}
+
+// Tokens returns the generated tokens; they must be stored before their transfers
+func (g *Generator) Tokens() []*entities.Token {
+	return g.tokens
+}
+
+// Generated returns the number of transfers produced so far
+func (g *Generator) Generated() int64 {
+	return g.generated
+}
+
+// Next returns up to n further transfers, or nil once Config.Transfers have been produced
+func (g *Generator) Next(n int) []entities.Transfer {
+	remaining := g.cfg.Transfers - g.generated
+	if remaining <= 0 {
+		return nil
+	}
+	if int64(n) > remaining {
+		n = int(remaining)
+	}
+
+	transfers := make([]entities.Transfer, n)
+	for i := range transfers {
+		transfers[i] = g.transfer()
+	}
+	return transfers
+}
+
+// transfer produces the next transfer
+func (g *Generator) transfer() entities.Transfer {
+	blockOffset := g.generated / int64(g.cfg.TransfersPerBlock)
+	logIndex := int(g.generated % int64(g.cfg.TransfersPerBlock))
+	g.generated++
+
+	token := g.rng.Intn(len(g.tokens))
+	balances := g.balances[token]
+
+	sender := int(g.zipf.Uint64())
+	receiver := int(g.zipf.Uint64())
+	if receiver == sender {
+		receiver = (receiver + 1) % len(g.holders)
+	}
+
+	from := entities.ZeroAddress
+	var amount uint64
+	if balances[sender] == 0 || g.rng.Float64() < mintRate {
+		amount = uint64(1+g.rng.Int63n(1_000_000)) * microUnits
+	} else {
+		from = g.holders[sender]
+		amount = uint64(1 + g.rng.Int63n(int64(balances[sender])))
+		balances[sender] -= amount
+	}
+	balances[receiver] += amount
+
+	value := new(big.Int).Mul(new(big.Int).SetUint64(amount), g.scales[token])
+
+	return entities.Transfer{
+		TxHash:         g.randomHex(32),
+		LogIndex:       logIndex,
+		BlockNumber:    g.cfg.StartBlock + blockOffset,
+		BlockTimestamp: g.startTime.Add(time.Duration(blockOffset) * g.cfg.BlockTime),
+		TokenAddress:   g.tokens[token].Address,
+		FromAddress:    from,
+		ToAddress:      g.holders[receiver],
+		Value:          value,
+		ValueString:    value.String(),
+	}
+}
+
+// randomHex returns n random bytes as a 0x-prefixed lowercase hex string
+func (g *Generator) randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = g.rng.Read(b)
+	return "0x" + hex.EncodeToString(b)
+}
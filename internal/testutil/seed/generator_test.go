@@ -0,0 +1,95 @@
+package seed
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bimakw/chain-indexer/internal/domain/entities"
+)
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.Tokens = 2
+	cfg.Holders = 50
+	cfg.Transfers = 1000
+	cfg.TransfersPerBlock = 10
+	cfg.EndTime = time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	return cfg
+}
+
+func generateAll(t *testing.T, cfg Config) []entities.Transfer {
+	t.Helper()
+	g, err := NewGenerator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all []entities.Transfer
+	for batch := g.Next(64); batch != nil; batch = g.Next(64) {
+		all = append(all, batch...)
+	}
+	return all
+}
+
+func TestGenerator_Deterministic(t *testing.T) {
+	cfg := testConfig()
+	a, b := generateAll(t, cfg), generateAll(t, cfg)
+	if len(a) != 1000 {
+		t.Fatalf("expected 1000 transfers, got %d", len(a))
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Error("expected identical output for the same seed")
+	}
+
+	cfg.Seed = 2
+	if c := generateAll(t, cfg); reflect.DeepEqual(a, c) {
+		t.Error("expected different output for a different seed")
+	}
+}
+
+func TestGenerator_Shape(t *testing.T) {
+	cfg := testConfig()
+	transfers := generateAll(t, cfg)
+
+	first, last := transfers[0], transfers[len(transfers)-1]
+	if first.BlockNumber != cfg.StartBlock || last.BlockNumber != cfg.StartBlock+cfg.Blocks()-1 {
+		t.Errorf("unexpected block range %d-%d", first.BlockNumber, last.BlockNumber)
+	}
+	if !last.BlockTimestamp.Equal(cfg.EndTime) || last.LogIndex != cfg.TransfersPerBlock-1 {
+		t.Errorf("unexpected last transfer: %+v", last)
+	}
+
+	// Replaying the transfers never takes a holder below zero
+	balances := make(map[string]*big.Int)
+	for _, tr := range transfers {
+		for _, addr := range []string{tr.FromAddress, tr.ToAddress} {
+			if balances[tr.TokenAddress+addr] == nil {
+				balances[tr.TokenAddress+addr] = new(big.Int)
+			}
+		}
+		balances[tr.TokenAddress+tr.ToAddress].Add(balances[tr.TokenAddress+tr.ToAddress], tr.Value)
+		if tr.FromAddress == entities.ZeroAddress {
+			continue
+		}
+		from := balances[tr.TokenAddress+tr.FromAddress].Sub(balances[tr.TokenAddress+tr.FromAddress], tr.Value)
+		if from.Sign() < 0 {
+			t.Fatalf("negative balance for %s after %s", tr.FromAddress, tr.TxHash)
+		}
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	for name, mutate := range map[string]func(*Config){
+		"no tokens":     func(c *Config) { c.Tokens = 0 },
+		"one holder":    func(c *Config) { c.Holders = 1 },
+		"flat skew":     func(c *Config) { c.Skew = 1 },
+		"no block time": func(c *Config) { c.BlockTime = 0 },
+	} {
+		cfg := testConfig()
+		mutate(&cfg)
+		if _, err := NewGenerator(cfg); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS swaps;
+DROP TABLE IF EXISTS dex_pools;
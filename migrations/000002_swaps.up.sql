@@ -0,0 +1,39 @@
+-- DEX pools: pools whose Swap events are indexed
+CREATE TABLE IF NOT EXISTS dex_pools (
+    address VARCHAR(42) PRIMARY KEY,
+    token0_address VARCHAR(42) NOT NULL,
+    token1_address VARCHAR(42) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Swaps table: stores Uniswap V2/V3 Swap events
+-- amount0/amount1 are signed, from the pool's perspective (positive = into the pool)
+CREATE TABLE IF NOT EXISTS swaps (
+    id BIGSERIAL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_timestamp TIMESTAMPTZ NOT NULL,
+    pool_address VARCHAR(42) NOT NULL REFERENCES dex_pools(address),
+    protocol VARCHAR(16) NOT NULL,
+    sender VARCHAR(42) NOT NULL,
+    recipient VARCHAR(42) NOT NULL,
+    token0_address VARCHAR(42) NOT NULL,
+    token1_address VARCHAR(42) NOT NULL,
+    amount0 NUMERIC(78, 0) NOT NULL,
+    amount1 NUMERIC(78, 0) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (id, block_timestamp)
+);
+
+SELECT create_hypertable('swaps', 'block_timestamp',
+    chunk_time_interval => INTERVAL '1 day',
+    if_not_exists => TRUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_swaps_pool ON swaps (pool_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_swaps_token0 ON swaps (token0_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_swaps_token1 ON swaps (token1_address, block_timestamp DESC);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_swaps_unique
+    ON swaps (tx_hash, log_index, block_timestamp);
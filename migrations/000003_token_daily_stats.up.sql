@@ -0,0 +1,17 @@
+-- Daily per-token transfer rollup, maintained by the indexer for every day it writes transfers to.
+-- new_senders/new_holders count addresses whose first send/receipt of the token falls on that day,
+-- so their sums over all days equal the all-time unique sender/receiver counts.
+CREATE TABLE IF NOT EXISTS token_daily_stats (
+    token_address VARCHAR(42) NOT NULL REFERENCES tokens(address),
+    day DATE NOT NULL, -- UTC
+    transfers BIGINT NOT NULL DEFAULT 0,
+    volume NUMERIC NOT NULL DEFAULT 0,
+    unique_senders BIGINT NOT NULL DEFAULT 0,
+    unique_receivers BIGINT NOT NULL DEFAULT 0,
+    new_senders BIGINT NOT NULL DEFAULT 0,
+    new_holders BIGINT NOT NULL DEFAULT 0,
+    first_transfer_at TIMESTAMPTZ NOT NULL,
+    last_transfer_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (token_address, day)
+);
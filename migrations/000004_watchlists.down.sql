@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS watchlist_addresses;
+DROP TABLE IF EXISTS watchlists;
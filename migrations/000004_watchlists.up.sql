@@ -0,0 +1,14 @@
+-- Watchlists: named groups of addresses whose transfers are served as one feed
+CREATE TABLE IF NOT EXISTS watchlists (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS watchlist_addresses (
+    watchlist_id BIGINT NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    address VARCHAR(42) NOT NULL,
+    added_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (watchlist_id, address)
+);
@@ -0,0 +1,13 @@
+-- Alert rules: transfer conditions evaluated by the indexer on every new batch
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    token_address VARCHAR(42),
+    address VARCHAR(42),
+    direction VARCHAR(8) NOT NULL DEFAULT 'any',
+    min_value NUMERIC(78, 0),
+    counterparties VARCHAR(42)[] NOT NULL DEFAULT '{}',
+    channel VARCHAR(32) NOT NULL,
+    target TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
@@ -0,0 +1,10 @@
+-- Deny lists: sanctioned or otherwise flagged addresses, imported per list (e.g. OFAC)
+CREATE TABLE IF NOT EXISTS deny_list (
+    list_name VARCHAR(64) NOT NULL,
+    address VARCHAR(42) NOT NULL,
+    label TEXT NOT NULL DEFAULT '',
+    added_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (list_name, address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_deny_list_address ON deny_list (address);
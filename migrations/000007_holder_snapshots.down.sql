@@ -0,0 +1 @@
+DROP TABLE IF EXISTS holder_snapshots;
@@ -0,0 +1,12 @@
+-- Holder snapshots: a token's top holders captured periodically by the indexer,
+-- diffed against current balances to report who entered or left the top N
+CREATE TABLE IF NOT EXISTS holder_snapshots (
+    token_address VARCHAR(42) NOT NULL,
+    taken_at TIMESTAMPTZ NOT NULL,
+    address VARCHAR(42) NOT NULL,
+    balance NUMERIC(78, 0) NOT NULL,
+    rank INTEGER NOT NULL,
+    PRIMARY KEY (token_address, taken_at, address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_holder_snapshots_taken_at ON holder_snapshots (taken_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS entity_addresses;
+DROP TABLE IF EXISTS entities;
@@ -0,0 +1,15 @@
+-- Entities: named groups of addresses (e.g. a fund's or exchange's wallets) whose holdings
+-- and activity are aggregated, with transfers between members treated as internal
+CREATE TABLE IF NOT EXISTS entities (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS entity_addresses (
+    entity_id BIGINT NOT NULL REFERENCES entities(id) ON DELETE CASCADE,
+    address VARCHAR(42) NOT NULL,
+    added_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (entity_id, address)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS eth_trace_state;
+DROP TABLE IF EXISTS eth_transfers;
@@ -0,0 +1,35 @@
+-- Native ETH transfers captured from block traces: top-level transaction values and internal
+-- calls that move ETH. trace_address locates the call in its transaction's call tree
+-- ('' for the top-level call, '0-2' for the third subcall of the first subcall).
+CREATE TABLE IF NOT EXISTS eth_transfers (
+    id BIGSERIAL,
+    tx_hash VARCHAR(66) NOT NULL,
+    trace_address VARCHAR(255) NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_timestamp TIMESTAMPTZ NOT NULL,
+    from_address VARCHAR(42) NOT NULL,
+    to_address VARCHAR(42) NOT NULL,
+    value NUMERIC(78, 0) NOT NULL,
+    call_type VARCHAR(16) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (id, block_timestamp)
+);
+
+SELECT create_hypertable('eth_transfers', 'block_timestamp',
+    chunk_time_interval => INTERVAL '1 day',
+    if_not_exists => TRUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_eth_transfers_from ON eth_transfers (from_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_eth_transfers_to ON eth_transfers (to_address, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_eth_transfers_block ON eth_transfers (block_number);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_eth_transfers_unique
+    ON eth_transfers (tx_hash, trace_address, block_timestamp);
+
+-- Trace indexing checkpoint; a single row, since traces cover whole blocks rather than one token
+CREATE TABLE IF NOT EXISTS eth_trace_state (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_indexed_block BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
@@ -0,0 +1,14 @@
+-- Raw logs: the original eth_getLogs entries behind indexed records, stored as JSON so decoding
+-- can be replayed after parser fixes without re-fetching from RPC (`indexer replay`)
+CREATE TABLE IF NOT EXISTS raw_logs (
+    block_number BIGINT NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    address VARCHAR(42) NOT NULL,
+    block_timestamp TIMESTAMPTZ NOT NULL,
+    log JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (block_number, tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_raw_logs_address ON raw_logs (address, block_number);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transfers_initiator;
+ALTER TABLE transfers DROP COLUMN IF EXISTS initiator;
@@ -0,0 +1,6 @@
+-- Transaction sender (tx.origin) of each transfer, filled when INDEXER_ENRICH_INITIATOR is set.
+-- The Transfer event's from is often a router or other contract rather than the account that acted.
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS initiator VARCHAR(42);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_initiator ON transfers (initiator, block_timestamp DESC)
+    WHERE initiator IS NOT NULL;
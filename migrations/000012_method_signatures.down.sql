@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS method_signatures;
+DROP INDEX IF EXISTS idx_transfers_method_selector;
+ALTER TABLE transfers DROP COLUMN IF EXISTS method_selector;
@@ -0,0 +1,33 @@
+-- 4-byte selector of the transaction that emitted each transfer, filled with the initiator
+ALTER TABLE transfers ADD COLUMN IF NOT EXISTS method_selector VARCHAR(10);
+
+CREATE INDEX IF NOT EXISTS idx_transfers_method_selector ON transfers (method_selector, block_timestamp DESC)
+    WHERE method_selector IS NOT NULL;
+
+-- Selector -> function name lookup; label groups functions by what they do (swap, bridge, ...)
+CREATE TABLE IF NOT EXISTS method_signatures (
+    selector VARCHAR(10) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    label VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_method_signatures_label ON method_signatures (label);
+
+INSERT INTO method_signatures (selector, name, label) VALUES
+    ('0xa9059cbb', 'transfer(address,uint256)', 'transfer'),
+    ('0x23b872dd', 'transferFrom(address,address,uint256)', 'transferFrom'),
+    ('0x095ea7b3', 'approve(address,uint256)', 'approve'),
+    ('0x38ed1739', 'swapExactTokensForTokens(uint256,uint256,address[],address,uint256)', 'swap'),
+    ('0x8803dbee', 'swapTokensForExactTokens(uint256,uint256,address[],address,uint256)', 'swap'),
+    ('0x7ff36ab5', 'swapExactETHForTokens(uint256,address[],address,uint256)', 'swap'),
+    ('0x18cbafe5', 'swapExactTokensForETH(uint256,uint256,address[],address,uint256)', 'swap'),
+    ('0x414bf389', 'exactInputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))', 'swap'),
+    ('0xc04b8d59', 'exactInput((bytes,address,uint256,uint256,uint256))', 'swap'),
+    ('0x3593564c', 'execute(bytes,bytes[],uint256)', 'swap'),
+    ('0xac9650d8', 'multicall(bytes[])', 'multicall'),
+    ('0x5ae401dc', 'multicall(uint256,bytes[])', 'multicall'),
+    ('0x0f5287b0', 'transferTokens(address,uint256,uint16,bytes32,uint256,uint32)', 'bridge'),
+    ('0xd2ce7d65', 'outboundTransfer(address,address,uint256,uint256,uint256,bytes)', 'bridge')
+ON CONFLICT (selector) DO NOTHING;
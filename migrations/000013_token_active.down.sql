@@ -0,0 +1,2 @@
+ALTER TABLE tokens DROP COLUMN IF EXISTS deactivated_at;
+ALTER TABLE tokens DROP COLUMN IF EXISTS active;
@@ -0,0 +1,3 @@
+-- Deactivated tokens keep their history and checkpoint but are no longer indexed or listed by default
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMPTZ;
@@ -0,0 +1,7 @@
+-- Checkpoint of events scoped to their own contracts, such as DEX pool swaps. They are fetched
+-- once per block range for all tokens, so a single row tracks them.
+CREATE TABLE IF NOT EXISTS scoped_event_state (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_indexed_block BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE indexer_state DROP COLUMN IF EXISTS backfill_checkpoint;
@@ -0,0 +1,3 @@
+-- Last block of the contiguous completed prefix of a backfill, so an interrupted backfill of the
+-- same range resumes after it. Cleared whenever the recorded range changes.
+ALTER TABLE indexer_state ADD COLUMN IF NOT EXISTS backfill_checkpoint BIGINT;
@@ -0,0 +1,7 @@
+-- Holder counts: each token's holder count as of the last holder snapshot, served as an
+-- approximate count for tokens too large to aggregate on every request
+CREATE TABLE IF NOT EXISTS holder_counts (
+    token_address VARCHAR(42) PRIMARY KEY,
+    holder_count BIGINT NOT NULL,
+    counted_at TIMESTAMPTZ NOT NULL
+);
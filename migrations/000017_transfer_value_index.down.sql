@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_transfers_token_value;
@@ -0,0 +1,17 @@
+-- transfers.value must be NUMERIC(78, 0) for value filters and SUM aggregations to be exact.
+-- Databases created from an early schema may still hold it in another type; convert those.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema()
+          AND table_name = 'transfers'
+          AND column_name = 'value'
+          AND (data_type <> 'numeric' OR numeric_precision IS DISTINCT FROM 78 OR numeric_scale IS DISTINCT FROM 0)
+    ) THEN
+        ALTER TABLE transfers ALTER COLUMN value TYPE NUMERIC(78, 0) USING value::NUMERIC(78, 0);
+    END IF;
+END $$;
+
+-- Serves min_value/max_value filters and large-transfer lookups within a token
+CREATE INDEX IF NOT EXISTS idx_transfers_token_value ON transfers (token_address, value DESC);
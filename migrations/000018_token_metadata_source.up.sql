@@ -0,0 +1,2 @@
+-- Operators can override garbage on-chain metadata; overridden metadata is kept when a token is re-registered
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS metadata_source VARCHAR(16) NOT NULL DEFAULT 'onchain';
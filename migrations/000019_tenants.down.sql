@@ -0,0 +1,4 @@
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS tenant_id;
+DROP TABLE IF EXISTS tenant_tokens;
+DROP TABLE IF EXISTS tenant_api_keys;
+DROP TABLE IF EXISTS tenants;
@@ -0,0 +1,26 @@
+-- Tenants: namespaces with their own tracked token set, API keys, rate limit and alert rules,
+-- sharing the indexed transfer data
+CREATE TABLE IF NOT EXISTS tenants (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    rate_limit_rps INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- API keys are stored as SHA-256 hex digests; the key itself is only shown when it is created
+CREATE TABLE IF NOT EXISTS tenant_api_keys (
+    key_hash CHAR(64) PRIMARY KEY,
+    tenant_id BIGINT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_api_keys_tenant ON tenant_api_keys (tenant_id);
+
+CREATE TABLE IF NOT EXISTS tenant_tokens (
+    tenant_id BIGINT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    token_address VARCHAR(42) NOT NULL,
+    PRIMARY KEY (tenant_id, token_address)
+);
+
+-- Rules without a tenant belong to requests made without an API key
+ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS tenant_id BIGINT REFERENCES tenants(id) ON DELETE CASCADE;
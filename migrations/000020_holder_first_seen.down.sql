@@ -0,0 +1 @@
+DROP TABLE IF EXISTS holder_first_seen;
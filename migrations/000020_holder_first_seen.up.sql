@@ -0,0 +1,11 @@
+-- Each address's first receipt of each token, kept up to date with token_daily_stats, so the
+-- newest holders can be listed without scanning a token's transfers
+CREATE TABLE IF NOT EXISTS holder_first_seen (
+    token_address VARCHAR(42) NOT NULL,
+    address VARCHAR(42) NOT NULL,
+    first_block BIGINT NOT NULL,
+    first_seen_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (token_address, address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_holder_first_seen_newest ON holder_first_seen (token_address, first_seen_at DESC);
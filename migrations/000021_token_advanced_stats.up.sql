@@ -0,0 +1,14 @@
+-- Token velocity and dormancy inputs, recomputed by the indexer's advanced stats job: volume and
+-- average circulating supply over a trailing window, and the supply held by inactive addresses
+CREATE TABLE IF NOT EXISTS token_advanced_stats (
+    token_address VARCHAR(42) PRIMARY KEY,
+    window_from TIMESTAMPTZ NOT NULL,
+    window_to TIMESTAMPTZ NOT NULL,
+    volume NUMERIC NOT NULL,
+    average_supply NUMERIC NOT NULL,
+    circulating_supply NUMERIC NOT NULL,
+    inactive_since TIMESTAMPTZ NOT NULL,
+    dormant_supply NUMERIC NOT NULL,
+    dormant_holders BIGINT NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL
+);
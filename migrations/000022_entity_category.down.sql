@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_entities_category;
+ALTER TABLE entities DROP COLUMN IF EXISTS category;
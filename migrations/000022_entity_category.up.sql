@@ -0,0 +1,5 @@
+-- Entity categories (exchange, bridge), for reporting a token's flows to and from each kind of
+-- counterparty. Existing entities have none.
+ALTER TABLE entities ADD COLUMN IF NOT EXISTS category VARCHAR(32) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_entities_category ON entities (category) WHERE category <> '';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS token_metadata_history;
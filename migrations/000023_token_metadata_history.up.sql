@@ -0,0 +1,16 @@
+-- On-chain metadata changes of tokens, such as a proxy upgrade changing decimals, detected by the
+-- indexer's metadata refresh job. block_number is the chain head when the change was detected.
+CREATE TABLE IF NOT EXISTS token_metadata_history (
+    id BIGSERIAL PRIMARY KEY,
+    token_address VARCHAR(42) NOT NULL REFERENCES tokens(address),
+    previous_name VARCHAR(255) NOT NULL,
+    previous_symbol VARCHAR(32) NOT NULL,
+    previous_decimals INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    symbol VARCHAR(32) NOT NULL,
+    decimals INTEGER NOT NULL,
+    block_number BIGINT NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_metadata_history_token ON token_metadata_history (token_address, id);
@@ -0,0 +1,7 @@
+ALTER TABLE token_advanced_stats
+    DROP COLUMN IF EXISTS self_transfers,
+    DROP COLUMN IF EXISTS self_transfer_volume,
+    DROP COLUMN IF EXISTS fan_out_threshold,
+    DROP COLUMN IF EXISTS fan_out_transactions,
+    DROP COLUMN IF EXISTS fan_out_transfers,
+    DROP COLUMN IF EXISTS fan_out_volume;
@@ -0,0 +1,9 @@
+-- Self-transfers and airdrop-like fan-outs (transactions with many transfers from one sender)
+-- within the advanced stats window. Rows computed before are zero until the next run.
+ALTER TABLE token_advanced_stats
+    ADD COLUMN IF NOT EXISTS self_transfers BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS self_transfer_volume NUMERIC NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fan_out_threshold INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fan_out_transactions BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fan_out_transfers BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS fan_out_volume NUMERIC NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+-- Heuristic labels attached to transfers by the indexer's analysis pass, such as wash_trade for
+-- transfers ping-ponging between two addresses. Keyed like transfers, so a transfer removed by a
+-- reorg leaves its flags behind without them being counted.
+CREATE TABLE IF NOT EXISTS transfer_flags (
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    flag VARCHAR(32) NOT NULL,
+    token_address VARCHAR(42) NOT NULL,
+    flagged_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tx_hash, log_index, flag)
+);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS notify_indexed_token ON transfers;
+DROP FUNCTION IF EXISTS notify_indexed_token();
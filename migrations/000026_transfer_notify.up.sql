@@ -0,0 +1,17 @@
+-- Announces the token of every stored transfer on the indexed_tokens channel, so API processes
+-- can LISTEN for new data instead of relying on the indexer's Redis pub/sub. Row triggers are
+-- used because hypertables do not support transition tables; identical notifications are
+-- delivered once per transaction, so a batch insert announces each token once.
+CREATE OR REPLACE FUNCTION notify_indexed_token()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('indexed_tokens', NEW.token_address);
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS notify_indexed_token ON transfers;
+CREATE TRIGGER notify_indexed_token
+    AFTER INSERT ON transfers
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_indexed_token();
@@ -0,0 +1,20 @@
+-- Block ranges that failed INDEXER_RANGE_MAX_RETRIES times in a row, such as ranges whose logs a
+-- provider cannot serve or the decoder cannot read. With INDEXER_FAILED_RANGE_POLICY=skip the
+-- checkpoint moves past them, and `chain-indexer failed-ranges --repair` reindexes them later.
+CREATE TABLE IF NOT EXISTS failed_ranges (
+    id BIGSERIAL PRIMARY KEY,
+    token_address VARCHAR(42) NOT NULL,
+    from_block BIGINT NOT NULL,
+    to_block BIGINT NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    skipped BOOLEAN NOT NULL DEFAULT FALSE,
+    first_failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ
+);
+
+-- One open record per range
+CREATE UNIQUE INDEX IF NOT EXISTS idx_failed_ranges_unresolved
+    ON failed_ranges (token_address, from_block, to_block)
+    WHERE resolved_at IS NULL;
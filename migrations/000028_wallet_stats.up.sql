@@ -0,0 +1,38 @@
+-- Per wallet and token transfer totals, kept up to date by the indexer as it stores transfers, so
+-- the wallet summary reads one row per token the wallet touched instead of scanning its transfers.
+-- A transfer counts as out for its sender and in for its receiver, and both for a self-transfer.
+CREATE TABLE IF NOT EXISTS wallet_stats (
+    wallet_address VARCHAR(42) NOT NULL,
+    token_address VARCHAR(42) NOT NULL,
+    transfers_in BIGINT NOT NULL DEFAULT 0,
+    transfers_out BIGINT NOT NULL DEFAULT 0,
+    volume_in NUMERIC NOT NULL DEFAULT 0,
+    volume_out NUMERIC NOT NULL DEFAULT 0,
+    first_transfer_at TIMESTAMPTZ NOT NULL,
+    last_transfer_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (wallet_address, token_address)
+);
+
+-- Totals of the transfers stored before the indexer maintained them. Transfers an indexer of an
+-- earlier build stores after this runs are picked up by `chain-indexer rollup`.
+INSERT INTO wallet_stats (
+    wallet_address, token_address, transfers_in, transfers_out,
+    volume_in, volume_out, first_transfer_at, last_transfer_at
+)
+SELECT wallet, token_address, SUM(transfers_in), SUM(transfers_out),
+    SUM(volume_in), SUM(volume_out), MIN(first_transfer_at), MAX(last_transfer_at)
+FROM (
+    SELECT to_address AS wallet, token_address, COUNT(*) AS transfers_in, 0 AS transfers_out,
+        SUM(value) AS volume_in, 0 AS volume_out,
+        MIN(block_timestamp) AS first_transfer_at, MAX(block_timestamp) AS last_transfer_at
+    FROM transfers
+    GROUP BY to_address, token_address
+    UNION ALL
+    SELECT from_address, token_address, 0, COUNT(*), 0, SUM(value),
+        MIN(block_timestamp), MAX(block_timestamp)
+    FROM transfers
+    GROUP BY from_address, token_address
+) totals
+GROUP BY wallet, token_address
+ON CONFLICT (wallet_address, token_address) DO NOTHING;
@@ -0,0 +1,10 @@
+-- Private notes tenants attach to addresses, returned with the transfers and portfolios of the
+-- tenant's requests. One note per tenant and address; deleting the tenant deletes its notes.
+CREATE TABLE IF NOT EXISTS address_notes (
+    tenant_id BIGINT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    address VARCHAR(42) NOT NULL,
+    note TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, address)
+);
@@ -0,0 +1,37 @@
+-- Contracts registered by ABI: the selected events of each are decoded into contract_events
+CREATE TABLE IF NOT EXISTS contracts (
+    address VARCHAR(42) PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    abi JSONB NOT NULL,
+    events TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Decoded contract events: the raw topics and the arguments as a JSON object by argument name.
+-- Integers are JSON numbers, so args can be compared numerically; addresses are lowercase hex.
+CREATE TABLE IF NOT EXISTS contract_events (
+    id BIGSERIAL,
+    contract_address VARCHAR(42) NOT NULL REFERENCES contracts(address) ON DELETE CASCADE,
+    event_name TEXT NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_timestamp TIMESTAMPTZ NOT NULL,
+    topics TEXT[] NOT NULL,
+    args JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (id, block_timestamp)
+);
+
+SELECT create_hypertable('contract_events', 'block_timestamp',
+    chunk_time_interval => INTERVAL '1 day',
+    if_not_exists => TRUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_contract_events_contract
+    ON contract_events (contract_address, event_name, block_timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_contract_events_args ON contract_events USING GIN (args jsonb_path_ops);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contract_events_unique
+    ON contract_events (tx_hash, log_index, block_timestamp);
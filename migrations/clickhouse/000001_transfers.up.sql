@@ -0,0 +1,18 @@
+-- ClickHouse analytics store: a copy of the transfers table for aggregate queries.
+-- PostgreSQL remains the system of record; this table is written by the indexer.
+CREATE DATABASE IF NOT EXISTS chain_indexer;
+
+-- ReplacingMergeTree collapses re-inserted transfers; queries read with FINAL
+CREATE TABLE IF NOT EXISTS chain_indexer.transfers (
+    token_address   LowCardinality(String),
+    tx_hash         String,
+    log_index       UInt32,
+    block_number    UInt64,
+    block_timestamp DateTime('UTC'),
+    from_address    String,
+    to_address      String,
+    value           UInt256
+)
+ENGINE = ReplacingMergeTree
+PARTITION BY toYYYYMM(block_timestamp)
+ORDER BY (token_address, block_number, tx_hash, log_index);
@@ -0,0 +1,9 @@
+// Package migrations embeds the PostgreSQL schema migrations so the binary can apply them itself.
+package migrations
+
+import "embed"
+
+// Files holds the NNNNNN_name.up.sql and NNNNNN_name.down.sql migrations
+//
+//go:embed *.sql
+var Files embed.FS